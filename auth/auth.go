@@ -0,0 +1,390 @@
+// Package auth authenticates requests through guards backed by user providers.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Request locals the manager keeps per-request guards under.
+const (
+	guardsKey       = "auth.guards"
+	defaultGuardKey = "auth.guard"
+)
+
+// ErrSessionRequired is returned by session guards on requests without a
+// started session.
+var ErrSessionRequired = errors.New("auth: session guard requires the session middleware")
+
+// ErrInvalidCredentials is returned when issuing tokens for credentials
+// that don't match a user.
+var ErrInvalidCredentials = errors.New("auth: invalid credentials")
+
+// GuardConfig configures a guard.
+type GuardConfig struct {
+	// Driver is the guard driver: "session", "token", "jwt", or one added
+	// with Extend.
+	Driver string
+
+	// Provider is the name of the user provider the guard retrieves users from.
+	Provider string
+
+	// InputKey is the query or form field the token guard reads the token
+	// from when no bearer token is sent. Defaults to "api_token".
+	InputKey string
+
+	// StorageKey is the column the token guard looks tokens up by.
+	// Defaults to "api_token".
+	StorageKey string
+
+	// Hash makes the token guard look up the SHA-256 of the token, so
+	// tokens can be stored hashed.
+	Hash bool
+
+	// JWT configures the jwt driver's signing keys and token lifetimes.
+	JWT JWTConfig
+}
+
+// Config holds authentication configuration.
+type Config struct {
+	// Default is the guard used when none is named.
+	Default string
+
+	// Guards maps guard names to their configuration.
+	Guards map[string]GuardConfig
+}
+
+// DefaultConfig returns a "web" session guard and an "api" token guard,
+// both using the "users" provider.
+func DefaultConfig() Config {
+	return Config{
+		Default: "web",
+		Guards: map[string]GuardConfig{
+			"web": {Driver: "session", Provider: "users"},
+			"api": {Driver: "token", Provider: "users"},
+		},
+	}
+}
+
+// GuardFactory creates a guard for one request.
+type GuardFactory func(name string, config GuardConfig, provider contracts.UserProvider, c *fiber.Ctx) (contracts.Guard, error)
+
+// Manager creates guards for requests and holds the user providers.
+type Manager struct {
+	config      Config
+	drivers     map[string]GuardFactory
+	providers   map[string]contracts.UserProvider
+	creators    map[string]func() (contracts.UserProvider, error)
+	jwts        map[string]*JWT
+	invalidator TokenInvalidator
+	acting      map[string]contracts.Authenticatable
+	mu          sync.RWMutex
+}
+
+// NewManager creates an auth manager with the session, token and jwt drivers.
+func NewManager(config ...Config) *Manager {
+	cfg := DefaultConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Guards == nil {
+		cfg.Guards = make(map[string]GuardConfig)
+	}
+
+	m := &Manager{
+		config: cfg,
+		drivers: map[string]GuardFactory{
+			"session": newSessionGuard,
+			"token":   newTokenGuard,
+		},
+		providers:   make(map[string]contracts.UserProvider),
+		creators:    make(map[string]func() (contracts.UserProvider, error)),
+		jwts:        make(map[string]*JWT),
+		invalidator: NewCacheInvalidator(cache.NewMemoryStore()),
+		acting:      make(map[string]contracts.Authenticatable),
+	}
+	m.drivers["jwt"] = m.newJWTGuard
+	return m
+}
+
+// Config returns the manager's configuration.
+func (m *Manager) Config() Config {
+	return m.config
+}
+
+// DefaultGuard returns the name of the default guard.
+func (m *Manager) DefaultGuard() string {
+	return m.config.Default
+}
+
+// Extend registers a guard driver.
+func (m *Manager) Extend(driver string, factory GuardFactory) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.drivers[driver] = factory
+}
+
+// RegisterProvider registers a user provider.
+func (m *Manager) RegisterProvider(name string, provider contracts.UserProvider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.providers[name] = provider
+	delete(m.creators, name)
+}
+
+// ProviderFunc registers a user provider that is created on first use, for
+// providers that depend on services booted after the auth provider.
+func (m *Manager) ProviderFunc(name string, creator func() (contracts.UserProvider, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.creators[name] = creator
+	delete(m.providers, name)
+}
+
+// Provider returns a user provider.
+func (m *Manager) Provider(name string) (contracts.UserProvider, error) {
+	m.mu.RLock()
+	provider, ok := m.providers[name]
+	m.mu.RUnlock()
+	if ok {
+		return provider, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if provider, ok := m.providers[name]; ok {
+		return provider, nil
+	}
+	creator, ok := m.creators[name]
+	if !ok {
+		return nil, fmt.Errorf("auth: user provider %q is not registered", name)
+	}
+	provider, err := creator()
+	if err != nil {
+		return nil, fmt.Errorf("auth: user provider %q: %w", name, err)
+	}
+	m.providers[name] = provider
+	delete(m.creators, name)
+	return provider, nil
+}
+
+// Guard returns a guard for the request. Without a name it returns the
+// request's default guard: the one set with ShouldUse, else Config.Default.
+// Guards are created once per request.
+func (m *Manager) Guard(c *fiber.Ctx, name ...string) (contracts.Guard, error) {
+	guardName := m.config.Default
+	if requested, ok := c.Locals(defaultGuardKey).(string); ok {
+		guardName = requested
+	}
+	if len(name) > 0 && name[0] != "" {
+		guardName = name[0]
+	}
+
+	guards, _ := c.Locals(guardsKey).(map[string]contracts.Guard)
+	if guard, ok := guards[guardName]; ok {
+		return guard, nil
+	}
+
+	config, ok := m.config.Guards[guardName]
+	if !ok {
+		return nil, fmt.Errorf("auth: guard %q is not defined", guardName)
+	}
+	m.mu.RLock()
+	factory, ok := m.drivers[config.Driver]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("auth: guard %q uses unknown driver %q", guardName, config.Driver)
+	}
+	provider, err := m.Provider(config.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	guard, err := factory(guardName, config, provider, c)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	user, acting := m.acting[guardName]
+	m.mu.RUnlock()
+	if acting {
+		guard.SetUser(user)
+	}
+	if guards == nil {
+		guards = make(map[string]contracts.Guard)
+		c.Locals(guardsKey, guards)
+	}
+	guards[guardName] = guard
+	return guard, nil
+}
+
+// ActingAs authenticates every request as user on a guard, the default
+// one without a name, until it is called again with a nil user. It is
+// meant for tests, and applies to all requests the manager serves.
+func (m *Manager) ActingAs(user contracts.Authenticatable, guard ...string) {
+	name := m.config.Default
+	if len(guard) > 0 && guard[0] != "" {
+		name = guard[0]
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if user == nil {
+		delete(m.acting, name)
+		return
+	}
+	m.acting[name] = user
+}
+
+// ShouldUse makes name the default guard for the rest of the request.
+func (m *Manager) ShouldUse(c *fiber.Ctx, name string) {
+	c.Locals(defaultGuardKey, name)
+}
+
+// ResolvedGuards returns the guards created for the request so far, by name.
+func ResolvedGuards(c *fiber.Ctx) map[string]contracts.Guard {
+	guards, _ := c.Locals(guardsKey).(map[string]contracts.Guard)
+	return guards
+}
+
+// SetTokenInvalidator sets where guards without their own invalidator
+// record revoked JWTs. It defaults to an in-memory store, which only
+// works for a single instance.
+func (m *Manager) SetTokenInvalidator(invalidator TokenInvalidator) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.invalidator = invalidator
+	clear(m.jwts)
+}
+
+// JWT returns the token issuer of a jwt guard, or of the default guard.
+func (m *Manager) JWT(guard ...string) (*JWT, error) {
+	name := m.config.Default
+	if len(guard) > 0 && guard[0] != "" {
+		name = guard[0]
+	}
+
+	m.mu.RLock()
+	issuer, ok := m.jwts[name]
+	m.mu.RUnlock()
+	if ok {
+		return issuer, nil
+	}
+
+	config, ok := m.config.Guards[name]
+	if !ok {
+		return nil, fmt.Errorf("auth: guard %q is not defined", name)
+	}
+	if config.Driver != "jwt" {
+		return nil, fmt.Errorf("auth: guard %q does not use the jwt driver", name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if issuer, ok := m.jwts[name]; ok {
+		return issuer, nil
+	}
+	if config.JWT.Invalidator == nil {
+		config.JWT.Invalidator = m.invalidator
+	}
+	issuer, err := NewJWT(config.JWT)
+	if err != nil {
+		return nil, fmt.Errorf("auth: guard %q: %w", name, err)
+	}
+	m.jwts[name] = issuer
+	return issuer, nil
+}
+
+// Attempt validates credentials against a jwt guard's user provider and
+// issues tokens for the user. It returns ErrInvalidCredentials when they
+// don't match.
+func (m *Manager) Attempt(ctx context.Context, credentials map[string]any, guard ...string) (*TokenPair, error) {
+	issuer, provider, err := m.jwtGuard(guard)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := provider.RetrieveByCredentials(ctx, credentials)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !provider.ValidateCredentials(user, credentials) {
+		return nil, ErrInvalidCredentials
+	}
+	return issuer.Issue(user)
+}
+
+// Refresh exchanges a refresh token for new tokens. The refresh token is
+// invalidated, so each one can be used once; with an invalidator that
+// implements TokenClaimer this holds for concurrent refreshes too.
+func (m *Manager) Refresh(ctx context.Context, refreshToken string, guard ...string) (*TokenPair, error) {
+	issuer, provider, err := m.jwtGuard(guard)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := issuer.Parse(ctx, refreshToken, RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	user, err := provider.RetrieveByID(ctx, claims.UserID())
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("%w: unknown user", ErrInvalidToken)
+	}
+	if err := issuer.claim(ctx, claims); err != nil {
+		return nil, err
+	}
+	return issuer.Issue(user)
+}
+
+// Invalidate revokes an access or refresh token of a jwt guard.
+func (m *Manager) Invalidate(ctx context.Context, token string, guard ...string) error {
+	issuer, err := m.JWT(guard...)
+	if err != nil {
+		return err
+	}
+	return issuer.Invalidate(ctx, token)
+}
+
+func (m *Manager) jwtGuard(guard []string) (*JWT, contracts.UserProvider, error) {
+	issuer, err := m.JWT(guard...)
+	if err != nil {
+		return nil, nil, err
+	}
+	name := m.config.Default
+	if len(guard) > 0 && guard[0] != "" {
+		name = guard[0]
+	}
+	provider, err := m.Provider(m.config.Guards[name].Provider)
+	if err != nil {
+		return nil, nil, err
+	}
+	return issuer, provider, nil
+}
+
+// validateCredentials returns the user the credentials belong to, or nil.
+func validateCredentials(c *fiber.Ctx, provider contracts.UserProvider, credentials map[string]any) (contracts.Authenticatable, error) {
+	user, err := provider.RetrieveByCredentials(c.UserContext(), credentials)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	if !provider.ValidateCredentials(user, credentials) {
+		return nil, nil
+	}
+	return user, nil
+}
@@ -0,0 +1,370 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/genesysflow/go-genesys/database/schema"
+	"github.com/genesysflow/go-genesys/hashing"
+	"github.com/genesysflow/go-genesys/session"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	_ "modernc.org/sqlite"
+)
+
+// memoryProvider is a user provider over a fixed set of users.
+type memoryProvider struct {
+	users []GenericUser
+}
+
+func (p *memoryProvider) RetrieveByID(ctx context.Context, id any) (contracts.Authenticatable, error) {
+	for _, user := range p.users {
+		if user["id"] == id {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (p *memoryProvider) RetrieveByCredentials(ctx context.Context, credentials map[string]any) (contracts.Authenticatable, error) {
+	for _, user := range p.users {
+		matches := true
+		for key, value := range credentials {
+			if key != "password" && user[key] != value {
+				matches = false
+			}
+		}
+		if matches {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (p *memoryProvider) ValidateCredentials(user contracts.Authenticatable, credentials map[string]any) bool {
+	return user.(GenericUser)["password"] == credentials["password"]
+}
+
+func newAuthApp(t *testing.T) (*fiber.App, *Manager) {
+	t.Helper()
+
+	manager := NewManager()
+	manager.RegisterProvider("users", &memoryProvider{users: []GenericUser{
+		{"id": int64(1), "email": "ada@example.com", "password": "secret", "api_token": "token-1"},
+	}})
+	sessions := session.NewManager()
+
+	app := fiber.New()
+	app.Use(sessions.Middleware())
+	app.Post("/login", func(c *fiber.Ctx) error {
+		guard, err := manager.Guard(c)
+		if err != nil {
+			return err
+		}
+		ok, err := guard.Attempt(map[string]any{"email": c.FormValue("email"), "password": c.FormValue("password")})
+		if err != nil {
+			return err
+		}
+		return c.SendString(fmt.Sprint(ok))
+	})
+	app.Post("/logout", func(c *fiber.Ctx) error {
+		guard, err := manager.Guard(c)
+		if err != nil {
+			return err
+		}
+		return guard.Logout()
+	})
+	app.Get("/me", func(c *fiber.Ctx) error {
+		guard, err := manager.Guard(c, c.Query("guard"))
+		if err != nil {
+			return err
+		}
+		return c.SendString(fmt.Sprint(guard.ID()))
+	})
+	return app, manager
+}
+
+// send performs a request and returns the body and the session cookie.
+func send(t *testing.T, app *fiber.App, req *http.Request, cookie string) (string, string) {
+	t.Helper()
+	if cookie != "" {
+		req.AddCookie(&http.Cookie{Name: "genesys_session", Value: cookie})
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	for _, c := range resp.Cookies() {
+		if c.Name == "genesys_session" {
+			cookie = c.Value
+		}
+	}
+	return string(body), cookie
+}
+
+func login(email, password string) *http.Request {
+	req := httptest.NewRequest("POST", "/login", strings.NewReader("email="+email+"&password="+password))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestSessionGuardLoginAndLogout(t *testing.T) {
+	app, _ := newAuthApp(t)
+
+	body, guest := send(t, app, httptest.NewRequest("GET", "/me", nil), "")
+	assert.Equal(t, "<nil>", body)
+
+	body, cookie := send(t, app, login("ada@example.com", "wrong"), guest)
+	assert.Equal(t, "false", body)
+
+	body, cookie = send(t, app, login("ada@example.com", "secret"), cookie)
+	assert.Equal(t, "true", body)
+	assert.NotEqual(t, guest, cookie, "login regenerates the session ID")
+
+	body, _ = send(t, app, httptest.NewRequest("GET", "/me", nil), cookie)
+	assert.Equal(t, "1", body)
+
+	_, cookie = send(t, app, httptest.NewRequest("POST", "/logout", nil), cookie)
+	body, _ = send(t, app, httptest.NewRequest("GET", "/me", nil), cookie)
+	assert.Equal(t, "<nil>", body)
+}
+
+func TestManagerActingAs(t *testing.T) {
+	app, manager := newAuthApp(t)
+
+	manager.ActingAs(GenericUser{"id": int64(2)})
+	body, _ := send(t, app, httptest.NewRequest("GET", "/me", nil), "")
+	assert.Equal(t, "2", body)
+
+	manager.ActingAs(nil)
+	body, _ = send(t, app, httptest.NewRequest("GET", "/me", nil), "")
+	assert.Equal(t, "<nil>", body)
+}
+
+func TestSessionGuardRequiresSession(t *testing.T) {
+	manager := NewManager()
+	manager.RegisterProvider("users", &memoryProvider{})
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		guard, err := manager.Guard(c)
+		require.NoError(t, err)
+		_, err = guard.User()
+		return c.SendString(fmt.Sprint(err))
+	})
+
+	body, _ := send(t, app, httptest.NewRequest("GET", "/", nil), "")
+	assert.Equal(t, ErrSessionRequired.Error(), body)
+}
+
+func TestTokenGuard(t *testing.T) {
+	app, _ := newAuthApp(t)
+
+	req := httptest.NewRequest("GET", "/me?guard=api", nil)
+	req.Header.Set("Authorization", "Bearer token-1")
+	body, _ := send(t, app, req, "")
+	assert.Equal(t, "1", body)
+
+	body, _ = send(t, app, httptest.NewRequest("GET", "/me?guard=api&api_token=token-1", nil), "")
+	assert.Equal(t, "1", body)
+
+	body, _ = send(t, app, httptest.NewRequest("GET", "/me?guard=api&api_token=nope", nil), "")
+	assert.Equal(t, "<nil>", body)
+}
+
+func TestTokenGuardHash(t *testing.T) {
+	sum := sha256.Sum256([]byte("plain-token"))
+	manager := NewManager(Config{
+		Default: "api",
+		Guards:  map[string]GuardConfig{"api": {Driver: "token", Provider: "users", Hash: true}},
+	})
+	manager.RegisterProvider("users", &memoryProvider{users: []GenericUser{
+		{"id": int64(2), "api_token": hex.EncodeToString(sum[:])},
+	}})
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		guard, err := manager.Guard(c)
+		if err != nil {
+			return err
+		}
+		return c.SendString(fmt.Sprint(guard.Check()))
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer plain-token")
+	body, _ := send(t, app, req, "")
+	assert.Equal(t, "true", body)
+}
+
+func TestGuardErrors(t *testing.T) {
+	manager := NewManager(Config{
+		Default: "web",
+		Guards: map[string]GuardConfig{
+			"web":    {Driver: "session", Provider: "users"},
+			"custom": {Driver: "magic", Provider: "users"},
+		},
+	})
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		_, err := manager.Guard(c, c.Query("guard"))
+		return c.SendString(err.Error())
+	})
+
+	body, _ := send(t, app, httptest.NewRequest("GET", "/", nil), "")
+	assert.Equal(t, `auth: user provider "users" is not registered`, body)
+	body, _ = send(t, app, httptest.NewRequest("GET", "/?guard=admin", nil), "")
+	assert.Equal(t, `auth: guard "admin" is not defined`, body)
+	body, _ = send(t, app, httptest.NewRequest("GET", "/?guard=custom", nil), "")
+	assert.Equal(t, `auth: guard "custom" uses unknown driver "magic"`, body)
+}
+
+func TestExtendGuardDriver(t *testing.T) {
+	manager := NewManager(Config{
+		Default: "header",
+		Guards:  map[string]GuardConfig{"header": {Driver: "header", Provider: "users"}},
+	})
+	manager.RegisterProvider("users", &memoryProvider{users: []GenericUser{{"id": int64(3), "email": "grace@example.com"}}})
+	manager.Extend("header", func(name string, config GuardConfig, provider contracts.UserProvider, c *fiber.Ctx) (contracts.Guard, error) {
+		guard, err := newTokenGuard(name, config, provider, c)
+		if err != nil {
+			return nil, err
+		}
+		user, err := provider.RetrieveByCredentials(c.UserContext(), map[string]any{"email": c.Get("X-User")})
+		if err != nil {
+			return nil, err
+		}
+		guard.SetUser(user)
+		return guard, nil
+	})
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		guard, err := manager.Guard(c)
+		if err != nil {
+			return err
+		}
+		return c.SendString(fmt.Sprint(guard.ID()))
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-User", "grace@example.com")
+	body, _ := send(t, app, req, "")
+	assert.Equal(t, "3", body)
+}
+
+// newTestUsersConnection creates a users table holding one user on the test
+// database testutil.DatabaseEnv selects. MySQL is skipped because the schema
+// builder has no MySQL grammar.
+func newTestUsersConnection(t *testing.T, id int, email, password string) contracts.Connection {
+	t.Helper()
+	td, cleanup := testutil.SetupDatabaseFor(t, "pgsql", "sqlite")
+	t.Cleanup(cleanup)
+	db := database.NewManager(database.Config{
+		Default: "default",
+		Connections: map[string]database.ConnectionConfig{
+			"default": {
+				Driver:   td.Driver,
+				Host:     td.Host,
+				Port:     td.Port,
+				Database: td.Database,
+				Username: td.Username,
+				Password: td.Password,
+				SSLMode:  "disable",
+			},
+		},
+	})
+	t.Cleanup(func() { db.Close() })
+
+	conn := db.Connection()
+	require.NoError(t, conn.Error())
+	require.NoError(t, schema.NewBuilder(conn.DB(), conn.Driver()).Create("users", func(table *schema.Blueprint) {
+		table.BigIncrements("id")
+		table.String("email")
+		table.String("password")
+	}))
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	dialect := database.NewDialect(conn.Driver())
+	_, err = conn.Exec(fmt.Sprintf("INSERT INTO users (id, email, password) VALUES (%s, %s, %s)",
+		dialect.Placeholder(1), dialect.Placeholder(2), dialect.Placeholder(3)), id, email, string(hash))
+	require.NoError(t, err)
+	return conn
+}
+
+func TestDatabaseUserProvider(t *testing.T) {
+	conn := newTestUsersConnection(t, 7, "ada@example.com", "secret")
+
+	provider := NewDatabaseUserProvider(conn, "users")
+	ctx := context.Background()
+
+	user, err := provider.RetrieveByID(ctx, int64(7))
+	require.NoError(t, err)
+	require.NotNil(t, user)
+	assert.Equal(t, int64(7), user.GetAuthIdentifier())
+
+	user, err = provider.RetrieveByCredentials(ctx, map[string]any{"email": "ada@example.com", "password": "secret"})
+	require.NoError(t, err)
+	require.NotNil(t, user)
+	assert.True(t, provider.ValidateCredentials(user, map[string]any{"password": "secret"}))
+	assert.False(t, provider.ValidateCredentials(user, map[string]any{"password": "wrong"}))
+
+	encoded, err := json.Marshal(user)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "password")
+
+	user, err = provider.RetrieveByCredentials(ctx, map[string]any{"email": "nobody@example.com"})
+	require.NoError(t, err)
+	assert.Nil(t, user)
+
+	_, err = provider.RetrieveByCredentials(ctx, map[string]any{`email" OR 1=1 --`: "x"})
+	assert.ErrorContains(t, err, "invalid credential column")
+}
+
+func TestDatabaseUserProviderRehashesPasswords(t *testing.T) {
+	conn := newTestUsersConnection(t, 1, "ada@example.com", "secret")
+
+	hasher := hashing.NewManager("argon2id")
+	hasher.Extend("argon2id", hashing.NewArgon2id(hashing.Argon2idOptions{Memory: 1024, Time: 1, Threads: 1}))
+	provider := NewDatabaseUserProvider(conn, "users")
+	provider.SetHasher(hasher)
+
+	credentials := map[string]any{"email": "ada@example.com", "password": "secret"}
+	user, err := provider.RetrieveByCredentials(context.Background(), credentials)
+	require.NoError(t, err)
+	require.True(t, provider.ValidateCredentials(user, credentials))
+
+	// The bcrypt hash was replaced with an argon2id one that still validates.
+	user, err = provider.RetrieveByCredentials(context.Background(), credentials)
+	require.NoError(t, err)
+	stored := user.(GenericUser).GetAuthPassword()
+	assert.Equal(t, "argon2id", hashing.Algorithm(stored))
+	assert.False(t, hasher.NeedsRehash(stored))
+	assert.True(t, provider.ValidateCredentials(user, credentials))
+}
+
+func TestNormalizeID(t *testing.T) {
+	assert.Equal(t, int64(7), normalizeID(float64(7)))
+	assert.Equal(t, 7.5, normalizeID(7.5))
+	assert.Equal(t, "abc", normalizeID("abc"))
+}
+
+var (
+	_ contracts.Guard           = (*SessionGuard)(nil)
+	_ contracts.Guard           = (*TokenGuard)(nil)
+	_ contracts.UserProvider    = (*DatabaseUserProvider)(nil)
+	_ contracts.HasAuthPassword = GenericUser{}
+)
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/genesysflow/go-genesys/hashing"
+)
+
+// GenericUser is a user row returned by DatabaseUserProvider, keyed by column.
+type GenericUser map[string]any
+
+// GetAuthIdentifier returns the "id" column.
+func (u GenericUser) GetAuthIdentifier() any {
+	return u["id"]
+}
+
+// GetAuthPassword returns the "password" column.
+func (u GenericUser) GetAuthPassword() string {
+	password, _ := u["password"].(string)
+	return password
+}
+
+// MarshalJSON encodes the user without its password and remember token.
+func (u GenericUser) MarshalJSON() ([]byte, error) {
+	visible := maps.Clone(map[string]any(u))
+	delete(visible, "password")
+	delete(visible, "remember_token")
+	return json.Marshal(visible)
+}
+
+var columnName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// DatabaseUserProvider retrieves users from a table. Passwords are checked
+// against hashes in the "password" column.
+type DatabaseUserProvider struct {
+	conn    contracts.Connection
+	dialect database.Dialect
+	table   string
+	hasher  contracts.Hasher
+}
+
+// NewDatabaseUserProvider creates a provider for users in table. The
+// connection's table prefix is applied to table.
+func NewDatabaseUserProvider(conn contracts.Connection, table string) *DatabaseUserProvider {
+	if table == "" {
+		table = "users"
+	}
+	return &DatabaseUserProvider{
+		conn:    conn,
+		dialect: database.NewDialect(conn.Driver()),
+		table:   conn.Prefix() + table,
+		hasher:  hashing.NewManager(""),
+	}
+}
+
+// SetHasher sets the hasher passwords are checked with. By default bcrypt
+// and argon2id hashes are accepted and passwords are rehashed with bcrypt.
+func (p *DatabaseUserProvider) SetHasher(hasher contracts.Hasher) {
+	p.hasher = hasher
+}
+
+// RetrieveByID returns the user with the given id, or nil.
+func (p *DatabaseUserProvider) RetrieveByID(ctx context.Context, id any) (contracts.Authenticatable, error) {
+	return p.first(ctx, map[string]any{"id": id})
+}
+
+// RetrieveByCredentials returns the first user whose columns match every
+// credential except the password, or nil.
+func (p *DatabaseUserProvider) RetrieveByCredentials(ctx context.Context, credentials map[string]any) (contracts.Authenticatable, error) {
+	conditions := make(map[string]any, len(credentials))
+	for column, value := range credentials {
+		if !strings.Contains(column, "password") {
+			conditions[column] = value
+		}
+	}
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+	return p.first(ctx, conditions)
+}
+
+// ValidateCredentials checks credentials["password"] against the user's
+// hash. When the hash was made with an older algorithm or cost, the
+// password is rehashed and saved; a failed update does not fail the login.
+func (p *DatabaseUserProvider) ValidateCredentials(user contracts.Authenticatable, credentials map[string]any) bool {
+	password, _ := credentials["password"].(string)
+	hashed, ok := user.(contracts.HasAuthPassword)
+	if !ok || password == "" || !p.hasher.Check(password, hashed.GetAuthPassword()) {
+		return false
+	}
+
+	if p.hasher.NeedsRehash(hashed.GetAuthPassword()) {
+		if rehashed, err := p.hasher.Make(password); err == nil {
+			if p.updatePassword(user.GetAuthIdentifier(), rehashed) == nil {
+				if generic, ok := user.(GenericUser); ok {
+					generic["password"] = rehashed
+				}
+			}
+		}
+	}
+	return true
+}
+
+// updatePassword stores a new password hash for the user with the given id.
+func (p *DatabaseUserProvider) updatePassword(id any, hashed string) error {
+	query := fmt.Sprintf(`UPDATE %s SET %s = %s WHERE %s = %s`,
+		p.dialect.Quote(p.table), p.dialect.Quote("password"), p.dialect.Placeholder(1), p.dialect.Quote("id"), p.dialect.Placeholder(2))
+	_, err := p.conn.ExecContext(context.Background(), query, hashed, id)
+	return err
+}
+
+// first returns the first row matching all conditions, or nil.
+func (p *DatabaseUserProvider) first(ctx context.Context, conditions map[string]any) (contracts.Authenticatable, error) {
+	columns := slices.Sorted(maps.Keys(conditions))
+	where := make([]string, len(columns))
+	bindings := make([]any, len(columns))
+	for i, column := range columns {
+		if !columnName.MatchString(column) {
+			return nil, fmt.Errorf("auth: invalid credential column %q", column)
+		}
+		where[i] = p.dialect.Quote(column) + " = " + p.dialect.Placeholder(i+1)
+		bindings[i] = conditions[column]
+	}
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE %s LIMIT 1`, p.dialect.Quote(p.table), strings.Join(where, " AND "))
+	rows, err := p.conn.QueryContext(ctx, query, bindings...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]any, len(names))
+	pointers := make([]any, len(names))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := rows.Scan(pointers...); err != nil {
+		return nil, err
+	}
+
+	user := make(GenericUser, len(names))
+	for i, name := range names {
+		if b, ok := values[i].([]byte); ok {
+			values[i] = string(b)
+		}
+		user[name] = values[i]
+	}
+	return user, nil
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+)
+
+// AuthError is returned when a request is not authenticated, or the user
+// may not perform an action. The error handler responds with its status.
+type AuthError struct {
+	// Status is 401 for unauthenticated requests and 403 for forbidden ones.
+	Status int
+
+	// Guards are the guards that were checked, if any.
+	Guards []string
+
+	message string
+}
+
+// Unauthenticated returns a 401 AuthError for the given guards.
+func Unauthenticated(guards ...string) *AuthError {
+	return &AuthError{Status: http.StatusUnauthorized, Guards: guards, message: "Unauthenticated"}
+}
+
+// Forbidden returns a 403 AuthError, with "This action is unauthorized."
+// as the default message.
+func Forbidden(message ...string) *AuthError {
+	msg := "This action is unauthorized."
+	if len(message) > 0 {
+		msg = message[0]
+	}
+	return &AuthError{Status: http.StatusForbidden, message: msg}
+}
+
+// Error implements the error interface.
+func (e *AuthError) Error() string {
+	if len(e.Guards) > 0 {
+		return "auth: " + strings.ToLower(e.message) + " (guards: " + strings.Join(e.Guards, ", ") + ")"
+	}
+	return "auth: " + strings.ToLower(e.message)
+}
+
+// StatusCode returns the HTTP status code.
+func (e *AuthError) StatusCode() int {
+	return e.Status
+}
+
+// Message returns the message shown to the client.
+func (e *AuthError) Message() string {
+	return e.message
+}
+
+// Unwrap returns nil; auth errors don't wrap another error.
+func (e *AuthError) Unwrap() error {
+	return nil
+}
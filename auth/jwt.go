@@ -0,0 +1,480 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// Token types carried in the "type" claim.
+const (
+	AccessToken  = "access"
+	RefreshToken = "refresh"
+)
+
+// ErrInvalidToken is returned for tokens that are malformed, badly signed,
+// expired, of the wrong type, or invalidated.
+var ErrInvalidToken = errors.New("auth: invalid token")
+
+// JWTConfig configures signing and verification of JSON Web Tokens.
+type JWTConfig struct {
+	// Algorithm is HS256 (the default), HS384, HS512, RS256, RS384, RS512,
+	// ES256, ES384, ES512 or EdDSA.
+	Algorithm string
+
+	// Secret is the key for the HS algorithms.
+	Secret []byte
+
+	// PrivateKey signs tokens for the RS, ES and EdDSA algorithms.
+	PrivateKey crypto.Signer
+
+	// PublicKey verifies tokens. It defaults to PrivateKey's public key, so
+	// services that only verify tokens set just this.
+	PublicKey crypto.PublicKey
+
+	// Issuer is set as the "iss" claim and required when verifying.
+	Issuer string
+
+	// AccessTTL defaults to 15 minutes.
+	AccessTTL time.Duration
+
+	// RefreshTTL defaults to 14 days.
+	RefreshTTL time.Duration
+
+	// Leeway tolerates clock skew when checking expiry.
+	Leeway time.Duration
+
+	// Invalidator records invalidated tokens. Defaults to the manager's.
+	Invalidator TokenInvalidator
+}
+
+// Claims are the claims of tokens issued by JWT.
+type Claims struct {
+	Subject   string `json:"sub"`
+	Issuer    string `json:"iss,omitempty"`
+	ID        string `json:"jti"`
+	Type      string `json:"type"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// UserID returns the subject, as an int64 when it is numeric.
+func (c *Claims) UserID() any {
+	if id, err := strconv.ParseInt(c.Subject, 10, 64); err == nil {
+		return id
+	}
+	return c.Subject
+}
+
+// TokenPair is an access token and the refresh token that renews it.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// TokenInvalidator revokes tokens before they expire, on logout and when
+// refresh tokens are used. Implement it to add other revocation rules, such
+// as rejecting tokens issued before a password change.
+type TokenInvalidator interface {
+	// Invalidate revokes a token.
+	Invalidate(ctx context.Context, claims *Claims) error
+
+	// IsInvalidated reports whether a token has been revoked.
+	IsInvalidated(ctx context.Context, claims *Claims) (bool, error)
+}
+
+// CacheInvalidator keeps revoked token IDs in a cache store until the
+// tokens would have expired anyway.
+type CacheInvalidator struct {
+	store      cache.Store
+	repository *cache.Repository
+}
+
+// NewCacheInvalidator creates an invalidator on a cache store. Use a shared
+// store such as Redis when running several instances.
+func NewCacheInvalidator(store cache.Store) *CacheInvalidator {
+	return &CacheInvalidator{store: store, repository: cache.NewRepository(store)}
+}
+
+// Invalidate revokes a token until its expiry.
+func (i *CacheInvalidator) Invalidate(ctx context.Context, claims *Claims) error {
+	ttl := time.Until(time.Unix(claims.ExpiresAt, 0))
+	if ttl <= 0 {
+		return nil
+	}
+	return i.store.Put("jwt:invalidated:"+claims.ID, true, ttl)
+}
+
+// IsInvalidated reports whether a token has been revoked.
+func (i *CacheInvalidator) IsInvalidated(ctx context.Context, claims *Claims) (bool, error) {
+	value, err := i.store.Get("jwt:invalidated:" + claims.ID)
+	if err != nil {
+		return false, err
+	}
+	return value != nil, nil
+}
+
+// Claim revokes a token until its expiry and reports whether it wasn't
+// revoked already. It is atomic on stores implementing cache.Adder.
+func (i *CacheInvalidator) Claim(ctx context.Context, claims *Claims) (bool, error) {
+	ttl := time.Until(time.Unix(claims.ExpiresAt, 0))
+	if ttl <= 0 {
+		return false, nil
+	}
+	return i.repository.Add("jwt:invalidated:"+claims.ID, true, ttl)
+}
+
+// TokenClaimer is implemented by invalidators that can revoke a token and
+// report whether it was already revoked in one step. Refresh uses it so two
+// concurrent refreshes with the same token can't both succeed; with other
+// invalidators the token is checked and revoked separately.
+type TokenClaimer interface {
+	// Claim revokes a token and reports whether it wasn't revoked already.
+	Claim(ctx context.Context, claims *Claims) (bool, error)
+}
+
+// JWT issues and verifies signed tokens.
+type JWT struct {
+	config JWTConfig
+}
+
+// NewJWT validates the configuration and creates a JWT issuer.
+func NewJWT(config JWTConfig) (*JWT, error) {
+	if config.Algorithm == "" {
+		config.Algorithm = "HS256"
+	}
+	if config.AccessTTL <= 0 {
+		config.AccessTTL = 15 * time.Minute
+	}
+	if config.RefreshTTL <= 0 {
+		config.RefreshTTL = 14 * 24 * time.Hour
+	}
+	if config.PublicKey == nil && config.PrivateKey != nil {
+		config.PublicKey = config.PrivateKey.Public()
+	}
+
+	switch config.Algorithm {
+	case "HS256", "HS384", "HS512":
+		if len(config.Secret) < 32 {
+			return nil, fmt.Errorf("auth: %s needs a secret of at least 32 bytes", config.Algorithm)
+		}
+	case "RS256", "RS384", "RS512":
+		if _, ok := config.PublicKey.(*rsa.PublicKey); !ok {
+			return nil, fmt.Errorf("auth: %s needs an RSA key", config.Algorithm)
+		}
+	case "ES256", "ES384", "ES512":
+		key, ok := config.PublicKey.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("auth: %s needs an ECDSA key", config.Algorithm)
+		}
+		curve := curveFor(config.Algorithm)
+		if private, ok := config.PrivateKey.(*ecdsa.PrivateKey); ok && private.Curve != curve {
+			key = &private.PublicKey
+		}
+		if key.Curve != curve {
+			return nil, fmt.Errorf("auth: %s needs a key on the %s curve, got %s", config.Algorithm, curve.Params().Name, key.Curve.Params().Name)
+		}
+	case "EdDSA":
+		if _, ok := config.PublicKey.(ed25519.PublicKey); !ok {
+			return nil, fmt.Errorf("auth: EdDSA needs an Ed25519 key")
+		}
+	default:
+		return nil, fmt.Errorf("auth: unsupported JWT algorithm %q", config.Algorithm)
+	}
+
+	return &JWT{config: config}, nil
+}
+
+// Config returns the JWT configuration.
+func (j *JWT) Config() JWTConfig {
+	return j.config
+}
+
+// Issue creates an access and refresh token for a user.
+func (j *JWT) Issue(user contracts.Authenticatable) (*TokenPair, error) {
+	subject := fmt.Sprint(user.GetAuthIdentifier())
+	now := time.Now()
+
+	access, err := j.Sign(j.claims(subject, AccessToken, now, j.config.AccessTTL))
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := j.Sign(j.claims(subject, RefreshToken, now, j.config.RefreshTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(j.config.AccessTTL.Seconds()),
+	}, nil
+}
+
+func (j *JWT) claims(subject, tokenType string, now time.Time, ttl time.Duration) *Claims {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return &Claims{
+		Subject:   subject,
+		Issuer:    j.config.Issuer,
+		ID:        hex.EncodeToString(id),
+		Type:      tokenType,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+}
+
+// Sign encodes and signs claims.
+func (j *JWT) Sign(claims *Claims) (string, error) {
+	if j.config.PrivateKey == nil && j.config.Secret == nil {
+		return "", errors.New("auth: JWT has no signing key")
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": j.config.Algorithm, "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := encodeSegment(header) + "." + encodeSegment(payload)
+
+	signature, err := j.sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + encodeSegment(signature), nil
+}
+
+// Parse verifies a token of the given type and returns its claims. Tokens
+// that fail verification return an error wrapping ErrInvalidToken.
+func (j *JWT) Parse(ctx context.Context, token, tokenType string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != j.config.Algorithm {
+		return nil, fmt.Errorf("%w: unexpected algorithm", ErrInvalidToken)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !j.verify([]byte(parts[0]+"."+parts[1]), signature) {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims", ErrInvalidToken)
+	}
+	if claims.Type != tokenType {
+		return nil, fmt.Errorf("%w: not an %s token", ErrInvalidToken, tokenType)
+	}
+	if time.Now().Add(-j.config.Leeway).Unix() >= claims.ExpiresAt {
+		return nil, fmt.Errorf("%w: expired", ErrInvalidToken)
+	}
+	if j.config.Issuer != "" && claims.Issuer != j.config.Issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+
+	if j.config.Invalidator != nil {
+		invalidated, err := j.config.Invalidator.IsInvalidated(ctx, &claims)
+		if err != nil {
+			return nil, err
+		}
+		if invalidated {
+			return nil, fmt.Errorf("%w: invalidated", ErrInvalidToken)
+		}
+	}
+	return &claims, nil
+}
+
+// Invalidate revokes a token of any type. Invalid tokens are ignored.
+func (j *JWT) Invalidate(ctx context.Context, token string) error {
+	for _, tokenType := range []string{AccessToken, RefreshToken} {
+		if claims, err := j.Parse(ctx, token, tokenType); err == nil {
+			return j.invalidate(ctx, claims)
+		} else if !errors.Is(err, ErrInvalidToken) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j *JWT) invalidate(ctx context.Context, claims *Claims) error {
+	if j.config.Invalidator == nil {
+		return errors.New("auth: JWT has no invalidator")
+	}
+	return j.config.Invalidator.Invalidate(ctx, claims)
+}
+
+// claim revokes a token for single use, failing if it was already revoked.
+func (j *JWT) claim(ctx context.Context, claims *Claims) error {
+	claimer, ok := j.config.Invalidator.(TokenClaimer)
+	if !ok {
+		return j.invalidate(ctx, claims)
+	}
+	claimed, err := claimer.Claim(ctx, claims)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return fmt.Errorf("%w: token was already used", ErrInvalidToken)
+	}
+	return nil
+}
+
+func (j *JWT) sign(input []byte) ([]byte, error) {
+	switch alg := j.config.Algorithm; {
+	case strings.HasPrefix(alg, "HS"):
+		mac := hmac.New(hashFor(alg).New, j.config.Secret)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	case alg == "EdDSA":
+		return j.config.PrivateKey.Sign(rand.Reader, input, crypto.Hash(0))
+	case strings.HasPrefix(alg, "RS"):
+		digest := digest(alg, input)
+		return j.config.PrivateKey.Sign(rand.Reader, digest, hashFor(alg))
+	default:
+		key, ok := j.config.PrivateKey.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("auth: %s needs an ECDSA private key", alg)
+		}
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest(alg, input))
+		if err != nil {
+			return nil, err
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		signature := make([]byte, 2*size)
+		r.FillBytes(signature[:size])
+		s.FillBytes(signature[size:])
+		return signature, nil
+	}
+}
+
+func (j *JWT) verify(input, signature []byte) bool {
+	switch alg := j.config.Algorithm; {
+	case strings.HasPrefix(alg, "HS"):
+		mac := hmac.New(hashFor(alg).New, j.config.Secret)
+		mac.Write(input)
+		return hmac.Equal(signature, mac.Sum(nil))
+	case alg == "EdDSA":
+		return ed25519.Verify(j.config.PublicKey.(ed25519.PublicKey), input, signature)
+	case strings.HasPrefix(alg, "RS"):
+		return rsa.VerifyPKCS1v15(j.config.PublicKey.(*rsa.PublicKey), hashFor(alg), digest(alg, input), signature) == nil
+	default:
+		key := j.config.PublicKey.(*ecdsa.PublicKey)
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(key, digest(alg, input), r, s)
+	}
+}
+
+func hashFor(alg string) crypto.Hash {
+	switch alg[2:] {
+	case "384":
+		return crypto.SHA384
+	case "512":
+		return crypto.SHA512
+	}
+	return crypto.SHA256
+}
+
+// curveFor returns the curve an ES algorithm signs with.
+func curveFor(alg string) elliptic.Curve {
+	switch alg[2:] {
+	case "384":
+		return elliptic.P384()
+	case "512":
+		return elliptic.P521()
+	}
+	return elliptic.P256()
+}
+
+func digest(alg string, input []byte) []byte {
+	switch hashFor(alg) {
+	case crypto.SHA384:
+		sum := sha512.Sum384(input)
+		return sum[:]
+	case crypto.SHA512:
+		sum := sha512.Sum512(input)
+		return sum[:]
+	}
+	sum := sha256.Sum256(input)
+	return sum[:]
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeSegment(segment string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// ParsePrivateKeyPEM parses a PKCS#8, PKCS#1 RSA or SEC 1 EC private key.
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("auth: no PEM block in private key")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("auth: unsupported private key")
+}
+
+// ParsePublicKeyPEM parses a PKIX or PKCS#1 RSA public key.
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("auth: no PEM block in public key")
+	}
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("auth: unsupported public key")
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/gofiber/fiber/v2"
+)
+
+// JWTGuard authenticates stateless API requests by a signed access token
+// sent as a bearer token.
+type JWTGuard struct {
+	issuer   *JWT
+	provider contracts.UserProvider
+	c        *fiber.Ctx
+	user     contracts.Authenticatable
+	claims   *Claims
+	tokens   *TokenPair
+	resolved bool
+}
+
+func (m *Manager) newJWTGuard(name string, config GuardConfig, provider contracts.UserProvider, c *fiber.Ctx) (contracts.Guard, error) {
+	issuer, err := m.JWT(name)
+	if err != nil {
+		return nil, err
+	}
+	return &JWTGuard{
+		issuer:   issuer,
+		provider: provider,
+		c:        c,
+	}, nil
+}
+
+// token returns the bearer token sent with the request.
+func (g *JWTGuard) token() string {
+	token, _ := strings.CutPrefix(g.c.Get(fiber.HeaderAuthorization), "Bearer ")
+	return strings.TrimSpace(token)
+}
+
+// User returns the user the request's access token belongs to, or nil.
+// Missing, expired and invalidated tokens are treated as guests.
+func (g *JWTGuard) User() (contracts.Authenticatable, error) {
+	if g.resolved {
+		return g.user, nil
+	}
+
+	token := g.token()
+	if token == "" {
+		g.resolved = true
+		return nil, nil
+	}
+	claims, err := g.issuer.Parse(g.c.UserContext(), token, AccessToken)
+	if errors.Is(err, ErrInvalidToken) {
+		g.resolved = true
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := g.provider.RetrieveByID(g.c.UserContext(), claims.UserID())
+	if err != nil {
+		return nil, err
+	}
+	g.user, g.claims, g.resolved = user, claims, true
+	return user, nil
+}
+
+// Claims returns the claims of the request's access token, or nil.
+func (g *JWTGuard) Claims() *Claims {
+	g.User()
+	return g.claims
+}
+
+// Check reports whether the request has a valid access token.
+func (g *JWTGuard) Check() bool {
+	user, _ := g.User()
+	return user != nil
+}
+
+// Guest reports whether the request has no valid access token.
+func (g *JWTGuard) Guest() bool {
+	return !g.Check()
+}
+
+// ID returns the authenticated user's identifier, or nil.
+func (g *JWTGuard) ID() any {
+	if user, _ := g.User(); user != nil {
+		return user.GetAuthIdentifier()
+	}
+	return nil
+}
+
+// Validate reports whether the credentials match a user.
+func (g *JWTGuard) Validate(credentials map[string]any) (bool, error) {
+	user, err := validateCredentials(g.c, g.provider, credentials)
+	return user != nil, err
+}
+
+// Attempt logs in the user the credentials match, issuing tokens for them.
+func (g *JWTGuard) Attempt(credentials map[string]any) (bool, error) {
+	user, err := validateCredentials(g.c, g.provider, credentials)
+	if err != nil || user == nil {
+		return false, err
+	}
+	return true, g.Login(user)
+}
+
+// Login issues tokens for user, available from Tokens, and authenticates
+// the rest of the request as them.
+func (g *JWTGuard) Login(user contracts.Authenticatable) error {
+	tokens, err := g.issuer.Issue(user)
+	if err != nil {
+		return err
+	}
+	g.tokens = tokens
+	g.SetUser(user)
+	return nil
+}
+
+// Tokens returns the tokens issued by Attempt or Login, or nil.
+func (g *JWTGuard) Tokens() *TokenPair {
+	return g.tokens
+}
+
+// Logout invalidates the request's access token and clears the user.
+func (g *JWTGuard) Logout() error {
+	if claims := g.Claims(); claims != nil {
+		if err := g.issuer.invalidate(g.c.UserContext(), claims); err != nil {
+			return err
+		}
+	}
+	g.user, g.claims, g.tokens = nil, nil, nil
+	return nil
+}
+
+// SetUser sets the user for the rest of the request.
+func (g *JWTGuard) SetUser(user contracts.Authenticatable) {
+	g.user, g.resolved = user, true
+}
@@ -0,0 +1,281 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func newJWTManager(t *testing.T) *Manager {
+	t.Helper()
+
+	manager := NewManager(Config{
+		Default: "api",
+		Guards: map[string]GuardConfig{
+			"api": {Driver: "jwt", Provider: "users", JWT: JWTConfig{Secret: testSecret, Issuer: "genesys"}},
+		},
+	})
+	manager.RegisterProvider("users", &memoryProvider{users: []GenericUser{
+		{"id": int64(1), "email": "ada@example.com", "password": "secret"},
+	}})
+	return manager
+}
+
+func TestJWTAlgorithms(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	configs := map[string]JWTConfig{
+		"HS256": {Algorithm: "HS256", Secret: testSecret},
+		"HS512": {Algorithm: "HS512", Secret: testSecret},
+		"RS256": {Algorithm: "RS256", PrivateKey: rsaKey},
+		"ES256": {Algorithm: "ES256", PrivateKey: ecKey},
+		"EdDSA": {Algorithm: "EdDSA", PrivateKey: edKey},
+	}
+	for name, config := range configs {
+		t.Run(name, func(t *testing.T) {
+			issuer, err := NewJWT(config)
+			require.NoError(t, err)
+
+			tokens, err := issuer.Issue(GenericUser{"id": int64(7)})
+			require.NoError(t, err)
+
+			claims, err := issuer.Parse(context.Background(), tokens.AccessToken, AccessToken)
+			require.NoError(t, err)
+			assert.Equal(t, int64(7), claims.UserID())
+
+			// A verifier with only the public key accepts the token.
+			if config.PrivateKey != nil {
+				verifier, err := NewJWT(JWTConfig{Algorithm: config.Algorithm, PublicKey: config.PrivateKey.Public()})
+				require.NoError(t, err)
+				_, err = verifier.Parse(context.Background(), tokens.AccessToken, AccessToken)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestJWTRejectsInvalidTokens(t *testing.T) {
+	issuer, err := NewJWT(JWTConfig{Secret: testSecret, Issuer: "genesys"})
+	require.NoError(t, err)
+	tokens, err := issuer.Issue(GenericUser{"id": int64(1)})
+	require.NoError(t, err)
+
+	expired, err := issuer.Sign(&Claims{Subject: "1", Issuer: "genesys", ID: "x", Type: AccessToken, ExpiresAt: time.Now().Add(-time.Minute).Unix()})
+	require.NoError(t, err)
+	other, err := NewJWT(JWTConfig{Secret: []byte("another-secret-that-is-long-enough")})
+	require.NoError(t, err)
+	forged, err := other.Sign(&Claims{Subject: "1", Issuer: "genesys", ID: "y", Type: AccessToken, ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	require.NoError(t, err)
+
+	parts := strings.Split(tokens.AccessToken, ".")
+	none := encodeSegment([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + parts[1] + "."
+
+	for name, token := range map[string]string{
+		"refresh as access": tokens.RefreshToken,
+		"expired":           expired,
+		"forged":            forged,
+		"alg none":          none,
+		"malformed":         "not-a-token",
+	} {
+		_, err := issuer.Parse(context.Background(), token, AccessToken)
+		assert.ErrorIs(t, err, ErrInvalidToken, name)
+	}
+}
+
+func TestNewJWTValidatesKeys(t *testing.T) {
+	_, err := NewJWT(JWTConfig{Secret: []byte("short")})
+	assert.ErrorContains(t, err, "at least 32 bytes")
+
+	_, err = NewJWT(JWTConfig{Algorithm: "RS256", Secret: testSecret})
+	assert.ErrorContains(t, err, "needs an RSA key")
+
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, err = NewJWT(JWTConfig{Algorithm: "ES384", PrivateKey: p256})
+	assert.ErrorContains(t, err, "ES384 needs a key on the P-384 curve, got P-256")
+	_, err = NewJWT(JWTConfig{Algorithm: "ES512", PublicKey: &p256.PublicKey})
+	assert.ErrorContains(t, err, "needs a key on the P-521 curve")
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	_, err = NewJWT(JWTConfig{Algorithm: "ES384", PrivateKey: p256, PublicKey: &p384.PublicKey})
+	assert.ErrorContains(t, err, "got P-256")
+	_, err = NewJWT(JWTConfig{Algorithm: "ES384", PrivateKey: p384})
+	assert.NoError(t, err)
+
+	_, err = NewJWT(JWTConfig{Algorithm: "none"})
+	assert.ErrorContains(t, err, "unsupported JWT algorithm")
+}
+
+func TestManagerAttemptRefreshInvalidate(t *testing.T) {
+	manager := newJWTManager(t)
+	ctx := context.Background()
+
+	_, err := manager.Attempt(ctx, map[string]any{"email": "ada@example.com", "password": "wrong"})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	tokens, err := manager.Attempt(ctx, map[string]any{"email": "ada@example.com", "password": "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", tokens.TokenType)
+	assert.Equal(t, 900, tokens.ExpiresIn)
+
+	// Refresh tokens are single use.
+	refreshed, err := manager.Refresh(ctx, tokens.RefreshToken)
+	require.NoError(t, err)
+	_, err = manager.Refresh(ctx, tokens.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	issuer, err := manager.JWT()
+	require.NoError(t, err)
+	require.NoError(t, manager.Invalidate(ctx, refreshed.AccessToken))
+	_, err = issuer.Parse(ctx, refreshed.AccessToken, AccessToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestManagerJWTRequiresJWTGuard(t *testing.T) {
+	_, err := NewManager().JWT("web")
+	assert.ErrorContains(t, err, "does not use the jwt driver")
+}
+
+func TestJWTGuard(t *testing.T) {
+	manager := newJWTManager(t)
+	tokens, err := manager.Attempt(context.Background(), map[string]any{"email": "ada@example.com", "password": "secret"})
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Get("/me", func(c *fiber.Ctx) error {
+		guard, err := manager.Guard(c)
+		if err != nil {
+			return err
+		}
+		user, err := guard.User()
+		if err != nil || user == nil {
+			return fiber.ErrUnauthorized
+		}
+		return c.JSON(guard.ID())
+	})
+	app.Post("/logout", func(c *fiber.Ctx) error {
+		guard, err := manager.Guard(c)
+		if err != nil {
+			return err
+		}
+		return guard.Logout()
+	})
+
+	request := func(method, path string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusOK, request("GET", "/me"))
+	assert.Equal(t, fiber.StatusOK, request("POST", "/logout"))
+	assert.Equal(t, fiber.StatusUnauthorized, request("GET", "/me"))
+}
+
+func TestJWTGuardAttemptIssuesTokens(t *testing.T) {
+	manager := newJWTManager(t)
+
+	app := fiber.New()
+	app.Post("/login", func(c *fiber.Ctx) error {
+		guard, err := manager.Guard(c)
+		if err != nil {
+			return err
+		}
+		ok, err := guard.Attempt(map[string]any{"email": "ada@example.com", "password": "secret"})
+		if err != nil || !ok {
+			return fiber.ErrUnauthorized
+		}
+		return c.JSON(guard.(*JWTGuard).Tokens())
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/login", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestParseKeyPEM(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	private, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	public, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+
+	signer, err := ParsePrivateKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: private}))
+	require.NoError(t, err)
+	assert.True(t, key.Equal(signer))
+
+	parsed, err := ParsePublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public}))
+	require.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(parsed))
+}
+
+// barrierProvider holds every user lookup until n of them are waiting, so
+// concurrent refreshes all get past parsing the token before any claims it.
+type barrierProvider struct {
+	*memoryProvider
+	arrived sync.WaitGroup
+}
+
+func (p *barrierProvider) RetrieveByID(ctx context.Context, id any) (contracts.Authenticatable, error) {
+	p.arrived.Done()
+	p.arrived.Wait()
+	return p.memoryProvider.RetrieveByID(ctx, id)
+}
+
+func TestManagerRefreshConcurrently(t *testing.T) {
+	const refreshes = 10
+	manager := newJWTManager(t)
+	provider := &barrierProvider{memoryProvider: &memoryProvider{users: []GenericUser{
+		{"id": int64(1), "email": "ada@example.com", "password": "secret"},
+	}}}
+	provider.arrived.Add(refreshes)
+	manager.RegisterProvider("users", provider)
+
+	ctx := context.Background()
+	tokens, err := manager.Attempt(ctx, map[string]any{"email": "ada@example.com", "password": "secret"})
+	require.NoError(t, err)
+
+	var (
+		wg        sync.WaitGroup
+		refreshed atomic.Int32
+	)
+	for range refreshes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := manager.Refresh(ctx, tokens.RefreshToken); err == nil {
+				refreshed.Add(1)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidToken)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), refreshed.Load())
+}
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"math"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/session"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SessionGuard remembers the logged in user's identifier in the session.
+type SessionGuard struct {
+	name     string
+	provider contracts.UserProvider
+	c        *fiber.Ctx
+	user     contracts.Authenticatable
+	resolved bool
+}
+
+func newSessionGuard(name string, config GuardConfig, provider contracts.UserProvider, c *fiber.Ctx) (contracts.Guard, error) {
+	return &SessionGuard{
+		name:     name,
+		provider: provider,
+		c:        c,
+	}, nil
+}
+
+// key is the session key the user's identifier is stored under.
+func (g *SessionGuard) key() string {
+	return "login_" + g.name
+}
+
+func (g *SessionGuard) session() (*session.Session, error) {
+	sess := session.GetFromContext(g.c)
+	if sess == nil {
+		return nil, ErrSessionRequired
+	}
+	return sess, nil
+}
+
+// User returns the user whose identifier is in the session, or nil.
+func (g *SessionGuard) User() (contracts.Authenticatable, error) {
+	if g.resolved {
+		return g.user, nil
+	}
+
+	sess, err := g.session()
+	if err != nil {
+		return nil, err
+	}
+	id := sess.Get(g.key())
+	if id == nil {
+		g.resolved = true
+		return nil, nil
+	}
+
+	user, err := g.provider.RetrieveByID(g.c.UserContext(), normalizeID(id))
+	if err != nil {
+		return nil, err
+	}
+	g.user, g.resolved = user, true
+	return user, nil
+}
+
+// Check reports whether a user is logged in.
+func (g *SessionGuard) Check() bool {
+	user, _ := g.User()
+	return user != nil
+}
+
+// Guest reports whether no user is logged in.
+func (g *SessionGuard) Guest() bool {
+	return !g.Check()
+}
+
+// ID returns the logged in user's identifier, or nil.
+func (g *SessionGuard) ID() any {
+	if user, _ := g.User(); user != nil {
+		return user.GetAuthIdentifier()
+	}
+	return nil
+}
+
+// Validate checks credentials without logging the user in.
+func (g *SessionGuard) Validate(credentials map[string]any) (bool, error) {
+	user, err := validateCredentials(g.c, g.provider, credentials)
+	return user != nil, err
+}
+
+// Attempt logs the user in if the credentials are valid.
+func (g *SessionGuard) Attempt(credentials map[string]any) (bool, error) {
+	user, err := validateCredentials(g.c, g.provider, credentials)
+	if err != nil || user == nil {
+		return false, err
+	}
+	return true, g.Login(user)
+}
+
+// Login stores the user in the session under a new session ID.
+func (g *SessionGuard) Login(user contracts.Authenticatable) error {
+	sess, err := g.session()
+	if err != nil {
+		return err
+	}
+	if err := sess.Regenerate(); err != nil {
+		return err
+	}
+	if err := sess.Put(g.key(), user.GetAuthIdentifier()); err != nil {
+		return err
+	}
+	g.SetUser(user)
+	return nil
+}
+
+// Logout removes the user from the session and regenerates its ID.
+func (g *SessionGuard) Logout() error {
+	sess, err := g.session()
+	if err != nil {
+		return err
+	}
+	if err := sess.Forget(g.key()); err != nil {
+		return err
+	}
+	g.SetUser(nil)
+	return sess.Regenerate()
+}
+
+// SetUser sets the user for the rest of the request without logging them in.
+func (g *SessionGuard) SetUser(user contracts.Authenticatable) {
+	g.user, g.resolved = user, true
+}
+
+// normalizeID turns whole float64 identifiers, which integer IDs become
+// after a round trip through the session, back into int64.
+func normalizeID(id any) any {
+	if f, ok := id.(float64); ok && f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return int64(f)
+	}
+	return id
+}
@@ -0,0 +1,104 @@
+// Package broadcasting publishes server-side events to browsers. Events
+// implementing ShouldBroadcast are sent to their channels as JSON messages
+// through the built-in WebSocket hub, or over Redis pub/sub so every
+// instance's hub delivers them. Private and presence channels are
+// authorized by callbacks registered with Manager.Channel.
+package broadcasting
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/events"
+)
+
+// Channel name prefixes, as understood by Pusher-style JavaScript clients.
+const (
+	PrivatePrefix  = "private-"
+	PresencePrefix = "presence-"
+)
+
+// ShouldBroadcast is implemented by events that are sent to browsers.
+type ShouldBroadcast interface {
+	// BroadcastOn returns the channels the event is sent to.
+	BroadcastOn() []string
+}
+
+// BroadcastAs is implemented by events that choose their broadcast name.
+// Otherwise the event's Name, or its type name, is used.
+type BroadcastAs interface {
+	BroadcastAs() string
+}
+
+// BroadcastWith is implemented by events that choose their payload.
+// Otherwise the event itself is JSON encoded.
+type BroadcastWith interface {
+	BroadcastWith() any
+}
+
+// Public returns the name of a public channel, which anyone may subscribe to.
+func Public(name string) string {
+	return name
+}
+
+// Private returns the name of a private channel, whose subscribers must be
+// authorized.
+func Private(name string) string {
+	return PrivatePrefix + name
+}
+
+// Presence returns the name of a presence channel: a private channel whose
+// subscribers are told who else is subscribed.
+func Presence(name string) string {
+	return PresencePrefix + name
+}
+
+// Message is the JSON form of everything sent to WebSocket clients:
+// broadcast events, subscription results and presence changes.
+type Message struct {
+	Event   string `json:"event"`
+	Channel string `json:"channel,omitempty"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// Broadcaster sends an event to channels.
+type Broadcaster interface {
+	Broadcast(ctx context.Context, channels []string, event string, data any) error
+}
+
+// NullBroadcaster drops every event, for tests and apps without clients.
+type NullBroadcaster struct{}
+
+// Broadcast does nothing.
+func (NullBroadcaster) Broadcast(ctx context.Context, channels []string, event string, data any) error {
+	return nil
+}
+
+// eventName returns the name an event is broadcast as.
+func eventName(event ShouldBroadcast) string {
+	if named, ok := event.(BroadcastAs); ok {
+		return named.BroadcastAs()
+	}
+	if named, ok := event.(events.Event); ok {
+		return named.Name()
+	}
+	t := reflect.TypeOf(event)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// eventData returns the payload an event is broadcast with.
+func eventData(event ShouldBroadcast) any {
+	if with, ok := event.(BroadcastWith); ok {
+		return with.BroadcastWith()
+	}
+	return event
+}
+
+// isPrivate reports whether subscribing to channel needs authorization.
+func isPrivate(channel string) bool {
+	return strings.HasPrefix(channel, PrivatePrefix) || strings.HasPrefix(channel, PresencePrefix)
+}
@@ -0,0 +1,244 @@
+package broadcasting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/events"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testUser struct{ id string }
+
+func (u testUser) GetAuthIdentifier() any { return u.id }
+
+type orderShipped struct {
+	OrderID string `json:"order_id"`
+}
+
+func (e orderShipped) Name() string          { return "order.shipped" }
+func (e orderShipped) BroadcastOn() []string { return []string{Private("orders." + e.OrderID)} }
+
+type statsUpdated struct{ Visitors int }
+
+func (statsUpdated) BroadcastOn() []string { return []string{Public("stats")} }
+func (statsUpdated) BroadcastAs() string   { return "stats" }
+func (e statsUpdated) BroadcastWith() any  { return map[string]int{"visitors": e.Visitors} }
+
+type recordingBroadcaster struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func (b *recordingBroadcaster) Broadcast(ctx context.Context, channels []string, event string, data any) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, channel := range channels {
+		b.messages = append(b.messages, Message{Event: event, Channel: channel, Data: data})
+	}
+	return nil
+}
+
+func newTestManager() *Manager {
+	m := NewManager(NullBroadcaster{})
+	m.Channel("orders.{id}", func(user contracts.Authenticatable, params map[string]string) (any, bool) {
+		return nil, user.GetAuthIdentifier() == "u"+params["id"]
+	})
+	m.Channel("chat.{room}", func(user contracts.Authenticatable, params map[string]string) (any, bool) {
+		return map[string]string{"name": "User " + user.GetAuthIdentifier().(string)}, true
+	})
+	return m
+}
+
+func TestAuthorize(t *testing.T) {
+	m := newTestManager()
+
+	_, err := m.Authorize(nil, "stats")
+	assert.NoError(t, err, "public channels are open")
+
+	_, err = m.Authorize(nil, Private("orders.1"))
+	assert.ErrorIs(t, err, ErrForbidden)
+	_, err = m.Authorize(testUser{"u1"}, Private("orders.1"))
+	assert.NoError(t, err)
+	_, err = m.Authorize(testUser{"u2"}, Private("orders.1"))
+	assert.ErrorIs(t, err, ErrForbidden)
+	_, err = m.Authorize(testUser{"u1"}, Private("orders.1.items"))
+	assert.ErrorIs(t, err, ErrForbidden, "placeholders match one segment")
+	_, err = m.Authorize(testUser{"u1"}, Private("invoices.1"))
+	assert.ErrorIs(t, err, ErrForbidden, "channels without an authorizer are closed")
+
+	info, err := m.Authorize(testUser{"u1"}, Presence("chat.lobby"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"name": "User u1"}, info)
+}
+
+func TestListenBroadcastsEvents(t *testing.T) {
+	recorder := &recordingBroadcaster{}
+	m := NewManager(recorder)
+	dispatcher := events.NewDispatcher()
+	m.Listen(dispatcher)
+
+	require.NoError(t, dispatcher.Dispatch(orderShipped{OrderID: "7"}))
+	require.NoError(t, m.Broadcast(context.Background(), statsUpdated{Visitors: 3}))
+
+	assert.Equal(t, []Message{
+		{Event: "order.shipped", Channel: "private-orders.7", Data: orderShipped{OrderID: "7"}},
+		{Event: "stats", Channel: "stats", Data: map[string]int{"visitors": 3}},
+	}, recorder.messages)
+}
+
+// fakePubSub delivers published messages to subscribers in process.
+type fakePubSub struct {
+	mu          sync.Mutex
+	subscribers map[string][]func(string)
+	subscribed  chan struct{}
+}
+
+func (p *fakePubSub) Publish(ctx context.Context, channel, message string) error {
+	p.mu.Lock()
+	handlers := p.subscribers[channel]
+	p.mu.Unlock()
+	for _, handler := range handlers {
+		handler(message)
+	}
+	return nil
+}
+
+func (p *fakePubSub) Subscribe(ctx context.Context, channel string, handler func(string)) error {
+	p.mu.Lock()
+	p.subscribers[channel] = append(p.subscribers[channel], handler)
+	p.mu.Unlock()
+	close(p.subscribed)
+	<-ctx.Done()
+	return nil
+}
+
+// serve mounts the manager's WebSocket handler. Clients pick their user
+// with the user query parameter.
+func serve(t *testing.T, m *Manager) (string, *http.Router) {
+	t.Helper()
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(testutil.NewMockApplication(), app)
+	router.WebSocket("/broadcasting", m.WebSocketHandler(), func(ctx *http.Context, next func() error) error {
+		if id := ctx.Query("user"); id != "" {
+			ctx.Set("user", testUser{id})
+		}
+		return next()
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go app.Listener(ln)
+	t.Cleanup(func() { app.Shutdown() })
+	return "ws://" + ln.Addr().String() + "/broadcasting", router
+}
+
+func dial(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+func send(t *testing.T, conn *websocket.Conn, event, channel string) {
+	t.Helper()
+	require.NoError(t, conn.WriteJSON(Message{Event: event, Channel: channel}))
+}
+
+func read(t *testing.T, conn *websocket.Conn) map[string]any {
+	t.Helper()
+	var message map[string]any
+	require.NoError(t, conn.ReadJSON(&message))
+	return message
+}
+
+func TestWebSocketSubscriptions(t *testing.T) {
+	m := newTestManager()
+	url, router := serve(t, m)
+	m.SetBroadcaster(NewHubBroadcaster(router.Hub()))
+
+	guest := dial(t, url)
+	send(t, guest, "subscribe", Private("orders.1"))
+	assert.Equal(t, map[string]any{"event": "subscription_error", "channel": "private-orders.1", "data": map[string]any{"status": float64(403)}}, read(t, guest))
+
+	owner := dial(t, url+"?user=u1")
+	send(t, owner, "subscribe", Private("orders.1"))
+	assert.Equal(t, map[string]any{"event": "subscription_succeeded", "channel": "private-orders.1"}, read(t, owner))
+
+	require.NoError(t, m.Broadcast(context.Background(), orderShipped{OrderID: "1"}))
+	assert.Equal(t, map[string]any{
+		"event":   "order.shipped",
+		"channel": "private-orders.1",
+		"data":    map[string]any{"order_id": "1"},
+	}, read(t, owner))
+
+	require.NoError(t, guest.WriteMessage(websocket.TextMessage, []byte("{")))
+	assert.Equal(t, "error", read(t, guest)["event"])
+}
+
+func TestWebSocketPresence(t *testing.T) {
+	m := newTestManager()
+	url, _ := serve(t, m)
+
+	alice := dial(t, url+"?user=alice")
+	send(t, alice, "subscribe", Presence("chat.lobby"))
+	joined := read(t, alice)
+	assert.Equal(t, "subscription_succeeded", joined["event"])
+	assert.Len(t, joined["data"].(map[string]any)["members"], 1)
+
+	bob := dial(t, url+"?user=bob")
+	send(t, bob, "subscribe", Presence("chat.lobby"))
+	joined = read(t, bob)
+	assert.Len(t, joined["data"].(map[string]any)["members"], 2)
+	assert.Equal(t, map[string]any{"user_id": "bob", "user_info": map[string]any{"name": "User bob"}}, joined["data"].(map[string]any)["me"])
+
+	added := read(t, alice)
+	assert.Equal(t, "member_added", added["event"])
+	assert.Equal(t, "bob", added["data"].(map[string]any)["user_id"])
+
+	bob.Close()
+	removed := read(t, alice)
+	assert.Equal(t, "member_removed", removed["event"])
+	assert.Equal(t, "bob", removed["data"].(map[string]any)["user_id"])
+}
+
+func TestRedisBroadcasterRelaysToHub(t *testing.T) {
+	pubsub := &fakePubSub{subscribers: map[string][]func(string){}, subscribed: make(chan struct{})}
+	m := newTestManager()
+	m.SetBroadcaster(NewRedisBroadcaster(pubsub, ""))
+	t.Cleanup(func() { m.Close() })
+	url, _ := serve(t, m)
+
+	client := dial(t, url)
+	send(t, client, "subscribe", "stats")
+	assert.Equal(t, "subscription_succeeded", read(t, client)["event"])
+	<-pubsub.subscribed
+
+	require.NoError(t, m.Broadcast(context.Background(), statsUpdated{Visitors: 5}))
+	assert.Equal(t, map[string]any{
+		"event":   "stats",
+		"channel": "stats",
+		"data":    map[string]any{"visitors": float64(5)},
+	}, read(t, client))
+}
+
+func TestRedisBroadcasterEncodeError(t *testing.T) {
+	b := NewRedisBroadcaster(&fakePubSub{}, "")
+	err := b.Broadcast(context.Background(), []string{"stats"}, "bad", func() {})
+	var encodeErr *json.UnsupportedTypeError
+	assert.True(t, errors.As(err, &encodeErr))
+}
@@ -0,0 +1,93 @@
+package broadcasting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/genesysflow/go-genesys/http"
+)
+
+// HubBroadcaster sends events to the connections subscribed on a WebSocket
+// hub in this process. Apps running several instances should use
+// RedisBroadcaster so every instance's clients receive them.
+type HubBroadcaster struct {
+	hub *http.WebSocketHub
+}
+
+// NewHubBroadcaster creates a broadcaster for a hub, usually the router's.
+func NewHubBroadcaster(hub *http.WebSocketHub) *HubBroadcaster {
+	return &HubBroadcaster{hub: hub}
+}
+
+// Broadcast sends the event to each channel's room on the hub.
+func (b *HubBroadcaster) Broadcast(ctx context.Context, channels []string, event string, data any) error {
+	var errs []error
+	for _, channel := range channels {
+		if err := b.hub.BroadcastJSON(channel, Message{Event: event, Channel: channel, Data: data}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PubSub publishes and subscribes to messages. *cache.RedisClient
+// implements it with Redis pub/sub.
+type PubSub interface {
+	Publish(ctx context.Context, channel, message string) error
+
+	// Subscribe calls handler for each message until ctx is cancelled or the
+	// subscription fails.
+	Subscribe(ctx context.Context, channel string, handler func(message string)) error
+}
+
+// RedisBroadcaster publishes events as Message JSON on a single Redis
+// channel. Each instance relays them to its own hub, and other consumers,
+// such as a Node socket server, can subscribe to the same channel.
+type RedisBroadcaster struct {
+	client  PubSub
+	channel string
+}
+
+// NewRedisBroadcaster creates a broadcaster publishing on the given Redis
+// channel, "broadcasts" if empty.
+func NewRedisBroadcaster(client PubSub, channel string) *RedisBroadcaster {
+	if channel == "" {
+		channel = "broadcasts"
+	}
+	return &RedisBroadcaster{client: client, channel: channel}
+}
+
+// Broadcast publishes one message per channel.
+func (b *RedisBroadcaster) Broadcast(ctx context.Context, channels []string, event string, data any) error {
+	var errs []error
+	for _, channel := range channels {
+		payload, err := json.Marshal(Message{Event: event, Channel: channel, Data: data})
+		if err != nil {
+			return fmt.Errorf("broadcasting: failed to encode event %s: %w", event, err)
+		}
+		if err := b.client.Publish(ctx, b.channel, string(payload)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Relay delivers published messages to the hub's rooms until ctx is
+// cancelled or the subscription fails.
+func (b *RedisBroadcaster) Relay(ctx context.Context, hub *http.WebSocketHub) error {
+	return b.client.Subscribe(ctx, b.channel, func(payload string) {
+		var message Message
+		if err := json.Unmarshal([]byte(payload), &message); err != nil || message.Channel == "" {
+			return
+		}
+		hub.Broadcast(message.Channel, http.TextMessage, []byte(payload))
+	})
+}
+
+// Relayer is implemented by broadcasters whose events reach the local hub
+// through a subscription, such as RedisBroadcaster.
+type Relayer interface {
+	Relay(ctx context.Context, hub *http.WebSocketHub) error
+}
@@ -0,0 +1,321 @@
+package broadcasting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/events"
+	"github.com/genesysflow/go-genesys/http"
+)
+
+// ErrForbidden is returned when a user may not subscribe to a channel.
+var ErrForbidden = errors.New("broadcasting: not authorized for channel")
+
+// ChannelAuthorizer decides whether user may subscribe to a private or
+// presence channel. params holds the values of the pattern's {placeholders}.
+// On presence channels, info is shared with the other subscribers.
+type ChannelAuthorizer func(user contracts.Authenticatable, params map[string]string) (info any, ok bool)
+
+// PresenceMember is a subscriber of a presence channel.
+type PresenceMember struct {
+	UserID   any `json:"user_id"`
+	UserInfo any `json:"user_info,omitempty"`
+}
+
+// channelRule is a channel pattern and its authorizer.
+type channelRule struct {
+	pattern   *regexp.Regexp
+	authorize ChannelAuthorizer
+}
+
+// Manager broadcasts events and authorizes channel subscriptions.
+type Manager struct {
+	broadcaster Broadcaster
+	rules       []channelRule
+	presence    map[string]map[*http.WebSocketConn]PresenceMember
+	logger      contracts.Logger
+	relayOnce   sync.Once
+	ctx         context.Context
+	cancel      context.CancelFunc
+	mu          sync.RWMutex
+}
+
+// NewManager creates a manager sending events through broadcaster.
+func NewManager(broadcaster Broadcaster) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		broadcaster: broadcaster,
+		presence:    make(map[string]map[*http.WebSocketConn]PresenceMember),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Broadcaster returns the broadcaster events are sent through.
+func (m *Manager) Broadcaster() Broadcaster {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.broadcaster
+}
+
+// SetBroadcaster sets the broadcaster events are sent through.
+func (m *Manager) SetBroadcaster(broadcaster Broadcaster) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.broadcaster = broadcaster
+}
+
+// SetLogger sets the logger that receives relay failures.
+func (m *Manager) SetLogger(logger contracts.Logger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logger = logger
+}
+
+// Channel registers the authorizer for private and presence channels
+// matching pattern, given without its prefix. Placeholders match one
+// dot-separated segment:
+//
+//	manager.Channel("orders.{id}", func(user contracts.Authenticatable, params map[string]string) (any, bool) {
+//		return nil, ownsOrder(user, params["id"])
+//	})
+func (m *Manager) Channel(pattern string, authorize ChannelAuthorizer) {
+	expr := regexp.QuoteMeta(pattern)
+	expr = regexp.MustCompile(`\\\{(\w+)\\\}`).ReplaceAllString(expr, `(?P<$1>[^.]+)`)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rules = append(m.rules, channelRule{
+		pattern:   regexp.MustCompile("^" + expr + "$"),
+		authorize: authorize,
+	})
+}
+
+// Authorize checks whether user may subscribe to channel. Public channels
+// are open to everyone; private and presence channels need a signed-in
+// user accepted by the first matching authorizer. It returns the user's
+// presence information.
+func (m *Manager) Authorize(user contracts.Authenticatable, channel string) (any, error) {
+	if !isPrivate(channel) {
+		return nil, nil
+	}
+	if user == nil {
+		return nil, ErrForbidden
+	}
+
+	name := strings.TrimPrefix(strings.TrimPrefix(channel, PrivatePrefix), PresencePrefix)
+	m.mu.RLock()
+	rules := m.rules
+	m.mu.RUnlock()
+
+	for _, rule := range rules {
+		match := rule.pattern.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+		params := make(map[string]string)
+		for i, key := range rule.pattern.SubexpNames() {
+			if key != "" {
+				params[key] = match[i]
+			}
+		}
+		if info, ok := rule.authorize(user, params); ok {
+			return info, nil
+		}
+		return nil, ErrForbidden
+	}
+	return nil, ErrForbidden
+}
+
+// Broadcast sends an event to its channels.
+func (m *Manager) Broadcast(ctx context.Context, event ShouldBroadcast) error {
+	channels := event.BroadcastOn()
+	if len(channels) == 0 {
+		return nil
+	}
+	return m.Broadcaster().Broadcast(ctx, channels, eventName(event), eventData(event))
+}
+
+// Listen broadcasts the dispatcher's events that implement ShouldBroadcast,
+// after their listeners have run.
+func (m *Manager) Listen(dispatcher *events.Dispatcher) {
+	dispatcher.ListenAll(func(event events.Event) error {
+		if broadcast, ok := event.(ShouldBroadcast); ok {
+			return m.Broadcast(context.Background(), broadcast)
+		}
+		return nil
+	})
+}
+
+// WebSocketHandler serves the client side of broadcasting. Clients send
+//
+//	{"event": "subscribe", "channel": "private-orders.1"}
+//	{"event": "unsubscribe", "channel": "private-orders.1"}
+//
+// and receive subscription_succeeded or subscription_error replies, the
+// events broadcast on their channels, and member_added and member_removed
+// on presence channels. Register it on a route behind the middleware that
+// signs users in:
+//
+//	router.WebSocket("/broadcasting", manager.WebSocketHandler(), middleware.Auth())
+func (m *Manager) WebSocketHandler() http.WebSocketHandler {
+	return func(conn *http.WebSocketConn) error {
+		m.startRelay(conn.Hub())
+		defer m.leavePresence(conn)
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return nil // the client went away
+			}
+			var message Message
+			if err := json.Unmarshal(data, &message); err != nil {
+				conn.WriteJSON(Message{Event: "error", Data: map[string]string{"message": "invalid message"}})
+				continue
+			}
+
+			switch message.Event {
+			case "subscribe":
+				m.subscribe(conn, message.Channel)
+			case "unsubscribe":
+				conn.Leave(message.Channel)
+				m.removeMember(conn, message.Channel)
+			case "ping":
+				conn.WriteJSON(Message{Event: "pong"})
+			}
+		}
+	}
+}
+
+// subscribe authorizes a connection and joins it to a channel.
+func (m *Manager) subscribe(conn *http.WebSocketConn, channel string) {
+	if channel == "" {
+		return
+	}
+	info, err := m.Authorize(conn.User(), channel)
+	if err != nil {
+		conn.WriteJSON(Message{Event: "subscription_error", Channel: channel, Data: map[string]int{"status": 403}})
+		return
+	}
+
+	conn.Join(channel)
+	if !strings.HasPrefix(channel, PresencePrefix) {
+		conn.WriteJSON(Message{Event: "subscription_succeeded", Channel: channel})
+		return
+	}
+
+	member := PresenceMember{UserID: conn.User().GetAuthIdentifier(), UserInfo: info}
+	m.mu.Lock()
+	members, ok := m.presence[channel]
+	if !ok {
+		members = make(map[*http.WebSocketConn]PresenceMember)
+		m.presence[channel] = members
+	}
+	members[conn] = member
+	list := uniqueMembers(members)
+	m.mu.Unlock()
+
+	conn.WriteJSON(Message{Event: "subscription_succeeded", Channel: channel, Data: map[string]any{
+		"members": list,
+		"me":      member,
+	}})
+	broadcastFrom(conn, Message{Event: "member_added", Channel: channel, Data: member})
+}
+
+// removeMember drops a connection from a presence channel and tells the
+// other subscribers.
+func (m *Manager) removeMember(conn *http.WebSocketConn, channel string) {
+	m.mu.Lock()
+	member, ok := m.presence[channel][conn]
+	if ok {
+		delete(m.presence[channel], conn)
+		if len(m.presence[channel]) == 0 {
+			delete(m.presence, channel)
+		}
+	}
+	m.mu.Unlock()
+
+	if ok {
+		broadcastFrom(conn, Message{Event: "member_removed", Channel: channel, Data: member})
+	}
+}
+
+// leavePresence removes a closing connection from its presence channels.
+func (m *Manager) leavePresence(conn *http.WebSocketConn) {
+	m.mu.RLock()
+	var channels []string
+	for channel, members := range m.presence {
+		if _, ok := members[conn]; ok {
+			channels = append(channels, channel)
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, channel := range channels {
+		m.removeMember(conn, channel)
+	}
+}
+
+// startRelay starts relaying the broadcaster's subscription to the hub
+// once a client connects, retrying until the manager is closed.
+func (m *Manager) startRelay(hub *http.WebSocketHub) {
+	relayer, ok := m.Broadcaster().(Relayer)
+	if !ok {
+		return
+	}
+	m.relayOnce.Do(func() {
+		go func() {
+			for m.ctx.Err() == nil {
+				if err := relayer.Relay(m.ctx, hub); err != nil {
+					m.mu.RLock()
+					logger := m.logger
+					m.mu.RUnlock()
+					if logger != nil {
+						logger.Error("Broadcast relay failed", "error", err.Error())
+					}
+				}
+				select {
+				case <-m.ctx.Done():
+				case <-time.After(time.Second):
+				}
+			}
+		}()
+	})
+}
+
+// Close stops relaying broadcasts to the hub.
+func (m *Manager) Close() error {
+	m.cancel()
+	return nil
+}
+
+// uniqueMembers lists a channel's members once per user.
+func uniqueMembers(members map[*http.WebSocketConn]PresenceMember) []PresenceMember {
+	seen := make(map[string]bool)
+	list := make([]PresenceMember, 0, len(members))
+	for _, member := range members {
+		id := fmt.Sprint(member.UserID)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		list = append(list, member)
+	}
+	return list
+}
+
+// broadcastFrom sends a message to a channel's other connections.
+func broadcastFrom(conn *http.WebSocketConn, message Message) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	conn.Broadcast(message.Channel, http.TextMessage, data)
+}
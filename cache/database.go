@@ -0,0 +1,201 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+)
+
+// DatabaseStore keeps items in a table with key, value and expiration
+// columns, expiration being a Unix time in milliseconds or 0 for items
+// without expiry. Generate its migration with `cache:table`. Values are JSON
+// encoded, so Get returns them as decoded JSON (numbers as float64, objects
+// as map[string]any). Add and Increment are atomic across processes.
+// Locks are kept in a second table with key, owner and expiration columns.
+// The store relies on ON CONFLICT and RETURNING, so it supports PostgreSQL
+// and SQLite but not MySQL.
+type DatabaseStore struct {
+	conn      contracts.Connection
+	dialect   database.Dialect
+	table     string
+	lockTable string
+}
+
+// NewDatabaseStore creates a database store. The connection's table prefix
+// is applied to table. Locks use the "cache_locks" table.
+func NewDatabaseStore(conn contracts.Connection, table string) *DatabaseStore {
+	if table == "" {
+		table = "cache"
+	}
+	return &DatabaseStore{
+		conn:      conn,
+		dialect:   database.NewDialect(conn.Driver()),
+		table:     conn.Prefix() + table,
+		lockTable: conn.Prefix() + "cache_locks",
+	}
+}
+
+// SetLockTable sets the table locks are kept in.
+func (s *DatabaseStore) SetLockTable(table string) {
+	s.lockTable = s.conn.Prefix() + table
+}
+
+// Get retrieves an item from the cache. Expired rows are removed.
+func (s *DatabaseStore) Get(key string) (any, error) {
+	ctx := context.Background()
+	query := fmt.Sprintf(`SELECT value, expiration FROM %s WHERE key = %s`, s.dialect.Quote(s.table), s.dialect.Placeholder(1))
+
+	var payload string
+	var expiration int64
+	err := s.conn.QueryRowContext(ctx, query, key).Scan(&payload, &expiration)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if expiration != 0 && time.Now().UnixMilli() >= expiration {
+		return nil, s.forgetExpired(ctx, key)
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(payload), &value); err != nil {
+		return nil, fmt.Errorf("cache: failed to decode value for key %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// Put inserts or replaces an item. A ttl of zero stores it without expiry.
+func (s *DatabaseStore) Put(key string, value any, ttl time.Duration) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("cache: failed to encode value for key %s: %w", key, err)
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (key, value, expiration) VALUES (%s, %s, %s)
+ON CONFLICT (key) DO UPDATE SET value = excluded.value, expiration = excluded.expiration`,
+		s.dialect.Quote(s.table), s.dialect.Placeholder(1), s.dialect.Placeholder(2), s.dialect.Placeholder(3))
+
+	_, err = s.conn.ExecContext(context.Background(), query, key, string(payload), expiration(ttl))
+	return err
+}
+
+// Add inserts an item if the key is missing or expired.
+func (s *DatabaseStore) Add(key string, value any, ttl time.Duration) (bool, error) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("cache: failed to encode value for key %s: %w", key, err)
+	}
+
+	ctx := context.Background()
+	if err := s.forgetExpired(ctx, key); err != nil {
+		return false, err
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (key, value, expiration) VALUES (%s, %s, %s) ON CONFLICT (key) DO NOTHING`,
+		s.dialect.Quote(s.table), s.dialect.Placeholder(1), s.dialect.Placeholder(2), s.dialect.Placeholder(3))
+	result, err := s.conn.ExecContext(ctx, query, key, string(payload), expiration(ttl))
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
+
+// Increment adds by to an integer item in a single UPDATE. Missing items
+// are stored without expiry; existing ones keep theirs.
+func (s *DatabaseStore) Increment(key string, by int64) (int64, error) {
+	ctx := context.Background()
+	if err := s.forgetExpired(ctx, key); err != nil {
+		return 0, err
+	}
+
+	insert := fmt.Sprintf(`INSERT INTO %s (key, value, expiration) VALUES (%s, '0', 0) ON CONFLICT (key) DO NOTHING`,
+		s.dialect.Quote(s.table), s.dialect.Placeholder(1))
+	if _, err := s.conn.ExecContext(ctx, insert, key); err != nil {
+		return 0, err
+	}
+
+	// The value guard keeps SQLite from casting non-integers to zero.
+	update := fmt.Sprintf(`UPDATE %s SET value = CAST(CAST(value AS BIGINT) + %s AS TEXT)
+WHERE key = %s AND value = CAST(CAST(value AS BIGINT) AS TEXT) RETURNING value`,
+		s.dialect.Quote(s.table), s.dialect.Placeholder(1), s.dialect.Placeholder(2))
+	var payload string
+	err := s.conn.QueryRowContext(ctx, update, by, key).Scan(&payload)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("cache: value for key %s is not an integer", key)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return toInt64(key, payload)
+}
+
+// Forget removes an item from the cache.
+func (s *DatabaseStore) Forget(key string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE key = %s`, s.dialect.Quote(s.table), s.dialect.Placeholder(1))
+	_, err := s.conn.ExecContext(context.Background(), query, key)
+	return err
+}
+
+// Flush removes all items from the cache.
+func (s *DatabaseStore) Flush() error {
+	_, err := s.conn.ExecContext(context.Background(), fmt.Sprintf(`DELETE FROM %s`, s.dialect.Quote(s.table)))
+	return err
+}
+
+// AcquireLock inserts the lock's row, or takes over an expired one, in a
+// single statement.
+func (s *DatabaseStore) AcquireLock(name, owner string, ttl time.Duration) (bool, error) {
+	query := fmt.Sprintf(`INSERT INTO %[1]s (key, owner, expiration) VALUES (%[2]s, %[3]s, %[4]s)
+ON CONFLICT (key) DO UPDATE SET owner = excluded.owner, expiration = excluded.expiration
+WHERE %[1]s.expiration <> 0 AND %[1]s.expiration <= %[5]s`,
+		s.dialect.Quote(s.lockTable), s.dialect.Placeholder(1), s.dialect.Placeholder(2), s.dialect.Placeholder(3), s.dialect.Placeholder(4))
+
+	result, err := s.conn.ExecContext(context.Background(), query, name, owner, expiration(ttl), time.Now().UnixMilli())
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
+
+// ReleaseLock deletes the lock's row if owner holds it.
+func (s *DatabaseStore) ReleaseLock(name, owner string) (bool, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE key = %s AND owner = %s`, s.dialect.Quote(s.lockTable), s.dialect.Placeholder(1), s.dialect.Placeholder(2))
+	result, err := s.conn.ExecContext(context.Background(), query, name, owner)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
+
+// ForceReleaseLock deletes the lock's row.
+func (s *DatabaseStore) ForceReleaseLock(name string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE key = %s`, s.dialect.Quote(s.lockTable), s.dialect.Placeholder(1))
+	_, err := s.conn.ExecContext(context.Background(), query, name)
+	return err
+}
+
+// forgetExpired removes the key's row if it has expired.
+func (s *DatabaseStore) forgetExpired(ctx context.Context, key string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE key = %s AND expiration <> 0 AND expiration <= %s`,
+		s.dialect.Quote(s.table), s.dialect.Placeholder(1), s.dialect.Placeholder(2))
+	_, err := s.conn.ExecContext(ctx, query, key, time.Now().UnixMilli())
+	return err
+}
+
+// expiration returns the Unix millisecond expiry for ttl, or 0 for none.
+func expiration(ttl time.Duration) int64 {
+	if ttl == 0 {
+		return 0
+	}
+	return time.Now().Add(ttl).UnixMilli()
+}
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/genesysflow/go-genesys/crypt"
+)
+
+// EncryptedStore encrypts values before handing them to the underlying store.
+// Values are JSON encoded before encryption, so Get returns them as decoded
+// JSON (numbers as float64, objects as map[string]any). Counters written by
+// Increment are kept as plain integers so the underlying store can increment
+// them atomically, and Get returns them as int64. Locks and tags are passed
+// through; they return ErrLocksNotSupported and ErrTagsNotSupported where the
+// underlying store lacks them.
+type EncryptedStore struct {
+	store      Store
+	repository *Repository
+	encrypter  *crypt.Encrypter
+}
+
+// NewEncryptedStore wraps a store so its values are encrypted at rest.
+func NewEncryptedStore(store Store, encrypter *crypt.Encrypter) *EncryptedStore {
+	return &EncryptedStore{
+		store:      store,
+		repository: NewRepository(store),
+		encrypter:  encrypter,
+	}
+}
+
+// Get retrieves and decrypts an item from the cache.
+func (s *EncryptedStore) Get(key string) (any, error) {
+	raw, err := s.store.Get(key)
+	if err != nil || raw == nil {
+		return nil, err
+	}
+
+	payload, ok := raw.(string)
+	if !ok {
+		if n, err := toInt64(key, raw); err == nil {
+			return n, nil
+		}
+		return nil, fmt.Errorf("cache: value for key %s is not encrypted", key)
+	}
+	if n, err := strconv.ParseInt(payload, 10, 64); err == nil {
+		return n, nil
+	}
+
+	plain, err := s.encrypter.Decrypt(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	if err := json.Unmarshal(plain, &value); err != nil {
+		return nil, fmt.Errorf("cache: failed to decode value for key %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// Put encrypts and stores an item in the cache.
+func (s *EncryptedStore) Put(key string, value any, ttl time.Duration) error {
+	payload, err := s.encrypt(key, value)
+	if err != nil {
+		return err
+	}
+	return s.store.Put(key, payload, ttl)
+}
+
+// Add encrypts and stores an item if the key is missing, atomically where
+// the underlying store supports it.
+func (s *EncryptedStore) Add(key string, value any, ttl time.Duration) (bool, error) {
+	payload, err := s.encrypt(key, value)
+	if err != nil {
+		return false, err
+	}
+	return s.repository.Add(key, payload, ttl)
+}
+
+// Increment increments an unencrypted counter in the underlying store.
+func (s *EncryptedStore) Increment(key string, by int64) (int64, error) {
+	return s.repository.Increment(key, by)
+}
+
+// Forget removes an item from the cache.
+func (s *EncryptedStore) Forget(key string) error {
+	return s.store.Forget(key)
+}
+
+// Flush removes all items from the cache.
+func (s *EncryptedStore) Flush() error {
+	return s.store.Flush()
+}
+
+// Tag records that key belongs to each of the tags in the underlying store.
+func (s *EncryptedStore) Tag(key string, tags ...string) error {
+	taggable, ok := s.store.(Taggable)
+	if !ok {
+		return ErrTagsNotSupported
+	}
+	return taggable.Tag(key, tags...)
+}
+
+// FlushTags removes every item recorded under any of the tags.
+func (s *EncryptedStore) FlushTags(tags ...string) error {
+	taggable, ok := s.store.(Taggable)
+	if !ok {
+		return ErrTagsNotSupported
+	}
+	return taggable.FlushTags(tags...)
+}
+
+// AcquireLock takes the lock in the underlying store for owner.
+func (s *EncryptedStore) AcquireLock(name, owner string, ttl time.Duration) (bool, error) {
+	locker, ok := s.store.(Locker)
+	if !ok {
+		return false, ErrLocksNotSupported
+	}
+	return locker.AcquireLock(name, owner, ttl)
+}
+
+// ReleaseLock frees the lock in the underlying store if owner holds it.
+func (s *EncryptedStore) ReleaseLock(name, owner string) (bool, error) {
+	locker, ok := s.store.(Locker)
+	if !ok {
+		return false, ErrLocksNotSupported
+	}
+	return locker.ReleaseLock(name, owner)
+}
+
+// ForceReleaseLock frees the lock in the underlying store whoever holds it.
+func (s *EncryptedStore) ForceReleaseLock(name string) error {
+	locker, ok := s.store.(Locker)
+	if !ok {
+		return ErrLocksNotSupported
+	}
+	return locker.ForceReleaseLock(name)
+}
+
+func (s *EncryptedStore) encrypt(key string, value any) (string, error) {
+	plain, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("cache: failed to encode value for key %s: %w", key, err)
+	}
+	return s.encrypter.Encrypt(plain)
+}
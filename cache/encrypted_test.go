@@ -0,0 +1,156 @@
+package cache
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/crypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEncrypter(t *testing.T) *crypt.Encrypter {
+	t.Helper()
+	enc, err := crypt.NewEncrypter([]byte(strings.Repeat("k", crypt.KeySize)))
+	require.NoError(t, err)
+	return enc
+}
+
+func TestEncryptedStore(t *testing.T) {
+	inner := NewMemoryStore()
+	store := NewEncryptedStore(inner, newTestEncrypter(t))
+
+	err := store.Put("user", map[string]any{"email": "jane@example.com"}, time.Minute)
+	require.NoError(t, err)
+
+	// The underlying store only ever sees ciphertext.
+	raw, err := inner.Get("user")
+	require.NoError(t, err)
+	assert.IsType(t, "", raw)
+	assert.NotContains(t, raw, "jane@example.com")
+
+	value, err := store.Get("user")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"email": "jane@example.com"}, value)
+
+	require.NoError(t, store.Forget("user"))
+	value, err = store.Get("user")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestEncryptedStoreMisses(t *testing.T) {
+	repository := NewRepository(NewEncryptedStore(NewMemoryStore(), newTestEncrypter(t)))
+
+	value, err := repository.Get("missing")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	value, err = repository.Remember("plan", time.Minute, func() (any, error) { return "pro", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "pro", value)
+	value, err = repository.Remember("plan", time.Minute, func() (any, error) { return "free", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "pro", value)
+
+	added, err := repository.Add("seen", true, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, added)
+}
+
+func TestEncryptedStoreRejectsPlainValues(t *testing.T) {
+	inner := NewMemoryStore()
+	inner.Put("admin", true, time.Minute)
+	inner.Put("token", "plain-token", time.Minute)
+
+	store := NewEncryptedStore(inner, newTestEncrypter(t))
+	_, err := store.Get("admin")
+	assert.Error(t, err)
+	_, err = store.Get("token")
+	assert.Error(t, err)
+}
+
+func TestManagerEncryptedStore(t *testing.T) {
+	manager := NewManager()
+	manager.Encrypt("memory")
+
+	_, err := manager.Store()
+	assert.Error(t, err, "encrypted store without encrypter should fail")
+
+	manager.SetEncrypter(newTestEncrypter(t))
+	store, err := manager.Store()
+	require.NoError(t, err)
+	assert.IsType(t, &EncryptedStore{}, store)
+
+	require.NoError(t, store.Put("key", "value", time.Minute))
+	val, err := store.Get("key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", val)
+}
+
+func TestEncryptedStoreLocks(t *testing.T) {
+	repository := NewRepository(NewEncryptedStore(NewMemoryStore(), newTestEncrypter(t)))
+
+	lock, err := repository.Lock("reports", time.Minute)
+	require.NoError(t, err)
+	acquired, err := lock.Acquire()
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	other, err := repository.Lock("reports", time.Minute)
+	require.NoError(t, err)
+	acquired, err = other.Acquire()
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	released, err := lock.Release()
+	require.NoError(t, err)
+	assert.True(t, released)
+}
+
+func TestEncryptedStoreTags(t *testing.T) {
+	inner := NewMemoryStore()
+	repository := NewRepository(NewEncryptedStore(inner, newTestEncrypter(t)))
+
+	users, err := repository.Tags("users")
+	require.NoError(t, err)
+	require.NoError(t, users.Put("jane", "jane@example.com", time.Minute))
+	require.NoError(t, repository.Put("plan", "pro", time.Minute))
+
+	raw, err := inner.Get("jane")
+	require.NoError(t, err)
+	assert.NotContains(t, raw, "jane@example.com")
+
+	require.NoError(t, users.Flush())
+	value, err := repository.Get("jane")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+	value, err = repository.Get("plan")
+	require.NoError(t, err)
+	assert.Equal(t, "pro", value)
+}
+
+func TestEncryptedStoreIncrement(t *testing.T) {
+	repository := NewRepository(NewEncryptedStore(NewMemoryStore(), newTestEncrypter(t)))
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repository.Increment("visits")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	value, err := repository.Get("visits")
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), value)
+
+	n, err := repository.Decrement("visits", 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(45), n)
+}
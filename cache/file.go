@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type fileItem struct {
+	ExpiresAt int64           `json:"expires_at"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// FileStore keeps each item in a file named after the hash of its key.
+// Values are JSON encoded, so Get returns them as decoded JSON (numbers as
+// float64, objects as map[string]any). Add and Increment are atomic within
+// the process.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileStore creates a file store, creating dir if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Get retrieves an item from the cache. Expired files are removed.
+func (s *FileStore) Get(key string) (any, error) {
+	payload, err := s.read(key)
+	if err != nil || payload == nil {
+		return nil, err
+	}
+
+	var value any
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return nil, fmt.Errorf("cache: failed to decode value for key %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// Put stores an item in the cache. A ttl of zero stores it without expiry.
+// The file is replaced atomically so readers never see a partial item.
+func (s *FileStore) Put(key string, value any, ttl time.Duration) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("cache: failed to encode value for key %s: %w", key, err)
+	}
+
+	entry := fileItem{Value: payload}
+	if ttl != 0 {
+		entry.ExpiresAt = time.Now().Add(ttl).UnixMilli()
+	}
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// Add stores an item if the key is missing or expired.
+func (s *FileStore) Add(key string, value any, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.read(key)
+	if err != nil || existing != nil {
+		return false, err
+	}
+	return true, s.Put(key, value, ttl)
+}
+
+// Increment adds by to an integer item. Missing items are stored without
+// expiry; existing ones keep theirs.
+func (s *FileStore) Increment(key string, by int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, err := s.readItem(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var current int64
+	if entry.Value != nil {
+		var value any
+		if err := json.Unmarshal(entry.Value, &value); err != nil {
+			return 0, fmt.Errorf("cache: failed to decode value for key %s: %w", key, err)
+		}
+		if current, err = toInt64(key, value); err != nil {
+			return 0, err
+		}
+	}
+
+	ttl := time.Duration(0)
+	if entry.ExpiresAt != 0 {
+		ttl = time.Until(time.UnixMilli(entry.ExpiresAt))
+	}
+	return current + by, s.Put(key, current+by, ttl)
+}
+
+// Forget removes an item from the cache.
+func (s *FileStore) Forget(key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Flush removes all items from the cache.
+func (s *FileStore) Flush() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := os.Remove(filepath.Join(s.dir, entry.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// read returns the item's encoded value, or nil if it is missing or expired.
+func (s *FileStore) read(key string) (json.RawMessage, error) {
+	entry, err := s.readItem(key)
+	return entry.Value, err
+}
+
+func (s *FileStore) readItem(key string) (fileItem, error) {
+	b, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fileItem{}, nil
+	}
+	if err != nil {
+		return fileItem{}, err
+	}
+
+	var entry fileItem
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return fileItem{}, fmt.Errorf("cache: corrupt file for key %s: %w", key, err)
+	}
+	if entry.ExpiresAt != 0 && time.Now().UnixMilli() >= entry.ExpiresAt {
+		os.Remove(s.path(key))
+		return fileItem{}, nil
+	}
+	return entry, nil
+}
+
+func (s *FileStore) path(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// flexibleCreatedSuffix names the key recording when a flexible value was
+// computed. It is kept separately so the value itself round-trips through
+// stores that serialize values, such as Redis.
+const flexibleCreatedSuffix = ":flexible:created"
+
+// refreshing tracks keys with a background refresh in flight, so a burst of
+// stale reads recomputes a value once per process. Keys are tracked across
+// stores, since stores such as EncryptedStore are wrapped per call.
+var refreshing sync.Map
+
+// Flexible returns a cached value, computing it with fn on a miss.
+//
+// A value is fresh for freshTTL after it is computed and is returned as is.
+// For a further staleTTL it is still returned, but fn runs in the background
+// to refresh it, so callers don't wait on an expiring hot key. After both
+// windows the value is gone and the caller waits for fn.
+func Flexible(store Store, key string, freshTTL, staleTTL time.Duration, fn func() (any, error)) (any, error) {
+	value, err := store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return putFlexible(store, key, freshTTL, staleTTL, fn)
+	}
+
+	created, err := store.Get(key + flexibleCreatedSuffix)
+	if err != nil {
+		return nil, err
+	}
+	if at, ok := unixMilli(created); ok && time.Since(at) < freshTTL {
+		return value, nil
+	}
+
+	if _, inFlight := refreshing.LoadOrStore(key, true); !inFlight {
+		go func() {
+			defer refreshing.Delete(key)
+			putFlexible(store, key, freshTTL, staleTTL, fn)
+		}()
+	}
+	return value, nil
+}
+
+// putFlexible computes a value and stores it with its creation time.
+func putFlexible(store Store, key string, freshTTL, staleTTL time.Duration, fn func() (any, error)) (any, error) {
+	value, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFlexible(store, key, value, freshTTL+staleTTL); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func writeFlexible(store Store, key string, value any, ttl time.Duration) error {
+	if err := store.Put(key, value, ttl); err != nil {
+		return err
+	}
+	return store.Put(key+flexibleCreatedSuffix, time.Now().UnixMilli(), ttl)
+}
+
+// unixMilli reads a timestamp written by writeFlexible. Serializing stores
+// may return it as a float or json.Number.
+func unixMilli(value any) (time.Time, bool) {
+	var ms int64
+	switch v := value.(type) {
+	case int64:
+		ms = v
+	case int:
+		ms = int64(v)
+	case float64:
+		ms = int64(v)
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		ms = n
+	default:
+		return time.Time{}, false
+	}
+	return time.UnixMilli(ms), true
+}
@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlexibleServesStaleWhileRefreshing(t *testing.T) {
+	store := NewMemoryStore()
+	var calls atomic.Int32
+	fn := func() (any, error) {
+		return int(calls.Add(1)), nil
+	}
+
+	value, err := Flexible(store, "report", 20*time.Millisecond, time.Minute, fn)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	// Fresh: served without calling fn.
+	value, _ = Flexible(store, "report", 20*time.Millisecond, time.Minute, fn)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, int32(1), calls.Load())
+
+	time.Sleep(30 * time.Millisecond)
+
+	// Stale: the old value is returned immediately and refreshed in the background.
+	value, _ = Flexible(store, "report", 20*time.Millisecond, time.Minute, fn)
+	assert.Equal(t, 1, value)
+	require.Eventually(t, func() bool {
+		value, _ := store.Get("report")
+		return value == 2
+	}, time.Second, time.Millisecond)
+
+	value, _ = Flexible(store, "report", 20*time.Millisecond, time.Minute, fn)
+	assert.Equal(t, 2, value)
+}
+
+func TestFlexibleRefreshesOncePerKey(t *testing.T) {
+	store := NewMemoryStore()
+	require.NoError(t, writeFlexible(store, "hot", "old", time.Minute))
+	store.Put("hot"+flexibleCreatedSuffix, time.Now().Add(-time.Hour).UnixMilli(), time.Minute)
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	fn := func() (any, error) {
+		calls.Add(1)
+		<-release
+		return "new", nil
+	}
+
+	for i := 0; i < 10; i++ {
+		value, err := Flexible(store, "hot", time.Second, time.Minute, fn)
+		require.NoError(t, err)
+		assert.Equal(t, "old", value)
+	}
+	close(release)
+
+	require.Eventually(t, func() bool {
+		value, _ := store.Get("hot")
+		return value == "new"
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestFlexibleMissReturnsErrors(t *testing.T) {
+	store := NewMemoryStore()
+	_, err := Flexible(store, "broken", time.Second, time.Second, func() (any, error) {
+		return nil, errors.New("boom")
+	})
+	assert.EqualError(t, err, "boom")
+
+	value, _ := store.Get("broken")
+	assert.Nil(t, value)
+}
+
+func TestManagerWarm(t *testing.T) {
+	manager := NewManager()
+	var calls atomic.Int32
+
+	require.NoError(t, manager.Warmable(WarmEntry{
+		Key: "popular",
+		TTL: time.Minute,
+		Resolve: func() (any, error) {
+			return int(calls.Add(1)), nil
+		},
+	}))
+	require.NoError(t, manager.Warmable(WarmEntry{
+		Key:   "dashboard",
+		TTL:   time.Minute,
+		Stale: time.Hour,
+		Resolve: func() (any, error) {
+			return "stats", nil
+		},
+	}))
+	require.NoError(t, manager.Warmable(WarmEntry{
+		Key: "failing",
+		Resolve: func() (any, error) {
+			return nil, errors.New("upstream down")
+		},
+	}))
+	assert.Error(t, manager.Warmable(WarmEntry{Key: "no-resolver"}))
+
+	err := manager.Warm()
+	assert.ErrorContains(t, err, "cache warm [failing]: upstream down")
+
+	store, _ := manager.Store()
+	value, _ := store.Get("popular")
+	assert.Equal(t, 1, value)
+
+	// Flexible reads see warmed values as fresh.
+	value, err = Flexible(store, "dashboard", time.Minute, time.Hour, func() (any, error) {
+		t.Fatal("warmed value should be fresh")
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "stats", value)
+
+	require.NoError(t, manager.Warm("popular"))
+	value, _ = store.Get("popular")
+	assert.Equal(t, 2, value)
+
+	entries := manager.WarmEntries()
+	require.Len(t, entries, 3)
+	assert.Equal(t, "dashboard", entries[0].Key)
+}
@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// ErrLocksNotSupported is returned by Repository.Lock for stores that do
+// not implement Locker.
+var ErrLocksNotSupported = errors.New("cache: store does not support locks")
+
+// ErrLockTimeout is returned by Lock.Block when the lock could not be
+// acquired in time.
+var ErrLockTimeout = errors.New("cache: timed out waiting for lock")
+
+// DefaultLockRetryInterval is how long Lock.Block sleeps between attempts.
+const DefaultLockRetryInterval = 250 * time.Millisecond
+
+// Locker is implemented by stores that can hold named locks shared by every
+// process using the store. The memory, Redis and database stores implement
+// it.
+type Locker interface {
+	// AcquireLock takes the lock for owner if it is free or expired, and
+	// reports whether it did. A ttl of zero holds it until released.
+	AcquireLock(name, owner string, ttl time.Duration) (bool, error)
+
+	// ReleaseLock frees the lock if owner holds it, and reports whether it did.
+	ReleaseLock(name, owner string) (bool, error)
+
+	// ForceReleaseLock frees the lock whoever holds it.
+	ForceReleaseLock(name string) error
+}
+
+// Lock is a named lock held for at most its ttl, so a crashed holder never
+// blocks others for good. Only the owner that acquired it can release it.
+type Lock struct {
+	locker Locker
+	name   string
+	owner  string
+	ttl    time.Duration
+	retry  time.Duration
+}
+
+// Lock returns a lock on the store. Pass the owner of a lock acquired
+// elsewhere, such as in another process, to release it; otherwise a random
+// owner is generated.
+func (r *Repository) Lock(name string, ttl time.Duration, owner ...string) (*Lock, error) {
+	locker, ok := r.store.(Locker)
+	if !ok {
+		return nil, ErrLocksNotSupported
+	}
+
+	lock := &Lock{locker: locker, name: name, ttl: ttl, retry: DefaultLockRetryInterval}
+	if len(owner) > 0 && owner[0] != "" {
+		lock.owner = owner[0]
+	} else {
+		var id [16]byte
+		if _, err := rand.Read(id[:]); err != nil {
+			return nil, err
+		}
+		lock.owner = hex.EncodeToString(id[:])
+	}
+	return lock, nil
+}
+
+// Name returns the lock's name.
+func (l *Lock) Name() string {
+	return l.name
+}
+
+// Owner returns the token identifying the lock's holder.
+func (l *Lock) Owner() string {
+	return l.owner
+}
+
+// SetRetryInterval sets how long Block sleeps between attempts.
+func (l *Lock) SetRetryInterval(interval time.Duration) *Lock {
+	l.retry = interval
+	return l
+}
+
+// Acquire takes the lock if it is free and reports whether it did.
+func (l *Lock) Acquire() (bool, error) {
+	return l.locker.AcquireLock(l.name, l.owner, l.ttl)
+}
+
+// Get runs fn while holding the lock and releases it afterwards. If the lock
+// is held elsewhere, fn is not run and Get returns false.
+func (l *Lock) Get(fn func() error) (bool, error) {
+	acquired, err := l.Acquire()
+	if err != nil || !acquired {
+		return false, err
+	}
+	defer l.Release()
+
+	return true, fn()
+}
+
+// Block waits up to timeout for the lock, then runs fn and releases it. With
+// a nil fn the lock stays held until Release. It returns ErrLockTimeout if
+// the lock was not acquired in time.
+func (l *Lock) Block(timeout time.Duration, fn func() error) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		acquired, err := l.Acquire()
+		if err != nil {
+			return err
+		}
+		if acquired {
+			break
+		}
+		if time.Now().Add(l.retry).After(deadline) {
+			return ErrLockTimeout
+		}
+		time.Sleep(l.retry)
+	}
+
+	if fn == nil {
+		return nil
+	}
+	defer l.Release()
+	return fn()
+}
+
+// Release frees the lock if this owner holds it, and reports whether it did.
+func (l *Lock) Release() (bool, error) {
+	return l.locker.ReleaseLock(l.name, l.owner)
+}
+
+// ForceRelease frees the lock whoever holds it.
+func (l *Lock) ForceRelease() error {
+	return l.locker.ForceReleaseLock(l.name)
+}
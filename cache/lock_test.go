@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLocks(t *testing.T, store Store) {
+	t.Helper()
+	repository := NewRepository(store)
+
+	first, err := repository.Lock("report", time.Minute)
+	require.NoError(t, err)
+	second, err := repository.Lock("report", time.Minute)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Owner(), second.Owner())
+
+	ran, err := first.Get(func() error {
+		// Held by first, so second can neither take nor release it.
+		acquired, err := second.Acquire()
+		require.NoError(t, err)
+		assert.False(t, acquired)
+		released, err := second.Release()
+		require.NoError(t, err)
+		assert.False(t, released)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, ran)
+
+	// Get released the lock.
+	acquired, err := second.Acquire()
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	// A lock restored with the owner token can release it.
+	restored, err := repository.Lock("report", time.Minute, second.Owner())
+	require.NoError(t, err)
+	released, err := restored.Release()
+	require.NoError(t, err)
+	assert.True(t, released)
+
+	// Expired locks can be taken over.
+	short, _ := repository.Lock("short", 10*time.Millisecond)
+	acquired, _ = short.Acquire()
+	require.True(t, acquired)
+	other, _ := repository.Lock("short", time.Minute)
+	acquired, _ = other.Acquire()
+	assert.False(t, acquired)
+	time.Sleep(20 * time.Millisecond)
+	acquired, err = other.Acquire()
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	require.NoError(t, short.ForceRelease())
+	acquired, _ = short.Acquire()
+	assert.True(t, acquired)
+}
+
+func TestLocks(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		testLocks(t, NewMemoryStore())
+	})
+	t.Run("redis", func(t *testing.T) {
+		server := newFakeRedis(t)
+		testLocks(t, NewRedisStore(NewRedisClient(RedisOptions{Addr: server.addr()}), "app:"))
+	})
+	t.Run("database", func(t *testing.T) {
+		testLocks(t, newTestDatabaseStore(t))
+	})
+}
+
+func TestLockBlock(t *testing.T) {
+	repository := NewRepository(NewMemoryStore())
+	holder, _ := repository.Lock("import", time.Minute)
+	acquired, _ := holder.Acquire()
+	require.True(t, acquired)
+
+	waiter, _ := repository.Lock("import", time.Minute)
+	waiter.SetRetryInterval(5 * time.Millisecond)
+	assert.ErrorIs(t, waiter.Block(20*time.Millisecond, func() error { return nil }), ErrLockTimeout)
+
+	time.AfterFunc(10*time.Millisecond, func() { holder.Release() })
+	err := waiter.Block(time.Second, func() error { return errors.New("import failed") })
+	assert.EqualError(t, err, "import failed")
+
+	// The lock was released after fn ran.
+	acquired, _ = holder.Acquire()
+	assert.True(t, acquired)
+}
+
+func TestLocksNotSupported(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = NewRepository(store).Lock("report", time.Minute)
+	assert.ErrorIs(t, err, ErrLocksNotSupported)
+}
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type lruEntry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// LRUStore is an in-memory cache store holding at most a fixed number of
+// items, evicting the least recently used item when full.
+type LRUStore struct {
+	size  int
+	items map[string]*list.Element
+	order *list.List
+	mu    sync.Mutex
+}
+
+// NewLRUStore creates an LRU store holding up to size items.
+func NewLRUStore(size int) *LRUStore {
+	if size <= 0 {
+		size = 1000
+	}
+	return &LRUStore{
+		size:  size,
+		items: make(map[string]*list.Element),
+		order: list.New(),
+	}
+}
+
+// Get retrieves an item from the cache.
+func (s *LRUStore) Get(key string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.items[key]
+	if !ok {
+		return nil, nil
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		s.remove(element)
+		return nil, nil
+	}
+
+	s.order.MoveToFront(element)
+	return entry.value, nil
+}
+
+// Put stores an item in the cache. A ttl of zero stores it without expiry.
+func (s *LRUStore) Put(key string, value any, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl != 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+	if element, ok := s.items[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		s.order.MoveToFront(element)
+		return nil
+	}
+
+	s.items[key] = s.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	if s.order.Len() > s.size {
+		s.remove(s.order.Back())
+	}
+	return nil
+}
+
+// Forget removes an item from the cache.
+func (s *LRUStore) Forget(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.items[key]; ok {
+		s.remove(element)
+	}
+	return nil
+}
+
+// Flush removes all items from the cache.
+func (s *LRUStore) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items = make(map[string]*list.Element)
+	s.order.Init()
+	return nil
+}
+
+// Len returns the number of items held, including expired items not yet evicted.
+func (s *LRUStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+func (s *LRUStore) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.items, element.Value.(*lruEntry).key)
+}
@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	store := NewLRUStore(2)
+
+	require.NoError(t, store.Put("a", 1, time.Minute))
+	require.NoError(t, store.Put("b", 2, time.Minute))
+
+	// Reading a makes b the least recently used.
+	value, _ := store.Get("a")
+	assert.Equal(t, 1, value)
+
+	require.NoError(t, store.Put("c", 3, time.Minute))
+	assert.Equal(t, 2, store.Len())
+
+	value, _ = store.Get("b")
+	assert.Nil(t, value)
+	value, _ = store.Get("a")
+	assert.Equal(t, 1, value)
+	value, _ = store.Get("c")
+	assert.Equal(t, 3, value)
+}
+
+func TestLRUStoreExpirationAndForget(t *testing.T) {
+	store := NewLRUStore(10)
+
+	store.Put("short", "x", time.Millisecond)
+	store.Put("long", "y", time.Minute)
+	time.Sleep(5 * time.Millisecond)
+
+	value, _ := store.Get("short")
+	assert.Nil(t, value)
+	assert.Equal(t, 1, store.Len())
+
+	store.Put("long", "z", time.Minute)
+	value, _ = store.Get("long")
+	assert.Equal(t, "z", value)
+
+	store.Forget("long")
+	assert.Equal(t, 0, store.Len())
+
+	store.Put("a", 1, time.Minute)
+	store.Flush()
+	assert.Equal(t, 0, store.Len())
+}
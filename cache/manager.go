@@ -3,12 +3,19 @@ package cache
 import (
 	"fmt"
 	"sync"
+
+	"github.com/genesysflow/go-genesys/crypt"
 )
 
 // Manager manages cache stores.
 type Manager struct {
 	stores       map[string]Store
+	creators     map[string]func() (Store, error)
+	repositories map[string]*Repository
 	defaultStore string
+	encrypted    map[string]bool
+	encrypter    *crypt.Encrypter
+	warm         map[string]WarmEntry
 	mu           sync.RWMutex
 }
 
@@ -16,17 +23,37 @@ type Manager struct {
 func NewManager() *Manager {
 	return &Manager{
 		stores:       make(map[string]Store),
+		creators:     make(map[string]func() (Store, error)),
+		repositories: make(map[string]*Repository),
 		defaultStore: "memory",
+		encrypted:    make(map[string]bool),
+		warm:         make(map[string]WarmEntry),
 	}
 }
 
 // Store returns a cache store by name.
+// Stores marked with Encrypt are returned wrapped in an EncryptedStore.
 func (m *Manager) Store(name ...string) (Store, error) {
-	storeName := m.defaultStore
-	if len(name) > 0 && name[0] != "" {
-		storeName = name[0]
+	storeName := m.storeName(name)
+
+	store, err := m.store(storeName)
+	if err != nil {
+		return nil, err
 	}
 
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if !m.encrypted[storeName] {
+		return store, nil
+	}
+	if m.encrypter == nil {
+		return nil, fmt.Errorf("cache store [%s] requires encryption but no encrypter is set", storeName)
+	}
+	return NewEncryptedStore(store, m.encrypter), nil
+}
+
+// store returns the raw cache store by name.
+func (m *Manager) store(storeName string) (Store, error) {
 	m.mu.RLock()
 	store, ok := m.stores[storeName]
 	m.mu.RUnlock()
@@ -35,16 +62,25 @@ func (m *Manager) Store(name ...string) (Store, error) {
 		return store, nil
 	}
 
-	// Create store if not exists (lazy loading could be implemented here)
-	// For now, we only support memory store and it should be registered manually or via config
-	// But to make it work out of the box:
-	if storeName == "memory" {
-		m.mu.Lock()
-		defer m.mu.Unlock()
-		// Double check
-		if store, ok := m.stores[storeName]; ok {
-			return store, nil
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Double check
+	if store, ok := m.stores[storeName]; ok {
+		return store, nil
+	}
+
+	if creator, ok := m.creators[storeName]; ok {
+		store, err := creator()
+		if err != nil {
+			return nil, fmt.Errorf("cache store [%s]: %w", storeName, err)
 		}
+		m.stores[storeName] = store
+		delete(m.creators, storeName)
+		return store, nil
+	}
+
+	// The memory store works out of the box.
+	if storeName == "memory" {
 		store = NewMemoryStore()
 		m.stores[storeName] = store
 		return store, nil
@@ -53,9 +89,87 @@ func (m *Manager) Store(name ...string) (Store, error) {
 	return nil, fmt.Errorf("cache store [%s] not found", storeName)
 }
 
+// Repository returns a Repository for a cache store by name, or for the
+// default store.
+func (m *Manager) Repository(name ...string) (*Repository, error) {
+	storeName := m.storeName(name)
+
+	m.mu.RLock()
+	repository, ok := m.repositories[storeName]
+	m.mu.RUnlock()
+	if ok {
+		return repository, nil
+	}
+
+	store, err := m.Store(storeName)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if repository, ok := m.repositories[storeName]; ok {
+		return repository, nil
+	}
+	repository = NewRepository(store)
+	m.repositories[storeName] = repository
+	return repository, nil
+}
+
+// DefaultStore returns the name of the default store.
+func (m *Manager) DefaultStore() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.defaultStore
+}
+
+// SetDefaultStore sets the name of the default store.
+func (m *Manager) SetDefaultStore(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.defaultStore = name
+}
+
+func (m *Manager) storeName(name []string) string {
+	if len(name) > 0 && name[0] != "" {
+		return name[0]
+	}
+	return m.DefaultStore()
+}
+
 // Register registers a cache store.
 func (m *Manager) Register(name string, store Store) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.stores[name] = store
+	delete(m.creators, name)
+	delete(m.repositories, name)
+}
+
+// RegisterFunc registers a store that is created on first use, for stores
+// that depend on services booted after the cache provider.
+func (m *Manager) RegisterFunc(name string, creator func() (Store, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.creators[name] = creator
+	delete(m.stores, name)
+	delete(m.repositories, name)
+}
+
+// SetEncrypter sets the encrypter used for encrypted stores.
+func (m *Manager) SetEncrypter(encrypter *crypt.Encrypter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.encrypter = encrypter
+	m.repositories = make(map[string]*Repository)
+}
+
+// Encrypt marks stores whose values must be encrypted at rest.
+func (m *Manager) Encrypt(names ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, name := range names {
+		m.encrypted[name] = true
+		delete(m.repositories, name)
+	}
 }
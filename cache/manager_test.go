@@ -130,3 +130,27 @@ func TestManagerEmptyStoreName(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, store)
 }
+
+func TestManagerRegisterFuncAndDefault(t *testing.T) {
+	manager := NewManager()
+	created := 0
+	manager.RegisterFunc("lazy", func() (Store, error) {
+		created++
+		return NewMemoryStore(), nil
+	})
+	manager.RegisterFunc("broken", func() (Store, error) {
+		return nil, assert.AnError
+	})
+	manager.SetDefaultStore("lazy")
+	assert.Equal(t, 0, created)
+
+	repository, err := manager.Repository()
+	require.NoError(t, err)
+	again, err := manager.Repository("lazy")
+	require.NoError(t, err)
+	assert.Same(t, repository, again)
+	assert.Equal(t, 1, created)
+
+	_, err = manager.Store("broken")
+	assert.ErrorContains(t, err, "cache store [broken]")
+}
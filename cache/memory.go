@@ -10,9 +10,17 @@ type item struct {
 	expiresAt time.Time
 }
 
+// expired reports whether the item has expired. Items without an expiry
+// never do.
+func (i item) expired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && now.After(i.expiresAt)
+}
+
 // MemoryStore is an in-memory cache store.
 type MemoryStore struct {
 	items map[string]item
+	tags  map[string]map[string]struct{}
+	locks map[string]item
 	mu    sync.RWMutex
 }
 
@@ -20,6 +28,8 @@ type MemoryStore struct {
 func NewMemoryStore() *MemoryStore {
 	return &MemoryStore{
 		items: make(map[string]item),
+		tags:  make(map[string]map[string]struct{}),
+		locks: make(map[string]item),
 	}
 }
 
@@ -33,25 +43,58 @@ func (s *MemoryStore) Get(key string) (any, error) {
 		return nil, nil
 	}
 
-	if time.Now().After(item.expiresAt) {
+	if item.expired(time.Now()) {
 		return nil, nil
 	}
 
 	return item.value, nil
 }
 
-// Put stores an item in the cache.
+// Put stores an item in the cache. A ttl of zero stores it without expiry.
 func (s *MemoryStore) Put(key string, value any, ttl time.Duration) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.items[key] = item{
-		value:     value,
-		expiresAt: time.Now().Add(ttl),
-	}
+	s.items[key] = newItem(value, ttl)
 	return nil
 }
 
+// Add stores an item if the key is missing or expired.
+func (s *MemoryStore) Add(key string, value any, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.items[key]; ok && !existing.expired(time.Now()) {
+		return false, nil
+	}
+	s.items[key] = newItem(value, ttl)
+	return true, nil
+}
+
+// Increment adds by to an integer item, keeping its expiry.
+func (s *MemoryStore) Increment(key string, by int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.items[key]
+	if !ok || existing.expired(time.Now()) {
+		existing = item{}
+	}
+
+	var current int64
+	if existing.value != nil {
+		n, err := toInt64(key, existing.value)
+		if err != nil {
+			return 0, err
+		}
+		current = n
+	}
+
+	existing.value = current + by
+	s.items[key] = existing
+	return current + by, nil
+}
+
 // Forget removes an item from the cache.
 func (s *MemoryStore) Forget(key string) error {
 	s.mu.Lock()
@@ -65,5 +108,73 @@ func (s *MemoryStore) Flush() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.items = make(map[string]item)
+	s.tags = make(map[string]map[string]struct{})
+	return nil
+}
+
+// Tag records that key belongs to each of the tags.
+func (s *MemoryStore) Tag(key string, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tag := range tags {
+		if s.tags[tag] == nil {
+			s.tags[tag] = make(map[string]struct{})
+		}
+		s.tags[tag][key] = struct{}{}
+	}
+	return nil
+}
+
+// FlushTags removes every item recorded under any of the tags.
+func (s *MemoryStore) FlushTags(tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tag := range tags {
+		for key := range s.tags[tag] {
+			delete(s.items, key)
+		}
+		delete(s.tags, tag)
+	}
 	return nil
 }
+
+// AcquireLock takes the lock for owner if it is free or expired.
+func (s *MemoryStore) AcquireLock(name, owner string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if lock, ok := s.locks[name]; ok && !lock.expired(time.Now()) {
+		return false, nil
+	}
+	s.locks[name] = newItem(owner, ttl)
+	return true, nil
+}
+
+// ReleaseLock frees the lock if owner holds it.
+func (s *MemoryStore) ReleaseLock(name, owner string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock, ok := s.locks[name]
+	if !ok || lock.expired(time.Now()) || lock.value != owner {
+		return false, nil
+	}
+	delete(s.locks, name)
+	return true, nil
+}
+
+// ForceReleaseLock frees the lock whoever holds it.
+func (s *MemoryStore) ForceReleaseLock(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.locks, name)
+	return nil
+}
+
+func newItem(value any, ttl time.Duration) item {
+	entry := item{value: value}
+	if ttl != 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	return entry
+}
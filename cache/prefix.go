@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrPrefixFlushNotSupported is returned by Flush on a prefixed repository
+// whose store does not implement Taggable.
+var ErrPrefixFlushNotSupported = errors.New("cache: store can't flush a prefix")
+
+// Prefix returns a Repository that stores every key under prefix in the
+// same store, so callers such as tenants can share a store without seeing
+// each other's items. Lock names and tags are prefixed too. On stores
+// implementing Taggable its Flush removes only the prefixed items; on
+// others it returns ErrPrefixFlushNotSupported. Locks and tags return
+// ErrLocksNotSupported and ErrTagsNotSupported where the store lacks them.
+func (r *Repository) Prefix(prefix string) *Repository {
+	store := &prefixedStore{repository: r, prefix: prefix}
+	store.taggable, _ = r.store.(Taggable)
+	store.locker, _ = r.store.(Locker)
+	return NewRepository(store)
+}
+
+// prefixedStore prefixes every key, lock name and tag. Add and Increment
+// go through the parent repository so they stay atomic where the store is.
+type prefixedStore struct {
+	repository *Repository
+	taggable   Taggable
+	locker     Locker
+	prefix     string
+}
+
+func (s *prefixedStore) Get(key string) (any, error) {
+	return s.repository.store.Get(s.prefix + key)
+}
+
+func (s *prefixedStore) Put(key string, value any, ttl time.Duration) error {
+	if err := s.repository.store.Put(s.prefix+key, value, ttl); err != nil {
+		return err
+	}
+	return s.tag(key)
+}
+
+func (s *prefixedStore) Add(key string, value any, ttl time.Duration) (bool, error) {
+	added, err := s.repository.Add(s.prefix+key, value, ttl)
+	if err != nil || !added {
+		return added, err
+	}
+	return true, s.tag(key)
+}
+
+func (s *prefixedStore) Increment(key string, by int64) (int64, error) {
+	n, err := s.repository.Increment(s.prefix+key, by)
+	if err != nil {
+		return 0, err
+	}
+	return n, s.tag(key)
+}
+
+func (s *prefixedStore) Forget(key string) error {
+	return s.repository.store.Forget(s.prefix + key)
+}
+
+// Flush removes the prefixed items.
+func (s *prefixedStore) Flush() error {
+	if s.taggable == nil {
+		return ErrPrefixFlushNotSupported
+	}
+	return s.taggable.FlushTags(s.tagName())
+}
+
+// Tag records that the prefixed key belongs to each of the prefixed tags.
+func (s *prefixedStore) Tag(key string, tags ...string) error {
+	if s.taggable == nil {
+		return ErrTagsNotSupported
+	}
+	return s.taggable.Tag(s.prefix+key, s.prefixAll(tags)...)
+}
+
+// FlushTags removes every item recorded under any of the prefixed tags.
+func (s *prefixedStore) FlushTags(tags ...string) error {
+	if s.taggable == nil {
+		return ErrTagsNotSupported
+	}
+	return s.taggable.FlushTags(s.prefixAll(tags)...)
+}
+
+// AcquireLock takes the prefixed lock for owner.
+func (s *prefixedStore) AcquireLock(name, owner string, ttl time.Duration) (bool, error) {
+	if s.locker == nil {
+		return false, ErrLocksNotSupported
+	}
+	return s.locker.AcquireLock(s.prefix+name, owner, ttl)
+}
+
+// ReleaseLock frees the prefixed lock if owner holds it.
+func (s *prefixedStore) ReleaseLock(name, owner string) (bool, error) {
+	if s.locker == nil {
+		return false, ErrLocksNotSupported
+	}
+	return s.locker.ReleaseLock(s.prefix+name, owner)
+}
+
+// ForceReleaseLock frees the prefixed lock whoever holds it.
+func (s *prefixedStore) ForceReleaseLock(name string) error {
+	if s.locker == nil {
+		return ErrLocksNotSupported
+	}
+	return s.locker.ForceReleaseLock(s.prefix + name)
+}
+
+func (s *prefixedStore) prefixAll(names []string) []string {
+	prefixed := make([]string, len(names))
+	for i, name := range names {
+		prefixed[i] = s.prefix + name
+	}
+	return prefixed
+}
+
+// tag records a written key under the prefix's tag, so Flush can find it.
+func (s *prefixedStore) tag(key string) error {
+	if s.taggable == nil {
+		return nil
+	}
+	return s.taggable.Tag(s.prefix+key, s.tagName())
+}
+
+func (s *prefixedStore) tagName() string {
+	return "prefix:" + s.prefix
+}
@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefix(t *testing.T) {
+	repository := NewRepository(NewMemoryStore())
+	acme := repository.Prefix("tenant:acme:")
+	globex := repository.Prefix("tenant:globex:")
+
+	require.NoError(t, acme.Put("plan", "pro", time.Minute))
+	require.NoError(t, globex.Put("plan", "free", time.Minute))
+	_, err := acme.Increment("visits")
+	require.NoError(t, err)
+	require.NoError(t, repository.Put("plan", "shared", time.Minute))
+
+	value, err := acme.Get("plan")
+	require.NoError(t, err)
+	assert.Equal(t, "pro", value)
+	value, err = repository.Get("tenant:globex:plan")
+	require.NoError(t, err)
+	assert.Equal(t, "free", value)
+
+	require.NoError(t, acme.Flush())
+	for _, key := range []string{"plan", "visits"} {
+		has, err := acme.Has(key)
+		require.NoError(t, err)
+		assert.False(t, has, key)
+	}
+	has, err := globex.Has("plan")
+	require.NoError(t, err)
+	assert.True(t, has)
+	has, err = repository.Has("plan")
+	require.NoError(t, err)
+	assert.True(t, has)
+}
+
+func TestPrefixFlushNotSupported(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	prefixed := NewRepository(store).Prefix("tenant:acme:")
+
+	require.NoError(t, prefixed.Put("plan", "pro", time.Minute))
+	value, err := store.Get("tenant:acme:plan")
+	require.NoError(t, err)
+	assert.Equal(t, "pro", value)
+	assert.ErrorIs(t, prefixed.Flush(), ErrPrefixFlushNotSupported)
+}
+
+func TestPrefixLocks(t *testing.T) {
+	repository := NewRepository(NewMemoryStore())
+	acme := repository.Prefix("tenant:acme:")
+	globex := repository.Prefix("tenant:globex:")
+
+	acmeLock, err := acme.Lock("reports", time.Minute)
+	require.NoError(t, err)
+	acquired, err := acmeLock.Acquire()
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	// Tenants don't share locks of the same name.
+	globexLock, err := globex.Lock("reports", time.Minute)
+	require.NoError(t, err)
+	acquired, err = globexLock.Acquire()
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	other, err := acme.Lock("reports", time.Minute)
+	require.NoError(t, err)
+	acquired, err = other.Acquire()
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	shared, err := repository.Lock("tenant:acme:reports", time.Minute)
+	require.NoError(t, err)
+	acquired, err = shared.Acquire()
+	require.NoError(t, err)
+	assert.False(t, acquired, "the lock is held under the prefixed name")
+
+	released, err := acmeLock.Release()
+	require.NoError(t, err)
+	assert.True(t, released)
+}
+
+func TestPrefixTags(t *testing.T) {
+	repository := NewRepository(NewMemoryStore())
+	acme := repository.Prefix("tenant:acme:")
+	globex := repository.Prefix("tenant:globex:")
+
+	acmeUsers, err := acme.Tags("users")
+	require.NoError(t, err)
+	globexUsers, err := globex.Tags("users")
+	require.NoError(t, err)
+	require.NoError(t, acmeUsers.Put("count", 3, time.Minute))
+	require.NoError(t, globexUsers.Put("count", 5, time.Minute))
+	require.NoError(t, acme.Put("plan", "pro", time.Minute))
+
+	require.NoError(t, acmeUsers.Flush())
+	has, err := acme.Has("count")
+	require.NoError(t, err)
+	assert.False(t, has)
+	has, err = acme.Has("plan")
+	require.NoError(t, err)
+	assert.True(t, has, "flushing a tag leaves the tenant's untagged items")
+	value, err := globex.Get("count")
+	require.NoError(t, err)
+	assert.Equal(t, 5, value, "flushing a tag leaves other tenants' items")
+}
+
+func TestPrefixLocksAndTagsNotSupported(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	prefixed := NewRepository(store).Prefix("tenant:acme:")
+
+	lock, err := prefixed.Lock("reports", time.Minute)
+	require.NoError(t, err)
+	_, err = lock.Acquire()
+	assert.ErrorIs(t, err, ErrLocksNotSupported)
+
+	tagged, err := prefixed.Tags("users")
+	require.NoError(t, err)
+	assert.ErrorIs(t, tagged.Put("count", 3, time.Minute), ErrTagsNotSupported)
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// QueryKey returns the cache key of a query's results, derived from its SQL
+// and bindings.
+func QueryKey(query string, bindings ...any) string {
+	hash := sha1.New()
+	hash.Write([]byte(query))
+	encoded, _ := json.Marshal(bindings)
+	hash.Write(encoded)
+	return "query:" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// RememberQuery returns the rows of a select, caching them for ttl under key
+// or, if key is empty, under QueryKey(query, bindings...). Each row maps
+// column names to values; byte slices are returned as strings. Stores that
+// JSON encode values return numbers as float64.
+func (r *Repository) RememberQuery(ctx context.Context, conn contracts.DBTX, ttl time.Duration, key, query string, bindings ...any) ([]map[string]any, error) {
+	if key == "" {
+		key = QueryKey(query, bindings...)
+	}
+
+	value, err := r.Remember(key, ttl, func() (any, error) {
+		return selectRows(ctx, conn, query, bindings...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return queryRows(key, value)
+}
+
+// ForgetQuery removes the cached results of a query remembered without an
+// explicit key.
+func (r *Repository) ForgetQuery(query string, bindings ...any) error {
+	return r.Forget(QueryKey(query, bindings...))
+}
+
+func selectRows(ctx context.Context, conn contracts.DBTX, query string, bindings ...any) ([]map[string]any, error) {
+	rows, err := conn.QueryContext(ctx, query, bindings...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	results := []map[string]any{}
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				row[column] = string(b)
+			} else {
+				row[column] = values[i]
+			}
+		}
+		results = append(results, row)
+	}
+	return results, rows.Err()
+}
+
+// queryRows converts cached rows back to maps. Stores that JSON encode
+// values return them as []any.
+func queryRows(key string, value any) ([]map[string]any, error) {
+	switch rows := value.(type) {
+	case []map[string]any:
+		return rows, nil
+	case []any:
+		results := make([]map[string]any, len(rows))
+		for i, row := range rows {
+			m, ok := row.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("cache: value for key %s is not a query result", key)
+			}
+			results[i] = m
+		}
+		return results, nil
+	}
+	return nil, fmt.Errorf("cache: value for key %s is not a query result", key)
+}
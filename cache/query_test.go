@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConnection(t *testing.T) contracts.Connection {
+	t.Helper()
+	manager := database.NewManager(database.Config{
+		Default: "default",
+		Connections: map[string]database.ConnectionConfig{
+			"default": {Driver: "sqlite", Database: filepath.Join(t.TempDir(), "app.db")},
+		},
+	})
+	t.Cleanup(func() { manager.Close() })
+
+	conn := manager.Connection()
+	require.NoError(t, conn.Error())
+	_, err := conn.Exec(`CREATE TABLE orders (id INTEGER, status TEXT, total INTEGER)`)
+	require.NoError(t, err)
+	_, err = conn.Exec(`INSERT INTO orders VALUES (1, 'paid', 40), (2, 'paid', 60), (3, 'open', 10)`)
+	require.NoError(t, err)
+	return conn
+}
+
+func TestRememberQuery(t *testing.T) {
+	ctx := context.Background()
+	query := `SELECT status, SUM(total) AS total FROM orders WHERE status = ? GROUP BY status`
+
+	file, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	for name, store := range map[string]Store{"memory": NewMemoryStore(), "file": file} {
+		t.Run(name, func(t *testing.T) {
+			conn := newTestConnection(t)
+			repository := NewRepository(store)
+
+			rows, err := repository.RememberQuery(ctx, conn, time.Minute, "", query, "paid")
+			require.NoError(t, err)
+			require.Len(t, rows, 1)
+			assert.Equal(t, "paid", rows[0]["status"])
+			assert.EqualValues(t, 100, rows[0]["total"])
+
+			// Cached: a new order is not seen until the query is forgotten.
+			_, err = conn.Exec(`INSERT INTO orders VALUES (4, 'paid', 5)`)
+			require.NoError(t, err)
+			rows, err = repository.RememberQuery(ctx, conn, time.Minute, "", query, "paid")
+			require.NoError(t, err)
+			assert.EqualValues(t, 100, rows[0]["total"])
+
+			// Other bindings are cached separately.
+			rows, err = repository.RememberQuery(ctx, conn, time.Minute, "", query, "open")
+			require.NoError(t, err)
+			assert.EqualValues(t, 10, rows[0]["total"])
+
+			require.NoError(t, repository.ForgetQuery(query, "paid"))
+			rows, err = repository.RememberQuery(ctx, conn, time.Minute, "", query, "paid")
+			require.NoError(t, err)
+			assert.EqualValues(t, 105, rows[0]["total"])
+
+			// An explicit key can be forgotten by name.
+			_, err = repository.RememberQuery(ctx, conn, time.Minute, "dashboard:revenue", query, "paid")
+			require.NoError(t, err)
+			has, _ := repository.Has("dashboard:revenue")
+			assert.True(t, has)
+		})
+	}
+}
+
+func TestQueryKey(t *testing.T) {
+	assert.Equal(t, QueryKey("SELECT ?", 1), QueryKey("SELECT ?", 1))
+	assert.NotEqual(t, QueryKey("SELECT ?", 1), QueryKey("SELECT ?", "1"))
+}
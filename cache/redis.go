@@ -0,0 +1,239 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// RedisStore is a cache store backed by Redis. Values are JSON encoded, so
+// Get returns them as decoded JSON (numbers as float64, objects as map[string]any).
+type RedisStore struct {
+	client *RedisClient
+	prefix string
+}
+
+// NewRedisStore creates a Redis store. All keys are stored under prefix.
+func NewRedisStore(client *RedisClient, prefix string) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Client returns the underlying Redis client.
+func (s *RedisStore) Client() *RedisClient {
+	return s.client
+}
+
+// Prefix returns the prefix of the store's keys.
+func (s *RedisStore) Prefix() string {
+	return s.prefix
+}
+
+// Get retrieves an item from the cache.
+func (s *RedisStore) Get(key string) (any, error) {
+	reply, err := s.client.Do(context.Background(), "GET", s.prefix+key)
+	if err != nil || reply == nil {
+		return nil, err
+	}
+	return s.decode(key, reply)
+}
+
+// GetWithTTL retrieves an item and its remaining time to live.
+func (s *RedisStore) GetWithTTL(key string) (any, time.Duration, error) {
+	value, err := s.Get(key)
+	if err != nil || value == nil {
+		return nil, 0, err
+	}
+
+	reply, err := s.client.Do(context.Background(), "PTTL", s.prefix+key)
+	if err != nil {
+		return nil, 0, err
+	}
+	ms, _ := reply.(int64)
+	if ms < 0 {
+		// -1: no expiry, -2: expired since the GET
+		if ms == -2 {
+			return nil, 0, nil
+		}
+		return value, 0, nil
+	}
+	return value, time.Duration(ms) * time.Millisecond, nil
+}
+
+// Put stores an item in the cache. A ttl of zero stores it without expiry.
+func (s *RedisStore) Put(key string, value any, ttl time.Duration) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("cache: failed to encode value for key %s: %w", key, err)
+	}
+
+	args := []any{"SET", s.prefix + key, payload}
+	if ttl > 0 {
+		args = append(args, "PX", ttl.Milliseconds())
+	}
+	_, err = s.client.Do(context.Background(), args...)
+	return err
+}
+
+// Add stores an item with SET NX if the key is missing.
+func (s *RedisStore) Add(key string, value any, ttl time.Duration) (bool, error) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("cache: failed to encode value for key %s: %w", key, err)
+	}
+
+	args := []any{"SET", s.prefix + key, payload, "NX"}
+	if ttl > 0 {
+		args = append(args, "PX", ttl.Milliseconds())
+	}
+	reply, err := s.client.Do(context.Background(), args...)
+	if err != nil {
+		return false, err
+	}
+	return reply != nil, nil
+}
+
+// Increment adds by to an integer item with INCRBY.
+func (s *RedisStore) Increment(key string, by int64) (int64, error) {
+	reply, err := s.client.Do(context.Background(), "INCRBY", s.prefix+key, by)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("cache: unexpected reply for key %s", key)
+	}
+	return n, nil
+}
+
+// Forget removes an item from the cache.
+func (s *RedisStore) Forget(key string) error {
+	_, err := s.client.Do(context.Background(), "DEL", s.prefix+key)
+	return err
+}
+
+// Flush removes all items under the store's prefix, or the whole database
+// if the store has no prefix.
+func (s *RedisStore) Flush() error {
+	ctx := context.Background()
+	if s.prefix == "" {
+		_, err := s.client.Do(ctx, "FLUSHDB")
+		return err
+	}
+
+	cursor := "0"
+	for {
+		reply, err := s.client.Do(ctx, "SCAN", cursor, "MATCH", s.prefix+"*", "COUNT", 500)
+		if err != nil {
+			return err
+		}
+		parts, ok := reply.([]any)
+		if !ok || len(parts) != 2 {
+			return fmt.Errorf("cache: unexpected SCAN reply")
+		}
+
+		keys, _ := parts[1].([]any)
+		if len(keys) > 0 {
+			if _, err := s.client.Do(ctx, append([]any{"DEL"}, keys...)...); err != nil {
+				return err
+			}
+		}
+
+		cursor, _ = parts[0].(string)
+		if cursor == "0" || cursor == "" {
+			return nil
+		}
+	}
+}
+
+// Tag adds key to a Redis set per tag.
+func (s *RedisStore) Tag(key string, tags ...string) error {
+	ctx := context.Background()
+	for _, tag := range tags {
+		if _, err := s.client.Do(ctx, "SADD", s.tagKey(tag), key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FlushTags deletes the keys in each tag's set, then the set.
+func (s *RedisStore) FlushTags(tags ...string) error {
+	ctx := context.Background()
+	for _, tag := range tags {
+		reply, err := s.client.Do(ctx, "SMEMBERS", s.tagKey(tag))
+		if err != nil {
+			return err
+		}
+		members, _ := reply.([]any)
+
+		keys := []any{"DEL", s.tagKey(tag)}
+		for _, member := range members {
+			if key, ok := member.(string); ok {
+				keys = append(keys, s.prefix+key)
+			}
+		}
+		if _, err := s.client.Do(ctx, keys...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseScript deletes a lock only if its value is the caller's owner token.
+const releaseScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+
+// AcquireLock takes the lock with SET NX, storing the owner token.
+func (s *RedisStore) AcquireLock(name, owner string, ttl time.Duration) (bool, error) {
+	args := []any{"SET", s.lockKey(name), owner, "NX"}
+	if ttl > 0 {
+		args = append(args, "PX", ttl.Milliseconds())
+	}
+	reply, err := s.client.Do(context.Background(), args...)
+	if err != nil {
+		return false, err
+	}
+	return reply != nil, nil
+}
+
+// ReleaseLock frees the lock if owner holds it. The check and delete run in
+// one script so a lock that expired and was taken by another owner is left
+// alone.
+func (s *RedisStore) ReleaseLock(name, owner string) (bool, error) {
+	reply, err := s.client.Do(context.Background(), "EVAL", releaseScript, 1, s.lockKey(name), owner)
+	if err != nil {
+		return false, err
+	}
+	n, _ := reply.(int64)
+	return n == 1, nil
+}
+
+// ForceReleaseLock frees the lock whoever holds it.
+func (s *RedisStore) ForceReleaseLock(name string) error {
+	_, err := s.client.Do(context.Background(), "DEL", s.lockKey(name))
+	return err
+}
+
+func (s *RedisStore) lockKey(name string) string {
+	return s.prefix + "lock:" + name
+}
+
+func (s *RedisStore) tagKey(tag string) string {
+	return s.prefix + "tag:" + tag + ":keys"
+}
+
+func (s *RedisStore) decode(key string, reply any) (any, error) {
+	payload, ok := reply.(string)
+	if !ok {
+		return nil, fmt.Errorf("cache: unexpected reply for key %s", key)
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(payload), &value); err != nil {
+		return nil, fmt.Errorf("cache: failed to decode value for key %s: %w", key, err)
+	}
+	return value, nil
+}
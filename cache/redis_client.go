@@ -0,0 +1,285 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// RedisError is an error reply from the Redis server.
+type RedisError string
+
+func (e RedisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// RedisOptions configures a Redis connection.
+type RedisOptions struct {
+	// Addr is the host:port of the server. Defaults to 127.0.0.1:6379.
+	Addr     string
+	Password string
+	DB       int
+
+	// PoolSize is the number of idle connections kept open. Defaults to 10.
+	PoolSize int
+
+	// DialTimeout defaults to 5 seconds.
+	DialTimeout time.Duration
+}
+
+// RedisCommander sends Redis commands. *RedisClient implements it; the
+// Redis drivers of other packages accept any implementation, such as an
+// adapter for another client library.
+type RedisCommander interface {
+	Do(ctx context.Context, args ...any) (any, error)
+}
+
+// RedisClient is a minimal Redis client speaking RESP over a pool of connections.
+type RedisClient struct {
+	options RedisOptions
+	idle    chan *redisConn
+}
+
+// NewRedisClient creates a Redis client. Connections are opened lazily.
+func NewRedisClient(options RedisOptions) *RedisClient {
+	if options.Addr == "" {
+		options.Addr = "127.0.0.1:6379"
+	}
+	if options.PoolSize <= 0 {
+		options.PoolSize = 10
+	}
+	if options.DialTimeout <= 0 {
+		options.DialTimeout = 5 * time.Second
+	}
+
+	return &RedisClient{
+		options: options,
+		idle:    make(chan *redisConn, options.PoolSize),
+	}
+}
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+// dial opens an authenticated connection to the configured database.
+func (c *RedisClient) dial(ctx context.Context) (*redisConn, error) {
+	dialer := net.Dialer{Timeout: c.options.DialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.options.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	rc := &redisConn{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		writer: bufio.NewWriter(conn),
+	}
+	if c.options.Password != "" {
+		if _, err := rc.do("AUTH", c.options.Password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if c.options.DB != 0 {
+		if _, err := rc.do("SELECT", c.options.DB); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return rc, nil
+}
+
+// Do sends a command and returns its reply: a string, int64, []any, or nil
+// for a null reply. Error replies are returned as RedisError.
+func (c *RedisClient) Do(ctx context.Context, args ...any) (any, error) {
+	var rc *redisConn
+	select {
+	case rc = <-c.idle:
+	default:
+		var err error
+		if rc, err = c.dial(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		rc.conn.SetDeadline(deadline)
+	} else {
+		rc.conn.SetDeadline(time.Time{})
+	}
+
+	reply, err := rc.do(args...)
+	var redisErr RedisError
+	if err != nil && !errors.As(err, &redisErr) {
+		// The connection may be mid-reply; don't reuse it.
+		rc.conn.Close()
+		return nil, err
+	}
+
+	select {
+	case c.idle <- rc:
+	default:
+		rc.conn.Close()
+	}
+	return reply, err
+}
+
+// Publish posts a message to a channel.
+func (c *RedisClient) Publish(ctx context.Context, channel, message string) error {
+	_, err := c.Do(ctx, "PUBLISH", channel, message)
+	return err
+}
+
+// Subscribe listens for messages on a channel on a dedicated connection,
+// calling handler for each one. It blocks until ctx is cancelled or the
+// connection fails, and returns nil only when ctx is cancelled.
+func (c *RedisClient) Subscribe(ctx context.Context, channel string, handler func(message string)) error {
+	return c.SubscribeReady(ctx, channel, nil, handler)
+}
+
+// SubscribeReady is Subscribe, calling ready once Redis confirms the
+// subscription.
+func (c *RedisClient) SubscribeReady(ctx context.Context, channel string, ready func(), handler func(message string)) error {
+	rc, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer rc.conn.Close()
+
+	stop := context.AfterFunc(ctx, func() { rc.conn.Close() })
+	defer stop()
+
+	if err := rc.write("SUBSCRIBE", channel); err != nil {
+		return err
+	}
+
+	for {
+		reply, err := rc.read()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		parts, ok := reply.([]any)
+		if !ok || len(parts) != 3 {
+			continue
+		}
+		switch kind, _ := parts[0].(string); kind {
+		case "subscribe":
+			if ready != nil {
+				ready()
+			}
+		case "message":
+			message, _ := parts[2].(string)
+			handler(message)
+		}
+	}
+}
+
+// Close closes idle connections.
+func (c *RedisClient) Close() error {
+	for {
+		select {
+		case rc := <-c.idle:
+			rc.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (rc *redisConn) do(args ...any) (any, error) {
+	if err := rc.write(args...); err != nil {
+		return nil, err
+	}
+	return rc.read()
+}
+
+// write sends a command as a RESP array of bulk strings.
+func (rc *redisConn) write(args ...any) error {
+	fmt.Fprintf(rc.writer, "*%d\r\n", len(args))
+	for _, arg := range args {
+		var s string
+		switch v := arg.(type) {
+		case string:
+			s = v
+		case []byte:
+			s = string(v)
+		case int:
+			s = strconv.Itoa(v)
+		case int64:
+			s = strconv.FormatInt(v, 10)
+		default:
+			s = fmt.Sprint(v)
+		}
+		fmt.Fprintf(rc.writer, "$%d\r\n%s\r\n", len(s), s)
+	}
+	return rc.writer.Flush()
+}
+
+// read parses a single RESP reply.
+func (rc *redisConn) read() (any, error) {
+	line, err := rc.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, RedisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(rc.reader, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			item, err := rc.read()
+			var redisErr RedisError
+			if errors.As(err, &redisErr) {
+				// Keep reading so the connection stays in sync.
+				item = redisErr
+			} else if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+	}
+}
@@ -0,0 +1,324 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis is a tiny in-memory Redis server supporting the commands the
+// cache uses.
+type fakeRedis struct {
+	listener    net.Listener
+	password    string
+	values      map[string]string
+	sets        map[string]map[string]bool
+	expires     map[string]time.Time
+	subscribers map[string][]*redisConn
+	mu          sync.Mutex
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &fakeRedis{
+		listener:    listener,
+		values:      make(map[string]string),
+		sets:        make(map[string]map[string]bool),
+		expires:     make(map[string]time.Time),
+		subscribers: make(map[string][]*redisConn),
+	}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (f *fakeRedis) addr() string {
+	return f.listener.Addr().String()
+}
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(&redisConn{conn: conn, reader: bufio.NewReader(conn), writer: bufio.NewWriter(conn)})
+	}
+}
+
+func (f *fakeRedis) handle(rc *redisConn) {
+	defer rc.conn.Close()
+	for {
+		request, err := rc.read()
+		if err != nil {
+			return
+		}
+		parts, _ := request.([]any)
+		args := make([]string, len(parts))
+		for i, part := range parts {
+			args[i], _ = part.(string)
+		}
+
+		// Replies are written under the lock since PUBLISH writes to
+		// subscriber connections from other goroutines.
+		f.mu.Lock()
+		rc.writer.WriteString(f.exec(rc, args))
+		rc.writer.Flush()
+		f.mu.Unlock()
+	}
+}
+
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+// exec runs a command and returns the raw RESP reply. Callers hold the lock.
+func (f *fakeRedis) exec(rc *redisConn, args []string) string {
+	if len(args) == 0 {
+		return "-ERR empty command\r\n"
+	}
+
+	f.expire()
+	switch strings.ToUpper(args[0]) {
+	case "AUTH":
+		if args[1] != f.password {
+			return "-WRONGPASS invalid password\r\n"
+		}
+		return "+OK\r\n"
+	case "SELECT", "PING":
+		return "+OK\r\n"
+	case "GET":
+		value, ok := f.values[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(value)
+	case "SET":
+		var ttl time.Duration
+		for i := 3; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "NX":
+				if _, ok := f.values[args[1]]; ok {
+					return "$-1\r\n"
+				}
+			case "PX":
+				ms, _ := strconv.Atoi(args[i+1])
+				ttl = time.Duration(ms) * time.Millisecond
+				i++
+			}
+		}
+		f.values[args[1]] = args[2]
+		delete(f.expires, args[1])
+		if ttl > 0 {
+			f.expires[args[1]] = time.Now().Add(ttl)
+		}
+		return "+OK\r\n"
+	case "INCRBY":
+		current, err := strconv.ParseInt(f.values[args[1]], 10, 64)
+		if _, ok := f.values[args[1]]; ok && err != nil {
+			return "-ERR value is not an integer or out of range\r\n"
+		}
+		by, _ := strconv.ParseInt(args[2], 10, 64)
+		f.values[args[1]] = strconv.FormatInt(current+by, 10)
+		return fmt.Sprintf(":%d\r\n", current+by)
+	case "EVAL":
+		// Only the lock release script is supported.
+		if f.values[args[3]] != args[4] {
+			return ":0\r\n"
+		}
+		delete(f.values, args[3])
+		return ":1\r\n"
+	case "SADD":
+		members := f.sets[args[1]]
+		if members == nil {
+			members = make(map[string]bool)
+			f.sets[args[1]] = members
+		}
+		for _, member := range args[2:] {
+			members[member] = true
+		}
+		return fmt.Sprintf(":%d\r\n", len(args)-2)
+	case "SMEMBERS":
+		reply := fmt.Sprintf("*%d\r\n", len(f.sets[args[1]]))
+		for member := range f.sets[args[1]] {
+			reply += bulk(member)
+		}
+		return reply
+	case "PTTL":
+		if _, ok := f.values[args[1]]; !ok {
+			return ":-2\r\n"
+		}
+		expiresAt, ok := f.expires[args[1]]
+		if !ok {
+			return ":-1\r\n"
+		}
+		return fmt.Sprintf(":%d\r\n", time.Until(expiresAt).Milliseconds())
+	case "DEL":
+		n := 0
+		for _, key := range args[1:] {
+			if _, ok := f.values[key]; ok {
+				delete(f.values, key)
+				n++
+			}
+			if _, ok := f.sets[key]; ok {
+				delete(f.sets, key)
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	case "FLUSHDB":
+		f.values = make(map[string]string)
+		return "+OK\r\n"
+	case "SCAN":
+		prefix := strings.TrimSuffix(args[3], "*")
+		var keys []string
+		for key := range f.values {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, bulk(key))
+			}
+		}
+		return fmt.Sprintf("*2\r\n%s*%d\r\n%s", bulk("0"), len(keys), strings.Join(keys, ""))
+	case "PUBLISH":
+		message := "*3\r\n" + bulk("message") + bulk(args[1]) + bulk(args[2])
+		for _, subscriber := range f.subscribers[args[1]] {
+			subscriber.writer.WriteString(message)
+			subscriber.writer.Flush()
+		}
+		return fmt.Sprintf(":%d\r\n", len(f.subscribers[args[1]]))
+	case "SUBSCRIBE":
+		f.subscribers[args[1]] = append(f.subscribers[args[1]], rc)
+		return "*3\r\n" + bulk("subscribe") + bulk(args[1]) + ":1\r\n"
+	default:
+		return "-ERR unknown command '" + args[0] + "'\r\n"
+	}
+}
+
+func (f *fakeRedis) expire() {
+	for key, expiresAt := range f.expires {
+		if time.Now().After(expiresAt) {
+			delete(f.values, key)
+			delete(f.expires, key)
+		}
+	}
+}
+
+// subscriberCount returns the number of subscriptions to a channel.
+func (f *fakeRedis) subscriberCount(channel string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subscribers[channel])
+}
+
+func TestRedisClientDo(t *testing.T) {
+	server := newFakeRedis(t)
+	server.password = "secret"
+	ctx := context.Background()
+
+	client := NewRedisClient(RedisOptions{Addr: server.addr(), Password: "secret", DB: 2})
+	defer client.Close()
+
+	reply, err := client.Do(ctx, "SET", "greeting", "hello\r\nworld")
+	require.NoError(t, err)
+	assert.Equal(t, "OK", reply)
+
+	reply, err = client.Do(ctx, "GET", "greeting")
+	require.NoError(t, err)
+	assert.Equal(t, "hello\r\nworld", reply)
+
+	reply, err = client.Do(ctx, "GET", "missing")
+	require.NoError(t, err)
+	assert.Nil(t, reply)
+
+	reply, err = client.Do(ctx, "DEL", "greeting", "missing")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), reply)
+
+	_, err = client.Do(ctx, "NOPE")
+	var redisErr RedisError
+	assert.ErrorAs(t, err, &redisErr)
+
+	// The connection is still usable after an error reply.
+	_, err = client.Do(ctx, "PING")
+	assert.NoError(t, err)
+
+	bad := NewRedisClient(RedisOptions{Addr: server.addr(), Password: "wrong"})
+	_, err = bad.Do(ctx, "PING")
+	assert.ErrorContains(t, err, "WRONGPASS")
+}
+
+func TestRedisStore(t *testing.T) {
+	server := newFakeRedis(t)
+	client := NewRedisClient(RedisOptions{Addr: server.addr()})
+	store := NewRedisStore(client, "app:")
+
+	require.NoError(t, store.Put("user", map[string]any{"name": "Jane", "age": 30}, time.Minute))
+	value, err := store.Get("user")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "Jane", "age": float64(30)}, value)
+
+	value, ttl, err := store.GetWithTTL("user")
+	require.NoError(t, err)
+	assert.NotNil(t, value)
+	assert.InDelta(t, time.Minute, ttl, float64(time.Second))
+
+	require.NoError(t, store.Put("forever", "x", 0))
+	_, ttl, err = store.GetWithTTL("forever")
+	require.NoError(t, err)
+	assert.Zero(t, ttl)
+
+	require.NoError(t, store.Put("short", "x", time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+	value, err = store.Get("short")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	require.NoError(t, store.Forget("user"))
+	value, _ = store.Get("user")
+	assert.Nil(t, value)
+
+	// Flush only removes keys under the prefix.
+	_, err = client.Do(context.Background(), "SET", "other:key", "1")
+	require.NoError(t, err)
+	require.NoError(t, store.Flush())
+	value, _ = store.Get("forever")
+	assert.Nil(t, value)
+	reply, _ := client.Do(context.Background(), "GET", "other:key")
+	assert.Equal(t, "1", reply)
+}
+
+func TestRedisStoreIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	rc, cleanup := testutil.SetupRedisContainer(t)
+	defer cleanup()
+
+	client := NewRedisClient(RedisOptions{Addr: rc.Addr()})
+	defer client.Close()
+	store := NewRedisStore(client, "app:")
+
+	require.NoError(t, store.Put("user", map[string]any{"name": "Jane"}, time.Minute))
+	value, ttl, err := store.GetWithTTL("user")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "Jane"}, value)
+	assert.InDelta(t, time.Minute, ttl, float64(time.Second))
+
+	require.NoError(t, store.Flush())
+	value, err = store.Get("user")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
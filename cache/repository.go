@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Repository wraps a store with the operations applications use day to day:
+// remembering computed values, storing items forever, atomic adds and
+// counters. Stores that implement Adder or Incrementer perform Add and
+// Increment atomically; for other stores they are only atomic within the
+// repository.
+type Repository struct {
+	store Store
+	mu    sync.Mutex
+}
+
+// NewRepository wraps a store.
+func NewRepository(store Store) *Repository {
+	return &Repository{store: store}
+}
+
+// Store returns the underlying store.
+func (r *Repository) Store() Store {
+	return r.store
+}
+
+// Get retrieves an item, or nil if it is missing or expired.
+func (r *Repository) Get(key string) (any, error) {
+	return r.store.Get(key)
+}
+
+// Has reports whether an item exists.
+func (r *Repository) Has(key string) (bool, error) {
+	value, err := r.store.Get(key)
+	return value != nil, err
+}
+
+// Put stores an item for ttl. A ttl of zero stores it without expiry.
+func (r *Repository) Put(key string, value any, ttl time.Duration) error {
+	return r.store.Put(key, value, ttl)
+}
+
+// Forever stores an item without expiry.
+func (r *Repository) Forever(key string, value any) error {
+	return r.store.Put(key, value, 0)
+}
+
+// Add stores an item only if the key is missing, and reports whether it did.
+func (r *Repository) Add(key string, value any, ttl time.Duration) (bool, error) {
+	if adder, ok := r.store.(Adder); ok {
+		return adder.Add(key, value, ttl)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.store.Get(key)
+	if err != nil || existing != nil {
+		return false, err
+	}
+	return true, r.store.Put(key, value, ttl)
+}
+
+// Remember returns the cached item, or computes it with fn and caches it for
+// ttl. Errors from fn are returned and nothing is cached.
+func (r *Repository) Remember(key string, ttl time.Duration, fn func() (any, error)) (any, error) {
+	value, err := r.store.Get(key)
+	if err != nil || value != nil {
+		return value, err
+	}
+
+	value, err = fn()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.store.Put(key, value, ttl); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// RememberForever is Remember without expiry.
+func (r *Repository) RememberForever(key string, fn func() (any, error)) (any, error) {
+	return r.Remember(key, 0, fn)
+}
+
+// Pull retrieves an item and removes it.
+func (r *Repository) Pull(key string) (any, error) {
+	value, err := r.store.Get(key)
+	if err != nil || value == nil {
+		return value, err
+	}
+	return value, r.store.Forget(key)
+}
+
+// Increment adds by to an integer item, starting from zero if it is
+// missing, and returns the new value.
+func (r *Repository) Increment(key string, by ...int64) (int64, error) {
+	step := int64(1)
+	if len(by) > 0 {
+		step = by[0]
+	}
+
+	if incrementer, ok := r.store.(Incrementer); ok {
+		return incrementer.Increment(key, step)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var current int64
+	value, err := r.store.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	if value != nil {
+		if current, err = toInt64(key, value); err != nil {
+			return 0, err
+		}
+	}
+	return current + step, r.store.Put(key, current+step, 0)
+}
+
+// Decrement subtracts by from an integer item and returns the new value.
+func (r *Repository) Decrement(key string, by ...int64) (int64, error) {
+	step := int64(1)
+	if len(by) > 0 {
+		step = by[0]
+	}
+	return r.Increment(key, -step)
+}
+
+// Forget removes an item.
+func (r *Repository) Forget(key string) error {
+	return r.store.Forget(key)
+}
+
+// Flush removes all items from the store.
+func (r *Repository) Flush() error {
+	return r.store.Flush()
+}
+
+// toInt64 converts a cached counter to an integer. Stores that JSON encode
+// values return numbers as float64.
+func toInt64(key string, value any) (int64, error) {
+	switch n := value.(type) {
+	case int:
+		return int64(n), nil
+	case int32:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case float64:
+		if n == float64(int64(n)) {
+			return int64(n), nil
+		}
+	case json.Number:
+		return n.Int64()
+	case string:
+		if i, err := strconv.ParseInt(n, 10, 64); err == nil {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("cache: value for key %s is not an integer", key)
+}
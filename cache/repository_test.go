@@ -0,0 +1,192 @@
+package cache
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/genesysflow/go-genesys/database/schema"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "modernc.org/sqlite"
+)
+
+// plainStore hides a store's Adder and Incrementer implementations so the
+// repository's fallbacks are exercised.
+type plainStore struct{ Store }
+
+func testRepository(t *testing.T, store Store) {
+	t.Helper()
+	repository := NewRepository(store)
+	require.NoError(t, repository.Flush())
+
+	value, err := repository.Get("missing")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	require.NoError(t, repository.Forever("name", "Ada"))
+	has, err := repository.Has("name")
+	require.NoError(t, err)
+	assert.True(t, has)
+
+	calls := 0
+	compute := func() (any, error) { calls++; return "computed", nil }
+	for range 2 {
+		value, err = repository.Remember("remembered", time.Minute, compute)
+		require.NoError(t, err)
+		assert.Equal(t, "computed", value)
+	}
+	assert.Equal(t, 1, calls)
+
+	_, err = repository.Remember("failing", time.Minute, func() (any, error) { return nil, errors.New("boom") })
+	assert.EqualError(t, err, "boom")
+	has, _ = repository.Has("failing")
+	assert.False(t, has)
+
+	added, err := repository.Add("lock", "a", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, added)
+	added, err = repository.Add("lock", "b", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	require.NoError(t, repository.Put("expiring", "x", 5*time.Millisecond))
+	time.Sleep(10 * time.Millisecond)
+	added, err = repository.Add("expiring", "y", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, added, "expired keys can be added again")
+
+	n, err := repository.Increment("hits")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	n, err = repository.Increment("hits", 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), n)
+	n, err = repository.Decrement("hits", 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), n)
+
+	_, err = repository.Increment("name")
+	assert.Error(t, err)
+
+	value, err = repository.Pull("remembered")
+	require.NoError(t, err)
+	assert.Equal(t, "computed", value)
+	has, _ = repository.Has("remembered")
+	assert.False(t, has)
+
+	require.NoError(t, repository.Forget("name"))
+	has, _ = repository.Has("name")
+	assert.False(t, has)
+}
+
+func TestRepositoryStores(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		testRepository(t, NewMemoryStore())
+	})
+	t.Run("fallback", func(t *testing.T) {
+		testRepository(t, plainStore{NewMemoryStore()})
+	})
+	t.Run("file", func(t *testing.T) {
+		store, err := NewFileStore(t.TempDir())
+		require.NoError(t, err)
+		testRepository(t, store)
+	})
+	t.Run("redis", func(t *testing.T) {
+		server := newFakeRedis(t)
+		testRepository(t, NewRedisStore(NewRedisClient(RedisOptions{Addr: server.addr()}), "app:"))
+	})
+	t.Run("database", func(t *testing.T) {
+		testRepository(t, newTestDatabaseStore(t))
+	})
+}
+
+func TestRepositoryAddIsAtomic(t *testing.T) {
+	for name, store := range map[string]Store{
+		"memory":   NewMemoryStore(),
+		"fallback": plainStore{NewMemoryStore()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repository := NewRepository(store)
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			winners := 0
+			for range 10 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					added, err := repository.Add("once", true, time.Minute)
+					assert.NoError(t, err)
+					if added {
+						mu.Lock()
+						winners++
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+			assert.Equal(t, 1, winners)
+		})
+	}
+}
+
+func TestFileStoreFlush(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put("user", map[string]any{"age": 30}, 0))
+	value, err := store.Get("user")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"age": float64(30)}, value)
+
+	require.NoError(t, store.Flush())
+	entries, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+// newTestDatabaseStore creates a database store on the test database
+// testutil.DatabaseEnv selects. The store supports PostgreSQL and SQLite.
+func newTestDatabaseStore(t *testing.T) *DatabaseStore {
+	t.Helper()
+	td, cleanup := testutil.SetupDatabaseFor(t, "pgsql", "sqlite")
+	t.Cleanup(cleanup)
+	manager := database.NewManager(database.Config{
+		Default: "default",
+		Connections: map[string]database.ConnectionConfig{
+			"default": {
+				Driver:   td.Driver,
+				Host:     td.Host,
+				Port:     td.Port,
+				Database: td.Database,
+				Username: td.Username,
+				Password: td.Password,
+				SSLMode:  "disable",
+			},
+		},
+	})
+	t.Cleanup(func() { manager.Close() })
+
+	conn := manager.Connection()
+	require.NoError(t, conn.Error())
+	builder := schema.NewBuilder(conn.DB(), conn.Driver())
+	require.NoError(t, builder.Create("cache", func(table *schema.Blueprint) {
+		table.String("key", 255).Unique()
+		table.Text("value")
+		table.BigInteger("expiration")
+	}))
+	require.NoError(t, builder.Create("cache_locks", func(table *schema.Blueprint) {
+		table.String("key", 255).Unique()
+		table.String("owner", 255)
+		table.BigInteger("expiration")
+	}))
+
+	return NewDatabaseStore(conn, "")
+}
@@ -7,7 +7,7 @@ type Store interface {
 	// Get retrieves an item from the cache.
 	Get(key string) (any, error)
 
-	// Put stores an item in the cache.
+	// Put stores an item in the cache. A ttl of zero stores it without expiry.
 	Put(key string, value any, ttl time.Duration) error
 
 	// Forget removes an item from the cache.
@@ -16,3 +16,18 @@ type Store interface {
 	// Flush removes all items from the cache.
 	Flush() error
 }
+
+// Adder is implemented by stores that can atomically store an item only if
+// the key is missing. Repository.Add falls back to Get and Put otherwise.
+type Adder interface {
+	// Add stores an item if the key is missing and reports whether it did.
+	Add(key string, value any, ttl time.Duration) (bool, error)
+}
+
+// Incrementer is implemented by stores that can atomically increment an
+// integer item. Repository.Increment falls back to Get and Put otherwise.
+type Incrementer interface {
+	// Increment adds by to the item, starting from zero if it is missing,
+	// and returns the new value. Missing items are stored without expiry.
+	Increment(key string, by int64) (int64, error)
+}
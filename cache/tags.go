@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTagsNotSupported is returned by Repository.Tags for stores that do not
+// implement Taggable.
+var ErrTagsNotSupported = errors.New("cache: store does not support tags")
+
+// Taggable is implemented by stores that can group items under tags, so a
+// group can be flushed without flushing the whole store. The memory and
+// Redis stores implement it.
+type Taggable interface {
+	// Tag records that key belongs to each of the tags.
+	Tag(key string, tags ...string) error
+
+	// FlushTags removes every item recorded under any of the tags.
+	FlushTags(tags ...string) error
+}
+
+// TaggedCache is a Repository whose writes are recorded under tags. Items
+// are read by key as usual; Flush removes only the items written through a
+// TaggedCache sharing one of its tags.
+type TaggedCache struct {
+	*Repository
+	tags []string
+}
+
+// Tags returns a TaggedCache for the given tags.
+func (r *Repository) Tags(names ...string) (*TaggedCache, error) {
+	taggable, ok := r.store.(Taggable)
+	if !ok {
+		return nil, ErrTagsNotSupported
+	}
+
+	store := &taggedStore{repository: r, taggable: taggable, tags: names}
+	tagged := &TaggedCache{Repository: NewRepository(store), tags: names}
+	return tagged, nil
+}
+
+// GetTags returns the cache's tags.
+func (c *TaggedCache) GetTags() []string {
+	return c.tags
+}
+
+// taggedStore tags every key written through it. Add and Increment go
+// through the parent repository so they stay atomic where the store is.
+type taggedStore struct {
+	repository *Repository
+	taggable   Taggable
+	tags       []string
+}
+
+func (s *taggedStore) Get(key string) (any, error) {
+	return s.repository.store.Get(key)
+}
+
+func (s *taggedStore) Put(key string, value any, ttl time.Duration) error {
+	if err := s.repository.store.Put(key, value, ttl); err != nil {
+		return err
+	}
+	return s.taggable.Tag(key, s.tags...)
+}
+
+func (s *taggedStore) Add(key string, value any, ttl time.Duration) (bool, error) {
+	added, err := s.repository.Add(key, value, ttl)
+	if err != nil || !added {
+		return added, err
+	}
+	return true, s.taggable.Tag(key, s.tags...)
+}
+
+func (s *taggedStore) Increment(key string, by int64) (int64, error) {
+	n, err := s.repository.Increment(key, by)
+	if err != nil {
+		return 0, err
+	}
+	return n, s.taggable.Tag(key, s.tags...)
+}
+
+func (s *taggedStore) Forget(key string) error {
+	return s.repository.store.Forget(key)
+}
+
+// Flush removes the items recorded under the tags.
+func (s *taggedStore) Flush() error {
+	return s.taggable.FlushTags(s.tags...)
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTags(t *testing.T, store Store) {
+	t.Helper()
+	repository := NewRepository(store)
+
+	users, err := repository.Tags("users")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"users"}, users.GetTags())
+	both, err := repository.Tags("users", "posts")
+	require.NoError(t, err)
+	posts, err := repository.Tags("posts")
+	require.NoError(t, err)
+
+	require.NoError(t, users.Put("user:1", "Ada", time.Minute))
+	_, err = users.Remember("user:2", time.Minute, func() (any, error) { return "Grace", nil })
+	require.NoError(t, err)
+	_, err = both.Increment("user:1:posts")
+	require.NoError(t, err)
+	require.NoError(t, posts.Forever("post:1", "Hello"))
+	require.NoError(t, repository.Put("settings", "dark", time.Minute))
+
+	// Tagged items are read by key.
+	value, err := repository.Get("user:1")
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", value)
+
+	require.NoError(t, users.Flush())
+	for _, key := range []string{"user:1", "user:2", "user:1:posts"} {
+		has, err := repository.Has(key)
+		require.NoError(t, err)
+		assert.False(t, has, key)
+	}
+	for _, key := range []string{"post:1", "settings"} {
+		has, err := repository.Has(key)
+		require.NoError(t, err)
+		assert.True(t, has, key)
+	}
+}
+
+func TestTaggedCache(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		testTags(t, NewMemoryStore())
+	})
+	t.Run("redis", func(t *testing.T) {
+		server := newFakeRedis(t)
+		testTags(t, NewRedisStore(NewRedisClient(RedisOptions{Addr: server.addr()}), "app:"))
+	})
+}
+
+func TestTagsNotSupported(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = NewRepository(store).Tags("users")
+	assert.ErrorIs(t, err, ErrTagsNotSupported)
+}
@@ -0,0 +1,317 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+// InvalidationBus broadcasts cache invalidations between processes.
+// RedisClient implements it with Redis pub/sub.
+type InvalidationBus interface {
+	Publish(ctx context.Context, channel, message string) error
+
+	// SubscribeReady calls ready once the subscription is active, then
+	// handler for each message until ctx is cancelled or the subscription
+	// fails.
+	SubscribeReady(ctx context.Context, channel string, ready func(), handler func(message string)) error
+}
+
+// TieredOptions configures a TieredStore.
+type TieredOptions struct {
+	// LocalSize is the number of items kept in process. Defaults to 1000.
+	LocalSize int
+
+	// LocalTTL caps how long an item is served from the process before it
+	// is read from the remote store again. Defaults to one minute.
+	LocalTTL time.Duration
+
+	// Channel is the pub/sub channel for invalidations. Defaults to "cache:invalidate".
+	Channel string
+}
+
+// TieredStats reports how often reads were served from the local tier.
+type TieredStats struct {
+	LocalHits   uint64
+	LocalMisses uint64
+}
+
+type invalidation struct {
+	Origin string `json:"origin"`
+	Key    string `json:"key,omitempty"`
+	Flush  bool   `json:"flush,omitempty"`
+}
+
+// TieredStore reads through a small in-process LRU in front of a shared
+// remote store such as Redis. Writes go to both tiers and are broadcast so
+// other processes drop their local copies. Add, Increment, locks and tags
+// go to the remote store; Add, Increment and FlushTags invalidate the
+// local copies they affect.
+type TieredStore struct {
+	local      *LRUStore
+	remote     Store
+	repository *Repository
+	bus        InvalidationBus
+	options    TieredOptions
+	origin     string
+
+	// version changes on every received invalidation, so a read that raced
+	// with one doesn't repopulate the local tier with a stale value.
+	version    atomic.Uint64
+	subscribed atomic.Bool
+	hits       atomic.Uint64
+	misses     atomic.Uint64
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewTieredStore creates a two-tier store and starts listening for
+// invalidations. Call Close to stop listening.
+func NewTieredStore(remote Store, bus InvalidationBus, options TieredOptions) *TieredStore {
+	if options.LocalTTL <= 0 {
+		options.LocalTTL = time.Minute
+	}
+	if options.Channel == "" {
+		options.Channel = "cache:invalidate"
+	}
+
+	b := make([]byte, 8)
+	rand.Read(b)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &TieredStore{
+		local:      NewLRUStore(options.LocalSize),
+		remote:     remote,
+		repository: NewRepository(remote),
+		bus:        bus,
+		options:    options,
+		origin:     hex.EncodeToString(b),
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+	go s.listen(ctx)
+	return s
+}
+
+// listen subscribes to invalidations, resubscribing with backoff. The local
+// tier is bypassed until the bus confirms the subscription and cleared on
+// every (re)subscribe, since invalidations may have been missed.
+func (s *TieredStore) listen(ctx context.Context) {
+	defer close(s.done)
+
+	backoff := 100 * time.Millisecond
+	for {
+		err := s.bus.SubscribeReady(ctx, s.options.Channel, func() {
+			s.local.Flush()
+			s.version.Add(1)
+			s.subscribed.Store(true)
+		}, s.handle)
+		s.subscribed.Store(false)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			backoff = 100 * time.Millisecond
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 5*time.Second)
+	}
+}
+
+// handle applies an invalidation from another process.
+func (s *TieredStore) handle(message string) {
+	var msg invalidation
+	if err := json.Unmarshal([]byte(message), &msg); err != nil || msg.Origin == s.origin {
+		return
+	}
+
+	s.version.Add(1)
+	if msg.Flush {
+		s.local.Flush()
+		return
+	}
+	s.local.Forget(msg.Key)
+}
+
+// Get retrieves an item, from the local tier when possible.
+func (s *TieredStore) Get(key string) (any, error) {
+	useLocal := s.subscribed.Load()
+	if useLocal {
+		if value, _ := s.local.Get(key); value != nil {
+			s.hits.Add(1)
+			return value, nil
+		}
+	}
+	s.misses.Add(1)
+
+	version := s.version.Load()
+	value, ttl, err := s.getRemote(key)
+	if err != nil || value == nil {
+		return value, err
+	}
+
+	if useLocal && s.version.Load() == version {
+		s.local.Put(key, value, s.localTTL(ttl))
+	}
+	return value, nil
+}
+
+// getRemote reads from the remote store, with the remaining TTL if the store reports it.
+func (s *TieredStore) getRemote(key string) (any, time.Duration, error) {
+	if store, ok := s.remote.(interface {
+		GetWithTTL(key string) (any, time.Duration, error)
+	}); ok {
+		return store.GetWithTTL(key)
+	}
+	value, err := s.remote.Get(key)
+	return value, 0, err
+}
+
+// localTTL caps a remote TTL at the configured local TTL. Zero means no expiry.
+func (s *TieredStore) localTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 || ttl > s.options.LocalTTL {
+		return s.options.LocalTTL
+	}
+	return ttl
+}
+
+// Put stores an item in both tiers and invalidates other processes' copies.
+func (s *TieredStore) Put(key string, value any, ttl time.Duration) error {
+	if err := s.remote.Put(key, value, ttl); err != nil {
+		s.local.Forget(key)
+		return err
+	}
+	s.local.Put(key, value, s.localTTL(ttl))
+	return s.publish(invalidation{Key: key})
+}
+
+// Forget removes an item from both tiers and from other processes.
+func (s *TieredStore) Forget(key string) error {
+	s.local.Forget(key)
+	if err := s.remote.Forget(key); err != nil {
+		return err
+	}
+	return s.publish(invalidation{Key: key})
+}
+
+// Flush removes all items from both tiers and from other processes.
+func (s *TieredStore) Flush() error {
+	s.local.Flush()
+	if err := s.remote.Flush(); err != nil {
+		return err
+	}
+	return s.publish(invalidation{Flush: true})
+}
+
+// Add stores an item in the remote store if the key is missing, and
+// invalidates local copies when it did.
+func (s *TieredStore) Add(key string, value any, ttl time.Duration) (bool, error) {
+	added, err := s.repository.Add(key, value, ttl)
+	if err != nil || !added {
+		return added, err
+	}
+	s.forgetLocal(key)
+	return true, s.publish(invalidation{Key: key})
+}
+
+// Increment increments an item in the remote store and invalidates local copies.
+func (s *TieredStore) Increment(key string, by int64) (int64, error) {
+	n, err := s.repository.Increment(key, by)
+	if err != nil {
+		return 0, err
+	}
+	s.forgetLocal(key)
+	return n, s.publish(invalidation{Key: key})
+}
+
+// Tag records that key belongs to each of the tags in the remote store.
+func (s *TieredStore) Tag(key string, tags ...string) error {
+	taggable, ok := s.remote.(Taggable)
+	if !ok {
+		return ErrTagsNotSupported
+	}
+	return taggable.Tag(key, tags...)
+}
+
+// FlushTags removes the tagged items from the remote store. The local tier
+// doesn't know which keys were tagged, so it is cleared here and in other
+// processes.
+func (s *TieredStore) FlushTags(tags ...string) error {
+	taggable, ok := s.remote.(Taggable)
+	if !ok {
+		return ErrTagsNotSupported
+	}
+	if err := taggable.FlushTags(tags...); err != nil {
+		return err
+	}
+	s.version.Add(1)
+	s.local.Flush()
+	return s.publish(invalidation{Flush: true})
+}
+
+// AcquireLock takes the lock in the remote store for owner.
+func (s *TieredStore) AcquireLock(name, owner string, ttl time.Duration) (bool, error) {
+	locker, ok := s.remote.(Locker)
+	if !ok {
+		return false, ErrLocksNotSupported
+	}
+	return locker.AcquireLock(name, owner, ttl)
+}
+
+// ReleaseLock frees the lock in the remote store if owner holds it.
+func (s *TieredStore) ReleaseLock(name, owner string) (bool, error) {
+	locker, ok := s.remote.(Locker)
+	if !ok {
+		return false, ErrLocksNotSupported
+	}
+	return locker.ReleaseLock(name, owner)
+}
+
+// ForceReleaseLock frees the lock in the remote store whoever holds it.
+func (s *TieredStore) ForceReleaseLock(name string) error {
+	locker, ok := s.remote.(Locker)
+	if !ok {
+		return ErrLocksNotSupported
+	}
+	return locker.ForceReleaseLock(name)
+}
+
+// forgetLocal drops a local copy, so a concurrent read doesn't put back the
+// value it read before the change.
+func (s *TieredStore) forgetLocal(key string) {
+	s.version.Add(1)
+	s.local.Forget(key)
+}
+
+// Stats returns local tier hit and miss counts.
+func (s *TieredStore) Stats() TieredStats {
+	return TieredStats{
+		LocalHits:   s.hits.Load(),
+		LocalMisses: s.misses.Load(),
+	}
+}
+
+// Close stops listening for invalidations.
+func (s *TieredStore) Close() error {
+	s.cancel()
+	<-s.done
+	return nil
+}
+
+func (s *TieredStore) publish(msg invalidation) error {
+	msg.Origin = s.origin
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.bus.Publish(context.Background(), s.options.Channel, string(payload))
+}
@@ -0,0 +1,236 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBus is an in-process InvalidationBus.
+type memoryBus struct {
+	handlers map[string][]func(string)
+	failing  bool
+	mu       sync.Mutex
+}
+
+func newMemoryBus() *memoryBus {
+	return &memoryBus{handlers: make(map[string][]func(string))}
+}
+
+func (b *memoryBus) Publish(ctx context.Context, channel, message string) error {
+	b.mu.Lock()
+	handlers := append([]func(string){}, b.handlers[channel]...)
+	failing := b.failing
+	b.mu.Unlock()
+
+	if failing {
+		return errors.New("bus unavailable")
+	}
+	for _, handler := range handlers {
+		handler(message)
+	}
+	return nil
+}
+
+func (b *memoryBus) SubscribeReady(ctx context.Context, channel string, ready func(), handler func(string)) error {
+	b.mu.Lock()
+	b.handlers[channel] = append(b.handlers[channel], handler)
+	b.mu.Unlock()
+
+	ready()
+	<-ctx.Done()
+	return nil
+}
+
+func (b *memoryBus) subscribers(channel string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.handlers[channel])
+}
+
+// countingStore counts reads that reach the remote tier.
+type countingStore struct {
+	*MemoryStore
+	gets int
+	mu   sync.Mutex
+}
+
+func (s *countingStore) Get(key string) (any, error) {
+	s.mu.Lock()
+	s.gets++
+	s.mu.Unlock()
+	return s.MemoryStore.Get(key)
+}
+
+func (s *countingStore) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.gets
+}
+
+func newTiered(t *testing.T, remote Store, bus *memoryBus) *TieredStore {
+	t.Helper()
+	store := NewTieredStore(remote, bus, TieredOptions{LocalSize: 100})
+	t.Cleanup(func() { store.Close() })
+	require.Eventually(t, func() bool { return store.subscribed.Load() }, time.Second, time.Millisecond)
+	return store
+}
+
+func TestTieredStoreServesHotKeysLocally(t *testing.T) {
+	remote := &countingStore{MemoryStore: NewMemoryStore()}
+	remote.Put("config", "v1", time.Minute)
+	store := newTiered(t, remote, newMemoryBus())
+
+	for i := 0; i < 5; i++ {
+		value, err := store.Get("config")
+		require.NoError(t, err)
+		assert.Equal(t, "v1", value)
+	}
+
+	assert.Equal(t, 1, remote.count())
+	assert.Equal(t, TieredStats{LocalHits: 4, LocalMisses: 1}, store.Stats())
+
+	// Misses are not cached locally.
+	store.Get("missing")
+	store.Get("missing")
+	assert.Equal(t, 3, remote.count())
+}
+
+func TestTieredStoreInvalidatesOtherProcesses(t *testing.T) {
+	bus := newMemoryBus()
+	remote := NewMemoryStore()
+	a := newTiered(t, remote, bus)
+	b := newTiered(t, remote, bus)
+	require.Eventually(t, func() bool { return bus.subscribers("cache:invalidate") == 2 }, time.Second, time.Millisecond)
+
+	require.NoError(t, a.Put("price", 10, time.Minute))
+	value, _ := b.Get("price")
+	assert.Equal(t, 10, value)
+
+	require.NoError(t, a.Put("price", 12, time.Minute))
+	value, _ = b.Get("price")
+	assert.Equal(t, 12, value, "b should drop its local copy when a writes")
+
+	require.NoError(t, a.Forget("price"))
+	value, _ = b.Get("price")
+	assert.Nil(t, value)
+
+	b.Put("x", 1, time.Minute)
+	b.Put("y", 2, time.Minute)
+	a.Get("x")
+	require.NoError(t, b.Flush())
+	value, _ = a.Get("x")
+	assert.Nil(t, value)
+}
+
+func TestTieredStoreReturnsPublishErrors(t *testing.T) {
+	bus := newMemoryBus()
+	store := newTiered(t, NewMemoryStore(), bus)
+
+	bus.mu.Lock()
+	bus.failing = true
+	bus.mu.Unlock()
+
+	assert.Error(t, store.Put("key", "value", time.Minute))
+}
+
+func TestTieredStoreOverRedis(t *testing.T) {
+	server := newFakeRedis(t)
+	client := NewRedisClient(RedisOptions{Addr: server.addr()})
+
+	a := NewTieredStore(NewRedisStore(client, "app:"), client, TieredOptions{})
+	defer a.Close()
+	b := NewTieredStore(NewRedisStore(client, "app:"), client, TieredOptions{})
+	defer b.Close()
+	require.Eventually(t, func() bool { return server.subscriberCount("cache:invalidate") == 2 }, time.Second, time.Millisecond)
+
+	require.NoError(t, a.Put("flag", true, time.Minute))
+	value, err := b.Get("flag")
+	require.NoError(t, err)
+	assert.Equal(t, true, value)
+
+	require.NoError(t, a.Put("flag", false, time.Minute))
+	require.Eventually(t, func() bool {
+		value, _ := b.Get("flag")
+		return value == false
+	}, time.Second, time.Millisecond)
+}
+
+// pendingBus never confirms its subscriptions.
+type pendingBus struct{ *memoryBus }
+
+func (b *pendingBus) SubscribeReady(ctx context.Context, channel string, ready func(), handler func(string)) error {
+	return b.memoryBus.SubscribeReady(ctx, channel, func() {}, handler)
+}
+
+func TestTieredStoreBypassesLocalUntilSubscribed(t *testing.T) {
+	bus := &pendingBus{memoryBus: newMemoryBus()}
+	remote := &countingStore{MemoryStore: NewMemoryStore()}
+	remote.Put("config", "v1", time.Minute)
+	store := NewTieredStore(remote, bus, TieredOptions{LocalSize: 100})
+	t.Cleanup(func() { store.Close() })
+	require.Eventually(t, func() bool { return bus.subscribers("cache:invalidate") == 1 }, time.Second, time.Millisecond)
+
+	store.Get("config")
+	store.Get("config")
+	assert.Equal(t, 2, remote.count())
+	assert.Equal(t, TieredStats{LocalMisses: 2}, store.Stats())
+}
+
+func TestTieredStoreIncrementAndTagsInvalidateOtherProcesses(t *testing.T) {
+	bus := newMemoryBus()
+	remote := NewMemoryStore()
+	a := newTiered(t, remote, bus)
+	b := newTiered(t, remote, bus)
+	require.Eventually(t, func() bool { return bus.subscribers("cache:invalidate") == 2 }, time.Second, time.Millisecond)
+
+	repository := NewRepository(a)
+	n, err := repository.Increment("visits", 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	value, _ := b.Get("visits")
+	assert.Equal(t, int64(2), value)
+	_, err = repository.Increment("visits")
+	require.NoError(t, err)
+	value, _ = b.Get("visits")
+	assert.Equal(t, int64(3), value, "b should drop its local copy when a increments")
+
+	added, err := repository.Add("visits", 10, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	users, err := NewRepository(b).Tags("users")
+	require.NoError(t, err)
+	require.NoError(t, users.Put("jane", "jane@example.com", time.Minute))
+	value, _ = a.Get("jane")
+	assert.Equal(t, "jane@example.com", value)
+	require.NoError(t, users.Flush())
+	value, _ = a.Get("jane")
+	assert.Nil(t, value)
+	value, _ = b.Get("jane")
+	assert.Nil(t, value)
+}
+
+func TestTieredStoreLocks(t *testing.T) {
+	bus := newMemoryBus()
+	remote := NewMemoryStore()
+	a := NewRepository(newTiered(t, remote, bus))
+	b := NewRepository(newTiered(t, remote, bus))
+
+	lock, err := a.Lock("reports", time.Minute)
+	require.NoError(t, err)
+	acquired, err := lock.Acquire()
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	other, err := b.Lock("reports", time.Minute)
+	require.NoError(t, err)
+	acquired, err = other.Acquire()
+	require.NoError(t, err)
+	assert.False(t, acquired)
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// WarmEntry describes a key kept warm by Manager.Warm.
+type WarmEntry struct {
+	// Key is the cache key to refresh.
+	Key string
+
+	// Store is the store name. Empty uses the default store.
+	Store string
+
+	// TTL is how long the refreshed value is cached, or how long it stays
+	// fresh when Stale is set.
+	TTL time.Duration
+
+	// Stale, when set, writes the value in the layout read by Flexible, so
+	// it may be served stale for this long after TTL.
+	Stale time.Duration
+
+	// Resolve computes the value.
+	Resolve func() (any, error)
+}
+
+// Warmable registers a key to be refreshed by Warm, replacing any entry
+// with the same store and key.
+func (m *Manager) Warmable(entry WarmEntry) error {
+	if entry.Key == "" {
+		return fmt.Errorf("cache warm entry requires a key")
+	}
+	if entry.Resolve == nil {
+		return fmt.Errorf("cache warm entry [%s] requires a resolver", entry.Key)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.warm[entry.Store+"\x00"+entry.Key] = entry
+	return nil
+}
+
+// WarmEntries returns the registered warm entries, sorted by store and key.
+func (m *Manager) WarmEntries() []WarmEntry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entries := make([]WarmEntry, 0, len(m.warm))
+	for _, entry := range m.warm {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Store != entries[j].Store {
+			return entries[i].Store < entries[j].Store
+		}
+		return entries[i].Key < entries[j].Key
+	})
+	return entries
+}
+
+// Warm recomputes registered keys ahead of expiry. With keys, only entries
+// with those keys are refreshed. A failing entry doesn't stop the others;
+// their errors are joined.
+func (m *Manager) Warm(keys ...string) error {
+	only := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		only[key] = true
+	}
+
+	var errs []error
+	for _, entry := range m.WarmEntries() {
+		if len(only) > 0 && !only[entry.Key] {
+			continue
+		}
+		if err := m.warmEntry(entry); err != nil {
+			errs = append(errs, fmt.Errorf("cache warm [%s]: %w", entry.Key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Manager) warmEntry(entry WarmEntry) error {
+	store, err := m.Store(entry.Store)
+	if err != nil {
+		return err
+	}
+	value, err := entry.Resolve()
+	if err != nil {
+		return err
+	}
+	if entry.Stale > 0 {
+		return writeFlexible(store, entry.Key, value, entry.TTL+entry.Stale)
+	}
+	return store.Put(entry.Key, value, entry.TTL)
+}
@@ -0,0 +1,458 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/config"
+	"github.com/genesysflow/go-genesys/crypt"
+	"github.com/genesysflow/go-genesys/env"
+	"github.com/genesysflow/go-genesys/foundation"
+	"github.com/spf13/cobra"
+)
+
+const modulePath = "github.com/genesysflow/go-genesys"
+
+// deprecatedKeys maps config keys the framework no longer reads to their
+// replacements.
+var deprecatedKeys = map[string]string{
+	"app.cipher":          "app.key (AES-256-GCM is always used)",
+	"auth.default":        "auth.defaults.guard",
+	"database.connection": "database.default",
+	"logging.channel":     "logging.default",
+	"mail.driver":         "mail.default",
+	"session.expiration":  "session.lifetime (in minutes)",
+	"session.storage":     "session.driver",
+}
+
+// DoctorCmd creates the 'doctor' command.
+func DoctorCmd() *cobra.Command {
+	var path, app string
+
+	cmd := &cobra.Command{
+		Use:          "doctor",
+		SilenceUsage: true,
+		Short:        "Check a Go-Genesys project for common problems",
+		Long: `Check the project in the current directory for framework version
+drift, deprecated or inconsistent configuration, a missing APP_KEY and
+unwritable storage paths, and print how to fix each problem.
+
+The command exits with an error when it finds problems, so it can run in CI.
+In a Go workspace with several apps, choose one with --app.
+
+Example:
+  genesys doctor
+  genesys doctor --path ./myapp
+  genesys doctor --app api`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := filepath.Abs(path)
+			if err != nil {
+				return err
+			}
+			if dir, err = resolveApp(dir, app); err != nil {
+				return err
+			}
+			d, err := newDoctor(dir)
+			if err != nil {
+				return err
+			}
+			d.run()
+			return d.report(cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVarP(&path, "path", "p", ".", "Project directory")
+	cmd.Flags().StringVar(&app, "app", "", "App to check in a Go workspace")
+	return cmd
+}
+
+// Severities of doctor findings.
+const (
+	doctorOK    = "ok"
+	doctorWarn  = "warn"
+	doctorError = "error"
+)
+
+type doctorFinding struct {
+	severity string
+	message  string
+	fix      string
+}
+
+// doctor inspects a project without starting it.
+type doctor struct {
+	dir      string
+	version  string
+	config   *config.Config
+	findings []doctorFinding
+}
+
+func newDoctor(dir string) (*doctor, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(abs, "go.mod")); err != nil {
+		return nil, fmt.Errorf("go.mod not found in %s. Are you in a Go-Genesys project directory?", abs)
+	}
+
+	// Load the environment the same way the application does, so ${VAR}
+	// references in config resolve to what the app would see.
+	env.LoadIfExists(filepath.Join(abs, ".env"))
+	env.LoadIfExists(filepath.Join(abs, ".env."+env.Get("APP_ENV", "local")))
+
+	cfg := config.New()
+	if configDir := filepath.Join(abs, "config"); dirExists(configDir) {
+		if err := cfg.Load(configDir); err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+	}
+
+	return &doctor{dir: abs, version: foundation.Version, config: cfg}, nil
+}
+
+func (d *doctor) ok(message string) {
+	d.findings = append(d.findings, doctorFinding{severity: doctorOK, message: message})
+}
+
+func (d *doctor) warn(message, fix string) {
+	d.findings = append(d.findings, doctorFinding{severity: doctorWarn, message: message, fix: fix})
+}
+
+func (d *doctor) fail(message, fix string) {
+	d.findings = append(d.findings, doctorFinding{severity: doctorError, message: message, fix: fix})
+}
+
+func (d *doctor) run() {
+	d.checkVersion()
+	d.checkAppKey()
+	d.checkDeprecatedKeys()
+	d.checkProviders()
+	d.checkStoragePaths()
+}
+
+// report prints the findings and returns an error if any are errors.
+func (d *doctor) report(out io.Writer) error {
+	var warnings, errs int
+	for _, f := range d.findings {
+		switch f.severity {
+		case doctorOK:
+			fmt.Fprintf(out, "✓ %s\n", f.message)
+		case doctorWarn:
+			warnings++
+			fmt.Fprintf(out, "! %s\n", f.message)
+		case doctorError:
+			errs++
+			fmt.Fprintf(out, "✗ %s\n", f.message)
+		}
+		if f.fix != "" {
+			fmt.Fprintf(out, "  → %s\n", f.fix)
+		}
+	}
+
+	fmt.Fprintf(out, "\n%d error(s), %d warning(s)\n", errs, warnings)
+	if errs > 0 {
+		return fmt.Errorf("doctor found %d problem(s)", errs)
+	}
+	return nil
+}
+
+// checkVersion compares the framework version in go.mod with the CLI's.
+func (d *doctor) checkVersion() {
+	content, err := os.ReadFile(filepath.Join(d.dir, "go.mod"))
+	if err != nil {
+		d.fail("go.mod could not be read: "+err.Error(), "")
+		return
+	}
+
+	required, replaced := frameworkRequirement(content)
+	switch {
+	case required == "":
+		d.fail("go.mod does not require "+modulePath, "run `go get "+modulePath+"@v"+d.version+"`")
+	case replaced:
+		d.warn(fmt.Sprintf("go.mod replaces %s, so the version in use is not %s", modulePath, required),
+			"remove the replace directive before deploying")
+	case compareVersions(required, d.version) < 0:
+		d.warn(fmt.Sprintf("project uses go-genesys %s, the CLI is %s", required, d.version),
+			"run `genesys upgrade`")
+	case compareVersions(required, d.version) > 0:
+		d.warn(fmt.Sprintf("project uses go-genesys %s, newer than the CLI (%s)", required, d.version),
+			"run `genesys self-update`")
+	default:
+		d.ok("go-genesys " + required + " matches the CLI")
+	}
+}
+
+// frameworkRequirement returns the framework version go.mod requires and
+// whether a replace directive overrides it.
+func frameworkRequirement(gomod []byte) (version string, replaced bool) {
+	block := ""
+	scanner := bufio.NewScanner(strings.NewReader(string(gomod)))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		directive := block
+		switch {
+		case fields[0] == ")":
+			block = ""
+			continue
+		case len(fields) == 2 && fields[1] == "(":
+			block = fields[0]
+			continue
+		case fields[0] == "require" || fields[0] == "replace":
+			directive, fields = fields[0], fields[1:]
+		}
+		if len(fields) < 2 || fields[0] != modulePath {
+			continue
+		}
+
+		switch directive {
+		case "require":
+			version = fields[1]
+		case "replace":
+			replaced = true
+		}
+	}
+	return version, replaced
+}
+
+// checkAppKey verifies app.key is set and usable for encryption.
+func (d *doctor) checkAppKey() {
+	key := d.config.GetString("app.key")
+	if key == "" {
+		fix := "run `genesys key:generate` and add `key: ${APP_KEY}` to config/app.yaml"
+		if generated, err := crypt.GenerateKey(); err == nil {
+			fix += ", or set APP_KEY=" + generated + " yourself"
+		}
+		d.fail("app.key is not set; encryption, encrypted cookies and the cookie session driver are unavailable", fix)
+		return
+	}
+
+	raw, err := crypt.ParseKey(key)
+	if err == nil {
+		_, err = crypt.NewEncrypter(raw)
+	}
+	if err != nil {
+		d.fail("app.key is invalid: "+err.Error(), fmt.Sprintf("use a %d byte key in \"base64:\" form", crypt.KeySize))
+		return
+	}
+	d.ok("app.key is set")
+}
+
+func (d *doctor) checkDeprecatedKeys() {
+	found := false
+	for _, key := range sortedKeys(deprecatedKeys) {
+		if d.config.Has(key) {
+			found = true
+			d.warn(fmt.Sprintf("config key %s is deprecated and ignored", key), "use "+deprecatedKeys[key])
+		}
+	}
+	if !found {
+		d.ok("no deprecated config keys")
+	}
+}
+
+// checkProviders catches configuration the service providers would reject
+// or silently ignore at startup.
+func (d *doctor) checkProviders() {
+	before := len(d.findings)
+	cfg := d.config
+
+	if connections := cfg.GetMap("database.connections"); len(connections) > 0 {
+		if name := cfg.GetString("database.default"); name != "" && connections[name] == nil {
+			d.fail(fmt.Sprintf("database.default is %q, which is not in database.connections", name),
+				"set database.default to one of: "+strings.Join(sortedKeys(connections), ", "))
+		}
+	}
+
+	switch driver := cfg.GetString("session.driver"); driver {
+	case "", "memory", "file", "database", "redis":
+	case "cookie":
+		if cfg.GetString("app.key") == "" {
+			d.fail("session.driver is cookie but app.key is not set", "set APP_KEY, or use another session driver")
+		}
+	default:
+		d.fail(fmt.Sprintf("session.driver %q is not supported", driver), "use memory, file, database, redis or cookie")
+	}
+
+	switch channel := cfg.GetString("logging.default"); channel {
+	case "", "console", "file", "json":
+	default:
+		d.warn(fmt.Sprintf("logging.default %q is unknown, so logs go to the console", channel), "use console, file or json")
+	}
+
+	switch driver := cfg.GetString("mail.default"); driver {
+	case "", "log", "array":
+	default:
+		d.fail(fmt.Sprintf("mail.default %q is not supported", driver), "use log or array")
+	}
+
+	for _, name := range sortedKeys(cfg.GetMap("cache.stores")) {
+		settings, _ := cfg.GetMap("cache.stores")[name].(map[string]any)
+		switch driver, _ := settings["driver"].(string); driver {
+		case "", "memory", "redis", "tiered":
+		default:
+			d.fail(fmt.Sprintf("cache store %s uses unsupported driver %q", name, driver), "use memory, redis or tiered")
+		}
+	}
+
+	if disks := cfg.GetMap("filesystem.disks"); len(disks) > 0 {
+		if name := cfg.GetString("filesystem.default"); name != "" && disks[name] == nil {
+			d.fail(fmt.Sprintf("filesystem.default is %q, which is not in filesystem.disks", name),
+				"set filesystem.default to one of: "+strings.Join(sortedKeys(disks), ", "))
+		}
+	}
+
+	d.checkAuth()
+
+	if len(d.findings) == before {
+		d.ok("provider configuration is consistent")
+	}
+}
+
+func (d *doctor) checkAuth() {
+	cfg := d.config
+	guards := cfg.GetMap("auth.guards")
+	if len(guards) == 0 {
+		return
+	}
+
+	if name := cfg.GetString("auth.defaults.guard"); name != "" && guards[name] == nil {
+		d.fail(fmt.Sprintf("auth.defaults.guard is %q, which is not in auth.guards", name),
+			"set auth.defaults.guard to one of: "+strings.Join(sortedKeys(guards), ", "))
+	}
+
+	providers := cfg.GetMap("auth.providers")
+	for _, name := range sortedKeys(guards) {
+		settings, _ := guards[name].(map[string]any)
+		driver, _ := settings["driver"].(string)
+		provider, _ := settings["provider"].(string)
+
+		if len(providers) > 0 && providers[provider] == nil {
+			d.fail(fmt.Sprintf("auth guard %s uses user provider %q, which is not in auth.providers", name, provider),
+				"add it to auth.providers or change the guard's provider")
+		}
+
+		switch driver {
+		case "session", "token":
+		case "jwt":
+			jwt, _ := settings["jwt"].(map[string]any)
+			algorithm, _ := jwt["algorithm"].(string)
+			secret, _ := jwt["secret"].(string)
+			privateKey, _ := jwt["private_key"].(string)
+			publicKey, _ := jwt["public_key"].(string)
+			if algorithm == "" || strings.HasPrefix(algorithm, "HS") {
+				if len(secret) < 32 {
+					d.fail(fmt.Sprintf("auth guard %s needs a jwt secret of at least 32 bytes", name),
+						"set auth.guards."+name+".jwt.secret, e.g. to ${JWT_SECRET}")
+				}
+			} else if privateKey == "" && publicKey == "" {
+				d.fail(fmt.Sprintf("auth guard %s uses %s but has no key", name, algorithm),
+					"set auth.guards."+name+".jwt.private_key or public_key")
+			}
+		default:
+			d.warn(fmt.Sprintf("auth guard %s uses driver %q, which is not built in", name, driver),
+				"make sure it is registered with the auth manager's Extend")
+		}
+	}
+}
+
+// checkStoragePaths verifies the directories the app writes to are writable.
+func (d *doctor) checkStoragePaths() {
+	cfg := d.config
+	paths := map[string]string{"storage": "storage"}
+
+	if cfg.GetString("logging.default") == "file" {
+		logPath := cfg.GetString("logging.channels.file.path")
+		if logPath == "" {
+			logPath = filepath.Join("storage", "logs", "app.log")
+		}
+		paths["log file directory"] = filepath.Dir(logPath)
+	}
+	if cfg.GetString("session.driver") == "file" {
+		dir := cfg.GetString("session.files")
+		if dir == "" {
+			dir = filepath.Join("storage", "framework", "sessions")
+		}
+		paths["session directory"] = dir
+	}
+	disks := cfg.GetMap("filesystem.disks")
+	for _, name := range sortedKeys(disks) {
+		settings, _ := disks[name].(map[string]any)
+		if driver, _ := settings["driver"].(string); driver == "local" {
+			if root, _ := settings["root"].(string); root != "" {
+				paths["disk "+name] = root
+			}
+		}
+	}
+	connections := cfg.GetMap("database.connections")
+	for _, name := range sortedKeys(connections) {
+		settings, _ := connections[name].(map[string]any)
+		database, _ := settings["database"].(string)
+		if driver, _ := settings["driver"].(string); driver == "sqlite" && database != "" && database != ":memory:" {
+			paths["sqlite connection "+name] = filepath.Dir(database)
+		}
+	}
+
+	failed := false
+	for _, label := range sortedKeys(paths) {
+		path := paths[label]
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(d.dir, path)
+		}
+		if err := checkWritable(path); err != nil {
+			failed = true
+			d.fail(fmt.Sprintf("%s (%s) is not writable: %v", label, path, err),
+				fmt.Sprintf("run `mkdir -p %s && chmod u+w %s`", path, path))
+		}
+	}
+	if !failed {
+		d.ok("storage paths are writable")
+	}
+}
+
+// checkWritable reports whether files can be created in dir. Directories
+// that don't exist yet are fine if the app can create them.
+func checkWritable(dir string) error {
+	for !dirExists(dir) {
+		if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+			return errors.New("not a directory")
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return errors.New("no existing parent directory")
+		}
+		dir = parent
+	}
+
+	file, err := os.CreateTemp(dir, ".genesys-doctor-")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
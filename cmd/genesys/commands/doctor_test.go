@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// doctorProject writes a project with the given go.mod and config files.
+func doctorProject(t *testing.T, gomod string, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0644))
+	for name, content := range files {
+		path := filepath.Join(dir, "config", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func runDoctor(t *testing.T, dir string) (string, error) {
+	t.Helper()
+	d, err := newDoctor(dir)
+	require.NoError(t, err)
+	d.version = "1.1.0"
+	d.run()
+
+	var out bytes.Buffer
+	err = d.report(&out)
+	return out.String(), err
+}
+
+func TestFrameworkRequirement(t *testing.T) {
+	version, replaced := frameworkRequirement([]byte("module x\n\nrequire github.com/genesysflow/go-genesys v1.2.0\n"))
+	assert.Equal(t, "v1.2.0", version)
+	assert.False(t, replaced)
+
+	version, replaced = frameworkRequirement([]byte(`module x
+
+require (
+	github.com/spf13/cobra v1.8.1
+	github.com/genesysflow/go-genesys v1.0.0 // indirect
+)
+
+replace (
+	github.com/genesysflow/go-genesys => ../go-genesys
+)
+`))
+	assert.Equal(t, "v1.0.0", version)
+	assert.True(t, replaced)
+}
+
+func TestDoctorHealthyProject(t *testing.T) {
+	dir := doctorProject(t, "module x\n\nrequire github.com/genesysflow/go-genesys v1.1.0\n", map[string]string{
+		"app.yaml":     "key: base64:AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=\n",
+		"session.yaml": "driver: file\nlifetime: 120\n",
+	})
+
+	out, err := runDoctor(t, dir)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "✓ go-genesys v1.1.0 matches the CLI")
+	assert.Contains(t, out, "✓ app.key is set")
+	assert.Contains(t, out, "0 error(s), 0 warning(s)")
+}
+
+func TestDoctorReportsProblems(t *testing.T) {
+	dir := doctorProject(t, "module x\n\nrequire github.com/genesysflow/go-genesys v1.0.0\n", map[string]string{
+		"session.yaml":  "driver: cookie\nexpiration: 60\n",
+		"database.yaml": "default: mysql\nconnections:\n  sqlite:\n    driver: sqlite\n    database: blocked/app.sqlite\n",
+		"auth.yaml":     "guards:\n  api:\n    driver: jwt\n    provider: admins\n    jwt:\n      secret: short\nproviders:\n  users:\n    driver: database\n",
+	})
+	// A file where a directory should be makes the sqlite path unwritable.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "blocked"), nil, 0644))
+
+	out, err := runDoctor(t, dir)
+	assert.ErrorContains(t, err, "doctor found")
+	for _, want := range []string{
+		"project uses go-genesys v1.0.0, the CLI is 1.1.0",
+		"run `genesys upgrade`",
+		"app.key is not set",
+		"APP_KEY=base64:",
+		"config key session.expiration is deprecated",
+		"session.driver is cookie but app.key is not set",
+		`database.default is "mysql"`,
+		`user provider "admins"`,
+		"auth guard api needs a jwt secret of at least 32 bytes",
+		"sqlite connection sqlite",
+	} {
+		assert.Contains(t, out, want)
+	}
+}
+
+func TestDoctorRequiresGoMod(t *testing.T) {
+	_, err := newDoctor(t.TempDir())
+	assert.ErrorContains(t, err, "go.mod not found")
+}
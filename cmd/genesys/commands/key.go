@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/crypt"
+	"github.com/spf13/cobra"
+)
+
+// KeyGenerateCmd creates the 'key:generate' command.
+func KeyGenerateCmd() *cobra.Command {
+	var path, app string
+	var show, force, rotate bool
+
+	cmd := &cobra.Command{
+		Use:          "key:generate",
+		SilenceUsage: true,
+		Short:        "Generate the application key",
+		Long: `Generate a random AES-256 application key and write it to APP_KEY in
+the project's .env file. The key encrypts cookies, sessions, cache entries
+and queued jobs, so an existing key is only replaced with --force or
+--rotate.
+
+--rotate moves the current key to APP_PREVIOUS_KEYS, so data encrypted
+with it stays readable while it is re-encrypted. Make sure config/app.yaml
+has ` + "`previous_keys: ${APP_PREVIOUS_KEYS:-}`" + `. Remove old keys once
+nothing depends on them.
+
+Example:
+  genesys key:generate
+  genesys key:generate --show
+  genesys key:generate --rotate
+  genesys key:generate --app api`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := crypt.GenerateKey()
+			if err != nil {
+				return err
+			}
+			if show {
+				fmt.Fprintln(cmd.OutOrStdout(), key)
+				return nil
+			}
+
+			dir, err := filepath.Abs(path)
+			if err != nil {
+				return err
+			}
+			if dir, err = resolveApp(dir, app); err != nil {
+				return err
+			}
+			return writeAppKey(cmd.OutOrStdout(), filepath.Join(dir, ".env"), key, force, rotate)
+		},
+	}
+
+	cmd.Flags().StringVarP(&path, "path", "p", ".", "Project directory")
+	cmd.Flags().StringVar(&app, "app", "", "App to generate the key for in a Go workspace")
+	cmd.Flags().BoolVar(&show, "show", false, "Print the key instead of writing it")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Replace an existing key")
+	cmd.Flags().BoolVar(&rotate, "rotate", false, "Replace an existing key and keep it in APP_PREVIOUS_KEYS")
+	return cmd
+}
+
+// writeAppKey sets APP_KEY in the env file at path, creating the file when
+// it does not exist.
+func writeAppKey(out io.Writer, path, key string, force, rotate bool) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	if len(content) == 0 {
+		lines = nil
+	}
+
+	current, _ := envValue(lines, "APP_KEY")
+	if current != "" && !force && !rotate {
+		return fmt.Errorf("APP_KEY is already set in %s; use --rotate to replace it and keep it for decryption, or --force to discard it", path)
+	}
+
+	lines = setEnvValue(lines, "APP_KEY", key)
+	if rotate && current != "" {
+		previous, _ := envValue(lines, "APP_PREVIOUS_KEYS")
+		keys := []string{current}
+		for _, old := range strings.Split(previous, ",") {
+			if old = strings.TrimSpace(old); old != "" && old != current {
+				keys = append(keys, old)
+			}
+		}
+		lines = setEnvValue(lines, "APP_PREVIOUS_KEYS", strings.Join(keys, ","))
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		return err
+	}
+
+	if rotate && current != "" {
+		fmt.Fprintf(out, "✓ Rotated the application key in %s; the old key is kept in APP_PREVIOUS_KEYS\n", path)
+	} else {
+		fmt.Fprintf(out, "✓ Application key set in %s\n", path)
+	}
+	return nil
+}
+
+// envValue returns the unquoted value of name in env file lines.
+func envValue(lines []string, name string) (string, bool) {
+	for _, line := range lines {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		key = strings.TrimSpace(strings.TrimPrefix(key, "export "))
+		if ok && key == name {
+			return strings.Trim(strings.TrimSpace(value), `"'`), true
+		}
+	}
+	return "", false
+}
+
+// setEnvValue replaces name's line in env file lines, or appends one.
+func setEnvValue(lines []string, name, value string) []string {
+	for i, line := range lines {
+		key, _, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if ok && strings.TrimSpace(strings.TrimPrefix(key, "export ")) == name {
+			lines[i] = name + "=" + value
+			return lines
+		}
+	}
+	return append(lines, name+"="+value)
+}
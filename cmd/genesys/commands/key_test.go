@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/crypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readEnvFile(t *testing.T, path string) []string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+}
+
+func TestWriteAppKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("APP_NAME=Demo\nAPP_KEY=\nPORT=3000\n"), 0644))
+
+	var out bytes.Buffer
+	require.NoError(t, writeAppKey(&out, path, "base64:first", false, false))
+	assert.Contains(t, out.String(), "Application key set")
+	assert.Equal(t, []string{"APP_NAME=Demo", "APP_KEY=base64:first", "PORT=3000"}, readEnvFile(t, path))
+
+	err := writeAppKey(io.Discard, path, "base64:second", false, false)
+	assert.ErrorContains(t, err, "APP_KEY is already set")
+
+	require.NoError(t, writeAppKey(io.Discard, path, "base64:second", false, true))
+	require.NoError(t, writeAppKey(io.Discard, path, "base64:third", false, true))
+	lines := readEnvFile(t, path)
+	value, _ := envValue(lines, "APP_KEY")
+	assert.Equal(t, "base64:third", value)
+	value, _ = envValue(lines, "APP_PREVIOUS_KEYS")
+	assert.Equal(t, "base64:second,base64:first", value)
+
+	require.NoError(t, writeAppKey(io.Discard, path, "base64:fourth", true, false))
+	value, _ = envValue(readEnvFile(t, path), "APP_KEY")
+	assert.Equal(t, "base64:fourth", value)
+}
+
+func TestWriteAppKeyCreatesEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, writeAppKey(io.Discard, path, "base64:key", false, false))
+	assert.Equal(t, []string{"APP_KEY=base64:key"}, readEnvFile(t, path))
+}
+
+func TestKeyGenerateCmd(t *testing.T) {
+	dir := t.TempDir()
+	cmd := KeyGenerateCmd()
+	cmd.SetArgs([]string{"--path", dir})
+	cmd.SetOut(io.Discard)
+	require.NoError(t, cmd.Execute())
+
+	key, ok := envValue(readEnvFile(t, filepath.Join(dir, ".env")), "APP_KEY")
+	require.True(t, ok)
+	raw, err := crypt.ParseKey(key)
+	require.NoError(t, err)
+	assert.Len(t, raw, crypt.KeySize)
+
+	var out bytes.Buffer
+	cmd = KeyGenerateCmd()
+	cmd.SetArgs([]string{"--show"})
+	cmd.SetOut(&out)
+	require.NoError(t, cmd.Execute())
+	assert.True(t, strings.HasPrefix(out.String(), "base64:"))
+}
@@ -2,12 +2,14 @@ package commands
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"text/template"
 
+	"github.com/genesysflow/go-genesys/crypt"
 	"github.com/genesysflow/go-genesys/foundation"
 	"github.com/spf13/cobra"
 )
@@ -106,6 +108,7 @@ func createProject(name, moduleName string) error {
 		"bootstrap/app.go":                      "bootstrap_app.go.tmpl",
 		"app/providers/app_service_provider.go": "app_service_provider.go.tmpl",
 		"database/migrations/migrations.go":     "migrations.go.tmpl",
+		"database/seeders/seeders.go":           "seeders.go.tmpl",
 		"routes/routes.go":                      "routes.go.tmpl",
 		"routes/web.go":                         "routes_web.go.tmpl",
 		"routes/api.go":                         "routes_api.go.tmpl",
@@ -118,6 +121,9 @@ func createProject(name, moduleName string) error {
 		"config/session.yaml":                   "config_session.yaml.tmpl",
 		"config/database.yaml":                  "config_database.yaml.tmpl",
 		"config/filesystem.yaml":                "config_filesystem.yaml.tmpl",
+		"config/cors.yaml":                      "config_cors.yaml.tmpl",
+		"config/auth.yaml":                      "config_auth.yaml.tmpl",
+		"config/hashing.yaml":                   "config_hashing.yaml.tmpl",
 	}
 
 	for filename, tmplFilename := range templates {
@@ -130,6 +136,15 @@ func createProject(name, moduleName string) error {
 		}
 	}
 
+	// Give the app its own key; .env.example keeps an empty one.
+	key, err := crypt.GenerateKey()
+	if err != nil {
+		return err
+	}
+	if err := writeAppKey(io.Discard, filepath.Join(name, ".env"), key, true, false); err != nil {
+		return fmt.Errorf("failed to write APP_KEY: %w", err)
+	}
+
 	// Create go.mod
 	goModContent := fmt.Sprintf(`module %s
 
@@ -145,6 +160,16 @@ require (
 		return fmt.Errorf("failed to create go.mod: %w", err)
 	}
 
+	// Inside a Go workspace, add the new app to go.work.
+	if cwd, err := os.Getwd(); err == nil {
+		if ws, err := findWorkspace(cwd); err == nil && ws != nil {
+			if err := addWorkspaceUse(ws, filepath.Join(cwd, name)); err != nil {
+				return fmt.Errorf("failed to add %s to go.work: %w", name, err)
+			}
+			fmt.Printf("✓ Added %s to %s\n", name, filepath.Join(ws.root, "go.work"))
+		}
+	}
+
 	fmt.Printf("\n✓ Project created successfully!\n\n")
 	fmt.Printf("Next steps:\n")
 	fmt.Printf("  cd %s\n", name)
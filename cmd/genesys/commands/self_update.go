@@ -0,0 +1,377 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/genesysflow/go-genesys/foundation"
+	"github.com/spf13/cobra"
+)
+
+// ReleasePublicKey is the base64 ed25519 key release checksums are signed
+// with. Release builds set it with
+// -ldflags "-X github.com/genesysflow/go-genesys/cmd/genesys/commands.ReleasePublicKey=...".
+// Without it, or for a release without checksums.txt.sig, self-update
+// refuses to install unless --insecure is passed.
+var ReleasePublicKey = ""
+
+const releasesURL = "https://api.github.com/repos/genesysflow/go-genesys/releases"
+
+// SelfUpdateCmd creates the 'self-update' command.
+func SelfUpdateCmd() *cobra.Command {
+	var channel string
+	var check bool
+	var insecure bool
+
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Update the genesys CLI to the latest release",
+		Long: `Download the latest genesys release from GitHub and replace the
+running binary. The download is verified against the release's
+checksums.txt, which must be signed by checksums.txt.sig. Builds without a
+release public key can't verify the signature and refuse to update unless
+--insecure is passed.
+
+Releases must attach the binary as genesys_<os>_<arch> (with .exe on
+Windows), along with checksums.txt in sha256sum format.
+
+Example:
+  genesys self-update
+  genesys self-update --channel beta
+  genesys self-update --check`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			updater, err := newSelfUpdater()
+			if err != nil {
+				return err
+			}
+			updater.insecure = insecure
+			return updater.run(cmd.Context(), cmd.OutOrStdout(), channel, check)
+		},
+	}
+
+	cmd.Flags().StringVar(&channel, "channel", "stable", "Release channel: stable or beta")
+	cmd.Flags().BoolVar(&check, "check", false, "Only report whether an update is available")
+	cmd.Flags().BoolVar(&insecure, "insecure", false, "Install without a verified release signature")
+	return cmd
+}
+
+type release struct {
+	TagName    string         `json:"tag_name"`
+	Draft      bool           `json:"draft"`
+	Prerelease bool           `json:"prerelease"`
+	Assets     []releaseAsset `json:"assets"`
+}
+
+type releaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+func (r release) asset(name string) (releaseAsset, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return releaseAsset{}, false
+}
+
+// selfUpdater replaces the CLI binary with a newer release.
+type selfUpdater struct {
+	client     *http.Client
+	url        string
+	current    string
+	executable string
+	asset      string
+	publicKey  ed25519.PublicKey
+
+	// insecure allows installing a release whose checksums.txt signature
+	// can't be verified.
+	insecure bool
+}
+
+func newSelfUpdater() (*selfUpdater, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate the genesys binary: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return nil, fmt.Errorf("failed to locate the genesys binary: %w", err)
+	}
+
+	asset := "genesys_" + runtime.GOOS + "_" + runtime.GOARCH
+	if runtime.GOOS == "windows" {
+		asset += ".exe"
+	}
+
+	var key ed25519.PublicKey
+	if ReleasePublicKey != "" {
+		raw, err := base64.StdEncoding.DecodeString(ReleasePublicKey)
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid release public key")
+		}
+		key = raw
+	}
+
+	return &selfUpdater{
+		client:     &http.Client{Timeout: 5 * time.Minute},
+		url:        releasesURL,
+		current:    foundation.Version,
+		executable: exe,
+		asset:      asset,
+		publicKey:  key,
+	}, nil
+}
+
+func (u *selfUpdater) run(ctx context.Context, out io.Writer, channel string, check bool) error {
+	if channel != "stable" && channel != "beta" {
+		return fmt.Errorf("unknown channel %q: use stable or beta", channel)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	latest, err := u.latest(ctx, channel)
+	if err != nil {
+		return err
+	}
+	if latest == nil || compareVersions(latest.TagName, u.current) <= 0 {
+		fmt.Fprintf(out, "✓ genesys %s is up to date (%s channel)\n", u.current, channel)
+		return nil
+	}
+	if check {
+		fmt.Fprintf(out, "genesys %s is available (installed: %s). Run `genesys self-update` to install it.\n", latest.TagName, u.current)
+		return nil
+	}
+
+	fmt.Fprintf(out, "Downloading genesys %s...\n", latest.TagName)
+	binary, err := u.download(ctx, out, *latest)
+	if err != nil {
+		return err
+	}
+	if err := u.replace(binary); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "✓ Updated genesys %s → %s\n", u.current, latest.TagName)
+	return nil
+}
+
+// latest returns the newest release on the channel that has a binary for
+// this platform, or nil. Beta includes prereleases.
+func (u *selfUpdater) latest(ctx context.Context, channel string) (*release, error) {
+	body, err := u.get(ctx, u.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	var releases []release
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return nil, fmt.Errorf("failed to parse releases: %w", err)
+	}
+
+	var latest *release
+	for i, r := range releases {
+		if r.Draft || (r.Prerelease && channel != "beta") {
+			continue
+		}
+		if _, ok := r.asset(u.asset); !ok {
+			continue
+		}
+		if latest == nil || compareVersions(r.TagName, latest.TagName) > 0 {
+			latest = &releases[i]
+		}
+	}
+	return latest, nil
+}
+
+// download fetches the release binary and verifies it against the signed
+// checksums.
+func (u *selfUpdater) download(ctx context.Context, out io.Writer, r release) ([]byte, error) {
+	checksumsAsset, ok := r.asset("checksums.txt")
+	if !ok {
+		return nil, fmt.Errorf("release %s has no checksums.txt", r.TagName)
+	}
+	checksums, err := u.get(ctx, checksumsAsset.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download checksums: %w", err)
+	}
+
+	sigAsset, signed := r.asset("checksums.txt.sig")
+	switch {
+	case u.publicKey != nil && signed:
+		sig, err := u.get(ctx, sigAsset.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download signature: %w", err)
+		}
+		if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig))); err == nil {
+			sig = decoded
+		}
+		if !ed25519.Verify(u.publicKey, checksums, sig) {
+			return nil, errors.New("checksums.txt signature is invalid")
+		}
+	case u.insecure:
+		fmt.Fprintln(out, "! --insecure: installing without a verified signature")
+	case u.publicKey == nil:
+		return nil, errors.New("this genesys build has no release public key to verify the download with; rerun with --insecure to trust checksums.txt alone")
+	default:
+		return nil, fmt.Errorf("release %s is not signed; rerun with --insecure to trust checksums.txt alone", r.TagName)
+	}
+
+	want, err := checksumFor(checksums, u.asset)
+	if err != nil {
+		return nil, err
+	}
+
+	binaryAsset, _ := r.asset(u.asset)
+	binary, err := u.get(ctx, binaryAsset.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", u.asset, err)
+	}
+	sum := sha256.Sum256(binary)
+	if hex.EncodeToString(sum[:]) != want {
+		return nil, fmt.Errorf("checksum mismatch for %s", u.asset)
+	}
+	return binary, nil
+}
+
+// replace swaps the running binary for the new one. The old binary is
+// moved aside first, since Windows cannot overwrite a running executable.
+func (u *selfUpdater) replace(binary []byte) error {
+	dir := filepath.Dir(u.executable)
+	tmp, err := os.CreateTemp(dir, ".genesys-update-")
+	if err != nil {
+		return fmt.Errorf("failed to write update (is %s writable?): %w", dir, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return err
+	}
+
+	old := u.executable + ".old"
+	os.Remove(old)
+	if err := os.Rename(u.executable, old); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", u.executable, err)
+	}
+	if err := os.Rename(tmp.Name(), u.executable); err != nil {
+		os.Rename(old, u.executable)
+		return fmt.Errorf("failed to replace %s: %w", u.executable, err)
+	}
+	os.Remove(old)
+	return nil
+}
+
+func (u *selfUpdater) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// checksumFor finds a file's hash in sha256sum output.
+func checksumFor(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(strings.NewReader(string(checksums)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("checksums.txt has no entry for %s", name)
+}
+
+// compareVersions compares semantic versions such as "v1.2.0" and
+// "1.3.0-beta.1", returning -1, 0 or 1. Prereleases sort before their release.
+func compareVersions(a, b string) int {
+	coreA, preA, _ := strings.Cut(strings.TrimPrefix(a, "v"), "-")
+	coreB, preB, _ := strings.Cut(strings.TrimPrefix(b, "v"), "-")
+
+	partsA, partsB := strings.Split(coreA, "."), strings.Split(coreB, ".")
+	for i := 0; i < 3; i++ {
+		if c := compareNumbers(part(partsA, i), part(partsB, i)); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	}
+
+	idsA, idsB := strings.Split(preA, "."), strings.Split(preB, ".")
+	for i := 0; i < len(idsA) && i < len(idsB); i++ {
+		_, errA := strconv.Atoi(idsA[i])
+		_, errB := strconv.Atoi(idsB[i])
+		var c int
+		if errA == nil && errB == nil {
+			c = compareNumbers(idsA[i], idsB[i])
+		} else {
+			c = strings.Compare(idsA[i], idsB[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareNumbers(strconv.Itoa(len(idsA)), strconv.Itoa(len(idsB)))
+}
+
+func part(parts []string, i int) string {
+	if i < len(parts) {
+		return parts[i]
+	}
+	return "0"
+}
+
+func compareNumbers(a, b string) int {
+	x, _ := strconv.Atoi(a)
+	y, _ := strconv.Atoi(b)
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
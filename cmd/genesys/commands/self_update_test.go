@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// releaseServer serves a release list and assets. Assets are served from
+// files keyed by name.
+func releaseServer(t *testing.T, releases func(base string) []release, files map[string][]byte) string {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/releases" {
+			json.NewEncoder(w).Encode(releases(server.URL))
+			return
+		}
+		body, ok := files[filepath.Base(r.URL.Path)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func assets(base string, names ...string) []releaseAsset {
+	list := make([]releaseAsset, len(names))
+	for i, name := range names {
+		list[i] = releaseAsset{Name: name, URL: base + "/download/" + name}
+	}
+	return list
+}
+
+func checksums(name string, body []byte) []byte {
+	sum := sha256.Sum256(body)
+	return []byte(hex.EncodeToString(sum[:]) + "  " + name + "\n")
+}
+
+func newTestUpdater(t *testing.T, url string) *selfUpdater {
+	exe := filepath.Join(t.TempDir(), "genesys")
+	require.NoError(t, os.WriteFile(exe, []byte("old"), 0755))
+	return &selfUpdater{
+		client:     http.DefaultClient,
+		url:        url + "/releases",
+		current:    "1.1.0",
+		executable: exe,
+		asset:      "genesys_linux_amd64",
+	}
+}
+
+func TestSelfUpdateReplacesBinary(t *testing.T) {
+	binary := []byte("new binary")
+	url := releaseServer(t, func(base string) []release {
+		return []release{
+			{TagName: "v1.2.0", Assets: assets(base, "genesys_linux_amd64", "checksums.txt")},
+			{TagName: "v1.3.0-beta.1", Prerelease: true, Assets: assets(base, "genesys_linux_amd64", "checksums.txt")},
+			{TagName: "v1.4.0", Assets: assets(base, "genesys_darwin_arm64", "checksums.txt")},
+		}
+	}, map[string][]byte{
+		"genesys_linux_amd64": binary,
+		"checksums.txt":       checksums("genesys_linux_amd64", binary),
+	})
+	updater := newTestUpdater(t, url)
+	updater.insecure = true
+
+	var out bytes.Buffer
+	require.NoError(t, updater.run(context.Background(), &out, "stable", false))
+	assert.Contains(t, out.String(), "installing without a verified signature")
+	assert.Contains(t, out.String(), "Updated genesys 1.1.0 → v1.2.0")
+
+	installed, err := os.ReadFile(updater.executable)
+	require.NoError(t, err)
+	assert.Equal(t, binary, installed)
+	assert.NoFileExists(t, updater.executable+".old")
+}
+
+func TestSelfUpdateChannels(t *testing.T) {
+	url := releaseServer(t, func(base string) []release {
+		return []release{
+			{TagName: "v1.1.0", Assets: assets(base, "genesys_linux_amd64")},
+			{TagName: "v1.2.0-beta.2", Prerelease: true, Assets: assets(base, "genesys_linux_amd64")},
+			{TagName: "v1.2.0-beta.10", Prerelease: true, Assets: assets(base, "genesys_linux_amd64")},
+			{TagName: "v9.0.0", Draft: true, Assets: assets(base, "genesys_linux_amd64")},
+		}
+	}, nil)
+	updater := newTestUpdater(t, url)
+
+	var out bytes.Buffer
+	require.NoError(t, updater.run(context.Background(), &out, "stable", true))
+	assert.Contains(t, out.String(), "is up to date")
+
+	out.Reset()
+	require.NoError(t, updater.run(context.Background(), &out, "beta", true))
+	assert.Contains(t, out.String(), "v1.2.0-beta.10 is available")
+
+	assert.ErrorContains(t, updater.run(context.Background(), &out, "nightly", true), `unknown channel "nightly"`)
+}
+
+func TestSelfUpdateRejectsBadChecksum(t *testing.T) {
+	url := releaseServer(t, func(base string) []release {
+		return []release{{TagName: "v1.2.0", Assets: assets(base, "genesys_linux_amd64", "checksums.txt")}}
+	}, map[string][]byte{
+		"genesys_linux_amd64": []byte("tampered"),
+		"checksums.txt":       checksums("genesys_linux_amd64", []byte("original")),
+	})
+	updater := newTestUpdater(t, url)
+	updater.insecure = true
+
+	err := updater.run(context.Background(), &bytes.Buffer{}, "stable", false)
+	assert.ErrorContains(t, err, "checksum mismatch")
+
+	installed, _ := os.ReadFile(updater.executable)
+	assert.Equal(t, "old", string(installed))
+}
+
+func TestSelfUpdateVerifiesSignature(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	_, otherPrivate, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	binary := []byte("signed binary")
+	sums := checksums("genesys_linux_amd64", binary)
+	files := map[string][]byte{
+		"genesys_linux_amd64": binary,
+		"checksums.txt":       sums,
+	}
+	signed := true
+	url := releaseServer(t, func(base string) []release {
+		names := []string{"genesys_linux_amd64", "checksums.txt"}
+		if signed {
+			names = append(names, "checksums.txt.sig")
+		}
+		return []release{{TagName: "v1.2.0", Assets: assets(base, names...)}}
+	}, files)
+
+	updater := newTestUpdater(t, url)
+	updater.publicKey = public
+
+	files["checksums.txt.sig"] = []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(otherPrivate, sums)))
+	err = updater.run(context.Background(), &bytes.Buffer{}, "stable", false)
+	assert.ErrorContains(t, err, "signature is invalid")
+
+	signed = false
+	err = updater.run(context.Background(), &bytes.Buffer{}, "stable", false)
+	assert.ErrorContains(t, err, "is not signed")
+
+	signed = true
+	files["checksums.txt.sig"] = ed25519.Sign(private, sums)
+	require.NoError(t, updater.run(context.Background(), &bytes.Buffer{}, "stable", false))
+}
+
+func TestSelfUpdateRequiresSignature(t *testing.T) {
+	binary := []byte("unsigned binary")
+	url := releaseServer(t, func(base string) []release {
+		return []release{{TagName: "v1.2.0", Assets: assets(base, "genesys_linux_amd64", "checksums.txt")}}
+	}, map[string][]byte{
+		"genesys_linux_amd64": binary,
+		"checksums.txt":       checksums("genesys_linux_amd64", binary),
+	})
+	updater := newTestUpdater(t, url)
+
+	err := updater.run(context.Background(), &bytes.Buffer{}, "stable", false)
+	assert.ErrorContains(t, err, "no release public key")
+	installed, _ := os.ReadFile(updater.executable)
+	assert.Equal(t, "old", string(installed))
+
+	// Checking for updates needs no signature.
+	require.NoError(t, updater.run(context.Background(), &bytes.Buffer{}, "stable", true))
+
+	updater.insecure = true
+	require.NoError(t, updater.run(context.Background(), &bytes.Buffer{}, "stable", false))
+	installed, _ = os.ReadFile(updater.executable)
+	assert.Equal(t, binary, installed)
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 1, compareVersions("v1.10.0", "1.9.0"))
+	assert.Equal(t, 0, compareVersions("v1.2", "1.2.0"))
+	assert.Equal(t, -1, compareVersions("1.2.0-beta.1", "1.2.0"))
+	assert.Equal(t, 1, compareVersions("1.2.0-beta.10", "1.2.0-beta.2"))
+	assert.Equal(t, 1, compareVersions("1.2.0-rc.1", "1.2.0-beta.5"))
+	assert.Equal(t, 1, compareVersions("1.2.0-beta.1.1", "1.2.0-beta.1"))
+}
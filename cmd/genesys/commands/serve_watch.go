@@ -0,0 +1,202 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// watchInterval is how often watched files are checked for changes.
+const watchInterval = 500 * time.Millisecond
+
+// watchStopTimeout is how long a server gets to shut down before it's
+// killed on restart.
+const watchStopTimeout = 10 * time.Second
+
+// watchedExtensions are the files whose changes rebuild the app.
+var watchedExtensions = map[string]bool{
+	".go":   true,
+	".mod":  true,
+	".sum":  true,
+	".env":  true,
+	".yaml": true,
+	".yml":  true,
+	".json": true,
+	".toml": true,
+}
+
+// skippedDirs are directories never watched.
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"storage":      true,
+	"tmp":          true,
+}
+
+// fileStamp is what a change to a watched file is detected by.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// snapshotFiles stamps the watched files under dir.
+func snapshotFiles(dir string) (map[string]fileStamp, error) {
+	files := make(map[string]fileStamp)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != dir && (strings.HasPrefix(name, ".") || skippedDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !watchedExtensions[filepath.Ext(name)] && !strings.HasPrefix(name, ".env") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	return files, err
+}
+
+// changedFile returns a file that was added, changed or removed between two
+// snapshots, or "" if none was.
+func changedFile(before, after map[string]fileStamp) string {
+	for path, stamp := range after {
+		if previous, ok := before[path]; !ok || previous != stamp {
+			return path
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			return path
+		}
+	}
+	return ""
+}
+
+// watchServe builds the app in dir and runs its server, then rebuilds and
+// restarts it whenever a watched file changes. A build that fails leaves
+// the running server as it is.
+func watchServe(out, errOut io.Writer, dir string, args []string, env []string) error {
+	buildDir, err := os.MkdirTemp("", "genesys-serve-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(buildDir)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	files, err := snapshotFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	var server *exec.Cmd
+	var running string
+	build := 0
+	restart := func() {
+		build++
+		binary := filepath.Join(buildDir, fmt.Sprintf("app-%d", build))
+		fmt.Fprintln(out, "Building...")
+		compile := exec.Command("go", "build", "-o", binary, ".")
+		compile.Dir = dir
+		compile.Env = env
+		compile.Stdout = out
+		compile.Stderr = errOut
+		if err := compile.Run(); err != nil {
+			fmt.Fprintln(errOut, "Build failed; waiting for changes.")
+			return
+		}
+
+		// The new binary is ready, so the old server only stops now.
+		stopServer(server)
+		if running != "" {
+			os.Remove(running)
+		}
+		running = binary
+
+		server = exec.Command(binary, append([]string{"serve"}, args...)...)
+		server.Dir = dir
+		server.Env = env
+		server.Stdin = os.Stdin
+		server.Stdout = out
+		server.Stderr = errOut
+		if err := server.Start(); err != nil {
+			fmt.Fprintf(errOut, "Failed to start server: %v\n", err)
+			server = nil
+		}
+	}
+
+	restart()
+	fmt.Fprintln(out, "Watching for changes. Press Ctrl+C to stop.")
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-signals:
+			stopServer(server)
+			return nil
+		case <-ticker.C:
+			current, err := snapshotFiles(dir)
+			if err != nil {
+				continue
+			}
+			changed := changedFile(files, current)
+			if changed == "" {
+				continue
+			}
+			// Editors often write several files at once; let them settle.
+			time.Sleep(watchInterval)
+			if settled, err := snapshotFiles(dir); err == nil {
+				current = settled
+			}
+			files = current
+
+			rel, _ := filepath.Rel(dir, changed)
+			fmt.Fprintf(out, "Change in %s, restarting.\n", rel)
+			restart()
+		}
+	}
+}
+
+// stopServer asks a server to shut down gracefully and kills it if it
+// hasn't within watchStopTimeout.
+func stopServer(server *exec.Cmd) {
+	if server == nil || server.Process == nil {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		server.Wait()
+		close(done)
+	}()
+
+	if err := server.Process.Signal(os.Interrupt); err != nil {
+		// Interrupts can't be sent on every platform.
+		server.Process.Kill()
+	}
+	select {
+	case <-done:
+	case <-time.After(watchStopTimeout):
+		server.Process.Kill()
+		<-done
+	}
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	write("main.go", "package main")
+	write("config/app.yaml", "name: app")
+	write(".env", "APP_ENV=local")
+	write(".env.testing", "APP_ENV=testing")
+	write("README.md", "# app")
+	write("node_modules/pkg/index.json", "{}")
+	write(".git/config.json", "{}")
+	write("storage/logs/app.json", "{}")
+
+	files, err := snapshotFiles(dir)
+	require.NoError(t, err)
+
+	var names []string
+	for path := range files {
+		rel, _ := filepath.Rel(dir, path)
+		names = append(names, filepath.ToSlash(rel))
+	}
+	assert.ElementsMatch(t, []string{"main.go", "config/app.yaml", ".env", ".env.testing"}, names)
+}
+
+func TestChangedFile(t *testing.T) {
+	now := time.Now()
+	before := map[string]fileStamp{
+		"main.go":   {modTime: now, size: 10},
+		"routes.go": {modTime: now, size: 20},
+	}
+
+	assert.Empty(t, changedFile(before, map[string]fileStamp{
+		"main.go":   {modTime: now, size: 10},
+		"routes.go": {modTime: now, size: 20},
+	}))
+	assert.Equal(t, "main.go", changedFile(before, map[string]fileStamp{
+		"main.go":   {modTime: now.Add(time.Second), size: 10},
+		"routes.go": {modTime: now, size: 20},
+	}))
+	assert.Equal(t, "routes.go", changedFile(before, map[string]fileStamp{
+		"main.go": {modTime: now, size: 10},
+	}))
+	assert.Equal(t, "new.go", changedFile(before, map[string]fileStamp{
+		"main.go":   {modTime: now, size: 10},
+		"routes.go": {modTime: now, size: 20},
+		"new.go":    {modTime: now, size: 1},
+	}))
+}
@@ -0,0 +1,322 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// appCommands are the console commands of a Go-Genesys app that the CLI
+// runs in the app's directory.
+var appCommands = []struct {
+	name  string
+	short string
+}{
+	{"serve", "Start the app's development server"},
+	{"route:list", "List the app's registered routes"},
+	{"route:cache", "Cache the app's route table"},
+	{"route:clear", "Remove the app's route cache"},
+	{"migrate", "Run the app's pending migrations"},
+	{"migrate:rollback", "Rollback the app's last migration batch"},
+	{"migrate:status", "Show the app's migration status"},
+	{"make:migration", "Create a new database migration in the app"},
+	{"make:controller", "Create a new controller in the app"},
+	{"make:model", "Create a new model in the app"},
+	{"make:middleware", "Create a new middleware in the app"},
+	{"make:provider", "Create a new service provider in the app"},
+	{"make:seeder", "Create a new database seeder in the app"},
+	{"make:job", "Create a new queued job in the app"},
+	{"make:event", "Create a new event in the app"},
+	{"make:listener", "Create a new event listener in the app"},
+	{"make:policy", "Create a new authorization policy in the app"},
+	{"stub:publish", "Copy the generator stubs into the app for customization"},
+	{"db:seed", "Run the app's database seeders"},
+	{"console", "Start a service and SQL console with the app booted"},
+	{"config:cache", "Cache the app's configuration for faster boot"},
+	{"config:clear", "Remove the app's configuration cache"},
+	{"config:show", "Show the app's effective configuration values"},
+	{"container:list", "List the services bound in the app's container"},
+	{"package:discover", "Write the app's provider manifest"},
+	{"down", "Put the app into maintenance mode"},
+	{"up", "Bring the app out of maintenance mode"},
+	{"log:clear", "Clear the app's log files"},
+	{"session:table", "Create a migration for the app's sessions table"},
+	{"cache:table", "Create a migration for the app's cache table"},
+	{"queue:table", "Create a migration for the app's queue jobs table"},
+	{"queue:failed-table", "Create a migration for the app's failed queue jobs table"},
+	{"audit:table", "Create a migration for the app's audit log table"},
+	{"mail:sent-table", "Create a migration for the app's mail sent log table"},
+	{"schedule:run", "Run the app's scheduled tasks that are due"},
+	{"schedule:work", "Run the app's scheduler every minute until stopped"},
+	{"schedule:list", "List the app's scheduled tasks"},
+	{"db:schema:dump", "Dump the app's database schema"},
+}
+
+// AppCmds creates the commands that run in an app's directory through
+// `go run .`, so `genesys make:controller User` works from anywhere in
+// the project. In a Go workspace with several apps, --app picks the app.
+func AppCmds() []*cobra.Command {
+	cmds := make([]*cobra.Command, 0, len(appCommands))
+	for _, command := range appCommands {
+		name := command.name
+		long := command.short + `.
+
+The command runs as ` + "`go run . " + name + "`" + ` in the app's directory, so
+the app's own config and .env are used. Inside a Go workspace (go.work)
+with several apps, choose one with --app=<name>, where the name is the
+app's directory name; from within an app's directory it is detected.
+--env=<name> runs the command with APP_ENV set to name.
+`
+		if name == "serve" {
+			long += `
+With --watch, the app is built and its server restarted whenever a Go,
+config or .env file changes. A build that fails keeps the running server.
+`
+		}
+		cmds = append(cmds, &cobra.Command{
+			Use:   name,
+			Short: command.short,
+			Long: long + `
+Example:
+  genesys ` + name + `
+  genesys ` + name + ` --app=api`,
+			// Flags belong to the app's command; --app is taken out by hand.
+			DisableFlagParsing: true,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				appName, args := splitAppFlag(args)
+				envName, args := splitFlag(args, "env")
+				cwd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+				dir, err := resolveApp(cwd, appName)
+				if err != nil {
+					return err
+				}
+
+				env := os.Environ()
+				if envName != "" {
+					env = append(env, "APP_ENV="+envName)
+				}
+				if name == "serve" {
+					if watch, rest := splitBoolFlag(args, "watch"); watch {
+						return watchServe(cmd.OutOrStdout(), cmd.ErrOrStderr(), dir, rest, env)
+					}
+				}
+
+				run := exec.Command("go", append([]string{"run", ".", name}, args...)...)
+				run.Dir = dir
+				run.Env = env
+				run.Stdin = os.Stdin
+				run.Stdout = cmd.OutOrStdout()
+				run.Stderr = cmd.ErrOrStderr()
+				return run.Run()
+			},
+		})
+	}
+	return cmds
+}
+
+// splitAppFlag removes --app from args and returns its value.
+func splitAppFlag(args []string) (string, []string) {
+	return splitFlag(args, "app")
+}
+
+// splitFlag removes the flag --name from args and returns its value.
+func splitFlag(args []string, name string) (string, []string) {
+	var value string
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case strings.HasPrefix(arg, "--"+name+"="):
+			value = strings.TrimPrefix(arg, "--"+name+"=")
+		case arg == "--"+name && i+1 < len(args):
+			value = args[i+1]
+			i++
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return value, rest
+}
+
+// splitBoolFlag removes the boolean flag --name from args and reports
+// whether it was set.
+func splitBoolFlag(args []string, name string) (bool, []string) {
+	set := false
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		switch arg {
+		case "--" + name, "--" + name + "=true":
+			set = true
+		case "--" + name + "=false":
+			set = false
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return set, rest
+}
+
+// workspaceApp is a Go-Genesys app listed in a go.work file.
+type workspaceApp struct {
+	name string
+	dir  string
+}
+
+// workspace is a Go workspace and the Go-Genesys apps it uses.
+type workspace struct {
+	root string
+	apps []workspaceApp
+}
+
+// findWorkspace looks for go.work in dir and its parents. It returns nil
+// when dir is not in a workspace.
+func findWorkspace(dir string) (*workspace, error) {
+	for {
+		content, err := os.ReadFile(filepath.Join(dir, "go.work"))
+		if err == nil {
+			ws := &workspace{root: dir}
+			for _, use := range workspaceUses(content) {
+				appDir := filepath.Join(dir, use)
+				if isGenesysApp(appDir) {
+					ws.apps = append(ws.apps, workspaceApp{name: filepath.Base(appDir), dir: appDir})
+				}
+			}
+			return ws, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// workspaceUses returns the module directories of go.work use directives.
+func workspaceUses(gowork []byte) []string {
+	var uses []string
+	inBlock := false
+	scanner := bufio.NewScanner(strings.NewReader(string(gowork)))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case inBlock && fields[0] == ")":
+			inBlock = false
+		case inBlock:
+			uses = append(uses, strings.Trim(fields[0], `"`))
+		case fields[0] == "use" && len(fields) == 2 && fields[1] == "(":
+			inBlock = true
+		case fields[0] == "use" && len(fields) == 2:
+			uses = append(uses, strings.Trim(fields[1], `"`))
+		}
+	}
+	return uses
+}
+
+// isGenesysApp reports whether dir is a module that depends on the framework.
+func isGenesysApp(dir string) bool {
+	content, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return false
+	}
+	version, replaced := frameworkRequirement(content)
+	return version != "" || replaced
+}
+
+// resolveApp returns the directory of the app to operate on. Outside a
+// workspace that is dir itself. In a workspace it is the app named name,
+// the app dir is in, or the only app.
+func resolveApp(dir, name string) (string, error) {
+	ws, err := findWorkspace(dir)
+	if err != nil {
+		return "", err
+	}
+	if ws == nil || len(ws.apps) == 0 {
+		if name != "" {
+			return "", fmt.Errorf("--app=%s needs a go.work workspace, and none was found from %s", name, dir)
+		}
+		return dir, nil
+	}
+
+	if name != "" {
+		for _, app := range ws.apps {
+			if app.name == name {
+				return app.dir, nil
+			}
+		}
+		return "", fmt.Errorf("no app %q in the workspace at %s; apps: %s", name, ws.root, ws.names())
+	}
+
+	var found *workspaceApp
+	for i, app := range ws.apps {
+		rel, err := filepath.Rel(app.dir, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		// Prefer the innermost app when apps are nested.
+		if found == nil || len(app.dir) > len(found.dir) {
+			found = &ws.apps[i]
+		}
+	}
+	if found != nil {
+		return found.dir, nil
+	}
+	if len(ws.apps) == 1 {
+		return ws.apps[0].dir, nil
+	}
+	return "", fmt.Errorf("the workspace at %s has several apps (%s); choose one with --app", ws.root, ws.names())
+}
+
+func (ws *workspace) names() string {
+	names := make([]string, len(ws.apps))
+	for i, app := range ws.apps {
+		names[i] = app.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// addWorkspaceUse adds a module directory to the workspace's go.work.
+func addWorkspaceUse(ws *workspace, dir string) error {
+	rel, err := filepath.Rel(ws.root, dir)
+	if err != nil {
+		return err
+	}
+	use := "./" + filepath.ToSlash(rel)
+
+	path := filepath.Join(ws.root, "go.work")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	for _, existing := range workspaceUses(content) {
+		if filepath.Clean(existing) == filepath.Clean(use) {
+			return nil
+		}
+	}
+
+	text := string(content)
+	if i := strings.Index(text, "use ("); i >= 0 {
+		if end := strings.Index(text[i:], ")"); end >= 0 {
+			at := i + end
+			text = text[:at] + "\t" + use + "\n" + text[at:]
+			return os.WriteFile(path, []byte(text), 0644)
+		}
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	text += "\nuse " + use + "\n"
+	return os.WriteFile(path, []byte(text), 0644)
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWorkspace creates a go.work with the given content and a module in
+// each directory; modules in apps depend on the framework.
+func newWorkspace(t *testing.T, gowork string, apps []string, others ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.work"), []byte(gowork), 0644))
+	for _, dir := range apps {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir, "config"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, dir, "go.mod"), []byte("module "+dir+"\n\nrequire github.com/genesysflow/go-genesys v1.1.0\n"), 0644))
+	}
+	for _, dir := range others {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, dir, "go.mod"), []byte("module "+dir+"\n"), 0644))
+	}
+	return root
+}
+
+func TestWorkspaceUses(t *testing.T) {
+	uses := workspaceUses([]byte(`go 1.24
+
+use ./tools // shared tooling
+
+use (
+	./services/api
+	"./services/worker"
+)
+`))
+	assert.Equal(t, []string{"./tools", "./services/api", "./services/worker"}, uses)
+}
+
+func TestResolveApp(t *testing.T) {
+	root := newWorkspace(t, "go 1.24\n\nuse (\n\t./services/api\n\t./services/worker\n\t./lib\n)\n",
+		[]string{"services/api", "services/worker"}, "lib")
+
+	dir, err := resolveApp(root, "worker")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "services", "worker"), dir)
+
+	// Inside an app, the app is detected.
+	dir, err = resolveApp(filepath.Join(root, "services", "api", "config"), "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "services", "api"), dir)
+
+	_, err = resolveApp(root, "")
+	assert.ErrorContains(t, err, "several apps (api, worker)")
+
+	// Modules that don't use the framework aren't apps.
+	_, err = resolveApp(root, "lib")
+	assert.ErrorContains(t, err, `no app "lib"`)
+}
+
+func TestResolveAppSingleAppAndNoWorkspace(t *testing.T) {
+	root := newWorkspace(t, "go 1.24\n\nuse ./api\n", []string{"api"})
+	dir, err := resolveApp(root, "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "api"), dir)
+
+	plain := t.TempDir()
+	dir, err = resolveApp(plain, "")
+	require.NoError(t, err)
+	assert.Equal(t, plain, dir)
+
+	_, err = resolveApp(plain, "api")
+	assert.ErrorContains(t, err, "needs a go.work workspace")
+}
+
+func TestSplitAppFlag(t *testing.T) {
+	app, args := splitAppFlag([]string{"User", "--app=api", "--resource"})
+	assert.Equal(t, "api", app)
+	assert.Equal(t, []string{"User", "--resource"}, args)
+
+	app, args = splitAppFlag([]string{"--app", "worker", "--port=8080"})
+	assert.Equal(t, "worker", app)
+	assert.Equal(t, []string{"--port=8080"}, args)
+}
+
+func TestAddWorkspaceUse(t *testing.T) {
+	root := newWorkspace(t, "go 1.24\n\nuse (\n\t./api\n)\n", []string{"api"})
+	ws, err := findWorkspace(root)
+	require.NoError(t, err)
+
+	require.NoError(t, addWorkspaceUse(ws, filepath.Join(root, "services", "billing")))
+	require.NoError(t, addWorkspaceUse(ws, filepath.Join(root, "api")))
+
+	content, err := os.ReadFile(filepath.Join(root, "go.work"))
+	require.NoError(t, err)
+	assert.Equal(t, "go 1.24\n\nuse (\n\t./api\n\t./services/billing\n)\n", string(content))
+}
+
+func TestSplitFlags(t *testing.T) {
+	env, rest := splitFlag([]string{"--port=8080", "--env", "staging", "--watch"}, "env")
+	assert.Equal(t, "staging", env)
+	assert.Equal(t, []string{"--port=8080", "--watch"}, rest)
+
+	watch, rest := splitBoolFlag(rest, "watch")
+	assert.True(t, watch)
+	assert.Equal(t, []string{"--port=8080"}, rest)
+
+	watch, _ = splitBoolFlag([]string{"--watch=false"}, "watch")
+	assert.False(t, watch)
+}
@@ -24,6 +24,10 @@ Inspired by Laravel's elegant syntax and powerful features.`,
 	// Add commands
 	rootCmd.AddCommand(commands.NewCmd())
 	rootCmd.AddCommand(commands.UpgradeCmd())
+	rootCmd.AddCommand(commands.SelfUpdateCmd())
+	rootCmd.AddCommand(commands.DoctorCmd())
+	rootCmd.AddCommand(commands.KeyGenerateCmd())
+	rootCmd.AddCommand(commands.AppCmds()...)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
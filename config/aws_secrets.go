@@ -0,0 +1,159 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// AWSSecretsManagerConfig configures an AWSSecretsManagerProvider.
+type AWSSecretsManagerConfig struct {
+	// Key and Secret are static credentials. Without them, credentials come
+	// from the environment, shared config files or the instance role.
+	Key    string
+	Secret string
+	Token  string
+
+	Region string // defaults to the region of the AWS config, then us-east-1
+
+	// Endpoint overrides the regional API endpoint, such as for LocalStack.
+	Endpoint string
+}
+
+// AWSSecretsManagerProvider resolves ${aws-sm:name#key} references with
+// AWS Secrets Manager. The name may be a secret name or ARN. Without a key
+// the secret string is used as it is; with one, the secret must hold a
+// JSON object and the key's value is used.
+type AWSSecretsManagerProvider struct {
+	config      AWSSecretsManagerConfig
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	client      *http.Client
+	mu          sync.Mutex
+}
+
+// NewAWSSecretsManagerProvider creates a new AWS Secrets Manager secret
+// provider. Default credentials are only looked up when the first secret
+// is resolved.
+func NewAWSSecretsManagerProvider(config AWSSecretsManagerConfig) *AWSSecretsManagerProvider {
+	return &AWSSecretsManagerProvider{
+		config: config,
+		signer: v4.NewSigner(),
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Resolve fetches the current version of the secret.
+func (p *AWSSecretsManagerProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	name, key, _ := strings.Cut(ref, "#")
+	if name == "" {
+		return "", errors.New("aws-sm: secret name is empty")
+	}
+	if err := p.init(ctx); err != nil {
+		return "", err
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": name})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(p.config.Endpoint, "/")+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	creds, err := p.credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("aws-sm: failed to retrieve aws credentials: %w", err)
+	}
+	hash := sha256.Sum256(body)
+	if err := p.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "secretsmanager", p.config.Region, time.Now()); err != nil {
+		return "", fmt.Errorf("aws-sm: failed to sign request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("aws-sm: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("aws-sm: returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	var result struct {
+		SecretString *string `json:"SecretString"`
+		SecretBinary []byte  `json:"SecretBinary"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("aws-sm: invalid response: %w", err)
+	}
+	secret := string(result.SecretBinary)
+	if result.SecretString != nil {
+		secret = *result.SecretString
+	}
+	if key == "" {
+		return secret, nil
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(secret), &data); err != nil {
+		return "", fmt.Errorf("aws-sm: secret '%s' is not a JSON object, so it has no key '%s'", name, key)
+	}
+	value, err := secretField(data, key, name)
+	if err != nil {
+		return "", fmt.Errorf("aws-sm: %w", err)
+	}
+	return value, nil
+}
+
+// init sets up the region, endpoint and credentials on first use.
+func (p *AWSSecretsManagerProvider) init(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.credentials != nil {
+		return nil
+	}
+
+	var provider aws.CredentialsProvider
+	if p.config.Key != "" {
+		provider = credentials.NewStaticCredentialsProvider(p.config.Key, p.config.Secret, p.config.Token)
+	} else {
+		var options []func(*awsconfig.LoadOptions) error
+		if p.config.Region != "" {
+			options = append(options, awsconfig.WithRegion(p.config.Region))
+		}
+		cfg, err := awsconfig.LoadDefaultConfig(ctx, options...)
+		if err != nil {
+			return fmt.Errorf("aws-sm: failed to load aws config: %w", err)
+		}
+		if p.config.Region == "" {
+			p.config.Region = cfg.Region
+		}
+		provider = cfg.Credentials
+	}
+	if p.config.Region == "" {
+		p.config.Region = "us-east-1"
+	}
+	if p.config.Endpoint == "" {
+		p.config.Endpoint = "https://secretsmanager." + p.config.Region + ".amazonaws.com"
+	}
+	p.credentials = aws.NewCredentialsCache(provider)
+	return nil
+}
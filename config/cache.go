@@ -0,0 +1,82 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// CachePath returns the path of the config cache under an app's storage
+// directory.
+func CachePath(storagePath string) string {
+	return filepath.Join(storagePath, "framework", "config.json")
+}
+
+// WriteCache writes the loaded configuration, with environment variables
+// already interpolated, to path as a single file that LoadCache reads
+// back. Secrets are written as resolved, so a cache meant to keep them out
+// should be written from a config without secret providers; LoadCache
+// resolves the references left in it.
+func (c *Config) WriteCache(path string) error {
+	data, err := json.MarshalIndent(c.All(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("config: failed to encode cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("config: failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("config: failed to write cache '%s': %w", path, err)
+	}
+	return nil
+}
+
+// LoadCache replaces the configuration with a cache written by WriteCache.
+func (c *Config) LoadCache(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: failed to read cache '%s': %w", path, err)
+	}
+
+	var parsed map[string]any
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return fmt.Errorf("config: invalid cache '%s': %w", path, err)
+	}
+	normalizeNumbers(parsed)
+	if _, err := c.resolveSecrets(parsed); err != nil {
+		return err
+	}
+
+	c.setSource(&source{path: path, cache: true})
+	c.change(func() {
+		c.data = parsed
+		c.overrides = make(map[string]any)
+	})
+	return nil
+}
+
+// normalizeNumbers turns whole JSON numbers back into ints, as they were
+// when read from YAML.
+func normalizeNumbers(data map[string]any) {
+	for key, value := range data {
+		data[key] = normalizeNumber(value)
+	}
+}
+
+func normalizeNumber(value any) any {
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v <= math.MaxInt64 {
+			return int(v)
+		}
+	case map[string]any:
+		normalizeNumbers(v)
+	case []any:
+		for i := range v {
+			v[i] = normalizeNumber(v[i])
+		}
+	}
+	return value
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.yaml"), []byte(`
+name: ${CACHE_TEST_NAME:-fallback}
+port: 8080
+ratio: 0.5
+hosts:
+  - a
+  - b
+limits:
+  burst: 10
+`), 0644))
+	t.Setenv("CACHE_TEST_NAME", "cached-app")
+
+	cfg := New()
+	require.NoError(t, cfg.Load(dir))
+
+	path := CachePath(filepath.Join(dir, "storage"))
+	assert.Equal(t, filepath.Join(dir, "storage", "framework", "config.json"), path)
+	require.NoError(t, cfg.WriteCache(path))
+
+	// The cache keeps the values interpolated when it was written.
+	t.Setenv("CACHE_TEST_NAME", "changed")
+	cached := New()
+	cached.Set("stale", true)
+	require.NoError(t, cached.LoadCache(path))
+
+	assert.Equal(t, "cached-app", cached.GetString("app.name"))
+	assert.Equal(t, 8080, cached.Get("app.port"), "whole numbers stay ints")
+	assert.Equal(t, 0.5, cached.GetFloat("app.ratio"))
+	assert.Equal(t, []string{"a", "b"}, cached.GetStringSlice("app.hosts"))
+	assert.Equal(t, 10, cached.Get("app.limits.burst"))
+	assert.False(t, cached.Has("stale"), "the cache replaces what was loaded")
+}
+
+func TestLoadCacheInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0644))
+
+	assert.ErrorContains(t, New().LoadCache(path), "invalid cache")
+	assert.Error(t, New().LoadCache(filepath.Join(t.TempDir(), "missing.json")))
+}
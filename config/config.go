@@ -18,33 +18,81 @@ import (
 
 // Config holds the application configuration.
 type Config struct {
-	data map[string]any
-	mu   sync.RWMutex
+	data      map[string]any
+	overrides map[string]any // values from Set and Merge, kept across Reload
+	source    *source        // what was last loaded, for Reload
+	secrets   *secrets
+	watchers  []*watcher
+	mu        sync.RWMutex
 }
 
 // New creates a new Config instance.
 func New() *Config {
 	return &Config{
-		data: make(map[string]any),
+		data:      make(map[string]any),
+		overrides: make(map[string]any),
+		secrets: &secrets{
+			providers: make(map[string]SecretProvider),
+			cache:     make(map[string]string),
+		},
 	}
 }
 
 // Load loads configuration from a file or directory.
-// If path is a directory, it loads all .yaml, .yml, and .json files.
+// If path is a directory, it loads all .yaml, .yml, and .json files, with
+// the overrides for the environment named by APP_ENV ("local" if unset);
+// see LoadEnvironment.
 func (c *Config) Load(path string) error {
+	return c.LoadEnvironment(path, env.Get("APP_ENV", "local"))
+}
+
+// LoadEnvironment loads configuration from a file or directory for an
+// environment. Each file in a directory is loaded under its name without
+// the extension, and files for the same key are merged in this order, later
+// values replacing earlier ones:
+//
+//  1. config/app.yaml                     shared config
+//  2. config/{environment}/app.yaml       overrides for the environment
+//  3. config/app.local.yaml               developer overrides, kept out of VCS
+//  4. config/{environment}/app.local.yaml developer overrides for the environment
+//
+// Within each step files are read in name order, so app.json comes before
+// app.yaml. Maps are merged key by key; other values, lists included, are
+// replaced.
+func (c *Config) LoadEnvironment(path, environment string) error {
 	info, err := os.Stat(path)
 	if err != nil {
 		return fmt.Errorf("config: failed to stat path '%s': %w", path, err)
 	}
 
-	if info.IsDir() {
-		return c.loadDir(path)
+	c.setSource(&source{path: path, environment: environment})
+	if !info.IsDir() {
+		return c.loadFile(path, "")
+	}
+
+	envDir := ""
+	if environment != "" {
+		if info, err := os.Stat(filepath.Join(path, environment)); err == nil && info.IsDir() {
+			envDir = filepath.Join(path, environment)
+		}
+	}
+
+	for _, local := range []bool{false, true} {
+		if err := c.loadDir(path, local); err != nil {
+			return err
+		}
+		if envDir != "" {
+			if err := c.loadDir(envDir, local); err != nil {
+				return err
+			}
+		}
 	}
-	return c.loadFile(path, "")
+	return nil
 }
 
-// loadDir loads all config files from a directory.
-func (c *Config) loadDir(dir string) error {
+// loadDir loads the config files in a directory, either the shared ones
+// or only the .local ones.
+func (c *Config) loadDir(dir string, local bool) error {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return fmt.Errorf("config: failed to read directory '%s': %w", dir, err)
@@ -62,7 +110,11 @@ func (c *Config) loadDir(dir string) error {
 		}
 
 		// Use filename without extension as the config key
-		key := strings.TrimSuffix(name, ext)
+		key := strings.TrimSuffix(name, filepath.Ext(name))
+		key, isLocal := strings.CutSuffix(key, ".local")
+		if isLocal != local {
+			continue
+		}
 		if err := c.loadFile(filepath.Join(dir, name), key); err != nil {
 			return err
 		}
@@ -71,7 +123,8 @@ func (c *Config) loadDir(dir string) error {
 	return nil
 }
 
-// loadFile loads a single config file.
+// loadFile loads a single config file, merging it into the values already
+// loaded under key, or into the root if key is empty.
 func (c *Config) loadFile(path, key string) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -97,17 +150,21 @@ func (c *Config) loadFile(path, key string) error {
 		return fmt.Errorf("config: unsupported file format '%s'", ext)
 	}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if key == "" {
-		// Merge into root
-		for k, v := range parsed {
-			c.data[k] = v
-		}
-	} else {
-		c.data[key] = parsed
+	if parsed == nil {
+		parsed = make(map[string]any)
+	}
+	if _, err := c.resolveSecrets(parsed); err != nil {
+		return err
 	}
+	c.change(func() {
+		if key == "" {
+			mergeMaps(c.data, parsed)
+		} else if existing, ok := c.data[key].(map[string]any); ok {
+			mergeMaps(existing, parsed)
+		} else {
+			c.data[key] = parsed
+		}
+	})
 
 	return nil
 }
@@ -306,10 +363,10 @@ func (c *Config) GetStringMap(key string) map[string]string {
 
 // Set sets a configuration value using dot notation.
 func (c *Config) Set(key string, value any) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	setNestedValue(c.data, key, value)
+	c.change(func() {
+		setNestedValue(c.data, key, value)
+		setNestedValue(c.overrides, key, value)
+	})
 }
 
 // Has checks if a configuration key exists.
@@ -328,10 +385,10 @@ func (c *Config) All() map[string]any {
 
 // Merge merges another config map into this config.
 func (c *Config) Merge(data map[string]any) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	mergeMaps(c.data, data)
+	c.change(func() {
+		mergeMaps(c.data, data)
+		mergeMaps(c.overrides, copyMap(data))
+	})
 }
 
 // getNestedValue retrieves a value from a nested map using dot notation.
@@ -431,3 +431,48 @@ func TestInvalidJSON(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to parse JSON")
 }
+
+func TestLoadEnvironmentOverrides(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	write("app.yaml", "name: shop\ndebug: false\nhosts: [a, b]\ncache:\n  driver: redis\n  ttl: 60\n")
+	write("production/app.yaml", "debug: false\nhosts: [c]\ncache:\n  ttl: 3600\n")
+	write("local/app.yaml", "debug: true\n")
+	write("app.local.yaml", "name: my-shop\ncache:\n  driver: memory\n")
+	write("local/app.local.yml", "cache:\n  ttl: 1\n")
+	write("local/database.yaml", "host: localhost\n")
+
+	cfg := New()
+	require.NoError(t, cfg.LoadEnvironment(dir, "production"))
+	assert.Equal(t, "my-shop", cfg.GetString("app.name"), "local files apply in every environment")
+	assert.False(t, cfg.GetBool("app.debug"))
+	assert.Equal(t, []string{"c"}, cfg.GetStringSlice("app.hosts"), "lists are replaced, not merged")
+	assert.Equal(t, "memory", cfg.GetString("app.cache.driver"))
+	assert.Equal(t, 3600, cfg.GetInt("app.cache.ttl"))
+	assert.False(t, cfg.Has("database"), "other environments' files are ignored")
+
+	cfg = New()
+	require.NoError(t, cfg.LoadEnvironment(dir, "local"))
+	assert.True(t, cfg.GetBool("app.debug"))
+	assert.Equal(t, []string{"a", "b"}, cfg.GetStringSlice("app.hosts"))
+	assert.Equal(t, 1, cfg.GetInt("app.cache.ttl"), "environment local files come last")
+	assert.Equal(t, "localhost", cfg.GetString("database.host"))
+	assert.False(t, cfg.Has("app.local"), "local files merge into their base key")
+}
+
+func TestLoadUsesAppEnv(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "testing"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.yaml"), []byte("name: base\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "testing", "app.yaml"), []byte("name: testing\n"), 0644))
+
+	t.Setenv("APP_ENV", "testing")
+	cfg := New()
+	require.NoError(t, cfg.Load(dir))
+	assert.Equal(t, "testing", cfg.GetString("app.name"))
+}
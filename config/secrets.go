@@ -0,0 +1,160 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// SecretProvider resolves secret references in config values. A value such
+// as ${vault:secret/data/app#password} is passed to the provider registered
+// for the "vault" scheme as "secret/data/app#password".
+type SecretProvider interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// SecretProviderFunc adapts a function to a SecretProvider.
+type SecretProviderFunc func(ctx context.Context, ref string) (string, error)
+
+// Resolve calls f.
+func (f SecretProviderFunc) Resolve(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+// secretRegex matches ${scheme:ref}. Schemes are lower case, so environment
+// references such as ${DB_HOST:-localhost} never match.
+var secretRegex = regexp.MustCompile(`\$\{([a-z][a-z0-9-]*):([^}]+)\}`)
+
+// secrets holds the secret providers and resolved values of a Config. It is
+// shared with the configs Reload loads into, so reloading files doesn't
+// fetch every secret again.
+type secrets struct {
+	providers map[string]SecretProvider
+	cache     map[string]string
+	mu        sync.Mutex
+}
+
+// AddSecretProvider registers a provider for references with the given
+// scheme, such as "vault" for ${vault:...}. Providers must be added before
+// the config is loaded; references to schemes without a provider are left
+// as they are.
+func (c *Config) AddSecretProvider(scheme string, provider SecretProvider) {
+	c.secrets.mu.Lock()
+	defer c.secrets.mu.Unlock()
+	c.secrets.providers[scheme] = provider
+}
+
+// RefreshSecrets forgets the resolved secrets and reloads the config, so
+// every reference is resolved again. Watchers of keys whose secrets
+// changed are notified, which is how rotated credentials reach the code
+// using them.
+func (c *Config) RefreshSecrets() error {
+	c.secrets.mu.Lock()
+	cache := c.secrets.cache
+	c.secrets.cache = make(map[string]string)
+	c.secrets.mu.Unlock()
+
+	if err := c.Reload(); err != nil {
+		// Keep the values the loaded config was resolved with.
+		c.secrets.mu.Lock()
+		c.secrets.cache = cache
+		c.secrets.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// WatchSecrets calls RefreshSecrets every interval until ctx is done.
+// Errors are passed to onError, which may be nil. It returns at once; the
+// refreshes run in the background.
+func (c *Config) WatchSecrets(ctx context.Context, interval time.Duration, onError func(error)) {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := c.RefreshSecrets(); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// resolveSecrets replaces the secret references in the strings of value.
+func (c *Config) resolveSecrets(value any) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			resolved, err := c.resolveSecrets(item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = resolved
+		}
+	case []any:
+		for i, item := range v {
+			resolved, err := c.resolveSecrets(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+	case string:
+		var resolveErr error
+		resolved := secretRegex.ReplaceAllStringFunc(v, func(match string) string {
+			if resolveErr != nil {
+				return match
+			}
+			matches := secretRegex.FindStringSubmatch(match)
+			secret, ok, err := c.secret(matches[1], matches[2])
+			if err != nil {
+				resolveErr = fmt.Errorf("config: failed to resolve secret '%s': %w", match, err)
+				return match
+			}
+			if !ok {
+				return match
+			}
+			return secret
+		})
+		if resolveErr != nil {
+			return nil, resolveErr
+		}
+		return resolved, nil
+	}
+	return value, nil
+}
+
+// secret returns the value of a reference from the cache or its provider.
+// It reports false if no provider is registered for the scheme.
+func (c *Config) secret(scheme, ref string) (string, bool, error) {
+	c.secrets.mu.Lock()
+	provider, ok := c.secrets.providers[scheme]
+	cached, hit := c.secrets.cache[scheme+":"+ref]
+	c.secrets.mu.Unlock()
+	if !ok {
+		return "", false, nil
+	}
+	if hit {
+		return cached, true, nil
+	}
+
+	value, err := provider.Resolve(context.Background(), ref)
+	if err != nil {
+		return "", true, err
+	}
+
+	c.secrets.mu.Lock()
+	c.secrets.cache[scheme+":"+ref] = value
+	c.secrets.mu.Unlock()
+	return value, true, nil
+}
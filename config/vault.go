@@ -0,0 +1,135 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/genesysflow/go-genesys/env"
+)
+
+// VaultConfig configures a VaultProvider.
+type VaultConfig struct {
+	Address   string // defaults to VAULT_ADDR, then http://127.0.0.1:8200
+	Token     string // defaults to VAULT_TOKEN
+	Namespace string // defaults to VAULT_NAMESPACE
+}
+
+// VaultProvider resolves ${vault:path#field} references by reading secrets
+// from HashiCorp Vault's HTTP API. The path is the API path without /v1/,
+// so secrets in a KV version 2 engine are read from mount/data/name. The
+// field names the key within the secret and may be left out when the
+// secret has a single key.
+type VaultProvider struct {
+	config VaultConfig
+	client *http.Client
+}
+
+// NewVaultProvider creates a new Vault secret provider.
+func NewVaultProvider(config VaultConfig) *VaultProvider {
+	if config.Address == "" {
+		config.Address = env.Get("VAULT_ADDR", "http://127.0.0.1:8200")
+	}
+	if config.Token == "" {
+		config.Token = env.Get("VAULT_TOKEN", "")
+	}
+	if config.Namespace == "" {
+		config.Namespace = env.Get("VAULT_NAMESPACE", "")
+	}
+	return &VaultProvider{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Resolve reads the secret at path and returns the value of field.
+func (p *VaultProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	path, field, _ := strings.Cut(ref, "#")
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return "", errors.New("vault: secret path is empty")
+	}
+
+	endpoint := strings.TrimRight(p.config.Address, "/") + "/v1/" + (&url.URL{Path: path}).EscapedPath()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	if p.config.Token != "" {
+		req.Header.Set("X-Vault-Token", p.config.Token)
+	}
+	if p.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.config.Namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("vault: secret '%s' not found", path)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("vault: returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("vault: invalid response: %w", err)
+	}
+
+	data := body.Data
+	// KV version 2 nests the secret under data, next to its metadata.
+	if nested, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+	value, err := secretField(data, field, path)
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	return value, nil
+}
+
+// secretField returns the field of a secret holding several values, or its
+// only value when field is empty.
+func secretField(data map[string]any, field, name string) (string, error) {
+	if field == "" {
+		if len(data) != 1 {
+			keys := make([]string, 0, len(data))
+			for key := range data {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			return "", fmt.Errorf("secret '%s' has keys %s; name one with #key", name, strings.Join(keys, ", "))
+		}
+		for key := range data {
+			field = key
+		}
+	}
+
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("secret '%s' has no key '%s'", name, field)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
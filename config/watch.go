@@ -0,0 +1,202 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// source is what a Config was last loaded from.
+type source struct {
+	path        string
+	environment string
+	cache       bool
+}
+
+// watcher is a subscriber to changes of a key.
+type watcher struct {
+	key string
+	fn  func(value any)
+}
+
+// Watch calls fn with the new value of key whenever it changes, through
+// Set, Merge, loading or Reload. The key may name a section, such as
+// "logging", which changes when any value in it does; an empty key watches
+// the whole configuration. fn runs after the change, outside the lock, so
+// it may read the config. Call the returned function to stop watching.
+func (c *Config) Watch(key string, fn func(value any)) func() {
+	w := &watcher{key: key, fn: fn}
+
+	c.mu.Lock()
+	c.watchers = append(c.watchers, w)
+	c.mu.Unlock()
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for i, existing := range c.watchers {
+			if existing == w {
+				c.watchers = append(c.watchers[:i:i], c.watchers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Reload reads the files or cache the config was last loaded from again,
+// replacing the loaded values, and notifies watchers of what changed.
+// Values from Set and Merge are applied again over the files. If reading
+// fails, the config is left as it was.
+func (c *Config) Reload() error {
+	c.mu.RLock()
+	src := c.source
+	c.mu.RUnlock()
+	if src == nil {
+		return errors.New("config: nothing was loaded to reload")
+	}
+
+	fresh := New()
+	fresh.secrets = c.secrets
+	var err error
+	if src.cache {
+		err = fresh.LoadCache(src.path)
+	} else {
+		err = fresh.LoadEnvironment(src.path, src.environment)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.change(func() {
+		c.data = fresh.data
+		mergeMaps(c.data, copyMap(c.overrides))
+	})
+	return nil
+}
+
+// WatchFiles checks the loaded config files for changes every interval and
+// reloads them when one changes, until ctx is done. Reload errors are
+// passed to onError, which may be nil; the config then keeps its values
+// until the files are fixed. It returns at once; the checks run in the
+// background.
+func (c *Config) WatchFiles(ctx context.Context, interval time.Duration, onError func(error)) {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	stamps := c.sourceStamps()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				current := c.sourceStamps()
+				if reflect.DeepEqual(stamps, current) {
+					continue
+				}
+				stamps = current
+				if err := c.Reload(); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// sourceStamps returns the modification time and size of each config file
+// the config was loaded from.
+func (c *Config) sourceStamps() map[string][2]int64 {
+	c.mu.RLock()
+	src := c.source
+	c.mu.RUnlock()
+
+	stamps := make(map[string][2]int64)
+	if src == nil {
+		return stamps
+	}
+	filepath.WalkDir(src.path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		if info, err := os.Stat(path); err == nil {
+			stamps[path] = [2]int64{info.ModTime().UnixNano(), info.Size()}
+		}
+		return nil
+	})
+	return stamps
+}
+
+// setSource records what the config was loaded from.
+func (c *Config) setSource(src *source) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.source = src
+}
+
+// change applies a change to the config under the lock, then calls the
+// watchers of the keys whose values it changed.
+func (c *Config) change(apply func()) {
+	c.mu.Lock()
+	if len(c.watchers) == 0 {
+		apply()
+		c.mu.Unlock()
+		return
+	}
+
+	watchers := append([]*watcher(nil), c.watchers...)
+	before := make([]any, len(watchers))
+	for i, w := range watchers {
+		before[i] = copyValue(c.lookup(w.key))
+	}
+	apply()
+	type notification struct {
+		fn    func(any)
+		value any
+	}
+	var changed []notification
+	for i, w := range watchers {
+		if after := c.lookup(w.key); !reflect.DeepEqual(before[i], after) {
+			changed = append(changed, notification{fn: w.fn, value: copyValue(after)})
+		}
+	}
+	c.mu.Unlock()
+
+	for _, n := range changed {
+		n.fn(n.value)
+	}
+}
+
+// lookup returns the value of key, or all values for an empty key.
+// Callers hold the lock.
+func (c *Config) lookup(key string) any {
+	if key == "" {
+		return c.data
+	}
+	return getNestedValue(c.data, key)
+}
+
+// copyValue returns a deep copy of maps and slices, so values handed to
+// watchers don't change under them.
+func copyValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return copyMap(v)
+	case []any:
+		return copySlice(v)
+	default:
+		return value
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	cfg := New()
+	cfg.Set("logging.level", "info")
+	cfg.Set("features.beta", false)
+
+	var levels []any
+	stop := cfg.Watch("logging.level", func(value any) { levels = append(levels, value) })
+	var sections int
+	cfg.Watch("features", func(value any) {
+		sections++
+		assert.Equal(t, map[string]any{"beta": true}, value)
+	})
+
+	cfg.Set("logging.level", "debug")
+	cfg.Set("logging.level", "debug") // unchanged, no call
+	cfg.Set("features.beta", true)
+	cfg.Merge(map[string]any{"logging": map[string]any{"level": "warn"}})
+	stop()
+	cfg.Set("logging.level", "error")
+
+	assert.Equal(t, []any{"debug", "warn"}, levels)
+	assert.Equal(t, 1, sections)
+}
+
+func TestWatchCanReadConfig(t *testing.T) {
+	cfg := New()
+	done := make(chan any, 1)
+	cfg.Watch("app.name", func(value any) {
+		done <- cfg.Get("app.name") // must not deadlock
+	})
+	cfg.Set("app.name", "shop")
+	assert.Equal(t, "shop", <-done)
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("name: shop\nlevel: info\n"), 0644))
+
+	cfg := New()
+	assert.Error(t, cfg.Reload(), "nothing loaded yet")
+	require.NoError(t, cfg.LoadEnvironment(dir, "local"))
+	cfg.Set("app.name", "overridden")
+
+	var level any
+	cfg.Watch("app.level", func(value any) { level = value })
+
+	require.NoError(t, os.WriteFile(path, []byte("name: shop\nlevel: debug\n"), 0644))
+	require.NoError(t, cfg.Reload())
+	assert.Equal(t, "debug", level)
+	assert.Equal(t, "overridden", cfg.GetString("app.name"), "Set values survive reloads")
+
+	// A broken file leaves the config as it was.
+	require.NoError(t, os.WriteFile(path, []byte("level: [\n"), 0644))
+	assert.Error(t, cfg.Reload())
+	assert.Equal(t, "debug", cfg.GetString("app.level"))
+}
+
+func TestWatchFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "features.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("beta: false\n"), 0644))
+
+	cfg := New()
+	require.NoError(t, cfg.LoadEnvironment(dir, "local"))
+
+	var mu sync.Mutex
+	var values []any
+	var errs []error
+	cfg.Watch("features.beta", func(value any) {
+		mu.Lock()
+		defer mu.Unlock()
+		values = append(values, value)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg.WatchFiles(ctx, 10*time.Millisecond, func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	})
+
+	require.NoError(t, os.WriteFile(path, []byte("beta: true # enabled\n"), 0644))
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(values) == 1 && values[0] == true
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(path, []byte("beta: [\n"), 0644))
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(errs) > 0
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.True(t, cfg.GetBool("features.beta"))
+
+	mu.Lock()
+	assert.False(t, errors.Is(errs[0], context.Canceled))
+	mu.Unlock()
+}
@@ -0,0 +1,177 @@
+package console
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Command is a console command defined by the application.
+//
+//	kernel.Register(console.Command{
+//		Name:        "users:import",
+//		Description: "Import users from a CSV file",
+//		Arguments:   []console.Argument{{Name: "file", Required: true}},
+//		Options:     []console.Option{{Name: "dry-run", Default: false}},
+//		Handle: func(io *console.IO) error {
+//			io.Info("Importing %s", io.Argument("file"))
+//			return nil
+//		},
+//	})
+type Command struct {
+	// Name is the command name, such as "users:import".
+	Name string
+
+	// Description is the one-line description shown in the command list.
+	Description string
+
+	// Help is the longer description shown by --help.
+	Help string
+
+	// Aliases are alternative names for the command.
+	Aliases []string
+
+	// Arguments are the positional arguments, in order. Required arguments
+	// must come before optional ones, and only the last can be variadic.
+	Arguments []Argument
+
+	// Options are the flags the command accepts.
+	Options []Option
+
+	// Handle runs the command.
+	Handle func(io *IO) error
+}
+
+// Argument is a positional argument of a command.
+type Argument struct {
+	Name        string
+	Description string
+	Required    bool
+
+	// Variadic collects the remaining arguments; read them with
+	// IO.Arguments.
+	Variadic bool
+
+	// Default is the value of an optional argument that isn't given.
+	Default string
+}
+
+// Option is a flag of a command. Its type follows Default: bool, int,
+// []string or, when nil or a string, string.
+type Option struct {
+	Name        string
+	Shorthand   string
+	Description string
+	Default     any
+}
+
+// Register adds commands to the kernel.
+func (k *Kernel) Register(commands ...Command) error {
+	for _, command := range commands {
+		cmd, err := k.cobraCommand(command)
+		if err != nil {
+			return err
+		}
+		k.rootCmd.AddCommand(cmd)
+	}
+	return nil
+}
+
+// cobraCommand builds the cobra command running command.
+func (k *Kernel) cobraCommand(command Command) (*cobra.Command, error) {
+	if command.Name == "" {
+		return nil, fmt.Errorf("console: command has no name")
+	}
+	if command.Handle == nil {
+		return nil, fmt.Errorf("console: command [%s] has no handler", command.Name)
+	}
+
+	use := []string{command.Name}
+	required := 0
+	for i, arg := range command.Arguments {
+		if arg.Variadic && i != len(command.Arguments)-1 {
+			return nil, fmt.Errorf("console: variadic argument [%s] of [%s] must be last", arg.Name, command.Name)
+		}
+		if arg.Required {
+			if required != i {
+				return nil, fmt.Errorf("console: required argument [%s] of [%s] follows an optional one", arg.Name, command.Name)
+			}
+			required++
+		}
+		use = append(use, argumentUsage(arg))
+	}
+
+	cmd := &cobra.Command{
+		Use:     strings.Join(use, " "),
+		Short:   command.Description,
+		Long:    command.Help,
+		Aliases: command.Aliases,
+		Args:    argumentsValidator(command.Arguments, required),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return command.Handle(newIO(k.app, cmd, command.Arguments, args))
+		},
+	}
+	if cmd.Long == "" {
+		cmd.Long = describeArguments(command)
+	} else if described := describeArguments(command); described != "" {
+		cmd.Long += "\n\n" + described
+	}
+
+	flags := cmd.Flags()
+	for _, option := range command.Options {
+		switch value := option.Default.(type) {
+		case nil:
+			flags.StringP(option.Name, option.Shorthand, "", option.Description)
+		case string:
+			flags.StringP(option.Name, option.Shorthand, value, option.Description)
+		case bool:
+			flags.BoolP(option.Name, option.Shorthand, value, option.Description)
+		case int:
+			flags.IntP(option.Name, option.Shorthand, value, option.Description)
+		case []string:
+			flags.StringSliceP(option.Name, option.Shorthand, value, option.Description)
+		default:
+			return nil, fmt.Errorf("console: option [%s] of [%s] has unsupported type %T", option.Name, command.Name, value)
+		}
+	}
+	return cmd, nil
+}
+
+func argumentUsage(arg Argument) string {
+	name := arg.Name
+	if arg.Variadic {
+		name += "..."
+	}
+	if arg.Required {
+		return "<" + name + ">"
+	}
+	return "[" + name + "]"
+}
+
+// describeArguments lists the arguments with descriptions for --help.
+func describeArguments(command Command) string {
+	var lines []string
+	for _, arg := range command.Arguments {
+		if arg.Description == "" {
+			continue
+		}
+		line := fmt.Sprintf("  %-16s %s", arg.Name, arg.Description)
+		if arg.Default != "" {
+			line += fmt.Sprintf(" (default %q)", arg.Default)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "Arguments:\n" + strings.Join(lines, "\n")
+}
+
+// argumentsValidator checks the number of arguments given.
+func argumentsValidator(arguments []Argument, required int) cobra.PositionalArgs {
+	if len(arguments) > 0 && arguments[len(arguments)-1].Variadic {
+		return cobra.MinimumNArgs(required)
+	}
+	return cobra.RangeArgs(required, len(arguments))
+}
@@ -0,0 +1,102 @@
+package console
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKernelRegister(t *testing.T) {
+	kernel := NewKernel(testutil.NewMockApplication())
+	var got struct {
+		file, format string
+		tags         []string
+		dryRun       bool
+		limit        int
+		only         []string
+		hasLimit     bool
+	}
+	err := kernel.Register(Command{
+		Name:        "users:import",
+		Description: "Import users",
+		Arguments: []Argument{
+			{Name: "file", Description: "CSV file to import", Required: true},
+			{Name: "format", Default: "csv"},
+			{Name: "tags", Variadic: true},
+		},
+		Options: []Option{
+			{Name: "dry-run", Default: false},
+			{Name: "limit", Shorthand: "l", Default: 100},
+			{Name: "only", Default: []string{}},
+			{Name: "queue"},
+		},
+		Handle: func(io *IO) error {
+			got.file = io.Argument("file")
+			got.format = io.Argument("format")
+			got.tags = io.Arguments("tags")
+			got.dryRun = io.OptionBool("dry-run")
+			got.limit = io.OptionInt("limit")
+			got.only = io.OptionStrings("only")
+			got.hasLimit = io.HasOption("limit")
+			io.Info("Imported %s", io.Argument("file"))
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	kernel.RootCommand().SetOut(&out)
+	require.NoError(t, kernel.Handle([]string{"users:import", "users.csv", "--dry-run", "--only", "a,b"}))
+	assert.Equal(t, "users.csv", got.file)
+	assert.Equal(t, "csv", got.format)
+	assert.Empty(t, got.tags)
+	assert.True(t, got.dryRun)
+	assert.Equal(t, 100, got.limit)
+	assert.False(t, got.hasLimit)
+	assert.Equal(t, []string{"a", "b"}, got.only)
+	assert.Equal(t, "Imported users.csv\n", out.String())
+
+	require.NoError(t, kernel.Handle([]string{"users:import", "users.json", "json", "new", "vip", "-l", "5"}))
+	assert.Equal(t, "json", got.format)
+	assert.Equal(t, []string{"new", "vip"}, got.tags)
+	assert.Equal(t, 5, got.limit)
+	assert.True(t, got.hasLimit)
+
+	out.Reset()
+	require.NoError(t, kernel.Handle([]string{"users:import", "--help"}))
+	assert.Contains(t, out.String(), "users:import <file> [format] [tags...]")
+	assert.True(t, strings.Contains(out.String(), "CSV file to import"))
+}
+
+func TestKernelRegisterMissingArgument(t *testing.T) {
+	kernel := NewKernel(testutil.NewMockApplication())
+	require.NoError(t, kernel.Register(Command{
+		Name:      "users:import",
+		Arguments: []Argument{{Name: "file", Required: true}, {Name: "tags", Variadic: true}},
+		Handle:    func(*IO) error { return nil },
+	}))
+	kernel.RootCommand().SetOut(&bytes.Buffer{})
+	kernel.RootCommand().SetErr(&bytes.Buffer{})
+	assert.ErrorContains(t, kernel.Handle([]string{"users:import"}), "requires at least 1 arg")
+}
+
+func TestKernelRegisterInvalid(t *testing.T) {
+	kernel := NewKernel(testutil.NewMockApplication())
+	handle := func(*IO) error { return nil }
+
+	assert.ErrorContains(t, kernel.Register(Command{Handle: handle}), "no name")
+	assert.ErrorContains(t, kernel.Register(Command{Name: "a"}), "no handler")
+	assert.ErrorContains(t, kernel.Register(Command{Name: "b", Handle: handle, Arguments: []Argument{
+		{Name: "rest", Variadic: true}, {Name: "last"},
+	}}), "must be last")
+	assert.ErrorContains(t, kernel.Register(Command{Name: "c", Handle: handle, Arguments: []Argument{
+		{Name: "optional"}, {Name: "required", Required: true},
+	}}), "follows an optional one")
+	assert.ErrorContains(t, kernel.Register(Command{Name: "d", Handle: handle, Options: []Option{
+		{Name: "ratio", Default: 0.5},
+	}}), "unsupported type float64")
+}
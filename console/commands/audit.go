@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/spf13/cobra"
+)
+
+// AuditTableCommand creates the audit:table command.
+func AuditTableCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "audit:table",
+		Short: "Create a migration for the audit log database table",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			table := app.GetConfig().GetString("audit.table")
+			if table == "" {
+				table = "audit_logs"
+			}
+			return createMigrationFrom(app, "create_"+table+"_table", "audit_migration.go.tmpl", map[string]string{
+				"Table": table,
+			})
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/spf13/cobra"
+)
+
+// CacheWarmCommand creates the cache:warm command.
+func CacheWarmCommand(app contracts.Application) *cobra.Command {
+	var keys []string
+	var every time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "cache:warm",
+		Short: "Refresh warmable cache keys ahead of expiry",
+		Long: `Recompute every key registered with cache.Manager.Warmable so hot keys
+are refreshed before they expire. Run it from cron, or pass --every to keep
+refreshing until the command is stopped.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.Boot(); err != nil {
+				return fmt.Errorf("failed to boot application: %w", err)
+			}
+
+			manager, err := container.Resolve[*cache.Manager](app)
+			if err != nil {
+				return fmt.Errorf("cache manager not available: %w", err)
+			}
+
+			if every <= 0 {
+				return warmCache(cmd, manager, keys)
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			ticker := time.NewTicker(every)
+			defer ticker.Stop()
+			for {
+				if err := warmCache(cmd, manager, keys); err != nil {
+					app.GetLogger().Error("Cache warm failed", "error", err.Error())
+				}
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&keys, "key", nil, "Only refresh these keys")
+	cmd.Flags().DurationVar(&every, "every", 0, "Keep refreshing at this interval until stopped")
+
+	return cmd
+}
+
+func warmCache(cmd *cobra.Command, manager *cache.Manager, keys []string) error {
+	start := time.Now()
+	if err := manager.Warm(keys...); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Cache warmed in %s.\n", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// CacheTableCommand creates the cache:table command.
+func CacheTableCommand(app contracts.Application) *cobra.Command {
+	var store string
+
+	cmd := &cobra.Command{
+		Use:   "cache:table",
+		Short: "Create a migration for the cache database table",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, _ := app.GetConfig().GetMap("cache.stores")[store].(map[string]any)
+			table, _ := settings["table"].(string)
+			if table == "" {
+				table = "cache"
+			}
+			lockTable, _ := settings["lock_table"].(string)
+			if lockTable == "" {
+				lockTable = "cache_locks"
+			}
+			return createMigrationFrom(app, "create_"+table+"_table", "cache_migration.go.tmpl", map[string]string{
+				"Table":     table,
+				"LockTable": lockTable,
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&store, "store", "database", "Store in cache.stores whose table to create")
+
+	return cmd
+}
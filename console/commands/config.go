@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/config"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigCacheCommand creates the config:cache command.
+func ConfigCacheCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "config:cache",
+		Short: "Cache the configuration for faster boot",
+		Long: `Read every file in the config directory, interpolate environment
+variables, and write the result to storage/framework/config.json. While the
+cache exists it is loaded at boot instead of the config directory, so
+changes to config files or to the environment take effect only after
+running config:cache again or config:clear.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.New()
+			if _, err := os.Stat(app.ConfigPath()); err == nil {
+				if err := cfg.LoadEnvironment(app.ConfigPath(), app.Environment()); err != nil {
+					return err
+				}
+			}
+
+			path := config.CachePath(app.StoragePath())
+			if err := cfg.WriteCache(path); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Configuration cached in %s.\n", path)
+			return nil
+		},
+	}
+}
+
+// ConfigClearCommand creates the config:clear command.
+func ConfigClearCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "config:clear",
+		Short: "Remove the configuration cache",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := config.CachePath(app.StoragePath())
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove config cache: %w", err)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Configuration cache cleared.")
+			return nil
+		},
+	}
+}
+
+// ConfigShowCommand creates the config:show command.
+func ConfigShowCommand(app contracts.Application) *cobra.Command {
+	var asJSON, reveal bool
+
+	cmd := &cobra.Command{
+		Use:   "config:show [key]",
+		Short: "Show effective configuration values",
+		Long: `Show the configuration the booted application sees, after environment
+interpolation and any values set by providers. Give a dot-notation key such
+as database.connections to show part of it. Values of keys that look like
+secrets (passwords, tokens, keys) are masked unless --reveal is given.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.Boot(); err != nil {
+				return fmt.Errorf("failed to boot application: %w", err)
+			}
+
+			cfg := app.GetConfig()
+			var value any = cfg.All()
+			if len(args) == 1 {
+				if !cfg.Has(args[0]) {
+					return fmt.Errorf("config key %q is not set", args[0])
+				}
+				value = cfg.Get(args[0])
+				if !reveal && isSecretKey(args[0]) {
+					value = maskedValue
+				}
+			}
+			if !reveal {
+				value = maskSecrets(value)
+			}
+
+			if asJSON {
+				data, err := json.MarshalIndent(value, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(data))
+				return nil
+			}
+			if _, ok := value.(map[string]any); !ok {
+				if _, ok := value.([]any); !ok {
+					fmt.Fprintln(cmd.OutOrStdout(), value)
+					return nil
+				}
+			}
+			data, err := yaml.Marshal(value)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), string(data))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the values as JSON")
+	cmd.Flags().BoolVar(&reveal, "reveal", false, "Show secret values instead of masking them")
+	return cmd
+}
+
+// maskedValue replaces secret config values in config:show.
+const maskedValue = "********"
+
+// secretKeyParts mark config keys whose values are masked.
+var secretKeyParts = []string{"password", "secret", "token", "credential", "private"}
+
+// isSecretKey reports whether a config key, or the last part of a dotted
+// one, names a secret.
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key[strings.LastIndex(key, ".")+1:])
+	if key == "key" || strings.HasSuffix(key, "_key") || strings.HasSuffix(key, "keys") {
+		return true
+	}
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskSecrets returns a copy of value with the values of secret keys
+// masked.
+func maskSecrets(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		masked := make(map[string]any, len(v))
+		for key, item := range v {
+			if _, nested := item.(map[string]any); !nested && isSecretKey(key) && item != nil && item != "" {
+				masked[key] = maskedValue
+				continue
+			}
+			masked[key] = maskSecrets(item)
+		}
+		return masked
+	case []any:
+		masked := make([]any, len(v))
+		for i, item := range v {
+			masked[i] = maskSecrets(item)
+		}
+		return masked
+	default:
+		return value
+	}
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/config"
+	"github.com/genesysflow/go-genesys/foundation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newConfigTestApp(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "config"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "app.yaml"), []byte(`
+name: Shop
+key: base64:c2VjcmV0
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "database.yaml"), []byte(`
+default: main
+connections:
+  main:
+    host: db.internal
+    password: hunter2
+`), 0644))
+	return dir
+}
+
+func TestConfigCacheAndClear(t *testing.T) {
+	dir := newConfigTestApp(t)
+	app := foundation.New(dir)
+
+	var out bytes.Buffer
+	cmd := ConfigCacheCommand(app)
+	cmd.SetOut(&out)
+	require.NoError(t, cmd.Execute())
+
+	path := config.CachePath(app.StoragePath())
+	assert.FileExists(t, path)
+	assert.Contains(t, out.String(), path)
+
+	// Booting a new app reads the cache, not the changed files.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "app.yaml"), []byte("name: Changed\n"), 0644))
+	cached := foundation.New(dir)
+	require.NoError(t, cached.Boot())
+	assert.Equal(t, "Shop", cached.GetConfig().GetString("app.name"))
+
+	cmd = ConfigClearCommand(app)
+	cmd.SetOut(&out)
+	require.NoError(t, cmd.Execute())
+	assert.NoFileExists(t, path)
+
+	fresh := foundation.New(dir)
+	require.NoError(t, fresh.Boot())
+	assert.Equal(t, "Changed", fresh.GetConfig().GetString("app.name"))
+}
+
+func TestConfigShowCommand(t *testing.T) {
+	show := func(args ...string) (string, error) {
+		app := foundation.New(newConfigTestApp(t))
+		var out bytes.Buffer
+		cmd := ConfigShowCommand(app)
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := show("app.name")
+	require.NoError(t, err)
+	assert.Equal(t, "Shop\n", out)
+
+	out, err = show("database")
+	require.NoError(t, err)
+	assert.Contains(t, out, "host: db.internal")
+	assert.Contains(t, out, "password: '********'")
+	assert.NotContains(t, out, "hunter2")
+
+	out, err = show("app.key")
+	require.NoError(t, err)
+	assert.Equal(t, "********\n", out)
+
+	out, err = show("database.connections.main", "--json", "--reveal")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"password": "hunter2"`)
+
+	_, err = show("app.missing")
+	assert.ErrorContains(t, err, `config key "app.missing" is not set`)
+}
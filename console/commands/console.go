@@ -0,0 +1,354 @@
+package commands
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/samber/do/v2"
+	"github.com/spf13/cobra"
+)
+
+// errConsoleExit ends a console session.
+var errConsoleExit = errors.New("exit")
+
+// consoleHelp lists the console commands.
+const consoleHelp = `Commands:
+  config <key>          Show a config value, such as config database.default
+  env                   Show the environment, debug mode and base path
+  services              List the services bound in the container
+  has <service>         Report whether a service is bound
+  make <service>        Resolve a service and show it
+  sql <query>           Run a query and show the rows it returns
+  exec <statement>      Run a statement and show the rows it affected
+  begin                 Start a transaction for the following sql and exec
+  commit | rollback     End the transaction
+  help                  Show this help
+  exit | quit           Leave the console (an open transaction is rolled back)`
+
+// ConsoleCommand creates the console command. It is a fixed set of
+// commands for services, config and SQL, not a Go expression evaluator.
+func ConsoleCommand(app contracts.Application) *cobra.Command {
+	var execute []string
+
+	cmd := &cobra.Command{
+		Use:   "console",
+		Short: "Inspect services and query the database of the booted application",
+		Long: `Start a service and SQL console with the application booted, to inspect its
+config and container and to query or fix data through its database
+connection. It runs the commands listed by help; it does not evaluate Go
+expressions. Statements can run inside a transaction, which is rolled back
+unless committed.
+
+With --execute, run the given commands and exit instead.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.Boot(); err != nil {
+				return fmt.Errorf("failed to boot application: %w", err)
+			}
+
+			t := &appConsole{app: app, out: cmd.OutOrStdout()}
+			defer t.close()
+
+			if len(execute) > 0 {
+				for _, line := range execute {
+					if err := t.run(line); err != nil {
+						if errors.Is(err, errConsoleExit) {
+							return nil
+						}
+						return err
+					}
+				}
+				return nil
+			}
+
+			fmt.Fprintf(t.out, "Genesys console (%s). Type help for commands.\n", app.Environment())
+			if app.IsProduction() {
+				fmt.Fprintln(t.out, "Warning: this is a production environment.")
+			}
+			return t.loop(cmd.InOrStdin())
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&execute, "execute", "e", nil, "Run a command and exit; repeat for several")
+	return cmd
+}
+
+// appConsole is an interactive console session.
+type appConsole struct {
+	app contracts.Application
+	out io.Writer
+	db  contracts.DB
+	tx  contracts.Transaction
+}
+
+// loop reads and runs commands until exit or the end of input.
+func (t *appConsole) loop(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		if t.tx != nil {
+			fmt.Fprint(t.out, "tx>>> ")
+		} else {
+			fmt.Fprint(t.out, ">>> ")
+		}
+		if !scanner.Scan() {
+			fmt.Fprintln(t.out)
+			return scanner.Err()
+		}
+		if err := t.run(scanner.Text()); err != nil {
+			if errors.Is(err, errConsoleExit) {
+				return nil
+			}
+			fmt.Fprintf(t.out, "Error: %v\n", err)
+		}
+	}
+}
+
+// run runs one command line.
+func (t *appConsole) run(line string) error {
+	line = strings.TrimSpace(line)
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch strings.ToLower(name) {
+	case "":
+		return nil
+	case "help":
+		fmt.Fprintln(t.out, consoleHelp)
+	case "exit", "quit":
+		return errConsoleExit
+	case "env":
+		fmt.Fprintf(t.out, "environment: %s\ndebug: %t\nbase path: %s\n", t.app.Environment(), t.app.IsDebug(), t.app.BasePath())
+	case "config":
+		if arg == "" {
+			return errors.New("usage: config <key>")
+		}
+		t.dump(t.app.GetConfig().Get(arg))
+	case "services":
+		return t.services()
+	case "has":
+		if arg == "" {
+			return errors.New("usage: has <service>")
+		}
+		fmt.Fprintln(t.out, t.app.Has(arg))
+	case "make":
+		if arg == "" {
+			return errors.New("usage: make <service>")
+		}
+		value, err := t.app.Make(arg)
+		if err != nil {
+			return err
+		}
+		t.dump(value)
+	case "sql":
+		return t.query(arg)
+	case "exec":
+		return t.exec(arg)
+	case "begin":
+		return t.begin()
+	case "commit":
+		return t.end(true)
+	case "rollback":
+		return t.end(false)
+	default:
+		return fmt.Errorf("unknown command %q, type help for the commands", name)
+	}
+	return nil
+}
+
+// services lists the container's services, sorted.
+func (t *appConsole) services() error {
+	scoped, ok := t.app.(interface{ Injector() *do.RootScope })
+	if !ok {
+		return errors.New("the container can't list its services")
+	}
+	var names []string
+	for _, service := range scoped.Injector().ListProvidedServices() {
+		names = append(names, service.Service)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintln(t.out, name)
+	}
+	return nil
+}
+
+// dump prints a value as indented JSON, or with %+v when it can't be
+// encoded.
+func (t *appConsole) dump(value any) {
+	if data, err := json.MarshalIndent(value, "", "  "); err == nil {
+		fmt.Fprintln(t.out, string(data))
+		return
+	}
+	fmt.Fprintf(t.out, "%+v\n", value)
+}
+
+// database resolves the database the first time it's used.
+func (t *appConsole) database() (contracts.DB, error) {
+	if t.db == nil {
+		db, err := container.Resolve[*database.Manager](t.app)
+		if err != nil {
+			return nil, fmt.Errorf("database not available: %w", err)
+		}
+		t.db = db
+	}
+	return t.db, nil
+}
+
+// query runs a query and prints its rows as a table.
+func (t *appConsole) query(query string) error {
+	if query == "" {
+		return errors.New("usage: sql <query>")
+	}
+
+	var rows *sql.Rows
+	var err error
+	if t.tx != nil {
+		rows, err = t.tx.Query(query)
+	} else {
+		db, dbErr := t.database()
+		if dbErr != nil {
+			return dbErr
+		}
+		rows, err = db.Select(query)
+	}
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(t.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(columns, "\t"))
+
+	count := 0
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+		cells := make([]string, len(values))
+		for i, value := range values {
+			cells[i] = formatCell(value)
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	w.Flush()
+	if count == 1 {
+		fmt.Fprintln(t.out, "(1 row)")
+	} else {
+		fmt.Fprintf(t.out, "(%d rows)\n", count)
+	}
+	return nil
+}
+
+// exec runs a statement and prints the rows it affected.
+func (t *appConsole) exec(statement string) error {
+	if statement == "" {
+		return errors.New("usage: exec <statement>")
+	}
+
+	var result sql.Result
+	var err error
+	if t.tx != nil {
+		result, err = t.tx.Exec(statement)
+	} else {
+		db, dbErr := t.database()
+		if dbErr != nil {
+			return dbErr
+		}
+		result, err = db.Statement(statement)
+	}
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil {
+		fmt.Fprintf(t.out, "%d rows affected\n", affected)
+	} else {
+		fmt.Fprintln(t.out, "OK")
+	}
+	return nil
+}
+
+// begin starts a transaction.
+func (t *appConsole) begin() error {
+	if t.tx != nil {
+		return errors.New("a transaction is already open")
+	}
+	db, err := t.database()
+	if err != nil {
+		return err
+	}
+	tx, err := db.BeginTransaction()
+	if err != nil {
+		return err
+	}
+	t.tx = tx
+	fmt.Fprintln(t.out, "Transaction started.")
+	return nil
+}
+
+// end commits or rolls back the open transaction.
+func (t *appConsole) end(commit bool) error {
+	if t.tx == nil {
+		return errors.New("no transaction is open")
+	}
+	tx := t.tx
+	t.tx = nil
+	if commit {
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		fmt.Fprintln(t.out, "Committed.")
+		return nil
+	}
+	if err := tx.Rollback(); err != nil {
+		return err
+	}
+	fmt.Fprintln(t.out, "Rolled back.")
+	return nil
+}
+
+// close rolls back a transaction left open.
+func (t *appConsole) close() {
+	if t.tx != nil {
+		if err := t.tx.Rollback(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to roll back: %v\n", err)
+			return
+		}
+		fmt.Fprintln(t.out, "Open transaction rolled back.")
+		t.tx = nil
+	}
+}
+
+// formatCell formats a scanned column value for display.
+func formatCell(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return string(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/genesysflow/go-genesys/foundation"
+	"github.com/genesysflow/go-genesys/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newConsoleTestApp(t *testing.T) *foundation.Application {
+	t.Helper()
+
+	dir := t.TempDir()
+	app := foundation.New(dir)
+	app.Register(&providers.DatabaseServiceProvider{Config: &database.Config{
+		Default: "default",
+		Connections: map[string]database.ConnectionConfig{
+			"default": {Driver: "sqlite", Database: filepath.Join(dir, "console.db")},
+		},
+	}})
+	return app
+}
+
+func runConsole(t *testing.T, app *foundation.Application, input string, args ...string) string {
+	t.Helper()
+
+	var out bytes.Buffer
+	cmd := ConsoleCommand(app)
+	cmd.SetOut(&out)
+	cmd.SetIn(strings.NewReader(input))
+	cmd.SetArgs(args)
+	require.NoError(t, cmd.Execute())
+	return out.String()
+}
+
+func TestConsoleCommand_Session(t *testing.T) {
+	app := newConsoleTestApp(t)
+	app.GetConfig().Set("app.name", "Shop")
+
+	out := runConsole(t, app, strings.Join([]string{
+		"config app.name",
+		"has db",
+		"exec CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)",
+		"exec INSERT INTO users (name) VALUES ('Ada'), ('Grace')",
+		"sql SELECT id, name FROM users ORDER BY id",
+		"frobnicate",
+		"exit",
+		"config app.name",
+	}, "\n"))
+
+	assert.Contains(t, out, `"Shop"`)
+	assert.Contains(t, out, "true")
+	assert.Contains(t, out, "2 rows affected")
+	assert.Contains(t, out, "1   Ada")
+	assert.Contains(t, out, "(2 rows)")
+	assert.Contains(t, out, `Error: unknown command "frobnicate"`)
+	assert.Equal(t, 1, strings.Count(out, `"Shop"`), "commands after exit must not run")
+}
+
+func TestConsoleCommand_Transactions(t *testing.T) {
+	app := newConsoleTestApp(t)
+
+	runConsole(t, app, "", "-e", "exec CREATE TABLE notes (body TEXT)")
+
+	out := runConsole(t, app, strings.Join([]string{
+		"begin",
+		"exec INSERT INTO notes VALUES ('discarded')",
+		"rollback",
+		"begin",
+		"exec INSERT INTO notes VALUES ('kept')",
+		"commit",
+		"begin",
+		"exec INSERT INTO notes VALUES ('left open')",
+	}, "\n"))
+	assert.Contains(t, out, "Rolled back.")
+	assert.Contains(t, out, "Committed.")
+	assert.Contains(t, out, "Open transaction rolled back.")
+
+	out = runConsole(t, app, "", "-e", "sql SELECT body FROM notes")
+	assert.Contains(t, out, "kept")
+	assert.NotContains(t, out, "discarded")
+	assert.NotContains(t, out, "left open")
+	assert.Contains(t, out, "(1 row)")
+}
+
+func TestConsoleCommand_ExecuteStopsOnError(t *testing.T) {
+	app := newConsoleTestApp(t)
+
+	cmd := ConsoleCommand(app)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"-e", "commit"})
+	assert.ErrorContains(t, cmd.Execute(), "no transaction is open")
+}
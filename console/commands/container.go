@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/spf13/cobra"
+)
+
+// ContainerListCommand creates the container:list command.
+func ContainerListCommand(app contracts.Application) *cobra.Command {
+	var filter string
+	var asJSON, asDOT, resolve bool
+
+	cmd := &cobra.Command{
+		Use:   "container:list",
+		Short: "List the services bound in the container",
+		Long: `Boot the application and list every service in the container with its
+lifetime, whether it has been resolved, its tags, and the services it
+resolved while being built. Filter names with --filter. --resolve resolves
+every service first, so all dependencies are known; --dot prints the
+dependency graph in Graphviz DOT format, for example:
+
+  genesys container:list --resolve --dot | dot -Tsvg > container.svg`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			lister, ok := app.(interface{ Bindings() []container.Binding })
+			if !ok {
+				return errors.New("the container can't list its services")
+			}
+			if err := app.Boot(); err != nil {
+				return fmt.Errorf("failed to boot application: %w", err)
+			}
+
+			if resolve {
+				for _, binding := range lister.Bindings() {
+					if _, err := app.Make(binding.Name); err != nil {
+						fmt.Fprintf(cmd.ErrOrStderr(), "Failed to resolve %s: %v\n", binding.Name, err)
+					}
+				}
+			}
+
+			var bindings []container.Binding
+			for _, binding := range lister.Bindings() {
+				if strings.Contains(strings.ToLower(binding.Name), strings.ToLower(filter)) {
+					bindings = append(bindings, binding)
+				}
+			}
+
+			switch {
+			case asDOT:
+				return container.WriteDOT(cmd.OutOrStdout(), bindings)
+			case asJSON:
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(bindings)
+			}
+			printBindings(cmd, bindings)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&filter, "filter", "", "Only list services whose name contains this")
+	cmd.Flags().BoolVar(&resolve, "resolve", false, "Resolve every service before listing")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the services as JSON")
+	cmd.Flags().BoolVar(&asDOT, "dot", false, "Print the dependency graph in DOT format")
+
+	return cmd
+}
+
+func printBindings(cmd *cobra.Command, bindings []container.Binding) {
+	if len(bindings) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No services found.")
+		return
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tLIFETIME\tRESOLVED\tTAGS\tDEPENDENCIES")
+	for _, binding := range bindings {
+		resolved := "no"
+		if binding.Resolved {
+			resolved = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", binding.Name, binding.Lifetime, resolved,
+			strings.Join(binding.Tags, ", "), strings.Join(binding.Dependencies, ", "))
+	}
+	w.Flush()
+	fmt.Fprintf(cmd.OutOrStdout(), "\nShowing %d services.\n", len(bindings))
+}
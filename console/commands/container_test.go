@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/foundation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reportStore struct{ Name string }
+
+type reportService struct{ Store *reportStore }
+
+func newContainerTestApp(t *testing.T) *foundation.Application {
+	t.Helper()
+
+	app := foundation.New(t.TempDir())
+	require.NoError(t, app.Singleton("reports.store", func() (*reportStore, error) {
+		return &reportStore{Name: "main"}, nil
+	}))
+	require.NoError(t, app.Bind("reports.service", func(c *container.Container) (*reportService, error) {
+		return &reportService{}, nil
+	}))
+	require.NoError(t, app.SingletonType(func() (*reportStore, error) {
+		return &reportStore{}, nil
+	}))
+	require.NoError(t, app.Singleton("reports.daily", func(store *reportStore) (*reportService, error) {
+		return &reportService{Store: store}, nil
+	}))
+	app.Tag("reports", "reports.daily")
+	return app
+}
+
+func TestContainerList(t *testing.T) {
+	app := newContainerTestApp(t)
+
+	var out bytes.Buffer
+	cmd := ContainerListCommand(app)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--filter", "reports."})
+	require.NoError(t, cmd.Execute())
+
+	assert.Contains(t, out.String(), "NAME")
+	assert.Regexp(t, `reports\.daily\s+singleton\s+no\s+reports`, out.String())
+	assert.Regexp(t, `reports\.service\s+transient\s+no`, out.String())
+	assert.Contains(t, out.String(), "Showing 3 services.")
+}
+
+func TestContainerListResolveJSON(t *testing.T) {
+	app := newContainerTestApp(t)
+
+	var out bytes.Buffer
+	cmd := ContainerListCommand(app)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--filter", "reports.daily", "--resolve", "--json"})
+	require.NoError(t, cmd.Execute())
+
+	var bindings []container.Binding
+	require.NoError(t, json.Unmarshal(out.Bytes(), &bindings))
+	require.Len(t, bindings, 1)
+	assert.True(t, bindings[0].Resolved)
+	assert.Equal(t, []string{"*github.com/genesysflow/go-genesys/console/commands.reportStore"}, bindings[0].Dependencies)
+	assert.Equal(t, []string{"reports"}, bindings[0].Tags)
+}
+
+func TestContainerListDOT(t *testing.T) {
+	app := newContainerTestApp(t)
+	_, err := app.Make("reports.daily")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	cmd := ContainerListCommand(app)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--dot", "--filter", "report"})
+	require.NoError(t, cmd.Execute())
+
+	assert.Contains(t, out.String(), "digraph container {")
+	assert.Contains(t, out.String(), `"reports.daily" -> "*github.com/genesysflow/go-genesys/console/commands.reportStore";`)
+	assert.Contains(t, out.String(), `"reports.service" [label="reports.service\ntransient", style=dashed];`)
+}
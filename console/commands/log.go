@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/log"
+	"github.com/spf13/cobra"
+)
+
+// LogClearCommand creates the log:clear command.
+func LogClearCommand(app contracts.Application) *cobra.Command {
+	var backups bool
+
+	cmd := &cobra.Command{
+		Use:   "log:clear",
+		Short: "Clear the log files in storage/logs",
+		Long: `Empty the log files in storage/logs and remove their rotated backups.
+Current files are truncated rather than removed, so a running app keeps
+writing to them and their space is freed. --backups only removes rotated
+backups.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := filepath.Join(app.StoragePath(), "logs")
+			entries, err := os.ReadDir(dir)
+			if os.IsNotExist(err) {
+				fmt.Fprintln(cmd.OutOrStdout(), "No log files found.")
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read log directory: %w", err)
+			}
+
+			var removed, truncated int
+			for _, entry := range entries {
+				name := entry.Name()
+				path := filepath.Join(dir, name)
+				switch {
+				case entry.IsDir():
+				case log.IsBackup(name):
+					if err := os.Remove(path); err != nil {
+						return fmt.Errorf("failed to remove %s: %w", name, err)
+					}
+					removed++
+				case !backups && strings.HasSuffix(name, ".log"):
+					if err := os.Truncate(path, 0); err != nil {
+						return fmt.Errorf("failed to truncate %s: %w", name, err)
+					}
+					truncated++
+				}
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Truncated %d log files and removed %d backups.\n", truncated, removed)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&backups, "backups", false, "Only remove rotated backups")
+	return cmd
+}
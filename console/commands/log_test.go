@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/foundation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogClear(t *testing.T) {
+	app := foundation.New(t.TempDir())
+	dir := filepath.Join(app.StoragePath(), "logs")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	current := filepath.Join(dir, "app.log")
+	backup := filepath.Join(dir, "app-2026-03-01T00-00-00.000.log.gz")
+	require.NoError(t, os.WriteFile(current, []byte("entry\n"), 0644))
+	require.NoError(t, os.WriteFile(backup, []byte("old"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*\n"), 0644))
+
+	var out bytes.Buffer
+	cmd := LogClearCommand(app)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--backups"})
+	require.NoError(t, cmd.Execute())
+	assert.NoFileExists(t, backup)
+	content, err := os.ReadFile(current)
+	require.NoError(t, err)
+	assert.Equal(t, "entry\n", string(content))
+
+	out.Reset()
+	cmd = LogClearCommand(app)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+	require.NoError(t, cmd.Execute())
+	content, err = os.ReadFile(current)
+	require.NoError(t, err)
+	assert.Empty(t, content)
+	assert.FileExists(t, filepath.Join(dir, ".gitignore"))
+	assert.Contains(t, out.String(), "Truncated 1 log files and removed 0 backups.")
+}
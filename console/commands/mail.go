@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/mail"
+	"github.com/spf13/cobra"
+)
+
+// MailPreviewCommand creates the mail:preview command.
+func MailPreviewCommand(app contracts.Application) *cobra.Command {
+	var text bool
+
+	cmd := &cobra.Command{
+		Use:   "mail:preview [name]",
+		Short: "Render a mail preview without sending it",
+		Long: `Render a mailable registered with mailer.Preview to an HTML file you can
+open in a browser. Without a name, the registered previews are listed.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.Boot(); err != nil {
+				return fmt.Errorf("failed to boot application: %w", err)
+			}
+
+			mailer, err := container.Resolve[*mail.Mailer](app)
+			if err != nil {
+				return fmt.Errorf("mailer not available: %w", err)
+			}
+
+			if len(args) == 0 {
+				for _, name := range mailer.Previews() {
+					fmt.Println(name)
+				}
+				return nil
+			}
+
+			message, err := mailer.RenderPreview(args[0])
+			if err != nil {
+				return err
+			}
+			if text {
+				fmt.Print(message.Text)
+				return nil
+			}
+
+			dir := filepath.Join(app.StoragePath(), "framework", "mail")
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return err
+			}
+			path := filepath.Join(dir, strings.ReplaceAll(args[0], "/", "_")+".html")
+			if err := os.WriteFile(path, []byte(message.HTML), 0644); err != nil {
+				return err
+			}
+
+			fmt.Printf("Preview written to file://%s\n", path)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&text, "text", false, "Print the plain text body instead")
+
+	return cmd
+}
+
+// MailSentTableCommand creates the mail:sent-table command.
+func MailSentTableCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "mail:sent-table",
+		Short: "Create a migration for the mail sent log database table",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			table := app.GetConfig().GetString("mail.sent_log.table")
+			if table == "" {
+				table = "mail_sent_messages"
+			}
+			return createMigrationFrom(app, "create_"+table+"_table", "mail_sent_migration.go.tmpl", map[string]string{
+				"Table": table,
+			})
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/spf13/cobra"
+)
+
+// DownCommand creates the down command.
+func DownCommand(app contracts.Application) *cobra.Command {
+	var secret string
+	var retry int
+
+	cmd := &cobra.Command{
+		Use:   "down",
+		Short: "Put the application into maintenance mode",
+		Long: `Write storage/framework/down. While it exists, the maintenance middleware
+answers requests with 503 Service Unavailable, with a Retry-After header of
+--retry seconds. With --secret, visiting /<secret>, or any page with
+?maintenance=<secret>, sets a cookie that lets that browser past maintenance.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := http.MaintenanceModePath(app.StoragePath())
+			mode := &http.MaintenanceMode{Time: time.Now(), Retry: retry, Secret: secret}
+			if err := http.WriteMaintenanceMode(path, mode); err != nil {
+				return fmt.Errorf("failed to write maintenance file: %w", err)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Application is now in maintenance mode.")
+			if secret != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Bypass it by visiting /%s, or any page with ?maintenance=%s.\n", secret, secret)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&secret, "secret", "", "Secret that lets a browser past maintenance")
+	cmd.Flags().IntVar(&retry, "retry", 0, "Seconds to send in the Retry-After header")
+	return cmd
+}
+
+// UpCommand creates the up command.
+func UpCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "up",
+		Short: "Bring the application out of maintenance mode",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := os.Remove(http.MaintenanceModePath(app.StoragePath()))
+			if os.IsNotExist(err) {
+				fmt.Fprintln(cmd.OutOrStdout(), "Application is not in maintenance mode.")
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to remove maintenance file: %w", err)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Application is now live.")
+			return nil
+		},
+	}
+}
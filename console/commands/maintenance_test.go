@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/foundation"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownAndUp(t *testing.T) {
+	app := foundation.New(t.TempDir())
+	path := http.MaintenanceModePath(app.StoragePath())
+
+	var out bytes.Buffer
+	cmd := DownCommand(app)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--secret=token", "--retry=60"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "/token, or any page with ?maintenance=token")
+
+	mode, err := http.LoadMaintenanceMode(path)
+	require.NoError(t, err)
+	require.NotNil(t, mode)
+	assert.Equal(t, 60, mode.Retry)
+	assert.Equal(t, "token", mode.Secret)
+
+	out.Reset()
+	cmd = UpCommand(app)
+	cmd.SetOut(&out)
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "now live")
+	assert.NoFileExists(t, path)
+
+	out.Reset()
+	cmd = UpCommand(app)
+	cmd.SetOut(&out)
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "not in maintenance mode")
+}
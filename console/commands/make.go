@@ -46,14 +46,34 @@ func MakeControllerCommand(app contracts.Application) *cobra.Command {
 
 // MakeModelCommand creates the make:model command.
 func MakeModelCommand(app contracts.Application) *cobra.Command {
-	return &cobra.Command{
+	var migration, factory bool
+
+	cmd := &cobra.Command{
 		Use:   "make:model <name>",
 		Short: "Create a new model",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return createModel(app, args[0])
+			if err := createModel(app, args[0]); err != nil {
+				return err
+			}
+			if migration {
+				table := tableName(support.ToPascalCase(args[0]))
+				if err := createMigrationFrom(app, "create_"+table+"_table", "create_table_migration.go.tmpl", map[string]string{
+					"Table": table,
+				}); err != nil {
+					return err
+				}
+			}
+			if factory {
+				return createFactory(app, args[0])
+			}
+			return nil
 		},
 	}
+
+	cmd.Flags().BoolVarP(&migration, "migration", "m", false, "Also create a migration for the model's table")
+	cmd.Flags().BoolVarP(&factory, "factory", "f", false, "Also create a factory for the model")
+	return cmd
 }
 
 // MakeMiddlewareCommand creates the make:middleware command.
@@ -80,11 +100,178 @@ func MakeProviderCommand(app contracts.Application) *cobra.Command {
 	}
 }
 
+// MakeSeederCommand creates the make:seeder command.
+func MakeSeederCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "make:seeder <name>",
+		Short: "Create a new database seeder",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return createSeeder(app, args[0])
+		},
+	}
+}
+
+// MakeJobCommand creates the make:job command.
+func MakeJobCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "make:job <name>",
+		Short: "Create a new queued job",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := support.ToPascalCase(args[0])
+			return writeStub(app, "Job", filepath.Join("app", "jobs", support.ToSnakeCase(name)+".go"), "job.go.tmpl", map[string]string{
+				"Name": name,
+			})
+		},
+	}
+}
+
+// MakeEventCommand creates the make:event command.
+func MakeEventCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "make:event <name>",
+		Short: "Create a new event",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := support.ToPascalCase(args[0])
+			return writeStub(app, "Event", filepath.Join("app", "events", support.ToSnakeCase(name)+".go"), "event.go.tmpl", map[string]string{
+				"Name":      name,
+				"EventName": strings.ReplaceAll(support.ToSnakeCase(name), "_", "."),
+			})
+		},
+	}
+}
+
+// MakeListenerCommand creates the make:listener command.
+func MakeListenerCommand(app contracts.Application) *cobra.Command {
+	var event string
+	var queued bool
+
+	cmd := &cobra.Command{
+		Use:   "make:listener <name>",
+		Short: "Create a new event listener",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := support.ToPascalCase(args[0])
+			data := map[string]any{
+				"Name":   name,
+				"Queued": queued,
+				"Event":  "",
+			}
+			if event != "" {
+				module, err := modulePath(app)
+				if err != nil {
+					return err
+				}
+				data["Event"] = support.ToPascalCase(event)
+				data["ModulePath"] = module
+			}
+			return writeStub(app, "Listener", filepath.Join("app", "listeners", support.ToSnakeCase(name)+".go"), "listener.go.tmpl", data)
+		},
+	}
+
+	cmd.Flags().StringVarP(&event, "event", "e", "", "The event type the listener handles, from app/events")
+	cmd.Flags().BoolVar(&queued, "queued", false, "Create a listener that runs on a queue worker")
+	return cmd
+}
+
+// MakePolicyCommand creates the make:policy command.
+func MakePolicyCommand(app contracts.Application) *cobra.Command {
+	var model string
+
+	cmd := &cobra.Command{
+		Use:   "make:policy <name>",
+		Short: "Create a new authorization policy",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := strings.TrimSuffix(support.ToPascalCase(args[0]), "Policy")
+			data := map[string]string{"Name": name}
+			if model != "" {
+				module, err := modulePath(app)
+				if err != nil {
+					return err
+				}
+				data["Model"] = support.ToPascalCase(model)
+				data["Variable"] = strings.ToLower(data["Model"][:1]) + data["Model"][1:]
+				data["ModulePath"] = module
+			}
+			return writeStub(app, "Policy", filepath.Join("app", "policies", support.ToSnakeCase(name)+"_policy.go"), "policy.go.tmpl", data)
+		},
+	}
+
+	cmd.Flags().StringVar(&model, "model", "", "The model the policy authorizes, from app/models")
+	return cmd
+}
+
+// StubPublishCommand creates the stub:publish command.
+func StubPublishCommand(app contracts.Application) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "stub:publish",
+		Short: "Copy the generator stubs into the app for customization",
+		Long: `Copy the templates used by the make:* commands to the app's stubs directory.
+The generators use an app's copy of a stub instead of the built-in one, so
+edit them to change what is generated. Existing stubs are kept unless
+--force is given.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := filepath.Join(app.BasePath(), "stubs")
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			published := 0
+			for _, name := range generatorStubs {
+				path := filepath.Join(dir, name)
+				if _, err := os.Stat(path); err == nil && !force {
+					continue
+				}
+				content, err := templates.FS.ReadFile(name)
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(path, content, 0644); err != nil {
+					return err
+				}
+				published++
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "✓ Published %d stubs to %s\n", published, dir)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite stubs that were already published")
+	return cmd
+}
+
+// generatorStubs are the templates used by the make:* commands.
+var generatorStubs = []string{
+	"controller.go.tmpl",
+	"controller_simple.go.tmpl",
+	"create_table_migration.go.tmpl",
+	"event.go.tmpl",
+	"factory.go.tmpl",
+	"job.go.tmpl",
+	"listener.go.tmpl",
+	"middleware.go.tmpl",
+	"migration.go.tmpl",
+	"model.go.tmpl",
+	"policy.go.tmpl",
+	"provider.go.tmpl",
+	"seeder.go.tmpl",
+}
+
 // =============================================================================
 // Implementation Functions
 // =============================================================================
 
 func createMigration(app contracts.Application, name string) error {
+	return createMigrationFrom(app, name, "migration.go.tmpl", nil)
+}
+
+// createMigrationFrom renders a migration template and registers the
+// migration in bootstrap/app.go. Extra values are passed to the template.
+func createMigrationFrom(app contracts.Application, name, templateName string, extra map[string]string) error {
 	basePath := app.BasePath()
 	dir := filepath.Join(basePath, "database", "migrations")
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -105,8 +292,11 @@ func createMigration(app contracts.Application, name string) error {
 		"Timestamp": timestamp,
 		"LowerName": migrationName,
 	}
+	for key, value := range extra {
+		data[key] = value
+	}
 
-	content, err := render("migration.go.tmpl", data)
+	content, err := render(app, templateName, data)
 	if err != nil {
 		return err
 	}
@@ -167,9 +357,9 @@ func createController(app contracts.Application, name string, resource bool) err
 	var content []byte
 	var err error
 	if resource {
-		content, err = render("controller.go.tmpl", data)
+		content, err = render(app, "controller.go.tmpl", data)
 	} else {
-		content, err = render("controller_simple.go.tmpl", data)
+		content, err = render(app, "controller_simple.go.tmpl", data)
 	}
 	if err != nil {
 		return err
@@ -199,14 +389,13 @@ func createModel(app contracts.Application, name string) error {
 		return fmt.Errorf("model already exists: %s", path)
 	}
 
-	tableName := support.ToSnakeCase(modelName) + "s"
 	data := map[string]string{
 		"Name":      modelName,
 		"LowerName": strings.ToLower(modelName),
-		"TableName": tableName,
+		"TableName": tableName(modelName),
 	}
 
-	content, err := render("model.go.tmpl", data)
+	content, err := render(app, "model.go.tmpl", data)
 	if err != nil {
 		return err
 	}
@@ -242,7 +431,7 @@ func createMiddleware(app contracts.Application, name string) error {
 		"LowerName": strings.ToLower(middlewareName),
 	}
 
-	content, err := render("middleware.go.tmpl", data)
+	content, err := render(app, "middleware.go.tmpl", data)
 	if err != nil {
 		return err
 	}
@@ -281,7 +470,7 @@ func createProvider(app contracts.Application, name string) error {
 		"LowerName": strings.ToLower(baseName),
 	}
 
-	content, err := render("provider.go.tmpl", data)
+	content, err := render(app, "provider.go.tmpl", data)
 	if err != nil {
 		return err
 	}
@@ -294,8 +483,114 @@ func createProvider(app contracts.Application, name string) error {
 	return nil
 }
 
-func render(templateName string, data any) ([]byte, error) {
-	tmpl, err := template.ParseFS(templates.FS, templateName)
+func createFactory(app contracts.Application, name string) error {
+	module, err := modulePath(app)
+	if err != nil {
+		return err
+	}
+	modelName := support.ToPascalCase(name)
+	return writeStub(app, "Factory", filepath.Join("database", "factories", support.ToSnakeCase(modelName)+"_factory.go"), "factory.go.tmpl", map[string]string{
+		"Name":       modelName,
+		"ModulePath": module,
+	})
+}
+
+// createSeeder creates a seeder and registers it in bootstrap/app.go when
+// the file has the seeders marker.
+func createSeeder(app contracts.Application, name string) error {
+	seederName := support.ToPascalCase(name)
+	if !strings.HasSuffix(seederName, "Seeder") {
+		seederName += "Seeder"
+	}
+	filename := support.ToSnakeCase(strings.TrimSuffix(seederName, "Seeder")) + "_seeder.go"
+	if err := writeStub(app, "Seeder", filepath.Join("database", "seeders", filename), "seeder.go.tmpl", map[string]string{
+		"Name": seederName,
+	}); err != nil {
+		return err
+	}
+
+	const marker = "// DO NOT DELETE: Add new seeders here\n"
+	bootstrapPath := filepath.Join(app.BasePath(), "bootstrap", "app.go")
+	txt, err := os.ReadFile(bootstrapPath)
+	if err != nil || !strings.Contains(string(txt), marker) {
+		fmt.Printf("  Register it in MigrationServiceProvider.Seeders: &seeders.%s{}\n", seederName)
+		return nil
+	}
+
+	newTxt := strings.Replace(string(txt), marker, "&s."+seederName+"{},\n\t\t\t"+marker, 1)
+	if module, err := modulePath(app); err == nil {
+		importPath := `"` + module + `/database/seeders"`
+		if !strings.Contains(newTxt, importPath) {
+			newTxt = strings.Replace(newTxt, "import (\n", "import (\n\ts "+importPath+"\n", 1)
+		}
+	}
+	if err := os.WriteFile(bootstrapPath, []byte(newTxt), 0644); err != nil {
+		return fmt.Errorf("failed to update bootstrap/app.go: %w", err)
+	}
+	return nil
+}
+
+// writeStub renders a stub to path, relative to the app's base path,
+// refusing to overwrite an existing file.
+func writeStub(app contracts.Application, kind, path, templateName string, data any) error {
+	path = filepath.Join(app.BasePath(), path)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists: %s", strings.ToLower(kind), path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	content, err := render(app, templateName, data)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ %s created: %s\n", kind, path)
+	return nil
+}
+
+// modulePath returns the module path in the app's go.mod.
+func modulePath(app contracts.Application) (string, error) {
+	content, err := os.ReadFile(filepath.Join(app.BasePath(), "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if module, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(module), `"`), nil
+		}
+	}
+	return "", fmt.Errorf("no module path in go.mod")
+}
+
+// tableName returns the table of a model: its name in snake case, made
+// plural.
+func tableName(model string) string {
+	name := support.ToSnakeCase(model)
+	switch {
+	case strings.HasSuffix(name, "y") && !strings.HasSuffix(name, "ay") && !strings.HasSuffix(name, "ey") && !strings.HasSuffix(name, "oy"):
+		return strings.TrimSuffix(name, "y") + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	default:
+		return name + "s"
+	}
+}
+
+// render renders a generator stub. A copy of the stub in the app's stubs
+// directory, such as stubs/model.go.tmpl, replaces the built-in one.
+func render(app contracts.Application, templateName string, data any) ([]byte, error) {
+	var tmpl *template.Template
+	var err error
+	if override := filepath.Join(app.BasePath(), "stubs", templateName); fileExists(override) {
+		tmpl, err = template.ParseFiles(override)
+	} else {
+		tmpl, err = template.ParseFS(templates.FS, templateName)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -305,3 +600,8 @@ func render(templateName string, data any) ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
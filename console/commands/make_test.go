@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/foundation"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBootstrap = `package bootstrap
+
+import (
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/genesysflow/go-genesys/providers"
+)
+
+var migrations = &providers.MigrationServiceProvider{
+	Seeders: []database.Seeder{
+		// DO NOT DELETE: Add new seeders here
+	},
+}
+`
+
+func newMakeTestApp(t *testing.T) *foundation.Application {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/shop\n\ngo 1.24\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "bootstrap"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bootstrap", "app.go"), []byte(testBootstrap), 0644))
+	return foundation.New(dir)
+}
+
+// readGenerated reads a generated file and checks that it is valid Go.
+func readGenerated(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	_, err = parser.ParseFile(token.NewFileSet(), path, content, parser.AllErrors)
+	require.NoError(t, err, string(content))
+	return string(content)
+}
+
+func TestMakeModelCommand_WithMigrationAndFactory(t *testing.T) {
+	app := newMakeTestApp(t)
+
+	cmd := MakeModelCommand(app)
+	cmd.SetArgs([]string{"Category", "-m", "-f"})
+	require.NoError(t, cmd.Execute())
+
+	model := readGenerated(t, filepath.Join(app.BasePath(), "app", "models", "category.go"))
+	assert.Contains(t, model, `return "categories"`)
+
+	migrations, err := filepath.Glob(filepath.Join(app.BasePath(), "database", "migrations", "*_create_categories_table.go"))
+	require.NoError(t, err)
+	require.Len(t, migrations, 1)
+	assert.Contains(t, readGenerated(t, migrations[0]), "categories")
+
+	factory := readGenerated(t, filepath.Join(app.BasePath(), "database", "factories", "category_factory.go"))
+	assert.Contains(t, factory, `"example.com/shop/app/models"`)
+	assert.Contains(t, factory, "func NewCategoryFactory()")
+}
+
+func TestMakeSeederCommand_RegistersSeeder(t *testing.T) {
+	app := newMakeTestApp(t)
+
+	cmd := MakeSeederCommand(app)
+	cmd.SetArgs([]string{"users"})
+	require.NoError(t, cmd.Execute())
+
+	seeder := readGenerated(t, filepath.Join(app.BasePath(), "database", "seeders", "users_seeder.go"))
+	assert.Contains(t, seeder, "type UsersSeeder struct")
+
+	bootstrap := readGenerated(t, filepath.Join(app.BasePath(), "bootstrap", "app.go"))
+	assert.Contains(t, bootstrap, `s "example.com/shop/database/seeders"`)
+	assert.Contains(t, bootstrap, "&s.UsersSeeder{},")
+
+	// Generators never overwrite existing files.
+	cmd = MakeSeederCommand(app)
+	cmd.SetArgs([]string{"users"})
+	assert.Error(t, cmd.Execute())
+}
+
+func TestMakeEventListenerJobAndPolicyCommands(t *testing.T) {
+	app := newMakeTestApp(t)
+	base := app.BasePath()
+
+	run := func(cmd *cobra.Command, args ...string) {
+		t.Helper()
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+	}
+	run(MakeEventCommand(app), "OrderShipped")
+	run(MakeListenerCommand(app), "SendShipmentNotification", "--event", "OrderShipped", "--queued")
+	run(MakeListenerCommand(app), "AuditEvents")
+	run(MakeJobCommand(app), "ProcessPodcast")
+	run(MakePolicyCommand(app), "PostPolicy", "--model", "Post")
+
+	event := readGenerated(t, filepath.Join(base, "app", "events", "order_shipped.go"))
+	assert.Contains(t, event, `return "order.shipped"`)
+
+	listener := readGenerated(t, filepath.Join(base, "app", "listeners", "send_shipment_notification.go"))
+	assert.Contains(t, listener, `"example.com/shop/app/events"`)
+	assert.Contains(t, listener, "event *events.OrderShipped")
+	assert.Contains(t, listener, "events.ListenQueued")
+
+	generic := readGenerated(t, filepath.Join(base, "app", "listeners", "audit_events.go"))
+	assert.Contains(t, generic, `"github.com/genesysflow/go-genesys/events"`)
+
+	readGenerated(t, filepath.Join(base, "app", "jobs", "process_podcast.go"))
+
+	policy := readGenerated(t, filepath.Join(base, "app", "policies", "post_policy.go"))
+	assert.Contains(t, policy, "type PostPolicy struct")
+	assert.Contains(t, policy, "post *models.Post")
+}
+
+func TestStubOverride(t *testing.T) {
+	app := newMakeTestApp(t)
+
+	publish := StubPublishCommand(app)
+	publish.SetOut(io.Discard)
+	publish.SetArgs(nil)
+	require.NoError(t, publish.Execute())
+	assert.FileExists(t, filepath.Join(app.BasePath(), "stubs", "job.go.tmpl"))
+
+	custom := "package jobs\n\n// {{.Name}} was generated from a custom stub.\ntype {{.Name}} struct{}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(app.BasePath(), "stubs", "job.go.tmpl"), []byte(custom), 0644))
+
+	cmd := MakeJobCommand(app)
+	cmd.SetArgs([]string{"SendReport"})
+	require.NoError(t, cmd.Execute())
+
+	job := readGenerated(t, filepath.Join(app.BasePath(), "app", "jobs", "send_report.go"))
+	assert.Contains(t, job, "SendReport was generated from a custom stub.")
+}
+
+func TestTableName(t *testing.T) {
+	assert.Equal(t, "users", tableName("User"))
+	assert.Equal(t, "categories", tableName("Category"))
+	assert.Equal(t, "keys", tableName("Key"))
+	assert.Equal(t, "addresses", tableName("Address"))
+	assert.Equal(t, "blog_posts", tableName("BlogPost"))
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/foundation"
+	"github.com/spf13/cobra"
+)
+
+// providerDiscoverer is an application that can write a provider manifest.
+type providerDiscoverer interface {
+	DiscoverProviders() foundation.ProviderManifest
+	WriteProviderManifest(path string) error
+}
+
+// PackageDiscoverCommand creates the package:discover command.
+func PackageDiscoverCommand(app contracts.Application) *cobra.Command {
+	var clear bool
+
+	cmd := &cobra.Command{
+		Use:   "package:discover",
+		Short: "Write the provider manifest",
+		Long: `Record which registered providers are deferred and which services they
+provide in storage/framework/providers.json. While the manifest exists, the
+providers it lists are registered as it says without being asked, so run
+it again after adding a provider or changing what one defers or provides.
+--clear removes the manifest.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := foundation.ProviderManifestPath(app.StoragePath())
+			if clear {
+				if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to remove provider manifest: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "Provider manifest cleared.")
+				return nil
+			}
+
+			discoverer, ok := app.(providerDiscoverer)
+			if !ok {
+				return errors.New("the application can't list its providers")
+			}
+			if err := discoverer.WriteProviderManifest(path); err != nil {
+				return err
+			}
+
+			manifest := discoverer.DiscoverProviders()
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "PROVIDER\tDEFERRED\tPROVIDES")
+			for _, provider := range manifest.Providers {
+				deferred := "no"
+				if provider.Deferred {
+					deferred = "yes"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", provider.Name, deferred, strings.Join(provider.Provides, ", "))
+			}
+			w.Flush()
+			fmt.Fprintf(cmd.OutOrStdout(), "\nDiscovered %d providers in %s.\n", len(manifest.Providers), path)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&clear, "clear", false, "Remove the provider manifest")
+	return cmd
+}
@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/foundation"
+	"github.com/genesysflow/go-genesys/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageDiscover(t *testing.T) {
+	app := foundation.New(t.TempDir())
+	require.NoError(t, app.Register(&providers.HashingServiceProvider{}))
+
+	var out bytes.Buffer
+	cmd := PackageDiscoverCommand(app)
+	cmd.SetOut(&out)
+	require.NoError(t, cmd.Execute())
+
+	path := foundation.ProviderManifestPath(app.StoragePath())
+	assert.FileExists(t, path)
+	assert.Regexp(t, `providers\.HashingServiceProvider\s+no\s+hash`, out.String())
+	assert.Contains(t, out.String(), "Discovered 1 providers")
+
+	cmd = PackageDiscoverCommand(app)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--clear"})
+	require.NoError(t, cmd.Execute())
+	assert.NoFileExists(t, path)
+}
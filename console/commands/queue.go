@@ -0,0 +1,294 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/heartbeat"
+	"github.com/genesysflow/go-genesys/queue"
+	"github.com/spf13/cobra"
+)
+
+// QueueWorkCommand creates the queue:work command.
+func QueueWorkCommand(app contracts.Application) *cobra.Command {
+	var connection string
+	var queues string
+	var sleep time.Duration
+	var tries int
+	var backoff time.Duration
+	var concurrency int
+
+	cmd := &cobra.Command{
+		Use:   "queue:work",
+		Short: "Start processing jobs on the queue",
+		Long: `Start a worker that processes jobs until it is stopped.
+The worker exits cleanly after its current job when queue:restart is run,
+so a process supervisor can start it again with the new code.
+
+Use --queue to consume several named queues. "high,default,low" always
+drains higher queues first; "high:5,default:3,low:1" shares attempts by
+weight so lower queues are never starved.
+
+Use --concurrency to process several jobs at once. On SIGINT or SIGTERM
+the worker stops taking jobs and waits for those in progress to finish.
+Jobs waiting out their --backoff go back on their queue.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.Boot(); err != nil {
+				return fmt.Errorf("failed to boot application: %w", err)
+			}
+
+			manager, err := container.Resolve[*queue.Manager](app)
+			if err != nil {
+				return fmt.Errorf("queue manager not available: %w", err)
+			}
+
+			conn, err := manager.Connection(connection)
+			if err != nil {
+				return err
+			}
+
+			source, ok := conn.(queue.Source)
+			if !ok {
+				return fmt.Errorf("queue connection [%s] does not support workers", connection)
+			}
+
+			weights, err := queue.ParseQueues(queues)
+			if err != nil {
+				return err
+			}
+			if len(weights) > 0 {
+				if _, ok := conn.(queue.NamedSource); !ok {
+					return fmt.Errorf("queue connection [%s] does not support named queues", connection)
+				}
+			}
+
+			options := queue.WorkerOptions{
+				Sleep:       sleep,
+				Logger:      app.GetLogger(),
+				Queues:      weights,
+				Tries:       tries,
+				Backoff:     backoff,
+				DeadLetter:  manager.DeadLetter(),
+				Concurrency: concurrency,
+			}
+			if store, err := restartStore(app); err == nil {
+				options.Cache = store
+			}
+
+			monitor, err := heartbeat.FromConfig(app.GetConfig().GetMap("queue.heartbeat"))
+			if err != nil {
+				return err
+			}
+			options.Heartbeat = monitor
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			fmt.Println("Processing jobs...")
+			return queue.NewWorker(source, options).Run(ctx)
+		},
+	}
+
+	cmd.Flags().StringVarP(&connection, "connection", "c", "", "Queue connection to work")
+	cmd.Flags().StringVar(&queues, "queue", "", "Comma-separated queues to work, highest priority first (name or name:weight)")
+	cmd.Flags().DurationVar(&sleep, "sleep", time.Second, "Time to wait when no job is available")
+	cmd.Flags().IntVar(&tries, "tries", 1, "Number of times to attempt a job before dead-lettering it")
+	cmd.Flags().DurationVar(&backoff, "backoff", 0, "Time to wait before retrying a failed job")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 1, "Number of jobs to process at once")
+
+	return cmd
+}
+
+// QueueRestartCommand creates the queue:restart command.
+func QueueRestartCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "queue:restart",
+		Short: "Restart queue workers after their current job",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.Boot(); err != nil {
+				return fmt.Errorf("failed to boot application: %w", err)
+			}
+
+			store, err := restartStore(app)
+			if err != nil {
+				return fmt.Errorf("cache not available: %w", err)
+			}
+
+			if err := queue.SignalRestart(store); err != nil {
+				return err
+			}
+
+			fmt.Println("Broadcasting queue restart signal.")
+			return nil
+		},
+	}
+}
+
+// restartStore resolves the cache store used for worker restart signals.
+func restartStore(app contracts.Application) (cache.Store, error) {
+	manager, err := container.Resolve[*cache.Manager](app)
+	if err != nil {
+		return nil, err
+	}
+	return manager.Store(app.GetConfig().GetString("queue.restart_store"))
+}
+
+// QueueTableCommand creates the queue:table command.
+func QueueTableCommand(app contracts.Application) *cobra.Command {
+	var connection string
+
+	cmd := &cobra.Command{
+		Use:   "queue:table",
+		Short: "Create a migration for the queue jobs database table",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, _ := app.GetConfig().GetMap("queue.connections")[connection].(map[string]any)
+			table, _ := settings["table"].(string)
+			if table == "" {
+				table = "jobs"
+			}
+			return createMigrationFrom(app, "create_"+table+"_table", "queue_migration.go.tmpl", map[string]string{
+				"Table": table,
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&connection, "connection", "database", "Connection in queue.connections whose table to create")
+
+	return cmd
+}
+
+// QueueFailedTableCommand creates the queue:failed-table command.
+func QueueFailedTableCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "queue:failed-table",
+		Short: "Create a migration for the failed queue jobs database table",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			table := app.GetConfig().GetString("queue.failed.table")
+			if table == "" {
+				table = "failed_jobs"
+			}
+			return createMigrationFrom(app, "create_"+table+"_table", "queue_failed_migration.go.tmpl", map[string]string{
+				"Table": table,
+			})
+		},
+	}
+}
+
+// QueueFailedCommand creates the queue:failed command.
+func QueueFailedCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "queue:failed",
+		Short: "List the jobs in the dead-letter queue",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, failedJobs, err := bootFailedJobs(app)
+			if err != nil {
+				return err
+			}
+
+			failed, err := failedJobs.Failed()
+			if err != nil {
+				return err
+			}
+			if len(failed) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No failed jobs.")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tQUEUE\tJOB\tATTEMPTS\tFAILED AT\tERROR")
+			for _, job := range failed {
+				queueName := job.Queue
+				if queueName == "" {
+					queueName = queue.DefaultQueue
+				}
+				fmt.Fprintf(w, "%s\t%s\t%T\t%d\t%s\t%s\n", job.ID, queueName, job.Job, job.Attempts,
+					job.FailedAt.Format("2006-01-02 15:04:05"), strings.ReplaceAll(job.Error, "\n", " "))
+			}
+			return w.Flush()
+		},
+	}
+}
+
+// QueueRetryCommand creates the queue:retry command.
+func QueueRetryCommand(app contracts.Application) *cobra.Command {
+	var connection string
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "queue:retry [id...]",
+		Short: "Push failed jobs back onto their queues",
+		Long: `Push jobs from the dead-letter queue back onto the queue they failed on,
+and remove them from the dead-letter queue. Pass the IDs queue:failed
+lists, or --all to retry every failed job.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && !all {
+				return fmt.Errorf("pass the IDs of the jobs to retry, or --all")
+			}
+
+			manager, failedJobs, err := bootFailedJobs(app)
+			if err != nil {
+				return err
+			}
+
+			jobs := make([]*queue.FailedJob, 0, len(args))
+			if all {
+				if jobs, err = failedJobs.Failed(); err != nil {
+					return err
+				}
+			}
+			for _, id := range args {
+				job, err := failedJobs.Find(id)
+				if err != nil {
+					return err
+				}
+				if job == nil {
+					return fmt.Errorf("failed job [%s] not found", id)
+				}
+				jobs = append(jobs, job)
+			}
+
+			for _, job := range jobs {
+				if err := manager.Retry(job, connection); err != nil {
+					return fmt.Errorf("failed to retry job [%s]: %w", job.ID, err)
+				}
+				if err := failedJobs.Forget(job.ID); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Pushed failed job [%s] back onto the queue.\n", job.ID)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&connection, "connection", "c", "", "Queue connection to push the jobs onto")
+	cmd.Flags().BoolVar(&all, "all", false, "Retry every failed job")
+
+	return cmd
+}
+
+// bootFailedJobs boots the application and resolves the queue manager and
+// its dead-letter queue, which must support reading jobs back.
+func bootFailedJobs(app contracts.Application) (*queue.Manager, queue.FailedJobs, error) {
+	if err := app.Boot(); err != nil {
+		return nil, nil, fmt.Errorf("failed to boot application: %w", err)
+	}
+
+	manager, err := container.Resolve[*queue.Manager](app)
+	if err != nil {
+		return nil, nil, fmt.Errorf("queue manager not available: %w", err)
+	}
+	failedJobs, ok := manager.DeadLetter().(queue.FailedJobs)
+	if !ok {
+		return nil, nil, fmt.Errorf("no readable dead-letter queue is configured; set queue.failed.driver")
+	}
+	return manager, failedJobs, nil
+}
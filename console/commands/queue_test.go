@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/foundation"
+	"github.com/genesysflow/go-genesys/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reportJob struct {
+	Day string
+}
+
+func (j *reportJob) Handle() error { return nil }
+
+func newQueueTestApp(t *testing.T) (*foundation.Application, *queue.MemoryQueue, *queue.MemoryDeadLetter) {
+	t.Helper()
+
+	memory := queue.NewMemoryQueue()
+	dlq := queue.NewMemoryDeadLetter()
+	manager := queue.NewManager()
+	manager.Register("memory", memory)
+	manager.SetDefaultConnection("memory")
+	manager.SetDeadLetter(dlq)
+
+	app := foundation.New(t.TempDir())
+	app.InstanceType(manager)
+	return app, memory, dlq
+}
+
+func TestQueueFailed(t *testing.T) {
+	app, _, dlq := newQueueTestApp(t)
+
+	var out bytes.Buffer
+	cmd := QueueFailedCommand(app)
+	cmd.SetOut(&out)
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "No failed jobs.")
+
+	require.NoError(t, dlq.Push(&queue.FailedJob{Job: &reportJob{}, Queue: "reports", Attempts: 3, Error: "disk full", FailedAt: time.Now()}))
+	out.Reset()
+	require.NoError(t, cmd.Execute())
+	assert.Regexp(t, `1\s+reports\s+\*commands\.reportJob\s+3\s+.*disk full`, out.String())
+}
+
+func TestQueueRetry(t *testing.T) {
+	app, memory, dlq := newQueueTestApp(t)
+	require.NoError(t, dlq.Push(&queue.FailedJob{Job: &reportJob{Day: "mon"}, Queue: "reports", Error: "disk full"}))
+	require.NoError(t, dlq.Push(&queue.FailedJob{Job: &reportJob{Day: "tue"}, Error: "disk full"}))
+
+	cmd := QueueRetryCommand(app)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{})
+	assert.Error(t, cmd.Execute(), "retry needs IDs or --all")
+
+	cmd.SetArgs([]string{"3"})
+	assert.EqualError(t, cmd.Execute(), "failed job [3] not found")
+
+	cmd.SetArgs([]string{"1"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, 1, memory.SizeOf("reports"))
+	assert.Len(t, dlq.All(), 1)
+
+	cmd = QueueRetryCommand(app)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--all"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, 1, memory.SizeOf(queue.DefaultQueue))
+	assert.Empty(t, dlq.All())
+}
+
+func TestQueueRetryRequiresReadableDeadLetter(t *testing.T) {
+	app, _, _ := newQueueTestApp(t)
+	manager, err := container.Resolve[*queue.Manager](app)
+	require.NoError(t, err)
+	manager.SetDeadLetter(nil)
+
+	cmd := QueueFailedCommand(app)
+	cmd.SetOut(&bytes.Buffer{})
+	assert.ErrorContains(t, cmd.Execute(), "set queue.failed.driver")
+}
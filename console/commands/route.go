@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/spf13/cobra"
+)
+
+// RouteListCommand creates the route:list command.
+func RouteListCommand(app contracts.Application) *cobra.Command {
+	var method, name, path string
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "route:list",
+		Short: "List all registered routes",
+		Long: `List every route with its method, path, name, handler and middleware,
+in the order routes are matched. Filter with --method, --name and --path.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			router, err := bootRouter(app)
+			if err != nil {
+				return err
+			}
+
+			routes := filterRoutes(router.RouteList(), method, name, path)
+			if asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(routes)
+			}
+			printRoutes(cmd, routes)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&method, "method", "", "Only list routes with this method")
+	cmd.Flags().StringVar(&name, "name", "", "Only list routes whose name contains this")
+	cmd.Flags().StringVar(&path, "path", "", "Only list routes whose path starts with this")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the routes as JSON")
+
+	return cmd
+}
+
+// RouteCacheCommand creates the route:cache command.
+func RouteCacheCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "route:cache",
+		Short: "Cache the route table for faster boot",
+		Long: `Write the route table to storage/framework/routes.json. At boot, routes that
+match the cache skip locating their registration and checking for conflicts.
+Run it again after changing routes; a stale cache is ignored with a warning.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := http.RouteCachePath(app)
+			if err := removeRouteCache(path); err != nil {
+				return err
+			}
+
+			router, err := bootRouter(app)
+			if err != nil {
+				return err
+			}
+			cache := router.RouteCache()
+			if err := http.WriteRouteCache(path, cache); err != nil {
+				return fmt.Errorf("failed to write route cache: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Cached %d routes in %s.\n", len(cache.Routes), path)
+			return nil
+		},
+	}
+}
+
+// RouteClearCommand creates the route:clear command.
+func RouteClearCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "route:clear",
+		Short: "Remove the route cache",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := removeRouteCache(http.RouteCachePath(app)); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Route cache cleared.")
+			return nil
+		},
+	}
+}
+
+// bootRouter registers the app's routes as the serve command does and
+// boots the app.
+func bootRouter(app contracts.Application) (*http.Router, error) {
+	routeProvider := newRouteProvider(app)
+	app.Register(routeProvider)
+
+	if err := app.Boot(); err != nil {
+		return nil, fmt.Errorf("failed to boot application: %w", err)
+	}
+	return routeProvider.Kernel().Router(), nil
+}
+
+func removeRouteCache(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove route cache: %w", err)
+	}
+	return nil
+}
+
+func filterRoutes(routes []http.RouteInfo, method, name, path string) []http.RouteInfo {
+	filtered := make([]http.RouteInfo, 0, len(routes))
+	for _, route := range routes {
+		if method != "" && !strings.EqualFold(route.Method, method) {
+			continue
+		}
+		if name != "" && !strings.Contains(route.Name, name) {
+			continue
+		}
+		if path != "" && !strings.HasPrefix(route.Path, path) {
+			continue
+		}
+		filtered = append(filtered, route)
+	}
+	return filtered
+}
+
+func printRoutes(cmd *cobra.Command, routes []http.RouteInfo) {
+	if len(routes) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No routes found.")
+		return
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tNAME\tHANDLER\tMIDDLEWARE")
+	for _, route := range routes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			route.Method, route.Path, route.Name, route.Handler, strings.Join(route.Middleware, ", "))
+	}
+	w.Flush()
+	fmt.Fprintf(cmd.OutOrStdout(), "\nShowing %d routes.\n", len(routes))
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/foundation"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouteTestApp(t *testing.T) *foundation.Application {
+	t.Helper()
+
+	app := foundation.New(t.TempDir())
+	app.Register(&providers.LogServiceProvider{})
+	app.InstanceType(func(r *http.Router) {
+		r.GET("/users", func(ctx *http.Context) error { return nil }).Name("users.index")
+		r.Group("/admin", func(admin *http.Router) {
+			admin.POST("/reports", func(ctx *http.Context) error { return nil }).Name("admin.reports.store")
+		}, func(ctx *http.Context, next func() error) error { return next() })
+	})
+	app.InstanceType([]http.MiddlewareFunc{})
+	return app
+}
+
+func TestRouteListCommand(t *testing.T) {
+	app := newRouteTestApp(t)
+
+	var out bytes.Buffer
+	cmd := RouteListCommand(app)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--method", "post"})
+	require.NoError(t, cmd.Execute())
+
+	assert.Contains(t, out.String(), "METHOD")
+	assert.Contains(t, out.String(), "/admin/reports")
+	assert.Contains(t, out.String(), "admin.reports.store")
+	assert.Contains(t, out.String(), "commands.newRouteTestApp")
+	assert.NotContains(t, out.String(), "/users")
+	assert.Contains(t, out.String(), "Showing 1 routes.")
+}
+
+func TestRouteCacheCommand(t *testing.T) {
+	app := newRouteTestApp(t)
+
+	var out bytes.Buffer
+	cmd := RouteCacheCommand(app)
+	cmd.SetOut(&out)
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "Cached 2 routes")
+
+	cache, err := http.LoadRouteCache(http.RouteCachePath(app))
+	require.NoError(t, err)
+	require.Len(t, cache.Routes, 2)
+	assert.Equal(t, "users.index", cache.Routes[0].Name)
+	assert.Equal(t, "/admin/reports", cache.Routes[1].Path)
+	assert.Len(t, cache.Routes[1].Middleware, 1)
+
+	clear := RouteClearCommand(app)
+	clear.SetOut(&out)
+	require.NoError(t, clear.Execute())
+	_, err = os.Stat(http.RouteCachePath(app))
+	assert.True(t, os.IsNotExist(err))
+}
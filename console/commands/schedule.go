@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/queue"
+	"github.com/genesysflow/go-genesys/schedule"
+	"github.com/spf13/cobra"
+)
+
+// ScheduleRunCommand creates the schedule:run command.
+func ScheduleRunCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "schedule:run",
+		Short: "Run the scheduled tasks that are due",
+		Long: `Run the tasks due this minute. Add a cron entry that runs it every minute:
+
+  * * * * * cd /path/to/app && ./app schedule:run >> /dev/null 2>&1`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sched, err := bootSchedule(app)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			due := sched.DueEvents(now)
+			if len(due) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No scheduled tasks are due.")
+				return nil
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Running %d scheduled tasks.\n", len(due))
+			return sched.RunDue(cmd.Context(), now)
+		},
+	}
+}
+
+// ScheduleWorkCommand creates the schedule:work command.
+func ScheduleWorkCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "schedule:work",
+		Short: "Run the scheduler every minute until stopped",
+		Long: `Run the tasks that are due at the start of every minute, without a cron
+entry. Each minute's tasks run in the background so a long task doesn't delay
+the next minute; on SIGINT or SIGTERM the command waits for running tasks.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sched, err := bootSchedule(app)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Running scheduled tasks every minute.")
+
+			var wg sync.WaitGroup
+			defer wg.Wait()
+			for {
+				next := time.Now().Truncate(time.Minute).Add(time.Minute)
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(time.Until(next)):
+				}
+
+				wg.Add(1)
+				go func(now time.Time) {
+					defer wg.Done()
+					// Failures are logged by the schedule.
+					_ = sched.RunDue(context.WithoutCancel(ctx), now)
+				}(next)
+			}
+		},
+	}
+}
+
+// ScheduleListCommand creates the schedule:list command.
+func ScheduleListCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "schedule:list",
+		Short: "List the scheduled tasks",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sched, err := bootSchedule(app)
+			if err != nil {
+				return err
+			}
+
+			events := sched.Events()
+			if len(events) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No scheduled tasks.")
+				return nil
+			}
+
+			now := time.Now()
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "EXPRESSION\tTASK\tNEXT DUE")
+			for _, event := range events {
+				next, _ := sched.NextRun(event, now)
+				fmt.Fprintf(w, "%s\t%s\t%s\n", event.Expression(), event.Description(), next.Format("2006-01-02 15:04 MST"))
+			}
+			return w.Flush()
+		},
+	}
+}
+
+// bootSchedule boots the application and wires the schedule to its logger,
+// time zone (app.timezone), queue manager and the cache store named by
+// schedule.cache_store, whose locks guard WithoutOverlapping and OnOneServer.
+func bootSchedule(app contracts.Application) (*schedule.Schedule, error) {
+	if err := app.Boot(); err != nil {
+		return nil, fmt.Errorf("failed to boot application: %w", err)
+	}
+
+	sched, err := container.Resolve[*schedule.Schedule](app)
+	if err != nil {
+		return nil, fmt.Errorf("schedule not available: %w", err)
+	}
+	if err := sched.Validate(); err != nil {
+		return nil, err
+	}
+
+	sched.SetLogger(app.GetLogger())
+	if name := app.GetConfig().GetString("app.timezone"); name != "" {
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid app.timezone: %w", err)
+		}
+		sched.SetTimezone(location)
+	}
+	if manager, err := container.Resolve[*queue.Manager](app); err == nil {
+		sched.SetQueue(manager)
+	}
+	if manager, err := container.Resolve[*cache.Manager](app); err == nil {
+		repository, err := manager.Repository(app.GetConfig().GetString("schedule.cache_store"))
+		if err != nil {
+			return nil, err
+		}
+		sched.SetCache(repository)
+	}
+	return sched, nil
+}
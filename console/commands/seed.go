@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/spf13/cobra"
+)
+
+// DbSeedCommand creates the db:seed command.
+func DbSeedCommand(app contracts.Application) *cobra.Command {
+	var class string
+
+	cmd := &cobra.Command{
+		Use:   "db:seed",
+		Short: "Seed the database",
+		Long: `Run the seeders registered in MigrationServiceProvider.Seeders, in order.
+With --class, run only the seeder with that type name.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.Boot(); err != nil {
+				return fmt.Errorf("failed to boot application: %w", err)
+			}
+
+			value, err := app.Make("database.seeders")
+			if err != nil {
+				return fmt.Errorf("seeders not available: %w", err)
+			}
+			seeders, _ := value.([]database.Seeder)
+
+			db, err := container.Resolve[*database.Manager](app)
+			if err != nil {
+				return fmt.Errorf("database not available: %w", err)
+			}
+
+			ran := 0
+			for _, seeder := range seeders {
+				name := seederName(seeder)
+				if class != "" && name != class {
+					continue
+				}
+				if err := seeder.Run(db); err != nil {
+					return fmt.Errorf("seeder %s failed: %w", name, err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Seeded: %s\n", name)
+				ran++
+			}
+
+			switch {
+			case class != "" && ran == 0:
+				return fmt.Errorf("seeder %s is not registered", class)
+			case ran == 0:
+				fmt.Fprintln(cmd.OutOrStdout(), "No seeders registered.")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&class, "class", "", "Run only the seeder with this type name")
+	return cmd
+}
+
+// seederName returns a seeder's type name, such as "UserSeeder".
+func seederName(seeder database.Seeder) string {
+	t := reflect.TypeOf(seeder)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
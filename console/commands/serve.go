@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/genesysflow/go-genesys/container"
 	"github.com/genesysflow/go-genesys/contracts"
@@ -15,23 +16,55 @@ import (
 func ServeCommand(app contracts.Application) *cobra.Command {
 	var port string
 	var host string
+	var shutdownTimeout time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "serve",
 		Short: "Run the development server",
-		Long:  `Start the development server for your application.`,
+		Long: `Start the development server for your application.
+
+On SIGINT or SIGTERM the server stops accepting connections, waits for
+in-flight requests to finish, runs the application's terminating callbacks
+and closes queue and database connections. A second signal exits at once.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runServer(app, host, port)
+			return runServer(app, host, port, shutdownTimeout)
 		},
 	}
 
 	cmd.Flags().StringVarP(&port, "port", "p", "3000", "Port to run the server on")
 	cmd.Flags().StringVarP(&host, "host", "H", "localhost", "Host to bind the server to")
+	cmd.Flags().DurationVar(&shutdownTimeout, "shutdown-timeout", 0, "How long to wait for in-flight requests on shutdown (default http.shutdown_timeout or 30s)")
 
 	return cmd
 }
 
-func runServer(app contracts.Application, host, port string) error {
+func runServer(app contracts.Application, host, port string, shutdownTimeout time.Duration) error {
+	logger := app.GetLogger()
+
+	routeProvider := newRouteProvider(app)
+	app.Register(routeProvider)
+
+	if err := app.Boot(); err != nil {
+		return fmt.Errorf("failed to boot application: %w", err)
+	}
+
+	kernel := routeProvider.Kernel()
+
+	logger.Info("Starting server", "host", host, "port", port)
+
+	if tlsConfig := http.LoadTLSConfig(app.GetConfig()); tlsConfig.Enabled() {
+		fmt.Printf("Server starting at https://%s:%s\n", host, port)
+		return kernel.RunTLSWithGracefulShutdown(":"+port, tlsConfig, shutdownTimeout)
+	}
+
+	fmt.Printf("Server starting at http://%s:%s\n", host, port)
+	return kernel.RunWithGracefulShutdown(":"+port, shutdownTimeout)
+}
+
+// newRouteProvider creates the route provider for the app's routes,
+// middleware and kernel config from the container, with defaults for an
+// app that registered none.
+func newRouteProvider(app contracts.Application) *providers.RouteServiceProvider {
 	logger := app.GetLogger()
 
 	// Try to get routes callback from container
@@ -83,22 +116,9 @@ func runServer(app contracts.Application, host, port string) error {
 		}
 	}
 
-	// Create route service provider
-	routeProvider := &providers.RouteServiceProvider{
+	return &providers.RouteServiceProvider{
 		Routes:       routesCallback,
 		Middleware:   globalMiddleware,
 		KernelConfig: kernelConfig,
 	}
-	app.Register(routeProvider)
-
-	if err := app.Boot(); err != nil {
-		return fmt.Errorf("failed to boot application: %w", err)
-	}
-
-	kernel := routeProvider.Kernel()
-
-	logger.Info("Starting server", "host", host, "port", port)
-	fmt.Printf("Server starting at http://%s:%s\n", host, port)
-
-	return kernel.RunWithGracefulShutdown(":"+port, 10)
 }
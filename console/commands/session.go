@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/spf13/cobra"
+)
+
+// SessionTableCommand creates the session:table command.
+func SessionTableCommand(app contracts.Application) *cobra.Command {
+	return &cobra.Command{
+		Use:   "session:table",
+		Short: "Create a migration for the session database table",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			table := app.GetConfig().GetString("session.table")
+			if table == "" {
+				table = "sessions"
+			}
+			return createMigrationFrom(app, "create_"+table+"_table", "session_migration.go.tmpl", map[string]string{
+				"Table": table,
+			})
+		},
+	}
+}
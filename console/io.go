@@ -0,0 +1,357 @@
+package console
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// ANSI styles of the output helpers.
+const (
+	styleReset    = "\033[0m"
+	styleInfo     = "\033[32m"
+	styleComment  = "\033[33m"
+	styleWarn     = "\033[33m"
+	styleError    = "\033[31m"
+	styleQuestion = "\033[36m"
+)
+
+// IO gives a command its arguments and options, writes output and asks
+// questions. Output is colored when written to a terminal, unless NO_COLOR
+// is set.
+type IO struct {
+	app    contracts.Application
+	cmd    *cobra.Command
+	in     *bufio.Reader
+	out    io.Writer
+	err    io.Writer
+	args   map[string][]string
+	colors bool
+}
+
+// newIO creates the IO of a command run with args.
+func newIO(app contracts.Application, cmd *cobra.Command, arguments []Argument, args []string) *IO {
+	values := make(map[string][]string, len(arguments))
+	for i, arg := range arguments {
+		switch {
+		case arg.Variadic && i < len(args):
+			values[arg.Name] = args[i:]
+		case i < len(args):
+			values[arg.Name] = []string{args[i]}
+		case arg.Default != "":
+			values[arg.Name] = []string{arg.Default}
+		}
+	}
+
+	return &IO{
+		app:    app,
+		cmd:    cmd,
+		in:     bufio.NewReader(cmd.InOrStdin()),
+		out:    cmd.OutOrStdout(),
+		err:    cmd.ErrOrStderr(),
+		args:   values,
+		colors: isTerminal(cmd.OutOrStdout()) && os.Getenv("NO_COLOR") == "",
+	}
+}
+
+// NewIO creates an IO reading from in and writing to out and errOut, for
+// running command handlers outside the kernel, such as in tests.
+func NewIO(in io.Reader, out, errOut io.Writer) *IO {
+	return &IO{
+		in:   bufio.NewReader(in),
+		out:  out,
+		err:  errOut,
+		args: make(map[string][]string),
+	}
+}
+
+// App returns the application.
+func (c *IO) App() contracts.Application {
+	return c.app
+}
+
+// Context returns the command's context.
+func (c *IO) Context() context.Context {
+	if c.cmd != nil && c.cmd.Context() != nil {
+		return c.cmd.Context()
+	}
+	return context.Background()
+}
+
+// SetColors sets whether output is colored.
+func (c *IO) SetColors(colors bool) {
+	c.colors = colors
+}
+
+// Argument returns an argument's value, or its default when not given.
+func (c *IO) Argument(name string) string {
+	if values := c.args[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Arguments returns the values of a variadic argument.
+func (c *IO) Arguments(name string) []string {
+	return c.args[name]
+}
+
+// Option returns a string option.
+func (c *IO) Option(name string) string {
+	if c.cmd == nil {
+		return ""
+	}
+	value, _ := c.cmd.Flags().GetString(name)
+	return value
+}
+
+// OptionBool returns a bool option.
+func (c *IO) OptionBool(name string) bool {
+	if c.cmd == nil {
+		return false
+	}
+	value, _ := c.cmd.Flags().GetBool(name)
+	return value
+}
+
+// OptionInt returns an int option.
+func (c *IO) OptionInt(name string) int {
+	if c.cmd == nil {
+		return 0
+	}
+	value, _ := c.cmd.Flags().GetInt(name)
+	return value
+}
+
+// OptionStrings returns a []string option.
+func (c *IO) OptionStrings(name string) []string {
+	if c.cmd == nil {
+		return nil
+	}
+	value, _ := c.cmd.Flags().GetStringSlice(name)
+	return value
+}
+
+// HasOption reports whether an option was given on the command line.
+func (c *IO) HasOption(name string) bool {
+	return c.cmd != nil && c.cmd.Flags().Changed(name)
+}
+
+// Line writes a line.
+func (c *IO) Line(format string, args ...any) {
+	fmt.Fprintln(c.out, sprintf(format, args))
+}
+
+// NewLine writes count empty lines, one if not given.
+func (c *IO) NewLine(count ...int) {
+	n := 1
+	if len(count) > 0 {
+		n = count[0]
+	}
+	fmt.Fprint(c.out, strings.Repeat("\n", n))
+}
+
+// Info writes a line in green.
+func (c *IO) Info(format string, args ...any) {
+	fmt.Fprintln(c.out, c.style(styleInfo, sprintf(format, args)))
+}
+
+// Comment writes a line in yellow.
+func (c *IO) Comment(format string, args ...any) {
+	fmt.Fprintln(c.out, c.style(styleComment, sprintf(format, args)))
+}
+
+// Warn writes a warning in yellow to standard error.
+func (c *IO) Warn(format string, args ...any) {
+	fmt.Fprintln(c.err, c.style(styleWarn, sprintf(format, args)))
+}
+
+// Error writes an error in red to standard error.
+func (c *IO) Error(format string, args ...any) {
+	fmt.Fprintln(c.err, c.style(styleError, sprintf(format, args)))
+}
+
+// Ask asks a question and returns the answer, or def when the answer is
+// empty.
+func (c *IO) Ask(question string, def ...string) string {
+	fallback := first(def)
+	prompt := question
+	if fallback != "" {
+		prompt += fmt.Sprintf(" [%s]", fallback)
+	}
+	fmt.Fprint(c.out, c.style(styleQuestion, prompt)+" ")
+
+	answer := c.readLine()
+	if answer == "" {
+		return fallback
+	}
+	return answer
+}
+
+// Secret asks a question without echoing the answer when reading from a
+// terminal.
+func (c *IO) Secret(question string) string {
+	fmt.Fprint(c.out, c.style(styleQuestion, question)+" ")
+	if file, ok := c.cmdInput().(*os.File); ok && term.IsTerminal(int(file.Fd())) {
+		secret, err := term.ReadPassword(int(file.Fd()))
+		fmt.Fprintln(c.out)
+		if err == nil {
+			return string(secret)
+		}
+	}
+	return c.readLine()
+}
+
+// Confirm asks a yes/no question, returning def for an empty or
+// unrecognized answer.
+func (c *IO) Confirm(question string, def ...bool) bool {
+	fallback := len(def) > 0 && def[0]
+	hint := "[y/N]"
+	if fallback {
+		hint = "[Y/n]"
+	}
+	fmt.Fprint(c.out, c.style(styleQuestion, question+" "+hint)+" ")
+
+	switch strings.ToLower(c.readLine()) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	default:
+		return fallback
+	}
+}
+
+// Choice asks to pick one of choices, by number or by value, and asks again
+// until the answer is valid. An empty answer picks def, if given.
+func (c *IO) Choice(question string, choices []string, def ...string) string {
+	fallback := first(def)
+	for {
+		prompt := question
+		if fallback != "" {
+			prompt += fmt.Sprintf(" [%s]", fallback)
+		}
+		fmt.Fprintln(c.out, c.style(styleQuestion, prompt))
+		for i, choice := range choices {
+			fmt.Fprintf(c.out, "  [%d] %s\n", i+1, choice)
+		}
+		fmt.Fprint(c.out, "> ")
+
+		answer, eof := c.readLineEOF()
+		if answer == "" && (fallback != "" || eof) {
+			return fallback
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(choices) {
+			return choices[n-1]
+		}
+		for _, choice := range choices {
+			if choice == answer {
+				return choice
+			}
+		}
+		if eof {
+			return fallback
+		}
+		c.Error("Value %q is invalid.", answer)
+	}
+}
+
+// Table writes rows in a bordered table.
+func (c *IO) Table(headers []string, rows [][]string) {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = utf8.RuneCountInString(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	border := "+"
+	for _, width := range widths {
+		border += strings.Repeat("-", width+2) + "+"
+	}
+	writeRow := func(cells []string, style string) {
+		line := "|"
+		for i, width := range widths {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			padded := cell + strings.Repeat(" ", width-utf8.RuneCountInString(cell))
+			if style != "" {
+				padded = c.style(style, padded)
+			}
+			line += " " + padded + " |"
+		}
+		fmt.Fprintln(c.out, line)
+	}
+
+	fmt.Fprintln(c.out, border)
+	if len(headers) > 0 {
+		writeRow(headers, styleInfo)
+		fmt.Fprintln(c.out, border)
+	}
+	for _, row := range rows {
+		writeRow(row, "")
+	}
+	fmt.Fprintln(c.out, border)
+}
+
+// style wraps s in an ANSI style when colors are enabled.
+func (c *IO) style(style, s string) string {
+	if !c.colors {
+		return s
+	}
+	return style + s + styleReset
+}
+
+func (c *IO) readLine() string {
+	line, _ := c.readLineEOF()
+	return line
+}
+
+// readLineEOF reads a trimmed line, reporting whether input ended.
+func (c *IO) readLineEOF() (string, bool) {
+	line, err := c.in.ReadString('\n')
+	return strings.TrimSpace(line), err != nil
+}
+
+func (c *IO) cmdInput() io.Reader {
+	if c.cmd == nil {
+		return nil
+	}
+	return c.cmd.InOrStdin()
+}
+
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+func sprintf(format string, args []any) string {
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func first(values []string) string {
+	if len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package console
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIOOutput(t *testing.T) {
+	var out, errOut bytes.Buffer
+	io := NewIO(strings.NewReader(""), &out, &errOut)
+	io.Line("plain %d", 1)
+	io.Info("info")
+	io.Comment("comment")
+	io.NewLine()
+	io.Warn("careful")
+	io.Error("failed: %s", "boom")
+	assert.Equal(t, "plain 1\ninfo\ncomment\n\n", out.String())
+	assert.Equal(t, "careful\nfailed: boom\n", errOut.String())
+
+	out.Reset()
+	io.SetColors(true)
+	io.Info("100%")
+	assert.Equal(t, "\033[32m100%\033[0m\n", out.String())
+}
+
+func TestIOTable(t *testing.T) {
+	var out bytes.Buffer
+	io := NewIO(strings.NewReader(""), &out, &out)
+	io.Table([]string{"Name", "Email"}, [][]string{
+		{"Jane", "jane@example.com"},
+		{"Zoë", ""},
+	})
+	assert.Equal(t, `+------+------------------+
+| Name | Email            |
++------+------------------+
+| Jane | jane@example.com |
+| Zoë  |                  |
++------+------------------+
+`, out.String())
+}
+
+func TestIOPrompts(t *testing.T) {
+	var out bytes.Buffer
+	io := NewIO(strings.NewReader("Jane\n\nyes\n\n4\n2\nsqlite\ns3cret\n"), &out, &out)
+
+	assert.Equal(t, "Jane", io.Ask("Name?"))
+	assert.Equal(t, "UTC", io.Ask("Timezone?", "UTC"))
+	assert.True(t, io.Confirm("Continue?"))
+	assert.True(t, io.Confirm("Migrate?", true))
+	assert.Equal(t, "mysql", io.Choice("Driver?", []string{"postgres", "mysql", "sqlite"}))
+	assert.Contains(t, out.String(), `Value "4" is invalid.`)
+	assert.Equal(t, "sqlite", io.Choice("Driver?", []string{"postgres", "mysql", "sqlite"}, "postgres"))
+	assert.Equal(t, "s3cret", io.Secret("Password?"))
+
+	// At the end of input, prompts return their defaults.
+	assert.Equal(t, "", io.Ask("Name?"))
+	assert.False(t, io.Confirm("Continue?"))
+	assert.Equal(t, "postgres", io.Choice("Driver?", []string{"postgres"}, "postgres"))
+	assert.Contains(t, out.String(), "Timezone? [UTC] ")
+	assert.Contains(t, out.String(), "Migrate? [Y/n] ")
+	assert.Contains(t, out.String(), "  [2] mysql\n")
+}
+
+func TestProgressBar(t *testing.T) {
+	var out bytes.Buffer
+	io := NewIO(strings.NewReader(""), &out, &out)
+	bar := io.CreateProgressBar(4)
+	bar.Advance()
+	bar.SetMessage("users")
+	bar.Advance(10)
+	bar.Finish()
+	bar.Finish()
+
+	assert.Equal(t, 4, bar.Current())
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, []string{
+		"0/4 [>---------------------------]   0%",
+		" 1/4 [=======>--------------------]  25%",
+		" 1/4 [=======>--------------------]  25% users",
+		" 4/4 [============================] 100% users",
+		" 4/4 [============================] 100% users",
+	}, lines)
+}
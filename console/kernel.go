@@ -2,13 +2,15 @@ package console
 
 import (
 	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/schedule"
 	"github.com/spf13/cobra"
 )
 
 // Kernel is the console kernel that handles CLI commands.
 type Kernel struct {
-	app     contracts.Application
-	rootCmd *cobra.Command
+	app      contracts.Application
+	rootCmd  *cobra.Command
+	schedule *schedule.Schedule
 }
 
 // KernelConfig defines configuration for the console kernel.
@@ -46,8 +48,9 @@ func NewKernel(app contracts.Application, config ...KernelConfig) *Kernel {
 	}
 
 	return &Kernel{
-		app:     app,
-		rootCmd: rootCmd,
+		app:      app,
+		rootCmd:  rootCmd,
+		schedule: schedule.New(),
 	}
 }
 
@@ -76,3 +79,8 @@ func (k *Kernel) App() contracts.Application {
 func (k *Kernel) AddCommand(cmds ...*cobra.Command) {
 	k.rootCmd.AddCommand(cmds...)
 }
+
+// Schedule returns the schedule run by schedule:run and schedule:work.
+func (k *Kernel) Schedule() *schedule.Schedule {
+	return k.schedule
+}
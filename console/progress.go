@@ -0,0 +1,97 @@
+package console
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// progressWidth is the number of characters of a progress bar.
+const progressWidth = 28
+
+// ProgressBar shows the progress of a task with a known number of steps.
+// It redraws itself in place on a terminal and writes a line per update
+// otherwise.
+type ProgressBar struct {
+	out      io.Writer
+	total    int
+	current  int
+	message  string
+	redraw   bool
+	finished bool
+	mu       sync.Mutex
+}
+
+// CreateProgressBar creates a progress bar for total steps and draws it.
+func (c *IO) CreateProgressBar(total int) *ProgressBar {
+	bar := &ProgressBar{out: c.out, total: max(total, 0), redraw: isTerminal(c.out)}
+	bar.draw()
+	return bar
+}
+
+// Advance moves the bar forward by steps, one if not given.
+func (p *ProgressBar) Advance(steps ...int) {
+	n := 1
+	if len(steps) > 0 {
+		n = steps[0]
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current = min(p.current+n, p.total)
+	p.draw()
+}
+
+// SetMessage sets the text shown after the bar.
+func (p *ProgressBar) SetMessage(message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.message = message
+	p.draw()
+}
+
+// Finish completes the bar and ends its line.
+func (p *ProgressBar) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.finished {
+		return
+	}
+	p.current = p.total
+	p.draw()
+	if p.redraw {
+		fmt.Fprintln(p.out)
+	}
+	p.finished = true
+}
+
+// Current returns the number of steps done.
+func (p *ProgressBar) Current() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current
+}
+
+// draw renders the bar. Callers hold the lock.
+func (p *ProgressBar) draw() {
+	percent := 100
+	if p.total > 0 {
+		percent = p.current * 100 / p.total
+	}
+	filled := progressWidth * percent / 100
+	bar := strings.Repeat("=", filled)
+	if filled < progressWidth {
+		bar += ">" + strings.Repeat("-", progressWidth-filled-1)
+	}
+
+	width := len(fmt.Sprint(p.total))
+	line := fmt.Sprintf(" %*d/%d [%s] %3d%%", width, p.current, p.total, bar, percent)
+	if p.message != "" {
+		line += " " + p.message
+	}
+	if p.redraw {
+		fmt.Fprint(p.out, "\r\033[K"+line)
+	} else {
+		fmt.Fprintln(p.out, line)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/genesysflow/go-genesys/console/commands"
 	"github.com/genesysflow/go-genesys/contracts"
 	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/schedule"
 	"github.com/spf13/cobra"
 )
 
@@ -28,6 +29,14 @@ type ConsoleServiceProvider struct {
 	// This callback is executed after framework commands are registered.
 	Commands func(*cobra.Command)
 
+	// AppCommands are application commands with argument and option
+	// definitions, registered at boot after framework commands.
+	AppCommands []Command
+
+	// Schedule is an optional function that defines scheduled tasks, run
+	// by the schedule:run and schedule:work commands.
+	Schedule func(*schedule.Schedule)
+
 	app    contracts.Application
 	kernel *Kernel
 }
@@ -57,6 +66,9 @@ func (p *ConsoleServiceProvider) Register(app contracts.Application) error {
 
 	// Register framework commands
 	p.kernel.AddCommand(commands.ServeCommand(app))
+	p.kernel.AddCommand(commands.RouteListCommand(app))
+	p.kernel.AddCommand(commands.RouteCacheCommand(app))
+	p.kernel.AddCommand(commands.RouteClearCommand(app))
 	p.kernel.AddCommand(commands.MigrateCommand(app))
 	p.kernel.AddCommand(commands.MigrateRollbackCommand(app))
 	p.kernel.AddCommand(commands.MigrateStatusCommand(app))
@@ -66,12 +78,45 @@ func (p *ConsoleServiceProvider) Register(app contracts.Application) error {
 	p.kernel.AddCommand(commands.MakeModelCommand(app))
 	p.kernel.AddCommand(commands.MakeMiddlewareCommand(app))
 	p.kernel.AddCommand(commands.MakeProviderCommand(app))
+	p.kernel.AddCommand(commands.MakeSeederCommand(app))
+	p.kernel.AddCommand(commands.MakeJobCommand(app))
+	p.kernel.AddCommand(commands.MakeEventCommand(app))
+	p.kernel.AddCommand(commands.MakeListenerCommand(app))
+	p.kernel.AddCommand(commands.MakePolicyCommand(app))
+	p.kernel.AddCommand(commands.StubPublishCommand(app))
+	p.kernel.AddCommand(commands.DbSeedCommand(app))
+	p.kernel.AddCommand(commands.ConsoleCommand(app))
+	p.kernel.AddCommand(commands.ConfigCacheCommand(app))
+	p.kernel.AddCommand(commands.ConfigClearCommand(app))
+	p.kernel.AddCommand(commands.ConfigShowCommand(app))
+	p.kernel.AddCommand(commands.ContainerListCommand(app))
+	p.kernel.AddCommand(commands.PackageDiscoverCommand(app))
+	p.kernel.AddCommand(commands.DownCommand(app))
+	p.kernel.AddCommand(commands.UpCommand(app))
+	p.kernel.AddCommand(commands.LogClearCommand(app))
 	p.kernel.AddCommand(commands.SqlcGenerateCommand(app))
+	p.kernel.AddCommand(commands.QueueWorkCommand(app))
+	p.kernel.AddCommand(commands.QueueRestartCommand(app))
+	p.kernel.AddCommand(commands.QueueTableCommand(app))
+	p.kernel.AddCommand(commands.QueueFailedTableCommand(app))
+	p.kernel.AddCommand(commands.QueueFailedCommand(app))
+	p.kernel.AddCommand(commands.QueueRetryCommand(app))
+	p.kernel.AddCommand(commands.MailPreviewCommand(app))
+	p.kernel.AddCommand(commands.MailSentTableCommand(app))
+	p.kernel.AddCommand(commands.CacheWarmCommand(app))
+	p.kernel.AddCommand(commands.CacheTableCommand(app))
+	p.kernel.AddCommand(commands.SessionTableCommand(app))
+	p.kernel.AddCommand(commands.AuditTableCommand(app))
+	p.kernel.AddCommand(commands.ScheduleRunCommand(app))
+	p.kernel.AddCommand(commands.ScheduleWorkCommand(app))
+	p.kernel.AddCommand(commands.ScheduleListCommand(app))
 
 	// Bind kernel to container
 	app.InstanceType(p.kernel)
 	app.BindValue("console.kernel", p.kernel)
 	app.BindValue("console.kernel.interface", p.kernel)
+	app.InstanceType(p.kernel.Schedule())
+	app.BindValue("schedule", p.kernel.Schedule())
 
 	// Bind routes and middleware if provided
 	if p.Routes != nil {
@@ -92,6 +137,16 @@ func (p *ConsoleServiceProvider) Boot(app contracts.Application) error {
 	if p.Commands != nil && p.kernel != nil {
 		p.Commands(p.kernel.RootCommand())
 	}
+	if p.kernel != nil {
+		if err := p.kernel.Register(p.AppCommands...); err != nil {
+			return err
+		}
+	}
+
+	// Define scheduled tasks if provided
+	if p.Schedule != nil && p.kernel != nil {
+		p.Schedule(p.kernel.Schedule())
+	}
 
 	return nil
 }
@@ -101,6 +156,7 @@ func (p *ConsoleServiceProvider) Provides() []string {
 	return []string{
 		"console.kernel",
 		"console.kernel.interface",
+		"schedule",
 	}
 }
 
@@ -0,0 +1,213 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/samber/do/v2"
+)
+
+// ErrCircularDependency is returned when building a service needs the
+// service itself, directly or through its dependencies.
+var ErrCircularDependency = do.ErrCircularDependency
+
+// injection is the state of resolving the dependencies of one service.
+type injection struct {
+	injector  do.Injector // tracks the services being invoked, so do detects cycles
+	consumers []string    // names of the service being built, for contextual bindings
+	autowire  bool        // build unbound structs instead of failing
+	building  []string    // types being auto-wired, outermost first
+}
+
+// Fill sets the fields of the struct target points to that have an inject
+// tag. A field tagged `inject:""` is resolved by its type, and one tagged
+// `inject:"name"` by service name:
+//
+//	type ReportController struct {
+//		DB    *database.Manager `inject:""`
+//		Cache contracts.Cache   `inject:"cache"`
+//	}
+//
+// Fields whose type is a pointer to a struct that isn't bound are
+// auto-wired as with Build. Contextual bindings for the target's type apply.
+func (c *Container) Fill(target any) error {
+	t := reflect.TypeOf(target)
+	if !isStructPointer(t) || reflect.ValueOf(target).IsNil() {
+		return fmt.Errorf("container: Fill expected a pointer to a struct, got %T", target)
+	}
+
+	name := GetTypeName(t)
+	inj := &injection{injector: c.injector, consumers: []string{name}, autowire: true, building: []string{name}}
+	return c.fill(inj, reflect.ValueOf(target))
+}
+
+// Build constructs a T that isn't bound in the container. With a
+// constructor, the constructor is called with its arguments resolved and
+// its first result returned; it may return an error as its last result.
+// Without one, T must be a struct or a pointer to one, which is created
+// and filled as by Fill. Dependencies that are pointers to unbound structs
+// are built the same way, and a type that ends up depending on itself
+// fails with ErrCircularDependency.
+func Build[T any](c *Container, constructor ...any) (T, error) {
+	var zero T
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	var fn any
+	if len(constructor) > 0 {
+		fn = constructor[0]
+	}
+	instance, err := c.build(&injection{injector: c.injector}, t, fn)
+	if err != nil {
+		return zero, err
+	}
+	if instance == nil {
+		return zero, nil
+	}
+
+	typed, ok := instance.(T)
+	if !ok {
+		return zero, fmt.Errorf("container: constructor returned %T, not %s", instance, t)
+	}
+	return typed, nil
+}
+
+// MustBuild constructs a T as Build does, panicking on error.
+func MustBuild[T any](c *Container, constructor ...any) T {
+	instance, err := Build[T](c, constructor...)
+	if err != nil {
+		panic(err)
+	}
+	return instance
+}
+
+// build auto-wires a value of type t, calling constructor if it's given
+// or filling a new struct otherwise.
+func (c *Container) build(inj *injection, t reflect.Type, constructor any) (any, error) {
+	name := GetTypeName(t)
+	if slices.Contains(inj.building, name) {
+		chain := make([]string, 0, len(inj.building)+1)
+		for _, building := range append(inj.building, name) {
+			chain = append(chain, "`"+building+"`")
+		}
+		return nil, fmt.Errorf("%w: %s", ErrCircularDependency, strings.Join(chain, " -> "))
+	}
+
+	nested := &injection{
+		injector:  inj.injector,
+		consumers: []string{name},
+		autowire:  true,
+		building:  append(slices.Clip(inj.building), name),
+	}
+
+	if constructor != nil {
+		fn := reflect.ValueOf(constructor)
+		if fn.Kind() != reflect.Func || fn.Type().NumOut() == 0 {
+			return nil, fmt.Errorf("container: constructor for %s must be a function returning it, got %T", t, constructor)
+		}
+		results, err := c.call(nested, fn)
+		if err != nil {
+			return nil, err
+		}
+		return results[0], nil
+	}
+
+	var ptr reflect.Value
+	switch {
+	case isStructPointer(t):
+		ptr = reflect.New(t.Elem())
+	case t.Kind() == reflect.Struct:
+		ptr = reflect.New(t)
+	default:
+		return nil, fmt.Errorf("container: cannot auto-wire %s without a constructor", t)
+	}
+	if err := c.fill(nested, ptr); err != nil {
+		return nil, err
+	}
+	if t.Kind() == reflect.Struct {
+		return ptr.Elem().Interface(), nil
+	}
+	return ptr.Interface(), nil
+}
+
+// fill sets the inject-tagged fields of the struct ptr points to.
+func (c *Container) fill(inj *injection, ptr reflect.Value) error {
+	value := ptr.Elem()
+	t := value.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := field.Tag.Lookup("inject")
+		if !ok {
+			continue
+		}
+		if !field.IsExported() {
+			return fmt.Errorf("container: field %s.%s has an inject tag but is unexported", t, field.Name)
+		}
+
+		instance, err := c.resolve(inj, name, field.Type)
+		if err != nil {
+			return err
+		}
+		if instance == nil {
+			continue
+		}
+		v := reflect.ValueOf(instance)
+		if !v.Type().AssignableTo(field.Type) {
+			return fmt.Errorf("container: cannot inject %s into field %s.%s of type %s", v.Type(), t, field.Name, field.Type)
+		}
+		value.Field(i).Set(v)
+	}
+	return nil
+}
+
+// resolveType returns the instance given for a dependency of type t.
+func (c *Container) resolveType(inj *injection, t reflect.Type) (any, error) {
+	return c.resolve(inj, "", t)
+}
+
+// resolve returns the instance given for a dependency of type t, by the
+// service name, or by the type if name is empty.
+func (c *Container) resolve(inj *injection, name string, t reflect.Type) (any, error) {
+	if name == "" {
+		name = GetTypeName(t)
+	}
+
+	// Prefer a contextual binding for the service being built
+	if implementation, ok := c.contextualFor(inj.consumers, name); ok {
+		given := &injection{injector: inj.injector, autowire: inj.autowire, building: inj.building}
+		return c.invokeFactory(given, implementation)
+	}
+
+	if inj.autowire && isStructPointer(t) && name == GetTypeName(t) && !c.provided(name) {
+		return c.build(inj, t, nil)
+	}
+
+	instance, err := do.InvokeNamed[any](inj.injector, name)
+	if err != nil {
+		// A cycle is reported once, not wrapped by every service in it.
+		if errors.Is(err, ErrCircularDependency) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("container: failed to resolve dependency '%s' (type %s): %w", name, t, err)
+	}
+	return instance, nil
+}
+
+// provided reports whether a service is registered under name, by any of
+// the container's registration methods.
+func (c *Container) provided(name string) bool {
+	for _, service := range c.injector.ListProvidedServices() {
+		if service.Service == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isStructPointer reports whether t is a pointer to a struct.
+func isStructPointer(t reflect.Type) bool {
+	return t != nil && t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct
+}
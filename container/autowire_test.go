@@ -0,0 +1,130 @@
+package container
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type wiredRepository struct {
+	Service TestService `inject:""`
+}
+
+type wiredController struct {
+	Repository *wiredRepository `inject:""`
+	Named      TestService      `inject:"named"`
+	Untagged   TestService
+}
+
+type cycleA struct {
+	B *cycleB `inject:""`
+}
+
+type cycleB struct {
+	A *cycleA `inject:""`
+}
+
+func bindTestService(t *testing.T, c *Container) {
+	t.Helper()
+	require.NoError(t, c.SingletonType(func() (TestService, error) {
+		return &testServiceImpl{Value: "typed"}, nil
+	}))
+	require.NoError(t, c.Instance("named", TestService(&testServiceImpl{Value: "named"})))
+}
+
+func TestFill(t *testing.T) {
+	c := New()
+	bindTestService(t, c)
+
+	var controller wiredController
+	require.NoError(t, c.Fill(&controller))
+	require.NotNil(t, controller.Repository)
+	assert.Equal(t, "typed", controller.Repository.Service.GetValue())
+	assert.Equal(t, "named", controller.Named.GetValue())
+	assert.Nil(t, controller.Untagged)
+
+	assert.Error(t, c.Fill(controller))
+	assert.Error(t, c.Fill((*wiredController)(nil)))
+}
+
+func TestFillErrors(t *testing.T) {
+	c := New()
+
+	var missing wiredRepository
+	err := c.Fill(&missing)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to resolve dependency")
+
+	var unexported struct {
+		service TestService `inject:""`
+	}
+	assert.ErrorContains(t, c.Fill(&unexported), "unexported")
+}
+
+func TestBuild(t *testing.T) {
+	c := New()
+	bindTestService(t, c)
+
+	controller, err := Build[*wiredController](c)
+	require.NoError(t, err)
+	assert.Equal(t, "typed", controller.Repository.Service.GetValue())
+
+	value, err := Build[wiredRepository](c)
+	require.NoError(t, err)
+	assert.Equal(t, "typed", value.Service.GetValue())
+
+	another, err := Build[*AnotherService](c, func(s TestService, repo *wiredRepository) (*AnotherService, error) {
+		assert.NotNil(t, repo.Service)
+		return &AnotherService{Service: s}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "typed", another.Service.GetValue())
+
+	_, err = Build[*AnotherService](c, func() (*AnotherService, error) { return nil, errors.New("boom") })
+	assert.EqualError(t, err, "boom")
+
+	_, err = Build[TestService](c)
+	assert.ErrorContains(t, err, "without a constructor")
+
+	assert.NotPanics(t, func() { MustBuild[*wiredRepository](c) })
+}
+
+func TestBuildAppliesContextualBindings(t *testing.T) {
+	c := New()
+	bindTestService(t, c)
+	c.When((*wiredRepository)(nil)).Needs((*TestService)(nil)).Give(&testServiceImpl{Value: "contextual"})
+
+	controller, err := Build[*wiredController](c)
+	require.NoError(t, err)
+	assert.Equal(t, "contextual", controller.Repository.Service.GetValue())
+}
+
+func TestBuildCircularDependency(t *testing.T) {
+	c := New()
+
+	_, err := Build[*cycleA](c)
+	require.ErrorIs(t, err, ErrCircularDependency)
+	assert.Contains(t, err.Error(), "cycleA` -> `*github.com/genesysflow/go-genesys/container.cycleB` -> `*github.com/genesysflow/go-genesys/container.cycleA`")
+}
+
+func TestBoundCircularDependency(t *testing.T) {
+	c := New()
+	require.NoError(t, c.SingletonType(func(s TestService) (*cycleA, error) { return &cycleA{}, nil }))
+	require.NoError(t, c.SingletonType(func(a *cycleA) (TestService, error) { return &testServiceImpl{}, nil }))
+
+	_, err := c.Make("*github.com/genesysflow/go-genesys/container.cycleA")
+	require.ErrorIs(t, err, ErrCircularDependency)
+}
+
+func TestCallReturnsAllResults(t *testing.T) {
+	c := New()
+	bindTestService(t, c)
+
+	results, err := c.Call(func(s TestService, repo *wiredRepository) (string, int, error) {
+		return s.GetValue(), 2, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []any{"typed", 2}, results)
+}
@@ -17,13 +17,26 @@ type Container struct {
 	injector *do.RootScope
 	mu       sync.RWMutex
 	bindings map[string]bool // Track named bindings
+
+	// contextual holds what each consumer is given for a dependency.
+	contextual map[string]map[string]any
+
+	// tags holds the names of the services with each tag.
+	tags map[string][]string
+
+	// lifetimes holds the lifetime of the services registered by name, and
+	// extenders the decorators Extend added to each.
+	lifetimes map[string]string
+	extenders map[string][]func(any) any
 }
 
 // New creates a new container instance.
 func New() *Container {
 	return &Container{
-		injector: do.New(),
-		bindings: make(map[string]bool),
+		injector:  do.New(),
+		bindings:  make(map[string]bool),
+		lifetimes: make(map[string]string),
+		extenders: make(map[string][]func(any) any),
 	}
 }
 
@@ -38,15 +51,12 @@ func (c *Container) Bind(name string, factory any) error {
 	defer c.mu.Unlock()
 
 	// Register the factory to be invoked on demand
+	c.lifetimes[name] = LifetimeTransient
 	if c.bindings[name] {
-		do.OverrideNamedTransient(c.injector, name, func(i do.Injector) (any, error) {
-			return c.invokeFactory(factory)
-		})
+		do.OverrideNamedTransient(c.injector, name, c.provider(name, factory))
 	} else {
 		c.bindings[name] = true
-		do.ProvideNamedTransient(c.injector, name, func(i do.Injector) (any, error) {
-			return c.invokeFactory(factory)
-		})
+		do.ProvideNamedTransient(c.injector, name, c.provider(name, factory))
 	}
 	return nil
 }
@@ -57,19 +67,28 @@ func (c *Container) Singleton(name string, factory any) error {
 	defer c.mu.Unlock()
 
 	// Register the factory as a singleton
+	c.lifetimes[name] = LifetimeSingleton
 	if c.bindings[name] {
-		do.OverrideNamed(c.injector, name, func(i do.Injector) (any, error) {
-			return c.invokeFactory(factory)
-		})
+		do.OverrideNamed(c.injector, name, c.provider(name, factory))
 	} else {
 		c.bindings[name] = true
-		do.ProvideNamed(c.injector, name, func(i do.Injector) (any, error) {
-			return c.invokeFactory(factory)
-		})
+		do.ProvideNamed(c.injector, name, c.provider(name, factory))
 	}
 	return nil
 }
 
+// provider returns the do provider for a factory bound under name, which
+// builds the service and applies its extenders.
+func (c *Container) provider(name string, factory any) func(do.Injector) (any, error) {
+	return func(i do.Injector) (any, error) {
+		instance, err := c.invokeFactory(&injection{injector: i, consumers: consumersOf(name, factory)}, factory)
+		if err != nil {
+			return nil, err
+		}
+		return c.decorate(name, instance), nil
+	}
+}
+
 // BindType registers a factory function, inferring the service name from the return type.
 func (c *Container) BindType(factory any) error {
 	name, err := inferServiceName(factory)
@@ -104,7 +123,7 @@ func inferServiceName(factory any) (string, error) {
 }
 
 // invokeFactory executes the given factory function, injecting the container if needed.
-func (c *Container) invokeFactory(factory any) (any, error) {
+func (c *Container) invokeFactory(inj *injection, factory any) (any, error) {
 	val := reflect.ValueOf(factory)
 
 	// If it's not a function, return the value as is
@@ -112,7 +131,17 @@ func (c *Container) invokeFactory(factory any) (any, error) {
 		return factory, nil
 	}
 
-	t := val.Type()
+	results, err := c.call(inj, val)
+	if err != nil || len(results) == 0 {
+		return nil, err
+	}
+	return results[0], nil
+}
+
+// call invokes a function with its arguments resolved, returning its
+// results without a trailing error.
+func (c *Container) call(inj *injection, fn reflect.Value) ([]any, error) {
+	t := fn.Type()
 	args := make([]reflect.Value, t.NumIn())
 
 	for i := 0; i < t.NumIn(); i++ {
@@ -128,58 +157,57 @@ func (c *Container) invokeFactory(factory any) (any, error) {
 
 		// 2. Resolve by Type
 		// samber/do uses the type string as the service name when using Provide[T].
-		serviceName := GetTypeName(argType)
-
-		// Check if we have it
-		instance, err := c.Make(serviceName)
+		instance, err := c.resolveType(inj, argType)
 		if err != nil {
-			return nil, fmt.Errorf("container: failed to resolve dependency '%s' (type %s): %w", serviceName, argType, err)
+			return nil, err
+		}
+		if instance == nil {
+			args[i] = reflect.Zero(argType)
+			continue
 		}
 		args[i] = reflect.ValueOf(instance)
 	}
 
-	results := val.Call(args)
-
-	if len(results) == 0 {
-		return nil, nil
-	}
+	results := fn.Call(args)
 
 	// Check if the last return value is an error
-	last := results[len(results)-1]
-	if last.Type().Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-		if !last.IsNil() {
-			return nil, last.Interface().(error)
+	if len(results) > 0 {
+		last := results[len(results)-1]
+		if last.Type().Implements(reflect.TypeOf((*error)(nil)).Elem()) {
+			if !last.IsNil() {
+				return nil, last.Interface().(error)
+			}
+			results = results[:len(results)-1]
 		}
-		if len(results) > 1 {
-			return results[0].Interface(), nil
-		}
-		return nil, nil
 	}
 
-	return results[0].Interface(), nil
+	values := make([]any, len(results))
+	for i, result := range results {
+		values[i] = result.Interface()
+	}
+	return values, nil
 }
 
-// Call invokes a function, injecting its dependencies.
-// To support full auto-wiring, you would need to map types to do.Invoke calls.
+// Call invokes a function, injecting its dependencies, and returns its
+// results without a trailing error. Dependencies that aren't bound and are
+// pointers to structs are auto-wired, as with Build.
 func (c *Container) Call(function any) ([]any, error) {
 	val := reflect.ValueOf(function)
 	if val.Kind() != reflect.Func {
 		return nil, fmt.Errorf("container: Call expected a function, got %T", function)
 	}
 
-	// Reuse invokeFactory logic or expand it to handle more arguments
-	res, err := c.invokeFactory(function)
-	if err != nil {
-		return nil, err
-	}
-	return []any{res}, nil
+	return c.call(&injection{injector: c.injector, autowire: true}, val)
 }
 
 // Instance registers an already-created instance.
 func (c *Container) Instance(name string, instance any) error {
+	instance = c.decorate(name, instance)
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.lifetimes[name] = LifetimeInstance
 	if c.bindings[name] {
 		do.OverrideNamedValue(c.injector, name, instance)
 	} else {
@@ -0,0 +1,97 @@
+package container
+
+import (
+	"reflect"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// ContextualBinding builds a binding that applies only while a given
+// service is being built. It is created by When.
+type ContextualBinding struct {
+	container  *Container
+	consumer   string
+	dependency string
+}
+
+// When starts a contextual binding for the services built for consumer, so
+// a dependency they share with other services can be given differently:
+//
+//	c.When((*reports.Exporter)(nil)).
+//		Needs((*contracts.Filesystem)(nil)).
+//		Give(func(fs contracts.FilesystemFactory) (contracts.Filesystem, error) {
+//			return fs.Disk("s3"), nil
+//		})
+//
+// The consumer and the dependency may each be a service name, a
+// reflect.Type, a value of the type, or a nil pointer to an interface
+// type. A consumer matches the name a factory was bound under as well as
+// the type it returns.
+func (c *Container) When(consumer any) contracts.ContextualBindingBuilder {
+	return &ContextualBinding{container: c, consumer: serviceKey(consumer)}
+}
+
+// Needs names the dependency the binding replaces.
+func (b *ContextualBinding) Needs(dependency any) contracts.ContextualBindingBuilder {
+	return &ContextualBinding{container: b.container, consumer: b.consumer, dependency: serviceKey(dependency)}
+}
+
+// Give sets what the consumer receives for the dependency: a factory,
+// called with its own dependencies injected each time the consumer is
+// built, or a value given as it is.
+func (b *ContextualBinding) Give(implementation any) {
+	c := b.container
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.contextual == nil {
+		c.contextual = make(map[string]map[string]any)
+	}
+	if c.contextual[b.consumer] == nil {
+		c.contextual[b.consumer] = make(map[string]any)
+	}
+	c.contextual[b.consumer][b.dependency] = implementation
+}
+
+// contextualFor returns what is given for dependency when building any of
+// consumers, if a contextual binding applies.
+func (c *Container) contextualFor(consumers []string, dependency string) (any, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, consumer := range consumers {
+		if implementation, ok := c.contextual[consumer][dependency]; ok {
+			return implementation, true
+		}
+	}
+	return nil, false
+}
+
+// consumersOf returns the names a factory bound under name is known by.
+func consumersOf(name string, factory any) []string {
+	consumers := []string{name}
+	if t := reflect.TypeOf(factory); t != nil && t.Kind() == reflect.Func && t.NumOut() > 0 {
+		if typeName := GetTypeName(t.Out(0)); typeName != name {
+			consumers = append(consumers, typeName)
+		}
+	}
+	return consumers
+}
+
+// serviceKey returns the service name for a name, type or value.
+func serviceKey(service any) string {
+	switch s := service.(type) {
+	case string:
+		return s
+	case reflect.Type:
+		return GetTypeName(s)
+	}
+	t := reflect.TypeOf(service)
+	if t == nil {
+		return ""
+	}
+	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Interface {
+		return GetTypeName(t.Elem())
+	}
+	return GetTypeName(t)
+}
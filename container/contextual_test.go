@@ -0,0 +1,66 @@
+package container
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reportService struct {
+	Storage TestService
+}
+
+type photoService struct {
+	Storage TestService
+}
+
+func TestContextualBinding(t *testing.T) {
+	c := New()
+	require.NoError(t, c.SingletonType(func() (TestService, error) {
+		return &testServiceImpl{Value: "local"}, nil
+	}))
+	require.NoError(t, c.SingletonType(func(s TestService) (*reportService, error) {
+		return &reportService{Storage: s}, nil
+	}))
+	require.NoError(t, c.SingletonType(func(s TestService) (*photoService, error) {
+		return &photoService{Storage: s}, nil
+	}))
+	require.NoError(t, c.Bind("exports", func(s TestService) (*AnotherService, error) {
+		return &AnotherService{Service: s}, nil
+	}))
+
+	c.When((*reportService)(nil)).Needs((*TestService)(nil)).Give(func() (TestService, error) {
+		return &testServiceImpl{Value: "s3"}, nil
+	})
+	c.When("exports").Needs(reflect.TypeOf((*TestService)(nil)).Elem()).Give(&testServiceImpl{Value: "ftp"})
+
+	reports, err := Resolve[*reportService](c)
+	require.NoError(t, err)
+	assert.Equal(t, "s3", reports.Storage.GetValue())
+
+	photos, err := Resolve[*photoService](c)
+	require.NoError(t, err)
+	assert.Equal(t, "local", photos.Storage.GetValue())
+
+	exports, err := Resolve[*AnotherService](c, "exports")
+	require.NoError(t, err)
+	assert.Equal(t, "ftp", exports.Service.GetValue())
+}
+
+func TestContextualBindingInjectsDependencies(t *testing.T) {
+	c := New()
+	require.NoError(t, c.InstanceType("bucket"))
+	require.NoError(t, c.BindType(func(s TestService) (*reportService, error) {
+		return &reportService{Storage: s}, nil
+	}))
+
+	c.When(&reportService{}).Needs((*TestService)(nil)).Give(func(bucket string) (TestService, error) {
+		return &testServiceImpl{Value: bucket}, nil
+	})
+
+	reports, err := Resolve[*reportService](c)
+	require.NoError(t, err)
+	assert.Equal(t, "bucket", reports.Storage.GetValue())
+}
@@ -0,0 +1,71 @@
+package container
+
+import (
+	"fmt"
+
+	"github.com/samber/do/v2"
+)
+
+// Extend wraps a service with decorator without replacing how it's built,
+// so a provider can decorate a service another provider registered:
+//
+//	app.Extend("logger", func(old any) any {
+//		return &countingLogger{Logger: old.(contracts.Logger)}
+//	})
+//
+// Decorators run in the order they were added, each given the result of
+// the one before. A singleton or instance that already exists is wrapped
+// at once; other services are wrapped when they're built, including ones
+// registered after Extend is called. A service registered with the
+// generic Provide functions is replaced by its wrapped value, which is
+// then only resolvable by name, as with Make and Resolve.
+func (c *Container) Extend(name string, decorator func(old any) any) error {
+	c.mu.Lock()
+	c.extenders[name] = append(c.extenders[name], decorator)
+	lifetime, managed := c.lifetimes[name]
+	c.mu.Unlock()
+
+	switch {
+	case managed && lifetime == LifetimeTransient:
+		return nil
+	case managed && lifetime == LifetimeSingleton && !c.invoked(name):
+		return nil
+	case !managed && !c.provided(name):
+		return nil
+	}
+
+	current, err := do.InvokeNamed[any](c.injector, name)
+	if err != nil {
+		return fmt.Errorf("container: failed to resolve '%s' to extend it: %w", name, err)
+	}
+	extended := decorator(current)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lifetimes[name] = LifetimeInstance
+	c.bindings[name] = true
+	do.OverrideNamedValue(c.injector, name, extended)
+	return nil
+}
+
+// decorate applies the extenders of a service to an instance of it.
+func (c *Container) decorate(name string, instance any) any {
+	c.mu.RLock()
+	extenders := c.extenders[name]
+	c.mu.RUnlock()
+
+	for _, extender := range extenders {
+		instance = extender(instance)
+	}
+	return instance
+}
+
+// invoked reports whether a service has been resolved.
+func (c *Container) invoked(name string) bool {
+	for _, service := range c.injector.ListInvokedServices() {
+		if service.Service == name {
+			return true
+		}
+	}
+	return false
+}
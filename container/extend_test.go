@@ -0,0 +1,88 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// suffixed decorates a TestService by appending to its value.
+type suffixed struct {
+	inner  TestService
+	suffix string
+}
+
+func (s *suffixed) GetValue() string { return s.inner.GetValue() + s.suffix }
+
+func suffix(value string) func(any) any {
+	return func(old any) any { return &suffixed{inner: old.(TestService), suffix: value} }
+}
+
+func TestExtendBeforeResolving(t *testing.T) {
+	c := New()
+	require.NoError(t, c.Singleton("shared", func() (TestService, error) {
+		return &testServiceImpl{Value: "shared"}, nil
+	}))
+	require.NoError(t, c.Bind("transient", func() (TestService, error) {
+		return &testServiceImpl{Value: "transient"}, nil
+	}))
+
+	require.NoError(t, c.Extend("shared", suffix("+a")))
+	require.NoError(t, c.Extend("shared", suffix("+b")))
+	require.NoError(t, c.Extend("transient", suffix("+c")))
+
+	shared := MustResolve[TestService](c, "shared")
+	assert.Equal(t, "shared+a+b", shared.GetValue())
+	assert.Same(t, shared, MustResolve[TestService](c, "shared"))
+
+	transient := MustResolve[TestService](c, "transient")
+	assert.Equal(t, "transient+c", transient.GetValue())
+	assert.NotSame(t, transient, MustResolve[TestService](c, "transient"))
+}
+
+func TestExtendExistingInstances(t *testing.T) {
+	c := New()
+	require.NoError(t, c.Instance("instance", TestService(&testServiceImpl{Value: "instance"})))
+	require.NoError(t, c.Singleton("resolved", func() (TestService, error) {
+		return &testServiceImpl{Value: "resolved"}, nil
+	}))
+	_, err := c.Make("resolved")
+	require.NoError(t, err)
+
+	require.NoError(t, c.Extend("instance", suffix("+x")))
+	require.NoError(t, c.Extend("resolved", suffix("+y")))
+
+	assert.Equal(t, "instance+x", MustResolve[TestService](c, "instance").GetValue())
+	assert.Equal(t, "resolved+y", MustResolve[TestService](c, "resolved").GetValue())
+}
+
+func TestExtendBeforeRegistering(t *testing.T) {
+	c := New()
+	require.NoError(t, c.Extend("later", suffix("+late")))
+	require.NoError(t, c.Instance("later", TestService(&testServiceImpl{Value: "later"})))
+
+	assert.Equal(t, "later+late", MustResolve[TestService](c, "later").GetValue())
+}
+
+func TestExtendDecoratesDependencies(t *testing.T) {
+	c := New()
+	require.NoError(t, c.SingletonType(func() (TestService, error) {
+		return &testServiceImpl{Value: "inner"}, nil
+	}))
+	require.NoError(t, c.Bind("consumer", func(s TestService) (*AnotherService, error) {
+		return &AnotherService{Service: s}, nil
+	}))
+	require.NoError(t, c.Extend("github.com/genesysflow/go-genesys/container.TestService", suffix("+wrapped")))
+
+	consumer := MustResolve[*AnotherService](c, "consumer")
+	assert.Equal(t, "inner+wrapped", consumer.Service.GetValue())
+}
+
+func TestExtendGenericProvider(t *testing.T) {
+	c := New()
+	ProvideValue[TestService](c, &testServiceImpl{Value: "generic"})
+
+	require.NoError(t, c.Extend("github.com/genesysflow/go-genesys/container.TestService", suffix("+x")))
+	assert.Equal(t, "generic+x", MustResolve[TestService](c).GetValue())
+}
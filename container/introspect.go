@@ -0,0 +1,113 @@
+package container
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/samber/do/v2"
+)
+
+// Lifetimes of a binding.
+const (
+	LifetimeSingleton = "singleton" // built once, on first use
+	LifetimeTransient = "transient" // built on every use
+	LifetimeInstance  = "instance"  // an existing value
+	LifetimeAlias     = "alias"     // another name for a service
+)
+
+// Binding describes a service registered in the container.
+type Binding struct {
+	Name     string `json:"name"`
+	Lifetime string `json:"lifetime"`
+
+	// Resolved reports whether the service has been resolved since it was
+	// registered.
+	Resolved bool `json:"resolved"`
+
+	// Dependencies are the services resolved while building it. They are
+	// known once the service is resolved.
+	Dependencies []string `json:"dependencies,omitempty"`
+
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Bindings describes every service registered in the container, sorted by
+// name.
+func (c *Container) Bindings() []Binding {
+	invoked := make(map[string]bool)
+	for _, service := range c.injector.ListInvokedServices() {
+		invoked[service.Service] = true
+	}
+
+	c.mu.RLock()
+	tags := make(map[string][]string)
+	for tag, names := range c.tags {
+		for _, name := range names {
+			tags[name] = append(tags[name], tag)
+		}
+	}
+	c.mu.RUnlock()
+
+	var bindings []Binding
+	for _, service := range c.injector.ListProvidedServices() {
+		if service.ScopeID != c.injector.ID() {
+			continue
+		}
+		binding := Binding{Name: service.Service, Resolved: invoked[service.Service], Tags: tags[service.Service]}
+		if explained, ok := do.ExplainNamedService(c.injector, service.Service); ok {
+			binding.Lifetime = lifetimeOf(explained.ServiceType)
+			for _, dependency := range explained.Dependencies {
+				binding.Dependencies = append(binding.Dependencies, dependency.Service)
+			}
+		}
+		slices.Sort(binding.Tags)
+		bindings = append(bindings, binding)
+	}
+
+	slices.SortFunc(bindings, func(a, b Binding) int { return strings.Compare(a.Name, b.Name) })
+	return bindings
+}
+
+// lifetimeOf names a do service type.
+func lifetimeOf(serviceType do.ServiceType) string {
+	switch serviceType {
+	case do.ServiceTypeLazy:
+		return LifetimeSingleton
+	case do.ServiceTypeTransient:
+		return LifetimeTransient
+	case do.ServiceTypeEager:
+		return LifetimeInstance
+	case do.ServiceTypeAlias:
+		return LifetimeAlias
+	default:
+		return string(serviceType)
+	}
+}
+
+// WriteDOT writes bindings as a Graphviz DOT graph, with an edge from each
+// service to each of its dependencies. Resolved services are drawn solid
+// and the others dashed. Render it with, for example, dot -Tsvg.
+func WriteDOT(w io.Writer, bindings []Binding) error {
+	var b strings.Builder
+	b.WriteString("digraph container {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box, fontname=\"Helvetica\"];\n")
+	for _, binding := range bindings {
+		style := "solid"
+		if !binding.Resolved {
+			style = "dashed"
+		}
+		fmt.Fprintf(&b, "\t%q [label=%q, style=%s];\n", binding.Name, binding.Name+"\n"+binding.Lifetime, style)
+	}
+	for _, binding := range bindings {
+		for _, dependency := range binding.Dependencies {
+			fmt.Fprintf(&b, "\t%q -> %q;\n", binding.Name, dependency)
+		}
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,41 @@
+package container
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindings(t *testing.T) {
+	c := New()
+	require.NoError(t, c.SingletonType(func() (TestService, error) {
+		return &testServiceImpl{Value: "x"}, nil
+	}))
+	require.NoError(t, c.Bind("another", func(s TestService) (*AnotherService, error) {
+		return &AnotherService{Service: s}, nil
+	}))
+	require.NoError(t, c.Instance("value", 1))
+	c.Tag("values", "value")
+
+	_, err := c.Make("another")
+	require.NoError(t, err)
+
+	bindings := c.Bindings()
+	require.Len(t, bindings, 3)
+	assert.Equal(t, Binding{
+		Name:         "another",
+		Lifetime:     LifetimeTransient,
+		Resolved:     true,
+		Dependencies: []string{"github.com/genesysflow/go-genesys/container.TestService"},
+	}, bindings[0])
+	assert.Equal(t, LifetimeSingleton, bindings[1].Lifetime)
+	assert.True(t, bindings[1].Resolved)
+	assert.Equal(t, Binding{Name: "value", Lifetime: LifetimeInstance, Tags: []string{"values"}}, bindings[2])
+
+	var dot strings.Builder
+	require.NoError(t, WriteDOT(&dot, bindings))
+	assert.Contains(t, dot.String(), `"another" -> "github.com/genesysflow/go-genesys/container.TestService";`)
+	assert.Contains(t, dot.String(), `"value" [label="value\ninstance", style=dashed];`)
+}
@@ -0,0 +1,67 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// Tag adds services, by name, to a tag so they can be resolved together
+// with Tagged, such as every report registered by providers:
+//
+//	app.Tag("reports", "reports.sales", "reports.signups")
+//	reports, err := container.ResolveTagged[Report](app, "reports")
+//
+// Services are resolved in the order they were tagged; tagging a service
+// twice has no effect.
+func (c *Container) Tag(tag string, services ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.tags == nil {
+		c.tags = make(map[string][]string)
+	}
+	for _, service := range services {
+		if !slices.Contains(c.tags[tag], service) {
+			c.tags[tag] = append(c.tags[tag], service)
+		}
+	}
+}
+
+// Tagged resolves every service with a tag. A tag nothing was added to
+// resolves to no services.
+func (c *Container) Tagged(tag string) ([]any, error) {
+	c.mu.RLock()
+	names := slices.Clone(c.tags[tag])
+	c.mu.RUnlock()
+
+	services := make([]any, 0, len(names))
+	for _, name := range names {
+		service, err := c.Make(name)
+		if err != nil {
+			return nil, fmt.Errorf("container: failed to resolve '%s' tagged '%s': %w", name, tag, err)
+		}
+		services = append(services, service)
+	}
+	return services, nil
+}
+
+// ResolveTagged resolves every service with a tag as a T. It accepts any
+// contracts.Container, allowing usage with Application directly.
+func ResolveTagged[T any](c contracts.Container, tag string) ([]T, error) {
+	services, err := c.Tagged(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	typed := make([]T, len(services))
+	for i, service := range services {
+		var ok bool
+		if typed[i], ok = service.(T); !ok {
+			return nil, fmt.Errorf("container: service %T tagged '%s' is not of type %s", service, tag, reflect.TypeOf((*T)(nil)).Elem())
+		}
+	}
+	return typed, nil
+}
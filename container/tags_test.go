@@ -0,0 +1,46 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagged(t *testing.T) {
+	c := New()
+	require.NoError(t, c.Instance("reports.sales", TestService(&testServiceImpl{Value: "sales"})))
+	require.NoError(t, c.Bind("reports.signups", func() (TestService, error) {
+		return &testServiceImpl{Value: "signups"}, nil
+	}))
+
+	c.Tag("reports", "reports.sales")
+	c.Tag("reports", "reports.signups", "reports.sales")
+
+	services, err := c.Tagged("reports")
+	require.NoError(t, err)
+	assert.Len(t, services, 2)
+
+	reports, err := ResolveTagged[TestService](c, "reports")
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.Equal(t, "sales", reports[0].GetValue())
+	assert.Equal(t, "signups", reports[1].GetValue())
+
+	empty, err := ResolveTagged[TestService](c, "none")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestTaggedErrors(t *testing.T) {
+	c := New()
+	require.NoError(t, c.Instance("name", "not a service"))
+
+	c.Tag("missing", "unknown")
+	_, err := c.Tagged("missing")
+	assert.ErrorContains(t, err, "'unknown' tagged 'missing'")
+
+	c.Tag("wrong", "name")
+	_, err = ResolveTagged[TestService](c, "wrong")
+	assert.ErrorContains(t, err, "is not of type container.TestService")
+}
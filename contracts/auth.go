@@ -0,0 +1,59 @@
+package contracts
+
+import "context"
+
+// Authenticatable is implemented by authenticated users. Middleware find the
+// current user in the request context under the "user" key.
+type Authenticatable interface {
+	// GetAuthIdentifier returns the user's unique identifier.
+	GetAuthIdentifier() any
+}
+
+// HasAuthPassword is implemented by users that log in with a password.
+type HasAuthPassword interface {
+	// GetAuthPassword returns the user's hashed password.
+	GetAuthPassword() string
+}
+
+// UserProvider retrieves users for guards.
+type UserProvider interface {
+	// RetrieveByID returns the user with the given identifier, or nil.
+	RetrieveByID(ctx context.Context, id any) (Authenticatable, error)
+
+	// RetrieveByCredentials returns the user matching the credentials,
+	// ignoring the password, or nil.
+	RetrieveByCredentials(ctx context.Context, credentials map[string]any) (Authenticatable, error)
+
+	// ValidateCredentials checks the credentials' password against the user.
+	ValidateCredentials(user Authenticatable, credentials map[string]any) bool
+}
+
+// Guard authenticates the user of a request.
+type Guard interface {
+	// User returns the authenticated user, or nil for a guest.
+	User() (Authenticatable, error)
+
+	// Check reports whether the request is authenticated.
+	Check() bool
+
+	// Guest reports whether the request is not authenticated.
+	Guest() bool
+
+	// ID returns the authenticated user's identifier, or nil.
+	ID() any
+
+	// Validate checks credentials without logging the user in.
+	Validate(credentials map[string]any) (bool, error)
+
+	// Attempt validates credentials and logs the user in on success.
+	Attempt(credentials map[string]any) (bool, error)
+
+	// Login logs a user in.
+	Login(user Authenticatable) error
+
+	// Logout logs the current user out.
+	Logout() error
+
+	// SetUser sets the user for the rest of the request.
+	SetUser(user Authenticatable)
+}
@@ -41,4 +41,8 @@ type Config interface {
 
 	// Load loads configuration from a file or directory.
 	Load(path string) error
+
+	// Watch calls fn with the new value of key whenever it changes and
+	// returns a function that stops watching.
+	Watch(key string, fn func(value any)) func()
 }
@@ -33,6 +33,21 @@ type Container interface {
 	// Has checks if a service is registered in the container.
 	Has(name string) bool
 
+	// Extend wraps a service with a decorator without replacing how it's built.
+	Extend(name string, decorator func(old any) any) error
+
+	// Tag adds services, by name, to a tag.
+	Tag(tag string, services ...string)
+
+	// Tagged resolves every service with a tag.
+	Tagged(tag string) ([]any, error)
+
+	// Fill injects the inject-tagged fields of the struct target points to.
+	Fill(target any) error
+
+	// When starts a contextual binding for the services built for consumer.
+	When(consumer any) ContextualBindingBuilder
+
 	// Shutdown gracefully shuts down all services.
 	Shutdown() error
 
@@ -40,6 +55,16 @@ type Container interface {
 	ShutdownWithContext(ctx context.Context) error
 }
 
+// ContextualBindingBuilder builds a binding that applies only while a
+// given service is being built.
+type ContextualBindingBuilder interface {
+	// Needs names the dependency the binding replaces.
+	Needs(dependency any) ContextualBindingBuilder
+
+	// Give sets the factory or value given for the dependency.
+	Give(implementation any)
+}
+
 // ContainerAware is implemented by types that need access to the container.
 type ContainerAware interface {
 	SetContainer(container Container)
@@ -157,6 +157,10 @@ type Transaction interface {
 	// Pass this to SQLC-generated New() functions.
 	Tx() *sql.Tx
 
+	// AfterCommit registers a callback to run once the transaction commits.
+	// Callbacks are discarded if it rolls back.
+	AfterCommit(fn func() error)
+
 	// Commit commits the transaction.
 	Commit() error
 
@@ -6,6 +6,30 @@ import (
 	"time"
 )
 
+// File visibility values.
+const (
+	// VisibilityPublic makes a file readable by anyone.
+	VisibilityPublic = "public"
+
+	// VisibilityPrivate restricts a file to its owner.
+	VisibilityPrivate = "private"
+)
+
+// Checksum algorithms supported by Filesystem.Checksum.
+const (
+	ChecksumMD5    = "md5"
+	ChecksumSHA1   = "sha1"
+	ChecksumSHA256 = "sha256"
+)
+
+// FileMetadata describes a stored file.
+type FileMetadata struct {
+	Size         int64
+	LastModified time.Time
+	ETag         string
+	ContentType  string
+}
+
 // Filesystem defines the interface for filesystem operations.
 type Filesystem interface {
 	// Exists checks if a file exists.
@@ -17,6 +41,9 @@ type Filesystem interface {
 	// GetBytes retrieves the contents of a file as bytes.
 	GetBytes(ctx context.Context, path string) ([]byte, error)
 
+	// ReadStream opens a file for reading. Callers must close the reader.
+	ReadStream(ctx context.Context, path string) (io.ReadCloser, error)
+
 	// Put stores a file.
 	Put(ctx context.Context, path string, contents string) error
 
@@ -26,6 +53,12 @@ type Filesystem interface {
 	// PutStream stores a file from a reader.
 	PutStream(ctx context.Context, path string, contents io.Reader) error
 
+	// Prepend adds content to the beginning of a file, creating it if needed.
+	Prepend(ctx context.Context, path string, contents string) error
+
+	// Append adds content to the end of a file, creating it if needed.
+	Append(ctx context.Context, path string, contents string) error
+
 	// Delete deletes a file.
 	Delete(ctx context.Context, path string) error
 
@@ -49,6 +82,25 @@ type Filesystem interface {
 
 	// Url returns the public URL for the file.
 	Url(path string) string
+
+	// TemporaryUrl returns a URL that grants access to the file until expiry elapses.
+	TemporaryUrl(ctx context.Context, path string, expiry time.Duration) (string, error)
+
+	// SetVisibility sets the visibility of a file (VisibilityPublic or VisibilityPrivate).
+	SetVisibility(ctx context.Context, path string, visibility string) error
+
+	// GetVisibility gets the visibility of a file.
+	GetVisibility(ctx context.Context, path string) (string, error)
+
+	// Checksum returns the hex-encoded checksum of a file using the given
+	// algorithm (ChecksumMD5, ChecksumSHA1 or ChecksumSHA256).
+	Checksum(ctx context.Context, path string, algo string) (string, error)
+
+	// MimeType returns the file's content type.
+	MimeType(ctx context.Context, path string) (string, error)
+
+	// Metadata returns the file's size, modification time, ETag and content type.
+	Metadata(ctx context.Context, path string) (*FileMetadata, error)
 }
 
 // FilesystemFactory defines the interface for creating filesystem instances.
@@ -0,0 +1,14 @@
+package contracts
+
+// Hasher hashes and verifies passwords.
+type Hasher interface {
+	// Make hashes a value.
+	Make(value string) (string, error)
+
+	// Check reports whether value matches hashed.
+	Check(value, hashed string) bool
+
+	// NeedsRehash reports whether hashed was made with other settings
+	// than the hasher's current ones.
+	NeedsRehash(hashed string) bool
+}
@@ -180,6 +180,48 @@ type Response interface {
 	Sent() bool
 }
 
+// UploadedFile is a file uploaded with a multipart request.
+type UploadedFile interface {
+	// Header returns the underlying multipart file header.
+	Header() *multipart.FileHeader
+
+	// ClientName returns the file name sent by the client.
+	ClientName() string
+
+	// Extension returns the lowercase extension of the client file name, without the dot.
+	Extension() string
+
+	// Size returns the file size in bytes.
+	Size() int64
+
+	// ClientMimeType returns the content type sent by the client.
+	ClientMimeType() string
+
+	// MimeType detects the content type from the file's contents.
+	MimeType() (string, error)
+
+	// GuessExtension returns the extension for the detected content type.
+	GuessExtension() (string, error)
+
+	// Open opens the uploaded file for reading.
+	Open() (multipart.File, error)
+
+	// Store stores the file in dir on a disk under a random name, with the
+	// extension of its detected content type, and returns its path. An empty
+	// disk name uses the default disk.
+	Store(disk, dir string) (string, error)
+
+	// StoreAs stores the file in dir on a disk under the given name and returns its path.
+	StoreAs(disk, dir, name string) (string, error)
+
+	// ValidateSize returns an error if the file is larger than max bytes.
+	ValidateSize(max int64) error
+
+	// ValidateMimeType returns an error if the detected content type is not
+	// one of allowed. Entries like "image/*" match any subtype.
+	ValidateMimeType(allowed ...string) error
+}
+
 // Cookie represents an HTTP cookie.
 type Cookie struct {
 	Name     string
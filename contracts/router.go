@@ -104,6 +104,10 @@ type Context interface {
 	// Validate validates the request data against the given rules.
 	Validate(v any) error
 
+	// Validated returns the input the last successful Validate with a map
+	// of rules checked.
+	Validated() map[string]any
+
 	// Get retrieves a value from the context store.
 	Get(key string) any
 
@@ -137,8 +141,17 @@ type Context interface {
 	// HTML sends an HTML response.
 	HTML(html string) error
 
-	// File sends a file response.
-	File(path string) error
+	// File returns an uploaded file.
+	File(key string) (UploadedFile, error)
+
+	// Files returns all files uploaded under a key.
+	Files(key string) ([]UploadedFile, error)
+
+	// HasFile reports whether a file was uploaded under a key.
+	HasFile(key string) bool
+
+	// SendFile sends a file response.
+	SendFile(path string) error
 
 	// Redirect redirects to another URL.
 	Redirect(url string, status ...int) error
@@ -22,7 +22,10 @@ type Session interface {
 	// GetBool retrieves a boolean value from the session.
 	GetBool(key string) bool
 
-	// Set stores a value in the session.
+	// Put stores a value in the session.
+	Put(key string, value any) error
+
+	// Set stores a value in the session. It is an alias of Put.
 	Set(key string, value any) error
 
 	// Has checks if a key exists in the session.
@@ -37,7 +40,7 @@ type Session interface {
 	// Flush removes all values from the session.
 	Flush() error
 
-	// Flash stores a value for the next request only.
+	// Flash stores a value for the current and the next request only.
 	Flash(key string, value any) error
 
 	// Keep keeps specific flash data for another request.
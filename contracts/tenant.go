@@ -0,0 +1,8 @@
+package contracts
+
+// Tenant is implemented by tenants in multi-tenant applications. Middleware
+// find the current tenant in the request context under the "tenant" key.
+type Tenant interface {
+	// GetTenantKey returns the tenant's unique identifier.
+	GetTenantKey() any
+}
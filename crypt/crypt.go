@@ -0,0 +1,160 @@
+// Package crypt provides authenticated symmetric encryption using the
+// application key.
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the required application key length in bytes (AES-256).
+const KeySize = 32
+
+var (
+	// ErrInvalidKey is returned when the key is not KeySize bytes long.
+	ErrInvalidKey = errors.New("crypt: key must be 32 bytes")
+
+	// ErrDecrypt is returned when a payload is malformed or fails authentication.
+	ErrDecrypt = errors.New("crypt: the payload is invalid")
+)
+
+// Encrypter encrypts and decrypts values with AES-256-GCM.
+// Encrypted payloads are base64 encoded and safe to store as strings.
+type Encrypter struct {
+	aead     cipher.AEAD
+	previous []cipher.AEAD
+}
+
+// NewEncrypter creates a new encrypter for the given key. Payloads are
+// always encrypted with key; previous keys are only tried when decrypting,
+// so data encrypted before a key rotation stays readable.
+func NewEncrypter(key []byte, previous ...[]byte) (*Encrypter, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Encrypter{aead: aead}
+	for i, old := range previous {
+		aead, err := newAEAD(old)
+		if err != nil {
+			return nil, fmt.Errorf("previous key %d: %w", i+1, err)
+		}
+		e.previous = append(e.previous, aead)
+	}
+	return e, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("crypt: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("crypt: %w", err)
+	}
+	return aead, nil
+}
+
+// Encrypt encrypts the given bytes.
+func (e *Encrypter) Encrypt(value []byte) (string, error) {
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("crypt: %w", err)
+	}
+
+	sealed := e.aead.Seal(nonce, nonce, value, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a payload produced by Encrypt with the current key or
+// one of the previous keys.
+func (e *Encrypter) Decrypt(payload string) ([]byte, error) {
+	value, _, err := e.decrypt(payload)
+	return value, err
+}
+
+// decrypt also reports whether the payload was encrypted with the current key.
+func (e *Encrypter) decrypt(payload string) ([]byte, bool, error) {
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, false, ErrDecrypt
+	}
+
+	if value, err := open(e.aead, sealed); err == nil {
+		return value, true, nil
+	}
+	for _, aead := range e.previous {
+		if value, err := open(aead, sealed); err == nil {
+			return value, false, nil
+		}
+	}
+	return nil, false, ErrDecrypt
+}
+
+func open(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	nonceSize := aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, ErrDecrypt
+	}
+	return aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+}
+
+// ReEncrypt re-encrypts a payload made with a previous key under the
+// current key. Payloads already using the current key are returned as is.
+func (e *Encrypter) ReEncrypt(payload string) (string, error) {
+	value, current, err := e.decrypt(payload)
+	if err != nil {
+		return "", err
+	}
+	if current {
+		return payload, nil
+	}
+	return e.Encrypt(value)
+}
+
+// EncryptString encrypts a string.
+func (e *Encrypter) EncryptString(value string) (string, error) {
+	return e.Encrypt([]byte(value))
+}
+
+// DecryptString decrypts a payload into a string.
+func (e *Encrypter) DecryptString(payload string) (string, error) {
+	value, err := e.Decrypt(payload)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// ParseKey parses an application key. Keys prefixed with "base64:" are
+// decoded; anything else is used as raw bytes.
+func ParseKey(key string) ([]byte, error) {
+	if encoded, ok := strings.CutPrefix(key, "base64:"); ok {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("crypt: invalid base64 key: %w", err)
+		}
+		return decoded, nil
+	}
+	return []byte(key), nil
+}
+
+// GenerateKey returns a new random key in "base64:" form.
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("crypt: %w", err)
+	}
+	return "base64:" + base64.StdEncoding.EncodeToString(key), nil
+}
@@ -0,0 +1,109 @@
+package crypt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEncrypter(t *testing.T) *Encrypter {
+	t.Helper()
+	enc, err := NewEncrypter([]byte(strings.Repeat("k", KeySize)))
+	require.NoError(t, err)
+	return enc
+}
+
+func TestNewEncrypterRejectsInvalidKey(t *testing.T) {
+	_, err := NewEncrypter([]byte("short"))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	enc := newTestEncrypter(t)
+
+	payload, err := enc.EncryptString("secret value")
+	require.NoError(t, err)
+	assert.NotContains(t, payload, "secret value")
+
+	value, err := enc.DecryptString(payload)
+	require.NoError(t, err)
+	assert.Equal(t, "secret value", value)
+
+	// Each encryption uses a fresh nonce.
+	again, _ := enc.EncryptString("secret value")
+	assert.NotEqual(t, payload, again)
+}
+
+func TestDecryptRejectsTamperedPayload(t *testing.T) {
+	enc := newTestEncrypter(t)
+	payload, _ := enc.EncryptString("secret value")
+
+	tampered := []byte(payload)
+	tampered[len(tampered)/2] ^= 1
+	_, err := enc.Decrypt(string(tampered))
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	_, err = enc.Decrypt("not base64!")
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	other, _ := NewEncrypter([]byte(strings.Repeat("x", KeySize)))
+	_, err = other.Decrypt(payload)
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestParseAndGenerateKey(t *testing.T) {
+	key, err := GenerateKey()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, "base64:"))
+
+	raw, err := ParseKey(key)
+	require.NoError(t, err)
+	assert.Len(t, raw, KeySize)
+
+	raw, err = ParseKey("plain-key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("plain-key"), raw)
+
+	_, err = ParseKey("base64:%%%")
+	assert.Error(t, err)
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKey := []byte(strings.Repeat("o", KeySize))
+	newKey := []byte(strings.Repeat("n", KeySize))
+
+	old, err := NewEncrypter(oldKey)
+	require.NoError(t, err)
+	legacy, err := old.EncryptString("secret value")
+	require.NoError(t, err)
+
+	rotated, err := NewEncrypter(newKey, oldKey)
+	require.NoError(t, err)
+
+	value, err := rotated.DecryptString(legacy)
+	require.NoError(t, err)
+	assert.Equal(t, "secret value", value)
+
+	// New payloads use the current key only.
+	payload, err := rotated.EncryptString("secret value")
+	require.NoError(t, err)
+	_, err = old.Decrypt(payload)
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	reencrypted, err := rotated.ReEncrypt(legacy)
+	require.NoError(t, err)
+	assert.NotEqual(t, legacy, reencrypted)
+	current, _ := NewEncrypter(newKey)
+	value, err = current.DecryptString(reencrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "secret value", value)
+
+	same, err := rotated.ReEncrypt(payload)
+	require.NoError(t, err)
+	assert.Equal(t, payload, same)
+
+	_, err = NewEncrypter(newKey, []byte("short"))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect writes the SQL fragments that differ between drivers, for code
+// that builds its own queries, such as the database cache, session and
+// queue drivers.
+type Dialect struct {
+	driver string
+}
+
+// NewDialect returns the dialect of a driver name, such as a connection's
+// Driver().
+func NewDialect(driver string) Dialect {
+	return Dialect{driver: mapDriver(driver)}
+}
+
+// Postgres reports whether the dialect is PostgreSQL's.
+func (d Dialect) Postgres() bool {
+	return d.driver == "postgres"
+}
+
+// MySQL reports whether the dialect is MySQL's.
+func (d Dialect) MySQL() bool {
+	return d.driver == "mysql"
+}
+
+// Placeholder returns the nth bind parameter, counting from 1: $n on
+// PostgreSQL and ? elsewhere.
+func (d Dialect) Placeholder(n int) string {
+	if d.Postgres() {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+// Quote quotes a table or column name: with backticks on MySQL and double
+// quotes elsewhere. Each part of a dotted name is quoted separately.
+func (d Dialect) Quote(identifier string) string {
+	quote := `"`
+	if d.MySQL() {
+		quote = "`"
+	}
+	parts := strings.Split(identifier, ".")
+	for i, part := range parts {
+		parts[i] = quote + strings.ReplaceAll(part, quote, quote+quote) + quote
+	}
+	return strings.Join(parts, ".")
+}
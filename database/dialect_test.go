@@ -0,0 +1,27 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialect(t *testing.T) {
+	for _, driver := range []string{"pgsql", "postgres", "postgresql"} {
+		d := NewDialect(driver)
+		assert.True(t, d.Postgres(), driver)
+		assert.Equal(t, "$3", d.Placeholder(3))
+		assert.Equal(t, `"public"."my""table"`, d.Quote(`public.my"table`))
+	}
+
+	mysql := NewDialect("mysql")
+	assert.True(t, mysql.MySQL())
+	assert.Equal(t, "?", mysql.Placeholder(3))
+	assert.Equal(t, "`app`.`my``table`", mysql.Quote("app.my`table"))
+
+	sqlite := NewDialect("sqlite")
+	assert.False(t, sqlite.Postgres())
+	assert.False(t, sqlite.MySQL())
+	assert.Equal(t, "?", sqlite.Placeholder(1))
+	assert.Equal(t, `"cache"`, sqlite.Quote("cache"))
+}
@@ -4,6 +4,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -22,7 +23,7 @@ type Config struct {
 
 // ConnectionConfig represents a single database connection configuration.
 type ConnectionConfig struct {
-	// Driver is the database driver (pgsql, sqlite).
+	// Driver is the database driver (pgsql, mysql, sqlite).
 	Driver string `yaml:"driver" json:"driver"`
 
 	// Host is the database host.
@@ -112,7 +113,9 @@ func (m *Manager) Connection(name ...string) contracts.Connection {
 
 // makeConnection creates a new database connection.
 func (m *Manager) makeConnection(name string) (*Connection, error) {
+	m.mu.RLock()
 	config, ok := m.config.Connections[name]
+	m.mu.RUnlock()
 	if !ok {
 		return nil, fmt.Errorf("database connection [%s] not configured", name)
 	}
@@ -231,10 +234,24 @@ func (m *Manager) GetConfig(name ...string) (ConnectionConfig, bool) {
 		connName = name[0]
 	}
 
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	config, ok := m.config.Connections[connName]
 	return config, ok
 }
 
+// AddConnection configures a connection at runtime, such as one per
+// tenant. A connection already open under the name keeps its old settings
+// until it is reconnected.
+func (m *Manager) AddConnection(name string, config ConnectionConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.config.Connections == nil {
+		m.config.Connections = make(map[string]ConnectionConfig)
+	}
+	m.config.Connections[name] = config
+}
+
 // Disconnect disconnects from the given connection.
 func (m *Manager) Disconnect(name ...string) error {
 	connName := m.config.Default
@@ -310,6 +327,15 @@ func buildDSN(config ConnectionConfig) string {
 			config.Host, config.Port, config.Username, config.Password, config.Database, sslMode,
 		)
 
+	case "mysql":
+		if config.Port == 0 {
+			config.Port = 3306
+		}
+		return fmt.Sprintf(
+			"%s:%s@tcp(%s:%d)/%s?parseTime=true",
+			config.Username, config.Password, config.Host, config.Port, config.Database,
+		)
+
 	case "sqlite", "sqlite3":
 		return config.Database
 
@@ -338,6 +364,34 @@ type Connection struct {
 	db     *sql.DB
 	prefix string
 	err    error
+
+	// test is the transaction every query runs in during a test, and
+	// savepoints numbers the transactions nested in it.
+	test       *sql.Tx
+	savepoints int
+	mu         sync.Mutex
+}
+
+// querier is the part of *sql.DB and *sql.Tx queries run on.
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	Exec(query string, args ...any) (sql.Result, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	Prepare(query string) (*sql.Stmt, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// querier returns the test transaction while one is active, or the pool.
+func (c *Connection) querier() querier {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.test != nil {
+		return c.test
+	}
+	return c.db
 }
 
 // Name returns the connection name.
@@ -366,7 +420,7 @@ func (c *Connection) Query(sqlQuery string, bindings ...any) (*sql.Rows, error)
 	if c.err != nil {
 		return nil, c.err
 	}
-	return c.db.Query(sqlQuery, bindings...)
+	return c.querier().Query(sqlQuery, bindings...)
 }
 
 // QueryContext executes a raw query with context.
@@ -374,17 +428,17 @@ func (c *Connection) QueryContext(ctx context.Context, sqlQuery string, bindings
 	if c.err != nil {
 		return nil, c.err
 	}
-	return c.db.QueryContext(ctx, sqlQuery, bindings...)
+	return c.querier().QueryContext(ctx, sqlQuery, bindings...)
 }
 
 // QueryRow executes a query that returns at most one row.
 func (c *Connection) QueryRow(sqlQuery string, bindings ...any) *sql.Row {
-	return c.db.QueryRow(sqlQuery, bindings...)
+	return c.querier().QueryRow(sqlQuery, bindings...)
 }
 
 // QueryRowContext executes a query that returns at most one row with context.
 func (c *Connection) QueryRowContext(ctx context.Context, sqlQuery string, bindings ...any) *sql.Row {
-	return c.db.QueryRowContext(ctx, sqlQuery, bindings...)
+	return c.querier().QueryRowContext(ctx, sqlQuery, bindings...)
 }
 
 // Exec executes a raw statement.
@@ -392,7 +446,7 @@ func (c *Connection) Exec(sqlQuery string, bindings ...any) (sql.Result, error)
 	if c.err != nil {
 		return nil, c.err
 	}
-	return c.db.Exec(sqlQuery, bindings...)
+	return c.querier().Exec(sqlQuery, bindings...)
 }
 
 // ExecContext executes a raw statement with context.
@@ -400,7 +454,7 @@ func (c *Connection) ExecContext(ctx context.Context, sqlQuery string, bindings
 	if c.err != nil {
 		return nil, c.err
 	}
-	return c.db.ExecContext(ctx, sqlQuery, bindings...)
+	return c.querier().ExecContext(ctx, sqlQuery, bindings...)
 }
 
 // Prepare prepares a statement.
@@ -408,7 +462,7 @@ func (c *Connection) Prepare(sqlQuery string) (*sql.Stmt, error) {
 	if c.err != nil {
 		return nil, c.err
 	}
-	return c.db.Prepare(sqlQuery)
+	return c.querier().Prepare(sqlQuery)
 }
 
 // PrepareContext prepares a statement with context.
@@ -416,14 +470,18 @@ func (c *Connection) PrepareContext(ctx context.Context, sqlQuery string) (*sql.
 	if c.err != nil {
 		return nil, c.err
 	}
-	return c.db.PrepareContext(ctx, sqlQuery)
+	return c.querier().PrepareContext(ctx, sqlQuery)
 }
 
-// BeginTransaction starts a transaction.
+// BeginTransaction starts a transaction. During a test transaction, it
+// starts a savepoint instead.
 func (c *Connection) BeginTransaction() (contracts.Transaction, error) {
 	if c.err != nil {
 		return nil, c.err
 	}
+	if c.inTestTransaction() {
+		return c.savepoint(context.Background())
+	}
 	tx, err := c.db.Begin()
 	if err != nil {
 		return nil, err
@@ -431,11 +489,15 @@ func (c *Connection) BeginTransaction() (contracts.Transaction, error) {
 	return &Transaction{tx: tx}, nil
 }
 
-// BeginTx starts a transaction with options.
+// BeginTx starts a transaction with options. During a test transaction, it
+// starts a savepoint instead and ignores the options.
 func (c *Connection) BeginTx(ctx context.Context, opts *sql.TxOptions) (contracts.Transaction, error) {
 	if c.err != nil {
 		return nil, c.err
 	}
+	if c.inTestTransaction() {
+		return c.savepoint(ctx)
+	}
 	tx, err := c.db.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, err
@@ -465,6 +527,62 @@ func (c *Connection) Transaction(fn func(tx contracts.Transaction) error) error
 	return tx.Commit()
 }
 
+// BeginTestTransaction starts a transaction that the connection's queries
+// and transactions run in until RollbackTestTransaction, so a test's
+// writes are undone when it ends. Transactions begun meanwhile are
+// savepoints. Queries on DB() bypass it.
+func (c *Connection) BeginTestTransaction() error {
+	if c.err != nil {
+		return c.err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.test != nil {
+		return fmt.Errorf("database: connection [%s] is already in a test transaction", c.name)
+	}
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	c.test = tx
+	c.savepoints = 0
+	return nil
+}
+
+// RollbackTestTransaction rolls back the test transaction, if any.
+func (c *Connection) RollbackTestTransaction() error {
+	c.mu.Lock()
+	tx := c.test
+	c.test = nil
+	c.mu.Unlock()
+	if tx == nil {
+		return nil
+	}
+	return tx.Rollback()
+}
+
+func (c *Connection) inTestTransaction() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.test != nil
+}
+
+// savepoint starts a transaction nested in the test transaction.
+func (c *Connection) savepoint(ctx context.Context) (contracts.Transaction, error) {
+	c.mu.Lock()
+	tx := c.test
+	c.savepoints++
+	name := fmt.Sprintf("genesys_savepoint_%d", c.savepoints)
+	c.mu.Unlock()
+	if tx == nil {
+		return nil, fmt.Errorf("database: connection [%s] has no test transaction", c.name)
+	}
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return nil, err
+	}
+	return &Transaction{tx: tx, savepoint: name}, nil
+}
+
 // Close closes the connection.
 func (c *Connection) Close() error {
 	if c.err != nil {
@@ -497,7 +615,10 @@ func (c *Connection) Error() error {
 // Transaction represents an active database transaction.
 // It implements the DBTX interface expected by SQLC.
 type Transaction struct {
-	tx *sql.Tx
+	tx          *sql.Tx
+	savepoint   string
+	afterCommit []func() error
+	mu          sync.Mutex
 }
 
 // Query executes a query within the transaction.
@@ -546,12 +667,55 @@ func (t *Transaction) Tx() *sql.Tx {
 	return t.tx
 }
 
-// Commit commits the transaction.
+// AfterCommit registers a callback to run once the transaction commits.
+// Callbacks run in registration order and are discarded on rollback, so
+// side effects such as events never act on rolled-back state.
+func (t *Transaction) AfterCommit(fn func() error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.afterCommit = append(t.afterCommit, fn)
+}
+
+// Commit commits the transaction and runs after-commit callbacks. A callback
+// error is returned wrapped, but the transaction remains committed; every
+// callback runs regardless. A savepoint in a test transaction is released
+// instead of committed.
 func (t *Transaction) Commit() error {
-	return t.tx.Commit()
+	callbacks := t.takeCallbacks()
+	if t.savepoint != "" {
+		if _, err := t.tx.Exec("RELEASE SAVEPOINT " + t.savepoint); err != nil {
+			return err
+		}
+	} else if err := t.tx.Commit(); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, fn := range callbacks {
+		if err := fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("database: transaction committed, but an after-commit callback failed: %w", err)
+	}
+	return nil
 }
 
-// Rollback rolls back the transaction.
+// Rollback rolls back the transaction and discards after-commit callbacks.
 func (t *Transaction) Rollback() error {
+	t.takeCallbacks()
+	if t.savepoint != "" {
+		_, err := t.tx.Exec("ROLLBACK TO SAVEPOINT " + t.savepoint)
+		return err
+	}
 	return t.tx.Rollback()
 }
+
+func (t *Transaction) takeCallbacks() []func() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	callbacks := t.afterCommit
+	t.afterCommit = nil
+	return callbacks
+}
@@ -2,6 +2,8 @@ package database
 
 import (
 	"errors"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/genesysflow/go-genesys/contracts"
@@ -9,28 +11,45 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/lib/pq"
 )
 
-// newTestDatabaseManager creates a database.Manager configured to use the test container.
-func newTestDatabaseManager(pc *testutil.PostgresContainer) *Manager {
+// newTestDatabaseManager creates a database.Manager configured to use the test database.
+func newTestDatabaseManager(td *testutil.Database) *Manager {
 	cfg := Config{
 		Default: "default",
 		Connections: map[string]ConnectionConfig{
-			"default": {
-				Driver:   "pgsql",
-				Host:     pc.Host,
-				Port:     pc.Port,
-				Database: pc.Database,
-				Username: pc.Username,
-				Password: pc.Password,
-				SSLMode:  "disable",
-			},
+			"default": testConnectionConfig(td),
 		},
 	}
 	return NewManager(cfg)
 }
 
+// testConnectionConfig returns the connection config of a test database.
+func testConnectionConfig(td *testutil.Database) ConnectionConfig {
+	return ConnectionConfig{
+		Driver:   td.Driver,
+		Host:     td.Host,
+		Port:     td.Port,
+		Database: td.Database,
+		Username: td.Username,
+		Password: td.Password,
+		SSLMode:  "disable",
+	}
+}
+
+// rebind replaces ? placeholders with PostgreSQL's $1, $2, etc.
+func rebind(driver, query string) string {
+	if mapDriver(driver) != "postgres" {
+		return query
+	}
+	for n := 1; strings.Contains(query, "?"); n++ {
+		query = strings.Replace(query, "?", "$"+strconv.Itoa(n), 1)
+	}
+	return query
+}
+
 func TestNewManager(t *testing.T) {
 	cfg := Config{
 		Default: "default",
@@ -87,6 +106,23 @@ func TestGetConfig(t *testing.T) {
 	assert.False(t, ok)
 }
 
+func TestAddConnection(t *testing.T) {
+	manager := NewManager(Config{Default: "default"})
+
+	_, ok := manager.GetConfig("tenant:acme")
+	assert.False(t, ok)
+
+	manager.AddConnection("tenant:acme", ConnectionConfig{Driver: "sqlite", Database: ":memory:"})
+	connCfg, ok := manager.GetConfig("tenant:acme")
+	assert.True(t, ok)
+	assert.Equal(t, ":memory:", connCfg.Database)
+
+	conn := manager.Connection("tenant:acme")
+	require.NoError(t, conn.Error())
+	assert.Equal(t, "tenant:acme", conn.Name())
+	require.NoError(t, manager.Close())
+}
+
 func TestSetDefaultConnection(t *testing.T) {
 	cfg := Config{
 		Default: "primary",
@@ -158,6 +194,17 @@ func TestBuildDSN(t *testing.T) {
 			},
 			expected: "host=localhost port=5432 user=user password=pass dbname=mydb sslmode=disable",
 		},
+		{
+			name: "mysql",
+			config: ConnectionConfig{
+				Driver:   "mysql",
+				Host:     "localhost",
+				Database: "mydb",
+				Username: "user",
+				Password: "pass",
+			},
+			expected: "user:pass@tcp(localhost:3306)/mydb?parseTime=true",
+		},
 		{
 			name: "sqlite",
 			config: ConnectionConfig{
@@ -228,16 +275,16 @@ func TestConnectionIntegration(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	pc, cleanup := testutil.SetupPostgresContainer(t)
+	td, cleanup := testutil.SetupDatabase(t)
 	defer cleanup()
 
-	manager := newTestDatabaseManager(pc)
+	manager := newTestDatabaseManager(td)
 	defer manager.Close()
 
 	conn := manager.Connection()
 	assert.NotNil(t, conn)
 	assert.NoError(t, conn.Error())
-	assert.Equal(t, "pgsql", conn.Driver())
+	assert.Equal(t, td.Driver, conn.Driver())
 	assert.Equal(t, "default", conn.Name())
 }
 
@@ -246,10 +293,10 @@ func TestConnectionCaching(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	pc, cleanup := testutil.SetupPostgresContainer(t)
+	td, cleanup := testutil.SetupDatabase(t)
 	defer cleanup()
 
-	manager := newTestDatabaseManager(pc)
+	manager := newTestDatabaseManager(td)
 	defer manager.Close()
 
 	conn1 := manager.Connection()
@@ -264,10 +311,10 @@ func TestConnectionPing(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	pc, cleanup := testutil.SetupPostgresContainer(t)
+	td, cleanup := testutil.SetupDatabase(t)
 	defer cleanup()
 
-	manager := newTestDatabaseManager(pc)
+	manager := newTestDatabaseManager(td)
 	defer manager.Close()
 
 	conn := manager.Connection()
@@ -280,10 +327,10 @@ func TestConnectionQuery(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	pc, cleanup := testutil.SetupPostgresContainer(t)
+	td, cleanup := testutil.SetupDatabase(t)
 	defer cleanup()
 
-	manager := newTestDatabaseManager(pc)
+	manager := newTestDatabaseManager(td)
 	defer manager.Close()
 
 	conn := manager.Connection()
@@ -304,10 +351,10 @@ func TestConnectionExec(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	pc, cleanup := testutil.SetupPostgresContainer(t)
+	td, cleanup := testutil.SetupDatabase(t)
 	defer cleanup()
 
-	manager := newTestDatabaseManager(pc)
+	manager := newTestDatabaseManager(td)
 	defer manager.Close()
 
 	conn := manager.Connection()
@@ -315,14 +362,13 @@ func TestConnectionExec(t *testing.T) {
 	// Create a table
 	_, err := conn.Exec(`
 		CREATE TABLE IF NOT EXISTS test_users (
-			id SERIAL PRIMARY KEY,
 			name VARCHAR(255) NOT NULL
 		)
 	`)
 	require.NoError(t, err)
 
 	// Insert a row
-	result, err := conn.Exec("INSERT INTO test_users (name) VALUES ($1)", "John")
+	result, err := conn.Exec(rebind(conn.Driver(), "INSERT INTO test_users (name) VALUES (?)"), "John")
 	require.NoError(t, err)
 
 	rowsAffected, err := result.RowsAffected()
@@ -335,10 +381,10 @@ func TestManagerRaw(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	pc, cleanup := testutil.SetupPostgresContainer(t)
+	td, cleanup := testutil.SetupDatabase(t)
 	defer cleanup()
 
-	manager := newTestDatabaseManager(pc)
+	manager := newTestDatabaseManager(td)
 	defer manager.Close()
 
 	rows, err := manager.Raw("SELECT 42 AS answer")
@@ -357,10 +403,10 @@ func TestTransactionCommit(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	pc, cleanup := testutil.SetupPostgresContainer(t)
+	td, cleanup := testutil.SetupDatabase(t)
 	defer cleanup()
 
-	manager := newTestDatabaseManager(pc)
+	manager := newTestDatabaseManager(td)
 	defer manager.Close()
 
 	conn := manager.Connection()
@@ -368,7 +414,6 @@ func TestTransactionCommit(t *testing.T) {
 	// Create test table
 	_, err := conn.Exec(`
 		CREATE TABLE IF NOT EXISTS tx_test (
-			id SERIAL PRIMARY KEY,
 			value VARCHAR(255)
 		)
 	`)
@@ -376,13 +421,13 @@ func TestTransactionCommit(t *testing.T) {
 
 	// Run transaction
 	err = conn.Transaction(func(tx contracts.Transaction) error {
-		_, err := tx.Exec("INSERT INTO tx_test (value) VALUES ($1)", "committed")
+		_, err := tx.Exec(rebind(conn.Driver(), "INSERT INTO tx_test (value) VALUES (?)"), "committed")
 		return err
 	})
 	require.NoError(t, err)
 
 	// Verify data was committed
-	rows, err := conn.Query("SELECT value FROM tx_test WHERE value = $1", "committed")
+	rows, err := conn.Query(rebind(conn.Driver(), "SELECT value FROM tx_test WHERE value = ?"), "committed")
 	require.NoError(t, err)
 	defer rows.Close()
 	assert.True(t, rows.Next())
@@ -393,10 +438,10 @@ func TestTransactionRollback(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	pc, cleanup := testutil.SetupPostgresContainer(t)
+	td, cleanup := testutil.SetupDatabase(t)
 	defer cleanup()
 
-	manager := newTestDatabaseManager(pc)
+	manager := newTestDatabaseManager(td)
 	defer manager.Close()
 
 	conn := manager.Connection()
@@ -404,7 +449,6 @@ func TestTransactionRollback(t *testing.T) {
 	// Create test table
 	_, err := conn.Exec(`
 		CREATE TABLE IF NOT EXISTS rollback_test (
-			id SERIAL PRIMARY KEY,
 			value VARCHAR(255)
 		)
 	`)
@@ -413,13 +457,13 @@ func TestTransactionRollback(t *testing.T) {
 	// Run transaction that will rollback
 	expectedErr := errors.New("intentional error")
 	err = conn.Transaction(func(tx contracts.Transaction) error {
-		_, _ = tx.Exec("INSERT INTO rollback_test (value) VALUES ($1)", "should-rollback")
+		_, _ = tx.Exec(rebind(conn.Driver(), "INSERT INTO rollback_test (value) VALUES (?)"), "should-rollback")
 		return expectedErr
 	})
 	assert.Equal(t, expectedErr, err)
 
 	// Verify data was NOT committed
-	rows, err := conn.Query("SELECT value FROM rollback_test WHERE value = $1", "should-rollback")
+	rows, err := conn.Query(rebind(conn.Driver(), "SELECT value FROM rollback_test WHERE value = ?"), "should-rollback")
 	require.NoError(t, err)
 	defer rows.Close()
 	assert.False(t, rows.Next())
@@ -430,10 +474,10 @@ func TestBeginTransaction(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	pc, cleanup := testutil.SetupPostgresContainer(t)
+	td, cleanup := testutil.SetupDatabase(t)
 	defer cleanup()
 
-	manager := newTestDatabaseManager(pc)
+	manager := newTestDatabaseManager(td)
 	defer manager.Close()
 
 	conn := manager.Connection()
@@ -441,7 +485,6 @@ func TestBeginTransaction(t *testing.T) {
 	// Create test table
 	_, err := conn.Exec(`
 		CREATE TABLE IF NOT EXISTS manual_tx_test (
-			id SERIAL PRIMARY KEY,
 			value VARCHAR(255)
 		)
 	`)
@@ -450,14 +493,14 @@ func TestBeginTransaction(t *testing.T) {
 	tx, err := conn.BeginTransaction()
 	require.NoError(t, err)
 
-	_, err = tx.Exec("INSERT INTO manual_tx_test (value) VALUES ($1)", "manual-tx")
+	_, err = tx.Exec(rebind(conn.Driver(), "INSERT INTO manual_tx_test (value) VALUES (?)"), "manual-tx")
 	require.NoError(t, err)
 
 	err = tx.Commit()
 	require.NoError(t, err)
 
 	// Verify
-	rows, err := conn.Query("SELECT value FROM manual_tx_test WHERE value = $1", "manual-tx")
+	rows, err := conn.Query(rebind(conn.Driver(), "SELECT value FROM manual_tx_test WHERE value = ?"), "manual-tx")
 	require.NoError(t, err)
 	defer rows.Close()
 	assert.True(t, rows.Next())
@@ -468,10 +511,10 @@ func TestDisconnect(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	pc, cleanup := testutil.SetupPostgresContainer(t)
+	td, cleanup := testutil.SetupDatabase(t)
 	defer cleanup()
 
-	manager := newTestDatabaseManager(pc)
+	manager := newTestDatabaseManager(td)
 	defer manager.Close()
 
 	// Get connection to create it
@@ -492,10 +535,10 @@ func TestReconnect(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	pc, cleanup := testutil.SetupPostgresContainer(t)
+	td, cleanup := testutil.SetupDatabase(t)
 	defer cleanup()
 
-	manager := newTestDatabaseManager(pc)
+	manager := newTestDatabaseManager(td)
 	defer manager.Close()
 
 	conn1 := manager.Connection()
@@ -514,30 +557,14 @@ func TestMultipleConnections(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	pc, cleanup := testutil.SetupPostgresContainer(t)
+	td, cleanup := testutil.SetupDatabase(t)
 	defer cleanup()
 
 	cfg := Config{
 		Default: "primary",
 		Connections: map[string]ConnectionConfig{
-			"primary": {
-				Driver:   "pgsql",
-				Host:     pc.Host,
-				Port:     pc.Port,
-				Database: pc.Database,
-				Username: pc.Username,
-				Password: pc.Password,
-				SSLMode:  "disable",
-			},
-			"secondary": {
-				Driver:   "pgsql",
-				Host:     pc.Host,
-				Port:     pc.Port,
-				Database: pc.Database,
-				Username: pc.Username,
-				Password: pc.Password,
-				SSLMode:  "disable",
-			},
+			"primary":   testConnectionConfig(td),
+			"secondary": testConnectionConfig(td),
 		},
 	}
 
@@ -559,10 +586,10 @@ func TestCloseAll(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	pc, cleanup := testutil.SetupPostgresContainer(t)
+	td, cleanup := testutil.SetupDatabase(t)
 	defer cleanup()
 
-	manager := newTestDatabaseManager(pc)
+	manager := newTestDatabaseManager(td)
 
 	// Create connection
 	conn := manager.Connection()
@@ -578,10 +605,10 @@ func TestConnectionDB(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	pc, cleanup := testutil.SetupPostgresContainer(t)
+	td, cleanup := testutil.SetupDatabase(t)
 	defer cleanup()
 
-	manager := newTestDatabaseManager(pc)
+	manager := newTestDatabaseManager(td)
 	defer manager.Close()
 
 	conn := manager.Connection()
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/genesysflow/go-genesys/database"
 	"github.com/genesysflow/go-genesys/database/schema"
 )
 
@@ -57,15 +58,9 @@ func (m *Migrator) RegisterAll(migrations []Migration) {
 	m.migrations = append(m.migrations, migrations...)
 }
 
-// placeholder returns the correct placeholder for the driver.
-// PostgreSQL uses $1, $2, etc., while MySQL and SQLite use ?.
+// placeholder returns the bind parameter at index in the driver's dialect.
 func (m *Migrator) placeholder(index int) string {
-	switch m.driver {
-	case "postgres", "postgresql", "pgsql":
-		return fmt.Sprintf("$%d", index)
-	default:
-		return "?"
-	}
+	return database.NewDialect(m.driver).Placeholder(index)
 }
 
 // createMigrationsTable creates the migrations table if it doesn't exist.
@@ -0,0 +1,10 @@
+package database
+
+import "github.com/genesysflow/go-genesys/contracts"
+
+// Seeder fills the database with data. Seeders are registered with the
+// MigrationServiceProvider and run by the db:seed command.
+type Seeder interface {
+	// Run inserts the seeder's data.
+	Run(db contracts.DB) error
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "modernc.org/sqlite"
+)
+
+// newSQLiteConnection returns a connection to a fresh SQLite database with a notes table.
+func newSQLiteConnection(t *testing.T) contracts.Connection {
+	t.Helper()
+
+	manager := NewManager(Config{
+		Default: "default",
+		Connections: map[string]ConnectionConfig{
+			"default": {Driver: "sqlite", Database: filepath.Join(t.TempDir(), "test.db")},
+		},
+	})
+	t.Cleanup(func() { manager.Close() })
+
+	conn := manager.Connection()
+	_, err := conn.Exec("CREATE TABLE notes (body TEXT)")
+	require.NoError(t, err)
+	return conn
+}
+
+func TestTransactionAfterCommit(t *testing.T) {
+	conn := newSQLiteConnection(t)
+
+	var seen []int
+	err := conn.Transaction(func(tx contracts.Transaction) error {
+		tx.AfterCommit(func() error {
+			// The callback sees committed state.
+			var count int
+			require.NoError(t, conn.QueryRow("SELECT COUNT(*) FROM notes").Scan(&count))
+			seen = append(seen, count)
+			return nil
+		})
+		_, err := tx.Exec("INSERT INTO notes (body) VALUES (?)", "hello")
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, seen)
+}
+
+func TestTransactionAfterCommitDiscardedOnRollback(t *testing.T) {
+	conn := newSQLiteConnection(t)
+
+	called := false
+	err := conn.Transaction(func(tx contracts.Transaction) error {
+		tx.AfterCommit(func() error {
+			called = true
+			return nil
+		})
+		return errors.New("validation failed")
+	})
+	assert.EqualError(t, err, "validation failed")
+	assert.False(t, called)
+
+	tx, err := conn.BeginTransaction()
+	require.NoError(t, err)
+	tx.AfterCommit(func() error {
+		called = true
+		return nil
+	})
+	require.NoError(t, tx.Rollback())
+	assert.False(t, called)
+}
+
+func TestTransactionAfterCommitErrors(t *testing.T) {
+	conn := newSQLiteConnection(t)
+	listenerErr := errors.New("listener failed")
+
+	ran := 0
+	err := conn.Transaction(func(tx contracts.Transaction) error {
+		tx.AfterCommit(func() error { return listenerErr })
+		tx.AfterCommit(func() error {
+			ran++
+			return nil
+		})
+		_, err := tx.Exec("INSERT INTO notes (body) VALUES (?)", "kept")
+		return err
+	})
+	assert.ErrorIs(t, err, listenerErr)
+	assert.ErrorContains(t, err, "transaction committed")
+	assert.Equal(t, 1, ran)
+
+	var count int
+	require.NoError(t, conn.QueryRow("SELECT COUNT(*) FROM notes").Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func TestConnectionTestTransaction(t *testing.T) {
+	conn := newSQLiteConnection(t)
+	tc := conn.(*Connection)
+
+	require.NoError(t, tc.BeginTestTransaction())
+	assert.Error(t, tc.BeginTestTransaction())
+
+	committed := false
+	err := conn.Transaction(func(tx contracts.Transaction) error {
+		tx.AfterCommit(func() error {
+			committed = true
+			return nil
+		})
+		_, err := tx.Exec("INSERT INTO notes (body) VALUES (?)", "hello")
+		return err
+	})
+	require.NoError(t, err)
+	assert.True(t, committed, "releasing the savepoint runs after-commit callbacks")
+
+	var count int
+	require.NoError(t, conn.QueryRow("SELECT COUNT(*) FROM notes").Scan(&count))
+	assert.Equal(t, 1, count)
+
+	require.NoError(t, tc.RollbackTestTransaction())
+	require.NoError(t, tc.RollbackTestTransaction())
+	require.NoError(t, conn.QueryRow("SELECT COUNT(*) FROM notes").Scan(&count))
+	assert.Equal(t, 0, count)
+}
@@ -2,25 +2,34 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"sync"
 
 	"github.com/genesysflow/go-genesys/contracts"
-	"github.com/gofiber/fiber/v2"
 )
 
-// Handler handles errors and panics.
+// Handler handles errors and panics. It is the application's central
+// exception handler: the HTTP kernel sends every error a handler returns
+// through Handle, which reports it and renders a JSON or HTML response.
 type Handler struct {
 	logger     contracts.Logger
 	debug      bool
 	dontReport []error
 	reporters  []Reporter
+	renderers  []Renderer
+	mu         sync.RWMutex
 }
 
 // Reporter is a function that reports errors.
 type Reporter func(err error, ctx contracts.Context)
 
+// Renderer renders an error response. It returns false to leave the error
+// to the next renderer, or to the default rendering.
+type Renderer func(ctx contracts.Context, err error) (bool, error)
+
 // Config holds error handler configuration.
 type Config struct {
 	Debug      bool
@@ -46,24 +55,66 @@ func NewHandler(config ...Config) *Handler {
 
 // SetLogger sets the logger.
 func (h *Handler) SetLogger(logger contracts.Logger) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	h.logger = logger
 }
 
-// SetDebug sets debug mode.
+// SetDebug sets debug mode. In debug mode responses include the error and
+// a stack trace.
 func (h *Handler) SetDebug(debug bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	h.debug = debug
 }
 
 // AddReporter adds an error reporter.
 func (h *Handler) AddReporter(reporter Reporter) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	h.reporters = append(h.reporters, reporter)
 }
 
+// AddRenderer adds a renderer that runs before the default rendering.
+// Renderers run in the order they were added.
+func (h *Handler) AddRenderer(renderer Renderer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.renderers = append(h.renderers, renderer)
+}
+
 // DontReport adds errors that should not be reported.
 func (h *Handler) DontReport(errs ...error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	h.dontReport = append(h.dontReport, errs...)
 }
 
+// Renderable adds a renderer for errors of type T, matched with errors.As.
+//
+//	errors.Renderable(handler, func(ctx contracts.Context, err *PaymentError) error {
+//		return ctx.Status(402).JSONResponse(map[string]any{"error": err.Reason})
+//	})
+func Renderable[T error](h *Handler, render func(ctx contracts.Context, err T) error) {
+	h.AddRenderer(func(ctx contracts.Context, err error) (bool, error) {
+		var target T
+		if !stderrors.As(err, &target) {
+			return false, nil
+		}
+		return true, render(ctx, target)
+	})
+}
+
+// Reportable adds a reporter for errors of type T, matched with errors.As.
+func Reportable[T error](h *Handler, report func(err T, ctx contracts.Context)) {
+	h.AddReporter(func(err error, ctx contracts.Context) {
+		var target T
+		if stderrors.As(err, &target) {
+			report(target, ctx)
+		}
+	})
+}
+
 // Handle handles an error.
 func (h *Handler) Handle(ctx contracts.Context, err error) error {
 	if h.ShouldReport(err) {
@@ -74,7 +125,12 @@ func (h *Handler) Handle(ctx contracts.Context, err error) error {
 
 // Report reports an error for logging.
 func (h *Handler) Report(err error, ctx ...contracts.Context) {
-	if h.logger != nil {
+	h.mu.RLock()
+	logger := h.logger
+	reporters := h.reporters
+	h.mu.RUnlock()
+
+	if logger != nil {
 		fields := map[string]any{
 			"error": err.Error(),
 		}
@@ -83,13 +139,16 @@ func (h *Handler) Report(err error, ctx ...contracts.Context) {
 			fields["path"] = ctx[0].Request().Path()
 			fields["method"] = ctx[0].Request().Method()
 			fields["ip"] = ctx[0].Request().IP()
+			if requestID, ok := ctx[0].Get("request_id").(string); ok && requestID != "" {
+				fields["request_id"] = requestID
+			}
 		}
 
-		h.logger.WithFields(fields).Error("Error occurred")
+		logger.WithFields(fields).Error("Error occurred")
 	}
 
 	// Call custom reporters
-	for _, reporter := range h.reporters {
+	for _, reporter := range reporters {
 		if len(ctx) > 0 {
 			reporter(err, ctx[0])
 		} else {
@@ -98,31 +157,67 @@ func (h *Handler) Report(err error, ctx ...contracts.Context) {
 	}
 }
 
-// ShouldReport determines if the error should be reported.
+// ShouldReport determines if the error should be reported. Client errors
+// (4xx), such as validation failures and missing models, are not reported.
 func (h *Handler) ShouldReport(err error) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
 	for _, dontReport := range h.dontReport {
-		if err == dontReport {
+		if stderrors.Is(err, dontReport) {
 			return false
 		}
 	}
-	return true
+	code, _ := Status(err)
+	return code >= http.StatusInternalServerError
 }
 
-// Render renders an error response.
+// Render renders an error response. Renderers added with AddRenderer or
+// Renderable run first. Otherwise requests that want JSON get a JSON body
+// and others, such as browsers, an HTML page. In debug mode both include
+// the error and a stack trace.
 func (h *Handler) Render(ctx contracts.Context, err error) error {
-	code := http.StatusInternalServerError
-	message := "Internal Server Error"
+	h.mu.RLock()
+	renderers := h.renderers
+	debugMode := h.debug
+	h.mu.RUnlock()
+
+	for _, render := range renderers {
+		if handled, renderErr := render(ctx, err); handled {
+			return renderErr
+		}
+	}
+
+	code, message := Status(err)
+
+	var fieldErrors map[string][]string
+	if fields, ok := asFieldErrors(err); ok {
+		fieldErrors = fields.All()
+	}
 
-	// Check for HTTP error
-	if httpErr, ok := err.(contracts.HTTPError); ok {
-		code = httpErr.StatusCode()
-		message = httpErr.Message()
+	var stack string
+	if debugMode {
+		var tracer stackTracer
+		if stderrors.As(err, &tracer) {
+			stack = string(tracer.StackTrace())
+		} else {
+			stack = string(debug.Stack())
+		}
 	}
 
-	// Check for Fiber error
-	if fiberErr, ok := err.(*fiber.Error); ok {
-		code = fiberErr.Code
-		message = fiberErr.Message
+	// The request ID lets users quote the failed request.
+	requestID, _ := ctx.Get("request_id").(string)
+
+	if !wantsJSON(ctx) {
+		return ctx.Status(code).HTML(renderPage(page{
+			Code:      code,
+			Message:   message,
+			Fields:    fieldErrors,
+			RequestID: requestID,
+			Debug:     debugMode,
+			Err:       err,
+			Stack:     stack,
+		}))
 	}
 
 	// Build response
@@ -131,11 +226,17 @@ func (h *Handler) Render(ctx contracts.Context, err error) error {
 		"error":   message,
 		"status":  code,
 	}
+	if fieldErrors != nil {
+		response["errors"] = fieldErrors
+	}
+	if requestID != "" {
+		response["request_id"] = requestID
+	}
 
 	// Include stack trace in debug mode
-	if h.debug {
+	if debugMode {
 		response["exception"] = err.Error()
-		response["stack"] = string(debug.Stack())
+		response["stack"] = stack
 	}
 
 	return ctx.Status(code).JSONResponse(response)
@@ -0,0 +1,161 @@
+package errors
+
+import (
+	"database/sql"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/auth"
+	"github.com/genesysflow/go-genesys/contracts"
+	genesyshttp "github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/genesysflow/go-genesys/validation"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, h *Handler, err error, accept string) (int, string, string) {
+	t.Helper()
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return h.Handle(genesyshttp.NewContext(c, testutil.NewMockApplication()), err)
+		},
+	})
+	app.Get("/", func(c *fiber.Ctx) error { return err })
+
+	req := httptest.NewRequest("GET", "/", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, testErr := app.Test(req)
+	require.NoError(t, testErr)
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, resp.Header.Get("Content-Type"), string(body)
+}
+
+func TestStatus(t *testing.T) {
+	invalid := validation.NewValidationErrors()
+	invalid.Add("email", "required")
+
+	tests := []struct {
+		err     error
+		code    int
+		message string
+	}{
+		{invalid, 422, "Validation failed"},
+		{fmt.Errorf("find user: %w", genesyshttp.ErrModelNotFound), 404, "Not Found"},
+		{sql.ErrNoRows, 404, "Not Found"},
+		{auth.Unauthenticated("api"), 401, "Unauthenticated"},
+		{auth.Forbidden(), 403, "This action is unauthorized."},
+		{auth.ErrInvalidToken, 401, "Unauthenticated"},
+		{NotFound("No such post"), 404, "No such post"},
+		{fiber.NewError(409, "Taken"), 409, "Taken"},
+		{stderrors.New("db password is hunter2"), 500, "Internal Server Error"},
+	}
+	for _, tt := range tests {
+		code, message := Status(tt.err)
+		assert.Equal(t, tt.code, code, tt.err.Error())
+		assert.Equal(t, tt.message, message, tt.err.Error())
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	invalid := validation.NewValidationErrors()
+	invalid.Add("email", "The email field is required.")
+
+	code, contentType, body := serve(t, NewHandler(), invalid, "application/json")
+	assert.Equal(t, 422, code)
+	assert.Contains(t, contentType, "application/json")
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal([]byte(body), &decoded))
+	assert.Equal(t, "Validation failed", decoded["error"])
+	assert.Equal(t, map[string]any{"email": []any{"The email field is required."}}, decoded["errors"])
+	assert.NotContains(t, decoded, "stack")
+
+	// Without an Accept header, clients get JSON too.
+	_, contentType, _ = serve(t, NewHandler(), sql.ErrNoRows, "")
+	assert.Contains(t, contentType, "application/json")
+}
+
+func TestRenderHTML(t *testing.T) {
+	err := stderrors.New("<b>boom</b>")
+
+	code, contentType, body := serve(t, NewHandler(), err, "text/html,application/xhtml+xml,*/*;q=0.8")
+	assert.Equal(t, 500, code)
+	assert.Contains(t, contentType, "text/html")
+	assert.Contains(t, body, "Internal Server Error")
+	assert.NotContains(t, body, "boom")
+
+	_, _, body = serve(t, NewHandler(Config{Debug: true}), err, "text/html")
+	assert.Contains(t, body, "&lt;b&gt;boom&lt;/b&gt;")
+	assert.Contains(t, body, "<pre>")
+}
+
+func TestRenderDebugJSON(t *testing.T) {
+	_, _, body := serve(t, NewHandler(Config{Debug: true}), stderrors.New("boom"), "application/json")
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal([]byte(body), &decoded))
+	assert.Equal(t, "Internal Server Error", decoded["error"])
+	assert.Equal(t, "boom", decoded["exception"])
+	assert.NotEmpty(t, decoded["stack"])
+}
+
+type paymentError struct{ reason string }
+
+func (e *paymentError) Error() string { return "payment failed: " + e.reason }
+
+func TestRenderableAndReportable(t *testing.T) {
+	h := NewHandler()
+	Renderable(h, func(ctx contracts.Context, err *paymentError) error {
+		return ctx.Status(402).JSONResponse(map[string]any{"reason": err.reason})
+	})
+	var reported []string
+	Reportable(h, func(err *paymentError, ctx contracts.Context) {
+		reported = append(reported, err.reason)
+	})
+
+	code, _, body := serve(t, h, fmt.Errorf("checkout: %w", &paymentError{reason: "declined"}), "")
+	assert.Equal(t, 402, code)
+	assert.JSONEq(t, `{"reason":"declined"}`, body)
+	assert.Equal(t, []string{"declined"}, reported)
+
+	// Other errors fall through to the default rendering.
+	code, _, _ = serve(t, h, NotFound(), "")
+	assert.Equal(t, 404, code)
+}
+
+func TestShouldReport(t *testing.T) {
+	ignored := stderrors.New("ignored")
+	h := NewHandler(Config{DontReport: []error{ignored}})
+
+	assert.True(t, h.ShouldReport(stderrors.New("boom")))
+	assert.False(t, h.ShouldReport(fmt.Errorf("wrapped: %w", ignored)))
+	assert.False(t, h.ShouldReport(NotFound()))
+	assert.False(t, h.ShouldReport(validation.NewValidationErrors()))
+}
+
+func TestRenderIncludesRequestID(t *testing.T) {
+	h := NewHandler()
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return h.Handle(genesyshttp.NewContext(c, testutil.NewMockApplication()), err)
+		},
+	})
+	app.Get("/", func(c *fiber.Ctx) error {
+		c.Locals("request_id", "req-123")
+		return stderrors.New("boom")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "req-123", body["request_id"])
+}
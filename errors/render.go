@@ -0,0 +1,155 @@
+package errors
+
+import (
+	"bytes"
+	"database/sql"
+	stderrors "errors"
+	"html/template"
+	"net/http"
+	"sort"
+
+	"github.com/genesysflow/go-genesys/auth"
+	"github.com/genesysflow/go-genesys/contracts"
+	genesyshttp "github.com/genesysflow/go-genesys/http"
+	"github.com/gofiber/fiber/v2"
+)
+
+// fieldErrors is implemented by errors that carry per-field messages, such
+// as *validation.ValidationErrors.
+type fieldErrors interface {
+	error
+	All() map[string][]string
+}
+
+// Status returns the HTTP status code and client-facing message for an
+// error. Wrapped errors are unwrapped, so fmt.Errorf("...: %w", err) keeps
+// the status of err:
+//
+//   - contracts.HTTPError, *validation.ValidationErrors and *auth.AuthError
+//     use their own status and message
+//   - *fiber.Error uses its code and message
+//   - http.ErrModelNotFound and sql.ErrNoRows are 404
+//   - auth.ErrInvalidToken and auth.ErrInvalidCredentials are 401
+//   - anything else is a 500, whose message doesn't reveal the error
+func Status(err error) (int, string) {
+	var httpErr contracts.HTTPError
+	if stderrors.As(err, &httpErr) {
+		return httpErr.StatusCode(), httpErr.Message()
+	}
+
+	var fiberErr *fiber.Error
+	if stderrors.As(err, &fiberErr) {
+		return fiberErr.Code, fiberErr.Message
+	}
+
+	switch {
+	case stderrors.Is(err, genesyshttp.ErrModelNotFound), stderrors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound, "Not Found"
+	case stderrors.Is(err, auth.ErrInvalidToken), stderrors.Is(err, auth.ErrInvalidCredentials):
+		return http.StatusUnauthorized, "Unauthenticated"
+	}
+	return http.StatusInternalServerError, "Internal Server Error"
+}
+
+// stackTracer is implemented by errors that carry their own stack trace,
+// such as recovered panics.
+type stackTracer interface {
+	error
+	StackTrace() []byte
+}
+
+func asFieldErrors(err error) (fieldErrors, bool) {
+	var fields fieldErrors
+	ok := stderrors.As(err, &fields)
+	return fields, ok
+}
+
+// wantsJSON reports whether the client prefers JSON over HTML. Clients
+// without an Accept header get JSON.
+func wantsJSON(ctx contracts.Context) bool {
+	return ctx.Request().IsJSON() || ctx.Request().IsAjax()
+}
+
+var errorPage = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Code}} {{.Message}}</title>
+<style>
+body { font-family: system-ui, sans-serif; color: #1f2937; background: #f9fafb; margin: 0; }
+main { max-width: 48rem; margin: 10vh auto; padding: 0 1.5rem; }
+h1 { font-size: 1.5rem; font-weight: 600; }
+h1 span { color: #9ca3af; margin-right: .75rem; }
+ul { padding-left: 1.25rem; }
+pre { background: #111827; color: #e5e7eb; padding: 1rem; overflow-x: auto; font-size: .8rem; border-radius: .375rem; }
+</style>
+</head>
+<body>
+<main>
+<h1><span>{{.Code}}</span>{{.Message}}</h1>
+{{- if .Fields}}
+<ul>
+{{- range .Fields}}
+<li><strong>{{.Name}}</strong>: {{range $i, $m := .Messages}}{{if $i}}; {{end}}{{$m}}{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .RequestID}}
+<p>Request ID: <code>{{.RequestID}}</code></p>
+{{- end}}
+{{- if .Debug}}
+<p>{{.Exception}}</p>
+<pre>{{.Stack}}</pre>
+{{- end}}
+</main>
+</body>
+</html>
+`))
+
+type pageField struct {
+	Name     string
+	Messages []string
+}
+
+// page is the data of an HTML error page.
+type page struct {
+	Code      int
+	Message   string
+	Fields    map[string][]string
+	RequestID string
+	Debug     bool
+	Err       error
+	Stack     string
+}
+
+// renderPage renders the HTML error page.
+func renderPage(p page) string {
+	data := struct {
+		Code      int
+		Message   string
+		Fields    []pageField
+		RequestID string
+		Debug     bool
+		Exception string
+		Stack     string
+	}{Code: p.Code, Message: p.Message, RequestID: p.RequestID, Debug: p.Debug, Stack: p.Stack}
+	if p.Debug {
+		data.Exception = p.Err.Error()
+	}
+
+	names := make([]string, 0, len(p.Fields))
+	for name := range p.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data.Fields = append(data.Fields, pageField{Name: name, Messages: p.Fields[name]})
+	}
+
+	var buf bytes.Buffer
+	if err := errorPage.Execute(&buf, data); err != nil {
+		return http.StatusText(p.Code)
+	}
+	return buf.String()
+}
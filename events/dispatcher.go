@@ -10,6 +10,7 @@ type Listener func(event Event) error
 // Dispatcher manages event listeners and dispatching.
 type Dispatcher struct {
 	listeners map[string][]Listener
+	wildcards []Listener
 	mu        sync.RWMutex
 }
 
@@ -27,10 +28,18 @@ func (d *Dispatcher) Listen(eventName string, listener Listener) {
 	d.listeners[eventName] = append(d.listeners[eventName], listener)
 }
 
+// ListenAll registers a listener for every event. It runs after the
+// event's own listeners.
+func (d *Dispatcher) ListenAll(listener Listener) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.wildcards = append(d.wildcards, listener)
+}
+
 // Dispatch dispatches an event to all registered listeners.
 func (d *Dispatcher) Dispatch(event Event) error {
 	d.mu.RLock()
-	listeners := d.listeners[event.Name()]
+	listeners := append(append([]Listener(nil), d.listeners[event.Name()]...), d.wildcards...)
 	d.mu.RUnlock()
 
 	for _, listener := range listeners {
@@ -54,3 +63,23 @@ func (d *Dispatcher) Forget(eventName string) {
 	defer d.mu.Unlock()
 	delete(d.listeners, eventName)
 }
+
+// Transaction is a unit of work that can defer callbacks until it commits.
+// database.Transaction implements it.
+type Transaction interface {
+	AfterCommit(fn func() error)
+}
+
+// DispatchAfterCommit dispatches an event once tx commits, so listeners
+// never act on state that is rolled back. Events are dropped on rollback.
+// Listener errors are returned from the transaction's Commit. With a nil
+// tx the event is dispatched immediately.
+func (d *Dispatcher) DispatchAfterCommit(tx Transaction, event Event) error {
+	if tx == nil {
+		return d.Dispatch(event)
+	}
+	tx.AfterCommit(func() error {
+		return d.Dispatch(event)
+	})
+	return nil
+}
@@ -120,6 +120,24 @@ func TestDispatchStopsOnError(t *testing.T) {
 	assert.False(t, secondCalled, "Second listener should not be called when first errors")
 }
 
+func TestListenAll(t *testing.T) {
+	d := NewDispatcher()
+
+	var order []string
+	d.ListenAll(func(event Event) error {
+		order = append(order, "all:"+event.Name())
+		return nil
+	})
+	d.Listen("a", func(event Event) error {
+		order = append(order, "a")
+		return nil
+	})
+
+	require.NoError(t, d.Dispatch(newTestEvent("a", nil)))
+	require.NoError(t, d.Dispatch(newTestEvent("b", nil)))
+	assert.Equal(t, []string{"a", "all:a", "all:b"}, order)
+}
+
 func TestHasListeners(t *testing.T) {
 	d := NewDispatcher()
 
@@ -246,3 +264,45 @@ func TestMultipleEventTypes(t *testing.T) {
 
 	assert.Equal(t, []string{"b", "a", "c"}, results)
 }
+
+// fakeTransaction buffers after-commit callbacks like database.Transaction.
+type fakeTransaction struct {
+	callbacks []func() error
+}
+
+func (tx *fakeTransaction) AfterCommit(fn func() error) {
+	tx.callbacks = append(tx.callbacks, fn)
+}
+
+func (tx *fakeTransaction) commit() error {
+	var errs []error
+	for _, fn := range tx.callbacks {
+		errs = append(errs, fn())
+	}
+	return errors.Join(errs...)
+}
+
+func TestDispatchAfterCommit(t *testing.T) {
+	d := NewDispatcher()
+	var dispatched []string
+	d.Listen("order.placed", func(event Event) error {
+		dispatched = append(dispatched, event.Name())
+		return nil
+	})
+
+	tx := &fakeTransaction{}
+	require.NoError(t, d.DispatchAfterCommit(tx, newTestEvent("order.placed", nil)))
+	assert.Empty(t, dispatched, "events are buffered until commit")
+
+	require.NoError(t, tx.commit())
+	assert.Equal(t, []string{"order.placed"}, dispatched)
+
+	// Rolled back transactions never run their callbacks.
+	rolledBack := &fakeTransaction{}
+	d.DispatchAfterCommit(rolledBack, newTestEvent("order.placed", nil))
+	assert.Len(t, dispatched, 1)
+
+	// Without a transaction the event is dispatched immediately.
+	require.NoError(t, d.DispatchAfterCommit(nil, newTestEvent("order.placed", nil)))
+	assert.Len(t, dispatched, 2)
+}
@@ -0,0 +1,82 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/genesysflow/go-genesys/queue"
+)
+
+// Listen registers a listener for events of type E, under E's event name.
+// The listener receives E directly, without type assertions:
+//
+//	events.Listen(dispatcher, func(e *UserRegistered) error { ... })
+func Listen[E Event](d *Dispatcher, listener func(event E) error) {
+	name := eventName[E]()
+	d.Listen(name, func(event Event) error {
+		typed, ok := event.(E)
+		if !ok {
+			return fmt.Errorf("events: listener for [%s] expects %T, got %T", name, *new(E), event)
+		}
+		return listener(typed)
+	})
+}
+
+// Dispatch dispatches a typed event. It is equivalent to d.Dispatch, and
+// exists so dispatch sites are checked against the same event type.
+func Dispatch[E Event](d *Dispatcher, event E) error {
+	return d.Dispatch(event)
+}
+
+// QueuedListener handles events of type E on a queue worker.
+// The listener value is serialized with the event, so its exported fields
+// carry any configuration it needs.
+type QueuedListener[E Event] interface {
+	Handle(event E) error
+}
+
+// QueuedEventJob is the queue job that runs a queued listener.
+type QueuedEventJob[E Event, L QueuedListener[E]] struct {
+	Event    E `json:"event"`
+	Listener L `json:"listener"`
+}
+
+// Handle runs the listener with the event.
+func (j *QueuedEventJob[E, L]) Handle() error {
+	return j.Listener.Handle(j.Event)
+}
+
+// ListenQueued registers a listener that runs on queue q instead of during
+// dispatch. The event and listener types are fixed at compile time, and both
+// must be JSON-serializable; it panics at registration if they are not.
+//
+//	events.ListenQueued[*UserRegistered](dispatcher, q, SendWelcomeEmail{})
+//
+// Workers in another process must make the same call so the job type is
+// registered for decoding.
+func ListenQueued[E Event, L QueuedListener[E]](d *Dispatcher, q queue.Queue, listener L) {
+	job := &QueuedEventJob[E, L]{Listener: listener}
+	if _, err := json.Marshal(job); err != nil {
+		panic(fmt.Sprintf("events: queued listener %T for [%s] cannot be serialized: %v", listener, eventName[E](), err))
+	}
+	queue.RegisterJob(job)
+
+	Listen(d, func(event E) error {
+		return q.Push(&QueuedEventJob[E, L]{Event: event, Listener: listener})
+	})
+}
+
+// eventName returns the name of event type E. Pointer types are
+// instantiated so Name methods may read their receiver.
+func eventName[E Event]() string {
+	t := reflect.TypeFor[E]()
+	switch t.Kind() {
+	case reflect.Interface:
+		panic(fmt.Sprintf("events: %s is not a concrete event type", t))
+	case reflect.Pointer:
+		return reflect.New(t.Elem()).Interface().(E).Name()
+	}
+	var zero E
+	return zero.Name()
+}
@@ -0,0 +1,98 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/crypt"
+	"github.com/genesysflow/go-genesys/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type userRegistered struct {
+	Email string `json:"email"`
+}
+
+func (userRegistered) Name() string { return "user.registered" }
+
+type orderShipped struct {
+	ID int `json:"id"`
+}
+
+func (e *orderShipped) Name() string { return "order.shipped" }
+
+func TestTypedListen(t *testing.T) {
+	d := NewDispatcher()
+
+	var got []string
+	Listen(d, func(e userRegistered) error {
+		got = append(got, e.Email)
+		return nil
+	})
+	Listen(d, func(e *orderShipped) error {
+		got = append(got, "order")
+		return nil
+	})
+
+	require.NoError(t, Dispatch(d, userRegistered{Email: "ada@example.com"}))
+	require.NoError(t, d.Dispatch(&orderShipped{ID: 1}))
+	assert.Equal(t, []string{"ada@example.com", "order"}, got)
+	assert.True(t, d.HasListeners("user.registered"))
+
+	// An untyped event sharing the name is reported, not cast.
+	err := d.Dispatch(newTestEvent("user.registered", nil))
+	assert.ErrorContains(t, err, "expects events.userRegistered")
+}
+
+func TestTypedListenRequiresConcreteType(t *testing.T) {
+	assert.Panics(t, func() {
+		Listen(NewDispatcher(), func(e Event) error { return nil })
+	})
+}
+
+// welcomeMails records emails handled by sendWelcome.
+var welcomeMails []string
+
+type sendWelcomeMail struct {
+	Template string `json:"template"`
+}
+
+func (l *sendWelcomeMail) Handle(e userRegistered) error {
+	welcomeMails = append(welcomeMails, l.Template+":"+e.Email)
+	return nil
+}
+
+func TestListenQueued(t *testing.T) {
+	welcomeMails = nil
+	encrypter, err := crypt.NewEncrypter([]byte(strings.Repeat("k", crypt.KeySize)))
+	require.NoError(t, err)
+	q := queue.NewEncryptedQueue(queue.NewMemoryQueue(), encrypter)
+
+	d := NewDispatcher()
+	ListenQueued[userRegistered](d, q, &sendWelcomeMail{Template: "welcome"})
+
+	require.NoError(t, Dispatch(d, userRegistered{Email: "ada@example.com"}))
+	assert.Empty(t, welcomeMails, "queued listeners don't run during dispatch")
+
+	// The job round-trips through the encrypted payload with its types intact.
+	job, err := q.Pop(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, job.Handle())
+	assert.Equal(t, []string{"welcome:ada@example.com"}, welcomeMails)
+}
+
+type unserializableListener struct {
+	Callback func() `json:"callback"`
+}
+
+func (l unserializableListener) Handle(e userRegistered) error { return nil }
+
+func TestListenQueuedRejectsUnserializableListeners(t *testing.T) {
+	assert.PanicsWithValue(t,
+		"events: queued listener events.unserializableListener for [user.registered] cannot be serialized: json: unsupported type: func()",
+		func() {
+			ListenQueued[userRegistered](NewDispatcher(), queue.NewMemoryQueue(), unserializableListener{})
+		})
+}
@@ -21,8 +21,11 @@ func App() *foundation.Application {
 	app.Register(&providers.AppServiceProvider{})
 	app.Register(&appProviders.AppServiceProvider{})
 	app.Register(&providers.LogServiceProvider{})
+	app.Register(&providers.CryptServiceProvider{})
 	app.Register(&providers.ValidationServiceProvider{})
 	app.Register(&providers.SessionServiceProvider{})
+	app.Register(&providers.HashingServiceProvider{})
+	app.Register(&providers.AuthServiceProvider{})
 	app.Register(&providers.DatabaseServiceProvider{})
 	app.Register(&providers.FilesystemServiceProvider{})
 
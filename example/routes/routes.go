@@ -12,12 +12,14 @@ func GlobalMiddleware(app *foundation.Application) []http.MiddlewareFunc {
 		middleware.RequestID(),
 		middleware.Logger(app.GetLogger()),
 		middleware.Recover(app.GetLogger()),
-		middleware.CORS(),
 	}
 }
 
 // Register registers all application routes.
 func Register(r *http.Router) {
+	// Answer CORS preflight requests and add CORS headers (config/cors.yaml)
+	r.CORS()
+
 	// Load web routes
 	Web(r)
 
@@ -0,0 +1,61 @@
+// Package auth provides a static facade for issuing and revoking JWTs.
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/genesysflow/go-genesys/auth"
+)
+
+// ErrNoInstance is returned when the auth manager has not been set.
+var ErrNoInstance = errors.New("auth: manager instance not set")
+
+var (
+	instance *auth.Manager
+	mu       sync.RWMutex
+)
+
+// SetInstance sets the auth manager instance.
+// This should be called during application bootstrap.
+func SetInstance(manager *auth.Manager) {
+	mu.Lock()
+	defer mu.Unlock()
+	instance = manager
+}
+
+// GetInstance returns the auth manager instance.
+func GetInstance() *auth.Manager {
+	mu.RLock()
+	defer mu.RUnlock()
+	return instance
+}
+
+// Attempt issues an access and refresh token for the user the credentials
+// match, using a jwt guard or the default guard.
+func Attempt(credentials map[string]any, guard ...string) (*auth.TokenPair, error) {
+	manager := GetInstance()
+	if manager == nil {
+		return nil, ErrNoInstance
+	}
+	return manager.Attempt(context.Background(), credentials, guard...)
+}
+
+// Refresh exchanges a refresh token for new tokens.
+func Refresh(refreshToken string, guard ...string) (*auth.TokenPair, error) {
+	manager := GetInstance()
+	if manager == nil {
+		return nil, ErrNoInstance
+	}
+	return manager.Refresh(context.Background(), refreshToken, guard...)
+}
+
+// Invalidate revokes an access or refresh token.
+func Invalidate(token string, guard ...string) error {
+	manager := GetInstance()
+	if manager == nil {
+		return ErrNoInstance
+	}
+	return manager.Invalidate(context.Background(), token, guard...)
+}
@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/genesysflow/go-genesys/auth"
+)
+
+func TestAttemptWithoutInstance(t *testing.T) {
+	SetInstance(nil)
+	if _, err := Attempt(map[string]any{"email": "ada@example.com"}); err != ErrNoInstance {
+		t.Fatalf("expected ErrNoInstance, got %v", err)
+	}
+}
+
+func TestAttemptUsesManager(t *testing.T) {
+	SetInstance(auth.NewManager())
+	defer SetInstance(nil)
+
+	// The default "web" guard is a session guard, which can't issue tokens.
+	if _, err := Attempt(map[string]any{"email": "ada@example.com"}); err == nil {
+		t.Fatal("expected an error for a non-jwt guard")
+	}
+}
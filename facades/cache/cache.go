@@ -0,0 +1,191 @@
+// Package cache provides a static facade for the default cache store.
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// ErrNoInstance is returned when the cache manager has not been set.
+var ErrNoInstance = errors.New("cache: manager instance not set")
+
+var (
+	instance *cache.Manager
+	mu       sync.RWMutex
+)
+
+// SetInstance sets the cache manager instance.
+// This should be called during application bootstrap.
+func SetInstance(manager *cache.Manager) {
+	mu.Lock()
+	defer mu.Unlock()
+	instance = manager
+}
+
+// GetInstance returns the cache manager instance.
+func GetInstance() *cache.Manager {
+	mu.RLock()
+	defer mu.RUnlock()
+	return instance
+}
+
+// Store returns the repository for a store by name, or the default store.
+func Store(name ...string) (*cache.Repository, error) {
+	manager := GetInstance()
+	if manager == nil {
+		return nil, ErrNoInstance
+	}
+	return manager.Repository(name...)
+}
+
+// Tags returns a tagged cache on the default store, whose Flush removes only
+// the items written under the tags.
+func Tags(names ...string) (*cache.TaggedCache, error) {
+	repository, err := Store()
+	if err != nil {
+		return nil, err
+	}
+	return repository.Tags(names...)
+}
+
+// Lock returns a named lock on the default store. Pass owner to restore a
+// lock acquired elsewhere so it can be released.
+func Lock(name string, ttl time.Duration, owner ...string) (*cache.Lock, error) {
+	repository, err := Store()
+	if err != nil {
+		return nil, err
+	}
+	return repository.Lock(name, ttl, owner...)
+}
+
+// Get retrieves an item, or nil if it is missing or expired.
+func Get(key string) (any, error) {
+	repository, err := Store()
+	if err != nil {
+		return nil, err
+	}
+	return repository.Get(key)
+}
+
+// Has reports whether an item exists.
+func Has(key string) (bool, error) {
+	repository, err := Store()
+	if err != nil {
+		return false, err
+	}
+	return repository.Has(key)
+}
+
+// Put stores an item for ttl. A ttl of zero stores it without expiry.
+func Put(key string, value any, ttl time.Duration) error {
+	repository, err := Store()
+	if err != nil {
+		return err
+	}
+	return repository.Put(key, value, ttl)
+}
+
+// Forever stores an item without expiry.
+func Forever(key string, value any) error {
+	repository, err := Store()
+	if err != nil {
+		return err
+	}
+	return repository.Forever(key, value)
+}
+
+// Add stores an item only if the key is missing, and reports whether it did.
+func Add(key string, value any, ttl time.Duration) (bool, error) {
+	repository, err := Store()
+	if err != nil {
+		return false, err
+	}
+	return repository.Add(key, value, ttl)
+}
+
+// Remember returns the cached item, or computes it with fn and caches it for ttl.
+func Remember(key string, ttl time.Duration, fn func() (any, error)) (any, error) {
+	repository, err := Store()
+	if err != nil {
+		return nil, err
+	}
+	return repository.Remember(key, ttl, fn)
+}
+
+// RememberForever is Remember without expiry.
+func RememberForever(key string, fn func() (any, error)) (any, error) {
+	repository, err := Store()
+	if err != nil {
+		return nil, err
+	}
+	return repository.RememberForever(key, fn)
+}
+
+// Pull retrieves an item and removes it.
+func Pull(key string) (any, error) {
+	repository, err := Store()
+	if err != nil {
+		return nil, err
+	}
+	return repository.Pull(key)
+}
+
+// Increment adds by (default 1) to an integer item and returns the new value.
+func Increment(key string, by ...int64) (int64, error) {
+	repository, err := Store()
+	if err != nil {
+		return 0, err
+	}
+	return repository.Increment(key, by...)
+}
+
+// Decrement subtracts by (default 1) from an integer item and returns the new value.
+func Decrement(key string, by ...int64) (int64, error) {
+	repository, err := Store()
+	if err != nil {
+		return 0, err
+	}
+	return repository.Decrement(key, by...)
+}
+
+// RememberQuery returns the rows of a select on conn, caching them in the
+// default store for ttl. An empty key derives one from the SQL and bindings.
+func RememberQuery(ctx context.Context, conn contracts.DBTX, ttl time.Duration, key, query string, bindings ...any) ([]map[string]any, error) {
+	repository, err := Store()
+	if err != nil {
+		return nil, err
+	}
+	return repository.RememberQuery(ctx, conn, ttl, key, query, bindings...)
+}
+
+// ForgetQuery removes the cached results of a query remembered without a key.
+func ForgetQuery(query string, bindings ...any) error {
+	repository, err := Store()
+	if err != nil {
+		return err
+	}
+	return repository.ForgetQuery(query, bindings...)
+}
+
+// Forget removes an item.
+func Forget(key string) error {
+	repository, err := Store()
+	if err != nil {
+		return err
+	}
+	return repository.Forget(key)
+}
+
+// Flush removes all items from the default store.
+func Flush() error {
+	repository, err := Store()
+	if err != nil {
+		return err
+	}
+	return repository.Flush()
+}
@@ -0,0 +1,31 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/cache"
+)
+
+func TestWithoutInstance(t *testing.T) {
+	SetInstance(nil)
+	if err := Put("key", "value", time.Minute); err != ErrNoInstance {
+		t.Fatalf("expected ErrNoInstance, got %v", err)
+	}
+}
+
+func TestUsesDefaultStore(t *testing.T) {
+	SetInstance(cache.NewManager())
+	defer SetInstance(nil)
+
+	value, err := Remember("answer", time.Minute, func() (any, error) { return 42, nil })
+	if err != nil || value != 42 {
+		t.Fatalf("Remember() = %v, %v", value, err)
+	}
+	if added, _ := Add("answer", 0, time.Minute); added {
+		t.Fatal("Add() stored over an existing key")
+	}
+	if n, _ := Increment("answer"); n != 43 {
+		t.Fatalf("Increment() = %d, want 43", n)
+	}
+}
@@ -0,0 +1,53 @@
+// Package crypt provides a static facade for encrypting values with the
+// application key.
+package crypt
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/genesysflow/go-genesys/crypt"
+)
+
+// ErrNoInstance is returned when no encrypter has been set, usually
+// because app.key is empty.
+var ErrNoInstance = errors.New("crypt: no encrypter is set; is app.key configured?")
+
+var (
+	instance *crypt.Encrypter
+	mu       sync.RWMutex
+)
+
+// SetInstance sets the encrypter instance.
+// This should be called during application bootstrap.
+func SetInstance(encrypter *crypt.Encrypter) {
+	mu.Lock()
+	defer mu.Unlock()
+	instance = encrypter
+}
+
+// GetInstance returns the encrypter instance, or nil.
+func GetInstance() *crypt.Encrypter {
+	mu.RLock()
+	defer mu.RUnlock()
+	return instance
+}
+
+// Encrypt encrypts a string with the application key.
+func Encrypt(value string) (string, error) {
+	encrypter := GetInstance()
+	if encrypter == nil {
+		return "", ErrNoInstance
+	}
+	return encrypter.EncryptString(value)
+}
+
+// Decrypt decrypts a payload made by Encrypt with the current or a
+// previous application key.
+func Decrypt(payload string) (string, error) {
+	encrypter := GetInstance()
+	if encrypter == nil {
+		return "", ErrNoInstance
+	}
+	return encrypter.DecryptString(payload)
+}
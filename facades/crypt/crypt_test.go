@@ -0,0 +1,29 @@
+package crypt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/crypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFacade(t *testing.T) {
+	SetInstance(nil)
+	_, err := Encrypt("secret")
+	assert.ErrorIs(t, err, ErrNoInstance)
+	_, err = Decrypt("payload")
+	assert.ErrorIs(t, err, ErrNoInstance)
+
+	encrypter, err := crypt.NewEncrypter([]byte(strings.Repeat("k", crypt.KeySize)))
+	require.NoError(t, err)
+	SetInstance(encrypter)
+	defer SetInstance(nil)
+
+	payload, err := Encrypt("secret")
+	require.NoError(t, err)
+	value, err := Decrypt(payload)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", value)
+}
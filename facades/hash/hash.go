@@ -0,0 +1,49 @@
+// Package hash provides a static facade for hashing passwords.
+package hash
+
+import (
+	"sync"
+
+	"github.com/genesysflow/go-genesys/hashing"
+)
+
+var (
+	instance *hashing.Manager
+	fallback = hashing.NewManager("")
+	mu       sync.RWMutex
+)
+
+// SetInstance sets the hashing manager instance.
+// This should be called during application bootstrap.
+func SetInstance(manager *hashing.Manager) {
+	mu.Lock()
+	defer mu.Unlock()
+	instance = manager
+}
+
+// GetInstance returns the hashing manager instance. Without one, hashes
+// are made with bcrypt's default cost.
+func GetInstance() *hashing.Manager {
+	mu.RLock()
+	defer mu.RUnlock()
+	if instance == nil {
+		return fallback
+	}
+	return instance
+}
+
+// Make hashes a value with the default driver.
+func Make(value string) (string, error) {
+	return GetInstance().Make(value)
+}
+
+// Check reports whether value matches hashed.
+func Check(value, hashed string) bool {
+	return GetInstance().Check(value, hashed)
+}
+
+// NeedsRehash reports whether hashed should be remade with the current
+// driver and settings.
+func NeedsRehash(hashed string) bool {
+	return GetInstance().NeedsRehash(hashed)
+}
@@ -0,0 +1,80 @@
+// Package lang provides a static facade for the translator.
+package lang
+
+import (
+	"sync"
+
+	"github.com/genesysflow/go-genesys/lang"
+)
+
+var (
+	instance *lang.Translator
+	mu       sync.RWMutex
+)
+
+// SetInstance sets the translator instance.
+// This should be called during application bootstrap.
+func SetInstance(translator *lang.Translator) {
+	mu.Lock()
+	defer mu.Unlock()
+	instance = translator
+}
+
+// GetInstance returns the translator instance.
+func GetInstance() *lang.Translator {
+	mu.RLock()
+	defer mu.RUnlock()
+	return instance
+}
+
+// Trans translates key in the default locale. The key is returned as is
+// when the translator has not been set.
+func Trans(key string, params ...map[string]any) string {
+	translator := GetInstance()
+	if translator == nil {
+		return key
+	}
+	return translator.Trans(key, first(params))
+}
+
+// TransIn translates key in locale.
+func TransIn(locale, key string, params ...map[string]any) string {
+	translator := GetInstance()
+	if translator == nil {
+		return key
+	}
+	return translator.TransIn(locale, key, first(params))
+}
+
+// Choice translates key in the default locale, picking the plural form for
+// count.
+func Choice(key string, count int, params ...map[string]any) string {
+	translator := GetInstance()
+	if translator == nil {
+		return key
+	}
+	return translator.Choice(key, count, first(params))
+}
+
+// Has reports whether key has a translation.
+func Has(key string, locale ...string) bool {
+	translator := GetInstance()
+	return translator != nil && translator.Has(key, locale...)
+}
+
+// Locale returns the default locale, or "" when the translator has not
+// been set.
+func Locale() string {
+	translator := GetInstance()
+	if translator == nil {
+		return ""
+	}
+	return translator.Locale()
+}
+
+func first(params []map[string]any) map[string]any {
+	if len(params) > 0 {
+		return params[0]
+	}
+	return nil
+}
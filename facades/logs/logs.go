@@ -0,0 +1,64 @@
+// Package logs provides a static facade for the log channels.
+package logs
+
+import (
+	"sync"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/log"
+)
+
+var (
+	instance *log.LogManager
+	fallback = log.NewManager()
+	mu       sync.RWMutex
+)
+
+// SetInstance sets the log manager instance.
+// This should be called during application bootstrap.
+func SetInstance(manager *log.LogManager) {
+	mu.Lock()
+	defer mu.Unlock()
+	instance = manager
+}
+
+// GetInstance returns the log manager instance. Without one, logs go to
+// the console.
+func GetInstance() *log.LogManager {
+	mu.RLock()
+	defer mu.RUnlock()
+	if instance == nil {
+		return fallback
+	}
+	return instance
+}
+
+// Channel returns a log channel, such as logs.Channel("audit").
+func Channel(name string) contracts.Logger {
+	return GetInstance().Channel(name)
+}
+
+// Stack returns a logger that writes to each of the channels.
+func Stack(channels ...string) contracts.Logger {
+	return GetInstance().Stack(channels...)
+}
+
+// Debug logs a debug message to the default channel.
+func Debug(msg string, fields ...any) {
+	GetInstance().Debug(msg, fields...)
+}
+
+// Info logs an info message to the default channel.
+func Info(msg string, fields ...any) {
+	GetInstance().Info(msg, fields...)
+}
+
+// Warn logs a warning message to the default channel.
+func Warn(msg string, fields ...any) {
+	GetInstance().Warn(msg, fields...)
+}
+
+// Error logs an error message to the default channel.
+func Error(msg string, fields ...any) {
+	GetInstance().Error(msg, fields...)
+}
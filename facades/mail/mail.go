@@ -0,0 +1,67 @@
+// Package mail provides a static facade for sending mail.
+package mail
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/genesysflow/go-genesys/mail"
+)
+
+// ErrNoManager is returned when the mail manager has not been set.
+var ErrNoManager = errors.New("mail: manager instance not set")
+
+var (
+	instance *mail.Mailer
+	manager  *mail.Manager
+	mu       sync.RWMutex
+)
+
+// SetInstance sets the mailer instance.
+func SetInstance(mailer *mail.Mailer) {
+	mu.Lock()
+	defer mu.Unlock()
+	instance = mailer
+}
+
+// Mailer returns the mailer instance.
+func Mailer() *mail.Mailer {
+	mu.RLock()
+	defer mu.RUnlock()
+	return instance
+}
+
+// SetManager sets the manager holding the named mailers.
+func SetManager(m *mail.Manager) {
+	mu.Lock()
+	defer mu.Unlock()
+	manager = m
+}
+
+// Use returns a named mailer from mail.mailers.
+func Use(name string) (*mail.Mailer, error) {
+	mu.RLock()
+	m := manager
+	mu.RUnlock()
+	if m == nil {
+		return nil, ErrNoManager
+	}
+	return m.Mailer(name)
+}
+
+// Send sends a mailable, queueing it if it implements mail.ShouldQueue.
+func Send(ctx context.Context, mailable mail.Mailable) (string, error) {
+	return Mailer().Send(ctx, mailable)
+}
+
+// SendNow sends a mailable immediately.
+func SendNow(ctx context.Context, mailable mail.Mailable) (string, error) {
+	return Mailer().SendNow(ctx, mailable)
+}
+
+// Later queues a mailable to be sent after the given delay.
+func Later(delay time.Duration, mailable mail.Mailable) (string, error) {
+	return Mailer().Later(delay, mailable)
+}
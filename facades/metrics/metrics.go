@@ -0,0 +1,85 @@
+// Package metrics provides a static facade for recording application metrics.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/genesysflow/go-genesys/metrics"
+)
+
+var (
+	instance *metrics.Recorder
+	saved    *metrics.Recorder
+	faked    bool
+	mu       sync.RWMutex
+)
+
+// SetInstance sets the metrics recorder instance.
+func SetInstance(recorder *metrics.Recorder) {
+	mu.Lock()
+	defer mu.Unlock()
+	instance = recorder
+}
+
+// GetInstance returns the metrics recorder instance. Without one,
+// measurements are discarded.
+func GetInstance() *metrics.Recorder {
+	mu.RLock()
+	defer mu.RUnlock()
+	if instance == nil {
+		return metrics.New(nil, nil)
+	}
+	return instance
+}
+
+// Fake replaces the recorder with an in-memory registry, for asserting on
+// recorded metrics in tests. Call Restore to put the original back.
+func Fake() *metrics.Registry {
+	registry := metrics.NewRegistry()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !faked {
+		saved = instance
+		faked = true
+	}
+	instance = metrics.New(registry, nil)
+	return registry
+}
+
+// Restore puts back the recorder replaced by Fake.
+func Restore() {
+	mu.Lock()
+	defer mu.Unlock()
+	if faked {
+		instance = saved
+		saved = nil
+		faked = false
+	}
+}
+
+// Count increments a counter by one.
+func Count(name string, tags ...metrics.Tags) {
+	GetInstance().Count(name, tags...)
+}
+
+// Add increments a counter by value.
+func Add(name string, value float64, tags ...metrics.Tags) {
+	GetInstance().Add(name, value, tags...)
+}
+
+// Gauge sets a gauge to value.
+func Gauge(name string, value float64, tags ...metrics.Tags) {
+	GetInstance().Gauge(name, value, tags...)
+}
+
+// Histogram records value in a histogram.
+func Histogram(name string, value float64, tags ...metrics.Tags) {
+	GetInstance().Histogram(name, value, tags...)
+}
+
+// Since records the seconds elapsed since start in a histogram.
+func Since(name string, start time.Time, tags ...metrics.Tags) {
+	GetInstance().Since(name, start, tags...)
+}
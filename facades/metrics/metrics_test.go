@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/genesysflow/go-genesys/metrics"
+)
+
+func TestFakeRecordsMetrics(t *testing.T) {
+	original := metrics.New(metrics.NewRegistry(), nil)
+	SetInstance(original)
+	defer SetInstance(nil)
+	defer Restore()
+
+	fake := Fake()
+	Count("orders.placed", metrics.Tags{"plan": "pro"})
+	Add("orders.placed", 2, metrics.Tags{"plan": "pro"})
+	Gauge("queue.depth", 4)
+
+	if got := fake.Value("orders.placed", metrics.Tags{"plan": "pro"}); got != 3 {
+		t.Fatalf("expected orders.placed to be 3, got %v", got)
+	}
+	if got := fake.Value("queue.depth"); got != 4 {
+		t.Fatalf("expected queue.depth to be 4, got %v", got)
+	}
+
+	Restore()
+	if GetInstance() != original {
+		t.Fatal("expected Restore to put back the original recorder")
+	}
+}
+
+func TestWithoutInstanceDiscards(t *testing.T) {
+	SetInstance(nil)
+
+	// Must not panic.
+	Count("orders.placed")
+	Histogram("checkout.seconds", 0.2)
+}
@@ -0,0 +1,33 @@
+// Package notification provides a static facade for sending notifications.
+package notification
+
+import (
+	"context"
+	"sync"
+
+	"github.com/genesysflow/go-genesys/notification"
+)
+
+var (
+	instance *notification.Manager
+	mu       sync.RWMutex
+)
+
+// SetInstance sets the notification manager instance.
+func SetInstance(manager *notification.Manager) {
+	mu.Lock()
+	defer mu.Unlock()
+	instance = manager
+}
+
+// Manager returns the notification manager instance.
+func Manager() *notification.Manager {
+	mu.RLock()
+	defer mu.RUnlock()
+	return instance
+}
+
+// Send sends a notification to each notifiable on the channels it selects.
+func Send(ctx context.Context, n notification.Notification, notifiables ...notification.Notifiable) error {
+	return Manager().Send(ctx, n, notifiables...)
+}
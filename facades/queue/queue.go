@@ -0,0 +1,70 @@
+// Package queue provides a static facade for dispatching jobs.
+package queue
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/genesysflow/go-genesys/queue"
+)
+
+// ErrNoInstance is returned when the queue manager has not been set.
+var ErrNoInstance = errors.New("queue: manager instance not set")
+
+var (
+	instance *queue.Manager
+	mu       sync.RWMutex
+)
+
+// SetInstance sets the queue manager instance.
+// This should be called during application bootstrap.
+func SetInstance(manager *queue.Manager) {
+	mu.Lock()
+	defer mu.Unlock()
+	instance = manager
+}
+
+// GetInstance returns the queue manager instance.
+func GetInstance() *queue.Manager {
+	mu.RLock()
+	defer mu.RUnlock()
+	return instance
+}
+
+// Connection returns a queue connection by name, or the default connection.
+func Connection(name ...string) (queue.Queue, error) {
+	manager := GetInstance()
+	if manager == nil {
+		return nil, ErrNoInstance
+	}
+	return manager.Connection(name...)
+}
+
+// Dispatch pushes a job onto the default connection.
+func Dispatch(job queue.Job) error {
+	manager := GetInstance()
+	if manager == nil {
+		return ErrNoInstance
+	}
+	return manager.Dispatch(job)
+}
+
+// DispatchOn pushes a job onto a named queue of the default connection.
+func DispatchOn(name string, job queue.Job) error {
+	manager := GetInstance()
+	if manager == nil {
+		return ErrNoInstance
+	}
+	return manager.DispatchOn(name, job)
+}
+
+// DispatchAfter pushes a job onto the default connection, to be processed
+// once delay has passed.
+func DispatchAfter(delay time.Duration, job queue.Job) error {
+	manager := GetInstance()
+	if manager == nil {
+		return ErrNoInstance
+	}
+	return manager.DispatchAfter(delay, job)
+}
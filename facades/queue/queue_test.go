@@ -0,0 +1,46 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/queue"
+)
+
+type countJob struct{ count *int }
+
+func (j *countJob) Handle() error {
+	*j.count++
+	return nil
+}
+
+func TestWithoutInstance(t *testing.T) {
+	SetInstance(nil)
+	if err := Dispatch(&countJob{}); err != ErrNoInstance {
+		t.Fatalf("expected ErrNoInstance, got %v", err)
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	manager := queue.NewManager()
+	SetInstance(manager)
+	defer SetInstance(nil)
+
+	count := 0
+	if err := Dispatch(&countJob{count: &count}); err != nil || count != 1 {
+		t.Fatalf("Dispatch() on sync = %v, ran %d times", err, count)
+	}
+
+	memory := queue.NewMemoryQueue()
+	manager.Register("memory", memory)
+	manager.SetDefaultConnection("memory")
+	if err := DispatchOn("high", &countJob{count: &count}); err != nil {
+		t.Fatal(err)
+	}
+	if err := DispatchAfter(time.Minute, &countJob{count: &count}); err != nil {
+		t.Fatal(err)
+	}
+	if memory.SizeOf("high") != 1 || memory.SizeOf(queue.DefaultQueue) != 1 {
+		t.Fatalf("unexpected queue sizes %d and %d", memory.SizeOf("high"), memory.SizeOf(queue.DefaultQueue))
+	}
+}
@@ -8,30 +8,75 @@ import (
 	"time"
 
 	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/filesystem"
 )
 
 var (
 	instance contracts.FilesystemFactory
+	fakes    = make(map[string]contracts.Filesystem)
 	mu       sync.RWMutex
 )
 
 // SetInstance sets the filesystem factory instance.
+// This should be called during application bootstrap.
 func SetInstance(factory contracts.FilesystemFactory) {
 	mu.Lock()
 	defer mu.Unlock()
 	instance = factory
 }
 
-// Disk returns a filesystem instance by name.
+// GetInstance returns the filesystem factory instance.
+func GetInstance() contracts.FilesystemFactory {
+	mu.RLock()
+	defer mu.RUnlock()
+	return instance
+}
+
+// Disk returns a filesystem instance by name, or the default disk.
+// Faked disks take precedence over the factory's disks.
 func Disk(name ...string) contracts.Filesystem {
 	mu.RLock()
 	defer mu.RUnlock()
+	if fake, ok := fakes[diskName(name)]; ok {
+		return fake
+	}
 	if instance == nil {
 		return nil
 	}
 	return instance.Disk(name...)
 }
 
+// Fake replaces a disk, or the default disk, with an empty in-memory disk
+// for tests and returns it. Call Restore to remove the fakes.
+func Fake(name ...string) *filesystem.Memory {
+	disk, _ := filesystem.NewMemory(map[string]any{})
+
+	mu.Lock()
+	defer mu.Unlock()
+	fakes[diskName(name)] = disk
+	return disk
+}
+
+// Restore removes all disks replaced by Fake.
+func Restore() {
+	mu.Lock()
+	defer mu.Unlock()
+	fakes = make(map[string]contracts.Filesystem)
+}
+
+// diskName resolves the disk name, falling back to the factory's default
+// disk so Fake() and Fake("local") match when local is the default.
+// Callers hold the lock.
+func diskName(name []string) string {
+	if len(name) > 0 {
+		return name[0]
+	}
+	if factory, ok := instance.(interface{ DefaultDisk() string }); ok {
+		return factory.DefaultDisk()
+	}
+	return ""
+}
+
 // Exists checks if a file exists on the default disk.
 func Exists(ctx context.Context, path string) bool {
 	return Disk().Exists(ctx, path)
@@ -62,6 +107,16 @@ func PutStream(ctx context.Context, path string, contents io.Reader) error {
 	return Disk().PutStream(ctx, path, contents)
 }
 
+// Prepend adds content to the beginning of a file on the default disk.
+func Prepend(ctx context.Context, path string, contents string) error {
+	return Disk().Prepend(ctx, path, contents)
+}
+
+// Append adds content to the end of a file on the default disk.
+func Append(ctx context.Context, path string, contents string) error {
+	return Disk().Append(ctx, path, contents)
+}
+
 // Delete deletes a file from the default disk.
 func Delete(ctx context.Context, path string) error {
 	return Disk().Delete(ctx, path)
@@ -105,3 +160,33 @@ func Url(path string) string {
 	}
 	return d.Url(path)
 }
+
+// TemporaryUrl returns a temporary URL for the file from the default disk.
+func TemporaryUrl(ctx context.Context, path string, expiry time.Duration) (string, error) {
+	return Disk().TemporaryUrl(ctx, path, expiry)
+}
+
+// SetVisibility sets the visibility of a file on the default disk.
+func SetVisibility(ctx context.Context, path string, visibility string) error {
+	return Disk().SetVisibility(ctx, path, visibility)
+}
+
+// GetVisibility gets the visibility of a file on the default disk.
+func GetVisibility(ctx context.Context, path string) (string, error) {
+	return Disk().GetVisibility(ctx, path)
+}
+
+// Checksum returns the checksum of a file on the default disk.
+func Checksum(ctx context.Context, path string, algo string) (string, error) {
+	return Disk().Checksum(ctx, path, algo)
+}
+
+// MimeType returns the content type of a file on the default disk.
+func MimeType(ctx context.Context, path string) (string, error) {
+	return Disk().MimeType(ctx, path)
+}
+
+// Metadata returns the metadata of a file on the default disk.
+func Metadata(ctx context.Context, path string) (*contracts.FileMetadata, error) {
+	return Disk().Metadata(ctx, path)
+}
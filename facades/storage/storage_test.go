@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/filesystem"
+)
+
+type stubFactory struct {
+	defaultDisk string
+	disks       map[string]contracts.Filesystem
+}
+
+func (f *stubFactory) Disk(name ...string) contracts.Filesystem {
+	if len(name) > 0 {
+		return f.disks[name[0]]
+	}
+	return f.disks[f.defaultDisk]
+}
+
+func (f *stubFactory) DefaultDisk() string {
+	return f.defaultDisk
+}
+
+func TestFakeReplacesDisks(t *testing.T) {
+	local, _ := filesystem.NewMemory(map[string]any{})
+
+	SetInstance(&stubFactory{defaultDisk: "local", disks: map[string]contracts.Filesystem{"local": local}})
+	defer SetInstance(nil)
+	defer Restore()
+
+	ctx := context.Background()
+	fake := Fake()
+
+	if err := Put(ctx, "avatar.png", "png"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if !fake.Exists(ctx, "avatar.png") {
+		t.Error("expected the default disk to be faked")
+	}
+	if Disk("local") != fake {
+		t.Error("expected Disk(\"local\") to return the fake for the default disk")
+	}
+	if local.Exists(ctx, "avatar.png") {
+		t.Error("expected the real disk to be untouched")
+	}
+
+	s3 := Fake("s3")
+	Disk("s3").Put(ctx, "report.pdf", "pdf")
+	if !s3.Exists(ctx, "report.pdf") || fake.Exists(ctx, "report.pdf") {
+		t.Error("expected named fakes to be separate disks")
+	}
+
+	Restore()
+	if Disk() != local {
+		t.Error("expected Restore to bring back the real disk")
+	}
+}
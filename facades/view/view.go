@@ -0,0 +1,67 @@
+// Package view provides a static facade for the view engine.
+package view
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/genesysflow/go-genesys/view"
+)
+
+// ErrNoInstance is returned when the view engine has not been set.
+var ErrNoInstance = errors.New("view: engine instance not set")
+
+var (
+	instance *view.Engine
+	mu       sync.RWMutex
+)
+
+// SetInstance sets the view engine instance.
+// This should be called during application bootstrap.
+func SetInstance(engine *view.Engine) {
+	mu.Lock()
+	defer mu.Unlock()
+	instance = engine
+}
+
+// GetInstance returns the view engine instance.
+func GetInstance() *view.Engine {
+	mu.RLock()
+	defer mu.RUnlock()
+	return instance
+}
+
+// Render renders a view.
+func Render(name string, data any) (string, error) {
+	engine := GetInstance()
+	if engine == nil {
+		return "", ErrNoInstance
+	}
+	return engine.Render(name, data)
+}
+
+// Exists reports whether a view exists.
+func Exists(name string) bool {
+	engine := GetInstance()
+	return engine != nil && engine.Exists(name)
+}
+
+// Share makes a value available to every view.
+func Share(key string, value any) error {
+	engine := GetInstance()
+	if engine == nil {
+		return ErrNoInstance
+	}
+	engine.Share(key, value)
+	return nil
+}
+
+// Composer registers a composer for the views matching pattern.
+func Composer(pattern string, compose view.Composer) error {
+	engine := GetInstance()
+	if engine == nil {
+		return ErrNoInstance
+	}
+	engine.Composer(pattern, compose)
+	return nil
+}
@@ -0,0 +1,33 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// IsNotExist reports whether err means the file does not exist on any driver.
+func IsNotExist(err error) bool {
+	var noSuchKey *types.NoSuchKey
+	var notFound *types.NotFound
+	return errors.Is(err, os.ErrNotExist) || errors.As(err, &noSuchKey) || errors.As(err, &notFound)
+}
+
+// readModifyWrite rewrites a file with before and after around its current
+// contents. Object stores have no append operation, so the whole object is
+// read and written back; concurrent writers may overwrite each other.
+func readModifyWrite(ctx context.Context, fs contracts.Filesystem, path, before, after string) error {
+	existing, err := fs.GetBytes(ctx, path)
+	if err != nil && !IsNotExist(err) {
+		return err
+	}
+
+	contents := make([]byte, 0, len(before)+len(existing)+len(after))
+	contents = append(contents, before...)
+	contents = append(contents, existing...)
+	contents = append(contents, after...)
+	return fs.PutBytes(ctx, path, contents)
+}
@@ -0,0 +1,272 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// ErrVisibilityNotSupported is returned by drivers that cannot change the
+// visibility of individual files.
+var ErrVisibilityNotSupported = errors.New("filesystem: per-file visibility is not supported by this driver")
+
+// AzureBlobProperties holds the properties of an Azure blob.
+type AzureBlobProperties struct {
+	Size         int64
+	LastModified time.Time
+	ETag         string
+	ContentType  string
+
+	// ContentMD5 is the hex-encoded MD5 digest, if the service stored one.
+	ContentMD5 string
+}
+
+// AzureClientInterface defines the interface for Azure Blob Storage operations.
+type AzureClientInterface interface {
+	Properties(ctx context.Context, container, blob string) (*AzureBlobProperties, error)
+	Download(ctx context.Context, container, blob string) (io.ReadCloser, error)
+	Upload(ctx context.Context, container, blob string, body io.Reader) error
+	Delete(ctx context.Context, container, blob string) error
+	Copy(ctx context.Context, container, from, to string) error
+	List(ctx context.Context, container, prefix string) ([]string, error)
+	ContainerIsPublic(ctx context.Context, container string) (bool, error)
+	SASURL(container, blob string, expiry time.Duration) (string, error)
+	BlobURL(container, blob string) string
+}
+
+// Azure is the Azure Blob Storage filesystem driver.
+// Azure controls public access per container, so GetVisibility reports the
+// container's access level and SetVisibility is not supported.
+type Azure struct {
+	client    AzureClientInterface
+	container string
+	url       string
+}
+
+// NewAzure creates a new Azure Blob Storage filesystem instance.
+func NewAzure(config map[string]any) (*Azure, error) {
+	container := configString(config, "container")
+	if container == "" {
+		return nil, fmt.Errorf("filesystem: container not defined for azure driver")
+	}
+
+	client, err := newAzureHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Azure{
+		client:    client,
+		container: container,
+		url:       configString(config, "url"),
+	}, nil
+}
+
+func (a *Azure) Exists(ctx context.Context, path string) bool {
+	_, err := a.client.Properties(ctx, a.container, path)
+	return err == nil
+}
+
+func (a *Azure) Get(ctx context.Context, path string) (string, error) {
+	b, err := a.GetBytes(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (a *Azure) GetBytes(ctx context.Context, path string) ([]byte, error) {
+	body, err := a.client.Download(ctx, a.container, path)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return io.ReadAll(body)
+}
+
+func (a *Azure) ReadStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	return a.client.Download(ctx, a.container, path)
+}
+
+func (a *Azure) Put(ctx context.Context, path string, contents string) error {
+	return a.PutStream(ctx, path, strings.NewReader(contents))
+}
+
+func (a *Azure) PutBytes(ctx context.Context, path string, contents []byte) error {
+	return a.PutStream(ctx, path, bytes.NewReader(contents))
+}
+
+func (a *Azure) PutStream(ctx context.Context, path string, contents io.Reader) error {
+	return a.client.Upload(ctx, a.container, path, contents)
+}
+
+// Prepend rewrites the object with contents at the beginning.
+func (a *Azure) Prepend(ctx context.Context, path string, contents string) error {
+	return readModifyWrite(ctx, a, path, contents, "")
+}
+
+// Append rewrites the object with contents at the end.
+func (a *Azure) Append(ctx context.Context, path string, contents string) error {
+	return readModifyWrite(ctx, a, path, "", contents)
+}
+
+func (a *Azure) Delete(ctx context.Context, path string) error {
+	return a.client.Delete(ctx, a.container, path)
+}
+
+func (a *Azure) Copy(ctx context.Context, from, to string) error {
+	return a.client.Copy(ctx, a.container, from, to)
+}
+
+func (a *Azure) Move(ctx context.Context, from, to string) error {
+	if err := a.Copy(ctx, from, to); err != nil {
+		return fmt.Errorf("move %s -> %s: copy failed: %w", from, to, err)
+	}
+
+	if err := a.Delete(ctx, from); err != nil {
+		return &MovePartialError{
+			From: from,
+			To:   to,
+			Err:  err,
+		}
+	}
+
+	return nil
+}
+
+func (a *Azure) Size(ctx context.Context, path string) (int64, error) {
+	props, err := a.client.Properties(ctx, a.container, path)
+	if err != nil {
+		return 0, err
+	}
+	return props.Size, nil
+}
+
+func (a *Azure) LastModified(ctx context.Context, path string) (time.Time, error) {
+	props, err := a.client.Properties(ctx, a.container, path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return props.LastModified, nil
+}
+
+func (a *Azure) MakeDirectory(ctx context.Context, path string) error {
+	// Blob storage is flat; mimic directories with an empty placeholder blob.
+	if !strings.HasSuffix(path, "/") {
+		path = path + "/"
+	}
+	return a.Put(ctx, path, "")
+}
+
+func (a *Azure) DeleteDirectory(ctx context.Context, path string) error {
+	if !strings.HasSuffix(path, "/") {
+		path = path + "/"
+	}
+
+	blobs, err := a.client.List(ctx, a.container, path)
+	if err != nil {
+		return err
+	}
+	for _, blob := range blobs {
+		if err := a.client.Delete(ctx, a.container, blob); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Azure) Url(path string) string {
+	if a.url != "" {
+		return strings.TrimRight(a.url, "/") + "/" + strings.TrimLeft(path, "/")
+	}
+	return a.client.BlobURL(a.container, strings.TrimLeft(path, "/"))
+}
+
+// TemporaryUrl returns a read-only service SAS URL for the blob.
+func (a *Azure) TemporaryUrl(ctx context.Context, path string, expiry time.Duration) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return a.client.SASURL(a.container, path, expiry)
+}
+
+// SetVisibility is not supported: Azure sets public access on the container.
+func (a *Azure) SetVisibility(ctx context.Context, path string, visibility string) error {
+	if err := validateVisibility(visibility); err != nil {
+		return err
+	}
+	return ErrVisibilityNotSupported
+}
+
+// GetVisibility reports the public access level of the disk's container.
+func (a *Azure) GetVisibility(ctx context.Context, path string) (string, error) {
+	public, err := a.client.ContainerIsPublic(ctx, a.container)
+	if err != nil {
+		return "", err
+	}
+	if public {
+		return contracts.VisibilityPublic, nil
+	}
+	return contracts.VisibilityPrivate, nil
+}
+
+// Checksum hashes the blob's contents. MD5 checksums are taken from the
+// blob's Content-MD5 property when available.
+func (a *Azure) Checksum(ctx context.Context, path string, algo string) (string, error) {
+	if _, err := newChecksumHash(algo); err != nil {
+		return "", err
+	}
+
+	if algo == contracts.ChecksumMD5 {
+		props, err := a.client.Properties(ctx, a.container, path)
+		if err != nil {
+			return "", err
+		}
+		if props.ContentMD5 != "" {
+			return props.ContentMD5, nil
+		}
+	}
+
+	body, err := a.client.Download(ctx, a.container, path)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	return checksumReader(body, algo)
+}
+
+// MimeType returns the blob's stored content type.
+func (a *Azure) MimeType(ctx context.Context, path string) (string, error) {
+	meta, err := a.Metadata(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return meta.ContentType, nil
+}
+
+// Metadata returns the blob's properties.
+func (a *Azure) Metadata(ctx context.Context, path string) (*contracts.FileMetadata, error) {
+	props, err := a.client.Properties(ctx, a.container, path)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := props.ContentType
+	if contentType == "" {
+		contentType = contentTypeFor(path)
+	}
+	return &contracts.FileMetadata{
+		Size:         props.Size,
+		LastModified: props.LastModified,
+		ETag:         props.ETag,
+		ContentType:  contentType,
+	}, nil
+}
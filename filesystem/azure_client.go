@@ -0,0 +1,323 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const azureAPIVersion = "2021-08-06"
+
+// azureHTTPClient talks to the Blob Storage REST API using Shared Key auth.
+type azureHTTPClient struct {
+	account  string
+	key      []byte
+	endpoint string
+	http     *http.Client
+}
+
+// newAzureHTTPClient creates a Blob Storage client from a disk configuration.
+func newAzureHTTPClient(config map[string]any) (*azureHTTPClient, error) {
+	account := configString(config, "account_name")
+	if account == "" {
+		return nil, fmt.Errorf("filesystem: account_name not defined for azure driver")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(configString(config, "account_key"))
+	if err != nil || len(key) == 0 {
+		return nil, fmt.Errorf("filesystem: account_key for azure driver must be base64 encoded")
+	}
+
+	endpoint := strings.TrimRight(configString(config, "endpoint"), "/")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", account)
+	}
+
+	return &azureHTTPClient{
+		account:  account,
+		key:      key,
+		endpoint: endpoint,
+		http:     defaultHTTPClient,
+	}, nil
+}
+
+// BlobURL returns the unsigned URL of a blob.
+func (c *azureHTTPClient) BlobURL(container, blob string) string {
+	return c.endpoint + "/" + url.PathEscape(container) + "/" + escapeObjectPath(blob)
+}
+
+// do signs and sends a request. Headers set on the request are included in the signature.
+func (c *azureHTTPClient) do(ctx context.Context, method, rawURL string, body []byte, headers map[string]string) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureAPIVersion)
+	if body != nil {
+		req.ContentLength = int64(len(body))
+	}
+
+	req.Header.Set("Authorization", "SharedKey "+c.account+":"+c.signRequest(req))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// signRequest computes the Shared Key signature for a request.
+func (c *azureHTTPClient) signRequest(req *http.Request) string {
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+
+	stringToSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date: x-ms-date is used instead
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+		c.canonicalHeaders(req.Header) + c.canonicalResource(req.URL),
+	}, "\n")
+
+	return c.hmac(stringToSign)
+}
+
+// canonicalHeaders returns the x-ms-* headers sorted by name, one per line.
+func (c *azureHTTPClient) canonicalHeaders(header http.Header) string {
+	names := make([]string, 0)
+	for name := range header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-ms-") {
+			names = append(names, lower)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name + ":" + strings.TrimSpace(header.Get(name)) + "\n")
+	}
+	return b.String()
+}
+
+// canonicalResource returns the account, path and sorted query parameters.
+func (c *azureHTTPClient) canonicalResource(u *url.URL) string {
+	resource := "/" + c.account + u.EscapedPath()
+
+	query := u.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		values := append([]string(nil), query[name]...)
+		sort.Strings(values)
+		resource += "\n" + strings.ToLower(name) + ":" + strings.Join(values, ",")
+	}
+	return resource
+}
+
+func (c *azureHTTPClient) hmac(s string) string {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(s))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (c *azureHTTPClient) Properties(ctx context.Context, container, blob string) (*AzureBlobProperties, error) {
+	resp, err := c.do(ctx, http.MethodHead, c.BlobURL(container, blob), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	props := &AzureBlobProperties{
+		Size:        resp.ContentLength,
+		ETag:        resp.Header.Get("ETag"),
+		ContentType: resp.Header.Get("Content-Type"),
+		ContentMD5:  base64ToHex(resp.Header.Get("Content-MD5")),
+	}
+	if modified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		props.LastModified = modified
+	}
+	return props, nil
+}
+
+func (c *azureHTTPClient) Download(ctx context.Context, container, blob string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, http.MethodGet, c.BlobURL(container, blob), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Upload stores a block blob with a single Put Blob request.
+func (c *azureHTTPClient) Upload(ctx context.Context, container, blob string, body io.Reader) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(ctx, http.MethodPut, c.BlobURL(container, blob), data, map[string]string{
+		"x-ms-blob-type":         "BlockBlob",
+		"x-ms-blob-content-type": contentTypeFor(blob),
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (c *azureHTTPClient) Delete(ctx context.Context, container, blob string) error {
+	resp, err := c.do(ctx, http.MethodDelete, c.BlobURL(container, blob), nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Copy copies a blob within the account, waiting for the copy to finish.
+func (c *azureHTTPClient) Copy(ctx context.Context, container, from, to string) error {
+	resp, err := c.do(ctx, http.MethodPut, c.BlobURL(container, to), nil, map[string]string{
+		"x-ms-copy-source": c.BlobURL(container, from),
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	status := resp.Header.Get("x-ms-copy-status")
+	for status == "pending" {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+
+		head, err := c.do(ctx, http.MethodHead, c.BlobURL(container, to), nil, nil)
+		if err != nil {
+			return err
+		}
+		head.Body.Close()
+		status = head.Header.Get("x-ms-copy-status")
+	}
+
+	if status != "" && status != "success" {
+		return fmt.Errorf("filesystem: azure copy %s -> %s finished with status %s", from, to, status)
+	}
+	return nil
+}
+
+func (c *azureHTTPClient) List(ctx context.Context, container, prefix string) ([]string, error) {
+	names := make([]string, 0)
+	query := url.Values{"restype": {"container"}, "comp": {"list"}, "prefix": {prefix}}
+
+	for {
+		target := c.endpoint + "/" + url.PathEscape(container) + "?" + query.Encode()
+		resp, err := c.do(ctx, http.MethodGet, target, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Blobs []struct {
+				Name string `xml:"Name"`
+			} `xml:"Blobs>Blob"`
+			NextMarker string `xml:"NextMarker"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("filesystem: invalid azure list response: %w", err)
+		}
+
+		for _, blob := range result.Blobs {
+			names = append(names, blob.Name)
+		}
+		if result.NextMarker == "" {
+			return names, nil
+		}
+		query.Set("marker", result.NextMarker)
+	}
+}
+
+// ContainerIsPublic reports whether anonymous reads are allowed on the container.
+func (c *azureHTTPClient) ContainerIsPublic(ctx context.Context, container string) (bool, error) {
+	target := c.endpoint + "/" + url.PathEscape(container) + "?restype=container&comp=acl"
+	resp, err := c.do(ctx, http.MethodGet, target, nil, nil)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	access := resp.Header.Get("x-ms-blob-public-access")
+	return access == "blob" || access == "container", nil
+}
+
+// SASURL returns a read-only service SAS URL for a blob.
+func (c *azureHTTPClient) SASURL(container, blob string, expiry time.Duration) (string, error) {
+	if expiry <= 0 {
+		return "", fmt.Errorf("filesystem: azure sas expiry must be positive")
+	}
+
+	expires := time.Now().UTC().Add(expiry).Format("2006-01-02T15:04:05Z")
+	protocol := "https"
+	if strings.HasPrefix(c.endpoint, "http://") {
+		protocol = "https,http"
+	}
+
+	// Fields: permissions, start, expiry, resource, identifier, IP, protocol,
+	// version, resource type, snapshot time, encryption scope, and the five
+	// response header overrides.
+	stringToSign := strings.Join([]string{
+		"r", "", expires,
+		"/blob/" + c.account + "/" + container + "/" + blob,
+		"", "", protocol, azureAPIVersion, "b", "", "",
+		"", "", "", "", "",
+	}, "\n")
+
+	query := url.Values{
+		"sv":  {azureAPIVersion},
+		"sr":  {"b"},
+		"sp":  {"r"},
+		"se":  {expires},
+		"spr": {protocol},
+		"sig": {c.hmac(stringToSign)},
+	}
+	return c.BlobURL(container, blob) + "?" + query.Encode(), nil
+}
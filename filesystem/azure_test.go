@@ -0,0 +1,248 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// Mock Azure client for testing
+type mockAzureClient struct {
+	blobs  map[string][]byte
+	public bool
+}
+
+func (m *mockAzureClient) Properties(ctx context.Context, container, blob string) (*AzureBlobProperties, error) {
+	data, ok := m.blobs[blob]
+	if !ok {
+		return nil, &RequestError{Status: http.StatusNotFound}
+	}
+	return &AzureBlobProperties{
+		Size:         int64(len(data)),
+		LastModified: time.Unix(1700000000, 0),
+		ETag:         `"0x8D"`,
+	}, nil
+}
+
+func (m *mockAzureClient) Download(ctx context.Context, container, blob string) (io.ReadCloser, error) {
+	data, ok := m.blobs[blob]
+	if !ok {
+		return nil, &RequestError{Status: http.StatusNotFound}
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *mockAzureClient) Upload(ctx context.Context, container, blob string, body io.Reader) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	m.blobs[blob] = data
+	return nil
+}
+
+func (m *mockAzureClient) Delete(ctx context.Context, container, blob string) error {
+	delete(m.blobs, blob)
+	return nil
+}
+
+func (m *mockAzureClient) Copy(ctx context.Context, container, from, to string) error {
+	data, ok := m.blobs[from]
+	if !ok {
+		return &RequestError{Status: http.StatusNotFound}
+	}
+	m.blobs[to] = data
+	return nil
+}
+
+func (m *mockAzureClient) List(ctx context.Context, container, prefix string) ([]string, error) {
+	names := make([]string, 0)
+	for name := range m.blobs {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func (m *mockAzureClient) ContainerIsPublic(ctx context.Context, container string) (bool, error) {
+	return m.public, nil
+}
+
+func (m *mockAzureClient) SASURL(container, blob string, expiry time.Duration) (string, error) {
+	return m.BlobURL(container, blob) + "?sig=abc", nil
+}
+
+func (m *mockAzureClient) BlobURL(container, blob string) string {
+	return "https://account.blob.core.windows.net/" + container + "/" + blob
+}
+
+func setupAzureFS(t *testing.T) (*Azure, *mockAzureClient) {
+	t.Helper()
+
+	mock := &mockAzureClient{blobs: make(map[string][]byte)}
+	return &Azure{client: mock, container: "files"}, mock
+}
+
+func TestAzureBasicOperations(t *testing.T) {
+	ctx := context.Background()
+	fs, mock := setupAzureFS(t)
+
+	if err := fs.Put(ctx, "dir/file.txt", "hello"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	content, err := fs.Get(ctx, "dir/file.txt")
+	if err != nil || content != "hello" {
+		t.Errorf("expected 'hello', got %q (%v)", content, err)
+	}
+
+	if err := fs.Copy(ctx, "dir/file.txt", "dir/copy.txt"); err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+	fs.Put(ctx, "keep.txt", "x")
+	if err := fs.DeleteDirectory(ctx, "dir"); err != nil {
+		t.Fatalf("DeleteDirectory failed: %v", err)
+	}
+	if len(mock.blobs) != 1 {
+		t.Errorf("expected only keep.txt to remain, got %v", mock.blobs)
+	}
+
+	if url := fs.Url("keep.txt"); url != "https://account.blob.core.windows.net/files/keep.txt" {
+		t.Errorf("unexpected url %q", url)
+	}
+	if url, _ := fs.TemporaryUrl(ctx, "keep.txt", time.Minute); !strings.HasSuffix(url, "?sig=abc") {
+		t.Errorf("unexpected temporary url %q", url)
+	}
+}
+
+func TestAzureChecksumAndMetadata(t *testing.T) {
+	ctx := context.Background()
+	fs, _ := setupAzureFS(t)
+	fs.Put(ctx, "hello.txt", "hello")
+
+	// Without a stored Content-MD5 the blob is downloaded and hashed.
+	if sum, _ := fs.Checksum(ctx, "hello.txt", contracts.ChecksumMD5); sum != "5d41402abc4b2a76b9719d911017c592" {
+		t.Errorf("unexpected md5 %q", sum)
+	}
+
+	meta, err := fs.Metadata(ctx, "hello.txt")
+	if err != nil {
+		t.Fatalf("Metadata failed: %v", err)
+	}
+	if meta.Size != 5 || meta.ETag != `"0x8D"` || !strings.HasPrefix(meta.ContentType, "text/plain") {
+		t.Errorf("unexpected metadata %+v", meta)
+	}
+}
+
+func TestAzureVisibility(t *testing.T) {
+	ctx := context.Background()
+	fs, mock := setupAzureFS(t)
+
+	if v, _ := fs.GetVisibility(ctx, "file.txt"); v != contracts.VisibilityPrivate {
+		t.Errorf("expected private, got %q", v)
+	}
+	mock.public = true
+	if v, _ := fs.GetVisibility(ctx, "file.txt"); v != contracts.VisibilityPublic {
+		t.Errorf("expected public, got %q", v)
+	}
+
+	if err := fs.SetVisibility(ctx, "file.txt", contracts.VisibilityPublic); !errors.Is(err, ErrVisibilityNotSupported) {
+		t.Errorf("expected ErrVisibilityNotSupported, got %v", err)
+	}
+}
+
+func TestNewAzure(t *testing.T) {
+	if _, err := NewAzure(map[string]any{"account_name": "a", "account_key": "a2V5"}); err == nil {
+		t.Error("expected error without container")
+	}
+	if _, err := NewAzure(map[string]any{"container": "c", "account_name": "a", "account_key": "%%"}); err == nil {
+		t.Error("expected error for invalid account key")
+	}
+}
+
+func TestAzureHTTPClient(t *testing.T) {
+	ctx := context.Background()
+	key := base64.StdEncoding.EncodeToString([]byte("secret-key"))
+	blobs := make(map[string]string)
+
+	var client *azureHTTPClient
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Verify the Shared Key signature the way the service does.
+		expected := "SharedKey account:" + client.signRequest(r)
+		if r.Header.Get("Authorization") != expected {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPut:
+			if r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			blobs[r.URL.Path] = string(body)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			if r.URL.Query().Get("comp") == "list" {
+				w.Write([]byte(`<?xml version="1.0"?><EnumerationResults><Blobs><Blob><Name>dir/a.txt</Name></Blob></Blobs><NextMarker/></EnumerationResults>`))
+				return
+			}
+			data, ok := blobs[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(data))
+		}
+	}))
+	defer srv.Close()
+
+	var err error
+	client, err = newAzureHTTPClient(map[string]any{
+		"account_name": "account",
+		"account_key":  key,
+		"endpoint":     srv.URL,
+	})
+	if err != nil {
+		t.Fatalf("newAzureHTTPClient failed: %v", err)
+	}
+
+	if err := client.Upload(ctx, "files", "dir/a.txt", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	body, err := client.Download(ctx, "files", "dir/a.txt")
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if string(data) != "hello" {
+		t.Errorf("expected 'hello', got %q", data)
+	}
+
+	names, err := client.List(ctx, "files", "dir/")
+	if err != nil || len(names) != 1 || names[0] != "dir/a.txt" {
+		t.Errorf("unexpected list result %v (%v)", names, err)
+	}
+
+	sas, err := client.SASURL("files", "dir/a.txt", time.Hour)
+	if err != nil {
+		t.Fatalf("SASURL failed: %v", err)
+	}
+	u, _ := url.Parse(sas)
+	q := u.Query()
+	if q.Get("sp") != "r" || q.Get("sr") != "b" || q.Get("sig") == "" {
+		t.Errorf("unexpected sas query %q", u.RawQuery)
+	}
+}
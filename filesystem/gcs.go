@@ -0,0 +1,288 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// GCSObjectAttrs holds the attributes of a Google Cloud Storage object.
+type GCSObjectAttrs struct {
+	Size        int64
+	Updated     time.Time
+	ETag        string
+	ContentType string
+
+	// MD5 is the hex-encoded MD5 digest, empty for composite objects.
+	MD5 string
+}
+
+// GCSClientInterface defines the interface for Google Cloud Storage operations.
+type GCSClientInterface interface {
+	Attrs(ctx context.Context, bucket, object string) (*GCSObjectAttrs, error)
+	Read(ctx context.Context, bucket, object string) (io.ReadCloser, error)
+	Write(ctx context.Context, bucket, object string, body io.Reader, predefinedACL string) error
+	Delete(ctx context.Context, bucket, object string) error
+	Copy(ctx context.Context, bucket, from, to string, predefinedACL string) error
+	List(ctx context.Context, bucket, prefix string) ([]string, error)
+	SetPublic(ctx context.Context, bucket, object string, public bool) error
+	IsPublic(ctx context.Context, bucket, object string) (bool, error)
+	SignedURL(bucket, object string, expiry time.Duration) (string, error)
+}
+
+// GCS is the Google Cloud Storage filesystem driver.
+type GCS struct {
+	client     GCSClientInterface
+	bucket     string
+	url        string
+	visibility string
+}
+
+// NewGCS creates a new Google Cloud Storage filesystem instance.
+//
+// Credentials are read from a service account JSON file (key_file) or inline
+// JSON (credentials). Without credentials requests are unauthenticated, which
+// is useful against local emulators.
+func NewGCS(config map[string]any) (*GCS, error) {
+	bucket := configString(config, "bucket")
+	if bucket == "" {
+		return nil, fmt.Errorf("filesystem: bucket not defined for gcs driver")
+	}
+
+	visibility, err := visibilityFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newGCSHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GCS{
+		client:     client,
+		bucket:     bucket,
+		url:        configString(config, "url"),
+		visibility: visibility,
+	}, nil
+}
+
+// predefinedACL maps a visibility to a GCS predefined ACL.
+func (g *GCS) predefinedACL(visibility string) string {
+	switch visibility {
+	case contracts.VisibilityPublic:
+		return "publicRead"
+	case contracts.VisibilityPrivate:
+		return "private"
+	default:
+		return ""
+	}
+}
+
+func (g *GCS) Exists(ctx context.Context, path string) bool {
+	_, err := g.client.Attrs(ctx, g.bucket, path)
+	return err == nil
+}
+
+func (g *GCS) Get(ctx context.Context, path string) (string, error) {
+	b, err := g.GetBytes(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (g *GCS) GetBytes(ctx context.Context, path string) ([]byte, error) {
+	body, err := g.client.Read(ctx, g.bucket, path)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return io.ReadAll(body)
+}
+
+func (g *GCS) ReadStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	return g.client.Read(ctx, g.bucket, path)
+}
+
+func (g *GCS) Put(ctx context.Context, path string, contents string) error {
+	return g.PutStream(ctx, path, strings.NewReader(contents))
+}
+
+func (g *GCS) PutBytes(ctx context.Context, path string, contents []byte) error {
+	return g.PutStream(ctx, path, bytes.NewReader(contents))
+}
+
+func (g *GCS) PutStream(ctx context.Context, path string, contents io.Reader) error {
+	return g.client.Write(ctx, g.bucket, path, contents, g.predefinedACL(g.visibility))
+}
+
+// Prepend rewrites the object with contents at the beginning.
+func (g *GCS) Prepend(ctx context.Context, path string, contents string) error {
+	return readModifyWrite(ctx, g, path, contents, "")
+}
+
+// Append rewrites the object with contents at the end.
+func (g *GCS) Append(ctx context.Context, path string, contents string) error {
+	return readModifyWrite(ctx, g, path, "", contents)
+}
+
+func (g *GCS) Delete(ctx context.Context, path string) error {
+	return g.client.Delete(ctx, g.bucket, path)
+}
+
+func (g *GCS) Copy(ctx context.Context, from, to string) error {
+	return g.client.Copy(ctx, g.bucket, from, to, g.predefinedACL(g.visibility))
+}
+
+func (g *GCS) Move(ctx context.Context, from, to string) error {
+	if err := g.Copy(ctx, from, to); err != nil {
+		return fmt.Errorf("move %s -> %s: copy failed: %w", from, to, err)
+	}
+
+	if err := g.Delete(ctx, from); err != nil {
+		return &MovePartialError{
+			From: from,
+			To:   to,
+			Err:  err,
+		}
+	}
+
+	return nil
+}
+
+func (g *GCS) Size(ctx context.Context, path string) (int64, error) {
+	attrs, err := g.client.Attrs(ctx, g.bucket, path)
+	if err != nil {
+		return 0, err
+	}
+	return attrs.Size, nil
+}
+
+func (g *GCS) LastModified(ctx context.Context, path string) (time.Time, error) {
+	attrs, err := g.client.Attrs(ctx, g.bucket, path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return attrs.Updated, nil
+}
+
+func (g *GCS) MakeDirectory(ctx context.Context, path string) error {
+	// GCS is flat; mimic directories with an empty placeholder object.
+	if !strings.HasSuffix(path, "/") {
+		path = path + "/"
+	}
+	return g.Put(ctx, path, "")
+}
+
+func (g *GCS) DeleteDirectory(ctx context.Context, path string) error {
+	if !strings.HasSuffix(path, "/") {
+		path = path + "/"
+	}
+
+	objects, err := g.client.List(ctx, g.bucket, path)
+	if err != nil {
+		return err
+	}
+	for _, object := range objects {
+		if err := g.client.Delete(ctx, g.bucket, object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *GCS) Url(path string) string {
+	if g.url != "" {
+		return strings.TrimRight(g.url, "/") + "/" + strings.TrimLeft(path, "/")
+	}
+	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", g.bucket, strings.TrimLeft(path, "/"))
+}
+
+// TemporaryUrl returns a V4 signed URL for the object.
+func (g *GCS) TemporaryUrl(ctx context.Context, path string, expiry time.Duration) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return g.client.SignedURL(g.bucket, path, expiry)
+}
+
+// SetVisibility grants or revokes read access for allUsers.
+func (g *GCS) SetVisibility(ctx context.Context, path string, visibility string) error {
+	if err := validateVisibility(visibility); err != nil {
+		return err
+	}
+	return g.client.SetPublic(ctx, g.bucket, path, visibility == contracts.VisibilityPublic)
+}
+
+// GetVisibility reports an object as public if allUsers can read it.
+func (g *GCS) GetVisibility(ctx context.Context, path string) (string, error) {
+	public, err := g.client.IsPublic(ctx, g.bucket, path)
+	if err != nil {
+		return "", err
+	}
+	if public {
+		return contracts.VisibilityPublic, nil
+	}
+	return contracts.VisibilityPrivate, nil
+}
+
+// Checksum hashes the object's contents. MD5 checksums are taken from the
+// object's metadata when available.
+func (g *GCS) Checksum(ctx context.Context, path string, algo string) (string, error) {
+	if _, err := newChecksumHash(algo); err != nil {
+		return "", err
+	}
+
+	if algo == contracts.ChecksumMD5 {
+		attrs, err := g.client.Attrs(ctx, g.bucket, path)
+		if err != nil {
+			return "", err
+		}
+		if attrs.MD5 != "" {
+			return attrs.MD5, nil
+		}
+	}
+
+	body, err := g.client.Read(ctx, g.bucket, path)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	return checksumReader(body, algo)
+}
+
+// MimeType returns the object's stored content type.
+func (g *GCS) MimeType(ctx context.Context, path string) (string, error) {
+	meta, err := g.Metadata(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return meta.ContentType, nil
+}
+
+// Metadata returns the object's metadata.
+func (g *GCS) Metadata(ctx context.Context, path string) (*contracts.FileMetadata, error) {
+	attrs, err := g.client.Attrs(ctx, g.bucket, path)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := attrs.ContentType
+	if contentType == "" {
+		contentType = contentTypeFor(path)
+	}
+	return &contracts.FileMetadata{
+		Size:         attrs.Size,
+		LastModified: attrs.Updated,
+		ETag:         attrs.ETag,
+		ContentType:  contentType,
+	}, nil
+}
@@ -0,0 +1,473 @@
+package filesystem
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	gcsDefaultEndpoint = "https://storage.googleapis.com"
+	gcsScope           = "https://www.googleapis.com/auth/devstorage.full_control"
+	gcsMaxSignedExpiry = 7 * 24 * time.Hour
+)
+
+// gcsServiceAccount is the subset of a service account key file used for auth.
+type gcsServiceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+
+	key *rsa.PrivateKey
+}
+
+// gcsHTTPClient talks to the Cloud Storage JSON API.
+type gcsHTTPClient struct {
+	endpoint string
+	http     *http.Client
+	account  *gcsServiceAccount
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// newGCSHTTPClient creates a JSON API client from a disk configuration.
+func newGCSHTTPClient(config map[string]any) (*gcsHTTPClient, error) {
+	client := &gcsHTTPClient{
+		endpoint: strings.TrimRight(configString(config, "endpoint"), "/"),
+		http:     defaultHTTPClient,
+	}
+	if client.endpoint == "" {
+		client.endpoint = gcsDefaultEndpoint
+	}
+
+	credentials := []byte(configString(config, "credentials"))
+	if keyFile := configString(config, "key_file"); keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("filesystem: failed to read gcs key file: %w", err)
+		}
+		credentials = data
+	}
+
+	if len(credentials) > 0 {
+		account, err := parseGCSServiceAccount(credentials)
+		if err != nil {
+			return nil, err
+		}
+		client.account = account
+	}
+
+	return client, nil
+}
+
+// parseGCSServiceAccount parses a service account JSON key.
+func parseGCSServiceAccount(data []byte) (*gcsServiceAccount, error) {
+	var account gcsServiceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("filesystem: invalid gcs credentials: %w", err)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, fmt.Errorf("filesystem: gcs credentials require client_email and private_key")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("filesystem: invalid gcs private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		rsaKey, rsaErr := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if rsaErr != nil {
+			return nil, fmt.Errorf("filesystem: invalid gcs private key: %w", err)
+		}
+		parsed = rsaKey
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("filesystem: gcs private key must be RSA")
+	}
+	account.key = key
+
+	return &account, nil
+}
+
+// accessToken returns a cached OAuth2 token, exchanging a signed JWT when it expires.
+func (c *gcsHTTPClient) accessToken(ctx context.Context) (string, error) {
+	if c.account == nil {
+		return "", nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Now().Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	now := time.Now()
+	assertion, err := c.signJWT(map[string]any{
+		"iss":   c.account.ClientEmail,
+		"scope": gcsScope,
+		"aud":   c.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("filesystem: gcs token request failed: %w", err)
+	}
+	if err := checkResponse(resp); err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("filesystem: invalid gcs token response: %w", err)
+	}
+
+	c.token = token.AccessToken
+	// Refresh a minute early to avoid using a token as it expires.
+	c.tokenExpiry = now.Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return c.token, nil
+}
+
+// signJWT creates an RS256-signed JWT with the service account key.
+func (c *gcsHTTPClient) signJWT(claims map[string]any) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature, err := c.sign([]byte(unsigned))
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// sign signs data with RSASSA-PKCS1-v1_5 SHA-256.
+func (c *gcsHTTPClient) sign(data []byte) ([]byte, error) {
+	sum := sha256.Sum256(data)
+	return rsa.SignPKCS1v15(rand.Reader, c.account.key, crypto.SHA256, sum[:])
+}
+
+// do sends an authenticated request to the JSON API.
+func (c *gcsHTTPClient) do(ctx context.Context, method, rawURL string, body io.Reader, contentType string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// objectURL returns the JSON API metadata URL for an object.
+func (c *gcsHTTPClient) objectURL(bucket, object string) string {
+	return fmt.Sprintf("%s/storage/v1/b/%s/o/%s", c.endpoint, url.PathEscape(bucket), url.PathEscape(object))
+}
+
+func (c *gcsHTTPClient) Attrs(ctx context.Context, bucket, object string) (*GCSObjectAttrs, error) {
+	resp, err := c.do(ctx, http.MethodGet, c.objectURL(bucket, object), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var meta struct {
+		Size        string    `json:"size"`
+		Updated     time.Time `json:"updated"`
+		ETag        string    `json:"etag"`
+		ContentType string    `json:"contentType"`
+		MD5Hash     string    `json:"md5Hash"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+		return nil, fmt.Errorf("filesystem: invalid gcs object metadata: %w", err)
+	}
+	size, _ := strconv.ParseInt(meta.Size, 10, 64)
+
+	return &GCSObjectAttrs{
+		Size:        size,
+		Updated:     meta.Updated,
+		ETag:        meta.ETag,
+		ContentType: meta.ContentType,
+		MD5:         base64ToHex(meta.MD5Hash),
+	}, nil
+}
+
+func (c *gcsHTTPClient) Read(ctx context.Context, bucket, object string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, http.MethodGet, c.objectURL(bucket, object)+"?alt=media", nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (c *gcsHTTPClient) Write(ctx context.Context, bucket, object string, body io.Reader, predefinedACL string) error {
+	query := url.Values{"uploadType": {"media"}, "name": {object}}
+	if predefinedACL != "" {
+		query.Set("predefinedAcl", predefinedACL)
+	}
+	target := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?%s", c.endpoint, url.PathEscape(bucket), query.Encode())
+
+	resp, err := c.do(ctx, http.MethodPost, target, body, contentTypeFor(object))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (c *gcsHTTPClient) Delete(ctx context.Context, bucket, object string) error {
+	resp, err := c.do(ctx, http.MethodDelete, c.objectURL(bucket, object), nil, "")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (c *gcsHTTPClient) Copy(ctx context.Context, bucket, from, to string, predefinedACL string) error {
+	query := url.Values{}
+	if predefinedACL != "" {
+		query.Set("destinationPredefinedAcl", predefinedACL)
+	}
+
+	// Large objects may take several rewrite calls to complete.
+	for {
+		target := fmt.Sprintf("%s/rewriteTo/b/%s/o/%s?%s",
+			c.objectURL(bucket, from), url.PathEscape(bucket), url.PathEscape(to), query.Encode())
+
+		resp, err := c.do(ctx, http.MethodPost, target, nil, "")
+		if err != nil {
+			return err
+		}
+
+		var result struct {
+			Done         bool   `json:"done"`
+			RewriteToken string `json:"rewriteToken"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("filesystem: invalid gcs rewrite response: %w", err)
+		}
+
+		if result.Done || result.RewriteToken == "" {
+			return nil
+		}
+		query.Set("rewriteToken", result.RewriteToken)
+	}
+}
+
+func (c *gcsHTTPClient) List(ctx context.Context, bucket, prefix string) ([]string, error) {
+	names := make([]string, 0)
+	query := url.Values{"prefix": {prefix}, "fields": {"items(name),nextPageToken"}}
+
+	for {
+		target := fmt.Sprintf("%s/storage/v1/b/%s/o?%s", c.endpoint, url.PathEscape(bucket), query.Encode())
+		resp, err := c.do(ctx, http.MethodGet, target, nil, "")
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Items []struct {
+				Name string `json:"name"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("filesystem: invalid gcs list response: %w", err)
+		}
+
+		for _, item := range page.Items {
+			names = append(names, item.Name)
+		}
+		if page.NextPageToken == "" {
+			return names, nil
+		}
+		query.Set("pageToken", page.NextPageToken)
+	}
+}
+
+func (c *gcsHTTPClient) SetPublic(ctx context.Context, bucket, object string, public bool) error {
+	if public {
+		body := strings.NewReader(`{"entity":"allUsers","role":"READER"}`)
+		resp, err := c.do(ctx, http.MethodPost, c.objectURL(bucket, object)+"/acl", body, "application/json")
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	resp, err := c.do(ctx, http.MethodDelete, c.objectURL(bucket, object)+"/acl/allUsers", nil, "")
+	if errors.Is(err, os.ErrNotExist) {
+		// Already private.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (c *gcsHTTPClient) IsPublic(ctx context.Context, bucket, object string) (bool, error) {
+	resp, err := c.do(ctx, http.MethodGet, c.objectURL(bucket, object)+"/acl/allUsers", nil, "")
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return true, nil
+}
+
+// SignedURL creates a V4 signed GET URL using the service account key.
+func (c *gcsHTTPClient) SignedURL(bucket, object string, expiry time.Duration) (string, error) {
+	if c.account == nil {
+		return "", fmt.Errorf("filesystem: gcs signed urls require service account credentials")
+	}
+	if expiry <= 0 || expiry > gcsMaxSignedExpiry {
+		return "", fmt.Errorf("filesystem: gcs signed url expiry must be between 1s and 7 days")
+	}
+
+	endpoint, err := url.Parse(c.endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	datetime := now.Format("20060102T150405Z")
+	scope := now.Format("20060102") + "/auto/storage/goog4_request"
+
+	query := url.Values{
+		"X-Goog-Algorithm":     {"GOOG4-RSA-SHA256"},
+		"X-Goog-Credential":    {c.account.ClientEmail + "/" + scope},
+		"X-Goog-Date":          {datetime},
+		"X-Goog-Expires":       {strconv.FormatInt(int64(expiry/time.Second), 10)},
+		"X-Goog-SignedHeaders": {"host"},
+	}
+	path := "/" + rfc3986Escape(bucket) + "/" + escapeObjectPath(object)
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		path,
+		canonicalQuery(query),
+		"host:" + endpoint.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	hash := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := strings.Join([]string{
+		"GOOG4-RSA-SHA256",
+		datetime,
+		scope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	signature, err := c.sign([]byte(stringToSign))
+	if err != nil {
+		return "", err
+	}
+	query.Set("X-Goog-Signature", hex.EncodeToString(signature))
+
+	return endpoint.Scheme + "://" + endpoint.Host + path + "?" + canonicalQuery(query), nil
+}
+
+// escapeObjectPath percent-encodes an object name, keeping slashes.
+func escapeObjectPath(object string) string {
+	segments := strings.Split(object, "/")
+	for i, segment := range segments {
+		segments[i] = rfc3986Escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery encodes query parameters sorted by key with RFC 3986 escaping.
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, rfc3986Escape(key)+"="+rfc3986Escape(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// rfc3986Escape escapes everything except unreserved characters.
+func rfc3986Escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
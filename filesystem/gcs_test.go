@@ -0,0 +1,348 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// Mock GCS client for testing
+type mockGCSClient struct {
+	objects map[string][]byte
+	acls    map[string]string
+	public  map[string]bool
+}
+
+func newMockGCS() *mockGCSClient {
+	return &mockGCSClient{
+		objects: make(map[string][]byte),
+		acls:    make(map[string]string),
+		public:  make(map[string]bool),
+	}
+}
+
+func (m *mockGCSClient) Attrs(ctx context.Context, bucket, object string) (*GCSObjectAttrs, error) {
+	data, ok := m.objects[object]
+	if !ok {
+		return nil, &RequestError{Status: http.StatusNotFound}
+	}
+	sum := md5.Sum(data)
+	return &GCSObjectAttrs{
+		Size:        int64(len(data)),
+		Updated:     time.Unix(1700000000, 0),
+		ETag:        "CJ+abc=",
+		ContentType: contentTypeFor(object),
+		MD5:         hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+func (m *mockGCSClient) Read(ctx context.Context, bucket, object string) (io.ReadCloser, error) {
+	data, ok := m.objects[object]
+	if !ok {
+		return nil, &RequestError{Status: http.StatusNotFound}
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *mockGCSClient) Write(ctx context.Context, bucket, object string, body io.Reader, predefinedACL string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	m.objects[object] = data
+	m.acls[object] = predefinedACL
+	return nil
+}
+
+func (m *mockGCSClient) Delete(ctx context.Context, bucket, object string) error {
+	if _, ok := m.objects[object]; !ok {
+		return &RequestError{Status: http.StatusNotFound}
+	}
+	delete(m.objects, object)
+	return nil
+}
+
+func (m *mockGCSClient) Copy(ctx context.Context, bucket, from, to string, predefinedACL string) error {
+	data, ok := m.objects[from]
+	if !ok {
+		return &RequestError{Status: http.StatusNotFound}
+	}
+	m.objects[to] = data
+	m.acls[to] = predefinedACL
+	return nil
+}
+
+func (m *mockGCSClient) List(ctx context.Context, bucket, prefix string) ([]string, error) {
+	names := make([]string, 0)
+	for name := range m.objects {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func (m *mockGCSClient) SetPublic(ctx context.Context, bucket, object string, public bool) error {
+	m.public[object] = public
+	return nil
+}
+
+func (m *mockGCSClient) IsPublic(ctx context.Context, bucket, object string) (bool, error) {
+	return m.public[object], nil
+}
+
+func (m *mockGCSClient) SignedURL(bucket, object string, expiry time.Duration) (string, error) {
+	return "https://storage.googleapis.com/" + bucket + "/" + object + "?X-Goog-Signature=abc", nil
+}
+
+func setupGCSFS(t *testing.T) (*GCS, *mockGCSClient) {
+	t.Helper()
+
+	mock := newMockGCS()
+	return &GCS{client: mock, bucket: "test-bucket"}, mock
+}
+
+func TestGCSBasicOperations(t *testing.T) {
+	ctx := context.Background()
+	fs, mock := setupGCSFS(t)
+
+	if err := fs.Put(ctx, "dir/file.txt", "hello"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if !fs.Exists(ctx, "dir/file.txt") {
+		t.Error("expected file to exist")
+	}
+
+	content, err := fs.Get(ctx, "dir/file.txt")
+	if err != nil || content != "hello" {
+		t.Errorf("expected 'hello', got %q (%v)", content, err)
+	}
+
+	size, _ := fs.Size(ctx, "dir/file.txt")
+	if size != 5 {
+		t.Errorf("expected size 5, got %d", size)
+	}
+
+	if err := fs.Move(ctx, "dir/file.txt", "dir/moved.txt"); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if fs.Exists(ctx, "dir/file.txt") || !fs.Exists(ctx, "dir/moved.txt") {
+		t.Error("expected file to be moved")
+	}
+
+	fs.Put(ctx, "dir/other.txt", "x")
+	fs.Put(ctx, "keep.txt", "x")
+	if err := fs.DeleteDirectory(ctx, "dir"); err != nil {
+		t.Fatalf("DeleteDirectory failed: %v", err)
+	}
+	if len(mock.objects) != 1 {
+		t.Errorf("expected only keep.txt to remain, got %v", mock.objects)
+	}
+
+	_, err = fs.Get(ctx, "missing.txt")
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist, got %v", err)
+	}
+}
+
+func TestGCSChecksumAndMetadata(t *testing.T) {
+	ctx := context.Background()
+	fs, _ := setupGCSFS(t)
+	fs.Put(ctx, "hello.json", "hello")
+
+	if sum, _ := fs.Checksum(ctx, "hello.json", contracts.ChecksumMD5); sum != "5d41402abc4b2a76b9719d911017c592" {
+		t.Errorf("unexpected md5 %q", sum)
+	}
+	if sum, _ := fs.Checksum(ctx, "hello.json", contracts.ChecksumSHA1); sum != "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d" {
+		t.Errorf("unexpected sha1 %q", sum)
+	}
+
+	meta, err := fs.Metadata(ctx, "hello.json")
+	if err != nil {
+		t.Fatalf("Metadata failed: %v", err)
+	}
+	if meta.Size != 5 || meta.ETag != "CJ+abc=" || meta.ContentType != "application/json" {
+		t.Errorf("unexpected metadata %+v", meta)
+	}
+}
+
+func TestGCSUrl(t *testing.T) {
+	fs, _ := setupGCSFS(t)
+	if url := fs.Url("a/b.txt"); url != "https://storage.googleapis.com/test-bucket/a/b.txt" {
+		t.Errorf("unexpected url %q", url)
+	}
+
+	fs.url = "https://cdn.example.com/"
+	if url := fs.Url("/a/b.txt"); url != "https://cdn.example.com/a/b.txt" {
+		t.Errorf("unexpected url %q", url)
+	}
+}
+
+func TestGCSVisibility(t *testing.T) {
+	ctx := context.Background()
+	fs, mock := setupGCSFS(t)
+	fs.visibility = contracts.VisibilityPrivate
+
+	fs.Put(ctx, "file.txt", "x")
+	if mock.acls["file.txt"] != "private" {
+		t.Errorf("expected private predefined ACL, got %q", mock.acls["file.txt"])
+	}
+
+	fs.SetVisibility(ctx, "file.txt", contracts.VisibilityPublic)
+	if v, _ := fs.GetVisibility(ctx, "file.txt"); v != contracts.VisibilityPublic {
+		t.Errorf("expected public, got %q", v)
+	}
+	if err := fs.SetVisibility(ctx, "file.txt", "hidden"); err == nil {
+		t.Error("expected error for invalid visibility")
+	}
+}
+
+func TestNewGCS(t *testing.T) {
+	if _, err := NewGCS(map[string]any{}); err == nil {
+		t.Error("expected error without bucket")
+	}
+
+	fs, err := NewGCS(map[string]any{"bucket": "b", "endpoint": "http://localhost:4443"})
+	if err != nil {
+		t.Fatalf("NewGCS failed: %v", err)
+	}
+	if _, err := fs.TemporaryUrl(context.Background(), "file.txt", time.Minute); err == nil {
+		t.Error("expected signed urls to require credentials")
+	}
+}
+
+func testServiceAccount(t *testing.T, tokenURI string) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	data, _ := json.Marshal(map[string]string{
+		"client_email": "svc@project.iam.gserviceaccount.com",
+		"private_key":  string(pemKey),
+		"token_uri":    tokenURI,
+	})
+	return string(data)
+}
+
+func TestGCSHTTPClient(t *testing.T) {
+	ctx := context.Background()
+	objects := make(map[string]string)
+	var auth []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			r.ParseForm()
+			if strings.Count(r.Form.Get("assertion"), ".") != 2 {
+				t.Errorf("expected a JWT assertion")
+			}
+			w.Write([]byte(`{"access_token":"token-123","expires_in":3600}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/upload/storage/v1/b/bucket/o":
+			auth = append(auth, r.Header.Get("Authorization"))
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Query().Get("name")] = string(body)
+			w.Write([]byte(`{}`))
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/storage/v1/b/bucket/o/"):
+			auth = append(auth, r.Header.Get("Authorization"))
+			name := strings.TrimPrefix(r.URL.Path, "/storage/v1/b/bucket/o/")
+			data, ok := objects[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if r.URL.Query().Get("alt") == "media" {
+				w.Write([]byte(data))
+				return
+			}
+			fmt.Fprintf(w, `{"size":"%d","updated":"2024-01-02T03:04:05Z","contentType":"text/plain","md5Hash":"XUFAKrxLKna5cZ2REBfFkg=="}`, len(data))
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newGCSHTTPClient(map[string]any{
+		"endpoint":    srv.URL,
+		"credentials": testServiceAccount(t, srv.URL+"/token"),
+	})
+	if err != nil {
+		t.Fatalf("newGCSHTTPClient failed: %v", err)
+	}
+
+	if err := client.Write(ctx, "bucket", "dir/a.txt", strings.NewReader("hello"), ""); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	attrs, err := client.Attrs(ctx, "bucket", "dir/a.txt")
+	if err != nil {
+		t.Fatalf("Attrs failed: %v", err)
+	}
+	if attrs.Size != 5 || attrs.Updated.Year() != 2024 || attrs.MD5 != "5d41402abc4b2a76b9719d911017c592" {
+		t.Errorf("unexpected attrs %+v", attrs)
+	}
+
+	body, err := client.Read(ctx, "bucket", "dir/a.txt")
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if string(data) != "hello" {
+		t.Errorf("expected 'hello', got %q", data)
+	}
+
+	for _, header := range auth {
+		if header != "Bearer token-123" {
+			t.Errorf("expected bearer token, got %q", header)
+		}
+	}
+
+	if _, err := client.Attrs(ctx, "bucket", "missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist, got %v", err)
+	}
+
+	signed, err := client.SignedURL("bucket", "dir/a b.txt", 10*time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL failed: %v", err)
+	}
+	u, _ := url.Parse(signed)
+	if u.EscapedPath() != "/bucket/dir/a%20b.txt" {
+		t.Errorf("unexpected signed path %q", u.EscapedPath())
+	}
+	if u.Query().Get("X-Goog-Expires") != "600" || u.Query().Get("X-Goog-Signature") == "" {
+		t.Errorf("unexpected signed query %q", u.RawQuery)
+	}
+}
+
+func TestGCSAppend(t *testing.T) {
+	ctx := context.Background()
+	fs, _ := setupGCSFS(t)
+
+	fs.Append(ctx, "log.txt", "one\n")
+	fs.Append(ctx, "log.txt", "two\n")
+	if content, _ := fs.Get(ctx, "log.txt"); content != "one\ntwo\n" {
+		t.Errorf("unexpected content %q", content)
+	}
+}
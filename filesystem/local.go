@@ -8,12 +8,22 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
 )
 
+// Permission bits used for each visibility.
+var localPermissions = map[string]struct{ file, dir os.FileMode }{
+	contracts.VisibilityPublic:  {file: 0644, dir: 0755},
+	contracts.VisibilityPrivate: {file: 0600, dir: 0700},
+}
+
 // Local is the local filesystem driver.
 type Local struct {
-	root string
-	url  string
+	root       string
+	url        string
+	signingKey []byte
+	visibility string
 }
 
 // NewLocal creates a new local filesystem instance.
@@ -30,13 +40,34 @@ func NewLocal(config map[string]any) (*Local, error) {
 	}
 
 	url, _ := config["url"].(string)
+	signingKey, _ := config["signing_key"].(string)
+
+	visibility, err := visibilityFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if visibility == "" {
+		visibility = contracts.VisibilityPublic
+	}
 
 	return &Local{
-		root: absRoot,
-		url:  url,
+		root:       absRoot,
+		url:        url,
+		signingKey: []byte(signingKey),
+		visibility: visibility,
 	}, nil
 }
 
+// filePerm returns the permission bits for new files.
+func (l *Local) filePerm() os.FileMode {
+	return localPermissions[l.visibility].file
+}
+
+// dirPerm returns the permission bits for new directories.
+func (l *Local) dirPerm() os.FileMode {
+	return localPermissions[l.visibility].dir
+}
+
 func (l *Local) path(path string) (string, error) {
 	// Clean the path to remove any ".." or "." components
 	cleanPath := filepath.Clean(path)
@@ -88,6 +119,17 @@ func (l *Local) GetBytes(ctx context.Context, path string) ([]byte, error) {
 	return os.ReadFile(fullPath)
 }
 
+func (l *Local) ReadStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	fullPath, err := l.path(path)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(fullPath)
+}
+
 func (l *Local) Put(ctx context.Context, path string, contents string) error {
 	return l.PutBytes(ctx, path, []byte(contents))
 }
@@ -100,10 +142,10 @@ func (l *Local) PutBytes(ctx context.Context, path string, contents []byte) erro
 	if err != nil {
 		return err
 	}
-	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(fullPath), l.dirPerm()); err != nil {
 		return err
 	}
-	return os.WriteFile(fullPath, contents, 0644)
+	return os.WriteFile(fullPath, contents, l.filePerm())
 }
 
 func (l *Local) PutStream(ctx context.Context, path string, contents io.Reader) error {
@@ -114,11 +156,11 @@ func (l *Local) PutStream(ctx context.Context, path string, contents io.Reader)
 	if err != nil {
 		return err
 	}
-	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(fullPath), l.dirPerm()); err != nil {
 		return err
 	}
 
-	f, err := os.Create(fullPath)
+	f, err := os.OpenFile(fullPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, l.filePerm())
 	if err != nil {
 		return err
 	}
@@ -142,6 +184,70 @@ func (l *Local) PutStream(ctx context.Context, path string, contents io.Reader)
 	}
 }
 
+// Prepend writes the new contents followed by the old to a temporary file and
+// renames it over the original, so readers never see a partial file.
+func (l *Local) Prepend(ctx context.Context, path string, contents string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fullPath, err := l.path(path)
+	if err != nil {
+		return err
+	}
+
+	existing, err := os.ReadFile(fullPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), l.dirPerm()); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), "."+filepath.Base(fullPath)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(contents); err == nil {
+		_, err = tmp.Write(existing)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), l.filePerm()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), fullPath)
+}
+
+// Append writes to the end of the file with O_APPEND, without reading it.
+func (l *Local) Append(ctx context.Context, path string, contents string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fullPath, err := l.path(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), l.dirPerm()); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(fullPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, l.filePerm())
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(contents); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 func (l *Local) Delete(ctx context.Context, path string) error {
 	if err := ctx.Err(); err != nil {
 		return err
@@ -172,7 +278,7 @@ func (l *Local) Copy(ctx context.Context, from, to string) error {
 	}
 
 	// Create destination directory
-	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(destPath), l.dirPerm()); err != nil {
 		return err
 	}
 
@@ -184,7 +290,7 @@ func (l *Local) Copy(ctx context.Context, from, to string) error {
 	defer source.Close()
 
 	// Create destination
-	dest, err := os.Create(destPath)
+	dest, err := os.OpenFile(destPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, l.filePerm())
 	if err != nil {
 		return err
 	}
@@ -222,7 +328,7 @@ func (l *Local) Move(ctx context.Context, from, to string) error {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(destPath), l.dirPerm()); err != nil {
 		return err
 	}
 
@@ -267,7 +373,7 @@ func (l *Local) MakeDirectory(ctx context.Context, path string) error {
 	if err != nil {
 		return err
 	}
-	return os.MkdirAll(fullPath, 0755)
+	return os.MkdirAll(fullPath, l.dirPerm())
 }
 
 func (l *Local) DeleteDirectory(ctx context.Context, path string) error {
@@ -284,3 +390,122 @@ func (l *Local) DeleteDirectory(ctx context.Context, path string) error {
 func (l *Local) Url(path string) string {
 	return strings.TrimRight(l.url, "/") + "/" + strings.TrimLeft(path, "/")
 }
+
+// TemporaryUrl returns an HMAC-signed URL for the file.
+// The URL must be served behind middleware.ValidateSignature with the same key.
+func (l *Local) TemporaryUrl(ctx context.Context, path string, expiry time.Duration) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if _, err := l.path(path); err != nil {
+		return "", err
+	}
+	return SignURL(l.Url(path), time.Now().Add(expiry), l.signingKey)
+}
+
+// SetVisibility changes the file's permission bits to match the visibility.
+func (l *Local) SetVisibility(ctx context.Context, path string, visibility string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := validateVisibility(visibility); err != nil {
+		return err
+	}
+	fullPath, err := l.path(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return err
+	}
+
+	perm := localPermissions[visibility].file
+	if info.IsDir() {
+		perm = localPermissions[visibility].dir
+	}
+	return os.Chmod(fullPath, perm)
+}
+
+// GetVisibility reports a file as public if it is readable by others.
+func (l *Local) GetVisibility(ctx context.Context, path string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	fullPath, err := l.path(path)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return "", err
+	}
+	if info.Mode().Perm()&0004 != 0 {
+		return contracts.VisibilityPublic, nil
+	}
+	return contracts.VisibilityPrivate, nil
+}
+
+// Checksum hashes the file's contents.
+func (l *Local) Checksum(ctx context.Context, path string, algo string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	fullPath, err := l.path(path)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return checksumReader(f, algo)
+}
+
+// MimeType guesses the content type from the extension, or from the file's
+// leading bytes when the extension is unknown.
+func (l *Local) MimeType(ctx context.Context, path string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	fullPath, err := l.path(path)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return sniffContentType(path, f)
+}
+
+// Metadata returns the file's metadata. The ETag is derived from the size and
+// modification time, so it changes whenever the file is rewritten.
+func (l *Local) Metadata(ctx context.Context, path string) (*contracts.FileMetadata, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	fullPath, err := l.path(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	contentType, err := l.MimeType(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.FileMetadata{
+		Size:         info.Size(),
+		LastModified: info.ModTime(),
+		ETag:         fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()),
+		ContentType:  contentType,
+	}, nil
+}
@@ -3,11 +3,14 @@ package filesystem
 import (
 	"context"
 	"io"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
 )
 
 func setupLocalFS(t *testing.T) (*Local, string, func()) {
@@ -251,6 +254,37 @@ func TestLocalPutStream(t *testing.T) {
 	})
 }
 
+func TestLocalReadStream(t *testing.T) {
+	fs, _, cleanup := setupLocalFS(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := fs.Put(ctx, "read.txt", "streamed"); err != nil {
+		t.Fatalf("failed to put file: %v", err)
+	}
+
+	reader, err := fs.ReadStream(ctx, "read.txt")
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	defer reader.Close()
+
+	got, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read stream: %v", err)
+	}
+	if string(got) != "streamed" {
+		t.Errorf("expected 'streamed', got '%s'", got)
+	}
+
+	if _, err := fs.ReadStream(ctx, "missing.txt"); !IsNotExist(err) {
+		t.Errorf("expected not exist error, got %v", err)
+	}
+	if _, err := fs.ReadStream(ctx, "../outside.txt"); err == nil {
+		t.Error("expected error for path outside root")
+	}
+}
+
 func TestLocalDelete(t *testing.T) {
 	fs, _, cleanup := setupLocalFS(t)
 	defer cleanup()
@@ -600,3 +634,258 @@ func (r *slowReader) Read(p []byte) (n int, err error) {
 	r.pos += n
 	return n, nil
 }
+
+func TestLocalTemporaryUrl(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("signs url with expiry", func(t *testing.T) {
+		fs, err := NewLocal(map[string]any{
+			"root":        t.TempDir(),
+			"url":         "http://localhost/storage",
+			"signing_key": "secret",
+		})
+		if err != nil {
+			t.Fatalf("failed to create filesystem: %v", err)
+		}
+
+		signed, err := fs.TemporaryUrl(ctx, "private/report.pdf", time.Minute)
+		if err != nil {
+			t.Fatalf("TemporaryUrl failed: %v", err)
+		}
+
+		u, err := url.Parse(signed)
+		if err != nil {
+			t.Fatalf("invalid url: %v", err)
+		}
+		if u.Path != "/storage/private/report.pdf" {
+			t.Errorf("unexpected path %q", u.Path)
+		}
+
+		q := u.Query()
+		if err := VerifySignature(u.EscapedPath(), q.Get("expires"), q.Get("signature"), []byte("secret")); err != nil {
+			t.Errorf("expected valid signature, got %v", err)
+		}
+		if err := VerifySignature(u.EscapedPath(), q.Get("expires"), q.Get("signature"), []byte("other")); err != ErrInvalidSignature {
+			t.Errorf("expected ErrInvalidSignature for wrong key, got %v", err)
+		}
+		if err := VerifySignature("/storage/private/other.pdf", q.Get("expires"), q.Get("signature"), []byte("secret")); err != ErrInvalidSignature {
+			t.Errorf("expected ErrInvalidSignature for other path, got %v", err)
+		}
+	})
+
+	t.Run("expired url", func(t *testing.T) {
+		fs, _ := NewLocal(map[string]any{
+			"root":        t.TempDir(),
+			"url":         "http://localhost/storage",
+			"signing_key": "secret",
+		})
+
+		signed, err := fs.TemporaryUrl(ctx, "file.txt", -time.Minute)
+		if err != nil {
+			t.Fatalf("TemporaryUrl failed: %v", err)
+		}
+		u, _ := url.Parse(signed)
+		q := u.Query()
+		if err := VerifySignature(u.EscapedPath(), q.Get("expires"), q.Get("signature"), []byte("secret")); err != ErrSignatureExpired {
+			t.Errorf("expected ErrSignatureExpired, got %v", err)
+		}
+	})
+
+	t.Run("missing signing key", func(t *testing.T) {
+		fs, tmpDir, cleanup := setupLocalFS(t)
+		defer cleanup()
+		_ = tmpDir
+
+		if _, err := fs.TemporaryUrl(ctx, "file.txt", time.Minute); err == nil {
+			t.Error("expected error without signing key")
+		}
+	})
+}
+
+func TestLocalVisibility(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("defaults to public", func(t *testing.T) {
+		fs, _, cleanup := setupLocalFS(t)
+		defer cleanup()
+
+		fs.Put(ctx, "file.txt", "content")
+		visibility, err := fs.GetVisibility(ctx, "file.txt")
+		if err != nil {
+			t.Fatalf("GetVisibility failed: %v", err)
+		}
+		if visibility != contracts.VisibilityPublic {
+			t.Errorf("expected public, got %q", visibility)
+		}
+	})
+
+	t.Run("private disk writes private files", func(t *testing.T) {
+		root := t.TempDir()
+		fs, err := NewLocal(map[string]any{"root": root, "visibility": "private"})
+		if err != nil {
+			t.Fatalf("failed to create filesystem: %v", err)
+		}
+
+		fs.Put(ctx, "nested/file.txt", "content")
+		fs.PutStream(ctx, "stream.txt", strings.NewReader("content"))
+
+		for _, path := range []string{"nested/file.txt", "stream.txt"} {
+			info, err := os.Stat(filepath.Join(root, path))
+			if err != nil {
+				t.Fatalf("stat failed: %v", err)
+			}
+			if perm := info.Mode().Perm(); perm != 0600 {
+				t.Errorf("expected 0600 for %s, got %o", path, perm)
+			}
+		}
+
+		info, _ := os.Stat(filepath.Join(root, "nested"))
+		if perm := info.Mode().Perm(); perm != 0700 {
+			t.Errorf("expected 0700 for directory, got %o", perm)
+		}
+	})
+
+	t.Run("set visibility", func(t *testing.T) {
+		fs, tmpDir, cleanup := setupLocalFS(t)
+		defer cleanup()
+
+		fs.Put(ctx, "file.txt", "content")
+		if err := fs.SetVisibility(ctx, "file.txt", contracts.VisibilityPrivate); err != nil {
+			t.Fatalf("SetVisibility failed: %v", err)
+		}
+
+		info, _ := os.Stat(filepath.Join(tmpDir, "file.txt"))
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("expected 0600, got %o", perm)
+		}
+		if v, _ := fs.GetVisibility(ctx, "file.txt"); v != contracts.VisibilityPrivate {
+			t.Errorf("expected private, got %q", v)
+		}
+
+		fs.SetVisibility(ctx, "file.txt", contracts.VisibilityPublic)
+		if v, _ := fs.GetVisibility(ctx, "file.txt"); v != contracts.VisibilityPublic {
+			t.Errorf("expected public, got %q", v)
+		}
+	})
+
+	t.Run("invalid visibility", func(t *testing.T) {
+		fs, _, cleanup := setupLocalFS(t)
+		defer cleanup()
+
+		fs.Put(ctx, "file.txt", "content")
+		if err := fs.SetVisibility(ctx, "file.txt", "hidden"); err == nil {
+			t.Error("expected error for invalid visibility")
+		}
+		if _, err := NewLocal(map[string]any{"root": t.TempDir(), "visibility": "hidden"}); err == nil {
+			t.Error("expected error for invalid default visibility")
+		}
+	})
+}
+
+func TestLocalChecksum(t *testing.T) {
+	fs, _, cleanup := setupLocalFS(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	fs.Put(ctx, "hello.txt", "hello")
+
+	cases := map[string]string{
+		contracts.ChecksumMD5:    "5d41402abc4b2a76b9719d911017c592",
+		contracts.ChecksumSHA1:   "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d",
+		contracts.ChecksumSHA256: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+	}
+	for algo, expected := range cases {
+		sum, err := fs.Checksum(ctx, "hello.txt", algo)
+		if err != nil {
+			t.Fatalf("%s checksum failed: %v", algo, err)
+		}
+		if sum != expected {
+			t.Errorf("expected %s checksum %s, got %s", algo, expected, sum)
+		}
+	}
+
+	if _, err := fs.Checksum(ctx, "hello.txt", "crc32"); err == nil {
+		t.Error("expected error for unsupported algorithm")
+	}
+	if _, err := fs.Checksum(ctx, "missing.txt", contracts.ChecksumMD5); !os.IsNotExist(err) {
+		t.Errorf("expected not-exist error, got %v", err)
+	}
+}
+
+func TestLocalMimeTypeAndMetadata(t *testing.T) {
+	fs, _, cleanup := setupLocalFS(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	fs.Put(ctx, "page.html", "<p>hi</p>")
+	fs.PutBytes(ctx, "image", []byte("\x89PNG\r\n\x1a\n0000"))
+
+	if mimeType, _ := fs.MimeType(ctx, "page.html"); !strings.HasPrefix(mimeType, "text/html") {
+		t.Errorf("expected text/html, got %q", mimeType)
+	}
+	if mimeType, _ := fs.MimeType(ctx, "image"); mimeType != "image/png" {
+		t.Errorf("expected sniffed image/png, got %q", mimeType)
+	}
+
+	meta, err := fs.Metadata(ctx, "page.html")
+	if err != nil {
+		t.Fatalf("metadata failed: %v", err)
+	}
+	if meta.Size != 9 || meta.ETag == "" || meta.LastModified.IsZero() {
+		t.Errorf("unexpected metadata %+v", meta)
+	}
+
+	// Rewriting the file changes its ETag.
+	time.Sleep(10 * time.Millisecond)
+	fs.Put(ctx, "page.html", "<p>bye!</p>")
+	updated, _ := fs.Metadata(ctx, "page.html")
+	if updated.ETag == meta.ETag {
+		t.Error("expected ETag to change after rewrite")
+	}
+}
+
+func TestLocalPrependAndAppend(t *testing.T) {
+	fs, tmpDir, cleanup := setupLocalFS(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := fs.Append(ctx, "logs/app.log", "second\n"); err != nil {
+		t.Fatalf("Append to missing file failed: %v", err)
+	}
+	fs.Append(ctx, "logs/app.log", "third\n")
+	if err := fs.Prepend(ctx, "logs/app.log", "first\n"); err != nil {
+		t.Fatalf("Prepend failed: %v", err)
+	}
+
+	content, _ := fs.Get(ctx, "logs/app.log")
+	if content != "first\nsecond\nthird\n" {
+		t.Errorf("unexpected content %q", content)
+	}
+
+	if err := fs.Prepend(ctx, "new.txt", "header"); err != nil {
+		t.Fatalf("Prepend to missing file failed: %v", err)
+	}
+	if content, _ := fs.Get(ctx, "new.txt"); content != "header" {
+		t.Errorf("unexpected content %q", content)
+	}
+
+	// No temporary files are left behind.
+	entries, _ := os.ReadDir(filepath.Join(tmpDir, "logs"))
+	if len(entries) != 1 {
+		t.Errorf("expected only app.log, got %d entries", len(entries))
+	}
+}
+
+func TestLocalPrependKeepsVisibility(t *testing.T) {
+	fs, err := NewLocal(map[string]any{"root": t.TempDir(), "visibility": contracts.VisibilityPrivate})
+	if err != nil {
+		t.Fatalf("failed to create local filesystem: %v", err)
+	}
+	ctx := context.Background()
+
+	fs.Put(ctx, "secret.txt", "b")
+	fs.Prepend(ctx, "secret.txt", "a")
+	if v, _ := fs.GetVisibility(ctx, "secret.txt"); v != contracts.VisibilityPrivate {
+		t.Errorf("expected private file after prepend, got %s", v)
+	}
+}
@@ -12,15 +12,19 @@ type Manager struct {
 	config  contracts.Config
 	disks   map[string]contracts.Filesystem
 	drivers map[string]func(config map[string]any) (contracts.Filesystem, error)
-	mu      sync.RWMutex
+
+	// resolving guards against scoped disks that refer back to themselves.
+	resolving map[string]bool
+	mu        sync.RWMutex
 }
 
 // NewManager creates a new filesystem manager.
 func NewManager(config contracts.Config) *Manager {
 	return &Manager{
-		config:  config,
-		disks:   make(map[string]contracts.Filesystem),
-		drivers: make(map[string]func(config map[string]any) (contracts.Filesystem, error)),
+		config:    config,
+		disks:     make(map[string]contracts.Filesystem),
+		drivers:   make(map[string]func(config map[string]any) (contracts.Filesystem, error)),
+		resolving: make(map[string]bool),
 	}
 }
 
@@ -43,27 +47,58 @@ func (m *Manager) Disk(name ...string) contracts.Filesystem {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	disk, err := m.disk(diskName)
+	if err != nil {
+		panic(err)
+	}
+	return disk
+}
+
+// Build creates a disk from a configuration without registering it, such as
+// a scoped disk for a tenant. Built disks are not cached.
+func (m *Manager) Build(config map[string]any) (contracts.Filesystem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.build(config)
+}
+
+// disk returns a cached disk or resolves and caches it. Callers hold the write lock.
+func (m *Manager) disk(name string) (contracts.Filesystem, error) {
 	// Double-check: another goroutine might have initialized it
-	if disk, ok := m.disks[diskName]; ok {
-		return disk
+	if disk, ok := m.disks[name]; ok {
+		return disk, nil
 	}
 
-	disk, err := m.resolve(diskName)
+	if m.resolving[name] {
+		return nil, fmt.Errorf("filesystem: disk %s refers to itself", name)
+	}
+	m.resolving[name] = true
+	defer delete(m.resolving, name)
+
+	disk, err := m.resolve(name)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	m.disks[diskName] = disk
-	return disk
+	m.disks[name] = disk
+	return disk, nil
 }
 
 // resolve resolves a disk instance.
 func (m *Manager) resolve(name string) (contracts.Filesystem, error) {
 	config := m.getConfig(name)
 
+	if _, ok := config["driver"].(string); !ok {
+		return nil, fmt.Errorf("filesystem: driver not defined for disk %s", name)
+	}
+	return m.build(config)
+}
+
+// build creates a disk from its configuration. Callers hold the write lock.
+func (m *Manager) build(config map[string]any) (contracts.Filesystem, error) {
 	driver, ok := config["driver"].(string)
 	if !ok {
-		return nil, fmt.Errorf("filesystem: driver not defined for disk %s", name)
+		return nil, fmt.Errorf("filesystem: driver not defined")
 	}
 
 	if creator, ok := m.drivers[driver]; ok {
@@ -76,6 +111,22 @@ func (m *Manager) resolve(name string) (contracts.Filesystem, error) {
 		return NewLocal(config)
 	case "s3":
 		return NewS3(config)
+	case "gcs":
+		return NewGCS(config)
+	case "azure":
+		return NewAzure(config)
+	case "memory":
+		return NewMemory(config)
+	case "scoped":
+		parent := configString(config, "disk")
+		if parent == "" {
+			return nil, fmt.Errorf("filesystem: disk not defined for scoped driver")
+		}
+		disk, err := m.disk(parent)
+		if err != nil {
+			return nil, err
+		}
+		return NewScoped(disk, configString(config, "prefix"))
 	default:
 		return nil, fmt.Errorf("filesystem: driver %s not supported", driver)
 	}
@@ -88,6 +139,11 @@ func (m *Manager) Extend(driver string, creator func(config map[string]any) (con
 	m.drivers[driver] = creator
 }
 
+// DefaultDisk returns the name of the default disk.
+func (m *Manager) DefaultDisk() string {
+	return m.getDefaultDriver()
+}
+
 // getDefaultDriver gets the default driver name.
 func (m *Manager) getDefaultDriver() string {
 	return m.config.GetString("filesystem.default")
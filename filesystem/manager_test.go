@@ -94,6 +94,10 @@ func (m *mockConfig) Load(path string) error {
 	return nil
 }
 
+func (m *mockConfig) Watch(key string, fn func(value any)) func() {
+	return func() {}
+}
+
 // Mock filesystem for testing
 type mockFilesystem struct {
 	name string
@@ -111,6 +115,10 @@ func (m *mockFilesystem) GetBytes(ctx context.Context, path string) ([]byte, err
 	return nil, nil
 }
 
+func (m *mockFilesystem) ReadStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	return nil, nil
+}
+
 func (m *mockFilesystem) Put(ctx context.Context, path string, contents string) error {
 	return nil
 }
@@ -155,6 +163,38 @@ func (m *mockFilesystem) Url(path string) string {
 	return ""
 }
 
+func (m *mockFilesystem) TemporaryUrl(ctx context.Context, path string, expiry time.Duration) (string, error) {
+	return "", nil
+}
+
+func (m *mockFilesystem) SetVisibility(ctx context.Context, path string, visibility string) error {
+	return nil
+}
+
+func (m *mockFilesystem) GetVisibility(ctx context.Context, path string) (string, error) {
+	return contracts.VisibilityPublic, nil
+}
+
+func (m *mockFilesystem) Prepend(ctx context.Context, path string, contents string) error {
+	return nil
+}
+
+func (m *mockFilesystem) Append(ctx context.Context, path string, contents string) error {
+	return nil
+}
+
+func (m *mockFilesystem) Checksum(ctx context.Context, path string, algo string) (string, error) {
+	return "", nil
+}
+
+func (m *mockFilesystem) MimeType(ctx context.Context, path string) (string, error) {
+	return "", nil
+}
+
+func (m *mockFilesystem) Metadata(ctx context.Context, path string) (*contracts.FileMetadata, error) {
+	return &contracts.FileMetadata{}, nil
+}
+
 func setupManager(t *testing.T) (*Manager, *mockConfig) {
 	t.Helper()
 
@@ -250,6 +290,20 @@ func TestManagerResolve(t *testing.T) {
 		}
 	})
 
+	t.Run("resolve memory driver", func(t *testing.T) {
+		cfg.data["filesystem.disks.memory"] = map[string]any{
+			"driver": "memory",
+		}
+
+		disk, err := manager.resolve("memory")
+		if err != nil {
+			t.Fatalf("failed to resolve memory driver: %v", err)
+		}
+		if _, ok := disk.(*Memory); !ok {
+			t.Errorf("expected *Memory, got %T", disk)
+		}
+	})
+
 	t.Run("missing driver in config", func(t *testing.T) {
 		cfg.data["filesystem.disks.nodrive"] = map[string]any{
 			"root": "/tmp",
@@ -0,0 +1,375 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+type memoryFile struct {
+	contents   []byte
+	modified   time.Time
+	visibility string
+}
+
+// Memory is an in-memory filesystem driver. It is intended for tests, where
+// it stands in for real disks through storage.Fake.
+type Memory struct {
+	files      map[string]*memoryFile
+	url        string
+	signingKey []byte
+	visibility string
+	mu         sync.RWMutex
+}
+
+// NewMemory creates a new in-memory filesystem instance.
+func NewMemory(config map[string]any) (*Memory, error) {
+	visibility, err := visibilityFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if visibility == "" {
+		visibility = contracts.VisibilityPublic
+	}
+
+	url := configString(config, "url")
+	if url == "" {
+		url = "/storage"
+	}
+	signingKey := configString(config, "signing_key")
+	if signingKey == "" {
+		signingKey = "memory"
+	}
+
+	return &Memory{
+		files:      make(map[string]*memoryFile),
+		url:        url,
+		signingKey: []byte(signingKey),
+		visibility: visibility,
+	}, nil
+}
+
+// normalizePath strips leading and trailing slashes so "a/b" and "/a/b" match.
+func (m *Memory) normalizePath(path string) string {
+	return strings.Trim(path, "/")
+}
+
+// file returns a file or an fs.ErrNotExist path error. Callers hold the lock.
+func (m *Memory) file(op, path string) (*memoryFile, error) {
+	file, ok := m.files[m.normalizePath(path)]
+	if !ok {
+		return nil, &fs.PathError{Op: op, Path: path, Err: fs.ErrNotExist}
+	}
+	return file, nil
+}
+
+// Files returns the paths of all stored files, sorted.
+func (m *Memory) Files() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	paths := make([]string, 0, len(m.files))
+	for path := range m.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Exists reports whether a file, or a directory containing files, exists.
+func (m *Memory) Exists(ctx context.Context, path string) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	path = m.normalizePath(path)
+	if _, ok := m.files[path]; ok {
+		return true
+	}
+	for name := range m.files {
+		if strings.HasPrefix(name, path+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Memory) Get(ctx context.Context, path string) (string, error) {
+	b, err := m.GetBytes(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (m *Memory) GetBytes(ctx context.Context, path string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	file, err := m.file("open", path)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(file.contents), nil
+}
+
+func (m *Memory) ReadStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	b, err := m.GetBytes(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (m *Memory) Put(ctx context.Context, path string, contents string) error {
+	return m.PutBytes(ctx, path, []byte(contents))
+}
+
+func (m *Memory) PutBytes(ctx context.Context, path string, contents []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.files[m.normalizePath(path)] = &memoryFile{
+		contents:   bytes.Clone(contents),
+		modified:   time.Now(),
+		visibility: m.visibility,
+	}
+	return nil
+}
+
+func (m *Memory) PutStream(ctx context.Context, path string, contents io.Reader) error {
+	b, err := io.ReadAll(contents)
+	if err != nil {
+		return err
+	}
+	return m.PutBytes(ctx, path, b)
+}
+
+func (m *Memory) Prepend(ctx context.Context, path string, contents string) error {
+	return m.write(ctx, path, func(existing []byte) []byte {
+		return append([]byte(contents), existing...)
+	})
+}
+
+func (m *Memory) Append(ctx context.Context, path string, contents string) error {
+	return m.write(ctx, path, func(existing []byte) []byte {
+		return append(existing, contents...)
+	})
+}
+
+// write updates a file's contents in place, creating it if missing.
+func (m *Memory) write(ctx context.Context, path string, update func(existing []byte) []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path = m.normalizePath(path)
+	file, ok := m.files[path]
+	if !ok {
+		file = &memoryFile{visibility: m.visibility}
+		m.files[path] = file
+	}
+	file.contents = update(bytes.Clone(file.contents))
+	file.modified = time.Now()
+	return nil
+}
+
+func (m *Memory) Delete(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.file("remove", path); err != nil {
+		return err
+	}
+	delete(m.files, m.normalizePath(path))
+	return nil
+}
+
+func (m *Memory) Copy(ctx context.Context, from, to string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, err := m.file("copy", from)
+	if err != nil {
+		return err
+	}
+	m.files[m.normalizePath(to)] = &memoryFile{
+		contents:   bytes.Clone(file.contents),
+		modified:   time.Now(),
+		visibility: file.visibility,
+	}
+	return nil
+}
+
+func (m *Memory) Move(ctx context.Context, from, to string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, err := m.file("rename", from)
+	if err != nil {
+		return err
+	}
+	delete(m.files, m.normalizePath(from))
+	m.files[m.normalizePath(to)] = file
+	return nil
+}
+
+func (m *Memory) Size(ctx context.Context, path string) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	file, err := m.file("stat", path)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(file.contents)), nil
+}
+
+func (m *Memory) LastModified(ctx context.Context, path string) (time.Time, error) {
+	if err := ctx.Err(); err != nil {
+		return time.Time{}, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	file, err := m.file("stat", path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return file.modified, nil
+}
+
+// MakeDirectory is a no-op: directories exist implicitly while they contain files.
+func (m *Memory) MakeDirectory(ctx context.Context, path string) error {
+	return ctx.Err()
+}
+
+func (m *Memory) DeleteDirectory(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prefix := m.normalizePath(path) + "/"
+	for name := range m.files {
+		if strings.HasPrefix(name, prefix) {
+			delete(m.files, name)
+		}
+	}
+	return nil
+}
+
+func (m *Memory) Url(path string) string {
+	return strings.TrimRight(m.url, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+// TemporaryUrl returns a URL signed the same way as local disks.
+func (m *Memory) TemporaryUrl(ctx context.Context, path string, expiry time.Duration) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return SignURL(m.Url(path), time.Now().Add(expiry), m.signingKey)
+}
+
+func (m *Memory) SetVisibility(ctx context.Context, path string, visibility string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := validateVisibility(visibility); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, err := m.file("chmod", path)
+	if err != nil {
+		return err
+	}
+	file.visibility = visibility
+	return nil
+}
+
+func (m *Memory) GetVisibility(ctx context.Context, path string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	file, err := m.file("stat", path)
+	if err != nil {
+		return "", err
+	}
+	return file.visibility, nil
+}
+
+func (m *Memory) Checksum(ctx context.Context, path string, algo string) (string, error) {
+	b, err := m.GetBytes(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return checksumReader(bytes.NewReader(b), algo)
+}
+
+// MimeType guesses the content type from the extension, or from the file's
+// leading bytes when the extension is unknown.
+func (m *Memory) MimeType(ctx context.Context, path string) (string, error) {
+	b, err := m.GetBytes(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return sniffContentType(path, bytes.NewReader(b))
+}
+
+func (m *Memory) Metadata(ctx context.Context, path string) (*contracts.FileMetadata, error) {
+	b, err := m.GetBytes(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	modified, err := m.LastModified(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	contentType, err := sniffContentType(path, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.FileMetadata{
+		Size:         int64(len(b)),
+		LastModified: modified,
+		ETag:         fmt.Sprintf(`"%x-%x"`, modified.UnixNano(), len(b)),
+		ContentType:  contentType,
+	}, nil
+}
@@ -0,0 +1,142 @@
+package filesystem
+
+import (
+	"context"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+func setupMemoryFS(t *testing.T) *Memory {
+	t.Helper()
+	fs, err := NewMemory(map[string]any{})
+	if err != nil {
+		t.Fatalf("failed to create memory filesystem: %v", err)
+	}
+	return fs
+}
+
+func TestMemoryPutAndGet(t *testing.T) {
+	fs := setupMemoryFS(t)
+	ctx := context.Background()
+
+	if err := fs.Put(ctx, "/docs/readme.txt", "hello"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	content, err := fs.Get(ctx, "docs/readme.txt")
+	if err != nil || content != "hello" {
+		t.Errorf("expected hello, got %q (%v)", content, err)
+	}
+	if !fs.Exists(ctx, "docs/readme.txt") || !fs.Exists(ctx, "docs") {
+		t.Error("expected file and its directory to exist")
+	}
+	if fs.Exists(ctx, "doc") {
+		t.Error("expected partial directory names not to match")
+	}
+
+	if _, err := fs.Get(ctx, "missing.txt"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist, got %v", err)
+	}
+}
+
+func TestMemoryReturnsCopies(t *testing.T) {
+	fs := setupMemoryFS(t)
+	ctx := context.Background()
+
+	data := []byte("abc")
+	fs.PutBytes(ctx, "a.bin", data)
+	data[0] = 'x'
+
+	b, _ := fs.GetBytes(ctx, "a.bin")
+	b[1] = 'y'
+
+	if content, _ := fs.Get(ctx, "a.bin"); content != "abc" {
+		t.Errorf("expected stored contents to be isolated, got %q", content)
+	}
+}
+
+func TestMemoryFileOperations(t *testing.T) {
+	fs := setupMemoryFS(t)
+	ctx := context.Background()
+
+	fs.PutStream(ctx, "a.txt", strings.NewReader("one"))
+	fs.Append(ctx, "a.txt", "-two")
+	fs.Prepend(ctx, "a.txt", "zero-")
+
+	if content, _ := fs.Get(ctx, "a.txt"); content != "zero-one-two" {
+		t.Errorf("unexpected content %q", content)
+	}
+	if size, _ := fs.Size(ctx, "a.txt"); size != 12 {
+		t.Errorf("expected size 12, got %d", size)
+	}
+
+	if err := fs.Copy(ctx, "a.txt", "b.txt"); err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+	if err := fs.Move(ctx, "b.txt", "dir/c.txt"); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if fs.Exists(ctx, "b.txt") || !fs.Exists(ctx, "dir/c.txt") {
+		t.Error("expected b.txt to be moved to dir/c.txt")
+	}
+
+	if got := strings.Join(fs.Files(), ","); got != "a.txt,dir/c.txt" {
+		t.Errorf("unexpected files %s", got)
+	}
+
+	if err := fs.DeleteDirectory(ctx, "dir"); err != nil {
+		t.Fatalf("DeleteDirectory failed: %v", err)
+	}
+	if err := fs.Delete(ctx, "a.txt"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if len(fs.Files()) != 0 {
+		t.Errorf("expected no files, got %v", fs.Files())
+	}
+	if err := fs.Delete(ctx, "a.txt"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist deleting a missing file, got %v", err)
+	}
+}
+
+func TestMemoryVisibilityAndMetadata(t *testing.T) {
+	fs := setupMemoryFS(t)
+	ctx := context.Background()
+
+	fs.Put(ctx, "page.html", "<html></html>")
+
+	if v, _ := fs.GetVisibility(ctx, "page.html"); v != contracts.VisibilityPublic {
+		t.Errorf("expected public, got %s", v)
+	}
+	fs.SetVisibility(ctx, "page.html", contracts.VisibilityPrivate)
+	if v, _ := fs.GetVisibility(ctx, "page.html"); v != contracts.VisibilityPrivate {
+		t.Errorf("expected private, got %s", v)
+	}
+
+	sum, _ := fs.Checksum(ctx, "page.html", contracts.ChecksumMD5)
+	if expected := fmt.Sprintf("%x", md5.Sum([]byte("<html></html>"))); sum != expected {
+		t.Errorf("expected md5 %s, got %s", expected, sum)
+	}
+
+	meta, err := fs.Metadata(ctx, "page.html")
+	if err != nil {
+		t.Fatalf("Metadata failed: %v", err)
+	}
+	if meta.Size != 13 || !strings.HasPrefix(meta.ContentType, "text/html") || meta.ETag == "" {
+		t.Errorf("unexpected metadata %+v", meta)
+	}
+
+	if url := fs.Url("page.html"); url != "/storage/page.html" {
+		t.Errorf("unexpected url %s", url)
+	}
+	temporary, err := fs.TemporaryUrl(ctx, "page.html", time.Minute)
+	if err != nil || !strings.Contains(temporary, "signature=") {
+		t.Errorf("expected signed url, got %s (%v)", temporary, err)
+	}
+}
@@ -0,0 +1,78 @@
+package filesystem
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// defaultContentType is used when a content type cannot be determined.
+const defaultContentType = "application/octet-stream"
+
+// newChecksumHash returns the hash for a checksum algorithm.
+func newChecksumHash(algo string) (hash.Hash, error) {
+	switch algo {
+	case contracts.ChecksumMD5:
+		return md5.New(), nil
+	case contracts.ChecksumSHA1:
+		return sha1.New(), nil
+	case contracts.ChecksumSHA256:
+		return sha256.New(), nil
+	default:
+		return nil, fmt.Errorf("filesystem: unsupported checksum algorithm %q", algo)
+	}
+}
+
+// checksumReader hashes a stream and returns the hex-encoded digest.
+func checksumReader(r io.Reader, algo string) (string, error) {
+	h, err := newChecksumHash(algo)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// contentTypeFor guesses a content type from a path's extension.
+func contentTypeFor(path string) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(path)); contentType != "" {
+		return contentType
+	}
+	return defaultContentType
+}
+
+// sniffContentType guesses a content type from a path's extension, falling
+// back to the leading bytes of the file.
+func sniffContentType(path string, r io.Reader) (string, error) {
+	if contentType := mime.TypeByExtension(filepath.Ext(path)); contentType != "" {
+		return contentType, nil
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	return http.DetectContentType(head[:n]), nil
+}
+
+// base64ToHex converts a base64-encoded digest, as returned by cloud APIs, to hex.
+func base64ToHex(digest string) string {
+	raw, err := base64.StdEncoding.DecodeString(digest)
+	if err != nil {
+		return ""
+	}
+	return hex.EncodeToString(raw)
+}
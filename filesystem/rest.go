@@ -0,0 +1,81 @@
+package filesystem
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultHTTPClient is used by the REST-based cloud drivers.
+var defaultHTTPClient = &http.Client{Timeout: 60 * time.Second}
+
+// RequestError is returned when a cloud storage API responds with an error status.
+type RequestError struct {
+	Method string
+	URL    string
+	Status int
+	Body   string
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("filesystem: %s %s returned %d: %s", e.Method, e.URL, e.Status, e.Body)
+}
+
+// Is reports 404 responses as os.ErrNotExist.
+func (e *RequestError) Is(target error) bool {
+	return target == os.ErrNotExist && e.Status == http.StatusNotFound
+}
+
+// checkResponse returns a RequestError for non-2xx responses and closes the body.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	url := ""
+	method := ""
+	if resp.Request != nil {
+		method = resp.Request.Method
+		url = resp.Request.URL.Redacted()
+		// Strip signatures and tokens from the reported URL.
+		if i := strings.IndexByte(url, '?'); i >= 0 {
+			url = url[:i]
+		}
+	}
+	return &RequestError{
+		Method: method,
+		URL:    url,
+		Status: resp.StatusCode,
+		Body:   strings.TrimSpace(string(body)),
+	}
+}
+
+// configInt reads an integer value from a disk configuration.
+// YAML and environment values may arrive as floats or numeric strings.
+func configInt(config map[string]any, key string, fallback int) int {
+	switch value := config[key].(type) {
+	case int:
+		return value
+	case int64:
+		return int(value)
+	case float64:
+		return int(value)
+	case string:
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+// configString reads a string value from a disk configuration.
+func configString(config map[string]any, key string) string {
+	value, _ := config[key].(string)
+	return value
+}
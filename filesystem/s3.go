@@ -9,12 +9,17 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/genesysflow/go-genesys/contracts"
 )
 
+// s3AllUsersGroup is the grantee URI S3 uses for anonymous (public) access.
+const s3AllUsersGroup = "http://acs.amazonaws.com/groups/global/AllUsers"
+
 // S3ClientInterface defines the interface for S3 operations
 type S3ClientInterface interface {
 	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
@@ -24,14 +29,33 @@ type S3ClientInterface interface {
 	CopyObject(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error)
 	DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
 	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+	PutObjectAcl(ctx context.Context, params *s3.PutObjectAclInput, optFns ...func(*s3.Options)) (*s3.PutObjectAclOutput, error)
+	GetObjectAcl(ctx context.Context, params *s3.GetObjectAclInput, optFns ...func(*s3.Options)) (*s3.GetObjectAclOutput, error)
+	CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error)
+	CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error)
+	ListParts(ctx context.Context, params *s3.ListPartsInput, optFns ...func(*s3.Options)) (*s3.ListPartsOutput, error)
+}
+
+// S3PresignerInterface defines the interface for presigning S3 requests
+type S3PresignerInterface interface {
+	PresignGetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
 }
 
 // S3 is the S3 filesystem driver.
 type S3 struct {
-	client S3ClientInterface
-	bucket string
-	url    string
-	region string
+	client     S3ClientInterface
+	presigner  S3PresignerInterface
+	bucket     string
+	url        string
+	region     string
+	visibility string
+
+	// Multipart upload settings; see PutStream.
+	partSize    int64
+	concurrency int
+	resumable   bool
 }
 
 // NewS3 creates a new S3 filesystem instance.
@@ -47,6 +71,19 @@ func NewS3(config map[string]any) (*S3, error) {
 	endpoint, _ := config["endpoint"].(string)
 	usePathStyle, _ := config["use_path_style_endpoint"].(bool)
 
+	// Only send ACLs when the disk asks for them; buckets with
+	// Object Ownership enforced reject any ACL.
+	visibility, err := visibilityFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	partSize := configInt(config, "part_size", DefaultS3PartSize)
+	if partSize < MinS3PartSize {
+		return nil, fmt.Errorf("filesystem: part_size must be at least %d bytes", MinS3PartSize)
+	}
+	resumable, _ := config["resumable"].(bool)
+
 	// Load AWS config using a root context; this is initialization-time configuration,
 	// so we don't currently require a cancellable context here.
 	cfg, err := awsconfig.LoadDefaultConfig(context.Background(),
@@ -66,10 +103,15 @@ func NewS3(config map[string]any) (*S3, error) {
 	})
 
 	return &S3{
-		client: client,
-		bucket: bucket,
-		url:    url,
-		region: region,
+		client:      client,
+		presigner:   s3.NewPresignClient(client),
+		bucket:      bucket,
+		url:         url,
+		region:      region,
+		visibility:  visibility,
+		partSize:    int64(partSize),
+		concurrency: configInt(config, "concurrency", DefaultS3UploadConcurrency),
+		resumable:   resumable,
 	}, nil
 }
 
@@ -102,6 +144,17 @@ func (s *S3) GetBytes(ctx context.Context, path string) ([]byte, error) {
 	return io.ReadAll(out.Body)
 }
 
+func (s *S3) ReadStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(path),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
 func (s *S3) Put(ctx context.Context, path string, contents string) error {
 	return s.PutStream(ctx, path, strings.NewReader(contents))
 }
@@ -110,13 +163,14 @@ func (s *S3) PutBytes(ctx context.Context, path string, contents []byte) error {
 	return s.PutStream(ctx, path, bytes.NewReader(contents))
 }
 
-func (s *S3) PutStream(ctx context.Context, path string, contents io.Reader) error {
-	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(s.bucket),
-		Key:    aws.String(path),
-		Body:   contents,
-	})
-	return err
+// Prepend rewrites the object with contents at the beginning.
+func (s *S3) Prepend(ctx context.Context, path string, contents string) error {
+	return readModifyWrite(ctx, s, path, contents, "")
+}
+
+// Append rewrites the object with contents at the end.
+func (s *S3) Append(ctx context.Context, path string, contents string) error {
+	return readModifyWrite(ctx, s, path, "", contents)
 }
 
 func (s *S3) Delete(ctx context.Context, path string) error {
@@ -132,6 +186,7 @@ func (s *S3) Copy(ctx context.Context, from, to string) error {
 		Bucket:     aws.String(s.bucket),
 		CopySource: aws.String(fmt.Sprintf("%s/%s", s.bucket, from)),
 		Key:        aws.String(to),
+		ACL:        s.acl(s.visibility),
 	})
 	return err
 }
@@ -246,3 +301,129 @@ func (s *S3) Url(path string) string {
 	}
 	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, strings.TrimLeft(path, "/"))
 }
+
+// TemporaryUrl returns a presigned GET URL for the object.
+func (s *S3) TemporaryUrl(ctx context.Context, path string, expiry time.Duration) (string, error) {
+	if s.presigner == nil {
+		return "", fmt.Errorf("filesystem: presigner not configured for s3 driver")
+	}
+
+	req, err := s.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(path),
+	}, s3.WithPresignExpires(expiry))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+// acl maps a visibility to a canned ACL. An empty visibility leaves the ACL unset.
+func (s *S3) acl(visibility string) types.ObjectCannedACL {
+	switch visibility {
+	case contracts.VisibilityPublic:
+		return types.ObjectCannedACLPublicRead
+	case contracts.VisibilityPrivate:
+		return types.ObjectCannedACLPrivate
+	default:
+		return ""
+	}
+}
+
+// SetVisibility applies the matching canned ACL to the object.
+func (s *S3) SetVisibility(ctx context.Context, path string, visibility string) error {
+	if err := validateVisibility(visibility); err != nil {
+		return err
+	}
+	_, err := s.client.PutObjectAcl(ctx, &s3.PutObjectAclInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(path),
+		ACL:    s.acl(visibility),
+	})
+	return err
+}
+
+// GetVisibility reports an object as public if anonymous users are granted read access.
+func (s *S3) GetVisibility(ctx context.Context, path string) (string, error) {
+	out, err := s.client.GetObjectAcl(ctx, &s3.GetObjectAclInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(path),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, grant := range out.Grants {
+		if grant.Grantee == nil || aws.ToString(grant.Grantee.URI) != s3AllUsersGroup {
+			continue
+		}
+		if grant.Permission == types.PermissionRead || grant.Permission == types.PermissionFullControl {
+			return contracts.VisibilityPublic, nil
+		}
+	}
+	return contracts.VisibilityPrivate, nil
+}
+
+// Checksum hashes the object's contents. MD5 checksums of objects uploaded in
+// a single part are taken from the ETag without downloading the object.
+func (s *S3) Checksum(ctx context.Context, path string, algo string) (string, error) {
+	if _, err := newChecksumHash(algo); err != nil {
+		return "", err
+	}
+
+	if algo == contracts.ChecksumMD5 {
+		out, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(s.bucket),
+			Key:    aws.String(path),
+		})
+		if err != nil {
+			return "", err
+		}
+		// Multipart and SSE-KMS ETags are not MD5 digests.
+		if etag := strings.Trim(aws.ToString(out.ETag), `"`); len(etag) == 32 {
+			return etag, nil
+		}
+	}
+
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(path),
+	})
+	if err != nil {
+		return "", err
+	}
+	defer out.Body.Close()
+
+	return checksumReader(out.Body, algo)
+}
+
+// MimeType returns the object's stored content type.
+func (s *S3) MimeType(ctx context.Context, path string) (string, error) {
+	meta, err := s.Metadata(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return meta.ContentType, nil
+}
+
+// Metadata returns the object's metadata from a HEAD request.
+func (s *S3) Metadata(ctx context.Context, path string) (*contracts.FileMetadata, error) {
+	out, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(path),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := aws.ToString(out.ContentType)
+	if contentType == "" {
+		contentType = contentTypeFor(path)
+	}
+	return &contracts.FileMetadata{
+		Size:         aws.ToInt64(out.ContentLength),
+		LastModified: aws.ToTime(out.LastModified),
+		ETag:         aws.ToString(out.ETag),
+		ContentType:  contentType,
+	}, nil
+}
@@ -0,0 +1,252 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// DefaultS3PartSize is the multipart upload part size used when a disk does not set part_size.
+	DefaultS3PartSize = 16 << 20
+
+	// MinS3PartSize is the smallest part size S3 accepts for all but the last part.
+	MinS3PartSize = 5 << 20
+
+	// DefaultS3UploadConcurrency is the number of parts uploaded in parallel by default.
+	DefaultS3UploadConcurrency = 4
+)
+
+// MultipartUploadError is returned when a multipart upload fails on a
+// resumable disk. The parts uploaded so far are kept, so the upload can be
+// continued with ResumeUpload or discarded with AbortUpload.
+type MultipartUploadError struct {
+	Path     string
+	UploadID string
+	Err      error
+}
+
+func (e *MultipartUploadError) Error() string {
+	return fmt.Sprintf("filesystem: multipart upload %s of %s failed: %v", e.UploadID, e.Path, e.Err)
+}
+
+func (e *MultipartUploadError) Unwrap() error {
+	return e.Err
+}
+
+// uploadedPart is a part that already exists in a multipart upload.
+type uploadedPart struct {
+	size     int64
+	etag     string
+	checksum *string
+}
+
+func (s *S3) getPartSize() int64 {
+	if s.partSize <= 0 {
+		return DefaultS3PartSize
+	}
+	return s.partSize
+}
+
+func (s *S3) getConcurrency() int {
+	if s.concurrency <= 0 {
+		return DefaultS3UploadConcurrency
+	}
+	return s.concurrency
+}
+
+// PutStream uploads a stream with the S3 upload manager. Streams larger than
+// the part size are sent as a multipart upload, holding at most concurrency
+// parts in memory at once.
+func (s *S3) PutStream(ctx context.Context, path string, contents io.Reader) error {
+	uploader := manager.NewUploader(s.client, func(u *manager.Uploader) {
+		u.PartSize = s.getPartSize()
+		u.Concurrency = s.getConcurrency()
+		u.LeavePartsOnError = s.resumable
+	})
+	_, err := uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(path),
+		Body:        contents,
+		ContentType: aws.String(contentTypeFor(path)),
+		ACL:         s.acl(s.visibility),
+		// Pinned so ResumeUpload sends parts with the same checksum.
+		ChecksumAlgorithm: types.ChecksumAlgorithmCrc32,
+	})
+
+	var failure manager.MultiUploadFailure
+	if s.resumable && errors.As(err, &failure) {
+		return &MultipartUploadError{Path: path, UploadID: failure.UploadID(), Err: errors.Unwrap(failure)}
+	}
+	return err
+}
+
+// ResumeUpload continues a multipart upload that failed with a
+// MultipartUploadError. contents must be the full stream from the start;
+// parts that were already uploaded intact are skipped. If it fails again,
+// the parts are kept and a MultipartUploadError is returned.
+func (s *S3) ResumeUpload(ctx context.Context, path, uploadID string, contents io.Reader) error {
+	existing, err := s.listParts(ctx, path, uploadID)
+	if err != nil {
+		return err
+	}
+
+	completed, err := s.uploadMissingParts(ctx, path, uploadID, contents, existing)
+	if err == nil {
+		sort.Slice(completed, func(i, j int) bool {
+			return aws.ToInt32(completed[i].PartNumber) < aws.ToInt32(completed[j].PartNumber)
+		})
+		_, err = s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(s.bucket),
+			Key:             aws.String(path),
+			UploadId:        aws.String(uploadID),
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+		})
+	}
+	if err != nil {
+		return &MultipartUploadError{Path: path, UploadID: uploadID, Err: err}
+	}
+	return nil
+}
+
+// AbortUpload discards a multipart upload and the parts uploaded so far.
+func (s *S3) AbortUpload(ctx context.Context, path, uploadID string) error {
+	_, err := s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(path),
+		UploadId: aws.String(uploadID),
+	})
+	return err
+}
+
+// uploadMissingParts reads the stream in parts, uploading those not present
+// in existing with matching content, and returns every part of the upload.
+func (s *S3) uploadMissingParts(ctx context.Context, path, uploadID string, contents io.Reader, existing map[int32]uploadedPart) ([]types.CompletedPart, error) {
+	var (
+		completed []types.CompletedPart
+		readErr   error
+		mu        sync.Mutex
+	)
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(s.getConcurrency())
+
+	partSize := s.getPartSize()
+	for partNumber := int32(1); ctx.Err() == nil; partNumber++ {
+		data, err := readPart(contents, partSize)
+		if err != nil {
+			readErr = err
+			break
+		}
+		if len(data) == 0 && partNumber > 1 {
+			break
+		}
+		if partNumber > manager.MaxUploadParts {
+			readErr = fmt.Errorf("filesystem: upload exceeds %d parts, increase part_size", manager.MaxUploadParts)
+			break
+		}
+
+		if part, ok := existing[partNumber]; ok && part.matches(data) {
+			mu.Lock()
+			completed = append(completed, types.CompletedPart{
+				PartNumber:    aws.Int32(partNumber),
+				ETag:          aws.String(part.etag),
+				ChecksumCRC32: part.checksum,
+			})
+			mu.Unlock()
+			continue
+		}
+
+		group.Go(func() error {
+			out, err := s.client.UploadPart(ctx, &s3.UploadPartInput{
+				Bucket:            aws.String(s.bucket),
+				Key:               aws.String(path),
+				UploadId:          aws.String(uploadID),
+				PartNumber:        aws.Int32(partNumber),
+				Body:              bytes.NewReader(data),
+				ChecksumAlgorithm: types.ChecksumAlgorithmCrc32,
+			})
+			if err != nil {
+				return fmt.Errorf("part %d: %w", partNumber, err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			completed = append(completed, types.CompletedPart{
+				PartNumber:    aws.Int32(partNumber),
+				ETag:          out.ETag,
+				ChecksumCRC32: out.ChecksumCRC32,
+			})
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return completed, readErr
+}
+
+// listParts returns the parts already uploaded to a multipart upload.
+func (s *S3) listParts(ctx context.Context, path, uploadID string) (map[int32]uploadedPart, error) {
+	parts := make(map[int32]uploadedPart)
+	input := &s3.ListPartsInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(path),
+		UploadId: aws.String(uploadID),
+	}
+
+	for {
+		out, err := s.client.ListParts(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, part := range out.Parts {
+			parts[aws.ToInt32(part.PartNumber)] = uploadedPart{
+				size:     aws.ToInt64(part.Size),
+				etag:     aws.ToString(part.ETag),
+				checksum: part.ChecksumCRC32,
+			}
+		}
+		if !aws.ToBool(out.IsTruncated) {
+			return parts, nil
+		}
+		input.PartNumberMarker = out.NextPartNumberMarker
+	}
+}
+
+// matches reports whether an uploaded part holds data. Plain ETags are the
+// part's MD5; other ETags (e.g. SSE-KMS) can only be compared by size.
+func (p uploadedPart) matches(data []byte) bool {
+	if p.size != int64(len(data)) {
+		return false
+	}
+	etag := strings.Trim(p.etag, `"`)
+	if len(etag) != 32 {
+		return true
+	}
+	sum := md5.Sum(data)
+	return etag == hex.EncodeToString(sum[:])
+}
+
+// readPart reads up to size bytes, returning fewer only at the end of the stream.
+func readPart(r io.Reader, size int64) ([]byte, error) {
+	buf := make([]byte, size)
+	n, err := io.ReadFull(r, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	return buf[:n], err
+}
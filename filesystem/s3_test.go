@@ -3,30 +3,46 @@ package filesystem
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/genesysflow/go-genesys/contracts"
 )
 
 // Mock S3 client for testing
 type mockS3Client struct {
 	objects        map[string][]byte
 	objectMetadata map[string]objectMeta
+	acls           map[string]types.ObjectCannedACL
 	headObjectErr  error
 	getObjectErr   error
 	putObjectErr   error
 	deleteErr      error
 	copyErr        error
+
+	uploads        map[string]map[int32][]byte
+	uploadPartErr  func(partNumber int32) error
+	uploadedParts  []int32
+	abortedUploads []string
+	nextUploadID   int
+	mu             sync.Mutex
 }
 
 type objectMeta struct {
 	size         int64
 	lastModified time.Time
+	contentType  string
 }
 
 func (m *mockS3Client) HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
@@ -40,9 +56,12 @@ func (m *mockS3Client) HeadObject(ctx context.Context, params *s3.HeadObjectInpu
 	}
 
 	meta := m.objectMetadata[key]
+	sum := md5.Sum(m.objects[key])
 	return &s3.HeadObjectOutput{
 		ContentLength: aws.Int64(meta.size),
 		LastModified:  aws.Time(meta.lastModified),
+		ETag:          aws.String(`"` + hex.EncodeToString(sum[:]) + `"`),
+		ContentType:   aws.String(meta.contentType),
 	}, nil
 }
 
@@ -77,7 +96,9 @@ func (m *mockS3Client) PutObject(ctx context.Context, params *s3.PutObjectInput,
 	m.objectMetadata[key] = objectMeta{
 		size:         int64(len(data)),
 		lastModified: time.Now(),
+		contentType:  aws.ToString(params.ContentType),
 	}
+	m.acls[key] = params.ACL
 
 	return &s3.PutObjectOutput{}, nil
 }
@@ -118,6 +139,7 @@ func (m *mockS3Client) CopyObject(ctx context.Context, params *s3.CopyObjectInpu
 		size:         int64(len(data)),
 		lastModified: time.Now(),
 	}
+	m.acls[destKey] = params.ACL
 
 	return &s3.CopyObjectOutput{}, nil
 }
@@ -156,10 +178,130 @@ func (m *mockS3Client) ListObjectsV2(ctx context.Context, params *s3.ListObjects
 	}, nil
 }
 
+func (m *mockS3Client) PutObjectAcl(ctx context.Context, params *s3.PutObjectAclInput, optFns ...func(*s3.Options)) (*s3.PutObjectAclOutput, error) {
+	key := aws.ToString(params.Key)
+	if _, exists := m.objects[key]; !exists {
+		return nil, &types.NoSuchKey{}
+	}
+	m.acls[key] = params.ACL
+	return &s3.PutObjectAclOutput{}, nil
+}
+
+func (m *mockS3Client) GetObjectAcl(ctx context.Context, params *s3.GetObjectAclInput, optFns ...func(*s3.Options)) (*s3.GetObjectAclOutput, error) {
+	key := aws.ToString(params.Key)
+	if _, exists := m.objects[key]; !exists {
+		return nil, &types.NoSuchKey{}
+	}
+
+	grants := []types.Grant{{
+		Grantee:    &types.Grantee{Type: types.TypeCanonicalUser, ID: aws.String("owner")},
+		Permission: types.PermissionFullControl,
+	}}
+	if m.acls[key] == types.ObjectCannedACLPublicRead {
+		grants = append(grants, types.Grant{
+			Grantee:    &types.Grantee{Type: types.TypeGroup, URI: aws.String(s3AllUsersGroup)},
+			Permission: types.PermissionRead,
+		})
+	}
+	return &s3.GetObjectAclOutput{Grants: grants}, nil
+}
+
+func (m *mockS3Client) CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextUploadID++
+	id := fmt.Sprintf("upload-%d", m.nextUploadID)
+	m.uploads[id] = make(map[int32][]byte)
+	m.acls[aws.ToString(params.Key)] = params.ACL
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String(id)}, nil
+}
+
+func (m *mockS3Client) UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error) {
+	partNumber := aws.ToInt32(params.PartNumber)
+	if m.uploadPartErr != nil {
+		if err := m.uploadPartErr(partNumber); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	parts, ok := m.uploads[aws.ToString(params.UploadId)]
+	if !ok {
+		return nil, &types.NoSuchUpload{}
+	}
+	parts[partNumber] = data
+	m.uploadedParts = append(m.uploadedParts, partNumber)
+
+	sum := md5.Sum(data)
+	return &s3.UploadPartOutput{ETag: aws.String(`"` + hex.EncodeToString(sum[:]) + `"`)}, nil
+}
+
+func (m *mockS3Client) CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := aws.ToString(params.UploadId)
+	parts, ok := m.uploads[id]
+	if !ok {
+		return nil, &types.NoSuchUpload{}
+	}
+
+	var data []byte
+	for i, part := range params.MultipartUpload.Parts {
+		if aws.ToInt32(part.PartNumber) != int32(i+1) {
+			return nil, fmt.Errorf("parts out of order")
+		}
+		data = append(data, parts[int32(i+1)]...)
+	}
+
+	key := aws.ToString(params.Key)
+	m.objects[key] = data
+	m.objectMetadata[key] = objectMeta{size: int64(len(data)), lastModified: time.Now()}
+	delete(m.uploads, id)
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (m *mockS3Client) AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.uploads, aws.ToString(params.UploadId))
+	m.abortedUploads = append(m.abortedUploads, aws.ToString(params.UploadId))
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func (m *mockS3Client) ListParts(ctx context.Context, params *s3.ListPartsInput, optFns ...func(*s3.Options)) (*s3.ListPartsOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	parts, ok := m.uploads[aws.ToString(params.UploadId)]
+	if !ok {
+		return nil, &types.NoSuchUpload{}
+	}
+
+	out := &s3.ListPartsOutput{IsTruncated: aws.Bool(false)}
+	for number, data := range parts {
+		sum := md5.Sum(data)
+		out.Parts = append(out.Parts, types.Part{
+			PartNumber: aws.Int32(number),
+			Size:       aws.Int64(int64(len(data))),
+			ETag:       aws.String(`"` + hex.EncodeToString(sum[:]) + `"`),
+		})
+	}
+	return out, nil
+}
+
 func newMockS3() *mockS3Client {
 	return &mockS3Client{
 		objects:        make(map[string][]byte),
 		objectMetadata: make(map[string]objectMeta),
+		acls:           make(map[string]types.ObjectCannedACL),
+		uploads:        make(map[string]map[int32][]byte),
 	}
 }
 
@@ -651,3 +793,248 @@ func (m *mockS3ClientNilTime) HeadObject(ctx context.Context, params *s3.HeadObj
 		LastModified:  nil, // Intentionally nil
 	}, nil
 }
+
+type mockS3Presigner struct {
+	expires time.Duration
+}
+
+func (m *mockS3Presigner) PresignGetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error) {
+	opts := s3.PresignOptions{}
+	for _, fn := range optFns {
+		fn(&opts)
+	}
+	m.expires = opts.Expires
+	return &v4.PresignedHTTPRequest{
+		URL:    "https://" + aws.ToString(params.Bucket) + ".s3.amazonaws.com/" + aws.ToString(params.Key) + "?X-Amz-Signature=abc",
+		Method: "GET",
+	}, nil
+}
+
+func TestS3TemporaryUrl(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("uses presigner", func(t *testing.T) {
+		fs, _ := setupS3FS(t)
+		presigner := &mockS3Presigner{}
+		fs.presigner = presigner
+
+		url, err := fs.TemporaryUrl(ctx, "private/file.txt", 15*time.Minute)
+		if err != nil {
+			t.Fatalf("TemporaryUrl failed: %v", err)
+		}
+		expected := "https://test-bucket.s3.amazonaws.com/private/file.txt?X-Amz-Signature=abc"
+		if url != expected {
+			t.Errorf("expected '%s', got '%s'", expected, url)
+		}
+		if presigner.expires != 15*time.Minute {
+			t.Errorf("expected expiry to be passed to presigner, got %v", presigner.expires)
+		}
+	})
+
+	t.Run("without presigner", func(t *testing.T) {
+		fs, _ := setupS3FS(t)
+		if _, err := fs.TemporaryUrl(ctx, "file.txt", time.Minute); err == nil {
+			t.Error("expected error without presigner")
+		}
+	})
+}
+
+func TestS3Visibility(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no default visibility leaves ACL unset", func(t *testing.T) {
+		fs, mock := setupS3FS(t)
+		if err := fs.Put(ctx, "file.txt", "content"); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		if acl := mock.acls["file.txt"]; acl != "" {
+			t.Errorf("expected no ACL, got %q", acl)
+		}
+	})
+
+	t.Run("default visibility applied on put and copy", func(t *testing.T) {
+		fs, mock := setupS3FS(t)
+		fs.visibility = contracts.VisibilityPublic
+
+		fs.Put(ctx, "file.txt", "content")
+		fs.Copy(ctx, "file.txt", "copy.txt")
+
+		for _, key := range []string{"file.txt", "copy.txt"} {
+			if acl := mock.acls[key]; acl != types.ObjectCannedACLPublicRead {
+				t.Errorf("expected public-read ACL on %s, got %q", key, acl)
+			}
+		}
+	})
+
+	t.Run("set and get visibility", func(t *testing.T) {
+		fs, _ := setupS3FS(t)
+		fs.Put(ctx, "file.txt", "content")
+
+		if err := fs.SetVisibility(ctx, "file.txt", contracts.VisibilityPublic); err != nil {
+			t.Fatalf("SetVisibility failed: %v", err)
+		}
+		if v, _ := fs.GetVisibility(ctx, "file.txt"); v != contracts.VisibilityPublic {
+			t.Errorf("expected public, got %q", v)
+		}
+
+		fs.SetVisibility(ctx, "file.txt", contracts.VisibilityPrivate)
+		if v, _ := fs.GetVisibility(ctx, "file.txt"); v != contracts.VisibilityPrivate {
+			t.Errorf("expected private, got %q", v)
+		}
+	})
+
+	t.Run("invalid visibility", func(t *testing.T) {
+		fs, _ := setupS3FS(t)
+		fs.Put(ctx, "file.txt", "content")
+		if err := fs.SetVisibility(ctx, "file.txt", "secret"); err == nil {
+			t.Error("expected error for invalid visibility")
+		}
+	})
+}
+
+func TestS3MultipartUpload(t *testing.T) {
+	ctx := context.Background()
+	part := strings.Repeat("a", MinS3PartSize)
+
+	t.Run("small streams use a single put", func(t *testing.T) {
+		fs, mock := setupS3FS(t)
+		fs.partSize = MinS3PartSize
+
+		if err := fs.Put(ctx, "small.txt", part[1:]); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+		if len(mock.uploadedParts) != 0 {
+			t.Errorf("expected no multipart upload, got parts %v", mock.uploadedParts)
+		}
+	})
+
+	t.Run("large streams are uploaded in parts", func(t *testing.T) {
+		fs, mock := setupS3FS(t)
+		fs.partSize = MinS3PartSize
+		fs.concurrency = 2
+		content := strings.Repeat(part, 4) + "xyz"
+
+		if err := fs.PutStream(ctx, "large.txt", strings.NewReader(content)); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+		if len(mock.uploadedParts) != 5 {
+			t.Errorf("expected 5 parts, got %v", mock.uploadedParts)
+		}
+		if string(mock.objects["large.txt"]) != content {
+			t.Errorf("unexpected object content of %d bytes", len(mock.objects["large.txt"]))
+		}
+	})
+
+	t.Run("failed uploads are aborted", func(t *testing.T) {
+		fs, mock := setupS3FS(t)
+		fs.partSize = MinS3PartSize
+		mock.uploadPartErr = func(partNumber int32) error {
+			if partNumber == 2 {
+				return errors.New("connection reset")
+			}
+			return nil
+		}
+
+		err := fs.PutStream(ctx, "large.txt", strings.NewReader(strings.Repeat(part, 3)+"end"))
+		if err == nil || !strings.Contains(err.Error(), "connection reset") {
+			t.Fatalf("expected upload error, got %v", err)
+		}
+		if len(mock.abortedUploads) != 1 {
+			t.Errorf("expected upload to be aborted, got %v", mock.abortedUploads)
+		}
+	})
+
+	t.Run("resumable uploads continue where they failed", func(t *testing.T) {
+		fs, mock := setupS3FS(t)
+		fs.partSize = MinS3PartSize
+		fs.concurrency = 1
+		fs.resumable = true
+		content := strings.Repeat(part, 3) + "end"
+
+		failing := true
+		mock.uploadPartErr = func(partNumber int32) error {
+			if failing && partNumber == 3 {
+				return errors.New("connection reset")
+			}
+			return nil
+		}
+
+		err := fs.PutStream(ctx, "large.txt", strings.NewReader(content))
+		var uploadErr *MultipartUploadError
+		if !errors.As(err, &uploadErr) {
+			t.Fatalf("expected MultipartUploadError, got %v", err)
+		}
+		if len(mock.abortedUploads) != 0 {
+			t.Fatal("resumable uploads must not be aborted")
+		}
+
+		failing = false
+		mock.uploadedParts = nil
+		if err := fs.ResumeUpload(ctx, "large.txt", uploadErr.UploadID, strings.NewReader(content)); err != nil {
+			t.Fatalf("resume failed: %v", err)
+		}
+		if fmt.Sprint(mock.uploadedParts) != "[3 4]" {
+			t.Errorf("expected only parts 3 and 4 to be uploaded, got %v", mock.uploadedParts)
+		}
+		if string(mock.objects["large.txt"]) != content {
+			t.Errorf("unexpected object content of %d bytes", len(mock.objects["large.txt"]))
+		}
+	})
+}
+
+func TestNewS3PartSize(t *testing.T) {
+	_, err := NewS3(map[string]any{"bucket": "b", "part_size": 1024})
+	if err == nil || !strings.Contains(err.Error(), "part_size") {
+		t.Errorf("expected part_size error, got %v", err)
+	}
+}
+
+func TestS3ChecksumAndMetadata(t *testing.T) {
+	fs, _ := setupS3FS(t)
+	ctx := context.Background()
+
+	if err := fs.Put(ctx, "docs/readme.txt", "hello"); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+
+	sum, err := fs.Checksum(ctx, "docs/readme.txt", contracts.ChecksumMD5)
+	if err != nil || sum != "5d41402abc4b2a76b9719d911017c592" {
+		t.Errorf("unexpected md5 %q (%v)", sum, err)
+	}
+	sum, err = fs.Checksum(ctx, "docs/readme.txt", contracts.ChecksumSHA256)
+	if err != nil || sum != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("unexpected sha256 %q (%v)", sum, err)
+	}
+	if _, err := fs.Checksum(ctx, "docs/readme.txt", "crc32"); err == nil {
+		t.Error("expected error for unsupported algorithm")
+	}
+
+	meta, err := fs.Metadata(ctx, "docs/readme.txt")
+	if err != nil {
+		t.Fatalf("metadata failed: %v", err)
+	}
+	if meta.Size != 5 || meta.ETag == "" || !strings.HasPrefix(meta.ContentType, "text/plain") {
+		t.Errorf("unexpected metadata %+v", meta)
+	}
+}
+
+func TestS3PrependAndAppend(t *testing.T) {
+	fs, mock := setupS3FS(t)
+	ctx := context.Background()
+
+	if err := fs.Append(ctx, "manifest.txt", "b\n"); err != nil {
+		t.Fatalf("append to missing object failed: %v", err)
+	}
+	fs.Append(ctx, "manifest.txt", "c\n")
+	if err := fs.Prepend(ctx, "manifest.txt", "a\n"); err != nil {
+		t.Fatalf("prepend failed: %v", err)
+	}
+	if got := string(mock.objects["manifest.txt"]); got != "a\nb\nc\n" {
+		t.Errorf("unexpected content %q", got)
+	}
+
+	mock.getObjectErr = errors.New("access denied")
+	if err := fs.Append(ctx, "manifest.txt", "d\n"); err == nil {
+		t.Error("expected read errors other than not-found to be returned")
+	}
+}
@@ -0,0 +1,144 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"io"
+	pathpkg "path"
+	"strings"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// Scoped is a disk that roots every path under a prefix of another disk.
+type Scoped struct {
+	disk   contracts.Filesystem
+	prefix string
+}
+
+// NewScoped creates a disk that stores files under prefix on disk.
+func NewScoped(disk contracts.Filesystem, prefix string) (*Scoped, error) {
+	if disk == nil {
+		return nil, fmt.Errorf("filesystem: scoped disk requires a parent disk")
+	}
+	prefix = strings.Trim(pathpkg.Clean("/"+prefix), "/")
+	if prefix == "" {
+		return nil, fmt.Errorf("filesystem: prefix not defined for scoped driver")
+	}
+
+	return &Scoped{
+		disk:   disk,
+		prefix: prefix,
+	}, nil
+}
+
+// Prefix returns the prefix all paths are stored under.
+func (s *Scoped) Prefix() string {
+	return s.prefix
+}
+
+// path maps a path onto the parent disk. Paths are cleaned as if rooted, so
+// ".." cannot climb out of the prefix.
+func (s *Scoped) path(path string) string {
+	cleaned := strings.TrimLeft(pathpkg.Clean("/"+path), "/")
+	if cleaned == "" {
+		return s.prefix
+	}
+	if strings.HasSuffix(path, "/") {
+		cleaned += "/"
+	}
+	return s.prefix + "/" + cleaned
+}
+
+func (s *Scoped) Exists(ctx context.Context, path string) bool {
+	return s.disk.Exists(ctx, s.path(path))
+}
+
+func (s *Scoped) Get(ctx context.Context, path string) (string, error) {
+	return s.disk.Get(ctx, s.path(path))
+}
+
+func (s *Scoped) GetBytes(ctx context.Context, path string) ([]byte, error) {
+	return s.disk.GetBytes(ctx, s.path(path))
+}
+
+func (s *Scoped) ReadStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	return s.disk.ReadStream(ctx, s.path(path))
+}
+
+func (s *Scoped) Put(ctx context.Context, path string, contents string) error {
+	return s.disk.Put(ctx, s.path(path), contents)
+}
+
+func (s *Scoped) PutBytes(ctx context.Context, path string, contents []byte) error {
+	return s.disk.PutBytes(ctx, s.path(path), contents)
+}
+
+func (s *Scoped) PutStream(ctx context.Context, path string, contents io.Reader) error {
+	return s.disk.PutStream(ctx, s.path(path), contents)
+}
+
+func (s *Scoped) Prepend(ctx context.Context, path string, contents string) error {
+	return s.disk.Prepend(ctx, s.path(path), contents)
+}
+
+func (s *Scoped) Append(ctx context.Context, path string, contents string) error {
+	return s.disk.Append(ctx, s.path(path), contents)
+}
+
+func (s *Scoped) Delete(ctx context.Context, path string) error {
+	return s.disk.Delete(ctx, s.path(path))
+}
+
+func (s *Scoped) Copy(ctx context.Context, from, to string) error {
+	return s.disk.Copy(ctx, s.path(from), s.path(to))
+}
+
+func (s *Scoped) Move(ctx context.Context, from, to string) error {
+	return s.disk.Move(ctx, s.path(from), s.path(to))
+}
+
+func (s *Scoped) Size(ctx context.Context, path string) (int64, error) {
+	return s.disk.Size(ctx, s.path(path))
+}
+
+func (s *Scoped) LastModified(ctx context.Context, path string) (time.Time, error) {
+	return s.disk.LastModified(ctx, s.path(path))
+}
+
+func (s *Scoped) MakeDirectory(ctx context.Context, path string) error {
+	return s.disk.MakeDirectory(ctx, s.path(path))
+}
+
+func (s *Scoped) DeleteDirectory(ctx context.Context, path string) error {
+	return s.disk.DeleteDirectory(ctx, s.path(path))
+}
+
+func (s *Scoped) Url(path string) string {
+	return s.disk.Url(s.path(path))
+}
+
+func (s *Scoped) TemporaryUrl(ctx context.Context, path string, expiry time.Duration) (string, error) {
+	return s.disk.TemporaryUrl(ctx, s.path(path), expiry)
+}
+
+func (s *Scoped) SetVisibility(ctx context.Context, path string, visibility string) error {
+	return s.disk.SetVisibility(ctx, s.path(path), visibility)
+}
+
+func (s *Scoped) GetVisibility(ctx context.Context, path string) (string, error) {
+	return s.disk.GetVisibility(ctx, s.path(path))
+}
+
+func (s *Scoped) Checksum(ctx context.Context, path string, algo string) (string, error) {
+	return s.disk.Checksum(ctx, s.path(path), algo)
+}
+
+func (s *Scoped) MimeType(ctx context.Context, path string) (string, error) {
+	return s.disk.MimeType(ctx, s.path(path))
+}
+
+func (s *Scoped) Metadata(ctx context.Context, path string) (*contracts.FileMetadata, error) {
+	return s.disk.Metadata(ctx, s.path(path))
+}
@@ -0,0 +1,91 @@
+package filesystem
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestScopedPaths(t *testing.T) {
+	parent := setupMemoryFS(t)
+	fs, err := NewScoped(parent, "/tenants/42/")
+	if err != nil {
+		t.Fatalf("NewScoped failed: %v", err)
+	}
+	ctx := context.Background()
+
+	fs.Put(ctx, "invoices/1.pdf", "pdf")
+	fs.Put(ctx, "../../escape.txt", "nope")
+	fs.Put(ctx, "/abs.txt", "abs")
+
+	if got := strings.Join(parent.Files(), ","); got != "tenants/42/abs.txt,tenants/42/escape.txt,tenants/42/invoices/1.pdf" {
+		t.Errorf("unexpected parent files %s", got)
+	}
+	if content, _ := fs.Get(ctx, "invoices/1.pdf"); content != "pdf" {
+		t.Errorf("unexpected content %q", content)
+	}
+
+	if err := fs.Move(ctx, "abs.txt", "moved.txt"); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if !parent.Exists(ctx, "tenants/42/moved.txt") {
+		t.Error("expected move to stay within the prefix")
+	}
+
+	if url := fs.Url("invoices/1.pdf"); url != "/storage/tenants/42/invoices/1.pdf" {
+		t.Errorf("unexpected url %s", url)
+	}
+
+	fs.DeleteDirectory(ctx, "invoices")
+	if parent.Exists(ctx, "tenants/42/invoices/1.pdf") {
+		t.Error("expected directory to be deleted")
+	}
+
+	if _, err := NewScoped(parent, "/"); err == nil {
+		t.Error("expected an error for an empty prefix")
+	}
+}
+
+func TestManagerBuildScoped(t *testing.T) {
+	manager, cfg := setupManager(t)
+	ctx := context.Background()
+
+	disk, err := manager.Build(map[string]any{
+		"driver": "scoped",
+		"disk":   "local",
+		"prefix": "tenants/7",
+	})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if err := disk.Put(ctx, "avatar.txt", "hi"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if !manager.Disk("local").Exists(ctx, "tenants/7/avatar.txt") {
+		t.Error("expected file under the prefix on the parent disk")
+	}
+
+	// Scoped disks can also be configured and are cached like other disks.
+	cfg.data["filesystem.disks.uploads"] = map[string]any{
+		"driver": "scoped",
+		"disk":   "local",
+		"prefix": "uploads",
+	}
+	if manager.Disk("uploads") != manager.Disk("uploads") {
+		t.Error("expected configured scoped disk to be cached")
+	}
+
+	if _, err := manager.Build(map[string]any{"driver": "scoped", "prefix": "x"}); err == nil {
+		t.Error("expected an error without a parent disk")
+	}
+
+	cfg.data["filesystem.disks.loop"] = map[string]any{
+		"driver": "scoped",
+		"disk":   "loop",
+		"prefix": "x",
+	}
+	if _, err := manager.Build(map[string]any{"driver": "scoped", "disk": "loop", "prefix": "y"}); err == nil {
+		t.Error("expected an error for a disk that refers to itself")
+	}
+}
@@ -0,0 +1,30 @@
+package filesystem
+
+import (
+	"fmt"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// validateVisibility ensures the visibility is one of the supported values.
+func validateVisibility(visibility string) error {
+	switch visibility {
+	case contracts.VisibilityPublic, contracts.VisibilityPrivate:
+		return nil
+	default:
+		return fmt.Errorf("filesystem: invalid visibility %q", visibility)
+	}
+}
+
+// visibilityFromConfig reads the default visibility for a disk.
+// It returns an empty string if the disk does not configure one.
+func visibilityFromConfig(config map[string]any) (string, error) {
+	visibility, _ := config["visibility"].(string)
+	if visibility == "" {
+		return "", nil
+	}
+	if err := validateVisibility(visibility); err != nil {
+		return "", err
+	}
+	return visibility, nil
+}
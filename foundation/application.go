@@ -4,13 +4,16 @@ package foundation
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"reflect"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/genesysflow/go-genesys/config"
 	"github.com/genesysflow/go-genesys/container"
@@ -33,7 +36,11 @@ type Application struct {
 	debug       bool
 	booted      bool
 
+	configLoaded    bool
+	stopConfigWatch context.CancelFunc
+
 	providers *providers.ProviderRegistry
+	manifest  map[string]ManifestProvider // from package:discover, read on first Register
 	config    *config.Config
 	logger    contracts.Logger
 
@@ -76,6 +83,10 @@ func New(basePath ...string) *Application {
 	app.environment = env.Get("APP_ENV", "local")
 	app.debug = env.GetBool("APP_DEBUG", true)
 
+	// Resolve ${vault:...} and ${aws-sm:...} references in config files
+	app.config.AddSecretProvider("vault", config.NewVaultProvider(config.VaultConfig{}))
+	app.config.AddSecretProvider("aws-sm", config.NewAWSSecretsManagerProvider(config.AWSSecretsManagerConfig{}))
+
 	return app
 }
 
@@ -199,11 +210,7 @@ func (app *Application) Register(provider contracts.ServiceProvider) error {
 	defer app.mu.Unlock()
 
 	// Get provider name for tracking
-	t := reflect.TypeOf(provider)
-	if t.Kind() == reflect.Ptr {
-		t = t.Elem()
-	}
-	providerName := t.PkgPath() + "." + t.Name()
+	providerName := providerName(provider)
 
 	// Check if already registered
 	if app.providers.IsRegistered(providerName) {
@@ -213,10 +220,17 @@ func (app *Application) Register(provider contracts.ServiceProvider) error {
 	// Add to registry
 	app.providers.Register(provider)
 
-	// Check if this is a deferrable provider
-	if deferrable, ok := provider.(contracts.DeferrableProvider); ok && deferrable.IsDeferred() {
+	// Check if this is a deferrable provider, as recorded in the provider
+	// manifest if there is one
+	deferred, provides := false, []string(nil)
+	if entry, ok := app.manifestEntry(providerName); ok {
+		deferred, provides = entry.Deferred, entry.Provides
+	} else if deferrable, ok := provider.(contracts.DeferrableProvider); ok && deferrable.IsDeferred() {
+		deferred, provides = true, provider.Provides()
+	}
+	if deferred {
 		// Register for deferred loading
-		for _, service := range provider.Provides() {
+		for _, service := range provides {
 			app.providers.AddDeferred(service, provider)
 		}
 		app.providers.MarkRegistered(providerName)
@@ -273,12 +287,9 @@ func (app *Application) Boot() error {
 	}
 
 	// Load configuration
-	configPath := app.ConfigPath()
-	if _, err := os.Stat(configPath); err == nil {
-		if err := app.config.Load(configPath); err != nil {
-			app.mu.Unlock()
-			return fmt.Errorf("failed to load config: %w", err)
-		}
+	if err := app.loadConfiguration(); err != nil {
+		app.mu.Unlock()
+		return err
 	}
 
 	// Run booting callbacks
@@ -298,6 +309,26 @@ func (app *Application) Boot() error {
 	app.mu.Lock()
 	app.booted = true
 
+	// Reload config files when they change and refresh secrets, if enabled
+	watchFiles := app.config.GetBool("app.config_watch")
+	secretsInterval := time.Duration(app.config.GetInt("app.secrets_refresh_interval")) * time.Second
+	if watchFiles || secretsInterval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		app.stopConfigWatch = cancel
+		onError := func(err error) {
+			if logger := app.GetLogger(); logger != nil {
+				logger.Error("Failed to reload config", "error", err)
+			}
+		}
+		if watchFiles {
+			interval := time.Duration(app.config.GetInt("app.config_watch_interval")) * time.Second
+			app.config.WatchFiles(ctx, interval, onError)
+		}
+		if secretsInterval > 0 {
+			app.config.WatchSecrets(ctx, secretsInterval, onError)
+		}
+	}
+
 	// Run booted callbacks
 	for _, callback := range app.bootedCallbacks {
 		callback(app)
@@ -307,6 +338,32 @@ func (app *Application) Boot() error {
 	return nil
 }
 
+// loadConfiguration loads the config directory once, or the config cache
+// written by config:cache when there is one. Callers hold the lock.
+func (app *Application) loadConfiguration() error {
+	if app.configLoaded {
+		return nil
+	}
+
+	cachePath := config.CachePath(app.StoragePath())
+	if _, err := os.Stat(cachePath); err == nil {
+		if err := app.config.LoadCache(cachePath); err != nil {
+			return fmt.Errorf("failed to load config cache: %w", err)
+		}
+		app.configLoaded = true
+		return nil
+	}
+
+	configPath := app.ConfigPath()
+	if _, err := os.Stat(configPath); err == nil {
+		if err := app.config.LoadEnvironment(configPath, app.environment); err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+	}
+	app.configLoaded = true
+	return nil
+}
+
 // IsBooted returns true if the application has been booted.
 func (app *Application) IsBooted() bool {
 	app.mu.RLock()
@@ -341,6 +398,11 @@ func (app *Application) Terminating(callback func(contracts.Application)) {
 	app.terminatingCallback = append(app.terminatingCallback, callback)
 }
 
+// closeOnTerminate names the services Terminate closes after the
+// terminating callbacks, in order: queues stop before the database their
+// jobs may use.
+var closeOnTerminate = []string{"queue", "db"}
+
 // Terminate terminates the application.
 func (app *Application) Terminate() error {
 	return app.TerminateWithContext(context.Background())
@@ -350,6 +412,10 @@ func (app *Application) Terminate() error {
 func (app *Application) TerminateWithContext(ctx context.Context) error {
 	app.mu.Lock()
 	callbacks := app.terminatingCallback
+	if app.stopConfigWatch != nil {
+		app.stopConfigWatch()
+		app.stopConfigWatch = nil
+	}
 	app.mu.Unlock()
 
 	// Run terminating callbacks
@@ -357,17 +423,36 @@ func (app *Application) TerminateWithContext(ctx context.Context) error {
 		callback(app)
 	}
 
-	// Shutdown the container
+	// Close connections, then shutdown the container
+	closeErr := app.closeServices()
+
 	// Note: samber/do may return marshaling errors during shutdown which are harmless
 	err := app.ShutdownWithContext(ctx)
-	if err != nil {
+	if err != nil && strings.Contains(err.Error(), "marshaling error") {
 		// Ignore JSON marshaling errors from samber/do - they don't affect shutdown
-		if strings.Contains(err.Error(), "marshaling error") {
-			return nil
+		err = nil
+	}
+	return errors.Join(closeErr, err)
+}
+
+// closeServices closes the bound services in closeOnTerminate.
+func (app *Application) closeServices() error {
+	var errs []error
+	for _, name := range closeOnTerminate {
+		if !app.Container.Has(name) {
+			continue
+		}
+		service, err := app.Container.Make(name)
+		if err != nil {
+			continue
+		}
+		if closer, ok := service.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("application: failed to close %s: %w", name, err))
+			}
 		}
-		return err
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
 // Make resolves a service by name from the container.
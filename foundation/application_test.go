@@ -4,7 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/genesysflow/go-genesys/config"
 	"github.com/genesysflow/go-genesys/container"
 	"github.com/genesysflow/go-genesys/contracts"
 	"github.com/samber/do/v2"
@@ -127,6 +129,30 @@ func TestTerminate(t *testing.T) {
 	assert.True(t, terminatedCalled)
 }
 
+type recordingCloser struct {
+	name   string
+	closed *[]string
+}
+
+func (c recordingCloser) Close() error {
+	*c.closed = append(*c.closed, c.name)
+	return nil
+}
+
+func TestTerminateClosesConnectionsInOrder(t *testing.T) {
+	app := New()
+	var closed []string
+
+	app.Instance("db", recordingCloser{name: "db", closed: &closed})
+	app.Instance("queue", recordingCloser{name: "queue", closed: &closed})
+	app.Terminating(func(a contracts.Application) {
+		closed = append(closed, "callback")
+	})
+
+	_ = app.Terminate()
+	assert.Equal(t, []string{"callback", "queue", "db"}, closed)
+}
+
 // verify interfaces
 var _ contracts.Application = (*Application)(nil)
 var _ contracts.Container = (*Application)(nil)
@@ -163,3 +189,34 @@ func TestResolve_WithApplication(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "hello", val)
 }
+
+func TestBoot_UsesConfigCache(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "config"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "config", "app.yaml"), []byte("name: from-files\n"), 0644))
+
+	cfg := config.New()
+	cfg.Set("app.name", "from-cache")
+	assert.NoError(t, cfg.WriteCache(config.CachePath(filepath.Join(dir, "storage"))))
+
+	app := New(dir)
+	assert.NoError(t, app.Boot())
+	assert.Equal(t, "from-cache", app.GetConfig().GetString("app.name"))
+}
+
+func TestBoot_WatchesConfigWhenEnabled(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config", "features.yaml")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "config", "app.yaml"), []byte("config_watch: true\nconfig_watch_interval: 1\n"), 0644))
+	assert.NoError(t, os.WriteFile(path, []byte("beta: false\n"), 0644))
+
+	app := New(dir)
+	assert.NoError(t, app.Boot())
+	defer app.Terminate()
+
+	assert.NoError(t, os.WriteFile(path, []byte("beta: true # now on\n"), 0644))
+	assert.Eventually(t, func() bool {
+		return app.GetConfig().GetBool("features.beta")
+	}, 5*time.Second, 50*time.Millisecond)
+}
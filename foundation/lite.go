@@ -0,0 +1,42 @@
+package foundation
+
+import (
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/providers"
+)
+
+// Lite creates and boots an application with only the container, config,
+// logging, validation, encryption and database services, for adopting the
+// framework piece by piece inside an existing service. Unlike New, the
+// config directory is loaded before any provider registers, so config is
+// available right away.
+//
+// More providers can be added with Register; they are booted immediately.
+// No HTTP server is started; mount routes in an existing net/http server
+// with http.Kernel's Handler.
+func Lite(basePath ...string) (*Application, error) {
+	app := New(basePath...)
+
+	app.mu.Lock()
+	err := app.loadConfiguration()
+	app.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, provider := range []contracts.ServiceProvider{
+		&providers.AppServiceProvider{},
+		&providers.LogServiceProvider{},
+		&providers.ValidationServiceProvider{},
+		&providers.CryptServiceProvider{},
+		&providers.DatabaseServiceProvider{},
+	} {
+		if err := app.Register(provider); err != nil {
+			return nil, err
+		}
+	}
+	if err := app.Boot(); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
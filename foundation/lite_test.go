@@ -0,0 +1,44 @@
+package foundation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/genesysflow/go-genesys/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLite(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "config"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "database.yaml"), []byte(
+		"default: sqlite\nconnections:\n  sqlite:\n    driver: sqlite\n    database: \":memory:\"\n"), 0644))
+
+	var configAtRegister string
+	app, err := Lite(dir)
+	require.NoError(t, err)
+	assert.True(t, app.IsBooted())
+
+	// Providers registered later see the config too.
+	provider := &MockProvider{}
+	provider.On("Register", app).Run(func(args mock.Arguments) {
+		configAtRegister = app.Config().GetString("database.default")
+	}).Return(nil)
+	provider.On("Boot", app).Return(nil)
+	require.NoError(t, app.Register(provider))
+	assert.Equal(t, "sqlite", configAtRegister)
+
+	_, err = container.Resolve[*validation.Validator](app)
+	assert.NoError(t, err)
+
+	db, err := container.Resolve[*database.Manager](app)
+	require.NoError(t, err)
+	conn := db.Connection()
+	require.NoError(t, conn.Error())
+	assert.NoError(t, conn.DB().Ping())
+}
@@ -0,0 +1,95 @@
+package foundation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// ProviderManifest records what each registered provider told the
+// application at registration: whether it's deferred and which services
+// it provides. It is written by package:discover.
+type ProviderManifest struct {
+	Providers []ManifestProvider `json:"providers"`
+}
+
+// ManifestProvider is a provider's entry in the manifest.
+type ManifestProvider struct {
+	Name     string   `json:"name"`
+	Deferred bool     `json:"deferred"`
+	Provides []string `json:"provides,omitempty"`
+}
+
+// ProviderManifestPath returns the path of the provider manifest under an
+// app's storage directory.
+func ProviderManifestPath(storagePath string) string {
+	return filepath.Join(storagePath, "framework", "providers.json")
+}
+
+// DiscoverProviders describes the registered providers, in registration
+// order.
+func (app *Application) DiscoverProviders() ProviderManifest {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+
+	manifest := ProviderManifest{Providers: []ManifestProvider{}}
+	for _, provider := range app.providers.All() {
+		entry := ManifestProvider{Name: providerName(provider), Provides: provider.Provides()}
+		if deferrable, ok := provider.(contracts.DeferrableProvider); ok {
+			entry.Deferred = deferrable.IsDeferred()
+		}
+		manifest.Providers = append(manifest.Providers, entry)
+	}
+	return manifest
+}
+
+// WriteProviderManifest writes the manifest of the registered providers to
+// path. While it exists, providers it lists are registered as it says
+// without being asked, so it must be written again when a provider changes
+// whether it's deferred or what it provides; providers it doesn't list
+// are asked as usual.
+func (app *Application) WriteProviderManifest(path string) error {
+	data, err := json.MarshalIndent(app.DiscoverProviders(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("application: failed to encode provider manifest: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("application: failed to create manifest directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("application: failed to write provider manifest '%s': %w", path, err)
+	}
+	return nil
+}
+
+// manifestEntry returns the manifest's entry for a provider, reading the
+// manifest on first use. Callers hold the lock.
+func (app *Application) manifestEntry(name string) (ManifestProvider, bool) {
+	if app.manifest == nil {
+		app.manifest = make(map[string]ManifestProvider)
+		if data, err := os.ReadFile(ProviderManifestPath(app.StoragePath())); err == nil {
+			var manifest ProviderManifest
+			// An unreadable manifest is ignored, as if it weren't there.
+			if json.Unmarshal(data, &manifest) == nil {
+				for _, entry := range manifest.Providers {
+					app.manifest[entry.Name] = entry
+				}
+			}
+		}
+	}
+	entry, ok := app.manifest[name]
+	return entry, ok
+}
+
+// providerName returns the name a provider is tracked by.
+func providerName(provider contracts.ServiceProvider) string {
+	t := reflect.TypeOf(provider)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
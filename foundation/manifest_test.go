@@ -0,0 +1,81 @@
+package foundation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reportsProvider is a deferrable provider that counts how often it is
+// asked about itself.
+type reportsProvider struct {
+	providers.DeferrableProvider
+	asked      int
+	registered int
+}
+
+func (p *reportsProvider) IsDeferred() bool {
+	p.asked++
+	return p.DeferrableProvider.IsDeferred()
+}
+
+func (p *reportsProvider) Provides() []string {
+	p.asked++
+	return []string{"reports"}
+}
+
+func (p *reportsProvider) Register(app contracts.Application) error {
+	p.registered++
+	return app.Instance("reports", "report service")
+}
+
+func TestProviderManifest(t *testing.T) {
+	dir := t.TempDir()
+	app := New(dir)
+	provider := &reportsProvider{}
+	provider.SetDeferred(true)
+	require.NoError(t, app.Register(provider))
+	assert.Equal(t, 0, provider.registered)
+
+	manifest := app.DiscoverProviders()
+	require.Len(t, manifest.Providers, 1)
+	assert.Equal(t, ManifestProvider{
+		Name:     "github.com/genesysflow/go-genesys/foundation.reportsProvider",
+		Deferred: true,
+		Provides: []string{"reports"},
+	}, manifest.Providers[0])
+
+	path := ProviderManifestPath(app.StoragePath())
+	require.NoError(t, app.WriteProviderManifest(path))
+
+	// A new app registers the provider from the manifest without asking it.
+	cached := New(dir)
+	fresh := &reportsProvider{}
+	require.NoError(t, cached.Register(fresh))
+	assert.Equal(t, 0, fresh.asked)
+	assert.Equal(t, 0, fresh.registered)
+
+	service, err := cached.Make("reports")
+	require.NoError(t, err)
+	assert.Equal(t, "report service", service)
+	assert.Equal(t, 1, fresh.registered)
+}
+
+func TestProviderManifestIgnoresUnknownProviders(t *testing.T) {
+	dir := t.TempDir()
+	path := ProviderManifestPath(filepath.Join(dir, "storage"))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(`{"providers":[]}`), 0644))
+
+	app := New(dir)
+	provider := &reportsProvider{}
+	provider.SetDeferred(true)
+	require.NoError(t, app.Register(provider))
+	assert.Positive(t, provider.asked, "providers missing from the manifest are asked as usual")
+	assert.Equal(t, 0, provider.registered)
+}
@@ -0,0 +1,109 @@
+package hashing
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2idOptions are the argon2id cost parameters.
+type Argon2idOptions struct {
+	// Memory is the memory cost in KiB. Defaults to 65536 (64 MiB).
+	Memory uint32
+
+	// Time is the number of passes. Defaults to 3.
+	Time uint32
+
+	// Threads is the degree of parallelism. Defaults to 2.
+	Threads uint8
+}
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// Argon2idHasher hashes passwords with argon2id, encoded in the PHC string
+// format used by PHP and libsodium:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>.
+type Argon2idHasher struct {
+	options Argon2idOptions
+}
+
+// NewArgon2id creates an argon2id hasher. Zero options use the defaults.
+func NewArgon2id(options Argon2idOptions) *Argon2idHasher {
+	if options.Memory == 0 {
+		options.Memory = 65536
+	}
+	if options.Time == 0 {
+		options.Time = 3
+	}
+	if options.Threads == 0 {
+		options.Threads = 2
+	}
+	return &Argon2idHasher{options: options}
+}
+
+// Make hashes a value with a random salt.
+func (h *Argon2idHasher) Make(value string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("hashing: %w", err)
+	}
+
+	o := h.options
+	key := argon2.IDKey([]byte(value), salt, o.Time, o.Memory, o.Threads, argon2KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, o.Memory, o.Time, o.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Check reports whether value matches an argon2id hash, using the
+// parameters stored in the hash.
+func (h *Argon2idHasher) Check(value, hashed string) bool {
+	options, salt, key, err := decodeArgon2id(hashed)
+	if err != nil || value == "" {
+		return false
+	}
+	computed := argon2.IDKey([]byte(value), salt, options.Time, options.Memory, options.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(computed, key) == 1
+}
+
+// NeedsRehash reports whether hashed is not an argon2id hash with the
+// current parameters.
+func (h *Argon2idHasher) NeedsRehash(hashed string) bool {
+	options, _, _, err := decodeArgon2id(hashed)
+	return err != nil || options != h.options
+}
+
+func decodeArgon2id(hashed string) (Argon2idOptions, []byte, []byte, error) {
+	var options Argon2idOptions
+	parts := strings.Split(hashed, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return options, nil, nil, fmt.Errorf("hashing: not an argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return options, nil, nil, fmt.Errorf("hashing: unsupported argon2 version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &options.Memory, &options.Time, &options.Threads); err != nil {
+		return options, nil, nil, fmt.Errorf("hashing: invalid argon2id parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return options, nil, nil, fmt.Errorf("hashing: invalid argon2id salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return options, nil, nil, fmt.Errorf("hashing: invalid argon2id hash")
+	}
+	return options, salt, key, nil
+}
@@ -0,0 +1,41 @@
+package hashing
+
+import (
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptHasher hashes passwords with bcrypt.
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcrypt creates a bcrypt hasher. A cost of 0 uses bcrypt.DefaultCost.
+func NewBcrypt(cost int) *BcryptHasher {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{cost: cost}
+}
+
+// Make hashes a value. Values longer than 72 bytes are rejected by bcrypt.
+func (h *BcryptHasher) Make(value string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(value), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// Check reports whether value matches a bcrypt hash.
+func (h *BcryptHasher) Check(value, hashed string) bool {
+	if value == "" || hashed == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(value)) == nil
+}
+
+// NeedsRehash reports whether hashed is not a bcrypt hash of the current cost.
+func (h *BcryptHasher) NeedsRehash(hashed string) bool {
+	cost, err := bcrypt.Cost([]byte(hashed))
+	return err != nil || cost != h.cost
+}
@@ -0,0 +1,104 @@
+// Package hashing hashes and verifies passwords with bcrypt or argon2id.
+package hashing
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// Manager hashes with the default driver and verifies hashes made by any
+// registered driver, so hashes can move to a new algorithm as users log in.
+type Manager struct {
+	driver  string
+	drivers map[string]contracts.Hasher
+	mu      sync.RWMutex
+}
+
+// NewManager creates a manager with default bcrypt and argon2id drivers.
+// An empty driver name selects bcrypt.
+func NewManager(driver string) *Manager {
+	if driver == "" {
+		driver = "bcrypt"
+	}
+	return &Manager{
+		driver: driver,
+		drivers: map[string]contracts.Hasher{
+			"bcrypt":   NewBcrypt(0),
+			"argon2id": NewArgon2id(Argon2idOptions{}),
+		},
+	}
+}
+
+// Extend registers a driver, replacing any driver with the same name.
+func (m *Manager) Extend(name string, hasher contracts.Hasher) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.drivers[name] = hasher
+}
+
+// DefaultDriver returns the name of the driver new hashes are made with.
+func (m *Manager) DefaultDriver() string {
+	return m.driver
+}
+
+// Driver returns a driver, or the default driver.
+func (m *Manager) Driver(name ...string) (contracts.Hasher, error) {
+	driver := m.driver
+	if len(name) > 0 && name[0] != "" {
+		driver = name[0]
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	hasher, ok := m.drivers[driver]
+	if !ok {
+		return nil, fmt.Errorf("hashing: driver %q is not registered", driver)
+	}
+	return hasher, nil
+}
+
+// Make hashes a value with the default driver.
+func (m *Manager) Make(value string) (string, error) {
+	hasher, err := m.Driver()
+	if err != nil {
+		return "", err
+	}
+	return hasher.Make(value)
+}
+
+// Check reports whether value matches hashed, using the driver that made
+// the hash.
+func (m *Manager) Check(value, hashed string) bool {
+	hasher, err := m.Driver(Algorithm(hashed))
+	if err != nil {
+		return false
+	}
+	return hasher.Check(value, hashed)
+}
+
+// NeedsRehash reports whether hashed was not made by the default driver
+// with its current settings.
+func (m *Manager) NeedsRehash(hashed string) bool {
+	hasher, err := m.Driver()
+	if err != nil {
+		return false
+	}
+	return hasher.NeedsRehash(hashed)
+}
+
+// Algorithm returns the name of the driver that made a hash, or "" if it
+// is not recognized.
+func Algorithm(hashed string) string {
+	switch {
+	case strings.HasPrefix(hashed, "$argon2id$"):
+		return "argon2id"
+	case strings.HasPrefix(hashed, "$2a$"), strings.HasPrefix(hashed, "$2b$"), strings.HasPrefix(hashed, "$2y$"):
+		return "bcrypt"
+	}
+	return ""
+}
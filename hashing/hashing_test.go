@@ -0,0 +1,76 @@
+package hashing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fastArgon2id keeps tests quick.
+var fastArgon2id = Argon2idOptions{Memory: 1024, Time: 1, Threads: 1}
+
+func TestBcrypt(t *testing.T) {
+	hasher := NewBcrypt(bcrypt.MinCost)
+	hashed, err := hasher.Make("secret")
+	require.NoError(t, err)
+
+	assert.True(t, hasher.Check("secret", hashed))
+	assert.False(t, hasher.Check("wrong", hashed))
+	assert.False(t, hasher.Check("", hashed))
+	assert.False(t, hasher.NeedsRehash(hashed))
+	assert.True(t, NewBcrypt(bcrypt.MinCost+1).NeedsRehash(hashed))
+	assert.True(t, hasher.NeedsRehash("not a hash"))
+}
+
+func TestArgon2id(t *testing.T) {
+	hasher := NewArgon2id(fastArgon2id)
+	hashed, err := hasher.Make("secret")
+	require.NoError(t, err)
+	assert.Regexp(t, `^\$argon2id\$v=19\$m=1024,t=1,p=1\$[A-Za-z0-9+/]+\$[A-Za-z0-9+/]+$`, hashed)
+
+	other, err := hasher.Make("secret")
+	require.NoError(t, err)
+	assert.NotEqual(t, hashed, other, "salts are random")
+
+	assert.True(t, hasher.Check("secret", hashed))
+	assert.False(t, hasher.Check("wrong", hashed))
+	assert.False(t, hasher.NeedsRehash(hashed))
+
+	// Hashes keep their own parameters, so stronger settings still verify
+	// old hashes but ask for a rehash.
+	stronger := NewArgon2id(Argon2idOptions{Memory: 2048, Time: 1, Threads: 1})
+	assert.True(t, stronger.Check("secret", hashed))
+	assert.True(t, stronger.NeedsRehash(hashed))
+
+	assert.False(t, hasher.Check("secret", "$argon2id$v=19$m=1024,t=1,p=1$bad$"))
+	assert.False(t, hasher.Check("secret", "$argon2i$v=19$m=1024,t=1,p=1$c2FsdA$aGFzaA"))
+}
+
+func TestArgon2idDefaults(t *testing.T) {
+	hasher := NewArgon2id(Argon2idOptions{})
+	assert.Equal(t, Argon2idOptions{Memory: 65536, Time: 3, Threads: 2}, hasher.options)
+}
+
+func TestManager(t *testing.T) {
+	manager := NewManager("argon2id")
+	manager.Extend("argon2id", NewArgon2id(fastArgon2id))
+	manager.Extend("bcrypt", NewBcrypt(bcrypt.MinCost))
+
+	hashed, err := manager.Make("secret")
+	require.NoError(t, err)
+	assert.Equal(t, "argon2id", Algorithm(hashed))
+	assert.True(t, manager.Check("secret", hashed))
+	assert.False(t, manager.NeedsRehash(hashed))
+
+	// Hashes from the previous driver still check, and need a rehash.
+	legacy, err := NewBcrypt(bcrypt.MinCost).Make("secret")
+	require.NoError(t, err)
+	assert.Equal(t, "bcrypt", Algorithm(legacy))
+	assert.True(t, manager.Check("secret", legacy))
+	assert.True(t, manager.NeedsRehash(legacy))
+
+	_, err = NewManager("scrypt").Make("secret")
+	assert.ErrorContains(t, err, `driver "scrypt" is not registered`)
+}
@@ -0,0 +1,98 @@
+package health
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path"
+
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// Pinger is a connection that can be pinged, such as a database connection
+// or a *sql.DB.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Ping checks that a connection answers a ping.
+func Ping(conn Pinger) CheckFunc {
+	return conn.PingContext
+}
+
+// ConnectionResolver resolves database connections by name.
+// *database.Manager implements it.
+type ConnectionResolver interface {
+	Connection(name ...string) contracts.Connection
+}
+
+// Database pings the named database connections, or the default one.
+func Database(db ConnectionResolver, connections ...string) CheckFunc {
+	if len(connections) == 0 {
+		connections = []string{""}
+	}
+	return func(ctx context.Context) error {
+		for _, name := range connections {
+			if err := db.Connection(name).PingContext(ctx); err != nil {
+				if name == "" {
+					return err
+				}
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		return nil
+	}
+}
+
+// Redis checks that a Redis server answers PING.
+func Redis(client cache.RedisCommander) CheckFunc {
+	return func(ctx context.Context) error {
+		reply, err := client.Do(ctx, "PING")
+		if err != nil {
+			return err
+		}
+		if reply != "PONG" {
+			return fmt.Errorf("unexpected PING reply %v", reply)
+		}
+		return nil
+	}
+}
+
+// DiskWritable checks that a file can be written to and deleted from a disk.
+// The probe file is written under ".health/".
+func DiskWritable(disk contracts.Filesystem) CheckFunc {
+	return func(ctx context.Context) error {
+		var id [8]byte
+		if _, err := rand.Read(id[:]); err != nil {
+			return err
+		}
+		file := path.Join(".health", hex.EncodeToString(id[:]))
+
+		if err := disk.Put(ctx, file, "ok"); err != nil {
+			return fmt.Errorf("write: %w", err)
+		}
+		if err := disk.Delete(ctx, file); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+		return nil
+	}
+}
+
+// Sizer reports the number of pending jobs in a queue.
+// *queue.MemoryQueue implements it.
+type Sizer interface {
+	Size() int
+}
+
+// QueueDepth checks that a queue holds at most max pending jobs, so a
+// backlog marks the application unready before it grows unbounded.
+func QueueDepth(queue Sizer, max int) CheckFunc {
+	return func(ctx context.Context) error {
+		if depth := queue.Size(); depth > max {
+			return fmt.Errorf("queue depth %d exceeds %d", depth, max)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,184 @@
+// Package health runs liveness and readiness checks, such as database
+// pings and queue depth limits, and reports them as JSON for load balancers
+// and orchestrators on /healthz and /readyz.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Status is the outcome of a check or a report.
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// DefaultTimeout bounds each check unless the registry sets another.
+const DefaultTimeout = 5 * time.Second
+
+// CheckFunc checks one dependency and returns an error if it is unhealthy.
+// The context is cancelled when the check times out.
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult is the outcome of one check.
+type CheckResult struct {
+	Status    Status  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report aggregates check results. It is up only if every check is up.
+type Report struct {
+	Status Status                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Healthy reports whether every check passed.
+func (r Report) Healthy() bool {
+	return r.Status == StatusUp
+}
+
+// Registry holds the application's checks. Liveness checks tell whether the
+// process works at all and should be restarted if not; readiness checks
+// tell whether it can serve traffic, which depends on its databases, caches
+// and queues.
+type Registry struct {
+	checks  []check
+	timeout time.Duration
+	mu      sync.RWMutex
+}
+
+type check struct {
+	name     string
+	fn       CheckFunc
+	liveness bool
+}
+
+// NewRegistry creates a registry without checks.
+func NewRegistry() *Registry {
+	return &Registry{timeout: DefaultTimeout}
+}
+
+// SetTimeout sets how long each check may run before it is reported down.
+func (r *Registry) SetTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if timeout > 0 {
+		r.timeout = timeout
+	}
+}
+
+// Register adds a readiness check, replacing any check with the same name.
+func (r *Registry) Register(name string, fn CheckFunc) {
+	r.add(check{name: name, fn: fn})
+}
+
+// RegisterLiveness adds a liveness check, replacing any check with the same
+// name. Liveness checks run for readiness too.
+func (r *Registry) RegisterLiveness(name string, fn CheckFunc) {
+	r.add(check{name: name, fn: fn, liveness: true})
+}
+
+func (r *Registry) add(c check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, existing := range r.checks {
+		if existing.name == c.name {
+			r.checks[i] = c
+			return
+		}
+	}
+	r.checks = append(r.checks, c)
+}
+
+// Names returns the names of the registered checks in registration order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, len(r.checks))
+	for i, c := range r.checks {
+		names[i] = c.name
+	}
+	return names
+}
+
+// Liveness runs the liveness checks. Without any, the report is up.
+func (r *Registry) Liveness(ctx context.Context) Report {
+	return r.run(ctx, true)
+}
+
+// Readiness runs every check.
+func (r *Registry) Readiness(ctx context.Context) Report {
+	return r.run(ctx, false)
+}
+
+// run runs the checks concurrently, each with the registry's timeout.
+func (r *Registry) run(ctx context.Context, livenessOnly bool) Report {
+	r.mu.RLock()
+	timeout := r.timeout
+	var checks []check
+	for _, c := range r.checks {
+		if c.liveness || !livenessOnly {
+			checks = append(checks, c)
+		}
+	}
+	r.mu.RUnlock()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]CheckResult, len(checks))}
+	results := make([]CheckResult, len(checks))
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runCheck(ctx, c.fn, timeout)
+		}()
+	}
+	wg.Wait()
+
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+		if results[i].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+// runCheck runs fn with a timeout. A check that panics or overruns its
+// timeout is down, even if fn ignores the context.
+func runCheck(ctx context.Context, fn CheckFunc, timeout time.Duration) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("panic: %v", p)
+			}
+		}()
+		done <- fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	result := CheckResult{Status: StatusUp, LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,98 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/filesystem"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/queue"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ok(ctx context.Context) error { return nil }
+
+func TestRegistryReports(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetTimeout(50 * time.Millisecond)
+
+	registry.RegisterLiveness("goroutines", ok)
+	registry.Register("database", ok)
+	registry.Register("cache", func(ctx context.Context) error { return errors.New("connection refused") })
+	registry.Register("slow", func(ctx context.Context) error { time.Sleep(time.Second); return nil })
+	registry.Register("broken", func(ctx context.Context) error { panic("boom") })
+	registry.Register("database", ok) // replaces
+
+	assert.Equal(t, []string{"goroutines", "database", "cache", "slow", "broken"}, registry.Names())
+
+	live := registry.Liveness(context.Background())
+	assert.True(t, live.Healthy())
+	assert.Len(t, live.Checks, 1)
+
+	ready := registry.Readiness(context.Background())
+	assert.Equal(t, StatusDown, ready.Status)
+	assert.Len(t, ready.Checks, 5)
+	assert.Equal(t, StatusUp, ready.Checks["database"].Status)
+	assert.Equal(t, "connection refused", ready.Checks["cache"].Error)
+	assert.Equal(t, "timed out after 50ms", ready.Checks["slow"].Error)
+	assert.Equal(t, "panic: boom", ready.Checks["broken"].Error)
+
+	assert.True(t, NewRegistry().Readiness(context.Background()).Healthy())
+}
+
+type fakeRedis struct{ reply any }
+
+func (f fakeRedis) Do(ctx context.Context, args ...any) (any, error) { return f.reply, nil }
+
+func TestChecks(t *testing.T) {
+	ctx := context.Background()
+
+	assert.NoError(t, Redis(fakeRedis{reply: "PONG"})(ctx))
+	assert.Error(t, Redis(fakeRedis{reply: nil})(ctx))
+
+	disk, err := filesystem.NewMemory(map[string]any{})
+	require.NoError(t, err)
+	assert.NoError(t, DiskWritable(disk)(ctx))
+	assert.Empty(t, disk.Files())
+
+	jobs := queue.NewMemoryQueue()
+	check := QueueDepth(jobs, 1)
+	assert.NoError(t, check(ctx))
+	require.NoError(t, jobs.Push(&noopJob{}))
+	require.NoError(t, jobs.Push(&noopJob{}))
+	assert.EqualError(t, check(ctx), "queue depth 2 exceeds 1")
+}
+
+type noopJob struct{}
+
+func (noopJob) Handle() error { return nil }
+
+func TestRoutes(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(nil, app)
+
+	registry := NewRegistry()
+	registry.Register("database", func(ctx context.Context) error { return errors.New("down") })
+	registry.Routes(router)
+
+	resp, err := app.Test(httptest.NewRequest("GET", LivenessPath, nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+
+	resp, err = app.Test(httptest.NewRequest("GET", ReadinessPath, nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+
+	var report Report
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, "down", report.Checks["database"].Error)
+	assert.NotNil(t, router.NamedRoute("health.readiness"))
+}
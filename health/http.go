@@ -0,0 +1,53 @@
+package health
+
+import (
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Default paths of the health endpoints.
+const (
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+// Routes registers the liveness and readiness endpoints on router, at
+// LivenessPath and ReadinessPath unless other paths are given. They are
+// named "health.liveness" and "health.readiness".
+func (r *Registry) Routes(router *http.Router, paths ...string) {
+	liveness, readiness := LivenessPath, ReadinessPath
+	if len(paths) > 0 && paths[0] != "" {
+		liveness = paths[0]
+	}
+	if len(paths) > 1 && paths[1] != "" {
+		readiness = paths[1]
+	}
+
+	router.GET(liveness, r.LivenessHandler()).Name("health.liveness")
+	router.GET(readiness, r.ReadinessHandler()).Name("health.readiness")
+}
+
+// LivenessHandler responds with the liveness report: 200 when up, 503 when
+// down.
+func (r *Registry) LivenessHandler() http.HandlerFunc {
+	return func(ctx *http.Context) error {
+		return respond(ctx, r.Liveness(ctx.FiberCtx().UserContext()))
+	}
+}
+
+// ReadinessHandler responds with the readiness report: 200 when up, 503
+// when down.
+func (r *Registry) ReadinessHandler() http.HandlerFunc {
+	return func(ctx *http.Context) error {
+		return respond(ctx, r.Readiness(ctx.FiberCtx().UserContext()))
+	}
+}
+
+func respond(ctx *http.Context, report Report) error {
+	status := fiber.StatusOK
+	if !report.Healthy() {
+		status = fiber.StatusServiceUnavailable
+	}
+	ctx.Header(fiber.HeaderCacheControl, "no-store")
+	return ctx.Status(status).JSONResponse(report)
+}
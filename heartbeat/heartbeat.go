@@ -0,0 +1,188 @@
+// Package heartbeat pings external monitoring services (healthchecks.io,
+// Cronitor, or any custom URL) when scheduled tasks and workers run, so
+// silent failures are noticed.
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Monitor pings configured URLs when a task starts, succeeds or fails.
+// Empty URLs are skipped.
+type Monitor struct {
+	// StartURL is pinged before the task runs.
+	StartURL string
+
+	// SuccessURL is pinged after the task completes without error.
+	SuccessURL string
+
+	// FailureURL is pinged after the task returns an error.
+	FailureURL string
+
+	// Client is the HTTP client used for pings. Defaults to a client with a 10s timeout.
+	Client *http.Client
+}
+
+// URL creates a monitor that pings custom success and failure URLs.
+func URL(success, failure string) *Monitor {
+	return &Monitor{
+		SuccessURL: success,
+		FailureURL: failure,
+	}
+}
+
+// Healthchecks creates a monitor for a healthchecks.io check.
+// The check argument is either the check UUID or a full ping URL
+// (for self-hosted instances).
+func Healthchecks(check string) *Monitor {
+	base := check
+	if !strings.Contains(check, "://") {
+		base = "https://hc-ping.com/" + check
+	}
+	base = strings.TrimRight(base, "/")
+
+	return &Monitor{
+		StartURL:   base + "/start",
+		SuccessURL: base,
+		FailureURL: base + "/fail",
+	}
+}
+
+// Cronitor creates a monitor for a Cronitor telemetry ping.
+func Cronitor(apiKey, monitorKey string) *Monitor {
+	base := fmt.Sprintf("https://cronitor.link/p/%s/%s", url.PathEscape(apiKey), url.PathEscape(monitorKey))
+
+	return &Monitor{
+		StartURL:   base + "?state=run",
+		SuccessURL: base + "?state=complete",
+		FailureURL: base + "?state=fail",
+	}
+}
+
+// FromConfig creates a monitor from a configuration map.
+//
+//	driver: healthchecks | cronitor | url
+//	check: <uuid or ping url>          # healthchecks
+//	api_key: <key>                     # cronitor
+//	monitor: <key>                     # cronitor
+//	start_url / success_url / failure_url  # url
+//
+// It returns nil if the map is empty.
+func FromConfig(cfg map[string]any) (*Monitor, error) {
+	if len(cfg) == 0 {
+		return nil, nil
+	}
+
+	get := func(key string) string {
+		if v, ok := cfg[key].(string); ok {
+			return v
+		}
+		return ""
+	}
+
+	var monitor *Monitor
+	switch driver := get("driver"); driver {
+	case "healthchecks":
+		if get("check") == "" {
+			return nil, fmt.Errorf("heartbeat: healthchecks driver requires a check")
+		}
+		monitor = Healthchecks(get("check"))
+	case "cronitor":
+		if get("api_key") == "" || get("monitor") == "" {
+			return nil, fmt.Errorf("heartbeat: cronitor driver requires api_key and monitor")
+		}
+		monitor = Cronitor(get("api_key"), get("monitor"))
+	case "", "url":
+		monitor = &Monitor{
+			StartURL:   get("start_url"),
+			SuccessURL: get("success_url"),
+			FailureURL: get("failure_url"),
+		}
+	default:
+		return nil, fmt.Errorf("heartbeat: unsupported driver [%s]", driver)
+	}
+
+	return monitor, nil
+}
+
+// Start pings the start URL.
+func (m *Monitor) Start(ctx context.Context) error {
+	return m.ping(ctx, m.StartURL, "")
+}
+
+// Success pings the success URL.
+func (m *Monitor) Success(ctx context.Context) error {
+	return m.ping(ctx, m.SuccessURL, "")
+}
+
+// Failure pings the failure URL, sending the error message as the request body.
+func (m *Monitor) Failure(ctx context.Context, cause error) error {
+	body := ""
+	if cause != nil {
+		body = cause.Error()
+	}
+	return m.ping(ctx, m.FailureURL, body)
+}
+
+// Wrap returns a task that reports its start and outcome to the monitor.
+// Ping errors never affect the task result.
+func (m *Monitor) Wrap(task func() error) func() error {
+	return func() error {
+		ctx := context.Background()
+		_ = m.Start(ctx)
+
+		err := task()
+		if err != nil {
+			_ = m.Failure(ctx, err)
+		} else {
+			_ = m.Success(ctx)
+		}
+		return err
+	}
+}
+
+// ping sends a single request to target. A body switches the request to POST.
+func (m *Monitor) ping(ctx context.Context, target, body string) error {
+	if m == nil || target == "" {
+		return nil
+	}
+
+	method := http.MethodGet
+	var reader io.Reader
+	if body != "" {
+		method = http.MethodPost
+		reader = strings.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return fmt.Errorf("heartbeat: %w", err)
+	}
+
+	resp, err := m.client().Do(req)
+	if err != nil {
+		return fmt.Errorf("heartbeat: ping failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("heartbeat: ping to %s returned status %d", target, resp.StatusCode)
+	}
+	return nil
+}
+
+func (m *Monitor) client() *http.Client {
+	if m.Client != nil {
+		return m.Client
+	}
+	return defaultClient
+}
+
+var defaultClient = &http.Client{Timeout: 10 * time.Second}
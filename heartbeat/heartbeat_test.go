@@ -0,0 +1,103 @@
+package heartbeat
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	mu       sync.Mutex
+	requests []string
+	bodies   []string
+}
+
+func (r *recorder) server(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.requests = append(r.requests, req.Method+" "+req.URL.RequestURI())
+		r.bodies = append(r.bodies, string(body))
+		r.mu.Unlock()
+		if req.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestHealthchecksURLs(t *testing.T) {
+	m := Healthchecks("abc-123")
+	assert.Equal(t, "https://hc-ping.com/abc-123/start", m.StartURL)
+	assert.Equal(t, "https://hc-ping.com/abc-123", m.SuccessURL)
+	assert.Equal(t, "https://hc-ping.com/abc-123/fail", m.FailureURL)
+
+	self := Healthchecks("https://hc.example.com/ping/abc/")
+	assert.Equal(t, "https://hc.example.com/ping/abc/fail", self.FailureURL)
+}
+
+func TestCronitorURLs(t *testing.T) {
+	m := Cronitor("key", "nightly-backup")
+	assert.Equal(t, "https://cronitor.link/p/key/nightly-backup?state=run", m.StartURL)
+	assert.Equal(t, "https://cronitor.link/p/key/nightly-backup?state=complete", m.SuccessURL)
+	assert.Equal(t, "https://cronitor.link/p/key/nightly-backup?state=fail", m.FailureURL)
+}
+
+func TestFromConfig(t *testing.T) {
+	m, err := FromConfig(nil)
+	require.NoError(t, err)
+	assert.Nil(t, m)
+
+	m, err = FromConfig(map[string]any{"driver": "healthchecks", "check": "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://hc-ping.com/abc", m.SuccessURL)
+
+	m, err = FromConfig(map[string]any{"success_url": "http://example.com/ok"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://example.com/ok", m.SuccessURL)
+	assert.Empty(t, m.FailureURL)
+
+	_, err = FromConfig(map[string]any{"driver": "cronitor"})
+	assert.Error(t, err)
+
+	_, err = FromConfig(map[string]any{"driver": "pagerduty"})
+	assert.Error(t, err)
+}
+
+func TestWrapPingsOnSuccess(t *testing.T) {
+	rec := &recorder{}
+	srv := rec.server(t)
+	m := &Monitor{StartURL: srv.URL + "/start", SuccessURL: srv.URL + "/ok", FailureURL: srv.URL + "/fail"}
+
+	err := m.Wrap(func() error { return nil })()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET /start", "GET /ok"}, rec.requests)
+}
+
+func TestWrapPingsOnFailure(t *testing.T) {
+	rec := &recorder{}
+	srv := rec.server(t)
+	m := &Monitor{SuccessURL: srv.URL + "/ok", FailureURL: srv.URL + "/fail"}
+
+	taskErr := errors.New("disk full")
+	err := m.Wrap(func() error { return taskErr })()
+	assert.Equal(t, taskErr, err)
+	assert.Equal(t, []string{"POST /fail"}, rec.requests)
+	assert.Equal(t, "disk full", rec.bodies[0])
+}
+
+func TestPingReportsHTTPErrors(t *testing.T) {
+	rec := &recorder{}
+	srv := rec.server(t)
+	m := URL(srv.URL+"/broken", "")
+
+	assert.Error(t, m.Success(t.Context()))
+	assert.NoError(t, m.Failure(t.Context(), errors.New("ignored: no failure url")))
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"errors"
+	"mime/multipart"
+	"reflect"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	fileHeaderType   = reflect.TypeOf((*multipart.FileHeader)(nil))
+	uploadedFileType = reflect.TypeOf((*contracts.UploadedFile)(nil)).Elem()
+)
+
+// Bind binds the query string and the request body into v, a pointer to a
+// struct. The body is decoded by Content-Type: JSON and XML use the `json`
+// and `xml` tags, urlencoded and multipart forms the `form` tag, and the
+// query string the `query` tag. Forms and query strings bind nested structs
+// as "address.city" and slices as repeated keys or "items[0].name". Body
+// fields win over query fields.
+//
+// In multipart requests, fields of type *multipart.FileHeader,
+// contracts.UploadedFile, or slices of them, receive the uploaded files
+// named by their `form` tag.
+//
+// Malformed input is reported as 400, and bodies of other types as 415.
+func (c *Context) Bind(v any) error {
+	fc := c.fiberCtx
+	if len(fc.Request().URI().QueryString()) > 0 {
+		if err := fc.QueryParser(v); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Malformed query string: "+err.Error())
+		}
+	}
+	if len(fc.Body()) == 0 {
+		return nil
+	}
+
+	if err := fc.BodyParser(v); err != nil {
+		if errors.Is(err, fiber.ErrUnprocessableEntity) {
+			return fiber.NewError(fiber.StatusUnsupportedMediaType, "Unsupported content type: "+string(fc.Request().Header.ContentType()))
+		}
+		return fiber.NewError(fiber.StatusBadRequest, "Malformed request body: "+err.Error())
+	}
+
+	if strings.HasPrefix(strings.ToLower(string(fc.Request().Header.ContentType())), fiber.MIMEMultipartForm) {
+		form, err := fc.MultipartForm()
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Malformed request body: "+err.Error())
+		}
+		c.bindFiles(reflect.ValueOf(v), form.File, "")
+	}
+	return nil
+}
+
+// bindFiles sets file fields of the struct v points to from uploaded files.
+// Files of nested structs are named with the struct's prefix, as in
+// "profile.avatar".
+func (c *Context) bindFiles(v reflect.Value, files map[string][]*multipart.FileHeader, prefix string) {
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	v = v.Elem()
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		name = prefix + name
+		headers := files[name]
+
+		value := v.Field(i)
+		switch {
+		case field.Type == fileHeaderType && len(headers) > 0:
+			value.Set(reflect.ValueOf(headers[0]))
+		case field.Type == reflect.SliceOf(fileHeaderType) && len(headers) > 0:
+			value.Set(reflect.ValueOf(headers))
+		case field.Type == uploadedFileType && len(headers) > 0:
+			value.Set(reflect.ValueOf(c.uploadedFile(headers[0])))
+		case field.Type == reflect.SliceOf(uploadedFileType) && len(headers) > 0:
+			uploads := make([]contracts.UploadedFile, len(headers))
+			for j, header := range headers {
+				uploads[j] = c.uploadedFile(header)
+			}
+			value.Set(reflect.ValueOf(uploads))
+		case field.Type.Kind() == reflect.Struct:
+			c.bindFiles(value.Addr(), files, name+".")
+		}
+	}
+}
+
+func (c *Context) uploadedFile(header *multipart.FileHeader) contracts.UploadedFile {
+	return NewUploadedFile(c.fiberCtx.UserContext(), header, c.app)
+}
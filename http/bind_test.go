@@ -0,0 +1,131 @@
+package http
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bindAddress struct {
+	City string `json:"city" xml:"city" form:"city" query:"city"`
+}
+
+type bindProfile struct {
+	Bio    string                `form:"bio"`
+	Avatar *multipart.FileHeader `form:"avatar"`
+}
+
+type bindDTO struct {
+	Name    string        `json:"name" xml:"name" form:"name" query:"name"`
+	Page    int           `json:"page" xml:"page" form:"page" query:"page"`
+	Tags    []string      `json:"tags" xml:"tags" form:"tags" query:"tags"`
+	Address bindAddress   `json:"address" xml:"address" form:"address" query:"address"`
+	Items   []bindAddress `json:"items" xml:"items" form:"items" query:"items"`
+
+	Photos  []contracts.UploadedFile `form:"photos"`
+	Profile bindProfile              `form:"profile"`
+}
+
+func bindRequest(t *testing.T, target, contentType string, body []byte) (bindDTO, error) {
+	t.Helper()
+	var dto bindDTO
+	var bindErr error
+
+	app := fiber.New()
+	app.All("/", func(c *fiber.Ctx) error {
+		bindErr = NewContext(c, &mockApplication{}).Bind(&dto)
+		return nil
+	})
+
+	req := httptest.NewRequest("POST", target, bytes.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	_, err := app.Test(req)
+	require.NoError(t, err)
+	return dto, bindErr
+}
+
+func TestBindJSON(t *testing.T) {
+	dto, err := bindRequest(t, "/?page=2&name=query", "application/json; charset=utf-8",
+		[]byte(`{"name":"Ada","tags":["a","b"],"address":{"city":"London"},"items":[{"city":"Paris"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", dto.Name, "body wins over query")
+	assert.Equal(t, 2, dto.Page)
+	assert.Equal(t, []string{"a", "b"}, dto.Tags)
+	assert.Equal(t, "London", dto.Address.City)
+	assert.Equal(t, []bindAddress{{City: "Paris"}}, dto.Items)
+}
+
+func TestBindXML(t *testing.T) {
+	dto, err := bindRequest(t, "/", "application/xml",
+		[]byte(`<bindDTO><name>Ada</name><tags>a</tags><tags>b</tags><address><city>London</city></address></bindDTO>`))
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", dto.Name)
+	assert.Equal(t, []string{"a", "b"}, dto.Tags)
+	assert.Equal(t, "London", dto.Address.City)
+}
+
+func TestBindForm(t *testing.T) {
+	dto, err := bindRequest(t, "/", "application/x-www-form-urlencoded",
+		[]byte("name=Ada&page=3&tags=a&tags=b&address.city=London&items[0].city=Paris&items[1].city=Rome"))
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", dto.Name)
+	assert.Equal(t, 3, dto.Page)
+	assert.Equal(t, []string{"a", "b"}, dto.Tags)
+	assert.Equal(t, "London", dto.Address.City)
+	assert.Equal(t, []bindAddress{{City: "Paris"}, {City: "Rome"}}, dto.Items)
+}
+
+func TestBindQuery(t *testing.T) {
+	dto, err := bindRequest(t, "/?name=Ada&tags=a&tags=b&address.city=London", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", dto.Name)
+	assert.Equal(t, []string{"a", "b"}, dto.Tags)
+	assert.Equal(t, "London", dto.Address.City)
+}
+
+func TestBindMultipart(t *testing.T) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("name", "Ada")
+	writer.WriteField("profile.bio", "Mathematician")
+	for _, name := range []string{"one.txt", "two.txt"} {
+		part, _ := writer.CreateFormFile("photos", name)
+		part.Write([]byte(name))
+	}
+	part, _ := writer.CreateFormFile("profile.avatar", "me.png")
+	part.Write([]byte("png"))
+	writer.Close()
+
+	dto, err := bindRequest(t, "/", writer.FormDataContentType(), body.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", dto.Name)
+	assert.Equal(t, "Mathematician", dto.Profile.Bio)
+	require.Len(t, dto.Photos, 2)
+	assert.Equal(t, "two.txt", dto.Photos[1].ClientName())
+	require.NotNil(t, dto.Profile.Avatar)
+	assert.Equal(t, "me.png", dto.Profile.Avatar.Filename)
+}
+
+func TestBindErrors(t *testing.T) {
+	_, err := bindRequest(t, "/", "application/json", []byte(`{"name":`))
+	var fiberErr *fiber.Error
+	require.ErrorAs(t, err, &fiberErr)
+	assert.Equal(t, fiber.StatusBadRequest, fiberErr.Code)
+
+	_, err = bindRequest(t, "/", "text/csv", []byte("a,b"))
+	require.ErrorAs(t, err, &fiberErr)
+	assert.Equal(t, fiber.StatusUnsupportedMediaType, fiberErr.Code)
+	assert.Contains(t, fiberErr.Message, "text/csv")
+
+	_, err = bindRequest(t, "/?page=abc", "", nil)
+	require.ErrorAs(t, err, &fiberErr)
+	assert.Equal(t, fiber.StatusBadRequest, fiberErr.Code)
+}
@@ -1,11 +1,17 @@
 package http
 
 import (
+	"errors"
+	"fmt"
 	"sync"
 
+	"github.com/genesysflow/go-genesys/auth"
 	"github.com/genesysflow/go-genesys/container"
 	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/lang"
+	"github.com/genesysflow/go-genesys/session"
 	"github.com/genesysflow/go-genesys/validation"
+	"github.com/genesysflow/go-genesys/view"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -86,29 +92,56 @@ func (c *Context) JSON(v any) error {
 	return c.request.JSON(v)
 }
 
-// Bind binds the request body to a struct (alias for JSON).
-func (c *Context) Bind(v any) error {
-	return c.fiberCtx.BodyParser(v)
-}
-
-// Validate validates the request data against the given rules.
+// Validate validates the request. Given a struct, it checks the struct's
+// `validate` tags; given a map[string]string of rules, it checks the request
+// input, e.g. ctx.Validate(map[string]string{"email": "required,email"}).
+// Failures are returned as *validation.ValidationErrors, which respond 422
+// with the field errors when returned from a handler.
 func (c *Context) Validate(v any) error {
 	validator, err := container.Resolve[*validation.Validator](c.app)
 	if err != nil {
-		return err
+		validator = validation.New()
 	}
 
-	result := validator.Validate(v)
+	if locale, ok := c.fiberCtx.Locals(LocaleKey).(string); ok && locale != "" {
+		validator = validator.InLocale(locale)
+	}
+
+	var result *validation.ValidationResult
+	if rules, ok := v.(map[string]string); ok {
+		result = validator.ValidateMap(c.All(), rules)
+	} else {
+		result = validator.Validate(v)
+	}
 	if result.Fails() {
 		return result.Errors()
 	}
+	if data := result.Validated(); data != nil {
+		c.Set(validatedKey, data)
+	}
 	return nil
 }
 
-// Get retrieves a value from the context store.
+// validatedKey is the context key Validate stores the validated input
+// under.
+const validatedKey = "genesys.validated"
+
+// Validated returns the input the last successful Validate with a map of
+// rules checked: only the fields its rules cover, coerced to the types
+// they expect, so it can be mass-assigned safely. It is nil before then.
+func (c *Context) Validated() map[string]any {
+	data, _ := c.Get(validatedKey).(map[string]any)
+	return data
+}
+
+// Get retrieves a value from the context store, falling back to the
+// request's Fiber locals, which outlive the context, e.g. into the error
+// handler.
 func (c *Context) Get(key string) any {
-	value, _ := c.store.Load(key)
-	return value
+	if value, ok := c.store.Load(key); ok {
+		return value
+	}
+	return c.fiberCtx.Locals(key)
 }
 
 // Set stores a value in the context store.
@@ -116,6 +149,141 @@ func (c *Context) Set(key string, value any) {
 	c.store.Store(key, value)
 }
 
+// Context keys the RequestID middleware stores the request's ID and its
+// request-scoped logger under.
+const (
+	RequestIDKey = "request_id"
+	LoggerKey    = "logger"
+)
+
+// RequestID returns the request's ID, or "" when the RequestID middleware
+// didn't run.
+func (c *Context) RequestID() string {
+	id, _ := c.Get(RequestIDKey).(string)
+	return id
+}
+
+// Logger returns the request's logger. After the RequestID middleware, its
+// entries include the request's ID; otherwise it is the application logger.
+func (c *Context) Logger() contracts.Logger {
+	if logger, ok := c.Get(LoggerKey).(contracts.Logger); ok {
+		return logger
+	}
+	if c.app == nil {
+		return nil
+	}
+	return c.app.GetLogger()
+}
+
+// CsrfTokenKey is the request local the CSRF middleware stores the token under.
+const CsrfTokenKey = "csrf_token"
+
+// CsrfToken returns the request's CSRF token, for embedding in forms as the
+// "_token" field. It is empty unless the CSRF middleware ran.
+func (c *Context) CsrfToken() string {
+	token, _ := c.fiberCtx.Locals(CsrfTokenKey).(string)
+	return token
+}
+
+// LocaleKey is the request local the Locale middleware stores the request's
+// locale under.
+const LocaleKey = "locale"
+
+// Locale returns the request's locale: the one the Locale middleware chose,
+// or the translator's default locale. It is empty when neither is set.
+func (c *Context) Locale() string {
+	if locale, ok := c.fiberCtx.Locals(LocaleKey).(string); ok && locale != "" {
+		return locale
+	}
+	if translator := c.translator(); translator != nil {
+		return translator.Locale()
+	}
+	return ""
+}
+
+// Trans translates key into the request's locale, replacing :placeholders
+// with params. The key is returned as is when it has no translation or no
+// translator is registered.
+func (c *Context) Trans(key string, params ...map[string]any) string {
+	translator := c.translator()
+	if translator == nil {
+		return key
+	}
+	return translator.TransIn(c.Locale(), key, firstParams(params))
+}
+
+// TransChoice translates key into the request's locale, picking the plural
+// form for count.
+func (c *Context) TransChoice(key string, count int, params ...map[string]any) string {
+	translator := c.translator()
+	if translator == nil {
+		return key
+	}
+	return translator.ChoiceIn(c.Locale(), key, count, firstParams(params))
+}
+
+// translator returns the app's translator, or nil when none is registered.
+func (c *Context) translator() *lang.Translator {
+	if c.app == nil {
+		return nil
+	}
+	translator, err := container.Resolve[*lang.Translator](c.app)
+	if err != nil {
+		return nil
+	}
+	return translator
+}
+
+func firstParams(params []map[string]any) map[string]any {
+	if len(params) > 0 {
+		return params[0]
+	}
+	return nil
+}
+
+// Session returns the request's session. It is nil unless the session
+// middleware ran.
+func (c *Context) Session() contracts.Session {
+	if sess := session.GetFromContext(c.fiberCtx); sess != nil {
+		return sess
+	}
+	return nil
+}
+
+// Auth returns a guard for the request: the named guard, or the default
+// one. It panics if the guard is not configured or the AuthServiceProvider
+// is not registered.
+func (c *Context) Auth(guard ...string) contracts.Guard {
+	g, err := c.guard(guard...)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// User returns the authenticated user, or nil for a guest. It is the user
+// of the default guard, which the auth middleware switches to the guard
+// that authenticated the request.
+func (c *Context) User() contracts.Authenticatable {
+	if g, err := c.guard(); err == nil {
+		user, _ := g.User()
+		return user
+	}
+	user, _ := c.Get("user").(contracts.Authenticatable)
+	return user
+}
+
+func (c *Context) guard(name ...string) (contracts.Guard, error) {
+	if c.app == nil {
+		return nil, errors.New("http: no application to resolve the auth manager from")
+	}
+	manager, err := container.Resolve[*auth.Manager](c.app)
+	if err != nil {
+		return nil, err
+	}
+	return manager.Guard(c.fiberCtx, name...)
+}
+
 // SetNext sets the next handler function for middleware.
 func (c *Context) SetNext(next func() error) {
 	c.next = next
@@ -179,9 +347,10 @@ func (c *Context) String(s string) error {
 	return c.fiberCtx.SendString(s)
 }
 
-// JSONResponse sends a JSON response.
+// JSONResponse sends a JSON response, encoded with the kernel's JSON codec.
+// Transformer values are sent as their ToMap.
 func (c *Context) JSONResponse(v any) error {
-	return c.fiberCtx.JSON(v)
+	return writeJSON(c.fiberCtx, c.transform(v))
 }
 
 // HTML sends an HTML response.
@@ -190,17 +359,83 @@ func (c *Context) HTML(html string) error {
 	return c.fiberCtx.SendString(html)
 }
 
-// File sends a file response.
-func (c *Context) File(path string) error {
+// View renders a view with the app's view engine and sends it as HTML.
+// Map data is merged with the engine's shared data and the request's
+// csrf_token, when the CSRF middleware set one.
+func (c *Context) View(name string, data ...any) error {
+	engine, err := container.Resolve[*view.Engine](c.app)
+	if err != nil {
+		return fmt.Errorf("view engine not available: %w", err)
+	}
+
+	var value any
+	if len(data) > 0 {
+		value = data[0]
+	}
+	if token := c.CsrfToken(); token != "" {
+		switch values := value.(type) {
+		case nil:
+			value = view.Data{CsrfTokenKey: token}
+		case view.Data:
+			value = withDefault(values, CsrfTokenKey, token)
+		case map[string]any:
+			value = withDefault(view.Data(values), CsrfTokenKey, token)
+		}
+	}
+
+	html, err := engine.Render(name, value)
+	if err != nil {
+		return err
+	}
+	return c.HTML(html)
+}
+
+// withDefault returns a copy of data with key set, unless data already has it.
+func withDefault(data view.Data, key string, value any) view.Data {
+	if _, ok := data[key]; ok {
+		return data
+	}
+	copied := make(view.Data, len(data)+1)
+	for k, v := range data {
+		copied[k] = v
+	}
+	copied[key] = value
+	return copied
+}
+
+// SendFile sends a file response.
+func (c *Context) SendFile(path string) error {
 	return c.fiberCtx.SendFile(path)
 }
 
-// Download sends a file as download.
-func (c *Context) Download(path string, filename ...string) error {
-	if len(filename) > 0 {
-		return c.fiberCtx.Download(path, filename[0])
+// File returns the file uploaded under key.
+func (c *Context) File(key string) (contracts.UploadedFile, error) {
+	header, err := c.request.File(key)
+	if err != nil {
+		return nil, err
+	}
+	return c.uploadedFile(header), nil
+}
+
+// Files returns all files uploaded under key, such as "photos" for
+// <input type="file" name="photos" multiple>.
+func (c *Context) Files(key string) ([]contracts.UploadedFile, error) {
+	headers, err := c.request.Files(key)
+	if err != nil {
+		return nil, err
 	}
-	return c.fiberCtx.Download(path)
+
+	files := make([]contracts.UploadedFile, len(headers))
+	for i, header := range headers {
+		files[i] = c.uploadedFile(header)
+	}
+	return files, nil
+}
+
+// HasFile reports whether a file was uploaded under key.
+func (c *Context) HasFile(key string) bool {
+	files, err := c.request.Files(key)
+	return err == nil && len(files) > 0
 }
 
 // Redirect redirects to another URL.
@@ -230,14 +465,14 @@ func (c *Context) Send(body []byte) error {
 // Created sends a 201 Created response with JSON body.
 func (c *Context) Created(v any) error {
 	c.fiberCtx.Status(fiber.StatusCreated)
-	return c.fiberCtx.JSON(v)
+	return c.fiberCtx.JSON(c.transform(v))
 }
 
 // Accepted sends a 202 Accepted response.
 func (c *Context) Accepted(v ...any) error {
 	c.fiberCtx.Status(fiber.StatusAccepted)
 	if len(v) > 0 {
-		return c.fiberCtx.JSON(v[0])
+		return c.fiberCtx.JSON(c.transform(v[0]))
 	}
 	return nil
 }
@@ -1,10 +1,13 @@
 package http
 
 import (
+	"io"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/genesysflow/go-genesys/view"
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -271,3 +274,29 @@ func TestContextHTML(t *testing.T) {
 	resp, _ := app.Test(req)
 	assert.Contains(t, resp.Header.Get("Content-Type"), "text/html")
 }
+
+func TestContextView(t *testing.T) {
+	engine := view.NewEngine("", "")
+	engine.AddView("greeting", `<p>Hi {{ .Name }}</p><input name="_token" value="{{ .csrf_token }}">`)
+	mockApp := testutil.NewMockApplication()
+	require.NoError(t, mockApp.InstanceType(engine))
+
+	app := fiber.New()
+	app.Get("/hello", func(c *fiber.Ctx) error {
+		c.Locals(CsrfTokenKey, "token-1")
+		return NewContext(c, mockApp).View("greeting", view.Data{"Name": "Jane"})
+	})
+	app.Get("/missing", func(c *fiber.Ctx) error {
+		return NewContext(c, &mockApplication{}).View("greeting")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/hello", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, `<p>Hi Jane</p><input name="_token" value="token-1">`, string(body))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/missing", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+}
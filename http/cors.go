@@ -0,0 +1,218 @@
+package http
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CORSConfig configures cross-origin resource sharing.
+type CORSConfig struct {
+	// AllowedOrigins lists the origins allowed to make requests. "*" allows
+	// any origin and "https://*.example.com" allows its subdomains.
+	AllowedOrigins []string
+
+	// AllowedMethods lists the methods allowed in preflight requests.
+	AllowedMethods []string
+
+	// AllowedHeaders lists the request headers allowed in preflight
+	// requests. "*" allows whatever the client asks for.
+	AllowedHeaders []string
+
+	// ExposedHeaders lists the response headers readable by the client.
+	ExposedHeaders []string
+
+	// MaxAge is how long, in seconds, preflight results may be cached.
+	MaxAge int
+
+	// SupportsCredentials allows cookies and authorization headers. The
+	// origin is then echoed back instead of "*".
+	SupportsCredentials bool
+}
+
+// DefaultCORSConfig allows any origin to use the common methods and headers.
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization"},
+	}
+}
+
+// LoadCORSConfig reads the cors config file (config/cors.yaml). Missing
+// keys keep their DefaultCORSConfig values.
+func LoadCORSConfig(cfg contracts.Config) CORSConfig {
+	config := DefaultCORSConfig()
+	if cfg == nil {
+		return config
+	}
+
+	if origins := cfg.GetStringSlice("cors.allowed_origins"); origins != nil {
+		config.AllowedOrigins = origins
+	}
+	if methods := cfg.GetStringSlice("cors.allowed_methods"); methods != nil {
+		config.AllowedMethods = methods
+	}
+	if headers := cfg.GetStringSlice("cors.allowed_headers"); headers != nil {
+		config.AllowedHeaders = headers
+	}
+	config.ExposedHeaders = cfg.GetStringSlice("cors.exposed_headers")
+	config.MaxAge = cfg.GetInt("cors.max_age")
+	config.SupportsCredentials = cfg.GetBool("cors.supports_credentials")
+	return config
+}
+
+// CORS creates a CORS middleware. Without a config it uses the application's
+// cors config file. Preflight requests only reach route middleware for
+// routes that accept OPTIONS; use Route.CORS or Router.CORS to answer them
+// for every route.
+func CORS(config ...CORSConfig) MiddlewareFunc {
+	var cfg *CORSConfig
+	if len(config) > 0 {
+		cfg = &config[0]
+	}
+
+	var once sync.Once
+	return func(ctx *Context, next func() error) error {
+		once.Do(func() {
+			if cfg == nil {
+				loaded := LoadCORSConfig(appConfig(ctx.App()))
+				cfg = &loaded
+			}
+		})
+
+		if handleCORS(ctx.FiberCtx(), cfg) {
+			return nil
+		}
+		return next()
+	}
+}
+
+// CORS answers CORS preflight requests for this route and adds CORS
+// headers to its responses. Without a config it uses the cors config file.
+func (r *Route) CORS(config ...CORSConfig) *Route {
+	cfg := r.router.corsConfig(config)
+	r.middleware = append([]MiddlewareFunc{CORS(cfg)}, r.middleware...)
+	r.router.preflight(r.path, cfg)
+	return r
+}
+
+// CORS answers CORS preflight requests for every path under the router's
+// prefix and adds CORS headers to the responses of its routes.
+func (r *Router) CORS(config ...CORSConfig) {
+	cfg := r.corsConfig(config)
+	r.Use(CORS(cfg))
+	r.preflight(r.prefix+"/*", cfg)
+}
+
+func (r *Router) corsConfig(config []CORSConfig) CORSConfig {
+	if len(config) > 0 {
+		return config[0]
+	}
+	return LoadCORSConfig(appConfig(r.app))
+}
+
+// preflight registers an OPTIONS handler for path that answers preflight
+// requests before any middleware runs, so authentication doesn't reject
+// them. Other OPTIONS requests fall through to the routes.
+func (r *Router) preflight(path string, cfg CORSConfig) {
+	if r.registry.preflights == nil {
+		r.registry.preflights = make(map[string]bool)
+	}
+	if r.registry.preflights[path] {
+		return
+	}
+	r.registry.preflights[path] = true
+
+	r.fiber.Options(path, func(c *fiber.Ctx) error {
+		if isPreflight(c) && handleCORS(c, &cfg) {
+			return nil
+		}
+		return c.Next()
+	})
+}
+
+func appConfig(app contracts.Application) contracts.Config {
+	if app == nil {
+		return nil
+	}
+	return app.GetConfig()
+}
+
+func isPreflight(c *fiber.Ctx) bool {
+	return c.Method() == fiber.MethodOptions && c.Get(fiber.HeaderAccessControlRequestMethod) != ""
+}
+
+// handleCORS adds CORS headers for requests from allowed origins. It
+// reports whether the request was a preflight and has been answered.
+func handleCORS(c *fiber.Ctx, cfg *CORSConfig) bool {
+	origin := c.Get(fiber.HeaderOrigin)
+	if origin == "" {
+		return false
+	}
+	c.Vary(fiber.HeaderOrigin)
+
+	preflight := isPreflight(c)
+	if preflight {
+		c.Vary(fiber.HeaderAccessControlRequestMethod, fiber.HeaderAccessControlRequestHeaders)
+	}
+
+	anyOrigin, allowed := cfg.allowsOrigin(origin)
+	if allowed {
+		if anyOrigin && !cfg.SupportsCredentials {
+			c.Set(fiber.HeaderAccessControlAllowOrigin, "*")
+		} else {
+			c.Set(fiber.HeaderAccessControlAllowOrigin, origin)
+		}
+		if cfg.SupportsCredentials {
+			c.Set(fiber.HeaderAccessControlAllowCredentials, "true")
+		}
+	}
+
+	if !preflight {
+		if allowed && len(cfg.ExposedHeaders) > 0 {
+			c.Set(fiber.HeaderAccessControlExposeHeaders, strings.Join(cfg.ExposedHeaders, ", "))
+		}
+		return false
+	}
+
+	if allowed {
+		c.Set(fiber.HeaderAccessControlAllowMethods, strings.Join(cfg.AllowedMethods, ", "))
+		if slices.Contains(cfg.AllowedHeaders, "*") {
+			if requested := c.Get(fiber.HeaderAccessControlRequestHeaders); requested != "" {
+				c.Set(fiber.HeaderAccessControlAllowHeaders, requested)
+			}
+		} else if len(cfg.AllowedHeaders) > 0 {
+			c.Set(fiber.HeaderAccessControlAllowHeaders, strings.Join(cfg.AllowedHeaders, ", "))
+		}
+		if cfg.MaxAge > 0 {
+			c.Set(fiber.HeaderAccessControlMaxAge, strconv.Itoa(cfg.MaxAge))
+		}
+	}
+	c.Status(fiber.StatusNoContent)
+	return true
+}
+
+// allowsOrigin reports whether origin is allowed, and whether that is
+// because any origin is.
+func (cfg *CORSConfig) allowsOrigin(origin string) (anyOrigin, allowed bool) {
+	for _, allowedOrigin := range cfg.AllowedOrigins {
+		if allowedOrigin == "*" {
+			return true, true
+		}
+		if strings.EqualFold(allowedOrigin, origin) {
+			return false, true
+		}
+		if prefix, suffix, ok := strings.Cut(allowedOrigin, "*"); ok &&
+			len(origin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(strings.ToLower(origin), strings.ToLower(prefix)) &&
+			strings.HasSuffix(strings.ToLower(origin), strings.ToLower(suffix)) {
+			return false, true
+		}
+	}
+	return false, false
+}
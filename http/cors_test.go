@@ -0,0 +1,128 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func corsRequest(t *testing.T, app *fiber.App, method, path, origin string, headers ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Origin", origin)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	rec.Code = resp.StatusCode
+	for key, values := range resp.Header {
+		rec.Header()[key] = values
+	}
+	return rec
+}
+
+func TestRouteCORSAnswersPreflight(t *testing.T) {
+	app := newTestApp()
+	router := NewRouter(&mockApplication{}, app)
+
+	denied := func(ctx *Context, next func() error) error {
+		return ctx.Unauthorized()
+	}
+	router.POST("/orders", func(ctx *Context) error {
+		return ctx.String("created")
+	}, denied).CORS(CORSConfig{
+		AllowedOrigins:      []string{"https://*.example.com"},
+		AllowedMethods:      []string{"POST"},
+		AllowedHeaders:      []string{"*"},
+		ExposedHeaders:      []string{"X-Request-Id"},
+		MaxAge:              600,
+		SupportsCredentials: true,
+	})
+
+	// Preflight is answered before the route's middleware runs.
+	rec := corsRequest(t, app, "OPTIONS", "/orders", "https://shop.example.com",
+		"Access-Control-Request-Method", "POST",
+		"Access-Control-Request-Headers", "X-Token")
+	assert.Equal(t, fiber.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://shop.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "POST", rec.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "X-Token", rec.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+	assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+
+	// Actual requests get CORS headers even when middleware rejects them.
+	rec = corsRequest(t, app, "POST", "/orders", "https://shop.example.com")
+	assert.Equal(t, fiber.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "https://shop.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Request-Id", rec.Header().Get("Access-Control-Expose-Headers"))
+	assert.Contains(t, rec.Header().Get("Vary"), "Origin")
+
+	// Other origins get no CORS headers.
+	rec = corsRequest(t, app, "OPTIONS", "/orders", "https://example.org",
+		"Access-Control-Request-Method", "POST")
+	assert.Equal(t, fiber.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Methods"))
+}
+
+func TestRouterCORSFromConfig(t *testing.T) {
+	cfg := testutil.NewMockConfig(map[string]any{
+		"cors.allowed_origins": []string{"https://app.example.com"},
+		"cors.allowed_methods": []string{"GET", "DELETE"},
+	})
+	app := newTestApp()
+	router := NewRouter(testutil.NewMockApplicationWithConfig(cfg), app)
+	router.Group("/api", func(api *Router) {
+		api.CORS()
+		api.GET("/users", func(ctx *Context) error { return ctx.String("users") })
+	})
+	router.GET("/public", func(ctx *Context) error { return ctx.String("public") })
+
+	rec := corsRequest(t, app, "OPTIONS", "/api/users", "https://app.example.com",
+		"Access-Control-Request-Method", "DELETE")
+	assert.Equal(t, fiber.StatusNoContent, rec.Code)
+	assert.Equal(t, "GET, DELETE", rec.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Origin, Content-Type, Accept, Authorization", rec.Header().Get("Access-Control-Allow-Headers"))
+
+	rec = corsRequest(t, app, "GET", "/api/users", "https://app.example.com")
+	assert.Equal(t, fiber.StatusOK, rec.Code)
+	assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+
+	// Routes outside the group are unaffected.
+	rec = corsRequest(t, app, "GET", "/public", "https://app.example.com")
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORSMiddlewareAnyOrigin(t *testing.T) {
+	app := newTestApp()
+	router := NewRouter(&mockApplication{}, app)
+	router.Use(CORS())
+	router.GET("/ping", func(ctx *Context) error { return ctx.String("pong") })
+
+	rec := corsRequest(t, app, "GET", "/ping", "https://anywhere.test")
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestCORSAllowsOrigin(t *testing.T) {
+	cfg := CORSConfig{AllowedOrigins: []string{"https://example.com", "https://*.example.com"}}
+	for origin, want := range map[string]bool{
+		"https://example.com":          true,
+		"https://EXAMPLE.com":          true,
+		"https://a.example.com":        true,
+		"https://.example.com":         false,
+		"https://example.com.evil.com": false,
+		"http://a.example.com":         false,
+		"https://evilexample.com":      false,
+	} {
+		_, allowed := cfg.allowsOrigin(origin)
+		assert.Equal(t, want, allowed, origin)
+	}
+}
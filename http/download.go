@@ -0,0 +1,151 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	pathpkg "path"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/facades/storage"
+	"github.com/genesysflow/go-genesys/filesystem"
+	"github.com/gofiber/fiber/v2"
+)
+
+// DiskResponse sends files stored on a filesystem disk.
+type DiskResponse struct {
+	ctx  *Context
+	disk string
+}
+
+// FromDisk returns a responder for files on the named disk. An empty name
+// uses the default disk.
+func (c *Context) FromDisk(disk string) *DiskResponse {
+	return &DiskResponse{ctx: c, disk: disk}
+}
+
+// Download streams a file from the default disk as an attachment. The
+// download name defaults to the file's base name.
+func (c *Context) Download(path string, name ...string) error {
+	return c.FromDisk("").Download(path, name...)
+}
+
+// FileResponse streams a file from the default disk for display in the browser.
+func (c *Context) FileResponse(path string) error {
+	return c.FromDisk("").FileResponse(path)
+}
+
+// Stream sends reader as the response body with the given content type.
+// The body is sent chunked; reader is closed afterwards if it is an io.Closer.
+func (c *Context) Stream(reader io.Reader, contentType string) error {
+	c.fiberCtx.Set(fiber.HeaderContentType, contentType)
+	return c.fiberCtx.SendStream(reader)
+}
+
+// Download streams a file as an attachment. The download name defaults to
+// the file's base name.
+func (d *DiskResponse) Download(path string, name ...string) error {
+	filename := pathpkg.Base(path)
+	if len(name) > 0 && name[0] != "" {
+		filename = name[0]
+	}
+	return d.send(path, "attachment", filename)
+}
+
+// FileResponse streams a file for display in the browser.
+func (d *DiskResponse) FileResponse(path string) error {
+	return d.send(path, "inline", pathpkg.Base(path))
+}
+
+// send streams a file with its content type, length and modification time.
+// Missing files are reported as 404 errors.
+func (d *DiskResponse) send(path, disposition, filename string) error {
+	fs, err := resolveDisk(d.ctx.app, d.disk)
+	if err != nil {
+		return err
+	}
+
+	ctx := d.ctx.fiberCtx.UserContext()
+	meta, err := fs.Metadata(ctx, path)
+	if err != nil {
+		if filesystem.IsNotExist(err) {
+			return fiber.NewError(fiber.StatusNotFound, "File not found")
+		}
+		return err
+	}
+	contentType := meta.ContentType
+	if contentType == "" {
+		contentType = fiber.MIMEOctetStream
+	}
+
+	// Serve a pre-compressed sibling when the client accepts one. It keeps
+	// the original's content type and download name.
+	c := d.ctx.fiberCtx
+	c.Vary(fiber.HeaderAcceptEncoding)
+	encoding, ext := negotiatePrecompressed(c.Get(fiber.HeaderAcceptEncoding), func(ext string) bool {
+		compressed, err := fs.Metadata(ctx, path+ext)
+		if err != nil {
+			return false
+		}
+		meta = compressed
+		return true
+	})
+	if encoding != "" {
+		path += ext
+		c.Set(fiber.HeaderContentEncoding, encoding)
+	}
+
+	// Answer conditional requests from the metadata, without reading.
+	if meta.ETag != "" && d.ctx.IfNoneMatch(meta.ETag) {
+		return nil
+	}
+	if !meta.LastModified.IsZero() && d.ctx.IfModifiedSince(meta.LastModified) {
+		return nil
+	}
+
+	reader, err := fs.ReadStream(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, contentDisposition(disposition, filename))
+	return c.SendStream(reader, int(meta.Size))
+}
+
+// contentDisposition formats a Content-Disposition header. Non-ASCII names
+// are encoded per RFC 2231 so browsers keep them intact.
+func contentDisposition(disposition, filename string) string {
+	if value := mime.FormatMediaType(disposition, map[string]string{"filename": filename}); value != "" {
+		return value
+	}
+	return disposition
+}
+
+// resolveDisk resolves a disk by name from the storage facade, or from the
+// application's "filesystem" service. Unknown disks are reported as errors
+// rather than panics.
+func resolveDisk(app contracts.Application, name string) (fs contracts.Filesystem, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("http: failed to resolve disk %q: %v", name, r)
+		}
+	}()
+
+	names := []string{}
+	if name != "" {
+		names = append(names, name)
+	}
+
+	if disk := storage.Disk(names...); disk != nil {
+		return disk, nil
+	}
+	if app != nil {
+		if service, err := app.Make("filesystem"); err == nil {
+			if factory, ok := service.(contracts.FilesystemFactory); ok {
+				return factory.Disk(names...), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("http: no filesystem is configured")
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/facades/storage"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// get sends a GET request to a handler receiving the Context.
+func get(t *testing.T, handler func(ctx *Context) error) (int, map[string]string, string) {
+	t.Helper()
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return handler(NewContext(c, &mockApplication{}))
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	headers := map[string]string{}
+	for key := range resp.Header {
+		headers[key] = resp.Header.Get(key)
+	}
+	return resp.StatusCode, headers, string(body)
+}
+
+func TestContextDownload(t *testing.T) {
+	disk := storage.Fake()
+	defer storage.Restore()
+	require.NoError(t, disk.Put(context.Background(), "reports/q3.csv", "a,b\n1,2\n"))
+
+	status, headers, body := get(t, func(c *Context) error {
+		return c.Download("reports/q3.csv")
+	})
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "a,b\n1,2\n", body)
+	assert.Equal(t, `attachment; filename=q3.csv`, headers["Content-Disposition"])
+	assert.Equal(t, "8", headers["Content-Length"])
+	assert.NotEmpty(t, headers["Last-Modified"])
+
+	_, headers, _ = get(t, func(c *Context) error {
+		return c.Download("reports/q3.csv", "Résumé 2024.csv")
+	})
+	assert.Equal(t, `attachment; filename*=utf-8''R%C3%A9sum%C3%A9%202024.csv`, headers["Content-Disposition"])
+}
+
+func TestContextFileResponse(t *testing.T) {
+	disk := storage.Fake("s3")
+	defer storage.Restore()
+	require.NoError(t, disk.PutBytes(context.Background(), "avatars/1.png", pngHeader))
+
+	status, headers, body := get(t, func(c *Context) error {
+		return c.FromDisk("s3").FileResponse("avatars/1.png")
+	})
+	assert.Equal(t, 200, status)
+	assert.Equal(t, string(pngHeader), body)
+	assert.Equal(t, "image/png", headers["Content-Type"])
+	assert.Equal(t, "inline; filename=1.png", headers["Content-Disposition"])
+
+	status, _, _ = get(t, func(c *Context) error {
+		return c.FromDisk("s3").FileResponse("avatars/missing.png")
+	})
+	assert.Equal(t, 404, status)
+}
+
+func TestContextStream(t *testing.T) {
+	status, headers, body := get(t, func(c *Context) error {
+		return c.Stream(strings.NewReader("line 1\nline 2\n"), "text/plain; charset=utf-8")
+	})
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "text/plain; charset=utf-8", headers["Content-Type"])
+	assert.Equal(t, "line 1\nline 2\n", body)
+}
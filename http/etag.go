@@ -0,0 +1,163 @@
+package http
+
+import (
+	"fmt"
+	"hash/fnv"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ETagConfig configures the ETag middleware.
+type ETagConfig struct {
+	// Weak marks generated tags as weak (W/"..."), for responses that are
+	// equivalent but not necessarily byte-identical, such as compressed ones.
+	Weak bool
+}
+
+// ETag creates a middleware that tags successful GET and HEAD responses
+// with an ETag computed from the body, and answers 304 Not Modified when
+// the request's If-None-Match matches it. Tags set by the handler are kept
+// and checked the same way. Streamed bodies, such as files, are left alone.
+//
+// Enable it for all routes with KernelConfig.ETag or the http.etag config
+// value.
+func ETag(config ...ETagConfig) MiddlewareFunc {
+	cfg := ETagConfig{}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	return func(ctx *Context, next func() error) error {
+		c := ctx.fiberCtx
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return next()
+		}
+		if err := next(); err != nil {
+			return err
+		}
+
+		response := c.Response()
+		if response.StatusCode() != fiber.StatusOK || response.IsBodyStream() {
+			return nil
+		}
+		etag := string(response.Header.Peek(fiber.HeaderETag))
+		if etag == "" {
+			body := response.Body()
+			if len(body) == 0 {
+				return nil
+			}
+			etag = GenerateETag(body, cfg.Weak)
+		}
+		ctx.IfNoneMatch(etag)
+		return nil
+	}
+}
+
+// GenerateETag returns a quoted ETag for body, weak if asked.
+func GenerateETag(body []byte, weak bool) string {
+	h := fnv.New64a()
+	h.Write(body)
+	etag := fmt.Sprintf(`"%x-%x"`, len(body), h.Sum64())
+	if weak {
+		return "W/" + etag
+	}
+	return etag
+}
+
+// SetETag sets the response's ETag header. Unquoted tags are quoted.
+func (c *Context) SetETag(etag string) contracts.Context {
+	c.fiberCtx.Set(fiber.HeaderETag, quoteETag(etag))
+	return c
+}
+
+// SetLastModified sets the response's Last-Modified header.
+func (c *Context) SetLastModified(modified time.Time) contracts.Context {
+	c.fiberCtx.Set(fiber.HeaderLastModified, modified.UTC().Format(nethttp.TimeFormat))
+	return c
+}
+
+// IfNoneMatch sets the response's ETag and reports whether the request's
+// If-None-Match already names it. If it does, the response becomes an
+// empty 304 Not Modified and the handler can return without rendering:
+//
+//	if ctx.IfNoneMatch(post.Version()) {
+//		return nil
+//	}
+//	return ctx.JSONResponse(post)
+//
+// Only GET and HEAD requests are answered with 304.
+func (c *Context) IfNoneMatch(etag string) bool {
+	etag = quoteETag(etag)
+	c.fiberCtx.Set(fiber.HeaderETag, etag)
+	if !isConditionalMethod(c.fiberCtx.Method()) || !etagMatches(c.fiberCtx.Get(fiber.HeaderIfNoneMatch), etag) {
+		return false
+	}
+	c.notModified()
+	return true
+}
+
+// IfModifiedSince sets the response's Last-Modified and reports whether the
+// request's If-Modified-Since is no older than modified. If so, the
+// response becomes an empty 304 Not Modified, as with IfNoneMatch. Requests
+// with If-None-Match are left to IfNoneMatch, which takes precedence.
+func (c *Context) IfModifiedSince(modified time.Time) bool {
+	c.SetLastModified(modified)
+	if !isConditionalMethod(c.fiberCtx.Method()) || c.fiberCtx.Get(fiber.HeaderIfNoneMatch) != "" {
+		return false
+	}
+	since, err := nethttp.ParseTime(c.fiberCtx.Get(fiber.HeaderIfModifiedSince))
+	if err != nil || modified.Truncate(time.Second).After(since) {
+		return false
+	}
+	c.notModified()
+	return true
+}
+
+// NotModified sends an empty 304 Not Modified response.
+func (c *Context) NotModified() error {
+	c.notModified()
+	return nil
+}
+
+func (c *Context) notModified() {
+	c.fiberCtx.Status(fiber.StatusNotModified)
+	c.fiberCtx.Response().ResetBody()
+	c.fiberCtx.Response().Header.Del(fiber.HeaderContentLength)
+}
+
+func isConditionalMethod(method string) bool {
+	return method == fiber.MethodGet || method == fiber.MethodHead
+}
+
+// quoteETag quotes an opaque tag, keeping a weak prefix.
+func quoteETag(etag string) string {
+	weak := strings.HasPrefix(etag, "W/")
+	etag = strings.TrimPrefix(etag, "W/")
+	if !strings.HasPrefix(etag, `"`) {
+		etag = `"` + etag + `"`
+	}
+	if weak {
+		return "W/" + etag
+	}
+	return etag
+}
+
+// etagMatches reports whether an If-None-Match header names etag, using
+// the weak comparison RFC 9110 prescribes for it.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/facades/storage"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// conditionalGet sends a request with the given headers to a handler
+// behind the given middleware.
+func conditionalGet(t *testing.T, method string, headers map[string]string, handler HandlerFunc, middleware ...MiddlewareFunc) (int, map[string]string, string) {
+	t.Helper()
+
+	app := fiber.New()
+	router := NewRouter(&mockApplication{}, app)
+	if method == "POST" {
+		router.POST("/", handler, middleware...)
+	} else {
+		router.GET("/", handler, middleware...)
+	}
+
+	req := httptest.NewRequest(method, "/", nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	got := map[string]string{}
+	for key := range resp.Header {
+		got[key] = resp.Header.Get(key)
+	}
+	return resp.StatusCode, got, string(body)
+}
+
+func TestETagMiddleware(t *testing.T) {
+	handler := func(ctx *Context) error {
+		return ctx.JSONResponse(map[string]any{"id": 1})
+	}
+
+	status, headers, body := conditionalGet(t, "GET", nil, handler, ETag())
+	assert.Equal(t, 200, status)
+	assert.Equal(t, `{"id":1}`, body)
+	etag := headers["Etag"]
+	assert.Equal(t, GenerateETag([]byte(`{"id":1}`), false), etag)
+
+	status, headers, body = conditionalGet(t, "GET", map[string]string{"If-None-Match": etag}, handler, ETag())
+	assert.Equal(t, 304, status)
+	assert.Empty(t, body)
+	assert.Equal(t, etag, headers["Etag"])
+
+	// Weak comparison: a weak client tag matches the strong one.
+	status, _, _ = conditionalGet(t, "GET", map[string]string{"If-None-Match": `"other", W/` + etag}, handler, ETag())
+	assert.Equal(t, 304, status)
+
+	status, _, _ = conditionalGet(t, "GET", map[string]string{"If-None-Match": `"stale"`}, handler, ETag())
+	assert.Equal(t, 200, status)
+
+	status, headers, _ = conditionalGet(t, "POST", map[string]string{"If-None-Match": etag}, handler, ETag())
+	assert.Equal(t, 200, status)
+	assert.Empty(t, headers["Etag"])
+
+	_, headers, _ = conditionalGet(t, "GET", nil, handler, ETag(ETagConfig{Weak: true}))
+	assert.Equal(t, "W/"+etag, headers["Etag"])
+}
+
+func TestETagMiddlewareKeepsHandlerTags(t *testing.T) {
+	handler := func(ctx *Context) error {
+		ctx.SetETag("v42")
+		return ctx.String("post")
+	}
+
+	_, headers, _ := conditionalGet(t, "GET", nil, handler, ETag())
+	assert.Equal(t, `"v42"`, headers["Etag"])
+
+	status, _, _ := conditionalGet(t, "GET", map[string]string{"If-None-Match": `"v42"`}, handler, ETag())
+	assert.Equal(t, 304, status)
+
+	// Errors are not tagged.
+	status, headers, _ = conditionalGet(t, "GET", nil, func(ctx *Context) error {
+		return ctx.Status(500).String("boom")
+	}, ETag())
+	assert.Equal(t, 500, status)
+	assert.Empty(t, headers["Etag"])
+}
+
+func TestContextIfNoneMatch(t *testing.T) {
+	rendered := false
+	handler := func(ctx *Context) error {
+		if ctx.IfNoneMatch("W/\"v1\"") {
+			return nil
+		}
+		rendered = true
+		return ctx.String("post")
+	}
+
+	status, headers, body := conditionalGet(t, "GET", map[string]string{"If-None-Match": `"v1"`}, handler)
+	assert.Equal(t, 304, status)
+	assert.Empty(t, body)
+	assert.Equal(t, `W/"v1"`, headers["Etag"])
+	assert.False(t, rendered)
+
+	status, _, _ = conditionalGet(t, "GET", map[string]string{"If-None-Match": `*`}, handler)
+	assert.Equal(t, 304, status)
+
+	status, _, body = conditionalGet(t, "GET", nil, handler)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "post", body)
+	assert.True(t, rendered)
+}
+
+func TestContextIfModifiedSince(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC)
+	handler := func(ctx *Context) error {
+		if ctx.IfModifiedSince(modified) {
+			return nil
+		}
+		return ctx.String("report")
+	}
+
+	status, headers, _ := conditionalGet(t, "GET", map[string]string{"If-Modified-Since": "Wed, 01 May 2024 12:00:00 GMT"}, handler)
+	assert.Equal(t, 304, status)
+	assert.Equal(t, "Wed, 01 May 2024 12:00:00 GMT", headers["Last-Modified"])
+
+	status, _, _ = conditionalGet(t, "GET", map[string]string{"If-Modified-Since": "Wed, 01 May 2024 11:59:59 GMT"}, handler)
+	assert.Equal(t, 200, status)
+
+	// If-None-Match takes precedence.
+	status, _, _ = conditionalGet(t, "GET", map[string]string{
+		"If-Modified-Since": "Wed, 01 May 2024 12:00:00 GMT",
+		"If-None-Match":     `"other"`,
+	}, handler)
+	assert.Equal(t, 200, status)
+}
+
+func TestDiskResponseConditional(t *testing.T) {
+	disk := storage.Fake()
+	defer storage.Restore()
+	require.NoError(t, disk.Put(context.Background(), "reports/q3.csv", "a,b\n1,2\n"))
+	meta, err := disk.Metadata(context.Background(), "reports/q3.csv")
+	require.NoError(t, err)
+
+	handler := func(ctx *Context) error {
+		return ctx.Download("reports/q3.csv")
+	}
+
+	status, headers, _ := conditionalGet(t, "GET", nil, handler)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, meta.ETag, headers["Etag"])
+	assert.Equal(t, meta.LastModified.UTC().Format("Mon, 02 Jan 2006 15:04:05 GMT"), headers["Last-Modified"])
+
+	status, _, body := conditionalGet(t, "GET", map[string]string{"If-None-Match": meta.ETag}, handler)
+	assert.Equal(t, 304, status)
+	assert.Empty(t, body)
+
+	status, _, _ = conditionalGet(t, "GET", map[string]string{"If-Modified-Since": headers["Last-Modified"]}, handler)
+	assert.Equal(t, 304, status)
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Fallback registers the handler for requests no route matches, in place
+// of the default 404. On a group it only catches paths under the group's
+// prefix, and the most specific fallback wins. Group middleware runs before
+// the handler as it does for routes.
+//
+//	router.Fallback(func(ctx *http.Context) error {
+//		return ctx.Status(404).JSONResponse(map[string]any{"error": "Not Found"})
+//	})
+func (r *Router) Fallback(handler HandlerFunc, middleware ...MiddlewareFunc) *Route {
+	route := r.catchAll(handler, middleware)
+	r.registry.fallbacks = append(r.registry.fallbacks, route)
+	return route
+}
+
+// MethodNotAllowed registers the handler for requests whose path matches a
+// route but whose method doesn't. The Allow header lists the methods the
+// path accepts before the handler runs. Without a handler, these requests
+// fail with a 405 error for the error handler to render.
+func (r *Router) MethodNotAllowed(handler HandlerFunc, middleware ...MiddlewareFunc) *Route {
+	route := r.catchAll(handler, middleware)
+	r.registry.notAllowed = route
+	return route
+}
+
+// AllowedMethods returns the methods routes accept for path, in the order
+// they were registered. GET routes also accept HEAD.
+func (r *Router) AllowedMethods(path string) []string {
+	var methods []string
+	seen := make(map[string]bool)
+	add := func(method string) {
+		if !seen[method] {
+			seen[method] = true
+			methods = append(methods, method)
+		}
+	}
+
+	for _, route := range r.registry.routes {
+		params, _ := matchRoutePath(route.path, path)
+		if params == nil {
+			continue
+		}
+		if _, failed := route.failedConstraint(params); failed {
+			continue
+		}
+		add(route.method)
+		if route.method == fiber.MethodGet {
+			add(fiber.MethodHead)
+		}
+	}
+	return methods
+}
+
+// unmatched runs first for every request and handles the errors Fiber
+// returns when no route takes it: 404 for unknown paths and 405 for known
+// paths with other methods.
+func (r *Router) unmatched(c *fiber.Ctx) error {
+	err := c.Next()
+	if err == nil {
+		return nil
+	}
+
+	if err == fiber.ErrMethodNotAllowed {
+		if methods := r.AllowedMethods(c.Path()); len(methods) > 0 {
+			c.Set(fiber.HeaderAllow, strings.Join(methods, ", "))
+		}
+		if route := r.registry.notAllowed; route != nil {
+			return route.router.runUnmatched(c, route)
+		}
+		return err
+	}
+
+	if isRouteNotFound(c, err) {
+		if route := r.fallbackFor(c.Path()); route != nil {
+			return route.router.runUnmatched(c, route)
+		}
+		// Global middleware still sees the request, so middleware such as
+		// Maintenance can answer paths no route serves.
+		return r.runUnmatched(c, r.catchAll(func(*Context) error { return err }, nil))
+	}
+	return err
+}
+
+// fallbackFor returns the fallback with the longest prefix covering path.
+func (r *Router) fallbackFor(path string) *Route {
+	var match *Route
+	for _, route := range r.registry.fallbacks {
+		prefix := route.router.prefix
+		if prefix != "" && path != prefix && !strings.HasPrefix(path, prefix+"/") {
+			continue
+		}
+		if match == nil || len(prefix) > len(match.router.prefix) {
+			match = route
+		}
+	}
+	return match
+}
+
+func (r *Router) catchAll(handler HandlerFunc, middleware []MiddlewareFunc) *Route {
+	return &Route{method: "*", path: r.prefix + "/*", handler: handler, middleware: middleware, router: r}
+}
+
+func (r *Router) runUnmatched(c *fiber.Ctx, route *Route) error {
+	c.Locals(routerLocalsKey, r)
+	c.Locals(routeLocalsKey, route)
+	return r.executeMiddleware(NewContext(c, r.app), r.routeMiddleware(route), route.handler)
+}
+
+// isRouteNotFound reports whether err is Fiber's 404 for a request no
+// route matched, as opposed to a 404 returned by a handler.
+func isRouteNotFound(c *fiber.Ctx, err error) bool {
+	e, ok := err.(*fiber.Error)
+	return ok && e.Code == fiber.StatusNotFound && strings.HasPrefix(e.Message, "Cannot "+c.Method()+" ")
+}
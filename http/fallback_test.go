@@ -0,0 +1,113 @@
+package http
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sendFallback(t *testing.T, app *fiber.App, method, path string) (int, string, string) {
+	t.Helper()
+
+	resp, err := app.Test(httptest.NewRequest(method, path, nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, resp.Header.Get("Allow"), string(body)
+}
+
+func TestRouterFallback(t *testing.T) {
+	app := fiber.New()
+	router := NewRouter(&mockApplication{}, app)
+
+	router.GET("/users", func(ctx *Context) error { return ctx.String("users") })
+	router.GET("/missing", func(ctx *Context) error { return fiber.ErrNotFound })
+	router.Fallback(func(ctx *Context) error {
+		return ctx.Status(404).JSONResponse(map[string]any{"error": "root"})
+	})
+	router.Group("/api", func(api *Router) {
+		api.GET("/ping", func(ctx *Context) error { return ctx.String("pong") })
+		api.Fallback(func(ctx *Context) error {
+			return ctx.Status(404).JSONResponse(map[string]any{"error": "api", "tagged": ctx.Get("tagged")})
+		})
+	}, func(ctx *Context, next func() error) error {
+		ctx.Set("tagged", true)
+		return next()
+	})
+
+	status, _, body := sendFallback(t, app, "GET", "/users")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "users", body)
+
+	status, _, body = sendFallback(t, app, "GET", "/nope")
+	assert.Equal(t, 404, status)
+	assert.JSONEq(t, `{"error":"root"}`, body)
+
+	// Group fallbacks take precedence under their prefix and run group middleware.
+	status, _, body = sendFallback(t, app, "GET", "/api/nope")
+	assert.Equal(t, 404, status)
+	assert.JSONEq(t, `{"error":"api","tagged":true}`, body)
+
+	status, _, body = sendFallback(t, app, "GET", "/apiary")
+	assert.Equal(t, 404, status)
+	assert.JSONEq(t, `{"error":"root"}`, body)
+
+	// A 404 returned by a handler isn't a missing route.
+	status, _, body = sendFallback(t, app, "GET", "/missing")
+	assert.Equal(t, 404, status)
+	assert.Equal(t, "Not Found", body)
+}
+
+func TestRouterUnmatchedRunsGlobalMiddleware(t *testing.T) {
+	app := fiber.New()
+	router := NewRouter(&mockApplication{}, app)
+	router.Use(func(ctx *Context, next func() error) error {
+		if ctx.Path() == "/intercepted" {
+			return ctx.String("intercepted")
+		}
+		return next()
+	})
+	router.GET("/users", func(ctx *Context) error { return ctx.String("users") })
+
+	status, _, body := sendFallback(t, app, "GET", "/intercepted")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "intercepted", body)
+
+	status, _, _ = sendFallback(t, app, "GET", "/nope")
+	assert.Equal(t, 404, status)
+}
+
+func TestRouterMethodNotAllowed(t *testing.T) {
+	app := fiber.New()
+	router := NewRouter(&mockApplication{}, app)
+
+	router.GET("/users/:id", func(ctx *Context) error { return nil }).WhereNumber("id")
+	router.DELETE("/users/:id", func(ctx *Context) error { return nil })
+	router.Fallback(func(ctx *Context) error { return ctx.Status(404).String("fallback") })
+
+	// Without a handler the 405 error is returned with an Allow header.
+	status, allow, _ := sendFallback(t, app, "POST", "/users/1")
+	assert.Equal(t, 405, status)
+	assert.Equal(t, "GET, HEAD, DELETE", allow)
+
+	// Constraints narrow the allowed methods.
+	_, allow, _ = sendFallback(t, app, "POST", "/users/abc")
+	assert.Equal(t, "DELETE", allow)
+
+	router.MethodNotAllowed(func(ctx *Context) error {
+		return ctx.Status(405).JSONResponse(map[string]any{"error": "Method Not Allowed"})
+	})
+	status, allow, body := sendFallback(t, app, "PUT", "/users/1")
+	assert.Equal(t, 405, status)
+	assert.Equal(t, "GET, HEAD, DELETE", allow)
+	assert.JSONEq(t, `{"error":"Method Not Allowed"}`, body)
+
+	assert.Equal(t, []string{"GET", "HEAD", "DELETE"}, router.AllowedMethods("/users/1"))
+	assert.Empty(t, router.AllowedMethods("/posts"))
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/genesysflow/go-genesys/validation"
+	"github.com/gofiber/fiber/v2"
+)
+
+// FormRequest is a request type that decides whether the current user may
+// make the request. Together with `validate` struct tags it describes a
+// request completely; see Form.
+//
+//	type StorePostRequest struct {
+//		Title string `json:"title" validate:"required,max=120"`
+//		Body  string `json:"body" validate:"required"`
+//	}
+//
+//	func (r *StorePostRequest) Authorize(ctx *http.Context) bool {
+//		return ctx.User() != nil
+//	}
+type FormRequest interface {
+	Authorize(ctx *Context) bool
+}
+
+// Form wraps a handler that takes a FormRequest. For each request it binds
+// a new T with Context.Bind, responds 403 when Authorize returns false,
+// validates the struct tags and responds 422 with the field errors when
+// they fail, and otherwise calls handler with the request.
+//
+//	router.POST("/posts", http.Form(func(ctx *http.Context, req *StorePostRequest) error {
+//		return ctx.Created(req)
+//	}))
+func Form[T any, PT interface {
+	*T
+	FormRequest
+}](handler func(ctx *Context, req PT) error) HandlerFunc {
+	return func(ctx *Context) error {
+		req := PT(new(T))
+		if err := ctx.Bind(req); err != nil {
+			return err
+		}
+		if !req.Authorize(ctx) {
+			return ctx.Forbidden("This action is unauthorized.")
+		}
+		if err := ctx.Validate(req); err != nil {
+			var invalid *validation.ValidationErrors
+			if errors.As(err, &invalid) {
+				return ValidationFailed(ctx, invalid)
+			}
+			return err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// ValidationFailed responds 422 with the field errors, in the same shape as
+// Controller.ValidationError.
+func ValidationFailed(ctx *Context, errs *validation.ValidationErrors) error {
+	return ctx.Status(fiber.StatusUnprocessableEntity).JSONResponse(map[string]any{
+		"success": false,
+		"error":   errs.Message(),
+		"errors":  errs.All(),
+	})
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/validation"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type storePostRequest struct {
+	Title string `json:"title" query:"title" validate:"required,max=10"`
+	Draft bool   `json:"draft" query:"draft"`
+}
+
+func (r *storePostRequest) Authorize(ctx *Context) bool {
+	return ctx.Request().Header("Authorization") != "deny"
+}
+
+func newFormRouter(t *testing.T) *fiber.App {
+	t.Helper()
+	app := fiber.New()
+	router := NewRouter(&mockApplication{}, app)
+	router.POST("/posts", Form(func(ctx *Context, req *storePostRequest) error {
+		return ctx.Created(req)
+	}))
+	return app
+}
+
+func doForm(t *testing.T, app *fiber.App, target, body string, headers map[string]string) (int, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest("POST", target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	raw, _ := io.ReadAll(resp.Body)
+	var decoded map[string]any
+	json.Unmarshal(raw, &decoded)
+	return resp.StatusCode, decoded
+}
+
+func TestFormBindsAndValidates(t *testing.T) {
+	app := newFormRouter(t)
+
+	status, body := doForm(t, app, "/posts?draft=true", `{"title":"Hello"}`, nil)
+	assert.Equal(t, 201, status)
+	assert.Equal(t, "Hello", body["title"])
+	assert.Equal(t, true, body["draft"])
+
+	status, body = doForm(t, app, "/posts", `{"title":"A very long title"}`, nil)
+	assert.Equal(t, 422, status)
+	assert.Equal(t, false, body["success"])
+	assert.Equal(t, "Validation failed", body["error"])
+	assert.Equal(t, map[string]any{"title": []any{"Title must not exceed 10 characters"}}, body["errors"])
+
+	status, _ = doForm(t, app, "/posts", `{"title":"Hello"}`, map[string]string{"Authorization": "deny"})
+	assert.Equal(t, 403, status)
+
+	status, _ = doForm(t, app, "/posts", `{"title":`, nil)
+	assert.Equal(t, 400, status)
+}
+
+func TestContextValidateRules(t *testing.T) {
+	app := fiber.New()
+	var err error
+	app.Post("/", func(c *fiber.Ctx) error {
+		ctx := NewContext(c, &mockApplication{})
+		err = ctx.Validate(map[string]string{"email": "required,email", "name": "required"})
+		return nil
+	})
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"email":"not-an-email"}`))
+	req.Header.Set("Content-Type", "application/json")
+	_, testErr := app.Test(req)
+	require.NoError(t, testErr)
+
+	var invalid *validation.ValidationErrors
+	require.ErrorAs(t, err, &invalid)
+	assert.True(t, invalid.Has("email"))
+	assert.True(t, invalid.Has("name"))
+	assert.Equal(t, 422, invalid.StatusCode())
+}
+
+func TestContextValidated(t *testing.T) {
+	app := fiber.New()
+	var validated map[string]any
+	var err error
+	app.Post("/", func(c *fiber.Ctx) error {
+		ctx := NewContext(c, &mockApplication{})
+		err = ctx.Validate(map[string]string{"name": "required", "age": "required,number", "newsletter": "sometimes,boolean"})
+		validated = ctx.Validated()
+		return nil
+	})
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader("name=Ada&age=36&admin=1"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	_, testErr := app.Test(req)
+	require.NoError(t, testErr)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "Ada", "age": 36}, validated)
+}
+
+func TestKernelRendersValidationErrors(t *testing.T) {
+	handler := createErrorHandler(&mockApplication{})
+	app := fiber.New(fiber.Config{ErrorHandler: handler})
+	app.Get("/", func(c *fiber.Ctx) error {
+		errs := validation.NewValidationErrors()
+		errs.Add("email", "The email field is required.")
+		return errs
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 422, resp.StatusCode)
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, map[string]any{"email": []any{"The email field is required."}}, body["errors"])
+}
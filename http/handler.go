@@ -0,0 +1,26 @@
+package http
+
+import (
+	nethttp "net/http"
+
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// Handler returns the router's routes as a net/http handler, for mounting
+// in an existing net/http server. Routes keep their full paths, so mount
+// the handler at the router's prefix without stripping it:
+//
+//	mux.Handle("/api/", kernel.Router().Handler())
+//
+// Each request is copied into a Fiber context, and request bodies are read
+// fully before the handler runs. The handler serves every route registered
+// on the router's Fiber app, including those of other groups.
+func (r *Router) Handler() nethttp.Handler {
+	return adaptor.FiberApp(r.fiber)
+}
+
+// Handler returns the kernel's routes, middleware and error handling as a
+// net/http handler. See Router.Handler.
+func (k *Kernel) Handler() nethttp.Handler {
+	return adaptor.FiberApp(k.fiber)
+}
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterHandlerMountsInServeMux(t *testing.T) {
+	router := NewRouter(&mockApplication{}, fiber.New())
+	router.Group("/api", func(r *Router) {
+		r.GET("/users/:id", func(ctx *Context) error {
+			return ctx.String("user " + ctx.Param("id") + " " + ctx.Get("via").(string))
+		})
+		r.POST("/echo", func(ctx *Context) error {
+			return ctx.String(string(ctx.FiberCtx().Body()))
+		})
+	}, func(ctx *Context, next func() error) error {
+		ctx.Set("via", "net/http")
+		return next()
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/", router.Handler())
+	mux.HandleFunc("/legacy", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "legacy")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := get("/api/users/7")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "user 7 net/http", body)
+
+	status, body = get("/legacy")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "legacy", body)
+
+	status, _ = get("/api/missing")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	resp, err := http.Post(server.URL+"/api/echo", "text/plain", strings.NewReader("ping"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	echoed, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "ping", string(echoed))
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JSONCodec encodes response bodies and decodes request bodies. Register
+// faster implementations such as sonic or go-json with RegisterJSONCodec and
+// select them with KernelConfig.JSONCodec or the http.json_codec config key.
+type JSONCodec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+
+	// Encode writes the encoding of v to w. Responses are encoded into
+	// pooled buffers through it.
+	Encode(w io.Writer, v any) error
+}
+
+// StdJSON is the encoding/json codec, registered as "std".
+type StdJSON struct{}
+
+func (StdJSON) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (StdJSON) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (StdJSON) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+var (
+	jsonCodecs = map[string]JSONCodec{"std": StdJSON{}}
+	codecsMu   sync.RWMutex
+)
+
+// RegisterJSONCodec makes a codec available under name.
+func RegisterJSONCodec(name string, codec JSONCodec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	jsonCodecs[name] = codec
+}
+
+// GetJSONCodec returns the codec registered under name.
+func GetJSONCodec(name string) (JSONCodec, error) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	codec, ok := jsonCodecs[name]
+	if !ok {
+		return nil, fmt.Errorf("http: JSON codec %q is not registered", name)
+	}
+	return codec, nil
+}
+
+// SetJSONCodec sets the codec responses of the router and its groups are
+// encoded with. The kernel sets it from KernelConfig.JSONCodec; pass the same
+// codec's Unmarshal as the Fiber app's JSONDecoder for request bodies.
+func (r *Router) SetJSONCodec(codec JSONCodec) *Router {
+	r.registry.codec = codec
+	return r
+}
+
+// codecFor returns the codec of the router handling the request,
+// encoding/json by default.
+func codecFor(c *fiber.Ctx) JSONCodec {
+	if router, ok := c.Locals(routerLocalsKey).(*Router); ok && router.registry.codec != nil {
+		return router.registry.codec
+	}
+	return StdJSON{}
+}
+
+// maxPooledBuffer keeps unusually large responses from pinning memory in the pool.
+const maxPooledBuffer = 64 << 10
+
+var jsonBuffers = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// writeJSON encodes v into a pooled buffer and copies it into the response body.
+func writeJSON(c *fiber.Ctx, v any) error {
+	buf := jsonBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBuffer {
+			jsonBuffers.Put(buf)
+		}
+	}()
+
+	if err := codecFor(c).Encode(buf, v); err != nil {
+		return err
+	}
+
+	c.Response().SetBody(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	c.Response().Header.SetContentType(fiber.MIMEApplicationJSON)
+	return nil
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingCodec wraps encoding/json and counts calls, standing in for sonic or go-json.
+type countingCodec struct {
+	StdJSON
+	encodes, decodes atomic.Int32
+}
+
+func (c *countingCodec) Encode(w io.Writer, v any) error {
+	c.encodes.Add(1)
+	return c.StdJSON.Encode(w, v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v any) error {
+	c.decodes.Add(1)
+	return c.StdJSON.Unmarshal(data, v)
+}
+
+func TestJSONResponseUsesStdByDefault(t *testing.T) {
+	app := newTestApp()
+	router := NewRouter(&mockApplication{}, app)
+	router.GET("/user", func(ctx *Context) error {
+		return ctx.JSONResponse(map[string]any{"name": "<Ada>"})
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/user", nil))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get("Content-Type"))
+	expected, _ := json.Marshal(map[string]any{"name": "<Ada>"})
+	assert.Equal(t, string(expected), string(body))
+}
+
+func TestJSONCodecPerRouter(t *testing.T) {
+	codec := &countingCodec{}
+	RegisterJSONCodec("counting", codec)
+
+	registered, err := GetJSONCodec("counting")
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{JSONDecoder: registered.Unmarshal})
+	router := NewRouter(&mockApplication{}, app).SetJSONCodec(registered)
+	router.Group("/api", func(api *Router) {
+		api.POST("/echo", func(ctx *Context) error {
+			var payload map[string]any
+			if err := ctx.JSON(&payload); err != nil {
+				return err
+			}
+			return ctx.Response().JSON(payload)
+		})
+	})
+
+	req := httptest.NewRequest("POST", "/api/echo", strings.NewReader(`{"id":7}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, `{"id":7}`, string(body))
+	assert.Equal(t, int32(1), codec.encodes.Load())
+	assert.Equal(t, int32(1), codec.decodes.Load())
+}
+
+func TestJSONResponseEncodeError(t *testing.T) {
+	app := newTestApp()
+	router := NewRouter(&mockApplication{}, app)
+	router.GET("/bad", func(ctx *Context) error {
+		return ctx.JSONResponse(map[string]any{"fn": func() {}})
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/bad", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestGetJSONCodecUnknown(t *testing.T) {
+	_, err := GetJSONCodec("simdjson")
+	assert.EqualError(t, err, `http: JSON codec "simdjson" is not registered`)
+}
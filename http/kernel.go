@@ -2,14 +2,18 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	nethttp "net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/genesysflow/go-genesys/container"
 	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/validation"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -20,6 +24,13 @@ type Kernel struct {
 	router     *Router
 	middleware []MiddlewareFunc
 	logger     contracts.Logger
+
+	shutdownTimeout time.Duration
+
+	// servers are net/http servers serving HTTP/2 and HTTPS redirects,
+	// shut down with the Fiber server.
+	servers   []*nethttp.Server
+	serversMu sync.Mutex
 }
 
 // KernelConfig defines configuration for the HTTP kernel.
@@ -41,6 +52,27 @@ type KernelConfig struct {
 
 	// Genesys-specific
 	TrustedProxies []string
+
+	// RouteConflicts controls how duplicate or shadowed routes are reported.
+	RouteConflicts ConflictMode
+
+	// JSONCodec names the registered codec used for JSON requests and
+	// responses. Defaults to the http.json_codec config value, then "std".
+	JSONCodec string
+
+	// ETag tags GET and HEAD responses and answers conditional requests for
+	// all routes; see ETag. The http.etag config value enables it too.
+	ETag bool
+
+	// MiddlewareGroups names lists of middleware aliases, such as "web" and
+	// "api", for UseNamed and MiddlewareNamed. Groups under the
+	// http.middleware_groups config value are added first.
+	MiddlewareGroups map[string][]string
+
+	// ShutdownTimeout bounds how long a graceful shutdown waits for
+	// in-flight requests, and then for the application to terminate. The
+	// http.shutdown_timeout config value (e.g. "15s") overrides it.
+	ShutdownTimeout time.Duration
 }
 
 // DefaultKernelConfig returns the default kernel configuration.
@@ -55,16 +87,30 @@ func DefaultKernelConfig() KernelConfig {
 		ReadBufferSize:    4096,
 		WriteBufferSize:   4096,
 		EnablePrintRoutes: false,
+		ShutdownTimeout:   30 * time.Second,
 	}
 }
 
-// NewKernel creates a new HTTP kernel.
+// NewKernel creates a new HTTP kernel. It panics if the configured JSON
+// codec is not registered.
 func NewKernel(app contracts.Application, config ...KernelConfig) *Kernel {
 	cfg := DefaultKernelConfig()
 	if len(config) > 0 {
 		cfg = config[0]
 	}
 
+	codecName := cfg.JSONCodec
+	if codecName == "" && app.GetConfig() != nil {
+		codecName = app.GetConfig().GetString("http.json_codec")
+	}
+	if codecName == "" {
+		codecName = "std"
+	}
+	codec, err := GetJSONCodec(codecName)
+	if err != nil {
+		panic(err)
+	}
+
 	// Create Fiber app with configuration
 	fiberApp := fiber.New(fiber.Config{
 		AppName:               cfg.AppName,
@@ -81,6 +127,8 @@ func NewKernel(app contracts.Application, config ...KernelConfig) *Kernel {
 		EnablePrintRoutes:     cfg.EnablePrintRoutes,
 		DisableStartupMessage: cfg.DisableStartupMessage,
 		ErrorHandler:          createErrorHandler(app),
+		JSONEncoder:           codec.Marshal,
+		JSONDecoder:           codec.Unmarshal,
 	})
 
 	// Note: Trusted proxies are set via fiber.Config during app creation
@@ -91,14 +139,45 @@ func NewKernel(app contracts.Application, config ...KernelConfig) *Kernel {
 	logger := container.MustResolve[contracts.Logger](app)
 
 	kernel := &Kernel{
-		app:        app,
-		fiber:      fiberApp,
-		middleware: make([]MiddlewareFunc, 0),
-		logger:     logger,
+		app:             app,
+		fiber:           fiberApp,
+		middleware:      make([]MiddlewareFunc, 0),
+		logger:          logger,
+		shutdownTimeout: cfg.ShutdownTimeout,
+	}
+	if app.GetConfig() != nil {
+		if value := app.GetConfig().GetString("http.shutdown_timeout"); value != "" {
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				panic(fmt.Errorf("invalid http.shutdown_timeout: %w", err))
+			}
+			kernel.shutdownTimeout = timeout
+		}
 	}
 
 	// Create router
 	kernel.router = NewRouter(app, fiberApp)
+	kernel.router.SetConflictMode(cfg.RouteConflicts)
+	kernel.router.SetJSONCodec(codec)
+	if cache, err := LoadRouteCache(RouteCachePath(app)); err == nil {
+		kernel.router.UseRouteCache(cache)
+	} else if !os.IsNotExist(err) && logger != nil {
+		logger.Warn("Ignoring route cache", "error", err.Error())
+	}
+
+	named := kernel.router.MiddlewareRegistry()
+	if appConfig := app.GetConfig(); appConfig != nil {
+		for name := range appConfig.GetMap("http.middleware_groups") {
+			named.Group(name, appConfig.GetStringSlice("http.middleware_groups."+name)...)
+		}
+	}
+	for name, middleware := range cfg.MiddlewareGroups {
+		named.Group(name, middleware...)
+	}
+
+	if cfg.ETag || (app.GetConfig() != nil && app.GetConfig().GetBool("http.etag")) {
+		kernel.Use(ETag())
+	}
 
 	return kernel
 }
@@ -120,6 +199,11 @@ func createErrorHandler(app contracts.Application) fiber.ErrorHandler {
 			}
 		}
 
+		// Validation errors respond 422 with the field errors
+		if invalid, ok := err.(*validation.ValidationErrors); ok {
+			return ValidationFailed(NewContext(c, app), invalid)
+		}
+
 		code := fiber.StatusInternalServerError
 
 		// Check if it's a Fiber error
@@ -156,6 +240,11 @@ func createErrorHandler(app contracts.Application) fiber.ErrorHandler {
 	}
 }
 
+// App returns the application the kernel serves.
+func (k *Kernel) App() contracts.Application {
+	return k.app
+}
+
 // Fiber returns the underlying Fiber app.
 func (k *Kernel) Fiber() *fiber.App {
 	return k.fiber
@@ -173,6 +262,12 @@ func (k *Kernel) Use(middleware ...MiddlewareFunc) *Kernel {
 	return k
 }
 
+// UseNamed registers global middleware by alias or group name. It panics if
+// a name can't be resolved.
+func (k *Kernel) UseNamed(names ...string) *Kernel {
+	return k.Use(k.router.mustResolveMiddleware(names)...)
+}
+
 // UseFiber registers Fiber middleware directly.
 func (k *Kernel) UseFiber(middleware ...fiber.Handler) *Kernel {
 	for _, m := range middleware {
@@ -199,44 +294,81 @@ func (k *Kernel) RunTLS(addr, certFile, keyFile string) error {
 	return k.fiber.ListenTLS(addr, certFile, keyFile)
 }
 
-// RunWithGracefulShutdown starts the server with graceful shutdown support.
+// RunWithGracefulShutdown starts the server and shuts it down gracefully
+// on SIGINT or SIGTERM; see RunUntil. A second signal exits immediately. A
+// zero timeout uses the kernel's ShutdownTimeout.
 func (k *Kernel) RunWithGracefulShutdown(addr string, timeout time.Duration) error {
-	// Channel to listen for errors from the server
-	errChan := make(chan error, 1)
+	ctx, stop := shutdownSignal()
+	defer stop()
+
+	return k.RunUntil(ctx, addr, timeout)
+}
+
+// shutdownSignal returns a context done on SIGINT or SIGTERM. Signals are
+// only trapped once, so a second one exits immediately.
+func shutdownSignal() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	context.AfterFunc(ctx, stop)
+	return ctx, stop
+}
+
+// RunUntil starts the server and shuts it down gracefully once ctx is done;
+// see GracefulShutdown.
+func (k *Kernel) RunUntil(ctx context.Context, addr string, timeout time.Duration) error {
+	if k.logger != nil {
+		k.logger.Info("Starting HTTP server with graceful shutdown", "address", addr)
+	}
+	return k.serveUntil(ctx, func() error { return k.fiber.Listen(addr) }, timeout)
+}
 
-	// Start server in goroutine
+func (k *Kernel) serveUntil(ctx context.Context, serve func() error, timeout time.Duration) error {
+	errChan := make(chan error, 1)
 	go func() {
-		if k.logger != nil {
-			k.logger.Info("Starting HTTP server with graceful shutdown", "address", addr)
-		}
-		errChan <- k.fiber.Listen(addr)
+		errChan <- serve()
 	}()
 
-	// Channel to listen for interrupt signals
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-	// Wait for interrupt or error
 	select {
 	case err := <-errChan:
 		return err
-	case sig := <-sigChan:
+	case <-ctx.Done():
 		if k.logger != nil {
-			k.logger.Info("Received shutdown signal", "signal", sig.String())
+			k.logger.Info("Received shutdown signal")
 		}
 	}
 
-	// Create shutdown context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+	return k.GracefulShutdown(timeout)
+}
 
-	// Terminate the application
-	// Note: We ignore the error here as samber/do may return non-critical marshaling errors
-	_ = k.app.TerminateWithContext(ctx)
+// GracefulShutdown stops accepting connections, waits up to timeout for
+// in-flight requests to finish, and then terminates the application: its
+// Terminating callbacks run, and queue and database connections are closed.
+// The application is terminated even if requests are still running when
+// the timeout expires. A zero timeout uses the kernel's ShutdownTimeout.
+func (k *Kernel) GracefulShutdown(timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = k.shutdownTimeout
+	}
+	if timeout <= 0 {
+		timeout = DefaultKernelConfig().ShutdownTimeout
+	}
 
-	// Shutdown Fiber
-	if err := k.fiber.ShutdownWithContext(ctx); err != nil {
-		return fmt.Errorf("server shutdown failed: %w", err)
+	drainCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	drainErr := k.fiber.ShutdownWithContext(drainCtx)
+	for _, server := range k.trackedServers() {
+		drainErr = errors.Join(drainErr, server.Shutdown(drainCtx))
+	}
+	if drainErr != nil && k.logger != nil {
+		k.logger.Warn("Requests still in flight at shutdown timeout", "timeout", timeout.String())
+	}
+
+	terminateCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := k.app.TerminateWithContext(terminateCtx); err != nil {
+		return fmt.Errorf("application termination failed: %w", err)
+	}
+	if drainErr != nil {
+		return fmt.Errorf("server shutdown failed: %w", drainErr)
 	}
 
 	if k.logger != nil {
@@ -246,6 +378,18 @@ func (k *Kernel) RunWithGracefulShutdown(addr string, timeout time.Duration) err
 	return nil
 }
 
+func (k *Kernel) trackServer(server *nethttp.Server) {
+	k.serversMu.Lock()
+	defer k.serversMu.Unlock()
+	k.servers = append(k.servers, server)
+}
+
+func (k *Kernel) trackedServers() []*nethttp.Server {
+	k.serversMu.Lock()
+	defer k.serversMu.Unlock()
+	return append([]*nethttp.Server(nil), k.servers...)
+}
+
 // Shutdown gracefully shuts down the server.
 func (k *Kernel) Shutdown() error {
 	return k.fiber.Shutdown()
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// terminatingApplication records when the application is terminated.
+type terminatingApplication struct {
+	mockApplication
+	terminated func()
+}
+
+func (a *terminatingApplication) TerminateWithContext(ctx context.Context) error {
+	a.terminated()
+	return nil
+}
+
+func TestKernelGracefulShutdown(t *testing.T) {
+	var handled, terminatedAfterHandled atomic.Bool
+	app := &terminatingApplication{terminated: func() {
+		terminatedAfterHandled.Store(handled.Load())
+	}}
+
+	fiberApp := newTestApp()
+	kernel := &Kernel{app: app, fiber: fiberApp, router: NewRouter(app, fiberApp)}
+	started := make(chan struct{})
+	kernel.GET("/slow", func(ctx *Context) error {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		handled.Store(true)
+		return ctx.String("done")
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- kernel.serveUntil(ctx, func() error { return fiberApp.Listener(ln) }, time.Second)
+	}()
+
+	type result struct {
+		body string
+		err  error
+	}
+	response := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		response <- result{body: string(body), err: err}
+	}()
+
+	<-started
+	cancel()
+
+	// The in-flight request completes before the app is terminated.
+	res := <-response
+	require.NoError(t, res.err)
+	assert.Equal(t, "done", res.body)
+	require.NoError(t, <-done)
+	assert.True(t, terminatedAfterHandled.Load())
+
+	// New connections are refused.
+	_, err = net.DialTimeout("tcp", ln.Addr().String(), 100*time.Millisecond)
+	assert.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MaintenanceMode is the state `genesys down` writes while the app is down
+// for maintenance. The Maintenance middleware answers requests with 503
+// while the file exists.
+type MaintenanceMode struct {
+	// Time is when the app went down.
+	Time time.Time `json:"time"`
+
+	// Retry is the number of seconds sent in the Retry-After header; zero
+	// leaves the header out.
+	Retry int `json:"retry,omitempty"`
+
+	// Secret lets a visitor past maintenance: visiting /{secret} sets a
+	// cookie that the middleware accepts.
+	Secret string `json:"secret,omitempty"`
+}
+
+// MaintenanceModePath returns where the maintenance file of an app is
+// kept: storage/framework/down.
+func MaintenanceModePath(storagePath string) string {
+	return filepath.Join(storagePath, "framework", "down")
+}
+
+// WriteMaintenanceMode puts the app down for maintenance by writing mode
+// to path, creating its directory.
+func WriteMaintenanceMode(path string, mode *MaintenanceMode) error {
+	data, err := json.MarshalIndent(mode, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadMaintenanceMode reads the maintenance file at path. It returns nil
+// and no error when the app isn't down.
+func LoadMaintenanceMode(path string) (*MaintenanceMode, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var mode MaintenanceMode
+	if err := json.Unmarshal(data, &mode); err != nil {
+		return nil, fmt.Errorf("invalid maintenance file '%s': %w", path, err)
+	}
+	return &mode, nil
+}
+
+// BypassToken returns the cookie value that lets a visitor past
+// maintenance, derived from the secret so the secret itself isn't stored
+// in browsers.
+func (m *MaintenanceMode) BypassToken() string {
+	sum := sha256.Sum256([]byte("genesys-maintenance:" + m.Secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/errors"
+	"github.com/genesysflow/go-genesys/http"
+)
+
+// Access log formats.
+const (
+	// AccessLogJSON logs each request as structured fields.
+	AccessLogJSON = "json"
+
+	// AccessLogCombined logs each request as an Apache combined log line.
+	AccessLogCombined = "combined"
+)
+
+// LoggerConfig configures LoggerWithConfig.
+type LoggerConfig struct {
+	// Logger receives the entries. Defaults to the request's logger.
+	Logger contracts.Logger
+
+	// Format is AccessLogJSON (the default) or AccessLogCombined.
+	Format string
+
+	// Skip lists paths that are not logged, such as health checks. A
+	// trailing "/*" skips everything under a path, and other patterns are
+	// matched with path.Match.
+	Skip []string
+}
+
+// Logger creates a request logging middleware that logs to logger.
+func Logger(logger contracts.Logger) http.MiddlewareFunc {
+	return LoggerWithConfig(LoggerConfig{Logger: logger})
+}
+
+// LoggerWithConfig creates a request logging middleware. Each request is
+// logged after it is handled with its method, path, status, latency,
+// response size, client IP, user agent and request ID. Server errors are
+// logged at error level and client errors at warn level.
+func LoggerWithConfig(config LoggerConfig) http.MiddlewareFunc {
+	if config.Format == "" {
+		config.Format = AccessLogJSON
+	}
+	if config.Format != AccessLogJSON && config.Format != AccessLogCombined {
+		panic(fmt.Sprintf("middleware: unknown access log format %q", config.Format))
+	}
+
+	return func(ctx *http.Context, next func() error) error {
+		if skipPath(config.Skip, ctx.Path()) {
+			return next()
+		}
+
+		start := time.Now()
+		err := next()
+		latency := time.Since(start)
+
+		logger := config.Logger
+		if logger == nil {
+			logger = ctx.Logger()
+		}
+		if logger == nil {
+			return err
+		}
+
+		entry := newAccessEntry(ctx, err, latency)
+		write := logger.Info
+		switch {
+		case entry.status >= 500:
+			write = logger.Error
+		case entry.status >= 400:
+			write = logger.Warn
+		}
+
+		if config.Format == AccessLogCombined {
+			write(entry.combined(), "request_id", entry.requestID)
+		} else {
+			write("HTTP Request", entry.fields()...)
+		}
+		return err
+	}
+}
+
+// accessEntry is what is logged about a request.
+type accessEntry struct {
+	time      time.Time
+	method    string
+	path      string
+	protocol  string
+	status    int
+	latency   time.Duration
+	bytes     int
+	ip        string
+	referer   string
+	userAgent string
+	requestID string
+}
+
+func newAccessEntry(ctx *http.Context, err error, latency time.Duration) accessEntry {
+	c := ctx.FiberCtx()
+	status := c.Response().StatusCode()
+	if err != nil {
+		// The error handler writes the response after the middleware returns.
+		status, _ = errors.Status(err)
+	}
+
+	// Reading a streamed body, such as a file, would buffer it.
+	size := c.Response().Header.ContentLength()
+	if !c.Response().IsBodyStream() {
+		size = len(c.Response().Body())
+	} else if size < 0 {
+		size = 0
+	}
+
+	return accessEntry{
+		time:      time.Now(),
+		method:    ctx.Method(),
+		path:      string(c.Request().RequestURI()),
+		protocol:  string(c.Request().Header.Protocol()),
+		status:    status,
+		latency:   latency,
+		bytes:     size,
+		ip:        ctx.IP(),
+		referer:   c.Get("Referer"),
+		userAgent: c.Get("User-Agent"),
+		requestID: ctx.RequestID(),
+	}
+}
+
+func (e accessEntry) fields() []any {
+	return []any{
+		"method", e.method,
+		"path", e.path,
+		"status", e.status,
+		"latency_ms", float64(e.latency.Microseconds()) / 1000,
+		"bytes", e.bytes,
+		"ip", e.ip,
+		"user_agent", e.userAgent,
+		"request_id", e.requestID,
+	}
+}
+
+// combined formats the entry in the Apache combined log format.
+func (e accessEntry) combined() string {
+	return fmt.Sprintf(`%s - - [%s] "%s %s %s" %d %d %q %q`,
+		e.ip,
+		e.time.Format("02/Jan/2006:15:04:05 -0700"),
+		e.method, e.path, e.protocol,
+		e.status, e.bytes,
+		dash(e.referer), dash(e.userAgent),
+	)
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// skipPath reports whether p matches one of the patterns.
+func skipPath(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+			if p == prefix || strings.HasPrefix(p, prefix+"/") {
+				return true
+			}
+			continue
+		}
+		if matched, _ := path.Match(pattern, p); matched {
+			return true
+		}
+	}
+	return false
+}
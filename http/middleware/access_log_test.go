@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/log"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAccessLogApp(t *testing.T, config LoggerConfig) (*fiber.App, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	config.Logger = log.NewJSON(&buf)
+
+	app := fiber.New()
+	router := http.NewRouter(testutil.NewMockApplication(), app)
+	router.Use(RequestID(), LoggerWithConfig(config))
+	router.GET("/users", func(ctx *http.Context) error {
+		return ctx.String("hello")
+	})
+	router.GET("/fail", func(ctx *http.Context) error {
+		return errors.New("boom")
+	})
+	router.GET("/health", func(ctx *http.Context) error {
+		return ctx.NoContent()
+	})
+	router.GET("/internal/metrics", func(ctx *http.Context) error {
+		return ctx.NoContent()
+	})
+	return app, &buf
+}
+
+func logEntries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestLoggerJSON(t *testing.T) {
+	app, buf := newAccessLogApp(t, LoggerConfig{})
+
+	req := httptest.NewRequest("GET", "/users?page=2", nil)
+	req.Header.Set("User-Agent", "test-agent")
+	req.Header.Set("X-Request-ID", "req-1")
+	_, err := app.Test(req)
+	require.NoError(t, err)
+	_, err = app.Test(httptest.NewRequest("GET", "/fail", nil))
+	require.NoError(t, err)
+
+	entries := logEntries(t, buf)
+	require.Len(t, entries, 2)
+
+	entry := entries[0]
+	assert.Equal(t, "info", entry["level"])
+	assert.Equal(t, "HTTP Request", entry["message"])
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, "/users?page=2", entry["path"])
+	assert.Equal(t, float64(200), entry["status"])
+	assert.Equal(t, float64(5), entry["bytes"])
+	assert.Equal(t, "test-agent", entry["user_agent"])
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Contains(t, entry, "latency_ms")
+
+	assert.Equal(t, "error", entries[1]["level"])
+	assert.Equal(t, float64(500), entries[1]["status"])
+}
+
+func TestLoggerCombined(t *testing.T) {
+	app, buf := newAccessLogApp(t, LoggerConfig{Format: AccessLogCombined})
+
+	req := httptest.NewRequest("GET", "/users", nil)
+	req.Header.Set("User-Agent", "test-agent")
+	_, err := app.Test(req)
+	require.NoError(t, err)
+
+	entries := logEntries(t, buf)
+	require.Len(t, entries, 1)
+	assert.Regexp(t, `^0\.0\.0\.0 - - \[[^\]]+\] "GET /users HTTP/1\.1" 200 5 "-" "test-agent"$`, entries[0]["message"])
+	assert.NotEmpty(t, entries[0]["request_id"])
+}
+
+func TestLoggerSkip(t *testing.T) {
+	app, buf := newAccessLogApp(t, LoggerConfig{Skip: []string{"/health", "/internal/*"}})
+
+	for _, target := range []string{"/health", "/internal/metrics", "/users"} {
+		_, err := app.Test(httptest.NewRequest("GET", target, nil))
+		require.NoError(t, err)
+	}
+
+	entries := logEntries(t, buf)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "/users", entries[0]["path"])
+}
+
+func TestLoggerRejectsUnknownFormat(t *testing.T) {
+	assert.Panics(t, func() { LoggerWithConfig(LoggerConfig{Format: "xml"}) })
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/genesysflow/go-genesys/http"
+)
+
+// RegisterAliases registers the framework middleware under their names:
+//
+//	auth[:guard,...]       Auth
+//	jwt[:guard]            JWT
+//	throttle:attempts,period  Throttle, e.g. "throttle:60,1"
+//	csrf                   CSRF
+//	session                StartSession
+//	cors                   CORS
+//	request_id             RequestID
+//	log_context            LogContext
+//	secure                 Secure
+//	compress               Compress
+//	https                  HTTPSRedirect
+//	etag                   http.ETag
+//	maintenance            Maintenance
+//	timeout:duration       Timeout, e.g. "timeout:10s"
+//	audit                  Audit, configured by the app's audit config
+//
+// The route service provider registers them on every kernel.
+func RegisterAliases(registry *http.MiddlewareRegistry) {
+	registry.AliasFactory("auth", func(guards ...string) (http.MiddlewareFunc, error) {
+		return Auth(guards...), nil
+	})
+	registry.AliasFactory("jwt", func(params ...string) (http.MiddlewareFunc, error) {
+		if len(params) > 1 {
+			return nil, fmt.Errorf("middleware: jwt takes one guard, got %d", len(params))
+		}
+		return JWT(params...), nil
+	})
+	registry.AliasFactory("throttle", func(params ...string) (http.MiddlewareFunc, error) {
+		spec := strings.Join(params, ",")
+		if _, _, err := parseThrottle(spec); err != nil {
+			return nil, err
+		}
+		return Throttle(spec), nil
+	})
+	registry.AliasFactory("timeout", func(params ...string) (http.MiddlewareFunc, error) {
+		if len(params) != 1 {
+			return nil, fmt.Errorf("middleware: timeout takes a duration")
+		}
+		timeout, err := time.ParseDuration(params[0])
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("middleware: invalid timeout %q", params[0])
+		}
+		return Timeout(timeout), nil
+	})
+
+	registry.Alias("csrf", CSRF())
+	registry.Alias("session", StartSession())
+	registry.Alias("cors", CORS())
+	registry.Alias("request_id", RequestID())
+	registry.Alias("log_context", LogContext())
+	registry.Alias("secure", Secure())
+	registry.Alias("compress", Compress())
+	registry.Alias("https", HTTPSRedirect())
+	registry.Alias("etag", http.ETag())
+	registry.Alias("maintenance", Maintenance())
+	registry.Alias("audit", Audit())
+}
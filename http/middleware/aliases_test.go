@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterAliases(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(nil, app)
+	RegisterAliases(router.MiddlewareRegistry())
+	router.MiddlewareRegistry().Group("api", "request_id", "throttle:1,1")
+
+	router.GET("/ping", func(ctx *http.Context) error {
+		return ctx.String("pong")
+	}).MiddlewareNamed("api")
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/ping", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("X-RateLimit-Limit"))
+	assert.NotEmpty(t, resp.Header.Get("X-Request-ID"))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/ping", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+
+	for _, name := range []string{"throttle:x", "timeout", "timeout:soon", "jwt:a,b", "csrf:1"} {
+		_, err := router.MiddlewareRegistry().Resolve(name)
+		assert.Error(t, err, name)
+	}
+	_, err = router.MiddlewareRegistry().Resolve("auth:web,api", "timeout:5s", "secure")
+	assert.NoError(t, err)
+}
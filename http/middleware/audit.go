@@ -0,0 +1,271 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/genesysflow/go-genesys/errors"
+	"github.com/genesysflow/go-genesys/http"
+)
+
+// Redacted replaces the values of redacted fields in audit records.
+const Redacted = "[REDACTED]"
+
+// DefaultAuditMaxBodySize is the largest body, in bytes, Audit records.
+const DefaultAuditMaxBodySize = 64 << 10
+
+// DefaultAuditRedact lists the fields Audit redacts without Redact.
+var DefaultAuditRedact = []string{
+	"password", "password_confirmation", "current_password",
+	"token", "access_token", "refresh_token", "secret", "api_key",
+	"card_number", "cvv",
+}
+
+// AuditRecord is what Audit records about a request.
+type AuditRecord struct {
+	Time      time.Time
+	Method    string
+	Path      string
+	Query     map[string]any
+	Status    int
+	Duration  time.Duration
+	IP        string
+	UserAgent string
+	RequestID string
+
+	// UserID identifies the authenticated user, or is nil for guests.
+	UserID any
+
+	// RequestBody and ResponseBody are the decoded JSON or form bodies with
+	// fields redacted. Other bodies, and bodies that are omitted or too
+	// large, are recorded as a description such as "[2048 bytes]", or nil
+	// when empty.
+	RequestBody  any
+	ResponseBody any
+}
+
+// AuditSink stores audit records.
+type AuditSink interface {
+	Record(ctx context.Context, record AuditRecord) error
+}
+
+// AuditConfig configures Audit.
+type AuditConfig struct {
+	// Sink stores the records. Nil uses the sink the app's audit config
+	// names: audit.sink "database" (the default) inserts into audit.table
+	// on audit.connection, and "log" writes to the audit.channel channel.
+	// The other fields fall back to the audit config too.
+	Sink AuditSink
+
+	// Methods lists the methods recorded, such as only writes. Empty
+	// records every method.
+	Methods []string
+
+	// Skip lists paths that are not recorded. Patterns are matched as
+	// LoggerConfig.Skip is.
+	Skip []string
+
+	// Redact lists the fields whose values are replaced with Redacted in
+	// query strings and bodies. A name matches the field at any depth,
+	// case-insensitively; a dotted path such as "card.number" or
+	// "items.*.secret" matches from the top of the body. Defaults to
+	// DefaultAuditRedact.
+	Redact []string
+
+	// OmitBodies lists paths whose bodies are not recorded at all, such
+	// as login or payment endpoints.
+	OmitBodies []string
+
+	// RecordResponse records response bodies too.
+	RecordResponse bool
+
+	// MaxBodySize is the largest body recorded. Defaults to
+	// DefaultAuditMaxBodySize.
+	MaxBodySize int
+}
+
+// Audit records every request, after it is handled, to an audit sink:
+// its method, path, query, status, duration, client, request ID, the
+// authenticated user and the sanitized body. Place it after the auth
+// middleware so the user is known. A sink error is logged and doesn't
+// fail the request.
+func Audit(config ...AuditConfig) http.MiddlewareFunc {
+	var cfg AuditConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	var (
+		once sync.Once
+		err  error
+	)
+	return func(ctx *http.Context, next func() error) error {
+		once.Do(func() {
+			cfg, err = resolveAuditConfig(ctx.App(), cfg)
+		})
+		if err != nil {
+			return err
+		}
+		if skipPath(cfg.Skip, ctx.Path()) || !auditMethod(cfg.Methods, ctx.Method()) {
+			return next()
+		}
+
+		start := time.Now()
+		handlerErr := next()
+		record := newAuditRecord(ctx, cfg, handlerErr, time.Since(start))
+
+		if err := cfg.Sink.Record(ctx.FiberCtx().UserContext(), record); err != nil {
+			if logger := ctx.Logger(); logger != nil {
+				logger.Error("Failed to record audit entry", "error", err.Error(), "path", record.Path)
+			}
+		}
+		return handlerErr
+	}
+}
+
+func auditMethod(methods []string, method string) bool {
+	if len(methods) == 0 {
+		return true
+	}
+	for _, m := range methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func newAuditRecord(ctx *http.Context, cfg AuditConfig, err error, duration time.Duration) AuditRecord {
+	c := ctx.FiberCtx()
+	status := c.Response().StatusCode()
+	if err != nil {
+		// The error handler writes the response after the middleware returns.
+		status, _ = errors.Status(err)
+	}
+
+	// Fiber reuses the request's buffers, and sinks may keep the record.
+	record := AuditRecord{
+		Time:      time.Now(),
+		Method:    strings.Clone(ctx.Method()),
+		Path:      strings.Clone(ctx.Path()),
+		Status:    status,
+		Duration:  duration,
+		IP:        strings.Clone(ctx.IP()),
+		UserAgent: strings.Clone(c.Get("User-Agent")),
+		RequestID: strings.Clone(ctx.RequestID()),
+	}
+	if query, _ := url.ParseQuery(string(c.Request().URI().QueryString())); len(query) > 0 {
+		record.Query = redact(formValues(query), "", cfg.Redact).(map[string]any)
+	}
+	if user := ctx.User(); user != nil {
+		record.UserID = user.GetAuthIdentifier()
+	}
+
+	if skipPath(cfg.OmitBodies, record.Path) {
+		return record
+	}
+	record.RequestBody = auditBody(c.Body(), c.Get("Content-Type"), cfg)
+	if cfg.RecordResponse && err == nil && !c.Response().IsBodyStream() {
+		record.ResponseBody = auditBody(c.Response().Body(), string(c.Response().Header.ContentType()), cfg)
+	}
+	return record
+}
+
+// auditBody decodes a JSON or form body and redacts it, or describes a
+// body it can't sanitize.
+func auditBody(body []byte, contentType string, cfg AuditConfig) any {
+	if len(body) == 0 {
+		return nil
+	}
+	if len(body) > cfg.MaxBodySize {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var decoded any
+		if err := json.Unmarshal(body, &decoded); err == nil {
+			return redact(decoded, "", cfg.Redact)
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			return redact(formValues(form), "", cfg.Redact)
+		}
+	}
+	return fmt.Sprintf("[%d bytes]", len(body))
+}
+
+// formValues converts url.Values to a map, with single values as strings.
+func formValues(v url.Values) map[string]any {
+	m := make(map[string]any, len(v))
+	for key, list := range v {
+		if len(list) == 1 {
+			m[key] = list[0]
+			continue
+		}
+		items := make([]any, len(list))
+		for i, item := range list {
+			items[i] = item
+		}
+		m[key] = items
+	}
+	return m
+}
+
+// redact returns value with the fields matching rules replaced, where
+// prefix is the dotted path of value in the body.
+func redact(value any, prefix string, rules []string) any {
+	switch node := value.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(node))
+		for key, child := range node {
+			fieldPath := joinField(prefix, key)
+			if redactField(key, fieldPath, rules) {
+				redacted[key] = Redacted
+				continue
+			}
+			redacted[key] = redact(child, fieldPath, rules)
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(node))
+		for i, child := range node {
+			redacted[i] = redact(child, joinField(prefix, strconv.Itoa(i)), rules)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+func redactField(key, fieldPath string, rules []string) bool {
+	for _, rule := range rules {
+		if !strings.Contains(rule, ".") {
+			if strings.EqualFold(rule, key) {
+				return true
+			}
+			continue
+		}
+		pattern := strings.ReplaceAll(strings.ToLower(rule), ".", "/")
+		if matched, _ := path.Match(pattern, strings.ReplaceAll(strings.ToLower(fieldPath), ".", "/")); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func joinField(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
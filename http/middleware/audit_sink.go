@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/genesysflow/go-genesys/log"
+)
+
+// DatabaseAuditSink inserts audit records into a table with method, path,
+// query, status, duration_ms, ip, user_agent, request_id, user_id,
+// request_body, response_body and created_at columns. Generate its
+// migration with `audit:table`. Query and bodies are stored as JSON.
+type DatabaseAuditSink struct {
+	conn    contracts.Connection
+	dialect database.Dialect
+	table   string
+}
+
+// NewDatabaseAuditSink creates a database audit sink. The connection's
+// table prefix is applied to table, which defaults to "audit_logs".
+func NewDatabaseAuditSink(conn contracts.Connection, table string) *DatabaseAuditSink {
+	if table == "" {
+		table = "audit_logs"
+	}
+	return &DatabaseAuditSink{conn: conn, dialect: database.NewDialect(conn.Driver()), table: conn.Prefix() + table}
+}
+
+// Record inserts a record.
+func (s *DatabaseAuditSink) Record(ctx context.Context, record AuditRecord) error {
+	query, err := nullableJSON(record.Query)
+	if err != nil {
+		return err
+	}
+	requestBody, err := nullableJSON(record.RequestBody)
+	if err != nil {
+		return err
+	}
+	responseBody, err := nullableJSON(record.ResponseBody)
+	if err != nil {
+		return err
+	}
+	var userID any
+	if record.UserID != nil {
+		userID = fmt.Sprint(record.UserID)
+	}
+
+	placeholders := make([]any, 12)
+	for i := range placeholders {
+		placeholders[i] = s.dialect.Placeholder(i + 1)
+	}
+	statement := fmt.Sprintf(`INSERT INTO %s (method, path, query, status, duration_ms, ip, user_agent, request_id, user_id, request_body, response_body, created_at)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)`, append([]any{s.dialect.Quote(s.table)}, placeholders...)...)
+
+	_, err = s.conn.ExecContext(ctx, statement,
+		record.Method, record.Path, query, record.Status, record.Duration.Milliseconds(),
+		record.IP, record.UserAgent, record.RequestID, userID,
+		requestBody, responseBody, record.Time.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("middleware: failed to insert audit record: %w", err)
+	}
+	return nil
+}
+
+// nullableJSON encodes value as JSON, or returns nil for a nil value.
+func nullableJSON(value any) (any, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		if v == nil {
+			return nil, nil
+		}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("middleware: failed to encode audit record: %w", err)
+	}
+	return string(data), nil
+}
+
+// LogAuditSink writes audit records as "HTTP Audit" entries to a logger,
+// such as a dedicated log channel.
+type LogAuditSink struct {
+	logger contracts.Logger
+}
+
+// NewLogAuditSink creates a log audit sink.
+func NewLogAuditSink(logger contracts.Logger) *LogAuditSink {
+	return &LogAuditSink{logger: logger}
+}
+
+// Record logs a record.
+func (s *LogAuditSink) Record(ctx context.Context, record AuditRecord) error {
+	fields := []any{
+		"method", record.Method,
+		"path", record.Path,
+		"status", record.Status,
+		"duration_ms", record.Duration.Milliseconds(),
+		"ip", record.IP,
+		"user_agent", record.UserAgent,
+		"request_id", record.RequestID,
+	}
+	if record.UserID != nil {
+		fields = append(fields, "user_id", record.UserID)
+	}
+	if record.Query != nil {
+		fields = append(fields, "query", record.Query)
+	}
+	if record.RequestBody != nil {
+		fields = append(fields, "request_body", record.RequestBody)
+	}
+	if record.ResponseBody != nil {
+		fields = append(fields, "response_body", record.ResponseBody)
+	}
+	s.logger.Info("HTTP Audit", fields...)
+	return nil
+}
+
+// resolveAuditConfig fills the unset fields of cfg from the app's audit
+// config: sink, connection, table, channel, methods, skip, redact,
+// omit_bodies, record_response and max_body_size.
+func resolveAuditConfig(app contracts.Application, cfg AuditConfig) (AuditConfig, error) {
+	var settings contracts.Config
+	if app != nil {
+		settings = app.GetConfig()
+	}
+	setting := func(key string) []string {
+		if settings == nil {
+			return nil
+		}
+		return settings.GetStringSlice("audit." + key)
+	}
+
+	if cfg.Methods == nil {
+		cfg.Methods = setting("methods")
+	}
+	if cfg.Skip == nil {
+		cfg.Skip = setting("skip")
+	}
+	if cfg.Redact == nil {
+		cfg.Redact = setting("redact")
+	}
+	if cfg.Redact == nil {
+		cfg.Redact = DefaultAuditRedact
+	}
+	if cfg.OmitBodies == nil {
+		cfg.OmitBodies = setting("omit_bodies")
+	}
+	if settings != nil {
+		cfg.RecordResponse = cfg.RecordResponse || settings.GetBool("audit.record_response")
+		if cfg.MaxBodySize == 0 {
+			cfg.MaxBodySize = settings.GetInt("audit.max_body_size")
+		}
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = DefaultAuditMaxBodySize
+	}
+	if cfg.Sink != nil {
+		return cfg, nil
+	}
+
+	if app == nil {
+		return cfg, fmt.Errorf("middleware: audit needs a sink")
+	}
+	switch sink := settings.GetString("audit.sink"); sink {
+	case "", "database":
+		manager, err := container.Resolve[*database.Manager](app)
+		if err != nil {
+			return cfg, fmt.Errorf("middleware: audit database sink needs a database: %w", err)
+		}
+		conn := manager.Connection(settings.GetString("audit.connection"))
+		if err := conn.Error(); err != nil {
+			return cfg, fmt.Errorf("middleware: audit database sink: %w", err)
+		}
+		cfg.Sink = NewDatabaseAuditSink(conn, settings.GetString("audit.table"))
+	case "log":
+		logger := app.GetLogger()
+		if channel := settings.GetString("audit.channel"); channel != "" {
+			manager, err := container.Resolve[*log.LogManager](app)
+			if err != nil {
+				return cfg, fmt.Errorf("middleware: audit log sink needs the log manager: %w", err)
+			}
+			logger = manager.Channel(channel)
+		}
+		cfg.Sink = NewLogAuditSink(logger)
+	default:
+		return cfg, fmt.Errorf("middleware: unknown audit sink %q", sink)
+	}
+	return cfg, nil
+}
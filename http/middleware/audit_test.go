@@ -0,0 +1,231 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/auth"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/genesysflow/go-genesys/database/schema"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/log"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "modernc.org/sqlite"
+)
+
+// recordingSink keeps the records in memory.
+type recordingSink struct {
+	records []AuditRecord
+	err     error
+}
+
+func (s *recordingSink) Record(ctx context.Context, record AuditRecord) error {
+	s.records = append(s.records, record)
+	return s.err
+}
+
+func newAuditApp(container contracts.Application, config AuditConfig) *fiber.App {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(container, app)
+	router.Use(func(ctx *http.Context, next func() error) error {
+		if ctx.Query("as") != "" {
+			ctx.Set("user", auth.GenericUser{"id": ctx.Query("as")})
+		}
+		return next()
+	}, Audit(config))
+	router.POST("/users", func(ctx *http.Context) error {
+		return ctx.Status(201).JSONResponse(fiber.Map{"id": 1, "token": "issued"})
+	})
+	router.POST("/login", func(ctx *http.Context) error {
+		return ctx.NoContent()
+	})
+	router.GET("/health", func(ctx *http.Context) error {
+		return ctx.NoContent()
+	})
+	router.GET("/users", func(ctx *http.Context) error {
+		return ctx.String("list")
+	})
+	router.DELETE("/users/:id", func(ctx *http.Context) error {
+		return fiber.NewError(fiber.StatusForbidden, "forbidden")
+	})
+	return app
+}
+
+func sendAudit(t *testing.T, app *fiber.App, method, target, contentType, body string) {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("User-Agent", "test-agent")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	io.Copy(io.Discard, resp.Body)
+}
+
+func TestAuditRecordsRequests(t *testing.T) {
+	sink := &recordingSink{}
+	app := newAuditApp(nil, AuditConfig{
+		Sink:           sink,
+		Redact:         []string{"password", "api_key", "card.number", "items.*.secret", "token"},
+		RecordResponse: true,
+	})
+
+	sendAudit(t, app, "POST", "/users?api_key=abc&page=2&as=7", "application/json",
+		`{"name":"Ada","Password":"hunter2","card":{"number":"4242","brand":"visa"},"number":"1","items":[{"secret":"x","id":1}]}`)
+
+	require.Len(t, sink.records, 1)
+	record := sink.records[0]
+	assert.Equal(t, "POST", record.Method)
+	assert.Equal(t, "/users", record.Path)
+	assert.Equal(t, 201, record.Status)
+	assert.Equal(t, "test-agent", record.UserAgent)
+	assert.Equal(t, "7", record.UserID)
+	assert.False(t, record.Time.IsZero())
+	assert.Equal(t, map[string]any{"api_key": Redacted, "page": "2", "as": "7"}, record.Query)
+	assert.Equal(t, map[string]any{
+		"name":     "Ada",
+		"Password": Redacted,
+		"card":     map[string]any{"number": Redacted, "brand": "visa"},
+		"number":   "1",
+		"items":    []any{map[string]any{"secret": Redacted, "id": float64(1)}},
+	}, record.RequestBody)
+	assert.Equal(t, map[string]any{"id": float64(1), "token": Redacted}, record.ResponseBody)
+}
+
+func TestAuditRules(t *testing.T) {
+	sink := &recordingSink{}
+	app := newAuditApp(nil, AuditConfig{
+		Sink:        sink,
+		Methods:     []string{"post", "DELETE"},
+		Skip:        []string{"/health"},
+		OmitBodies:  []string{"/login"},
+		MaxBodySize: 64,
+	})
+
+	sendAudit(t, app, "GET", "/users", "", "")
+	sendAudit(t, app, "POST", "/users", "application/x-www-form-urlencoded", "name=Ada&password=secret&tag=a&tag=b")
+	sendAudit(t, app, "POST", "/login", "application/json", `{"email":"ada@example.com"}`)
+	sendAudit(t, app, "POST", "/users", "application/json", `{"bio":"`+strings.Repeat("x", 100)+`"}`)
+	sendAudit(t, app, "POST", "/users", "text/plain", "hello")
+	sendAudit(t, app, "DELETE", "/users/1", "", "")
+
+	require.Len(t, sink.records, 5)
+	assert.Equal(t, map[string]any{"name": "Ada", "password": Redacted, "tag": []any{"a", "b"}}, sink.records[0].RequestBody)
+	assert.Equal(t, "/login", sink.records[1].Path)
+	assert.Nil(t, sink.records[1].RequestBody)
+	assert.Equal(t, "[110 bytes]", sink.records[2].RequestBody)
+	assert.Equal(t, "[5 bytes]", sink.records[3].RequestBody)
+	assert.Nil(t, sink.records[0].ResponseBody)
+	assert.Equal(t, 403, sink.records[4].Status)
+	assert.Nil(t, sink.records[4].UserID)
+}
+
+func TestAuditSinkErrorsDontFailRequests(t *testing.T) {
+	sink := &recordingSink{err: errors.New("disk full")}
+	app := newAuditApp(nil, AuditConfig{Sink: sink})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Len(t, sink.records, 1)
+}
+
+func TestAuditDatabaseSinkFromConfig(t *testing.T) {
+	td, cleanup := testutil.SetupDatabaseFor(t, "pgsql", "sqlite")
+	defer cleanup()
+	manager := database.NewManager(database.Config{
+		Default: "default",
+		Connections: map[string]database.ConnectionConfig{
+			"default": {
+				Driver:   td.Driver,
+				Host:     td.Host,
+				Port:     td.Port,
+				Database: td.Database,
+				Username: td.Username,
+				Password: td.Password,
+				SSLMode:  "disable",
+			},
+		},
+	})
+	defer manager.Close()
+	conn := manager.Connection()
+	err := schema.NewBuilder(conn.DB(), conn.Driver()).Create("audits", func(table *schema.Blueprint) {
+		table.ID()
+		table.String("method", 10)
+		table.String("path", 2048)
+		table.Text("query").Nullable()
+		table.Integer("status")
+		table.BigInteger("duration_ms")
+		table.String("ip", 45)
+		table.String("user_agent", 512)
+		table.String("request_id", 64)
+		table.String("user_id", 255).Nullable()
+		table.Text("request_body").Nullable()
+		table.Text("response_body").Nullable()
+		table.Timestamp("created_at")
+	})
+	require.NoError(t, err)
+
+	container := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"audit.table":  "audits",
+		"audit.redact": []string{"secret"},
+	}))
+	container.InstanceType(manager)
+	app := newAuditApp(container, AuditConfig{})
+
+	sendAudit(t, app, "POST", "/users?as=ada", "application/json", `{"name":"Ada","secret":"x"}`)
+	sendAudit(t, app, "GET", "/health", "", "")
+
+	var count int
+	require.NoError(t, conn.QueryRow("SELECT COUNT(*) FROM audits").Scan(&count))
+	assert.Equal(t, 2, count)
+
+	var method, path, userID, body string
+	var status int
+	err = conn.QueryRow("SELECT method, path, status, user_id, request_body FROM audits ORDER BY id LIMIT 1").
+		Scan(&method, &path, &status, &userID, &body)
+	require.NoError(t, err)
+	assert.Equal(t, "POST", method)
+	assert.Equal(t, "/users", path)
+	assert.Equal(t, 201, status)
+	assert.Equal(t, "ada", userID)
+	assert.JSONEq(t, `{"name":"Ada","secret":"[REDACTED]"}`, body)
+}
+
+func TestAuditLogSink(t *testing.T) {
+	var buf bytes.Buffer
+	app := newAuditApp(nil, AuditConfig{Sink: NewLogAuditSink(log.NewJSON(&buf))})
+
+	sendAudit(t, app, "POST", "/users?as=7", "application/json", `{"password":"secret"}`)
+
+	entries := logEntries(t, &buf)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, "HTTP Audit", entry["message"])
+	assert.Equal(t, "POST", entry["method"])
+	assert.Equal(t, float64(201), entry["status"])
+	assert.Equal(t, "7", entry["user_id"])
+	assert.Equal(t, map[string]any{"password": Redacted}, entry["request_body"])
+}
+
+func TestAuditUnknownSink(t *testing.T) {
+	container := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"audit.sink": "kafka",
+	}))
+	app := newAuditApp(container, AuditConfig{})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+}
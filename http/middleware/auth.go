@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/genesysflow/go-genesys/auth"
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/http"
+)
+
+// Auth aborts with 401 unless one of the guards authenticates the request.
+// Without guard names the default guard is used. The first guard with a
+// user becomes the request's default guard, and the user is stored under
+// the "user" key for ctx.User() and other middleware.
+func Auth(guards ...string) http.MiddlewareFunc {
+	return func(ctx *http.Context, next func() error) error {
+		manager, err := container.Resolve[*auth.Manager](ctx.App())
+		if err != nil {
+			return fmt.Errorf("middleware: auth manager not available: %w", err)
+		}
+
+		names := guards
+		if len(names) == 0 {
+			names = []string{manager.DefaultGuard()}
+		}
+		for _, name := range names {
+			guard, err := manager.Guard(ctx.FiberCtx(), name)
+			if err != nil {
+				return err
+			}
+			user, err := guard.User()
+			if err != nil {
+				return err
+			}
+			if user != nil {
+				manager.ShouldUse(ctx.FiberCtx(), name)
+				ctx.Set("user", user)
+				return next()
+			}
+		}
+
+		return ctx.Unauthorized("Unauthenticated")
+	}
+}
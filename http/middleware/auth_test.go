@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/auth"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenUsers finds users by their api_token.
+type tokenUsers map[string]auth.GenericUser
+
+func (u tokenUsers) RetrieveByID(ctx context.Context, id any) (contracts.Authenticatable, error) {
+	return nil, nil
+}
+
+func (u tokenUsers) RetrieveByCredentials(ctx context.Context, credentials map[string]any) (contracts.Authenticatable, error) {
+	if user, ok := u[fmt.Sprint(credentials["api_token"])]; ok {
+		return user, nil
+	}
+	return nil, nil
+}
+
+func (u tokenUsers) ValidateCredentials(user contracts.Authenticatable, credentials map[string]any) bool {
+	return false
+}
+
+func newAuthApp() *fiber.App {
+	manager := auth.NewManager()
+	manager.RegisterProvider("users", tokenUsers{"secret": {"id": 1, "name": "Ada"}})
+	container := testutil.NewMockApplication()
+	container.InstanceType(manager)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(container, app)
+	router.GET("/me", func(ctx *http.Context) error {
+		user := ctx.User().(auth.GenericUser)
+		return ctx.String(fmt.Sprintf("%v %v", user["name"], ctx.Get("user") != nil))
+	}).Middleware(Auth("api"))
+	router.GET("/guest", func(ctx *http.Context) error {
+		return ctx.String(fmt.Sprint(ctx.User() == nil, ctx.Auth("api").Guest()))
+	})
+	return app
+}
+
+func TestAuthRejectsGuests(t *testing.T) {
+	resp, err := newAuthApp().Test(httptest.NewRequest("GET", "/me", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestAuthSwitchesDefaultGuard(t *testing.T) {
+	req := httptest.NewRequest("GET", "/me", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := newAuthApp().Test(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "Ada true", string(body))
+}
+
+func TestContextUserForGuests(t *testing.T) {
+	resp, err := newAuthApp().Test(httptest.NewRequest("GET", "/guest", nil))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "true true", string(body))
+}
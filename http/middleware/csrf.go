@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CSRFConfig configures the CSRF middleware.
+type CSRFConfig struct {
+	// CookieName is the cookie holding the token. It is readable by scripts
+	// so SPAs can echo it in the X-XSRF-TOKEN header. Defaults to "XSRF-TOKEN".
+	CookieName string
+
+	// FormField is the form field checked for the token. Defaults to "_token".
+	FormField string
+
+	// Except lists paths that are not checked. A "*" matches any run of
+	// characters, e.g. "/webhooks/*".
+	Except []string
+
+	// Expiration is the token cookie lifetime. Defaults to 12 hours.
+	Expiration time.Duration
+
+	CookieDomain   string
+	CookieSecure   bool
+	CookieSameSite string
+}
+
+// DefaultCSRFConfig is the default CSRF configuration.
+var DefaultCSRFConfig = CSRFConfig{
+	CookieName:     "XSRF-TOKEN",
+	FormField:      "_token",
+	Expiration:     12 * time.Hour,
+	CookieSameSite: fiber.CookieSameSiteLaxMode,
+}
+
+// csrfHeaders are the request headers checked for the token.
+var csrfHeaders = []string{"X-CSRF-TOKEN", "X-XSRF-TOKEN"}
+
+// CSRF protects against cross-site request forgery with double-submit
+// tokens. Each client gets a random token in a cookie; requests other than
+// GET, HEAD, OPTIONS and TRACE must send it back in the X-CSRF-TOKEN or
+// X-XSRF-TOKEN header or the "_token" form field, or get 403. The token is
+// available to handlers through ctx.CsrfToken.
+func CSRF(config ...CSRFConfig) http.MiddlewareFunc {
+	cfg := DefaultCSRFConfig
+	if len(config) > 0 {
+		cfg = config[0]
+		if cfg.CookieName == "" {
+			cfg.CookieName = DefaultCSRFConfig.CookieName
+		}
+		if cfg.FormField == "" {
+			cfg.FormField = DefaultCSRFConfig.FormField
+		}
+		if cfg.Expiration <= 0 {
+			cfg.Expiration = DefaultCSRFConfig.Expiration
+		}
+		if cfg.CookieSameSite == "" {
+			cfg.CookieSameSite = DefaultCSRFConfig.CookieSameSite
+		}
+	}
+
+	return func(ctx *http.Context, next func() error) error {
+		c := ctx.FiberCtx()
+
+		token := c.Cookies(cfg.CookieName)
+		valid := isCSRFToken(token)
+
+		if !isSafeMethod(c.Method()) && !csrfExempt(cfg.Except, c.Path()) {
+			if !valid || !tokenMatches(token, submittedCSRFToken(c, cfg.FormField)) {
+				return ctx.Forbidden("CSRF token mismatch")
+			}
+		}
+
+		if !valid {
+			token = newCSRFToken()
+			c.Cookie(&fiber.Cookie{
+				Name:     cfg.CookieName,
+				Value:    token,
+				Path:     "/",
+				Domain:   cfg.CookieDomain,
+				Expires:  time.Now().Add(cfg.Expiration),
+				Secure:   cfg.CookieSecure,
+				SameSite: cfg.CookieSameSite,
+			})
+		}
+		c.Locals(http.CsrfTokenKey, token)
+
+		return next()
+	}
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions, fiber.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// submittedCSRFToken returns the token sent in a header or form field.
+func submittedCSRFToken(c *fiber.Ctx, field string) string {
+	for _, header := range csrfHeaders {
+		if token := c.Get(header); token != "" {
+			return token
+		}
+	}
+	return c.FormValue(field)
+}
+
+func tokenMatches(expected, submitted string) bool {
+	return submitted != "" && subtle.ConstantTimeCompare([]byte(expected), []byte(submitted)) == 1
+}
+
+const csrfTokenBytes = 32
+
+func newCSRFToken() string {
+	b := make([]byte, csrfTokenBytes)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// isCSRFToken reports whether a cookie value looks like a token we issued.
+func isCSRFToken(token string) bool {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	return err == nil && len(b) == csrfTokenBytes
+}
+
+// csrfExempt reports whether path matches one of the exempt patterns.
+func csrfExempt(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if wildcardMatch(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardMatch matches s against a pattern where "*" matches any run of characters.
+func wildcardMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
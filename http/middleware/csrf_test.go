@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCSRFApp(config ...CSRFConfig) *fiber.App {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(nil, app)
+	router.Use(CSRF(config...))
+	router.GET("/form", func(ctx *http.Context) error {
+		return ctx.String(ctx.CsrfToken())
+	})
+	router.POST("/profile", func(ctx *http.Context) error {
+		return ctx.String("saved")
+	})
+	router.POST("/webhooks/stripe", func(ctx *http.Context) error {
+		return ctx.String("received")
+	})
+	return app
+}
+
+// csrfCookie fetches the form and returns the issued cookie and the token
+// rendered into the page.
+func csrfCookie(t *testing.T, app *fiber.App) (*nethttp.Cookie, string) {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest("GET", "/form", nil))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == "XSRF-TOKEN" {
+			return cookie, string(body)
+		}
+	}
+	t.Fatal("no XSRF-TOKEN cookie issued")
+	return nil, ""
+}
+
+func TestCSRFIssuesToken(t *testing.T) {
+	app := newCSRFApp()
+	cookie, token := csrfCookie(t, app)
+
+	assert.Equal(t, cookie.Value, token)
+	assert.False(t, cookie.HttpOnly)
+	assert.Equal(t, nethttp.SameSiteLaxMode, cookie.SameSite)
+
+	// An existing token is reused.
+	req := httptest.NewRequest("GET", "/form", nil)
+	req.AddCookie(cookie)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, token, string(body))
+	assert.Empty(t, resp.Cookies())
+}
+
+func TestCSRFValidatesToken(t *testing.T) {
+	app := newCSRFApp(CSRFConfig{Except: []string{"/webhooks/*"}})
+	cookie, token := csrfCookie(t, app)
+
+	post := func(body, header string, withCookie bool) int {
+		req := httptest.NewRequest("POST", "/profile", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if header != "" {
+			req.Header.Set("X-XSRF-TOKEN", header)
+		}
+		if withCookie {
+			req.AddCookie(cookie)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusOK, post(url.Values{"_token": {token}}.Encode(), "", true))
+	assert.Equal(t, fiber.StatusOK, post("", token, true))
+	assert.Equal(t, fiber.StatusForbidden, post("", "", true))
+	assert.Equal(t, fiber.StatusForbidden, post("", "forged", true))
+	assert.Equal(t, fiber.StatusForbidden, post(url.Values{"_token": {token}}.Encode(), "", false))
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/webhooks/stripe", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestWildcardMatch(t *testing.T) {
+	for _, tt := range []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"/webhooks/*", "/webhooks/stripe", true},
+		{"/webhooks/*", "/webhooks", false},
+		{"/api/*/callback", "/api/v1/payments/callback", true},
+		{"/api/*/callback", "/api/v1/payments", false},
+		{"/login", "/login", true},
+		{"/login", "/login/2fa", false},
+	} {
+		assert.Equal(t, tt.want, wildcardMatch(tt.pattern, tt.path), "%s ~ %s", tt.pattern, tt.path)
+	}
+}
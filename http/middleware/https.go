@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net"
+
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HTTPSRedirectConfig configures HTTPSRedirect.
+type HTTPSRedirectConfig struct {
+	// Port is the HTTPS port redirects point at. Empty or "443" leaves it
+	// out of the URL.
+	Port string
+
+	// Except lists paths still served over plain HTTP, such as health
+	// checks. Patterns are matched as LoggerConfig.Skip is.
+	Except []string
+}
+
+// HTTPSRedirect redirects plain HTTP requests to HTTPS: GET and HEAD with
+// 301, other methods with 308 so they are repeated with their body. Behind
+// a TLS-terminating proxy, the scheme comes from X-Forwarded-Proto.
+func HTTPSRedirect(config ...HTTPSRedirectConfig) http.MiddlewareFunc {
+	var cfg HTTPSRedirectConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	return func(ctx *http.Context, next func() error) error {
+		c := ctx.FiberCtx()
+		if c.Protocol() == "https" || skipPath(cfg.Except, ctx.Path()) {
+			return next()
+		}
+
+		host, _, err := net.SplitHostPort(c.Hostname())
+		if err != nil {
+			host = c.Hostname()
+		}
+		if cfg.Port != "" && cfg.Port != "443" {
+			host = net.JoinHostPort(host, cfg.Port)
+		}
+
+		status := fiber.StatusMovedPermanently
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			status = fiber.StatusPermanentRedirect
+		}
+		return c.Redirect("https://"+host+string(c.Request().URI().RequestURI()), status)
+	}
+}
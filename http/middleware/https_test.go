@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSRedirect(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(nil, app)
+	router.Use(HTTPSRedirect(HTTPSRedirectConfig{Port: "8443", Except: []string{"/health"}}))
+	handler := func(ctx *http.Context) error { return ctx.String("ok") }
+	router.GET("/orders", handler)
+	router.POST("/orders", handler)
+	router.GET("/health", handler)
+
+	tests := []struct {
+		method, path, proto string
+		status              int
+		location            string
+	}{
+		{"GET", "/orders?page=2", "", fiber.StatusMovedPermanently, "https://example.com:8443/orders?page=2"},
+		{"POST", "/orders", "", fiber.StatusPermanentRedirect, "https://example.com:8443/orders"},
+		{"GET", "/orders", "https", fiber.StatusOK, ""},
+		{"GET", "/health", "", fiber.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "http://example.com"+tt.path, nil)
+		if tt.proto != "" {
+			req.Header.Set("X-Forwarded-Proto", tt.proto)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, tt.status, resp.StatusCode, tt.method+" "+tt.path)
+		assert.Equal(t, tt.location, resp.Header.Get("Location"))
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/genesysflow/go-genesys/auth"
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/http"
+)
+
+// JWT aborts with 401 unless the request's Authorization header carries a
+// valid access token for a jwt guard. Without a guard name the default
+// guard is used. The guard becomes the request's default guard and the
+// user is stored under the "user" key.
+func JWT(guard ...string) http.MiddlewareFunc {
+	return func(ctx *http.Context, next func() error) error {
+		manager, err := container.Resolve[*auth.Manager](ctx.App())
+		if err != nil {
+			return fmt.Errorf("middleware: auth manager not available: %w", err)
+		}
+
+		name := manager.DefaultGuard()
+		if len(guard) > 0 && guard[0] != "" {
+			name = guard[0]
+		}
+		g, err := manager.Guard(ctx.FiberCtx(), name)
+		if err != nil {
+			return err
+		}
+		if _, ok := g.(*auth.JWTGuard); !ok {
+			return fmt.Errorf("middleware: guard %q does not use the jwt driver", name)
+		}
+
+		user, err := g.User()
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			ctx.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			return ctx.Unauthorized("Unauthenticated")
+		}
+
+		manager.ShouldUse(ctx.FiberCtx(), name)
+		ctx.Set("user", user)
+		return next()
+	}
+}
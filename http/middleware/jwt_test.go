@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/auth"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// idUsers finds users by ID.
+type idUsers map[int64]auth.GenericUser
+
+func (u idUsers) RetrieveByID(ctx context.Context, id any) (contracts.Authenticatable, error) {
+	if n, ok := id.(int64); ok {
+		if user, ok := u[n]; ok {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (u idUsers) RetrieveByCredentials(ctx context.Context, credentials map[string]any) (contracts.Authenticatable, error) {
+	return nil, nil
+}
+
+func (u idUsers) ValidateCredentials(user contracts.Authenticatable, credentials map[string]any) bool {
+	return false
+}
+
+func newJWTApp(t *testing.T) (*fiber.App, *auth.Manager) {
+	t.Helper()
+
+	manager := auth.NewManager(auth.Config{
+		Default: "web",
+		Guards: map[string]auth.GuardConfig{
+			"web": {Driver: "session", Provider: "users"},
+			"api": {Driver: "jwt", Provider: "users", JWT: auth.JWTConfig{Secret: []byte("0123456789abcdef0123456789abcdef")}},
+		},
+	})
+	manager.RegisterProvider("users", idUsers{1: {"id": int64(1), "name": "Ada"}})
+	container := testutil.NewMockApplication()
+	container.InstanceType(manager)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(container, app)
+	router.GET("/me", func(ctx *http.Context) error {
+		return ctx.String(ctx.User().(auth.GenericUser)["name"].(string))
+	}).Middleware(JWT("api"))
+	router.GET("/web", func(ctx *http.Context) error {
+		return ctx.String("ok")
+	}).Middleware(JWT())
+	return app, manager
+}
+
+func TestJWTRejectsMissingAndInvalidTokens(t *testing.T) {
+	app, _ := newJWTApp(t)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/me", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("WWW-Authenticate"), "Bearer")
+
+	req := httptest.NewRequest("GET", "/me", nil)
+	req.Header.Set("Authorization", "Bearer a.b.c")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestJWTAuthenticatesAccessToken(t *testing.T) {
+	app, manager := newJWTApp(t)
+	issuer, err := manager.JWT("api")
+	require.NoError(t, err)
+	tokens, err := issuer.Issue(auth.GenericUser{"id": int64(1)})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "Ada", string(body))
+
+	// Refresh tokens are not accepted as access tokens.
+	req = httptest.NewRequest("GET", "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.RefreshToken)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestJWTRequiresJWTGuard(t *testing.T) {
+	app, _ := newJWTApp(t)
+	resp, err := app.Test(httptest.NewRequest("GET", "/web", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+}
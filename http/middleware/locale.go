@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/lang"
+)
+
+// LocaleSessionKey is the session key the Locale middleware reads a user's
+// chosen locale from.
+const LocaleSessionKey = "locale"
+
+// Locale sets the request's locale, available through ctx.Locale() and used
+// by ctx.Trans and ctx.Validate. The locale stored in the session under
+// "locale" wins when the translator has lines for it; otherwise the best
+// match for the Accept-Language header among the translator's locales, and
+// finally the translator's default. The chosen locale is sent back in the
+// Content-Language header. The translator is resolved from the container
+// when none is given.
+func Locale(translator ...*lang.Translator) http.MiddlewareFunc {
+	var t *lang.Translator
+	if len(translator) > 0 {
+		t = translator[0]
+	}
+
+	return func(ctx *http.Context, next func() error) error {
+		trans := t
+		if trans == nil {
+			resolved, err := container.Resolve[*lang.Translator](ctx.App())
+			if err != nil {
+				return fmt.Errorf("middleware: translator not available: %w", err)
+			}
+			trans = resolved
+		}
+
+		available := trans.Locales()
+		locale := ""
+		if sess := ctx.Session(); sess != nil {
+			locale = matchLocale(sess.GetString(LocaleSessionKey), available)
+		}
+		if locale == "" {
+			for _, accepted := range acceptedLanguages(ctx.FiberCtx().Get("Accept-Language")) {
+				if locale = matchLocale(accepted, available); locale != "" {
+					break
+				}
+			}
+		}
+		if locale == "" {
+			locale = trans.Locale()
+		}
+
+		ctx.FiberCtx().Locals(http.LocaleKey, locale)
+		ctx.FiberCtx().Set("Content-Language", locale)
+		return next()
+	}
+}
+
+// matchLocale returns the available locale matching requested exactly, or
+// else by language: "en-US" matches "en", and "de" matches "de_AT".
+func matchLocale(requested string, available []string) string {
+	requested = normalizeLocale(requested)
+	if requested == "" {
+		return ""
+	}
+	language, _, _ := strings.Cut(requested, "-")
+	var partial string
+	for _, locale := range available {
+		normalized := normalizeLocale(locale)
+		if normalized == requested {
+			return locale
+		}
+		if partial == "" {
+			if candidate, _, _ := strings.Cut(normalized, "-"); candidate == language {
+				partial = locale
+			}
+		}
+	}
+	return partial
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// acceptedLanguages parses an Accept-Language header into its language
+// tags, most preferred first. Wildcards and tags with q=0 are left out.
+func acceptedLanguages(header string) []string {
+	type accepted struct {
+		tag     string
+		quality float64
+	}
+	var languages []accepted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > 0 {
+			languages = append(languages, accepted{tag, quality})
+		}
+	}
+	sort.SliceStable(languages, func(i, j int) bool { return languages[i].quality > languages[j].quality })
+
+	tags := make([]string, len(languages))
+	for i, language := range languages {
+		tags[i] = language.tag
+	}
+	return tags
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/lang"
+	"github.com/genesysflow/go-genesys/session"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTranslator() *lang.Translator {
+	translator := lang.New("en", "en")
+	translator.AddLines("en", map[string]string{"messages.welcome": "Welcome, :name!"})
+	translator.AddLines("de", map[string]string{"messages.welcome": "Willkommen, :name!"})
+	translator.AddLines("pt_BR", map[string]string{"messages.welcome": "Bem-vindo, :name!"})
+	return translator
+}
+
+func TestLocale(t *testing.T) {
+	container := testutil.NewMockApplication()
+	container.InstanceType(newTestTranslator())
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(container, app)
+	router.Use(StartSession(session.NewManager()), Locale())
+	router.GET("/", func(ctx *http.Context) error {
+		return ctx.String(ctx.Locale() + ": " + ctx.Trans("messages.welcome", map[string]any{"name": "Jane"}))
+	})
+	router.POST("/locale", func(ctx *http.Context) error {
+		return ctx.Session().Put(LocaleSessionKey, ctx.Query("locale"))
+	})
+
+	get := func(acceptLanguage string, cookies ...*nethttp.Cookie) string {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		assert.NotEmpty(t, resp.Header.Get("Content-Language"))
+		return string(body)
+	}
+
+	assert.Equal(t, "en: Welcome, Jane!", get(""))
+	assert.Equal(t, "de: Willkommen, Jane!", get("fr-CH, fr;q=0.9, de-DE;q=0.8, en;q=0.5"))
+	assert.Equal(t, "en: Welcome, Jane!", get("de;q=0, en-GB"))
+	assert.Equal(t, "pt_BR: Bem-vindo, Jane!", get("pt-br"))
+	assert.Equal(t, "en: Welcome, Jane!", get("*"))
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/locale?locale=de", nil))
+	require.NoError(t, err)
+	require.Len(t, resp.Cookies(), 1)
+	cookie := &nethttp.Cookie{Name: resp.Cookies()[0].Name, Value: resp.Cookies()[0].Value}
+	assert.Equal(t, "de: Willkommen, Jane!", get("en", cookie))
+}
+
+func TestLocaleWithoutTranslator(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(testutil.NewMockApplication(), app)
+	router.Use(Locale())
+	router.GET("/", func(ctx *http.Context) error { return ctx.String("ok") })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestAcceptedLanguages(t *testing.T) {
+	assert.Equal(t, []string{"da", "en-gb", "en"}, acceptedLanguages("da, en-gb;q=0.8, en;q=0.7"))
+	assert.Equal(t, []string{"en", "de"}, acceptedLanguages("de;q=0.5,en,*;q=0.1,fr;q=0"))
+	assert.Empty(t, acceptedLanguages(""))
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/log"
+)
+
+// LogContext seeds the request's logger with correlation fields: the
+// request ID (from RequestID, which must run first), the authenticated
+// user's ID as "user_id", and the route's name as "route". Every entry of
+// ctx.Logger() and of log.FromContext(ctx.Request().Context()) carries
+// them for the rest of the request, including in code that is only given
+// the context.Context.
+func LogContext() http.MiddlewareFunc {
+	return func(ctx *http.Context, next func() error) error {
+		fields := make(map[string]any, 3)
+		if id := ctx.RequestID(); id != "" {
+			fields[RequestIDKey] = id
+		}
+		if user := ctx.User(); user != nil {
+			fields["user_id"] = user.GetAuthIdentifier()
+		}
+		if route := ctx.CurrentRoute(); route != nil && route.GetName() != "" {
+			fields["route"] = route.GetName()
+		}
+
+		logger := ctx.Logger()
+		if logger == nil {
+			logger = log.Default()
+		}
+		logger = logger.WithFields(fields)
+		ctx.Set(http.LoggerKey, logger)
+		ctx.FiberCtx().Locals(http.LoggerKey, logger)
+		ctx.Request().WithContext(log.WithLogger(ctx.Request().Context(), logger))
+
+		return next()
+	}
+}
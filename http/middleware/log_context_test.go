@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/log"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogContext(t *testing.T) {
+	var buf bytes.Buffer
+	app := testutil.NewMockApplication()
+	app.SetLogger(log.NewJSON(&buf))
+
+	fiberApp := fiber.New()
+	router := http.NewRouter(app, fiberApp)
+	router.Use(RequestID(), func(ctx *http.Context, next func() error) error {
+		ctx.Set("user", throttleUser{id: 7})
+		return next()
+	}, LogContext())
+	router.GET("/orders", func(ctx *http.Context) error {
+		// Code given only the context.Context logs with the same fields.
+		log.FromContext(ctx.Request().Context()).Info("listing orders")
+		return ctx.NoContent()
+	}).Name("orders.index")
+
+	req := httptest.NewRequest("GET", "/orders", nil)
+	req.Header.Set("X-Request-ID", "upstream-42")
+	_, err := fiberApp.Test(req)
+	require.NoError(t, err)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "listing orders", entry["message"])
+	assert.Equal(t, "upstream-42", entry["request_id"])
+	assert.Equal(t, float64(7), entry["user_id"])
+	assert.Equal(t, "orders.index", entry["route"])
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strconv"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MaintenanceCookie is the cookie that lets a visitor past maintenance.
+const MaintenanceCookie = "genesys_maintenance"
+
+// MaintenanceConfig configures Maintenance.
+type MaintenanceConfig struct {
+	// StoragePath is the storage directory holding the maintenance file.
+	// Empty uses the app's storage path.
+	StoragePath string
+
+	// Except lists paths still served during maintenance, such as health
+	// checks. Patterns are matched as LoggerConfig.Skip is.
+	Except []string
+}
+
+// Maintenance answers requests with 503 Service Unavailable while the app
+// is down for maintenance (`genesys down`), with Retry-After when a retry
+// was given. The maintenance file is checked on every request, so
+// `genesys up` takes effect without a restart.
+//
+// When the app was put down with --secret, visiting /<secret> sets a
+// cookie that lets that browser past maintenance and redirects to /.
+// Visiting any page with ?maintenance=<secret> does the same and redirects
+// to the page without the parameter.
+func Maintenance(config ...MaintenanceConfig) http.MiddlewareFunc {
+	var cfg MaintenanceConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	return func(ctx *http.Context, next func() error) error {
+		storagePath := cfg.StoragePath
+		if storagePath == "" && ctx.App() != nil {
+			storagePath = ctx.App().StoragePath()
+		}
+		mode, err := http.LoadMaintenanceMode(http.MaintenanceModePath(storagePath))
+		if err != nil {
+			return err
+		}
+		if mode == nil || skipPath(cfg.Except, ctx.Path()) {
+			return next()
+		}
+
+		if mode.Secret != "" {
+			token := mode.BypassToken()
+			if matchesSecret(strings.TrimPrefix(ctx.Path(), "/"), mode.Secret) {
+				return bypassMaintenance(ctx, token, "/")
+			}
+			if matchesSecret(ctx.Query("maintenance"), mode.Secret) {
+				return bypassMaintenance(ctx, token, ctx.Path())
+			}
+			if cookie := ctx.FiberCtx().Cookies(MaintenanceCookie); cookie != "" &&
+				subtle.ConstantTimeCompare([]byte(cookie), []byte(token)) == 1 {
+				return next()
+			}
+		}
+
+		if mode.Retry > 0 {
+			ctx.Header("Retry-After", strconv.Itoa(mode.Retry))
+		}
+		return ctx.Status(fiber.StatusServiceUnavailable).JSONResponse(fiber.Map{
+			"error": "Service Unavailable",
+		})
+	}
+}
+
+// matchesSecret compares a visitor's secret in constant time.
+func matchesSecret(given, secret string) bool {
+	return given != "" && subtle.ConstantTimeCompare([]byte(given), []byte(secret)) == 1
+}
+
+// bypassMaintenance sets the cookie that lets the browser past maintenance
+// and redirects to location.
+func bypassMaintenance(ctx *http.Context, token, location string) error {
+	ctx.Cookie(&contracts.Cookie{
+		Name:     MaintenanceCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   12 * 60 * 60,
+		HTTPOnly: true,
+		SameSite: "Lax",
+	})
+	return ctx.Redirect(location)
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenance(t *testing.T) {
+	storage := t.TempDir()
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(nil, app)
+	router.Use(Maintenance(MaintenanceConfig{StoragePath: storage, Except: []string{"/health"}}))
+	handler := func(ctx *http.Context) error { return ctx.String("ok") }
+	router.GET("/orders", handler)
+	router.GET("/health", handler)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	path := http.MaintenanceModePath(storage)
+	require.NoError(t, http.WriteMaintenanceMode(path, &http.MaintenanceMode{Time: time.Now(), Retry: 60, Secret: "s3cret"}))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/orders", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "60", resp.Header.Get("Retry-After"))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/health", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/orders?maintenance=wrong", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/orders?maintenance=s3cret", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusFound, resp.StatusCode)
+	assert.Equal(t, "/orders", resp.Header.Get("Location"))
+	cookies := resp.Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, MaintenanceCookie, cookies[0].Name)
+
+	req := httptest.NewRequest("GET", "/orders", nil)
+	req.AddCookie(cookies[0])
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/orders", nil)
+	req.Header.Set("Cookie", MaintenanceCookie+"=forged")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/wrong", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+
+	// Visiting /{secret} sets the cookie and redirects home.
+	resp, err = app.Test(httptest.NewRequest("GET", "/s3cret", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusFound, resp.StatusCode)
+	assert.Equal(t, "/", resp.Header.Get("Location"))
+	cookies = resp.Cookies()
+	require.Len(t, cookies, 1)
+
+	req = httptest.NewRequest("GET", "/orders", nil)
+	req.AddCookie(cookies[0])
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
@@ -2,146 +2,114 @@
 package middleware
 
 import (
-	"fmt"
-	"runtime/debug"
 	"time"
 
-	"github.com/genesysflow/go-genesys/container"
-	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/filesystem"
 	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/log"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
-// Recover creates a panic recovery middleware.
-func Recover(logger contracts.Logger) http.MiddlewareFunc {
-	return func(ctx *http.Context, next func() error) error {
-		defer func() {
-			if r := recover(); r != nil {
-				stack := debug.Stack()
-				logger.Error("Panic recovered",
-					"error", r,
-					"stack", string(stack),
-					"path", ctx.Path(),
-					"method", ctx.Method(),
-				)
-
-				// Try to use error handler
-				if app := ctx.App(); app != nil {
-					// We use reflection or interface assertion to call Render
-					// Since we can't import errors package due to circular dependency (errors imports http)
-					// We define a local interface
-					type ErrorRenderer interface {
-						Render(ctx contracts.Context, err error) error
-					}
-
-					if h, err := container.Resolve[any](app, "error.handler"); err == nil {
-						if handler, ok := h.(ErrorRenderer); ok {
-							err := fmt.Errorf("panic: %v", r)
-							_ = handler.Render(ctx, err)
-							return
-						}
-					}
-				}
-
-				ctx.Status(fiber.StatusInternalServerError).JSONResponse(fiber.Map{
-					"error": "Internal Server Error",
-				})
-			}
-		}()
-
-		return next()
-	}
-}
+// RequestIDKey is the context key RequestID stores the request's ID under.
+const RequestIDKey = http.RequestIDKey
 
-// Logger creates a request logging middleware.
-func Logger(logger contracts.Logger) http.MiddlewareFunc {
-	return func(ctx *http.Context, next func() error) error {
-		start := time.Now()
-
-		// Execute request
-		err := next()
-
-		// Log request
-		duration := time.Since(start)
-		logger.Info("HTTP Request",
-			"method", ctx.Method(),
-			"path", ctx.Path(),
-			"status", ctx.FiberCtx().Response().StatusCode(),
-			"duration", duration.String(),
-			"ip", ctx.IP(),
-		)
-
-		return err
-	}
-}
+// RequestIDHeader is the header request IDs are read from and sent in.
+const RequestIDHeader = "X-Request-ID"
 
-// RequestID adds a unique request ID to each request.
-func RequestID() http.MiddlewareFunc {
-	return func(ctx *http.Context, next func() error) error {
-		requestID := ctx.Request().Header("X-Request-ID")
-		if requestID == "" {
-			requestID = uuid.New().String()
-		}
+// RequestIDConfig configures RequestID.
+type RequestIDConfig struct {
+	// Header is the header the ID is read from and sent in.
+	// Defaults to X-Request-ID.
+	Header string
 
-		ctx.Set("request_id", requestID)
-		ctx.Header("X-Request-ID", requestID)
+	// Generator creates IDs for requests without a valid one.
+	// Defaults to random UUIDs.
+	Generator func() string
 
-		return next()
-	}
+	// IgnoreIncoming always generates a new ID. By default a valid ID sent
+	// by the client or a proxy is reused, so logs correlate across services.
+	IgnoreIncoming bool
 }
 
-// CORS creates a CORS middleware.
-func CORS(config ...CORSConfig) http.MiddlewareFunc {
-	cfg := DefaultCORSConfig
+// RequestID gives each request an ID, reusing a valid incoming one. The ID
+// is sent back in the response header and is available from ctx.RequestID(),
+// from the request's context.Context through log.RequestID, and in every
+// entry of ctx.Logger() and of loggers given that context.
+func RequestID(config ...RequestIDConfig) http.MiddlewareFunc {
+	cfg := RequestIDConfig{}
 	if len(config) > 0 {
 		cfg = config[0]
 	}
+	if cfg.Header == "" {
+		cfg.Header = RequestIDHeader
+	}
+	if cfg.Generator == nil {
+		cfg.Generator = func() string { return uuid.New().String() }
+	}
 
 	return func(ctx *http.Context, next func() error) error {
-		origin := ctx.Request().Header("Origin")
-
-		// Check if origin is allowed
-		allowOrigin := ""
-		if cfg.AllowOrigins == "*" {
-			allowOrigin = "*"
-		} else {
-			for _, o := range splitAndTrim(cfg.AllowOrigins, ",") {
-				if o == origin {
-					allowOrigin = origin
-					break
-				}
-			}
+		var requestID string
+		if !cfg.IgnoreIncoming {
+			requestID = ctx.Request().Header(cfg.Header)
 		}
+		if !validRequestID(requestID) {
+			requestID = cfg.Generator()
+		}
+		setRequestID(ctx, requestID)
+		ctx.Header(cfg.Header, requestID)
 
-		if allowOrigin != "" {
-			ctx.Header("Access-Control-Allow-Origin", allowOrigin)
-			ctx.Header("Access-Control-Allow-Methods", cfg.AllowMethods)
-			ctx.Header("Access-Control-Allow-Headers", cfg.AllowHeaders)
-
-			if cfg.AllowCredentials {
-				ctx.Header("Access-Control-Allow-Credentials", "true")
-			}
-
-			if cfg.ExposeHeaders != "" {
-				ctx.Header("Access-Control-Expose-Headers", cfg.ExposeHeaders)
-			}
+		return next()
+	}
+}
 
-			if cfg.MaxAge > 0 {
-				ctx.Header("Access-Control-Max-Age", string(rune(cfg.MaxAge)))
-			}
-		}
+// setRequestID stores a request's ID on the context, its Fiber locals and
+// its context.Context, along with a logger that includes the ID.
+func setRequestID(ctx *http.Context, requestID string) {
+	ctx.Set(RequestIDKey, requestID)
+	ctx.FiberCtx().Locals(RequestIDKey, requestID)
+	ctx.Request().WithContext(log.WithRequestID(ctx.Request().Context(), requestID))
+
+	if logger := ctx.Logger(); logger != nil {
+		logger = logger.WithField(RequestIDKey, requestID)
+		ctx.Set(http.LoggerKey, logger)
+		ctx.FiberCtx().Locals(http.LoggerKey, logger)
+	}
+}
 
-		// Handle preflight
-		if ctx.Method() == "OPTIONS" {
-			return ctx.NoContent()
+// validRequestID reports whether an incoming ID is safe to reuse: up to 128
+// visible ASCII characters, so it can't forge log lines or headers.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
 		}
+	}
+	return true
+}
 
-		return next()
+// CORS creates a CORS middleware. Without a config it uses the
+// application's cors config file; see http.CORS.
+func CORS(config ...CORSConfig) http.MiddlewareFunc {
+	if len(config) == 0 {
+		return http.CORS()
 	}
+
+	cfg := config[0]
+	return http.CORS(http.CORSConfig{
+		AllowedOrigins:      splitAndTrim(cfg.AllowOrigins, ","),
+		AllowedMethods:      splitAndTrim(cfg.AllowMethods, ","),
+		AllowedHeaders:      splitAndTrim(cfg.AllowHeaders, ","),
+		ExposedHeaders:      splitAndTrim(cfg.ExposeHeaders, ","),
+		MaxAge:              cfg.MaxAge,
+		SupportsCredentials: cfg.AllowCredentials,
+	})
 }
 
-// CORSConfig defines CORS middleware configuration.
+// CORSConfig defines CORS middleware configuration as comma-separated lists.
 type CORSConfig struct {
 	AllowOrigins     string
 	AllowMethods     string
@@ -289,6 +257,23 @@ func BasicAuth(users map[string]string) http.MiddlewareFunc {
 	}
 }
 
+// ValidateSignature rejects requests for signed URLs that were tampered with
+// or have expired. Use it to guard routes serving local disk temporary URLs.
+func ValidateSignature(key string) http.MiddlewareFunc {
+	return func(ctx *http.Context, next func() error) error {
+		path := string(ctx.FiberCtx().Request().URI().PathOriginal())
+		err := filesystem.VerifySignature(path, ctx.Query("expires"), ctx.Query("signature"), []byte(key))
+		if err == filesystem.ErrSignatureExpired {
+			return ctx.Forbidden("Signature expired")
+		}
+		if err != nil {
+			return ctx.Forbidden("Invalid signature")
+		}
+
+		return next()
+	}
+}
+
 // splitAndTrim splits a string and trims whitespace.
 func splitAndTrim(s, sep string) []string {
 	var result []string
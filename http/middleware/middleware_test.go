@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/log"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	app := testutil.NewMockApplication()
+	app.SetLogger(log.NewJSON(&buf))
+
+	var seen, fromContext string
+	fiberApp := fiber.New()
+	router := http.NewRouter(app, fiberApp)
+	router.Use(RequestID())
+	router.GET("/", func(ctx *http.Context) error {
+		seen = ctx.RequestID()
+		fromContext = log.RequestID(ctx.Request().Context())
+		ctx.Logger().Info("handled")
+		return ctx.NoContent()
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Request-ID", "upstream-42")
+	resp, err := fiberApp.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, "upstream-42", resp.Header.Get("X-Request-ID"))
+	assert.Equal(t, "upstream-42", seen)
+	assert.Equal(t, "upstream-42", fromContext)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "handled", entry["message"])
+	assert.Equal(t, "upstream-42", entry["request_id"])
+
+	// Invalid incoming IDs are replaced.
+	for _, bad := range []string{strings.Repeat("x", 129), "a b", "line\nbreak"} {
+		req = httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-Request-ID", bad)
+		resp, err = fiberApp.Test(req)
+		require.NoError(t, err)
+		assert.NotEqual(t, bad, resp.Header.Get("X-Request-ID"))
+		assert.Len(t, resp.Header.Get("X-Request-ID"), 36)
+	}
+}
+
+func TestRequestIDConfig(t *testing.T) {
+	fiberApp := fiber.New()
+	router := http.NewRouter(testutil.NewMockApplication(), fiberApp)
+	router.Use(RequestID(RequestIDConfig{
+		Header:         "X-Correlation-ID",
+		Generator:      func() string { return "generated" },
+		IgnoreIncoming: true,
+	}))
+	router.GET("/", func(ctx *http.Context) error {
+		return ctx.String(ctx.RequestID())
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Correlation-ID", "client-chosen")
+	resp, err := fiberApp.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, "generated", resp.Header.Get("X-Correlation-ID"))
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// PanicError is a panic recovered by Recover. It is passed to the error
+// handler, so errors.Renderable can customize the response.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+
+	// Stack is the goroutine's stack trace at the panic.
+	Stack []byte
+
+	// RequestID is the ID of the request that panicked.
+	RequestID string
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// StackTrace returns the stack trace, which the error handler shows in
+// debug mode.
+func (e *PanicError) StackTrace() []byte {
+	return e.Stack
+}
+
+// Unwrap returns the panic value when it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// PanicReporter receives recovered panics, for example to send them to an
+// error tracker such as Sentry.
+type PanicReporter func(ctx *http.Context, panicErr *PanicError)
+
+// RecoverConfig configures RecoverWithConfig.
+type RecoverConfig struct {
+	// Logger logs recovered panics. Defaults to the application's logger.
+	Logger contracts.Logger
+
+	// Reporter, if set, is called with every recovered panic.
+	Reporter PanicReporter
+}
+
+// Recover creates a panic recovery middleware that logs panics to logger.
+func Recover(logger contracts.Logger) http.MiddlewareFunc {
+	return RecoverWithConfig(RecoverConfig{Logger: logger})
+}
+
+// RecoverWithConfig creates a panic recovery middleware. Panics in later
+// middleware and handlers are logged with their stack trace, passed to the
+// reporter, and rendered as a 500 by the application's error handler. The
+// response carries the request's ID in the X-Request-ID header, and in the
+// body, so users can quote it; one is generated when RequestID didn't run.
+func RecoverWithConfig(config RecoverConfig) http.MiddlewareFunc {
+	return func(ctx *http.Context, next func() error) (err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			requestID, _ := ctx.Get(RequestIDKey).(string)
+			if requestID == "" {
+				requestID = uuid.New().String()
+				setRequestID(ctx, requestID)
+			}
+			ctx.Header(RequestIDHeader, requestID)
+
+			panicErr := &PanicError{Value: r, Stack: debug.Stack(), RequestID: requestID}
+
+			logger := config.Logger
+			if logger == nil {
+				logger = ctx.Logger()
+			}
+			if logger != nil {
+				logger.WithFields(map[string]any{
+					"error":      panicErr.Error(),
+					"stack":      string(panicErr.Stack),
+					"request_id": requestID,
+					"method":     ctx.Method(),
+					"path":       ctx.Path(),
+					"ip":         ctx.IP(),
+				}).Error("Panic recovered")
+			}
+			if config.Reporter != nil {
+				config.Reporter(ctx, panicErr)
+			}
+
+			err = renderPanic(ctx, panicErr)
+		}()
+
+		return next()
+	}
+}
+
+// renderPanic renders a recovered panic with the application's error
+// handler, or as a JSON 500.
+func renderPanic(ctx *http.Context, panicErr *PanicError) error {
+	// The errors package imports http, so its handler is used through an
+	// interface.
+	type errorRenderer interface {
+		Render(ctx contracts.Context, err error) error
+	}
+
+	if app := ctx.App(); app != nil {
+		if h, err := container.Resolve[any](app, "error.handler"); err == nil {
+			if handler, ok := h.(errorRenderer); ok {
+				return handler.Render(ctx, panicErr)
+			}
+		}
+	}
+
+	return ctx.Status(fiber.StatusInternalServerError).JSONResponse(fiber.Map{
+		"success":    false,
+		"error":      "Internal Server Error",
+		"status":     fiber.StatusInternalServerError,
+		"request_id": panicErr.RequestID,
+	})
+}
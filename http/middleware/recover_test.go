@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecover(t *testing.T) {
+	logger := &testutil.MockLogger{}
+	var reported *PanicError
+
+	app := fiber.New()
+	router := http.NewRouter(testutil.NewMockApplication(), app)
+	router.Use(RequestID(), RecoverWithConfig(RecoverConfig{
+		Logger:   logger,
+		Reporter: func(ctx *http.Context, panicErr *PanicError) { reported = panicErr },
+	}))
+	router.GET("/boom", func(ctx *http.Context) error {
+		panic(errors.New("boom"))
+	})
+
+	req := httptest.NewRequest("GET", "/boom", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, 500, resp.StatusCode)
+	assert.Equal(t, "req-123", resp.Header.Get(RequestIDHeader))
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "req-123", body["request_id"])
+	assert.Equal(t, "Internal Server Error", body["error"])
+	assert.NotContains(t, body, "exception")
+
+	assert.Equal(t, []string{"ERROR: Panic recovered"}, logger.Messages)
+	require.NotNil(t, reported)
+	assert.Equal(t, "panic: boom", reported.Error())
+	assert.EqualError(t, errors.Unwrap(reported), "boom")
+	assert.Equal(t, "req-123", reported.RequestID)
+	assert.Contains(t, string(reported.Stack), "recover_test.go")
+}
+
+func TestRecoverGeneratesRequestID(t *testing.T) {
+	app := fiber.New()
+	router := http.NewRouter(testutil.NewMockApplication(), app)
+	router.Use(Recover(&testutil.MockLogger{}))
+	router.GET("/boom", func(ctx *http.Context) error {
+		panic("boom")
+	})
+	router.GET("/ok", func(ctx *http.Context) error {
+		return ctx.String("ok")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/boom", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get(RequestIDHeader))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/ok", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+}
@@ -0,0 +1,285 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/genesysflow/go-genesys/auth"
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/gofiber/fiber/v2"
+)
+
+// VaryBy returns the part of a request a cached response depends on.
+// Requests with different values get separate cache entries.
+type VaryBy func(ctx *http.Context) string
+
+// VaryByUser separates entries per authenticated user.
+func VaryByUser() VaryBy {
+	return func(ctx *http.Context) string {
+		return userKey(ctx)
+	}
+}
+
+// VaryByTenant separates entries per tenant.
+func VaryByTenant() VaryBy {
+	return func(ctx *http.Context) string {
+		return tenantKey(ctx)
+	}
+}
+
+// VaryByLocale separates entries per locale: the "locale" context value if
+// set, otherwise the preferred Accept-Language.
+func VaryByLocale() VaryBy {
+	return func(ctx *http.Context) string {
+		if locale, ok := ctx.Get("locale").(string); ok && locale != "" {
+			return locale
+		}
+		c := ctx.FiberCtx()
+		c.Vary(fiber.HeaderAcceptLanguage)
+		language, _, _ := strings.Cut(c.Get(fiber.HeaderAcceptLanguage), ",")
+		language, _, _ = strings.Cut(language, ";")
+		return strings.ToLower(strings.TrimSpace(language))
+	}
+}
+
+// VaryByHeader separates entries by request headers and lists them in the
+// response's Vary header.
+func VaryByHeader(names ...string) VaryBy {
+	return func(ctx *http.Context) string {
+		c := ctx.FiberCtx()
+		c.Vary(names...)
+		values := make([]string, len(names))
+		for i, name := range names {
+			values[i] = c.Get(name)
+		}
+		return strings.Join(values, "\x00")
+	}
+}
+
+// ResponseCacheConfig configures the CacheResponse middleware.
+type ResponseCacheConfig struct {
+	// TTL is how long responses are cached.
+	TTL time.Duration
+
+	// Store holds cached responses. Defaults to the default cache store.
+	Store cache.Store
+
+	// Vary lists what else, besides the URL, responses depend on.
+	Vary []VaryBy
+
+	// Shared stops entries from being scoped to the authenticated user and
+	// tenant. Only set it for responses that are the same for everyone.
+	Shared bool
+
+	// Prefix is prepended to cache keys. Defaults to "response:".
+	Prefix string
+}
+
+// CacheResponse caches successful GET and HEAD responses for ttl. Entries
+// are scoped to the authenticated user and tenant, so place it after the
+// middleware that sets them. See CacheResponseWith.
+func CacheResponse(ttl time.Duration, vary ...VaryBy) http.MiddlewareFunc {
+	return CacheResponseWith(ResponseCacheConfig{TTL: ttl, Vary: vary})
+}
+
+// CacheResponseWith creates a response caching middleware from a config.
+// Responses are not cached if they set cookies or Cache-Control: no-store,
+// or if the user or tenant was only set after the middleware ran, including
+// a user any guard resolved while handling the request. Served
+// responses carry X-Cache: HIT or MISS.
+func CacheResponseWith(cfg ResponseCacheConfig) http.MiddlewareFunc {
+	if cfg.Prefix == "" {
+		cfg.Prefix = "response:"
+	}
+
+	var (
+		store cache.Store
+		once  sync.Once
+		err   error
+	)
+	return func(ctx *http.Context, next func() error) error {
+		c := ctx.FiberCtx()
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return next()
+		}
+
+		once.Do(func() {
+			store, err = responseCacheStore(ctx, cfg.Store)
+		})
+		if err != nil {
+			return err
+		}
+
+		scope := ""
+		if !cfg.Shared {
+			scope = userKey(ctx) + "\x00" + tenantKey(ctx)
+		}
+		key := cfg.Prefix + responseCacheKey(ctx, scope, cfg.Vary)
+
+		if entry := cachedResponse(store, key); entry != nil {
+			entry.write(c)
+			c.Set("X-Cache", "HIT")
+			return nil
+		}
+
+		before := responseHeaders(c)
+		if err := next(); err != nil {
+			return err
+		}
+		c.Set("X-Cache", "MISS")
+
+		if !cfg.Shared && (userKey(ctx)+"\x00"+tenantKey(ctx) != scope || resolvedOtherUser(ctx, userKey(ctx))) {
+			return nil
+		}
+		if entry := newCachedEntry(c, before); entry != nil {
+			if payload, err := json.Marshal(entry); err == nil {
+				store.Put(key, string(payload), cfg.TTL)
+			}
+		}
+		return nil
+	}
+}
+
+func responseCacheStore(ctx *http.Context, store cache.Store) (cache.Store, error) {
+	if store != nil {
+		return store, nil
+	}
+	manager, err := container.Resolve[*cache.Manager](ctx.App())
+	if err != nil {
+		return nil, fmt.Errorf("middleware: response cache needs a store: %w", err)
+	}
+	return manager.Store()
+}
+
+// userKey identifies the authenticated user: the default guard's user,
+// else the one stored under "user".
+func userKey(ctx *http.Context) string {
+	user := ctx.User()
+	if user == nil {
+		user, _ = ctx.Get("user").(contracts.Authenticatable)
+	}
+	if user != nil {
+		return fmt.Sprint(user.GetAuthIdentifier())
+	}
+	return ""
+}
+
+// resolvedOtherUser reports whether the "user" value or any guard used
+// during the request resolved a user other than the one identified by user.
+func resolvedOtherUser(ctx *http.Context, user string) bool {
+	if u, ok := ctx.Get("user").(contracts.Authenticatable); ok && fmt.Sprint(u.GetAuthIdentifier()) != user {
+		return true
+	}
+	for _, guard := range auth.ResolvedGuards(ctx.FiberCtx()) {
+		if u, _ := guard.User(); u != nil && fmt.Sprint(u.GetAuthIdentifier()) != user {
+			return true
+		}
+	}
+	return false
+}
+
+// tenantKey identifies the tenant stored under "tenant".
+func tenantKey(ctx *http.Context) string {
+	if tenant, ok := ctx.Get("tenant").(contracts.Tenant); ok {
+		return fmt.Sprint(tenant.GetTenantKey())
+	}
+	return ""
+}
+
+// responseCacheKey hashes the request URL with the scope and vary values.
+// Query parameters are sorted so their order doesn't matter.
+func responseCacheKey(ctx *http.Context, scope string, vary []VaryBy) string {
+	c := ctx.FiberCtx()
+	query, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s", c.Method(), c.Hostname(), c.Path(), query.Encode())
+	fmt.Fprintf(h, "\x00%s", scope)
+	for _, v := range vary {
+		fmt.Fprintf(h, "\x00%s", v(ctx))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type cachedEntry struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    []byte            `json:"body"`
+}
+
+func cachedResponse(store cache.Store, key string) *cachedEntry {
+	value, err := store.Get(key)
+	if err != nil || value == nil {
+		return nil
+	}
+	payload, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	var entry cachedEntry
+	if json.Unmarshal([]byte(payload), &entry) != nil {
+		return nil
+	}
+	return &entry
+}
+
+// newCachedEntry captures a cacheable response with the headers set after
+// before was taken, or returns nil.
+func newCachedEntry(c *fiber.Ctx, before map[string]string) *cachedEntry {
+	resp := c.Response()
+	if resp.StatusCode() != fiber.StatusOK || resp.IsBodyStream() {
+		return nil
+	}
+	if strings.Contains(string(resp.Header.Peek(fiber.HeaderCacheControl)), "no-store") {
+		return nil
+	}
+	setsCookies := false
+	resp.Header.VisitAllCookie(func(key, value []byte) {
+		setsCookies = true
+	})
+	if setsCookies {
+		return nil
+	}
+
+	headers := map[string]string{}
+	for key, value := range responseHeaders(c) {
+		if previous, ok := before[key]; !ok || previous != value {
+			headers[key] = value
+		}
+	}
+	return &cachedEntry{
+		Status:  resp.StatusCode(),
+		Headers: headers,
+		Body:    append([]byte(nil), resp.Body()...),
+	}
+}
+
+func (e *cachedEntry) write(c *fiber.Ctx) {
+	for key, value := range e.Headers {
+		c.Set(key, value)
+	}
+	c.Status(e.Status)
+	c.Response().SetBody(e.Body)
+}
+
+// responseHeaders returns the response headers worth caching.
+func responseHeaders(c *fiber.Ctx) map[string]string {
+	headers := map[string]string{}
+	c.Response().Header.VisitAll(func(key, value []byte) {
+		switch k := string(key); k {
+		case fiber.HeaderContentLength, fiber.HeaderDate, fiber.HeaderServer, fiber.HeaderSetCookie, "X-Cache":
+		default:
+			headers[k] = string(value)
+		}
+	})
+	return headers
+}
@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/auth"
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cacheTenant string
+
+func (t cacheTenant) GetTenantKey() any { return string(t) }
+
+// authenticate sets the user and tenant from the X-User and X-Tenant headers.
+func authenticate(ctx *http.Context, next func() error) error {
+	if id := ctx.Request().Header("X-User"); id != "" {
+		ctx.Set("user", throttleUser{id: len(id)})
+	}
+	if tenant := ctx.Request().Header("X-Tenant"); tenant != "" {
+		ctx.Set("tenant", cacheTenant(tenant))
+	}
+	return next()
+}
+
+type cachedApp struct {
+	app   *fiber.App
+	calls int
+}
+
+func newCachedApp(t *testing.T, middleware ...http.MiddlewareFunc) *cachedApp {
+	t.Helper()
+	a := &cachedApp{app: fiber.New(fiber.Config{DisableStartupMessage: true})}
+	router := http.NewRouter(nil, a.app)
+	router.GET("/dashboard", func(ctx *http.Context) error {
+		a.calls++
+		ctx.Header("X-Rendered", fmt.Sprint(a.calls))
+		if ctx.Query("cookie") != "" {
+			ctx.FiberCtx().Cookie(&fiber.Cookie{Name: "seen", Value: "1"})
+		}
+		return ctx.JSONResponse(map[string]any{"call": a.calls})
+	}, middleware...)
+	return a
+}
+
+func (a *cachedApp) get(t *testing.T, path string, headers ...string) (string, string) {
+	t.Helper()
+	req := httptest.NewRequest("GET", path, nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := a.app.Test(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get("Content-Type"))
+	return string(body), resp.Header.Get("X-Cache")
+}
+
+func TestCacheResponseServesHits(t *testing.T) {
+	a := newCachedApp(t, CacheResponseWith(ResponseCacheConfig{TTL: time.Minute, Store: cache.NewMemoryStore()}))
+
+	body, status := a.get(t, "/dashboard?a=1&b=2")
+	assert.Equal(t, `{"call":1}`, body)
+	assert.Equal(t, "MISS", status)
+
+	body, status = a.get(t, "/dashboard?b=2&a=1")
+	assert.Equal(t, `{"call":1}`, body)
+	assert.Equal(t, "HIT", status)
+
+	body, _ = a.get(t, "/dashboard?a=2")
+	assert.Equal(t, `{"call":2}`, body)
+}
+
+func TestCacheResponseScopesToUserAndTenant(t *testing.T) {
+	a := newCachedApp(t, authenticate, CacheResponseWith(ResponseCacheConfig{TTL: time.Minute, Store: cache.NewMemoryStore()}))
+
+	body, _ := a.get(t, "/dashboard", "X-User", "ada")
+	assert.Equal(t, `{"call":1}`, body)
+	body, _ = a.get(t, "/dashboard", "X-User", "grace")
+	assert.Equal(t, `{"call":2}`, body)
+	body, _ = a.get(t, "/dashboard", "X-User", "ada", "X-Tenant", "acme")
+	assert.Equal(t, `{"call":3}`, body)
+
+	body, status := a.get(t, "/dashboard", "X-User", "ada")
+	assert.Equal(t, `{"call":1}`, body)
+	assert.Equal(t, "HIT", status)
+}
+
+func TestCacheResponseSkipsLateAuthentication(t *testing.T) {
+	// The user is set inside the cache middleware, so the response can't be
+	// attributed to a cache scope.
+	a := newCachedApp(t, CacheResponseWith(ResponseCacheConfig{TTL: time.Minute, Store: cache.NewMemoryStore()}), authenticate)
+	a.get(t, "/dashboard", "X-User", "ada")
+	body, status := a.get(t, "/dashboard", "X-User", "grace")
+	assert.Equal(t, `{"call":2}`, body)
+	assert.Equal(t, "MISS", status)
+}
+
+func TestCacheResponseVaryByHeader(t *testing.T) {
+	a := newCachedApp(t, CacheResponseWith(ResponseCacheConfig{
+		TTL:   time.Minute,
+		Store: cache.NewMemoryStore(),
+		Vary:  []VaryBy{VaryByHeader("X-Device"), VaryByLocale()},
+	}))
+
+	a.get(t, "/dashboard", "X-Device", "mobile", "Accept-Language", "fr-CA,fr;q=0.8")
+	body, status := a.get(t, "/dashboard", "X-Device", "mobile", "Accept-Language", "fr-CA")
+	assert.Equal(t, `{"call":1}`, body)
+	assert.Equal(t, "HIT", status)
+
+	body, _ = a.get(t, "/dashboard", "X-Device", "desktop", "Accept-Language", "fr-CA")
+	assert.Equal(t, `{"call":2}`, body)
+	body, _ = a.get(t, "/dashboard", "X-Device", "mobile", "Accept-Language", "en")
+	assert.Equal(t, `{"call":3}`, body)
+}
+
+func TestCacheResponseSkipsCookies(t *testing.T) {
+	a := newCachedApp(t, CacheResponseWith(ResponseCacheConfig{TTL: time.Minute, Store: cache.NewMemoryStore()}))
+
+	a.get(t, "/dashboard?cookie=1")
+	body, _ := a.get(t, "/dashboard?cookie=1")
+	assert.Equal(t, `{"call":2}`, body)
+}
+
+func TestCacheResponseScopesToGuardUser(t *testing.T) {
+	// No middleware sets "user"; the handler resolves it from session guards.
+	manager := auth.NewManager(auth.Config{
+		Default: "web",
+		Guards: map[string]auth.GuardConfig{
+			"web":   {Driver: "session", Provider: "users"},
+			"admin": {Driver: "session", Provider: "users"},
+		},
+	})
+	manager.RegisterProvider("users", tokenUsers{})
+	container := testutil.NewMockApplication()
+	container.InstanceType(manager)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(container, app)
+	cached := CacheResponseWith(ResponseCacheConfig{TTL: time.Minute, Store: cache.NewMemoryStore()})
+	router.GET("/me", func(ctx *http.Context) error {
+		return ctx.JSONResponse(map[string]any{"user": ctx.User().GetAuthIdentifier()})
+	}, cached)
+	router.GET("/admin", func(ctx *http.Context) error {
+		user, err := ctx.Auth("admin").User()
+		if err != nil {
+			return err
+		}
+		return ctx.JSONResponse(map[string]any{"admin": user.GetAuthIdentifier()})
+	}, cached)
+	a := &cachedApp{app: app}
+
+	manager.ActingAs(auth.GenericUser{"id": 1})
+	a.get(t, "/me")
+	manager.ActingAs(auth.GenericUser{"id": 2})
+	body, status := a.get(t, "/me")
+	assert.Equal(t, `{"user":2}`, body)
+	assert.Equal(t, "MISS", status)
+
+	manager.ActingAs(nil)
+	manager.ActingAs(auth.GenericUser{"id": 1}, "admin")
+	a.get(t, "/admin")
+	manager.ActingAs(auth.GenericUser{"id": 2}, "admin")
+	body, status = a.get(t, "/admin")
+	assert.Equal(t, `{"admin":2}`, body)
+	assert.Equal(t, "MISS", status)
+}
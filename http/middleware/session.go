@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/session"
+)
+
+// StartSession starts the request's session, making it available through
+// ctx.Session(), and saves it once the handler returns. The manager is
+// resolved from the container when none is given.
+func StartSession(manager ...*session.Manager) http.MiddlewareFunc {
+	var m *session.Manager
+	if len(manager) > 0 {
+		m = manager[0]
+	}
+
+	return func(ctx *http.Context, next func() error) error {
+		sessions := m
+		if sessions == nil {
+			resolved, err := container.Resolve[*session.Manager](ctx.App())
+			if err != nil {
+				return fmt.Errorf("middleware: session manager not available: %w", err)
+			}
+			sessions = resolved
+		}
+		return sessions.Handle(ctx.FiberCtx(), next)
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/session"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartSession(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(nil, app)
+	router.Use(StartSession(session.NewManager()))
+	router.POST("/login", func(ctx *http.Context) error {
+		ctx.Session().Put("user_id", 7)
+		ctx.Session().Flash("status", "Welcome back")
+		return ctx.Session().Regenerate()
+	})
+	router.GET("/dashboard", func(ctx *http.Context) error {
+		return ctx.String(ctx.Session().GetString("status"))
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/login", nil))
+	require.NoError(t, err)
+	cookies := resp.Cookies()
+	require.Len(t, cookies, 1)
+
+	get := func() string {
+		req := httptest.NewRequest("GET", "/dashboard", nil)
+		req.AddCookie(&nethttp.Cookie{Name: cookies[0].Name, Value: cookies[0].Value})
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+	assert.Equal(t, "Welcome back", get())
+	assert.Equal(t, "", get())
+}
+
+func TestStartSessionResolvesManager(t *testing.T) {
+	container := testutil.NewMockApplication()
+	container.InstanceType(session.NewManager())
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(container, app)
+	router.GET("/", func(ctx *http.Context) error {
+		if ctx.Session() == nil {
+			return ctx.String("none")
+		}
+		return ctx.String("started")
+	})
+	router.GET("/session", func(ctx *http.Context) error {
+		return ctx.String("started")
+	}).Middleware(StartSession())
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "none", string(body))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/session", nil))
+	require.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	assert.Equal(t, "started", string(body))
+}
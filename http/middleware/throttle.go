@@ -0,0 +1,221 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ThrottleStore counts requests per key in fixed windows.
+type ThrottleStore interface {
+	// Hit records a request for key and returns the number of requests in
+	// the current window and the time until it resets. A window of length
+	// decay starts with the first request.
+	Hit(ctx context.Context, key string, decay time.Duration) (hits int, resetIn time.Duration, err error)
+}
+
+// ThrottleConfig configures the Throttle middleware.
+type ThrottleConfig struct {
+	// MaxAttempts is the number of requests allowed per window.
+	MaxAttempts int
+
+	// Decay is the length of the window.
+	Decay time.Duration
+
+	// Store holds the counters. Defaults to an in-memory store shared by all
+	// throttled routes in the process.
+	Store ThrottleStore
+
+	// Name groups routes under one set of counters. By default each route
+	// is counted separately.
+	Name string
+
+	// Key identifies the client. Defaults to ThrottleKey.
+	Key func(ctx *http.Context) string
+}
+
+var defaultThrottleStore = NewMemoryThrottleStore()
+
+// Throttle limits requests per authenticated user, or per IP for guests.
+// The spec is "attempts,minutes", e.g. "60,1"; the period may also be a
+// duration such as "10,30s". It panics on a malformed spec.
+func Throttle(spec string, store ...ThrottleStore) http.MiddlewareFunc {
+	maxAttempts, decay, err := parseThrottle(spec)
+	if err != nil {
+		panic(err)
+	}
+
+	cfg := ThrottleConfig{MaxAttempts: maxAttempts, Decay: decay}
+	if len(store) > 0 {
+		cfg.Store = store[0]
+	}
+	return ThrottleWith(cfg)
+}
+
+// ThrottleWith creates a rate limiting middleware from a config. Responses
+// carry X-RateLimit-Limit and X-RateLimit-Remaining headers; throttled
+// requests get 429 with Retry-After and X-RateLimit-Reset.
+func ThrottleWith(cfg ThrottleConfig) http.MiddlewareFunc {
+	if cfg.Store == nil {
+		cfg.Store = defaultThrottleStore
+	}
+	if cfg.Key == nil {
+		cfg.Key = ThrottleKey
+	}
+
+	return func(ctx *http.Context, next func() error) error {
+		name := cfg.Name
+		if name == "" {
+			route := ctx.FiberCtx().Route()
+			name = route.Method + " " + route.Path
+		}
+
+		hits, resetIn, err := cfg.Store.Hit(ctx.FiberCtx().UserContext(), "throttle:"+name+":"+cfg.Key(ctx), cfg.Decay)
+		if err != nil {
+			return err
+		}
+
+		ctx.Header("X-RateLimit-Limit", strconv.Itoa(cfg.MaxAttempts))
+		ctx.Header("X-RateLimit-Remaining", strconv.Itoa(max(cfg.MaxAttempts-hits, 0)))
+
+		if hits > cfg.MaxAttempts {
+			retryAfter := int((resetIn + time.Second - 1) / time.Second)
+			ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+			ctx.Header("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(resetIn).Unix(), 10))
+			return ctx.Status(fiber.StatusTooManyRequests).JSONResponse(fiber.Map{
+				"error": "Too Many Requests",
+			})
+		}
+
+		return next()
+	}
+}
+
+// ThrottleKey identifies the authenticated user stored under "user", or the
+// client IP for guests.
+func ThrottleKey(ctx *http.Context) string {
+	if user, ok := ctx.Get("user").(contracts.Authenticatable); ok {
+		return fmt.Sprintf("user:%v", user.GetAuthIdentifier())
+	}
+	return "ip:" + ctx.IP()
+}
+
+// parseThrottle parses an "attempts,period" spec.
+func parseThrottle(spec string) (int, time.Duration, error) {
+	attempts, period, _ := strings.Cut(spec, ",")
+	maxAttempts, err := strconv.Atoi(strings.TrimSpace(attempts))
+	if err != nil || maxAttempts <= 0 {
+		return 0, 0, fmt.Errorf("middleware: invalid throttle attempts in %q", spec)
+	}
+
+	period = strings.TrimSpace(period)
+	if period == "" {
+		return maxAttempts, time.Minute, nil
+	}
+	if minutes, err := strconv.Atoi(period); err == nil && minutes > 0 {
+		return maxAttempts, time.Duration(minutes) * time.Minute, nil
+	}
+	if decay, err := time.ParseDuration(period); err == nil && decay > 0 {
+		return maxAttempts, decay, nil
+	}
+	return 0, 0, fmt.Errorf("middleware: invalid throttle period in %q", spec)
+}
+
+type throttleWindow struct {
+	hits    int
+	resetAt time.Time
+}
+
+// MemoryThrottleStore keeps counters in process.
+type MemoryThrottleStore struct {
+	windows   map[string]*throttleWindow
+	nextSweep time.Time
+	mu        sync.Mutex
+}
+
+// NewMemoryThrottleStore creates an in-memory throttle store.
+func NewMemoryThrottleStore() *MemoryThrottleStore {
+	return &MemoryThrottleStore{
+		windows: make(map[string]*throttleWindow),
+	}
+}
+
+// Hit records a request for key.
+func (s *MemoryThrottleStore) Hit(ctx context.Context, key string, decay time.Duration) (int, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	window, ok := s.windows[key]
+	if !ok || !now.Before(window.resetAt) {
+		window = &throttleWindow{resetAt: now.Add(decay)}
+		s.windows[key] = window
+	}
+	window.hits++
+	return window.hits, window.resetAt.Sub(now), nil
+}
+
+// sweep drops expired windows, at most once a minute.
+func (s *MemoryThrottleStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	s.nextSweep = now.Add(time.Minute)
+	for key, window := range s.windows {
+		if !now.Before(window.resetAt) {
+			delete(s.windows, key)
+		}
+	}
+}
+
+// RedisThrottleStore keeps counters in Redis, shared by every process.
+type RedisThrottleStore struct {
+	client cache.RedisCommander
+	prefix string
+}
+
+// NewRedisThrottleStore creates a throttle store on a Redis client.
+func NewRedisThrottleStore(client cache.RedisCommander, prefix string) *RedisThrottleStore {
+	return &RedisThrottleStore{client: client, prefix: prefix}
+}
+
+// Hit records a request for key.
+func (s *RedisThrottleStore) Hit(ctx context.Context, key string, decay time.Duration) (int, time.Duration, error) {
+	key = s.prefix + key
+
+	reply, err := s.client.Do(ctx, "INCR", key)
+	if err != nil {
+		return 0, 0, err
+	}
+	hits, _ := reply.(int64)
+	if hits == 1 {
+		if _, err := s.client.Do(ctx, "PEXPIRE", key, decay.Milliseconds()); err != nil {
+			return 0, 0, err
+		}
+		return 1, decay, nil
+	}
+
+	reply, err = s.client.Do(ctx, "PTTL", key)
+	if err != nil {
+		return 0, 0, err
+	}
+	ttl, _ := reply.(int64)
+	if ttl < 0 {
+		// The expiry was never set, e.g. the process died after INCR.
+		if _, err := s.client.Do(ctx, "PEXPIRE", key, decay.Milliseconds()); err != nil {
+			return 0, 0, err
+		}
+		ttl = decay.Milliseconds()
+	}
+	return int(hits), time.Duration(ttl) * time.Millisecond, nil
+}
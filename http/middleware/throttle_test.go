@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type throttleUser struct{ id int }
+
+func (u throttleUser) GetAuthIdentifier() any { return u.id }
+
+var _ contracts.Authenticatable = throttleUser{}
+
+func newThrottledApp(t *testing.T, throttle http.MiddlewareFunc) *fiber.App {
+	t.Helper()
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(nil, app)
+	router.GET("/orders", func(ctx *http.Context) error {
+		return ctx.String("ok")
+	}, func(ctx *http.Context, next func() error) error {
+		if id := ctx.Query("user"); id != "" {
+			ctx.Set("user", throttleUser{id: len(id)})
+		}
+		return next()
+	}, throttle)
+	router.GET("/reports", func(ctx *http.Context) error {
+		return ctx.String("ok")
+	}, throttle)
+	return app
+}
+
+func TestThrottleLimitsRequests(t *testing.T) {
+	app := newThrottledApp(t, Throttle("2,1", NewMemoryThrottleStore()))
+
+	for i, remaining := range []string{"1", "0"} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/orders", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode, "request %d", i)
+		assert.Equal(t, "2", resp.Header.Get("X-RateLimit-Limit"))
+		assert.Equal(t, remaining, resp.Header.Get("X-RateLimit-Remaining"))
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "60", resp.Header.Get("Retry-After"))
+	assert.NotEmpty(t, resp.Header.Get("X-RateLimit-Reset"))
+
+	// Other routes and authenticated users have their own counters.
+	resp, err = app.Test(httptest.NewRequest("GET", "/reports", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/orders?user=ada", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestThrottleNamedLimitSharesCounters(t *testing.T) {
+	app := newThrottledApp(t, ThrottleWith(ThrottleConfig{
+		MaxAttempts: 1,
+		Decay:       time.Minute,
+		Store:       NewMemoryThrottleStore(),
+		Name:        "api",
+	}))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/reports", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+}
+
+func TestMemoryThrottleStoreResetsWindow(t *testing.T) {
+	store := NewMemoryThrottleStore()
+	ctx := context.Background()
+
+	hits, _, _ := store.Hit(ctx, "k", 20*time.Millisecond)
+	assert.Equal(t, 1, hits)
+	hits, resetIn, _ := store.Hit(ctx, "k", 20*time.Millisecond)
+	assert.Equal(t, 2, hits)
+	assert.LessOrEqual(t, resetIn, 20*time.Millisecond)
+
+	time.Sleep(25 * time.Millisecond)
+	hits, _, _ = store.Hit(ctx, "k", 20*time.Millisecond)
+	assert.Equal(t, 1, hits)
+}
+
+// fakeRedis implements INCR, PEXPIRE and PTTL.
+type fakeRedis struct {
+	counts  map[string]int64
+	expires map[string]time.Time
+	mu      sync.Mutex
+}
+
+func (f *fakeRedis) Do(ctx context.Context, args ...any) (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := args[1].(string)
+	switch args[0] {
+	case "INCR":
+		f.counts[key]++
+		return f.counts[key], nil
+	case "PEXPIRE":
+		f.expires[key] = time.Now().Add(time.Duration(args[2].(int64)) * time.Millisecond)
+		return int64(1), nil
+	case "PTTL":
+		expiry, ok := f.expires[key]
+		if !ok {
+			return int64(-1), nil
+		}
+		return time.Until(expiry).Milliseconds(), nil
+	}
+	return nil, nil
+}
+
+func TestRedisThrottleStore(t *testing.T) {
+	redis := &fakeRedis{counts: map[string]int64{}, expires: map[string]time.Time{}}
+	store := NewRedisThrottleStore(redis, "app:")
+	ctx := context.Background()
+
+	hits, resetIn, err := store.Hit(ctx, "k", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, time.Minute, resetIn)
+	assert.Contains(t, redis.expires, "app:k")
+
+	hits, resetIn, err = store.Hit(ctx, "k", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, hits)
+	assert.Greater(t, resetIn, 58*time.Second)
+
+	// A counter left without an expiry gets one.
+	redis.counts["app:orphan"] = 5
+	hits, resetIn, err = store.Hit(ctx, "orphan", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 6, hits)
+	assert.Equal(t, time.Minute, resetIn)
+	assert.Contains(t, redis.expires, "app:orphan")
+}
+
+func TestParseThrottle(t *testing.T) {
+	for spec, want := range map[string]time.Duration{
+		"60,1":   time.Minute,
+		"60":     time.Minute,
+		"10,5":   5 * time.Minute,
+		"10,30s": 30 * time.Second,
+	} {
+		_, decay, err := parseThrottle(spec)
+		require.NoError(t, err, spec)
+		assert.Equal(t, want, decay, spec)
+	}
+
+	for _, spec := range []string{"", "x,1", "0,1", "10,soon"} {
+		_, _, err := parseThrottle(spec)
+		assert.Error(t, err, spec)
+	}
+	assert.Panics(t, func() { Throttle(strings.Repeat("x", 3)) })
+}
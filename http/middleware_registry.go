@@ -0,0 +1,177 @@
+package http
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MiddlewareFactory builds a middleware from the parameters of its alias:
+// "throttle:60,1" calls the "throttle" factory with "60" and "1".
+type MiddlewareFactory func(params ...string) (MiddlewareFunc, error)
+
+// MiddlewareRegistry maps names to middleware. Aliases name a single
+// middleware, optionally with parameters; groups name a list of aliases and
+// other groups, such as "web" or "api". Routes and groups refer to them with
+// UseNamed and MiddlewareNamed.
+type MiddlewareRegistry struct {
+	mu      sync.RWMutex
+	aliases map[string]MiddlewareFactory
+	groups  map[string][]string
+}
+
+// NewMiddlewareRegistry creates an empty registry.
+func NewMiddlewareRegistry() *MiddlewareRegistry {
+	return &MiddlewareRegistry{
+		aliases: make(map[string]MiddlewareFactory),
+		groups:  make(map[string][]string),
+	}
+}
+
+// Alias registers a middleware that takes no parameters under name.
+func (m *MiddlewareRegistry) Alias(name string, middleware MiddlewareFunc) *MiddlewareRegistry {
+	return m.AliasFactory(name, func(params ...string) (MiddlewareFunc, error) {
+		if len(params) > 0 {
+			return nil, fmt.Errorf("http: middleware %q takes no parameters", name)
+		}
+		return middleware, nil
+	})
+}
+
+// AliasFactory registers a middleware factory under name.
+func (m *MiddlewareRegistry) AliasFactory(name string, factory MiddlewareFactory) *MiddlewareRegistry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.aliases[name] = factory
+	return m
+}
+
+// Group sets the middleware of a named group, replacing any earlier
+// definition. Entries are aliases with optional parameters, or groups.
+func (m *MiddlewareRegistry) Group(name string, middleware ...string) *MiddlewareRegistry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.groups[name] = append([]string(nil), middleware...)
+	return m
+}
+
+// AppendToGroup adds middleware to the end of a named group.
+func (m *MiddlewareRegistry) AppendToGroup(name string, middleware ...string) *MiddlewareRegistry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.groups[name] = append(m.groups[name], middleware...)
+	return m
+}
+
+// Has reports whether name is a registered alias or group.
+func (m *MiddlewareRegistry) Has(name string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, alias := m.aliases[name]
+	_, group := m.groups[name]
+	return alias || group
+}
+
+// Aliases returns the registered alias names, sorted.
+func (m *MiddlewareRegistry) Aliases() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.aliases))
+	for name := range m.aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Groups returns the registered groups.
+func (m *MiddlewareRegistry) Groups() map[string][]string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	groups := make(map[string][]string, len(m.groups))
+	for name, middleware := range m.groups {
+		groups[name] = append([]string(nil), middleware...)
+	}
+	return groups
+}
+
+// Resolve builds the middleware for names in order, expanding groups.
+// A group that includes itself, directly or not, is an error.
+func (m *MiddlewareRegistry) Resolve(names ...string) ([]MiddlewareFunc, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var resolved []MiddlewareFunc
+	if err := m.resolve(names, nil, &resolved); err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+func (m *MiddlewareRegistry) resolve(names, groups []string, resolved *[]MiddlewareFunc) error {
+	for _, entry := range names {
+		name, params := parseMiddlewareName(entry)
+
+		if members, ok := m.groups[name]; ok {
+			if params != nil {
+				return fmt.Errorf("http: middleware group %q takes no parameters", name)
+			}
+			for _, group := range groups {
+				if group == name {
+					return fmt.Errorf("http: middleware group %q includes itself", name)
+				}
+			}
+			if err := m.resolve(members, append(groups, name), resolved); err != nil {
+				return err
+			}
+			continue
+		}
+
+		factory, ok := m.aliases[name]
+		if !ok {
+			return fmt.Errorf("http: unknown middleware %q", name)
+		}
+		middleware, err := factory(params...)
+		if err != nil {
+			return fmt.Errorf("http: middleware %q: %w", entry, err)
+		}
+		*resolved = append(*resolved, middleware)
+	}
+	return nil
+}
+
+// parseMiddlewareName splits "name:a,b" into the name and its parameters.
+func parseMiddlewareName(entry string) (string, []string) {
+	name, params, ok := strings.Cut(strings.TrimSpace(entry), ":")
+	if !ok {
+		return name, nil
+	}
+	return name, strings.Split(params, ",")
+}
+
+// MiddlewareRegistry returns the named middleware shared by the router and
+// its groups.
+func (r *Router) MiddlewareRegistry() *MiddlewareRegistry {
+	return r.registry.middleware
+}
+
+// UseNamed registers middleware by alias or group name for this
+// router/group. It panics if a name can't be resolved.
+func (r *Router) UseNamed(names ...string) {
+	r.Use(r.mustResolveMiddleware(names)...)
+}
+
+// MiddlewareNamed adds middleware to the route by alias or group name. It
+// panics if a name can't be resolved.
+func (r *Route) MiddlewareNamed(names ...string) *Route {
+	return r.Middleware(r.router.mustResolveMiddleware(names)...)
+}
+
+func (r *Router) mustResolveMiddleware(names []string) []MiddlewareFunc {
+	middleware, err := r.registry.middleware.Resolve(names...)
+	if err != nil {
+		panic(err)
+	}
+	return middleware
+}
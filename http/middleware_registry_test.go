@@ -0,0 +1,79 @@
+package http
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tagMiddleware appends tag and its parameters to the X-Trace header.
+func tagMiddleware(tag string) MiddlewareFactory {
+	return func(params ...string) (MiddlewareFunc, error) {
+		label := tag
+		if len(params) > 0 {
+			label += "(" + strings.Join(params, ",") + ")"
+		}
+		return func(ctx *Context, next func() error) error {
+			ctx.FiberCtx().Append("X-Trace", label)
+			return next()
+		}, nil
+	}
+}
+
+func newNamedRegistry() *MiddlewareRegistry {
+	return NewMiddlewareRegistry().
+		AliasFactory("a", tagMiddleware("a")).
+		AliasFactory("b", tagMiddleware("b")).
+		Group("web", "a", "b:1,2").
+		Group("api", "web", "b")
+}
+
+func TestMiddlewareRegistryResolve(t *testing.T) {
+	registry := newNamedRegistry()
+
+	assert.True(t, registry.Has("web"))
+	assert.True(t, registry.Has("a"))
+	assert.False(t, registry.Has("c"))
+	assert.Equal(t, []string{"a", "b"}, registry.Aliases())
+
+	resolved, err := registry.Resolve("api", "a:x")
+	require.NoError(t, err)
+	assert.Len(t, resolved, 4)
+
+	_, err = registry.Resolve("missing")
+	assert.EqualError(t, err, `http: unknown middleware "missing"`)
+
+	_, err = registry.Resolve("web:1")
+	assert.Error(t, err)
+
+	registry.AppendToGroup("web", "api")
+	_, err = registry.Resolve("api")
+	assert.EqualError(t, err, `http: middleware group "api" includes itself`)
+
+	registry.Alias("plain", func(ctx *Context, next func() error) error { return next() })
+	_, err = registry.Resolve("plain:1")
+	assert.Error(t, err)
+}
+
+func TestRouterNamedMiddleware(t *testing.T) {
+	app := fiber.New()
+	router := NewRouter(&mockApplication{}, app)
+	router.registry.middleware = newNamedRegistry()
+
+	router.Group("/api", func(api *Router) {
+		api.UseNamed("web")
+		api.GET("/users", func(ctx *Context) error { return nil }).MiddlewareNamed("b:3")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/users", nil))
+	require.NoError(t, err)
+	_, _ = io.Copy(io.Discard, resp.Body)
+	assert.Equal(t, "a, b(1,2), b(3)", resp.Header.Get("X-Trace"))
+
+	assert.Panics(t, func() { router.UseNamed("missing") })
+}
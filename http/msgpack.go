@@ -0,0 +1,117 @@
+package http
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// writeMsgpack encodes v as MessagePack, from its JSON form. Integers use
+// the smallest encoding that holds them and map keys are sorted.
+func writeMsgpack(c *fiber.Ctx, v any) error {
+	generic, err := jsonValue(c, v)
+	if err != nil {
+		return err
+	}
+
+	body, err := appendMsgpack(nil, generic)
+	if err != nil {
+		return err
+	}
+	c.Response().SetBody(body)
+	c.Response().Header.SetContentType(MIMEMsgpack)
+	return nil
+}
+
+// appendMsgpack appends the encoding of a value decoded from JSON to b.
+func appendMsgpack(b []byte, v any) ([]byte, error) {
+	switch value := v.(type) {
+	case nil:
+		return append(b, 0xc0), nil
+	case bool:
+		if value {
+			return append(b, 0xc3), nil
+		}
+		return append(b, 0xc2), nil
+	case json.Number:
+		if n, err := value.Int64(); err == nil {
+			return appendMsgpackInt(b, n), nil
+		}
+		f, err := value.Float64()
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, 0xcb)
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(f)), nil
+	case string:
+		b = appendMsgpackHeader(b, len(value), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		return append(b, value...), nil
+	case []any:
+		b = appendMsgpackHeader(b, len(value), 0x90, 16, 0, 0xdc, 0xdd)
+		for _, item := range value {
+			var err error
+			if b, err = appendMsgpack(b, item); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case map[string]any:
+		b = appendMsgpackHeader(b, len(value), 0x80, 16, 0, 0xde, 0xdf)
+		for _, key := range sortedKeys(value) {
+			var err error
+			if b, err = appendMsgpack(b, key); err != nil {
+				return nil, err
+			}
+			if b, err = appendMsgpack(b, value[key]); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("http: cannot encode %T as MessagePack", v)
+	}
+}
+
+func appendMsgpackInt(b []byte, n int64) []byte {
+	switch {
+	case n >= 0 && n <= math.MaxInt8:
+		return append(b, byte(n))
+	case n < 0 && n >= -32:
+		return append(b, byte(int8(n)))
+	case n >= 0 && n <= math.MaxUint8:
+		return append(b, 0xcc, byte(n))
+	case n >= 0 && n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(n))
+	case n >= 0 && n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(n))
+	case n >= 0:
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), uint64(n))
+	case n >= math.MinInt8:
+		return append(b, 0xd0, byte(int8(n)))
+	case n >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(int16(n)))
+	case n >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(int32(n)))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(n))
+	}
+}
+
+// appendMsgpackHeader appends the type and length of a string, array or
+// map: the fixed form below fixLimit, then 8-bit (strings only), 16-bit
+// and 32-bit lengths.
+func appendMsgpackHeader(b []byte, n int, fix byte, fixLimit int, code8, code16, code32 byte) []byte {
+	switch {
+	case n < fixLimit:
+		return append(b, fix|byte(n))
+	case code8 != 0 && n <= math.MaxUint8:
+		return append(b, code8, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, code16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, code32), uint32(n))
+	}
+}
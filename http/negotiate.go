@@ -0,0 +1,215 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Response formats for Negotiate.
+const (
+	FormatJSON    = "json"
+	FormatXML     = "xml"
+	FormatMsgpack = "msgpack"
+)
+
+// MIMEMsgpack is the content type of MessagePack responses.
+const MIMEMsgpack = "application/msgpack"
+
+// Transformer shapes a value for API responses, so that models are not
+// serialized as they are stored. Negotiate, JSONResponse, Created and
+// Accepted call ToMap on values that implement it, and on each element of
+// slices of them. The context allows fields that depend on the request,
+// such as ones only admins see.
+//
+//	func (u UserResource) ToMap(ctx *http.Context) map[string]any {
+//		return map[string]any{"id": u.ID, "name": u.Name}
+//	}
+type Transformer interface {
+	ToMap(ctx *Context) map[string]any
+}
+
+var transformerType = reflect.TypeOf((*Transformer)(nil)).Elem()
+
+// responseFormat writes a value as the response body.
+type responseFormat struct {
+	contentType string
+	write       func(c *fiber.Ctx, v any) error
+}
+
+var (
+	responseFormats = map[string]responseFormat{
+		FormatJSON:    {fiber.MIMEApplicationJSON, writeJSON},
+		FormatXML:     {fiber.MIMEApplicationXMLCharsetUTF8, writeXML},
+		FormatMsgpack: {MIMEMsgpack, writeMsgpack},
+	}
+	defaultFormats = []string{FormatJSON, FormatXML, FormatMsgpack}
+	formatsMu      sync.RWMutex
+)
+
+// RegisterFormat makes a response format available to Negotiate under
+// name, for clients that accept contentType.
+func RegisterFormat(name, contentType string, encode func(v any) ([]byte, error)) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	responseFormats[name] = responseFormat{contentType, func(c *fiber.Ctx, v any) error {
+		body, err := encode(v)
+		if err != nil {
+			return err
+		}
+		c.Response().SetBody(body)
+		c.Response().Header.SetContentType(contentType)
+		return nil
+	}}
+}
+
+// Negotiate sends data in the format the request's Accept header prefers
+// among formats, which default to JSON, XML and MessagePack. Without an
+// Accept header the first format is used, and when none is acceptable the
+// response is 406 Not Acceptable.
+//
+//	return ctx.Negotiate(users)                  // JSON, XML or MessagePack
+//	return ctx.Negotiate(report, http.FormatXML) // XML only
+//
+// Maps and slices are sent as XML elements under a <response> root, with
+// slice elements named <item>; structs use their `xml` tags. MessagePack
+// bodies are encoded from the value's JSON form, so `json` tags apply.
+func (c *Context) Negotiate(data any, formats ...string) error {
+	if len(formats) == 0 {
+		formats = defaultFormats
+	}
+
+	formatsMu.RLock()
+	offers := make([]responseFormat, len(formats))
+	types := make([]string, len(formats))
+	for i, name := range formats {
+		format, ok := responseFormats[name]
+		if !ok {
+			formatsMu.RUnlock()
+			return fmt.Errorf("http: response format %q is not registered", name)
+		}
+		offers[i] = format
+		types[i] = format.contentType
+	}
+	formatsMu.RUnlock()
+
+	c.fiberCtx.Vary(fiber.HeaderAccept)
+	accepted := c.fiberCtx.Accepts(types...)
+	for _, offer := range offers {
+		if offer.contentType == accepted {
+			return offer.write(c.fiberCtx, c.transform(data))
+		}
+	}
+	return fiber.NewError(fiber.StatusNotAcceptable, "Not Acceptable")
+}
+
+// transform applies Transformer to v, or to each element of a slice of
+// transformers.
+func (c *Context) transform(v any) any {
+	if t, ok := v.(Transformer); ok {
+		return t.ToMap(c)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || !rv.Type().Elem().Implements(transformerType) {
+		return v
+	}
+	items := make([]map[string]any, rv.Len())
+	for i := range items {
+		if t, ok := rv.Index(i).Interface().(Transformer); ok && t != nil {
+			items[i] = t.ToMap(c)
+		}
+	}
+	return items
+}
+
+// writeXML encodes v as XML. Structs use encoding/xml; other values go
+// through their JSON form, which encoding/xml cannot express for maps.
+func writeXML(c *fiber.Ctx, v any) error {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() == reflect.Struct {
+		if err := xml.NewEncoder(&buf).Encode(v); err != nil {
+			return err
+		}
+	} else {
+		generic, err := jsonValue(c, v)
+		if err != nil {
+			return err
+		}
+		enc := xml.NewEncoder(&buf)
+		if err := encodeXMLElement(enc, "response", generic); err != nil {
+			return err
+		}
+		if err := enc.Flush(); err != nil {
+			return err
+		}
+	}
+
+	c.Response().SetBody(buf.Bytes())
+	c.Response().Header.SetContentType(fiber.MIMEApplicationXMLCharsetUTF8)
+	return nil
+}
+
+// encodeXMLElement writes a value decoded from JSON as an element.
+func encodeXMLElement(enc *xml.Encoder, name string, v any) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch value := v.(type) {
+	case map[string]any:
+		for _, key := range sortedKeys(value) {
+			if err := encodeXMLElement(enc, key, value[key]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range value {
+			if err := encodeXMLElement(enc, "item", item); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		if err := enc.EncodeToken(xml.CharData(fmt.Sprint(value))); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+// jsonValue returns v as decoded from its JSON encoding by the app's codec:
+// maps, slices, strings, booleans, json.Number and nil.
+func jsonValue(c *fiber.Ctx, v any) (any, error) {
+	data, err := codecFor(c).Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
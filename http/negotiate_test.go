@@ -0,0 +1,123 @@
+package http
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type userModel struct {
+	ID       int
+	Name     string
+	Password string
+}
+
+type userResource struct{ userModel }
+
+func (u userResource) ToMap(ctx *Context) map[string]any {
+	data := map[string]any{"id": u.ID, "name": u.Name}
+	if ctx.Query("admin") != "" {
+		data["has_password"] = u.Password != ""
+	}
+	return data
+}
+
+type xmlReport struct {
+	XMLName xml.Name `xml:"report"`
+	Title   string   `xml:"title,attr"`
+}
+
+func TestContextNegotiate(t *testing.T) {
+	data := map[string]any{"id": 1, "tags": []string{"a"}}
+	handler := func(ctx *Context) error { return ctx.Negotiate(data) }
+
+	status, headers, body := conditionalGet(t, "GET", nil, handler)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "application/json", headers["Content-Type"])
+	assert.Equal(t, `{"id":1,"tags":["a"]}`, body)
+	assert.Equal(t, "Accept", headers["Vary"])
+
+	_, headers, body = conditionalGet(t, "GET", map[string]string{"Accept": "application/xml;q=0.9, application/json;q=0.5"}, handler)
+	assert.Equal(t, "application/xml; charset=utf-8", headers["Content-Type"])
+	assert.Equal(t, xml.Header+`<response><id>1</id><tags><item>a</item></tags></response>`, body)
+
+	_, headers, body = conditionalGet(t, "GET", map[string]string{"Accept": "application/msgpack"}, handler)
+	assert.Equal(t, MIMEMsgpack, headers["Content-Type"])
+	// {"id": 1, "tags": ["a"]}
+	assert.Equal(t, "\x82\xa2id\x01\xa4tags\x91\xa1a", body)
+
+	status, _, _ = conditionalGet(t, "GET", map[string]string{"Accept": "text/csv"}, handler)
+	assert.Equal(t, 406, status)
+}
+
+func TestContextNegotiateFormats(t *testing.T) {
+	status, _, _ := conditionalGet(t, "GET", map[string]string{"Accept": "application/json"}, func(ctx *Context) error {
+		return ctx.Negotiate(xmlReport{Title: "Q3"}, FormatXML)
+	})
+	assert.Equal(t, 406, status)
+
+	_, _, body := conditionalGet(t, "GET", map[string]string{"Accept": "*/*"}, func(ctx *Context) error {
+		return ctx.Negotiate(xmlReport{Title: "Q3"}, FormatXML)
+	})
+	assert.Equal(t, xml.Header+`<report title="Q3"></report>`, body)
+
+	RegisterFormat("csv", "text/csv", func(v any) ([]byte, error) {
+		return []byte(strings.Join(v.([]string), ",")), nil
+	})
+	_, headers, body := conditionalGet(t, "GET", map[string]string{"Accept": "text/csv"}, func(ctx *Context) error {
+		return ctx.Negotiate([]string{"a", "b"}, FormatJSON, "csv")
+	})
+	assert.Equal(t, "text/csv", headers["Content-Type"])
+	assert.Equal(t, "a,b", body)
+
+	status, _, _ = conditionalGet(t, "GET", nil, func(ctx *Context) error {
+		return ctx.Negotiate(nil, "yaml")
+	})
+	assert.Equal(t, 500, status)
+}
+
+func TestContextTransformers(t *testing.T) {
+	users := []userResource{{userModel{1, "Ada", "secret"}}, {userModel{2, "Grace", ""}}}
+
+	_, _, body := conditionalGet(t, "GET", nil, func(ctx *Context) error {
+		return ctx.JSONResponse(users[0])
+	})
+	assert.Equal(t, `{"id":1,"name":"Ada"}`, body)
+	assert.NotContains(t, body, "secret")
+
+	_, _, body = conditionalGet(t, "GET", nil, func(ctx *Context) error {
+		return ctx.Negotiate(users)
+	})
+	assert.Equal(t, `[{"id":1,"name":"Ada"},{"id":2,"name":"Grace"}]`, body)
+
+	status, _, body := conditionalGet(t, "POST", nil, func(ctx *Context) error {
+		return ctx.Created(users[1])
+	})
+	assert.Equal(t, 201, status)
+	assert.Equal(t, `{"id":2,"name":"Grace"}`, body)
+}
+
+func TestAppendMsgpack(t *testing.T) {
+	cases := []struct {
+		value any
+		want  []byte
+	}{
+		{nil, []byte{0xc0}},
+		{true, []byte{0xc3}},
+		{json.Number("-1"), []byte{0xff}},
+		{json.Number("200"), []byte{0xcc, 0xc8}},
+		{json.Number("-200"), []byte{0xd1, 0xff, 0x38}},
+		{json.Number("70000"), []byte{0xce, 0x00, 0x01, 0x11, 0x70}},
+		{json.Number("1.5"), []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{strings.Repeat("x", 40), append([]byte{0xd9, 40}, strings.Repeat("x", 40)...)},
+	}
+	for _, tc := range cases {
+		got, err := appendMsgpack(nil, tc.value)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, got, "%v", tc.value)
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// precompressed lists the sibling files checked for pre-compressed copies,
+// in order of preference.
+var precompressed = []struct {
+	encoding, ext string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// negotiatePrecompressed picks the preferred pre-compressed sibling the client
+// accepts. It returns empty strings when none is acceptable or exists.
+func negotiatePrecompressed(acceptEncoding string, exists func(ext string) bool) (encoding, ext string) {
+	if acceptEncoding == "" {
+		return "", ""
+	}
+	for _, candidate := range precompressed {
+		if acceptsEncoding(acceptEncoding, candidate.encoding) && exists(candidate.ext) {
+			return candidate.encoding, candidate.ext
+		}
+	}
+	return "", ""
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header allows encoding.
+// An explicit entry takes precedence over "*", and q=0 refuses an encoding.
+func acceptsEncoding(header, encoding string) bool {
+	wildcard := false
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != encoding && name != "*" {
+			continue
+		}
+
+		accepted := true
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(param, "=")
+			if strings.TrimSpace(key) == "q" {
+				q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				accepted = err == nil && q > 0
+			}
+		}
+
+		if name == encoding {
+			return accepted
+		}
+		wildcard = accepted
+	}
+	return wildcard
+}
+
+// precompressedStatic serves .br and .gz siblings of files under root to
+// clients that accept them, and passes everything else on to the static handler.
+func precompressedStatic(prefix, root string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return c.Next()
+		}
+
+		rel := pathpkg.Clean("/" + strings.TrimPrefix(c.Path(), prefix))
+		name := filepath.Join(root, filepath.FromSlash(rel))
+		if info, err := os.Stat(name); err != nil || !info.Mode().IsRegular() {
+			return c.Next()
+		}
+
+		c.Vary(fiber.HeaderAcceptEncoding)
+		encoding, ext := negotiatePrecompressed(c.Get(fiber.HeaderAcceptEncoding), func(ext string) bool {
+			info, err := os.Stat(name + ext)
+			return err == nil && info.Mode().IsRegular()
+		})
+		if encoding == "" {
+			return c.Next()
+		}
+
+		if err := c.SendFile(name + ext); err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderContentEncoding, encoding)
+		c.Type(strings.TrimPrefix(filepath.Ext(name), "."))
+		return nil
+	}
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/facades/storage"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptsEncoding(t *testing.T) {
+	tests := []struct {
+		header, encoding string
+		want             bool
+	}{
+		{"gzip, deflate, br", "br", true},
+		{"gzip", "br", false},
+		{"br;q=0, gzip", "br", false},
+		{"br;q=0.5", "br", true},
+		{"*", "gzip", true},
+		{"*;q=0", "gzip", false},
+		{"gzip;q=0, *", "gzip", false},
+		{"GZIP", "gzip", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, acceptsEncoding(tt.header, tt.encoding), "%q accepts %s", tt.header, tt.encoding)
+	}
+}
+
+func TestStaticServesPrecompressed(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "app.js"), []byte("console.log(1)"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "app.js.br"), []byte("brotli"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "app.js.gz"), []byte("gzipped"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "app.css"), []byte("body{}"), 0644))
+
+	app := newTestApp()
+	NewRouter(&mockApplication{}, app).Static("/assets", root)
+
+	fetch := func(path, acceptEncoding string) (string, string, string) {
+		req := httptest.NewRequest("GET", path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		return string(body), resp.Header.Get("Content-Encoding"), resp.Header.Get("Content-Type")
+	}
+
+	body, encoding, contentType := fetch("/assets/app.js", "gzip, br")
+	assert.Equal(t, "brotli", body)
+	assert.Equal(t, "br", encoding)
+	assert.Contains(t, contentType, "javascript")
+
+	body, encoding, _ = fetch("/assets/app.js", "gzip")
+	assert.Equal(t, "gzipped", body)
+	assert.Equal(t, "gzip", encoding)
+
+	body, encoding, _ = fetch("/assets/app.js", "")
+	assert.Equal(t, "console.log(1)", body)
+	assert.Empty(t, encoding)
+
+	// Files without siblings are served as usual.
+	body, encoding, _ = fetch("/assets/app.css", "br")
+	assert.Equal(t, "body{}", body)
+	assert.Empty(t, encoding)
+}
+
+func TestDiskResponseServesPrecompressed(t *testing.T) {
+	disk := storage.Fake()
+	defer storage.Restore()
+	ctx := context.Background()
+	require.NoError(t, disk.Put(ctx, "bundles/app.js", "console.log(1)"))
+	require.NoError(t, disk.Put(ctx, "bundles/app.js.gz", "gzipped"))
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return NewContext(c, &mockApplication{}).FileResponse("bundles/app.js")
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "br, gzip")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, "gzipped", string(body))
+	assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+	assert.Equal(t, "7", resp.Header.Get("Content-Length"))
+	assert.Contains(t, resp.Header.Get("Content-Type"), "javascript")
+	assert.Equal(t, `inline; filename=app.js`, resp.Header.Get("Content-Disposition"))
+	assert.Equal(t, "Accept-Encoding", resp.Header.Get("Vary"))
+}
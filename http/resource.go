@@ -0,0 +1,151 @@
+package http
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Resource actions, in the order their routes are registered.
+const (
+	ActionIndex   = "index"
+	ActionCreate  = "create"
+	ActionStore   = "store"
+	ActionShow    = "show"
+	ActionEdit    = "edit"
+	ActionUpdate  = "update"
+	ActionDestroy = "destroy"
+)
+
+var resourceActions = []string{ActionIndex, ActionCreate, ActionStore, ActionShow, ActionEdit, ActionUpdate, ActionDestroy}
+
+// ResourceController defines the interface for resourceful controllers.
+// Controllers generated by `make:controller --resource` implement it.
+type ResourceController interface {
+	Index(ctx *Context) error
+	Store(ctx *Context) error
+	Show(ctx *Context) error
+	Update(ctx *Context) error
+	Destroy(ctx *Context) error
+}
+
+// ResourceFormController is implemented by resource controllers that also
+// serve the create and edit forms. Resource registers those routes only for
+// controllers that implement it.
+type ResourceFormController interface {
+	Create(ctx *Context) error
+	Edit(ctx *Context) error
+}
+
+// APIResourceController defines the interface for API resourceful controllers.
+type APIResourceController = ResourceController
+
+// ResourceOption limits the routes registered by Resource and APIResource.
+type ResourceOption func(actions []string) []string
+
+// Only registers just the given resource actions.
+func Only(actions ...string) ResourceOption {
+	checkResourceActions(actions)
+	return func(registered []string) []string {
+		return slices.DeleteFunc(registered, func(action string) bool {
+			return !slices.Contains(actions, action)
+		})
+	}
+}
+
+// Except registers all resource actions but the given ones.
+func Except(actions ...string) ResourceOption {
+	checkResourceActions(actions)
+	return func(registered []string) []string {
+		return slices.DeleteFunc(registered, func(action string) bool {
+			return slices.Contains(actions, action)
+		})
+	}
+}
+
+// checkResourceActions panics on unknown actions, so typos fail at startup
+// rather than silently dropping routes.
+func checkResourceActions(actions []string) {
+	for _, action := range actions {
+		if !slices.Contains(resourceActions, action) {
+			panic(fmt.Sprintf("http: unknown resource action %q", action))
+		}
+	}
+}
+
+// Resource creates RESTful routes for a resource and returns them:
+//
+//	GET    /users           users.index
+//	GET    /users/create    users.create  (ResourceFormController only)
+//	POST   /users           users.store
+//	GET    /users/:id       users.show
+//	GET    /users/:id/edit  users.edit    (ResourceFormController only)
+//	PUT    /users/:id       users.update
+//	PATCH  /users/:id       users.update.patch
+//	DELETE /users/:id       users.destroy
+//
+// Route names join the path's static segments, so "/photos/:photo/comments"
+// is named "photos.comments.index" and so on.
+func (r *Router) Resource(path string, controller ResourceController, options ...ResourceOption) []*Route {
+	actions := slices.Clone(resourceActions)
+	if _, ok := controller.(ResourceFormController); !ok {
+		actions = slices.DeleteFunc(actions, isFormAction)
+	}
+	return r.resource(path, controller, actions, options)
+}
+
+// APIResource creates API RESTful routes, like Resource without the create
+// and edit forms.
+func (r *Router) APIResource(path string, controller APIResourceController, options ...ResourceOption) []*Route {
+	actions := slices.DeleteFunc(slices.Clone(resourceActions), isFormAction)
+	return r.resource(path, controller, actions, options)
+}
+
+func isFormAction(action string) bool {
+	return action == ActionCreate || action == ActionEdit
+}
+
+func (r *Router) resource(path string, controller ResourceController, actions []string, options []ResourceOption) []*Route {
+	for _, option := range options {
+		actions = option(actions)
+	}
+
+	path = "/" + strings.Trim(path, "/")
+	name := resourceName(path)
+	forms, _ := controller.(ResourceFormController)
+
+	var routes []*Route
+	for _, action := range actions {
+		switch action {
+		case ActionIndex:
+			routes = append(routes, r.GET(path, controller.Index).Name(name+".index"))
+		case ActionCreate:
+			routes = append(routes, r.GET(path+"/create", forms.Create).Name(name+".create"))
+		case ActionStore:
+			routes = append(routes, r.POST(path, controller.Store).Name(name+".store"))
+		case ActionShow:
+			routes = append(routes, r.GET(path+"/:id", controller.Show).Name(name+".show"))
+		case ActionEdit:
+			routes = append(routes, r.GET(path+"/:id/edit", forms.Edit).Name(name+".edit"))
+		case ActionUpdate:
+			routes = append(routes,
+				r.PUT(path+"/:id", controller.Update).Name(name+".update"),
+				r.PATCH(path+"/:id", controller.Update).Name(name+".update.patch"),
+			)
+		case ActionDestroy:
+			routes = append(routes, r.DELETE(path+"/:id", controller.Destroy).Name(name+".destroy"))
+		}
+	}
+	return routes
+}
+
+// resourceName joins the static segments of a resource path with dots.
+func resourceName(path string) string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" && !strings.HasPrefix(segment, ":") && !strings.HasPrefix(segment, "*") {
+			segments = append(segments, segment)
+		}
+	}
+	return strings.Join(segments, ".")
+}
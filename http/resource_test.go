@@ -0,0 +1,93 @@
+package http
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type photoController struct{}
+
+func (photoController) Index(ctx *Context) error   { return ctx.String("index") }
+func (photoController) Store(ctx *Context) error   { return ctx.String("store") }
+func (photoController) Show(ctx *Context) error    { return ctx.String("show " + ctx.Param("id")) }
+func (photoController) Update(ctx *Context) error  { return ctx.String("update " + ctx.Param("id")) }
+func (photoController) Destroy(ctx *Context) error { return ctx.String("destroy " + ctx.Param("id")) }
+
+type photoFormController struct{ photoController }
+
+func (photoFormController) Create(ctx *Context) error { return ctx.String("create") }
+func (photoFormController) Edit(ctx *Context) error   { return ctx.String("edit " + ctx.Param("id")) }
+
+func routeNames(routes []*Route) []string {
+	names := make([]string, len(routes))
+	for i, route := range routes {
+		names[i] = route.GetMethod() + " " + route.GetPath() + " " + route.GetName()
+	}
+	return names
+}
+
+func TestResourceRoutes(t *testing.T) {
+	app := newTestApp()
+	router := NewRouter(&mockApplication{}, app)
+
+	routes := router.Resource("/photos", photoFormController{})
+	assert.Equal(t, []string{
+		"GET /photos photos.index",
+		"GET /photos/create photos.create",
+		"POST /photos photos.store",
+		"GET /photos/:id photos.show",
+		"GET /photos/:id/edit photos.edit",
+		"PUT /photos/:id photos.update",
+		"PATCH /photos/:id photos.update.patch",
+		"DELETE /photos/:id photos.destroy",
+	}, routeNames(routes))
+
+	for request, body := range map[[2]string]string{
+		{"GET", "/photos/create"}: "create",
+		{"GET", "/photos/7"}:      "show 7",
+		{"GET", "/photos/7/edit"}: "edit 7",
+		{"PATCH", "/photos/7"}:    "update 7",
+		{"DELETE", "/photos/7"}:   "destroy 7",
+	} {
+		resp, err := app.Test(httptest.NewRequest(request[0], request[1], nil))
+		require.NoError(t, err)
+		got, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, body, string(got), request)
+	}
+}
+
+func TestResourceWithoutForms(t *testing.T) {
+	router := NewRouter(&mockApplication{}, newTestApp())
+
+	routes := router.Resource("photos", photoController{})
+	assert.Len(t, routes, 6)
+	assert.Nil(t, router.NamedRoute("photos.create"))
+	assert.Nil(t, router.NamedRoute("photos.edit"))
+}
+
+func TestAPIResourceOnlyAndExcept(t *testing.T) {
+	router := NewRouter(&mockApplication{}, newTestApp())
+
+	routes := router.APIResource("/photos/:photo/comments", photoFormController{}, Only(ActionIndex, ActionShow, ActionCreate))
+	assert.Equal(t, []string{
+		"GET /photos/:photo/comments photos.comments.index",
+		"GET /photos/:photo/comments/:id photos.comments.show",
+	}, routeNames(routes))
+
+	routes = router.APIResource("/tags", photoController{}, Except(ActionUpdate, ActionDestroy))
+	assert.Equal(t, []string{
+		"GET /tags tags.index",
+		"POST /tags tags.store",
+		"GET /tags/:id tags.show",
+	}, routeNames(routes))
+}
+
+func TestResourceUnknownAction(t *testing.T) {
+	assert.PanicsWithValue(t, `http: unknown resource action "list"`, func() {
+		Only("list")
+	})
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"encoding/json"
 	"encoding/xml"
+	"fmt"
 	"io"
 
 	"github.com/genesysflow/go-genesys/contracts"
@@ -80,10 +81,10 @@ func (r *Response) String(s string) error {
 	return r.ctx.SendString(s)
 }
 
-// JSON sends a JSON response.
+// JSON sends a JSON response, encoded with the kernel's JSON codec.
 func (r *Response) JSON(v any) error {
 	r.sent = true
-	return r.ctx.JSON(v)
+	return writeJSON(r.ctx, v)
 }
 
 // PrettyJSON sends a pretty-printed JSON response.
@@ -160,12 +161,18 @@ func (r *Response) RedirectBack(fallback ...string) error {
 }
 
 // RedirectRoute redirects to a named route.
-// Note: This requires the router to support named routes.
 func (r *Response) RedirectRoute(name string, params ...map[string]any) error {
+	router := routerFor(r.ctx)
+	if router == nil {
+		return fmt.Errorf("http: no router is handling this request")
+	}
+	path, err := router.buildURL(name, params...)
+	if err != nil {
+		return err
+	}
+
 	r.sent = true
-	// For now, redirect to the name as a path
-	// Full implementation would look up the route by name
-	return r.ctx.Redirect("/" + name)
+	return r.ctx.Redirect(router.baseURL() + path)
 }
 
 // NoContent sends a 204 No Content response.
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Common parameter patterns for Route.Where.
+const (
+	PatternNumber       = `[0-9]+`
+	PatternAlpha        = `[a-zA-Z]+`
+	PatternAlphaNumeric = `[a-zA-Z0-9]+`
+	PatternUUID         = `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`
+)
+
+// ErrModelNotFound can be returned by a BindingResolver to respond with 404.
+// Resolvers may also return sql.ErrNoRows, or a nil value.
+var ErrModelNotFound = errors.New("http: model not found")
+
+// BindingResolver resolves a route parameter value into an entity.
+type BindingResolver func(ctx *Context, value string) (any, error)
+
+// boundKeyPrefix namespaces bound entities in the context store.
+const boundKeyPrefix = "http.bound."
+
+// Where constrains a route parameter to a regular expression. The pattern
+// must match the whole segment; requests that don't match fall through to
+// later routes, or 404. It panics if the pattern is invalid, since routes
+// are registered at boot.
+func (r *Route) Where(param, pattern string) *Route {
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		panic(fmt.Sprintf("route %s %s: invalid pattern for %q: %v", r.method, r.path, param, err))
+	}
+	if r.constraints == nil {
+		r.constraints = make(map[string]*regexp.Regexp)
+	}
+	r.constraints[param] = re
+	return r
+}
+
+// WhereNumber constrains parameters to digits.
+func (r *Route) WhereNumber(params ...string) *Route {
+	return r.whereAll(params, PatternNumber)
+}
+
+// WhereAlpha constrains parameters to letters.
+func (r *Route) WhereAlpha(params ...string) *Route {
+	return r.whereAll(params, PatternAlpha)
+}
+
+// WhereAlphaNumeric constrains parameters to letters and digits.
+func (r *Route) WhereAlphaNumeric(params ...string) *Route {
+	return r.whereAll(params, PatternAlphaNumeric)
+}
+
+// WhereUUID constrains parameters to UUIDs.
+func (r *Route) WhereUUID(params ...string) *Route {
+	return r.whereAll(params, PatternUUID)
+}
+
+// WhereIn constrains a parameter to one of the given values.
+func (r *Route) WhereIn(param string, values ...string) *Route {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = regexp.QuoteMeta(value)
+	}
+	return r.Where(param, strings.Join(quoted, "|"))
+}
+
+func (r *Route) whereAll(params []string, pattern string) *Route {
+	for _, param := range params {
+		r.Where(param, pattern)
+	}
+	return r
+}
+
+// Bind resolves a parameter of this route with resolver before the handler
+// runs. It takes precedence over bindings registered with Router.Bind.
+func (r *Route) Bind(param string, resolver BindingResolver) *Route {
+	if r.bindings == nil {
+		r.bindings = make(map[string]BindingResolver)
+	}
+	r.bindings[param] = resolver
+	return r
+}
+
+// Bind resolves a parameter with resolver on every route of the router and
+// its groups that has it, before the handler runs.
+func (r *Router) Bind(param string, resolver BindingResolver) {
+	if r.registry.bindings == nil {
+		r.registry.bindings = make(map[string]BindingResolver)
+	}
+	r.registry.bindings[param] = resolver
+}
+
+// matchesConstraints reports whether the request's parameters satisfy the
+// route's constraints. Missing optional parameters are not checked.
+func (r *Route) matchesConstraints(c *fiber.Ctx) bool {
+	for param, re := range r.constraints {
+		if value := c.Params(param); value != "" && !re.MatchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// failedConstraint returns the first parameter that fails a constraint.
+func (r *Route) failedConstraint(params map[string]string) (string, bool) {
+	for param, re := range r.constraints {
+		if value := params[param]; value != "" && !re.MatchString(value) {
+			return param, true
+		}
+	}
+	return "", false
+}
+
+// resolveBindings resolves bound parameters into the context. Missing
+// entities are reported as 404 errors.
+func (r *Route) resolveBindings(ctx *Context) error {
+	for _, param := range routeParamNames(r.path) {
+		resolver := r.bindings[param]
+		if resolver == nil && r.router != nil {
+			resolver = r.router.registry.bindings[param]
+		}
+		value := ctx.Param(param)
+		if resolver == nil || value == "" {
+			continue
+		}
+
+		entity, err := resolver(ctx, value)
+		if errors.Is(err, ErrModelNotFound) || errors.Is(err, sql.ErrNoRows) || (err == nil && entity == nil) {
+			return fiber.NewError(fiber.StatusNotFound, "Not Found")
+		}
+		if err != nil {
+			return err
+		}
+		ctx.Set(boundKeyPrefix+param, entity)
+	}
+	return nil
+}
+
+// routeParamNames returns the parameter names in a route path, in order.
+func routeParamNames(path string) []string {
+	var names []string
+	for _, seg := range splitRoutePath(path) {
+		if isParamSegment(seg) {
+			names = append(names, strings.TrimSuffix(seg[1:], "?"))
+		}
+	}
+	return names
+}
+
+// Bound returns the entity a route binding resolved for a parameter, or nil.
+func (c *Context) Bound(param string) any {
+	return c.Get(boundKeyPrefix + param)
+}
+
+// BoundAs returns the entity bound to a parameter as T.
+func BoundAs[T any](c *Context, param string) (T, bool) {
+	value, ok := c.Bound(param).(T)
+	return value, ok
+}
@@ -0,0 +1,160 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type boundUser struct {
+	ID   string
+	Name string
+}
+
+// request sends a GET request and returns the status and body.
+func request(t *testing.T, router *Router, path string) (int, string) {
+	t.Helper()
+	resp, err := router.fiber.Test(httptest.NewRequest("GET", path, nil))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestRouteConstraints(t *testing.T) {
+	router := NewRouter(&mockApplication{}, newTestApp())
+
+	router.GET("/users/:id", func(ctx *Context) error {
+		return ctx.String("id " + ctx.Param("id"))
+	}).WhereNumber("id")
+	router.GET("/users/:name", func(ctx *Context) error {
+		return ctx.String("name " + ctx.Param("name"))
+	}).WhereAlpha("name")
+	router.GET("/orders/:order", func(ctx *Context) error {
+		return ctx.String("order")
+	}).WhereUUID("order")
+	router.GET("/posts/:status", func(ctx *Context) error {
+		return ctx.String(ctx.Param("status"))
+	}).WhereIn("status", "draft", "published")
+	router.GET("/tags/:slug?", func(ctx *Context) error {
+		return ctx.String("tags")
+	}).Where("slug", `[a-z-]+`)
+
+	for path, want := range map[string]string{
+		"/users/42":    "id 42",
+		"/users/alice": "name alice",
+		"/orders/9b2f1c3e-4d5a-4b6c-8d7e-0f1a2b3c4d5e": "order",
+		"/posts/draft":  "draft",
+		"/tags":         "tags",
+		"/tags/go-lang": "tags",
+	} {
+		status, body := request(t, router, path)
+		assert.Equal(t, 200, status, path)
+		assert.Equal(t, want, body, path)
+	}
+
+	for _, path := range []string{"/users/4a!", "/orders/42", "/posts/archived", "/tags/Go"} {
+		status, _ := request(t, router, path)
+		assert.Equal(t, 404, status, path)
+	}
+
+	assert.Panics(t, func() {
+		router.GET("/bad/:id", nil).Where("id", "[")
+	})
+}
+
+func TestRouterDebugReportsConstraints(t *testing.T) {
+	router := NewRouter(&mockApplication{}, newTestApp())
+	handler := func(ctx *Context) error { return nil }
+
+	router.GET("/users/:id", handler).WhereNumber("id")
+	router.GET("/users/me", handler)
+
+	trace := router.Debug("GET", "/users/me")
+	require.NotNil(t, trace.Matched)
+	assert.Equal(t, "/users/me", trace.Matched.GetPath())
+	assert.Contains(t, trace.Entries[0].Reason, `parameter "id" does not match`)
+}
+
+func TestRouteModelBinding(t *testing.T) {
+	router := NewRouter(&mockApplication{}, newTestApp())
+	users := map[string]*boundUser{"1": {ID: "1", Name: "Ada"}}
+
+	router.Bind("user", func(ctx *Context, value string) (any, error) {
+		if user, ok := users[value]; ok {
+			return user, nil
+		}
+		return nil, ErrModelNotFound
+	})
+
+	var middlewareSaw any
+	router.Group("/api", func(r *Router) {
+		r.GET("/users/:user", func(ctx *Context) error {
+			user, ok := BoundAs[*boundUser](ctx, "user")
+			require.True(t, ok)
+			return ctx.String(user.Name)
+		}, func(ctx *Context, next func() error) error {
+			// Bindings resolve after middleware, so authorization runs first.
+			middlewareSaw = ctx.Bound("user")
+			return next()
+		})
+	})
+
+	status, body := request(t, router, "/api/users/1")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "Ada", body)
+	assert.Nil(t, middlewareSaw)
+
+	status, _ = request(t, router, "/api/users/2")
+	assert.Equal(t, 404, status)
+}
+
+func TestRouteBindOverridesRouter(t *testing.T) {
+	router := NewRouter(&mockApplication{}, newTestApp())
+	router.Bind("post", func(ctx *Context, value string) (any, error) {
+		return "router", nil
+	})
+
+	router.GET("/posts/:post", func(ctx *Context) error {
+		return ctx.String(ctx.Bound("post").(string))
+	}).Bind("post", func(ctx *Context, value string) (any, error) {
+		switch value {
+		case "missing":
+			return nil, sql.ErrNoRows
+		case "nil":
+			return nil, nil
+		case "broken":
+			return nil, errors.New("database unavailable")
+		}
+		return "route " + value, nil
+	})
+
+	status, body := request(t, router, "/posts/7")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "route 7", body)
+
+	status, _ = request(t, router, "/posts/missing")
+	assert.Equal(t, 404, status)
+	status, _ = request(t, router, "/posts/nil")
+	assert.Equal(t, 404, status)
+	status, _ = request(t, router, "/posts/broken")
+	assert.Equal(t, 500, status)
+}
+
+func TestRouteMiddlewareAddedAfterRegistration(t *testing.T) {
+	router := NewRouter(&mockApplication{}, newTestApp())
+
+	router.GET("/admin", func(ctx *Context) error {
+		return ctx.String("admin")
+	}).Middleware(func(ctx *Context, next func() error) error {
+		return ctx.Status(403).String("forbidden")
+	})
+
+	status, body := request(t, router, "/admin")
+	assert.Equal(t, 403, status)
+	assert.Equal(t, "forbidden", body)
+}
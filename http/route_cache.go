@@ -0,0 +1,116 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// RouteCache is the route table `route:cache` writes. Handlers are code
+// and are always registered by the app's route definitions; the cache
+// spares the router the per-route work of booting: finding where each route
+// was registered and checking it against all earlier routes for conflicts.
+// Routes that no longer match the cache are checked as usual.
+type RouteCache struct {
+	Routes    []RouteInfo           `json:"routes"`
+	Conflicts []CachedRouteConflict `json:"conflicts,omitempty"`
+}
+
+// CachedRouteConflict is a RouteConflict by route index.
+type CachedRouteConflict struct {
+	Existing    int    `json:"existing"`
+	Conflicting int    `json:"conflicting"`
+	Reason      string `json:"reason"`
+}
+
+// RouteCachePath returns where the route cache of an app is kept:
+// storage/framework/routes.json.
+func RouteCachePath(app contracts.Application) string {
+	return filepath.Join(app.StoragePath(), "framework", "routes.json")
+}
+
+// RouteCache returns the router's route table for caching.
+func (r *Router) RouteCache() *RouteCache {
+	index := make(map[*Route]int, len(r.registry.routes))
+	for i, route := range r.registry.routes {
+		index[route] = i
+	}
+
+	cache := &RouteCache{Routes: r.RouteList()}
+	for _, conflict := range r.registry.conflicts {
+		cache.Conflicts = append(cache.Conflicts, CachedRouteConflict{
+			Existing:    index[conflict.Existing],
+			Conflicting: index[conflict.Conflicting],
+			Reason:      conflict.Reason,
+		})
+	}
+	return cache
+}
+
+// UseRouteCache makes the router trust cache for routes registered in the
+// same order with the same methods and paths. Call it before registering
+// routes; the kernel does so when a cache file exists.
+func (r *Router) UseRouteCache(cache *RouteCache) *Router {
+	r.registry.cache = cache
+	return r
+}
+
+// WriteRouteCache writes a route cache to path, creating its directory.
+func WriteRouteCache(path string, cache *RouteCache) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadRouteCache reads a route cache written by WriteRouteCache.
+func LoadRouteCache(path string) (*RouteCache, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cache RouteCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("http: invalid route cache %s: %w", path, err)
+	}
+	return &cache, nil
+}
+
+// registerCachedRoute registers the route at the next index from the
+// cache, and reports false once routes stop matching it. A stale cache is
+// dropped with a warning, and later routes are checked as usual.
+func (r *Router) registerCachedRoute(route *Route) bool {
+	cache := r.registry.cache
+	if cache == nil {
+		return false
+	}
+
+	index := len(r.registry.routes)
+	if index >= len(cache.Routes) || cache.Routes[index].Method != route.method || cache.Routes[index].Path != route.path {
+		r.registry.cache = nil
+		if r.app != nil && r.app.GetLogger() != nil {
+			r.app.GetLogger().Warn("Route cache is stale; run `route:cache` again or `route:clear`",
+				"method", route.method, "path", route.path)
+		}
+		return false
+	}
+
+	route.source = cache.Routes[index].Source
+	r.registry.routes = append(r.registry.routes, route)
+	for _, cached := range cache.Conflicts {
+		if cached.Conflicting != index || cached.Existing >= index {
+			continue
+		}
+		conflict := RouteConflict{Existing: r.registry.routes[cached.Existing], Conflicting: route, Reason: cached.Reason}
+		r.registry.conflicts = append(r.registry.conflicts, conflict)
+		r.reportConflict(conflict)
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// RouteInfo describes a registered route, for route listings and the
+// route cache.
+type RouteInfo struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Name       string   `json:"name,omitempty"`
+	Handler    string   `json:"handler"`
+	Middleware []string `json:"middleware,omitempty"`
+	Source     string   `json:"source,omitempty"`
+}
+
+// Info describes the route.
+func (r *Route) Info() RouteInfo {
+	return RouteInfo{
+		Method:     r.method,
+		Path:       r.path,
+		Name:       r.name,
+		Handler:    r.HandlerName(),
+		Middleware: r.MiddlewareNames(),
+		Source:     r.source,
+	}
+}
+
+// HandlerName identifies the route's handler by its function name, such as
+// "controllers.(*UserController).Show". Anonymous handlers are named after
+// the function they are declared in.
+func (r *Route) HandlerName() string {
+	return funcName(r.handler)
+}
+
+// MiddlewareNames names the middleware that runs for the route, in order:
+// that of enclosing groups first, then the route's own.
+func (r *Route) MiddlewareNames() []string {
+	var middleware []MiddlewareFunc
+	if r.router != nil {
+		middleware = append(r.router.collectParentMiddleware(), r.router.middleware...)
+	}
+	middleware = append(middleware, r.middleware...)
+	if len(middleware) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(middleware))
+	for i, m := range middleware {
+		names[i] = funcName(m)
+	}
+	return names
+}
+
+// RouteList describes every route of the router and its groups, in
+// registration order.
+func (r *Router) RouteList() []RouteInfo {
+	routes := make([]RouteInfo, len(r.registry.routes))
+	for i, route := range r.registry.routes {
+		routes[i] = route.Info()
+	}
+	return routes
+}
+
+// closureSuffix matches the suffixes Go gives closures and method values.
+var closureSuffix = regexp.MustCompile(`(\.func\d+(\.\d+)*|\.gowrap\d+|-fm)+$`)
+
+// funcName returns a function's name without its package path or closure
+// suffixes: "middleware.RequestID" for the closure RequestID returns.
+func funcName(fn any) string {
+	v := reflect.ValueOf(fn)
+	if !v.IsValid() || v.Kind() != reflect.Func || v.IsNil() {
+		return ""
+	}
+	f := runtime.FuncForPC(v.Pointer())
+	if f == nil {
+		return ""
+	}
+
+	name := closureSuffix.ReplaceAllString(f.Name(), "")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
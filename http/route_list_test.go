@@ -0,0 +1,80 @@
+package http
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type listController struct{}
+
+func (listController) Show(ctx *Context) error { return nil }
+
+func auditMiddleware(ctx *Context, next func() error) error { return next() }
+
+func TestRouteList(t *testing.T) {
+	router := NewRouter(&mockApplication{}, newTestApp())
+	router.Use(CORS())
+
+	router.GET("/users/:id", listController{}.Show).Name("users.show")
+	router.Group("/admin", func(admin *Router) {
+		admin.POST("/reports", func(ctx *Context) error { return nil }, auditMiddleware)
+	}, ETag())
+
+	routes := router.RouteList()
+	require.Len(t, routes, 2)
+
+	assert.Equal(t, "GET", routes[0].Method)
+	assert.Equal(t, "/users/:id", routes[0].Path)
+	assert.Equal(t, "users.show", routes[0].Name)
+	assert.Equal(t, "http.listController.Show", routes[0].Handler)
+	assert.Equal(t, []string{"http.CORS"}, routes[0].Middleware)
+	assert.Contains(t, routes[0].Source, "route_list_test.go")
+
+	assert.Equal(t, "/admin/reports", routes[1].Path)
+	assert.Equal(t, "http.TestRouteList", routes[1].Handler)
+	assert.Equal(t, []string{"http.CORS", "http.ETag", "http.auditMiddleware"}, routes[1].Middleware)
+}
+
+func registerCacheRoutes(router *Router, extra bool) {
+	router.GET("/posts", func(ctx *Context) error { return nil })
+	router.GET("/posts/:id", func(ctx *Context) error { return nil })
+	router.GET("/posts/:slug", func(ctx *Context) error { return nil }) // conflicts
+	if extra {
+		router.GET("/tags", func(ctx *Context) error { return nil })
+	}
+}
+
+func TestRouteCache(t *testing.T) {
+	router := NewRouter(&mockApplication{}, newTestApp()).SetConflictMode(ConflictIgnore)
+	registerCacheRoutes(router, false)
+	require.Len(t, router.Conflicts(), 1)
+
+	path := filepath.Join(t.TempDir(), "framework", "routes.json")
+	require.NoError(t, WriteRouteCache(path, router.RouteCache()))
+	cache, err := LoadRouteCache(path)
+	require.NoError(t, err)
+	assert.Equal(t, router.RouteList(), cache.Routes)
+
+	// A cached router takes sources and conflicts from the cache.
+	cache.Routes[0].Source = "cached.go:1"
+	cached := NewRouter(&mockApplication{}, newTestApp()).SetConflictMode(ConflictIgnore).UseRouteCache(cache)
+	registerCacheRoutes(cached, false)
+	assert.Equal(t, "cached.go:1", cached.AllRoutes()[0].GetSource())
+	require.Len(t, cached.Conflicts(), 1)
+	assert.Equal(t, "/posts/:slug", cached.Conflicts()[0].Conflicting.GetPath())
+	assert.Equal(t, "/posts/:id", cached.Conflicts()[0].Existing.GetPath())
+
+	// Routes beyond a stale cache are checked as usual.
+	stale := NewRouter(&mockApplication{}, newTestApp()).SetConflictMode(ConflictIgnore).UseRouteCache(cache)
+	registerCacheRoutes(stale, true)
+	assert.Contains(t, stale.AllRoutes()[3].GetSource(), "route_list_test.go")
+	assert.Len(t, stale.Conflicts(), 1)
+}
+
+func TestLoadRouteCacheErrors(t *testing.T) {
+	_, err := LoadRouteCache(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
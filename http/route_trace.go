@@ -0,0 +1,324 @@
+package http
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// ConflictMode controls how the router reacts to conflicting route registrations.
+type ConflictMode int
+
+const (
+	// ConflictLog logs a warning with both registration call sites (default).
+	ConflictLog ConflictMode = iota
+
+	// ConflictPanic panics at registration time, failing fast during boot.
+	ConflictPanic
+
+	// ConflictIgnore records the conflict without logging it.
+	ConflictIgnore
+)
+
+// RouteConflict describes two routes that can never both be reached.
+type RouteConflict struct {
+	// Existing is the route that was registered first and wins the match.
+	Existing *Route
+
+	// Conflicting is the route that was registered later and is shadowed.
+	Conflicting *Route
+
+	// Reason explains why the routes conflict.
+	Reason string
+}
+
+// Error implements the error interface.
+func (c RouteConflict) Error() string {
+	return fmt.Sprintf("route conflict: %s %s (registered at %s) %s %s %s (registered at %s)",
+		c.Conflicting.method, c.Conflicting.path, c.Conflicting.source,
+		c.Reason,
+		c.Existing.method, c.Existing.path, c.Existing.source,
+	)
+}
+
+// routeRegistry is shared between a router and all of its groups.
+type routeRegistry struct {
+	routes       []*Route
+	conflicts    []RouteConflict
+	conflictMode ConflictMode
+	bindings     map[string]BindingResolver
+	preflights   map[string]bool
+	cache        *RouteCache
+	fallbacks    []*Route
+	notAllowed   *Route
+	middleware   *MiddlewareRegistry
+	codec        JSONCodec
+}
+
+// RouteTraceEntry records how a single route was evaluated against a path.
+type RouteTraceEntry struct {
+	Route   *Route
+	Matched bool
+	Reason  string
+}
+
+// RouteTrace explains which route matched a given method and path.
+type RouteTrace struct {
+	Method  string
+	Path    string
+	Matched *Route
+	Params  map[string]string
+	Entries []RouteTraceEntry
+}
+
+// String returns a human-readable explanation of the trace.
+func (t *RouteTrace) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", t.Method, t.Path)
+	for _, entry := range t.Entries {
+		mark := " "
+		if entry.Matched {
+			mark = "✓"
+		}
+		fmt.Fprintf(&b, "  %s %-7s %-30s %s (%s)\n", mark, entry.Route.method, entry.Route.path, entry.Reason, entry.Route.source)
+	}
+	if t.Matched == nil {
+		b.WriteString("  no route matched\n")
+	}
+	return b.String()
+}
+
+// SetConflictMode sets how conflicting route registrations are reported.
+func (r *Router) SetConflictMode(mode ConflictMode) *Router {
+	r.registry.conflictMode = mode
+	return r
+}
+
+// Conflicts returns all route conflicts detected so far, including those in groups.
+func (r *Router) Conflicts() []RouteConflict {
+	return r.registry.conflicts
+}
+
+// AllRoutes returns every route registered on this router and its groups,
+// in registration order.
+func (r *Router) AllRoutes() []*Route {
+	return r.registry.routes
+}
+
+// Debug explains which route matches the given method and path.
+// Routes are evaluated in registration order, mirroring Fiber's matching.
+func (r *Router) Debug(method, path string) *RouteTrace {
+	method = strings.ToUpper(method)
+	trace := &RouteTrace{
+		Method:  method,
+		Path:    path,
+		Entries: make([]RouteTraceEntry, 0),
+	}
+
+	for _, route := range r.registry.routes {
+		// Fiber serves HEAD requests with GET handlers.
+		if route.method != method && !(method == "HEAD" && route.method == "GET") {
+			continue
+		}
+
+		entry := RouteTraceEntry{Route: route}
+		if trace.Matched != nil {
+			entry.Reason = "skipped: an earlier route already matched"
+			trace.Entries = append(trace.Entries, entry)
+			continue
+		}
+
+		params, reason := matchRoutePath(route.path, path)
+		if param, failed := route.failedConstraint(params); params != nil && failed {
+			params = nil
+			reason = fmt.Sprintf("parameter %q does not match %s", param, route.constraints[param])
+		}
+		if params != nil {
+			entry.Matched = true
+			entry.Reason = "matched"
+			trace.Matched = route
+			trace.Params = params
+		} else {
+			entry.Reason = reason
+		}
+		trace.Entries = append(trace.Entries, entry)
+	}
+
+	return trace
+}
+
+// registerRoute records a route and checks it against earlier registrations.
+func (r *Router) registerRoute(route *Route) {
+	if r.registerCachedRoute(route) {
+		return
+	}
+	route.source = callerSource()
+
+	for _, existing := range r.registry.routes {
+		if existing.method != route.method {
+			continue
+		}
+		reason := ""
+		switch {
+		case normalizeRoutePath(existing.path) == normalizeRoutePath(route.path):
+			reason = "duplicates"
+		case routeCovers(existing.path, route.path):
+			reason = "is shadowed by"
+		default:
+			continue
+		}
+
+		conflict := RouteConflict{Existing: existing, Conflicting: route, Reason: reason}
+		r.registry.conflicts = append(r.registry.conflicts, conflict)
+		r.reportConflict(conflict)
+		break
+	}
+
+	r.registry.routes = append(r.registry.routes, route)
+}
+
+// reportConflict reports a conflict according to the configured mode.
+func (r *Router) reportConflict(conflict RouteConflict) {
+	switch r.registry.conflictMode {
+	case ConflictPanic:
+		panic(conflict.Error())
+	case ConflictIgnore:
+		return
+	}
+
+	if r.app == nil {
+		return
+	}
+	if logger := r.app.GetLogger(); logger != nil {
+		logger.Warn("Route conflict detected",
+			"method", conflict.Conflicting.method,
+			"path", conflict.Conflicting.path,
+			"source", conflict.Conflicting.source,
+			"existing_path", conflict.Existing.path,
+			"existing_source", conflict.Existing.source,
+			"reason", conflict.Reason,
+		)
+	}
+}
+
+// callerSource returns the file:line of the first caller outside the router internals.
+func callerSource() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isRouterInternal(frame.File) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// isRouterInternal reports whether a file belongs to the route registration internals.
+func isRouterInternal(file string) bool {
+	for _, suffix := range []string{"/http/router.go", "/http/kernel.go", "/http/route_trace.go", "/http/resource.go", "/http/websocket.go"} {
+		if strings.HasSuffix(file, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitRoutePath splits a path into segments, ignoring leading and trailing slashes.
+func splitRoutePath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return []string{}
+	}
+	return strings.Split(path, "/")
+}
+
+// normalizeRoutePath replaces parameter names so that equivalent patterns compare equal.
+func normalizeRoutePath(path string) string {
+	segments := splitRoutePath(path)
+	for i, seg := range segments {
+		if isParamSegment(seg) {
+			segments[i] = ":"
+		} else {
+			segments[i] = strings.ToLower(seg)
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+func isParamSegment(seg string) bool {
+	return strings.HasPrefix(seg, ":")
+}
+
+func isWildcardSegment(seg string) bool {
+	return seg == "*" || seg == "+"
+}
+
+// routeCovers reports whether every path matched by pattern b is also matched by pattern a.
+func routeCovers(a, b string) bool {
+	as, bs := splitRoutePath(a), splitRoutePath(b)
+	for i := 0; ; i++ {
+		if i < len(as) && as[i] == "*" {
+			return true
+		}
+		if i < len(as) && as[i] == "+" {
+			return i < len(bs)
+		}
+		if i >= len(as) || i >= len(bs) {
+			return len(as) == len(bs)
+		}
+		if isWildcardSegment(bs[i]) {
+			return false
+		}
+		if isParamSegment(as[i]) {
+			continue
+		}
+		if isParamSegment(bs[i]) || !strings.EqualFold(as[i], bs[i]) {
+			return false
+		}
+	}
+}
+
+// matchRoutePath matches a concrete path against a route pattern.
+// It returns the captured parameters, or nil and the reason for the mismatch.
+func matchRoutePath(pattern, path string) (map[string]string, string) {
+	ps, segs := splitRoutePath(pattern), splitRoutePath(path)
+	params := make(map[string]string)
+
+	for i, p := range ps {
+		if isWildcardSegment(p) {
+			rest := strings.Join(segs[min(i, len(segs)):], "/")
+			if p == "+" && rest == "" {
+				return nil, "wildcard '+' requires at least one segment"
+			}
+			params[p] = rest
+			return params, ""
+		}
+
+		if i >= len(segs) {
+			if strings.HasSuffix(p, "?") {
+				params[strings.TrimSuffix(p[1:], "?")] = ""
+				continue
+			}
+			return nil, fmt.Sprintf("path is too short: missing segment %q", p)
+		}
+
+		if isParamSegment(p) {
+			params[strings.TrimSuffix(p[1:], "?")] = segs[i]
+			continue
+		}
+
+		if !strings.EqualFold(p, segs[i]) {
+			return nil, fmt.Sprintf("segment %d: %q does not match %q", i+1, segs[i], p)
+		}
+	}
+
+	if len(segs) > len(ps) {
+		return nil, fmt.Sprintf("path is too long: unexpected segment %q", segs[len(ps)])
+	}
+
+	return params, ""
+}
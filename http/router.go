@@ -1,6 +1,8 @@
 package http
 
 import (
+	"regexp"
+
 	"github.com/genesysflow/go-genesys/contracts"
 	"github.com/gofiber/fiber/v2"
 )
@@ -21,38 +23,62 @@ type Router struct {
 	namedRoutes map[string]*Route
 	groups      []*Router
 	parent      *Router
+	registry    *routeRegistry
+	hub         *WebSocketHub
 }
 
 // NewRouter creates a new Router instance.
 func NewRouter(app contracts.Application, fiberApp *fiber.App) *Router {
-	return &Router{
+	router := &Router{
 		app:         app,
 		fiber:       fiberApp,
 		routes:      make([]*Route, 0),
 		namedRoutes: make(map[string]*Route),
 		groups:      make([]*Router, 0),
+		registry:    &routeRegistry{middleware: NewMiddlewareRegistry()},
+		hub:         NewWebSocketHub(),
 	}
+	fiberApp.Use(router.unmatched)
+	return router
 }
 
-// wrapHandler wraps a HandlerFunc to a Fiber handler.
-func (r *Router) wrapHandler(handler HandlerFunc, middleware ...MiddlewareFunc) fiber.Handler {
+// wrapHandler wraps a route to a Fiber handler. Requests whose parameters
+// fail the route's constraints fall through to the next matching route.
+// Bindings are resolved after middleware, just before the handler.
+func (r *Router) wrapHandler(route *Route) fiber.Handler {
+	handler := func(ctx *Context) error {
+		if err := route.resolveBindings(ctx); err != nil {
+			return err
+		}
+		return route.handler(ctx)
+	}
+
 	return func(c *fiber.Ctx) error {
+		if !route.matchesConstraints(c) {
+			return c.Next()
+		}
+		c.Locals(routerLocalsKey, r)
+		c.Locals(routeLocalsKey, route)
 		ctx := NewContext(c, r.app)
 
-		// Collect all middleware (group middleware + route middleware)
-		allMiddleware := make([]MiddlewareFunc, 0, len(r.middleware)+len(middleware))
-		allMiddleware = append(allMiddleware, r.middleware...)
-		allMiddleware = append(allMiddleware, middleware...)
+		// Execute middleware chain
+		return r.executeMiddleware(ctx, r.routeMiddleware(route), handler)
+	}
+}
 
-		// If we're in a group, add parent middleware
-		if r.parent != nil {
-			parentMiddleware := r.collectParentMiddleware()
-			allMiddleware = append(parentMiddleware, allMiddleware...)
-		}
+// routeMiddleware collects the middleware a route runs: parent group
+// middleware, then this router's, then the route's own.
+func (r *Router) routeMiddleware(route *Route) []MiddlewareFunc {
+	allMiddleware := make([]MiddlewareFunc, 0, len(r.middleware)+len(route.middleware))
+	allMiddleware = append(allMiddleware, r.middleware...)
+	allMiddleware = append(allMiddleware, route.middleware...)
 
-		// Execute middleware chain
-		return r.executeMiddleware(ctx, allMiddleware, handler)
+	// If we're in a group, add parent middleware
+	if r.parent != nil {
+		parentMiddleware := r.collectParentMiddleware()
+		allMiddleware = append(parentMiddleware, allMiddleware...)
 	}
+	return allMiddleware
 }
 
 // collectParentMiddleware collects middleware from all parent routers.
@@ -158,9 +184,10 @@ func (r *Router) addRoute(method, path string, handler HandlerFunc, middleware .
 		router:     r,
 	}
 	r.routes = append(r.routes, route)
+	r.registerRoute(route)
 
 	// Register with Fiber
-	wrappedHandler := r.wrapHandler(handler, middleware...)
+	wrappedHandler := r.wrapHandler(route)
 	switch method {
 	case "GET":
 		r.fiber.Get(fullPath, wrappedHandler)
@@ -192,6 +219,8 @@ func (r *Router) Group(prefix string, fn func(*Router), middleware ...Middleware
 		namedRoutes: r.namedRoutes, // Share named routes with parent
 		groups:      make([]*Router, 0),
 		parent:      r,
+		registry:    r.registry, // Share route registry with parent
+		hub:         r.hub,
 	}
 
 	r.groups = append(r.groups, group)
@@ -203,9 +232,11 @@ func (r *Router) Use(middleware ...MiddlewareFunc) {
 	r.middleware = append(r.middleware, middleware...)
 }
 
-// Static serves static files from a directory.
+// Static serves static files from a directory. Pre-compressed .br and .gz
+// siblings are served instead to clients that accept them.
 func (r *Router) Static(prefix, root string) {
 	fullPath := r.prefix + prefix
+	r.fiber.Use(fullPath, precompressedStatic(fullPath, root))
 	r.fiber.Static(fullPath, root)
 }
 
@@ -219,31 +250,6 @@ func (r *Router) NamedRoute(name string) *Route {
 	return r.namedRoutes[name]
 }
 
-// URL generates a URL for a named route.
-func (r *Router) URL(name string, params ...map[string]any) string {
-	route := r.namedRoutes[name]
-	if route == nil {
-		return ""
-	}
-
-	path := route.path
-	if len(params) > 0 {
-		for key, value := range params[0] {
-			// Replace route parameters like :id with actual values
-			// This is a simple implementation
-			path = replaceParam(path, key, value)
-		}
-	}
-	return path
-}
-
-// replaceParam replaces a route parameter with a value.
-func replaceParam(path, key string, value any) string {
-	// Simple string replacement
-	// A full implementation would use regex
-	return path
-}
-
 // Route represents a single route.
 type Route struct {
 	name       string
@@ -252,6 +258,10 @@ type Route struct {
 	handler    HandlerFunc
 	middleware []MiddlewareFunc
 	router     *Router
+	source     string
+
+	constraints map[string]*regexp.Regexp
+	bindings    map[string]BindingResolver
 }
 
 // Name sets the route name.
@@ -289,44 +299,7 @@ func (r *Route) GetHandler() HandlerFunc {
 	return r.handler
 }
 
-// Resource creates RESTful routes for a resource.
-func (r *Router) Resource(name string, controller ResourceController) {
-	r.GET("/"+name, controller.Index).Name(name + ".index")
-	r.GET("/"+name+"/create", controller.Create).Name(name + ".create")
-	r.POST("/"+name, controller.Store).Name(name + ".store")
-	r.GET("/"+name+"/:id", controller.Show).Name(name + ".show")
-	r.GET("/"+name+"/:id/edit", controller.Edit).Name(name + ".edit")
-	r.PUT("/"+name+"/:id", controller.Update).Name(name + ".update")
-	r.PATCH("/"+name+"/:id", controller.Update).Name(name + ".update.patch")
-	r.DELETE("/"+name+"/:id", controller.Destroy).Name(name + ".destroy")
-}
-
-// APIResource creates API RESTful routes (without create/edit).
-func (r *Router) APIResource(name string, controller APIResourceController) {
-	r.GET("/"+name, controller.Index).Name(name + ".index")
-	r.POST("/"+name, controller.Store).Name(name + ".store")
-	r.GET("/"+name+"/:id", controller.Show).Name(name + ".show")
-	r.PUT("/"+name+"/:id", controller.Update).Name(name + ".update")
-	r.PATCH("/"+name+"/:id", controller.Update).Name(name + ".update.patch")
-	r.DELETE("/"+name+"/:id", controller.Destroy).Name(name + ".destroy")
-}
-
-// ResourceController defines the interface for resourceful controllers.
-type ResourceController interface {
-	Index(ctx *Context) error
-	Create(ctx *Context) error
-	Store(ctx *Context) error
-	Show(ctx *Context) error
-	Edit(ctx *Context) error
-	Update(ctx *Context) error
-	Destroy(ctx *Context) error
-}
-
-// APIResourceController defines the interface for API resourceful controllers.
-type APIResourceController interface {
-	Index(ctx *Context) error
-	Store(ctx *Context) error
-	Show(ctx *Context) error
-	Update(ctx *Context) error
-	Destroy(ctx *Context) error
+// GetSource returns the file and line where the route was registered.
+func (r *Route) GetSource() string {
+	return r.source
 }
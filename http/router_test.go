@@ -49,6 +49,12 @@ func (m *mockApplication) TerminateWithContext(ctx context.Context) error    { r
 func (m *mockApplication) GetConfig() contracts.Config                       { return nil }
 func (m *mockApplication) GetLogger() contracts.Logger                       { return nil }
 
+func (m *mockApplication) Extend(name string, decorator func(any) any) error    { return nil }
+func (m *mockApplication) Tag(tag string, services ...string)                   {}
+func (m *mockApplication) Tagged(tag string) ([]any, error)                     { return nil, nil }
+func (m *mockApplication) Fill(target any) error                                { return nil }
+func (m *mockApplication) When(consumer any) contracts.ContextualBindingBuilder { return nil }
+
 func newTestApp() *fiber.App {
 	return fiber.New(fiber.Config{
 		DisableStartupMessage: true,
@@ -433,3 +439,72 @@ func TestStatic(t *testing.T) {
 	// Will be 404 since testdata doesn't exist, but that's expected
 	assert.NotNil(t, resp)
 }
+
+func TestRouteConflictDuplicate(t *testing.T) {
+	router := NewRouter(&mockApplication{}, newTestApp())
+	handler := func(ctx *Context) error { return nil }
+
+	router.GET("/users/:id", handler)
+	router.GET("/users/:user", handler)
+	router.POST("/users/:id", handler)
+
+	conflicts := router.Conflicts()
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, "duplicates", conflicts[0].Reason)
+	assert.Equal(t, "/users/:id", conflicts[0].Existing.GetPath())
+	assert.Contains(t, conflicts[0].Conflicting.GetSource(), "router_test.go")
+}
+
+func TestRouteConflictShadowed(t *testing.T) {
+	router := NewRouter(&mockApplication{}, newTestApp())
+	handler := func(ctx *Context) error { return nil }
+
+	router.GET("/files/*", handler)
+	router.Group("/files", func(r *Router) {
+		r.GET("/:name", handler)
+	})
+	router.GET("/users/me", handler)
+	router.GET("/users/:id", handler)
+
+	conflicts := router.Conflicts()
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, "is shadowed by", conflicts[0].Reason)
+	assert.Equal(t, "/files/:name", conflicts[0].Conflicting.GetPath())
+}
+
+func TestRouteConflictPanic(t *testing.T) {
+	router := NewRouter(&mockApplication{}, newTestApp())
+	router.SetConflictMode(ConflictPanic)
+	handler := func(ctx *Context) error { return nil }
+
+	router.GET("/dup", handler)
+	assert.Panics(t, func() {
+		router.GET("/dup", handler)
+	})
+}
+
+func TestRouterDebug(t *testing.T) {
+	router := NewRouter(&mockApplication{}, newTestApp())
+	handler := func(ctx *Context) error { return nil }
+
+	router.GET("/users/me", handler)
+	router.Group("/api", func(r *Router) {
+		r.GET("/users/:id", handler)
+	})
+
+	trace := router.Debug("GET", "/api/users/42")
+	require.NotNil(t, trace.Matched)
+	assert.Equal(t, "/api/users/:id", trace.Matched.GetPath())
+	assert.Equal(t, "42", trace.Params["id"])
+	require.Len(t, trace.Entries, 2)
+	assert.False(t, trace.Entries[0].Matched)
+	assert.Contains(t, trace.String(), "matched")
+
+	trace = router.Debug("HEAD", "/users/me")
+	require.NotNil(t, trace.Matched)
+	assert.Equal(t, "/users/me", trace.Matched.GetPath())
+
+	trace = router.Debug("GET", "/missing")
+	assert.Nil(t, trace.Matched)
+	assert.Contains(t, trace.String(), "no route matched")
+}
@@ -0,0 +1,284 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// TLSConfig configures HTTPS for RunTLSUntil and RunTLSWithGracefulShutdown.
+type TLSConfig struct {
+	// CertFile and KeyFile are PEM files with the certificate chain and
+	// its private key. They are ignored when AutoCert is enabled.
+	CertFile string
+	KeyFile  string
+
+	// HTTP2 serves through net/http so clients can negotiate HTTP/2. As
+	// with Handler, request bodies are read fully before handlers run.
+	HTTP2 bool
+
+	// AutoCert obtains and renews certificates from an ACME CA such as
+	// Let's Encrypt.
+	AutoCert AutoCertConfig
+
+	// RedirectAddr, such as ":80", serves a plain HTTP listener that
+	// redirects to HTTPS and answers ACME HTTP challenges.
+	RedirectAddr string
+}
+
+// AutoCertConfig configures automatic certificates.
+type AutoCertConfig struct {
+	Enabled bool
+
+	// Domains lists the host names certificates are issued for. Requests
+	// for other hosts are refused during the TLS handshake.
+	Domains []string
+
+	// Email is the contact address of the ACME account.
+	Email string
+
+	// CacheDisk names the filesystem disk certificates and the account key
+	// are stored on, so they survive restarts and are shared by instances.
+	// Without one, they are kept in storage/framework/certs.
+	CacheDisk string
+
+	// CachePath is the directory on CacheDisk. Defaults to "certs".
+	CachePath string
+
+	// DirectoryURL is the ACME directory. Defaults to Let's Encrypt's
+	// production directory; use its staging directory while testing.
+	DirectoryURL string
+}
+
+// Enabled reports whether the config serves HTTPS.
+func (c TLSConfig) Enabled() bool {
+	return c.AutoCert.Enabled || (c.CertFile != "" && c.KeyFile != "")
+}
+
+// LoadTLSConfig reads a TLS config from the http.tls config values:
+//
+//	http:
+//	  tls:
+//	    cert_file: /etc/ssl/app.pem
+//	    key_file: /etc/ssl/app.key
+//	    http2: true
+//	    redirect_addr: ":80"
+//	    autocert:
+//	      enabled: true
+//	      domains: [example.com, www.example.com]
+//	      email: ops@example.com
+//	      cache_disk: s3
+func LoadTLSConfig(cfg contracts.Config) TLSConfig {
+	if cfg == nil {
+		return TLSConfig{}
+	}
+	return TLSConfig{
+		CertFile:     cfg.GetString("http.tls.cert_file"),
+		KeyFile:      cfg.GetString("http.tls.key_file"),
+		HTTP2:        cfg.GetBool("http.tls.http2"),
+		RedirectAddr: cfg.GetString("http.tls.redirect_addr"),
+		AutoCert: AutoCertConfig{
+			Enabled:      cfg.GetBool("http.tls.autocert.enabled"),
+			Domains:      cfg.GetStringSlice("http.tls.autocert.domains"),
+			Email:        cfg.GetString("http.tls.autocert.email"),
+			CacheDisk:    cfg.GetString("http.tls.autocert.cache_disk"),
+			CachePath:    cfg.GetString("http.tls.autocert.cache_path"),
+			DirectoryURL: cfg.GetString("http.tls.autocert.directory_url"),
+		},
+	}
+}
+
+// RunTLSWithGracefulShutdown serves HTTPS as configured and shuts down
+// gracefully on SIGINT or SIGTERM, as RunWithGracefulShutdown does.
+func (k *Kernel) RunTLSWithGracefulShutdown(addr string, cfg TLSConfig, timeout time.Duration) error {
+	ctx, stop := shutdownSignal()
+	defer stop()
+
+	return k.RunTLSUntil(ctx, addr, cfg, timeout)
+}
+
+// RunTLSUntil serves HTTPS as configured and shuts down gracefully once
+// ctx is done, along with the redirect listener.
+func (k *Kernel) RunTLSUntil(ctx context.Context, addr string, cfg TLSConfig, timeout time.Duration) error {
+	tlsConfig, manager, err := k.newTLSConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	if cfg.RedirectAddr != "" {
+		if err := k.serveRedirect(cfg.RedirectAddr, addr, manager); err != nil {
+			ln.Close()
+			return err
+		}
+	}
+
+	if k.logger != nil {
+		k.logger.Info("Starting HTTPS server with graceful shutdown", "address", addr, "http2", cfg.HTTP2, "autocert", cfg.AutoCert.Enabled)
+	}
+	return k.serveUntil(ctx, k.tlsServer(ln, tlsConfig, cfg.HTTP2), timeout)
+}
+
+// tlsServer returns a function serving on ln with Fiber, or with net/http
+// for HTTP/2, which Fiber doesn't speak.
+func (k *Kernel) tlsServer(ln net.Listener, tlsConfig *tls.Config, http2 bool) func() error {
+	if !http2 {
+		return func() error { return k.fiber.Listener(tls.NewListener(ln, tlsConfig)) }
+	}
+
+	server := &nethttp.Server{Handler: k.Handler(), TLSConfig: tlsConfig}
+	k.trackServer(server)
+	return func() error {
+		if err := server.ServeTLS(ln, "", ""); !errors.Is(err, nethttp.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
+
+// serveRedirect serves redirects to the HTTPS address on addr in the
+// background. With autocert, it also answers ACME HTTP challenges.
+func (k *Kernel) serveRedirect(addr, httpsAddr string, manager *autocert.Manager) error {
+	_, port, _ := net.SplitHostPort(httpsAddr)
+	var handler nethttp.Handler = nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		status := nethttp.StatusMovedPermanently
+		if r.Method != nethttp.MethodGet && r.Method != nethttp.MethodHead {
+			status = nethttp.StatusPermanentRedirect
+		}
+		nethttp.Redirect(w, r, "https://"+host+r.URL.RequestURI(), status)
+	})
+	if manager != nil {
+		handler = manager.HTTPHandler(handler)
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	server := &nethttp.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	k.trackServer(server)
+	go func() {
+		if err := server.Serve(ln); !errors.Is(err, nethttp.ErrServerClosed) && k.logger != nil {
+			k.logger.Error("HTTP redirect server failed", "address", addr, "error", err.Error())
+		}
+	}()
+	return nil
+}
+
+// newTLSConfig builds the TLS config for cfg, and the autocert manager
+// when certificates are automatic.
+func (k *Kernel) newTLSConfig(cfg TLSConfig) (*tls.Config, *autocert.Manager, error) {
+	nextProtos := []string{"http/1.1"}
+	if cfg.HTTP2 {
+		nextProtos = []string{"h2", "http/1.1"}
+	}
+
+	if !cfg.AutoCert.Enabled {
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return nil, nil, fmt.Errorf("http: TLS needs a certificate and key file, or autocert")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("http: failed to load TLS certificate: %w", err)
+		}
+		return &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			NextProtos:   nextProtos,
+			MinVersion:   tls.VersionTLS12,
+		}, nil, nil
+	}
+
+	if len(cfg.AutoCert.Domains) == 0 {
+		return nil, nil, fmt.Errorf("http: autocert needs at least one domain")
+	}
+	cache, err := k.autoCertCache(cfg.AutoCert)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      cache,
+		HostPolicy: autocert.HostWhitelist(cfg.AutoCert.Domains...),
+		Email:      cfg.AutoCert.Email,
+	}
+	if cfg.AutoCert.DirectoryURL != "" {
+		manager.Client = &acme.Client{DirectoryURL: cfg.AutoCert.DirectoryURL}
+	}
+
+	return &tls.Config{
+		GetCertificate: manager.GetCertificate,
+		NextProtos:     append(nextProtos, acme.ALPNProto),
+		MinVersion:     tls.VersionTLS12,
+	}, manager, nil
+}
+
+// autoCertCache stores certificates on the configured disk, or in
+// storage/framework/certs without one.
+func (k *Kernel) autoCertCache(cfg AutoCertConfig) (autocert.Cache, error) {
+	dir := cfg.CachePath
+	if dir == "" {
+		dir = "certs"
+	}
+
+	disk, err := resolveDisk(k.app, cfg.CacheDisk)
+	if err != nil {
+		if cfg.CacheDisk != "" {
+			return nil, err
+		}
+		return autocert.DirCache(filepath.Join(k.app.StoragePath(), "framework", dir)), nil
+	}
+	return &diskCertCache{disk: disk, dir: dir}, nil
+}
+
+// diskCertCache is an autocert.Cache on a filesystem disk.
+type diskCertCache struct {
+	disk contracts.Filesystem
+	dir  string
+}
+
+func (c *diskCertCache) Get(ctx context.Context, name string) ([]byte, error) {
+	file := path.Join(c.dir, name)
+	if !c.disk.Exists(ctx, file) {
+		return nil, autocert.ErrCacheMiss
+	}
+	data, err := c.disk.GetBytes(ctx, file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, autocert.ErrCacheMiss
+	}
+	return data, err
+}
+
+func (c *diskCertCache) Put(ctx context.Context, name string, data []byte) error {
+	return c.disk.PutBytes(ctx, path.Join(c.dir, name), data)
+}
+
+func (c *diskCertCache) Delete(ctx context.Context, name string) error {
+	file := path.Join(c.dir, name)
+	if !c.disk.Exists(ctx, file) {
+		return nil
+	}
+	return c.disk.Delete(ctx, file)
+}
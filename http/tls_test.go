@@ -0,0 +1,167 @@
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	nethttp "net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/filesystem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// writeTestCert writes a self-signed certificate for 127.0.0.1 and returns
+// the certificate and key file paths.
+func writeTestCert(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	return ln.Addr().String()
+}
+
+// startTLSKernel serves a kernel over TLS until the test ends.
+func startTLSKernel(t *testing.T, cfg TLSConfig) string {
+	t.Helper()
+
+	app := &mockApplication{}
+	fiberApp := newTestApp()
+	kernel := &Kernel{app: app, fiber: fiberApp, router: NewRouter(app, fiberApp)}
+	kernel.GET("/hello", func(ctx *Context) error { return ctx.String("hello") })
+
+	addr := freeAddr(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- kernel.RunTLSUntil(ctx, addr, cfg, time.Second) }()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	return addr
+}
+
+func tlsClient() *nethttp.Client {
+	return &nethttp.Client{
+		Transport: &nethttp.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			ForceAttemptHTTP2: true,
+		},
+		CheckRedirect: func(req *nethttp.Request, via []*nethttp.Request) error {
+			return nethttp.ErrUseLastResponse
+		},
+	}
+}
+
+func TestKernelTLS(t *testing.T) {
+	certFile, keyFile := writeTestCert(t)
+
+	for _, http2 := range []bool{false, true} {
+		addr := startTLSKernel(t, TLSConfig{CertFile: certFile, KeyFile: keyFile, HTTP2: http2})
+
+		resp, err := tlsClient().Get("https://" + addr + "/hello")
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		assert.Equal(t, "hello", string(body))
+		if http2 {
+			assert.Equal(t, 2, resp.ProtoMajor)
+		} else {
+			assert.Equal(t, 1, resp.ProtoMajor)
+		}
+	}
+}
+
+func TestKernelTLSRedirect(t *testing.T) {
+	certFile, keyFile := writeTestCert(t)
+	redirectAddr := freeAddr(t)
+	addr := startTLSKernel(t, TLSConfig{CertFile: certFile, KeyFile: keyFile, RedirectAddr: redirectAddr})
+
+	resp, err := tlsClient().Get("http://" + redirectAddr + "/hello?x=1")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	_, port, _ := net.SplitHostPort(addr)
+	assert.Equal(t, nethttp.StatusMovedPermanently, resp.StatusCode)
+	assert.Equal(t, "https://127.0.0.1:"+port+"/hello?x=1", resp.Header.Get("Location"))
+}
+
+func TestKernelTLSConfigErrors(t *testing.T) {
+	kernel := &Kernel{app: &mockApplication{}}
+
+	_, _, err := kernel.newTLSConfig(TLSConfig{})
+	assert.Error(t, err)
+
+	_, _, err = kernel.newTLSConfig(TLSConfig{AutoCert: AutoCertConfig{Enabled: true}})
+	assert.Error(t, err)
+
+	_, _, err = kernel.newTLSConfig(TLSConfig{CertFile: "missing.pem", KeyFile: "missing.key"})
+	assert.Error(t, err)
+}
+
+func TestDiskCertCache(t *testing.T) {
+	disk, err := filesystem.NewMemory(map[string]any{})
+	require.NoError(t, err)
+	cache := &diskCertCache{disk: disk, dir: "certs"}
+	ctx := context.Background()
+
+	_, err = cache.Get(ctx, "example.com")
+	assert.ErrorIs(t, err, autocert.ErrCacheMiss)
+
+	require.NoError(t, cache.Put(ctx, "example.com", []byte("cert")))
+	data, err := cache.Get(ctx, "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "cert", string(data))
+	assert.True(t, disk.Exists(ctx, "certs/example.com"))
+
+	require.NoError(t, cache.Delete(ctx, "example.com"))
+	require.NoError(t, cache.Delete(ctx, "example.com"))
+	_, err = cache.Get(ctx, "example.com")
+	assert.ErrorIs(t, err, autocert.ErrCacheMiss)
+}
@@ -0,0 +1,209 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	nethttp "net/http"
+	pathpkg "path"
+	"path/filepath"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+var (
+	// ErrFileTooLarge is returned by ValidateSize for files over the limit.
+	ErrFileTooLarge = errors.New("http: uploaded file is too large")
+
+	// ErrFileType is returned by ValidateMimeType for files of a disallowed type.
+	ErrFileType = errors.New("http: uploaded file type is not allowed")
+)
+
+// UploadedFile is a file uploaded with a multipart request.
+type UploadedFile struct {
+	header *multipart.FileHeader
+	ctx    context.Context
+	app    contracts.Application
+}
+
+// NewUploadedFile wraps a multipart file header. Files are stored on disks
+// from the storage facade, or from the application's "filesystem" service.
+func NewUploadedFile(ctx context.Context, header *multipart.FileHeader, app contracts.Application) *UploadedFile {
+	return &UploadedFile{
+		header: header,
+		ctx:    ctx,
+		app:    app,
+	}
+}
+
+// Header returns the underlying multipart file header.
+func (f *UploadedFile) Header() *multipart.FileHeader {
+	return f.header
+}
+
+// ClientName returns the base name of the file name sent by the client.
+func (f *UploadedFile) ClientName() string {
+	return filepath.Base(strings.ReplaceAll(f.header.Filename, "\\", "/"))
+}
+
+// Extension returns the lowercase extension of the client file name, without the dot.
+func (f *UploadedFile) Extension() string {
+	return strings.ToLower(strings.TrimPrefix(filepath.Ext(f.ClientName()), "."))
+}
+
+// Size returns the file size in bytes.
+func (f *UploadedFile) Size() int64 {
+	return f.header.Size
+}
+
+// ClientMimeType returns the content type sent by the client. It is not
+// verified; use MimeType for validation.
+func (f *UploadedFile) ClientMimeType() string {
+	return f.header.Header.Get("Content-Type")
+}
+
+// Open opens the uploaded file for reading.
+func (f *UploadedFile) Open() (multipart.File, error) {
+	return f.header.Open()
+}
+
+// MimeType detects the content type from the file's leading bytes, without
+// parameters. The client's extension is never consulted, so unrecognized
+// content stays application/octet-stream and text formats such as SVG are
+// reported as text/plain or text/xml.
+func (f *UploadedFile) MimeType() (string, error) {
+	file, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+
+	detected, _, _ := mime.ParseMediaType(nethttp.DetectContentType(buf[:n]))
+	return detected, nil
+}
+
+// preferredExtensions picks the usual extension for types the system MIME
+// tables list several for.
+var preferredExtensions = map[string]string{
+	"text/plain": "txt",
+	"text/html":  "html",
+	"text/xml":   "xml",
+	"image/jpeg": "jpg",
+	"video/mpeg": "mpeg",
+	"audio/mpeg": "mp3",
+}
+
+// GuessExtension returns the extension for the detected content type,
+// without the dot: the client's extension if it names that type, otherwise
+// the usual one for it. It is empty for unrecognized content.
+func (f *UploadedFile) GuessExtension() (string, error) {
+	detected, err := f.MimeType()
+	if err != nil || detected == "application/octet-stream" {
+		return "", err
+	}
+
+	if ext := f.Extension(); ext != "" {
+		if byExtension, _, err := mime.ParseMediaType(mime.TypeByExtension("." + ext)); err == nil && byExtension == detected {
+			return ext, nil
+		}
+	}
+	if ext, ok := preferredExtensions[detected]; ok {
+		return ext, nil
+	}
+	extensions, err := mime.ExtensionsByType(detected)
+	if err != nil || len(extensions) == 0 {
+		return "", nil
+	}
+	return strings.TrimPrefix(extensions[0], "."), nil
+}
+
+// HashName returns a random file name with the extension guessed from the
+// file's contents.
+func (f *UploadedFile) HashName() (string, error) {
+	b := make([]byte, 20)
+	rand.Read(b)
+
+	ext, err := f.GuessExtension()
+	if err != nil {
+		return "", err
+	}
+	name := hex.EncodeToString(b)
+	if ext != "" {
+		name += "." + ext
+	}
+	return name, nil
+}
+
+// Store stores the file in dir on a disk under a random name and returns its path.
+// The name's extension comes from the file's contents, not the client's file
+// name. An empty disk name uses the default disk.
+func (f *UploadedFile) Store(disk, dir string) (string, error) {
+	name, err := f.HashName()
+	if err != nil {
+		return "", err
+	}
+	return f.StoreAs(disk, dir, name)
+}
+
+// StoreAs stores the file in dir on a disk under the given name and returns its path.
+func (f *UploadedFile) StoreAs(disk, dir, name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, "/\\") || name == "." || name == ".." {
+		return "", fmt.Errorf("http: invalid file name %q", name)
+	}
+
+	fs, err := resolveDisk(f.app, disk)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	path := strings.TrimPrefix(pathpkg.Join(dir, name), "/")
+	if err := fs.PutStream(f.ctx, path, file); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ValidateSize returns ErrFileTooLarge if the file is larger than max bytes.
+func (f *UploadedFile) ValidateSize(max int64) error {
+	if f.Size() > max {
+		return fmt.Errorf("%w: %s is %d bytes, the limit is %d", ErrFileTooLarge, f.ClientName(), f.Size(), max)
+	}
+	return nil
+}
+
+// ValidateMimeType returns ErrFileType if the detected content type is not
+// one of allowed. Entries like "image/*" match any subtype.
+func (f *UploadedFile) ValidateMimeType(allowed ...string) error {
+	detected, err := f.MimeType()
+	if err != nil {
+		return err
+	}
+
+	for _, pattern := range allowed {
+		if pattern == detected {
+			return nil
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok && strings.HasPrefix(detected, prefix+"/") {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is %s", ErrFileType, f.ClientName(), detected)
+}
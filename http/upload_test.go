@@ -0,0 +1,205 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/facades/storage"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pngHeader is enough of a PNG for content sniffing.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+type uploadPart struct {
+	field, filename, contentType string
+	content                      []byte
+}
+
+// postUpload sends a multipart request to a handler receiving the Context.
+func postUpload(t *testing.T, handler func(ctx *Context) error, parts ...uploadPart) int {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, part := range parts {
+		header := make(map[string][]string)
+		header["Content-Disposition"] = []string{`form-data; name="` + part.field + `"; filename="` + part.filename + `"`}
+		header["Content-Type"] = []string{part.contentType}
+		w, err := writer.CreatePart(header)
+		require.NoError(t, err)
+		w.Write(part.content)
+	}
+	require.NoError(t, writer.Close())
+
+	app := fiber.New()
+	app.Post("/upload", func(c *fiber.Ctx) error {
+		return handler(NewContext(c, &mockApplication{}))
+	})
+
+	req := httptest.NewRequest("POST", "/upload", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp.StatusCode
+}
+
+func TestContextFileStore(t *testing.T) {
+	disk := storage.Fake("s3")
+	defer storage.Restore()
+	ctx := context.Background()
+
+	var stored, named string
+	status := postUpload(t, func(c *Context) error {
+		require.True(t, c.HasFile("avatar"))
+		assert.False(t, c.HasFile("missing"))
+
+		file, err := c.File("avatar")
+		require.NoError(t, err)
+		assert.Equal(t, "me.PNG", file.ClientName())
+		assert.Equal(t, "png", file.Extension())
+		assert.Equal(t, int64(len(pngHeader)), file.Size())
+		assert.Equal(t, "image/png", file.ClientMimeType())
+
+		stored, err = file.Store("s3", "avatars")
+		require.NoError(t, err)
+		named, err = file.StoreAs("s3", "/avatars/", "1.png")
+		require.NoError(t, err)
+
+		_, err = file.StoreAs("s3", "avatars", "../escape.png")
+		assert.Error(t, err)
+		return c.NoContent()
+	}, uploadPart{"avatar", "me.PNG", "image/png", pngHeader})
+
+	assert.Equal(t, 204, status)
+	assert.Regexp(t, `^avatars/[0-9a-f]{40}\.png$`, stored)
+	assert.Equal(t, "avatars/1.png", named)
+
+	content, err := disk.GetBytes(ctx, stored)
+	require.NoError(t, err)
+	assert.Equal(t, pngHeader, content)
+	assert.True(t, disk.Exists(ctx, "avatars/1.png"))
+}
+
+func TestContextFiles(t *testing.T) {
+	disk := storage.Fake()
+	defer storage.Restore()
+
+	status := postUpload(t, func(c *Context) error {
+		files, err := c.Files("photos")
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+
+		for _, file := range files {
+			_, err := file.StoreAs("", "photos", file.ClientName())
+			require.NoError(t, err)
+		}
+		return c.NoContent()
+	},
+		uploadPart{"photos", "a.png", "image/png", pngHeader},
+		uploadPart{"photos", `C:\Users\me\b.png`, "image/png", pngHeader},
+	)
+
+	assert.Equal(t, 204, status)
+	assert.Equal(t, []string{"photos/a.png", "photos/b.png"}, disk.Files())
+}
+
+func TestUploadedFileValidation(t *testing.T) {
+	var files []contracts.UploadedFile
+	postUpload(t, func(c *Context) error {
+		var err error
+		files, err = c.Files("file")
+		require.NoError(t, err)
+
+		png, svg, disguised := files[0], files[1], files[2]
+
+		assert.NoError(t, png.ValidateSize(1024))
+		assert.True(t, errors.Is(png.ValidateSize(4), ErrFileTooLarge))
+
+		assert.NoError(t, png.ValidateMimeType("image/*"))
+		assert.NoError(t, png.ValidateMimeType("image/jpeg", "image/png"))
+		assert.ErrorIs(t, png.ValidateMimeType("application/pdf"), ErrFileType)
+
+		// Text is not upgraded to the type of the client's extension.
+		mimeType, err := svg.MimeType()
+		require.NoError(t, err)
+		assert.Equal(t, "text/plain", mimeType)
+		assert.ErrorIs(t, svg.ValidateMimeType("image/*"), ErrFileType)
+
+		// The client's content type and extension do not hide HTML content.
+		mimeType, err = disguised.MimeType()
+		require.NoError(t, err)
+		assert.Equal(t, "text/html", mimeType)
+		assert.ErrorIs(t, disguised.ValidateMimeType("image/*"), ErrFileType)
+		return nil
+	},
+		uploadPart{"file", "a.png", "image/png", pngHeader},
+		uploadPart{"file", "logo.svg", "image/svg+xml", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)},
+		uploadPart{"file", "cat.jpg", "image/jpeg", []byte("<html><script>alert(1)</script></html>")},
+	)
+	require.Len(t, files, 3)
+}
+
+func TestUploadedFileIgnoresMismatchedExtension(t *testing.T) {
+	disk := storage.Fake()
+	defer storage.Restore()
+
+	var stored []string
+	postUpload(t, func(c *Context) error {
+		files, err := c.Files("file")
+		require.NoError(t, err)
+		require.Len(t, files, 4)
+
+		for _, file := range files {
+			path, err := file.Store("", "uploads")
+			require.NoError(t, err)
+			stored = append(stored, path)
+		}
+
+		// Unrecognized content stays octet-stream and gets no extension.
+		mimeType, err := files[0].MimeType()
+		require.NoError(t, err)
+		assert.Equal(t, "application/octet-stream", mimeType)
+		ext, err := files[0].GuessExtension()
+		require.NoError(t, err)
+		assert.Empty(t, ext)
+
+		ext, err = files[1].GuessExtension()
+		require.NoError(t, err)
+		assert.Equal(t, "png", ext)
+		return nil
+	},
+		uploadPart{"file", "page.html", "text/html", []byte{0x00, 0x01, 0x02, 0x03}},
+		uploadPart{"file", "photo.jpg", "image/jpeg", pngHeader},
+		uploadPart{"file", "cat.png", "image/png", []byte("<html><script>alert(1)</script></html>")},
+		uploadPart{"file", "logo.svg", "image/svg+xml", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)},
+	)
+
+	require.Len(t, stored, 4)
+	assert.Regexp(t, `^uploads/[0-9a-f]{40}$`, stored[0])
+	assert.Regexp(t, `^uploads/[0-9a-f]{40}\.png$`, stored[1])
+	assert.Regexp(t, `^uploads/[0-9a-f]{40}\.html$`, stored[2])
+	assert.NotRegexp(t, `\.svg$`, stored[3])
+	assert.Len(t, disk.Files(), 4)
+}
+
+func TestStoreWithoutFilesystem(t *testing.T) {
+	storage.Restore()
+
+	postUpload(t, func(c *Context) error {
+		file, err := c.File("doc")
+		require.NoError(t, err)
+
+		_, err = file.Store("", "docs")
+		assert.ErrorContains(t, err, "no filesystem")
+		return nil
+	}, uploadPart{"doc", "a.txt", "text/plain", []byte(strings.Repeat("a", 10))})
+}
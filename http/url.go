@@ -0,0 +1,149 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// routerLocalsKey stores the router handling a request in Fiber's locals,
+// so named routes can be resolved from handlers.
+const routerLocalsKey = "genesys.router"
+
+// routeLocalsKey stores the route handling a request in Fiber's locals.
+const routeLocalsKey = "genesys.route"
+
+// URL generates the path for a named route. Parameters fill route segments
+// such as ":id" (and "*" for wildcards); the rest are appended as a query
+// string. It returns "" if the route does not exist or a required
+// parameter is missing.
+func (r *Router) URL(name string, params ...map[string]any) string {
+	path, err := r.buildURL(name, params...)
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// AbsoluteURL generates a URL for a named route prefixed with the "app.url"
+// configuration value. Without it, the path is returned.
+func (r *Router) AbsoluteURL(name string, params ...map[string]any) string {
+	path := r.URL(name, params...)
+	if path == "" {
+		return ""
+	}
+	return r.baseURL() + path
+}
+
+// baseURL returns the configured app URL without a trailing slash.
+func (r *Router) baseURL() string {
+	if r.app == nil || r.app.GetConfig() == nil {
+		return ""
+	}
+	return strings.TrimRight(r.app.GetConfig().GetString("app.url"), "/")
+}
+
+// buildURL substitutes parameters into a named route's path.
+func (r *Router) buildURL(name string, params ...map[string]any) (string, error) {
+	route := r.namedRoutes[name]
+	if route == nil {
+		return "", fmt.Errorf("http: route [%s] is not defined", name)
+	}
+
+	values := make(map[string]string)
+	if len(params) > 0 {
+		for key, value := range params[0] {
+			values[key] = fmt.Sprint(value)
+		}
+	}
+
+	segments := splitRoutePath(route.path)
+	built := make([]string, 0, len(segments))
+	for _, seg := range segments {
+		switch {
+		case isWildcardSegment(seg):
+			value, ok := values[seg]
+			delete(values, seg)
+			if !ok && seg == "+" {
+				return "", fmt.Errorf("http: missing wildcard parameter for route [%s]", name)
+			}
+			if value != "" {
+				built = append(built, escapeWildcard(value))
+			}
+
+		case isParamSegment(seg):
+			key := strings.TrimSuffix(seg[1:], "?")
+			value, ok := values[key]
+			delete(values, key)
+			if !ok || value == "" {
+				if strings.HasSuffix(seg, "?") {
+					continue
+				}
+				return "", fmt.Errorf("http: missing parameter [%s] for route [%s]", key, name)
+			}
+			built = append(built, url.PathEscape(value))
+
+		default:
+			built = append(built, seg)
+		}
+	}
+
+	path := "/" + strings.Join(built, "/")
+	if query := encodeQuery(values); query != "" {
+		path += "?" + query
+	}
+	return path, nil
+}
+
+// escapeWildcard escapes each segment of a wildcard value, keeping slashes.
+func escapeWildcard(value string) string {
+	parts := strings.Split(strings.Trim(value, "/"), "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// encodeQuery encodes leftover parameters with sorted keys.
+func encodeQuery(values map[string]string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	query := url.Values{}
+	for _, key := range keys {
+		query.Set(key, values[key])
+	}
+	return query.Encode()
+}
+
+// routerFor returns the router handling a request, if any.
+func routerFor(c *fiber.Ctx) *Router {
+	router, _ := c.Locals(routerLocalsKey).(*Router)
+	return router
+}
+
+// CurrentRoute returns the route handling the request, or nil outside
+// the router.
+func (c *Context) CurrentRoute() *Route {
+	route, _ := c.fiberCtx.Locals(routeLocalsKey).(*Route)
+	return route
+}
+
+// Route returns the URL for a named route, absolute when "app.url" is
+// configured. It returns "" if the route does not exist.
+func (c *Context) Route(name string, params ...map[string]any) string {
+	router := routerFor(c.fiberCtx)
+	if router == nil {
+		return ""
+	}
+	return router.AbsoluteURL(name, params...)
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterURL(t *testing.T) {
+	router := NewRouter(&mockApplication{}, newTestApp())
+	handler := func(ctx *Context) error { return nil }
+
+	router.GET("/users/:id", handler).Name("users.show")
+	router.GET("/posts/:post/comments/:comment?", handler).Name("comments.show")
+	router.GET("/files/*", handler).Name("files")
+	router.Group("/api", func(r *Router) {
+		r.GET("/teams/:team", handler).Name("api.teams.show")
+	})
+
+	assert.Equal(t, "/users/42", router.URL("users.show", map[string]any{"id": 42}))
+	assert.Equal(t, "/users/a%20b", router.URL("users.show", map[string]any{"id": "a b"}))
+	assert.Equal(t, "/users/42?sort=name&tab=posts", router.URL("users.show", map[string]any{
+		"id": 42, "tab": "posts", "sort": "name",
+	}))
+	assert.Equal(t, "/posts/1/comments/2", router.URL("comments.show", map[string]any{"post": 1, "comment": 2}))
+	assert.Equal(t, "/posts/1/comments", router.URL("comments.show", map[string]any{"post": 1}))
+	assert.Equal(t, "/files/docs/a%20b.pdf", router.URL("files", map[string]any{"*": "docs/a b.pdf"}))
+	assert.Equal(t, "/api/teams/core", router.URL("api.teams.show", map[string]any{"team": "core"}))
+
+	assert.Empty(t, router.URL("users.show"), "missing required parameter")
+	assert.Empty(t, router.URL("missing"))
+}
+
+func TestRouterAbsoluteURL(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"app.url": "https://example.com/",
+	}))
+	router := NewRouter(app, newTestApp())
+	router.GET("/users/:id", func(ctx *Context) error { return nil }).Name("users.show")
+
+	assert.Equal(t, "https://example.com/users/7", router.AbsoluteURL("users.show", map[string]any{"id": 7}))
+
+	// Without app.url, paths are returned as is.
+	router = NewRouter(&mockApplication{}, newTestApp())
+	router.GET("/users/:id", func(ctx *Context) error { return nil }).Name("users.show")
+	assert.Equal(t, "/users/7", router.AbsoluteURL("users.show", map[string]any{"id": 7}))
+}
+
+func TestContextRouteAndRedirectRoute(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"app.url": "https://example.com",
+	}))
+	router := NewRouter(app, newTestApp())
+
+	router.GET("/users/:id", func(ctx *Context) error { return nil }).Name("users.show")
+	router.GET("/link", func(ctx *Context) error {
+		return ctx.String(ctx.Route("users.show", map[string]any{"id": 5}))
+	})
+	router.GET("/go", func(ctx *Context) error {
+		return ctx.Response().RedirectRoute("users.show", map[string]any{"id": 5})
+	})
+	router.GET("/broken", func(ctx *Context) error {
+		return ctx.Response().RedirectRoute("missing")
+	})
+
+	resp, err := router.fiber.Test(httptest.NewRequest("GET", "/link", nil))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "https://example.com/users/5", string(body))
+
+	resp, err = router.fiber.Test(httptest.NewRequest("GET", "/go", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 302, resp.StatusCode)
+	assert.Equal(t, "https://example.com/users/5", resp.Header.Get("Location"))
+
+	resp, err = router.fiber.Test(httptest.NewRequest("GET", "/broken", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+}
@@ -0,0 +1,252 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"runtime/debug"
+	"slices"
+	"sync"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+// WebSocket message types, as in RFC 6455.
+const (
+	TextMessage   = websocket.TextMessage
+	BinaryMessage = websocket.BinaryMessage
+)
+
+// WebSocketHandler handles an upgraded WebSocket connection. The
+// connection is closed when it returns; a returned error is logged.
+type WebSocketHandler func(conn *WebSocketConn) error
+
+// WebSocket registers a GET route that upgrades requests to WebSocket
+// connections. Middleware runs on the upgrade request as on any route, so
+// auth, sessions and request IDs apply; the connection keeps the route
+// parameters, query, headers, context values and user of that request.
+// Requests that are not upgrades are answered with 426 Upgrade Required.
+//
+// Browsers may only connect from the same origin, or from the origins
+// listed in the http.websocket_origins config value ("*" allows any).
+//
+//	router.WebSocket("/chat/:room", func(conn *http.WebSocketConn) error {
+//		room := conn.Param("room")
+//		conn.Join(room)
+//		for {
+//			_, message, err := conn.ReadMessage()
+//			if err != nil {
+//				return nil // client went away
+//			}
+//			conn.Hub().Broadcast(room, http.TextMessage, message)
+//		}
+//	}, middleware.Auth())
+func (r *Router) WebSocket(path string, handler WebSocketHandler, middleware ...MiddlewareFunc) *Route {
+	return r.GET(path, func(ctx *Context) error {
+		if !websocket.IsWebSocketUpgrade(ctx.fiberCtx) {
+			return fiber.ErrUpgradeRequired
+		}
+		if !r.allowsWebSocketOrigin(ctx) {
+			return fiber.NewError(fiber.StatusForbidden, "Origin not allowed")
+		}
+
+		values := map[string]any{}
+		ctx.store.Range(func(key, value any) bool {
+			if name, ok := key.(string); ok {
+				values[name] = value
+			}
+			return true
+		})
+		user := ctx.User()
+
+		return websocket.New(func(c *websocket.Conn) {
+			conn := &WebSocketConn{
+				conn:   c,
+				app:    r.app,
+				hub:    r.hub,
+				values: values,
+				user:   user,
+			}
+			defer r.hub.leaveAll(conn)
+
+			if err := handler(conn); err != nil {
+				if logger := conn.Logger(); logger != nil {
+					logger.Error("WebSocket handler failed", "path", path, "error", err.Error())
+				}
+			}
+		}, websocket.Config{RecoverHandler: r.recoverWebSocket(path)})(ctx.fiberCtx)
+	}, middleware...)
+}
+
+// Hub returns the hub shared by the router's WebSocket connections, for
+// broadcasting to rooms from anywhere in the app.
+func (r *Router) Hub() *WebSocketHub {
+	return r.hub
+}
+
+// allowsWebSocketOrigin checks the Origin header, which browsers always
+// send, against the request's host and the configured origins.
+func (r *Router) allowsWebSocketOrigin(ctx *Context) bool {
+	origin := ctx.fiberCtx.Get(fiber.HeaderOrigin)
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && u.Host == ctx.fiberCtx.Hostname() {
+		return true
+	}
+	if r.app == nil || r.app.GetConfig() == nil {
+		return false
+	}
+	allowed := r.app.GetConfig().GetStringSlice("http.websocket_origins")
+	return slices.Contains(allowed, "*") || slices.Contains(allowed, origin)
+}
+
+// recoverWebSocket logs panics in WebSocket handlers and closes the
+// connection with an internal error.
+func (r *Router) recoverWebSocket(path string) func(*websocket.Conn) {
+	return func(c *websocket.Conn) {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		if r.app != nil && r.app.GetLogger() != nil {
+			r.app.GetLogger().Error("WebSocket handler panicked",
+				"path", path, "panic", fmt.Sprint(recovered), "stack", string(debug.Stack()))
+		}
+		_ = c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, ""))
+	}
+}
+
+// WebSocketConn is an upgraded WebSocket connection. Writes are safe from
+// several goroutines, so the hub can broadcast while the handler writes;
+// reads belong to the handler.
+type WebSocketConn struct {
+	conn    *websocket.Conn
+	app     contracts.Application
+	hub     *WebSocketHub
+	values  map[string]any
+	user    contracts.Authenticatable
+	writeMu sync.Mutex
+}
+
+// App returns the application instance.
+func (c *WebSocketConn) App() contracts.Application {
+	return c.app
+}
+
+// Conn returns the underlying connection, for settings such as read limits
+// and deadlines.
+func (c *WebSocketConn) Conn() *websocket.Conn {
+	return c.conn
+}
+
+// Param returns a route parameter of the upgrade request.
+func (c *WebSocketConn) Param(key string, defaultValue ...string) string {
+	return c.conn.Params(key, defaultValue...)
+}
+
+// Query returns a query parameter of the upgrade request.
+func (c *WebSocketConn) Query(key string, defaultValue ...string) string {
+	return c.conn.Query(key, defaultValue...)
+}
+
+// Header returns a header of the upgrade request.
+func (c *WebSocketConn) Header(key string, defaultValue ...string) string {
+	return c.conn.Headers(key, defaultValue...)
+}
+
+// Cookie returns a cookie of the upgrade request.
+func (c *WebSocketConn) Cookie(key string, defaultValue ...string) string {
+	return c.conn.Cookies(key, defaultValue...)
+}
+
+// IP returns the client's IP address.
+func (c *WebSocketConn) IP() string {
+	return c.conn.IP()
+}
+
+// Get returns a value middleware stored on the upgrade request's context,
+// falling back to its Fiber locals.
+func (c *WebSocketConn) Get(key string) any {
+	if value, ok := c.values[key]; ok {
+		return value
+	}
+	return c.conn.Locals(key)
+}
+
+// User returns the user authenticated on the upgrade request, or nil.
+func (c *WebSocketConn) User() contracts.Authenticatable {
+	return c.user
+}
+
+// RequestID returns the ID of the upgrade request, or "".
+func (c *WebSocketConn) RequestID() string {
+	id, _ := c.Get(RequestIDKey).(string)
+	return id
+}
+
+// Logger returns the upgrade request's logger, as Context.Logger does.
+func (c *WebSocketConn) Logger() contracts.Logger {
+	if logger, ok := c.Get(LoggerKey).(contracts.Logger); ok {
+		return logger
+	}
+	if c.app == nil {
+		return nil
+	}
+	return c.app.GetLogger()
+}
+
+// ReadMessage reads the next message and its type.
+func (c *WebSocketConn) ReadMessage() (messageType int, data []byte, err error) {
+	return c.conn.ReadMessage()
+}
+
+// ReadJSON reads the next message as JSON into v.
+func (c *WebSocketConn) ReadJSON(v any) error {
+	return c.conn.ReadJSON(v)
+}
+
+// WriteMessage sends a message of the given type.
+func (c *WebSocketConn) WriteMessage(messageType int, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.conn.WriteMessage(messageType, data)
+}
+
+// WriteJSON sends v as a JSON text message.
+func (c *WebSocketConn) WriteJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.WriteMessage(TextMessage, data)
+}
+
+// Close closes the connection with a normal closure.
+func (c *WebSocketConn) Close() error {
+	_ = c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	return c.conn.Close()
+}
+
+// Hub returns the hub the connection joins rooms on.
+func (c *WebSocketConn) Hub() *WebSocketHub {
+	return c.hub
+}
+
+// Join adds the connection to a room. Connections leave all their rooms
+// when they close.
+func (c *WebSocketConn) Join(room string) {
+	c.hub.Join(c, room)
+}
+
+// Leave removes the connection from a room.
+func (c *WebSocketConn) Leave(room string) {
+	c.hub.Leave(c, room)
+}
+
+// Broadcast sends a message to the other connections in a room.
+func (c *WebSocketConn) Broadcast(room string, messageType int, data []byte) error {
+	return c.hub.broadcast(room, messageType, data, c)
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// WebSocketHub groups WebSocket connections into rooms, such as a chat
+// channel or the viewers of one document, and sends messages to them.
+type WebSocketHub struct {
+	mu    sync.RWMutex
+	rooms map[string]map[*WebSocketConn]struct{}
+}
+
+// NewWebSocketHub creates an empty hub.
+func NewWebSocketHub() *WebSocketHub {
+	return &WebSocketHub{rooms: make(map[string]map[*WebSocketConn]struct{})}
+}
+
+// Join adds a connection to a room.
+func (h *WebSocketHub) Join(conn *WebSocketConn, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	members, ok := h.rooms[room]
+	if !ok {
+		members = make(map[*WebSocketConn]struct{})
+		h.rooms[room] = members
+	}
+	members[conn] = struct{}{}
+}
+
+// Leave removes a connection from a room. Empty rooms are dropped.
+func (h *WebSocketHub) Leave(conn *WebSocketConn, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.leave(conn, room)
+}
+
+func (h *WebSocketHub) leave(conn *WebSocketConn, room string) {
+	members := h.rooms[room]
+	delete(members, conn)
+	if len(members) == 0 {
+		delete(h.rooms, room)
+	}
+}
+
+// leaveAll removes a closed connection from every room.
+func (h *WebSocketHub) leaveAll(conn *WebSocketConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for room, members := range h.rooms {
+		if _, ok := members[conn]; ok {
+			h.leave(conn, room)
+		}
+	}
+}
+
+// Rooms returns the names of rooms with connections, sorted.
+func (h *WebSocketHub) Rooms() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	rooms := make([]string, 0, len(h.rooms))
+	for room := range h.rooms {
+		rooms = append(rooms, room)
+	}
+	sort.Strings(rooms)
+	return rooms
+}
+
+// Count returns the number of connections in a room.
+func (h *WebSocketHub) Count(room string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.rooms[room])
+}
+
+// Broadcast sends a message to every connection in a room. Failed writes,
+// usually to clients that went away, are joined into the returned error;
+// the other connections still receive the message.
+func (h *WebSocketHub) Broadcast(room string, messageType int, data []byte) error {
+	return h.broadcast(room, messageType, data, nil)
+}
+
+// BroadcastJSON sends v as a JSON text message to every connection in a room.
+func (h *WebSocketHub) BroadcastJSON(room string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return h.broadcast(room, TextMessage, data, nil)
+}
+
+// broadcast sends to a room's connections except one.
+func (h *WebSocketHub) broadcast(room string, messageType int, data []byte, except *WebSocketConn) error {
+	h.mu.RLock()
+	members := make([]*WebSocketConn, 0, len(h.rooms[room]))
+	for conn := range h.rooms[room] {
+		if conn != except {
+			members = append(members, conn)
+		}
+	}
+	h.mu.RUnlock()
+
+	var errs []error
+	for _, conn := range members {
+		if err := conn.WriteMessage(messageType, data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"net"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveWebSocket starts app on a local port and returns its address.
+func serveWebSocket(t *testing.T, app *fiber.App) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go app.Listener(ln)
+	t.Cleanup(func() { app.Shutdown() })
+	return ln.Addr().String()
+}
+
+func dialWebSocket(t *testing.T, url string, header nethttp.Header) *websocket.Conn {
+	t.Helper()
+
+	conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+	require.NoError(t, err)
+	resp.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+func TestRouterWebSocket(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := NewRouter(&mockApplication{}, app)
+
+	joined := make(chan struct{}, 2)
+	router.WebSocket("/rooms/:room", func(conn *WebSocketConn) error {
+		room := conn.Param("room")
+		conn.Join(room)
+		joined <- struct{}{}
+
+		if err := conn.WriteJSON(map[string]any{
+			"room":  room,
+			"name":  conn.Query("name"),
+			"trace": conn.Get("trace"),
+		}); err != nil {
+			return err
+		}
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return nil
+			}
+			conn.Broadcast(room, TextMessage, message)
+		}
+	}, func(ctx *Context, next func() error) error {
+		ctx.Set("trace", "abc")
+		return next()
+	})
+	addr := serveWebSocket(t, app)
+
+	alice := dialWebSocket(t, "ws://"+addr+"/rooms/general?name=alice", nil)
+	var hello map[string]any
+	require.NoError(t, alice.ReadJSON(&hello))
+	assert.Equal(t, map[string]any{"room": "general", "name": "alice", "trace": "abc"}, hello)
+
+	bob := dialWebSocket(t, "ws://"+addr+"/rooms/general?name=bob", nil)
+	require.NoError(t, bob.ReadJSON(&hello))
+	<-joined
+	<-joined
+	assert.Equal(t, 2, router.Hub().Count("general"))
+
+	// Messages reach the other members of the room, not the sender.
+	require.NoError(t, alice.WriteMessage(websocket.TextMessage, []byte("hi bob")))
+	_, message, err := bob.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "hi bob", string(message))
+
+	require.NoError(t, router.Hub().BroadcastJSON("general", map[string]string{"from": "server"}))
+	var fromServer map[string]string
+	require.NoError(t, alice.ReadJSON(&fromServer))
+	assert.Equal(t, "server", fromServer["from"])
+
+	// Closed connections leave their rooms.
+	bob.Close()
+	assert.Eventually(t, func() bool { return router.Hub().Count("general") == 1 }, 2*time.Second, 10*time.Millisecond)
+	alice.Close()
+	assert.Eventually(t, func() bool { return len(router.Hub().Rooms()) == 0 }, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestRouterWebSocketRejects(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := NewRouter(&mockApplication{}, app)
+	router.WebSocket("/ws", func(conn *WebSocketConn) error { return nil })
+	router.WebSocket("/private", func(conn *WebSocketConn) error { return nil }, func(ctx *Context, next func() error) error {
+		return fiber.ErrUnauthorized
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/ws", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUpgradeRequired, resp.StatusCode)
+
+	addr := serveWebSocket(t, app)
+
+	_, resp, err = websocket.DefaultDialer.Dial("ws://"+addr+"/ws", nethttp.Header{"Origin": {"https://evil.example"}})
+	require.Error(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	dialWebSocket(t, "ws://"+addr+"/ws", nethttp.Header{"Origin": {"http://" + addr}})
+
+	_, resp, err = websocket.DefaultDialer.Dial("ws://"+addr+"/private", nil)
+	require.Error(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
@@ -0,0 +1,216 @@
+// Package httpclient is a fluent HTTP client for calling other services.
+//
+//	resp, err := httpclient.WithToken(token).
+//		Retry(3, httpclient.Exponential(100*time.Millisecond)).
+//		Post("https://api.example.com/orders", map[string]any{"sku": "A-1"})
+//
+// Requests share a pooled transport, so connections to the same host are
+// reused. Fake replaces the transport with stubbed responses and records
+// every request for assertions in tests.
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MacroFunc configures a request, such as setting the base URL and token of
+// an API the application calls often.
+type MacroFunc func(r *PendingRequest) *PendingRequest
+
+// PoolRequest sends one of the requests of a pool.
+type PoolRequest func(r *PendingRequest) (*Response, error)
+
+// PoolResult is the outcome of a pooled request.
+type PoolResult struct {
+	Response *Response
+	Err      error
+}
+
+// Factory creates requests sharing an HTTP client and macros.
+type Factory struct {
+	client   *http.Client
+	macros   map[string]MacroFunc
+	recorder *Recorder
+	mu       sync.RWMutex
+}
+
+// New creates a factory sending requests with client, or with a client
+// whose transport keeps up to 100 idle connections, 10 per host.
+func New(client ...*http.Client) *Factory {
+	c := &http.Client{Transport: newTransport()}
+	if len(client) > 0 && client[0] != nil {
+		c = client[0]
+	}
+	return &Factory{
+		client: c,
+		macros: make(map[string]MacroFunc),
+	}
+}
+
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 100
+	transport.MaxIdleConnsPerHost = 10
+	transport.IdleConnTimeout = 90 * time.Second
+	return transport
+}
+
+// Request starts a new request.
+func (f *Factory) Request() *PendingRequest {
+	return newPendingRequest(f)
+}
+
+// Macro registers a macro, applied to new requests with Use.
+func (f *Factory) Macro(name string, macro MacroFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.macros[name] = macro
+}
+
+// HasMacro reports whether a macro is registered.
+func (f *Factory) HasMacro(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	_, ok := f.macros[name]
+	return ok
+}
+
+// Use starts a new request configured by the named macro. It panics if the
+// macro is not registered.
+func (f *Factory) Use(name string) *PendingRequest {
+	f.mu.RLock()
+	macro, ok := f.macros[name]
+	f.mu.RUnlock()
+	if !ok {
+		panic(fmt.Sprintf("httpclient: macro [%s] not defined", name))
+	}
+	return macro(f.Request())
+}
+
+// Pool sends requests concurrently, each with a new request, and returns
+// their results in the order given.
+func (f *Factory) Pool(requests ...PoolRequest) []PoolResult {
+	results := make([]PoolResult, len(requests))
+	var wg sync.WaitGroup
+	for i, send := range requests {
+		wg.Add(1)
+		go func(i int, send PoolRequest) {
+			defer wg.Done()
+			results[i].Response, results[i].Err = send(f.Request())
+		}(i, send)
+	}
+	wg.Wait()
+	return results
+}
+
+// Fake replaces the factory's transport with stubs and records the
+// requests sent; see Recorder. Call Restore to send real requests again.
+func (f *Factory) Fake(stubs ...map[string]Stub) *Recorder {
+	recorder := newRecorder(stubs...)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.recorder = recorder
+	return recorder
+}
+
+// Restore removes the fake set by Fake.
+func (f *Factory) Restore() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.recorder = nil
+}
+
+// httpClient returns the client requests are sent with.
+func (f *Factory) httpClient() *http.Client {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.recorder != nil {
+		return &http.Client{Transport: f.recorder, CheckRedirect: f.client.CheckRedirect, Jar: f.client.Jar}
+	}
+	return f.client
+}
+
+var (
+	defaultFactory = New()
+	defaultMu      sync.RWMutex
+)
+
+// Default returns the factory used by the package-level functions.
+func Default() *Factory {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultFactory
+}
+
+// SetDefault replaces the factory used by the package-level functions.
+func SetDefault(factory *Factory) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultFactory = factory
+}
+
+// NewRequest starts a new request with the default factory.
+func NewRequest() *PendingRequest { return Default().Request() }
+
+// BaseURL starts a request with a base URL for relative URLs.
+func BaseURL(url string) *PendingRequest { return Default().Request().BaseURL(url) }
+
+// WithHeaders starts a request with headers.
+func WithHeaders(headers map[string]string) *PendingRequest {
+	return Default().Request().WithHeaders(headers)
+}
+
+// WithToken starts a request with a bearer token.
+func WithToken(token string, tokenType ...string) *PendingRequest {
+	return Default().Request().WithToken(token, tokenType...)
+}
+
+// WithBasicAuth starts a request with basic authentication.
+func WithBasicAuth(username, password string) *PendingRequest {
+	return Default().Request().WithBasicAuth(username, password)
+}
+
+// AsForm starts a request sending its body form-encoded.
+func AsForm() *PendingRequest { return Default().Request().AsForm() }
+
+// Timeout starts a request with a timeout.
+func Timeout(timeout time.Duration) *PendingRequest { return Default().Request().Timeout(timeout) }
+
+// Retry starts a request that is retried; see PendingRequest.Retry.
+func Retry(times int, backoff Backoff) *PendingRequest {
+	return Default().Request().Retry(times, backoff)
+}
+
+// Use starts a request configured by a macro of the default factory.
+func Use(name string) *PendingRequest { return Default().Use(name) }
+
+// Macro registers a macro on the default factory.
+func Macro(name string, macro MacroFunc) { Default().Macro(name, macro) }
+
+// Get sends a GET request.
+func Get(url string) (*Response, error) { return Default().Request().Get(url) }
+
+// Post sends a POST request.
+func Post(url string, body any) (*Response, error) { return Default().Request().Post(url, body) }
+
+// Put sends a PUT request.
+func Put(url string, body any) (*Response, error) { return Default().Request().Put(url, body) }
+
+// Patch sends a PATCH request.
+func Patch(url string, body any) (*Response, error) { return Default().Request().Patch(url, body) }
+
+// Delete sends a DELETE request.
+func Delete(url string) (*Response, error) { return Default().Request().Delete(url) }
+
+// Pool sends requests concurrently with the default factory.
+func Pool(requests ...PoolRequest) []PoolResult { return Default().Pool(requests...) }
+
+// Fake fakes the default factory's transport.
+func Fake(stubs ...map[string]Stub) *Recorder { return Default().Fake(stubs...) }
+
+// Restore removes the default factory's fake.
+func Restore() { Default().Restore() }
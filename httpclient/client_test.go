@@ -0,0 +1,147 @@
+package httpclient_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestAgainstServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"method":        r.Method,
+			"path":          r.URL.Path,
+			"query":         r.URL.RawQuery,
+			"authorization": r.Header.Get("Authorization"),
+			"content_type":  r.Header.Get("Content-Type"),
+			"body":          string(body),
+		})
+	}))
+	defer server.Close()
+
+	client := httpclient.New()
+	resp, err := client.Request().
+		BaseURL(server.URL+"/api/").
+		WithToken("secret").
+		WithQuery(map[string]string{"page": "2"}).
+		Post("/orders", map[string]any{"sku": "A-1"})
+	require.NoError(t, err)
+	assert.True(t, resp.OK())
+	assert.Equal(t, "application/json", resp.Header("Content-Type"))
+
+	var echoed map[string]string
+	require.NoError(t, resp.JSON(&echoed))
+	assert.Equal(t, "POST", echoed["method"])
+	assert.Equal(t, "/api/orders", echoed["path"])
+	assert.Equal(t, "page=2", echoed["query"])
+	assert.Equal(t, "Bearer secret", echoed["authorization"])
+	assert.Equal(t, "application/json", echoed["content_type"])
+	assert.JSONEq(t, `{"sku":"A-1"}`, echoed["body"])
+
+	resp, err = client.Request().AsForm().WithBasicAuth("jane", "pw").Put(server.URL+"/users/1", map[string]string{"name": "Jane"})
+	require.NoError(t, err)
+	require.NoError(t, resp.JSON(&echoed))
+	assert.Equal(t, "application/x-www-form-urlencoded", echoed["content_type"])
+	assert.Equal(t, "name=Jane", echoed["body"])
+	assert.Equal(t, "Basic amFuZTpwdw==", echoed["authorization"])
+}
+
+func TestRetry(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := httpclient.New()
+	resp, err := client.Request().Retry(3, httpclient.Constant(time.Millisecond)).Get(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.String())
+	assert.EqualValues(t, 3, calls.Load())
+
+	calls.Store(0)
+	resp, err = client.Request().Retry(2, nil).Throw().Get(server.URL)
+	var requestErr *httpclient.RequestError
+	require.ErrorAs(t, err, &requestErr)
+	assert.Equal(t, http.StatusServiceUnavailable, requestErr.Response.Status())
+	assert.True(t, resp.ServerError())
+	assert.EqualValues(t, 2, calls.Load())
+
+	calls.Store(0)
+	resp, err = client.Request().
+		Retry(5, nil).
+		RetryWhen(func(resp *httpclient.Response, err error) bool { return false }).
+		Get(server.URL)
+	require.NoError(t, err)
+	assert.Error(t, resp.Err())
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	_, err := httpclient.New().Request().Timeout(20 * time.Millisecond).Get(server.URL)
+	assert.Error(t, err)
+}
+
+func TestExponential(t *testing.T) {
+	backoff := httpclient.Exponential(100 * time.Millisecond)
+	assert.Equal(t, 100*time.Millisecond, backoff(1))
+	assert.Equal(t, 400*time.Millisecond, backoff(3))
+}
+
+func TestMacrosAndPool(t *testing.T) {
+	client := httpclient.New()
+	fake := client.Fake(map[string]httpclient.Stub{
+		"api.github.com/users/*": httpclient.FakeResponse(map[string]string{"login": "jane"}, 200),
+		"api.github.com/*":       httpclient.FakeResponse("not found", 404),
+	})
+	client.Macro("github", func(r *httpclient.PendingRequest) *httpclient.PendingRequest {
+		return r.BaseURL("https://api.github.com").WithToken("gh-token").AcceptJSON()
+	})
+	assert.True(t, client.HasMacro("github"))
+	assert.Panics(t, func() { client.Use("gitlab") })
+
+	results := client.Pool(
+		func(r *httpclient.PendingRequest) (*httpclient.Response, error) {
+			return client.Use("github").Get("/users/jane")
+		},
+		func(r *httpclient.PendingRequest) (*httpclient.Response, error) {
+			return r.Get("https://api.github.com/repos")
+		},
+		func(r *httpclient.PendingRequest) (*httpclient.Response, error) {
+			return nil, errors.New("skipped")
+		},
+	)
+	require.Len(t, results, 3)
+	require.NoError(t, results[0].Err)
+	assert.JSONEq(t, `{"login":"jane"}`, results[0].Response.String())
+	assert.Equal(t, 404, results[1].Response.Status())
+	assert.EqualError(t, results[2].Err, "skipped")
+
+	fake.AssertSentCount(t, 2)
+	fake.AssertSent(t, func(req *httpclient.Request) bool {
+		return req.URL == "https://api.github.com/users/jane" && req.HasHeader("Authorization", "Bearer gh-token")
+	})
+}
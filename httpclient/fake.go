@@ -0,0 +1,252 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Request is a request sent to a fake.
+type Request struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// HasHeader reports whether the request has a header, with value if given.
+func (r *Request) HasHeader(name string, value ...string) bool {
+	values, ok := r.Header[http.CanonicalHeaderKey(name)]
+	if !ok || len(value) == 0 {
+		return ok
+	}
+	for _, v := range values {
+		if v == value[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// JSON decodes the JSON request body into v.
+func (r *Request) JSON(v any) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Form parses the form-encoded request body.
+func (r *Request) Form() url.Values {
+	form, _ := url.ParseQuery(string(r.Body))
+	return form
+}
+
+// Stub answers a faked request, with a response or a connection error.
+type Stub func(req *Request) (*http.Response, error)
+
+// FakeResponse answers with body and status, 200 if zero. A string or
+// []byte body is sent as is; other bodies are encoded as JSON.
+func FakeResponse(body any, status int, headers ...map[string]string) Stub {
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return func(*Request) (*http.Response, error) {
+		header := make(http.Header)
+		var content []byte
+		switch body := body.(type) {
+		case nil:
+		case string:
+			content = []byte(body)
+		case []byte:
+			content = body
+		default:
+			encoded, err := json.Marshal(body)
+			if err != nil {
+				return nil, err
+			}
+			content = encoded
+			header.Set("Content-Type", "application/json")
+		}
+		for _, h := range headers {
+			for name, value := range h {
+				header.Set(name, value)
+			}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+			StatusCode:    status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(content)),
+			ContentLength: int64(len(content)),
+		}, nil
+	}
+}
+
+// ErrConnectionFailed is the error of requests answered by FailedConnection.
+var ErrConnectionFailed = errors.New("httpclient: connection failed")
+
+// FailedConnection answers as if the server could not be reached.
+func FailedConnection() Stub {
+	return func(*Request) (*http.Response, error) {
+		return nil, ErrConnectionFailed
+	}
+}
+
+// Sequence answers with each stub in turn, then with empty 200 responses.
+func Sequence(stubs ...Stub) Stub {
+	var mu sync.Mutex
+	next := 0
+	return func(req *Request) (*http.Response, error) {
+		mu.Lock()
+		if next >= len(stubs) {
+			mu.Unlock()
+			return FakeResponse(nil, http.StatusOK)(req)
+		}
+		stub := stubs[next]
+		next++
+		mu.Unlock()
+		return stub(req)
+	}
+}
+
+// Recorder answers requests with stubs and records them.
+type Recorder struct {
+	patterns []string
+	stubs    map[string]Stub
+	requests []*Request
+	mu       sync.Mutex
+}
+
+// newRecorder creates a recorder for stubs keyed by URL pattern, in which
+// "*" matches any run of characters: "api.github.com/*" or "*". Patterns
+// may leave out the scheme. The longest matching pattern answers; requests
+// no pattern matches get empty 200 responses.
+func newRecorder(stubs ...map[string]Stub) *Recorder {
+	r := &Recorder{stubs: make(map[string]Stub)}
+	for _, set := range stubs {
+		for pattern, stub := range set {
+			if _, ok := r.stubs[pattern]; !ok {
+				r.patterns = append(r.patterns, pattern)
+			}
+			r.stubs[pattern] = stub
+		}
+	}
+	sort.SliceStable(r.patterns, func(i, j int) bool { return len(r.patterns[i]) > len(r.patterns[j]) })
+	return r
+}
+
+// RoundTrip records req and answers it with the matching stub.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded := &Request{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header.Clone(),
+	}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		recorded.Body = body
+	}
+
+	r.mu.Lock()
+	r.requests = append(r.requests, recorded)
+	stub := FakeResponse(nil, http.StatusOK)
+	withoutScheme := recorded.URL
+	if _, rest, ok := strings.Cut(withoutScheme, "://"); ok {
+		withoutScheme = rest
+	}
+	for _, pattern := range r.patterns {
+		if wildcardMatch(pattern, recorded.URL) || wildcardMatch(pattern, withoutScheme) {
+			stub = r.stubs[pattern]
+			break
+		}
+	}
+	r.mu.Unlock()
+
+	resp, err := stub(recorded)
+	if resp != nil {
+		resp.Request = req
+	}
+	return resp, err
+}
+
+// Recorded returns the requests sent so far.
+func (r *Recorder) Recorded() []*Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Request(nil), r.requests...)
+}
+
+// Sent returns the requests sent so far that match.
+func (r *Recorder) Sent(match func(req *Request) bool) []*Request {
+	var sent []*Request
+	for _, req := range r.Recorded() {
+		if match(req) {
+			sent = append(sent, req)
+		}
+	}
+	return sent
+}
+
+// AssertSent fails the test unless a request that matches was sent.
+func (r *Recorder) AssertSent(t testing.TB, match func(req *Request) bool) {
+	t.Helper()
+	if len(r.Sent(match)) == 0 {
+		t.Errorf("httpclient: expected request was not sent (%d requests recorded)", len(r.Recorded()))
+	}
+}
+
+// AssertNotSent fails the test if a request that matches was sent.
+func (r *Recorder) AssertNotSent(t testing.TB, match func(req *Request) bool) {
+	t.Helper()
+	if sent := r.Sent(match); len(sent) > 0 {
+		t.Errorf("httpclient: unexpected request was sent: %s %s", sent[0].Method, sent[0].URL)
+	}
+}
+
+// AssertSentCount fails the test unless count requests were sent.
+func (r *Recorder) AssertSentCount(t testing.TB, count int) {
+	t.Helper()
+	if sent := len(r.Recorded()); sent != count {
+		t.Errorf("httpclient: expected %d requests to be sent, got %d", count, sent)
+	}
+}
+
+// AssertNothingSent fails the test if any request was sent.
+func (r *Recorder) AssertNothingSent(t testing.TB) {
+	t.Helper()
+	r.AssertSentCount(t, 0)
+}
+
+// wildcardMatch matches s against a pattern where "*" matches any run of
+// characters.
+func wildcardMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
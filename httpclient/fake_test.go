@@ -0,0 +1,58 @@
+package httpclient_test
+
+import (
+	"testing"
+
+	"github.com/genesysflow/go-genesys/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFake(t *testing.T) {
+	fake := httpclient.Fake(map[string]httpclient.Stub{
+		"https://payments.example.com/charges": httpclient.Sequence(
+			httpclient.FailedConnection(),
+			httpclient.FakeResponse(map[string]string{"id": "ch_1"}, 201, map[string]string{"X-Request-Id": "abc"}),
+		),
+	})
+	defer httpclient.Restore()
+
+	fake.AssertNothingSent(t)
+
+	resp, err := httpclient.WithToken("sk_test").Retry(2, nil).Post("https://payments.example.com/charges", map[string]any{"amount": 500})
+	require.NoError(t, err)
+	assert.Equal(t, 201, resp.Status())
+	assert.Equal(t, "abc", resp.Header("X-Request-Id"))
+
+	resp, err = httpclient.AsForm().Post("https://payments.example.com/refunds", map[string]string{"charge": "ch_1"})
+	require.NoError(t, err)
+	assert.True(t, resp.OK())
+	assert.Empty(t, resp.Body())
+
+	fake.AssertSentCount(t, 3)
+	fake.AssertSent(t, func(req *httpclient.Request) bool {
+		var body map[string]int
+		return req.Method == "POST" && req.JSON(&body) == nil && body["amount"] == 500
+	})
+	fake.AssertSent(t, func(req *httpclient.Request) bool {
+		return req.Form().Get("charge") == "ch_1"
+	})
+	fake.AssertNotSent(t, func(req *httpclient.Request) bool {
+		return req.Method == "DELETE"
+	})
+
+	mockT := &testing.T{}
+	fake.AssertNothingSent(mockT)
+	assert.True(t, mockT.Failed())
+}
+
+func TestFakeConnectionFailure(t *testing.T) {
+	client := httpclient.New()
+	client.Fake(map[string]httpclient.Stub{"*": httpclient.FailedConnection()})
+
+	_, err := client.Request().Get("https://example.com")
+	assert.ErrorIs(t, err, httpclient.ErrConnectionFailed)
+
+	client.Restore()
+	assert.Empty(t, client.Fake().Recorded())
+}
@@ -0,0 +1,356 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Body formats.
+const (
+	formatJSON = "json"
+	formatForm = "form"
+)
+
+// DefaultTimeout is the timeout of each attempt of a request unless set
+// with Timeout.
+const DefaultTimeout = 30 * time.Second
+
+// Backoff returns how long to wait before a retry; attempt is 1 for the
+// first retry.
+type Backoff func(attempt int) time.Duration
+
+// Constant waits the same time before every retry.
+func Constant(wait time.Duration) Backoff {
+	return func(int) time.Duration { return wait }
+}
+
+// Exponential doubles the wait before every retry, starting at base.
+func Exponential(base time.Duration) Backoff {
+	return func(attempt int) time.Duration { return base << (attempt - 1) }
+}
+
+// PendingRequest is a request being configured. Its methods return the
+// request so they can be chained, ending with a method such as Get or Post
+// that sends it.
+type PendingRequest struct {
+	factory   *Factory
+	ctx       context.Context
+	baseURL   string
+	header    http.Header
+	query     url.Values
+	format    string
+	timeout   time.Duration
+	attempts  int
+	backoff   Backoff
+	retryWhen func(resp *Response, err error) bool
+	throw     bool
+}
+
+func newPendingRequest(factory *Factory) *PendingRequest {
+	return &PendingRequest{
+		factory:  factory,
+		ctx:      context.Background(),
+		header:   make(http.Header),
+		query:    make(url.Values),
+		format:   formatJSON,
+		timeout:  DefaultTimeout,
+		attempts: 1,
+	}
+}
+
+// BaseURL sets the URL relative request URLs are resolved against.
+func (r *PendingRequest) BaseURL(baseURL string) *PendingRequest {
+	r.baseURL = baseURL
+	return r
+}
+
+// WithContext sets the context the request is sent with.
+func (r *PendingRequest) WithContext(ctx context.Context) *PendingRequest {
+	r.ctx = ctx
+	return r
+}
+
+// WithHeader sets a header.
+func (r *PendingRequest) WithHeader(name, value string) *PendingRequest {
+	r.header.Set(name, value)
+	return r
+}
+
+// WithHeaders sets headers.
+func (r *PendingRequest) WithHeaders(headers map[string]string) *PendingRequest {
+	for name, value := range headers {
+		r.header.Set(name, value)
+	}
+	return r
+}
+
+// WithToken sets the Authorization header to a token of tokenType,
+// "Bearer" by default.
+func (r *PendingRequest) WithToken(token string, tokenType ...string) *PendingRequest {
+	kind := "Bearer"
+	if len(tokenType) > 0 {
+		kind = tokenType[0]
+	}
+	return r.WithHeader("Authorization", kind+" "+token)
+}
+
+// WithBasicAuth sets basic authentication credentials.
+func (r *PendingRequest) WithBasicAuth(username, password string) *PendingRequest {
+	req := http.Request{Header: make(http.Header)}
+	req.SetBasicAuth(username, password)
+	return r.WithHeader("Authorization", req.Header.Get("Authorization"))
+}
+
+// WithUserAgent sets the User-Agent header.
+func (r *PendingRequest) WithUserAgent(userAgent string) *PendingRequest {
+	return r.WithHeader("User-Agent", userAgent)
+}
+
+// Accept sets the Accept header.
+func (r *PendingRequest) Accept(contentType string) *PendingRequest {
+	return r.WithHeader("Accept", contentType)
+}
+
+// AcceptJSON accepts JSON responses.
+func (r *PendingRequest) AcceptJSON() *PendingRequest {
+	return r.Accept("application/json")
+}
+
+// AsJSON sends bodies as JSON. This is the default.
+func (r *PendingRequest) AsJSON() *PendingRequest {
+	r.format = formatJSON
+	return r
+}
+
+// AsForm sends bodies form-encoded.
+func (r *PendingRequest) AsForm() *PendingRequest {
+	r.format = formatForm
+	return r
+}
+
+// WithQuery adds query string parameters.
+func (r *PendingRequest) WithQuery(query map[string]string) *PendingRequest {
+	for key, value := range query {
+		r.query.Set(key, value)
+	}
+	return r
+}
+
+// Timeout sets the timeout of each attempt.
+func (r *PendingRequest) Timeout(timeout time.Duration) *PendingRequest {
+	r.timeout = timeout
+	return r
+}
+
+// Retry makes up to times attempts, waiting backoff between them, while the
+// request fails to connect or the response is a 5xx or 429. RetryWhen
+// changes which failures are retried. A nil backoff retries immediately.
+func (r *PendingRequest) Retry(times int, backoff Backoff) *PendingRequest {
+	r.attempts = max(times, 1)
+	r.backoff = backoff
+	return r
+}
+
+// RetryWhen sets which attempts are retried: those for which when returns
+// true, given the response, or the error when there is none.
+func (r *PendingRequest) RetryWhen(when func(resp *Response, err error) bool) *PendingRequest {
+	r.retryWhen = when
+	return r
+}
+
+// Throw makes responses with a 4xx or 5xx status return a *RequestError.
+func (r *PendingRequest) Throw() *PendingRequest {
+	r.throw = true
+	return r
+}
+
+// Get sends a GET request.
+func (r *PendingRequest) Get(url string) (*Response, error) {
+	return r.Send(http.MethodGet, url, nil)
+}
+
+// Head sends a HEAD request.
+func (r *PendingRequest) Head(url string) (*Response, error) {
+	return r.Send(http.MethodHead, url, nil)
+}
+
+// Post sends a POST request.
+func (r *PendingRequest) Post(url string, body any) (*Response, error) {
+	return r.Send(http.MethodPost, url, body)
+}
+
+// Put sends a PUT request.
+func (r *PendingRequest) Put(url string, body any) (*Response, error) {
+	return r.Send(http.MethodPut, url, body)
+}
+
+// Patch sends a PATCH request.
+func (r *PendingRequest) Patch(url string, body any) (*Response, error) {
+	return r.Send(http.MethodPatch, url, body)
+}
+
+// Delete sends a DELETE request.
+func (r *PendingRequest) Delete(url string) (*Response, error) {
+	return r.Send(http.MethodDelete, url, nil)
+}
+
+// Send sends a request. A string, []byte or io.Reader body is sent as is;
+// url.Values are form-encoded; other values are encoded as JSON, or as a
+// form after AsForm.
+func (r *PendingRequest) Send(method, rawURL string, body any) (*Response, error) {
+	target, err := r.url(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	payload, contentType, err := r.encode(body)
+	if err != nil {
+		return nil, err
+	}
+
+	client := r.factory.httpClient()
+	var resp *Response
+	for attempt := 1; ; attempt++ {
+		resp, err = r.attempt(client, method, target, payload, contentType)
+		if attempt >= r.attempts || !r.shouldRetry(resp, err) {
+			break
+		}
+		if r.backoff != nil {
+			select {
+			case <-time.After(r.backoff(attempt)):
+			case <-r.ctx.Done():
+				return resp, r.ctx.Err()
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.throw && resp.Failed() {
+		return resp, &RequestError{Response: resp}
+	}
+	return resp, nil
+}
+
+// attempt sends the request once.
+func (r *PendingRequest) attempt(client *http.Client, method, target string, payload []byte, contentType string) (*Response, error) {
+	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+	defer cancel()
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: %w", err)
+	}
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	if contentType != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	raw, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: %s %s: %w", method, target, err)
+	}
+	defer raw.Body.Close()
+	content, err := io.ReadAll(raw.Body)
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: failed to read response: %w", err)
+	}
+	return &Response{raw: raw, body: content}, nil
+}
+
+func (r *PendingRequest) shouldRetry(resp *Response, err error) bool {
+	if r.ctx.Err() != nil {
+		return false
+	}
+	if r.retryWhen != nil {
+		return r.retryWhen(resp, err)
+	}
+	return err != nil || resp.ServerError() || resp.Status() == http.StatusTooManyRequests
+}
+
+// url resolves rawURL against the base URL and adds the query parameters.
+func (r *PendingRequest) url(rawURL string) (string, error) {
+	if r.baseURL != "" && !strings.Contains(rawURL, "://") {
+		rawURL = strings.TrimRight(r.baseURL, "/") + "/" + strings.TrimLeft(rawURL, "/")
+	}
+	if len(r.query) == 0 {
+		return rawURL, nil
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("httpclient: %w", err)
+	}
+	query := parsed.Query()
+	for key, values := range r.query {
+		query[key] = values
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
+// encode encodes a body, returning its content type.
+func (r *PendingRequest) encode(body any) ([]byte, string, error) {
+	switch body := body.(type) {
+	case nil:
+		return nil, "", nil
+	case []byte:
+		return body, "", nil
+	case string:
+		return []byte(body), "", nil
+	case io.Reader:
+		content, err := io.ReadAll(body)
+		if err != nil {
+			return nil, "", fmt.Errorf("httpclient: failed to read body: %w", err)
+		}
+		return content, "", nil
+	case url.Values:
+		return []byte(body.Encode()), "application/x-www-form-urlencoded", nil
+	}
+
+	if r.format == formatForm {
+		form, err := formValues(body)
+		if err != nil {
+			return nil, "", err
+		}
+		return []byte(form.Encode()), "application/x-www-form-urlencoded", nil
+	}
+	content, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("httpclient: failed to encode body: %w", err)
+	}
+	return content, "application/json", nil
+}
+
+// formValues converts a map to form values.
+func formValues(body any) (url.Values, error) {
+	form := make(url.Values)
+	switch body := body.(type) {
+	case map[string]string:
+		for key, value := range body {
+			form.Set(key, value)
+		}
+	case map[string]any:
+		for key, value := range body {
+			if values, ok := value.([]string); ok {
+				form[key] = values
+				continue
+			}
+			form.Set(key, fmt.Sprint(value))
+		}
+	default:
+		return nil, fmt.Errorf("httpclient: can't form-encode %T", body)
+	}
+	return form, nil
+}
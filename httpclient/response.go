@@ -0,0 +1,99 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Response is a received response. Its body has been read in full.
+type Response struct {
+	raw  *http.Response
+	body []byte
+}
+
+// Status returns the status code.
+func (r *Response) Status() int {
+	return r.raw.StatusCode
+}
+
+// Header returns a response header.
+func (r *Response) Header(name string) string {
+	return r.raw.Header.Get(name)
+}
+
+// Headers returns the response headers.
+func (r *Response) Headers() http.Header {
+	return r.raw.Header
+}
+
+// Body returns the response body.
+func (r *Response) Body() []byte {
+	return r.body
+}
+
+// String returns the response body as a string.
+func (r *Response) String() string {
+	return string(r.body)
+}
+
+// JSON decodes the JSON response body into v.
+func (r *Response) JSON(v any) error {
+	if err := json.Unmarshal(r.body, v); err != nil {
+		return fmt.Errorf("httpclient: failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// OK reports whether the status is 200.
+func (r *Response) OK() bool {
+	return r.Status() == http.StatusOK
+}
+
+// Successful reports whether the status is 2xx.
+func (r *Response) Successful() bool {
+	return r.Status() >= 200 && r.Status() < 300
+}
+
+// Redirect reports whether the status is 3xx.
+func (r *Response) Redirect() bool {
+	return r.Status() >= 300 && r.Status() < 400
+}
+
+// Failed reports whether the status is 4xx or 5xx.
+func (r *Response) Failed() bool {
+	return r.ClientError() || r.ServerError()
+}
+
+// ClientError reports whether the status is 4xx.
+func (r *Response) ClientError() bool {
+	return r.Status() >= 400 && r.Status() < 500
+}
+
+// ServerError reports whether the status is 5xx.
+func (r *Response) ServerError() bool {
+	return r.Status() >= 500
+}
+
+// Err returns a *RequestError when the status is 4xx or 5xx, and nil
+// otherwise.
+func (r *Response) Err() error {
+	if r.Failed() {
+		return &RequestError{Response: r}
+	}
+	return nil
+}
+
+// Raw returns the underlying response. Its body has already been read.
+func (r *Response) Raw() *http.Response {
+	return r.raw
+}
+
+// RequestError is returned for responses with a 4xx or 5xx status.
+type RequestError struct {
+	Response *Response
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("httpclient: request failed with status %d", e.Response.Status())
+}
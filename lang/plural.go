@@ -0,0 +1,205 @@
+package lang
+
+import (
+	"strconv"
+	"strings"
+)
+
+// choose picks the form of a plural line for count in locale. Forms with an
+// explicit count ("{0}") or range ("[2,*]") are matched first; otherwise
+// the locale's plural rules index the remaining forms.
+func choose(line string, count int, locale string) string {
+	forms := strings.Split(line, "|")
+
+	plain := make([]string, 0, len(forms))
+	for _, form := range forms {
+		form = strings.TrimSpace(form)
+		text, matched := matchInterval(form, count)
+		if matched {
+			return text
+		}
+		plain = append(plain, text)
+	}
+
+	index := PluralIndex(locale, count)
+	if index >= len(plain) {
+		index = len(plain) - 1
+	}
+	return plain[index]
+}
+
+// matchInterval matches a form's "{n}" or "[min,max]" prefix against count.
+// It returns the form without its prefix and whether it selects count.
+func matchInterval(form string, count int) (string, bool) {
+	if form == "" || (form[0] != '{' && form[0] != '[') {
+		return form, false
+	}
+	closing := "}"
+	if form[0] == '[' {
+		closing = "]"
+	}
+	end := strings.Index(form, closing)
+	if end < 0 {
+		return form, false
+	}
+	interval, text := form[1:end], strings.TrimSpace(form[end+1:])
+
+	if closing == "}" {
+		for _, value := range strings.Split(interval, ",") {
+			if n, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && n == count {
+				return text, true
+			}
+		}
+		return text, false
+	}
+
+	bounds := strings.SplitN(interval, ",", 2)
+	if len(bounds) != 2 {
+		return form, false
+	}
+	return text, inBound(bounds[0], count, true) && inBound(bounds[1], count, false)
+}
+
+// inBound checks count against one end of a range; "*" is unbounded.
+func inBound(bound string, count int, lower bool) bool {
+	bound = strings.TrimSpace(bound)
+	if bound == "*" {
+		return true
+	}
+	n, err := strconv.Atoi(bound)
+	if err != nil {
+		return false
+	}
+	if lower {
+		return count >= n
+	}
+	return count <= n
+}
+
+// PluralIndex returns which plural form a language uses for count, following
+// the CLDR rules for integers. English has two forms (one, other), Russian
+// three, Arabic six, and Japanese only one.
+func PluralIndex(locale string, count int) int {
+	language := locale
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		language = locale[:i]
+	}
+	if count < 0 {
+		count = -count
+	}
+
+	switch language {
+	case "ja", "ko", "zh", "th", "vi", "id", "ms", "tr", "ka", "km", "lo", "my":
+		return 0
+
+	case "fr", "hy", "ff", "kab":
+		if count == 0 || count == 1 {
+			return 0
+		}
+		return 1
+
+	case "ru", "uk", "be", "sr", "hr", "bs":
+		switch {
+		case count%10 == 1 && count%100 != 11:
+			return 0
+		case count%10 >= 2 && count%10 <= 4 && (count%100 < 10 || count%100 >= 20):
+			return 1
+		default:
+			return 2
+		}
+
+	case "cs", "sk":
+		switch {
+		case count == 1:
+			return 0
+		case count >= 2 && count <= 4:
+			return 1
+		default:
+			return 2
+		}
+
+	case "pl":
+		switch {
+		case count == 1:
+			return 0
+		case count%10 >= 2 && count%10 <= 4 && (count%100 < 10 || count%100 >= 20):
+			return 1
+		default:
+			return 2
+		}
+
+	case "lt":
+		switch {
+		case count%10 == 1 && count%100 != 11:
+			return 0
+		case count%10 >= 2 && (count%100 < 10 || count%100 >= 20):
+			return 1
+		default:
+			return 2
+		}
+
+	case "lv":
+		switch {
+		case count == 0:
+			return 0
+		case count%10 == 1 && count%100 != 11:
+			return 1
+		default:
+			return 2
+		}
+
+	case "ro":
+		switch {
+		case count == 1:
+			return 0
+		case count == 0 || (count%100 > 0 && count%100 < 20):
+			return 1
+		default:
+			return 2
+		}
+
+	case "sl":
+		switch count % 100 {
+		case 1:
+			return 0
+		case 2:
+			return 1
+		case 3, 4:
+			return 2
+		default:
+			return 3
+		}
+
+	case "ga":
+		switch {
+		case count == 1:
+			return 0
+		case count == 2:
+			return 1
+		default:
+			return 2
+		}
+
+	case "ar":
+		switch {
+		case count == 0:
+			return 0
+		case count == 1:
+			return 1
+		case count == 2:
+			return 2
+		case count%100 >= 3 && count%100 <= 10:
+			return 3
+		case count%100 >= 11:
+			return 4
+		default:
+			return 5
+		}
+
+	default:
+		if count == 1 {
+			return 0
+		}
+		return 1
+	}
+}
@@ -0,0 +1,323 @@
+// Package lang translates application text from per-locale language files.
+//
+// Files live under a directory such as lang/, either grouped by locale,
+//
+//	lang/en/messages.yaml   welcome: "Welcome, :name!"
+//	lang/de/messages.yaml   welcome: "Willkommen, :name!"
+//
+// where keys are prefixed with the file name ("messages.welcome"), or as a
+// single file per locale (lang/de.json) whose keys are used as they are.
+// JSON and YAML are both supported, and nested objects are flattened into
+// dotted keys.
+package lang
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Translator looks up translated lines.
+type Translator struct {
+	locale   string
+	fallback string
+	lines    map[string]map[string]string // locale -> key -> line
+	mu       sync.RWMutex
+}
+
+// New creates a translator for locale, falling back to fallback for lines
+// the locale doesn't have.
+func New(locale, fallback string) *Translator {
+	if locale == "" {
+		locale = "en"
+	}
+	return &Translator{
+		locale:   locale,
+		fallback: fallback,
+		lines:    make(map[string]map[string]string),
+	}
+}
+
+// Locale returns the default locale.
+func (t *Translator) Locale() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.locale
+}
+
+// SetLocale sets the default locale.
+func (t *Translator) SetLocale(locale string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.locale = locale
+}
+
+// Fallback returns the fallback locale.
+func (t *Translator) Fallback() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.fallback
+}
+
+// SetFallback sets the fallback locale.
+func (t *Translator) SetFallback(locale string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.fallback = locale
+}
+
+// Locales returns the locales that have lines, sorted.
+func (t *Translator) Locales() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	locales := make([]string, 0, len(t.lines))
+	for locale := range t.lines {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// AddLines adds lines for a locale, replacing existing keys.
+func (t *Translator) AddLines(locale string, lines map[string]string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.lines[locale] == nil {
+		t.lines[locale] = make(map[string]string, len(lines))
+	}
+	for key, line := range lines {
+		t.lines[locale][key] = line
+	}
+}
+
+// Load reads the language files in dir. A missing directory has no lines.
+func (t *Translator) Load(dir string) error {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	return t.load(os.DirFS(dir), dir)
+}
+
+// LoadFS reads the language files at the root of fsys, laid out as in a
+// lang directory, such as files embedded in a package.
+func (t *Translator) LoadFS(fsys fs.FS) error {
+	return t.load(fsys, "")
+}
+
+// load reads the language files in fsys; root names it in errors.
+func (t *Translator) load(fsys fs.FS, root string) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return fmt.Errorf("lang: failed to read %s: %w", filepath.Join(root, "."), err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() {
+			if locale, ok := trimLangExt(name); ok {
+				lines, err := readLangFile(fsys, root, name, "")
+				if err != nil {
+					return err
+				}
+				t.AddLines(locale, lines)
+			}
+			continue
+		}
+
+		locale := name
+		err := fs.WalkDir(fsys, name, func(file string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			group, ok := trimLangExt(strings.TrimPrefix(file, name+"/"))
+			if !ok {
+				return nil
+			}
+			lines, err := readLangFile(fsys, root, file, strings.ReplaceAll(group, "/", "."))
+			if err != nil {
+				return err
+			}
+			t.AddLines(locale, lines)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Line returns the raw line for key in locale, or in the fallback locale.
+// Regional locales such as "en-GB" also fall back to their language.
+func (t *Translator) Line(locale, key string) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, candidate := range t.candidates(locale) {
+		if line, ok := t.lines[candidate][key]; ok {
+			return line, true
+		}
+	}
+	return "", false
+}
+
+// Has reports whether a line exists for key in the default locale or the
+// given one.
+func (t *Translator) Has(key string, locale ...string) bool {
+	_, ok := t.Line(t.localeOr(locale), key)
+	return ok
+}
+
+// Trans translates key in the default locale, replacing :placeholders with
+// params. Missing keys are returned as they are.
+func (t *Translator) Trans(key string, params map[string]any) string {
+	return t.TransIn(t.Locale(), key, params)
+}
+
+// TransIn translates key in locale.
+func (t *Translator) TransIn(locale, key string, params map[string]any) string {
+	line, ok := t.Line(locale, key)
+	if !ok {
+		return key
+	}
+	return Replace(line, params)
+}
+
+// Choice translates key in the default locale and picks the plural form
+// for count; see ChoiceIn.
+func (t *Translator) Choice(key string, count int, params map[string]any) string {
+	return t.ChoiceIn(t.Locale(), key, count, params)
+}
+
+// ChoiceIn translates key in locale and picks the plural form for count.
+// Forms are separated by "|" and ordered by the locale's plural rules
+// ("apple|apples"), or select explicit counts and ranges:
+//
+//	{0} No apples|{1} One apple|[2,*] :count apples
+//
+// :count is replaced with count unless params sets it.
+func (t *Translator) ChoiceIn(locale, key string, count int, params map[string]any) string {
+	line, ok := t.Line(locale, key)
+	if !ok {
+		return key
+	}
+
+	withCount := make(map[string]any, len(params)+1)
+	withCount["count"] = count
+	for name, value := range params {
+		withCount[name] = value
+	}
+	return Replace(choose(line, count, locale), withCount)
+}
+
+// candidates lists the locales searched for a line, most specific first.
+func (t *Translator) candidates(locale string) []string {
+	candidates := []string{locale}
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		candidates = append(candidates, locale[:i])
+	}
+	if t.fallback != "" && t.fallback != locale {
+		candidates = append(candidates, t.fallback)
+	}
+	return candidates
+}
+
+func (t *Translator) localeOr(locale []string) string {
+	if len(locale) > 0 && locale[0] != "" {
+		return locale[0]
+	}
+	return t.Locale()
+}
+
+// Replace replaces :name placeholders in line with params. :Name and :NAME
+// insert the value capitalized or upper-cased. Longer names are replaced
+// first so :name doesn't clobber :names.
+func Replace(line string, params map[string]any) string {
+	if len(params) == 0 {
+		return line
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+
+	pairs := make([]string, 0, len(names)*6)
+	for _, name := range names {
+		value := fmt.Sprint(params[name])
+		pairs = append(pairs,
+			":"+strings.ToUpper(name), strings.ToUpper(value),
+			":"+capitalize(name), capitalize(value),
+			":"+name, value,
+		)
+	}
+	return strings.NewReplacer(pairs...).Replace(line)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// trimLangExt strips a language file extension, reporting whether the name
+// had one.
+func trimLangExt(name string) (string, bool) {
+	for _, ext := range []string{".json", ".yaml", ".yml"} {
+		if base, ok := strings.CutSuffix(name, ext); ok {
+			return base, true
+		}
+	}
+	return "", false
+}
+
+// readLangFile reads a JSON or YAML file into flat keys under prefix.
+func readLangFile(fsys fs.FS, root, name, prefix string) (map[string]string, error) {
+	path := filepath.Join(root, filepath.FromSlash(name))
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("lang: failed to read %s: %w", path, err)
+	}
+
+	var values map[string]any
+	if filepath.Ext(path) == ".json" {
+		err = json.Unmarshal(data, &values)
+	} else {
+		err = yaml.Unmarshal(data, &values)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lang: failed to parse %s: %w", path, err)
+	}
+
+	lines := make(map[string]string)
+	flatten(lines, prefix, values)
+	return lines, nil
+}
+
+// flatten writes nested values into lines with dotted keys.
+func flatten(lines map[string]string, prefix string, values map[string]any) {
+	for key, value := range values {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch value := value.(type) {
+		case map[string]any:
+			flatten(lines, key, value)
+		case string:
+			lines[key] = value
+		case nil:
+		case float64:
+			lines[key] = strconv.FormatFloat(value, 'f', -1, 64)
+		default:
+			lines[key] = fmt.Sprint(value)
+		}
+	}
+}
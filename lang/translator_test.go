@@ -0,0 +1,132 @@
+package lang_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/genesysflow/go-genesys/lang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLangFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestTranslatorLoad(t *testing.T) {
+	dir := writeLangFiles(t, map[string]string{
+		"en/messages.yaml":    "welcome: \"Welcome, :name!\"\nnav:\n  home: Home\n",
+		"en/admin/users.json": `{"title": "Users"}`,
+		"de/messages.yml":     "welcome: \"Willkommen, :name!\"\n",
+		"fr.json":             `{"Log in": "Se connecter", "retries": 3}`,
+		"en/README.md":        "not a language file",
+	})
+
+	translator := lang.New("en", "en")
+	require.NoError(t, translator.Load(dir))
+
+	assert.Equal(t, []string{"de", "en", "fr"}, translator.Locales())
+	assert.Equal(t, "Welcome, Jane!", translator.Trans("messages.welcome", map[string]any{"name": "Jane"}))
+	assert.Equal(t, "Home", translator.Trans("messages.nav.home", nil))
+	assert.Equal(t, "Users", translator.Trans("admin.users.title", nil))
+	assert.Equal(t, "Willkommen, Jane!", translator.TransIn("de", "messages.welcome", map[string]any{"name": "Jane"}))
+	assert.Equal(t, "Se connecter", translator.TransIn("fr", "Log in", nil))
+	assert.Equal(t, "3", translator.TransIn("fr", "retries", nil))
+
+	// Missing lines fall back to the fallback locale, then to the key.
+	assert.Equal(t, "Home", translator.TransIn("de", "messages.nav.home", nil))
+	assert.Equal(t, "Willkommen, Jane!", translator.TransIn("de-AT", "messages.welcome", map[string]any{"name": "Jane"}))
+	assert.Equal(t, "messages.missing", translator.Trans("messages.missing", nil))
+	assert.True(t, translator.Has("messages.welcome", "de"))
+	assert.False(t, translator.Has("Log in"))
+
+	assert.NoError(t, lang.New("en", "").Load(filepath.Join(dir, "missing")))
+}
+
+func TestTranslatorLoadInvalidFile(t *testing.T) {
+	dir := writeLangFiles(t, map[string]string{"en/broken.json": "{"})
+	assert.ErrorContains(t, lang.New("en", "").Load(dir), "failed to parse")
+}
+
+func TestTranslatorLoadFS(t *testing.T) {
+	translator := lang.New("en", "en")
+	require.NoError(t, translator.LoadFS(fstest.MapFS{
+		"en/validation.yaml": {Data: []byte("required: \":attribute is required\"\nattributes:\n  email: email address\n")},
+		"de.json":            {Data: []byte(`{"Log in": "Anmelden"}`)},
+	}))
+
+	assert.Equal(t, "email address", translator.Trans("validation.attributes.email", nil))
+	assert.Equal(t, "Anmelden", translator.TransIn("de", "Log in", nil))
+}
+
+func TestReplace(t *testing.T) {
+	params := map[string]any{"name": "jane", "names": "jane and joe", "count": 2}
+	assert.Equal(t, "Hi jane, Jane, JANE", lang.Replace("Hi :name, :Name, :NAME", params))
+	assert.Equal(t, "jane and joe (2)", lang.Replace(":names (:count)", params))
+	assert.Equal(t, "Hi :name", lang.Replace("Hi :name", nil))
+}
+
+func TestTranslatorChoice(t *testing.T) {
+	translator := lang.New("en", "")
+	translator.AddLines("en", map[string]string{
+		"apples":   "apple|apples",
+		"comments": "{0} No comments|{1} One comment|[2,19] :count comments|[20,*] Lots of comments",
+		"minutes":  ":count minute ago|:count minutes ago",
+	})
+	translator.AddLines("ru", map[string]string{
+		"apples": ":count яблоко|:count яблока|:count яблок",
+	})
+	translator.AddLines("fr", map[string]string{
+		"apples": ":count pomme|:count pommes",
+	})
+
+	assert.Equal(t, "apple", translator.Choice("apples", 1, nil))
+	assert.Equal(t, "apples", translator.Choice("apples", 0, nil))
+	assert.Equal(t, "No comments", translator.Choice("comments", 0, nil))
+	assert.Equal(t, "One comment", translator.Choice("comments", 1, nil))
+	assert.Equal(t, "7 comments", translator.Choice("comments", 7, nil))
+	assert.Equal(t, "Lots of comments", translator.Choice("comments", 250, nil))
+	assert.Equal(t, "5 minutes ago", translator.Choice("minutes", 5, nil))
+	assert.Equal(t, "a few minutes ago", translator.Choice("minutes", 5, map[string]any{"count": "a few"}))
+
+	assert.Equal(t, "21 яблоко", translator.ChoiceIn("ru", "apples", 21, nil))
+	assert.Equal(t, "3 яблока", translator.ChoiceIn("ru", "apples", 3, nil))
+	assert.Equal(t, "11 яблок", translator.ChoiceIn("ru", "apples", 11, nil))
+	assert.Equal(t, "0 pomme", translator.ChoiceIn("fr", "apples", 0, nil))
+	assert.Equal(t, "missing", translator.Choice("missing", 1, nil))
+}
+
+func TestPluralIndex(t *testing.T) {
+	tests := []struct {
+		locale string
+		count  int
+		want   int
+	}{
+		{"en", 1, 0},
+		{"en_US", 2, 1},
+		{"de", 0, 1},
+		{"fr", 1, 0},
+		{"ja", 5, 0},
+		{"pl", 1, 0},
+		{"pl", 22, 1},
+		{"pl", 12, 2},
+		{"cs", 3, 1},
+		{"cs", 5, 2},
+		{"ar", 0, 0},
+		{"ar", 2, 2},
+		{"ar", 105, 3},
+		{"ar", 111, 4},
+		{"ar", 100, 5},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, lang.PluralIndex(tt.locale, tt.count), "%s %d", tt.locale, tt.count)
+	}
+}
@@ -0,0 +1,77 @@
+package log
+
+import (
+	"context"
+	"sync"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// requestIDKey is the context.Context key request IDs are stored under.
+type requestIDKey struct{}
+
+// loggerKey is the context.Context key loggers are stored under.
+type loggerKey struct{}
+
+var (
+	defaultLogger contracts.Logger = New()
+	defaultMu     sync.RWMutex
+)
+
+// WithRequestID returns a copy of ctx carrying a request ID. Loggers given
+// the context through WithContext add it to every entry as "request_id".
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "".
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return id
+	}
+	// Contexts built by hand with the plain "request_id" key.
+	if id, ok := ctx.Value("request_id").(string); ok {
+		return id
+	}
+	return ""
+}
+
+// WithLogger returns a copy of ctx carrying logger, so code given the
+// context logs with its fields through FromContext.
+func WithLogger(ctx context.Context, logger contracts.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx. Without one, it returns
+// the default logger with ctx attached, so a request ID the context
+// carries is still logged.
+func FromContext(ctx context.Context) contracts.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(contracts.Logger); ok {
+			return logger
+		}
+	}
+	logger := Default()
+	if ctx == nil {
+		return logger
+	}
+	return logger.WithContext(ctx)
+}
+
+// SetDefault sets the logger FromContext falls back to. The log service
+// provider sets it to the default channel.
+func SetDefault(logger contracts.Logger) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultLogger = logger
+}
+
+// Default returns the logger FromContext falls back to.
+func Default() contracts.Logger {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultLogger
+}
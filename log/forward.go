@@ -0,0 +1,152 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// Entry is a log entry forwarded to a Handler.
+type Entry struct {
+	Level   string
+	Message string
+	Time    time.Time
+
+	// Fields holds the entry's other fields, such as "error" and
+	// "request_id".
+	Fields map[string]any
+}
+
+// Handler sends log entries to an external service.
+type Handler interface {
+	// Handle sends an entry. It is called from the forwarder's goroutine.
+	Handle(ctx context.Context, entry Entry) error
+}
+
+// HandlerFunc adapts a function to Handler.
+type HandlerFunc func(ctx context.Context, entry Entry) error
+
+// Handle calls f.
+func (f HandlerFunc) Handle(ctx context.Context, entry Entry) error {
+	return f(ctx, entry)
+}
+
+// DefaultForwardBuffer is the number of entries a Forwarder queues.
+const DefaultForwardBuffer = 100
+
+// forwardTimeout bounds how long a handler may take to send an entry.
+const forwardTimeout = 10 * time.Second
+
+// Forwarder is an io.Writer for loggers that hands the entries written to
+// it to a Handler on a goroutine of its own, so logging never waits on the
+// network. Entries written while its queue is full are dropped and
+// counted, and send failures are reported on stderr.
+type Forwarder struct {
+	handler Handler
+	queue   chan Entry
+	dropped atomic.Int64
+	done    chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewForwarder starts a forwarder queueing up to buffer entries;
+// DefaultForwardBuffer when buffer isn't positive.
+func NewForwarder(handler Handler, buffer int) *Forwarder {
+	if buffer <= 0 {
+		buffer = DefaultForwardBuffer
+	}
+	f := &Forwarder{
+		handler: handler,
+		queue:   make(chan Entry, buffer),
+		done:    make(chan struct{}),
+	}
+	go f.run()
+	return f
+}
+
+// Write queues the JSON entry in p.
+func (f *Forwarder) Write(p []byte) (int, error) {
+	entry, err := decodeEntry(p)
+	if err != nil {
+		return 0, err
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.closed {
+		f.dropped.Add(1)
+		return len(p), nil
+	}
+	select {
+	case f.queue <- entry:
+	default:
+		f.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Dropped returns the number of entries dropped because the queue was
+// full or the forwarder closed.
+func (f *Forwarder) Dropped() int64 {
+	return f.dropped.Load()
+}
+
+// Close stops accepting entries and waits for the queued ones to be sent.
+func (f *Forwarder) Close() error {
+	f.mu.Lock()
+	if !f.closed {
+		f.closed = true
+		close(f.queue)
+	}
+	f.mu.Unlock()
+	<-f.done
+	return nil
+}
+
+func (f *Forwarder) run() {
+	defer close(f.done)
+	for entry := range f.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), forwardTimeout)
+		if err := f.handler.Handle(ctx, entry); err != nil {
+			fmt.Fprintf(os.Stderr, "log: failed to forward entry %q: %v\n", entry.Message, err)
+		}
+		cancel()
+	}
+}
+
+// decodeEntry decodes an entry written by a JSON logger.
+func decodeEntry(p []byte) (Entry, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(p, &fields); err != nil {
+		return Entry{}, fmt.Errorf("log: forwarded entries must be JSON: %w", err)
+	}
+
+	entry := Entry{Fields: fields}
+	entry.Level, _ = fields["level"].(string)
+	entry.Message, _ = fields["message"].(string)
+	if value, ok := fields["time"].(string); ok {
+		entry.Time, _ = time.Parse(time.RFC3339, value)
+	}
+	delete(fields, "level")
+	delete(fields, "message")
+	delete(fields, "time")
+	return entry, nil
+}
+
+// NewForwardLogger creates a Logger that forwards its entries to handler
+// through a Forwarder, at error level and above. Close the forwarder on
+// shutdown to send the entries still queued.
+func NewForwardLogger(handler Handler, buffer int) (*Logger, *Forwarder) {
+	forwarder := NewForwarder(handler, buffer)
+	logger := NewJSON(forwarder)
+	logger.SetLevel(contracts.LogLevelError)
+	return logger, forwarder
+}
@@ -0,0 +1,120 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForwardLoggerSendsErrors(t *testing.T) {
+	var mu sync.Mutex
+	var entries []Entry
+	logger, forwarder := NewForwardLogger(HandlerFunc(func(ctx context.Context, entry Entry) error {
+		mu.Lock()
+		defer mu.Unlock()
+		entries = append(entries, entry)
+		return nil
+	}), 0)
+
+	logger.Info("skipped")
+	logger.WithField("order", 7).Error("payment failed", "gateway", "stripe")
+	require.NoError(t, forwarder.Close())
+
+	require.Len(t, entries, 1)
+	assert.Equal(t, "error", entries[0].Level)
+	assert.Equal(t, "payment failed", entries[0].Message)
+	assert.False(t, entries[0].Time.IsZero())
+	assert.Equal(t, map[string]any{"order": float64(7), "gateway": "stripe"}, entries[0].Fields)
+}
+
+func TestForwarderDropsWhenFull(t *testing.T) {
+	release := make(chan struct{})
+	logger, forwarder := NewForwardLogger(HandlerFunc(func(ctx context.Context, entry Entry) error {
+		<-release
+		return nil
+	}), 1)
+
+	// The first entry is taken by the handler, the second fills the
+	// queue, and the rest are dropped without blocking.
+	for i := 0; i < 5; i++ {
+		logger.Error("failed")
+	}
+	assert.GreaterOrEqual(t, forwarder.Dropped(), int64(3))
+
+	close(release)
+	require.NoError(t, forwarder.Close())
+	logger.Error("after close")
+	assert.GreaterOrEqual(t, forwarder.Dropped(), int64(4))
+}
+
+func TestManagerForwardDrivers(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]map[string]any)
+	headers := make(map[string]http.Header)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		requests[r.URL.Path] = body
+		headers[r.URL.Path] = r.Header
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	dsn := "http://public@" + server.Listener.Addr().String() + "/42"
+	manager := NewManager(Config{
+		Channels: map[string]ChannelConfig{
+			"slack":   {Driver: "slack", URL: server.URL + "/slack", Options: map[string]any{"username": "bot"}},
+			"webhook": {Driver: "webhook", URL: server.URL + "/hook", Options: map[string]any{"headers": map[string]any{"Authorization": "Bearer secret"}}},
+			"sentry":  {Driver: "sentry", DSN: dsn, Options: map[string]any{"environment": "production"}},
+			"alerts":  {Driver: "stack", Channels: []string{"slack", "webhook", "sentry"}},
+		},
+	})
+
+	manager.Channel("alerts").Warn("ignored")
+	manager.Channel("alerts").Error("disk full", "disk", "/var")
+	require.NoError(t, manager.Close())
+
+	require.Len(t, requests, 3)
+	assert.Equal(t, "*ERROR*: disk full", requests["/slack"]["text"])
+	assert.Equal(t, "bot", requests["/slack"]["username"])
+
+	assert.Equal(t, "disk full", requests["/hook"]["message"])
+	assert.Equal(t, "/var", requests["/hook"]["disk"])
+	assert.Equal(t, "Bearer secret", headers["/hook"].Get("Authorization"))
+
+	event := requests["/api/42/store/"]
+	require.NotNil(t, event)
+	assert.Equal(t, "disk full", event["message"])
+	assert.Equal(t, "error", event["level"])
+	assert.Equal(t, "production", event["environment"])
+	assert.Equal(t, map[string]any{"disk": "/var"}, event["extra"])
+	assert.Contains(t, headers["/api/42/store/"].Get("X-Sentry-Auth"), "sentry_key=public")
+}
+
+func TestManagerForwardDriverErrors(t *testing.T) {
+	manager := NewManager(Config{
+		Channels: map[string]ChannelConfig{
+			"slack":  {Driver: "slack"},
+			"sentry": {Driver: "sentry", DSN: "not-a-dsn"},
+		},
+	})
+
+	_, err := manager.Logger("slack")
+	assert.ErrorContains(t, err, "requires a url")
+	_, err = manager.Logger("sentry")
+	assert.ErrorContains(t, err, "invalid sentry dsn")
+}
+
+func TestParseSentryDSN(t *testing.T) {
+	endpoint, key, err := parseSentryDSN("https://abc@o1.ingest.sentry.io/prefix/123")
+	require.NoError(t, err)
+	assert.Equal(t, "https://o1.ingest.sentry.io/prefix/api/123/store/", endpoint)
+	assert.Equal(t, "abc", key)
+}
@@ -0,0 +1,218 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+var defaultHTTPClient = &http.Client{Timeout: 5 * time.Second}
+
+func httpClient(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+	return defaultHTTPClient
+}
+
+// postJSON sends body as JSON to target.
+func postJSON(ctx context.Context, client *http.Client, target string, body any, headers map[string]string) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := httpClient(client).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("log: %s returned %d: %s", req.URL.Host, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// SlackHandler posts entries to a Slack incoming webhook, with their
+// fields as attachment fields.
+type SlackHandler struct {
+	WebhookURL string
+
+	// Username and Channel override the webhook's defaults.
+	Username string
+	Channel  string
+
+	// Client sends the requests. Nil uses a client with a 5s timeout.
+	Client *http.Client
+}
+
+// Handle posts an entry.
+func (h *SlackHandler) Handle(ctx context.Context, entry Entry) error {
+	fields := make([]map[string]any, 0, len(entry.Fields))
+	for _, key := range sortedKeys(entry.Fields) {
+		fields = append(fields, map[string]any{
+			"title": key,
+			"value": fmt.Sprint(entry.Fields[key]),
+			"short": true,
+		})
+	}
+
+	message := map[string]any{
+		"text": fmt.Sprintf("*%s*: %s", strings.ToUpper(entry.Level), entry.Message),
+		"attachments": []map[string]any{{
+			"color":  slackColor(entry.Level),
+			"fields": fields,
+			"ts":     entry.Time.Unix(),
+		}},
+	}
+	if h.Username != "" {
+		message["username"] = h.Username
+	}
+	if h.Channel != "" {
+		message["channel"] = h.Channel
+	}
+	return postJSON(ctx, h.Client, h.WebhookURL, message, nil)
+}
+
+func slackColor(level string) string {
+	switch level {
+	case "warn":
+		return "warning"
+	case "debug", "info":
+		return "good"
+	default:
+		return "danger"
+	}
+}
+
+// WebhookHandler posts entries as JSON to a URL: their fields along with
+// "level", "message" and "time".
+type WebhookHandler struct {
+	URL string
+
+	// Headers are added to each request, such as an Authorization header.
+	Headers map[string]string
+
+	// Client sends the requests. Nil uses a client with a 5s timeout.
+	Client *http.Client
+}
+
+// Handle posts an entry.
+func (h *WebhookHandler) Handle(ctx context.Context, entry Entry) error {
+	body := make(map[string]any, len(entry.Fields)+3)
+	for key, value := range entry.Fields {
+		body[key] = value
+	}
+	body["level"] = entry.Level
+	body["message"] = entry.Message
+	body["time"] = entry.Time.Format(time.RFC3339)
+	return postJSON(ctx, h.Client, h.URL, body, h.Headers)
+}
+
+// SentryHandler sends entries to Sentry as events, with their fields as
+// extra data.
+type SentryHandler struct {
+	// DSN is the project's client key URL:
+	// https://<key>@<host>/<project>.
+	DSN string
+
+	Environment string
+	Release     string
+
+	// Client sends the requests. Nil uses a client with a 5s timeout.
+	Client *http.Client
+}
+
+// Handle sends an entry.
+func (h *SentryHandler) Handle(ctx context.Context, entry Entry) error {
+	endpoint, key, err := parseSentryDSN(h.DSN)
+	if err != nil {
+		return err
+	}
+
+	event := map[string]any{
+		"event_id":  newEventID(),
+		"timestamp": entry.Time.UTC().Format(time.RFC3339),
+		"level":     sentryLevel(entry.Level),
+		"logger":    "genesys",
+		"platform":  "go",
+		"message":   entry.Message,
+		"extra":     entry.Fields,
+	}
+	if h.Environment != "" {
+		event["environment"] = h.Environment
+	}
+	if h.Release != "" {
+		event["release"] = h.Release
+	}
+
+	auth := fmt.Sprintf("Sentry sentry_version=7, sentry_client=go-genesys/1.0, sentry_key=%s", key)
+	return postJSON(ctx, h.Client, endpoint, event, map[string]string{"X-Sentry-Auth": auth})
+}
+
+// parseSentryDSN returns the store endpoint and public key of a DSN.
+func parseSentryDSN(dsn string) (string, string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", fmt.Errorf("log: invalid sentry dsn: %w", err)
+	}
+	project := path.Base(u.Path)
+	if u.User == nil || u.User.Username() == "" || u.Host == "" || project == "." || project == "/" {
+		return "", "", fmt.Errorf("log: invalid sentry dsn: %q", dsn)
+	}
+
+	endpoint := url.URL{
+		Scheme: u.Scheme,
+		Host:   u.Host,
+		Path:   path.Join(path.Dir(u.Path), "api", project, "store") + "/",
+	}
+	return endpoint.String(), u.User.Username(), nil
+}
+
+func sentryLevel(level string) string {
+	switch level {
+	case "warn":
+		return "warning"
+	case "panic":
+		return "fatal"
+	case "":
+		return "error"
+	default:
+		return level
+	}
+}
+
+func newEventID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+func sortedKeys(fields map[string]any) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -6,6 +6,8 @@ import (
 	"context"
 	"io"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/genesysflow/go-genesys/contracts"
@@ -15,11 +17,18 @@ import (
 // Logger is the default logger implementation using zerolog.
 type Logger struct {
 	logger zerolog.Logger
-	level  contracts.LogLevel
+	level  *atomic.Int32 // minimum contracts.LogLevel, or -1 for none; shared with derived loggers
 	fields map[string]any
 	ctx    context.Context
 }
 
+// newLevel returns a level that lets every message through until set.
+func newLevel() *atomic.Int32 {
+	level := new(atomic.Int32)
+	level.Store(-1)
+	return level
+}
+
 // New creates a new Logger instance.
 func New(writers ...io.Writer) *Logger {
 	var writer io.Writer
@@ -35,7 +44,7 @@ func New(writers ...io.Writer) *Logger {
 
 	return &Logger{
 		logger: zerolog.New(writer).With().Timestamp().Logger(),
-		level:  contracts.LogLevelInfo,
+		level:  newLevel(),
 		fields: make(map[string]any),
 	}
 }
@@ -51,7 +60,7 @@ func NewJSON(writers ...io.Writer) *Logger {
 
 	return &Logger{
 		logger: zerolog.New(writer).With().Timestamp().Logger(),
-		level:  contracts.LogLevelInfo,
+		level:  newLevel(),
 		fields: make(map[string]any),
 	}
 }
@@ -97,6 +106,9 @@ func (l *Logger) Panic(msg string, fields ...any) {
 
 // log is the internal logging method.
 func (l *Logger) log(level zerolog.Level, msg string, fields ...any) {
+	if min := l.level.Load(); min >= 0 && level < toZerologLevel(contracts.LogLevel(min)) {
+		return
+	}
 	event := l.logger.WithLevel(level)
 
 	// Add stored fields
@@ -105,10 +117,8 @@ func (l *Logger) log(level zerolog.Level, msg string, fields ...any) {
 	}
 
 	// Add context values if present
-	if l.ctx != nil {
-		if reqID := l.ctx.Value("request_id"); reqID != nil {
-			event = event.Interface("request_id", reqID)
-		}
+	if reqID := RequestID(l.ctx); reqID != "" {
+		event = event.Str("request_id", reqID)
 	}
 
 	// Add inline fields (key-value pairs)
@@ -172,13 +182,16 @@ func (l *Logger) WithError(err error) contracts.Logger {
 
 // Level returns the current log level.
 func (l *Logger) Level() contracts.LogLevel {
-	return l.level
+	if level := l.level.Load(); level >= 0 {
+		return contracts.LogLevel(level)
+	}
+	return contracts.LogLevelInfo
 }
 
-// SetLevel sets the log level.
+// SetLevel sets the log level. It is safe to call while logging, and
+// applies to the loggers derived from this one with WithField and the like.
 func (l *Logger) SetLevel(level contracts.LogLevel) {
-	l.level = level
-	l.logger = l.logger.Level(toZerologLevel(level))
+	l.level.Store(int32(level))
 }
 
 // SetOutput sets the output writer.
@@ -186,6 +199,25 @@ func (l *Logger) SetOutput(w io.Writer) {
 	l.logger = l.logger.Output(w)
 }
 
+// ParseLevel parses a level name: debug, info, warn (or warning), error,
+// fatal or panic. Other names are info.
+func ParseLevel(level string) contracts.LogLevel {
+	switch strings.ToLower(level) {
+	case "debug":
+		return contracts.LogLevelDebug
+	case "warn", "warning":
+		return contracts.LogLevelWarn
+	case "error":
+		return contracts.LogLevelError
+	case "fatal":
+		return contracts.LogLevelFatal
+	case "panic":
+		return contracts.LogLevelPanic
+	default:
+		return contracts.LogLevelInfo
+	}
+}
+
 // toZerologLevel converts a contracts.LogLevel to zerolog.Level.
 func toZerologLevel(level contracts.LogLevel) zerolog.Level {
 	switch level {
@@ -205,112 +237,3 @@ func toZerologLevel(level contracts.LogLevel) zerolog.Level {
 		return zerolog.InfoLevel
 	}
 }
-
-// LogManager manages multiple log channels.
-type LogManager struct {
-	channels map[string]contracts.Logger
-	default_ string
-}
-
-// NewManager creates a new LogManager.
-func NewManager() *LogManager {
-	m := &LogManager{
-		channels: make(map[string]contracts.Logger),
-		default_: "default",
-	}
-
-	// Register default channel
-	m.channels["default"] = New()
-
-	return m
-}
-
-// Channel returns a specific log channel.
-func (m *LogManager) Channel(name string) contracts.Logger {
-	if ch, ok := m.channels[name]; ok {
-		return ch
-	}
-	return m.channels[m.default_]
-}
-
-// Stack creates a logger that writes to multiple channels.
-func (m *LogManager) Stack(channels ...string) contracts.Logger {
-	// For now, return the first channel. A proper implementation would
-	// create a multi-writer logger.
-	if len(channels) > 0 {
-		return m.Channel(channels[0])
-	}
-	return m.channels[m.default_]
-}
-
-// AddChannel adds a channel to the manager.
-func (m *LogManager) AddChannel(name string, logger contracts.Logger) {
-	m.channels[name] = logger
-}
-
-// SetDefault sets the default channel.
-func (m *LogManager) SetDefault(name string) {
-	if _, ok := m.channels[name]; ok {
-		m.default_ = name
-	}
-}
-
-// Debug logs a debug message to the default channel.
-func (m *LogManager) Debug(msg string, fields ...any) {
-	m.channels[m.default_].Debug(msg, fields...)
-}
-
-// Info logs an info message to the default channel.
-func (m *LogManager) Info(msg string, fields ...any) {
-	m.channels[m.default_].Info(msg, fields...)
-}
-
-// Warn logs a warning message to the default channel.
-func (m *LogManager) Warn(msg string, fields ...any) {
-	m.channels[m.default_].Warn(msg, fields...)
-}
-
-// Error logs an error message to the default channel.
-func (m *LogManager) Error(msg string, fields ...any) {
-	m.channels[m.default_].Error(msg, fields...)
-}
-
-// Fatal logs a fatal message to the default channel.
-func (m *LogManager) Fatal(msg string, fields ...any) {
-	m.channels[m.default_].Fatal(msg, fields...)
-}
-
-// Panic logs a panic message to the default channel.
-func (m *LogManager) Panic(msg string, fields ...any) {
-	m.channels[m.default_].Panic(msg, fields...)
-}
-
-// WithField returns a logger with a field attached.
-func (m *LogManager) WithField(key string, value any) contracts.Logger {
-	return m.channels[m.default_].WithField(key, value)
-}
-
-// WithFields returns a logger with multiple fields attached.
-func (m *LogManager) WithFields(fields map[string]any) contracts.Logger {
-	return m.channels[m.default_].WithFields(fields)
-}
-
-// WithContext returns a logger with context attached.
-func (m *LogManager) WithContext(ctx context.Context) contracts.Logger {
-	return m.channels[m.default_].WithContext(ctx)
-}
-
-// WithError returns a logger with an error attached.
-func (m *LogManager) WithError(err error) contracts.Logger {
-	return m.channels[m.default_].WithError(err)
-}
-
-// Level returns the current log level.
-func (m *LogManager) Level() contracts.LogLevel {
-	return m.channels[m.default_].Level()
-}
-
-// SetLevel sets the log level.
-func (m *LogManager) SetLevel(level contracts.LogLevel) {
-	m.channels[m.default_].SetLevel(level)
-}
@@ -181,6 +181,22 @@ func TestWithContext(t *testing.T) {
 	assert.Equal(t, "req-123", result["request_id"])
 }
 
+func TestWithRequestID(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewJSON(buf)
+
+	ctx := WithRequestID(context.Background(), "req-456")
+	assert.Equal(t, "req-456", RequestID(ctx))
+	assert.Equal(t, "", RequestID(context.Background()))
+	assert.Equal(t, "", RequestID(nil))
+
+	logger.WithContext(ctx).Info("with request id")
+
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Equal(t, "req-456", result["request_id"])
+}
+
 func TestWithError(t *testing.T) {
 	buf := &bytes.Buffer{}
 	logger := NewJSON(buf)
@@ -273,3 +289,34 @@ func TestAllLogLevels(t *testing.T) {
 		})
 	}
 }
+
+func TestSetLevelAppliesToDerivedLoggers(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewJSON(buf)
+	child := logger.WithField("request", "abc")
+
+	logger.SetLevel(contracts.LogLevelError)
+	child.Info("hidden")
+	assert.Empty(t, buf.String())
+
+	logger.SetLevel(contracts.LogLevelDebug)
+	child.Debug("shown")
+	assert.Contains(t, buf.String(), "shown")
+}
+
+func TestFromContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	previous := Default()
+	SetDefault(NewJSON(buf))
+	defer SetDefault(previous)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	FromContext(ctx).Info("from default")
+	assert.Contains(t, buf.String(), `"request_id":"req-1"`)
+
+	buf.Reset()
+	ctx = WithLogger(ctx, NewJSON(buf).WithField("user_id", 7))
+	FromContext(ctx).Info("from context")
+	assert.Contains(t, buf.String(), `"user_id":7`)
+	assert.Contains(t, buf.String(), "from context")
+}
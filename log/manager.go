@@ -0,0 +1,390 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// ChannelConfig configures a log channel.
+type ChannelConfig struct {
+	// Driver is the channel's driver: console (pretty stdout), stdout or
+	// json (JSON on stdout), stderr (JSON on stderr), file, syslog, stack,
+	// slack, webhook, sentry, or one added with Extend. Empty uses the
+	// channel's name.
+	Driver string `yaml:"driver" json:"driver"`
+
+	// Level is the channel's minimum level. Empty lets everything through,
+	// except on the slack, webhook and sentry drivers, which default to
+	// error.
+	Level string `yaml:"level" json:"level"`
+
+	// Path is the file the file driver appends JSON lines to.
+	Path string `yaml:"path" json:"path"`
+
+	// RotateOptions configure the rotation and retention of the file
+	// driver's file. Without any, the file grows without bound.
+	RotateOptions `yaml:",inline" json:",inline"`
+
+	// Channels are the channels a stack writes every entry to.
+	Channels []string `yaml:"channels" json:"channels"`
+
+	// Network, Address and Tag configure the syslog driver. An empty
+	// network and address use the local syslog daemon; an empty tag uses
+	// the program name.
+	Network string `yaml:"network" json:"network"`
+	Address string `yaml:"address" json:"address"`
+	Tag     string `yaml:"tag" json:"tag"`
+
+	// URL is the slack driver's incoming webhook or the webhook driver's
+	// endpoint, and DSN the sentry driver's project DSN.
+	URL string `yaml:"url" json:"url"`
+	DSN string `yaml:"dsn" json:"dsn"`
+
+	// Buffer is the number of entries the slack, webhook and sentry drivers
+	// queue while sending. Entries past it are dropped rather than block.
+	// Zero is 100.
+	Buffer int `yaml:"buffer" json:"buffer"`
+
+	// Options holds the settings of drivers added with Extend, and the
+	// slack driver's "username" and "channel", the webhook driver's
+	// "headers", and the sentry driver's "environment" and "release".
+	Options map[string]any `yaml:"options" json:"options"`
+}
+
+// Config configures a LogManager.
+type Config struct {
+	// Default is the channel the manager logs to itself.
+	Default string `yaml:"default" json:"default"`
+
+	// Channels configures the channels by name.
+	Channels map[string]ChannelConfig `yaml:"channels" json:"channels"`
+}
+
+// Driver creates the logger of a channel.
+type Driver func(name string, config ChannelConfig) (contracts.Logger, error)
+
+// LogManager manages named log channels, created from their config on
+// first use. It logs to its default channel itself.
+type LogManager struct {
+	config   Config
+	drivers  map[string]Driver
+	channels map[string]contracts.Logger
+	closers  []io.Closer
+	mu       sync.RWMutex
+}
+
+// NewManager creates a LogManager. Without a config, its default channel
+// is the console.
+func NewManager(config ...Config) *LogManager {
+	var cfg Config
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Default == "" {
+		cfg.Default = "console"
+	}
+
+	return &LogManager{
+		config:   cfg,
+		drivers:  make(map[string]Driver),
+		channels: make(map[string]contracts.Logger),
+	}
+}
+
+// Extend adds a driver channels can be configured with.
+func (m *LogManager) Extend(driver string, create Driver) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.drivers[driver] = create
+}
+
+// Logger returns a channel, creating it on first use.
+func (m *LogManager) Logger(name string) (contracts.Logger, error) {
+	return m.resolve(name, nil)
+}
+
+// Channel returns a channel. A channel that isn't configured or can't be
+// created falls back to the default channel, which logs why.
+func (m *LogManager) Channel(name string) contracts.Logger {
+	logger, err := m.Logger(name)
+	if err == nil {
+		return logger
+	}
+	fallback := m.defaultChannel()
+	fallback.Error("Failed to create log channel", "channel", name, "error", err.Error())
+	return fallback
+}
+
+// Stack returns a logger that writes every entry to each of the channels.
+func (m *LogManager) Stack(channels ...string) contracts.Logger {
+	loggers := make([]contracts.Logger, 0, len(channels))
+	for _, name := range channels {
+		loggers = append(loggers, m.Channel(name))
+	}
+	return NewStack(loggers...)
+}
+
+// AddChannel adds a channel to the manager, replacing any channel of the
+// same name.
+func (m *LogManager) AddChannel(name string, logger contracts.Logger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.channels[name] = logger
+}
+
+// SetDefault sets the default channel, if it exists or is configured.
+func (m *LogManager) SetDefault(name string) {
+	if _, err := m.Logger(name); err != nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Default = name
+}
+
+// DefaultChannel returns the name of the default channel.
+func (m *LogManager) DefaultChannel() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.config.Default
+}
+
+// defaultChannel returns the default channel, or a console logger when it
+// can't be created.
+func (m *LogManager) defaultChannel() contracts.Logger {
+	logger, err := m.Logger(m.DefaultChannel())
+	if err != nil {
+		return New()
+	}
+	return logger
+}
+
+// resolve returns a channel, creating it from its config. chain holds the
+// stacks being created, to catch stacks that contain themselves.
+func (m *LogManager) resolve(name string, chain []string) (contracts.Logger, error) {
+	m.mu.RLock()
+	logger, ok := m.channels[name]
+	config, configured := m.config.Channels[name]
+	m.mu.RUnlock()
+	if ok {
+		return logger, nil
+	}
+
+	for _, building := range chain {
+		if building == name {
+			return nil, fmt.Errorf("log channel [%s] contains itself: %s", name, strings.Join(append(chain, name), " -> "))
+		}
+	}
+
+	if config.Driver == "" {
+		config.Driver = name
+	}
+	if !configured && !m.isDriver(config.Driver) {
+		return nil, fmt.Errorf("log channel [%s] not configured", name)
+	}
+
+	logger, err := m.create(name, config, append(chain, name))
+	if err != nil {
+		return nil, fmt.Errorf("log channel [%s]: %w", name, err)
+	}
+	if config.Level != "" {
+		logger.SetLevel(ParseLevel(config.Level))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Another goroutine may have created it meanwhile; keep the first.
+	if existing, ok := m.channels[name]; ok {
+		return existing, nil
+	}
+	m.channels[name] = logger
+	return logger, nil
+}
+
+// create creates the logger of a channel with its driver.
+func (m *LogManager) create(name string, config ChannelConfig, chain []string) (contracts.Logger, error) {
+	m.mu.RLock()
+	custom, ok := m.drivers[config.Driver]
+	m.mu.RUnlock()
+	if ok {
+		return custom(name, config)
+	}
+
+	switch config.Driver {
+	case "console":
+		return New(), nil
+	case "stdout", "json":
+		return NewJSON(os.Stdout), nil
+	case "stderr":
+		return NewJSON(os.Stderr), nil
+	case "file":
+		if config.Path == "" {
+			return nil, fmt.Errorf("file driver requires a path")
+		}
+		if config.RotateOptions.enabled() {
+			return NewRotatingFileLogger(config.Path, config.RotateOptions)
+		}
+		if err := os.MkdirAll(filepath.Dir(config.Path), 0755); err != nil {
+			return nil, err
+		}
+		return NewFile(config.Path)
+	case "syslog":
+		return NewSyslog(config.Network, config.Address, config.Tag)
+	case "stack":
+		if len(config.Channels) == 0 {
+			return nil, fmt.Errorf("stack driver requires channels")
+		}
+		loggers := make([]contracts.Logger, 0, len(config.Channels))
+		for _, channel := range config.Channels {
+			logger, err := m.resolve(channel, chain)
+			if err != nil {
+				return nil, err
+			}
+			loggers = append(loggers, logger)
+		}
+		return NewStack(loggers...), nil
+	case "slack":
+		if config.URL == "" {
+			return nil, fmt.Errorf("slack driver requires a url")
+		}
+		return m.forward(&SlackHandler{
+			WebhookURL: config.URL,
+			Username:   optionString(config.Options, "username"),
+			Channel:    optionString(config.Options, "channel"),
+		}, config.Buffer), nil
+	case "webhook":
+		if config.URL == "" {
+			return nil, fmt.Errorf("webhook driver requires a url")
+		}
+		headers := make(map[string]string)
+		if values, ok := config.Options["headers"].(map[string]any); ok {
+			for key, value := range values {
+				headers[key] = fmt.Sprint(value)
+			}
+		}
+		return m.forward(&WebhookHandler{URL: config.URL, Headers: headers}, config.Buffer), nil
+	case "sentry":
+		if _, _, err := parseSentryDSN(config.DSN); err != nil {
+			return nil, err
+		}
+		return m.forward(&SentryHandler{
+			DSN:         config.DSN,
+			Environment: optionString(config.Options, "environment"),
+			Release:     optionString(config.Options, "release"),
+		}, config.Buffer), nil
+	default:
+		return nil, fmt.Errorf("unsupported log driver: %s", config.Driver)
+	}
+}
+
+// forward creates a logger forwarding to handler, closed by Close.
+func (m *LogManager) forward(handler Handler, buffer int) contracts.Logger {
+	logger, forwarder := NewForwardLogger(handler, buffer)
+	m.mu.Lock()
+	m.closers = append(m.closers, forwarder)
+	m.mu.Unlock()
+	return logger
+}
+
+// Close sends the entries the slack, webhook and sentry channels still
+// have queued and stops them. Call it on shutdown.
+func (m *LogManager) Close() error {
+	m.mu.Lock()
+	closers := m.closers
+	m.closers = nil
+	m.mu.Unlock()
+
+	var errs []error
+	for _, closer := range closers {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func optionString(options map[string]any, key string) string {
+	value, _ := options[key].(string)
+	return value
+}
+
+// isDriver reports whether a driver exists, so a channel named after it
+// needs no config.
+func (m *LogManager) isDriver(driver string) bool {
+	switch driver {
+	case "console", "stdout", "json", "stderr":
+		return true
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.drivers[driver]
+	return ok
+}
+
+// Debug logs a debug message to the default channel.
+func (m *LogManager) Debug(msg string, fields ...any) {
+	m.defaultChannel().Debug(msg, fields...)
+}
+
+// Info logs an info message to the default channel.
+func (m *LogManager) Info(msg string, fields ...any) {
+	m.defaultChannel().Info(msg, fields...)
+}
+
+// Warn logs a warning message to the default channel.
+func (m *LogManager) Warn(msg string, fields ...any) {
+	m.defaultChannel().Warn(msg, fields...)
+}
+
+// Error logs an error message to the default channel.
+func (m *LogManager) Error(msg string, fields ...any) {
+	m.defaultChannel().Error(msg, fields...)
+}
+
+// Fatal logs a fatal message to the default channel.
+func (m *LogManager) Fatal(msg string, fields ...any) {
+	m.defaultChannel().Fatal(msg, fields...)
+}
+
+// Panic logs a panic message to the default channel.
+func (m *LogManager) Panic(msg string, fields ...any) {
+	m.defaultChannel().Panic(msg, fields...)
+}
+
+// WithField returns a logger with a field attached.
+func (m *LogManager) WithField(key string, value any) contracts.Logger {
+	return m.defaultChannel().WithField(key, value)
+}
+
+// WithFields returns a logger with multiple fields attached.
+func (m *LogManager) WithFields(fields map[string]any) contracts.Logger {
+	return m.defaultChannel().WithFields(fields)
+}
+
+// WithContext returns a logger with context attached.
+func (m *LogManager) WithContext(ctx context.Context) contracts.Logger {
+	return m.defaultChannel().WithContext(ctx)
+}
+
+// WithError returns a logger with an error attached.
+func (m *LogManager) WithError(err error) contracts.Logger {
+	return m.defaultChannel().WithError(err)
+}
+
+// Level returns the current log level.
+func (m *LogManager) Level() contracts.LogLevel {
+	return m.defaultChannel().Level()
+}
+
+// SetLevel sets the log level.
+func (m *LogManager) SetLevel(level contracts.LogLevel) {
+	m.defaultChannel().SetLevel(level)
+}
@@ -0,0 +1,108 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerChannels(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager(Config{
+		Default: "app",
+		Channels: map[string]ChannelConfig{
+			"app":   {Driver: "file", Path: filepath.Join(dir, "app.log")},
+			"audit": {Driver: "file", Path: filepath.Join(dir, "audit", "audit.log"), Level: "warn"},
+			"all":   {Driver: "stack", Channels: []string{"app", "audit"}},
+		},
+	})
+
+	audit := manager.Channel("audit")
+	assert.Same(t, audit, manager.Channel("audit"))
+	assert.Equal(t, contracts.LogLevelWarn, audit.Level())
+
+	manager.Info("to the default channel")
+	manager.Channel("all").WithField("user", 7).Warn("to both")
+	audit.Info("below the audit level")
+
+	app, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(app), "to the default channel")
+	assert.Contains(t, string(app), `"user":7`)
+	auditLog, err := os.ReadFile(filepath.Join(dir, "audit", "audit.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(auditLog), "to both")
+	assert.NotContains(t, string(auditLog), "below the audit level")
+	assert.NotContains(t, string(auditLog), "to the default channel")
+}
+
+func TestManagerChannelErrors(t *testing.T) {
+	manager := NewManager(Config{
+		Channels: map[string]ChannelConfig{
+			"loop":    {Driver: "stack", Channels: []string{"inner"}},
+			"inner":   {Driver: "stack", Channels: []string{"loop"}},
+			"nopath":  {Driver: "file"},
+			"unknown": {Driver: "carrier-pigeon"},
+		},
+	})
+
+	_, err := manager.Logger("loop")
+	assert.ErrorContains(t, err, "contains itself: loop -> inner -> loop")
+	_, err = manager.Logger("nopath")
+	assert.ErrorContains(t, err, "requires a path")
+	_, err = manager.Logger("unknown")
+	assert.ErrorContains(t, err, "unsupported log driver: carrier-pigeon")
+	_, err = manager.Logger("missing")
+	assert.ErrorContains(t, err, "log channel [missing] not configured")
+
+	// Channels named after a driver need no config.
+	logger, err := manager.Logger("stderr")
+	require.NoError(t, err)
+	assert.NotNil(t, logger)
+
+	buf := &bytes.Buffer{}
+	manager.AddChannel("console", NewJSON(buf))
+	assert.Same(t, manager.Channel("console"), manager.Channel("missing"))
+	assert.Contains(t, buf.String(), "Failed to create log channel")
+}
+
+func TestManagerExtend(t *testing.T) {
+	buf := &bytes.Buffer{}
+	manager := NewManager(Config{
+		Default:  "memory",
+		Channels: map[string]ChannelConfig{"memory": {Driver: "buffer", Options: map[string]any{"prefix": "x"}}},
+	})
+	manager.Extend("buffer", func(name string, config ChannelConfig) (contracts.Logger, error) {
+		assert.Equal(t, "memory", name)
+		assert.Equal(t, "x", config.Options["prefix"])
+		return NewJSON(buf), nil
+	})
+
+	manager.Info("hello")
+	assert.Contains(t, buf.String(), "hello")
+}
+
+func TestStack(t *testing.T) {
+	first, second := &bytes.Buffer{}, &bytes.Buffer{}
+	a, b := NewJSON(first), NewJSON(second)
+	b.SetLevel(contracts.LogLevelError)
+	stack := NewStack(a, b)
+
+	stack.WithError(assert.AnError).Error("failed")
+	stack.Info("started")
+
+	assert.Contains(t, first.String(), "failed")
+	assert.Contains(t, first.String(), "started")
+	assert.Contains(t, second.String(), assert.AnError.Error())
+	assert.NotContains(t, second.String(), "started")
+	assert.Equal(t, contracts.LogLevelInfo, stack.Level())
+
+	stack.SetLevel(contracts.LogLevelWarn)
+	assert.Equal(t, contracts.LogLevelWarn, a.Level())
+	assert.Equal(t, contracts.LogLevelWarn, b.Level())
+}
@@ -0,0 +1,129 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// RotateOptions configures the rotation and retention of a log file.
+type RotateOptions struct {
+	// MaxSize is the size in megabytes a file is rotated at. Zero is 100.
+	MaxSize int `yaml:"max_size" json:"max_size"`
+
+	// Interval rotates the file when it crosses an interval boundary, such
+	// as every day at midnight UTC for 24h. Zero rotates by size only.
+	Interval time.Duration `yaml:"interval" json:"interval"`
+
+	// MaxBackups is the number of rotated files kept. Zero keeps them all,
+	// unless MaxAge removes them.
+	MaxBackups int `yaml:"max_backups" json:"max_backups"`
+
+	// MaxAge is the number of days rotated files are kept. Zero keeps them
+	// regardless of age.
+	MaxAge int `yaml:"max_age" json:"max_age"`
+
+	// Compress gzips rotated files.
+	Compress bool `yaml:"compress" json:"compress"`
+}
+
+// enabled reports whether any rotation or retention is configured.
+func (o RotateOptions) enabled() bool {
+	return o != RotateOptions{}
+}
+
+// RotatingFile is a log file that is rotated by size and time. Rotated
+// files are renamed to name-<timestamp>.log next to it, gzipped when
+// compression is on, and removed past MaxBackups or MaxAge.
+type RotatingFile struct {
+	file     *lumberjack.Logger
+	interval time.Duration
+
+	mu     sync.Mutex
+	period time.Time
+	now    func() time.Time
+}
+
+// NewRotatingFile opens path for appending with the given rotation.
+func NewRotatingFile(path string, options RotateOptions) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f := &RotatingFile{
+		file: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    options.MaxSize,
+			MaxBackups: options.MaxBackups,
+			MaxAge:     options.MaxAge,
+			Compress:   options.Compress,
+		},
+		interval: options.Interval,
+		now:      time.Now,
+	}
+	f.period = f.currentPeriod()
+	// A file last written in an earlier interval is rotated on first write.
+	if info, err := os.Stat(path); err == nil && f.interval > 0 {
+		f.period = info.ModTime().UTC().Truncate(f.interval)
+	}
+	return f, nil
+}
+
+// Write appends p, rotating the file first when it was last written in an
+// earlier interval or p would take it past MaxSize.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	if f.interval > 0 {
+		f.mu.Lock()
+		period := f.currentPeriod()
+		if period.After(f.period) {
+			f.period = period
+			if info, err := os.Stat(f.file.Filename); err == nil && info.Size() > 0 {
+				if err := f.file.Rotate(); err != nil {
+					f.mu.Unlock()
+					return 0, fmt.Errorf("log: failed to rotate '%s': %w", f.file.Filename, err)
+				}
+			}
+		}
+		f.mu.Unlock()
+	}
+	return f.file.Write(p)
+}
+
+// Rotate rotates the file now.
+func (f *RotatingFile) Rotate() error {
+	return f.file.Rotate()
+}
+
+// Close closes the file.
+func (f *RotatingFile) Close() error {
+	return f.file.Close()
+}
+
+func (f *RotatingFile) currentPeriod() time.Time {
+	if f.interval <= 0 {
+		return time.Time{}
+	}
+	return f.now().UTC().Truncate(f.interval)
+}
+
+// NewRotatingFileLogger creates a Logger that writes JSON lines to a
+// rotating file.
+func NewRotatingFileLogger(path string, options RotateOptions) (*Logger, error) {
+	file, err := NewRotatingFile(path, options)
+	if err != nil {
+		return nil, err
+	}
+	return NewJSON(file), nil
+}
+
+// backupPattern matches the names of rotated log files.
+var backupPattern = regexp.MustCompile(`-\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2}\.\d{3}\.log(\.gz)?$`)
+
+// IsBackup reports whether a file name is that of a rotated log file.
+func IsBackup(name string) bool {
+	return backupPattern.MatchString(name)
+}
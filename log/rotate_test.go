@@ -0,0 +1,97 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func logFiles(t *testing.T, dir string) (current []string, backups []string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		if IsBackup(entry.Name()) {
+			backups = append(backups, entry.Name())
+		} else {
+			current = append(current, entry.Name())
+		}
+	}
+	return current, backups
+}
+
+func TestRotatingFileInterval(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	file, err := NewRotatingFile(path, RotateOptions{Interval: 24 * time.Hour})
+	require.NoError(t, err)
+	defer file.Close()
+
+	now := time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)
+	file.now = func() time.Time { return now }
+	file.period = file.currentPeriod()
+
+	_, err = file.Write([]byte("before midnight\n"))
+	require.NoError(t, err)
+	now = now.Add(2 * time.Minute)
+	_, err = file.Write([]byte("after midnight\n"))
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "after midnight\n", string(content))
+	_, backups := logFiles(t, dir)
+	assert.Len(t, backups, 1)
+}
+
+func TestRotatingFileRotatesStaleFileOnFirstWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	require.NoError(t, os.WriteFile(path, []byte("yesterday\n"), 0644))
+	yesterday := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(path, yesterday, yesterday))
+
+	logger, err := NewRotatingFileLogger(path, RotateOptions{Interval: 24 * time.Hour})
+	require.NoError(t, err)
+	logger.Info("today")
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "today")
+	assert.NotContains(t, string(content), "yesterday")
+}
+
+func TestRotatingFileRetention(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	file, err := NewRotatingFile(path, RotateOptions{MaxBackups: 2, Compress: true})
+	require.NoError(t, err)
+	defer file.Close()
+
+	for i := 0; i < 4; i++ {
+		_, err := file.Write([]byte("entry\n"))
+		require.NoError(t, err)
+		require.NoError(t, file.Rotate())
+		// Backups are named by rotation time in milliseconds.
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	// Compression and removal happen in the background.
+	assert.Eventually(t, func() bool {
+		_, backups := logFiles(t, dir)
+		if len(backups) != 2 {
+			return false
+		}
+		for _, name := range backups {
+			if !strings.HasSuffix(name, ".log.gz") {
+				return false
+			}
+		}
+		return true
+	}, 2*time.Second, 10*time.Millisecond)
+}
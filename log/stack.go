@@ -0,0 +1,112 @@
+package log
+
+import (
+	"context"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// Stack is a logger that writes every entry to several loggers, each
+// applying its own level and format.
+type Stack struct {
+	loggers []contracts.Logger
+}
+
+// NewStack creates a logger that writes to each of loggers.
+func NewStack(loggers ...contracts.Logger) *Stack {
+	return &Stack{loggers: loggers}
+}
+
+// Loggers returns the loggers the stack writes to.
+func (s *Stack) Loggers() []contracts.Logger {
+	return s.loggers
+}
+
+// Debug logs a debug message.
+func (s *Stack) Debug(msg string, fields ...any) {
+	for _, logger := range s.loggers {
+		logger.Debug(msg, fields...)
+	}
+}
+
+// Info logs an info message.
+func (s *Stack) Info(msg string, fields ...any) {
+	for _, logger := range s.loggers {
+		logger.Info(msg, fields...)
+	}
+}
+
+// Warn logs a warning message.
+func (s *Stack) Warn(msg string, fields ...any) {
+	for _, logger := range s.loggers {
+		logger.Warn(msg, fields...)
+	}
+}
+
+// Error logs an error message.
+func (s *Stack) Error(msg string, fields ...any) {
+	for _, logger := range s.loggers {
+		logger.Error(msg, fields...)
+	}
+}
+
+// Fatal logs a fatal message.
+func (s *Stack) Fatal(msg string, fields ...any) {
+	for _, logger := range s.loggers {
+		logger.Fatal(msg, fields...)
+	}
+}
+
+// Panic logs a panic message.
+func (s *Stack) Panic(msg string, fields ...any) {
+	for _, logger := range s.loggers {
+		logger.Panic(msg, fields...)
+	}
+}
+
+// WithField returns a stack with a field attached to each logger.
+func (s *Stack) WithField(key string, value any) contracts.Logger {
+	return s.derive(func(logger contracts.Logger) contracts.Logger { return logger.WithField(key, value) })
+}
+
+// WithFields returns a stack with multiple fields attached to each logger.
+func (s *Stack) WithFields(fields map[string]any) contracts.Logger {
+	return s.derive(func(logger contracts.Logger) contracts.Logger { return logger.WithFields(fields) })
+}
+
+// WithContext returns a stack with context attached to each logger.
+func (s *Stack) WithContext(ctx context.Context) contracts.Logger {
+	return s.derive(func(logger contracts.Logger) contracts.Logger { return logger.WithContext(ctx) })
+}
+
+// WithError returns a stack with an error attached to each logger.
+func (s *Stack) WithError(err error) contracts.Logger {
+	return s.derive(func(logger contracts.Logger) contracts.Logger { return logger.WithError(err) })
+}
+
+// Level returns the lowest level of the stack's loggers.
+func (s *Stack) Level() contracts.LogLevel {
+	if len(s.loggers) == 0 {
+		return contracts.LogLevelInfo
+	}
+	level := s.loggers[0].Level()
+	for _, logger := range s.loggers[1:] {
+		level = min(level, logger.Level())
+	}
+	return level
+}
+
+// SetLevel sets the level of each of the stack's loggers.
+func (s *Stack) SetLevel(level contracts.LogLevel) {
+	for _, logger := range s.loggers {
+		logger.SetLevel(level)
+	}
+}
+
+func (s *Stack) derive(fn func(contracts.Logger) contracts.Logger) *Stack {
+	loggers := make([]contracts.Logger, len(s.loggers))
+	for i, logger := range s.loggers {
+		loggers[i] = fn(logger)
+	}
+	return &Stack{loggers: loggers}
+}
@@ -0,0 +1,24 @@
+//go:build !windows && !plan9
+
+package log
+
+import (
+	"log/syslog"
+
+	"github.com/rs/zerolog"
+)
+
+// NewSyslog creates a Logger that writes JSON entries to syslog at the
+// matching priority. Empty network and address use the local syslog
+// daemon; an empty tag uses the program name.
+func NewSyslog(network, address, tag string) (*Logger, error) {
+	writer, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &Logger{
+		logger: zerolog.New(zerolog.SyslogLevelWriter(writer)).With().Timestamp().Logger(),
+		level:  newLevel(),
+		fields: make(map[string]any),
+	}, nil
+}
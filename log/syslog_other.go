@@ -0,0 +1,10 @@
+//go:build windows || plan9
+
+package log
+
+import "errors"
+
+// NewSyslog is not supported on this platform.
+func NewSyslog(network, address, tag string) (*Logger, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
@@ -0,0 +1,250 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// cssRule is one selector of a stylesheet rule and its declarations.
+type cssRule struct {
+	selector    []cssCompound // descendant chain, outermost first
+	specificity [3]int
+	order       int
+	declaration string
+}
+
+// cssCompound is a simple selector such as p, .button, #header or a.button.
+type cssCompound struct {
+	tag     string
+	id      string
+	classes []string
+}
+
+// InlineCSS moves the rules in an HTML document's <style> elements onto the
+// style attributes of the elements they match, for mail clients that ignore
+// stylesheets. It supports type, class and ID selectors, compounds of them
+// and descendant combinators; declarations already in a style attribute win.
+// At-rules such as @media can't be inlined and are kept in a <style> element.
+func InlineCSS(document string) (string, error) {
+	root, err := html.Parse(strings.NewReader(document))
+	if err != nil {
+		return "", fmt.Errorf("mail: failed to parse html: %w", err)
+	}
+
+	var rules []cssRule
+	var styles []*html.Node
+	for node := range root.Descendants() {
+		if node.Type == html.ElementNode && node.Data == "style" {
+			styles = append(styles, node)
+		}
+	}
+	for _, style := range styles {
+		var css strings.Builder
+		for child := style.FirstChild; child != nil; child = child.NextSibling {
+			css.WriteString(child.Data)
+		}
+		parsed, atRules := parseCSS(css.String(), len(rules))
+		rules = append(rules, parsed...)
+
+		if len(atRules) > 0 {
+			style.FirstChild.Data = strings.Join(atRules, "\n")
+			for style.FirstChild.NextSibling != nil {
+				style.RemoveChild(style.FirstChild.NextSibling)
+			}
+		} else {
+			style.Parent.RemoveChild(style)
+		}
+	}
+	if len(rules) == 0 {
+		return document, nil
+	}
+
+	slices.SortStableFunc(rules, func(a, b cssRule) int {
+		for i := range a.specificity {
+			if a.specificity[i] != b.specificity[i] {
+				return a.specificity[i] - b.specificity[i]
+			}
+		}
+		return a.order - b.order
+	})
+
+	for node := range root.Descendants() {
+		if node.Type != html.ElementNode {
+			continue
+		}
+		var declarations []string
+		for _, rule := range rules {
+			if rule.matches(node) {
+				declarations = append(declarations, rule.declaration)
+			}
+		}
+		if len(declarations) == 0 {
+			continue
+		}
+		if existing := attr(node, "style"); existing != "" {
+			declarations = append(declarations, strings.TrimSuffix(strings.TrimSpace(existing), ";"))
+		}
+		setAttr(node, "style", strings.Join(declarations, "; "))
+	}
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, root); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// parseCSS splits a stylesheet into rules, returning at-rule blocks as is.
+// Comments are removed.
+func parseCSS(css string, order int) (rules []cssRule, atRules []string) {
+	for {
+		start := strings.Index(css, "/*")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(css[start+2:], "*/")
+		if end < 0 {
+			css = css[:start]
+			break
+		}
+		css = css[:start] + css[start+2+end+2:]
+	}
+
+	for {
+		css = strings.TrimSpace(css)
+		open := strings.IndexByte(css, '{')
+		if open < 0 {
+			return rules, atRules
+		}
+		selectors := strings.TrimSpace(css[:open])
+
+		if strings.HasPrefix(selectors, "@") {
+			// Find the matching brace of a nested block such as @media.
+			depth, end := 0, len(css)
+			for i := open; i < len(css); i++ {
+				if css[i] == '{' {
+					depth++
+				} else if css[i] == '}' {
+					if depth--; depth == 0 {
+						end = i + 1
+						break
+					}
+				}
+			}
+			atRules = append(atRules, css[:end])
+			css = css[end:]
+			continue
+		}
+
+		close := strings.IndexByte(css[open:], '}')
+		if close < 0 {
+			return rules, atRules
+		}
+		declaration := strings.TrimSuffix(strings.TrimSpace(css[open+1:open+close]), ";")
+		css = css[open+close+1:]
+		if declaration == "" {
+			continue
+		}
+
+		for _, selector := range strings.Split(selectors, ",") {
+			rule, ok := parseSelector(strings.TrimSpace(selector))
+			if !ok {
+				continue
+			}
+			rule.order = order
+			rule.declaration = declaration
+			rules = append(rules, rule)
+			order++
+		}
+	}
+}
+
+// parseSelector parses a descendant chain of simple selectors. Selectors
+// using other combinators, attributes or pseudo-classes are skipped.
+func parseSelector(selector string) (cssRule, bool) {
+	if selector == "" || strings.ContainsAny(selector, ">+~[:*") {
+		return cssRule{}, false
+	}
+
+	var rule cssRule
+	for _, part := range strings.Fields(selector) {
+		var compound cssCompound
+		for part != "" {
+			end := strings.IndexAny(part[1:], ".#") + 1
+			if end == 0 {
+				end = len(part)
+			}
+			token := part[:end]
+			part = part[end:]
+
+			switch token[0] {
+			case '#':
+				compound.id = token[1:]
+				rule.specificity[0]++
+			case '.':
+				compound.classes = append(compound.classes, token[1:])
+				rule.specificity[1]++
+			default:
+				compound.tag = strings.ToLower(token)
+				rule.specificity[2]++
+			}
+		}
+		rule.selector = append(rule.selector, compound)
+	}
+	return rule, true
+}
+
+// matches reports whether the rule's selector matches node.
+func (r cssRule) matches(node *html.Node) bool {
+	last := len(r.selector) - 1
+	if !r.selector[last].matches(node) {
+		return false
+	}
+	// Match the remaining compounds against ancestors, innermost first.
+	i := last - 1
+	for parent := node.Parent; parent != nil && i >= 0; parent = parent.Parent {
+		if parent.Type == html.ElementNode && r.selector[i].matches(parent) {
+			i--
+		}
+	}
+	return i < 0
+}
+
+func (c cssCompound) matches(node *html.Node) bool {
+	if c.tag != "" && c.tag != node.Data {
+		return false
+	}
+	if c.id != "" && c.id != attr(node, "id") {
+		return false
+	}
+	classes := strings.Fields(attr(node, "class"))
+	for _, class := range c.classes {
+		if !slices.Contains(classes, class) {
+			return false
+		}
+	}
+	return true
+}
+
+func attr(node *html.Node, key string) string {
+	for _, a := range node.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func setAttr(node *html.Node, key, value string) {
+	for i, a := range node.Attr {
+		if a.Key == key {
+			node.Attr[i].Val = value
+			return
+		}
+	}
+	node.Attr = append(node.Attr, html.Attribute{Key: key, Val: value})
+}
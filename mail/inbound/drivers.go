@@ -0,0 +1,267 @@
+package inbound
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// ErrInvalidSignature is returned when a webhook fails signature verification.
+var ErrInvalidSignature = errors.New("inbound: invalid webhook signature")
+
+// ErrNoMessage is returned for webhook requests that carry no message, such
+// as SNS subscription confirmations. Handlers respond 200 to them.
+var ErrNoMessage = errors.New("inbound: request contains no message")
+
+// Driver parses a provider's inbound webhook request.
+type Driver interface {
+	Parse(req contracts.Request) (*Message, error)
+}
+
+// MIME parses requests whose body is a raw RFC 5322 message.
+type MIME struct{}
+
+// Parse parses the request body as a MIME message.
+func (MIME) Parse(req contracts.Request) (*Message, error) {
+	return ParseMIME(bytes.NewReader(req.Body()))
+}
+
+// SES parses Amazon SES receipt notifications delivered through SNS. The
+// receipt rule's SNS action must include the message content. Every
+// notification's SNS signature is verified against the signing certificate
+// before it is trusted.
+type SES struct {
+	// TopicARN restricts notifications to a single SNS topic. Required.
+	TopicARN string
+
+	// Client confirms SNS subscriptions and fetches signing certificates.
+	// Defaults to a client with a 10 second timeout.
+	Client *http.Client
+}
+
+// Parse verifies an SNS notification and parses its embedded SES message.
+func (d SES) Parse(req contracts.Request) (*Message, error) {
+	var notification snsMessage
+	if err := json.Unmarshal(req.Body(), &notification); err != nil {
+		return nil, fmt.Errorf("inbound: invalid SNS notification: %w", err)
+	}
+	if d.TopicARN == "" || notification.TopicArn != d.TopicARN {
+		return nil, ErrInvalidSignature
+	}
+	if err := notification.verify(d.client()); err != nil {
+		return nil, err
+	}
+
+	switch notification.Type {
+	case "SubscriptionConfirmation":
+		return nil, d.confirm(notification.SubscribeURL)
+	case "Notification":
+	default:
+		return nil, ErrNoMessage
+	}
+
+	var payload struct {
+		Content string `json:"content"`
+		Receipt struct {
+			Action struct {
+				Encoding string `json:"encoding"`
+			} `json:"action"`
+		} `json:"receipt"`
+	}
+	if err := json.Unmarshal([]byte(notification.Message), &payload); err != nil {
+		return nil, fmt.Errorf("inbound: invalid SES notification: %w", err)
+	}
+	if payload.Content == "" {
+		return nil, fmt.Errorf("inbound: SES notification has no content; include it in the SNS action")
+	}
+
+	content := []byte(payload.Content)
+	if strings.EqualFold(payload.Receipt.Action.Encoding, "BASE64") {
+		decoded, err := base64.StdEncoding.DecodeString(payload.Content)
+		if err != nil {
+			return nil, fmt.Errorf("inbound: invalid SES content encoding: %w", err)
+		}
+		content = decoded
+	}
+	return ParseMIME(bytes.NewReader(content))
+}
+
+// confirm visits an SNS subscription URL. Only HTTPS URLs on amazonaws.com are followed.
+func (d SES) confirm(subscribeURL string) error {
+	u, err := url.Parse(subscribeURL)
+	if err != nil || u.Scheme != "https" || !strings.HasSuffix(u.Hostname(), ".amazonaws.com") {
+		return fmt.Errorf("inbound: refusing SNS subscribe URL %q", subscribeURL)
+	}
+
+	resp, err := d.client().Get(u.String())
+	if err != nil {
+		return fmt.Errorf("inbound: failed to confirm SNS subscription: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("inbound: SNS subscription confirmation returned %d", resp.StatusCode)
+	}
+	return ErrNoMessage
+}
+
+func (d SES) client() *http.Client {
+	if d.Client != nil {
+		return d.Client
+	}
+	return &http.Client{Timeout: 10 * time.Second}
+}
+
+// Mailgun parses Mailgun inbound route webhooks ("forward" or "store and notify").
+type Mailgun struct {
+	// SigningKey is the HTTP webhook signing key. Required.
+	SigningKey string
+
+	// Tolerance is how old a signed timestamp may be. Defaults to 5 minutes.
+	Tolerance time.Duration
+}
+
+// Parse verifies the webhook signature and parses the posted message.
+func (d Mailgun) Parse(req contracts.Request) (*Message, error) {
+	if err := d.verify(req.Input("timestamp"), req.Input("token"), req.Input("signature")); err != nil {
+		return nil, err
+	}
+
+	if raw := req.Input("body-mime"); raw != "" {
+		return ParseMIME(strings.NewReader(raw))
+	}
+
+	message := &Message{
+		MessageID:  req.Input("Message-Id"),
+		InReplyTo:  req.Input("In-Reply-To"),
+		References: strings.Fields(req.Input("References")),
+		To:         parseAddressList(req.Input("recipient")),
+		Cc:         parseAddressList(req.Input("Cc")),
+		Subject:    req.Input("subject"),
+		Text:       req.Input("body-plain"),
+		HTML:       req.Input("body-html"),
+		ReceivedAt: time.Now(),
+	}
+	message.From, message.FromName = parseAddress(req.Input("from"))
+
+	count, _ := strconv.Atoi(req.Input("attachment-count"))
+	for i := 1; i <= count; i++ {
+		attachment, err := formAttachment(req, fmt.Sprintf("attachment-%d", i))
+		if err != nil {
+			return nil, err
+		}
+		message.Attachments = append(message.Attachments, attachment)
+	}
+	return message, nil
+}
+
+func (d Mailgun) verify(timestamp, token, signature string) error {
+	if d.SigningKey == "" || timestamp == "" || token == "" || signature == "" {
+		return ErrInvalidSignature
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	tolerance := d.Tolerance
+	if tolerance == 0 {
+		tolerance = 5 * time.Minute
+	}
+	if age := time.Since(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(d.SigningKey))
+	mac.Write([]byte(timestamp + token))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// SendGrid parses SendGrid Inbound Parse webhooks, in either the default or
+// "send raw" format. SendGrid does not sign these requests; protect the route
+// with basic auth or a secret path.
+type SendGrid struct{}
+
+// Parse parses the posted message.
+func (SendGrid) Parse(req contracts.Request) (*Message, error) {
+	if raw := req.Input("email"); raw != "" {
+		return ParseMIME(strings.NewReader(raw))
+	}
+
+	headers := parseHeaderBlock(req.Input("headers"))
+	header := func(key string) string {
+		if values := headers[http.CanonicalHeaderKey(key)]; len(values) > 0 {
+			return strings.TrimSpace(values[0])
+		}
+		return ""
+	}
+
+	message := &Message{
+		MessageID:  header("Message-Id"),
+		InReplyTo:  header("In-Reply-To"),
+		References: strings.Fields(header("References")),
+		To:         parseAddressList(req.Input("to")),
+		Cc:         parseAddressList(req.Input("cc")),
+		Subject:    req.Input("subject"),
+		Text:       req.Input("text"),
+		HTML:       req.Input("html"),
+		Headers:    headers,
+		ReceivedAt: time.Now(),
+	}
+	message.From, message.FromName = parseAddress(req.Input("from"))
+
+	count, _ := strconv.Atoi(req.Input("attachments"))
+	for i := 1; i <= count; i++ {
+		attachment, err := formAttachment(req, fmt.Sprintf("attachment%d", i))
+		if err != nil {
+			return nil, err
+		}
+		message.Attachments = append(message.Attachments, attachment)
+	}
+	return message, nil
+}
+
+// formAttachment reads an uploaded file from a multipart webhook request.
+func formAttachment(req contracts.Request, field string) (*Attachment, error) {
+	header, err := req.File(field)
+	if err != nil {
+		return nil, fmt.Errorf("inbound: missing %s: %w", field, err)
+	}
+	return readFileHeader(header)
+}
+
+func readFileHeader(header *multipart.FileHeader) (*Attachment, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := header.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return NewAttachment(header.Filename, contentType, content), nil
+}
@@ -0,0 +1,406 @@
+package inbound_test
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/events"
+	"github.com/genesysflow/go-genesys/filesystem"
+	genesyshttp "github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/mail/inbound"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rawEmail = "From: =?UTF-8?Q?Jos=C3=A9?= <jose@example.com>\r\n" +
+	"To: support+1234@inbound.example.com, Help <help@example.com>\r\n" +
+	"Subject: =?UTF-8?Q?Re:_Ticket_=E2=9C=93?=\r\n" +
+	"Message-ID: <reply-1@example.com>\r\n" +
+	"In-Reply-To: <ticket-1234@example.com>\r\n" +
+	"References: <ticket-1234@example.com> <other@example.com>\r\n" +
+	"Date: Mon, 02 Jan 2006 15:04:05 +0000\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"Thanks, it works =E2=9C=93\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>Thanks, it works</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/plain; name=\"log.txt\"\r\n" +
+	"Content-Disposition: attachment; filename=\"log.txt\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"aGVsbG8gd29y\r\n" +
+	"bGQ=\r\n" +
+	"--outer--\r\n"
+
+func TestParseMIME(t *testing.T) {
+	message, err := inbound.ParseMIME(strings.NewReader(rawEmail))
+	require.NoError(t, err)
+
+	assert.Equal(t, "jose@example.com", message.From)
+	assert.Equal(t, "José", message.FromName)
+	assert.Equal(t, []string{"support+1234@inbound.example.com", "help@example.com"}, message.To)
+	assert.Equal(t, "Re: Ticket ✓", message.Subject)
+	assert.Equal(t, "<reply-1@example.com>", message.MessageID)
+	assert.Equal(t, "<ticket-1234@example.com>", message.InReplyTo)
+	assert.Len(t, message.References, 2)
+	assert.Equal(t, 2006, message.ReceivedAt.Year())
+	assert.Equal(t, "Thanks, it works ✓", message.Text)
+	assert.Equal(t, "<p>Thanks, it works</p>", message.HTML)
+
+	require.Len(t, message.Attachments, 1)
+	assert.Equal(t, "log.txt", message.Attachments[0].Filename)
+	assert.Equal(t, "text/plain", message.Attachments[0].ContentType)
+	assert.Equal(t, "hello world", string(message.Attachments[0].Content()))
+}
+
+// parseWith runs a driver against a request served by fiber.
+func parseWith(t *testing.T, driver inbound.Driver, contentType string, body []byte) (*inbound.Message, error) {
+	t.Helper()
+
+	var message *inbound.Message
+	var parseErr error
+	app := fiber.New()
+	app.Post("/inbound", func(c *fiber.Ctx) error {
+		message, parseErr = driver.Parse(genesyshttp.NewRequest(c))
+		return nil
+	})
+
+	req := httptest.NewRequest("POST", "/inbound", bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	_, err := app.Test(req)
+	require.NoError(t, err)
+	return message, parseErr
+}
+
+func multipartBody(t *testing.T, fields map[string]string, files map[string]string) (string, []byte) {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for key, value := range fields {
+		require.NoError(t, writer.WriteField(key, value))
+	}
+	for field, content := range files {
+		part, err := writer.CreateFormFile(field, field+".txt")
+		require.NoError(t, err)
+		io.WriteString(part, content)
+	}
+	require.NoError(t, writer.Close())
+	return writer.FormDataContentType(), body.Bytes()
+}
+
+func mailgunSignature(key, timestamp, token string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(timestamp + token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestMailgunDriver(t *testing.T) {
+	driver := inbound.Mailgun{SigningKey: "key-123"}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	fields := map[string]string{
+		"timestamp":        timestamp,
+		"token":            "tok",
+		"signature":        mailgunSignature("key-123", timestamp, "tok"),
+		"from":             "Jane <jane@example.com>",
+		"recipient":        "support@inbound.example.com",
+		"subject":          "Help",
+		"body-plain":       "It broke",
+		"In-Reply-To":      "<ticket-1@example.com>",
+		"attachment-count": "1",
+	}
+	contentType, body := multipartBody(t, fields, map[string]string{"attachment-1": "trace"})
+
+	message, err := parseWith(t, driver, contentType, body)
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", message.From)
+	assert.Equal(t, []string{"support@inbound.example.com"}, message.To)
+	assert.Equal(t, "It broke", message.Text)
+	assert.Equal(t, "<ticket-1@example.com>", message.InReplyTo)
+	require.Len(t, message.Attachments, 1)
+	assert.Equal(t, "trace", string(message.Attachments[0].Content()))
+
+	fields["signature"] = "forged"
+	contentType, body = multipartBody(t, fields, nil)
+	_, err = parseWith(t, driver, contentType, body)
+	assert.ErrorIs(t, err, inbound.ErrInvalidSignature)
+}
+
+func TestSendGridDriver(t *testing.T) {
+	t.Run("raw", func(t *testing.T) {
+		contentType, body := multipartBody(t, map[string]string{"email": rawEmail}, nil)
+		message, err := parseWith(t, inbound.SendGrid{}, contentType, body)
+		require.NoError(t, err)
+		assert.Equal(t, "Re: Ticket ✓", message.Subject)
+		assert.Len(t, message.Attachments, 1)
+	})
+
+	t.Run("parsed", func(t *testing.T) {
+		contentType, body := multipartBody(t, map[string]string{
+			"from":        "jane@example.com",
+			"to":          "support@inbound.example.com",
+			"subject":     "Help",
+			"text":        "It broke",
+			"headers":     "Message-ID: <m1@example.com>\nIn-Reply-To: <ticket-1@example.com>\n",
+			"attachments": "1",
+		}, map[string]string{"attachment1": "trace"})
+
+		message, err := parseWith(t, inbound.SendGrid{}, contentType, body)
+		require.NoError(t, err)
+		assert.Equal(t, "<m1@example.com>", message.MessageID)
+		assert.Equal(t, "<ticket-1@example.com>", message.InReplyTo)
+		assert.Len(t, message.Attachments, 1)
+	})
+}
+
+// roundTripFunc serves an http.Client's requests from a function.
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// snsSigner signs SNS messages and serves its certificate to SES drivers.
+type snsSigner struct {
+	key       *rsa.PrivateKey
+	cert      []byte
+	certURL   string
+	requested []string
+}
+
+func newSNSSigner(t *testing.T) *snsSigner {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return &snsSigner{
+		key:  key,
+		cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		// Each signer has its own URL, as certificates are cached by URL.
+		certURL: fmt.Sprintf("https://sns.us-east-1.amazonaws.com/SimpleNotificationService-%p.pem", key),
+	}
+}
+
+func (s *snsSigner) client() *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		s.requested = append(s.requested, req.URL.String())
+		body := "OK"
+		if req.URL.String() == s.certURL {
+			body = string(s.cert)
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}}, nil
+	})}
+}
+
+// sign sets the SNS signature fields of message with SignatureVersion version.
+func (s *snsSigner) sign(t *testing.T, message map[string]string, version string) []byte {
+	t.Helper()
+	names := []string{"Message", "MessageId", "Subject", "Timestamp", "TopicArn", "Type"}
+	if message["Type"] != "Notification" {
+		names = []string{"Message", "MessageId", "SubscribeURL", "Timestamp", "Token", "TopicArn", "Type"}
+	}
+	var canonical strings.Builder
+	for _, name := range names {
+		if value, ok := message[name]; ok {
+			canonical.WriteString(name + "\n" + value + "\n")
+		}
+	}
+
+	hash, digest := crypto.SHA256, sha256.Sum256([]byte(canonical.String()))
+	sum := digest[:]
+	if version == "1" {
+		sha1Digest := sha1.Sum([]byte(canonical.String()))
+		hash, sum = crypto.SHA1, sha1Digest[:]
+	}
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, hash, sum)
+	require.NoError(t, err)
+
+	message["SignatureVersion"] = version
+	message["Signature"] = base64.StdEncoding.EncodeToString(signature)
+	message["SigningCertURL"] = s.certURL
+	data, err := json.Marshal(message)
+	require.NoError(t, err)
+	return data
+}
+
+func TestSESDriver(t *testing.T) {
+	signer := newSNSSigner(t)
+	driver := inbound.SES{TopicARN: "arn:aws:sns:us-east-1:123:inbound", Client: signer.client()}
+
+	payload, _ := json.Marshal(map[string]any{
+		"content": base64.StdEncoding.EncodeToString([]byte(rawEmail)),
+		"receipt": map[string]any{"action": map[string]any{"encoding": "BASE64"}},
+	})
+	notification := func(topic string) map[string]string {
+		return map[string]string{
+			"Type":      "Notification",
+			"MessageId": "m-1",
+			"TopicArn":  topic,
+			"Subject":   "Amazon SES Email Receipt Notification",
+			"Message":   string(payload),
+			"Timestamp": "2024-01-02T15:04:05.000Z",
+		}
+	}
+
+	for _, version := range []string{"1", "2"} {
+		message, err := parseWith(t, driver, "text/plain", signer.sign(t, notification(driver.TopicARN), version))
+		require.NoError(t, err, version)
+		assert.Equal(t, "jose@example.com", message.From)
+	}
+	assert.Equal(t, []string{signer.certURL}, signer.requested, "the certificate is fetched once")
+
+	_, err := parseWith(t, driver, "text/plain", signer.sign(t, notification("arn:aws:sns:us-east-1:999:other"), "2"))
+	assert.ErrorIs(t, err, inbound.ErrInvalidSignature)
+
+	t.Run("rejects forged notifications", func(t *testing.T) {
+		var tampered map[string]string
+		require.NoError(t, json.Unmarshal(signer.sign(t, notification(driver.TopicARN), "2"), &tampered))
+		tampered["Message"] = strings.Replace(tampered["Message"], "content", "content ", 1)
+		body, _ := json.Marshal(tampered)
+		_, err := parseWith(t, driver, "text/plain", body)
+		assert.ErrorIs(t, err, inbound.ErrInvalidSignature)
+
+		unsigned, _ := json.Marshal(notification(driver.TopicARN))
+		_, err = parseWith(t, driver, "text/plain", unsigned)
+		assert.ErrorIs(t, err, inbound.ErrInvalidSignature)
+
+		// Signed with another key, claiming SNS's certificate.
+		forger := newSNSSigner(t)
+		forger.certURL = signer.certURL
+		_, err = parseWith(t, driver, "text/plain", forger.sign(t, notification(driver.TopicARN), "2"))
+		assert.ErrorIs(t, err, inbound.ErrInvalidSignature)
+
+		for _, certURL := range []string{
+			"http://sns.us-east-1.amazonaws.com/cert.pem",
+			"https://sns.us-east-1.amazonaws.com.attacker.example.com/cert.pem",
+			"https://attacker.s3.amazonaws.com/cert.pem",
+		} {
+			var message map[string]string
+			require.NoError(t, json.Unmarshal(forger.sign(t, notification(driver.TopicARN), "2"), &message))
+			message["SigningCertURL"] = certURL
+			body, _ := json.Marshal(message)
+			_, err = parseWith(t, driver, "text/plain", body)
+			assert.ErrorIs(t, err, inbound.ErrInvalidSignature, certURL)
+		}
+	})
+
+	t.Run("confirms subscriptions", func(t *testing.T) {
+		confirmation := func(subscribeURL string) map[string]string {
+			return map[string]string{
+				"Type":         "SubscriptionConfirmation",
+				"MessageId":    "m-2",
+				"Token":        "token",
+				"TopicArn":     driver.TopicARN,
+				"Message":      "You have chosen to subscribe",
+				"SubscribeURL": subscribeURL,
+				"Timestamp":    "2024-01-02T15:04:05.000Z",
+			}
+		}
+
+		subscribeURL := "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription&Token=token"
+		_, err := parseWith(t, driver, "text/plain", signer.sign(t, confirmation(subscribeURL), "1"))
+		assert.ErrorIs(t, err, inbound.ErrNoMessage)
+		assert.Contains(t, signer.requested, subscribeURL)
+
+		_, err = parseWith(t, driver, "text/plain", signer.sign(t, confirmation("https://attacker.example.com/confirm"), "1"))
+		assert.ErrorContains(t, err, "refusing SNS subscribe URL")
+
+		unsigned, _ := json.Marshal(confirmation(subscribeURL))
+		_, err = parseWith(t, driver, "text/plain", unsigned)
+		assert.ErrorIs(t, err, inbound.ErrInvalidSignature)
+	})
+}
+
+func TestReceiverStoresAttachmentsAndDispatches(t *testing.T) {
+	disk, err := filesystem.NewLocal(map[string]any{"root": t.TempDir()})
+	require.NoError(t, err)
+
+	dispatcher := events.NewDispatcher()
+	var received *inbound.Message
+	dispatcher.Listen("mail.inbound.received", func(event events.Event) error {
+		received = event.(*inbound.Received).Message
+		return nil
+	})
+
+	receiver := inbound.NewReceiver(disk, "local", dispatcher)
+	receiver.SetDirectory("mail/inbound")
+
+	message, err := inbound.ParseMIME(strings.NewReader(rawEmail))
+	require.NoError(t, err)
+	require.NoError(t, receiver.Receive(context.Background(), message))
+
+	require.NotNil(t, received)
+	attachment := received.Attachments[0]
+	assert.Equal(t, "local", attachment.Disk)
+	assert.True(t, strings.HasPrefix(attachment.Path, "mail/inbound/"))
+	assert.True(t, strings.HasSuffix(attachment.Path, "-log.txt"))
+	assert.Nil(t, attachment.Content())
+
+	stored, err := disk.Get(context.Background(), attachment.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", stored)
+}
+
+func TestReceiverHandler(t *testing.T) {
+	disk, err := filesystem.NewLocal(map[string]any{"root": t.TempDir()})
+	require.NoError(t, err)
+	receiver := inbound.NewReceiver(disk, "local", nil)
+
+	app := fiber.New()
+	handler := receiver.Handler(inbound.MIME{})
+	app.Post("/inbound", func(c *fiber.Ctx) error {
+		return handler(genesyshttp.NewContext(c, nil))
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/inbound", strings.NewReader(rawEmail)))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("POST", "/inbound", strings.NewReader("not an email")))
+	require.NoError(t, err)
+	assert.Equal(t, 422, resp.StatusCode)
+}
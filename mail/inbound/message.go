@@ -0,0 +1,66 @@
+// Package inbound parses incoming email from provider webhooks (Amazon SES,
+// Mailgun, SendGrid) or raw MIME, stores attachments on a filesystem disk and
+// dispatches an event so applications can build reply-by-email features.
+package inbound
+
+import (
+	"time"
+)
+
+// Message is a parsed inbound email.
+type Message struct {
+	MessageID  string
+	InReplyTo  string
+	References []string
+	From       string
+	FromName   string
+	To         []string
+	Cc         []string
+	Subject    string
+	Text       string
+	HTML       string
+
+	// Headers holds the raw headers, when the provider supplies them.
+	Headers map[string][]string
+
+	Attachments []*Attachment
+	ReceivedAt  time.Time
+}
+
+// Attachment is a file attached to an inbound email.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Size        int64
+
+	// Disk and Path locate the stored file once the message has been received.
+	Disk string
+	Path string
+
+	content []byte
+}
+
+// NewAttachment creates an attachment from its content.
+func NewAttachment(filename, contentType string, content []byte) *Attachment {
+	return &Attachment{
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        int64(len(content)),
+		content:     content,
+	}
+}
+
+// Content returns the attachment's content until it has been stored.
+func (a *Attachment) Content() []byte {
+	return a.content
+}
+
+// Received is dispatched after an inbound message is parsed and its attachments stored.
+type Received struct {
+	Message *Message
+}
+
+// Name returns the event name.
+func (e *Received) Name() string {
+	return "mail.inbound.received"
+}
@@ -0,0 +1,169 @@
+package inbound
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+var wordDecoder = new(mime.WordDecoder)
+
+// ParseMIME parses a raw RFC 5322 message.
+func ParseMIME(r io.Reader) (*Message, error) {
+	raw, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("inbound: invalid MIME message: %w", err)
+	}
+
+	message := &Message{
+		MessageID:  strings.TrimSpace(raw.Header.Get("Message-Id")),
+		InReplyTo:  strings.TrimSpace(raw.Header.Get("In-Reply-To")),
+		References: strings.Fields(raw.Header.Get("References")),
+		Subject:    decodeHeader(raw.Header.Get("Subject")),
+		Headers:    map[string][]string(raw.Header),
+		ReceivedAt: time.Now(),
+	}
+	if date, err := raw.Header.Date(); err == nil {
+		message.ReceivedAt = date
+	}
+	message.From, message.FromName = parseAddress(raw.Header.Get("From"))
+	message.To = parseAddressList(raw.Header.Get("To"))
+	message.Cc = parseAddressList(raw.Header.Get("Cc"))
+
+	header := textproto.MIMEHeader(raw.Header)
+	if err := walkPart(message, header, decodeTransfer(header, raw.Body)); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// walkPart collects text bodies and attachments from a MIME part.
+func walkPart(message *Message, header textproto.MIMEHeader, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("inbound: invalid multipart body: %w", err)
+			}
+			if err := walkPart(message, part.Header, decodeTransfer(part.Header, part)); err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("inbound: failed to read MIME part: %w", err)
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	filename = decodeHeader(filename)
+
+	switch {
+	case disposition == "attachment" || filename != "":
+		message.Attachments = append(message.Attachments, NewAttachment(filename, mediaType, content))
+	case mediaType == "text/plain" && message.Text == "":
+		message.Text = string(content)
+	case mediaType == "text/html" && message.HTML == "":
+		message.HTML = string(content)
+	}
+	return nil
+}
+
+// decodeTransfer decodes a part's Content-Transfer-Encoding.
+func decodeTransfer(header textproto.MIMEHeader, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(header.Get("Content-Transfer-Encoding"))) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &newlineStripper{r: body})
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// newlineStripper removes line breaks from base64 bodies.
+type newlineStripper struct {
+	r io.Reader
+}
+
+func (s *newlineStripper) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	kept := p[:0]
+	for _, b := range p[:n] {
+		if b != '\r' && b != '\n' {
+			kept = append(kept, b)
+		}
+	}
+	return len(kept), err
+}
+
+// decodeHeader decodes RFC 2047 encoded words.
+func decodeHeader(value string) string {
+	decoded, err := wordDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// parseAddress returns the address and display name of a single address.
+func parseAddress(value string) (address, name string) {
+	parsed, err := (&mail.AddressParser{WordDecoder: wordDecoder}).Parse(value)
+	if err != nil {
+		return strings.TrimSpace(value), ""
+	}
+	return parsed.Address, parsed.Name
+}
+
+// parseAddressList returns the addresses in a comma-separated list.
+func parseAddressList(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	parsed, err := (&mail.AddressParser{WordDecoder: wordDecoder}).ParseList(value)
+	if err != nil {
+		addresses := make([]string, 0)
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				addresses = append(addresses, part)
+			}
+		}
+		return addresses
+	}
+
+	addresses := make([]string, 0, len(parsed))
+	for _, addr := range parsed {
+		addresses = append(addresses, addr.Address)
+	}
+	return addresses
+}
+
+// parseHeaderBlock parses a raw header block, as sent by SendGrid.
+func parseHeaderBlock(block string) map[string][]string {
+	block = strings.TrimRight(block, "\r\n") + "\r\n\r\n"
+	header, _ := textproto.NewReader(bufio.NewReader(strings.NewReader(block))).ReadMIMEHeader()
+	return map[string][]string(header)
+}
@@ -0,0 +1,100 @@
+package inbound
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/events"
+)
+
+var unsafeFilename = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Receiver stores inbound message attachments and dispatches Received events.
+type Receiver struct {
+	disk      contracts.Filesystem
+	diskName  string
+	directory string
+	events    *events.Dispatcher
+}
+
+// NewReceiver creates a receiver that stores attachments on disk and
+// dispatches to events. diskName is recorded on stored attachments.
+func NewReceiver(disk contracts.Filesystem, diskName string, dispatcher *events.Dispatcher) *Receiver {
+	return &Receiver{
+		disk:      disk,
+		diskName:  diskName,
+		directory: "inbound",
+		events:    dispatcher,
+	}
+}
+
+// SetDirectory sets the directory attachments are stored under.
+func (r *Receiver) SetDirectory(directory string) {
+	r.directory = strings.Trim(directory, "/")
+}
+
+// Receive stores the message's attachments and dispatches a Received event.
+func (r *Receiver) Receive(ctx context.Context, message *Message) error {
+	for _, attachment := range message.Attachments {
+		if attachment.Path != "" {
+			continue
+		}
+		if r.disk == nil {
+			return fmt.Errorf("inbound: no disk configured for attachments")
+		}
+
+		stored := r.attachmentPath(attachment.Filename)
+		if err := r.disk.PutBytes(ctx, stored, attachment.content); err != nil {
+			return fmt.Errorf("inbound: failed to store attachment %q: %w", attachment.Filename, err)
+		}
+		attachment.Disk = r.diskName
+		attachment.Path = stored
+		attachment.content = nil
+	}
+
+	if r.events == nil {
+		return nil
+	}
+	return r.events.Dispatch(&Received{Message: message})
+}
+
+// attachmentPath returns a unique, date-partitioned path for an attachment.
+func (r *Receiver) attachmentPath(filename string) string {
+	name := unsafeFilename.ReplaceAllString(path.Base(filename), "_")
+	if name == "" || name == "." || name == "_" {
+		name = "attachment"
+	}
+
+	b := make([]byte, 8)
+	rand.Read(b)
+	return path.Join(r.directory, time.Now().Format("2006/01/02"), hex.EncodeToString(b)+"-"+name)
+}
+
+// Handler returns a route handler that parses webhooks with driver and
+// receives the message. It responds 401 to requests that fail verification.
+func (r *Receiver) Handler(driver Driver) contracts.HandlerFunc {
+	return func(ctx contracts.Context) error {
+		message, err := driver.Parse(ctx.Request())
+		switch {
+		case errors.Is(err, ErrNoMessage):
+			return ctx.Status(200).String("OK")
+		case errors.Is(err, ErrInvalidSignature):
+			return ctx.Status(401).String("Invalid signature")
+		case err != nil:
+			return ctx.Status(422).String(err.Error())
+		}
+
+		if err := r.Receive(ctx.Request().Context(), message); err != nil {
+			return err
+		}
+		return ctx.Status(200).String("OK")
+	}
+}
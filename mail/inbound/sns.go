@@ -0,0 +1,132 @@
+package inbound
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// snsCertHost matches the hosts SNS serves signing certificates from.
+var snsCertHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// snsCerts caches signing certificates by URL; SNS rotates them rarely.
+var snsCerts sync.Map
+
+// snsMessage is an SNS HTTP(S) delivery.
+type snsMessage struct {
+	Type             string `json:"Type"`
+	MessageId        string `json:"MessageId"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	Token            string `json:"Token"`
+	SubscribeURL     string `json:"SubscribeURL"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+}
+
+// stringToSign builds the canonical string SNS signs for the message type:
+// its signed fields, as name and value lines, in alphabetical order.
+func (m *snsMessage) stringToSign() string {
+	var fields []string
+	if m.Type == "Notification" {
+		fields = []string{"Message", m.Message, "MessageId", m.MessageId}
+		if m.Subject != "" {
+			fields = append(fields, "Subject", m.Subject)
+		}
+		fields = append(fields, "Timestamp", m.Timestamp)
+	} else {
+		fields = []string{
+			"Message", m.Message, "MessageId", m.MessageId, "SubscribeURL", m.SubscribeURL,
+			"Timestamp", m.Timestamp, "Token", m.Token,
+		}
+	}
+	fields = append(fields, "TopicArn", m.TopicArn, "Type", m.Type)
+	return strings.Join(fields, "\n") + "\n"
+}
+
+// verify checks the message's signature with SignatureVersion 1 (SHA1) or
+// 2 (SHA256) against the certificate at SigningCertURL, which must be an
+// HTTPS URL on an SNS host.
+func (m *snsMessage) verify(client *http.Client) error {
+	var (
+		hash   crypto.Hash
+		digest []byte
+	)
+	switch m.SignatureVersion {
+	case "1":
+		sum := sha1.Sum([]byte(m.stringToSign()))
+		hash, digest = crypto.SHA1, sum[:]
+	case "2":
+		sum := sha256.Sum256([]byte(m.stringToSign()))
+		hash, digest = crypto.SHA256, sum[:]
+	default:
+		return ErrInvalidSignature
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(m.Signature)
+	if err != nil || len(signature) == 0 {
+		return ErrInvalidSignature
+	}
+	key, err := snsSigningKey(client, m.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	if rsa.VerifyPKCS1v15(key, hash, digest, signature) != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// snsSigningKey returns the public key of the certificate at certURL.
+func snsSigningKey(client *http.Client, certURL string) (*rsa.PublicKey, error) {
+	u, err := url.Parse(certURL)
+	if err != nil || u.Scheme != "https" || !snsCertHost.MatchString(u.Hostname()) || u.Port() != "" ||
+		!strings.HasSuffix(u.Path, ".pem") {
+		return nil, ErrInvalidSignature
+	}
+	if key, ok := snsCerts.Load(u.String()); ok {
+		return key.(*rsa.PublicKey), nil
+	}
+
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("inbound: failed to fetch SNS signing certificate: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("inbound: SNS signing certificate returned %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, fmt.Errorf("inbound: failed to fetch SNS signing certificate: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("inbound: invalid SNS signing certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("inbound: invalid SNS signing certificate: %w", err)
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("inbound: SNS signing certificate has no RSA key")
+	}
+	snsCerts.Store(u.String(), key)
+	return key, nil
+}
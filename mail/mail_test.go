@@ -0,0 +1,252 @@
+package mail_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/genesysflow/go-genesys/mail"
+	"github.com/genesysflow/go-genesys/queue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+type welcomeEmail struct {
+	To string
+}
+
+func (m welcomeEmail) Build() (*mail.Message, error) {
+	return &mail.Message{
+		To:      []string{m.To},
+		Subject: "Welcome",
+		Text:    "Hello!",
+	}, nil
+}
+
+type queuedWelcomeEmail struct {
+	mail.Queueable
+	welcomeEmail
+}
+
+type failingTransport struct{}
+
+func (failingTransport) Send(ctx context.Context, message *mail.Message) error {
+	return errors.New("connection refused")
+}
+
+func newMailer() (*mail.Mailer, *mail.ArrayTransport, *mail.MemorySentLog) {
+	transport := mail.NewArrayTransport()
+	sentLog := mail.NewMemorySentLog()
+	mailer := mail.NewMailer(transport, "App <hello@example.com>")
+	mailer.SetSentLog(sentLog)
+	return mailer, transport, sentLog
+}
+
+func TestMailerSendsImmediately(t *testing.T) {
+	mailer, transport, sentLog := newMailer()
+
+	id, err := mailer.Send(context.Background(), welcomeEmail{To: "jane@example.com"})
+	require.NoError(t, err)
+
+	messages := transport.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "App <hello@example.com>", messages[0].From)
+	assert.Equal(t, id, messages[0].MessageID)
+	assert.Contains(t, id, "@example.com>")
+
+	entries, _ := sentLog.Find(id)
+	require.Len(t, entries, 1)
+	assert.Equal(t, mail.StatusSent, entries[0].Status)
+	assert.Equal(t, "jane@example.com", entries[0].Recipient)
+}
+
+func TestMailerQueuesShouldQueueMailables(t *testing.T) {
+	mailer, transport, sentLog := newMailer()
+	q := queue.NewMemoryQueue()
+	mailer.SetQueue(q)
+
+	id, err := mailer.Send(context.Background(), queuedWelcomeEmail{
+		Queueable:    mail.Queueable{Queue: "mail"},
+		welcomeEmail: welcomeEmail{To: "jane@example.com"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, transport.Messages())
+	assert.Equal(t, 1, q.SizeOf("mail"))
+
+	entries, _ := sentLog.ForRecipient("jane@example.com")
+	require.Len(t, entries, 1)
+	assert.Equal(t, mail.StatusQueued, entries[0].Status)
+
+	job, err := q.PopFrom(context.Background(), "mail")
+	require.NoError(t, err)
+	require.NoError(t, job.Handle())
+
+	assert.Len(t, transport.Messages(), 1)
+	entries, _ = sentLog.Find(id)
+	assert.Equal(t, mail.StatusSent, entries[0].Status)
+}
+
+func newConnection(t *testing.T) contracts.Connection {
+	t.Helper()
+	manager := database.NewManager(database.Config{
+		Default: "default",
+		Connections: map[string]database.ConnectionConfig{
+			"default": {Driver: "sqlite", Database: filepath.Join(t.TempDir(), "mail.db")},
+		},
+	})
+	t.Cleanup(func() { manager.Close() })
+	return manager.Connection()
+}
+
+func TestMailerQueuesThroughPersistentQueue(t *testing.T) {
+	conn := newConnection(t)
+	_, err := conn.Exec(`CREATE TABLE jobs (id INTEGER PRIMARY KEY AUTOINCREMENT, queue VARCHAR(255) NOT NULL,
+payload TEXT NOT NULL, attempts INTEGER NOT NULL DEFAULT 0, reserved_at INTEGER, available_at INTEGER NOT NULL,
+created_at INTEGER NOT NULL)`)
+	require.NoError(t, err)
+	q := queue.NewDatabaseQueue(conn, "")
+
+	// The web process queues mail through its "newsletter" mailer.
+	web, _, _ := newMailer()
+	mails := mail.NewManager()
+	mails.Register("newsletter", web)
+	web.SetQueue(q)
+	id, err := web.Later(time.Millisecond, welcomeEmail{To: "jane@example.com"})
+	require.NoError(t, err)
+
+	// The worker decodes the job and sends it through its own mailer of
+	// the same name.
+	worker, transport, sentLog := newMailer()
+	workerMails := mail.NewManager()
+	workerMails.Register("newsletter", worker)
+	workerMails.Register("log", mail.NewMailer(mail.NewArrayTransport(), ""))
+	mail.SetManager(workerMails)
+	t.Cleanup(func() { mail.SetManager(nil) })
+
+	var job queue.Job
+	require.Eventually(t, func() bool {
+		job, err = q.Pop(context.Background())
+		return job != nil || err != nil
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, job.Handle())
+
+	messages := transport.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, id, messages[0].MessageID)
+	entries, _ := sentLog.Find(id)
+	require.Len(t, entries, 1)
+	assert.Equal(t, mail.StatusSent, entries[0].Status)
+}
+
+func TestMailerSendNowBypassesQueue(t *testing.T) {
+	mailer, transport, _ := newMailer()
+	q := queue.NewMemoryQueue()
+	mailer.SetQueue(q)
+
+	_, err := mailer.SendNow(context.Background(), queuedWelcomeEmail{welcomeEmail: welcomeEmail{To: "jane@example.com"}})
+	require.NoError(t, err)
+	assert.Len(t, transport.Messages(), 1)
+	assert.Equal(t, 0, q.Size())
+}
+
+func TestMailerLater(t *testing.T) {
+	mailer, transport, _ := newMailer()
+
+	_, err := mailer.Later(time.Minute, welcomeEmail{To: "jane@example.com"})
+	assert.Error(t, err, "Later requires a queue")
+
+	mailer.SetQueue(queue.NewSyncQueue())
+	_, err = mailer.Later(time.Minute, welcomeEmail{To: "jane@example.com"})
+	assert.Error(t, err, "the sync queue cannot delay jobs")
+
+	q := queue.NewMemoryQueue()
+	mailer.SetQueue(q)
+	_, err = mailer.Later(30*time.Millisecond, welcomeEmail{To: "jane@example.com"})
+	require.NoError(t, err)
+
+	job, _ := q.Pop(context.Background())
+	assert.Nil(t, job)
+
+	assert.Eventually(t, func() bool {
+		job, _ = q.Pop(context.Background())
+		return job != nil
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, job.Handle())
+	assert.Len(t, transport.Messages(), 1)
+}
+
+func TestMailerRecordsFailures(t *testing.T) {
+	sentLog := mail.NewMemorySentLog()
+	mailer := mail.NewMailer(failingTransport{}, "hello@example.com")
+	mailer.SetSentLog(sentLog)
+
+	id, err := mailer.Send(context.Background(), welcomeEmail{To: "jane@example.com"})
+	assert.Error(t, err)
+
+	entries, _ := sentLog.Find(id)
+	require.Len(t, entries, 1)
+	assert.Equal(t, mail.StatusFailed, entries[0].Status)
+	assert.Equal(t, "connection refused", entries[0].Error)
+}
+
+func TestDatabaseSentLog(t *testing.T) {
+	conn := newConnection(t)
+	_, err := conn.Exec(`CREATE TABLE mail_sent_messages (id INTEGER PRIMARY KEY AUTOINCREMENT,
+message_id VARCHAR(255) NOT NULL, recipient VARCHAR(255) NOT NULL, subject VARCHAR(998) NOT NULL,
+status VARCHAR(16) NOT NULL, error TEXT, updated_at TIMESTAMP NOT NULL)`)
+	require.NoError(t, err)
+
+	// The web process queues the message; a worker sends it.
+	web, worker := mail.NewDatabaseSentLog(conn, ""), mail.NewDatabaseSentLog(conn, "")
+	mailer := mail.NewMailer(failingTransport{}, "hello@example.com")
+	mailer.SetSentLog(web)
+	q := queue.NewMemoryQueue()
+	mailer.SetQueue(q)
+	id, err := mailer.Send(context.Background(), queuedWelcomeEmail{welcomeEmail: welcomeEmail{To: "jane@example.com"}})
+	require.NoError(t, err)
+
+	mailer.SetSentLog(worker)
+	job, err := q.Pop(context.Background())
+	require.NoError(t, err)
+	assert.Error(t, job.Handle())
+
+	require.NoError(t, worker.Record(mail.SentMessage{MessageID: "<other@example.com>", Recipient: "jane@example.com", Status: mail.StatusSent}))
+
+	entries, err := web.Find(id)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, mail.StatusFailed, entries[0].Status)
+	assert.Equal(t, "connection refused", entries[0].Error)
+	assert.Equal(t, "Welcome", entries[0].Subject)
+	assert.False(t, entries[0].UpdatedAt.IsZero())
+
+	entries, err = web.ForRecipient("jane@example.com")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, id, entries[0].MessageID)
+	assert.Equal(t, "<other@example.com>", entries[1].MessageID)
+
+	entries, err = web.Find("<missing@example.com>")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestMailerRequiresRecipients(t *testing.T) {
+	mailer, _, _ := newMailer()
+
+	_, err := mailer.Send(context.Background(), noRecipients{})
+	assert.Error(t, err)
+}
+
+type noRecipients struct{}
+
+func (noRecipients) Build() (*mail.Message, error) {
+	return &mail.Message{Subject: "Nobody"}, nil
+}
@@ -0,0 +1,30 @@
+package mail
+
+// Mailable builds an email message.
+type Mailable interface {
+	// Build returns the message to send.
+	Build() (*Message, error)
+}
+
+// ShouldQueue is implemented by mailables that are sent through the queue
+// instead of immediately. Embed Queueable to implement it.
+type ShouldQueue interface {
+	// OnQueue returns the queue name to push to, or "" for the default queue.
+	OnQueue() string
+}
+
+// Queueable marks a mailable as queued. Embed it in a mailable struct:
+//
+//	type WelcomeEmail struct {
+//	    mail.Queueable
+//	    User *User
+//	}
+type Queueable struct {
+	// Queue is the named queue to push to. Empty uses the default queue.
+	Queue string
+}
+
+// OnQueue returns the queue name.
+func (q Queueable) OnQueue() string {
+	return q.Queue
+}
@@ -0,0 +1,367 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/queue"
+)
+
+// Mailer sends mailables through a transport, queueing those that implement ShouldQueue.
+type Mailer struct {
+	name      string
+	transport Transport
+	from      string
+	queue     queue.Queue
+	sentLog   SentLog
+	markdown  *Markdown
+	views     ViewRenderer
+	disks     contracts.FilesystemFactory
+	previews  map[string]func() Mailable
+	mu        sync.RWMutex
+}
+
+// NewMailer creates a new mailer. from is used when a message has no sender.
+func NewMailer(transport Transport, from string) *Mailer {
+	return &Mailer{
+		transport: transport,
+		from:      from,
+		markdown:  NewMarkdown(DefaultTheme()),
+		previews:  make(map[string]func() Mailable),
+	}
+}
+
+// SetQueue sets the queue used for ShouldQueue mailables and Later.
+// Without a queue, queued mailables are sent immediately.
+func (m *Mailer) SetQueue(q queue.Queue) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queue = q
+}
+
+// SetSentLog sets the log that records the delivery status of each message.
+func (m *Mailer) SetSentLog(log SentLog) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sentLog = log
+}
+
+// SetMarkdown sets the renderer used for markdown messages.
+func (m *Mailer) SetMarkdown(markdown *Markdown) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.markdown = markdown
+}
+
+// SetViews sets the renderer for messages with a View or TextView.
+func (m *Mailer) SetViews(views ViewRenderer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.views = views
+}
+
+// Views returns the renderer for message views, or nil if none is set.
+func (m *Mailer) Views() ViewRenderer {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.views
+}
+
+// SetDisks sets the disks that attachments created with AttachFromDisk are
+// read from.
+func (m *Mailer) SetDisks(disks contracts.FilesystemFactory) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.disks = disks
+}
+
+// Markdown returns the renderer used for markdown messages.
+func (m *Mailer) Markdown() *Markdown {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.markdown
+}
+
+// SentLog returns the sent message log, or nil if none is set.
+func (m *Mailer) SentLog() SentLog {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.sentLog
+}
+
+// Send sends a mailable and returns its message ID.
+// Mailables implementing ShouldQueue are pushed onto the queue instead.
+func (m *Mailer) Send(ctx context.Context, mailable Mailable) (string, error) {
+	message, err := m.build(mailable)
+	if err != nil {
+		return "", err
+	}
+
+	if q := m.getQueue(); q != nil {
+		if queued, ok := mailable.(ShouldQueue); ok {
+			return message.MessageID, m.push(q, queued.OnQueue(), 0, message)
+		}
+	}
+
+	return message.MessageID, m.deliver(ctx, message)
+}
+
+// SendNow sends a mailable immediately, even if it implements ShouldQueue.
+func (m *Mailer) SendNow(ctx context.Context, mailable Mailable) (string, error) {
+	message, err := m.build(mailable)
+	if err != nil {
+		return "", err
+	}
+	return message.MessageID, m.deliver(ctx, message)
+}
+
+// Later queues a mailable to be sent after the given delay.
+// The mailer's queue must support delayed jobs.
+func (m *Mailer) Later(delay time.Duration, mailable Mailable) (string, error) {
+	q := m.getQueue()
+	if q == nil {
+		return "", fmt.Errorf("mail: a queue is required to send mail later")
+	}
+
+	message, err := m.build(mailable)
+	if err != nil {
+		return "", err
+	}
+
+	queueName := ""
+	if queued, ok := mailable.(ShouldQueue); ok {
+		queueName = queued.OnQueue()
+	}
+	return message.MessageID, m.push(q, queueName, delay, message)
+}
+
+// build builds a mailable, applying the default sender and a message ID.
+func (m *Mailer) build(mailable Mailable) (*Message, error) {
+	message, err := m.render(mailable)
+	if err != nil {
+		return nil, err
+	}
+	if message.From == "" {
+		message.From = m.from
+	}
+	if len(message.Recipients()) == 0 {
+		return nil, fmt.Errorf("mail: message has no recipients")
+	}
+	if message.MessageID == "" {
+		message.MessageID = newMessageID(message.From)
+	}
+	return message, nil
+}
+
+// render builds a mailable and renders its markdown template and views, if any.
+func (m *Mailer) render(mailable Mailable) (*Message, error) {
+	message, err := mailable.Build()
+	if err != nil {
+		return nil, fmt.Errorf("mail: failed to build message: %w", err)
+	}
+
+	if message.Markdown != "" && (message.HTML == "" || message.Text == "") {
+		htmlBody, textBody, err := m.Markdown().Render(message.Markdown, message.Data)
+		if err != nil {
+			return nil, err
+		}
+		if message.HTML == "" {
+			message.HTML = htmlBody
+		}
+		if message.Text == "" {
+			message.Text = textBody
+		}
+	}
+
+	if (message.View != "" && message.HTML == "") || (message.TextView != "" && message.Text == "") {
+		views := m.Views()
+		if views == nil {
+			return nil, fmt.Errorf("mail: no views are set to render the message")
+		}
+		if message.View != "" && message.HTML == "" {
+			if message.HTML, err = views.Render(message.View, message.Data); err != nil {
+				return nil, err
+			}
+		}
+		if message.TextView != "" && message.Text == "" {
+			if message.Text, err = views.Render(message.TextView, message.Data); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return message, nil
+}
+
+// push pushes a send job onto the queue, optionally on a named queue or after a delay.
+func (m *Mailer) push(q queue.Queue, queueName string, delay time.Duration, message *Message) error {
+	m.mu.RLock()
+	job := &SendMailJob{Message: message, Mailer: m.name, mailer: m}
+	m.mu.RUnlock()
+
+	// Record before pushing: the sync driver delivers during Push.
+	m.record(message, StatusQueued, nil)
+
+	var err error
+	switch {
+	case delay > 0:
+		delayed, ok := q.(queue.DelayedQueue)
+		if !ok {
+			err = fmt.Errorf("mail: queue does not support delayed jobs")
+			break
+		}
+		if queueName == "" {
+			queueName = queue.DefaultQueue
+		}
+		err = delayed.LaterOn(queueName, delay, job)
+	case queueName != "":
+		named, ok := q.(queue.NamedQueue)
+		if !ok {
+			err = fmt.Errorf("mail: queue does not support named queues")
+			break
+		}
+		err = named.PushOn(queueName, job)
+	default:
+		err = q.Push(job)
+	}
+
+	if err != nil {
+		m.record(message, StatusFailed, err)
+	}
+	return err
+}
+
+// deliver sends a message through the transport and records the outcome.
+func (m *Mailer) deliver(ctx context.Context, message *Message) error {
+	outgoing, err := m.readAttachments(ctx, message)
+	if err == nil {
+		err = m.transport.Send(ctx, outgoing)
+	}
+	if err != nil {
+		m.record(message, StatusFailed, err)
+		return fmt.Errorf("mail: failed to send message %s: %w", message.MessageID, err)
+	}
+	m.record(message, StatusSent, nil)
+	return nil
+}
+
+// readAttachments returns the message with its disk attachments read into
+// memory, leaving the original untouched.
+func (m *Mailer) readAttachments(ctx context.Context, message *Message) (*Message, error) {
+	var pending bool
+	for _, attachment := range message.Attachments {
+		if attachment.Content == nil && attachment.Path != "" {
+			pending = true
+		}
+	}
+	if !pending {
+		return message, nil
+	}
+
+	m.mu.RLock()
+	disks := m.disks
+	m.mu.RUnlock()
+	if disks == nil {
+		return nil, fmt.Errorf("mail: no disks are set to read attachments from")
+	}
+
+	outgoing := *message
+	outgoing.Attachments = make([]Attachment, len(message.Attachments))
+	for i, attachment := range message.Attachments {
+		if attachment.Content == nil && attachment.Path != "" {
+			disk, err := resolveDisk(disks, attachment.Disk)
+			if err != nil {
+				return nil, err
+			}
+			if attachment.Content, err = disk.GetBytes(ctx, attachment.Path); err != nil {
+				return nil, fmt.Errorf("mail: failed to read attachment %s: %w", attachment.Path, err)
+			}
+		}
+		outgoing.Attachments[i] = attachment
+	}
+	return &outgoing, nil
+}
+
+// resolveDisk returns a disk by name, turning the panic the filesystem
+// manager raises for unknown disks into an error.
+func resolveDisk(disks contracts.FilesystemFactory, name string) (disk contracts.Filesystem, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("mail: attachment disk %q: %v", name, r)
+		}
+	}()
+	if name == "" {
+		return disks.Disk(), nil
+	}
+	return disks.Disk(name), nil
+}
+
+// record writes a log entry for every recipient of a message.
+func (m *Mailer) record(message *Message, status string, err error) {
+	log := m.SentLog()
+	if log == nil {
+		return
+	}
+
+	entry := SentMessage{
+		MessageID: message.MessageID,
+		Subject:   message.Subject,
+		Status:    status,
+		UpdatedAt: time.Now(),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	for _, recipient := range message.Recipients() {
+		entry.Recipient = recipient
+		log.Record(entry)
+	}
+}
+
+func (m *Mailer) getQueue() queue.Queue {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.queue
+}
+
+// SendMailJob is the queue job that delivers a queued message. It carries
+// the name its mailer is registered under, so a worker that decoded it from
+// a persistent queue sends it through the mailer of that name in the
+// manager set with SetManager.
+type SendMailJob struct {
+	Message *Message
+	Mailer  string
+
+	// mailer is the sending mailer, kept by in-process queues.
+	mailer *Mailer
+}
+
+// Handle delivers the message.
+func (j *SendMailJob) Handle() error {
+	mailer := j.mailer
+	if mailer == nil {
+		manager := jobManager.Load()
+		if manager == nil {
+			return fmt.Errorf("mail: job for message %s has no mail manager; call mail.SetManager in the worker", j.Message.MessageID)
+		}
+		var err error
+		if mailer, err = manager.Mailer(j.Mailer); err != nil {
+			return fmt.Errorf("mail: job for message %s: %w", j.Message.MessageID, err)
+		}
+	}
+	return mailer.deliver(context.Background(), j.Message)
+}
+
+// jobManager is the manager queued mail is delivered through.
+var jobManager atomic.Pointer[Manager]
+
+// SetManager sets the manager whose mailers deliver queued mail that a
+// worker decoded from a queue, and registers SendMailJob for decoding.
+// Worker processes must call it, as MailServiceProvider does.
+func SetManager(manager *Manager) {
+	queue.RegisterJob(&SendMailJob{})
+	jobManager.Store(manager)
+}
@@ -0,0 +1,77 @@
+package mail
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Manager holds named mailers, such as an SMTP mailer for transactional
+// mail and an SES mailer for newsletters.
+type Manager struct {
+	mailers       map[string]*Mailer
+	defaultMailer string
+	mu            sync.RWMutex
+}
+
+// NewManager creates a new mail manager.
+func NewManager() *Manager {
+	return &Manager{
+		mailers:       make(map[string]*Mailer),
+		defaultMailer: "log",
+	}
+}
+
+// Mailer returns a mailer by name, or the default mailer.
+func (m *Manager) Mailer(name ...string) (*Mailer, error) {
+	mailerName := m.DefaultMailer()
+	if len(name) > 0 && name[0] != "" {
+		mailerName = name[0]
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	mailer, ok := m.mailers[mailerName]
+	if !ok {
+		return nil, fmt.Errorf("mailer [%s] not found", mailerName)
+	}
+	return mailer, nil
+}
+
+// Register registers a mailer. Queued mail records the name, so workers
+// send it through the mailer of the same name.
+func (m *Manager) Register(name string, mailer *Mailer) {
+	mailer.mu.Lock()
+	mailer.name = name
+	mailer.mu.Unlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mailers[name] = mailer
+}
+
+// Names returns the names of the registered mailers, sorted.
+func (m *Manager) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.mailers))
+	for name := range m.mailers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// DefaultMailer returns the name of the default mailer.
+func (m *Manager) DefaultMailer() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.defaultMailer
+}
+
+// SetDefaultMailer sets the name of the default mailer.
+func (m *Manager) SetDefaultMailer(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.defaultMailer = name
+}
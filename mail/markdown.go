@@ -0,0 +1,388 @@
+package mail
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Markdown renders markdown email templates to themed HTML and plain text.
+//
+// Templates are Go text/template sources whose output is markdown. They can
+// use the button, panel and table components:
+//
+//	# Order shipped
+//
+//	Hi {{ .Name }}, your order is on its way.
+//
+//	{{ button .TrackingURL "Track order" }}
+//
+//	{{ panel "Questions? Just reply to this email." }}
+//
+//	{{ table }}
+//	| Item | Qty |
+//	| ---- | --- |
+//	| Mug  | 2   |
+//	{{ endtable }}
+type Markdown struct {
+	theme     Theme
+	templates map[string]string
+	mu        sync.RWMutex
+}
+
+// NewMarkdown creates a markdown renderer with the given theme.
+func NewMarkdown(theme Theme) *Markdown {
+	return &Markdown{
+		theme:     theme,
+		templates: make(map[string]string),
+	}
+}
+
+// Theme returns the renderer's theme.
+func (m *Markdown) Theme() Theme {
+	return m.theme
+}
+
+// AddTemplate registers a named template source.
+func (m *Markdown) AddTemplate(name, source string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.templates[name] = source
+}
+
+// LoadDirectory registers every .md file under dir, named by its path
+// relative to dir without the extension (e.g. "orders/shipped").
+func (m *Markdown) LoadDirectory(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".md" {
+			return err
+		}
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		m.AddTemplate(filepath.ToSlash(strings.TrimSuffix(rel, ".md")), string(source))
+		return nil
+	})
+}
+
+// Render renders a registered template.
+func (m *Markdown) Render(name string, data any) (htmlBody, textBody string, err error) {
+	m.mu.RLock()
+	source, ok := m.templates[name]
+	m.mu.RUnlock()
+	if !ok {
+		return "", "", fmt.Errorf("mail: markdown template [%s] not found", name)
+	}
+	return m.RenderString(source, data)
+}
+
+// RenderString renders a template source.
+func (m *Markdown) RenderString(source string, data any) (htmlBody, textBody string, err error) {
+	r := &markdownRender{theme: m.theme, html: true}
+	body, err := r.execute(source, data)
+	if err != nil {
+		return "", "", err
+	}
+	htmlBody = r.layout(r.toHTML(body))
+
+	r = &markdownRender{theme: m.theme}
+	body, err = r.execute(source, data)
+	if err != nil {
+		return "", "", err
+	}
+	return htmlBody, toText(body), nil
+}
+
+// markdownRender holds the state of a single render. Components emit
+// placeholders that survive markdown conversion and are swapped for their
+// HTML afterwards, so template data is always escaped.
+type markdownRender struct {
+	theme      Theme
+	html       bool
+	components []string
+}
+
+func (r *markdownRender) execute(source string, data any) (string, error) {
+	tmpl, err := template.New("mail").Funcs(template.FuncMap{
+		"button":   r.button,
+		"panel":    r.panel,
+		"table":    r.table,
+		"endtable": r.endTable,
+	}).Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("mail: invalid markdown template: %w", err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("mail: failed to render markdown template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// placeholder stores component HTML and returns its placeholder.
+func (r *markdownRender) placeholder(markup string) string {
+	r.components = append(r.components, markup)
+	return fmt.Sprintf("\x00%d\x00", len(r.components)-1)
+}
+
+// button renders a call-to-action link. An optional color overrides the theme.
+func (r *markdownRender) button(url, label string, color ...string) string {
+	if !r.html {
+		return label + ": " + url
+	}
+	background := r.theme.PrimaryColor
+	if len(color) > 0 && color[0] != "" {
+		background = color[0]
+	}
+	return r.placeholder(fmt.Sprintf(
+		`<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="margin: 24px 0;"><tr><td align="center">`+
+			`<a href="%s" target="_blank" rel="noopener" style="display: inline-block; padding: 10px 18px; border-radius: 4px; background-color: %s; color: %s; text-decoration: none; font-weight: bold;">%s</a>`+
+			`</td></tr></table>`,
+		html.EscapeString(url), html.EscapeString(background), html.EscapeString(r.theme.ButtonTextColor), html.EscapeString(label),
+	))
+}
+
+// panel renders highlighted text. Inline markdown is supported.
+func (r *markdownRender) panel(text string) string {
+	if !r.html {
+		return text
+	}
+	return r.placeholder(fmt.Sprintf(
+		`<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="margin: 16px 0; border-left: 4px solid %s; background-color: %s;"><tr><td style="padding: 16px;">%s</td></tr></table>`,
+		html.EscapeString(r.theme.PrimaryColor), html.EscapeString(r.theme.PanelBackground), inline(text),
+	))
+}
+
+// table and endTable wrap a markdown table so it is rendered full width.
+func (r *markdownRender) table() string {
+	if !r.html {
+		return ""
+	}
+	return r.placeholder(`<div class="table">`)
+}
+
+func (r *markdownRender) endTable() string {
+	if !r.html {
+		return ""
+	}
+	return r.placeholder(`</div>`)
+}
+
+// layout wraps the body in the themed email layout.
+func (r *markdownRender) layout(body string) string {
+	t := r.theme
+	header := html.EscapeString(t.AppName)
+	if t.LogoURL != "" {
+		header = fmt.Sprintf(`<img src="%s" alt="%s" style="max-height: 48px;">`, html.EscapeString(t.LogoURL), html.EscapeString(t.AppName))
+	}
+
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1.0">
+</head>
+<body style="margin: 0; padding: 0; background-color: %[1]s; font-family: %[2]s; color: %[3]s;">
+<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="background-color: %[1]s;">
+<tr><td align="center" style="padding: 24px 0; font-size: 19px; font-weight: bold;">%[4]s</td></tr>
+<tr><td align="center">
+<table role="presentation" width="570" cellpadding="0" cellspacing="0" style="background-color: %[5]s; border: 1px solid %[6]s; border-radius: 2px;">
+<tr><td style="padding: 32px; font-size: 16px; line-height: 1.5;">
+%[7]s
+</td></tr>
+</table>
+</td></tr>
+<tr><td align="center" style="padding: 24px; font-size: 12px; color: %[8]s;">%[9]s</td></tr>
+</table>
+</body>
+</html>
+`,
+		html.EscapeString(t.BackgroundColor), html.EscapeString(t.FontFamily), html.EscapeString(t.TextColor),
+		header, html.EscapeString(t.ContentBackground), html.EscapeString(t.BorderColor),
+		body, html.EscapeString(t.MutedColor), inline(t.Footer),
+	)
+}
+
+var (
+	placeholderPattern = regexp.MustCompile("\x00(\\d+)\x00")
+	headingPattern     = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	orderedPattern     = regexp.MustCompile(`^\d+\.\s+`)
+	linkPattern        = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	boldPattern        = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	italicPattern      = regexp.MustCompile(`(^|[^*\w])[*_]([^*_]+)[*_]`)
+	codePattern        = regexp.MustCompile("`([^`]+)`")
+)
+
+// toHTML converts markdown to HTML and substitutes component placeholders.
+func (r *markdownRender) toHTML(source string) string {
+	var out strings.Builder
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); {
+		line := strings.TrimSpace(lines[i])
+
+		switch {
+		case line == "":
+			i++
+
+		case placeholderPattern.MatchString(line) && placeholderPattern.ReplaceAllString(line, "") == "":
+			out.WriteString(line + "\n")
+			i++
+
+		case headingPattern.MatchString(line):
+			m := headingPattern.FindStringSubmatch(line)
+			level := len(m[1])
+			fmt.Fprintf(&out, `<h%d style="margin-top: 0;">%s</h%d>`+"\n", level, inline(m[2]), level)
+			i++
+
+		case line == "---" || line == "***":
+			fmt.Fprintf(&out, `<hr style="border: 0; border-top: 1px solid %s;">`+"\n", html.EscapeString(r.theme.BorderColor))
+			i++
+
+		case strings.HasPrefix(line, "|"):
+			var rows []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|") {
+				rows = append(rows, strings.TrimSpace(lines[i]))
+				i++
+			}
+			out.WriteString(r.tableHTML(rows))
+
+		case strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* "):
+			out.WriteString("<ul>\n")
+			for i < len(lines) {
+				item := strings.TrimSpace(lines[i])
+				if !strings.HasPrefix(item, "- ") && !strings.HasPrefix(item, "* ") {
+					break
+				}
+				out.WriteString("<li>" + inline(item[2:]) + "</li>\n")
+				i++
+			}
+			out.WriteString("</ul>\n")
+
+		case orderedPattern.MatchString(line):
+			out.WriteString("<ol>\n")
+			for i < len(lines) && orderedPattern.MatchString(strings.TrimSpace(lines[i])) {
+				item := orderedPattern.ReplaceAllString(strings.TrimSpace(lines[i]), "")
+				out.WriteString("<li>" + inline(item) + "</li>\n")
+				i++
+			}
+			out.WriteString("</ol>\n")
+
+		default:
+			var paragraph []string
+			for i < len(lines) {
+				next := strings.TrimSpace(lines[i])
+				if next == "" || (len(paragraph) > 0 && startsBlock(next)) {
+					break
+				}
+				paragraph = append(paragraph, inline(next))
+				i++
+			}
+			out.WriteString("<p>" + strings.Join(paragraph, "<br>\n") + "</p>\n")
+		}
+	}
+
+	return placeholderPattern.ReplaceAllStringFunc(out.String(), func(token string) string {
+		var index int
+		fmt.Sscanf(strings.Trim(token, "\x00"), "%d", &index)
+		if index < len(r.components) {
+			return r.components[index]
+		}
+		return ""
+	})
+}
+
+// startsBlock reports whether a line begins a new block element.
+func startsBlock(line string) bool {
+	return headingPattern.MatchString(line) || strings.HasPrefix(line, "|") ||
+		strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") ||
+		orderedPattern.MatchString(line) || line == "---" ||
+		(placeholderPattern.MatchString(line) && placeholderPattern.ReplaceAllString(line, "") == "")
+}
+
+// tableHTML renders markdown table rows. The second row must be the separator.
+func (r *markdownRender) tableHTML(rows []string) string {
+	cells := func(row string) []string {
+		parts := strings.Split(strings.Trim(row, "|"), "|")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		return parts
+	}
+
+	cellStyle := fmt.Sprintf(`style="padding: 8px; border-bottom: 1px solid %s; text-align: left;"`, html.EscapeString(r.theme.BorderColor))
+	var b strings.Builder
+	b.WriteString(`<table width="100%" cellpadding="0" cellspacing="0" style="margin: 16px 0; border-collapse: collapse;">` + "\n")
+	for i, row := range rows {
+		if i == 1 && strings.Trim(row, "|-: ") == "" {
+			continue
+		}
+		tag := "td"
+		if i == 0 {
+			tag = "th"
+		}
+		b.WriteString("<tr>")
+		for _, cell := range cells(row) {
+			fmt.Fprintf(&b, "<%s %s>%s</%s>", tag, cellStyle, inline(cell), tag)
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</table>\n")
+	return b.String()
+}
+
+// inline escapes text and applies inline markdown: links, bold, italic and code.
+func inline(text string) string {
+	text = html.EscapeString(text)
+	text = codePattern.ReplaceAllString(text, "<code>$1</code>")
+	text = linkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		m := linkPattern.FindStringSubmatch(match)
+		href := html.UnescapeString(m[2])
+		if !safeURL(href) {
+			return m[1]
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(href), m[1])
+	})
+	text = boldPattern.ReplaceAllString(text, "<strong>$1</strong>")
+	text = italicPattern.ReplaceAllString(text, "$1<em>$2</em>")
+	return text
+}
+
+// safeURL rejects javascript: and other script-capable link schemes.
+func safeURL(url string) bool {
+	lower := strings.ToLower(strings.TrimSpace(url))
+	return !strings.HasPrefix(lower, "javascript:") && !strings.HasPrefix(lower, "data:") && !strings.HasPrefix(lower, "vbscript:")
+}
+
+// toText converts markdown to a plain text body.
+func toText(source string) string {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if m := headingPattern.FindStringSubmatch(trimmed); m != nil {
+			trimmed = m[2]
+		}
+		trimmed = linkPattern.ReplaceAllString(trimmed, "$1 ($2)")
+		trimmed = boldPattern.ReplaceAllString(trimmed, "$1")
+		trimmed = codePattern.ReplaceAllString(trimmed, "$1")
+		out = append(out, trimmed)
+	}
+
+	text := strings.Join(out, "\n")
+	for strings.Contains(text, "\n\n\n") {
+		text = strings.ReplaceAll(text, "\n\n\n", "\n\n")
+	}
+	return strings.TrimSpace(text) + "\n"
+}
@@ -0,0 +1,141 @@
+package mail_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/mail"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const shippedTemplate = `# Order shipped
+
+Hi **{{ .Name }}**, your order is on its way.
+
+{{ button .URL "Track order" }}
+
+{{ panel "Questions? [Contact us](https://example.com/help)." }}
+
+{{ table }}
+| Item | Qty |
+| ---- | --- |
+| Mug  | 2   |
+{{ endtable }}
+`
+
+func TestMarkdownRendersComponents(t *testing.T) {
+	markdown := mail.NewMarkdown(mail.DefaultTheme())
+
+	htmlBody, textBody, err := markdown.RenderString(shippedTemplate, map[string]string{
+		"Name": "Jane",
+		"URL":  "https://example.com/track/1",
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, htmlBody, "<h1")
+	assert.Contains(t, htmlBody, "<strong>Jane</strong>")
+	assert.Contains(t, htmlBody, `href="https://example.com/track/1"`)
+	assert.Contains(t, htmlBody, "Track order</a>")
+	assert.Contains(t, htmlBody, `<a href="https://example.com/help">Contact us</a>`)
+	assert.Contains(t, htmlBody, "<th")
+	assert.Contains(t, htmlBody, ">Mug</td>")
+	assert.Contains(t, htmlBody, "#2d3748", "theme colors are inlined")
+
+	assert.Contains(t, textBody, "Order shipped\n")
+	assert.Contains(t, textBody, "Hi Jane, your order is on its way.")
+	assert.Contains(t, textBody, "Track order: https://example.com/track/1")
+	assert.Contains(t, textBody, "Contact us (https://example.com/help)")
+	assert.NotContains(t, textBody, "<")
+}
+
+func TestMarkdownEscapesData(t *testing.T) {
+	markdown := mail.NewMarkdown(mail.DefaultTheme())
+
+	htmlBody, _, err := markdown.RenderString("{{ .Name }}\n\n[click](javascript:alert(1))", map[string]string{
+		"Name": "<script>alert(1)</script>",
+	})
+	require.NoError(t, err)
+
+	assert.NotContains(t, htmlBody, "<script>")
+	assert.Contains(t, htmlBody, "&lt;script&gt;")
+	assert.NotContains(t, htmlBody, "javascript:")
+}
+
+func TestMarkdownTheme(t *testing.T) {
+	theme := mail.ThemeFromConfig(map[string]any{
+		"app_name":      "Acme",
+		"primary_color": "#ff0000",
+	})
+	assert.Equal(t, "Acme", theme.AppName)
+	assert.Equal(t, "#ff0000", theme.PrimaryColor)
+	assert.Equal(t, mail.DefaultTheme().TextColor, theme.TextColor)
+
+	htmlBody, _, err := mail.NewMarkdown(theme).RenderString(`{{ button "https://example.com" "Go" }}`, nil)
+	require.NoError(t, err)
+	assert.Contains(t, htmlBody, "background-color: #ff0000")
+	assert.Contains(t, htmlBody, "Acme")
+}
+
+func TestMarkdownLoadDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "orders"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "orders", "shipped.md"), []byte(shippedTemplate), 0644))
+
+	markdown := mail.NewMarkdown(mail.DefaultTheme())
+	require.NoError(t, markdown.LoadDirectory(dir))
+
+	_, textBody, err := markdown.Render("orders/shipped", map[string]string{"Name": "Jane", "URL": "https://x"})
+	require.NoError(t, err)
+	assert.Contains(t, textBody, "Hi Jane")
+
+	_, _, err = markdown.Render("missing", nil)
+	assert.Error(t, err)
+}
+
+type shippedEmail struct {
+	Name string
+}
+
+func (m shippedEmail) Build() (*mail.Message, error) {
+	return &mail.Message{
+		To:       []string{"jane@example.com"},
+		Subject:  "Your order shipped",
+		Markdown: "orders/shipped",
+		Data:     map[string]string{"Name": m.Name, "URL": "https://example.com/track/1"},
+	}, nil
+}
+
+func TestMailerRendersMarkdownMessages(t *testing.T) {
+	mailer, transport, _ := newMailer()
+	mailer.Markdown().AddTemplate("orders/shipped", shippedTemplate)
+
+	_, err := mailer.Send(context.Background(), shippedEmail{Name: "Jane"})
+	require.NoError(t, err)
+
+	message := transport.Messages()[0]
+	assert.True(t, strings.HasPrefix(message.HTML, "<!DOCTYPE html>"))
+	assert.Contains(t, message.Text, "Hi Jane")
+}
+
+func TestMailerPreviews(t *testing.T) {
+	mailer, transport, _ := newMailer()
+	mailer.Markdown().AddTemplate("orders/shipped", shippedTemplate)
+	mailer.Preview("orders.shipped", func() mail.Mailable {
+		return shippedEmail{Name: "Preview"}
+	})
+
+	assert.Equal(t, []string{"orders.shipped"}, mailer.Previews())
+
+	message, err := mailer.RenderPreview("orders.shipped")
+	require.NoError(t, err)
+	assert.Contains(t, message.HTML, "Preview")
+	assert.Empty(t, transport.Messages(), "previews are never sent")
+
+	_, err = mailer.RenderPreview("missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+// Package mail provides mailables, transports and a mailer that can send
+// messages immediately or through the queue.
+package mail
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"mime"
+	"net/mail"
+	"path"
+	"strings"
+)
+
+// Message is a fully built email message.
+type Message struct {
+	MessageID string
+	From      string
+	To        []string
+	Cc        []string
+	Bcc       []string
+	Subject   string
+	HTML      string
+	Text      string
+	Headers   map[string]string
+
+	Attachments []Attachment
+
+	// Markdown names a markdown template rendered into HTML and Text when
+	// those are empty, with Data as the template data.
+	Markdown string
+	Data     any
+
+	// View and TextView name the views rendered into HTML and Text when
+	// those are empty, with Data as the template data.
+	View     string
+	TextView string
+}
+
+// Recipients returns every To, Cc and Bcc address.
+func (m *Message) Recipients() []string {
+	recipients := make([]string, 0, len(m.To)+len(m.Cc)+len(m.Bcc))
+	recipients = append(recipients, m.To...)
+	recipients = append(recipients, m.Cc...)
+	recipients = append(recipients, m.Bcc...)
+	return recipients
+}
+
+// Attachment is a file attached to a message. Content holds the file itself,
+// or Disk and Path name a file on a filesystem disk that is read when the
+// message is delivered, which keeps queued messages small.
+type Attachment struct {
+	Name        string
+	ContentType string
+	Content     []byte
+	Disk        string
+	Path        string
+}
+
+// AttachData creates an attachment from its content.
+func AttachData(name string, content []byte) Attachment {
+	return Attachment{Name: name, ContentType: contentTypeOf(name), Content: content}
+}
+
+// AttachFromDisk creates an attachment read from a filesystem disk on
+// delivery. The attachment is named after the file; an empty disk means the
+// default disk.
+func AttachFromDisk(disk, filePath string) Attachment {
+	name := path.Base(filePath)
+	return Attachment{Name: name, ContentType: contentTypeOf(name), Disk: disk, Path: filePath}
+}
+
+// contentTypeOf guesses a file's content type from its extension.
+func contentTypeOf(name string) string {
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// newMessageID generates an RFC 5322 Message-ID using the sender's domain.
+func newMessageID(from string) string {
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if i := strings.LastIndexByte(addr.Address, '@'); i >= 0 {
+			domain = addr.Address[i+1:]
+		}
+	}
+
+	b := make([]byte, 16)
+	rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
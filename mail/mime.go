@@ -0,0 +1,209 @@
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"slices"
+	"strings"
+	"time"
+)
+
+// mimePart is a MIME entity: its headers and encoded body.
+type mimePart struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+// Bytes encodes the message as RFC 5322 MIME, as sent by the SMTP and SES
+// transports. Bcc recipients are left out of the headers. Disk attachments
+// must have been read into Content.
+func (m *Message) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeAddressHeader(&buf, "From", []string{m.From}); err != nil {
+		return nil, err
+	}
+	if err := writeAddressHeader(&buf, "To", m.To); err != nil {
+		return nil, err
+	}
+	if err := writeAddressHeader(&buf, "Cc", m.Cc); err != nil {
+		return nil, err
+	}
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	writeHeader(&buf, "Date", time.Now().Format(time.RFC1123Z))
+	if m.MessageID != "" {
+		writeHeader(&buf, "Message-ID", m.MessageID)
+	}
+	writeHeader(&buf, "MIME-Version", "1.0")
+
+	keys := make([]string, 0, len(m.Headers))
+	for key := range m.Headers {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		writeHeader(&buf, textproto.CanonicalMIMEHeaderKey(key), mime.QEncoding.Encode("utf-8", m.Headers[key]))
+	}
+
+	part, err := m.bodyPart()
+	if err != nil {
+		return nil, err
+	}
+	writePart(&buf, part)
+	return buf.Bytes(), nil
+}
+
+// bodyPart builds the message body: the text and HTML alternatives, wrapped
+// with the attachments in a multipart/mixed entity when there are any.
+func (m *Message) bodyPart() (mimePart, error) {
+	var alternatives []mimePart
+	if m.Text != "" || m.HTML == "" {
+		alternatives = append(alternatives, textPart("text/plain", m.Text))
+	}
+	if m.HTML != "" {
+		alternatives = append(alternatives, textPart("text/html", m.HTML))
+	}
+
+	body := alternatives[0]
+	if len(alternatives) > 1 {
+		var err error
+		if body, err = multipartOf("alternative", alternatives); err != nil {
+			return mimePart{}, err
+		}
+	}
+	if len(m.Attachments) == 0 {
+		return body, nil
+	}
+
+	parts := []mimePart{body}
+	for _, attachment := range m.Attachments {
+		if attachment.Content == nil && attachment.Path != "" {
+			return mimePart{}, fmt.Errorf("mail: attachment %s has not been read from disk", attachment.Path)
+		}
+		parts = append(parts, attachmentPart(attachment))
+	}
+	return multipartOf("mixed", parts)
+}
+
+// textPart creates a quoted-printable UTF-8 text entity.
+func textPart(contentType, content string) mimePart {
+	var body bytes.Buffer
+	w := quotedprintable.NewWriter(&body)
+	w.Write([]byte(content))
+	w.Close()
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", mime.FormatMediaType(contentType, map[string]string{"charset": "utf-8"}))
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	return mimePart{header: header, body: body.Bytes()}
+}
+
+// attachmentPart creates a base64 attachment entity.
+func attachmentPart(attachment Attachment) mimePart {
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = contentTypeOf(attachment.Name)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+	var body bytes.Buffer
+	for len(encoded) > 76 {
+		body.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	body.WriteString(encoded)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", mime.FormatMediaType(contentType, map[string]string{"name": attachment.Name}))
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+	header.Set("Content-Transfer-Encoding", "base64")
+	return mimePart{header: header, body: body.Bytes()}
+}
+
+// multipartOf joins parts into a multipart entity of the given subtype.
+func multipartOf(subtype string, parts []mimePart) (mimePart, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, part := range parts {
+		pw, err := w.CreatePart(part.header)
+		if err != nil {
+			return mimePart{}, err
+		}
+		if _, err := pw.Write(part.body); err != nil {
+			return mimePart{}, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return mimePart{}, err
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": w.Boundary()}))
+	return mimePart{header: header, body: body.Bytes()}, nil
+}
+
+// writePart writes an entity's headers, in a stable order, and its body.
+func writePart(buf *bytes.Buffer, part mimePart) {
+	keys := make([]string, 0, len(part.header))
+	for key := range part.header {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		writeHeader(buf, key, part.header.Get(key))
+	}
+	buf.WriteString("\r\n")
+	buf.Write(part.body)
+}
+
+// writeAddressHeader writes an address list header, encoding display names.
+// Empty lists are skipped.
+func writeAddressHeader(buf *bytes.Buffer, key string, addresses []string) error {
+	var formatted []string
+	for _, address := range addresses {
+		if address == "" {
+			continue
+		}
+		parsed, err := mail.ParseAddress(address)
+		if err != nil {
+			return fmt.Errorf("mail: invalid %s address %q: %w", strings.ToLower(key), address, err)
+		}
+		formatted = append(formatted, parsed.String())
+	}
+	if len(formatted) > 0 {
+		writeHeader(buf, key, strings.Join(formatted, ", "))
+	}
+	return nil
+}
+
+func writeHeader(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key + ": " + value + "\r\n")
+}
+
+// envelopeAddress returns the bare address of a possibly named address,
+// as used in the SMTP envelope.
+func envelopeAddress(address string) (string, error) {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return "", fmt.Errorf("mail: invalid address %q: %w", address, err)
+	}
+	return parsed.Address, nil
+}
+
+// envelopeAddresses returns the bare addresses of a list of addresses.
+func envelopeAddresses(addresses []string) ([]string, error) {
+	result := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		bare, err := envelopeAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, bare)
+	}
+	return result, nil
+}
@@ -0,0 +1,71 @@
+package mail
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// Preview registers a mailable that can be rendered in the browser or with
+// mail:preview without being sent. build should return sample data.
+func (m *Mailer) Preview(name string, build func() Mailable) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.previews[name] = build
+}
+
+// Previews returns the registered preview names in order.
+func (m *Mailer) Previews() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.previews))
+	for name := range m.previews {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RenderPreview builds and renders a registered preview without sending it.
+func (m *Mailer) RenderPreview(name string) (*Message, error) {
+	m.mu.RLock()
+	build, ok := m.previews[name]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("mail: preview [%s] not found", name)
+	}
+	return m.render(build())
+}
+
+// PreviewRoutes registers routes that render mail previews in the browser:
+// GET {prefix} lists the previews and GET {prefix}/:name renders one
+// (add ?format=text for the plain text body). Register them in development only.
+func PreviewRoutes(router contracts.Router, mailer *Mailer, prefix string) {
+	prefix = "/" + strings.Trim(prefix, "/")
+
+	router.GET(prefix, func(ctx contracts.Context) error {
+		var b strings.Builder
+		b.WriteString("<!DOCTYPE html><html><head><title>Mail previews</title></head><body><h1>Mail previews</h1><ul>")
+		for _, name := range mailer.Previews() {
+			fmt.Fprintf(&b, `<li><a href="%s/%s">%s</a> (<a href="%s/%s?format=text">text</a>)</li>`,
+				prefix, html.EscapeString(name), html.EscapeString(name), prefix, html.EscapeString(name))
+		}
+		b.WriteString("</ul></body></html>")
+		return ctx.HTML(b.String())
+	}).Name("mail.previews")
+
+	router.GET(prefix+"/:name", func(ctx contracts.Context) error {
+		message, err := mailer.RenderPreview(ctx.Param("name"))
+		if err != nil {
+			return ctx.Status(404).String(err.Error())
+		}
+		if ctx.Query("format") == "text" {
+			return ctx.String(message.Text)
+		}
+		return ctx.HTML(message.HTML)
+	}).Name("mail.preview")
+}
@@ -0,0 +1,186 @@
+package mail
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+)
+
+// Delivery statuses recorded in the sent message log.
+const (
+	StatusQueued = "queued"
+	StatusSent   = "sent"
+	StatusFailed = "failed"
+)
+
+// SentMessage is a sent message log entry for a single recipient.
+type SentMessage struct {
+	MessageID string
+	Recipient string
+	Subject   string
+	Status    string
+	Error     string
+	UpdatedAt time.Time
+}
+
+// SentLog records the delivery status of messages, so support staff can
+// look up what was sent to whom.
+type SentLog interface {
+	// Record stores an entry, replacing any entry for the same message and recipient.
+	Record(entry SentMessage) error
+
+	// Find returns the entries for a message ID.
+	Find(messageID string) ([]SentMessage, error)
+
+	// ForRecipient returns the entries for a recipient address, oldest first.
+	ForRecipient(recipient string) ([]SentMessage, error)
+}
+
+// MemorySentLog is an in-memory SentLog.
+type MemorySentLog struct {
+	entries []SentMessage
+	mu      sync.RWMutex
+}
+
+// NewMemorySentLog creates a new in-memory sent message log.
+func NewMemorySentLog() *MemorySentLog {
+	return &MemorySentLog{}
+}
+
+// Record stores an entry, replacing any entry for the same message and recipient.
+func (l *MemorySentLog) Record(entry SentMessage) error {
+	if entry.UpdatedAt.IsZero() {
+		entry.UpdatedAt = time.Now()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, existing := range l.entries {
+		if existing.MessageID == entry.MessageID && existing.Recipient == entry.Recipient {
+			l.entries[i] = entry
+			return nil
+		}
+	}
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+// Find returns the entries for a message ID.
+func (l *MemorySentLog) Find(messageID string) ([]SentMessage, error) {
+	return l.filter(func(entry SentMessage) bool {
+		return entry.MessageID == messageID
+	}), nil
+}
+
+// ForRecipient returns the entries for a recipient address, oldest first.
+func (l *MemorySentLog) ForRecipient(recipient string) ([]SentMessage, error) {
+	return l.filter(func(entry SentMessage) bool {
+		return entry.Recipient == recipient
+	}), nil
+}
+
+func (l *MemorySentLog) filter(match func(SentMessage) bool) []SentMessage {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entries := make([]SentMessage, 0)
+	for _, entry := range l.entries {
+		if match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// DatabaseSentLog is a SentLog kept in a table with message_id, recipient,
+// subject, status, error and updated_at columns, so every process, such as
+// queue workers and the web process support staff use, shares one log.
+// Generate its migration with `mail:sent-table`.
+type DatabaseSentLog struct {
+	conn    contracts.Connection
+	dialect database.Dialect
+	table   string
+}
+
+// NewDatabaseSentLog creates a database sent message log. The connection's
+// table prefix is applied to table, which defaults to "mail_sent_messages".
+func NewDatabaseSentLog(conn contracts.Connection, table string) *DatabaseSentLog {
+	if table == "" {
+		table = "mail_sent_messages"
+	}
+	return &DatabaseSentLog{
+		conn:    conn,
+		dialect: database.NewDialect(conn.Driver()),
+		table:   conn.Prefix() + table,
+	}
+}
+
+// Record stores an entry, replacing any entry for the same message and recipient.
+func (l *DatabaseSentLog) Record(entry SentMessage) error {
+	if entry.UpdatedAt.IsZero() {
+		entry.UpdatedAt = time.Now()
+	}
+	ctx := context.Background()
+	d := l.dialect
+
+	update := fmt.Sprintf(`UPDATE %s SET subject = %s, status = %s, error = %s, updated_at = %s
+WHERE message_id = %s AND recipient = %s`,
+		d.Quote(l.table), d.Placeholder(1), d.Placeholder(2), d.Placeholder(3), d.Placeholder(4),
+		d.Placeholder(5), d.Placeholder(6))
+	result, err := l.conn.ExecContext(ctx, update,
+		entry.Subject, entry.Status, entry.Error, entry.UpdatedAt.UTC(), entry.MessageID, entry.Recipient)
+	if err != nil {
+		return fmt.Errorf("mail: failed to record sent message: %w", err)
+	}
+	if updated, err := result.RowsAffected(); err == nil && updated > 0 {
+		return nil
+	}
+
+	insert := fmt.Sprintf(`INSERT INTO %s (message_id, recipient, subject, status, error, updated_at)
+VALUES (%s, %s, %s, %s, %s, %s)`,
+		d.Quote(l.table), d.Placeholder(1), d.Placeholder(2), d.Placeholder(3), d.Placeholder(4),
+		d.Placeholder(5), d.Placeholder(6))
+	_, err = l.conn.ExecContext(ctx, insert,
+		entry.MessageID, entry.Recipient, entry.Subject, entry.Status, entry.Error, entry.UpdatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("mail: failed to record sent message: %w", err)
+	}
+	return nil
+}
+
+// Find returns the entries for a message ID.
+func (l *DatabaseSentLog) Find(messageID string) ([]SentMessage, error) {
+	return l.query("message_id", messageID)
+}
+
+// ForRecipient returns the entries for a recipient address, oldest first.
+func (l *DatabaseSentLog) ForRecipient(recipient string) ([]SentMessage, error) {
+	return l.query("recipient", recipient)
+}
+
+func (l *DatabaseSentLog) query(column, value string) ([]SentMessage, error) {
+	query := fmt.Sprintf(`SELECT message_id, recipient, subject, status, error, updated_at FROM %s
+WHERE %s = %s ORDER BY id`, l.dialect.Quote(l.table), column, l.dialect.Placeholder(1))
+	rows, err := l.conn.QueryContext(context.Background(), query, value)
+	if err != nil {
+		return nil, fmt.Errorf("mail: failed to read sent messages: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]SentMessage, 0)
+	for rows.Next() {
+		var entry SentMessage
+		var failure sql.NullString
+		if err := rows.Scan(&entry.MessageID, &entry.Recipient, &entry.Subject, &entry.Status, &failure, &entry.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("mail: failed to read sent messages: %w", err)
+		}
+		entry.Error = failure.String
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
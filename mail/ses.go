@@ -0,0 +1,144 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// SESConfig configures an Amazon SES transport.
+type SESConfig struct {
+	// Key and Secret are static credentials. Without them, credentials come
+	// from the environment, shared config files or the instance role.
+	Key    string
+	Secret string
+	Token  string
+
+	Region string // defaults to us-east-1
+
+	// ConfigurationSet names the SES configuration set to send with.
+	ConfigurationSet string
+
+	// Endpoint overrides the regional API endpoint, such as for LocalStack.
+	Endpoint string
+}
+
+// SESTransport delivers messages through the Amazon SES v2 API as raw MIME,
+// so attachments and custom headers are preserved.
+type SESTransport struct {
+	config      SESConfig
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	client      *http.Client
+}
+
+// NewSESTransport creates a new SES transport.
+func NewSESTransport(config SESConfig) (*SESTransport, error) {
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://email." + config.Region + ".amazonaws.com"
+	}
+
+	var provider aws.CredentialsProvider
+	if config.Key != "" {
+		provider = credentials.NewStaticCredentialsProvider(config.Key, config.Secret, config.Token)
+	} else {
+		cfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(config.Region))
+		if err != nil {
+			return nil, fmt.Errorf("mail: failed to load aws config: %w", err)
+		}
+		provider = cfg.Credentials
+	}
+
+	return &SESTransport{
+		config:      config,
+		credentials: aws.NewCredentialsCache(provider),
+		signer:      v4.NewSigner(),
+		client:      &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// sesRequest is the body of an SES v2 SendEmail request.
+type sesRequest struct {
+	FromEmailAddress     string         `json:"FromEmailAddress"`
+	Destination          sesDestination `json:"Destination"`
+	Content              sesContent     `json:"Content"`
+	ConfigurationSetName string         `json:"ConfigurationSetName,omitempty"`
+}
+
+type sesDestination struct {
+	ToAddresses  []string `json:"ToAddresses,omitempty"`
+	CcAddresses  []string `json:"CcAddresses,omitempty"`
+	BccAddresses []string `json:"BccAddresses,omitempty"`
+}
+
+type sesContent struct {
+	Raw struct {
+		Data []byte `json:"Data"`
+	} `json:"Raw"`
+}
+
+// Send delivers the message.
+func (t *SESTransport) Send(ctx context.Context, message *Message) error {
+	data, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+
+	request := sesRequest{FromEmailAddress: message.From, ConfigurationSetName: t.config.ConfigurationSet}
+	request.Content.Raw.Data = data
+	if request.Destination.ToAddresses, err = envelopeAddresses(message.To); err != nil {
+		return err
+	}
+	if request.Destination.CcAddresses, err = envelopeAddresses(message.Cc); err != nil {
+		return err
+	}
+	if request.Destination.BccAddresses, err = envelopeAddresses(message.Bcc); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimRight(t.config.Endpoint, "/")+"/v2/email/outbound-emails", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	creds, err := t.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("mail: failed to retrieve aws credentials: %w", err)
+	}
+	hash := sha256.Sum256(body)
+	if err := t.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "ses", t.config.Region, time.Now()); err != nil {
+		return fmt.Errorf("mail: failed to sign ses request: %w", err)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("mail: ses request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("mail: ses returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTP encryption modes.
+const (
+	// EncryptionTLS connects over implicit TLS, usually on port 465.
+	EncryptionTLS = "tls"
+	// EncryptionSTARTTLS requires the server to upgrade the connection.
+	EncryptionSTARTTLS = "starttls"
+	// EncryptionNone never encrypts, for local relays and test servers.
+	EncryptionNone = "none"
+)
+
+// SMTPConfig configures an SMTP transport.
+type SMTPConfig struct {
+	Host     string
+	Port     int // defaults to 465 with EncryptionTLS, 587 otherwise
+	Username string
+	Password string
+
+	// Encryption is EncryptionTLS, EncryptionSTARTTLS or EncryptionNone.
+	// Empty upgrades with STARTTLS when the server offers it.
+	Encryption string
+
+	// LocalName is the host name sent with EHLO, "localhost" if empty.
+	LocalName string
+
+	// Timeout bounds the whole delivery, 30 seconds if zero.
+	Timeout time.Duration
+
+	// TLSConfig overrides the TLS settings, such as to trust a private CA.
+	TLSConfig *tls.Config
+}
+
+// SMTPTransport delivers messages to an SMTP server, opening a connection
+// for each message.
+type SMTPTransport struct {
+	config SMTPConfig
+}
+
+// NewSMTPTransport creates a new SMTP transport.
+func NewSMTPTransport(config SMTPConfig) (*SMTPTransport, error) {
+	if config.Host == "" {
+		return nil, fmt.Errorf("mail: smtp host not defined")
+	}
+	switch config.Encryption {
+	case "", EncryptionTLS, EncryptionSTARTTLS, EncryptionNone:
+	default:
+		return nil, fmt.Errorf("mail: unsupported smtp encryption: %s", config.Encryption)
+	}
+	if config.Port == 0 {
+		config.Port = 587
+		if config.Encryption == EncryptionTLS {
+			config.Port = 465
+		}
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+	return &SMTPTransport{config: config}, nil
+}
+
+// Send delivers the message.
+func (t *SMTPTransport) Send(ctx context.Context, message *Message) error {
+	data, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+	from, err := envelopeAddress(message.From)
+	if err != nil {
+		return err
+	}
+	recipients, err := envelopeAddresses(message.Recipients())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.config.Timeout)
+	defer cancel()
+
+	conn, err := t.dial(ctx)
+	if err != nil {
+		return err
+	}
+	// Bound the SMTP conversation by the context, as net/smtp has no
+	// context support of its own.
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, t.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("mail: smtp handshake failed: %w", err)
+	}
+	defer client.Close()
+
+	if err := t.session(client, from, recipients, data); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// dial connects to the server, over TLS with EncryptionTLS.
+func (t *SMTPTransport) dial(ctx context.Context) (net.Conn, error) {
+	addr := net.JoinHostPort(t.config.Host, strconv.Itoa(t.config.Port))
+	var conn net.Conn
+	var err error
+	if t.config.Encryption == EncryptionTLS {
+		dialer := &tls.Dialer{Config: t.tlsConfig()}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mail: failed to connect to %s: %w", addr, err)
+	}
+	return conn, nil
+}
+
+// session runs the SMTP commands that deliver one message.
+func (t *SMTPTransport) session(client *smtp.Client, from string, recipients []string, data []byte) error {
+	localName := t.config.LocalName
+	if localName == "" {
+		localName = "localhost"
+	}
+	if err := client.Hello(localName); err != nil {
+		return err
+	}
+
+	if t.config.Encryption != EncryptionTLS && t.config.Encryption != EncryptionNone {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(t.tlsConfig()); err != nil {
+				return fmt.Errorf("mail: starttls failed: %w", err)
+			}
+		} else if t.config.Encryption == EncryptionSTARTTLS {
+			return fmt.Errorf("mail: smtp server %s does not support starttls", t.config.Host)
+		}
+	}
+
+	if t.config.Username != "" {
+		auth := smtp.PlainAuth("", t.config.Username, t.config.Password, t.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("mail: smtp authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("mail: recipient %s rejected: %w", recipient, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func (t *SMTPTransport) tlsConfig() *tls.Config {
+	if t.config.TLSConfig != nil {
+		return t.config.TLSConfig
+	}
+	return &tls.Config{ServerName: t.config.Host}
+}
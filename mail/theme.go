@@ -0,0 +1,62 @@
+package mail
+
+// Theme controls the look of markdown emails.
+type Theme struct {
+	// AppName is shown in the header when no logo is set.
+	AppName string
+	// LogoURL is an optional header image.
+	LogoURL string
+	// Footer is shown below the message body.
+	Footer string
+
+	FontFamily        string
+	TextColor         string
+	MutedColor        string
+	BackgroundColor   string
+	ContentBackground string
+	PrimaryColor      string
+	ButtonTextColor   string
+	PanelBackground   string
+	BorderColor       string
+}
+
+// DefaultTheme returns the built-in theme.
+func DefaultTheme() Theme {
+	return Theme{
+		FontFamily:        "-apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Helvetica, Arial, sans-serif",
+		TextColor:         "#3d4852",
+		MutedColor:        "#718096",
+		BackgroundColor:   "#edf2f7",
+		ContentBackground: "#ffffff",
+		PrimaryColor:      "#2d3748",
+		ButtonTextColor:   "#ffffff",
+		PanelBackground:   "#f7fafc",
+		BorderColor:       "#e8e5ef",
+	}
+}
+
+// ThemeFromConfig overrides the default theme with values from a config map,
+// such as mail.markdown.theme. Keys are snake_case field names.
+func ThemeFromConfig(config map[string]any) Theme {
+	theme := DefaultTheme()
+	fields := map[string]*string{
+		"app_name":           &theme.AppName,
+		"logo_url":           &theme.LogoURL,
+		"footer":             &theme.Footer,
+		"font_family":        &theme.FontFamily,
+		"text_color":         &theme.TextColor,
+		"muted_color":        &theme.MutedColor,
+		"background_color":   &theme.BackgroundColor,
+		"content_background": &theme.ContentBackground,
+		"primary_color":      &theme.PrimaryColor,
+		"button_text_color":  &theme.ButtonTextColor,
+		"panel_background":   &theme.PanelBackground,
+		"border_color":       &theme.BorderColor,
+	}
+	for key, field := range fields {
+		if value, ok := config[key].(string); ok && value != "" {
+			*field = value
+		}
+	}
+	return theme
+}
@@ -0,0 +1,62 @@
+package mail
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+// Transport delivers built messages.
+type Transport interface {
+	// Send delivers a message.
+	Send(ctx context.Context, message *Message) error
+}
+
+// ArrayTransport keeps sent messages in memory. It is intended for tests.
+type ArrayTransport struct {
+	messages []*Message
+	mu       sync.Mutex
+}
+
+// NewArrayTransport creates a new in-memory transport.
+func NewArrayTransport() *ArrayTransport {
+	return &ArrayTransport{}
+}
+
+// Send stores the message.
+func (t *ArrayTransport) Send(ctx context.Context, message *Message) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.messages = append(t.messages, message)
+	return nil
+}
+
+// Messages returns the messages sent so far.
+func (t *ArrayTransport) Messages() []*Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*Message(nil), t.messages...)
+}
+
+// LogTransport writes messages to a logger instead of delivering them.
+type LogTransport struct {
+	logger contracts.Logger
+}
+
+// NewLogTransport creates a new log transport.
+func NewLogTransport(logger contracts.Logger) *LogTransport {
+	return &LogTransport{logger: logger}
+}
+
+// Send logs the message.
+func (t *LogTransport) Send(ctx context.Context, message *Message) error {
+	t.logger.Info("Mail sent",
+		"message_id", message.MessageID,
+		"from", message.From,
+		"to", strings.Join(message.Recipients(), ", "),
+		"subject", message.Subject,
+	)
+	return nil
+}
@@ -0,0 +1,323 @@
+package mail_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/filesystem"
+	"github.com/genesysflow/go-genesys/mail"
+	"github.com/genesysflow/go-genesys/mail/inbound"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMessage() *mail.Message {
+	return &mail.Message{
+		MessageID: "<1@example.com>",
+		From:      "Shop <shop@example.com>",
+		To:        []string{"Jane Doe <jane@example.com>"},
+		Cc:        []string{"ops@example.com"},
+		Bcc:       []string{"audit@example.com"},
+		Subject:   "Your invoice – March",
+		Text:      "Invoice attached.",
+		HTML:      "<p>Invoice attached.</p>",
+		Headers:   map[string]string{"X-Campaign": "invoices"},
+		Attachments: []mail.Attachment{
+			mail.AttachData("invoice.pdf", []byte("%PDF-1.4 invoice")),
+		},
+	}
+}
+
+func TestMessageBytes(t *testing.T) {
+	data, err := testMessage().Bytes()
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "audit@example.com")
+
+	parsed, err := inbound.ParseMIME(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "<1@example.com>", parsed.MessageID)
+	assert.Equal(t, "shop@example.com", parsed.From)
+	assert.Equal(t, "Shop", parsed.FromName)
+	assert.Equal(t, []string{"jane@example.com"}, parsed.To)
+	assert.Equal(t, []string{"ops@example.com"}, parsed.Cc)
+	assert.Equal(t, "Your invoice – March", parsed.Subject)
+	assert.Equal(t, "Invoice attached.", strings.TrimSpace(parsed.Text))
+	assert.Equal(t, "<p>Invoice attached.</p>", strings.TrimSpace(parsed.HTML))
+	assert.Equal(t, []string{"invoices"}, parsed.Headers["X-Campaign"])
+	require.Len(t, parsed.Attachments, 1)
+	assert.Equal(t, "invoice.pdf", parsed.Attachments[0].Filename)
+	assert.Equal(t, "application/pdf", parsed.Attachments[0].ContentType)
+	assert.Equal(t, "%PDF-1.4 invoice", string(parsed.Attachments[0].Content()))
+}
+
+func TestMessageBytesRejectsInvalidAddresses(t *testing.T) {
+	message := &mail.Message{From: "shop@example.com", To: []string{"not an address"}, Text: "Hi"}
+	_, err := message.Bytes()
+	assert.Error(t, err)
+}
+
+type memoryDisks struct {
+	disk contracts.Filesystem
+}
+
+func (d memoryDisks) Disk(name ...string) contracts.Filesystem {
+	if len(name) > 0 && name[0] != "local" {
+		panic("disk [" + name[0] + "] not configured")
+	}
+	return d.disk
+}
+
+type invoiceEmail struct {
+	Disk string
+}
+
+func (m invoiceEmail) Build() (*mail.Message, error) {
+	return &mail.Message{
+		To:          []string{"jane@example.com"},
+		Subject:     "Invoice",
+		Text:        "Attached.",
+		Attachments: []mail.Attachment{mail.AttachFromDisk(m.Disk, "invoices/42.pdf")},
+	}, nil
+}
+
+func TestMailerReadsDiskAttachments(t *testing.T) {
+	disk, err := filesystem.NewMemory(nil)
+	require.NoError(t, err)
+	require.NoError(t, disk.Put(context.Background(), "invoices/42.pdf", "%PDF invoice 42"))
+
+	mailer, transport, _ := newMailer()
+	mailer.SetDisks(memoryDisks{disk: disk})
+
+	_, err = mailer.Send(context.Background(), invoiceEmail{Disk: "local"})
+	require.NoError(t, err)
+
+	messages := transport.Messages()
+	require.Len(t, messages, 1)
+	require.Len(t, messages[0].Attachments, 1)
+	attachment := messages[0].Attachments[0]
+	assert.Equal(t, "42.pdf", attachment.Name)
+	assert.Equal(t, "application/pdf", attachment.ContentType)
+	assert.Equal(t, "%PDF invoice 42", string(attachment.Content))
+
+	_, err = mailer.Send(context.Background(), invoiceEmail{Disk: "s3"})
+	assert.ErrorContains(t, err, `attachment disk "s3"`)
+}
+
+func TestMailerDiskAttachmentsNeedDisks(t *testing.T) {
+	mailer, _, _ := newMailer()
+	_, err := mailer.Send(context.Background(), invoiceEmail{})
+	assert.ErrorContains(t, err, "no disks")
+}
+
+// smtpServer is a minimal SMTP server that records one delivery per session.
+type smtpServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	auth     string
+	from     string
+	rcpt     []string
+	data     string
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &smtpServer{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *smtpServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		s.mu.Lock()
+		switch command {
+		case "EHLO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			s.auth = line
+			reply("235 Authenticated")
+		case "MAIL":
+			s.from = line
+			reply("250 OK")
+		case "RCPT":
+			s.rcpt = append(s.rcpt, line)
+			reply("250 OK")
+		case "DATA":
+			reply("354 Go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil || l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.data = data.String()
+			reply("250 Queued")
+		case "QUIT":
+			reply("221 Bye")
+			s.mu.Unlock()
+			return
+		default:
+			reply("502 Unknown command")
+		}
+		s.mu.Unlock()
+	}
+}
+
+func TestSMTPTransport(t *testing.T) {
+	server := newSMTPServer(t)
+	transport, err := mail.NewSMTPTransport(mail.SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     server.port(),
+		Username: "user",
+		Password: "secret",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, transport.Send(context.Background(), testMessage()))
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.True(t, strings.HasPrefix(server.auth, "AUTH PLAIN"))
+	assert.Equal(t, "MAIL FROM:<shop@example.com>", strings.Split(server.from, " BODY")[0])
+	assert.Equal(t, []string{"RCPT TO:<jane@example.com>", "RCPT TO:<ops@example.com>", "RCPT TO:<audit@example.com>"}, server.rcpt)
+
+	parsed, err := inbound.ParseMIME(strings.NewReader(server.data))
+	require.NoError(t, err)
+	assert.Equal(t, "Your invoice – March", parsed.Subject)
+	require.Len(t, parsed.Attachments, 1)
+}
+
+func TestSMTPTransportRequiresStartTLS(t *testing.T) {
+	server := newSMTPServer(t)
+	transport, err := mail.NewSMTPTransport(mail.SMTPConfig{
+		Host:       "127.0.0.1",
+		Port:       server.port(),
+		Encryption: mail.EncryptionSTARTTLS,
+	})
+	require.NoError(t, err)
+
+	err = transport.Send(context.Background(), testMessage())
+	assert.ErrorContains(t, err, "does not support starttls")
+}
+
+func TestNewSMTPTransportValidatesConfig(t *testing.T) {
+	_, err := mail.NewSMTPTransport(mail.SMTPConfig{})
+	assert.Error(t, err)
+	_, err = mail.NewSMTPTransport(mail.SMTPConfig{Host: "smtp.example.com", Encryption: "ssl3"})
+	assert.Error(t, err)
+}
+
+func TestSESTransport(t *testing.T) {
+	var request struct {
+		FromEmailAddress     string
+		ConfigurationSetName string
+		Destination          struct{ ToAddresses, CcAddresses, BccAddresses []string }
+		Content              struct{ Raw struct{ Data []byte } }
+	}
+	var authorization, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Write([]byte(`{"MessageId":"ses-1"}`))
+	}))
+	defer server.Close()
+
+	transport, err := mail.NewSESTransport(mail.SESConfig{
+		Key:              "AKIDEXAMPLE",
+		Secret:           "secret",
+		Region:           "eu-west-1",
+		ConfigurationSet: "transactional",
+		Endpoint:         server.URL,
+	})
+	require.NoError(t, err)
+	require.NoError(t, transport.Send(context.Background(), testMessage()))
+
+	assert.Equal(t, "/v2/email/outbound-emails", path)
+	assert.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"))
+	assert.Contains(t, authorization, "/eu-west-1/ses/aws4_request")
+	assert.Equal(t, "transactional", request.ConfigurationSetName)
+	assert.Equal(t, []string{"jane@example.com"}, request.Destination.ToAddresses)
+	assert.Equal(t, []string{"audit@example.com"}, request.Destination.BccAddresses)
+
+	parsed, err := inbound.ParseMIME(bytes.NewReader(request.Content.Raw.Data))
+	require.NoError(t, err)
+	assert.Equal(t, "<1@example.com>", parsed.MessageID)
+}
+
+func TestSESTransportReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"Email address is not verified."}`))
+	}))
+	defer server.Close()
+
+	transport, err := mail.NewSESTransport(mail.SESConfig{Key: "key", Secret: "secret", Endpoint: server.URL})
+	require.NoError(t, err)
+
+	err = transport.Send(context.Background(), testMessage())
+	assert.ErrorContains(t, err, "ses returned "+strconv.Itoa(http.StatusBadRequest))
+	assert.ErrorContains(t, err, "not verified")
+}
+
+func TestManager(t *testing.T) {
+	manager := mail.NewManager()
+	transactional := mail.NewMailer(mail.NewArrayTransport(), "")
+	marketing := mail.NewMailer(mail.NewArrayTransport(), "")
+	manager.Register("log", transactional)
+	manager.Register("ses", marketing)
+
+	mailer, err := manager.Mailer()
+	require.NoError(t, err)
+	assert.Same(t, transactional, mailer)
+
+	manager.SetDefaultMailer("ses")
+	mailer, err = manager.Mailer()
+	require.NoError(t, err)
+	assert.Same(t, marketing, mailer)
+
+	assert.Equal(t, []string{"log", "ses"}, manager.Names())
+	_, err = manager.Mailer("postmark")
+	assert.Error(t, err)
+}
@@ -0,0 +1,212 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+)
+
+// ViewRenderer renders a named view to a string. Views implements it; an
+// application can use its own view engine instead.
+type ViewRenderer interface {
+	Render(name string, data any) (string, error)
+}
+
+// Views renders email bodies from Go templates.
+//
+// HTML views are parsed with html/template and text views, whose names end
+// in ".txt", with text/template. Templates under layouts/ and partials/ are
+// shared: views include partials with {{ template "partials/footer" . }},
+// and a view that defines a "content" block is rendered inside the layout:
+//
+//	{{/* layouts/default.html */}}
+//	<html><body>{{ template "content" . }}</body></html>
+//
+//	{{/* orders/shipped.html */}}
+//	{{ define "content" }}<p>Hi {{ .Name }}, your order shipped.</p>{{ end }}
+type Views struct {
+	sources   map[string]string
+	layout    string
+	inlineCSS bool
+	funcs     map[string]any
+	mu        sync.RWMutex
+}
+
+// NewViews creates an empty view set using the layouts/default layout.
+func NewViews() *Views {
+	return &Views{
+		sources: make(map[string]string),
+		layout:  "layouts/default",
+		funcs:   make(map[string]any),
+	}
+}
+
+// AddView registers a view source. Names use slashes or dots as separators
+// ("orders/shipped" or "orders.shipped"); text views end in ".txt".
+func (v *Views) AddView(name, source string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.sources[viewName(name)] = source
+}
+
+// LoadDirectory registers every .html and .txt file under dir, named by its
+// path relative to dir. HTML views drop their extension; text views keep
+// ".txt" (e.g. "orders/shipped" and "orders/shipped.txt").
+func (v *Views) LoadDirectory(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		ext := filepath.Ext(path)
+		if ext != ".html" && ext != ".txt" {
+			return nil
+		}
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		v.AddView(filepath.ToSlash(rel), string(source))
+		return nil
+	})
+}
+
+// SetLayout sets the layout that views defining a "content" block are
+// rendered in. Text views use the layout's ".txt" variant when it exists.
+func (v *Views) SetLayout(name string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.layout = viewName(name)
+}
+
+// SetInlineCSS sets whether the rules in HTML views' <style> elements are
+// moved onto the elements' style attributes, for mail clients that ignore
+// stylesheets.
+func (v *Views) SetInlineCSS(inline bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.inlineCSS = inline
+}
+
+// Funcs adds template functions available to every view.
+func (v *Views) Funcs(funcs map[string]any) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for name, fn := range funcs {
+		v.funcs[name] = fn
+	}
+}
+
+// Has reports whether a view is registered.
+func (v *Views) Has(name string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	_, ok := v.sources[viewName(name)]
+	return ok
+}
+
+// Render renders a view with data.
+func (v *Views) Render(name string, data any) (string, error) {
+	name = viewName(name)
+
+	v.mu.RLock()
+	source, ok := v.sources[name]
+	var shared []string
+	for key := range v.sources {
+		if key != name && isSharedView(key) && strings.HasSuffix(key, ".txt") == strings.HasSuffix(name, ".txt") {
+			shared = append(shared, key)
+		}
+	}
+	layout, inlineCSS := v.layout, v.inlineCSS
+	v.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("mail: view [%s] not found", name)
+	}
+
+	if strings.HasSuffix(name, ".txt") {
+		return v.renderText(name, source, shared, layout+".txt", data)
+	}
+	body, err := v.renderHTML(name, source, shared, layout, data)
+	if err != nil || !inlineCSS {
+		return body, err
+	}
+	return InlineCSS(body)
+}
+
+func (v *Views) renderHTML(name, source string, shared []string, layout string, data any) (string, error) {
+	v.mu.RLock()
+	tmpl, err := htmltemplate.New(name).Funcs(v.funcs).Parse(source)
+	for _, key := range shared {
+		if err == nil {
+			_, err = tmpl.New(key).Parse(v.sources[key])
+		}
+	}
+	v.mu.RUnlock()
+	if err != nil {
+		return "", fmt.Errorf("mail: failed to parse view [%s]: %w", name, err)
+	}
+
+	root := name
+	if tmpl.Lookup("content") != nil && name != layout {
+		if tmpl.Lookup(layout) == nil {
+			return "", fmt.Errorf("mail: layout [%s] for view [%s] not found", layout, name)
+		}
+		root = layout
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, root, data); err != nil {
+		return "", fmt.Errorf("mail: failed to render view [%s]: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+func (v *Views) renderText(name, source string, shared []string, layout string, data any) (string, error) {
+	v.mu.RLock()
+	tmpl, err := texttemplate.New(name).Funcs(v.funcs).Parse(source)
+	for _, key := range shared {
+		if err == nil {
+			_, err = tmpl.New(key).Parse(v.sources[key])
+		}
+	}
+	v.mu.RUnlock()
+	if err != nil {
+		return "", fmt.Errorf("mail: failed to parse view [%s]: %w", name, err)
+	}
+
+	// Text views fall back to rendering on their own without a text layout.
+	root := name
+	if tmpl.Lookup("content") != nil && name != layout && tmpl.Lookup(layout) != nil {
+		root = layout
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, root, data); err != nil {
+		return "", fmt.Errorf("mail: failed to render view [%s]: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// viewName normalizes a view name to slash separators, keeping the ".txt"
+// suffix of text views.
+func viewName(name string) string {
+	name = strings.TrimSuffix(name, ".html")
+	if base, ok := strings.CutSuffix(name, ".txt"); ok {
+		return strings.ReplaceAll(base, ".", "/") + ".txt"
+	}
+	return strings.ReplaceAll(name, ".", "/")
+}
+
+// isSharedView reports whether a view is a layout or partial, parsed
+// alongside every view.
+func isSharedView(name string) bool {
+	return strings.HasPrefix(name, "layouts/") || strings.HasPrefix(name, "partials/")
+}
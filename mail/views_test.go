@@ -0,0 +1,134 @@
+package mail_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/mail"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestViews(t *testing.T) *mail.Views {
+	dir := t.TempDir()
+	files := map[string]string{
+		"layouts/default.html": `<html><head><style>p { color: #333; } .footer { font-size: 12px }</style></head>` +
+			`<body>{{ template "content" . }}{{ template "partials/footer" . }}</body></html>`,
+		"layouts/default.txt":   `{{ template "content" . }}-- Acme`,
+		"partials/footer.html":  `<p class="footer">Sent to {{ .Email }}</p>`,
+		"orders/shipped.html":   `{{ define "content" }}<p>Hi {{ .Name }}, your order shipped.</p>{{ end }}`,
+		"orders/shipped.txt":    `{{ define "content" }}Hi {{ .Name }}, your order shipped.{{ end }}`,
+		"standalone.html":       `<b>{{ .Name }}</b>`,
+		"notes/ignored.md":      `# not a view`,
+		"orders/unstyled.html":  `{{ define "content" }}{{ upper .Name }}{{ end }}`,
+		"orders/cancelled.html": `{{ define "content" }}<p>Cancelled</p>{{ end }}`,
+	}
+	for name, source := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(source), 0644))
+	}
+
+	views := mail.NewViews()
+	views.Funcs(map[string]any{"upper": strings.ToUpper})
+	require.NoError(t, views.LoadDirectory(dir))
+	return views
+}
+
+func TestViewsRenderWithLayoutAndPartials(t *testing.T) {
+	views := newTestViews(t)
+	data := map[string]string{"Name": "<Jane>", "Email": "jane@example.com"}
+
+	body, err := views.Render("orders.shipped", data)
+	require.NoError(t, err)
+	assert.Contains(t, body, "<p>Hi &lt;Jane&gt;, your order shipped.</p>")
+	assert.Contains(t, body, `<p class="footer">Sent to jane@example.com</p>`)
+	assert.Contains(t, body, "<style>")
+
+	text, err := views.Render("orders/shipped.txt", data)
+	require.NoError(t, err)
+	assert.Equal(t, "Hi <Jane>, your order shipped.-- Acme", text)
+
+	body, err = views.Render("standalone", data)
+	require.NoError(t, err)
+	assert.Equal(t, "<b>&lt;Jane&gt;</b>", body)
+
+	assert.True(t, views.Has("orders/unstyled"))
+	assert.False(t, views.Has("notes/ignored"))
+	_, err = views.Render("missing", data)
+	assert.Error(t, err)
+}
+
+func TestViewsLayoutNotFound(t *testing.T) {
+	views := newTestViews(t)
+	views.SetLayout("layouts/marketing")
+
+	_, err := views.Render("orders/cancelled", nil)
+	assert.ErrorContains(t, err, "layout [layouts/marketing]")
+}
+
+func TestViewsInlineCSS(t *testing.T) {
+	views := newTestViews(t)
+	views.SetInlineCSS(true)
+
+	body, err := views.Render("orders/shipped", map[string]string{"Name": "Jane", "Email": "jane@example.com"})
+	require.NoError(t, err)
+	assert.NotContains(t, body, "<style>")
+	assert.Contains(t, body, `<p style="color: #333">Hi Jane, your order shipped.</p>`)
+	assert.Contains(t, body, `<p class="footer" style="color: #333; font-size: 12px">`)
+}
+
+func TestInlineCSS(t *testing.T) {
+	document := `<html><head><style>
+		/* base */
+		td a.button { background: blue }
+		a { color: red; }
+		#hero { margin: 0 } h1, h2 { padding: 0 }
+		@media (max-width: 600px) { a { display: block } }
+	</style></head><body>
+		<h1 id="hero">Hi</h1>
+		<table><tr><td><a class="button" href="#" style="color: white">Go</a></td></tr></table>
+		<a href="#">Plain</a>
+	</body></html>`
+
+	inlined, err := mail.InlineCSS(document)
+	require.NoError(t, err)
+	assert.Contains(t, inlined, `<h1 id="hero" style="padding: 0; margin: 0">`)
+	assert.Contains(t, inlined, `<a class="button" href="#" style="color: red; background: blue; color: white">Go</a>`)
+	assert.Contains(t, inlined, `<a href="#" style="color: red">Plain</a>`)
+	assert.Contains(t, inlined, "@media (max-width: 600px) { a { display: block } }")
+}
+
+type shippedViewEmail struct {
+	Name string
+}
+
+func (m shippedViewEmail) Build() (*mail.Message, error) {
+	return &mail.Message{
+		To:       []string{"jane@example.com"},
+		Subject:  "Shipped",
+		View:     "orders/shipped",
+		TextView: "orders/shipped.txt",
+		Data:     map[string]string{"Name": m.Name, "Email": "jane@example.com"},
+	}, nil
+}
+
+func TestMailerRendersViews(t *testing.T) {
+	mailer, transport, _ := newMailer()
+
+	_, err := mailer.Send(context.Background(), shippedViewEmail{Name: "Jane"})
+	assert.ErrorContains(t, err, "no views")
+
+	mailer.SetViews(newTestViews(t))
+	_, err = mailer.Send(context.Background(), shippedViewEmail{Name: "Jane"})
+	require.NoError(t, err)
+
+	messages := transport.Messages()
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0].HTML, "<p>Hi Jane, your order shipped.</p>")
+	assert.Equal(t, "Hi Jane, your order shipped.-- Acme", messages[0].Text)
+}
@@ -0,0 +1,69 @@
+// Package metrics records application-level metrics such as business events,
+// and sends them to Prometheus, StatsD or an OTLP collector alongside
+// infrastructure metrics.
+package metrics
+
+import (
+	"sort"
+	"strings"
+)
+
+// Tags are dimensions attached to a measurement.
+type Tags map[string]string
+
+// Driver records measurements.
+type Driver interface {
+	// Count adds value to a monotonically increasing counter.
+	Count(name string, value float64, tags Tags)
+
+	// Gauge sets the current value of a gauge.
+	Gauge(name string, value float64, tags Tags)
+
+	// Histogram records a value in a distribution, such as a duration or size.
+	Histogram(name string, value float64, tags Tags)
+
+	// Close flushes pending measurements and releases resources.
+	Close() error
+}
+
+// Null discards all measurements.
+type Null struct{}
+
+func (Null) Count(name string, value float64, tags Tags)     {}
+func (Null) Gauge(name string, value float64, tags Tags)     {}
+func (Null) Histogram(name string, value float64, tags Tags) {}
+func (Null) Close() error                                    { return nil }
+
+// mergeTags combines tag sets, later sets taking precedence.
+func mergeTags(sets ...Tags) Tags {
+	merged := Tags{}
+	for _, set := range sets {
+		for key, value := range set {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// sortedKeys returns the tag keys in order, so series are identified stably.
+func (t Tags) sortedKeys() []string {
+	keys := make([]string, 0, len(t))
+	for key := range t {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// seriesKey identifies a metric name with a set of tags.
+func seriesKey(name string, tags Tags) string {
+	var b strings.Builder
+	b.WriteString(name)
+	for _, key := range tags.sortedKeys() {
+		b.WriteByte(0)
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(tags[key])
+	}
+	return b.String()
+}
@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorderWithRegistry(t *testing.T) {
+	registry := NewRegistry(0.1, 1)
+	recorder := New(registry, Tags{"env": "test"})
+
+	recorder.Count("orders.placed")
+	recorder.Count("orders.placed", Tags{"plan": "pro"})
+	recorder.Add("orders.placed", 2, Tags{"plan": "pro"})
+	recorder.Gauge("carts.open", 5)
+	recorder.Gauge("carts.open", 3)
+	recorder.Histogram("checkout.seconds", 0.05)
+	recorder.Histogram("checkout.seconds", 0.5)
+	recorder.Since("checkout.seconds", time.Now())
+
+	assert.Equal(t, 1.0, registry.Value("orders.placed", Tags{"env": "test"}))
+	assert.Equal(t, 3.0, registry.Value("orders.placed", Tags{"env": "test", "plan": "pro"}))
+	assert.Equal(t, 3.0, registry.Value("carts.open", Tags{"env": "test"}))
+	assert.Equal(t, uint64(3), registry.Observations("checkout.seconds", Tags{"env": "test"}))
+	assert.Equal(t, 0.0, registry.Value("missing"))
+
+	for _, s := range registry.Snapshot() {
+		if s.Kind == KindHistogram {
+			assert.Equal(t, []float64{0.1, 1}, s.Bounds)
+			assert.Equal(t, []uint64{2, 3}, s.Buckets)
+		}
+	}
+
+	registry.Reset()
+	assert.Empty(t, registry.Snapshot())
+}
+
+func TestPrometheusExposition(t *testing.T) {
+	prom := NewPrometheus("app", 0.5)
+	prom.Count("orders.placed", 2, Tags{"plan": `pro "annual"`})
+	prom.Gauge("queue.depth", 7, nil)
+	prom.Histogram("checkout.seconds", 0.25, nil)
+	prom.Histogram("checkout.seconds", 2, nil)
+
+	rec := httptest.NewRecorder()
+	prom.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+	assert.Equal(t, `# TYPE app_checkout_seconds histogram
+app_checkout_seconds_bucket{le="0.5"} 1
+app_checkout_seconds_bucket{le="+Inf"} 2
+app_checkout_seconds_sum 2.25
+app_checkout_seconds_count 2
+# TYPE app_orders_placed_total counter
+app_orders_placed_total{plan="pro \"annual\""} 2
+# TYPE app_queue_depth gauge
+app_queue_depth 7
+`, rec.Body.String())
+}
+
+func TestStatsD(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	statsd, err := NewStatsD(conn.LocalAddr().String(), "app")
+	require.NoError(t, err)
+	defer statsd.Close()
+
+	read := func() string {
+		buf := make([]byte, 512)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		return string(buf[:n])
+	}
+
+	statsd.Count("orders.placed", 1, Tags{"plan": "pro", "env": "prod"})
+	assert.Equal(t, "app.orders.placed:1|c|#env:prod,plan:pro", read())
+
+	statsd.Gauge("queue.depth", 7.5, nil)
+	assert.Equal(t, "app.queue.depth:7.5|g", read())
+
+	statsd.Histogram("checkout|seconds", 0.2, nil)
+	assert.Equal(t, "app.checkout_seconds:0.2|h", read())
+}
+
+func TestOTLPExport(t *testing.T) {
+	var payload map[string]any
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/metrics", r.URL.Path)
+		auth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &payload))
+	}))
+	defer server.Close()
+
+	otlp, err := NewOTLP(OTLPOptions{
+		Endpoint:    server.URL,
+		Headers:     map[string]string{"Authorization": "Bearer token"},
+		ServiceName: "shop",
+		Interval:    time.Hour,
+		Buckets:     []float64{1},
+	})
+	require.NoError(t, err)
+
+	otlp.Count("orders.placed", 3, Tags{"plan": "pro"})
+	otlp.Histogram("checkout.seconds", 0.5, nil)
+	otlp.Histogram("checkout.seconds", 4, nil)
+	require.NoError(t, otlp.Close())
+
+	assert.Equal(t, "Bearer token", auth)
+	encoded, _ := json.Marshal(payload)
+	body := string(encoded)
+	assert.Contains(t, body, `"stringValue":"shop"`)
+	assert.Contains(t, body, `"name":"orders.placed","sum":{"aggregationTemporality":2,"dataPoints":[{"asDouble":3,"attributes":[{"key":"plan","value":{"stringValue":"pro"}}]`)
+	assert.Contains(t, body, `"bucketCounts":["1","1"],"count":"2","explicitBounds":[1]`)
+	assert.Contains(t, body, `"isMonotonic":true`)
+}
+
+func TestOTLPExportErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	otlp, err := NewOTLP(OTLPOptions{Endpoint: server.URL, Interval: time.Hour})
+	require.NoError(t, err)
+	defer otlp.Close()
+
+	// Nothing recorded: nothing sent.
+	require.NoError(t, otlp.Flush(context.Background()))
+
+	otlp.Count("orders.placed", 1, nil)
+	err = otlp.Flush(context.Background())
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "429"), err.Error())
+
+	_, err = NewOTLP(OTLPOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,271 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OTLPOptions configures an OTLP driver.
+type OTLPOptions struct {
+	// Endpoint is the collector's base URL, such as http://localhost:4318.
+	// Metrics are posted to Endpoint + "/v1/metrics".
+	Endpoint string
+
+	// Headers are sent with every export, for example for authentication.
+	Headers map[string]string
+
+	// ServiceName is reported as the service.name resource attribute.
+	ServiceName string
+
+	// Interval between exports. Defaults to one minute.
+	Interval time.Duration
+
+	// Buckets are histogram bounds. Defaults to DefaultBuckets.
+	Buckets []float64
+
+	// Client defaults to a client with a 10 second timeout.
+	Client *http.Client
+}
+
+// OTLP aggregates measurements and periodically exports them to an
+// OpenTelemetry collector over OTLP/HTTP with JSON encoding. Values are
+// exported with cumulative temporality.
+type OTLP struct {
+	*Registry
+	options OTLPOptions
+	start   time.Time
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewOTLP creates an OTLP driver and starts exporting every interval.
+func NewOTLP(options OTLPOptions) (*OTLP, error) {
+	if options.Endpoint == "" {
+		return nil, fmt.Errorf("metrics: otlp driver requires an endpoint")
+	}
+	if options.Interval <= 0 {
+		options.Interval = time.Minute
+	}
+	if options.Client == nil {
+		options.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	o := &OTLP{
+		Registry: NewRegistry(options.Buckets...),
+		options:  options,
+		start:    time.Now(),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go o.run()
+	return o, nil
+}
+
+func (o *OTLP) run() {
+	defer close(o.done)
+
+	ticker := time.NewTicker(o.options.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-o.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), o.options.Interval)
+			o.Flush(ctx)
+			cancel()
+		}
+	}
+}
+
+// Flush exports the current metrics.
+func (o *OTLP) Flush(ctx context.Context) error {
+	series := o.Snapshot()
+	if len(series) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(o.payload(series, time.Now()))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(o.options.Endpoint, "/")+"/v1/metrics", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range o.options.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := o.options.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("metrics: otlp export returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// Close stops the export loop and exports once more.
+func (o *OTLP) Close() error {
+	var err error
+	o.closeOnce.Do(func() {
+		close(o.stop)
+		<-o.done
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err = o.Flush(ctx)
+	})
+	return err
+}
+
+// OTLP JSON types, following the protobuf JSON mapping: 64-bit integers
+// are encoded as strings.
+type (
+	otlpPayload struct {
+		ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+	}
+	otlpResourceMetrics struct {
+		Resource     otlpResource       `json:"resource"`
+		ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+	otlpScopeMetrics struct {
+		Scope   otlpScope    `json:"scope"`
+		Metrics []otlpMetric `json:"metrics"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpAttribute struct {
+		Key   string          `json:"key"`
+		Value otlpStringValue `json:"value"`
+	}
+	otlpStringValue struct {
+		StringValue string `json:"stringValue"`
+	}
+	otlpMetric struct {
+		Name      string         `json:"name"`
+		Sum       *otlpSum       `json:"sum,omitempty"`
+		Gauge     *otlpGauge     `json:"gauge,omitempty"`
+		Histogram *otlpHistogram `json:"histogram,omitempty"`
+	}
+	otlpSum struct {
+		DataPoints             []otlpNumberPoint `json:"dataPoints"`
+		AggregationTemporality int               `json:"aggregationTemporality"`
+		IsMonotonic            bool              `json:"isMonotonic"`
+	}
+	otlpGauge struct {
+		DataPoints []otlpNumberPoint `json:"dataPoints"`
+	}
+	otlpHistogram struct {
+		DataPoints             []otlpHistogramPoint `json:"dataPoints"`
+		AggregationTemporality int                  `json:"aggregationTemporality"`
+	}
+	otlpNumberPoint struct {
+		Attributes        []otlpAttribute `json:"attributes"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		TimeUnixNano      string          `json:"timeUnixNano"`
+		AsDouble          float64         `json:"asDouble"`
+	}
+	otlpHistogramPoint struct {
+		Attributes        []otlpAttribute `json:"attributes"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		TimeUnixNano      string          `json:"timeUnixNano"`
+		Count             string          `json:"count"`
+		Sum               float64         `json:"sum"`
+		BucketCounts      []string        `json:"bucketCounts"`
+		ExplicitBounds    []float64       `json:"explicitBounds"`
+	}
+)
+
+// temporalityCumulative is AGGREGATION_TEMPORALITY_CUMULATIVE.
+const temporalityCumulative = 2
+
+// payload builds an export request from a snapshot.
+func (o *OTLP) payload(series []Series, now time.Time) otlpPayload {
+	start := strconv.FormatInt(o.start.UnixNano(), 10)
+	end := strconv.FormatInt(now.UnixNano(), 10)
+
+	metrics := make([]otlpMetric, 0, len(series))
+	for _, s := range series {
+		attributes := otlpAttributes(s.Tags)
+		metric := otlpMetric{Name: s.Name}
+
+		switch s.Kind {
+		case KindCounter:
+			metric.Sum = &otlpSum{
+				DataPoints:             []otlpNumberPoint{{attributes, start, end, s.Value}},
+				AggregationTemporality: temporalityCumulative,
+				IsMonotonic:            true,
+			}
+		case KindGauge:
+			metric.Gauge = &otlpGauge{
+				DataPoints: []otlpNumberPoint{{attributes, start, end, s.Value}},
+			}
+		case KindHistogram:
+			// OTLP counts each bucket separately, with a final overflow bucket.
+			counts := make([]string, len(s.Buckets)+1)
+			var previous uint64
+			for i, cumulative := range s.Buckets {
+				counts[i] = strconv.FormatUint(cumulative-previous, 10)
+				previous = cumulative
+			}
+			counts[len(s.Buckets)] = strconv.FormatUint(s.Count-previous, 10)
+
+			metric.Histogram = &otlpHistogram{
+				DataPoints: []otlpHistogramPoint{{
+					Attributes:        attributes,
+					StartTimeUnixNano: start,
+					TimeUnixNano:      end,
+					Count:             strconv.FormatUint(s.Count, 10),
+					Sum:               s.Sum,
+					BucketCounts:      counts,
+					ExplicitBounds:    s.Bounds,
+				}},
+				AggregationTemporality: temporalityCumulative,
+			}
+		}
+		metrics = append(metrics, metric)
+	}
+
+	var resource []otlpAttribute
+	if o.options.ServiceName != "" {
+		resource = otlpAttributes(Tags{"service.name": o.options.ServiceName})
+	}
+
+	return otlpPayload{ResourceMetrics: []otlpResourceMetrics{{
+		Resource: otlpResource{Attributes: resource},
+		ScopeMetrics: []otlpScopeMetrics{{
+			Scope:   otlpScope{Name: "github.com/genesysflow/go-genesys/metrics"},
+			Metrics: metrics,
+		}},
+	}}}
+}
+
+func otlpAttributes(tags Tags) []otlpAttribute {
+	attributes := make([]otlpAttribute, 0, len(tags))
+	for _, key := range tags.sortedKeys() {
+		attributes = append(attributes, otlpAttribute{Key: key, Value: otlpStringValue{StringValue: tags[key]}})
+	}
+	return attributes
+}
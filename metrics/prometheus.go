@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Prometheus aggregates measurements for scraping in the Prometheus text
+// exposition format. Serve Handler on a route such as /metrics.
+type Prometheus struct {
+	*Registry
+	namespace string
+}
+
+// NewPrometheus creates a Prometheus driver. Metric names are prefixed with
+// namespace, if set.
+func NewPrometheus(namespace string, buckets ...float64) *Prometheus {
+	return &Prometheus{
+		Registry:  NewRegistry(buckets...),
+		namespace: namespace,
+	}
+}
+
+// Handler serves the current metrics.
+func (p *Prometheus) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		p.WriteTo(w)
+	})
+}
+
+// WriteTo writes the current metrics in the text exposition format.
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	out := &countingWriter{w: w}
+	b := bufio.NewWriter(out)
+
+	lastName := ""
+	for _, s := range p.Snapshot() {
+		name := p.metricName(s.Name)
+		if s.Kind == KindCounter {
+			name += "_total"
+		}
+		if name != lastName {
+			fmt.Fprintf(b, "# TYPE %s %s\n", name, s.Kind)
+			lastName = name
+		}
+
+		switch s.Kind {
+		case KindHistogram:
+			for i, bound := range s.Bounds {
+				fmt.Fprintf(b, "%s_bucket%s %d\n", name, promLabels(s.Tags, "le", formatFloat(bound)), s.Buckets[i])
+			}
+			fmt.Fprintf(b, "%s_bucket%s %d\n", name, promLabels(s.Tags, "le", "+Inf"), s.Count)
+			fmt.Fprintf(b, "%s_sum%s %s\n", name, promLabels(s.Tags), formatFloat(s.Sum))
+			fmt.Fprintf(b, "%s_count%s %d\n", name, promLabels(s.Tags), s.Count)
+		default:
+			fmt.Fprintf(b, "%s%s %s\n", name, promLabels(s.Tags), formatFloat(s.Value))
+		}
+	}
+
+	err := b.Flush()
+	return out.n, err
+}
+
+// metricName converts a dotted name such as "orders.placed" to a valid
+// Prometheus name such as "app_orders_placed".
+func (p *Prometheus) metricName(name string) string {
+	if p.namespace != "" {
+		name = p.namespace + "_" + name
+	}
+	return sanitizeName(name)
+}
+
+// sanitizeName replaces characters not allowed in Prometheus names and labels.
+func sanitizeName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// promLabels formats tags, plus optional extra label pairs, as {k="v",...}.
+func promLabels(tags Tags, extra ...string) string {
+	if len(tags) == 0 && len(extra) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(tags)+len(extra)/2)
+	for _, key := range tags.sortedKeys() {
+		pairs = append(pairs, sanitizeName(key)+"="+quoteLabel(tags[key]))
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+"="+quoteLabel(extra[i+1]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// labelEscaper escapes label values as the exposition format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quoteLabel(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
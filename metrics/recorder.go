@@ -0,0 +1,61 @@
+package metrics
+
+import "time"
+
+// Recorder is the application-facing metrics API. It adds default tags,
+// such as the environment, to every measurement before passing it to the
+// configured driver.
+type Recorder struct {
+	driver Driver
+	tags   Tags
+}
+
+// New creates a recorder that sends measurements to driver with tags attached.
+func New(driver Driver, tags Tags) *Recorder {
+	if driver == nil {
+		driver = Null{}
+	}
+	return &Recorder{
+		driver: driver,
+		tags:   tags,
+	}
+}
+
+// Driver returns the underlying driver.
+func (r *Recorder) Driver() Driver {
+	return r.driver
+}
+
+// Count increments a counter by one.
+func (r *Recorder) Count(name string, tags ...Tags) {
+	r.Add(name, 1, tags...)
+}
+
+// Add increments a counter by value.
+func (r *Recorder) Add(name string, value float64, tags ...Tags) {
+	r.driver.Count(name, value, r.merge(tags))
+}
+
+// Gauge sets a gauge to value.
+func (r *Recorder) Gauge(name string, value float64, tags ...Tags) {
+	r.driver.Gauge(name, value, r.merge(tags))
+}
+
+// Histogram records value in a histogram.
+func (r *Recorder) Histogram(name string, value float64, tags ...Tags) {
+	r.driver.Histogram(name, value, r.merge(tags))
+}
+
+// Since records the seconds elapsed since start in a histogram.
+func (r *Recorder) Since(name string, start time.Time, tags ...Tags) {
+	r.Histogram(name, time.Since(start).Seconds(), tags...)
+}
+
+// Close flushes and closes the driver.
+func (r *Recorder) Close() error {
+	return r.driver.Close()
+}
+
+func (r *Recorder) merge(tags []Tags) Tags {
+	return mergeTags(append([]Tags{r.tags}, tags...)...)
+}
@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+)
+
+// Kind is the type of a metric.
+type Kind string
+
+const (
+	KindCounter   Kind = "counter"
+	KindGauge     Kind = "gauge"
+	KindHistogram Kind = "histogram"
+)
+
+// DefaultBuckets are histogram upper bounds suited to durations in seconds.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Series is the aggregated state of one metric and tag set.
+type Series struct {
+	Name  string
+	Tags  Tags
+	Kind  Kind
+	Value float64 // counter total or last gauge value
+
+	// Histogram state. Buckets holds cumulative counts for each upper
+	// bound in Bounds.
+	Count   uint64
+	Sum     float64
+	Bounds  []float64
+	Buckets []uint64
+}
+
+// Registry aggregates measurements in memory. It backs the Prometheus and
+// OTLP drivers, and is the driver returned by the facade's Fake.
+type Registry struct {
+	series  map[string]*Series
+	buckets []float64
+	mu      sync.Mutex
+}
+
+// NewRegistry creates a registry. Histograms use buckets, or DefaultBuckets if none are given.
+func NewRegistry(buckets ...float64) *Registry {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &Registry{
+		series:  make(map[string]*Series),
+		buckets: buckets,
+	}
+}
+
+// Count adds value to a counter. Negative values are ignored.
+func (r *Registry) Count(name string, value float64, tags Tags) {
+	if value < 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.get(name, tags, KindCounter).Value += value
+}
+
+// Gauge sets a gauge.
+func (r *Registry) Gauge(name string, value float64, tags Tags) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.get(name, tags, KindGauge).Value = value
+}
+
+// Histogram records a value in a histogram.
+func (r *Registry) Histogram(name string, value float64, tags Tags) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.get(name, tags, KindHistogram)
+	s.Count++
+	s.Sum += value
+	for i, bound := range s.Bounds {
+		if value <= bound {
+			s.Buckets[i]++
+		}
+	}
+}
+
+// Close is a no-op.
+func (r *Registry) Close() error {
+	return nil
+}
+
+// get returns a series, creating it on first use. Callers hold the lock.
+func (r *Registry) get(name string, tags Tags, kind Kind) *Series {
+	key := string(kind) + "\x00" + seriesKey(name, tags)
+	s, ok := r.series[key]
+	if !ok {
+		s = &Series{Name: name, Tags: mergeTags(tags), Kind: kind}
+		if kind == KindHistogram {
+			s.Bounds = r.buckets
+			s.Buckets = make([]uint64, len(r.buckets))
+		}
+		r.series[key] = s
+	}
+	return s
+}
+
+// Snapshot returns a copy of every series, sorted by name and tags.
+func (r *Registry) Snapshot() []Series {
+	r.mu.Lock()
+	keys := make([]string, 0, len(r.series))
+	for key := range r.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	snapshot := make([]Series, 0, len(keys))
+	for _, key := range keys {
+		s := *r.series[key]
+		s.Tags = mergeTags(s.Tags)
+		s.Buckets = append([]uint64(nil), s.Buckets...)
+		snapshot = append(snapshot, s)
+	}
+	r.mu.Unlock()
+
+	sort.SliceStable(snapshot, func(i, j int) bool {
+		return snapshot[i].Name < snapshot[j].Name
+	})
+	return snapshot
+}
+
+// Value returns a counter total or gauge value, or 0 if it wasn't recorded.
+func (r *Registry) Value(name string, tags ...Tags) float64 {
+	key := seriesKey(name, mergeTags(tags...))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, kind := range []Kind{KindCounter, KindGauge} {
+		if s, ok := r.series[string(kind)+"\x00"+key]; ok {
+			return s.Value
+		}
+	}
+	return 0
+}
+
+// Observations returns how many values a histogram recorded.
+func (r *Registry) Observations(name string, tags ...Tags) uint64 {
+	key := string(KindHistogram) + "\x00" + seriesKey(name, mergeTags(tags...))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.series[key]; ok {
+		return s.Count
+	}
+	return 0
+}
+
+// Reset removes all series.
+func (r *Registry) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.series = make(map[string]*Series)
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"net"
+	"strings"
+	"sync"
+)
+
+// StatsD sends measurements over UDP to a StatsD or DogStatsD agent. Tags
+// are sent in the DogStatsD format (|#key:value), which plain StatsD
+// servers ignore.
+type StatsD struct {
+	conn   net.Conn
+	prefix string
+	mu     sync.Mutex
+}
+
+// NewStatsD creates a StatsD driver sending to addr (host:port). Metric
+// names are prefixed with prefix, if set.
+func NewStatsD(addr, prefix string) (*StatsD, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, ".") {
+		prefix += "."
+	}
+
+	return &StatsD{
+		conn:   conn,
+		prefix: prefix,
+	}, nil
+}
+
+// Count sends a counter increment.
+func (s *StatsD) Count(name string, value float64, tags Tags) {
+	s.send(name, value, "c", tags)
+}
+
+// Gauge sends a gauge value.
+func (s *StatsD) Gauge(name string, value float64, tags Tags) {
+	s.send(name, value, "g", tags)
+}
+
+// Histogram sends a histogram value.
+func (s *StatsD) Histogram(name string, value float64, tags Tags) {
+	s.send(name, value, "h", tags)
+}
+
+// Close closes the UDP socket.
+func (s *StatsD) Close() error {
+	return s.conn.Close()
+}
+
+// send writes one datagram. Delivery is best effort, as with any StatsD client.
+func (s *StatsD) send(name string, value float64, kind string, tags Tags) {
+	var b strings.Builder
+	b.WriteString(s.prefix)
+	b.WriteString(statsdEscaper.Replace(name))
+	b.WriteByte(':')
+	b.WriteString(formatFloat(value))
+	b.WriteByte('|')
+	b.WriteString(kind)
+
+	for i, key := range tags.sortedKeys() {
+		if i == 0 {
+			b.WriteString("|#")
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(statsdEscaper.Replace(key))
+		b.WriteByte(':')
+		b.WriteString(statsdEscaper.Replace(tags[key]))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conn.Write([]byte(b.String()))
+}
+
+// statsdEscaper replaces characters that delimit the StatsD line format.
+var statsdEscaper = strings.NewReplacer(":", "_", "|", "_", "@", "_", ",", "_", "#", "_", "\n", "_")
@@ -0,0 +1,78 @@
+// Package notification sends notifications to users over channels such as SMS.
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Notifiable is implemented by anything that can receive notifications.
+type Notifiable interface {
+	// RouteNotificationFor returns the address to use for a channel, such as
+	// a phone number for "sms". An empty route skips the channel.
+	RouteNotificationFor(channel string) string
+}
+
+// Notification is a message that can be delivered over one or more channels.
+type Notification interface {
+	// Via returns the channels the notification is sent on for a notifiable.
+	Via(notifiable Notifiable) []string
+}
+
+// Channel delivers notifications.
+type Channel interface {
+	Send(ctx context.Context, notifiable Notifiable, notification Notification) error
+}
+
+// Manager routes notifications to their channels.
+type Manager struct {
+	channels map[string]Channel
+	mu       sync.RWMutex
+}
+
+// NewManager creates a new notification manager.
+func NewManager() *Manager {
+	return &Manager{
+		channels: make(map[string]Channel),
+	}
+}
+
+// Extend registers a channel under a name.
+func (m *Manager) Extend(name string, channel Channel) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.channels[name] = channel
+}
+
+// Channel returns a registered channel.
+func (m *Manager) Channel(name string) (Channel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	channel, ok := m.channels[name]
+	if !ok {
+		return nil, fmt.Errorf("notification: channel %s is not registered", name)
+	}
+	return channel, nil
+}
+
+// Send sends a notification to each notifiable on the channels it selects.
+// Delivery continues after a failure; all errors are returned joined.
+func (m *Manager) Send(ctx context.Context, notification Notification, notifiables ...Notifiable) error {
+	var errs []error
+	for _, notifiable := range notifiables {
+		for _, name := range notification.Via(notifiable) {
+			channel, err := m.Channel(name)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if err := channel.Send(ctx, notifiable, notification); err != nil {
+				errs = append(errs, fmt.Errorf("notification: %s channel: %w", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,306 @@
+package notification_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/genesysflow/go-genesys/events"
+	genesyshttp "github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/notification"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	phone string
+}
+
+func (u user) RouteNotificationFor(channel string) string {
+	if channel == notification.SMSChannelName {
+		return u.phone
+	}
+	return ""
+}
+
+type orderShipped struct {
+	channels []string
+}
+
+func (n orderShipped) Via(notifiable notification.Notifiable) []string {
+	if n.channels != nil {
+		return n.channels
+	}
+	return []string{notification.SMSChannelName}
+}
+
+func (n orderShipped) ToSMS(notifiable notification.Notifiable) *notification.SMSMessage {
+	return &notification.SMSMessage{Content: "Your order has shipped"}
+}
+
+func TestManagerSendsSMSToEachNotifiable(t *testing.T) {
+	driver := notification.NewArraySMSDriver()
+	manager := notification.NewManager()
+	manager.Extend(notification.SMSChannelName, notification.NewSMSChannel(driver, "+15550000000"))
+
+	err := manager.Send(context.Background(), orderShipped{},
+		user{phone: "+15551111111"},
+		user{phone: ""}, // no route, skipped
+		user{phone: "+15552222222"},
+	)
+	require.NoError(t, err)
+
+	messages := driver.Messages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, "+15551111111", messages[0].To)
+	assert.Equal(t, "+15550000000", messages[0].From)
+	assert.Equal(t, "Your order has shipped", messages[0].Content)
+	assert.Equal(t, "+15552222222", messages[1].To)
+}
+
+func TestManagerUnknownChannel(t *testing.T) {
+	manager := notification.NewManager()
+	err := manager.Send(context.Background(), orderShipped{channels: []string{"pager"}}, user{phone: "+15551111111"})
+	assert.ErrorContains(t, err, "pager")
+}
+
+func TestSMSChannelRateLimitPerRecipient(t *testing.T) {
+	driver := notification.NewArraySMSDriver()
+	channel := notification.NewSMSChannel(driver, "Acme")
+	channel.SetRateLimit(2, time.Minute)
+
+	ctx := context.Background()
+	alice := user{phone: "+15551111111"}
+	bob := user{phone: "+15552222222"}
+
+	require.NoError(t, channel.Send(ctx, alice, orderShipped{}))
+	require.NoError(t, channel.Send(ctx, alice, orderShipped{}))
+	assert.ErrorIs(t, channel.Send(ctx, alice, orderShipped{}), notification.ErrRateLimited)
+	require.NoError(t, channel.Send(ctx, bob, orderShipped{}))
+	assert.Len(t, driver.Messages(), 3)
+
+	channel.SetRateLimit(0, 0)
+	require.NoError(t, channel.Send(ctx, alice, orderShipped{}))
+}
+
+func TestTwilioSend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", r.URL.Path)
+		sid, token, _ := r.BasicAuth()
+		assert.Equal(t, "AC123", sid)
+		assert.Equal(t, "secret", token)
+
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "+15551111111", r.PostForm.Get("To"))
+		assert.Equal(t, "+15550000000", r.PostForm.Get("From"))
+		assert.Equal(t, "hello", r.PostForm.Get("Body"))
+		assert.Equal(t, "https://app.example.com/sms/status", r.PostForm.Get("StatusCallback"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"sid":"SM42","status":"queued"}`))
+	}))
+	defer server.Close()
+
+	driver := &notification.Twilio{
+		AccountSID:     "AC123",
+		AuthToken:      "secret",
+		StatusCallback: "https://app.example.com/sms/status",
+		BaseURL:        server.URL,
+	}
+	id, err := driver.Send(context.Background(), &notification.SMSMessage{To: "+15551111111", From: "+15550000000", Content: "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, "SM42", id)
+}
+
+func TestTwilioSendError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":21211,"message":"Invalid 'To' Phone Number"}`))
+	}))
+	defer server.Close()
+
+	driver := &notification.Twilio{AccountSID: "AC123", AuthToken: "secret", BaseURL: server.URL}
+	_, err := driver.Send(context.Background(), &notification.SMSMessage{To: "nope", Content: "hello"})
+	assert.ErrorContains(t, err, "21211")
+}
+
+// statusApp serves a channel's status handler and records dispatched events.
+func statusApp(channel *notification.SMSChannel) (*fiber.App, *[]events.Event) {
+	dispatched := &[]events.Event{}
+	dispatcher := events.NewDispatcher()
+	record := func(e events.Event) error {
+		*dispatched = append(*dispatched, e)
+		return nil
+	}
+	dispatcher.Listen("notification.sms.delivered", record)
+	dispatcher.Listen("notification.sms.failed", record)
+	dispatcher.Listen("notification.sms.status", record)
+	channel.SetDispatcher(dispatcher)
+
+	app := fiber.New()
+	handler := channel.StatusHandler()
+	app.All("/sms/status", func(c *fiber.Ctx) error {
+		return handler(genesyshttp.NewContext(c, nil))
+	})
+	return app, dispatched
+}
+
+func twilioSignature(token, callbackURL string, form url.Values) string {
+	// Twilio signs the URL followed by each parameter name and value, sorted by name.
+	payload := callbackURL
+	for _, key := range []string{"ErrorCode", "MessageSid", "MessageStatus", "To"} {
+		payload += key + form.Get(key)
+	}
+	mac := hmac.New(sha1.New, []byte(token))
+	mac.Write([]byte(payload))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestTwilioStatusCallback(t *testing.T) {
+	driver := &notification.Twilio{AuthToken: "secret", StatusCallback: "https://app.example.com/sms/status"}
+	app, dispatched := statusApp(notification.NewSMSChannel(driver, ""))
+
+	form := url.Values{
+		"MessageSid":    {"SM42"},
+		"MessageStatus": {"undelivered"},
+		"To":            {"+15551111111"},
+		"ErrorCode":     {"30003"},
+	}
+	post := func(signature string) int {
+		req := httptest.NewRequest("POST", "/sms/status", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Twilio-Signature", signature)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, 401, post("bogus"))
+	assert.Empty(t, *dispatched)
+
+	assert.Equal(t, 200, post(twilioSignature("secret", driver.StatusCallback, form)))
+	require.Len(t, *dispatched, 1)
+	failed, ok := (*dispatched)[0].(*notification.SMSFailed)
+	require.True(t, ok)
+	assert.Equal(t, "SM42", failed.Status.MessageID)
+	assert.Equal(t, "+15551111111", failed.Status.To)
+	assert.Equal(t, "undelivered", failed.Status.ProviderStatus)
+	assert.Equal(t, "30003", failed.Status.ErrorCode)
+	assert.False(t, failed.Status.ReceivedAt.IsZero())
+}
+
+func TestVonageSend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/sms/json", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "key", r.PostForm.Get("api_key"))
+		assert.Equal(t, "15551111111", r.PostForm.Get("to"))
+
+		if r.PostForm.Get("text") == "bad" {
+			w.Write([]byte(`{"message-count":"1","messages":[{"status":"2","error-text":"Missing from param"}]}`))
+			return
+		}
+		w.Write([]byte(`{"message-count":"1","messages":[{"status":"0","message-id":"0A0000001"}]}`))
+	}))
+	defer server.Close()
+
+	driver := &notification.Vonage{APIKey: "key", APISecret: "secret", BaseURL: server.URL}
+	id, err := driver.Send(context.Background(), &notification.SMSMessage{To: "+15551111111", From: "Acme", Content: "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "0A0000001", id)
+
+	_, err = driver.Send(context.Background(), &notification.SMSMessage{To: "+15551111111", Content: "bad"})
+	assert.ErrorContains(t, err, "Missing from param")
+}
+
+func TestVonageDeliveryReceipt(t *testing.T) {
+	driver := &notification.Vonage{SignatureSecret: "sigsecret"}
+	app, dispatched := statusApp(notification.NewSMSChannel(driver, ""))
+
+	params := url.Values{
+		"messageId": {"0A0000001"},
+		"msisdn":    {"15551111111"},
+		"status":    {"delivered"},
+		"err-code":  {"0"},
+		"timestamp": {"1700000000"},
+	}
+	// md5hash signatures cover "&key=value" pairs sorted by key, followed by the secret.
+	sum := md5.Sum([]byte("&err-code=0&messageId=0A0000001&msisdn=15551111111&status=delivered&timestamp=1700000000sigsecret"))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/sms/status?"+params.Encode(), nil))
+	require.NoError(t, err)
+	assert.Equal(t, 401, resp.StatusCode)
+
+	params.Set("sig", hex.EncodeToString(sum[:]))
+	resp, err = app.Test(httptest.NewRequest("GET", "/sms/status?"+params.Encode(), nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	require.Len(t, *dispatched, 1)
+	delivered, ok := (*dispatched)[0].(*notification.SMSDelivered)
+	require.True(t, ok)
+	assert.Equal(t, "0A0000001", delivered.Status.MessageID)
+	assert.Equal(t, "15551111111", delivered.Status.To)
+	assert.Empty(t, delivered.Status.ErrorCode)
+}
+
+func TestVonageDeliveryReceiptJSON(t *testing.T) {
+	app, dispatched := statusApp(notification.NewSMSChannel(&notification.Vonage{}, ""))
+
+	req := httptest.NewRequest("POST", "/sms/status", strings.NewReader(`{"messageId":"0A0000002","msisdn":"15551111111","status":"accepted"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	require.Len(t, *dispatched, 1)
+	updated, ok := (*dispatched)[0].(*notification.SMSStatusUpdated)
+	require.True(t, ok)
+	assert.Equal(t, notification.SMSStatusSent, updated.Status.Status)
+}
+
+func TestStatusHandlerWithoutCallbacks(t *testing.T) {
+	app, _ := statusApp(notification.NewSMSChannel(notification.NewArraySMSDriver(), ""))
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/sms/status", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
+func TestSNSSend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/")
+		assert.Contains(t, r.Header.Get("Authorization"), "/us-east-1/sns/aws4_request")
+
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "Publish", r.PostForm.Get("Action"))
+		assert.Equal(t, "+15551111111", r.PostForm.Get("PhoneNumber"))
+		assert.Equal(t, "AWS.SNS.SMS.SMSType", r.PostForm.Get("MessageAttributes.entry.1.Name"))
+		assert.Equal(t, "Transactional", r.PostForm.Get("MessageAttributes.entry.1.Value.StringValue"))
+
+		w.Write([]byte(`<PublishResponse><PublishResult><MessageId>msg-1</MessageId></PublishResult></PublishResponse>`))
+	}))
+	defer server.Close()
+
+	driver := &notification.SNS{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		SMSType:     "Transactional",
+		Endpoint:    server.URL,
+	}
+	id, err := driver.Send(context.Background(), &notification.SMSMessage{To: "+15551111111", From: "Acme", Content: "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "msg-1", id)
+}
@@ -0,0 +1,162 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/genesysflow/go-genesys/events"
+)
+
+// SMSChannelName is the channel name notifications use to select SMS.
+const SMSChannelName = "sms"
+
+// ErrRateLimited is returned when a recipient has received too many messages
+// within the channel's rate limit window.
+var ErrRateLimited = errors.New("notification: sms rate limit exceeded")
+
+// SMSMessage is a text message.
+type SMSMessage struct {
+	// To is the recipient's phone number. Defaults to the notifiable's sms route.
+	To string
+
+	// From is the sender number or ID. Defaults to the channel's sender.
+	From string
+
+	Content string
+}
+
+// SMSNotification is implemented by notifications sent on the sms channel.
+type SMSNotification interface {
+	Notification
+	ToSMS(notifiable Notifiable) *SMSMessage
+}
+
+// SMSDriver sends text messages through a provider.
+type SMSDriver interface {
+	// Send sends a message and returns the provider's message ID.
+	Send(ctx context.Context, message *SMSMessage) (string, error)
+}
+
+// SMSChannel sends notifications as text messages.
+type SMSChannel struct {
+	driver     SMSDriver
+	from       string
+	dispatcher *events.Dispatcher
+	limiter    *smsLimiter
+	mu         sync.RWMutex
+}
+
+// NewSMSChannel creates an SMS channel. from is used when a message has no sender.
+func NewSMSChannel(driver SMSDriver, from string) *SMSChannel {
+	return &SMSChannel{
+		driver: driver,
+		from:   from,
+	}
+}
+
+// SetRateLimit limits how many messages a single phone number can receive
+// within window. A max of zero disables the limit.
+func (c *SMSChannel) SetRateLimit(max int, window time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if max <= 0 || window <= 0 {
+		c.limiter = nil
+		return
+	}
+	c.limiter = newSMSLimiter(max, window)
+}
+
+// SetDispatcher sets the dispatcher that receives delivery status events.
+func (c *SMSChannel) SetDispatcher(dispatcher *events.Dispatcher) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dispatcher = dispatcher
+}
+
+// Driver returns the channel's SMS driver.
+func (c *SMSChannel) Driver() SMSDriver {
+	return c.driver
+}
+
+// Send sends an SMS notification. Notifiables without an sms route are skipped.
+func (c *SMSChannel) Send(ctx context.Context, notifiable Notifiable, notification Notification) error {
+	sms, ok := notification.(SMSNotification)
+	if !ok {
+		return fmt.Errorf("notification: %T does not implement SMSNotification", notification)
+	}
+
+	message := sms.ToSMS(notifiable)
+	if message == nil {
+		return nil
+	}
+	if message.To == "" {
+		message.To = notifiable.RouteNotificationFor(SMSChannelName)
+	}
+	if message.To == "" {
+		return nil
+	}
+
+	_, err := c.SendMessage(ctx, message)
+	return err
+}
+
+// SendMessage sends a message directly and returns the provider's message ID.
+func (c *SMSChannel) SendMessage(ctx context.Context, message *SMSMessage) (string, error) {
+	if message.To == "" {
+		return "", fmt.Errorf("notification: sms message has no recipient")
+	}
+	if message.From == "" {
+		message.From = c.from
+	}
+
+	c.mu.RLock()
+	limiter := c.limiter
+	c.mu.RUnlock()
+
+	if limiter != nil && !limiter.allow(message.To) {
+		return "", ErrRateLimited
+	}
+
+	return c.driver.Send(ctx, message)
+}
+
+// smsLimiter is a sliding window limiter keyed by phone number.
+type smsLimiter struct {
+	max    int
+	window time.Duration
+	sent   map[string][]time.Time
+	mu     sync.Mutex
+}
+
+func newSMSLimiter(max int, window time.Duration) *smsLimiter {
+	return &smsLimiter{
+		max:    max,
+		window: window,
+		sent:   make(map[string][]time.Time),
+	}
+}
+
+// allow records a message to the recipient if it is within the limit.
+func (l *smsLimiter) allow(to string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	valid := l.sent[to][:0]
+	for _, t := range l.sent[to] {
+		if now.Sub(t) < l.window {
+			valid = append(valid, t)
+		}
+	}
+
+	if len(valid) >= l.max {
+		l.sent[to] = valid
+		return false
+	}
+	l.sent[to] = append(valid, now)
+	return true
+}
@@ -0,0 +1,491 @@
+package notification
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/genesysflow/go-genesys/contracts"
+)
+
+var defaultHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+func httpClient(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+	return defaultHTTPClient
+}
+
+// postForm sends a form request and decodes a JSON response into out.
+func postForm(ctx context.Context, client *http.Client, target string, form url.Values, auth func(*http.Request), out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if auth != nil {
+		auth(req)
+	}
+
+	resp, err := httpClient(client).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification: %s returned %d: %s", req.URL.Host, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
+
+// formParams returns the request's parameters from its query string and
+// url-encoded or JSON body.
+func formParams(req contracts.Request) (url.Values, error) {
+	u, err := url.Parse(req.FullURL())
+	if err != nil {
+		return nil, err
+	}
+	params := u.Query()
+
+	body := req.Body()
+	if len(body) == 0 {
+		return params, nil
+	}
+
+	if req.IsJSON() {
+		var fields map[string]any
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return nil, fmt.Errorf("notification: invalid callback body: %w", err)
+		}
+		for key, value := range fields {
+			switch v := value.(type) {
+			case string:
+				params.Set(key, v)
+			case float64:
+				params.Set(key, strconv.FormatFloat(v, 'f', -1, 64))
+			case bool:
+				params.Set(key, strconv.FormatBool(v))
+			}
+		}
+		return params, nil
+	}
+
+	fields, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("notification: invalid callback body: %w", err)
+	}
+	for key, values := range fields {
+		params[key] = values
+	}
+	return params, nil
+}
+
+// Twilio sends messages through the Twilio Messaging API.
+type Twilio struct {
+	AccountSID string
+	AuthToken  string
+
+	// StatusCallback is the URL Twilio posts delivery statuses to. It is also
+	// the URL callback signatures are verified against; if empty, the
+	// request's own URL is used.
+	StatusCallback string
+
+	// BaseURL defaults to https://api.twilio.com.
+	BaseURL string
+	Client  *http.Client
+}
+
+// Send sends a message and returns its SID.
+func (d *Twilio) Send(ctx context.Context, message *SMSMessage) (string, error) {
+	base := d.BaseURL
+	if base == "" {
+		base = "https://api.twilio.com"
+	}
+	target := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", strings.TrimRight(base, "/"), url.PathEscape(d.AccountSID))
+
+	form := url.Values{
+		"To":   {message.To},
+		"From": {message.From},
+		"Body": {message.Content},
+	}
+	if d.StatusCallback != "" {
+		form.Set("StatusCallback", d.StatusCallback)
+	}
+
+	var result struct {
+		SID string `json:"sid"`
+	}
+	err := postForm(ctx, d.Client, target, form, func(req *http.Request) {
+		req.SetBasicAuth(d.AccountSID, d.AuthToken)
+	}, &result)
+	if err != nil {
+		return "", err
+	}
+	return result.SID, nil
+}
+
+// ParseStatus verifies the X-Twilio-Signature header and parses a status callback.
+func (d *Twilio) ParseStatus(req contracts.Request) (*DeliveryStatus, error) {
+	params, err := url.ParseQuery(string(req.Body()))
+	if err != nil {
+		return nil, fmt.Errorf("notification: invalid callback body: %w", err)
+	}
+
+	callbackURL := d.StatusCallback
+	if callbackURL == "" {
+		callbackURL = req.FullURL()
+	}
+	if !d.validSignature(callbackURL, params, req.Header("X-Twilio-Signature")) {
+		return nil, ErrInvalidSignature
+	}
+
+	sid := params.Get("MessageSid")
+	if sid == "" {
+		return nil, fmt.Errorf("notification: callback has no MessageSid")
+	}
+
+	providerStatus := params.Get("MessageStatus")
+	status := SMSStatusQueued
+	switch providerStatus {
+	case "sending", "sent":
+		status = SMSStatusSent
+	case "delivered", "read":
+		status = SMSStatusDelivered
+	case "undelivered", "failed", "canceled":
+		status = SMSStatusFailed
+	}
+
+	return &DeliveryStatus{
+		MessageID:      sid,
+		To:             params.Get("To"),
+		Status:         status,
+		ProviderStatus: providerStatus,
+		ErrorCode:      params.Get("ErrorCode"),
+	}, nil
+}
+
+// validSignature checks a signature computed over the URL followed by the
+// sorted POST parameters.
+func (d *Twilio) validSignature(callbackURL string, params url.Values, signature string) bool {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(callbackURL)
+	for _, key := range keys {
+		for _, value := range params[key] {
+			b.WriteString(key + value)
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(d.AuthToken))
+	mac.Write([]byte(b.String()))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return signature != "" && hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// Vonage sends messages through the Vonage (Nexmo) SMS API.
+type Vonage struct {
+	APIKey    string
+	APISecret string
+
+	// SignatureSecret verifies signed delivery receipts. Leave empty if
+	// signed webhooks are not enabled on the account.
+	SignatureSecret string
+
+	// SignatureMethod is md5hash (default) or sha256.
+	SignatureMethod string
+
+	// BaseURL defaults to https://rest.nexmo.com.
+	BaseURL string
+	Client  *http.Client
+}
+
+// Send sends a message and returns its message ID.
+func (d *Vonage) Send(ctx context.Context, message *SMSMessage) (string, error) {
+	base := d.BaseURL
+	if base == "" {
+		base = "https://rest.nexmo.com"
+	}
+
+	form := url.Values{
+		"api_key":    {d.APIKey},
+		"api_secret": {d.APISecret},
+		"to":         {strings.TrimPrefix(message.To, "+")},
+		"from":       {message.From},
+		"text":       {message.Content},
+	}
+
+	var result struct {
+		Messages []struct {
+			Status    string `json:"status"`
+			MessageID string `json:"message-id"`
+			ErrorText string `json:"error-text"`
+		} `json:"messages"`
+	}
+	if err := postForm(ctx, d.Client, strings.TrimRight(base, "/")+"/sms/json", form, nil, &result); err != nil {
+		return "", err
+	}
+	if len(result.Messages) == 0 {
+		return "", fmt.Errorf("notification: vonage returned no messages")
+	}
+
+	// Long messages are split into parts; the first part identifies the message.
+	first := result.Messages[0]
+	if first.Status != "0" {
+		return "", fmt.Errorf("notification: vonage rejected message (status %s): %s", first.Status, first.ErrorText)
+	}
+	return first.MessageID, nil
+}
+
+// ParseStatus parses a delivery receipt sent as query parameters, a form or JSON.
+func (d *Vonage) ParseStatus(req contracts.Request) (*DeliveryStatus, error) {
+	params, err := formParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.SignatureSecret != "" && !d.validSignature(params) {
+		return nil, ErrInvalidSignature
+	}
+
+	messageID := params.Get("messageId")
+	if messageID == "" {
+		return nil, fmt.Errorf("notification: delivery receipt has no messageId")
+	}
+
+	providerStatus := params.Get("status")
+	status := SMSStatusSent
+	switch providerStatus {
+	case "buffered":
+		status = SMSStatusQueued
+	case "delivered":
+		status = SMSStatusDelivered
+	case "expired", "failed", "rejected":
+		status = SMSStatusFailed
+	}
+
+	errorCode := params.Get("err-code")
+	if errorCode == "0" {
+		errorCode = ""
+	}
+
+	return &DeliveryStatus{
+		MessageID:      messageID,
+		To:             params.Get("msisdn"),
+		Status:         status,
+		ProviderStatus: providerStatus,
+		ErrorCode:      errorCode,
+	}, nil
+}
+
+// validSignature checks the sig parameter of a signed delivery receipt.
+func (d *Vonage) validSignature(params url.Values) bool {
+	signature := params.Get("sig")
+	if signature == "" {
+		return false
+	}
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		if key != "sig" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	replacer := strings.NewReplacer("&", "_", "=", "_")
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString("&" + key + "=" + replacer.Replace(params.Get(key)))
+	}
+
+	var expected string
+	switch d.SignatureMethod {
+	case "", "md5hash":
+		sum := md5.Sum([]byte(b.String() + d.SignatureSecret))
+		expected = hex.EncodeToString(sum[:])
+	case "sha256":
+		mac := hmac.New(sha256.New, []byte(d.SignatureSecret))
+		mac.Write([]byte(b.String()))
+		expected = hex.EncodeToString(mac.Sum(nil))
+	default:
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(signature))) == 1
+}
+
+// SNS sends messages with Amazon SNS. SNS reports delivery status to
+// CloudWatch Logs rather than over HTTP, so it has no status callbacks.
+type SNS struct {
+	Region      string
+	Credentials aws.CredentialsProvider
+
+	// SMSType is Transactional or Promotional. Defaults to the account setting.
+	SMSType string
+
+	// Endpoint defaults to https://sns.<region>.amazonaws.com.
+	Endpoint string
+	Client   *http.Client
+}
+
+// Send publishes a message to a phone number and returns its message ID.
+// From is sent as the sender ID where the destination country supports one.
+func (d *SNS) Send(ctx context.Context, message *SMSMessage) (string, error) {
+	if d.Credentials == nil {
+		return "", fmt.Errorf("notification: sns driver has no credentials")
+	}
+	creds, err := d.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"Action":      {"Publish"},
+		"Version":     {"2010-03-31"},
+		"PhoneNumber": {message.To},
+		"Message":     {message.Content},
+	}
+	attributes := map[string]string{}
+	if message.From != "" {
+		attributes["AWS.SNS.SMS.SenderID"] = message.From
+	}
+	if d.SMSType != "" {
+		attributes["AWS.SNS.SMS.SMSType"] = d.SMSType
+	}
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		prefix := fmt.Sprintf("MessageAttributes.entry.%d.", i+1)
+		form.Set(prefix+"Name", name)
+		form.Set(prefix+"Value.DataType", "String")
+		form.Set(prefix+"Value.StringValue", attributes[name])
+	}
+
+	endpoint := d.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://sns.%s.amazonaws.com", d.Region)
+	}
+	body := form.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(endpoint, "/")+"/", strings.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	payloadHash := sha256.Sum256([]byte(body))
+	if err := v4.NewSigner().SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), "sns", d.Region, time.Now()); err != nil {
+		return "", err
+	}
+
+	resp, err := httpClient(d.Client).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("notification: sns returned %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var result struct {
+		MessageID string `xml:"PublishResult>MessageId"`
+	}
+	if err := xml.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("notification: invalid sns response: %w", err)
+	}
+	return result.MessageID, nil
+}
+
+// ArraySMSDriver keeps sent messages in memory. It is intended for tests.
+type ArraySMSDriver struct {
+	messages []*SMSMessage
+	mu       sync.Mutex
+}
+
+// NewArraySMSDriver creates a new in-memory SMS driver.
+func NewArraySMSDriver() *ArraySMSDriver {
+	return &ArraySMSDriver{}
+}
+
+// Send stores the message.
+func (d *ArraySMSDriver) Send(ctx context.Context, message *SMSMessage) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.messages = append(d.messages, message)
+	return newSMSID(), nil
+}
+
+// Messages returns the messages sent so far.
+func (d *ArraySMSDriver) Messages() []*SMSMessage {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]*SMSMessage(nil), d.messages...)
+}
+
+// LogSMSDriver writes messages to a logger instead of sending them.
+type LogSMSDriver struct {
+	logger contracts.Logger
+}
+
+// NewLogSMSDriver creates a new log SMS driver.
+func NewLogSMSDriver(logger contracts.Logger) *LogSMSDriver {
+	return &LogSMSDriver{logger: logger}
+}
+
+// Send logs the message.
+func (d *LogSMSDriver) Send(ctx context.Context, message *SMSMessage) (string, error) {
+	id := newSMSID()
+	d.logger.Info("SMS sent",
+		"message_id", id,
+		"from", message.From,
+		"to", message.To,
+		"content", message.Content,
+	)
+	return id, nil
+}
+
+func newSMSID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
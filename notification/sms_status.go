@@ -0,0 +1,109 @@
+package notification
+
+import (
+	"errors"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/events"
+)
+
+// Normalized SMS delivery statuses.
+const (
+	SMSStatusQueued    = "queued"
+	SMSStatusSent      = "sent"
+	SMSStatusDelivered = "delivered"
+	SMSStatusFailed    = "failed"
+)
+
+// ErrInvalidSignature is returned when a status callback fails signature verification.
+var ErrInvalidSignature = errors.New("notification: invalid callback signature")
+
+// DeliveryStatus is a provider's report on a sent message.
+type DeliveryStatus struct {
+	// MessageID is the provider's message ID returned by SMSDriver.Send.
+	MessageID string
+	To        string
+
+	// Status is one of the SMSStatus constants.
+	Status string
+
+	// ProviderStatus is the status as reported by the provider.
+	ProviderStatus string
+	ErrorCode      string
+	ReceivedAt     time.Time
+}
+
+// StatusParser is implemented by SMS drivers that accept delivery status callbacks.
+type StatusParser interface {
+	ParseStatus(req contracts.Request) (*DeliveryStatus, error)
+}
+
+// SMSDelivered is dispatched when a provider reports a message as delivered.
+type SMSDelivered struct {
+	Status *DeliveryStatus
+}
+
+// Name returns the event name.
+func (e *SMSDelivered) Name() string { return "notification.sms.delivered" }
+
+// SMSFailed is dispatched when a provider reports a message as failed or undeliverable.
+type SMSFailed struct {
+	Status *DeliveryStatus
+}
+
+// Name returns the event name.
+func (e *SMSFailed) Name() string { return "notification.sms.failed" }
+
+// SMSStatusUpdated is dispatched for intermediate statuses such as queued or sent.
+type SMSStatusUpdated struct {
+	Status *DeliveryStatus
+}
+
+// Name returns the event name.
+func (e *SMSStatusUpdated) Name() string { return "notification.sms.status" }
+
+// StatusHandler returns a handler for the driver's delivery status callbacks.
+// Each callback is dispatched as an SMSDelivered, SMSFailed or SMSStatusUpdated event.
+func (c *SMSChannel) StatusHandler() contracts.HandlerFunc {
+	return func(ctx contracts.Context) error {
+		parser, ok := c.driver.(StatusParser)
+		if !ok {
+			return ctx.Status(404).String("Status callbacks are not supported by this driver")
+		}
+
+		status, err := parser.ParseStatus(ctx.Request())
+		switch {
+		case errors.Is(err, ErrInvalidSignature):
+			return ctx.Status(401).String("Invalid signature")
+		case err != nil:
+			return ctx.Status(422).String(err.Error())
+		}
+		if status.ReceivedAt.IsZero() {
+			status.ReceivedAt = time.Now()
+		}
+
+		c.mu.RLock()
+		dispatcher := c.dispatcher
+		c.mu.RUnlock()
+
+		if dispatcher != nil {
+			if err := dispatcher.Dispatch(statusEvent(status)); err != nil {
+				return err
+			}
+		}
+		return ctx.Status(200).String("OK")
+	}
+}
+
+// statusEvent maps a delivery status to its event.
+func statusEvent(status *DeliveryStatus) events.Event {
+	switch status.Status {
+	case SMSStatusDelivered:
+		return &SMSDelivered{Status: status}
+	case SMSStatusFailed:
+		return &SMSFailed{Status: status}
+	default:
+		return &SMSStatusUpdated{Status: status}
+	}
+}
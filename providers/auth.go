@@ -0,0 +1,171 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/genesysflow/go-genesys/auth"
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+	authfacade "github.com/genesysflow/go-genesys/facades/auth"
+	"github.com/genesysflow/go-genesys/hashing"
+)
+
+// AuthServiceProvider registers the auth manager.
+type AuthServiceProvider struct {
+	BaseProvider
+
+	// Config is optional auth configuration. It replaces the guards in
+	// config/auth.yaml.
+	Config *auth.Config
+}
+
+// Register registers the auth services. Guards come from auth.guards and
+// user providers from auth.providers; without them a "web" session guard
+// and an "api" token guard use the "users" table.
+func (p *AuthServiceProvider) Register(app contracts.Application) error {
+	p.app = app
+	cfg := app.GetConfig()
+
+	authConfig := auth.DefaultConfig()
+	if p.Config != nil {
+		authConfig = *p.Config
+	} else {
+		if guard := cfg.GetString("auth.defaults.guard"); guard != "" {
+			authConfig.Default = guard
+		}
+		if guards := cfg.GetMap("auth.guards"); len(guards) > 0 {
+			authConfig.Guards = make(map[string]auth.GuardConfig, len(guards))
+			for name, entry := range guards {
+				settings, _ := entry.(map[string]any)
+				jwtSettings, _ := settings["jwt"].(map[string]any)
+				jwtConfig, err := jwtConfig(jwtSettings)
+				if err != nil {
+					return fmt.Errorf("auth guard %s: %w", name, err)
+				}
+				authConfig.Guards[name] = auth.GuardConfig{
+					Driver:     settingString(settings, "driver"),
+					Provider:   settingString(settings, "provider"),
+					InputKey:   settingString(settings, "input_key"),
+					StorageKey: settingString(settings, "storage_key"),
+					Hash:       settings["hash"] == true,
+					JWT:        jwtConfig,
+				}
+			}
+		}
+	}
+
+	manager := auth.NewManager(authConfig)
+
+	providers := cfg.GetMap("auth.providers")
+	if len(providers) == 0 {
+		providers = map[string]any{"users": map[string]any{"driver": "database"}}
+	}
+	for name, entry := range providers {
+		settings, _ := entry.(map[string]any)
+		if err := registerUserProvider(app, manager, name, settings); err != nil {
+			return err
+		}
+	}
+
+	app.InstanceType(manager)
+	app.BindValue("auth", manager)
+	authfacade.SetInstance(manager)
+
+	return nil
+}
+
+// Boot bootstraps the auth services. Invalidated JWTs are recorded in the
+// default cache store when the cache is available, so they stay revoked
+// across instances sharing it.
+func (p *AuthServiceProvider) Boot(app contracts.Application) error {
+	manager, err := container.Resolve[*auth.Manager](app)
+	if err != nil {
+		return err
+	}
+	if cacheManager, err := container.Resolve[*cache.Manager](app); err == nil {
+		if store, err := cacheManager.Store(); err == nil {
+			manager.SetTokenInvalidator(auth.NewCacheInvalidator(store))
+		}
+	}
+	return nil
+}
+
+// Provides returns the services this provider registers.
+func (p *AuthServiceProvider) Provides() []string {
+	return []string{
+		"auth",
+	}
+}
+
+// registerUserProvider adds a user provider from its auth.providers entry.
+// Database providers connect on first use, after the database has booted.
+func registerUserProvider(app contracts.Application, manager *auth.Manager, name string, settings map[string]any) error {
+	switch driver := settingString(settings, "driver"); driver {
+	case "", "database":
+		manager.ProviderFunc(name, func() (contracts.UserProvider, error) {
+			db, err := container.Resolve[*database.Manager](app)
+			if err != nil {
+				return nil, fmt.Errorf("database manager not available: %w", err)
+			}
+			conn := db.Connection(settingString(settings, "connection"))
+			if err := conn.Error(); err != nil {
+				return nil, err
+			}
+			provider := auth.NewDatabaseUserProvider(conn, settingString(settings, "table"))
+			if hasher, err := container.Resolve[*hashing.Manager](app); err == nil {
+				provider.SetHasher(hasher)
+			}
+			return provider, nil
+		})
+		return nil
+	default:
+		return fmt.Errorf("auth provider %s: unsupported driver %s", name, driver)
+	}
+}
+
+// jwtConfig reads a jwt guard's settings. Keys are PEM text or paths to PEM
+// files; ttl and refresh_ttl are in minutes and leeway in seconds.
+func jwtConfig(settings map[string]any) (auth.JWTConfig, error) {
+	config := auth.JWTConfig{
+		Algorithm:  settingString(settings, "algorithm"),
+		Issuer:     settingString(settings, "issuer"),
+		AccessTTL:  time.Duration(settingInt(settings, "ttl")) * time.Minute,
+		RefreshTTL: time.Duration(settingInt(settings, "refresh_ttl")) * time.Minute,
+		Leeway:     time.Duration(settingInt(settings, "leeway")) * time.Second,
+	}
+	if secret := settingString(settings, "secret"); secret != "" {
+		config.Secret = []byte(secret)
+	}
+
+	if value := settingString(settings, "private_key"); value != "" {
+		data, err := pemSetting(value)
+		if err != nil {
+			return config, err
+		}
+		if config.PrivateKey, err = auth.ParsePrivateKeyPEM(data); err != nil {
+			return config, err
+		}
+	}
+	if value := settingString(settings, "public_key"); value != "" {
+		data, err := pemSetting(value)
+		if err != nil {
+			return config, err
+		}
+		if config.PublicKey, err = auth.ParsePublicKeyPEM(data); err != nil {
+			return config, err
+		}
+	}
+	return config, nil
+}
+
+func pemSetting(value string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(value), "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
@@ -0,0 +1,98 @@
+package providers
+
+import (
+	"fmt"
+
+	"github.com/genesysflow/go-genesys/broadcasting"
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/events"
+	"github.com/genesysflow/go-genesys/http"
+)
+
+// BroadcastServiceProvider registers the broadcasting manager, broadcasts
+// the dispatcher's ShouldBroadcast events and serves the client WebSocket
+// endpoint.
+type BroadcastServiceProvider struct {
+	BaseProvider
+
+	// Channels is an optional function that registers channel authorizers.
+	Channels func(manager *broadcasting.Manager)
+
+	// Middleware runs on the WebSocket endpoint's upgrade request, such as
+	// the auth middleware that private channels need.
+	Middleware []http.MiddlewareFunc
+}
+
+// Register registers the broadcasting manager.
+// broadcasting.driver is hub (the default), redis or null. The redis driver
+// reads its connection from broadcasting.redis (host, port, password,
+// database and channel).
+func (p *BroadcastServiceProvider) Register(app contracts.Application) error {
+	p.app = app
+
+	manager := broadcasting.NewManager(broadcasting.NullBroadcaster{})
+	switch driver := app.GetConfig().GetString("broadcasting.driver"); driver {
+	case "", "hub", "null":
+		// The hub broadcaster is set in Boot, once the router exists.
+	case "redis":
+		settings := app.GetConfig().GetMap("broadcasting.redis")
+		client := redisClient(settings)
+		manager.SetBroadcaster(broadcasting.NewRedisBroadcaster(client, settingString(settings, "channel")))
+		app.Terminating(func(contracts.Application) { client.Close() })
+	default:
+		return fmt.Errorf("unsupported broadcasting driver: %s", driver)
+	}
+	app.Terminating(func(contracts.Application) { manager.Close() })
+
+	app.InstanceType(manager)
+	app.BindValue("broadcasting", manager)
+
+	return nil
+}
+
+// Boot connects the manager to the event dispatcher and mounts the
+// WebSocket endpoint at broadcasting.path (default /broadcasting) on the
+// router. Set broadcasting.routes to false to mount it yourself.
+func (p *BroadcastServiceProvider) Boot(app contracts.Application) error {
+	manager, err := container.Resolve[*broadcasting.Manager](app)
+	if err != nil {
+		return err
+	}
+	manager.SetLogger(app.GetLogger())
+
+	cfg := app.GetConfig()
+	router, routerErr := container.Resolve[*http.Router](app)
+	switch cfg.GetString("broadcasting.driver") {
+	case "", "hub":
+		if routerErr != nil {
+			return fmt.Errorf("the hub broadcasting driver needs the route provider: %w", routerErr)
+		}
+		manager.SetBroadcaster(broadcasting.NewHubBroadcaster(router.Hub()))
+	}
+
+	if p.Channels != nil {
+		p.Channels(manager)
+	}
+	if dispatcher, err := container.Resolve[*events.Dispatcher](app); err == nil {
+		manager.Listen(dispatcher)
+	}
+
+	if routerErr != nil || (cfg.Get("broadcasting.routes") != nil && !cfg.GetBool("broadcasting.routes")) {
+		return nil
+	}
+	path := cfg.GetString("broadcasting.path")
+	if path == "" {
+		path = "/broadcasting"
+	}
+	router.WebSocket(path, manager.WebSocketHandler(), p.Middleware...)
+
+	return nil
+}
+
+// Provides returns the services this provider registers.
+func (p *BroadcastServiceProvider) Provides() []string {
+	return []string{
+		"broadcasting",
+	}
+}
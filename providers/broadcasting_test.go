@@ -0,0 +1,56 @@
+package providers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/broadcasting"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcastServiceProviderMountsEndpoint(t *testing.T) {
+	app := testutil.NewMockApplication()
+	fiberApp := fiber.New()
+	app.InstanceType(http.NewRouter(app, fiberApp))
+
+	channels := 0
+	provider := &BroadcastServiceProvider{Channels: func(*broadcasting.Manager) { channels++ }}
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+
+	manager, ok := app.GetInstance("broadcasting").(*broadcasting.Manager)
+	require.True(t, ok)
+	assert.IsType(t, &broadcasting.HubBroadcaster{}, manager.Broadcaster())
+	assert.Equal(t, 1, channels)
+
+	resp, err := fiberApp.Test(httptest.NewRequest("GET", "/broadcasting", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUpgradeRequired, resp.StatusCode)
+}
+
+func TestBroadcastServiceProviderDrivers(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"broadcasting.driver": "redis",
+		"broadcasting.redis":  map[string]any{"channel": "app:broadcasts"},
+	}))
+	provider := &BroadcastServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+	manager := app.GetInstance("broadcasting").(*broadcasting.Manager)
+	assert.IsType(t, &broadcasting.RedisBroadcaster{}, manager.Broadcaster())
+
+	// The hub driver needs a router.
+	app = testutil.NewMockApplication()
+	provider = &BroadcastServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	assert.ErrorContains(t, provider.Boot(app), "needs the route provider")
+
+	app = testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"broadcasting.driver": "pusher",
+	}))
+	assert.EqualError(t, (&BroadcastServiceProvider{}).Register(app), "unsupported broadcasting driver: pusher")
+}
@@ -1,20 +1,65 @@
 package providers
 
 import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"time"
+
 	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/container"
 	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/crypt"
+	"github.com/genesysflow/go-genesys/database"
+	cachefacade "github.com/genesysflow/go-genesys/facades/cache"
+	"github.com/genesysflow/go-genesys/health"
 )
 
 // CacheServiceProvider registers the cache services.
 type CacheServiceProvider struct {
 	BaseProvider
+
+	// redis holds the clients of Redis-backed stores, for health checks.
+	redis map[string]*cache.RedisClient
 }
 
 // Register registers the cache services.
+// cache.default names the default store (memory unless set), and
+// cache.prefix is the key prefix of Redis stores without their own, so
+// applications sharing a Redis server don't overwrite each other's keys.
 func (p *CacheServiceProvider) Register(app contracts.Application) error {
 	p.app = app
 
+	cfg := app.GetConfig()
 	manager := cache.NewManager()
+	if name := cfg.GetString("cache.default"); name != "" {
+		manager.SetDefaultStore(name)
+	}
+	for name, entry := range cfg.GetMap("cache.stores") {
+		settings, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+		if creator := lazyCacheStore(app, settings); creator != nil {
+			manager.RegisterFunc(name, creator)
+			continue
+		}
+		store, client, err := cacheStore(settings, cfg.GetString("cache.prefix"))
+		if err != nil {
+			return fmt.Errorf("cache store %s: %w", name, err)
+		}
+		if store != nil {
+			manager.Register(name, store)
+		}
+		if client != nil {
+			if p.redis == nil {
+				p.redis = make(map[string]*cache.RedisClient)
+			}
+			p.redis[name] = client
+		}
+	}
+
 	app.InstanceType(manager)
 	app.BindValue("cache", manager)
 
@@ -22,7 +67,25 @@ func (p *CacheServiceProvider) Register(app contracts.Application) error {
 }
 
 // Boot bootstraps the cache services.
+// Stores with `encrypt: true` in cache.stores are encrypted with the app encrypter.
+// Redis-backed stores get a "cache:<store>" health check.
 func (p *CacheServiceProvider) Boot(app contracts.Application) error {
+	manager, err := container.Resolve[*cache.Manager](app)
+	if err != nil {
+		return err
+	}
+
+	manager.Encrypt(encryptedEntries(app.GetConfig().GetMap("cache.stores"))...)
+	if encrypter, err := container.Resolve[*crypt.Encrypter](app); err == nil {
+		manager.SetEncrypter(encrypter)
+	}
+
+	for name, client := range p.redis {
+		registerHealthCheck(app, "cache:"+name, health.Redis(client))
+	}
+
+	cachefacade.SetInstance(manager)
+
 	return nil
 }
 
@@ -32,3 +95,121 @@ func (p *CacheServiceProvider) Provides() []string {
 		"cache",
 	}
 }
+
+// cacheStore creates a store from its cache.stores entry, and returns the
+// Redis client of Redis-backed stores. Memory stores return nil and are
+// created by the manager on first use. Redis keys are prefixed with the
+// entry's prefix, or with prefix if it has none.
+func cacheStore(settings map[string]any, prefix string) (cache.Store, *cache.RedisClient, error) {
+	if value, ok := settings["prefix"].(string); ok {
+		prefix = value
+	}
+
+	switch driver, _ := settings["driver"].(string); driver {
+	case "", "memory":
+		return nil, nil, nil
+	case "redis":
+		client := redisClient(settings)
+		return cache.NewRedisStore(client, prefix), client, nil
+	case "tiered":
+		// A Redis store with an in-process LRU in front, kept coherent over pub/sub.
+		client := redisClient(settings)
+		localTTL, err := settingDuration(settings, "local_ttl")
+		if err != nil {
+			return nil, nil, err
+		}
+		return cache.NewTieredStore(cache.NewRedisStore(client, prefix), client, cache.TieredOptions{
+			LocalSize: settingInt(settings, "local_size"),
+			LocalTTL:  localTTL,
+			Channel:   settingString(settings, "channel"),
+		}), client, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported cache driver: %s", driver)
+	}
+}
+
+// lazyCacheStore returns a creator for file and database stores, which are
+// created on first use so the storage directory and database connection are
+// only needed once the store is used.
+func lazyCacheStore(app contracts.Application, settings map[string]any) func() (cache.Store, error) {
+	switch driver, _ := settings["driver"].(string); driver {
+	case "file":
+		return func() (cache.Store, error) {
+			dir := settingString(settings, "path")
+			if dir == "" {
+				dir = filepath.Join(app.StoragePath(), "framework", "cache", "data")
+			}
+			return cache.NewFileStore(dir)
+		}
+	case "database":
+		return func() (cache.Store, error) {
+			db, err := container.Resolve[*database.Manager](app)
+			if err != nil {
+				return nil, fmt.Errorf("database manager not available: %w", err)
+			}
+			conn := db.Connection(settingString(settings, "connection"))
+			if err := conn.Error(); err != nil {
+				return nil, err
+			}
+			store := cache.NewDatabaseStore(conn, settingString(settings, "table"))
+			if table := settingString(settings, "lock_table"); table != "" {
+				store.SetLockTable(table)
+			}
+			return store, nil
+		}
+	}
+	return nil
+}
+
+// redisClient creates a Redis client from host, port, password and database settings.
+func redisClient(settings map[string]any) *cache.RedisClient {
+	host := settingString(settings, "host")
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	port := settingInt(settings, "port")
+	if port == 0 {
+		port = 6379
+	}
+
+	return cache.NewRedisClient(cache.RedisOptions{
+		Addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		Password: settingString(settings, "password"),
+		DB:       settingInt(settings, "database"),
+	})
+}
+
+func settingString(settings map[string]any, key string) string {
+	value, _ := settings[key].(string)
+	return value
+}
+
+// settingInt reads an integer setting. YAML and environment values may
+// arrive as floats or numeric strings.
+func settingInt(settings map[string]any, key string) int {
+	switch value := settings[key].(type) {
+	case int:
+		return value
+	case int64:
+		return int(value)
+	case float64:
+		return int(value)
+	case string:
+		n, _ := strconv.Atoi(value)
+		return n
+	}
+	return 0
+}
+
+// settingDuration reads a duration setting such as "30s".
+func settingDuration(settings map[string]any, key string) (time.Duration, error) {
+	value := settingString(settings, key)
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return d, nil
+}
@@ -2,8 +2,10 @@ package providers
 
 import (
 	"testing"
+	"time"
 
 	"github.com/genesysflow/go-genesys/cache"
+	cachefacade "github.com/genesysflow/go-genesys/facades/cache"
 	"github.com/genesysflow/go-genesys/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -39,3 +41,88 @@ func TestCacheServiceProviderProvides(t *testing.T) {
 
 	assert.Contains(t, provides, "cache")
 }
+
+func TestCacheServiceProviderConfiguredStores(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"cache.stores": map[string]any{
+			"redis": map[string]any{"driver": "redis", "host": "cache.internal", "port": 6380, "prefix": "app:"},
+			"hot":   map[string]any{"driver": "tiered", "local_size": 500, "local_ttl": "30s"},
+		},
+	}))
+
+	provider := &CacheServiceProvider{}
+	require.NoError(t, provider.Register(app))
+
+	manager := app.GetInstance("cache").(*cache.Manager)
+	store, err := manager.Store("redis")
+	require.NoError(t, err)
+	assert.IsType(t, &cache.RedisStore{}, store)
+
+	store, err = manager.Store("hot")
+	require.NoError(t, err)
+	require.IsType(t, &cache.TieredStore{}, store)
+	store.(*cache.TieredStore).Close()
+}
+
+func TestCacheServiceProviderInvalidStore(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"cache.stores": map[string]any{
+			"broken": map[string]any{"driver": "memcached"},
+		},
+	}))
+
+	err := (&CacheServiceProvider{}).Register(app)
+	assert.ErrorContains(t, err, "unsupported cache driver")
+
+	app = testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"cache.stores": map[string]any{
+			"hot": map[string]any{"driver": "tiered", "local_ttl": "soon"},
+		},
+	}))
+	assert.Error(t, (&CacheServiceProvider{}).Register(app))
+}
+
+func TestCacheServiceProviderFileAndDatabaseStores(t *testing.T) {
+	dir := t.TempDir()
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"cache.default": "files",
+		"cache.stores": map[string]any{
+			"files": map[string]any{"driver": "file", "path": dir},
+			"db":    map[string]any{"driver": "database"},
+		},
+	}))
+
+	provider := &CacheServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+	defer cachefacade.SetInstance(nil)
+
+	require.NoError(t, cachefacade.Put("greeting", "hello", time.Minute))
+	repository, err := cachefacade.Store()
+	require.NoError(t, err)
+	assert.IsType(t, &cache.FileStore{}, repository.Store())
+
+	// The database store needs the database provider.
+	_, err = cachefacade.Store("db")
+	assert.ErrorContains(t, err, "database manager not available")
+}
+
+func TestCacheServiceProviderPrefix(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"cache.prefix": "shop_staging:",
+		"cache.stores": map[string]any{
+			"redis": map[string]any{"driver": "redis"},
+			"jobs":  map[string]any{"driver": "redis", "prefix": "jobs:"},
+		},
+	}))
+	require.NoError(t, (&CacheServiceProvider{}).Register(app))
+
+	manager := app.GetInstance("cache").(*cache.Manager)
+	store, err := manager.Store("redis")
+	require.NoError(t, err)
+	assert.Equal(t, "shop_staging:", store.(*cache.RedisStore).Prefix())
+
+	store, err = manager.Store("jobs")
+	require.NoError(t, err)
+	assert.Equal(t, "jobs:", store.(*cache.RedisStore).Prefix())
+}
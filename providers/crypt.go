@@ -0,0 +1,98 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/crypt"
+	cryptfacade "github.com/genesysflow/go-genesys/facades/crypt"
+)
+
+// CryptServiceProvider registers the encrypter using the application key.
+type CryptServiceProvider struct {
+	BaseProvider
+}
+
+// Register registers the crypt services.
+// Nothing is registered when app.key is empty. Keys in app.previous_keys
+// still decrypt data encrypted before the key was rotated.
+func (p *CryptServiceProvider) Register(app contracts.Application) error {
+	p.app = app
+
+	cfg := app.GetConfig()
+	key := cfg.GetString("app.key")
+	if key == "" {
+		return nil
+	}
+
+	raw, err := crypt.ParseKey(key)
+	if err != nil {
+		return err
+	}
+	var previous [][]byte
+	for _, old := range previousKeys(cfg) {
+		decoded, err := crypt.ParseKey(old)
+		if err != nil {
+			return fmt.Errorf("invalid app.previous_keys: %w", err)
+		}
+		previous = append(previous, decoded)
+	}
+
+	encrypter, err := crypt.NewEncrypter(raw, previous...)
+	if err != nil {
+		return fmt.Errorf("invalid app.key: %w", err)
+	}
+
+	app.InstanceType(encrypter)
+	app.BindValue("crypt", encrypter)
+	cryptfacade.SetInstance(encrypter)
+
+	return nil
+}
+
+// previousKeys reads app.previous_keys, either a list or a comma-separated
+// string such as ${APP_PREVIOUS_KEYS}.
+func previousKeys(cfg contracts.Config) []string {
+	keys := cfg.GetStringSlice("app.previous_keys")
+	if len(keys) == 0 {
+		keys = strings.Split(cfg.GetString("app.previous_keys"), ",")
+	}
+
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// Boot bootstraps the crypt services.
+func (p *CryptServiceProvider) Boot(app contracts.Application) error {
+	return nil
+}
+
+// Provides returns the services this provider registers.
+func (p *CryptServiceProvider) Provides() []string {
+	return []string{
+		"crypt",
+	}
+}
+
+// encryptedEntries returns the names of config entries with `encrypt: true`.
+func encryptedEntries(entries map[string]any) []string {
+	names := make([]string, 0)
+	for name, entry := range entries {
+		cfg, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+		if encrypt, _ := cfg["encrypt"].(bool); encrypt {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
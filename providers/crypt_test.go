@@ -0,0 +1,104 @@
+package providers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/crypt"
+	cryptfacade "github.com/genesysflow/go-genesys/facades/crypt"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCryptServiceProviderRegister(t *testing.T) {
+	key, err := crypt.GenerateKey()
+	require.NoError(t, err)
+
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"app.key": key,
+	}))
+	provider := &CryptServiceProvider{}
+
+	err = provider.Register(app)
+	require.NoError(t, err)
+
+	encrypter := app.GetInstance("crypt")
+	assert.IsType(t, &crypt.Encrypter{}, encrypter)
+}
+
+func TestCryptServiceProviderPreviousKeys(t *testing.T) {
+	oldKey, err := crypt.GenerateKey()
+	require.NoError(t, err)
+	raw, err := crypt.ParseKey(oldKey)
+	require.NoError(t, err)
+	old, err := crypt.NewEncrypter(raw)
+	require.NoError(t, err)
+	legacy, err := old.EncryptString("secret")
+	require.NoError(t, err)
+
+	key, err := crypt.GenerateKey()
+	require.NoError(t, err)
+	for _, previous := range []any{oldKey + ", ", []string{oldKey}} {
+		app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+			"app.key":           key,
+			"app.previous_keys": previous,
+		}))
+		require.NoError(t, (&CryptServiceProvider{}).Register(app))
+		defer cryptfacade.SetInstance(nil)
+
+		value, err := cryptfacade.Decrypt(legacy)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", value)
+	}
+
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"app.key":           key,
+		"app.previous_keys": "too-short",
+	}))
+	assert.ErrorContains(t, (&CryptServiceProvider{}).Register(app), "previous key 1")
+}
+
+func TestCryptServiceProviderWithoutKey(t *testing.T) {
+	app := testutil.NewMockApplication()
+	provider := &CryptServiceProvider{}
+
+	err := provider.Register(app)
+	require.NoError(t, err)
+	assert.Nil(t, app.GetInstance("crypt"))
+}
+
+func TestCryptServiceProviderInvalidKey(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"app.key": "too-short",
+	}))
+	provider := &CryptServiceProvider{}
+
+	err := provider.Register(app)
+	assert.Error(t, err)
+}
+
+func TestCryptServiceProviderProvides(t *testing.T) {
+	provider := &CryptServiceProvider{}
+	assert.Contains(t, provider.Provides(), "crypt")
+}
+
+func TestCacheServiceProviderEncryptedStores(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"app.key": strings.Repeat("k", crypt.KeySize),
+		"cache.stores": map[string]any{
+			"memory": map[string]any{"encrypt": true},
+		},
+	}))
+
+	require.NoError(t, (&CryptServiceProvider{}).Register(app))
+	provider := &CacheServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+
+	manager := app.GetInstance("cache").(*cache.Manager)
+	store, err := manager.Store()
+	require.NoError(t, err)
+	assert.IsType(t, &cache.EncryptedStore{}, store)
+}
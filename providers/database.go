@@ -4,6 +4,7 @@ import (
 	"github.com/genesysflow/go-genesys/contracts"
 	"github.com/genesysflow/go-genesys/database"
 	"github.com/genesysflow/go-genesys/facades/db"
+	"github.com/genesysflow/go-genesys/health"
 
 	_ "modernc.org/sqlite"
 )
@@ -100,6 +101,8 @@ func (p *DatabaseServiceProvider) Boot(app contracts.Application) error {
 	// Initialize the DB facade
 	db.SetInstance(manager)
 
+	registerHealthCheck(app, "database", health.Database(manager))
+
 	return nil
 }
 
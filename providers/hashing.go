@@ -0,0 +1,62 @@
+package providers
+
+import (
+	"fmt"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	hashfacade "github.com/genesysflow/go-genesys/facades/hash"
+	"github.com/genesysflow/go-genesys/hashing"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HashingServiceProvider registers the password hashing manager.
+type HashingServiceProvider struct {
+	BaseProvider
+}
+
+// Register registers the hashing services. The driver is chosen by
+// hashing.driver: bcrypt (default) or argon2id, with costs from
+// hashing.bcrypt.rounds and hashing.argon2id.memory, time and threads.
+func (p *HashingServiceProvider) Register(app contracts.Application) error {
+	p.app = app
+	cfg := app.GetConfig()
+
+	driver := cfg.GetString("hashing.driver")
+	switch driver {
+	case "", "bcrypt", "argon2id":
+	default:
+		return fmt.Errorf("unsupported hashing driver: %s", driver)
+	}
+
+	rounds := cfg.GetInt("hashing.bcrypt.rounds")
+	if rounds != 0 && (rounds < bcrypt.MinCost || rounds > bcrypt.MaxCost) {
+		return fmt.Errorf("hashing.bcrypt.rounds must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+
+	manager := hashing.NewManager(driver)
+	manager.Extend("bcrypt", hashing.NewBcrypt(rounds))
+	argon := cfg.GetMap("hashing.argon2id")
+	manager.Extend("argon2id", hashing.NewArgon2id(hashing.Argon2idOptions{
+		Memory:  uint32(settingInt(argon, "memory")),
+		Time:    uint32(settingInt(argon, "time")),
+		Threads: uint8(settingInt(argon, "threads")),
+	}))
+
+	app.InstanceType(manager)
+	app.BindValue("hash", manager)
+	hashfacade.SetInstance(manager)
+
+	return nil
+}
+
+// Boot bootstraps the hashing services.
+func (p *HashingServiceProvider) Boot(app contracts.Application) error {
+	return nil
+}
+
+// Provides returns the services this provider registers.
+func (p *HashingServiceProvider) Provides() []string {
+	return []string{
+		"hash",
+	}
+}
@@ -0,0 +1,40 @@
+package providers
+
+import (
+	"testing"
+
+	hashfacade "github.com/genesysflow/go-genesys/facades/hash"
+	"github.com/genesysflow/go-genesys/hashing"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashingServiceProvider(t *testing.T) {
+	cfg := testutil.NewMockConfig(map[string]any{
+		"hashing.driver":        "argon2id",
+		"hashing.bcrypt.rounds": 4,
+		"hashing.argon2id":      map[string]any{"memory": 1024, "time": 1, "threads": 1},
+	})
+	app := testutil.NewMockApplicationWithConfig(cfg)
+	require.NoError(t, (&HashingServiceProvider{}).Register(app))
+	defer hashfacade.SetInstance(nil)
+
+	manager, ok := app.GetInstance("hash").(*hashing.Manager)
+	require.True(t, ok)
+	assert.Equal(t, "argon2id", manager.DefaultDriver())
+	assert.Same(t, manager, hashfacade.GetInstance())
+
+	hashed, err := hashfacade.Make("secret")
+	require.NoError(t, err)
+	assert.Contains(t, hashed, "$argon2id$v=19$m=1024,t=1,p=1$")
+	assert.True(t, hashfacade.Check("secret", hashed))
+}
+
+func TestHashingServiceProviderInvalidConfig(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{"hashing.driver": "md5"}))
+	assert.ErrorContains(t, (&HashingServiceProvider{}).Register(app), "unsupported hashing driver: md5")
+
+	app = testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{"hashing.bcrypt.rounds": 40}))
+	assert.ErrorContains(t, (&HashingServiceProvider{}).Register(app), "between 4 and 31")
+}
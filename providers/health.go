@@ -0,0 +1,85 @@
+package providers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/health"
+	"github.com/genesysflow/go-genesys/http"
+)
+
+// HealthServiceProvider registers the health check registry and serves it
+// on /healthz and /readyz. The database, cache and queue providers add
+// their checks to it when it is registered.
+type HealthServiceProvider struct {
+	BaseProvider
+}
+
+// Register registers the health check registry.
+// Checks time out after health.timeout (default 5s).
+func (p *HealthServiceProvider) Register(app contracts.Application) error {
+	p.app = app
+
+	registry := health.NewRegistry()
+	if value := app.GetConfig().GetString("health.timeout"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid health.timeout: %w", err)
+		}
+		registry.SetTimeout(timeout)
+	}
+
+	app.InstanceType(registry)
+	app.BindValue("health", registry)
+
+	return nil
+}
+
+// Boot adds a writable check for each disk in health.disks and mounts the
+// endpoints at health.liveness_path and health.readiness_path on the router,
+// if one is registered. Set health.routes to false to mount them yourself.
+func (p *HealthServiceProvider) Boot(app contracts.Application) error {
+	registry, err := container.Resolve[*health.Registry](app)
+	if err != nil {
+		return err
+	}
+
+	cfg := app.GetConfig()
+	for _, name := range cfg.GetStringSlice("health.disks") {
+		service, err := app.Make("filesystem")
+		if err != nil {
+			return fmt.Errorf("health.disks needs the filesystem provider: %w", err)
+		}
+		factory, ok := service.(contracts.FilesystemFactory)
+		if !ok {
+			return fmt.Errorf("filesystem service is not of type contracts.FilesystemFactory")
+		}
+		registry.Register("disk:"+name, health.DiskWritable(factory.Disk(name)))
+	}
+
+	if cfg.Get("health.routes") != nil && !cfg.GetBool("health.routes") {
+		return nil
+	}
+	if router, err := container.Resolve[*http.Router](app); err == nil {
+		registry.Routes(router, cfg.GetString("health.liveness_path"), cfg.GetString("health.readiness_path"))
+	}
+
+	return nil
+}
+
+// Provides returns the services this provider registers.
+func (p *HealthServiceProvider) Provides() []string {
+	return []string{
+		"health",
+	}
+}
+
+// registerHealthCheck adds a readiness check if the health provider is
+// registered.
+func registerHealthCheck(app contracts.Application, name string, check health.CheckFunc) {
+	if registry, err := container.Resolve[*health.Registry](app); err == nil {
+		registry.Register(name, check)
+	}
+}
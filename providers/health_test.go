@@ -0,0 +1,71 @@
+package providers
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/health"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/queue"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthServiceProviderMountsRoutes(t *testing.T) {
+	app := testutil.NewMockApplication()
+	fiberApp := fiber.New()
+	app.InstanceType(http.NewRouter(app, fiberApp))
+
+	provider := &HealthServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+
+	registry, ok := app.GetInstance("health").(*health.Registry)
+	require.True(t, ok)
+	registry.Register("database", func(ctx context.Context) error { return nil })
+
+	resp, err := fiberApp.Test(httptest.NewRequest("GET", "/readyz", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestHealthServiceProviderConfig(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"health.routes":          false,
+		"health.queue_max_depth": 1,
+	}))
+	fiberApp := fiber.New()
+	router := http.NewRouter(app, fiberApp)
+	app.InstanceType(router)
+
+	checks := &HealthServiceProvider{}
+	require.NoError(t, checks.Register(app))
+	queues := &QueueServiceProvider{}
+	require.NoError(t, queues.Register(app))
+	require.NoError(t, checks.Boot(app))
+	require.NoError(t, queues.Boot(app))
+
+	assert.Nil(t, router.NamedRoute("health.readiness"))
+
+	jobs := queue.NewMemoryQueue()
+	app.GetInstance("queue").(*queue.Manager).Register("sync", jobs)
+	require.NoError(t, jobs.Push(&healthTestJob{}))
+	require.NoError(t, jobs.Push(&healthTestJob{}))
+
+	report := app.GetInstance("health").(*health.Registry).Readiness(context.Background())
+	assert.Equal(t, "queue depth 2 exceeds 1", report.Checks["queue"].Error)
+}
+
+func TestHealthServiceProviderInvalidTimeout(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"health.timeout": "soon",
+	}))
+	assert.Error(t, (&HealthServiceProvider{}).Register(app))
+}
+
+type healthTestJob struct{}
+
+func (healthTestJob) Handle() error { return nil }
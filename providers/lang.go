@@ -0,0 +1,64 @@
+package providers
+
+import (
+	"path/filepath"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	langfacade "github.com/genesysflow/go-genesys/facades/lang"
+	"github.com/genesysflow/go-genesys/lang"
+	"github.com/genesysflow/go-genesys/validation"
+)
+
+// LangServiceProvider registers the translator.
+type LangServiceProvider struct {
+	BaseProvider
+}
+
+// Register registers the translator for app.locale (default en), falling
+// back to app.fallback_locale. Language files are read from lang.path
+// (default lang).
+func (p *LangServiceProvider) Register(app contracts.Application) error {
+	p.app = app
+
+	cfg := app.GetConfig()
+	translator := lang.New(cfg.GetString("app.locale"), cfg.GetString("app.fallback_locale"))
+
+	dir := cfg.GetString("lang.path")
+	if dir == "" {
+		dir = "lang"
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(app.BasePath(), dir)
+	}
+	if err := translator.Load(dir); err != nil {
+		return err
+	}
+
+	app.InstanceType(translator)
+	app.BindValue("translator", translator)
+
+	return nil
+}
+
+// Boot sets the facade and translates validation messages when the
+// validator is registered.
+func (p *LangServiceProvider) Boot(app contracts.Application) error {
+	translator, err := container.Resolve[*lang.Translator](app)
+	if err != nil {
+		return err
+	}
+	if validator, err := container.Resolve[*validation.Validator](app); err == nil {
+		validator.SetTranslator(translator)
+	}
+
+	langfacade.SetInstance(translator)
+	return nil
+}
+
+// Provides returns the services this provider registers.
+func (p *LangServiceProvider) Provides() []string {
+	return []string{
+		"translator",
+	}
+}
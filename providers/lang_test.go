@@ -0,0 +1,44 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/container"
+	langfacade "github.com/genesysflow/go-genesys/facades/lang"
+	"github.com/genesysflow/go-genesys/lang"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/genesysflow/go-genesys/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLangServiceProvider(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "de"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "de", "messages.yaml"), []byte("welcome: Willkommen\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "de", "validation.yaml"), []byte("required: \":attribute ist erforderlich.\"\n"), 0644))
+
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"app.locale":          "de",
+		"app.fallback_locale": "en",
+		"lang.path":           dir,
+	}))
+	require.NoError(t, (&ValidationServiceProvider{}).Register(app))
+	provider := &LangServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+
+	translator, err := container.Resolve[*lang.Translator](app)
+	require.NoError(t, err)
+	assert.Equal(t, "de", translator.Locale())
+	assert.Equal(t, "en", translator.Fallback())
+	assert.Equal(t, "Willkommen", langfacade.Trans("messages.welcome"))
+
+	validator, err := container.Resolve[*validation.Validator](app)
+	require.NoError(t, err)
+	result := validator.ValidateMap(map[string]any{}, map[string]string{"name": "required"})
+	assert.Equal(t, "Name ist erforderlich.", result.Errors().First("name"))
+	assert.Contains(t, provider.Provides(), "translator")
+}
@@ -1,75 +1,84 @@
 package providers
 
 import (
-	"os"
+	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/facades/logs"
 	"github.com/genesysflow/go-genesys/log"
 )
 
 // LogServiceProvider registers logging services.
 type LogServiceProvider struct {
 	BaseProvider
+	logger  contracts.Logger
+	manager *log.LogManager
 }
 
-// Register registers the logging services.
+// Register registers the log manager and the logger of its default
+// channel. Channels are configured under logging.channels and the default
+// one is named by logging.default (console unless set); logging.level sets
+// its level. A default channel that can't be created falls back to the
+// console.
 func (p *LogServiceProvider) Register(app contracts.Application) error {
 	p.app = app
 
 	cfg := app.GetConfig()
-
-	// Get log configuration
-	channel := cfg.GetString("logging.default")
-	if channel == "" {
-		channel = "console"
+	logConfig := log.Config{
+		Default:  cfg.GetString("logging.default"),
+		Channels: make(map[string]log.ChannelConfig),
 	}
-
-	var logger *log.Logger
-
-	switch channel {
-	case "file":
-		logPath := cfg.GetString("logging.channels.file.path")
-		if logPath == "" {
-			logPath = filepath.Join(app.StoragePath(), "logs", "app.log")
+	for name, entry := range cfg.GetMap("logging.channels") {
+		if settings, ok := entry.(map[string]any); ok {
+			channel, err := logChannelConfig(app, settings)
+			if err != nil {
+				return fmt.Errorf("log channel %s: %w", name, err)
+			}
+			logConfig.Channels[name] = channel
 		}
-
-		// Ensure directory exists
-		dir := filepath.Dir(logPath)
-		os.MkdirAll(dir, 0755)
-
-		var err error
-		logger, err = log.NewFile(logPath)
-		if err != nil {
-			// Fallback to console
-			logger = log.New()
-		}
-
-	case "json":
-		logger = log.NewJSON()
-
-	default:
-		logger = log.New()
 	}
 
+	logManager := log.NewManager(logConfig)
+	logger := logManager.Channel(logManager.DefaultChannel())
+
 	// Set log level from config
 	level := cfg.GetString("logging.level")
 	if level != "" {
 		logger.SetLevel(parseLogLevel(level))
 	}
 
+	p.logger = logger
+	log.SetDefault(logger)
 	app.InstanceType(logger)
 	app.BindValue("logger", logger)
+	if setter, ok := app.(interface{ SetLogger(contracts.Logger) }); ok {
+		setter.SetLogger(logger)
+	}
 
-	logManager := log.NewManager()
 	app.InstanceType(logManager)
 	app.BindValue("log.manager", logManager)
+	p.manager = logManager
+	logs.SetInstance(logManager)
 
 	return nil
 }
 
-// Boot bootstraps the logging services.
+// Boot bootstraps the logging services. Changes to logging.level, such as
+// from a config reload, apply to the running logger, and entries the
+// forwarding channels still have queued are sent on termination.
 func (p *LogServiceProvider) Boot(app contracts.Application) error {
+	if p.manager != nil {
+		app.Terminating(func(contracts.Application) { p.manager.Close() })
+	}
+	if p.logger != nil {
+		app.GetConfig().Watch("logging.level", func(value any) {
+			if level, ok := value.(string); ok && level != "" {
+				p.logger.SetLevel(parseLogLevel(level))
+			}
+		})
+	}
 	return nil
 }
 
@@ -83,20 +92,58 @@ func (p *LogServiceProvider) Provides() []string {
 
 // parseLogLevel parses a string log level.
 func parseLogLevel(level string) contracts.LogLevel {
-	switch level {
-	case "debug":
-		return contracts.LogLevelDebug
-	case "info":
-		return contracts.LogLevelInfo
-	case "warn", "warning":
-		return contracts.LogLevelWarn
-	case "error":
-		return contracts.LogLevelError
-	case "fatal":
-		return contracts.LogLevelFatal
-	case "panic":
-		return contracts.LogLevelPanic
+	return log.ParseLevel(level)
+}
+
+// logChannelConfig reads a channel from its logging.channels entry. File
+// channels without a path write to storage/logs/app.log, and are rotated
+// by max_size (megabytes) and rotate (daily, hourly or a duration), keeping
+// max_backups files for max_age days, gzipped with compress. Slack, webhook
+// and sentry channels read url, dsn and buffer.
+func logChannelConfig(app contracts.Application, settings map[string]any) (log.ChannelConfig, error) {
+	channel := log.ChannelConfig{
+		Driver:  settingString(settings, "driver"),
+		Level:   settingString(settings, "level"),
+		Path:    settingString(settings, "path"),
+		Network: settingString(settings, "network"),
+		Address: settingString(settings, "address"),
+		Tag:     settingString(settings, "tag"),
+		URL:     settingString(settings, "url"),
+		DSN:     settingString(settings, "dsn"),
+		Buffer:  settingInt(settings, "buffer"),
+		Options: settings,
+		RotateOptions: log.RotateOptions{
+			MaxSize:    settingInt(settings, "max_size"),
+			MaxBackups: settingInt(settings, "max_backups"),
+			MaxAge:     settingInt(settings, "max_age"),
+		},
+	}
+	channel.Compress, _ = settings["compress"].(bool)
+	switch settingString(settings, "rotate") {
+	case "":
+	case "daily":
+		channel.Interval = 24 * time.Hour
+	case "hourly":
+		channel.Interval = time.Hour
 	default:
-		return contracts.LogLevelInfo
+		interval, err := settingDuration(settings, "rotate")
+		if err != nil {
+			return channel, err
+		}
+		channel.Interval = interval
+	}
+	if channel.Driver == "file" && channel.Path == "" {
+		channel.Path = filepath.Join(app.StoragePath(), "logs", "app.log")
+	}
+	switch channels := settings["channels"].(type) {
+	case []string:
+		channel.Channels = channels
+	case []any:
+		for _, name := range channels {
+			if name, ok := name.(string); ok {
+				channel.Channels = append(channel.Channels, name)
+			}
+		}
 	}
+	return channel, nil
 }
@@ -1,10 +1,16 @@
 package providers
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/genesysflow/go-genesys/config"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/facades/logs"
 	"github.com/genesysflow/go-genesys/log"
 	"github.com/genesysflow/go-genesys/testutil"
 	"github.com/stretchr/testify/assert"
@@ -79,6 +85,78 @@ func TestLogServiceProviderRegisterFileChannel(t *testing.T) {
 	assert.NotNil(t, logger)
 }
 
+func TestLogServiceProviderRegisterChannels(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := testutil.NewMockConfig(map[string]any{
+		"logging.default": "stack",
+		"logging.channels": map[string]any{
+			"stack": map[string]any{"driver": "stack", "channels": []any{"app", "audit"}},
+			"app":   map[string]any{"driver": "file", "path": filepath.Join(tmpDir, "app.log")},
+			"audit": map[string]any{"driver": "file", "path": filepath.Join(tmpDir, "audit.log"), "level": "warn"},
+		},
+	})
+	app := testutil.NewMockApplicationWithConfig(cfg)
+	require.NoError(t, (&LogServiceProvider{}).Register(app))
+	defer logs.SetInstance(nil)
+
+	logger, ok := app.GetInstance("logger").(*log.Stack)
+	require.True(t, ok)
+	assert.Same(t, logger, app.GetLogger())
+	assert.Len(t, logger.Loggers(), 2)
+
+	logs.Channel("audit").Warn("role changed")
+	content, err := os.ReadFile(filepath.Join(tmpDir, "audit.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "role changed")
+}
+
+func TestLogServiceProviderRotation(t *testing.T) {
+	cfg := testutil.NewMockConfig(map[string]any{
+		"logging.channels": map[string]any{
+			"daily": map[string]any{"driver": "file", "rotate": "daily", "max_backups": 7, "compress": true},
+		},
+	})
+	app := testutil.NewMockApplicationWithConfig(cfg)
+	app.SetBasePath(t.TempDir())
+	require.NoError(t, (&LogServiceProvider{}).Register(app))
+	defer logs.SetInstance(nil)
+
+	logs.Channel("daily").Info("rotated daily")
+	content, err := os.ReadFile(filepath.Join(app.StoragePath(), "logs", "app.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "rotated daily")
+
+	cfg = testutil.NewMockConfig(map[string]any{
+		"logging.channels": map[string]any{"weekly": map[string]any{"driver": "file", "rotate": "weekly"}},
+	})
+	err = (&LogServiceProvider{}).Register(testutil.NewMockApplicationWithConfig(cfg))
+	assert.ErrorContains(t, err, "log channel weekly: invalid rotate")
+}
+
+func TestLogServiceProviderWebhookChannel(t *testing.T) {
+	received := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		received <- body["message"].(string)
+	}))
+	defer server.Close()
+
+	cfg := testutil.NewMockConfig(map[string]any{
+		"logging.channels": map[string]any{
+			"hook": map[string]any{"driver": "webhook", "url": server.URL, "buffer": 10},
+		},
+	})
+	require.NoError(t, (&LogServiceProvider{}).Register(testutil.NewMockApplicationWithConfig(cfg)))
+	defer logs.SetInstance(nil)
+
+	logs.Channel("hook").Info("not forwarded")
+	logs.Channel("hook").Error("forwarded")
+	require.NoError(t, logs.GetInstance().Close())
+	assert.Equal(t, "forwarded", <-received)
+	assert.Empty(t, received)
+}
+
 func TestLogServiceProviderRegisterWithLogLevel(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -119,6 +197,21 @@ func TestLogServiceProviderBoot(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestLogServiceProviderFollowsLevelChanges(t *testing.T) {
+	cfg := config.New()
+	cfg.Set("logging.level", "info")
+	app := testutil.NewMockApplicationWithConfig(cfg)
+	provider := &LogServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+
+	logger := app.GetInstance("logger").(*log.Logger)
+	assert.Equal(t, contracts.LogLevelInfo, logger.Level())
+
+	cfg.Set("logging.level", "debug")
+	assert.Equal(t, contracts.LogLevelDebug, logger.Level())
+}
+
 func TestLogServiceProviderProvides(t *testing.T) {
 	provider := &LogServiceProvider{}
 	provides := provider.Provides()
@@ -0,0 +1,245 @@
+package providers
+
+import (
+	"fmt"
+	"net/mail"
+	"path/filepath"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+	mailfacade "github.com/genesysflow/go-genesys/facades/mail"
+	genesysmail "github.com/genesysflow/go-genesys/mail"
+	"github.com/genesysflow/go-genesys/queue"
+	"github.com/genesysflow/go-genesys/view"
+)
+
+// MailServiceProvider registers the mailer.
+type MailServiceProvider struct {
+	BaseProvider
+}
+
+// Register registers the mail services.
+// mail.default names the default mailer (log unless set) and mail.mailers
+// configures named mailers, each with a transport of smtp, ses, log or
+// array. A default mailer without a mail.mailers entry uses the transport
+// of the same name. Messages without a sender are sent from
+// mail.from.address and mail.from.name, or the mailer's own from settings.
+func (p *MailServiceProvider) Register(app contracts.Application) error {
+	p.app = app
+
+	cfg := app.GetConfig()
+	manager := genesysmail.NewManager()
+	if name := cfg.GetString("mail.default"); name != "" {
+		manager.SetDefaultMailer(name)
+	}
+
+	mailers := make(map[string]map[string]any)
+	for name, entry := range cfg.GetMap("mail.mailers") {
+		if settings, ok := entry.(map[string]any); ok {
+			mailers[name] = settings
+		}
+	}
+	if _, ok := mailers[manager.DefaultMailer()]; !ok {
+		mailers[manager.DefaultMailer()] = map[string]any{"transport": manager.DefaultMailer()}
+	}
+
+	// Markdown templates are loaded from mail.markdown.path and themed with mail.markdown.theme.
+	markdown := genesysmail.NewMarkdown(genesysmail.ThemeFromConfig(cfg.GetMap("mail.markdown.theme")))
+	if path := cfg.GetString("mail.markdown.path"); path != "" {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(app.BasePath(), path)
+		}
+		if err := markdown.LoadDirectory(path); err != nil {
+			return fmt.Errorf("failed to load mail templates: %w", err)
+		}
+	}
+
+	// Views are loaded from mail.views.path, rendered in mail.views.layout
+	// and have their CSS inlined with mail.views.inline_css.
+	var views *genesysmail.Views
+	if path := cfg.GetString("mail.views.path"); path != "" {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(app.BasePath(), path)
+		}
+		views = genesysmail.NewViews()
+		if err := views.LoadDirectory(path); err != nil {
+			return fmt.Errorf("failed to load mail views: %w", err)
+		}
+		if layout := cfg.GetString("mail.views.layout"); layout != "" {
+			views.SetLayout(layout)
+		}
+		views.SetInlineCSS(cfg.GetBool("mail.views.inline_css"))
+	}
+
+	from := fromAddress(cfg.GetString("mail.from.address"), cfg.GetString("mail.from.name"))
+	sentLog := genesysmail.NewMemorySentLog()
+	for name, settings := range mailers {
+		transport, err := mailTransport(app, settings)
+		if err != nil {
+			return fmt.Errorf("mailer %s: %w", name, err)
+		}
+
+		mailerFrom := from
+		if override, ok := settings["from"].(map[string]any); ok {
+			mailerFrom = fromAddress(settingString(override, "address"), settingString(override, "name"))
+		}
+
+		mailer := genesysmail.NewMailer(transport, mailerFrom)
+		mailer.SetSentLog(sentLog)
+		mailer.SetMarkdown(markdown)
+		if views != nil {
+			mailer.SetViews(views)
+		}
+		manager.Register(name, mailer)
+	}
+
+	mailer, err := manager.Mailer()
+	if err != nil {
+		return err
+	}
+
+	genesysmail.SetManager(manager)
+	app.InstanceType(manager)
+	app.BindValue("mail.manager", manager)
+	app.InstanceType(mailer)
+	app.BindValue("mail", mailer)
+
+	return nil
+}
+
+// Boot bootstraps the mail services.
+// Queued mail uses the queue connection named in mail.queue, if set, and
+// disk attachments are read from the filesystem service. Without
+// mail.views.path, message views are rendered by the view engine. With
+// mail.sent_log.driver set to database, the sent log is kept in the
+// mail.sent_log.table table on the mail.sent_log.connection connection,
+// shared by every process, rather than in memory.
+func (p *MailServiceProvider) Boot(app contracts.Application) error {
+	manager, err := container.Resolve[*genesysmail.Manager](app)
+	if err != nil {
+		return err
+	}
+
+	sentLog, err := mailSentLog(app)
+	if err != nil {
+		return err
+	}
+
+	var conn queue.Queue
+	if connection := app.GetConfig().GetString("mail.queue"); connection != "" {
+		queues, err := container.Resolve[*queue.Manager](app)
+		if err != nil {
+			return fmt.Errorf("mail.queue requires the queue service: %w", err)
+		}
+		if conn, err = queues.Connection(connection); err != nil {
+			return err
+		}
+	}
+	var disks contracts.FilesystemFactory
+	if service, err := app.Make("filesystem"); err == nil {
+		disks, _ = service.(contracts.FilesystemFactory)
+	}
+
+	engine, _ := container.Resolve[*view.Engine](app)
+
+	for _, name := range manager.Names() {
+		mailer, err := manager.Mailer(name)
+		if err != nil {
+			return err
+		}
+		if sentLog != nil {
+			mailer.SetSentLog(sentLog)
+		}
+		if mailer.Views() == nil && engine != nil {
+			mailer.SetViews(engine)
+		}
+		if conn != nil {
+			mailer.SetQueue(conn)
+		}
+		if disks != nil {
+			mailer.SetDisks(disks)
+		}
+	}
+
+	mailer, err := manager.Mailer()
+	if err != nil {
+		return err
+	}
+	mailfacade.SetInstance(mailer)
+	mailfacade.SetManager(manager)
+	return nil
+}
+
+// Provides returns the services this provider registers.
+func (p *MailServiceProvider) Provides() []string {
+	return []string{
+		"mail",
+		"mail.manager",
+	}
+}
+
+// mailSentLog creates the sent log mail.sent_log.driver names, or returns
+// nil for the in-memory log the mailers start with.
+func mailSentLog(app contracts.Application) (genesysmail.SentLog, error) {
+	cfg := app.GetConfig()
+	switch driver := cfg.GetString("mail.sent_log.driver"); driver {
+	case "", "memory":
+		return nil, nil
+	case "database":
+		databases, err := container.Resolve[*database.Manager](app)
+		if err != nil {
+			return nil, fmt.Errorf("mail sent log requires the database service: %w", err)
+		}
+		conn := databases.Connection(cfg.GetString("mail.sent_log.connection"))
+		if err := conn.Error(); err != nil {
+			return nil, fmt.Errorf("mail sent log: %w", err)
+		}
+		return genesysmail.NewDatabaseSentLog(conn, cfg.GetString("mail.sent_log.table")), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail sent log driver: %s", driver)
+	}
+}
+
+// mailTransport creates a transport from a mail.mailers entry.
+func mailTransport(app contracts.Application, settings map[string]any) (genesysmail.Transport, error) {
+	switch transport := settingString(settings, "transport"); transport {
+	case "", "log":
+		return genesysmail.NewLogTransport(app.GetLogger()), nil
+	case "array":
+		return genesysmail.NewArrayTransport(), nil
+	case "smtp":
+		timeout, err := settingDuration(settings, "timeout")
+		if err != nil {
+			return nil, err
+		}
+		return genesysmail.NewSMTPTransport(genesysmail.SMTPConfig{
+			Host:       settingString(settings, "host"),
+			Port:       settingInt(settings, "port"),
+			Username:   settingString(settings, "username"),
+			Password:   settingString(settings, "password"),
+			Encryption: settingString(settings, "encryption"),
+			LocalName:  settingString(settings, "local_domain"),
+			Timeout:    timeout,
+		})
+	case "ses":
+		return genesysmail.NewSESTransport(genesysmail.SESConfig{
+			Key:              settingString(settings, "key"),
+			Secret:           settingString(settings, "secret"),
+			Token:            settingString(settings, "token"),
+			Region:           settingString(settings, "region"),
+			ConfigurationSet: settingString(settings, "configuration_set"),
+			Endpoint:         settingString(settings, "endpoint"),
+		})
+	default:
+		return nil, fmt.Errorf("unsupported mail driver: %s", transport)
+	}
+}
+
+// fromAddress formats a sender from its address and display name.
+func fromAddress(address, name string) string {
+	if name != "" && address != "" {
+		return (&mail.Address{Name: name, Address: address}).String()
+	}
+	return address
+}
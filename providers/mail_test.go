@@ -0,0 +1,229 @@
+package providers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/database"
+	mailfacade "github.com/genesysflow/go-genesys/facades/mail"
+	"github.com/genesysflow/go-genesys/mail"
+	"github.com/genesysflow/go-genesys/queue"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMailServiceProviderRegister(t *testing.T) {
+	app := testutil.NewMockApplication()
+	provider := &MailServiceProvider{}
+
+	require.NoError(t, provider.Register(app))
+
+	mailer := app.GetInstance("mail")
+	assert.NotNil(t, mailer)
+	assert.IsType(t, &mail.Mailer{}, mailer)
+}
+
+func TestMailServiceProviderUnsupportedDriver(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"mail.default": "carrier-pigeon",
+	}))
+	provider := &MailServiceProvider{}
+
+	assert.Error(t, provider.Register(app))
+}
+
+func TestMailServiceProviderBootUsesQueue(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"mail.default":      "array",
+		"mail.from.address": "hello@example.com",
+		"mail.queue":        "memory",
+	}))
+
+	queueProvider := &QueueServiceProvider{}
+	require.NoError(t, queueProvider.Register(app))
+	manager, err := container.Resolve[*queue.Manager](app)
+	require.NoError(t, err)
+	memory := queue.NewMemoryQueue()
+	manager.Register("memory", memory)
+
+	provider := &MailServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+
+	mailer, err := container.Resolve[*mail.Mailer](app)
+	require.NoError(t, err)
+	_, err = mailer.Send(context.Background(), queuedTestMailable{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, memory.Size())
+}
+
+func TestMailServiceProviderDatabaseSentLog(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"mail.default":         "array",
+		"mail.from.address":    "hello@example.com",
+		"mail.sent_log.driver": "database",
+		"mail.sent_log.table":  "sent_mail",
+	}))
+	databases := database.NewManager(database.Config{
+		Default: "default",
+		Connections: map[string]database.ConnectionConfig{
+			"default": {Driver: "sqlite", Database: filepath.Join(t.TempDir(), "mail.db")},
+		},
+	})
+	t.Cleanup(func() { databases.Close() })
+	conn := databases.Connection()
+	_, err := conn.Exec(`CREATE TABLE sent_mail (id INTEGER PRIMARY KEY AUTOINCREMENT, message_id VARCHAR(255) NOT NULL,
+recipient VARCHAR(255) NOT NULL, subject VARCHAR(998) NOT NULL, status VARCHAR(16) NOT NULL, error TEXT, updated_at TIMESTAMP NOT NULL)`)
+	require.NoError(t, err)
+	app.InstanceType(databases)
+
+	provider := &MailServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+
+	mailer, err := container.Resolve[*mail.Mailer](app)
+	require.NoError(t, err)
+	id, err := mailer.Send(context.Background(), queuedTestMailable{})
+	require.NoError(t, err)
+
+	// Another process reading the same table sees the status.
+	entries, err := mail.NewDatabaseSentLog(conn, "sent_mail").ForRecipient("jane@example.com")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, id, entries[0].MessageID)
+	assert.Equal(t, mail.StatusSent, entries[0].Status)
+}
+
+func TestMailServiceProviderUnsupportedSentLog(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"mail.sent_log.driver": "paper",
+	}))
+	provider := &MailServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	assert.ErrorContains(t, provider.Boot(app), "unsupported mail sent log driver")
+}
+
+type queuedTestMailable struct {
+	mail.Queueable
+}
+
+func (queuedTestMailable) Build() (*mail.Message, error) {
+	return &mail.Message{To: []string{"jane@example.com"}, Subject: "Hi"}, nil
+}
+
+func TestMailServiceProviderProvides(t *testing.T) {
+	provider := &MailServiceProvider{}
+	assert.Contains(t, provider.Provides(), "mail")
+}
+
+func TestMailServiceProviderLoadsMarkdownTemplates(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "welcome.md"), []byte("# Welcome {{ .Name }}"), 0644))
+
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"mail.markdown.path":  dir,
+		"mail.markdown.theme": map[string]any{"app_name": "Acme"},
+	}))
+	provider := &MailServiceProvider{}
+	require.NoError(t, provider.Register(app))
+
+	mailer, err := container.Resolve[*mail.Mailer](app)
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", mailer.Markdown().Theme().AppName)
+
+	_, text, err := mailer.Markdown().Render("welcome", map[string]string{"Name": "Jane"})
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome Jane\n", text)
+}
+
+type plainTestMailable struct{}
+
+func (plainTestMailable) Build() (*mail.Message, error) {
+	return &mail.Message{To: []string{"jane@example.com"}, Subject: "Hi", Text: "Hello"}, nil
+}
+
+func TestMailServiceProviderNamedMailers(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"mail.default":      "transactional",
+		"mail.from.address": "hello@example.com",
+		"mail.from.name":    "Acme",
+		"mail.mailers": map[string]any{
+			"transactional": map[string]any{"transport": "array"},
+			"marketing": map[string]any{
+				"transport": "array",
+				"from":      map[string]any{"address": "news@example.com"},
+			},
+			"smtp": map[string]any{"transport": "smtp", "host": "smtp.example.com", "port": "2525"},
+		},
+	}))
+	provider := &MailServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+
+	manager, err := container.Resolve[*mail.Manager](app)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"marketing", "smtp", "transactional"}, manager.Names())
+
+	transactional, err := manager.Mailer()
+	require.NoError(t, err)
+	defaultMailer, err := container.Resolve[*mail.Mailer](app)
+	require.NoError(t, err)
+	assert.Same(t, transactional, defaultMailer)
+
+	marketing, err := mailfacade.Use("marketing")
+	require.NoError(t, err)
+	_, err = transactional.SendNow(context.Background(), plainTestMailable{})
+	require.NoError(t, err)
+	_, err = marketing.SendNow(context.Background(), plainTestMailable{})
+	require.NoError(t, err)
+
+	// Mailers share one sent log.
+	entries, err := transactional.SentLog().ForRecipient("jane@example.com")
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestFromAddress(t *testing.T) {
+	assert.Equal(t, `"Acme" <hello@example.com>`, fromAddress("hello@example.com", "Acme"))
+	assert.Equal(t, "hello@example.com", fromAddress("hello@example.com", ""))
+	assert.Equal(t, "", fromAddress("", "Acme"))
+}
+
+func TestMailServiceProviderInvalidMailer(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"mail.mailers": map[string]any{
+			"smtp": map[string]any{"transport": "smtp"},
+		},
+	}))
+	provider := &MailServiceProvider{}
+	assert.ErrorContains(t, provider.Register(app), "mailer smtp")
+}
+
+func TestMailServiceProviderLoadsViews(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "layouts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "layouts", "mail.html"),
+		[]byte(`<html><head><style>p { margin: 0 }</style></head><body>{{ template "content" . }}</body></html>`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "welcome.html"),
+		[]byte(`{{ define "content" }}<p>Welcome {{ .Name }}</p>{{ end }}`), 0644))
+
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"mail.views.path":       dir,
+		"mail.views.layout":     "layouts.mail",
+		"mail.views.inline_css": true,
+	}))
+	provider := &MailServiceProvider{}
+	require.NoError(t, provider.Register(app))
+
+	mailer, err := container.Resolve[*mail.Mailer](app)
+	require.NoError(t, err)
+	require.NotNil(t, mailer.Views())
+
+	body, err := mailer.Views().Render("welcome", map[string]string{"Name": "Jane"})
+	require.NoError(t, err)
+	assert.Contains(t, body, `<p style="margin: 0">Welcome Jane</p>`)
+}
@@ -0,0 +1,97 @@
+package providers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	metricsfacade "github.com/genesysflow/go-genesys/facades/metrics"
+	"github.com/genesysflow/go-genesys/metrics"
+)
+
+// MetricsServiceProvider registers the metrics recorder and its driver.
+type MetricsServiceProvider struct {
+	BaseProvider
+}
+
+// Register registers the metrics services.
+// The driver is chosen by metrics.driver: null (default), memory,
+// prometheus, statsd or otlp.
+func (p *MetricsServiceProvider) Register(app contracts.Application) error {
+	p.app = app
+
+	driver, err := p.driver(app)
+	if err != nil {
+		return err
+	}
+	recorder := metrics.New(driver, metrics.Tags(app.GetConfig().GetStringMap("metrics.tags")))
+
+	app.InstanceType(recorder)
+	app.BindValue("metrics", recorder)
+
+	// Expose the Prometheus driver so its Handler can be mounted on a route.
+	if prometheus, ok := driver.(*metrics.Prometheus); ok {
+		app.InstanceType(prometheus)
+	}
+
+	return nil
+}
+
+// driver creates the driver named in metrics.driver.
+func (p *MetricsServiceProvider) driver(app contracts.Application) (metrics.Driver, error) {
+	cfg := app.GetConfig()
+	prefix := cfg.GetString("metrics.prefix")
+
+	switch driver := cfg.GetString("metrics.driver"); driver {
+	case "", "null":
+		return metrics.Null{}, nil
+	case "memory":
+		return metrics.NewRegistry(), nil
+	case "prometheus":
+		return metrics.NewPrometheus(prefix), nil
+	case "statsd":
+		addr := cfg.GetString("metrics.statsd.addr")
+		if addr == "" {
+			addr = "127.0.0.1:8125"
+		}
+		return metrics.NewStatsD(addr, prefix)
+	case "otlp":
+		options := metrics.OTLPOptions{
+			Endpoint:    cfg.GetString("metrics.otlp.endpoint"),
+			Headers:     cfg.GetStringMap("metrics.otlp.headers"),
+			ServiceName: cfg.GetString("metrics.otlp.service_name"),
+		}
+		if options.ServiceName == "" {
+			options.ServiceName = cfg.GetString("app.name")
+		}
+		if value := cfg.GetString("metrics.otlp.interval"); value != "" {
+			interval, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid metrics.otlp.interval: %w", err)
+			}
+			options.Interval = interval
+		}
+		return metrics.NewOTLP(options)
+	default:
+		return nil, fmt.Errorf("unsupported metrics driver: %s", driver)
+	}
+}
+
+// Boot bootstraps the metrics services.
+func (p *MetricsServiceProvider) Boot(app contracts.Application) error {
+	recorder, err := container.Resolve[*metrics.Recorder](app)
+	if err != nil {
+		return err
+	}
+
+	metricsfacade.SetInstance(recorder)
+	return nil
+}
+
+// Provides returns the services this provider registers.
+func (p *MetricsServiceProvider) Provides() []string {
+	return []string{
+		"metrics",
+	}
+}
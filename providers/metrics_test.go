@@ -0,0 +1,58 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/genesysflow/go-genesys/metrics"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsServiceProviderDefaultsToNull(t *testing.T) {
+	app := testutil.NewMockApplication()
+	provider := &MetricsServiceProvider{}
+
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+
+	recorder, ok := app.GetInstance("metrics").(*metrics.Recorder)
+	require.True(t, ok)
+	assert.IsType(t, metrics.Null{}, recorder.Driver())
+}
+
+func TestMetricsServiceProviderPrometheus(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"metrics.driver": "prometheus",
+		"metrics.prefix": "shop",
+		"metrics.tags":   map[string]string{"env": "prod"},
+	}))
+	provider := &MetricsServiceProvider{}
+	require.NoError(t, provider.Register(app))
+
+	recorder := app.GetInstance("metrics").(*metrics.Recorder)
+	prometheus, ok := recorder.Driver().(*metrics.Prometheus)
+	require.True(t, ok)
+
+	recorder.Count("orders.placed")
+	assert.Equal(t, 1.0, prometheus.Value("orders.placed", metrics.Tags{"env": "prod"}))
+}
+
+func TestMetricsServiceProviderInvalidConfig(t *testing.T) {
+	for name, config := range map[string]map[string]any{
+		"unknown driver":     {"metrics.driver": "graphite"},
+		"missing endpoint":   {"metrics.driver": "otlp"},
+		"malformed interval": {"metrics.driver": "otlp", "metrics.otlp.endpoint": "http://collector:4318", "metrics.otlp.interval": "soon"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(config))
+			provider := &MetricsServiceProvider{}
+			assert.Error(t, provider.Register(app))
+		})
+	}
+}
+
+func TestMetricsServiceProviderProvides(t *testing.T) {
+	provider := &MetricsServiceProvider{}
+	assert.Contains(t, provider.Provides(), "metrics")
+}
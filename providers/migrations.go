@@ -14,12 +14,15 @@ type MigrationServiceProvider struct {
 	BaseProvider
 	BeforeAllMigrations func() error
 	Migrations          []migrations.Migration
+
+	// Seeders are run in order by the db:seed command.
+	Seeders []database.Seeder
 }
 
 // Register registers the migration services.
 func (p *MigrationServiceProvider) Register(app contracts.Application) error {
 	p.app = app
-	return nil
+	return app.BindValue("database.seeders", p.Seeders)
 }
 
 // Boot bootstraps the migration services.
@@ -47,5 +50,5 @@ func (p *MigrationServiceProvider) Boot(app contracts.Application) error {
 
 // Provides returns the services this provider registers.
 func (p *MigrationServiceProvider) Provides() []string {
-	return []string{"migrator"}
+	return []string{"migrator", "database.seeders"}
 }
@@ -0,0 +1,122 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/events"
+	notificationfacade "github.com/genesysflow/go-genesys/facades/notification"
+	"github.com/genesysflow/go-genesys/notification"
+)
+
+// NotificationServiceProvider registers the notification manager and its channels.
+type NotificationServiceProvider struct {
+	BaseProvider
+}
+
+// Register registers the notification services.
+func (p *NotificationServiceProvider) Register(app contracts.Application) error {
+	p.app = app
+
+	cfg := app.GetConfig()
+	manager := notification.NewManager()
+
+	driver, err := p.smsDriver(app)
+	if err != nil {
+		return err
+	}
+	sms := notification.NewSMSChannel(driver, cfg.GetString("notification.sms.from"))
+
+	if max := cfg.GetInt("notification.sms.rate_limit.max"); max > 0 {
+		window := time.Hour
+		if value := cfg.GetString("notification.sms.rate_limit.window"); value != "" {
+			window, err = time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid notification.sms.rate_limit.window: %w", err)
+			}
+		}
+		sms.SetRateLimit(max, window)
+	}
+
+	manager.Extend(notification.SMSChannelName, sms)
+
+	app.InstanceType(manager)
+	app.InstanceType(sms)
+	app.BindValue("notification", manager)
+
+	return nil
+}
+
+// smsDriver creates the SMS driver named in notification.sms.driver.
+func (p *NotificationServiceProvider) smsDriver(app contracts.Application) (notification.SMSDriver, error) {
+	cfg := app.GetConfig()
+
+	switch driver := cfg.GetString("notification.sms.driver"); driver {
+	case "", "log":
+		return notification.NewLogSMSDriver(app.GetLogger()), nil
+	case "array":
+		return notification.NewArraySMSDriver(), nil
+	case "twilio":
+		return &notification.Twilio{
+			AccountSID:     cfg.GetString("notification.sms.twilio.account_sid"),
+			AuthToken:      cfg.GetString("notification.sms.twilio.auth_token"),
+			StatusCallback: cfg.GetString("notification.sms.twilio.status_callback"),
+		}, nil
+	case "vonage":
+		return &notification.Vonage{
+			APIKey:          cfg.GetString("notification.sms.vonage.key"),
+			APISecret:       cfg.GetString("notification.sms.vonage.secret"),
+			SignatureSecret: cfg.GetString("notification.sms.vonage.signature_secret"),
+			SignatureMethod: cfg.GetString("notification.sms.vonage.signature_method"),
+		}, nil
+	case "sns":
+		region := cfg.GetString("notification.sms.sns.region")
+		sns := &notification.SNS{
+			Region:  region,
+			SMSType: cfg.GetString("notification.sms.sns.sms_type"),
+		}
+		if key := cfg.GetString("notification.sms.sns.key"); key != "" {
+			sns.Credentials = credentials.NewStaticCredentialsProvider(key, cfg.GetString("notification.sms.sns.secret"), "")
+		} else {
+			// Fall back to the default AWS credential chain.
+			awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(region))
+			if err != nil {
+				return nil, err
+			}
+			sns.Credentials = awsCfg.Credentials
+		}
+		return sns, nil
+	default:
+		return nil, fmt.Errorf("unsupported sms driver: %s", driver)
+	}
+}
+
+// Boot bootstraps the notification services.
+// Delivery status events go to the event dispatcher, if one is registered.
+func (p *NotificationServiceProvider) Boot(app contracts.Application) error {
+	manager, err := container.Resolve[*notification.Manager](app)
+	if err != nil {
+		return err
+	}
+
+	if sms, err := container.Resolve[*notification.SMSChannel](app); err == nil {
+		if dispatcher, err := container.Resolve[*events.Dispatcher](app); err == nil {
+			sms.SetDispatcher(dispatcher)
+		}
+	}
+
+	notificationfacade.SetInstance(manager)
+	return nil
+}
+
+// Provides returns the services this provider registers.
+func (p *NotificationServiceProvider) Provides() []string {
+	return []string{
+		"notification",
+	}
+}
@@ -0,0 +1,81 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/notification"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationServiceProviderRegister(t *testing.T) {
+	app := testutil.NewMockApplication()
+	provider := &NotificationServiceProvider{}
+
+	require.NoError(t, provider.Register(app))
+
+	manager := app.GetInstance("notification")
+	assert.IsType(t, &notification.Manager{}, manager)
+
+	channel, err := manager.(*notification.Manager).Channel(notification.SMSChannelName)
+	require.NoError(t, err)
+	assert.IsType(t, &notification.LogSMSDriver{}, channel.(*notification.SMSChannel).Driver())
+}
+
+func TestNotificationServiceProviderSMSDrivers(t *testing.T) {
+	tests := map[string]any{
+		"twilio": &notification.Twilio{},
+		"vonage": &notification.Vonage{},
+		"array":  &notification.ArraySMSDriver{},
+	}
+
+	for driver, expected := range tests {
+		app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+			"notification.sms.driver": driver,
+		}))
+		provider := &NotificationServiceProvider{}
+		require.NoError(t, provider.Register(app), driver)
+
+		sms, err := container.Resolve[*notification.SMSChannel](app)
+		require.NoError(t, err)
+		assert.IsType(t, expected, sms.Driver(), driver)
+	}
+}
+
+func TestNotificationServiceProviderUnsupportedDriver(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"notification.sms.driver": "fax",
+	}))
+	provider := &NotificationServiceProvider{}
+
+	assert.Error(t, provider.Register(app))
+}
+
+func TestNotificationServiceProviderRateLimit(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"notification.sms.driver":            "array",
+		"notification.sms.from":              "+15550000000",
+		"notification.sms.rate_limit.max":    1,
+		"notification.sms.rate_limit.window": "1m",
+	}))
+	provider := &NotificationServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+
+	sms, err := container.Resolve[*notification.SMSChannel](app)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = sms.SendMessage(ctx, &notification.SMSMessage{To: "+15551234567", Content: "one"})
+	require.NoError(t, err)
+	_, err = sms.SendMessage(ctx, &notification.SMSMessage{To: "+15551234567", Content: "two"})
+	assert.ErrorIs(t, err, notification.ErrRateLimited)
+}
+
+func TestNotificationServiceProviderProvides(t *testing.T) {
+	provider := &NotificationServiceProvider{}
+	assert.Contains(t, provider.Provides(), "notification")
+}
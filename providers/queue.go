@@ -1,7 +1,15 @@
 package providers
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/genesysflow/go-genesys/container"
 	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/crypt"
+	"github.com/genesysflow/go-genesys/database"
+	queuefacade "github.com/genesysflow/go-genesys/facades/queue"
+	"github.com/genesysflow/go-genesys/health"
 	"github.com/genesysflow/go-genesys/queue"
 )
 
@@ -11,10 +19,26 @@ type QueueServiceProvider struct {
 }
 
 // Register registers the queue services.
+// queue.default names the default connection (sync unless set), and
+// queue.connections configures the memory, database and redis drivers.
 func (p *QueueServiceProvider) Register(app contracts.Application) error {
 	p.app = app
 
+	cfg := app.GetConfig()
 	manager := queue.NewManager()
+	if name := cfg.GetString("queue.default"); name != "" {
+		manager.SetDefaultConnection(name)
+	}
+	for name, entry := range cfg.GetMap("queue.connections") {
+		settings, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+		if err := registerQueueConnection(app, manager, name, settings); err != nil {
+			return fmt.Errorf("queue connection %s: %w", name, err)
+		}
+	}
+
 	app.InstanceType(manager)
 	app.BindValue("queue", manager)
 
@@ -22,7 +46,46 @@ func (p *QueueServiceProvider) Register(app contracts.Application) error {
 }
 
 // Boot bootstraps the queue services.
+// Connections with `encrypt: true` in queue.connections are encrypted with the app encrypter.
+// queue.failed.driver picks the dead-letter queue workers send failed jobs
+// to: memory, or database with queue.failed.connection and queue.failed.table.
+// With health.queue_max_depth set, a "queue" health check fails once the
+// default connection holds more pending jobs. Connections that cannot report
+// their size always pass.
 func (p *QueueServiceProvider) Boot(app contracts.Application) error {
+	manager, err := container.Resolve[*queue.Manager](app)
+	if err != nil {
+		return err
+	}
+
+	manager.Encrypt(encryptedEntries(app.GetConfig().GetMap("queue.connections"))...)
+	if encrypter, err := container.Resolve[*crypt.Encrypter](app); err == nil {
+		manager.SetEncrypter(encrypter)
+	}
+
+	deadLetter, err := queueDeadLetter(app)
+	if err != nil {
+		return err
+	}
+	if deadLetter != nil {
+		manager.SetDeadLetter(deadLetter)
+	}
+
+	if maxDepth := app.GetConfig().GetInt("health.queue_max_depth"); maxDepth > 0 {
+		registerHealthCheck(app, "queue", func(ctx context.Context) error {
+			conn, err := manager.Connection()
+			if err != nil {
+				return err
+			}
+			if sizer, ok := conn.(health.Sizer); ok {
+				return health.QueueDepth(sizer, maxDepth)(ctx)
+			}
+			return nil
+		})
+	}
+
+	queuefacade.SetInstance(manager)
+
 	return nil
 }
 
@@ -32,3 +95,71 @@ func (p *QueueServiceProvider) Provides() []string {
 		"queue",
 	}
 }
+
+// registerQueueConnection registers a connection from its queue.connections
+// entry. Database connections are created on first use, once the database
+// manager is available. retry_after sets how long database and redis jobs
+// stay reserved by a worker.
+func registerQueueConnection(app contracts.Application, manager *queue.Manager, name string, settings map[string]any) error {
+	switch driver, _ := settings["driver"].(string); driver {
+	case "":
+		// Entries without a driver only carry options such as encrypt.
+	case "sync":
+		manager.Register(name, queue.NewSyncQueue())
+	case "memory":
+		manager.Register(name, queue.NewMemoryQueue())
+	case "redis":
+		retryAfter, err := settingDuration(settings, "retry_after")
+		if err != nil {
+			return err
+		}
+		redis := queue.NewRedisQueue(redisClient(settings), settingString(settings, "prefix"))
+		redis.SetRetryAfter(retryAfter)
+		manager.Register(name, redis)
+	case "database":
+		retryAfter, err := settingDuration(settings, "retry_after")
+		if err != nil {
+			return err
+		}
+		manager.RegisterFunc(name, func() (queue.Queue, error) {
+			db, err := container.Resolve[*database.Manager](app)
+			if err != nil {
+				return nil, fmt.Errorf("database manager not available: %w", err)
+			}
+			conn := db.Connection(settingString(settings, "connection"))
+			if err := conn.Error(); err != nil {
+				return nil, err
+			}
+			jobs := queue.NewDatabaseQueue(conn, settingString(settings, "table"))
+			jobs.SetRetryAfter(retryAfter)
+			return jobs, nil
+		})
+	default:
+		return fmt.Errorf("unsupported queue driver: %s", driver)
+	}
+	return nil
+}
+
+// queueDeadLetter creates the dead-letter queue configured by queue.failed,
+// or returns nil if none is.
+func queueDeadLetter(app contracts.Application) (queue.DeadLetter, error) {
+	cfg := app.GetConfig()
+	switch driver := cfg.GetString("queue.failed.driver"); driver {
+	case "":
+		return nil, nil
+	case "memory":
+		return queue.NewMemoryDeadLetter(), nil
+	case "database":
+		databases, err := container.Resolve[*database.Manager](app)
+		if err != nil {
+			return nil, fmt.Errorf("failed job queue requires the database service: %w", err)
+		}
+		conn := databases.Connection(cfg.GetString("queue.failed.connection"))
+		if err := conn.Error(); err != nil {
+			return nil, fmt.Errorf("failed job queue: %w", err)
+		}
+		return queue.NewDatabaseDeadLetter(conn, cfg.GetString("queue.failed.table")), nil
+	default:
+		return nil, fmt.Errorf("unsupported failed job driver: %s", driver)
+	}
+}
@@ -1,8 +1,12 @@
 package providers
 
 import (
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/genesysflow/go-genesys/database"
+	queuefacade "github.com/genesysflow/go-genesys/facades/queue"
 	"github.com/genesysflow/go-genesys/queue"
 	"github.com/genesysflow/go-genesys/testutil"
 	"github.com/stretchr/testify/assert"
@@ -39,3 +43,96 @@ func TestQueueServiceProviderProvides(t *testing.T) {
 
 	assert.Contains(t, provides, "queue")
 }
+
+func TestQueueServiceProviderConnectionsFromConfig(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"queue.default": "memory",
+		"queue.connections": map[string]any{
+			"memory": map[string]any{"driver": "memory"},
+			"redis":  map[string]any{"driver": "redis", "prefix": "app:", "retry_after": "5m"},
+			"jobs":   map[string]any{"driver": "database", "table": "jobs"},
+		},
+	}))
+	provider := &QueueServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+
+	manager := app.GetInstance("queue").(*queue.Manager)
+	assert.Equal(t, "memory", manager.DefaultConnection())
+
+	conn, err := manager.Connection("redis")
+	require.NoError(t, err)
+	assert.IsType(t, &queue.RedisQueue{}, conn)
+
+	// Database connections are created on first use.
+	_, err = manager.Connection("jobs")
+	assert.ErrorContains(t, err, "database manager not available")
+
+	// The facade dispatches onto the default connection.
+	require.NoError(t, queuefacade.Dispatch(&healthTestJob{}))
+	conn, err = manager.Connection()
+	require.NoError(t, err)
+	assert.Equal(t, 1, conn.(*queue.MemoryQueue).Size())
+}
+
+func TestQueueServiceProviderUnsupportedDriver(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"queue.connections": map[string]any{
+			"sqs": map[string]any{"driver": "sqs"},
+		},
+	}))
+	err := (&QueueServiceProvider{}).Register(app)
+	assert.EqualError(t, err, "queue connection sqs: unsupported queue driver: sqs")
+
+	app = testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"queue.connections": map[string]any{
+			"jobs": map[string]any{"driver": "database", "retry_after": "soon"},
+		},
+	}))
+	err = (&QueueServiceProvider{}).Register(app)
+	assert.ErrorContains(t, err, "queue connection jobs: invalid retry_after")
+}
+
+func TestQueueServiceProviderDatabaseDeadLetter(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"queue.failed.driver": "database",
+		"queue.failed.table":  "dead_jobs",
+	}))
+	databases := database.NewManager(database.Config{
+		Default: "default",
+		Connections: map[string]database.ConnectionConfig{
+			"default": {Driver: "sqlite", Database: filepath.Join(t.TempDir(), "queue.db")},
+		},
+	})
+	t.Cleanup(func() { databases.Close() })
+	conn := databases.Connection()
+	_, err := conn.Exec(`CREATE TABLE dead_jobs (id INTEGER PRIMARY KEY AUTOINCREMENT, queue VARCHAR(255) NOT NULL,
+payload TEXT NOT NULL, attempts INTEGER NOT NULL, error TEXT NOT NULL, fingerprint VARCHAR(64) NOT NULL,
+poison BOOLEAN NOT NULL, failed_at INTEGER NOT NULL)`)
+	require.NoError(t, err)
+	app.InstanceType(databases)
+
+	provider := &QueueServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+
+	manager := app.GetInstance("queue").(*queue.Manager)
+	require.IsType(t, &queue.DatabaseDeadLetter{}, manager.DeadLetter())
+	require.NoError(t, manager.DeadLetter().Push(&queue.FailedJob{Job: &healthTestJob{}, Attempts: 3, Error: "boom", FailedAt: time.Now()}))
+
+	// Another process reading the same table sees the failure.
+	failed, err := queue.NewDatabaseDeadLetter(conn, "dead_jobs").Failed()
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, &healthTestJob{}, failed[0].Job)
+	assert.Equal(t, "boom", failed[0].Error)
+}
+
+func TestQueueServiceProviderUnsupportedDeadLetter(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"queue.failed.driver": "paper",
+	}))
+	provider := &QueueServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	assert.EqualError(t, provider.Boot(app), "unsupported failed job driver: paper")
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/genesysflow/go-genesys/container"
 	"github.com/genesysflow/go-genesys/contracts"
 	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/http/middleware"
 )
 
 // RouteServiceProvider registers HTTP routing services.
@@ -19,6 +20,14 @@ type RouteServiceProvider struct {
 	// Middleware is a list of global middleware to apply.
 	Middleware []http.MiddlewareFunc
 
+	// MiddlewareAliases registers application middleware by name, next to
+	// the framework's aliases such as "auth" and "throttle".
+	MiddlewareAliases map[string]http.MiddlewareFactory
+
+	// MiddlewareGroups names lists of middleware, such as "web" and "api".
+	// They are added after groups from the http.middleware_groups config.
+	MiddlewareGroups map[string][]string
+
 	// KernelConfig is optional kernel configuration.
 	KernelConfig *http.KernelConfig
 
@@ -37,6 +46,15 @@ func (p *RouteServiceProvider) Register(app contracts.Application) error {
 		p.kernel = http.NewKernel(app)
 	}
 
+	named := p.kernel.Router().MiddlewareRegistry()
+	middleware.RegisterAliases(named)
+	for name, factory := range p.MiddlewareAliases {
+		named.AliasFactory(name, factory)
+	}
+	for name, entries := range p.MiddlewareGroups {
+		named.Group(name, entries...)
+	}
+
 	// Apply global middleware
 	if len(p.Middleware) > 0 {
 		p.kernel.Use(p.Middleware...)
@@ -1,7 +1,14 @@
 package providers
 
 import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/genesysflow/go-genesys/container"
 	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/crypt"
+	"github.com/genesysflow/go-genesys/database"
 	"github.com/genesysflow/go-genesys/session"
 )
 
@@ -25,6 +32,12 @@ func (p *SessionServiceProvider) Register(app contracts.Application) error {
 		sessionConfig = *p.Config
 	} else {
 		// Load from config file
+		if driver := cfg.GetString("session.driver"); driver != "" {
+			sessionConfig.Storage = driver
+		}
+		if lifetime := cfg.GetInt("session.lifetime"); lifetime > 0 {
+			sessionConfig.Expiration = time.Duration(lifetime) * time.Minute
+		}
 		if name := cfg.GetString("session.cookie"); name != "" {
 			sessionConfig.CookieName = name
 		}
@@ -46,6 +59,8 @@ func (p *SessionServiceProvider) Register(app contracts.Application) error {
 	}
 
 	manager := session.NewManager(sessionConfig)
+	registerSessionDrivers(app, manager)
+
 	app.InstanceType(manager)
 	app.BindValue("session", manager)
 	app.BindValue("session.manager", manager)
@@ -65,3 +80,46 @@ func (p *SessionServiceProvider) Provides() []string {
 		"session.manager",
 	}
 }
+
+// registerSessionDrivers adds the file, database, redis and cookie drivers.
+// They are created on first use, once the services they need have booted.
+func registerSessionDrivers(app contracts.Application, manager *session.Manager) {
+	cfg := app.GetConfig()
+
+	manager.ExtendFunc("file", func() (contracts.SessionDriver, error) {
+		dir := cfg.GetString("session.files")
+		if dir == "" {
+			dir = filepath.Join(app.StoragePath(), "framework", "sessions")
+		}
+		return session.NewFileDriver(dir)
+	})
+
+	manager.ExtendFunc("database", func() (contracts.SessionDriver, error) {
+		db, err := container.Resolve[*database.Manager](app)
+		if err != nil {
+			return nil, fmt.Errorf("database manager not available: %w", err)
+		}
+		conn := db.Connection(cfg.GetString("session.connection"))
+		if err := conn.Error(); err != nil {
+			return nil, err
+		}
+		return session.NewDatabaseDriver(conn, cfg.GetString("session.table")), nil
+	})
+
+	manager.ExtendFunc("redis", func() (contracts.SessionDriver, error) {
+		settings := cfg.GetMap("session.redis")
+		prefix := settingString(settings, "prefix")
+		if prefix == "" {
+			prefix = "session:"
+		}
+		return session.NewRedisDriver(redisClient(settings), prefix), nil
+	})
+
+	manager.ExtendFunc("cookie", func() (contracts.SessionDriver, error) {
+		encrypter, err := container.Resolve[*crypt.Encrypter](app)
+		if err != nil {
+			return nil, fmt.Errorf("the cookie driver needs app.key to be set: %w", err)
+		}
+		return session.NewCookieDriver(encrypter), nil
+	})
+}
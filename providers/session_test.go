@@ -1,7 +1,9 @@
 package providers
 
 import (
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/genesysflow/go-genesys/session"
 	"github.com/genesysflow/go-genesys/testutil"
@@ -84,3 +86,30 @@ func TestSessionServiceProviderProvides(t *testing.T) {
 	assert.Contains(t, provides, "session")
 	assert.Contains(t, provides, "session.manager")
 }
+
+func TestSessionServiceProviderDrivers(t *testing.T) {
+	dir := t.TempDir()
+	cfg := testutil.NewMockConfig(map[string]any{
+		"session.driver":   "file",
+		"session.lifetime": 30,
+		"session.files":    dir,
+	})
+	app := testutil.NewMockApplicationWithConfig(cfg)
+	provider := &SessionServiceProvider{}
+	require.NoError(t, provider.Register(app))
+
+	manager := app.GetInstance("session").(*session.Manager)
+	assert.Equal(t, "file", manager.Config().Storage)
+	assert.Equal(t, 30*time.Minute, manager.Config().Expiration)
+	assert.IsType(t, &session.FileDriver{}, manager.Driver("file"))
+	assert.IsType(t, &session.RedisDriver{}, manager.Driver("redis"))
+
+	// The cookie driver needs the encrypter, which is not registered without app.key.
+	assert.Nil(t, manager.Driver("cookie"))
+
+	sess, err := manager.Start("")
+	require.NoError(t, err)
+	require.NoError(t, sess.Put("name", "ada"))
+	require.NoError(t, sess.Save())
+	assert.FileExists(t, filepath.Join(dir, sess.ID()))
+}
@@ -0,0 +1,92 @@
+package providers
+
+import (
+	"fmt"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/genesysflow/go-genesys/tenancy"
+)
+
+// TenancyServiceProvider registers the tenancy service, which identifies
+// the tenant of each request. Register it after the database provider so
+// tenants can have databases of their own.
+type TenancyServiceProvider struct {
+	BaseProvider
+
+	// Resolver looks tenants up by ID. Nil accepts every ID.
+	Resolver tenancy.Resolver
+}
+
+// Register checks the tenancy config.
+func (p *TenancyServiceProvider) Register(app contracts.Application) error {
+	p.app = app
+	_, err := p.identifiers(app.GetConfig())
+	return err
+}
+
+// Boot creates the tenancy service from the tenancy config:
+//
+//	tenancy.identify    identifiers tried in order: subdomain, header
+//	tenancy.domain      the domain tenants are subdomains of
+//	tenancy.header      the header naming the tenant (default X-Tenant)
+//	tenancy.connection  the connection tenant connections are copied from
+//	tenancy.optional    let requests without a tenant through
+func (p *TenancyServiceProvider) Boot(app contracts.Application) error {
+	cfg := app.GetConfig()
+	identifiers, err := p.identifiers(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Tenants without databases of their own don't need the database provider.
+	db, _ := container.Resolve[*database.Manager](app)
+
+	t := tenancy.New(tenancy.Config{
+		Identifiers: identifiers,
+		Resolver:    p.Resolver,
+		Connection:  cfg.GetString("tenancy.connection"),
+		Optional:    cfg.GetBool("tenancy.optional"),
+	}, db)
+
+	app.InstanceType(t)
+	app.BindValue("tenancy", t)
+
+	return nil
+}
+
+// Provides returns the services this provider registers.
+func (p *TenancyServiceProvider) Provides() []string {
+	return []string{
+		"tenancy",
+	}
+}
+
+func (p *TenancyServiceProvider) identifiers(cfg contracts.Config) ([]tenancy.Identifier, error) {
+	names := cfg.GetStringSlice("tenancy.identify")
+	if len(names) == 0 {
+		names = []string{"header"}
+	}
+
+	identifiers := make([]tenancy.Identifier, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case "subdomain":
+			domain := cfg.GetString("tenancy.domain")
+			if domain == "" {
+				return nil, fmt.Errorf("tenancy.domain is required to identify tenants by subdomain")
+			}
+			identifiers = append(identifiers, tenancy.Subdomain(domain))
+		case "header":
+			header := cfg.GetString("tenancy.header")
+			if header == "" {
+				header = "X-Tenant"
+			}
+			identifiers = append(identifiers, tenancy.Header(header))
+		default:
+			return nil, fmt.Errorf("unsupported tenant identifier: %s", name)
+		}
+	}
+	return identifiers, nil
+}
@@ -0,0 +1,34 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/tenancy"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenancyServiceProvider(t *testing.T) {
+	cfg := testutil.NewMockConfig(map[string]any{
+		"tenancy.identify": []string{"subdomain", "header"},
+		"tenancy.domain":   "example.com",
+	})
+	app := testutil.NewMockApplicationWithConfig(cfg)
+	provider := &TenancyServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+
+	resolved, err := container.Resolve[*tenancy.Tenancy](app)
+	require.NoError(t, err)
+	assert.Same(t, resolved, app.GetInstance("tenancy"))
+}
+
+func TestTenancyServiceProviderInvalidConfig(t *testing.T) {
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{"tenancy.identify": []string{"subdomain"}}))
+	assert.ErrorContains(t, (&TenancyServiceProvider{}).Register(app), "tenancy.domain is required")
+
+	app = testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{"tenancy.identify": []string{"cookie"}}))
+	assert.ErrorContains(t, (&TenancyServiceProvider{}).Register(app), "unsupported tenant identifier: cookie")
+}
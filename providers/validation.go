@@ -16,11 +16,15 @@ type ValidationServiceProvider struct {
 	AttributeNames map[string]string
 }
 
-// Register registers the validation services.
+// Register registers the validation services. Rule objects registered on
+// the validator get their dependencies injected from the application, and
+// the password rule checks the policy under validation.password: min,
+// letters, mixed_case, numbers, symbols, uncompromised and threshold.
 func (p *ValidationServiceProvider) Register(app contracts.Application) error {
 	p.app = app
 
 	v := validation.New()
+	v.SetContainer(app)
 
 	// Set custom messages if provided
 	if len(p.CustomMessages) > 0 {
@@ -32,6 +36,21 @@ func (p *ValidationServiceProvider) Register(app contracts.Application) error {
 		v.SetAttributeNames(p.AttributeNames)
 	}
 
+	// Register the password rule with the app's policy
+	cfg := app.GetConfig()
+	password := &validation.Password{
+		Min:           cfg.GetInt("validation.password.min"),
+		Letters:       cfg.GetBool("validation.password.letters"),
+		MixedCase:     cfg.GetBool("validation.password.mixed_case"),
+		Numbers:       cfg.GetBool("validation.password.numbers"),
+		Symbols:       cfg.GetBool("validation.password.symbols"),
+		Uncompromised: cfg.GetBool("validation.password.uncompromised"),
+		Threshold:     cfg.GetInt("validation.password.threshold"),
+	}
+	if err := v.RegisterRule("password", password); err != nil {
+		return err
+	}
+
 	app.InstanceType(v)
 	app.BindValue("validator", v)
 
@@ -89,3 +89,18 @@ func TestValidationServiceProviderProvides(t *testing.T) {
 
 	assert.Contains(t, provides, "validator")
 }
+
+func TestValidationServiceProviderPasswordPolicy(t *testing.T) {
+	cfg := testutil.NewMockConfig(map[string]any{
+		"validation.password.min":     12,
+		"validation.password.numbers": true,
+	})
+	app := testutil.NewMockApplicationWithConfig(cfg)
+	require.NoError(t, (&ValidationServiceProvider{}).Register(app))
+
+	validator := app.GetInstance("validator").(*validation.Validator)
+	rules := map[string]string{"password": "required,password"}
+	result := validator.ValidateMap(map[string]any{"password": "long enough but no digits"}, rules)
+	assert.Equal(t, "Password must be at least 12 characters and contain at least one number", result.FirstFor("password"))
+	assert.True(t, validator.ValidateMap(map[string]any{"password": "long enough with 1 digit"}, rules).Passes())
+}
@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"path/filepath"
+
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	viewfacade "github.com/genesysflow/go-genesys/facades/view"
+	"github.com/genesysflow/go-genesys/view"
+)
+
+// ViewServiceProvider registers the view engine.
+type ViewServiceProvider struct {
+	BaseProvider
+
+	// Composers is an optional function that shares data and registers
+	// view composers.
+	Composers func(engine *view.Engine)
+}
+
+// Register registers the view engine.
+// Views are loaded from view.path (default resources/views) with the
+// view.extension file extension (default .html). They are read again
+// before every render when view.reload is true, or in debug mode unless
+// view.reload is false.
+func (p *ViewServiceProvider) Register(app contracts.Application) error {
+	p.app = app
+
+	cfg := app.GetConfig()
+	dir := cfg.GetString("view.path")
+	if dir == "" {
+		dir = filepath.Join("resources", "views")
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(app.BasePath(), dir)
+	}
+
+	engine := view.NewEngine(dir, cfg.GetString("view.extension"))
+	reload := app.IsDebug()
+	if cfg.Get("view.reload") != nil {
+		reload = cfg.GetBool("view.reload")
+	}
+	engine.SetReload(reload)
+
+	app.InstanceType(engine)
+	app.BindValue("view", engine)
+
+	return nil
+}
+
+// Boot loads the views and runs Composers.
+func (p *ViewServiceProvider) Boot(app contracts.Application) error {
+	engine, err := container.Resolve[*view.Engine](app)
+	if err != nil {
+		return err
+	}
+	if err := engine.Load(); err != nil {
+		return err
+	}
+	if p.Composers != nil {
+		p.Composers(engine)
+	}
+
+	viewfacade.SetInstance(engine)
+	return nil
+}
+
+// Provides returns the services this provider registers.
+func (p *ViewServiceProvider) Provides() []string {
+	return []string{
+		"view",
+	}
+}
@@ -0,0 +1,65 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/container"
+	viewfacade "github.com/genesysflow/go-genesys/facades/view"
+	"github.com/genesysflow/go-genesys/mail"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/genesysflow/go-genesys/view"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewServiceProvider(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "welcome.tmpl"), []byte("Hi {{ .Name }} from {{ .App }}"), 0644))
+
+	app := testutil.NewMockApplicationWithConfig(testutil.NewMockConfig(map[string]any{
+		"view.path":      dir,
+		"view.extension": ".tmpl",
+	}))
+	provider := &ViewServiceProvider{
+		Composers: func(engine *view.Engine) {
+			engine.Share("App", "Acme")
+		},
+	}
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+
+	engine, err := container.Resolve[*view.Engine](app)
+	require.NoError(t, err)
+	html, err := engine.Render("welcome", view.Data{"Name": "Jane"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hi Jane from Acme", html)
+
+	html, err = viewfacade.Render("welcome", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Hi  from Acme", html)
+}
+
+func TestViewServiceProviderWithoutViews(t *testing.T) {
+	app := testutil.NewMockApplication()
+	provider := &ViewServiceProvider{}
+	require.NoError(t, provider.Register(app))
+	require.NoError(t, provider.Boot(app))
+	assert.Contains(t, provider.Provides(), "view")
+}
+
+func TestMailServiceProviderUsesViewEngine(t *testing.T) {
+	app := testutil.NewMockApplication()
+	viewProvider := &ViewServiceProvider{}
+	require.NoError(t, viewProvider.Register(app))
+	mailProvider := &MailServiceProvider{}
+	require.NoError(t, mailProvider.Register(app))
+	require.NoError(t, mailProvider.Boot(app))
+
+	engine, err := container.Resolve[*view.Engine](app)
+	require.NoError(t, err)
+	mailer, err := container.Resolve[*mail.Mailer](app)
+	require.NoError(t, err)
+	assert.Same(t, engine, mailer.Views())
+}
@@ -0,0 +1,176 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+)
+
+// DatabaseQueue keeps jobs in a table with id, queue, payload, attempts,
+// reserved_at, available_at and created_at columns, times being Unix
+// milliseconds. Generate its migration with `queue:table`. Jobs are stored
+// as JSON, so workers in other processes must RegisterJob their types.
+// Reserving relies on UPDATE … RETURNING, so the queue supports PostgreSQL
+// and SQLite but not MySQL.
+type DatabaseQueue struct {
+	conn       contracts.Connection
+	dialect    database.Dialect
+	table      string
+	retryAfter time.Duration
+}
+
+// NewDatabaseQueue creates a database queue. The connection's table prefix
+// is applied to table.
+func NewDatabaseQueue(conn contracts.Connection, table string) *DatabaseQueue {
+	if table == "" {
+		table = "jobs"
+	}
+	return &DatabaseQueue{
+		conn:       conn,
+		dialect:    database.NewDialect(conn.Driver()),
+		table:      conn.Prefix() + table,
+		retryAfter: DefaultRetryAfter,
+	}
+}
+
+// SetRetryAfter sets how long a job stays reserved before it becomes
+// available again. Defaults to DefaultRetryAfter.
+func (q *DatabaseQueue) SetRetryAfter(retryAfter time.Duration) {
+	if retryAfter > 0 {
+		q.retryAfter = retryAfter
+	}
+}
+
+// Push pushes a job onto the default queue.
+func (q *DatabaseQueue) Push(job Job) error {
+	return q.PushOn(DefaultQueue, job)
+}
+
+// PushOn pushes a job onto the named queue.
+func (q *DatabaseQueue) PushOn(queue string, job Job) error {
+	return q.LaterOn(queue, 0, job)
+}
+
+// Later pushes a job onto the default queue after the given delay.
+func (q *DatabaseQueue) Later(delay time.Duration, job Job) error {
+	return q.LaterOn(DefaultQueue, delay, job)
+}
+
+// LaterOn pushes a job onto the named queue after the given delay.
+func (q *DatabaseQueue) LaterOn(queue string, delay time.Duration, job Job) error {
+	payload, err := encodeJob(job)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	availableAt := now
+	if delay > 0 {
+		availableAt = now.Add(delay)
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (queue, payload, attempts, available_at, created_at) VALUES (%s, %s, 0, %s, %s)`,
+		q.dialect.Quote(q.table), q.dialect.Placeholder(1), q.dialect.Placeholder(2), q.dialect.Placeholder(3), q.dialect.Placeholder(4))
+	_, err = q.conn.ExecContext(context.Background(), query, queue, string(payload), availableAt.UnixMilli(), now.UnixMilli())
+	return err
+}
+
+// Pop removes the next job from the default queue.
+// It returns nil when the queue is empty.
+func (q *DatabaseQueue) Pop(ctx context.Context) (Job, error) {
+	return q.PopFrom(ctx, DefaultQueue)
+}
+
+// PopFrom reserves the oldest available job on the named queue and deletes
+// it. It returns nil when the queue is empty.
+func (q *DatabaseQueue) PopFrom(ctx context.Context, queue string) (Job, error) {
+	reservation, err := q.Reserve(ctx, queue)
+	if err != nil || reservation == nil {
+		return nil, err
+	}
+	return reservation.Job, q.Delete(ctx, reservation)
+}
+
+// Reserve reserves the oldest available job on the named queue, or one
+// whose reservation expired, in one statement so concurrent workers never
+// take the same job. It returns nil when the queue is empty.
+func (q *DatabaseQueue) Reserve(ctx context.Context, queue string) (*Reservation, error) {
+	// On PostgreSQL, workers skip rows another worker is reserving.
+	lock := ""
+	if q.dialect.Postgres() {
+		lock = " FOR UPDATE SKIP LOCKED"
+	}
+
+	d := q.dialect
+	query := fmt.Sprintf(`UPDATE %[1]s SET reserved_at = %[2]s, attempts = attempts + 1 WHERE id = (
+SELECT id FROM %[1]s WHERE queue = %[3]s AND (
+(reserved_at IS NULL AND available_at <= %[4]s) OR reserved_at <= %[5]s
+) ORDER BY id LIMIT 1%[6]s
+) RETURNING id, payload, attempts`, d.Quote(q.table), d.Placeholder(1), d.Placeholder(2), d.Placeholder(3), d.Placeholder(4), lock)
+
+	now := time.Now()
+	var (
+		id       int64
+		payload  string
+		attempts int
+	)
+	err := q.conn.QueryRowContext(ctx, query, now.UnixMilli(), queue, now.UnixMilli(), now.Add(-q.retryAfter).UnixMilli()).
+		Scan(&id, &payload, &attempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := decodeJob([]byte(payload))
+	if err != nil {
+		return nil, err
+	}
+	return &Reservation{Job: job, Queue: queue, Attempts: attempts, token: strconv.FormatInt(id, 10)}, nil
+}
+
+// Delete removes a reserved job.
+func (q *DatabaseQueue) Delete(ctx context.Context, reservation *Reservation) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id = %s`, q.dialect.Quote(q.table), q.dialect.Placeholder(1))
+	_, err := q.conn.ExecContext(ctx, query, reservationID(reservation))
+	return err
+}
+
+// Release makes a reserved job available again, keeping its attempts.
+func (q *DatabaseQueue) Release(ctx context.Context, reservation *Reservation) error {
+	query := fmt.Sprintf(`UPDATE %s SET reserved_at = NULL, attempts = %s WHERE id = %s`,
+		q.dialect.Quote(q.table), q.dialect.Placeholder(1), q.dialect.Placeholder(2))
+	_, err := q.conn.ExecContext(ctx, query, reservation.Attempts, reservationID(reservation))
+	return err
+}
+
+// reservationID returns the row id a reservation's token holds.
+func reservationID(reservation *Reservation) int64 {
+	id, _ := strconv.ParseInt(reservation.token, 10, 64)
+	return id
+}
+
+// Size returns the number of pending jobs across all queues, including
+// delayed jobs but not reserved ones. It returns 0 if the table can't be read.
+func (q *DatabaseQueue) Size() int {
+	var n int
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE reserved_at IS NULL`, q.dialect.Quote(q.table))
+	q.conn.QueryRowContext(context.Background(), query).Scan(&n)
+	return n
+}
+
+// SizeOf returns the number of pending jobs on the named queue, including
+// delayed jobs but not reserved ones. It returns 0 if the table can't be read.
+func (q *DatabaseQueue) SizeOf(queue string) int {
+	var n int
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE queue = %s AND reserved_at IS NULL`, q.dialect.Quote(q.table), q.dialect.Placeholder(1))
+	q.conn.QueryRowContext(context.Background(), query, queue).Scan(&n)
+	return n
+}
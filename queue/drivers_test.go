@@ -0,0 +1,459 @@
+package queue_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/genesysflow/go-genesys/database/schema"
+	"github.com/genesysflow/go-genesys/queue"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+type payloadJob struct {
+	ID int `json:"id"`
+}
+
+func (j *payloadJob) Handle() error { return nil }
+
+// fakeRedis implements the list, sorted set and EVAL commands RedisQueue
+// uses, emulating its scripts.
+type fakeRedis struct {
+	mu     sync.Mutex
+	lists  map[string][]string
+	zsets  map[string]map[string]int64
+	closed bool
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{lists: map[string][]string{}, zsets: map[string]map[string]int64{}}
+}
+
+func (r *fakeRedis) Do(ctx context.Context, args ...any) (any, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	str := func(i int) string { return fmt.Sprint(args[i]) }
+	switch args[0] {
+	case "RPUSH":
+		r.lists[str(1)] = append(r.lists[str(1)], string(args[2].([]byte)))
+		return int64(len(r.lists[str(1)])), nil
+	case "ZADD":
+		if r.zsets[str(1)] == nil {
+			r.zsets[str(1)] = map[string]int64{}
+		}
+		r.zsets[str(1)][string(args[3].([]byte))] = args[2].(int64)
+		return int64(1), nil
+	case "LLEN":
+		return int64(len(r.lists[str(1)])), nil
+	case "ZCARD":
+		return int64(len(r.zsets[str(1)])), nil
+	case "ZREM":
+		_, ok := r.zsets[str(1)][str(2)]
+		delete(r.zsets[str(1)], str(2))
+		if ok {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case "EVAL":
+		if args[2] == 2 {
+			// The release script: move the reservation to the front of the list.
+			list, reserved := str(3), str(4)
+			if _, ok := r.zsets[reserved][str(5)]; ok {
+				delete(r.zsets[reserved], str(5))
+				r.lists[list] = append([]string{string(args[6].([]byte))}, r.lists[list]...)
+			}
+			return int64(1), nil
+		}
+
+		// The reserve script: release due delayed and expired reserved jobs,
+		// then LPOP, count the attempt and add the job to the reserved set.
+		list, delayed, reserved, now := str(3), str(4), str(5), args[6].(int64)
+		for _, set := range []string{delayed, reserved} {
+			var due []string
+			for payload, score := range r.zsets[set] {
+				if score <= now {
+					due = append(due, payload)
+				}
+			}
+			sort.Slice(due, func(i, j int) bool { return r.zsets[set][due[i]] < r.zsets[set][due[j]] })
+			for _, payload := range due {
+				delete(r.zsets[set], payload)
+				r.lists[list] = append(r.lists[list], payload)
+			}
+		}
+		if len(r.lists[list]) == 0 {
+			return nil, nil
+		}
+		var payload map[string]any
+		json.Unmarshal([]byte(r.lists[list][0]), &payload)
+		r.lists[list] = r.lists[list][1:]
+		payload["attempts"] = payload["attempts"].(float64) + 1
+		encoded, _ := json.Marshal(payload)
+		if r.zsets[reserved] == nil {
+			r.zsets[reserved] = map[string]int64{}
+		}
+		r.zsets[reserved][string(encoded)] = args[7].(int64)
+		return string(encoded), nil
+	}
+	return nil, fmt.Errorf("unsupported command %v", args[0])
+}
+
+func (r *fakeRedis) Close() error {
+	r.closed = true
+	return nil
+}
+
+// storedQueue is implemented by the drivers that keep encoded jobs.
+type storedQueue interface {
+	queue.NamedQueue
+	queue.NamedSource
+	queue.DelayedQueue
+	SizeOf(queue string) int
+}
+
+func testStoredQueue(t *testing.T, q storedQueue) {
+	ctx := context.Background()
+
+	job, err := q.PopFrom(ctx, queue.DefaultQueue)
+	require.NoError(t, err)
+	assert.Nil(t, job)
+
+	require.NoError(t, q.PushOn(queue.DefaultQueue, &payloadJob{ID: 1}))
+	require.NoError(t, q.PushOn(queue.DefaultQueue, &payloadJob{ID: 2}))
+	require.NoError(t, q.PushOn("high", &payloadJob{ID: 3}))
+	require.NoError(t, q.LaterOn(queue.DefaultQueue, time.Hour, &payloadJob{ID: 4}))
+	assert.Equal(t, 3, q.SizeOf(queue.DefaultQueue))
+	assert.Equal(t, 1, q.SizeOf("high"))
+
+	for _, id := range []int{1, 2} {
+		job, err := q.PopFrom(ctx, queue.DefaultQueue)
+		require.NoError(t, err)
+		assert.Equal(t, &payloadJob{ID: id}, job)
+	}
+
+	// The delayed job is not available yet.
+	job, err = q.PopFrom(ctx, queue.DefaultQueue)
+	require.NoError(t, err)
+	assert.Nil(t, job)
+	assert.Equal(t, 1, q.SizeOf(queue.DefaultQueue))
+
+	job, err = q.PopFrom(ctx, "high")
+	require.NoError(t, err)
+	assert.Equal(t, &payloadJob{ID: 3}, job)
+
+	require.NoError(t, q.LaterOn("later", time.Millisecond, &payloadJob{ID: 5}))
+	time.Sleep(5 * time.Millisecond)
+	job, err = q.PopFrom(ctx, "later")
+	require.NoError(t, err)
+	assert.Equal(t, &payloadJob{ID: 5}, job)
+}
+
+// reservingQueue is implemented by the drivers that reserve jobs.
+type reservingQueue interface {
+	storedQueue
+	queue.Reserver
+	SetRetryAfter(retryAfter time.Duration)
+}
+
+func testReservations(t *testing.T, q reservingQueue) {
+	ctx := context.Background()
+	q.SetRetryAfter(20 * time.Millisecond)
+	require.NoError(t, q.PushOn("reports", &payloadJob{ID: 1}))
+
+	reservation, err := q.Reserve(ctx, "reports")
+	require.NoError(t, err)
+	require.NotNil(t, reservation)
+	assert.Equal(t, &payloadJob{ID: 1}, reservation.Job)
+	assert.Equal(t, "reports", reservation.Queue)
+	assert.Equal(t, 1, reservation.Attempts)
+	assert.Equal(t, 0, q.SizeOf("reports"))
+
+	// Reserved jobs aren't handed to other workers...
+	other, err := q.Reserve(ctx, "reports")
+	require.NoError(t, err)
+	assert.Nil(t, other)
+
+	// ...until the reservation expires, as it does when a worker dies.
+	time.Sleep(30 * time.Millisecond)
+	reservation, err = q.Reserve(ctx, "reports")
+	require.NoError(t, err)
+	require.NotNil(t, reservation)
+	assert.Equal(t, 2, reservation.Attempts)
+
+	// Released jobs keep their attempts.
+	require.NoError(t, q.Release(ctx, reservation))
+	assert.Equal(t, 1, q.SizeOf("reports"))
+	reservation, err = q.Reserve(ctx, "reports")
+	require.NoError(t, err)
+	require.NotNil(t, reservation)
+	assert.Equal(t, 3, reservation.Attempts)
+
+	require.NoError(t, q.Delete(ctx, reservation))
+	time.Sleep(30 * time.Millisecond)
+	reservation, err = q.Reserve(ctx, "reports")
+	require.NoError(t, err)
+	assert.Nil(t, reservation)
+}
+
+func TestRedisQueue(t *testing.T) {
+	client := newFakeRedis()
+	q := queue.NewRedisQueue(client, "app:")
+	testStoredQueue(t, q)
+	testReservations(t, q)
+
+	assert.Contains(t, client.zsets, "app:queues:default:delayed")
+	require.NoError(t, q.Close())
+	assert.True(t, client.closed)
+}
+
+// newTestConnection connects to the test database testutil.DatabaseEnv
+// selects. The database queue supports PostgreSQL and SQLite.
+func newTestConnection(t *testing.T) contracts.Connection {
+	t.Helper()
+	td, cleanup := testutil.SetupDatabaseFor(t, "pgsql", "sqlite")
+	t.Cleanup(cleanup)
+	manager := database.NewManager(database.Config{
+		Default: "default",
+		Connections: map[string]database.ConnectionConfig{
+			"default": {
+				Driver:   td.Driver,
+				Host:     td.Host,
+				Port:     td.Port,
+				Database: td.Database,
+				Username: td.Username,
+				Password: td.Password,
+				SSLMode:  "disable",
+			},
+		},
+	})
+	t.Cleanup(func() { manager.Close() })
+
+	conn := manager.Connection()
+	require.NoError(t, conn.Error())
+	return conn
+}
+
+func newTestDatabaseQueue(t *testing.T) *queue.DatabaseQueue {
+	t.Helper()
+	conn := newTestConnection(t)
+	require.NoError(t, schema.NewBuilder(conn.DB(), conn.Driver()).Create("jobs", func(table *schema.Blueprint) {
+		table.ID()
+		table.String("queue", 255)
+		table.Text("payload")
+		table.Integer("attempts").Default(0)
+		table.BigInteger("reserved_at").Nullable()
+		table.BigInteger("available_at")
+		table.BigInteger("created_at")
+	}))
+
+	return queue.NewDatabaseQueue(conn, "")
+}
+
+func TestDatabaseQueue(t *testing.T) {
+	q := newTestDatabaseQueue(t)
+	testStoredQueue(t, q)
+	assert.Equal(t, 1, q.Size())
+	testReservations(t, q)
+}
+
+func TestWorkerDeadLettersLostJobs(t *testing.T) {
+	q := newTestDatabaseQueue(t)
+	q.SetRetryAfter(time.Millisecond)
+	require.NoError(t, q.Push(&payloadJob{ID: 1}))
+
+	// A worker reserves the job and dies before finishing it.
+	_, err := q.Reserve(context.Background(), queue.DefaultQueue)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	dlq := queue.NewMemoryDeadLetter()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.NoError(t, queue.NewWorker(q, queue.WorkerOptions{Sleep: time.Millisecond, DeadLetter: dlq}).Run(ctx))
+
+	failed := dlq.All()
+	require.Len(t, failed, 1)
+	assert.Equal(t, &payloadJob{ID: 1}, failed[0].Job)
+	assert.Equal(t, queue.ErrJobLost.Error(), failed[0].Error)
+	assert.Equal(t, 1, failed[0].Attempts)
+	assert.True(t, failed[0].Poison)
+
+	reservation, err := q.Reserve(context.Background(), queue.DefaultQueue)
+	require.NoError(t, err)
+	assert.Nil(t, reservation, "dead-lettered jobs are deleted from the queue")
+}
+
+func TestDatabaseQueueEncrypted(t *testing.T) {
+	q := queue.NewEncryptedQueue(newTestDatabaseQueue(t), newTestEncrypter(t))
+
+	require.NoError(t, q.Push(&payloadJob{ID: 7}))
+	job, err := q.Pop(context.Background())
+	require.NoError(t, err)
+
+	encrypted, ok := job.(*queue.EncryptedJob)
+	require.True(t, ok)
+	original, err := encrypted.Decrypt()
+	require.NoError(t, err)
+	assert.Equal(t, &payloadJob{ID: 7}, original)
+}
+
+func TestManagerDispatch(t *testing.T) {
+	manager := queue.NewManager()
+	memory := queue.NewMemoryQueue()
+	manager.Register("memory", memory)
+	manager.SetDefaultConnection("memory")
+
+	require.NoError(t, manager.Dispatch(&payloadJob{ID: 1}))
+	require.NoError(t, manager.DispatchOn("high", &payloadJob{ID: 2}))
+	require.NoError(t, manager.DispatchAfter(time.Hour, &payloadJob{ID: 3}))
+
+	assert.Equal(t, "memory", manager.DefaultConnection())
+	assert.Equal(t, 2, memory.SizeOf(queue.DefaultQueue))
+	assert.Equal(t, 1, memory.SizeOf("high"))
+
+	manager.SetDefaultConnection("sync")
+	assert.EqualError(t, manager.DispatchAfter(time.Hour, &payloadJob{}), "queue connection [sync] does not support delayed jobs")
+}
+
+func TestManagerRegisterFunc(t *testing.T) {
+	manager := queue.NewManager()
+	calls := 0
+	manager.RegisterFunc("lazy", func() (queue.Queue, error) {
+		calls++
+		return queue.NewMemoryQueue(), nil
+	})
+	assert.Equal(t, 0, calls)
+
+	first, err := manager.Connection("lazy")
+	require.NoError(t, err)
+	second, err := manager.Connection("lazy")
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, calls)
+
+	manager.RegisterFunc("broken", func() (queue.Queue, error) {
+		return nil, fmt.Errorf("no database")
+	})
+	_, err = manager.Connection("broken")
+	assert.EqualError(t, err, "queue connection [broken]: no database")
+}
+
+// blockingJob records how many jobs run at once.
+type blockingJob struct {
+	running *int32
+	peak    *int32
+	done    *sync.WaitGroup
+}
+
+func (j *blockingJob) Handle() error {
+	n := atomic.AddInt32(j.running, 1)
+	for {
+		peak := atomic.LoadInt32(j.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(j.peak, peak, n) {
+			break
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	atomic.AddInt32(j.running, -1)
+	j.done.Done()
+	return nil
+}
+
+func TestWorkerConcurrency(t *testing.T) {
+	q := queue.NewMemoryQueue()
+	var running, peak int32
+	var done sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		done.Add(1)
+		require.NoError(t, q.Push(&blockingJob{running: &running, peak: &peak, done: &done}))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := make(chan error)
+	go func() {
+		finished <- queue.NewWorker(q, queue.WorkerOptions{Sleep: time.Millisecond, Concurrency: 3}).Run(ctx)
+	}()
+
+	done.Wait()
+	cancel()
+	require.NoError(t, <-finished)
+	assert.Equal(t, int32(3), peak)
+}
+
+func TestWorkerConcurrencyStopsOnError(t *testing.T) {
+	worker := queue.NewWorker(failingSource{}, queue.WorkerOptions{Sleep: time.Millisecond, Concurrency: 4})
+	assert.Error(t, worker.Run(context.Background()))
+}
+
+func TestDatabaseDeadLetter(t *testing.T) {
+	conn := newTestConnection(t)
+	require.NoError(t, schema.NewBuilder(conn.DB(), conn.Driver()).Create("failed_jobs", func(table *schema.Blueprint) {
+		table.ID()
+		table.String("queue", 255)
+		table.Text("payload")
+		table.Integer("attempts")
+		table.Text("error")
+		table.String("fingerprint", 64)
+		table.Boolean("poison")
+		table.BigInteger("failed_at")
+	}))
+
+	dlq := queue.NewDatabaseDeadLetter(conn, "")
+	failedAt := time.Now().Truncate(time.Millisecond)
+	require.NoError(t, dlq.Push(&queue.FailedJob{Job: &payloadJob{ID: 1}, Queue: "high", Attempts: 3,
+		Error: "timeout", Fingerprint: "abc", FailedAt: failedAt}))
+	require.NoError(t, dlq.Push(&queue.FailedJob{Job: &payloadJob{ID: 2}, Attempts: 1,
+		Error: "job panicked", Poison: true, FailedAt: failedAt}))
+
+	failed, err := dlq.Failed()
+	require.NoError(t, err)
+	require.Len(t, failed, 2)
+	assert.Equal(t, &payloadJob{ID: 1}, failed[0].Job)
+	assert.Equal(t, "high", failed[0].Queue)
+	assert.Equal(t, 3, failed[0].Attempts)
+	assert.Equal(t, "abc", failed[0].Fingerprint)
+	assert.False(t, failed[0].Poison)
+	assert.True(t, failed[0].FailedAt.Equal(failedAt))
+	assert.True(t, failed[1].Poison)
+
+	job, err := dlq.Find(failed[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, &payloadJob{ID: 2}, job.Job)
+
+	require.NoError(t, dlq.Forget(failed[1].ID))
+	job, err = dlq.Find(failed[1].ID)
+	require.NoError(t, err)
+	assert.Nil(t, job)
+}
+
+func TestManagerRetry(t *testing.T) {
+	manager := queue.NewManager()
+	memory := queue.NewMemoryQueue()
+	manager.Register("memory", memory)
+	manager.Register("encrypted", memory)
+	manager.Encrypt("encrypted")
+	manager.SetEncrypter(newTestEncrypter(t))
+
+	require.NoError(t, manager.Retry(&queue.FailedJob{Job: &payloadJob{ID: 1}, Queue: "high"}, "memory"))
+	job, err := memory.PopFrom(context.Background(), "high")
+	require.NoError(t, err)
+	assert.Equal(t, &payloadJob{ID: 1}, job)
+
+	// Jobs from encrypted connections are pushed back as they are.
+	encrypted := &queue.EncryptedJob{Payload: "sealed"}
+	require.NoError(t, manager.Retry(&queue.FailedJob{Job: encrypted}, "encrypted"))
+	job, err = memory.Pop(context.Background())
+	require.NoError(t, err)
+	assert.Same(t, encrypted, job)
+}
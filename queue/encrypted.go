@@ -0,0 +1,172 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/genesysflow/go-genesys/crypt"
+)
+
+// EncryptedJob carries another job's encrypted payload.
+// Handling it decrypts the payload and runs the original job.
+type EncryptedJob struct {
+	Payload string `json:"payload"`
+
+	encrypter *crypt.Encrypter
+}
+
+// Handle decrypts and runs the original job.
+func (j *EncryptedJob) Handle() error {
+	job, err := j.Decrypt()
+	if err != nil {
+		return err
+	}
+	return job.Handle()
+}
+
+// Decrypt returns the original job.
+func (j *EncryptedJob) Decrypt() (Job, error) {
+	if j.encrypter == nil {
+		return nil, fmt.Errorf("queue: no encrypter available for encrypted job")
+	}
+
+	plain, err := j.encrypter.Decrypt(j.Payload)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJob(plain)
+}
+
+// EncryptedQueue encrypts job payloads before pushing them to the underlying
+// queue, so they are never stored in plaintext.
+type EncryptedQueue struct {
+	queue     Queue
+	encrypter *crypt.Encrypter
+}
+
+// NewEncryptedQueue wraps a queue so its job payloads are encrypted at rest.
+func NewEncryptedQueue(queue Queue, encrypter *crypt.Encrypter) *EncryptedQueue {
+	return &EncryptedQueue{
+		queue:     queue,
+		encrypter: encrypter,
+	}
+}
+
+// Push encrypts and pushes a job onto the queue.
+func (q *EncryptedQueue) Push(job Job) error {
+	encrypted, err := q.encrypt(job)
+	if err != nil {
+		return err
+	}
+	return q.queue.Push(encrypted)
+}
+
+// encrypt wraps a job in an EncryptedJob.
+func (q *EncryptedQueue) encrypt(job Job) (*EncryptedJob, error) {
+	plain, err := encodeJob(job)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := q.encrypter.Encrypt(plain)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedJob{Payload: payload, encrypter: q.encrypter}, nil
+}
+
+// PushOn encrypts and pushes a job onto the named queue.
+func (q *EncryptedQueue) PushOn(queue string, job Job) error {
+	named, ok := q.queue.(NamedQueue)
+	if !ok {
+		return fmt.Errorf("queue: underlying queue does not support named queues")
+	}
+
+	encrypted, err := q.encrypt(job)
+	if err != nil {
+		return err
+	}
+	return named.PushOn(queue, encrypted)
+}
+
+// Later encrypts and pushes a job onto the queue after the given delay.
+func (q *EncryptedQueue) Later(delay time.Duration, job Job) error {
+	return q.LaterOn(DefaultQueue, delay, job)
+}
+
+// LaterOn encrypts and pushes a job onto the named queue after the given delay.
+func (q *EncryptedQueue) LaterOn(queue string, delay time.Duration, job Job) error {
+	delayed, ok := q.queue.(DelayedQueue)
+	if !ok {
+		return fmt.Errorf("queue: underlying queue does not support delayed jobs")
+	}
+
+	encrypted, err := q.encrypt(job)
+	if err != nil {
+		return err
+	}
+	return delayed.LaterOn(queue, delay, encrypted)
+}
+
+// PopFrom removes the next job from the named queue.
+func (q *EncryptedQueue) PopFrom(ctx context.Context, queue string) (Job, error) {
+	named, ok := q.queue.(NamedSource)
+	if !ok {
+		return nil, fmt.Errorf("queue: underlying queue does not support named queues")
+	}
+
+	job, err := named.PopFrom(ctx, queue)
+	return q.attach(job), err
+}
+
+// Pop removes the next job from the underlying queue.
+func (q *EncryptedQueue) Pop(ctx context.Context) (Job, error) {
+	source, ok := q.queue.(Source)
+	if !ok {
+		return nil, fmt.Errorf("queue: underlying queue does not support workers")
+	}
+
+	job, err := source.Pop(ctx)
+	return q.attach(job), err
+}
+
+// Reserve reserves the next job on the named queue of the underlying queue.
+func (q *EncryptedQueue) Reserve(ctx context.Context, queue string) (*Reservation, error) {
+	reserver, ok := q.queue.(Reserver)
+	if !ok {
+		return nil, fmt.Errorf("queue: underlying queue does not support reservations")
+	}
+
+	reservation, err := reserver.Reserve(ctx, queue)
+	if reservation != nil {
+		reservation.Job = q.attach(reservation.Job)
+	}
+	return reservation, err
+}
+
+// Delete removes a reserved job from the underlying queue.
+func (q *EncryptedQueue) Delete(ctx context.Context, reservation *Reservation) error {
+	reserver, ok := q.queue.(Reserver)
+	if !ok {
+		return fmt.Errorf("queue: underlying queue does not support reservations")
+	}
+	return reserver.Delete(ctx, reservation)
+}
+
+// Release makes a reserved job available again on the underlying queue.
+func (q *EncryptedQueue) Release(ctx context.Context, reservation *Reservation) error {
+	reserver, ok := q.queue.(Reserver)
+	if !ok {
+		return fmt.Errorf("queue: underlying queue does not support reservations")
+	}
+	return reserver.Release(ctx, reservation)
+}
+
+// attach gives a popped encrypted job access to the encrypter.
+func (q *EncryptedQueue) attach(job Job) Job {
+	if encrypted, ok := job.(*EncryptedJob); ok {
+		encrypted.encrypter = q.encrypter
+	}
+	return job
+}
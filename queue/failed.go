@@ -0,0 +1,297 @@
+package queue
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+)
+
+// FailedJob is a job that exhausted its attempts or crashed the worker.
+type FailedJob struct {
+	// ID identifies the job in its dead-letter queue. It is set by
+	// FailedJobs stores when jobs are read back.
+	ID string
+
+	// Job is the job that failed.
+	Job Job
+
+	// Queue is the named queue the job was taken from. Empty for the
+	// default queue of sources without named queues.
+	Queue string
+
+	// Attempts is the number of times the job was run.
+	Attempts int
+
+	// Error is the last error message.
+	Error string
+
+	// Fingerprint groups failures of the same job type with the same error.
+	Fingerprint string
+
+	// Poison is true if the job panicked instead of returning an error, or
+	// was lost by its workers (ErrJobLost).
+	Poison bool
+
+	// FailedAt is when the job was dead-lettered.
+	FailedAt time.Time
+}
+
+// DeadLetter receives jobs that can no longer be processed.
+type DeadLetter interface {
+	// Push stores a failed job.
+	Push(failed *FailedJob) error
+}
+
+// FailedJobs is implemented by dead-letter queues whose jobs can be read
+// back, so they can be inspected and retried with queue:failed and
+// queue:retry.
+type FailedJobs interface {
+	DeadLetter
+
+	// Failed returns the failed jobs, oldest first.
+	Failed() ([]*FailedJob, error)
+
+	// Find returns a failed job by ID, or nil if there is none.
+	Find(id string) (*FailedJob, error)
+
+	// Forget removes a failed job.
+	Forget(id string) error
+}
+
+// MemoryDeadLetter is an in-process dead-letter queue.
+type MemoryDeadLetter struct {
+	jobs   []*FailedJob
+	nextID int
+	mu     sync.Mutex
+}
+
+// NewMemoryDeadLetter creates a new in-memory dead-letter queue.
+func NewMemoryDeadLetter() *MemoryDeadLetter {
+	return &MemoryDeadLetter{
+		jobs: make([]*FailedJob, 0),
+	}
+}
+
+// Push stores a failed job.
+func (d *MemoryDeadLetter) Push(failed *FailedJob) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.nextID++
+	failed.ID = strconv.Itoa(d.nextID)
+	d.jobs = append(d.jobs, failed)
+	return nil
+}
+
+// Failed returns all failed jobs, oldest first.
+func (d *MemoryDeadLetter) Failed() ([]*FailedJob, error) {
+	return d.All(), nil
+}
+
+// Find returns a failed job by ID, or nil if there is none.
+func (d *MemoryDeadLetter) Find(id string) (*FailedJob, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, job := range d.jobs {
+		if job.ID == id {
+			return job, nil
+		}
+	}
+	return nil, nil
+}
+
+// Forget removes a failed job.
+func (d *MemoryDeadLetter) Forget(id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, job := range d.jobs {
+		if job.ID == id {
+			d.jobs = append(d.jobs[:i], d.jobs[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// All returns all failed jobs, oldest first.
+func (d *MemoryDeadLetter) All() []*FailedJob {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]*FailedJob(nil), d.jobs...)
+}
+
+// Fingerprints returns the number of failed jobs per fingerprint.
+func (d *MemoryDeadLetter) Fingerprints() map[string]int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	counts := make(map[string]int)
+	for _, job := range d.jobs {
+		counts[job.Fingerprint]++
+	}
+	return counts
+}
+
+// DatabaseDeadLetter keeps failed jobs in a table with id, queue, payload,
+// attempts, error, fingerprint, poison and failed_at columns, failed_at
+// being Unix milliseconds, so they outlive the worker and can be retried
+// from another process. Generate its migration with `queue:failed-table`.
+// Jobs are stored as JSON, so the process reading them back must
+// RegisterJob their types.
+type DatabaseDeadLetter struct {
+	conn    contracts.Connection
+	dialect database.Dialect
+	table   string
+}
+
+// NewDatabaseDeadLetter creates a database dead-letter queue. The
+// connection's table prefix is applied to table, which defaults to
+// "failed_jobs".
+func NewDatabaseDeadLetter(conn contracts.Connection, table string) *DatabaseDeadLetter {
+	if table == "" {
+		table = "failed_jobs"
+	}
+	return &DatabaseDeadLetter{
+		conn:    conn,
+		dialect: database.NewDialect(conn.Driver()),
+		table:   conn.Prefix() + table,
+	}
+}
+
+// Push stores a failed job.
+func (d *DatabaseDeadLetter) Push(failed *FailedJob) error {
+	payload, err := encodeJob(failed.Job)
+	if err != nil {
+		return err
+	}
+
+	p := d.dialect.Placeholder
+	query := fmt.Sprintf(`INSERT INTO %s (queue, payload, attempts, error, fingerprint, poison, failed_at)
+VALUES (%s, %s, %s, %s, %s, %s, %s)`, d.dialect.Quote(d.table), p(1), p(2), p(3), p(4), p(5), p(6), p(7))
+	_, err = d.conn.ExecContext(context.Background(), query, failed.Queue, string(payload), failed.Attempts,
+		failed.Error, failed.Fingerprint, failed.Poison, failed.FailedAt.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("queue: failed to store failed job: %w", err)
+	}
+	return nil
+}
+
+// Failed returns all failed jobs, oldest first.
+func (d *DatabaseDeadLetter) Failed() ([]*FailedJob, error) {
+	rows, err := d.conn.QueryContext(context.Background(), d.selectQuery("")+" ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("queue: failed to read failed jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*FailedJob, 0)
+	for rows.Next() {
+		failed, err := d.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, failed)
+	}
+	return jobs, rows.Err()
+}
+
+// Find returns a failed job by ID, or nil if there is none.
+func (d *DatabaseDeadLetter) Find(id string) (*FailedJob, error) {
+	rowID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+	failed, err := d.scan(d.conn.QueryRowContext(context.Background(), d.selectQuery("id = "+d.dialect.Placeholder(1)), rowID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return failed, err
+}
+
+// Forget removes a failed job.
+func (d *DatabaseDeadLetter) Forget(id string) error {
+	rowID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil
+	}
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id = %s`, d.dialect.Quote(d.table), d.dialect.Placeholder(1))
+	if _, err := d.conn.ExecContext(context.Background(), query, rowID); err != nil {
+		return fmt.Errorf("queue: failed to forget failed job: %w", err)
+	}
+	return nil
+}
+
+func (d *DatabaseDeadLetter) selectQuery(where string) string {
+	query := fmt.Sprintf(`SELECT id, queue, payload, attempts, error, fingerprint, poison, failed_at FROM %s`, d.dialect.Quote(d.table))
+	if where != "" {
+		query += " WHERE " + where
+	}
+	return query
+}
+
+func (d *DatabaseDeadLetter) scan(row interface{ Scan(dest ...any) error }) (*FailedJob, error) {
+	var (
+		failed   FailedJob
+		id       int64
+		payload  string
+		failedAt int64
+	)
+	err := row.Scan(&id, &failed.Queue, &payload, &failed.Attempts, &failed.Error, &failed.Fingerprint, &failed.Poison, &failedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("queue: failed to read failed jobs: %w", err)
+	}
+
+	job, err := decodeJob([]byte(payload))
+	if err != nil {
+		return nil, err
+	}
+	failed.ID = strconv.FormatInt(id, 10)
+	failed.Job = job
+	failed.FailedAt = time.UnixMilli(failedAt)
+	return &failed, nil
+}
+
+// volatileParts matches the parts of error messages that change between
+// occurrences of the same failure (ids, counts, addresses, durations).
+var volatileParts = regexp.MustCompile(`0x[0-9a-fA-F]+|[0-9a-fA-F]{8}-[0-9a-fA-F-]{27}|\d+`)
+
+// Fingerprint identifies a failure by job type, error type and error message,
+// ignoring numbers and ids so repeated occurrences group together.
+func Fingerprint(job Job, err error) string {
+	errType := "<nil>"
+	message := ""
+	if err != nil {
+		errType = reflect.TypeOf(err).String()
+		message = volatileParts.ReplaceAllString(err.Error(), "?")
+	}
+
+	sum := sha1.Sum([]byte(jobName(job) + "|" + errType + "|" + message))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// ErrJobLost is recorded for reserved jobs whose reservations expired more
+// often than their tries allow, usually because they crash or hang the
+// worker before it can finish them.
+var ErrJobLost = errors.New("queue: job reservation expired too many times; it may be crashing its worker")
+
+// PanicError is returned for jobs that panicked while being handled.
+type PanicError struct {
+	Value any
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("job panicked: %v", e.Value)
+}
@@ -1,14 +1,23 @@
 package queue
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"sync"
+	"time"
+
+	"github.com/genesysflow/go-genesys/crypt"
 )
 
 // Manager manages queue connections.
 type Manager struct {
 	connections map[string]Queue
+	creators    map[string]func() (Queue, error)
 	defaultConn string
+	encrypted   map[string]bool
+	encrypter   *crypt.Encrypter
+	deadLetter  DeadLetter
 	mu          sync.RWMutex
 }
 
@@ -16,17 +25,35 @@ type Manager struct {
 func NewManager() *Manager {
 	return &Manager{
 		connections: make(map[string]Queue),
+		creators:    make(map[string]func() (Queue, error)),
 		defaultConn: "sync",
+		encrypted:   make(map[string]bool),
 	}
 }
 
 // Connection returns a queue connection by name.
+// Connections marked with Encrypt are returned wrapped in an EncryptedQueue.
 func (m *Manager) Connection(name ...string) (Queue, error) {
-	connName := m.defaultConn
-	if len(name) > 0 && name[0] != "" {
-		connName = name[0]
+	connName := m.connectionName(name)
+
+	conn, err := m.connection(connName)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if !m.encrypted[connName] {
+		return conn, nil
+	}
+	if m.encrypter == nil {
+		return nil, fmt.Errorf("queue connection [%s] requires encryption but no encrypter is set", connName)
 	}
+	return NewEncryptedQueue(conn, m.encrypter), nil
+}
 
+// connection returns the raw queue connection by name.
+func (m *Manager) connection(connName string) (Queue, error) {
 	m.mu.RLock()
 	conn, ok := m.connections[connName]
 	m.mu.RUnlock()
@@ -35,13 +62,24 @@ func (m *Manager) Connection(name ...string) (Queue, error) {
 		return conn, nil
 	}
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if conn, ok := m.connections[connName]; ok {
+		return conn, nil
+	}
+
+	if creator, ok := m.creators[connName]; ok {
+		conn, err := creator()
+		if err != nil {
+			return nil, fmt.Errorf("queue connection [%s]: %w", connName, err)
+		}
+		m.connections[connName] = conn
+		delete(m.creators, connName)
+		return conn, nil
+	}
+
 	// Create connection if not exists
 	if connName == "sync" {
-		m.mu.Lock()
-		defer m.mu.Unlock()
-		if conn, ok := m.connections[connName]; ok {
-			return conn, nil
-		}
 		conn = NewSyncQueue()
 		m.connections[connName] = conn
 		return conn, nil
@@ -55,4 +93,148 @@ func (m *Manager) Register(name string, queue Queue) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.connections[name] = queue
+	delete(m.creators, name)
+}
+
+// RegisterFunc registers a connection that is created on first use, for
+// connections that depend on services booted after the queue provider.
+func (m *Manager) RegisterFunc(name string, creator func() (Queue, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.creators[name] = creator
+	delete(m.connections, name)
+}
+
+// DefaultConnection returns the name of the default connection.
+func (m *Manager) DefaultConnection() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.defaultConn
+}
+
+// SetDefaultConnection sets the name of the default connection.
+func (m *Manager) SetDefaultConnection(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.defaultConn = name
+}
+
+// connectionName returns the given connection name, or the default one.
+func (m *Manager) connectionName(name []string) string {
+	if len(name) > 0 && name[0] != "" {
+		return name[0]
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.defaultConn
+}
+
+// Dispatch pushes a job onto the default connection.
+func (m *Manager) Dispatch(job Job) error {
+	conn, err := m.Connection()
+	if err != nil {
+		return err
+	}
+	return conn.Push(job)
+}
+
+// DispatchOn pushes a job onto a named queue of the default connection.
+func (m *Manager) DispatchOn(queue string, job Job) error {
+	conn, err := m.Connection()
+	if err != nil {
+		return err
+	}
+	named, ok := conn.(NamedQueue)
+	if !ok {
+		return fmt.Errorf("queue connection [%s] does not support named queues", m.DefaultConnection())
+	}
+	return named.PushOn(queue, job)
+}
+
+// DispatchAfter pushes a job onto the default connection, to be processed
+// once delay has passed.
+func (m *Manager) DispatchAfter(delay time.Duration, job Job) error {
+	conn, err := m.Connection()
+	if err != nil {
+		return err
+	}
+	delayed, ok := conn.(DelayedQueue)
+	if !ok {
+		return fmt.Errorf("queue connection [%s] does not support delayed jobs", m.DefaultConnection())
+	}
+	return delayed.Later(delay, job)
+}
+
+// SetEncrypter sets the encrypter used for encrypted connections.
+func (m *Manager) SetEncrypter(encrypter *crypt.Encrypter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.encrypter = encrypter
+}
+
+// Encrypt marks connections whose job payloads must be encrypted at rest.
+func (m *Manager) Encrypt(names ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, name := range names {
+		m.encrypted[name] = true
+	}
+}
+
+// SetDeadLetter sets the dead-letter queue workers send failed jobs to.
+func (m *Manager) SetDeadLetter(deadLetter DeadLetter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deadLetter = deadLetter
+}
+
+// DeadLetter returns the dead-letter queue, or nil if none is set.
+func (m *Manager) DeadLetter() DeadLetter {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.deadLetter
+}
+
+// Retry pushes a failed job back onto its queue on the named connection,
+// or the default one. Jobs from encrypted connections are still encrypted,
+// so they are pushed without being encrypted again.
+func (m *Manager) Retry(failed *FailedJob, connection ...string) error {
+	connName := m.connectionName(connection)
+
+	var conn Queue
+	var err error
+	if _, ok := failed.Job.(*EncryptedJob); ok {
+		conn, err = m.connection(connName)
+	} else {
+		conn, err = m.Connection(connName)
+	}
+	if err != nil {
+		return err
+	}
+
+	if failed.Queue == "" {
+		return conn.Push(failed.Job)
+	}
+	named, ok := conn.(NamedQueue)
+	if !ok {
+		return fmt.Errorf("queue connection [%s] does not support named queues", connName)
+	}
+	return named.PushOn(failed.Queue, failed.Job)
+}
+
+// Close closes the connections that hold resources, such as network
+// clients. The application closes the manager when it terminates.
+func (m *Manager) Close() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var errs []error
+	for name, conn := range m.connections {
+		if closer, ok := conn.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("queue connection [%s]: %w", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
 }
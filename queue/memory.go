@@ -0,0 +1,159 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryQueue is an in-process queue driver.
+// Jobs are held in memory until a worker pops them. Reserved jobs can't
+// outlive the process, so reservations never expire.
+type MemoryQueue struct {
+	queues  map[string][]memoryJob
+	delayed []delayedJob
+	mu      sync.Mutex
+}
+
+// memoryJob is a queued job and the number of times it was attempted.
+type memoryJob struct {
+	job      Job
+	attempts int
+}
+
+// delayedJob is a job that becomes available at a later time.
+type delayedJob struct {
+	queue       string
+	job         Job
+	availableAt time.Time
+}
+
+// NewMemoryQueue creates a new in-memory queue.
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{
+		queues: make(map[string][]memoryJob),
+	}
+}
+
+// Push pushes a job onto the default queue.
+func (q *MemoryQueue) Push(job Job) error {
+	return q.PushOn(DefaultQueue, job)
+}
+
+// PushOn pushes a job onto the named queue.
+func (q *MemoryQueue) PushOn(queue string, job Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.queues[queue] = append(q.queues[queue], memoryJob{job: job})
+	return nil
+}
+
+// Later pushes a job onto the default queue after the given delay.
+func (q *MemoryQueue) Later(delay time.Duration, job Job) error {
+	return q.LaterOn(DefaultQueue, delay, job)
+}
+
+// LaterOn pushes a job onto the named queue after the given delay.
+func (q *MemoryQueue) LaterOn(queue string, delay time.Duration, job Job) error {
+	if delay <= 0 {
+		return q.PushOn(queue, job)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.delayed = append(q.delayed, delayedJob{
+		queue:       queue,
+		job:         job,
+		availableAt: time.Now().Add(delay),
+	})
+	return nil
+}
+
+// Pop removes the next job from the default queue.
+// It returns nil when the queue is empty.
+func (q *MemoryQueue) Pop(ctx context.Context) (Job, error) {
+	return q.PopFrom(ctx, DefaultQueue)
+}
+
+// PopFrom removes the next job from the named queue.
+// It returns nil when the queue is empty.
+func (q *MemoryQueue) PopFrom(ctx context.Context, queue string) (Job, error) {
+	reservation, err := q.Reserve(ctx, queue)
+	if err != nil || reservation == nil {
+		return nil, err
+	}
+	return reservation.Job, nil
+}
+
+// Reserve removes the next job from the named queue and counts the attempt.
+// It returns nil when the queue is empty.
+func (q *MemoryQueue) Reserve(ctx context.Context, queue string) (*Reservation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.releaseDelayed(time.Now())
+
+	jobs := q.queues[queue]
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	next := jobs[0]
+	q.queues[queue] = jobs[1:]
+	return &Reservation{Job: next.job, Queue: queue, Attempts: next.attempts + 1}, nil
+}
+
+// Delete does nothing: reserved jobs are already off the queue.
+func (q *MemoryQueue) Delete(ctx context.Context, reservation *Reservation) error {
+	return nil
+}
+
+// Release puts a reserved job back at the front of its queue.
+func (q *MemoryQueue) Release(ctx context.Context, reservation *Reservation) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	released := memoryJob{job: reservation.Job, attempts: reservation.Attempts}
+	q.queues[reservation.Queue] = append([]memoryJob{released}, q.queues[reservation.Queue]...)
+	return nil
+}
+
+// releaseDelayed moves delayed jobs that are due onto their queues.
+// The caller must hold q.mu.
+func (q *MemoryQueue) releaseDelayed(now time.Time) {
+	remaining := q.delayed[:0]
+	for _, delayed := range q.delayed {
+		if now.Before(delayed.availableAt) {
+			remaining = append(remaining, delayed)
+			continue
+		}
+		q.queues[delayed.queue] = append(q.queues[delayed.queue], memoryJob{job: delayed.job})
+	}
+	q.delayed = remaining
+}
+
+// Size returns the number of pending jobs across all queues, including delayed jobs.
+func (q *MemoryQueue) Size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	size := len(q.delayed)
+	for _, jobs := range q.queues {
+		size += len(jobs)
+	}
+	return size
+}
+
+// SizeOf returns the number of pending jobs on the named queue, including delayed jobs.
+func (q *MemoryQueue) SizeOf(queue string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	size := len(q.queues[queue])
+	for _, delayed := range q.delayed {
+		if delayed.queue == queue {
+			size++
+		}
+	}
+	return size
+}
@@ -0,0 +1,94 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	// jobTypes starts with EncryptedJob, which any process may read back
+	// from an encrypted connection or the dead-letter queue.
+	jobTypes = map[string]reflect.Type{
+		jobName(&EncryptedJob{}): reflect.TypeOf(&EncryptedJob{}),
+	}
+	jobTypesMu sync.RWMutex
+)
+
+// RegisterJob registers job types so they can be decoded from stored
+// payloads, as the database and Redis drivers and encrypted connections
+// keep them. Jobs pushed in the same process are registered automatically;
+// workers running in another process must register them explicitly.
+func RegisterJob(jobs ...Job) {
+	jobTypesMu.Lock()
+	defer jobTypesMu.Unlock()
+	for _, job := range jobs {
+		jobTypes[jobName(job)] = reflect.TypeOf(job)
+	}
+}
+
+// jobName returns the registry name for a job type.
+func jobName(job Job) string {
+	t := reflect.TypeOf(job)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// jobEnvelope is the stored form of a job: its registered type name and
+// its JSON encoded fields.
+type jobEnvelope struct {
+	Job  string          `json:"job"`
+	Data json.RawMessage `json:"data"`
+}
+
+// encodeJob registers the job's type and encodes it in an envelope.
+func encodeJob(job Job) ([]byte, error) {
+	RegisterJob(job)
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("queue: failed to encode job: %w", err)
+	}
+	payload, err := json.Marshal(jobEnvelope{Job: jobName(job), Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("queue: failed to encode job: %w", err)
+	}
+	return payload, nil
+}
+
+// decodeJob decodes an envelope into a job of its registered type.
+func decodeJob(payload []byte) (Job, error) {
+	var envelope jobEnvelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, fmt.Errorf("queue: failed to decode job payload: %w", err)
+	}
+
+	jobTypesMu.RLock()
+	t, ok := jobTypes[envelope.Job]
+	jobTypesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("queue: job type [%s] is not registered", envelope.Job)
+	}
+
+	var target reflect.Value
+	if t.Kind() == reflect.Pointer {
+		target = reflect.New(t.Elem())
+	} else {
+		target = reflect.New(t)
+	}
+	if err := json.Unmarshal(envelope.Data, target.Interface()); err != nil {
+		return nil, fmt.Errorf("queue: failed to decode job [%s]: %w", envelope.Job, err)
+	}
+	if t.Kind() != reflect.Pointer {
+		target = target.Elem()
+	}
+
+	job, ok := target.Interface().(Job)
+	if !ok {
+		return nil, fmt.Errorf("queue: type [%s] does not implement Job", envelope.Job)
+	}
+	return job, nil
+}
@@ -0,0 +1,122 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultQueue is the queue name used when none is given.
+const DefaultQueue = "default"
+
+// NamedQueue is implemented by drivers that support multiple named queues.
+type NamedQueue interface {
+	// PushOn pushes a job onto the named queue.
+	PushOn(queue string, job Job) error
+}
+
+// NamedSource is implemented by drivers that can hand jobs from a named queue to a worker.
+type NamedSource interface {
+	// PopFrom removes the next job from the named queue, or returns nil if none is available.
+	PopFrom(ctx context.Context, queue string) (Job, error)
+}
+
+// DelayedQueue is implemented by drivers that can hold jobs until a later time.
+type DelayedQueue interface {
+	// Later pushes a job onto the queue after the given delay.
+	Later(delay time.Duration, job Job) error
+
+	// LaterOn pushes a job onto the named queue after the given delay.
+	LaterOn(queue string, delay time.Duration, job Job) error
+}
+
+// QueueWeight is a queue a worker consumes, with its share of attempts.
+type QueueWeight struct {
+	Name string
+
+	// Weight is the queue's share in weighted round-robin. Zero means the
+	// queue is consumed in strict priority order.
+	Weight int
+}
+
+// ParseQueues parses a worker queue list such as "high,default,low" (strict
+// priority) or "high:5,default:3,low:1" (weighted round-robin).
+func ParseQueues(spec string) ([]QueueWeight, error) {
+	queues := make([]QueueWeight, 0)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, weight, hasWeight := strings.Cut(part, ":")
+		qw := QueueWeight{Name: strings.TrimSpace(name)}
+		if hasWeight {
+			w, err := strconv.Atoi(strings.TrimSpace(weight))
+			if err != nil || w <= 0 {
+				return nil, fmt.Errorf("queue: invalid weight for queue [%s]", qw.Name)
+			}
+			qw.Weight = w
+		}
+		queues = append(queues, qw)
+	}
+	return queues, nil
+}
+
+// queueScheduler decides the order in which a worker polls its queues.
+// Without weights, queues are always polled in the order given. With weights,
+// each poll starts at the queue chosen by smooth weighted round-robin and falls
+// back to the remaining queues in priority order, so low-priority queues get
+// their share even when higher ones are never empty.
+type queueScheduler struct {
+	queues   []QueueWeight
+	weighted bool
+	current  []int
+	total    int
+}
+
+func newQueueScheduler(queues []QueueWeight) *queueScheduler {
+	s := &queueScheduler{
+		queues:  append([]QueueWeight(nil), queues...),
+		current: make([]int, len(queues)),
+	}
+	for _, q := range queues {
+		if q.Weight > 0 {
+			s.weighted = true
+		}
+	}
+	for i := range queues {
+		if s.queues[i].Weight <= 0 {
+			s.queues[i].Weight = 1
+		}
+		s.total += s.queues[i].Weight
+	}
+	return s
+}
+
+// next returns the queue names to poll, in order, for the next job.
+func (s *queueScheduler) next() []string {
+	order := make([]string, 0, len(s.queues))
+	first := 0
+
+	if s.weighted {
+		for i, q := range s.queues {
+			s.current[i] += q.Weight
+			if s.current[i] > s.current[first] {
+				first = i
+			}
+		}
+		s.current[first] -= s.total
+		order = append(order, s.queues[first].Name)
+	}
+
+	for i, q := range s.queues {
+		if s.weighted && i == first {
+			continue
+		}
+		order = append(order, q.Name)
+	}
+	return order
+}
@@ -1,9 +1,19 @@
 package queue_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/crypt"
+	"github.com/genesysflow/go-genesys/heartbeat"
 	"github.com/genesysflow/go-genesys/queue"
 
 	"github.com/stretchr/testify/assert"
@@ -92,3 +102,395 @@ func TestManager(t *testing.T) {
 		assert.Equal(t, conn1, conn2)
 	})
 }
+
+func TestMemoryQueue(t *testing.T) {
+	q := queue.NewMemoryQueue()
+	first := &MockJob{}
+	second := &MockJob{}
+
+	assert.NoError(t, q.Push(first))
+	assert.NoError(t, q.Push(second))
+	assert.Equal(t, 2, q.Size())
+
+	job, err := q.Pop(context.Background())
+	assert.NoError(t, err)
+	assert.Same(t, first, job)
+
+	job, err = q.Pop(context.Background())
+	assert.NoError(t, err)
+	assert.Same(t, second, job)
+
+	job, err = q.Pop(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, job)
+}
+
+func TestMemoryQueueDelayedJobs(t *testing.T) {
+	q := queue.NewMemoryQueue()
+	delayed := &MockJob{}
+	immediate := &MockJob{}
+
+	assert.NoError(t, q.Later(50*time.Millisecond, delayed))
+	assert.NoError(t, q.Push(immediate))
+	assert.Equal(t, 2, q.Size())
+
+	job, _ := q.Pop(context.Background())
+	assert.Same(t, immediate, job)
+
+	job, _ = q.Pop(context.Background())
+	assert.Nil(t, job, "delayed job should not be available yet")
+
+	assert.Eventually(t, func() bool {
+		job, _ = q.Pop(context.Background())
+		return job == delayed
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0, q.Size())
+}
+
+// restartingJob signals a worker restart while it runs.
+type restartingJob struct {
+	store    cache.Store
+	executed bool
+}
+
+func (j *restartingJob) Handle() error {
+	j.executed = true
+	return queue.SignalRestart(j.store)
+}
+
+func TestWorkerRestartSignal(t *testing.T) {
+	store := cache.NewMemoryStore()
+	q := queue.NewMemoryQueue()
+	restarting := &restartingJob{store: store}
+	pending := &MockJob{}
+	q.Push(restarting)
+	q.Push(pending)
+
+	worker := queue.NewWorker(q, queue.WorkerOptions{
+		Sleep: time.Millisecond,
+		Cache: store,
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- worker.Run(context.Background()) }()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("worker did not exit after restart signal")
+	}
+
+	assert.True(t, restarting.executed)
+	assert.False(t, pending.executed, "worker should exit before picking up the next job")
+	assert.Equal(t, 1, q.Size())
+}
+
+func TestWorkerIgnoresSignalFromBeforeStart(t *testing.T) {
+	store := cache.NewMemoryStore()
+	assert.NoError(t, queue.SignalRestart(store))
+
+	q := queue.NewMemoryQueue()
+	job := &MockJob{}
+	q.Push(job)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	worker := queue.NewWorker(q, queue.WorkerOptions{Sleep: time.Millisecond, Cache: store})
+	assert.NoError(t, worker.Run(ctx))
+	assert.True(t, job.executed)
+	assert.False(t, worker.ShouldRestart())
+}
+
+type failingSource struct{}
+
+func (failingSource) Pop(ctx context.Context) (queue.Job, error) {
+	return nil, errors.New("connection lost")
+}
+
+func TestWorkerHeartbeat(t *testing.T) {
+	var mu sync.Mutex
+	var pings []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		pings = append(pings, r.URL.Path)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	monitor := &heartbeat.Monitor{
+		StartURL:   srv.URL + "/start",
+		SuccessURL: srv.URL + "/ok",
+		FailureURL: srv.URL + "/fail",
+	}
+
+	worker := queue.NewWorker(failingSource{}, queue.WorkerOptions{Heartbeat: monitor})
+	err := worker.Run(context.Background())
+	assert.Error(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"/start", "/ok", "/fail"}, pings)
+}
+
+var handledEmails []string
+
+type sendEmailJob struct {
+	Email string `json:"email"`
+}
+
+func (j *sendEmailJob) Handle() error {
+	handledEmails = append(handledEmails, j.Email)
+	return nil
+}
+
+func newTestEncrypter(t *testing.T) *crypt.Encrypter {
+	t.Helper()
+	enc, err := crypt.NewEncrypter([]byte(strings.Repeat("k", crypt.KeySize)))
+	assert.NoError(t, err)
+	return enc
+}
+
+func TestEncryptedQueue(t *testing.T) {
+	handledEmails = nil
+	inner := queue.NewMemoryQueue()
+	q := queue.NewEncryptedQueue(inner, newTestEncrypter(t))
+
+	assert.NoError(t, q.Push(&sendEmailJob{Email: "jane@example.com"}))
+
+	// The underlying queue only holds the encrypted payload.
+	stored, _ := inner.Pop(context.Background())
+	encrypted, ok := stored.(*queue.EncryptedJob)
+	assert.True(t, ok)
+	assert.NotContains(t, encrypted.Payload, "jane@example.com")
+	inner.Push(stored)
+
+	job, err := q.Pop(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, job.Handle())
+	assert.Equal(t, []string{"jane@example.com"}, handledEmails)
+}
+
+func TestEncryptedQueueWithSyncDriver(t *testing.T) {
+	handledEmails = nil
+	q := queue.NewEncryptedQueue(queue.NewSyncQueue(), newTestEncrypter(t))
+
+	assert.NoError(t, q.Push(&sendEmailJob{Email: "sync@example.com"}))
+	assert.Equal(t, []string{"sync@example.com"}, handledEmails)
+}
+
+func TestManagerEncryptedConnection(t *testing.T) {
+	manager := queue.NewManager()
+	manager.Encrypt("sync")
+
+	_, err := manager.Connection()
+	assert.Error(t, err, "encrypted connection without encrypter should fail")
+
+	manager.SetEncrypter(newTestEncrypter(t))
+	conn, err := manager.Connection()
+	assert.NoError(t, err)
+	assert.IsType(t, &queue.EncryptedQueue{}, conn)
+}
+
+type recordingJob struct {
+	name  string
+	order *[]string
+	limit int
+	stop  context.CancelFunc
+}
+
+func (j *recordingJob) Handle() error {
+	*j.order = append(*j.order, j.name)
+	if len(*j.order) >= j.limit {
+		j.stop()
+	}
+	return nil
+}
+
+func runQueues(t *testing.T, spec string, pending map[string]int, limit int) []string {
+	t.Helper()
+	queues, err := queue.ParseQueues(spec)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	order := make([]string, 0)
+	q := queue.NewMemoryQueue()
+	for name, count := range pending {
+		for i := 0; i < count; i++ {
+			q.PushOn(name, &recordingJob{name: name, order: &order, limit: limit, stop: cancel})
+		}
+	}
+
+	worker := queue.NewWorker(q, queue.WorkerOptions{Sleep: time.Millisecond, Queues: queues})
+	assert.NoError(t, worker.Run(ctx))
+	return order
+}
+
+func TestParseQueues(t *testing.T) {
+	queues, err := queue.ParseQueues("high:5, default:3,low")
+	assert.NoError(t, err)
+	assert.Equal(t, []queue.QueueWeight{
+		{Name: "high", Weight: 5},
+		{Name: "default", Weight: 3},
+		{Name: "low"},
+	}, queues)
+
+	_, err = queue.ParseQueues("high:0")
+	assert.Error(t, err)
+
+	queues, err = queue.ParseQueues("")
+	assert.NoError(t, err)
+	assert.Empty(t, queues)
+}
+
+func TestWorkerStrictPriority(t *testing.T) {
+	order := runQueues(t, "high,low", map[string]int{"high": 3, "low": 3}, 6)
+	assert.Equal(t, []string{"high", "high", "high", "low", "low", "low"}, order)
+}
+
+func TestWorkerWeightedQueues(t *testing.T) {
+	order := runQueues(t, "high:3,low:1", map[string]int{"high": 20, "low": 20}, 8)
+	assert.Equal(t, []string{"high", "high", "low", "high", "high", "high", "low", "high"}, order)
+}
+
+func TestWorkerWeightedQueuesFallBack(t *testing.T) {
+	// An empty queue's turn is given to the next queue in priority order.
+	order := runQueues(t, "high:1,low:5", map[string]int{"high": 2}, 2)
+	assert.Equal(t, []string{"high", "high"}, order)
+}
+
+func TestWorkerNamedQueuesRequireNamedSource(t *testing.T) {
+	worker := queue.NewWorker(failingSource{}, queue.WorkerOptions{
+		Queues: []queue.QueueWeight{{Name: "high"}},
+	})
+	assert.Error(t, worker.Run(context.Background()))
+}
+
+type flakyJob struct {
+	failures int
+	calls    int
+}
+
+func (j *flakyJob) Handle() error {
+	j.calls++
+	if j.calls <= j.failures {
+		return fmt.Errorf("attempt %d failed", j.calls)
+	}
+	return nil
+}
+
+type panickingJob struct {
+	calls int
+}
+
+func (j *panickingJob) Handle() error {
+	j.calls++
+	panic("nil map write")
+}
+
+func drain(t *testing.T, q *queue.MemoryQueue, options queue.WorkerOptions) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	options.Sleep = time.Millisecond
+	worker := queue.NewWorker(q, options)
+	go func() {
+		for q.Size() > 0 {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	assert.NoError(t, worker.Run(ctx))
+}
+
+func TestWorkerRetriesBeforeDeadLettering(t *testing.T) {
+	dlq := queue.NewMemoryDeadLetter()
+	q := queue.NewMemoryQueue()
+	recovers := &flakyJob{failures: 2}
+	broken := &flakyJob{failures: 10}
+	q.Push(recovers)
+	q.Push(broken)
+
+	drain(t, q, queue.WorkerOptions{Tries: 3, DeadLetter: dlq})
+
+	assert.Equal(t, 3, recovers.calls)
+	assert.Equal(t, 3, broken.calls)
+
+	failed := dlq.All()
+	assert.Len(t, failed, 1)
+	assert.Same(t, broken, failed[0].Job)
+	assert.Equal(t, 3, failed[0].Attempts)
+	assert.Equal(t, "attempt 3 failed", failed[0].Error)
+	assert.False(t, failed[0].Poison)
+}
+
+func TestWorkerReleasesJobsWhenStoppedDuringBackoff(t *testing.T) {
+	dlq := queue.NewMemoryDeadLetter()
+	q := queue.NewMemoryQueue()
+	broken := &flakyJob{failures: 10}
+	q.Push(broken)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		for q.Size() > 0 {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	worker := queue.NewWorker(q, queue.WorkerOptions{Sleep: time.Millisecond, Tries: 3, Backoff: time.Hour, DeadLetter: dlq})
+	assert.NoError(t, worker.Run(ctx))
+
+	assert.Equal(t, 1, broken.calls)
+	assert.Empty(t, dlq.All(), "a stopping worker should not dead-letter jobs with tries left")
+	assert.Equal(t, 1, q.Size())
+
+	// The next worker carries on with the remaining tries.
+	drain(t, q, queue.WorkerOptions{Tries: 3, DeadLetter: dlq})
+	assert.Equal(t, 3, broken.calls)
+	failed := dlq.All()
+	assert.Len(t, failed, 1)
+	assert.Equal(t, 3, failed[0].Attempts)
+}
+
+func TestWorkerDeadLettersPoisonMessages(t *testing.T) {
+	dlq := queue.NewMemoryDeadLetter()
+	q := queue.NewMemoryQueue()
+	poison := &panickingJob{}
+	after := &MockJob{}
+	q.Push(poison)
+	q.Push(after)
+
+	drain(t, q, queue.WorkerOptions{Tries: 5, DeadLetter: dlq})
+
+	assert.Equal(t, 1, poison.calls, "poison messages should not be retried")
+	assert.True(t, after.executed, "worker should keep processing after a poison message")
+
+	failed := dlq.All()
+	assert.Len(t, failed, 1)
+	assert.True(t, failed[0].Poison)
+	assert.Contains(t, failed[0].Error, "nil map write")
+}
+
+func TestFingerprintGroupsSimilarFailures(t *testing.T) {
+	job := &flakyJob{}
+	a := queue.Fingerprint(job, fmt.Errorf("user 42 not found"))
+	b := queue.Fingerprint(job, fmt.Errorf("user 1337 not found"))
+	c := queue.Fingerprint(job, fmt.Errorf("connection refused"))
+	d := queue.Fingerprint(&MockJob{}, fmt.Errorf("user 42 not found"))
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+	assert.NotEqual(t, a, d)
+
+	dlq := queue.NewMemoryDeadLetter()
+	dlq.Push(&queue.FailedJob{Fingerprint: a})
+	dlq.Push(&queue.FailedJob{Fingerprint: b})
+	dlq.Push(&queue.FailedJob{Fingerprint: c})
+	assert.Equal(t, map[string]int{a: 2, c: 1}, dlq.Fingerprints())
+}
@@ -0,0 +1,227 @@
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/genesysflow/go-genesys/cache"
+)
+
+// reserveScript moves due delayed jobs and jobs whose reservation expired
+// onto the queue's list, then pops the first job, counts the attempt and
+// adds it to the reserved set scored by when the reservation expires, in
+// one step so concurrent workers never take the same job.
+const reserveScript = `local function migrate(from)
+  local due = redis.call("ZRANGEBYSCORE", from, "-inf", ARGV[1])
+  for _, payload in ipairs(due) do
+    if redis.call("ZREM", from, payload) == 1 then
+      redis.call("RPUSH", KEYS[1], payload)
+    end
+  end
+end
+migrate(KEYS[2])
+migrate(KEYS[3])
+local job = redis.call("LPOP", KEYS[1])
+if not job then
+  return false
+end
+local payload = cjson.decode(job)
+payload["attempts"] = (payload["attempts"] or 0) + 1
+local reserved = cjson.encode(payload)
+redis.call("ZADD", KEYS[3], ARGV[2], reserved)
+return reserved`
+
+// releaseScript puts a reserved job back at the front of its list, unless
+// its reservation expired and another worker took it.
+const releaseScript = `if redis.call("ZREM", KEYS[2], ARGV[1]) == 1 then
+  redis.call("LPUSH", KEYS[1], ARGV[2])
+end
+return 1`
+
+// redisPayload gives each stored job an ID, so identical jobs stay
+// distinct in the delayed and reserved sets, and counts its attempts. The
+// job is kept as a string so the scripts never re-encode its fields.
+type redisPayload struct {
+	ID       string `json:"id"`
+	Attempts int    `json:"attempts"`
+	Job      string `json:"job"`
+}
+
+// RedisQueue keeps each named queue in a Redis list, and delayed and
+// reserved jobs in sorted sets scored by the time they become available.
+// Jobs are stored as JSON, so workers in other processes must RegisterJob
+// their types.
+type RedisQueue struct {
+	client     cache.RedisCommander
+	prefix     string
+	retryAfter time.Duration
+}
+
+// NewRedisQueue creates a Redis queue. Keys are prefixed with prefix.
+func NewRedisQueue(client cache.RedisCommander, prefix string) *RedisQueue {
+	return &RedisQueue{client: client, prefix: prefix, retryAfter: DefaultRetryAfter}
+}
+
+// SetRetryAfter sets how long a job stays reserved before it becomes
+// available again. Defaults to DefaultRetryAfter.
+func (q *RedisQueue) SetRetryAfter(retryAfter time.Duration) {
+	if retryAfter > 0 {
+		q.retryAfter = retryAfter
+	}
+}
+
+// Push pushes a job onto the default queue.
+func (q *RedisQueue) Push(job Job) error {
+	return q.PushOn(DefaultQueue, job)
+}
+
+// PushOn pushes a job onto the named queue.
+func (q *RedisQueue) PushOn(queue string, job Job) error {
+	payload, err := q.encode(job)
+	if err != nil {
+		return err
+	}
+	_, err = q.client.Do(context.Background(), "RPUSH", q.key(queue), payload)
+	return err
+}
+
+// Later pushes a job onto the default queue after the given delay.
+func (q *RedisQueue) Later(delay time.Duration, job Job) error {
+	return q.LaterOn(DefaultQueue, delay, job)
+}
+
+// LaterOn pushes a job onto the named queue after the given delay.
+func (q *RedisQueue) LaterOn(queue string, delay time.Duration, job Job) error {
+	if delay <= 0 {
+		return q.PushOn(queue, job)
+	}
+
+	payload, err := q.encode(job)
+	if err != nil {
+		return err
+	}
+	availableAt := time.Now().Add(delay).UnixMilli()
+	_, err = q.client.Do(context.Background(), "ZADD", q.delayedKey(queue), availableAt, payload)
+	return err
+}
+
+// Pop removes the next job from the default queue.
+// It returns nil when the queue is empty.
+func (q *RedisQueue) Pop(ctx context.Context) (Job, error) {
+	return q.PopFrom(ctx, DefaultQueue)
+}
+
+// PopFrom reserves the next job on the named queue and deletes it. It
+// returns nil when the queue is empty.
+func (q *RedisQueue) PopFrom(ctx context.Context, queue string) (Job, error) {
+	reservation, err := q.Reserve(ctx, queue)
+	if err != nil || reservation == nil {
+		return nil, err
+	}
+	return reservation.Job, q.Delete(ctx, reservation)
+}
+
+// Reserve reserves the next job on the named queue, releasing delayed jobs
+// that are due and jobs whose reservation expired first. It returns nil
+// when the queue is empty.
+func (q *RedisQueue) Reserve(ctx context.Context, queue string) (*Reservation, error) {
+	now := time.Now()
+	reply, err := q.client.Do(ctx, "EVAL", reserveScript, 3, q.key(queue), q.delayedKey(queue), q.reservedKey(queue),
+		now.UnixMilli(), now.Add(q.retryAfter).UnixMilli())
+	if err != nil || reply == nil {
+		return nil, err
+	}
+
+	reserved, ok := reply.(string)
+	if !ok {
+		return nil, fmt.Errorf("queue: unexpected reply reserving from [%s]", queue)
+	}
+	var payload redisPayload
+	if err := json.Unmarshal([]byte(reserved), &payload); err != nil {
+		return nil, fmt.Errorf("queue: failed to decode job payload: %w", err)
+	}
+	job, err := decodeJob([]byte(payload.Job))
+	if err != nil {
+		return nil, err
+	}
+	return &Reservation{Job: job, Queue: queue, Attempts: payload.Attempts, token: reserved}, nil
+}
+
+// Delete removes a reserved job.
+func (q *RedisQueue) Delete(ctx context.Context, reservation *Reservation) error {
+	_, err := q.client.Do(ctx, "ZREM", q.reservedKey(reservation.Queue), reservation.token)
+	return err
+}
+
+// Release puts a reserved job back at the front of its queue, keeping its
+// attempts.
+func (q *RedisQueue) Release(ctx context.Context, reservation *Reservation) error {
+	var payload redisPayload
+	if err := json.Unmarshal([]byte(reservation.token), &payload); err != nil {
+		return fmt.Errorf("queue: failed to decode job payload: %w", err)
+	}
+	payload.Attempts = reservation.Attempts
+	released, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.client.Do(ctx, "EVAL", releaseScript, 2, q.key(reservation.Queue), q.reservedKey(reservation.Queue),
+		reservation.token, released)
+	return err
+}
+
+// Size returns the number of pending jobs on the default queue, including
+// delayed jobs.
+func (q *RedisQueue) Size() int {
+	return q.SizeOf(DefaultQueue)
+}
+
+// SizeOf returns the number of pending jobs on the named queue, including
+// delayed jobs but not reserved ones. It returns 0 if Redis can't be reached.
+func (q *RedisQueue) SizeOf(queue string) int {
+	ctx := context.Background()
+	pending, _ := q.client.Do(ctx, "LLEN", q.key(queue))
+	delayed, _ := q.client.Do(ctx, "ZCARD", q.delayedKey(queue))
+	n, _ := pending.(int64)
+	m, _ := delayed.(int64)
+	return int(n + m)
+}
+
+// Close closes the Redis client.
+func (q *RedisQueue) Close() error {
+	if closer, ok := q.client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (q *RedisQueue) encode(job Job) ([]byte, error) {
+	data, err := encodeJob(job)
+	if err != nil {
+		return nil, err
+	}
+
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redisPayload{ID: hex.EncodeToString(id[:]), Job: string(data)})
+}
+
+func (q *RedisQueue) key(queue string) string {
+	return q.prefix + "queues:" + queue
+}
+
+func (q *RedisQueue) delayedKey(queue string) string {
+	return q.key(queue) + ":delayed"
+}
+
+func (q *RedisQueue) reservedKey(queue string) string {
+	return q.key(queue) + ":reserved"
+}
@@ -0,0 +1,438 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/heartbeat"
+)
+
+// RestartCacheKey is the cache key workers poll for restart signals.
+const RestartCacheKey = "genesys:queue:restart"
+
+// Source is implemented by queue drivers that can hand jobs to a worker.
+type Source interface {
+	// Pop removes the next job from the queue, or returns nil if none is available.
+	Pop(ctx context.Context) (Job, error)
+}
+
+// DefaultRetryAfter is how long the database and Redis drivers keep a job
+// reserved before making it available again. It must be longer than the
+// slowest job takes, or the job runs twice.
+const DefaultRetryAfter = 90 * time.Second
+
+// Reservation is a job a worker has reserved. The job stays on its queue
+// until the worker deletes it, and becomes available again if the worker
+// dies first.
+type Reservation struct {
+	// Job is the reserved job.
+	Job Job
+
+	// Queue is the named queue the job was reserved from.
+	Queue string
+
+	// Attempts is the number of times the job has been attempted,
+	// counting this reservation.
+	Attempts int
+
+	// token identifies the reservation to the driver.
+	token string
+}
+
+// Reserver is implemented by drivers that reserve jobs for a worker
+// instead of removing them when they are taken, so a job isn't lost when
+// its worker crashes. A reservation that is neither deleted nor released
+// expires, and the job becomes available again with its attempts counted.
+type Reserver interface {
+	// Reserve reserves the next job on the named queue, or returns nil if
+	// none is available.
+	Reserve(ctx context.Context, queue string) (*Reservation, error)
+
+	// Delete removes a reserved job from its queue.
+	Delete(ctx context.Context, reservation *Reservation) error
+
+	// Release makes a reserved job available again, keeping the
+	// reservation's Attempts.
+	Release(ctx context.Context, reservation *Reservation) error
+}
+
+// WorkerOptions configures a queue worker.
+type WorkerOptions struct {
+	// Sleep is how long the worker waits when the queue is empty.
+	Sleep time.Duration
+
+	// Cache is the store polled for restart signals. Restart signals are
+	// disabled when nil.
+	Cache cache.Store
+
+	// Logger receives job failures and lifecycle messages. Optional.
+	Logger contracts.Logger
+
+	// Heartbeat is pinged while the worker is alive, and with a failure
+	// if it stops on an error. Optional.
+	Heartbeat *heartbeat.Monitor
+
+	// HeartbeatInterval is the minimum time between success pings.
+	// Defaults to one minute.
+	HeartbeatInterval time.Duration
+
+	// Tries is the number of times a failing job is attempted before it is
+	// dead-lettered. Defaults to 1. Jobs from a Reserver whose earlier
+	// reservations expired count those attempts too, so a job that keeps
+	// crashing or hanging its worker is dead-lettered with ErrJobLost.
+	Tries int
+
+	// Backoff is how long the worker waits between attempts of a failing job.
+	Backoff time.Duration
+
+	// DeadLetter receives jobs that exhausted their tries or panicked. Optional.
+	DeadLetter DeadLetter
+
+	// Queues are the named queues to consume, highest priority first.
+	// The source must implement NamedSource. When empty, the worker pops
+	// from the source's default queue.
+	Queues []QueueWeight
+
+	// Concurrency is the number of jobs processed at once. Defaults to 1.
+	Concurrency int
+}
+
+// Worker processes jobs from a queue source until stopped.
+type Worker struct {
+	source      Source
+	options     WorkerOptions
+	scheduler   *queueScheduler
+	lastRestart any
+	lastBeat    time.Time
+	mu          sync.Mutex
+}
+
+// NewWorker creates a new queue worker.
+func NewWorker(source Source, options ...WorkerOptions) *Worker {
+	opts := WorkerOptions{Sleep: time.Second}
+	if len(options) > 0 {
+		opts = options[0]
+		if opts.Sleep <= 0 {
+			opts.Sleep = time.Second
+		}
+	}
+	if opts.HeartbeatInterval <= 0 {
+		opts.HeartbeatInterval = time.Minute
+	}
+	if opts.Tries <= 0 {
+		opts.Tries = 1
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+
+	worker := &Worker{
+		source:  source,
+		options: opts,
+	}
+	if len(opts.Queues) > 0 {
+		worker.scheduler = newQueueScheduler(opts.Queues)
+	}
+	return worker
+}
+
+// Run processes jobs until the context is cancelled or a restart is signalled.
+// The job in progress is always allowed to finish before Run returns, so
+// process supervisors can restart the worker with new code without losing work.
+// With Concurrency above 1, that many loops share the source; if one fails,
+// the others finish their current job and stop.
+func (w *Worker) Run(ctx context.Context) error {
+	w.lastRestart = w.restartSignal()
+	if w.options.Heartbeat != nil {
+		w.pingHeartbeat(w.options.Heartbeat.Start(ctx))
+	}
+
+	err := w.runPool(ctx)
+	if err != nil && w.options.Heartbeat != nil {
+		w.pingHeartbeat(w.options.Heartbeat.Failure(context.Background(), err))
+	}
+	return err
+}
+
+// runPool runs Concurrency worker loops and returns the first error.
+func (w *Worker) runPool(ctx context.Context) error {
+	if w.options.Concurrency == 1 {
+		return w.run(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, w.options.Concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < w.options.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := w.run(ctx); err != nil {
+				errs <- err
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
+
+// run is the worker loop.
+func (w *Worker) run(ctx context.Context) error {
+	for {
+		w.beat(ctx)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+		if w.ShouldRestart() {
+			w.log("Queue worker restarting: restart signal received")
+			return nil
+		}
+
+		reservation, err := w.pop(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		if reservation == nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(w.options.Sleep):
+			}
+			continue
+		}
+
+		w.process(ctx, reservation)
+	}
+}
+
+// pop returns the next job, polling named queues in scheduler order. Jobs
+// are reserved if the source is a Reserver, and popped otherwise.
+func (w *Worker) pop(ctx context.Context) (*Reservation, error) {
+	if w.scheduler == nil {
+		if reserver, ok := w.source.(Reserver); ok {
+			return reserver.Reserve(ctx, DefaultQueue)
+		}
+		job, err := w.source.Pop(ctx)
+		return reservationOf(job, ""), err
+	}
+
+	named, ok := w.source.(NamedSource)
+	if !ok {
+		return nil, fmt.Errorf("queue: source does not support named queues")
+	}
+	reserver, reserves := w.source.(Reserver)
+
+	w.mu.Lock()
+	order := w.scheduler.next()
+	w.mu.Unlock()
+
+	for _, queue := range order {
+		if reserves {
+			reservation, err := reserver.Reserve(ctx, queue)
+			if err != nil || reservation != nil {
+				return reservation, err
+			}
+			continue
+		}
+		job, err := named.PopFrom(ctx, queue)
+		if err != nil || job != nil {
+			return reservationOf(job, queue), err
+		}
+	}
+	return nil, nil
+}
+
+// reservationOf wraps a popped job, which has no earlier attempts.
+func reservationOf(job Job, queue string) *Reservation {
+	if job == nil {
+		return nil
+	}
+	return &Reservation{Job: job, Queue: queue}
+}
+
+// process runs a job up to Tries times. Jobs that keep failing, or that
+// panic (poison messages), are sent to the dead-letter queue so one bad
+// payload can't take the worker down. A job still waiting for its next
+// attempt when the worker stops is released for another worker to finish.
+func (w *Worker) process(ctx context.Context, reservation *Reservation) {
+	// A reservation counts as an attempt, so a job whose earlier
+	// reservations expired has used up some of its tries.
+	attempts := max(reservation.Attempts-1, 0)
+	if attempts >= w.options.Tries {
+		w.fail(reservation, attempts, ErrJobLost)
+		return
+	}
+
+	var err error
+	for first := true; attempts < w.options.Tries; first = false {
+		if !first && !w.backoff(ctx) {
+			w.release(reservation, attempts, err)
+			return
+		}
+
+		attempts++
+		err = handle(reservation.Job)
+		if err == nil {
+			w.delete(reservation)
+			return
+		}
+
+		var panicErr *PanicError
+		if errors.As(err, &panicErr) {
+			break
+		}
+	}
+
+	w.fail(reservation, attempts, err)
+}
+
+// handle runs a job, converting a panic into a PanicError.
+func handle(job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r}
+		}
+	}()
+	return job.Handle()
+}
+
+// backoff waits between attempts. It returns false if the worker is stopping.
+func (w *Worker) backoff(ctx context.Context) bool {
+	if w.options.Backoff <= 0 {
+		return ctx.Err() == nil
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(w.options.Backoff):
+		return true
+	}
+}
+
+// fail records a job that will not be attempted again, and removes it from
+// its queue.
+func (w *Worker) fail(reservation *Reservation, attempts int, err error) {
+	var panicErr *PanicError
+	failed := &FailedJob{
+		Job:         reservation.Job,
+		Queue:       reservation.Queue,
+		Attempts:    attempts,
+		Error:       err.Error(),
+		Fingerprint: Fingerprint(reservation.Job, err),
+		Poison:      errors.As(err, &panicErr) || errors.Is(err, ErrJobLost),
+		FailedAt:    time.Now(),
+	}
+
+	if w.options.Logger != nil {
+		w.options.Logger.Error("Queue job failed",
+			"error", failed.Error,
+			"queue", failed.Queue,
+			"attempts", attempts,
+			"fingerprint", failed.Fingerprint,
+			"poison", failed.Poison,
+		)
+	}
+
+	if w.options.DeadLetter != nil {
+		if dlqErr := w.options.DeadLetter.Push(failed); dlqErr != nil && w.options.Logger != nil {
+			w.options.Logger.Error("Failed to dead-letter queue job", "error", dlqErr.Error())
+		}
+	}
+	w.delete(reservation)
+}
+
+// release hands a job back to its queue with the attempts it has made, for
+// a worker that stops between attempts. Sources that can't reserve jobs
+// can't take it back, so the job fails with its last error.
+func (w *Worker) release(reservation *Reservation, attempts int, err error) {
+	reserver, ok := w.source.(Reserver)
+	if !ok {
+		w.fail(reservation, attempts, err)
+		return
+	}
+
+	reservation.Attempts = attempts
+	if releaseErr := reserver.Release(context.Background(), reservation); releaseErr != nil && w.options.Logger != nil {
+		w.options.Logger.Error("Failed to release queue job", "error", releaseErr.Error(), "queue", reservation.Queue)
+	}
+}
+
+// delete removes a reserved job from its queue once the worker is done with it.
+func (w *Worker) delete(reservation *Reservation) {
+	reserver, ok := w.source.(Reserver)
+	if !ok {
+		return
+	}
+	if err := reserver.Delete(context.Background(), reservation); err != nil && w.options.Logger != nil {
+		w.options.Logger.Error("Failed to delete queue job", "error", err.Error(), "queue", reservation.Queue)
+	}
+}
+
+// ShouldRestart reports whether a restart has been signalled since the worker started.
+func (w *Worker) ShouldRestart() bool {
+	if w.options.Cache == nil {
+		return false
+	}
+	return w.restartSignal() != w.lastRestart
+}
+
+// restartSignal reads the current restart timestamp from the cache.
+func (w *Worker) restartSignal() any {
+	if w.options.Cache == nil {
+		return nil
+	}
+	value, err := w.options.Cache.Get(RestartCacheKey)
+	if err != nil {
+		return w.lastRestart
+	}
+	return value
+}
+
+// beat pings the heartbeat monitor if the interval has elapsed.
+func (w *Worker) beat(ctx context.Context) {
+	if w.options.Heartbeat == nil {
+		return
+	}
+	w.mu.Lock()
+	if time.Since(w.lastBeat) < w.options.HeartbeatInterval {
+		w.mu.Unlock()
+		return
+	}
+	w.lastBeat = time.Now()
+	w.mu.Unlock()
+	w.pingHeartbeat(w.options.Heartbeat.Success(ctx))
+}
+
+// pingHeartbeat logs a failed heartbeat ping. Ping failures never stop the worker.
+func (w *Worker) pingHeartbeat(err error) {
+	if err != nil && w.options.Logger != nil {
+		w.options.Logger.Warn("Queue worker heartbeat failed", "error", err.Error())
+	}
+}
+
+func (w *Worker) log(msg string) {
+	if w.options.Logger != nil {
+		w.options.Logger.Info(msg)
+	}
+}
+
+// SignalRestart instructs all workers polling the given store to exit after
+// their current job.
+func SignalRestart(store cache.Store) error {
+	return store.Put(RestartCacheKey, time.Now().UnixNano(), 100*365*24*time.Hour)
+}
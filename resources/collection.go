@@ -0,0 +1,103 @@
+package resources
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/genesysflow/go-genesys/http"
+)
+
+// ResourceCollection presents a list of models, optionally as one page of
+// a larger result.
+type ResourceCollection[T any] struct {
+	definition *Definition[T]
+	items      []T
+	meta       Map
+	page       *pagination
+}
+
+type pagination struct {
+	page, perPage, total int
+}
+
+// Paginate marks the collection as page page of total models, perPage to
+// a page. The response then carries pagination "meta" and "links".
+func (c *ResourceCollection[T]) Paginate(page, perPage, total int) *ResourceCollection[T] {
+	if perPage < 1 {
+		perPage = 1
+	}
+	c.page = &pagination{page: page, perPage: perPage, total: total}
+	return c
+}
+
+// WithMeta adds a top-level "meta" entry to the response.
+func (c *ResourceCollection[T]) WithMeta(key string, value any) *ResourceCollection[T] {
+	if c.meta == nil {
+		c.meta = Map{}
+	}
+	c.meta[key] = value
+	return c
+}
+
+// ToMap returns the response body: the models under "data", then "meta"
+// and, for paginated collections, "links" to the first, previous, next and
+// last pages. It implements http.Transformer.
+//
+//	{
+//	  "data": [...],
+//	  "meta": {"page": 2, "per_page": 15, "total": 40, "total_pages": 3, "has_more": true},
+//	  "links": {"first": "/posts?page=1", "prev": "/posts?page=1", "next": "/posts?page=3", "last": "/posts?page=3"}
+//	}
+func (c *ResourceCollection[T]) ToMap(ctx *http.Context) map[string]any {
+	body := Map{"data": c.resolve(ctx, requestedIncludes(ctx))}
+
+	meta := Map{}
+	for key, value := range c.meta {
+		meta[key] = value
+	}
+	if p := c.page; p != nil {
+		totalPages := (p.total + p.perPage - 1) / p.perPage
+		meta["page"] = p.page
+		meta["per_page"] = p.perPage
+		meta["total"] = p.total
+		meta["total_pages"] = totalPages
+		meta["has_more"] = p.page < totalPages
+		body["links"] = c.links(ctx, totalPages)
+	}
+	if len(meta) > 0 {
+		body["meta"] = meta
+	}
+	return body
+}
+
+func (c *ResourceCollection[T]) resolve(ctx *http.Context, includes includeTree) any {
+	data := make([]Map, len(c.items))
+	for i, item := range c.items {
+		data[i] = c.definition.transform(ctx, item, includes)
+	}
+	return data
+}
+
+// links returns page URLs built from the request's path and query, with
+// null for pages that do not exist.
+func (c *ResourceCollection[T]) links(ctx *http.Context, totalPages int) Map {
+	pageURL := func(page int) any {
+		if page < 1 || page > totalPages {
+			return nil
+		}
+		path, query := "", url.Values{}
+		if ctx != nil {
+			path = ctx.Path()
+			query, _ = url.ParseQuery(string(ctx.FiberCtx().Request().URI().QueryString()))
+		}
+		query.Set("page", strconv.Itoa(page))
+		return path + "?" + query.Encode()
+	}
+
+	return Map{
+		"first": pageURL(min(1, totalPages)),
+		"prev":  pageURL(c.page.page - 1),
+		"next":  pageURL(c.page.page + 1),
+		"last":  pageURL(totalPages),
+	}
+}
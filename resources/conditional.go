@@ -0,0 +1,50 @@
+package resources
+
+import "reflect"
+
+type missing struct{}
+
+// Missing leaves a field or include out of the representation.
+var Missing any = missing{}
+
+// When returns value if condition holds, and Missing otherwise. A value of
+// type func() any is only called when condition holds:
+//
+//	"email": resources.When(ctx.User() != nil, user.Email),
+func When(condition bool, value any) any {
+	if !condition {
+		return Missing
+	}
+	return value
+}
+
+// WhenLoaded returns relation, or what present returns for it, if the
+// relation was loaded, that is, it is not a nil pointer, slice or map.
+// Otherwise the field is left out, so a post's author appears only when the
+// query fetched it:
+//
+//	"author": resources.WhenLoaded(post.Author, func() any {
+//		return UserResource.Make(post.Author)
+//	}),
+func WhenLoaded(relation any, present ...func() any) any {
+	if !loaded(relation) {
+		return Missing
+	}
+	if len(present) > 0 {
+		return present[0]
+	}
+	return relation
+}
+
+// loaded reports whether a relation holds a value.
+func loaded(relation any) bool {
+	if relation == nil {
+		return false
+	}
+	switch v := reflect.ValueOf(relation); v.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return !v.IsNil()
+	default:
+		return true
+	}
+}
@@ -0,0 +1,175 @@
+// Package resources shapes models into API responses. A Definition says
+// which fields a model exposes and which related data clients may include;
+// Resource and ResourceCollection apply it to one model or a page of them:
+//
+//	var PostResource = &resources.Definition[*models.Post]{
+//		Fields: func(ctx *http.Context, post *models.Post) resources.Map {
+//			return resources.Map{
+//				"id":     post.ID,
+//				"title":  post.Title,
+//				"author": resources.WhenLoaded(post.Author, func() any {
+//					return UserResource.Make(post.Author)
+//				}),
+//			}
+//		},
+//		Includes: map[string]resources.Include[*models.Post]{
+//			"comments": func(ctx *http.Context, post *models.Post) any {
+//				return CommentResource.Collection(post.Comments)
+//			},
+//		},
+//	}
+//
+//	return ctx.JSONResponse(PostResource.Make(post)) // {"data": {...}}
+//
+// Resources implement http.Transformer, so JSONResponse, Created and
+// Negotiate send them as shaped.
+package resources
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/http"
+)
+
+// IncludeParam is the query parameter that lists the includes a client
+// asks for, as in "?include=author,comments.author".
+const IncludeParam = "include"
+
+// Map is the representation of a model.
+type Map = map[string]any
+
+// Include returns related data for a model: a value, a Resource or a
+// ResourceCollection, or Missing to leave the key out.
+type Include[T any] func(ctx *http.Context, item T) any
+
+// Definition describes how models of type T are presented.
+type Definition[T any] struct {
+	// Fields returns the fields always present. Values may be resources,
+	// which are resolved with the includes requested under their key, or
+	// conditional values from When and WhenLoaded.
+	Fields func(ctx *http.Context, item T) Map
+
+	// Includes are the related data clients may ask for by name with
+	// IncludeParam. Names that are not listed are ignored.
+	Includes map[string]Include[T]
+
+	// DefaultIncludes are included without being asked for.
+	DefaultIncludes []string
+}
+
+// Make returns a resource for one model.
+func (d *Definition[T]) Make(item T) *Resource[T] {
+	return &Resource[T]{definition: d, item: item}
+}
+
+// Collection returns a resource for a list of models.
+func (d *Definition[T]) Collection(items []T) *ResourceCollection[T] {
+	return &ResourceCollection[T]{definition: d, items: items}
+}
+
+// transform presents one model with the requested includes.
+func (d *Definition[T]) transform(ctx *http.Context, item T, includes includeTree) Map {
+	data := Map{}
+	if d.Fields != nil {
+		data = resolveMap(ctx, d.Fields(ctx, item), includes)
+	}
+
+	for name, include := range d.Includes {
+		if _, requested := includes[name]; !requested && !slices.Contains(d.DefaultIncludes, name) {
+			continue
+		}
+		if value := resolve(ctx, include(ctx, item), includes[name]); value != Missing {
+			data[name] = value
+		}
+	}
+	return data
+}
+
+// Resource presents a single model.
+type Resource[T any] struct {
+	definition *Definition[T]
+	item       T
+	meta       Map
+}
+
+// WithMeta adds a top-level "meta" entry to the response.
+func (r *Resource[T]) WithMeta(key string, value any) *Resource[T] {
+	if r.meta == nil {
+		r.meta = Map{}
+	}
+	r.meta[key] = value
+	return r
+}
+
+// ToMap returns the response body: the model under "data", and "meta" if
+// any was added. It implements http.Transformer.
+func (r *Resource[T]) ToMap(ctx *http.Context) map[string]any {
+	body := Map{"data": r.resolve(ctx, requestedIncludes(ctx))}
+	if len(r.meta) > 0 {
+		body["meta"] = r.meta
+	}
+	return body
+}
+
+func (r *Resource[T]) resolve(ctx *http.Context, includes includeTree) any {
+	return r.definition.transform(ctx, r.item, includes)
+}
+
+// resolver is implemented by resources nested in another's fields or
+// includes, which are presented without their own "data" wrapper.
+type resolver interface {
+	resolve(ctx *http.Context, includes includeTree) any
+}
+
+// resolve presents a field value: nested resources and lazy values are
+// resolved, and maps are walked for them.
+func resolve(ctx *http.Context, value any, includes includeTree) any {
+	switch v := value.(type) {
+	case resolver:
+		return v.resolve(ctx, includes)
+	case func() any:
+		return resolve(ctx, v(), includes)
+	case Map:
+		return resolveMap(ctx, v, includes)
+	default:
+		return value
+	}
+}
+
+// resolveMap resolves each value with the includes requested under its key
+// and drops Missing ones.
+func resolveMap(ctx *http.Context, fields Map, includes includeTree) Map {
+	data := make(Map, len(fields))
+	for key, value := range fields {
+		if value = resolve(ctx, value, includes[key]); value != Missing {
+			data[key] = value
+		}
+	}
+	return data
+}
+
+// includeTree holds requested includes by name, with the includes of each
+// nested resource below it.
+type includeTree map[string]includeTree
+
+// requestedIncludes parses IncludeParam, such as "author,comments.author".
+func requestedIncludes(ctx *http.Context) includeTree {
+	tree := includeTree{}
+	if ctx == nil {
+		return tree
+	}
+	for _, path := range strings.Split(ctx.Query(IncludeParam), ",") {
+		node := tree
+		for _, name := range strings.Split(strings.TrimSpace(path), ".") {
+			if name == "" {
+				break
+			}
+			if node[name] == nil {
+				node[name] = includeTree{}
+			}
+			node = node[name]
+		}
+	}
+	return tree
+}
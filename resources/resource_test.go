@@ -0,0 +1,177 @@
+package resources
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	ID    int
+	Name  string
+	Email string
+}
+
+type comment struct {
+	ID     int
+	Body   string
+	Author *user
+}
+
+type post struct {
+	ID       int
+	Title    string
+	Author   *user
+	Comments []*comment
+}
+
+var userResource = &Definition[*user]{
+	Fields: func(ctx *http.Context, u *user) Map {
+		return Map{
+			"id":    u.ID,
+			"name":  u.Name,
+			"email": When(ctx.Query("admin") != "", u.Email),
+		}
+	},
+}
+
+var commentResource = &Definition[*comment]{
+	Fields: func(ctx *http.Context, c *comment) Map {
+		return Map{"id": c.ID, "body": c.Body}
+	},
+	Includes: map[string]Include[*comment]{
+		"author": func(ctx *http.Context, c *comment) any {
+			return WhenLoaded(c.Author, func() any { return userResource.Make(c.Author) })
+		},
+	},
+}
+
+var postResource = &Definition[*post]{
+	Fields: func(ctx *http.Context, p *post) Map {
+		return Map{
+			"id":    p.ID,
+			"title": p.Title,
+			"author": WhenLoaded(p.Author, func() any {
+				return userResource.Make(p.Author)
+			}),
+		}
+	},
+	Includes: map[string]Include[*post]{
+		"comments": func(ctx *http.Context, p *post) any {
+			return commentResource.Collection(p.Comments)
+		},
+	},
+}
+
+// respond sends a request to target and decodes the JSON response.
+func respond(t *testing.T, target string, handler func(ctx *http.Context) error) map[string]any {
+	t.Helper()
+
+	app := fiber.New()
+	app.Get("/posts", func(c *fiber.Ctx) error {
+		return handler(http.NewContext(c, testutil.NewMockApplication()))
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(body, &decoded), string(body))
+	return decoded
+}
+
+func jsonOf(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestResource(t *testing.T) {
+	p := &post{ID: 1, Title: "Hello", Author: &user{ID: 7, Name: "Ada", Email: "ada@example.com"}}
+
+	body := respond(t, "/posts", func(ctx *http.Context) error {
+		return ctx.JSONResponse(postResource.Make(p).WithMeta("version", "v1"))
+	})
+	assert.JSONEq(t, `{
+		"data": {"id": 1, "title": "Hello", "author": {"id": 7, "name": "Ada"}},
+		"meta": {"version": "v1"}
+	}`, jsonOf(t, body))
+
+	body = respond(t, "/posts?admin=1", func(ctx *http.Context) error {
+		return ctx.JSONResponse(postResource.Make(p))
+	})
+	assert.Equal(t, "ada@example.com", body["data"].(map[string]any)["author"].(map[string]any)["email"])
+
+	// Relations that were not loaded are left out.
+	body = respond(t, "/posts", func(ctx *http.Context) error {
+		return ctx.JSONResponse(postResource.Make(&post{ID: 2, Title: "Draft"}))
+	})
+	assert.JSONEq(t, `{"data": {"id": 2, "title": "Draft"}}`, jsonOf(t, body))
+}
+
+func TestResourceIncludes(t *testing.T) {
+	p := &post{ID: 1, Title: "Hello", Comments: []*comment{
+		{ID: 10, Body: "First", Author: &user{ID: 8, Name: "Grace"}},
+		{ID: 11, Body: "Anonymous"},
+	}}
+	handler := func(ctx *http.Context) error {
+		return ctx.JSONResponse(postResource.Make(p))
+	}
+
+	body := respond(t, "/posts", handler)
+	assert.NotContains(t, body["data"], "comments")
+
+	body = respond(t, "/posts?include=comments", handler)
+	assert.JSONEq(t, `[{"id": 10, "body": "First"}, {"id": 11, "body": "Anonymous"}]`, jsonOf(t, body["data"].(map[string]any)["comments"]))
+
+	body = respond(t, "/posts?include=comments.author,unknown", handler)
+	assert.JSONEq(t, `[
+		{"id": 10, "body": "First", "author": {"id": 8, "name": "Grace"}},
+		{"id": 11, "body": "Anonymous"}
+	]`, jsonOf(t, body["data"].(map[string]any)["comments"]))
+
+	withDefaults := *postResource
+	withDefaults.DefaultIncludes = []string{"comments"}
+	body = respond(t, "/posts", func(ctx *http.Context) error {
+		return ctx.JSONResponse(withDefaults.Make(p))
+	})
+	assert.Len(t, body["data"].(map[string]any)["comments"], 2)
+}
+
+func TestResourceCollectionPagination(t *testing.T) {
+	posts := []*post{{ID: 3, Title: "C"}, {ID: 4, Title: "D"}}
+
+	body := respond(t, "/posts?page=2&sort=title", func(ctx *http.Context) error {
+		return ctx.JSONResponse(postResource.Collection(posts).Paginate(2, 2, 5).WithMeta("sort", "title"))
+	})
+	assert.JSONEq(t, `{
+		"data": [{"id": 3, "title": "C"}, {"id": 4, "title": "D"}],
+		"meta": {"page": 2, "per_page": 2, "total": 5, "total_pages": 3, "has_more": true, "sort": "title"},
+		"links": {
+			"first": "/posts?page=1&sort=title",
+			"prev": "/posts?page=1&sort=title",
+			"next": "/posts?page=3&sort=title",
+			"last": "/posts?page=3&sort=title"
+		}
+	}`, jsonOf(t, body))
+
+	body = respond(t, "/posts", func(ctx *http.Context) error {
+		return ctx.JSONResponse(postResource.Collection(nil).Paginate(1, 15, 0))
+	})
+	assert.JSONEq(t, `{
+		"data": [],
+		"meta": {"page": 1, "per_page": 15, "total": 0, "total_pages": 0, "has_more": false},
+		"links": {"first": null, "prev": null, "next": null, "last": null}
+	}`, jsonOf(t, body))
+}
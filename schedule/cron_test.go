@@ -0,0 +1,72 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func at(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCronMatches(t *testing.T) {
+	tests := []struct {
+		expr  string
+		time  string
+		match bool
+	}{
+		{"* * * * *", "2024-05-06 10:17", true},
+		{"*/15 * * * *", "2024-05-06 10:30", true},
+		{"*/15 * * * *", "2024-05-06 10:31", false},
+		{"0 9 * * mon-fri", "2024-05-06 09:00", true}, // Monday
+		{"0 9 * * mon-fri", "2024-05-05 09:00", false}, // Sunday
+		{"0 0 * * 7", "2024-05-05 00:00", true},        // 7 is Sunday
+		{"30 8-10/2 * * *", "2024-05-06 10:30", true},
+		{"30 8-10/2 * * *", "2024-05-06 09:30", false},
+		{"0 0 1,15 * *", "2024-05-15 00:00", true},
+		{"0 0 1 jan *", "2024-01-01 00:00", true},
+		{"@hourly", "2024-05-06 10:00", true},
+		// With both day fields restricted, either one matches.
+		{"0 0 13 * 5", "2024-05-10 00:00", true}, // Friday the 10th
+		{"0 0 13 * 5", "2024-05-13 00:00", true}, // Monday the 13th
+		{"0 0 13 * 5", "2024-05-14 00:00", false},
+	}
+
+	for _, tt := range tests {
+		cron, err := ParseCron(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.match, cron.Matches(at(tt.time)), "%s at %s", tt.expr, tt.time)
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{"* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		_, err := ParseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	tests := []struct{ expr, from, next string }{
+		{"* * * * *", "2024-05-06 10:17", "2024-05-06 10:18"},
+		{"0 9 * * *", "2024-05-06 09:00", "2024-05-07 09:00"},
+		{"0 9 * * mon", "2024-05-06 10:00", "2024-05-13 09:00"},
+		{"0 0 29 2 *", "2024-03-01 00:00", "2028-02-29 00:00"},
+	}
+	for _, tt := range tests {
+		cron, err := ParseCron(tt.expr)
+		require.NoError(t, err)
+		assert.Equal(t, at(tt.next), cron.Next(at(tt.from)), tt.expr)
+	}
+
+	cron, err := ParseCron("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, cron.Next(at("2024-01-01 00:00")).IsZero())
+}
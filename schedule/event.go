@@ -0,0 +1,289 @@
+package schedule
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/genesysflow/go-genesys/heartbeat"
+)
+
+// Event is a scheduled task with the frequency it runs at. Weekdays and
+// Weekends only restrict the day of week, so Weekdays().DailyAt("09:00")
+// runs at 9am Monday to Friday.
+type Event struct {
+	fields      [5]string
+	location    *time.Location
+	description string
+	task        func(ctx context.Context) error
+	overlap     bool
+	overlapTTL  time.Duration
+	oneServer   bool
+	heartbeat   *heartbeat.Monitor
+	err         error
+}
+
+func newEvent(description string, task func(ctx context.Context) error) *Event {
+	return &Event{
+		fields:      [5]string{"*", "*", "*", "*", "*"},
+		description: description,
+		task:        task,
+	}
+}
+
+// Cron sets the event's cron expression, such as "*/5 * * * *".
+func (e *Event) Cron(expr string) *Event {
+	spec := strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		e.setErr(fmt.Errorf("schedule: cron expression [%s] must have 5 fields", expr))
+		return e
+	}
+	copy(e.fields[:], fields)
+	return e
+}
+
+// EveryMinute runs the event every minute.
+func (e *Event) EveryMinute() *Event {
+	return e.setFields("*", "*")
+}
+
+// EveryFiveMinutes runs the event every five minutes.
+func (e *Event) EveryFiveMinutes() *Event {
+	return e.setFields("*/5", "*")
+}
+
+// EveryTenMinutes runs the event every ten minutes.
+func (e *Event) EveryTenMinutes() *Event {
+	return e.setFields("*/10", "*")
+}
+
+// EveryFifteenMinutes runs the event every fifteen minutes.
+func (e *Event) EveryFifteenMinutes() *Event {
+	return e.setFields("*/15", "*")
+}
+
+// EveryThirtyMinutes runs the event every thirty minutes.
+func (e *Event) EveryThirtyMinutes() *Event {
+	return e.setFields("*/30", "*")
+}
+
+// Hourly runs the event at the start of every hour.
+func (e *Event) Hourly() *Event {
+	return e.setFields("0", "*")
+}
+
+// HourlyAt runs the event every hour at the given minute.
+func (e *Event) HourlyAt(minute int) *Event {
+	return e.setFields(fmt.Sprint(minute), "*")
+}
+
+// Daily runs the event every day at midnight.
+func (e *Event) Daily() *Event {
+	return e.setFields("0", "0")
+}
+
+// DailyAt runs the event every day at a time such as "09:00".
+func (e *Event) DailyAt(at string) *Event {
+	hour, minute, err := parseTime(at)
+	if err != nil {
+		e.setErr(err)
+		return e
+	}
+	return e.setFields(minute, hour)
+}
+
+// Weekly runs the event every Sunday at midnight.
+func (e *Event) Weekly() *Event {
+	e.setFields("0", "0")
+	e.fields[2], e.fields[4] = "*", "0"
+	return e
+}
+
+// WeeklyOn runs the event every week on the given day at a time such as
+// "09:00".
+func (e *Event) WeeklyOn(day time.Weekday, at string) *Event {
+	e.DailyAt(at)
+	e.fields[2], e.fields[4] = "*", fmt.Sprint(int(day))
+	return e
+}
+
+// Monthly runs the event on the first of every month at midnight.
+func (e *Event) Monthly() *Event {
+	e.setFields("0", "0")
+	e.fields[2] = "1"
+	return e
+}
+
+// MonthlyOn runs the event every month on the given day at a time such as
+// "09:00".
+func (e *Event) MonthlyOn(day int, at string) *Event {
+	e.DailyAt(at)
+	e.fields[2] = fmt.Sprint(day)
+	return e
+}
+
+// Yearly runs the event on January 1st at midnight.
+func (e *Event) Yearly() *Event {
+	e.setFields("0", "0")
+	e.fields[2], e.fields[3] = "1", "1"
+	return e
+}
+
+// Weekdays limits the event to Monday through Friday.
+func (e *Event) Weekdays() *Event {
+	e.fields[4] = "1-5"
+	return e
+}
+
+// Weekends limits the event to Saturday and Sunday.
+func (e *Event) Weekends() *Event {
+	e.fields[4] = "0,6"
+	return e
+}
+
+// Timezone evaluates the event's frequency in the named location, such as
+// "Europe/Berlin", instead of the schedule's.
+func (e *Event) Timezone(name string) *Event {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		e.setErr(fmt.Errorf("schedule: %w", err))
+		return e
+	}
+	e.location = location
+	return e
+}
+
+// Name sets the event's description. Closures need a name to use
+// WithoutOverlapping or OnOneServer across deploys that reorder them.
+func (e *Event) Name(description string) *Event {
+	e.description = description
+	return e
+}
+
+// WithoutOverlapping skips a run while the previous one is still going.
+// The lock expires after expiresAfter (24 hours by default) in case the
+// process running the event dies.
+func (e *Event) WithoutOverlapping(expiresAfter ...time.Duration) *Event {
+	e.overlap = true
+	e.overlapTTL = 24 * time.Hour
+	if len(expiresAfter) > 0 && expiresAfter[0] > 0 {
+		e.overlapTTL = expiresAfter[0]
+	}
+	return e
+}
+
+// OnOneServer runs the event on only one of the servers running the
+// schedule each time it is due. The servers must share the schedule's
+// cache store.
+func (e *Event) OnOneServer() *Event {
+	e.oneServer = true
+	return e
+}
+
+// Heartbeat reports each run to a monitoring service: the monitor's start
+// URL is pinged when the event starts, then its success or failure URL
+// with the outcome. Runs skipped by WithoutOverlapping or OnOneServer are
+// not reported, and ping errors never fail the event.
+func (e *Event) Heartbeat(monitor *heartbeat.Monitor) *Event {
+	e.heartbeat = monitor
+	return e
+}
+
+// Expression returns the event's cron expression.
+func (e *Event) Expression() string {
+	return strings.Join(e.fields[:], " ")
+}
+
+// Description returns the event's name, or the command it runs.
+func (e *Event) Description() string {
+	return e.description
+}
+
+// Err returns the first error from configuring the event, such as an
+// invalid time.
+func (e *Event) Err() error {
+	return e.err
+}
+
+// IsDue reports whether the event runs in now's minute.
+func (e *Event) IsDue(now time.Time) bool {
+	cron, err := e.cron()
+	if err != nil {
+		return false
+	}
+	return cron.Matches(e.localTime(now))
+}
+
+// NextRun returns when the event next runs after now.
+func (e *Event) NextRun(now time.Time) (time.Time, error) {
+	cron, err := e.cron()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cron.Next(e.localTime(now)), nil
+}
+
+// execute runs the event's task, reporting it to its heartbeat monitor.
+func (e *Event) execute(ctx context.Context) error {
+	if e.heartbeat == nil {
+		return e.task(ctx)
+	}
+
+	_ = e.heartbeat.Start(ctx)
+	err := e.task(ctx)
+	if err != nil {
+		_ = e.heartbeat.Failure(ctx, err)
+	} else {
+		_ = e.heartbeat.Success(ctx)
+	}
+	return err
+}
+
+// mutexName identifies the event in lock keys.
+func (e *Event) mutexName() string {
+	sum := sha1.Sum([]byte(e.Expression() + "|" + e.description))
+	return hex.EncodeToString(sum[:])
+}
+
+func (e *Event) cron() (*Cron, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return ParseCron(e.Expression())
+}
+
+func (e *Event) localTime(now time.Time) time.Time {
+	if e.location != nil {
+		return now.In(e.location)
+	}
+	return now
+}
+
+// setFields sets the minute and hour fields and resets the day fields.
+func (e *Event) setFields(minute, hour string) *Event {
+	e.fields[0], e.fields[1] = minute, hour
+	e.fields[2], e.fields[3] = "*", "*"
+	return e
+}
+
+func (e *Event) setErr(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+// parseTime splits a time such as "09:00" into its hour and minute fields.
+func parseTime(at string) (string, string, error) {
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return "", "", fmt.Errorf("schedule: invalid time [%s], expected HH:MM", at)
+	}
+	return fmt.Sprint(t.Hour()), fmt.Sprint(t.Minute()), nil
+}
@@ -0,0 +1,282 @@
+// Package schedule runs closures, console commands and queue jobs on cron
+// frequencies. The console kernel holds the application's schedule;
+// `schedule:run`, run every minute by cron, runs the events that are due,
+// and `schedule:work` does the same in a long-running process.
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/queue"
+)
+
+// Schedule holds the scheduled events.
+type Schedule struct {
+	events     []*Event
+	location   *time.Location
+	cache      *cache.Repository
+	queue      *queue.Manager
+	logger     contracts.Logger
+	executable string
+	output     io.Writer
+	mu         sync.RWMutex
+}
+
+// New creates an empty schedule. Until SetCache is called, overlap and
+// one-server locks are kept in memory, so they only hold within a process.
+func New() *Schedule {
+	return &Schedule{
+		location: time.Local,
+		cache:    cache.NewRepository(cache.NewMemoryStore()),
+		output:   os.Stdout,
+	}
+}
+
+// Call schedules a closure.
+func (s *Schedule) Call(task func(ctx context.Context) error) *Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	event := newEvent(fmt.Sprintf("closure #%d", len(s.events)+1), task)
+	s.events = append(s.events, event)
+	return event
+}
+
+// Command schedules a console command, such as Command("emails:send",
+// "--force"). It runs in a new process of the application's executable, so
+// it gets fresh flags and its own exit status.
+func (s *Schedule) Command(command string, args ...string) *Event {
+	argv := append(strings.Fields(command), args...)
+	event := newEvent(strings.Join(argv, " "), func(ctx context.Context) error {
+		return s.runCommand(ctx, argv)
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return event
+}
+
+// Job schedules a job to be pushed onto a queue connection, or onto the
+// default connection if none is named. Without a queue manager the job is
+// handled in the schedule's process.
+func (s *Schedule) Job(job queue.Job, connection ...string) *Event {
+	event := newEvent(fmt.Sprintf("%T", job), func(ctx context.Context) error {
+		manager := s.queueManager()
+		if manager == nil {
+			return job.Handle()
+		}
+		conn, err := manager.Connection(connection...)
+		if err != nil {
+			return err
+		}
+		return conn.Push(job)
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return event
+}
+
+// Events returns the scheduled events.
+func (s *Schedule) Events() []*Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]*Event(nil), s.events...)
+}
+
+// SetTimezone sets the location event frequencies are evaluated in, for
+// events without their own Timezone. Defaults to the local time zone.
+func (s *Schedule) SetTimezone(location *time.Location) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.location = location
+}
+
+// SetCache sets the cache whose locks WithoutOverlapping and OnOneServer
+// use. Servers sharing work must share the cache's store.
+func (s *Schedule) SetCache(repository *cache.Repository) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = repository
+}
+
+// SetQueue sets the queue manager jobs are pushed to.
+func (s *Schedule) SetQueue(manager *queue.Manager) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = manager
+}
+
+// SetLogger sets the logger that receives skipped and failed runs.
+func (s *Schedule) SetLogger(logger contracts.Logger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger = logger
+}
+
+// SetExecutable sets the program commands run with. Defaults to the
+// current executable.
+func (s *Schedule) SetExecutable(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.executable = path
+}
+
+// SetOutput sets where command output is written. Defaults to os.Stdout.
+func (s *Schedule) SetOutput(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.output = w
+}
+
+// Validate returns the configuration errors of all events, such as invalid
+// times or cron expressions.
+func (s *Schedule) Validate() error {
+	var errs []error
+	for _, event := range s.Events() {
+		if _, err := event.cron(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", event.Description(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DueEvents returns the events that run in now's minute.
+func (s *Schedule) DueEvents(now time.Time) []*Event {
+	now = s.localTime(now)
+	var due []*Event
+	for _, event := range s.Events() {
+		if event.IsDue(now) {
+			due = append(due, event)
+		}
+	}
+	return due
+}
+
+// NextRun returns when an event next runs after now, in the schedule's
+// time zone unless the event has its own.
+func (s *Schedule) NextRun(event *Event, now time.Time) (time.Time, error) {
+	return event.NextRun(s.localTime(now))
+}
+
+// RunDue runs the events due in now's minute, one after another, and
+// returns their errors joined. Events whose overlap or one-server lock is
+// held elsewhere are skipped.
+func (s *Schedule) RunDue(ctx context.Context, now time.Time) error {
+	var errs []error
+	for _, event := range s.DueEvents(now) {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := s.run(ctx, event, now); err != nil {
+			s.log(func(l contracts.Logger) {
+				l.Error("Scheduled task failed", "task", event.Description(), "error", err.Error())
+			})
+			errs = append(errs, fmt.Errorf("%s: %w", event.Description(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run runs an event under its locks.
+func (s *Schedule) run(ctx context.Context, event *Event, now time.Time) error {
+	repository := s.cacheRepository()
+
+	if event.oneServer {
+		// The first server to claim this run keeps the lock until it
+		// expires, so the others skip the event for this minute.
+		key := "schedule:" + event.mutexName() + ":" + now.UTC().Format("200601021504")
+		lock, err := repository.Lock(key, time.Hour)
+		if err != nil {
+			return err
+		}
+		acquired, err := lock.Acquire()
+		if err != nil {
+			return err
+		}
+		if !acquired {
+			s.skipped(event, "running on another server")
+			return nil
+		}
+	}
+
+	if !event.overlap {
+		return event.execute(ctx)
+	}
+
+	lock, err := repository.Lock("schedule:"+event.mutexName(), event.overlapTTL)
+	if err != nil {
+		return err
+	}
+	ran, err := lock.Get(func() error {
+		return event.execute(ctx)
+	})
+	if err == nil && !ran {
+		s.skipped(event, "previous run still in progress")
+	}
+	return err
+}
+
+// runCommand runs a console command in a new process.
+func (s *Schedule) runCommand(ctx context.Context, argv []string) error {
+	s.mu.RLock()
+	executable, output := s.executable, s.output
+	s.mu.RUnlock()
+
+	if executable == "" {
+		path, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		executable = path
+	}
+
+	cmd := exec.CommandContext(ctx, executable, argv...)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	return cmd.Run()
+}
+
+func (s *Schedule) skipped(event *Event, reason string) {
+	s.log(func(l contracts.Logger) {
+		l.Info("Scheduled task skipped", "task", event.Description(), "reason", reason)
+	})
+}
+
+func (s *Schedule) log(fn func(contracts.Logger)) {
+	s.mu.RLock()
+	logger := s.logger
+	s.mu.RUnlock()
+	if logger != nil {
+		fn(logger)
+	}
+}
+
+func (s *Schedule) localTime(now time.Time) time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return now.In(s.location)
+}
+
+func (s *Schedule) cacheRepository() *cache.Repository {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cache
+}
+
+func (s *Schedule) queueManager() *queue.Manager {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.queue
+}
@@ -0,0 +1,207 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/heartbeat"
+	"github.com/genesysflow/go-genesys/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSchedule creates a schedule evaluated in UTC, like the test times.
+func newTestSchedule() *Schedule {
+	s := New()
+	s.SetTimezone(time.UTC)
+	return s
+}
+
+func TestEventFrequencies(t *testing.T) {
+	s := newTestSchedule()
+	tests := []struct {
+		event *Event
+		want  string
+	}{
+		{s.Call(nil).EveryMinute(), "* * * * *"},
+		{s.Call(nil).EveryFiveMinutes(), "*/5 * * * *"},
+		{s.Call(nil).HourlyAt(17), "17 * * * *"},
+		{s.Call(nil).DailyAt("09:30"), "30 9 * * *"},
+		{s.Call(nil).Weekdays().DailyAt("09:00"), "0 9 * * 1-5"},
+		{s.Call(nil).WeeklyOn(time.Friday, "17:00"), "0 17 * * 5"},
+		{s.Call(nil).MonthlyOn(15, "08:00"), "0 8 15 * *"},
+		{s.Call(nil).Yearly(), "0 0 1 1 *"},
+		{s.Call(nil).Cron("@daily"), "0 0 * * *"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.event.Expression())
+	}
+
+	assert.NoError(t, s.Validate())
+	s.Call(nil).DailyAt("25:00")
+	s.Call(nil).Timezone("Mars/Olympus")
+	assert.Error(t, s.Validate())
+}
+
+func TestDueEventsUseTimezones(t *testing.T) {
+	s := newTestSchedule()
+	utc := s.Call(nil).DailyAt("09:00").Name("utc")
+	berlin := s.Call(nil).DailyAt("09:00").Timezone("Europe/Berlin").Name("berlin")
+
+	assert.Equal(t, []*Event{utc}, s.DueEvents(at("2024-05-06 09:00")))
+	assert.Equal(t, []*Event{berlin}, s.DueEvents(at("2024-05-06 07:00")))
+
+	next, err := s.NextRun(berlin, at("2024-05-06 08:00"))
+	require.NoError(t, err)
+	assert.True(t, next.Equal(at("2024-05-07 07:00")))
+}
+
+func TestRunDue(t *testing.T) {
+	s := newTestSchedule()
+	var ran []string
+	s.Call(func(ctx context.Context) error {
+		ran = append(ran, "every minute")
+		return nil
+	}).EveryMinute()
+	s.Call(func(ctx context.Context) error {
+		ran = append(ran, "hourly")
+		return errors.New("boom")
+	}).Hourly().Name("report")
+	s.Call(func(ctx context.Context) error {
+		ran = append(ran, "daily")
+		return nil
+	}).Daily()
+
+	err := s.RunDue(context.Background(), at("2024-05-06 10:00"))
+	assert.EqualError(t, err, "report: boom")
+	assert.Equal(t, []string{"every minute", "hourly"}, ran)
+}
+
+func TestHeartbeat(t *testing.T) {
+	var mu sync.Mutex
+	var pings []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		pings = append(pings, r.URL.Path)
+	}))
+	t.Cleanup(srv.Close)
+	monitor := func(name string) *heartbeat.Monitor {
+		return &heartbeat.Monitor{
+			StartURL:   srv.URL + "/" + name + "/start",
+			SuccessURL: srv.URL + "/" + name + "/ok",
+			FailureURL: srv.URL + "/" + name + "/fail",
+		}
+	}
+
+	s := newTestSchedule()
+	s.Call(func(ctx context.Context) error { return nil }).EveryMinute().Heartbeat(monitor("prune"))
+	s.Call(func(ctx context.Context) error { return errors.New("disk full") }).EveryMinute().Name("backup").Heartbeat(monitor("backup"))
+	s.Call(func(ctx context.Context) error { return nil }).Daily().Heartbeat(monitor("report"))
+
+	err := s.RunDue(context.Background(), at("2024-05-06 10:00"))
+	assert.EqualError(t, err, "backup: disk full")
+	assert.Equal(t, []string{"/prune/start", "/prune/ok", "/backup/start", "/backup/fail"}, pings)
+}
+
+func TestWithoutOverlapping(t *testing.T) {
+	s := newTestSchedule()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	runs := 0
+	s.Call(func(ctx context.Context) error {
+		runs++
+		close(started)
+		<-release
+		return nil
+	}).EveryMinute().WithoutOverlapping()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, s.RunDue(context.Background(), at("2024-05-06 10:00")))
+	}()
+	<-started
+
+	// The next minute's run is skipped while the first is still going.
+	assert.NoError(t, s.RunDue(context.Background(), at("2024-05-06 10:01")))
+	close(release)
+	wg.Wait()
+	assert.Equal(t, 1, runs)
+}
+
+func TestOnOneServer(t *testing.T) {
+	shared := cache.NewRepository(cache.NewMemoryStore())
+	runs := 0
+	servers := make([]*Schedule, 2)
+	for i := range servers {
+		servers[i] = newTestSchedule()
+		servers[i].SetCache(shared)
+		servers[i].Call(func(ctx context.Context) error {
+			runs++
+			return nil
+		}).EveryMinute().Name("prune").OnOneServer()
+	}
+
+	for _, s := range servers {
+		require.NoError(t, s.RunDue(context.Background(), at("2024-05-06 10:00")))
+	}
+	assert.Equal(t, 1, runs)
+
+	// The next minute is claimed afresh.
+	require.NoError(t, servers[1].RunDue(context.Background(), at("2024-05-06 10:01")))
+	assert.Equal(t, 2, runs)
+}
+
+type countingJob struct{ count *int }
+
+func (j *countingJob) Handle() error {
+	*j.count++
+	return nil
+}
+
+func TestJobEvents(t *testing.T) {
+	count := 0
+	s := newTestSchedule()
+	s.Job(&countingJob{count: &count}).EveryMinute()
+
+	// Without a queue manager the job is handled directly.
+	require.NoError(t, s.RunDue(context.Background(), at("2024-05-06 10:00")))
+	assert.Equal(t, 1, count)
+
+	manager := queue.NewManager()
+	memory := queue.NewMemoryQueue()
+	manager.Register("memory", memory)
+	manager.SetDefaultConnection("memory")
+	s.SetQueue(manager)
+
+	require.NoError(t, s.RunDue(context.Background(), at("2024-05-06 10:01")))
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 1, memory.Size())
+}
+
+func TestCommandEvents(t *testing.T) {
+	echo, err := exec.LookPath("echo")
+	if err != nil {
+		t.Skip("echo not available")
+	}
+
+	var out bytes.Buffer
+	s := newTestSchedule()
+	s.SetExecutable(echo)
+	s.SetOutput(&out)
+	event := s.Command("emails:send", "--force").EveryMinute()
+
+	assert.Equal(t, "emails:send --force", event.Description())
+	require.NoError(t, s.RunDue(context.Background(), at("2024-05-06 10:00")))
+	assert.Equal(t, "emails:send --force\n", out.String())
+}
@@ -0,0 +1,108 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/genesysflow/go-genesys/crypt"
+)
+
+// maxCookieSize is the largest cookie value browsers reliably accept.
+const maxCookieSize = 4000
+
+// ErrCookieTooLarge is returned when a session no longer fits in its cookie.
+var ErrCookieTooLarge = errors.New("session: session data is too large for the cookie driver")
+
+// CookieStore is implemented by drivers that keep the whole session in the
+// cookie rather than behind a session ID. The manager writes the encoded
+// session to the cookie after saving it.
+type CookieStore interface {
+	Encode(id string, data map[string]any, lifetime time.Duration) (string, error)
+	Decode(value string) (id string, data map[string]any, err error)
+}
+
+type cookieSession struct {
+	ID        string          `json:"id"`
+	ExpiresAt int64           `json:"expires_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// CookieDriver stores sessions encrypted in the session cookie itself, so
+// no server-side storage is needed. Sessions must stay under about 4KB.
+type CookieDriver struct {
+	encrypter *crypt.Encrypter
+}
+
+// NewCookieDriver creates a cookie session driver that encrypts sessions
+// with encrypter.
+func NewCookieDriver(encrypter *crypt.Encrypter) *CookieDriver {
+	return &CookieDriver{encrypter: encrypter}
+}
+
+// Encode encrypts a session into a cookie value.
+func (d *CookieDriver) Encode(id string, data map[string]any, lifetime time.Duration) (string, error) {
+	payload, err := encodePayload(data)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(cookieSession{
+		ID:        id,
+		ExpiresAt: time.Now().Add(lifetime).Unix(),
+		Data:      payload,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	value, err := d.encrypter.Encrypt(b)
+	if err != nil {
+		return "", err
+	}
+	if len(value) > maxCookieSize {
+		return "", ErrCookieTooLarge
+	}
+	return value, nil
+}
+
+// Decode decrypts a cookie value. Expired or tampered cookies are errors.
+func (d *CookieDriver) Decode(value string) (string, map[string]any, error) {
+	b, err := d.encrypter.Decrypt(value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var sess cookieSession
+	if err := json.Unmarshal(b, &sess); err != nil {
+		return "", nil, err
+	}
+	if time.Now().Unix() >= sess.ExpiresAt {
+		return "", nil, errors.New("session: cookie has expired")
+	}
+
+	data, err := decodePayload(sess.Data)
+	if err != nil {
+		return "", nil, err
+	}
+	return sess.ID, data, nil
+}
+
+// Read returns nil: sessions are decoded from the cookie instead.
+func (d *CookieDriver) Read(id string) (map[string]any, error) {
+	return nil, nil
+}
+
+// Write is a no-op: sessions are encoded into the cookie instead.
+func (d *CookieDriver) Write(id string, data map[string]any, lifetime time.Duration) error {
+	return nil
+}
+
+// Destroy is a no-op: the cookie is replaced when the response is sent.
+func (d *CookieDriver) Destroy(id string) error {
+	return nil
+}
+
+// GC is a no-op: expired cookies are rejected by Decode.
+func (d *CookieDriver) GC(lifetime time.Duration) error {
+	return nil
+}
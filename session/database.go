@@ -0,0 +1,80 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+)
+
+// DatabaseDriver stores sessions in a table with id, payload and
+// expires_at columns. Generate its migration with `session:table`. Writes
+// rely on ON CONFLICT, so the driver supports PostgreSQL and SQLite but not
+// MySQL.
+type DatabaseDriver struct {
+	conn    contracts.Connection
+	dialect database.Dialect
+	table   string
+}
+
+// NewDatabaseDriver creates a database session driver. The connection's
+// table prefix is applied to table.
+func NewDatabaseDriver(conn contracts.Connection, table string) *DatabaseDriver {
+	if table == "" {
+		table = "sessions"
+	}
+	return &DatabaseDriver{
+		conn:    conn,
+		dialect: database.NewDialect(conn.Driver()),
+		table:   conn.Prefix() + table,
+	}
+}
+
+// Read returns the session's data, or nil if it is missing or expired.
+func (d *DatabaseDriver) Read(id string) (map[string]any, error) {
+	query := fmt.Sprintf(`SELECT payload FROM %s WHERE id = %s AND expires_at > %s`,
+		d.dialect.Quote(d.table), d.dialect.Placeholder(1), d.dialect.Placeholder(2))
+
+	var payload string
+	err := d.conn.QueryRowContext(context.Background(), query, id, time.Now().Unix()).Scan(&payload)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodePayload([]byte(payload))
+}
+
+// Write inserts or replaces the session's row.
+func (d *DatabaseDriver) Write(id string, data map[string]any, lifetime time.Duration) error {
+	payload, err := encodePayload(data)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, payload, expires_at) VALUES (%s, %s, %s)
+ON CONFLICT (id) DO UPDATE SET payload = excluded.payload, expires_at = excluded.expires_at`,
+		d.dialect.Quote(d.table), d.dialect.Placeholder(1), d.dialect.Placeholder(2), d.dialect.Placeholder(3))
+
+	_, err = d.conn.ExecContext(context.Background(), query, id, string(payload), time.Now().Add(lifetime).Unix())
+	return err
+}
+
+// Destroy removes a session.
+func (d *DatabaseDriver) Destroy(id string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id = %s`, d.dialect.Quote(d.table), d.dialect.Placeholder(1))
+	_, err := d.conn.ExecContext(context.Background(), query, id)
+	return err
+}
+
+// GC removes expired sessions.
+func (d *DatabaseDriver) GC(lifetime time.Duration) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE expires_at <= %s`, d.dialect.Quote(d.table), d.dialect.Placeholder(1))
+	_, err := d.conn.ExecContext(context.Background(), query, time.Now().Unix())
+	return err
+}
@@ -0,0 +1,120 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type fileSession struct {
+	ExpiresAt int64           `json:"expires_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// FileDriver stores each session as a file in a directory.
+type FileDriver struct {
+	dir string
+}
+
+// NewFileDriver creates a file session driver, creating dir if needed.
+func NewFileDriver(dir string) (*FileDriver, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileDriver{dir: dir}, nil
+}
+
+// Read returns the session's data, or nil if it is missing or expired.
+func (d *FileDriver) Read(id string) (map[string]any, error) {
+	if !validID(id) {
+		return nil, nil
+	}
+
+	b, err := os.ReadFile(filepath.Join(d.dir, id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sess fileSession
+	if err := json.Unmarshal(b, &sess); err != nil || time.Now().Unix() >= sess.ExpiresAt {
+		return nil, nil
+	}
+	return decodePayload(sess.Data)
+}
+
+// Write stores the session's data. The file is replaced atomically so
+// concurrent requests never read a partial session.
+func (d *FileDriver) Write(id string, data map[string]any, lifetime time.Duration) error {
+	if !validID(id) {
+		return nil
+	}
+
+	payload, err := encodePayload(data)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(fileSession{
+		ExpiresAt: time.Now().Add(lifetime).Unix(),
+		Data:      payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(d.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(d.dir, id))
+}
+
+// Destroy removes a session.
+func (d *FileDriver) Destroy(id string) error {
+	if !validID(id) {
+		return nil
+	}
+
+	err := os.Remove(filepath.Join(d.dir, id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// GC removes session files not written to within lifetime.
+func (d *FileDriver) GC(lifetime time.Duration) error {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-lifetime)
+	for _, entry := range entries {
+		if !validID(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().Before(cutoff) {
+			os.Remove(filepath.Join(d.dir, entry.Name()))
+		}
+	}
+	return nil
+}
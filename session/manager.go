@@ -0,0 +1,275 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/gofiber/fiber/v2"
+)
+
+// contextKey is the request local the middleware stores the session under.
+const contextKey = "session"
+
+// Config holds session configuration.
+type Config struct {
+	// Expiration is the session lifetime. Idle sessions expire after it.
+	Expiration time.Duration
+
+	// CookieName is the name of the session cookie.
+	CookieName string
+
+	// CookiePath is the path of the session cookie.
+	CookiePath string
+
+	// CookieDomain is the domain of the session cookie.
+	CookieDomain string
+
+	// CookieSecure indicates if the cookie should only be sent over HTTPS.
+	CookieSecure bool
+
+	// CookieHTTPOnly indicates if the cookie should be HTTP only.
+	CookieHTTPOnly bool
+
+	// CookieSameSite controls the SameSite attribute.
+	CookieSameSite string
+
+	// Storage is the name of the driver sessions are stored with.
+	Storage string
+
+	// Lottery is the chance, as [hits, out of], that a request removes
+	// expired sessions from storage.
+	Lottery [2]int
+}
+
+// DefaultConfig returns the default session configuration.
+func DefaultConfig() Config {
+	return Config{
+		Expiration:     24 * time.Hour,
+		CookieName:     "genesys_session",
+		CookiePath:     "/",
+		CookieSecure:   false,
+		CookieHTTPOnly: true,
+		CookieSameSite: "Lax",
+		Storage:        "memory",
+		Lottery:        [2]int{2, 100},
+	}
+}
+
+// Manager starts sessions and stores them with the configured driver.
+type Manager struct {
+	config   Config
+	drivers  map[string]contracts.SessionDriver
+	creators map[string]func() (contracts.SessionDriver, error)
+	mu       sync.RWMutex
+}
+
+// NewManager creates a new session manager. The "memory" driver is always
+// available; others are added with Extend or ExtendFunc.
+func NewManager(config ...Config) *Manager {
+	cfg := DefaultConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+		defaults := DefaultConfig()
+		if cfg.Expiration <= 0 {
+			cfg.Expiration = defaults.Expiration
+		}
+		if cfg.CookieName == "" {
+			cfg.CookieName = defaults.CookieName
+		}
+		if cfg.CookiePath == "" {
+			cfg.CookiePath = defaults.CookiePath
+		}
+		if cfg.Storage == "" {
+			cfg.Storage = defaults.Storage
+		}
+		if cfg.Lottery == [2]int{} {
+			cfg.Lottery = defaults.Lottery
+		}
+	}
+
+	return &Manager{
+		config: cfg,
+		drivers: map[string]contracts.SessionDriver{
+			"memory": NewMemoryDriver(),
+		},
+		creators: make(map[string]func() (contracts.SessionDriver, error)),
+	}
+}
+
+// Config returns the manager's configuration.
+func (m *Manager) Config() Config {
+	return m.config
+}
+
+// Extend registers a session driver.
+func (m *Manager) Extend(name string, driver contracts.SessionDriver) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.drivers[name] = driver
+	delete(m.creators, name)
+}
+
+// ExtendFunc registers a driver that is created on first use, for drivers
+// that depend on services booted after the session provider.
+func (m *Manager) ExtendFunc(name string, creator func() (contracts.SessionDriver, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.creators[name] = creator
+	delete(m.drivers, name)
+}
+
+// Driver returns a session driver, or nil if it is unknown or fails to start.
+func (m *Manager) Driver(name string) contracts.SessionDriver {
+	driver, _ := m.resolve(name)
+	return driver
+}
+
+func (m *Manager) resolve(name string) (contracts.SessionDriver, error) {
+	m.mu.RLock()
+	driver, ok := m.drivers[name]
+	m.mu.RUnlock()
+	if ok {
+		return driver, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if driver, ok := m.drivers[name]; ok {
+		return driver, nil
+	}
+	creator, ok := m.creators[name]
+	if !ok {
+		return nil, fmt.Errorf("session: driver %q is not registered", name)
+	}
+	driver, err := creator()
+	if err != nil {
+		return nil, fmt.Errorf("session: driver %q: %w", name, err)
+	}
+	m.drivers[name] = driver
+	delete(m.creators, name)
+	return driver, nil
+}
+
+// Start resumes the session with the given ID, or starts a new one when the
+// ID is empty, malformed or expired.
+func (m *Manager) Start(id string) (contracts.Session, error) {
+	return m.start(id)
+}
+
+func (m *Manager) start(id string) (*Session, error) {
+	driver, err := m.resolve(m.config.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if !validID(id) {
+		return newSession(newID(), driver, m.config.Expiration, nil), nil
+	}
+
+	data, err := driver.Read(id)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		// Don't adopt an ID the client picked.
+		id = newID()
+	}
+	return newSession(id, driver, m.config.Expiration, data), nil
+}
+
+// GC removes expired sessions from the configured driver.
+func (m *Manager) GC() error {
+	driver, err := m.resolve(m.config.Storage)
+	if err != nil {
+		return err
+	}
+	return driver.GC(m.config.Expiration)
+}
+
+// Get starts the session for a request from its cookie.
+func (m *Manager) Get(c *fiber.Ctx) (*Session, error) {
+	value := c.Cookies(m.config.CookieName)
+
+	driver, err := m.resolve(m.config.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if store, ok := driver.(CookieStore); ok {
+		id, data, err := store.Decode(value)
+		if err != nil || !validID(id) {
+			return newSession(newID(), driver, m.config.Expiration, nil), nil
+		}
+		return newSession(id, driver, m.config.Expiration, data), nil
+	}
+	return m.start(value)
+}
+
+// Middleware returns Fiber middleware that starts the session before the
+// handler and saves it afterwards.
+func (m *Manager) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return m.Handle(c, c.Next)
+	}
+}
+
+// Handle starts the session, calls next, then saves the session and
+// refreshes its cookie. The session is saved even when next fails, so flash
+// data set before an error survives the redirect.
+func (m *Manager) Handle(c *fiber.Ctx, next func() error) error {
+	sess, err := m.Get(c)
+	if err != nil {
+		return err
+	}
+	c.Locals(contextKey, sess)
+
+	err = next()
+	if saveErr := m.save(c, sess); saveErr != nil {
+		err = errors.Join(err, saveErr)
+	}
+
+	if lottery := m.config.Lottery; lottery[1] > 0 && rand.IntN(lottery[1]) < lottery[0] {
+		go m.GC()
+	}
+	return err
+}
+
+func (m *Manager) save(c *fiber.Ctx, sess *Session) error {
+	if err := sess.Save(); err != nil {
+		return err
+	}
+
+	value := sess.ID()
+	if store, ok := sess.driver.(CookieStore); ok {
+		sess.mu.RLock()
+		encoded, err := store.Encode(sess.id, sess.data, m.config.Expiration)
+		sess.mu.RUnlock()
+		if err != nil {
+			return err
+		}
+		value = encoded
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     m.config.CookieName,
+		Value:    value,
+		Path:     m.config.CookiePath,
+		Domain:   m.config.CookieDomain,
+		Expires:  time.Now().Add(m.config.Expiration),
+		Secure:   m.config.CookieSecure,
+		HTTPOnly: m.config.CookieHTTPOnly,
+		SameSite: m.config.CookieSameSite,
+	})
+	return nil
+}
+
+// GetFromContext retrieves the session the middleware started, or nil.
+func GetFromContext(c *fiber.Ctx) *Session {
+	sess, _ := c.Locals(contextKey).(*Session)
+	return sess
+}
@@ -0,0 +1,78 @@
+package session
+
+import (
+	"sync"
+	"time"
+)
+
+type memorySession struct {
+	payload   []byte
+	expiresAt time.Time
+}
+
+// MemoryDriver keeps sessions in process. Sessions are lost on restart and
+// are not shared between instances, so it suits development and tests.
+type MemoryDriver struct {
+	sessions map[string]memorySession
+	mu       sync.RWMutex
+}
+
+// NewMemoryDriver creates an in-memory session driver.
+func NewMemoryDriver() *MemoryDriver {
+	return &MemoryDriver{
+		sessions: make(map[string]memorySession),
+	}
+}
+
+// Read returns the session's data, or nil if it is missing or expired.
+func (d *MemoryDriver) Read(id string) (map[string]any, error) {
+	d.mu.RLock()
+	sess, ok := d.sessions[id]
+	d.mu.RUnlock()
+
+	if !ok || time.Now().After(sess.expiresAt) {
+		return nil, nil
+	}
+	return decodePayload(sess.payload)
+}
+
+// Write stores the session's data. Data is encoded as for other drivers,
+// so values read back have the same types whichever driver is used.
+func (d *MemoryDriver) Write(id string, data map[string]any, lifetime time.Duration) error {
+	payload, err := encodePayload(data)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.sessions[id] = memorySession{
+		payload:   payload,
+		expiresAt: time.Now().Add(lifetime),
+	}
+	return nil
+}
+
+// Destroy removes a session.
+func (d *MemoryDriver) Destroy(id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.sessions, id)
+	return nil
+}
+
+// GC removes expired sessions.
+func (d *MemoryDriver) GC(lifetime time.Duration) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	for id, sess := range d.sessions {
+		if now.After(sess.expiresAt) {
+			delete(d.sessions, id)
+		}
+	}
+	return nil
+}
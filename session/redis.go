@@ -0,0 +1,53 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/genesysflow/go-genesys/cache"
+)
+
+// RedisDriver stores sessions in Redis, which expires them itself.
+type RedisDriver struct {
+	client cache.RedisCommander
+	prefix string
+}
+
+// NewRedisDriver creates a Redis session driver. Keys are prefixed with prefix.
+func NewRedisDriver(client cache.RedisCommander, prefix string) *RedisDriver {
+	return &RedisDriver{client: client, prefix: prefix}
+}
+
+// Read returns the session's data, or nil if it is missing or expired.
+func (d *RedisDriver) Read(id string) (map[string]any, error) {
+	reply, err := d.client.Do(context.Background(), "GET", d.prefix+id)
+	if err != nil {
+		return nil, err
+	}
+	payload, ok := reply.(string)
+	if !ok {
+		return nil, nil
+	}
+	return decodePayload([]byte(payload))
+}
+
+// Write stores the session's data with lifetime as its TTL.
+func (d *RedisDriver) Write(id string, data map[string]any, lifetime time.Duration) error {
+	payload, err := encodePayload(data)
+	if err != nil {
+		return err
+	}
+	_, err = d.client.Do(context.Background(), "SET", d.prefix+id, payload, "PX", lifetime.Milliseconds())
+	return err
+}
+
+// Destroy removes a session.
+func (d *RedisDriver) Destroy(id string) error {
+	_, err := d.client.Do(context.Background(), "DEL", d.prefix+id)
+	return err
+}
+
+// GC is a no-op: Redis removes expired sessions.
+func (d *RedisDriver) GC(lifetime time.Duration) error {
+	return nil
+}
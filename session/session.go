@@ -1,226 +1,108 @@
-// Package session provides session management using Fiber's session middleware.
+// Package session provides HTTP sessions backed by pluggable storage drivers.
 package session
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/session"
+	"github.com/genesysflow/go-genesys/contracts"
 )
 
-// Session wraps Fiber's session with Laravel-like API.
-type Session struct {
-	store     *session.Session
-	sess      *session.Store
-	id        string
-	data      map[string]any
-	flash     map[string]any
-	mu        sync.RWMutex
-	createdAt time.Time
-	dirty     bool
-}
-
-// Manager manages sessions.
-type Manager struct {
-	store   *session.Store
-	config  Config
-	drivers map[string]Driver
-}
-
-// Config holds session configuration.
-type Config struct {
-	// Expiration is the session expiration time.
-	Expiration time.Duration
-
-	// CookieName is the name of the session cookie.
-	CookieName string
-
-	// CookiePath is the path of the session cookie.
-	CookiePath string
-
-	// CookieDomain is the domain of the session cookie.
-	CookieDomain string
-
-	// CookieSecure indicates if the cookie should only be sent over HTTPS.
-	CookieSecure bool
-
-	// CookieHTTPOnly indicates if the cookie should be HTTP only.
-	CookieHTTPOnly bool
-
-	// CookieSameSite controls the SameSite attribute.
-	CookieSameSite string
-
-	// KeyLookup is the key lookup format (e.g., "cookie:session_id").
-	KeyLookup string
-
-	// Storage is the storage driver name.
-	Storage string
-}
-
-// DefaultConfig returns the default session configuration.
-func DefaultConfig() Config {
-	return Config{
-		Expiration:     24 * time.Hour,
-		CookieName:     "genesys_session",
-		CookiePath:     "/",
-		CookieSecure:   false,
-		CookieHTTPOnly: true,
-		CookieSameSite: "Lax",
-		KeyLookup:      "cookie:genesys_session",
-		Storage:        "memory",
-	}
-}
-
-// NewManager creates a new session manager.
-func NewManager(config ...Config) *Manager {
-	cfg := DefaultConfig()
-	if len(config) > 0 {
-		cfg = config[0]
-	}
-
-	if cfg.CookieName != "" {
-		cfg.KeyLookup = "cookie:" + cfg.CookieName
-	}
-
-	store := session.New(session.Config{
-		Expiration:     cfg.Expiration,
-		CookiePath:     cfg.CookiePath,
-		CookieDomain:   cfg.CookieDomain,
-		CookieSecure:   cfg.CookieSecure,
-		CookieHTTPOnly: cfg.CookieHTTPOnly,
-		CookieSameSite: cfg.CookieSameSite,
-		KeyLookup:      cfg.KeyLookup,
-	})
-
-	return &Manager{
-		store:   store,
-		config:  cfg,
-		drivers: make(map[string]Driver),
-	}
-}
+// Keys the session keeps its own bookkeeping under. They are hidden from All.
+const (
+	createdAtKey    = "_created_at"
+	lastActivityKey = "_last_activity"
+	flashNewKey     = "_flash.new"
+	flashOldKey     = "_flash.old"
+)
 
-// Store returns the underlying Fiber session store.
-func (m *Manager) Store() *session.Store {
-	return m.store
+// Session holds the data of one session between requests.
+//
+// Values round-trip through JSON when the session is stored, so on later
+// requests numbers come back as float64 and structs as map[string]any.
+type Session struct {
+	id       string
+	driver   contracts.SessionDriver
+	lifetime time.Duration
+	data     map[string]any
+	aged     bool
+	mu       sync.RWMutex
 }
 
-// Get retrieves or creates a session for the given Fiber context.
-func (m *Manager) Get(c *fiber.Ctx) (*Session, error) {
-	sess, err := m.store.Get(c)
-	if err != nil {
-		return nil, err
+func newSession(id string, driver contracts.SessionDriver, lifetime time.Duration, data map[string]any) *Session {
+	if data == nil {
+		data = map[string]any{createdAtKey: time.Now().Unix()}
 	}
-
-	s := &Session{
-		store:     sess,
-		sess:      m.store,
-		id:        sess.ID(),
-		data:      make(map[string]any),
-		flash:     make(map[string]any),
-		createdAt: time.Now(),
-	}
-
-	// Load existing flash data
-	if flashData := sess.Get("_flash"); flashData != nil {
-		if fm, ok := flashData.(map[string]any); ok {
-			s.flash = fm
-		}
-	}
-
-	return s, nil
-}
-
-// Middleware returns Fiber middleware for session handling.
-func (m *Manager) Middleware() fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		sess, err := m.Get(c)
-		if err != nil {
-			return err
-		}
-
-		// Store session in locals
-		c.Locals("session", sess)
-
-		// Continue to next handler
-		if err := c.Next(); err != nil {
-			return err
-		}
-
-		// Save session
-		return sess.Save()
+	return &Session{
+		id:       id,
+		driver:   driver,
+		lifetime: lifetime,
+		data:     data,
 	}
 }
 
 // ID returns the session ID.
 func (s *Session) ID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.id
 }
 
-// Regenerate regenerates the session ID.
+// Regenerate gives the session a new ID and removes the data stored under
+// the old one. Call it after login to prevent session fixation.
 func (s *Session) Regenerate() error {
-	return s.store.Regenerate()
+	s.mu.Lock()
+	old := s.id
+	s.id = newID()
+	s.mu.Unlock()
+
+	return s.driver.Destroy(old)
 }
 
 // Get retrieves a value from the session.
 func (s *Session) Get(key string) any {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-
-	// Check flash first
-	if val, ok := s.flash[key]; ok {
-		return val
-	}
-
-	return s.store.Get(key)
+	return s.data[key]
 }
 
 // GetString retrieves a string value from the session.
 func (s *Session) GetString(key string) string {
-	val := s.Get(key)
-	if val == nil {
-		return ""
-	}
-	if str, ok := val.(string); ok {
-		return str
-	}
-	return ""
+	str, _ := s.Get(key).(string)
+	return str
 }
 
 // GetInt retrieves an integer value from the session.
 func (s *Session) GetInt(key string) int {
-	val := s.Get(key)
-	if val == nil {
-		return 0
-	}
-	if i, ok := val.(int); ok {
-		return i
-	}
-	return 0
+	n, _ := toInt64(s.Get(key))
+	return int(n)
 }
 
 // GetBool retrieves a boolean value from the session.
 func (s *Session) GetBool(key string) bool {
-	val := s.Get(key)
-	if val == nil {
-		return false
-	}
-	if b, ok := val.(bool); ok {
-		return b
-	}
-	return false
+	b, _ := s.Get(key).(bool)
+	return b
 }
 
-// Set stores a value in the session.
-func (s *Session) Set(key string, value any) error {
+// Put stores a value in the session.
+func (s *Session) Put(key string, value any) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.store.Set(key, value)
-	s.dirty = true
+	s.data[key] = value
 	return nil
 }
 
+// Set stores a value in the session. It is an alias of Put.
+func (s *Session) Set(key string, value any) error {
+	return s.Put(key, value)
+}
+
 // Has checks if a key exists in the session.
 func (s *Session) Has(key string) bool {
 	return s.Get(key) != nil
@@ -231,10 +113,9 @@ func (s *Session) Pull(key string) any {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	val := s.store.Get(key)
-	s.store.Delete(key)
-	s.dirty = true
-	return val
+	value := s.data[key]
+	delete(s.data, key)
+	return value
 }
 
 // Forget removes a value from the session.
@@ -242,8 +123,7 @@ func (s *Session) Forget(key string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.store.Delete(key)
-	s.dirty = true
+	delete(s.data, key)
 	return nil
 }
 
@@ -252,43 +132,43 @@ func (s *Session) Flush() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	keys := s.store.Keys()
-	for _, key := range keys {
-		s.store.Delete(key)
-	}
-	s.flash = make(map[string]any)
-	s.dirty = true
+	s.data = map[string]any{createdAtKey: s.data[createdAtKey]}
 	return nil
 }
 
-// Flash stores a value for the next request only.
+// Flash stores a value for the current and the next request only.
 func (s *Session) Flash(key string, value any) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.flash[key] = value
-	s.dirty = true
+	s.data[key] = value
+	s.data[flashNewKey] = addKeys(s.keys(flashNewKey), key)
+	s.data[flashOldKey] = removeKeys(s.keys(flashOldKey), key)
 	return nil
 }
 
-// Keep keeps specific flash data for another request.
+// Keep keeps specific flash data for another request. With no keys it
+// keeps all of it, like Reflash.
 func (s *Session) Keep(keys ...string) error {
+	if len(keys) == 0 {
+		return s.Reflash()
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for _, key := range keys {
-		if val, ok := s.flash[key]; ok {
-			s.flash[key] = val
-		}
-	}
-	s.dirty = true
+	s.data[flashNewKey] = addKeys(s.keys(flashNewKey), keys...)
+	s.data[flashOldKey] = removeKeys(s.keys(flashOldKey), keys...)
 	return nil
 }
 
 // Reflash keeps all flash data for another request.
 func (s *Session) Reflash() error {
-	// Flash data is already in s.flash, nothing to do
-	s.dirty = true
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[flashNewKey] = addKeys(s.keys(flashNewKey), s.keys(flashOldKey)...)
+	s.data[flashOldKey] = []string{}
 	return nil
 }
 
@@ -297,71 +177,150 @@ func (s *Session) All() map[string]any {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	data := make(map[string]any)
-	for _, key := range s.store.Keys() {
-		data[key] = s.store.Get(key)
+	data := make(map[string]any, len(s.data))
+	for key, value := range s.data {
+		switch key {
+		case createdAtKey, lastActivityKey, flashNewKey, flashOldKey:
+			continue
+		}
+		data[key] = value
 	}
 	return data
 }
 
-// Save saves the session.
+// Save writes the session to its driver. The first save in a request
+// expires flash data from the previous request.
 func (s *Session) Save() error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	// Save flash data for next request
-	if len(s.flash) > 0 {
-		s.store.Set("_flash", s.flash)
-	} else {
-		s.store.Delete("_flash")
+	if !s.aged {
+		s.ageFlashData()
+		s.aged = true
 	}
+	s.data[lastActivityKey] = time.Now().Unix()
+	id, data := s.id, maps.Clone(s.data)
+	s.mu.Unlock()
 
-	// Clear flash after saving (it was for this request)
-	s.flash = make(map[string]any)
+	return s.driver.Write(id, data, s.lifetime)
+}
 
-	return s.store.Save()
+// ageFlashData drops the previous request's flash data and marks this
+// request's for removal on the next save. Callers hold the lock.
+func (s *Session) ageFlashData() {
+	for _, key := range s.keys(flashOldKey) {
+		delete(s.data, key)
+	}
+	s.data[flashOldKey] = s.keys(flashNewKey)
+	s.data[flashNewKey] = []string{}
 }
 
-// Destroy destroys the session.
+// Destroy removes the session from storage and starts a new, empty one
+// under a fresh ID.
 func (s *Session) Destroy() error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	old := s.id
+	s.id = newID()
+	s.data = map[string]any{createdAtKey: time.Now().Unix()}
+	s.mu.Unlock()
 
-	return s.store.Destroy()
+	return s.driver.Destroy(old)
 }
 
 // CreatedAt returns when the session was created.
 func (s *Session) CreatedAt() time.Time {
-	return s.createdAt
+	return s.timestamp(createdAtKey)
 }
 
-// LastActivity returns the last activity time.
+// LastActivity returns when the session was last saved, or the zero time
+// for a new session.
 func (s *Session) LastActivity() time.Time {
-	// For now, return current time
-	// A full implementation would track this
-	return time.Now()
+	return s.timestamp(lastActivityKey)
+}
+
+func (s *Session) timestamp(key string) time.Time {
+	seconds, ok := toInt64(s.Get(key))
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
+
+// keys reads a list of keys. Lists that went through JSON come back as []any.
+// Callers hold the lock.
+func (s *Session) keys(name string) []string {
+	switch list := s.data[name].(type) {
+	case []string:
+		return list
+	case []any:
+		keys := make([]string, 0, len(list))
+		for _, item := range list {
+			if key, ok := item.(string); ok {
+				keys = append(keys, key)
+			}
+		}
+		return keys
+	}
+	return nil
+}
+
+func addKeys(list []string, keys ...string) []string {
+	list = slices.Clone(list)
+	for _, key := range keys {
+		if !slices.Contains(list, key) {
+			list = append(list, key)
+		}
+	}
+	return list
+}
+
+func removeKeys(list []string, keys ...string) []string {
+	return slices.DeleteFunc(slices.Clone(list), func(key string) bool {
+		return slices.Contains(keys, key)
+	})
 }
 
-// Driver is the interface for session storage drivers.
-type Driver interface {
-	// Get retrieves session data.
-	Get(id string) (map[string]any, error)
+func toInt64(value any) (int64, bool) {
+	switch n := value.(type) {
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return i, err == nil
+	}
+	return 0, false
+}
 
-	// Set stores session data.
-	Set(id string, data map[string]any, expiration time.Duration) error
+// newID returns a random 40 character session ID.
+func newID() string {
+	b := make([]byte, 20)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
 
-	// Delete removes session data.
-	Delete(id string) error
+// validID reports whether id could have been made by newID. IDs from
+// cookies are checked before they reach a driver, so they are safe to use
+// in file names and keys.
+func validID(id string) bool {
+	if len(id) != 40 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
 
-	// Clear removes all sessions.
-	Clear() error
+func encodePayload(data map[string]any) ([]byte, error) {
+	return json.Marshal(data)
 }
 
-// GetFromContext retrieves the session from Fiber context.
-func GetFromContext(c *fiber.Ctx) *Session {
-	sess := c.Locals("session")
-	if sess == nil {
-		return nil
+func decodePayload(payload []byte) (map[string]any, error) {
+	var data map[string]any
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
 	}
-	return sess.(*Session)
+	return data, nil
 }
@@ -1,11 +1,26 @@
 package session
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/crypt"
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/genesysflow/go-genesys/database/schema"
+	"github.com/genesysflow/go-genesys/testutil"
+	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -93,3 +108,334 @@ func TestMiddlewareCreation(t *testing.T) {
 	middleware := manager.Middleware()
 	assert.NotNil(t, middleware)
 }
+
+// request sends a GET through app, passing cookie as the session cookie, and
+// returns the response body and the new cookie value.
+func request(t *testing.T, app *fiber.App, path, cookie string) (string, string) {
+	t.Helper()
+
+	req := httptest.NewRequest("GET", path, nil)
+	if cookie != "" {
+		req.AddCookie(&http.Cookie{Name: "genesys_session", Value: cookie})
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	for _, c := range resp.Cookies() {
+		if c.Name == "genesys_session" {
+			cookie = c.Value
+		}
+	}
+	return string(body), cookie
+}
+
+func newSessionApp(manager *Manager) *fiber.App {
+	app := fiber.New()
+	app.Use(manager.Middleware())
+	app.Get("/put", func(c *fiber.Ctx) error {
+		GetFromContext(c).Put("name", "ada")
+		GetFromContext(c).Put("visits", 3)
+		return nil
+	})
+	app.Get("/flash", func(c *fiber.Ctx) error {
+		return GetFromContext(c).Flash("status", "saved")
+	})
+	app.Get("/reflash", func(c *fiber.Ctx) error {
+		return GetFromContext(c).Reflash()
+	})
+	app.Get("/read", func(c *fiber.Ctx) error {
+		sess := GetFromContext(c)
+		return c.SendString(fmt.Sprintf("%s|%d|%s", sess.GetString("name"), sess.GetInt("visits"), sess.GetString("status")))
+	})
+	app.Get("/regenerate", func(c *fiber.Ctx) error {
+		return GetFromContext(c).Regenerate()
+	})
+	app.Get("/destroy", func(c *fiber.Ctx) error {
+		return GetFromContext(c).Destroy()
+	})
+	return app
+}
+
+func TestMiddlewarePersistsSession(t *testing.T) {
+	app := newSessionApp(NewManager())
+
+	_, cookie := request(t, app, "/put", "")
+	require.NotEmpty(t, cookie)
+
+	body, next := request(t, app, "/read", cookie)
+	assert.Equal(t, "ada|3|", body)
+	assert.Equal(t, cookie, next)
+
+	body, _ = request(t, app, "/read", "")
+	assert.Equal(t, "|0|", body)
+}
+
+func TestMiddlewareIgnoresUnknownSessionID(t *testing.T) {
+	app := newSessionApp(NewManager())
+
+	forged := strings.Repeat("ab", 20)
+	_, cookie := request(t, app, "/put", forged)
+	assert.NotEqual(t, forged, cookie)
+}
+
+func TestFlashDataLastsOneRequest(t *testing.T) {
+	app := newSessionApp(NewManager())
+
+	_, cookie := request(t, app, "/flash", "")
+
+	body, _ := request(t, app, "/read", cookie)
+	assert.Equal(t, "|0|saved", body)
+
+	body, _ = request(t, app, "/read", cookie)
+	assert.Equal(t, "|0|", body)
+}
+
+func TestReflashKeepsFlashData(t *testing.T) {
+	app := newSessionApp(NewManager())
+
+	_, cookie := request(t, app, "/flash", "")
+	request(t, app, "/reflash", cookie)
+
+	body, _ := request(t, app, "/read", cookie)
+	assert.Equal(t, "|0|saved", body)
+
+	body, _ = request(t, app, "/read", cookie)
+	assert.Equal(t, "|0|", body)
+}
+
+func TestRegenerateMovesSession(t *testing.T) {
+	manager := NewManager()
+	app := newSessionApp(manager)
+
+	_, cookie := request(t, app, "/put", "")
+	_, regenerated := request(t, app, "/regenerate", cookie)
+	require.NotEqual(t, cookie, regenerated)
+
+	body, _ := request(t, app, "/read", regenerated)
+	assert.Equal(t, "ada|3|", body)
+
+	data, err := manager.Driver("memory").Read(cookie)
+	require.NoError(t, err)
+	assert.Nil(t, data)
+}
+
+func TestDestroyClearsSession(t *testing.T) {
+	app := newSessionApp(NewManager())
+
+	_, cookie := request(t, app, "/put", "")
+	_, destroyed := request(t, app, "/destroy", cookie)
+	require.NotEqual(t, cookie, destroyed)
+
+	body, _ := request(t, app, "/read", destroyed)
+	assert.Equal(t, "|0|", body)
+	body, _ = request(t, app, "/read", cookie)
+	assert.Equal(t, "|0|", body)
+}
+
+func TestSessionKeepAndAll(t *testing.T) {
+	manager := NewManager()
+	sess, err := manager.start("")
+	require.NoError(t, err)
+
+	require.NoError(t, sess.Put("user", 1))
+	require.NoError(t, sess.Flash("a", 1))
+	require.NoError(t, sess.Flash("b", 2))
+	require.NoError(t, sess.Save())
+
+	next, err := manager.start(sess.ID())
+	require.NoError(t, err)
+	require.NoError(t, next.Keep("a"))
+	require.NoError(t, next.Save())
+
+	last, err := manager.start(sess.ID())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"user": float64(1), "a": float64(1)}, last.All())
+	assert.False(t, last.CreatedAt().IsZero())
+	assert.False(t, last.LastActivity().IsZero())
+	assert.Equal(t, 1, last.GetInt("user"))
+	assert.Equal(t, float64(1), last.Pull("a"))
+	assert.False(t, last.Has("a"))
+}
+
+func TestStartUnknownDriver(t *testing.T) {
+	manager := NewManager(Config{Storage: "missing"})
+
+	_, err := manager.Start("")
+	assert.ErrorContains(t, err, `driver "missing" is not registered`)
+	assert.Nil(t, manager.Driver("missing"))
+}
+
+func TestExtendFuncCreatesDriverOnce(t *testing.T) {
+	manager := NewManager(Config{Storage: "custom"})
+	calls := 0
+	manager.ExtendFunc("custom", func() (contracts.SessionDriver, error) {
+		calls++
+		return NewMemoryDriver(), nil
+	})
+
+	_, err := manager.Start("")
+	require.NoError(t, err)
+	_, err = manager.Start("")
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+// testDriver runs a driver through a write, read, destroy and GC cycle.
+func testDriver(t *testing.T, driver contracts.SessionDriver) {
+	t.Helper()
+	id := newID()
+
+	data, err := driver.Read(id)
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	require.NoError(t, driver.Write(id, map[string]any{"name": "ada"}, time.Hour))
+	data, err = driver.Read(id)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "ada"}, data)
+
+	require.NoError(t, driver.Write(id, map[string]any{"name": "grace"}, time.Hour))
+	data, err = driver.Read(id)
+	require.NoError(t, err)
+	assert.Equal(t, "grace", data["name"])
+
+	require.NoError(t, driver.Destroy(id))
+	data, err = driver.Read(id)
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	require.NoError(t, driver.Write(id, map[string]any{"name": "ada"}, -time.Second))
+	data, err = driver.Read(id)
+	require.NoError(t, err)
+	assert.Nil(t, data)
+	require.NoError(t, driver.GC(time.Hour))
+}
+
+func TestMemoryDriver(t *testing.T) {
+	testDriver(t, NewMemoryDriver())
+}
+
+func TestFileDriver(t *testing.T) {
+	dir := t.TempDir()
+	driver, err := NewFileDriver(dir)
+	require.NoError(t, err)
+	testDriver(t, driver)
+
+	id := newID()
+	require.NoError(t, driver.Write(id, map[string]any{}, time.Hour))
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, id), old, old))
+	require.NoError(t, driver.GC(time.Hour))
+	assert.NoFileExists(t, filepath.Join(dir, id))
+
+	data, err := driver.Read("../../etc/passwd")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+}
+
+func TestDatabaseDriver(t *testing.T) {
+	td, cleanup := testutil.SetupDatabaseFor(t, "pgsql", "sqlite")
+	defer cleanup()
+	manager := database.NewManager(database.Config{
+		Default: "default",
+		Connections: map[string]database.ConnectionConfig{
+			"default": {
+				Driver:   td.Driver,
+				Host:     td.Host,
+				Port:     td.Port,
+				Database: td.Database,
+				Username: td.Username,
+				Password: td.Password,
+				SSLMode:  "disable",
+			},
+		},
+	})
+	defer manager.Close()
+
+	conn := manager.Connection()
+	require.NoError(t, conn.Error())
+	require.NoError(t, schema.NewBuilder(conn.DB(), conn.Driver()).Create("sessions", func(table *schema.Blueprint) {
+		table.String("id", 40).Unique()
+		table.Text("payload")
+		table.BigInteger("expires_at")
+	}))
+
+	testDriver(t, NewDatabaseDriver(conn, "sessions"))
+}
+
+type fakeRedis struct {
+	values map[string]string
+}
+
+func (r *fakeRedis) Do(ctx context.Context, args ...any) (any, error) {
+	key := args[1].(string)
+	switch args[0] {
+	case "GET":
+		if value, ok := r.values[key]; ok {
+			return value, nil
+		}
+		return nil, nil
+	case "SET":
+		if args[4].(int64) <= 0 {
+			delete(r.values, key)
+			return "OK", nil
+		}
+		r.values[key] = string(args[2].([]byte))
+		return "OK", nil
+	case "DEL":
+		delete(r.values, key)
+		return int64(1), nil
+	}
+	return nil, fmt.Errorf("unexpected command %v", args[0])
+}
+
+func TestRedisDriver(t *testing.T) {
+	redis := &fakeRedis{values: make(map[string]string)}
+	testDriver(t, NewRedisDriver(redis, "session:"))
+
+	driver := NewRedisDriver(redis, "session:")
+	require.NoError(t, driver.Write("abc", map[string]any{}, time.Hour))
+	assert.Contains(t, redis.values, "session:abc")
+}
+
+func TestCookieDriver(t *testing.T) {
+	key, err := crypt.GenerateKey()
+	require.NoError(t, err)
+	raw, err := crypt.ParseKey(key)
+	require.NoError(t, err)
+	encrypter, err := crypt.NewEncrypter(raw)
+	require.NoError(t, err)
+
+	manager := NewManager(Config{Storage: "cookie"})
+	manager.Extend("cookie", NewCookieDriver(encrypter))
+	app := newSessionApp(manager)
+
+	_, cookie := request(t, app, "/put", "")
+	require.NotEmpty(t, cookie)
+	assert.NotContains(t, cookie, "ada")
+
+	body, _ := request(t, app, "/read", cookie)
+	assert.Equal(t, "ada|3|", body)
+
+	body, _ = request(t, app, "/read", "tampered"+cookie)
+	assert.Equal(t, "|0|", body)
+
+	driver := NewCookieDriver(encrypter)
+	_, err = driver.Encode(newID(), map[string]any{"big": strings.Repeat("x", 5000)}, time.Hour)
+	assert.ErrorIs(t, err, ErrCookieTooLarge)
+
+	expired, err := driver.Encode(newID(), map[string]any{}, -time.Second)
+	require.NoError(t, err)
+	_, _, err = driver.Decode(expired)
+	assert.Error(t, err)
+}
+
+var (
+	_ contracts.Session        = (*Session)(nil)
+	_ contracts.SessionManager = (*Manager)(nil)
+	_ contracts.SessionDriver  = (*CookieDriver)(nil)
+	_ CookieStore              = (*CookieDriver)(nil)
+)
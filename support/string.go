@@ -10,7 +10,13 @@ func ToPascalCase(s string) string {
 
 	for i, word := range words {
 		if len(word) > 0 {
-			words[i] = strings.ToUpper(string(word[0])) + strings.ToLower(word[1:])
+			rest := word[1:]
+			// Keep the casing of words that are already mixed case, such
+			// as "OrderShipped", and only lower all-caps ones.
+			if rest == strings.ToUpper(rest) {
+				rest = strings.ToLower(rest)
+			}
+			words[i] = strings.ToUpper(string(word[0])) + rest
 		}
 	}
 
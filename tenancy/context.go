@@ -0,0 +1,63 @@
+package tenancy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/filesystem"
+	"github.com/genesysflow/go-genesys/http"
+)
+
+// TenantKey is the context key Middleware stores the current tenant under.
+const TenantKey = "tenant"
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx carrying tenant, for work done for a
+// tenant outside a request, such as queued jobs.
+func WithTenant(ctx context.Context, tenant *Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// FromContext returns the tenant carried by ctx, or nil.
+func FromContext(ctx context.Context) *Tenant {
+	if ctx == nil {
+		return nil
+	}
+	tenant, _ := ctx.Value(tenantKey{}).(*Tenant)
+	return tenant
+}
+
+// Current returns the tenant of a request, or nil when Middleware didn't
+// identify one.
+func Current(ctx *http.Context) *Tenant {
+	tenant, _ := ctx.Get(TenantKey).(*Tenant)
+	return tenant
+}
+
+// Cache returns repository scoped to the tenant carried by ctx: its keys
+// are prefixed with "tenant:<ID>:". Without a tenant, repository is
+// returned as is.
+func Cache(ctx context.Context, repository *cache.Repository) *cache.Repository {
+	tenant := FromContext(ctx)
+	if tenant == nil {
+		return repository
+	}
+	return repository.Prefix("tenant:" + tenant.ID + ":")
+}
+
+// Disk returns disk scoped to the tenant carried by ctx: its files are
+// stored under "tenants/<ID>". Without a tenant, disk is returned as is.
+func Disk(ctx context.Context, disk contracts.Filesystem) (contracts.Filesystem, error) {
+	tenant := FromContext(ctx)
+	if tenant == nil {
+		return disk, nil
+	}
+	if strings.ContainsAny(tenant.ID, "/\\") || strings.Trim(tenant.ID, ".") == "" {
+		return nil, fmt.Errorf("tenancy: tenant ID '%s' can't name a directory", tenant.ID)
+	}
+	return filesystem.NewScoped(disk, "tenants/"+tenant.ID)
+}
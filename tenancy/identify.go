@@ -0,0 +1,39 @@
+package tenancy
+
+import (
+	"net"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/http"
+)
+
+// Identifier finds the tenant ID of a request, or "" when the request
+// doesn't name one.
+type Identifier func(ctx *http.Context) string
+
+// Subdomain identifies tenants by the subdomain of domain the request was
+// made to: a request to acme.example.com is for tenant "acme" with domain
+// "example.com". Requests to domain itself, to deeper subdomains and to
+// other hosts don't name a tenant.
+func Subdomain(domain string) Identifier {
+	suffix := "." + strings.ToLower(strings.TrimPrefix(domain, "."))
+	return func(ctx *http.Context) string {
+		host, _, err := net.SplitHostPort(ctx.FiberCtx().Hostname())
+		if err != nil {
+			host = ctx.FiberCtx().Hostname()
+		}
+		host = strings.ToLower(host)
+		sub, ok := strings.CutSuffix(host, suffix)
+		if !ok || sub == "" || strings.Contains(sub, ".") {
+			return ""
+		}
+		return sub
+	}
+}
+
+// Header identifies tenants by a request header, such as X-Tenant.
+func Header(name string) Identifier {
+	return func(ctx *http.Context) string {
+		return strings.TrimSpace(ctx.Request().Header(name))
+	}
+}
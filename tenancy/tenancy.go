@@ -0,0 +1,155 @@
+// Package tenancy identifies the tenant a request belongs to and scopes
+// database connections, cache keys and files to it.
+package tenancy
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrTenantNotFound is returned by resolvers for IDs that aren't tenants.
+var ErrTenantNotFound = errors.New("tenancy: tenant not found")
+
+// Tenant is the tenant a request belongs to.
+type Tenant struct {
+	// ID identifies the tenant. It prefixes the tenant's cache keys and
+	// files.
+	ID string `json:"id"`
+
+	// Database is the name of the tenant's own database, for apps with a
+	// database per tenant. Empty shares Config.Connection.
+	Database string `json:"database,omitempty"`
+
+	// Data holds whatever else the resolver knows about the tenant.
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// GetTenantKey returns the tenant's ID, so middleware such as
+// CacheResponse that scope by contracts.Tenant separate tenants.
+func (t *Tenant) GetTenantKey() any {
+	return t.ID
+}
+
+// Resolver looks up a tenant by the ID a request was identified by.
+type Resolver interface {
+	// Resolve returns the tenant with the ID, or ErrTenantNotFound.
+	Resolve(ctx context.Context, id string) (*Tenant, error)
+}
+
+// ResolverFunc adapts a function to Resolver.
+type ResolverFunc func(ctx context.Context, id string) (*Tenant, error)
+
+// Resolve calls f.
+func (f ResolverFunc) Resolve(ctx context.Context, id string) (*Tenant, error) {
+	return f(ctx, id)
+}
+
+// Config configures Tenancy.
+type Config struct {
+	// Identifiers find the tenant ID of a request, tried in order.
+	Identifiers []Identifier
+
+	// Resolver looks the ID up. Nil accepts every ID as a tenant with no
+	// database of its own.
+	Resolver Resolver
+
+	// Connection names the database connection tenant connections are
+	// copied from, with the tenant's Database. Empty uses the default.
+	Connection string
+
+	// Optional lets requests without a tenant ID through without a tenant.
+	// Otherwise they get 404, as do IDs the resolver doesn't know.
+	Optional bool
+}
+
+// Tenancy identifies tenants and hands out their connections.
+type Tenancy struct {
+	config Config
+	db     *database.Manager
+
+	mu          sync.Mutex
+	connections map[string]bool
+}
+
+// New creates a Tenancy. db may be nil when tenants have no databases of
+// their own.
+func New(config Config, db *database.Manager) *Tenancy {
+	return &Tenancy{config: config, db: db, connections: make(map[string]bool)}
+}
+
+// Identify returns the tenant of a request, or nil when no identifier finds
+// an ID.
+func (t *Tenancy) Identify(ctx *http.Context) (*Tenant, error) {
+	for _, identify := range t.config.Identifiers {
+		id := identify(ctx)
+		if id == "" {
+			continue
+		}
+		if t.config.Resolver == nil {
+			return &Tenant{ID: id}, nil
+		}
+		return t.config.Resolver.Resolve(ctx.Request().Context(), id)
+	}
+	return nil, nil
+}
+
+// Middleware identifies the tenant of each request and makes it current
+// for the rest of the request: Current returns it, and FromContext returns
+// it from the request's context.Context.
+func (t *Tenancy) Middleware() http.MiddlewareFunc {
+	return func(ctx *http.Context, next func() error) error {
+		tenant, err := t.Identify(ctx)
+		if errors.Is(err, ErrTenantNotFound) || (err == nil && tenant == nil && !t.config.Optional) {
+			return ctx.Status(fiber.StatusNotFound).JSONResponse(fiber.Map{
+				"error": "Tenant Not Found",
+			})
+		}
+		if err != nil {
+			return err
+		}
+		if tenant != nil {
+			ctx.Set(TenantKey, tenant)
+			ctx.Request().WithContext(WithTenant(ctx.Request().Context(), tenant))
+		}
+		return next()
+	}
+}
+
+// Connection returns the database connection of the tenant carried by ctx:
+// the tenant's own database when it has one, otherwise Config.Connection. Tenant connections are named "<connection>:<tenant ID>".
+func (t *Tenancy) Connection(ctx context.Context) contracts.Connection {
+	if t.db == nil {
+		panic("tenancy: no database manager")
+	}
+	tenant := FromContext(ctx)
+	if tenant == nil || tenant.Database == "" {
+		return t.db.Connection(t.config.Connection)
+	}
+
+	base := t.config.Connection
+	if base == "" {
+		base = t.db.GetDefaultConnection()
+	}
+	name := base + ":" + tenant.ID
+
+	t.mu.Lock()
+	if !t.connections[name] {
+		config, ok := t.db.GetConfig(base)
+		if !ok {
+			t.mu.Unlock()
+			return t.db.Connection(base)
+		}
+		config.Database = tenant.Database
+		t.db.AddConnection(name, config)
+		t.connections[name] = true
+	}
+	t.mu.Unlock()
+
+	return t.db.Connection(name)
+}
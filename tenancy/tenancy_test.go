@@ -0,0 +1,161 @@
+package tenancy
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/genesysflow/go-genesys/cache"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/genesysflow/go-genesys/filesystem"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/genesysflow/go-genesys/http/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestMiddleware(t *testing.T) {
+	tenants := map[string]*Tenant{"acme": {ID: "acme"}, "globex": {ID: "globex"}}
+	tenancy := New(Config{
+		Identifiers: []Identifier{Subdomain("example.com"), Header("X-Tenant")},
+		Resolver: ResolverFunc(func(ctx context.Context, id string) (*Tenant, error) {
+			if tenant, ok := tenants[id]; ok {
+				return tenant, nil
+			}
+			return nil, ErrTenantNotFound
+		}),
+	}, nil)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(nil, app)
+	router.Use(tenancy.Middleware())
+	router.GET("/whoami", func(ctx *http.Context) error {
+		assert.Same(t, Current(ctx), FromContext(ctx.Request().Context()))
+		return ctx.String(Current(ctx).ID)
+	})
+
+	tests := []struct {
+		host, header string
+		status       int
+		body         string
+	}{
+		{"acme.example.com", "", fiber.StatusOK, "acme"},
+		{"acme.example.com:8080", "", fiber.StatusOK, "acme"},
+		{"example.com", "globex", fiber.StatusOK, "globex"},
+		{"unknown.example.com", "", fiber.StatusNotFound, ""},
+		{"example.com", "", fiber.StatusNotFound, ""},
+		{"a.b.example.com", "", fiber.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "http://"+tt.host+"/whoami", nil)
+		if tt.header != "" {
+			req.Header.Set("X-Tenant", tt.header)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, tt.status, resp.StatusCode, tt.host)
+		if tt.body != "" {
+			body, _ := io.ReadAll(resp.Body)
+			assert.Equal(t, tt.body, string(body))
+		}
+	}
+}
+
+var _ contracts.Tenant = (*Tenant)(nil)
+
+func TestMiddlewareScopesCachedResponses(t *testing.T) {
+	tenancy := New(Config{Identifiers: []Identifier{Header("X-Tenant")}}, nil)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(nil, app)
+	calls := 0
+	router.GET("/dashboard", func(ctx *http.Context) error {
+		calls++
+		return ctx.String(Current(ctx).ID)
+	}, tenancy.Middleware(), middleware.CacheResponseWith(middleware.ResponseCacheConfig{
+		TTL:   time.Minute,
+		Store: cache.NewMemoryStore(),
+	}))
+
+	get := func(tenant string) string {
+		req := httptest.NewRequest("GET", "/dashboard", nil)
+		req.Header.Set("X-Tenant", tenant)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	assert.Equal(t, "acme", get("acme"))
+	assert.Equal(t, "globex", get("globex"))
+	assert.Equal(t, "acme", get("acme"))
+	assert.Equal(t, 2, calls)
+}
+
+func TestMiddlewareOptional(t *testing.T) {
+	tenancy := New(Config{Identifiers: []Identifier{Header("X-Tenant")}, Optional: true}, nil)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	router := http.NewRouter(nil, app)
+	router.Use(tenancy.Middleware())
+	router.GET("/", func(ctx *http.Context) error {
+		assert.Nil(t, Current(ctx))
+		return ctx.String("ok")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestConnection(t *testing.T) {
+	dir := t.TempDir()
+	db := database.NewManager(database.Config{
+		Default: "default",
+		Connections: map[string]database.ConnectionConfig{
+			"default": {Driver: "sqlite", Database: dir + "/central.db"},
+		},
+	})
+	defer db.Close()
+	tenancy := New(Config{}, db)
+
+	assert.Equal(t, "default", tenancy.Connection(context.Background()).Name())
+	shared := WithTenant(context.Background(), &Tenant{ID: "globex"})
+	assert.Equal(t, "default", tenancy.Connection(shared).Name())
+
+	ctx := WithTenant(context.Background(), &Tenant{ID: "acme", Database: dir + "/acme.db"})
+	conn := tenancy.Connection(ctx)
+	require.NoError(t, conn.Error())
+	assert.Equal(t, "default:acme", conn.Name())
+	config, ok := db.GetConfig("default:acme")
+	require.True(t, ok)
+	assert.Equal(t, dir+"/acme.db", config.Database)
+	assert.Same(t, conn, tenancy.Connection(ctx))
+}
+
+func TestCacheAndDisk(t *testing.T) {
+	repository := cache.NewRepository(cache.NewMemoryStore())
+	ctx := WithTenant(context.Background(), &Tenant{ID: "acme"})
+
+	assert.Same(t, repository, Cache(context.Background(), repository))
+	require.NoError(t, Cache(ctx, repository).Forever("plan", "pro"))
+	value, err := repository.Get("tenant:acme:plan")
+	require.NoError(t, err)
+	assert.Equal(t, "pro", value)
+
+	disk, err := filesystem.NewMemory(nil)
+	require.NoError(t, err)
+	scoped, err := Disk(ctx, disk)
+	require.NoError(t, err)
+	require.NoError(t, scoped.Put(ctx, "logo.png", "png"))
+	assert.True(t, disk.Exists(ctx, "tenants/acme/logo.png"))
+
+	_, err = Disk(WithTenant(ctx, &Tenant{ID: ".."}), disk)
+	assert.Error(t, err)
+}
@@ -0,0 +1,180 @@
+// Package test provides helpers for feature tests: a client that sends
+// requests through an HTTP kernel and fluent assertions on the responses.
+//
+//	func TestShowPost(t *testing.T) {
+//		client := test.NewClient(t, kernel)
+//		client.ActingAs(user).Get("/posts/1").
+//			AssertStatus(200).
+//			AssertJSONPath("data.title", "Hello")
+//	}
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/auth"
+	"github.com/genesysflow/go-genesys/container"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/http"
+)
+
+// Client sends requests to a kernel without a network listener. Its With
+// methods and ActingAs return a copy, so a base client can be shared
+// between the requests of a test.
+type Client struct {
+	t       testing.TB
+	kernel  *http.Kernel
+	headers map[string]string
+	cookies map[string]string
+	user    contracts.Authenticatable
+	guard   string
+}
+
+// NewClient creates a client for kernel. Failed requests and assertions
+// fail t.
+func NewClient(t testing.TB, kernel *http.Kernel) *Client {
+	return &Client{
+		t:       t,
+		kernel:  kernel,
+		headers: make(map[string]string),
+		cookies: make(map[string]string),
+	}
+}
+
+// clone returns a copy of the client.
+func (c *Client) clone() *Client {
+	clone := *c
+	clone.headers = make(map[string]string, len(c.headers))
+	for key, value := range c.headers {
+		clone.headers[key] = value
+	}
+	clone.cookies = make(map[string]string, len(c.cookies))
+	for name, value := range c.cookies {
+		clone.cookies[name] = value
+	}
+	return &clone
+}
+
+// WithHeader returns a client that sends a header.
+func (c *Client) WithHeader(key, value string) *Client {
+	clone := c.clone()
+	clone.headers[key] = value
+	return clone
+}
+
+// WithHeaders returns a client that sends headers.
+func (c *Client) WithHeaders(headers map[string]string) *Client {
+	clone := c.clone()
+	for key, value := range headers {
+		clone.headers[key] = value
+	}
+	return clone
+}
+
+// WithToken returns a client that sends a bearer token.
+func (c *Client) WithToken(token string) *Client {
+	return c.WithHeader("Authorization", "Bearer "+token)
+}
+
+// WithCookie returns a client that sends a cookie.
+func (c *Client) WithCookie(name, value string) *Client {
+	clone := c.clone()
+	clone.cookies[name] = value
+	return clone
+}
+
+// ActingAs returns a client whose requests are authenticated as user on a
+// guard, the default one without a name. It needs the auth manager in the
+// kernel's application, and as the manager is shared, tests acting as
+// different users mustn't run in parallel.
+func (c *Client) ActingAs(user contracts.Authenticatable, guard ...string) *Client {
+	clone := c.clone()
+	clone.user = user
+	clone.guard = ""
+	if len(guard) > 0 {
+		clone.guard = guard[0]
+	}
+	return clone
+}
+
+// Get sends a GET request.
+func (c *Client) Get(path string) *Response {
+	return c.Request("GET", path, nil)
+}
+
+// Post sends a POST request. See Request for the body.
+func (c *Client) Post(path string, body any) *Response {
+	return c.Request("POST", path, body)
+}
+
+// Put sends a PUT request. See Request for the body.
+func (c *Client) Put(path string, body any) *Response {
+	return c.Request("PUT", path, body)
+}
+
+// Patch sends a PATCH request. See Request for the body.
+func (c *Client) Patch(path string, body any) *Response {
+	return c.Request("PATCH", path, body)
+}
+
+// Delete sends a DELETE request.
+func (c *Client) Delete(path string) *Response {
+	return c.Request("DELETE", path, nil)
+}
+
+// Request sends a request. The body may be nil, url.Values for a form,
+// a string or []byte sent as is, or any other value, which is sent as
+// JSON.
+func (c *Client) Request(method, path string, body any) *Response {
+	c.t.Helper()
+
+	req := http.NewTestRequest(method, path)
+	switch body := body.(type) {
+	case nil:
+	case url.Values:
+		req.WithBody([]byte(body.Encode())).WithHeader("Content-Type", "application/x-www-form-urlencoded")
+	case string:
+		req.WithBody([]byte(body))
+	case []byte:
+		req.WithBody(body)
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			c.t.Fatalf("test: failed to encode the %s %s body: %v", method, path, err)
+		}
+		req.WithBody(data).WithHeader("Content-Type", "application/json")
+	}
+	req.WithHeaders(c.headers)
+	for name, value := range c.cookies {
+		req.WithCookie(name, value)
+	}
+
+	if c.user != nil {
+		manager, err := container.Resolve[*auth.Manager](c.kernel.App())
+		if err != nil {
+			c.t.Fatalf("test: ActingAs needs the auth manager: %v", err)
+		}
+		manager.ActingAs(c.user, c.guard)
+		defer manager.ActingAs(nil, c.guard)
+	}
+
+	resp, err := c.kernel.Test(req)
+	if err != nil {
+		c.t.Fatalf("test: %s %s failed: %v", method, path, err)
+	}
+	return &Response{TestResponse: resp, t: c.t, request: fmt.Sprintf("%s %s", method, path)}
+}
+
+// describe shortens a body for failure messages.
+func describe(body []byte) string {
+	const limit = 500
+	text := strings.TrimSpace(string(body))
+	if len(text) > limit {
+		return text[:limit] + "..."
+	}
+	return text
+}
@@ -0,0 +1,126 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/auth"
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/foundation"
+	"github.com/genesysflow/go-genesys/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func newKernel(t *testing.T) *http.Kernel {
+	t.Helper()
+	app := foundation.New(t.TempDir())
+	manager := auth.NewManager()
+	manager.RegisterProvider("users", users{})
+	app.InstanceType(manager)
+
+	kernel := http.NewKernel(app)
+	kernel.GET("/posts/:id", func(ctx *http.Context) error {
+		return ctx.JSONResponse(map[string]any{
+			"data": map[string]any{"id": ctx.Param("id"), "title": "Hello", "tags": []string{"go", "web"}},
+			"meta": map[string]any{"version": 2},
+		})
+	})
+	kernel.POST("/echo", func(ctx *http.Context) error {
+		var input map[string]any
+		if err := ctx.JSON(&input); err != nil {
+			input = ctx.All()
+		}
+		return ctx.Status(201).JSONResponse(map[string]any{
+			"input":  input,
+			"header": ctx.Request().Header("X-Trace"),
+		})
+	})
+	kernel.GET("/me", func(ctx *http.Context) error {
+		user := ctx.User()
+		if user == nil {
+			return ctx.Status(401).String("guest")
+		}
+		return ctx.String("user " + user.GetAuthIdentifier().(string))
+	})
+	return kernel
+}
+
+// users is a user provider without users, so requests are guests unless
+// a client acts as someone.
+type users struct{}
+
+func (users) RetrieveByID(ctx context.Context, id any) (contracts.Authenticatable, error) {
+	return nil, nil
+}
+
+func (users) RetrieveByCredentials(ctx context.Context, credentials map[string]any) (contracts.Authenticatable, error) {
+	return nil, nil
+}
+
+func (users) ValidateCredentials(user contracts.Authenticatable, credentials map[string]any) bool {
+	return false
+}
+
+func TestClientAssertions(t *testing.T) {
+	client := NewClient(t, newKernel(t))
+
+	client.Get("/posts/7").
+		AssertOK().
+		AssertHeader("Content-Type", "application/json").
+		AssertJSON(map[string]any{"data": map[string]any{"id": "7", "tags": []string{"go", "web"}}}).
+		AssertJSONPath("data.title", "Hello").
+		AssertJSONPath("data.tags.1", "web").
+		AssertJSONPath("meta.version", 2).
+		AssertJSONMissing("data.author").
+		AssertSee(`"title":"Hello"`).
+		AssertDontSee("Goodbye")
+
+	client.WithHeader("X-Trace", "abc").
+		Post("/echo", map[string]any{"name": "Ada"}).
+		AssertStatus(201).
+		AssertJSON(map[string]any{"input": map[string]any{"name": "Ada"}, "header": "abc"})
+
+	client.Post("/echo", url.Values{"name": {"Grace"}}).
+		AssertJSONPath("input.name", "Grace").
+		AssertJSONPath("header", "")
+
+	client.Get("/missing").AssertStatus(404)
+}
+
+func TestClientActingAs(t *testing.T) {
+	client := NewClient(t, newKernel(t))
+
+	client.Get("/me").AssertStatus(401)
+	client.ActingAs(auth.GenericUser{"id": "ada"}).Get("/me").AssertOK().AssertSee("user ada")
+
+	// The user only applies to the acting client's requests.
+	client.Get("/me").AssertStatus(401)
+}
+
+func TestClientReportsFailures(t *testing.T) {
+	recorder := &recordingT{TB: t}
+	client := NewClient(recorder, newKernel(t))
+
+	client.Get("/posts/7").
+		AssertStatus(500).
+		AssertJSONPath("data.title", "Goodbye").
+		AssertJSON(map[string]any{"data": map[string]any{"tags": []string{"go"}}}).
+		AssertSee("Goodbye")
+
+	assert.Len(t, recorder.errors, 4)
+	assert.Contains(t, recorder.errors[0], "GET /posts/7: expected status 500, got 200")
+	assert.Contains(t, recorder.errors[1], `expected JSON path "data.title" to be "Goodbye", got "Hello"`)
+	assert.Contains(t, recorder.errors[2], `JSON mismatch at "data.tags"`)
+}
+
+// recordingT records the errors of assertions expected to fail.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,242 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/genesysflow/go-genesys/database/migrations"
+)
+
+// Database is a test's database, with assertions on its tables.
+type Database struct {
+	t    testing.TB
+	conn contracts.Connection
+}
+
+// testTransactions is implemented by connections that can run a test in a
+// transaction, such as *database.Connection.
+type testTransactions interface {
+	BeginTestTransaction() error
+	RollbackTestTransaction() error
+}
+
+var (
+	migrated   = make(map[*sql.DB]error)
+	migratedMu sync.Mutex
+)
+
+// RefreshDatabase runs migrations on conn, once per test binary, and runs
+// the test in a transaction that is rolled back when it ends. Queries and
+// transactions on conn see the test's writes; code that uses conn.DB()
+// directly doesn't, so pass the connection itself to SQLC's New, or use
+// TruncateDatabase. Tests sharing conn mustn't run in parallel.
+func RefreshDatabase(t testing.TB, conn contracts.Connection, migrations ...migrations.Migration) *Database {
+	t.Helper()
+	migrate(t, conn, migrations)
+
+	tx, ok := conn.(testTransactions)
+	if !ok {
+		t.Fatalf("test: connection %T doesn't support test transactions", conn)
+	}
+	if err := tx.BeginTestTransaction(); err != nil {
+		t.Fatalf("test: failed to begin the test transaction: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := tx.RollbackTestTransaction(); err != nil {
+			t.Errorf("test: failed to roll back the test transaction: %v", err)
+		}
+	})
+	return &Database{t: t, conn: conn}
+}
+
+// TruncateDatabase runs migrations on conn, once per test binary, and
+// empties every table but the migrations table before the test. It is
+// slower than RefreshDatabase, but sees writes from any connection.
+func TruncateDatabase(t testing.TB, conn contracts.Connection, migrations ...migrations.Migration) *Database {
+	t.Helper()
+	migrate(t, conn, migrations)
+	if err := truncate(conn); err != nil {
+		t.Fatalf("test: failed to truncate the database: %v", err)
+	}
+	return &Database{t: t, conn: conn}
+}
+
+// migrate runs migrations on the first call for conn's database.
+func migrate(t testing.TB, conn contracts.Connection, list []migrations.Migration) {
+	t.Helper()
+	if err := conn.Error(); err != nil {
+		t.Fatalf("test: database connection failed: %v", err)
+	}
+
+	migratedMu.Lock()
+	defer migratedMu.Unlock()
+	err, done := migrated[conn.DB()]
+	if !done {
+		_, err = migrations.NewMigrator(conn.DB(), conn.Driver(), list, nil).Run()
+		migrated[conn.DB()] = err
+	}
+	if err != nil {
+		t.Fatalf("test: failed to run migrations: %v", err)
+	}
+}
+
+// truncate empties every table but the migrations table on a single
+// session, so foreign key checks stay off throughout.
+func truncate(conn contracts.Connection) error {
+	ctx := context.Background()
+	session, err := conn.DB().Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	dialect := database.NewDialect(conn.Driver())
+	var query string
+	switch {
+	case dialect.Postgres():
+		query = `SELECT table_name FROM information_schema.tables
+			WHERE table_schema = current_schema() AND table_type = 'BASE TABLE' AND table_name != 'migrations'`
+	case dialect.MySQL():
+		query = `SELECT table_name FROM information_schema.tables
+			WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE' AND table_name != 'migrations'`
+	default:
+		query = `SELECT name FROM sqlite_master
+			WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name != 'migrations'`
+	}
+	rows, err := session.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil || len(tables) == 0 {
+		return err
+	}
+
+	var statements []string
+	switch {
+	case dialect.Postgres():
+		quoted := make([]string, len(tables))
+		for i, table := range tables {
+			quoted[i] = dialect.Quote(table)
+		}
+		statements = []string{"TRUNCATE TABLE " + strings.Join(quoted, ", ") + " RESTART IDENTITY CASCADE"}
+	case dialect.MySQL():
+		statements = append(statements, "SET FOREIGN_KEY_CHECKS = 0")
+		for _, table := range tables {
+			statements = append(statements, "TRUNCATE TABLE "+dialect.Quote(table))
+		}
+		statements = append(statements, "SET FOREIGN_KEY_CHECKS = 1")
+	default:
+		statements = append(statements, "PRAGMA foreign_keys = OFF")
+		for _, table := range tables {
+			statements = append(statements, "DELETE FROM "+dialect.Quote(table))
+		}
+		// Reset AUTOINCREMENT counters, which SQLite keeps in sqlite_sequence
+		// once a table uses one.
+		var sequences int
+		if err := session.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE name = 'sqlite_sequence'").Scan(&sequences); err != nil {
+			return err
+		}
+		if sequences > 0 {
+			statements = append(statements, "DELETE FROM sqlite_sequence")
+		}
+		statements = append(statements, "PRAGMA foreign_keys = ON")
+	}
+	for _, statement := range statements {
+		if _, err := session.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AssertDatabaseHas asserts table has a row with the column values; a nil
+// value matches NULL.
+func (d *Database) AssertDatabaseHas(table string, data map[string]any) *Database {
+	d.t.Helper()
+	if count, ok := d.count(table, data); ok && count == 0 {
+		d.t.Errorf("test: expected table %q to have a row matching %s", table, describeRow(data))
+	}
+	return d
+}
+
+// AssertDatabaseMissing asserts table has no row with the column values.
+func (d *Database) AssertDatabaseMissing(table string, data map[string]any) *Database {
+	d.t.Helper()
+	if count, ok := d.count(table, data); ok && count > 0 {
+		d.t.Errorf("test: expected table %q not to have a row matching %s, found %d", table, describeRow(data), count)
+	}
+	return d
+}
+
+// AssertDatabaseCount asserts the number of rows in table.
+func (d *Database) AssertDatabaseCount(table string, expected int) *Database {
+	d.t.Helper()
+	if count, ok := d.count(table, nil); ok && count != expected {
+		d.t.Errorf("test: expected table %q to have %d rows, got %d", table, expected, count)
+	}
+	return d
+}
+
+// count counts the rows of table with the column values.
+func (d *Database) count(table string, data map[string]any) (int, bool) {
+	d.t.Helper()
+	dialect := database.NewDialect(d.conn.Driver())
+	columns := make([]string, 0, len(data))
+	for column := range data {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	var conditions []string
+	var bindings []any
+	for _, column := range columns {
+		if data[column] == nil {
+			conditions = append(conditions, dialect.Quote(column)+" IS NULL")
+			continue
+		}
+		bindings = append(bindings, data[column])
+		conditions = append(conditions, dialect.Quote(column)+" = "+dialect.Placeholder(len(bindings)))
+	}
+	query := "SELECT COUNT(*) FROM " + dialect.Quote(d.conn.Prefix()+table)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var count int
+	if err := d.conn.QueryRow(query, bindings...).Scan(&count); err != nil {
+		d.t.Errorf("test: failed to query table %q: %v", table, err)
+		return 0, false
+	}
+	return count, true
+}
+
+// describeRow formats column values in column order.
+func describeRow(data map[string]any) string {
+	columns := make([]string, 0, len(data))
+	for column := range data {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	parts := make([]string, len(columns))
+	for i, column := range columns {
+		parts[i] = fmt.Sprintf("%s=%v", column, data[column])
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
@@ -0,0 +1,124 @@
+package test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/genesysflow/go-genesys/database"
+	"github.com/genesysflow/go-genesys/database/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "modernc.org/sqlite"
+)
+
+// postsMigration creates a posts table and counts its runs.
+type postsMigration struct {
+	runs int
+}
+
+func (m *postsMigration) Name() string {
+	return "2024_01_01_000000_create_posts_table"
+}
+
+func (m *postsMigration) Up(builder *schema.Builder) error {
+	m.runs++
+	return builder.Create("posts", func(table *schema.Blueprint) {
+		table.ID()
+		table.String("title")
+		table.String("subtitle").Nullable()
+	})
+}
+
+func (m *postsMigration) Down(builder *schema.Builder) error {
+	return builder.DropIfExists("posts")
+}
+
+func newConnection(t *testing.T) contracts.Connection {
+	t.Helper()
+	manager := database.NewManager(database.Config{
+		Default: "default",
+		Connections: map[string]database.ConnectionConfig{
+			"default": {Driver: "sqlite", Database: filepath.Join(t.TempDir(), "test.db")},
+		},
+	})
+	t.Cleanup(func() { manager.Close() })
+	return manager.Connection()
+}
+
+func TestRefreshDatabase(t *testing.T) {
+	conn := newConnection(t)
+	migration := &postsMigration{}
+
+	t.Run("writes", func(t *testing.T) {
+		db := RefreshDatabase(t, conn, migration)
+
+		_, err := conn.Exec("INSERT INTO posts (title) VALUES (?)", "Hello")
+		require.NoError(t, err)
+
+		// Nested transactions are savepoints within the test transaction.
+		err = conn.Transaction(func(tx contracts.Transaction) error {
+			_, err := tx.Exec("INSERT INTO posts (title) VALUES (?)", "Rolled back")
+			require.NoError(t, err)
+			return errors.New("rollback")
+		})
+		require.Error(t, err)
+		err = conn.Transaction(func(tx contracts.Transaction) error {
+			_, err := tx.Exec("INSERT INTO posts (title, subtitle) VALUES (?, ?)", "Committed", "Nested")
+			return err
+		})
+		require.NoError(t, err)
+
+		db.AssertDatabaseHas("posts", map[string]any{"title": "Hello", "subtitle": nil}).
+			AssertDatabaseHas("posts", map[string]any{"title": "Committed", "subtitle": "Nested"}).
+			AssertDatabaseMissing("posts", map[string]any{"title": "Rolled back"}).
+			AssertDatabaseCount("posts", 2)
+	})
+
+	t.Run("starts empty", func(t *testing.T) {
+		RefreshDatabase(t, conn, migration).AssertDatabaseCount("posts", 0)
+	})
+
+	assert.Equal(t, 1, migration.runs)
+}
+
+func TestTruncateDatabase(t *testing.T) {
+	conn := newConnection(t)
+	migration := &postsMigration{}
+
+	db := TruncateDatabase(t, conn, migration)
+	_, err := conn.DB().Exec("INSERT INTO posts (title) VALUES ('Hello'), ('World')")
+	require.NoError(t, err)
+	db.AssertDatabaseCount("posts", 2)
+
+	db = TruncateDatabase(t, conn, migration)
+	db.AssertDatabaseCount("posts", 0)
+	db.AssertDatabaseCount("migrations", 1)
+
+	_, err = conn.Exec("INSERT INTO posts (title) VALUES ('Again')")
+	require.NoError(t, err)
+	db.AssertDatabaseHas("posts", map[string]any{"id": 1, "title": "Again"})
+}
+
+func TestDatabaseReportsFailures(t *testing.T) {
+	conn := newConnection(t)
+	recorder := &recordingT{TB: t}
+	db := RefreshDatabase(t, conn, &postsMigration{})
+
+	_, err := conn.Exec("INSERT INTO posts (title) VALUES ('Hello')")
+	require.NoError(t, err)
+
+	db = &Database{t: recorder, conn: conn}
+	db.AssertDatabaseHas("posts", map[string]any{"title": "Goodbye", "subtitle": nil}).
+		AssertDatabaseMissing("posts", map[string]any{"title": "Hello"}).
+		AssertDatabaseCount("posts", 3).
+		AssertDatabaseCount("comments", 0)
+
+	require.Len(t, recorder.errors, 4)
+	assert.Equal(t, `test: expected table "posts" to have a row matching {subtitle=<nil>, title=Goodbye}`, recorder.errors[0])
+	assert.Equal(t, `test: expected table "posts" not to have a row matching {title=Hello}, found 1`, recorder.errors[1])
+	assert.Equal(t, `test: expected table "posts" to have 3 rows, got 1`, recorder.errors[2])
+	assert.Contains(t, recorder.errors[3], `test: failed to query table "comments"`)
+}
@@ -0,0 +1,230 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/http"
+)
+
+// Response is a response to a client request, with assertions that fail
+// the test and return the response for chaining.
+type Response struct {
+	*http.TestResponse
+	t       testing.TB
+	request string
+}
+
+// AssertStatus asserts the response's status code.
+func (r *Response) AssertStatus(code int) *Response {
+	r.t.Helper()
+	if r.Status() != code {
+		r.t.Errorf("%s: expected status %d, got %d: %s", r.request, code, r.Status(), describe(r.Body()))
+	}
+	return r
+}
+
+// AssertOK asserts the response's status is 200.
+func (r *Response) AssertOK() *Response {
+	r.t.Helper()
+	return r.AssertStatus(200)
+}
+
+// AssertHeader asserts a response header's value.
+func (r *Response) AssertHeader(key, value string) *Response {
+	r.t.Helper()
+	if actual := r.Header(key); actual != value {
+		r.t.Errorf("%s: expected header %s to be %q, got %q", r.request, key, value, actual)
+	}
+	return r
+}
+
+// AssertRedirect asserts the response redirects to location.
+func (r *Response) AssertRedirect(location string) *Response {
+	r.t.Helper()
+	if !r.IsRedirect() {
+		r.t.Errorf("%s: expected a redirect, got status %d", r.request, r.Status())
+	}
+	return r.AssertHeader("Location", location)
+}
+
+// AssertSee asserts the body contains each of the texts.
+func (r *Response) AssertSee(texts ...string) *Response {
+	r.t.Helper()
+	for _, text := range texts {
+		if !strings.Contains(r.BodyString(), text) {
+			r.t.Errorf("%s: expected the body to contain %q: %s", r.request, text, describe(r.Body()))
+		}
+	}
+	return r
+}
+
+// AssertDontSee asserts the body contains none of the texts.
+func (r *Response) AssertDontSee(texts ...string) *Response {
+	r.t.Helper()
+	for _, text := range texts {
+		if strings.Contains(r.BodyString(), text) {
+			r.t.Errorf("%s: expected the body not to contain %q: %s", r.request, text, describe(r.Body()))
+		}
+	}
+	return r
+}
+
+// AssertJSON asserts the body is JSON that contains expected: objects may
+// have keys expected doesn't mention, while other values, arrays included,
+// must match. Expected is compared as JSON, so structs and maps both work.
+func (r *Response) AssertJSON(expected any) *Response {
+	r.t.Helper()
+	actual, ok := r.decode()
+	if !ok {
+		return r
+	}
+	want, err := normalize(expected)
+	if err != nil {
+		r.t.Errorf("%s: failed to encode the expected JSON: %v", r.request, err)
+		return r
+	}
+	if path, ok := contains(actual, want, ""); !ok {
+		r.t.Errorf("%s: JSON mismatch at %q:\nexpected: %s\nbody: %s", r.request, path, mustJSON(want), describe(r.Body()))
+	}
+	return r
+}
+
+// AssertJSONPath asserts the value at a dotted path of the JSON body, such
+// as "data.items.0.name", equals expected, compared as JSON.
+func (r *Response) AssertJSONPath(path string, expected any) *Response {
+	r.t.Helper()
+	actual, ok := r.decode()
+	if !ok {
+		return r
+	}
+	value, found := lookup(actual, path)
+	if !found {
+		r.t.Errorf("%s: JSON path %q not found: %s", r.request, path, describe(r.Body()))
+		return r
+	}
+	want, err := normalize(expected)
+	if err != nil {
+		r.t.Errorf("%s: failed to encode the expected JSON: %v", r.request, err)
+		return r
+	}
+	if !reflect.DeepEqual(value, want) {
+		r.t.Errorf("%s: expected JSON path %q to be %s, got %s", r.request, path, mustJSON(want), mustJSON(value))
+	}
+	return r
+}
+
+// AssertJSONMissing asserts a dotted path isn't in the JSON body.
+func (r *Response) AssertJSONMissing(path string) *Response {
+	r.t.Helper()
+	actual, ok := r.decode()
+	if !ok {
+		return r
+	}
+	if _, found := lookup(actual, path); found {
+		r.t.Errorf("%s: expected JSON path %q to be missing: %s", r.request, path, describe(r.Body()))
+	}
+	return r
+}
+
+// decode decodes the JSON body, failing the test when it isn't JSON.
+func (r *Response) decode() (any, bool) {
+	r.t.Helper()
+	var body any
+	if err := json.Unmarshal(r.Body(), &body); err != nil {
+		r.t.Errorf("%s: expected a JSON body: %v: %s", r.request, err, describe(r.Body()))
+		return nil, false
+	}
+	return body, true
+}
+
+// normalize converts a value to the types encoding/json decodes into.
+func normalize(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized any
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+// contains reports whether actual contains expected, returning the path of
+// the first mismatch.
+func contains(actual, expected any, path string) (string, bool) {
+	switch want := expected.(type) {
+	case map[string]any:
+		got, ok := actual.(map[string]any)
+		if !ok {
+			return path, false
+		}
+		for key, value := range want {
+			child, ok := got[key]
+			if !ok {
+				return join(path, key), false
+			}
+			if mismatch, ok := contains(child, value, join(path, key)); !ok {
+				return mismatch, false
+			}
+		}
+		return "", true
+	case []any:
+		got, ok := actual.([]any)
+		if !ok || len(got) != len(want) {
+			return path, false
+		}
+		for i, value := range want {
+			if mismatch, ok := contains(got[i], value, join(path, strconv.Itoa(i))); !ok {
+				return mismatch, false
+			}
+		}
+		return "", true
+	default:
+		return path, reflect.DeepEqual(actual, expected)
+	}
+}
+
+// lookup returns the value at a dotted path; numeric segments index arrays.
+func lookup(value any, path string) (any, bool) {
+	if path == "" {
+		return value, true
+	}
+	for _, segment := range strings.Split(path, ".") {
+		switch node := value.(type) {
+		case map[string]any:
+			child, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			value = child
+		case []any:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			value = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func mustJSON(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
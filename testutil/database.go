@@ -0,0 +1,95 @@
+package testutil
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// DatabaseEnv is the environment variable that selects the driver of
+// SetupDatabase: postgres (the default), mysql, or sqlite for a fast
+// in-memory database that needs no Docker.
+const DatabaseEnv = "GENESYS_TEST_DATABASE"
+
+// Database holds the connection details of a test database, in the shape
+// of database.ConnectionConfig.
+type Database struct {
+	Driver   string
+	Host     string
+	Port     int
+	Database string
+	Username string
+	Password string
+}
+
+var memoryDatabases atomic.Int64
+
+// SetupDatabase creates a test database for the driver DatabaseEnv selects,
+// so integration suites run against every supported driver. It returns the
+// database and a cleanup function that should be deferred.
+func SetupDatabase(t *testing.T) (*Database, func()) {
+	t.Helper()
+
+	switch driver := databaseDriver(); driver {
+	case "pgsql":
+		pc, cleanup := SetupPostgresContainer(t)
+		return &Database{
+			Driver:   "pgsql",
+			Host:     pc.Host,
+			Port:     pc.Port,
+			Database: pc.Database,
+			Username: pc.Username,
+			Password: pc.Password,
+		}, cleanup
+	case "mysql":
+		mc, cleanup := SetupMySQLContainer(t)
+		return &Database{
+			Driver:   "mysql",
+			Host:     mc.Host,
+			Port:     mc.Port,
+			Database: mc.Database,
+			Username: mc.Username,
+			Password: mc.Password,
+		}, cleanup
+	case "sqlite":
+		// A named shared-cache database is shared by the connections of a
+		// pool and dropped once the last one closes.
+		name := fmt.Sprintf("file:testdb%d?mode=memory&cache=shared", memoryDatabases.Add(1))
+		return &Database{Driver: "sqlite", Database: name}, func() {}
+	default:
+		t.Fatalf("unsupported %s %q: use postgres, mysql or sqlite", DatabaseEnv, driver)
+		return nil, nil
+	}
+}
+
+// SetupDatabaseFor is SetupDatabase for suites of code that supports only
+// some drivers, named as in Database.Driver ("pgsql", "mysql", "sqlite"):
+// the test is skipped when DatabaseEnv selects another, or in short mode
+// when the driver needs a container.
+func SetupDatabaseFor(t *testing.T, drivers ...string) (*Database, func()) {
+	t.Helper()
+
+	switch driver := databaseDriver(); driver {
+	case "pgsql", "mysql", "sqlite":
+		if !slices.Contains(drivers, driver) {
+			t.Skipf("%s=%s: this suite supports %s only", DatabaseEnv, driver, strings.Join(drivers, ", "))
+		}
+		if driver != "sqlite" && testing.Short() {
+			t.Skipf("%s=%s needs a container; skipping in short mode", DatabaseEnv, driver)
+		}
+	}
+	return SetupDatabase(t)
+}
+
+// databaseDriver returns the driver DatabaseEnv selects.
+func databaseDriver() string {
+	switch driver := os.Getenv(DatabaseEnv); driver {
+	case "", "postgres", "pgsql":
+		return "pgsql"
+	default:
+		return driver
+	}
+}
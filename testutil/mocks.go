@@ -187,6 +187,12 @@ func (m *MockConfig) Load(path string) error {
 	return nil
 }
 
+// Watch does nothing in the mock config; its values only change through
+// Set.
+func (m *MockConfig) Watch(key string, fn func(value any)) func() {
+	return func() {}
+}
+
 // MockProvider is a mock implementation of contracts.ServiceProvider.
 type MockProvider struct {
 	mock.Mock
@@ -272,6 +278,7 @@ type MockApplication struct {
 	logger    contracts.Logger
 	bindings  map[string]any
 	instances map[string]any
+	tags      map[string][]string
 	mu        sync.RWMutex
 	basePath  string
 	booted    bool
@@ -284,6 +291,7 @@ func NewMockApplication() *MockApplication {
 		logger:    &MockLogger{},
 		bindings:  make(map[string]any),
 		instances: make(map[string]any),
+		tags:      make(map[string][]string),
 		basePath:  "/tmp/test-app",
 	}
 }
@@ -458,6 +466,61 @@ func getTypeName(t reflect.Type) string {
 	return t.String()
 }
 
+// Extend wraps a registered instance or binding with decorator.
+func (m *MockApplication) Extend(name string, decorator func(old any) any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if instance, ok := m.instances[name]; ok {
+		m.instances[name] = decorator(instance)
+	}
+	return nil
+}
+
+// Tag adds services to a tag.
+func (m *MockApplication) Tag(tag string, services ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tags[tag] = append(m.tags[tag], services...)
+}
+
+// Tagged resolves every service with a tag.
+func (m *MockApplication) Tagged(tag string) ([]any, error) {
+	m.mu.RLock()
+	names := append([]string(nil), m.tags[tag]...)
+	m.mu.RUnlock()
+
+	services := make([]any, 0, len(names))
+	for _, name := range names {
+		service, err := m.Make(name)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, service)
+	}
+	return services, nil
+}
+
+// Fill injects nothing.
+func (m *MockApplication) Fill(target any) error {
+	return nil
+}
+
+// When starts a contextual binding; the mock records nothing.
+func (m *MockApplication) When(consumer any) contracts.ContextualBindingBuilder {
+	return mockContextualBinding{}
+}
+
+// mockContextualBinding is a contextual binding builder that does nothing.
+type mockContextualBinding struct{}
+
+// Needs returns the builder.
+func (b mockContextualBinding) Needs(dependency any) contracts.ContextualBindingBuilder {
+	return b
+}
+
+// Give does nothing.
+func (b mockContextualBinding) Give(implementation any) {}
+
 // BindValue is an alias for Instance.
 func (m *MockApplication) BindValue(name string, value any) error {
 	return m.Instance(name, value)
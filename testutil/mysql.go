@@ -0,0 +1,85 @@
+package testutil
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// MySQLContainer holds the container and connection details for tests.
+type MySQLContainer struct {
+	Container testcontainers.Container
+	Host      string
+	Port      int
+	Database  string
+	Username  string
+	Password  string
+}
+
+// SetupMySQLContainer creates a MySQL container for integration testing.
+// It returns the container info and a cleanup function that should be deferred.
+func SetupMySQLContainer(t *testing.T) (*MySQLContainer, func()) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	container, err := testcontainers.Run(ctx,
+		"mysql:8.4",
+		testcontainers.WithExposedPorts("3306/tcp"),
+		testcontainers.WithEnv(map[string]string{
+			"MYSQL_DATABASE":      "testdb",
+			"MYSQL_USER":          "testuser",
+			"MYSQL_PASSWORD":      "testpass",
+			"MYSQL_ROOT_PASSWORD": "testpass",
+		}),
+		testcontainers.WithWaitStrategy(
+			// MySQL restarts once after initializing; only the final server
+			// listens on 3306.
+			wait.ForLog("port: 3306  MySQL Community Server").
+				WithStartupTimeout(120*time.Second),
+		),
+	)
+	if err != nil {
+		t.Fatalf("failed to start mysql container: %v", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		container.Terminate(ctx)
+		t.Fatalf("failed to get container host: %v", err)
+	}
+
+	port, err := container.MappedPort(ctx, "3306")
+	if err != nil {
+		container.Terminate(ctx)
+		t.Fatalf("failed to get mapped port: %v", err)
+	}
+
+	mc := &MySQLContainer{
+		Container: container,
+		Host:      host,
+		Port:      port.Int(),
+		Database:  "testdb",
+		Username:  "testuser",
+		Password:  "testpass",
+	}
+
+	cleanup := func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate container: %v", err)
+		}
+	}
+
+	return mc, cleanup
+}
+
+// DSN returns the go-sql-driver/mysql connection string for the test container.
+func (mc *MySQLContainer) DSN() string {
+	return mc.Username + ":" + mc.Password +
+		"@tcp(" + mc.Host + ":" + strconv.Itoa(mc.Port) + ")/" +
+		mc.Database + "?parseTime=true"
+}
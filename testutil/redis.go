@@ -0,0 +1,70 @@
+package testutil
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// RedisContainer holds the container and address for tests.
+type RedisContainer struct {
+	Container testcontainers.Container
+	Host      string
+	Port      int
+}
+
+// SetupRedisContainer creates a Redis container for integration testing.
+// It returns the container info and a cleanup function that should be deferred.
+func SetupRedisContainer(t *testing.T) (*RedisContainer, func()) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	container, err := testcontainers.Run(ctx,
+		"redis:7-alpine",
+		testcontainers.WithExposedPorts("6379/tcp"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("Ready to accept connections").
+				WithStartupTimeout(60*time.Second),
+		),
+	)
+	if err != nil {
+		t.Fatalf("failed to start redis container: %v", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		container.Terminate(ctx)
+		t.Fatalf("failed to get container host: %v", err)
+	}
+
+	port, err := container.MappedPort(ctx, "6379")
+	if err != nil {
+		container.Terminate(ctx)
+		t.Fatalf("failed to get mapped port: %v", err)
+	}
+
+	rc := &RedisContainer{
+		Container: container,
+		Host:      host,
+		Port:      port.Int(),
+	}
+
+	cleanup := func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate container: %v", err)
+		}
+	}
+
+	return rc, cleanup
+}
+
+// Addr returns the host:port address of the test container.
+func (rc *RedisContainer) Addr() string {
+	return net.JoinHostPort(rc.Host, strconv.Itoa(rc.Port))
+}
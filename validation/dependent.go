@@ -0,0 +1,147 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Dependent rules check a field against the other fields of the data, so
+// ValidateMap evaluates them itself rather than go-playground/validator,
+// which validates each map field on its own. They take the other field
+// first, then any values, separated by spaces:
+//
+//	"email": "required_if=contact email,email"
+//	"phone": "required_without=email"
+//	"password_confirmation": "same=password"
+//	"company": "prohibited_unless=type business"
+var dependentRules = map[string]func(rule dependentRule, value any, data map[string]any) bool{
+	// required_if=other value...: required when other is one of the values.
+	"required_if": func(rule dependentRule, value any, data map[string]any) bool {
+		return filled(value) || !rule.matches(data)
+	},
+	// required_with=other...: required when any of the others is filled.
+	"required_with": func(rule dependentRule, value any, data map[string]any) bool {
+		if filled(value) {
+			return true
+		}
+		for _, other := range rule.other {
+			if filled(data[other]) {
+				return false
+			}
+		}
+		return true
+	},
+	// required_without=other...: required when any of the others is empty.
+	"required_without": func(rule dependentRule, value any, data map[string]any) bool {
+		if filled(value) {
+			return true
+		}
+		for _, other := range rule.other {
+			if !filled(data[other]) {
+				return false
+			}
+		}
+		return true
+	},
+	// same=other: equal to other, which fails when only one is present.
+	"same": func(rule dependentRule, value any, data map[string]any) bool {
+		return reflect.DeepEqual(value, data[rule.other[0]])
+	},
+	// different=other: when filled, not equal to other.
+	"different": func(rule dependentRule, value any, data map[string]any) bool {
+		return !filled(value) || !reflect.DeepEqual(value, data[rule.other[0]])
+	},
+	// prohibited_unless=other value...: empty unless other is one of the
+	// values.
+	"prohibited_unless": func(rule dependentRule, value any, data map[string]any) bool {
+		return !filled(value) || rule.matches(data)
+	},
+}
+
+// dependentRule is a parsed dependent rule.
+type dependentRule struct {
+	tag    string
+	param  string
+	other  []string
+	values []string
+}
+
+// matches reports whether the rule's other field is one of its values.
+func (r dependentRule) matches(data map[string]any) bool {
+	value, ok := data[r.other[0]]
+	if !ok || value == nil {
+		return false
+	}
+	actual := fmt.Sprint(value)
+	for _, expected := range r.values {
+		if actual == expected {
+			return true
+		}
+	}
+	return false
+}
+
+// splitDependentRules separates a field's dependent rules from the rules
+// go-playground/validator checks.
+func splitDependentRules(rules string) (string, []dependentRule, error) {
+	var remaining []string
+	var dependent []dependentRule
+	for _, tag := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(tag, "=")
+		if _, ok := dependentRules[name]; !ok {
+			remaining = append(remaining, tag)
+			continue
+		}
+
+		fields := strings.Fields(param)
+		rule := dependentRule{tag: name, param: param}
+		switch name {
+		case "required_if", "prohibited_unless":
+			if len(fields) < 2 {
+				return "", nil, fmt.Errorf("validation: %s requires a field and values", name)
+			}
+			rule.other, rule.values = fields[:1], fields[1:]
+		case "same", "different":
+			if len(fields) != 1 {
+				return "", nil, fmt.Errorf("validation: %s requires a field", name)
+			}
+			rule.other = fields
+		default:
+			if len(fields) == 0 {
+				return "", nil, fmt.Errorf("validation: %s requires fields", name)
+			}
+			rule.other = fields
+		}
+		dependent = append(dependent, rule)
+	}
+	return strings.Join(remaining, ","), dependent, nil
+}
+
+// hasTag reports whether rules include tag.
+func hasTag(rules, tag string) bool {
+	for _, t := range strings.Split(rules, ",") {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// filled reports whether a value is present: not nil, a blank string, or
+// an empty slice or map.
+func filled(value any) bool {
+	if value == nil {
+		return false
+	}
+	if s, ok := value.(string); ok {
+		return strings.TrimSpace(s) != ""
+	}
+	switch rv := reflect.ValueOf(value); rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() > 0
+	case reflect.Pointer, reflect.Interface:
+		return !rv.IsNil()
+	}
+	return true
+}
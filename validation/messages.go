@@ -0,0 +1,37 @@
+package validation
+
+import (
+	"embed"
+	"io/fs"
+	"sync"
+
+	"github.com/genesysflow/go-genesys/lang"
+)
+
+// defaultLang holds the default messages, laid out like an app's lang
+// directory.
+//
+//go:embed lang
+var defaultLang embed.FS
+
+// defaults returns the translator of the default messages, which falls
+// back to English.
+var defaults = sync.OnceValue(func() *lang.Translator {
+	translator := lang.New("en", "en")
+	root, err := fs.Sub(defaultLang, "lang")
+	if err == nil {
+		err = translator.LoadFS(root)
+	}
+	if err != nil {
+		panic("validation: failed to load default messages: " + err.Error())
+	}
+	return translator
+})
+
+// defaultLine returns a default message in locale.
+func defaultLine(locale, key string) (string, bool) {
+	if locale == "" {
+		locale = "en"
+	}
+	return defaults().Line(locale, key)
+}
@@ -0,0 +1,179 @@
+package validation
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultPasswordLength is the minimum length of a Password without Min.
+const DefaultPasswordLength = 8
+
+// PwnedPasswordsURL is the Have I Been Pwned range API Uncompromised
+// checks passwords against.
+const PwnedPasswordsURL = "https://api.pwnedpasswords.com/range/"
+
+// Password is a rule for password strength. Register it, then use it by
+// name in registration or password change rules:
+//
+//	validator.RegisterRule("password", &validation.Password{
+//		Min:           12,
+//		MixedCase:     true,
+//		Numbers:       true,
+//		Symbols:       true,
+//		Uncompromised: true,
+//	})
+//	ctx.Validate(map[string]string{"password": "required,password"})
+type Password struct {
+	// Min is the minimum length in characters. Zero is 8.
+	Min int
+
+	// Letters requires a letter, and MixedCase an uppercase and a lowercase
+	// letter.
+	Letters   bool
+	MixedCase bool
+
+	// Numbers requires a digit, and Symbols a punctuation character or
+	// symbol.
+	Numbers bool
+	Symbols bool
+
+	// Uncompromised rejects passwords found in data breaches, by sending
+	// the first five characters of the password's SHA-1 hash to Have I Been
+	// Pwned (k-anonymity: the password and its full hash never leave the
+	// app). When the service can't be reached, the password passes.
+	Uncompromised bool
+
+	// Threshold is the number of breaches a password may appear in before
+	// Uncompromised rejects it.
+	Threshold int
+
+	// Client sends the breach check. Nil uses a client with a 5s timeout.
+	Client *http.Client
+
+	// URL overrides PwnedPasswordsURL, such as for a mirror.
+	URL string
+}
+
+// Passes reports whether value is a string password that meets the policy.
+func (p *Password) Passes(attribute string, value any) bool {
+	password, ok := value.(string)
+	if !ok || utf8.RuneCountInString(password) < p.minLength() {
+		return false
+	}
+
+	var upper, lower, letter, number, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper, letter = true, true
+		case unicode.IsLower(r):
+			lower, letter = true, true
+		case unicode.IsLetter(r):
+			letter = true
+		case unicode.IsDigit(r):
+			number = true
+		case unicode.IsPunct(r), unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	if (p.Letters && !letter) || (p.MixedCase && !(upper && lower)) ||
+		(p.Numbers && !number) || (p.Symbols && !symbol) {
+		return false
+	}
+
+	if p.Uncompromised {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		count, err := p.breaches(ctx, password)
+		if err == nil && count > p.Threshold {
+			return false
+		}
+	}
+	return true
+}
+
+// Message describes the policy.
+func (p *Password) Message() string {
+	var requirements []string
+	switch {
+	case p.MixedCase:
+		requirements = append(requirements, "one uppercase and one lowercase letter")
+	case p.Letters:
+		requirements = append(requirements, "one letter")
+	}
+	if p.Numbers {
+		requirements = append(requirements, "one number")
+	}
+	if p.Symbols {
+		requirements = append(requirements, "one symbol")
+	}
+
+	msg := ":attribute must be at least " + strconv.Itoa(p.minLength()) + " characters"
+	if n := len(requirements); n > 0 {
+		list := requirements[0]
+		if n > 1 {
+			list = strings.Join(requirements[:n-1], ", ") + " and " + requirements[n-1]
+		}
+		msg += " and contain at least " + list
+	}
+	if p.Uncompromised {
+		msg += ", and must not have appeared in a data leak"
+	}
+	return msg
+}
+
+func (p *Password) minLength() int {
+	if p.Min > 0 {
+		return p.Min
+	}
+	return DefaultPasswordLength
+}
+
+// breaches returns the number of breaches password appeared in.
+func (p *Password) breaches(ctx context.Context, password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	base := p.URL
+	if base == "" {
+		base = PwnedPasswordsURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+prefix, nil)
+	if err != nil {
+		return 0, err
+	}
+	// Padding hides the number of matching suffixes from observers.
+	req.Header.Set("Add-Padding", "true")
+
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("validation: %s returned %d", req.URL.Host, resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if ok && strings.EqualFold(candidate, suffix) {
+			return strconv.Atoi(count)
+		}
+	}
+	return 0, scanner.Err()
+}
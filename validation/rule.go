@@ -0,0 +1,117 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/genesysflow/go-genesys/contracts"
+	"github.com/go-playground/validator/v10"
+)
+
+// Rule is a custom validation rule. Unlike a validator.Func, a rule is a
+// value that can carry dependencies, such as a database connection to
+// check that an email isn't taken:
+//
+//	type UniqueEmail struct {
+//		DB *database.Manager `inject:""`
+//	}
+//
+//	func (r *UniqueEmail) Passes(attribute string, value any) bool {
+//		var count int
+//		r.DB.Connection().QueryRow("SELECT COUNT(*) FROM users WHERE email = $1", value).Scan(&count)
+//		return count == 0
+//	}
+//
+//	func (r *UniqueEmail) Message() string {
+//		return "The :attribute has already been taken."
+//	}
+type Rule interface {
+	// Passes reports whether value is valid for the attribute, the name of
+	// the field being validated.
+	Passes(attribute string, value any) bool
+
+	// Message returns the message of a failure. :attribute and :value are
+	// replaced as in custom messages.
+	Message() string
+}
+
+// SetContainer sets the container the dependencies of rules registered
+// afterwards are injected from. The validation service provider sets the
+// application's.
+func (v *Validator) SetContainer(c contracts.Container) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.container = c
+}
+
+// RegisterRule registers a rule object under name, for use in struct tags
+// and rule maps like any other tag:
+//
+//	validator.RegisterRule("unique_email", &rules.UniqueEmail{})
+//	validator.ValidateMap(input, map[string]string{"email": "required,email,unique_email"})
+//
+// With a container set, the rule's inject-tagged fields are filled from it
+// first. In rule maps, rule objects run after the field's other rules have
+// passed. A custom message for "<field>.<name>", or a translated
+// "validation.<name>" line, takes precedence over the rule's Message.
+func (v *Validator) RegisterRule(name string, rule Rule) error {
+	v.mu.RLock()
+	c := v.container
+	v.mu.RUnlock()
+	if t := reflect.TypeOf(rule); c != nil && t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct {
+		if err := c.Fill(rule); err != nil {
+			return fmt.Errorf("validation: failed to inject rule %s: %w", name, err)
+		}
+	}
+
+	err := v.validate.RegisterValidation(name, func(fl validator.FieldLevel) bool {
+		if !fl.Field().IsValid() {
+			return rule.Passes(fl.FieldName(), nil)
+		}
+		return rule.Passes(fl.FieldName(), fl.Field().Interface())
+	}, true)
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.rules[name] = rule
+	return nil
+}
+
+// splitRuleObjects separates the registered rule objects in a field's rules
+// from the tags go-playground/validator checks.
+func (v *Validator) splitRuleObjects(rules string) (string, []string) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if len(v.rules) == 0 || rules == "" {
+		return rules, nil
+	}
+
+	var remaining, objects []string
+	for _, tag := range strings.Split(rules, ",") {
+		if _, ok := v.rules[tag]; ok {
+			objects = append(objects, tag)
+		} else {
+			remaining = append(remaining, tag)
+		}
+	}
+	return strings.Join(remaining, ","), objects
+}
+
+// checkRuleObjects runs a field's rule objects, adding the message of the
+// first that fails.
+func (v *Validator) checkRuleObjects(field string, value any, names []string, errors *ValidationErrors) {
+	for _, name := range names {
+		v.mu.RLock()
+		rule := v.rules[name]
+		v.mu.RUnlock()
+		if rule.Passes(field, value) {
+			continue
+		}
+		errors.Add(field, v.message(failure{field: field, tag: name, value: value}))
+		return
+	}
+}
@@ -0,0 +1,61 @@
+package validation
+
+import (
+	"strconv"
+	"strings"
+)
+
+// validatedData returns the fields of data that rules cover, coerced to
+// the types their rules expect.
+func validatedData(data map[string]any, rules map[string]string) map[string]any {
+	validated := make(map[string]any, len(rules))
+	for field, rule := range rules {
+		value, ok := data[field]
+		if !ok {
+			continue
+		}
+		validated[field] = coerce(value, rule)
+	}
+	return validated
+}
+
+// coerce converts a string, such as a form or query value, to the type its
+// rules expect: a bool for boolean, and an int, or a float64 when it has a
+// fraction, for number and numeric. Values that don't parse are kept as
+// they are.
+func coerce(value any, rules string) any {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+	switch {
+	case hasTag(rules, "boolean"):
+		if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+			return b
+		}
+	case hasTag(rules, "number"), hasTag(rules, "numeric"):
+		s = strings.TrimSpace(s)
+		if i, err := strconv.Atoi(s); err == nil {
+			return i
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return value
+}
+
+// cutTag removes tag from rules, reporting whether it was there.
+func cutTag(rules, tag string) (string, bool) {
+	tags := strings.Split(rules, ",")
+	kept := tags[:0]
+	found := false
+	for _, t := range tags {
+		if t == tag {
+			found = true
+			continue
+		}
+		kept = append(kept, t)
+	}
+	return strings.Join(kept, ","), found
+}
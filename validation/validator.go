@@ -4,10 +4,12 @@ package validation
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"reflect"
 	"strings"
 	"sync"
 
+	"github.com/genesysflow/go-genesys/contracts"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -16,9 +18,22 @@ type Validator struct {
 	validate       *validator.Validate
 	customMessages map[string]string
 	attributeNames map[string]string
+	translator     Translator
+	locale         string
+	rules          map[string]Rule
+	container      contracts.Container
 	mu             sync.RWMutex
 }
 
+// Translator supplies translated validation messages. *lang.Translator
+// implements it.
+type Translator interface {
+	// Locale returns the default locale.
+	Locale() string
+	// Line returns the line for key in locale.
+	Line(locale, key string) (string, bool)
+}
+
 // New creates a new Validator instance.
 func New() *Validator {
 	v := validator.New()
@@ -36,6 +51,7 @@ func New() *Validator {
 		validate:       v,
 		customMessages: make(map[string]string),
 		attributeNames: make(map[string]string),
+		rules:          make(map[string]Rule),
 	}
 }
 
@@ -45,24 +61,61 @@ func (v *Validator) Validate(data any) *ValidationResult {
 	return v.newResult(err, nil)
 }
 
-// ValidateMap validates a map against rules.
+// ValidateMap validates a map against rules. Besides go-playground/validator
+// tags, rules may use the dependent rules required_if, required_with,
+// required_without, same, different and prohibited_unless, which check a
+// field against the others. A field that fails one of them gets no other
+// error, and an empty field that isn't otherwise required skips its other
+// rules.
+//
+// A field whose rules include sometimes is only validated when it is
+// present in data. The result's Validated data holds only the fields the
+// rules cover that are present, coerced to the types their rules expect.
 func (v *Validator) ValidateMap(data map[string]any, rules map[string]string) *ValidationResult {
+	errors := NewValidationErrors()
+	validated := validatedData(data, rules)
+
 	// Convert rules to map[string]any
 	rulesAny := make(map[string]any, len(rules))
+	objects := make(map[string][]string)
 	for k, val := range rules {
-		rulesAny[k] = val
+		val, sometimes := cutTag(val, "sometimes")
+		if _, present := data[k]; sometimes && !present {
+			continue
+		}
+		remaining, dependent, err := splitDependentRules(val)
+		if err != nil {
+			errors.Add(k, err.Error())
+			continue
+		}
+		if len(dependent) > 0 {
+			if !v.checkDependent(k, data, dependent, errors) {
+				continue
+			}
+			if !filled(data[k]) && !hasTag(remaining, "required") {
+				continue
+			}
+		}
+		remaining, objects[k] = v.splitRuleObjects(remaining)
+		if remaining != "" {
+			rulesAny[k] = remaining
+		}
 	}
 
 	errs := v.validate.ValidateMap(data, rulesAny)
+	for field, names := range objects {
+		if _, failed := errs[field]; !failed && len(names) > 0 {
+			v.checkRuleObjects(field, data[field], names, errors)
+		}
+	}
 
-	if len(errs) == 0 {
+	if len(errs) == 0 && errors.IsEmpty() {
 		return &ValidationResult{
 			valid:     true,
-			validated: data,
+			validated: validated,
 		}
 	}
 
-	errors := NewValidationErrors()
 	for field, err := range errs {
 		if validationErr, ok := err.(validator.ValidationErrors); ok {
 			for _, fe := range validationErr {
@@ -78,10 +131,31 @@ func (v *Validator) ValidateMap(data map[string]any, rules map[string]string) *V
 	return &ValidationResult{
 		valid:     false,
 		errors:    errors,
-		validated: data,
+		validated: validated,
 	}
 }
 
+// checkDependent checks a field's dependent rules, adding the message of
+// the first that fails. It reports whether they all passed.
+func (v *Validator) checkDependent(field string, data map[string]any, rules []dependentRule, errors *ValidationErrors) bool {
+	value := data[field]
+	for _, rule := range rules {
+		if dependentRules[rule.tag](rule, value, data) {
+			continue
+		}
+		errors.Add(field, v.message(failure{
+			field:  field,
+			tag:    rule.tag,
+			param:  rule.param,
+			value:  value,
+			other:  rule.other,
+			values: rule.values,
+		}))
+		return false
+	}
+	return true
+}
+
 // ValidateValue validates a single value.
 func (v *Validator) ValidateValue(value any, rules string) error {
 	return v.validate.Var(value, rules)
@@ -124,127 +198,116 @@ func (v *Validator) formatMapError(fe validator.FieldError, fieldName string) st
 
 // formatErrorWithField formats a validation error message with an optional field name override.
 func (v *Validator) formatErrorWithField(fe validator.FieldError, fieldNameOverride string) string {
+	field := fe.Field()
+	if fieldNameOverride != "" {
+		field = fieldNameOverride
+	}
+	return v.message(failure{field: field, tag: fe.Tag(), param: fe.Param(), value: fe.Value()})
+}
+
+// failure describes a failed rule, for formatting its message.
+type failure struct {
+	field string
+	tag   string
+	param string
+	value any
+
+	// other holds the fields a dependent rule refers to, and values the
+	// values it compares the first of them with.
+	other  []string
+	values []string
+}
+
+// message returns the message of a failed rule: the custom message for
+// "<field>.<tag>", else the "validation.<tag>" line of the translator, else
+// a rule object's message, else the default line from the package's
+// language files.
+func (v *Validator) message(f failure) string {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
 
-	// Determine field name to use for key lookup and display
-	lookupField := fe.Field()
-	if lookupField == "" && fieldNameOverride != "" {
-		lookupField = fieldNameOverride
+	// Check for custom message
+	if msg, ok := v.customMessages[f.field+"."+f.tag]; ok {
+		return v.replaceMessagePlaceholders(msg, f)
 	}
 
-	// Check for custom message
-	key := lookupField + "." + fe.Tag()
-	if msg, ok := v.customMessages[key]; ok {
-		return v.replaceMessagePlaceholders(msg, fe, fieldNameOverride)
+	// Check for a translated message, such as validation.required
+	if msg, ok := v.line("validation." + f.tag); ok {
+		return v.replaceMessagePlaceholders(msg, f)
+	}
+
+	// Check for a rule object's message
+	if rule, ok := v.rules[f.tag]; ok {
+		return v.replaceMessagePlaceholders(rule.Message(), f)
 	}
 
 	// Default messages
-	return v.defaultMessage(fe, fieldNameOverride)
+	if msg, ok := defaultLine(v.currentLocale(), "validation."+f.tag); ok {
+		return v.replaceMessagePlaceholders(msg, f)
+	}
+	msg, ok := v.line("validation.failed")
+	if !ok {
+		msg, _ = defaultLine(v.currentLocale(), "validation.failed")
+	}
+	return v.replaceMessagePlaceholders(msg, f)
 }
 
-// defaultMessage returns the default error message for a validation tag.
-func (v *Validator) defaultMessage(fe validator.FieldError, fieldNameOverride string) string {
-	var field string
-	if fieldNameOverride != "" {
-		field = v.getAttributeName(fieldNameOverride)
-	} else {
-		field = v.getAttributeName(fe.Field())
-	}
-
-	switch fe.Tag() {
-	case "required":
-		return field + " is required"
-	case "email":
-		return field + " must be a valid email address"
-	case "min":
-		return field + " must be at least " + fe.Param() + " characters"
-	case "max":
-		return field + " must not exceed " + fe.Param() + " characters"
-	case "len":
-		return field + " must be exactly " + fe.Param() + " characters"
-	case "gt":
-		return field + " must be greater than " + fe.Param()
-	case "gte":
-		return field + " must be greater than or equal to " + fe.Param()
-	case "lt":
-		return field + " must be less than " + fe.Param()
-	case "lte":
-		return field + " must be less than or equal to " + fe.Param()
-	case "eq":
-		return field + " must be equal to " + fe.Param()
-	case "ne":
-		return field + " must not be equal to " + fe.Param()
-	case "oneof":
-		return field + " must be one of: " + fe.Param()
-	case "url":
-		return field + " must be a valid URL"
-	case "uuid":
-		return field + " must be a valid UUID"
-	case "alpha":
-		return field + " must contain only alphabetic characters"
-	case "alphanum":
-		return field + " must contain only alphanumeric characters"
-	case "numeric":
-		return field + " must be numeric"
-	case "number":
-		return field + " must be a number"
-	case "boolean":
-		return field + " must be a boolean"
-	case "json":
-		return field + " must be valid JSON"
-	case "datetime":
-		return field + " must be a valid datetime"
-	case "eqfield":
-		return field + " must match " + fe.Param()
-	case "nefield":
-		return field + " must not match " + fe.Param()
-	case "contains":
-		return field + " must contain " + fe.Param()
-	case "excludes":
-		return field + " must not contain " + fe.Param()
-	case "startswith":
-		return field + " must start with " + fe.Param()
-	case "endswith":
-		return field + " must end with " + fe.Param()
-	case "ip":
-		return field + " must be a valid IP address"
-	case "ipv4":
-		return field + " must be a valid IPv4 address"
-	case "ipv6":
-		return field + " must be a valid IPv6 address"
-	default:
-		return field + " failed validation: " + fe.Tag()
-	}
-}
-
-// getAttributeName returns the display name for a field.
+// currentLocale returns the locale messages are translated into.
+func (v *Validator) currentLocale() string {
+	if v.locale != "" || v.translator == nil {
+		return v.locale
+	}
+	return v.translator.Locale()
+}
+
+// line returns a line of the translator in the current locale.
+func (v *Validator) line(key string) (string, bool) {
+	if v.translator == nil {
+		return "", false
+	}
+	return v.translator.Line(v.currentLocale(), key)
+}
+
+// otherNames returns the display names of the fields a dependent rule
+// refers to.
+func (v *Validator) otherNames(f failure) string {
+	names := make([]string, len(f.other))
+	for i, other := range f.other {
+		names[i] = v.getAttributeName(other)
+	}
+	return strings.Join(names, " / ")
+}
+
+// getAttributeName returns the display name for a field: its custom
+// attribute name, else its "validation.attributes.<field>" line, else the
+// field in Title Case.
 func (v *Validator) getAttributeName(field string) string {
 	if name, ok := v.attributeNames[field]; ok {
 		return name
 	}
+	if name, ok := v.line("validation.attributes." + field); ok {
+		return name
+	}
 	// Convert camelCase/snake_case to Title Case
 	return strings.Title(strings.ReplaceAll(strings.ReplaceAll(field, "_", " "), "-", " "))
 }
 
-// replaceMessagePlaceholders replaces placeholders in custom messages.
-func (v *Validator) replaceMessagePlaceholders(msg string, fe validator.FieldError, fieldNameOverride string) string {
-	var field string
-	if fieldNameOverride != "" {
-		field = v.getAttributeName(fieldNameOverride)
-	} else {
-		field = v.getAttributeName(fe.Field())
-	}
-
-	msg = strings.ReplaceAll(msg, ":attribute", field)
+// replaceMessagePlaceholders replaces placeholders in messages:
+// :attribute, :value, :param and :rule, and for dependent rules :other and
+// :values.
+func (v *Validator) replaceMessagePlaceholders(msg string, f failure) string {
+	msg = strings.ReplaceAll(msg, ":attribute", v.getAttributeName(f.field))
 
 	// Safely convert value to string, handling all types
 	var valueStr string
-	if val := fe.Value(); val != nil {
-		valueStr = fmt.Sprintf("%v", val)
+	if f.value != nil {
+		valueStr = fmt.Sprintf("%v", f.value)
 	}
+	msg = strings.ReplaceAll(msg, ":values", strings.Join(f.values, ", "))
 	msg = strings.ReplaceAll(msg, ":value", valueStr)
-	msg = strings.ReplaceAll(msg, ":param", fe.Param())
+	msg = strings.ReplaceAll(msg, ":other", v.otherNames(f))
+	msg = strings.ReplaceAll(msg, ":param", f.param)
+	msg = strings.ReplaceAll(msg, ":rule", f.tag)
 	return msg
 }
 
@@ -266,6 +329,42 @@ func (v *Validator) SetAttributeNames(names map[string]string) {
 	}
 }
 
+// SetTranslator sets the translator messages are looked up in, under
+// "validation.<tag>" keys, when there is no custom message. The default
+// English messages are used for tags it doesn't translate.
+func (v *Validator) SetTranslator(translator Translator) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.translator = translator
+}
+
+// InLocale returns a validator that shares v's rules and messages but
+// translates messages into locale.
+func (v *Validator) InLocale(locale string) *Validator {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	localized := &Validator{
+		validate:       v.validate,
+		customMessages: make(map[string]string, len(v.customMessages)),
+		attributeNames: make(map[string]string, len(v.attributeNames)),
+		translator:     v.translator,
+		locale:         locale,
+		rules:          make(map[string]Rule, len(v.rules)),
+		container:      v.container,
+	}
+	for name, rule := range v.rules {
+		localized.rules[name] = rule
+	}
+	for k, val := range v.customMessages {
+		localized.customMessages[k] = val
+	}
+	for k, val := range v.attributeNames {
+		localized.attributeNames[k] = val
+	}
+	return localized
+}
+
 // RegisterValidation registers a custom validation function.
 func (v *Validator) RegisterValidation(tag string, fn validator.Func) error {
 	return v.validate.RegisterValidation(tag, fn)
@@ -439,6 +538,22 @@ func (e *ValidationErrors) ToJSON() ([]byte, error) {
 	return json.Marshal(e.errors)
 }
 
+// StatusCode returns 422, so handlers that return validation errors respond
+// with Unprocessable Entity.
+func (e *ValidationErrors) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
+// Message returns the message shown alongside the field errors.
+func (e *ValidationErrors) Message() string {
+	return "Validation failed"
+}
+
+// Unwrap returns nil; validation errors don't wrap another error.
+func (e *ValidationErrors) Unwrap() error {
+	return nil
+}
+
 // Error implements the error interface.
 func (e *ValidationErrors) Error() string {
 	e.mu.RLock()
@@ -1,10 +1,15 @@
 package validation
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/genesysflow/go-genesys/container"
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type User struct {
@@ -144,3 +149,222 @@ func TestValidationResult_Accessors(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, string(jsonBytes), "Name is required")
 }
+
+type mapTranslator map[string]map[string]string
+
+func (m mapTranslator) Locale() string { return "en" }
+
+func (m mapTranslator) Line(locale, key string) (string, bool) {
+	line, ok := m[locale][key]
+	return line, ok
+}
+
+func TestTranslatedMessages(t *testing.T) {
+	v := New()
+	v.SetTranslator(mapTranslator{
+		"en": {"validation.required": "The :attribute field is required."},
+		"de": {"validation.required": ":attribute ist erforderlich.", "validation.email": ":value ist keine E-Mail-Adresse."},
+	})
+	v.SetMessages(map[string]string{"name.required": "Tell us your name"})
+
+	result := v.Validate(&User{Email: "nope"})
+	assert.Equal(t, "Tell us your name", result.Errors().First("name"))
+	assert.Equal(t, "Email must be a valid email address", result.Errors().First("email"))
+
+	result = v.ValidateMap(map[string]any{}, map[string]string{"email": "required"})
+	assert.Equal(t, "The Email field is required.", result.Errors().First("email"))
+
+	german := v.InLocale("de")
+	result = german.ValidateMap(map[string]any{"email": "nope"}, map[string]string{"email": "email", "age": "required"})
+	assert.Equal(t, "nope ist keine E-Mail-Adresse.", result.Errors().First("email"))
+	assert.Equal(t, "Age ist erforderlich.", result.Errors().First("age"))
+}
+
+func TestDependentRules(t *testing.T) {
+	v := New()
+	rules := map[string]string{
+		"email":                 "required_if=contact email,email",
+		"phone":                 "required_without=email",
+		"password_confirmation": "same=password",
+		"nickname":              "different=name",
+		"company":               "prohibited_unless=type business",
+		"vat":                   "required_with=company country",
+	}
+
+	result := v.ValidateMap(map[string]any{
+		"contact":               "email",
+		"password":              "secret",
+		"password_confirmation": "secrets",
+		"name":                  "Ada",
+		"nickname":              "Ada",
+		"type":                  "personal",
+		"company":               "Acme",
+	}, rules)
+	assert.Equal(t, "Email is required when Contact is email", result.FirstFor("email"))
+	assert.Equal(t, "Phone is required when Email is not present", result.FirstFor("phone"))
+	assert.Equal(t, "Password Confirmation must match Password", result.FirstFor("password_confirmation"))
+	assert.Equal(t, "Nickname must be different from Name", result.FirstFor("nickname"))
+	assert.Equal(t, "Company is prohibited unless Type is business", result.FirstFor("company"))
+	assert.Equal(t, "Vat is required when Company / Country is present", result.FirstFor("vat"))
+
+	result = v.ValidateMap(map[string]any{
+		"contact":               "phone",
+		"phone":                 "555-0100",
+		"password":              "secret",
+		"password_confirmation": "secret",
+		"type":                  "business",
+		"company":               "Acme",
+		"vat":                   "GB123",
+	}, rules)
+	assert.True(t, result.Passes(), result.Messages())
+
+	// The email rule still applies once email is filled.
+	result = v.ValidateMap(map[string]any{"contact": "email", "email": "nope", "phone": "1"}, rules)
+	assert.Equal(t, "Email must be a valid email address", result.FirstFor("email"))
+}
+
+func TestDependentRuleMessages(t *testing.T) {
+	v := New()
+	v.SetAttributeNames(map[string]string{"contact": "contact method"})
+	v.SetTranslator(mapTranslator{
+		"en": {"validation.required_if": "The :attribute field is required when :other is :values."},
+	})
+
+	result := v.ValidateMap(map[string]any{"contact": "email"}, map[string]string{"email": "required_if=contact email post"})
+	assert.Equal(t, "The Email field is required when contact method is email, post.", result.FirstFor("email"))
+
+	result = v.ValidateMap(map[string]any{}, map[string]string{"email": "required_if=contact"})
+	assert.Equal(t, "validation: required_if requires a field and values", result.FirstFor("email"))
+}
+
+func TestSometimesAndValidatedData(t *testing.T) {
+	v := New()
+	rules := map[string]string{
+		"name":       "required",
+		"nickname":   "sometimes,required,min=2",
+		"age":        "number",
+		"score":      "numeric",
+		"newsletter": "boolean",
+	}
+
+	result := v.ValidateMap(map[string]any{
+		"name":       "Ada",
+		"age":        "36",
+		"score":      "9.5",
+		"newsletter": "true",
+		"admin":      true,
+	}, rules)
+	assert.True(t, result.Passes(), result.Messages())
+	assert.Equal(t, map[string]any{"name": "Ada", "age": 36, "score": 9.5, "newsletter": true}, result.Validated())
+
+	result = v.ValidateMap(map[string]any{"name": "Ada", "nickname": ""}, rules)
+	assert.Equal(t, "Nickname is required", result.FirstFor("nickname"))
+}
+
+type takenEmails struct{ emails []string }
+
+type uniqueEmail struct {
+	Taken *takenEmails `inject:""`
+}
+
+func (r *uniqueEmail) Passes(attribute string, value any) bool {
+	for _, email := range r.Taken.emails {
+		if value == email {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *uniqueEmail) Message() string {
+	return "The :attribute :value has already been taken."
+}
+
+func TestRuleObjects(t *testing.T) {
+	c := container.New()
+	container.ProvideValue(c, &takenEmails{emails: []string{"ada@example.com"}})
+
+	v := New()
+	v.SetContainer(c)
+	require.NoError(t, v.RegisterRule("unique_email", &uniqueEmail{}))
+
+	rules := map[string]string{"email": "required,email,unique_email"}
+	result := v.ValidateMap(map[string]any{"email": "ada@example.com"}, rules)
+	assert.Equal(t, "The Email ada@example.com has already been taken.", result.FirstFor("email"))
+
+	result = v.ValidateMap(map[string]any{"email": "nope"}, rules)
+	assert.Equal(t, "Email must be a valid email address", result.FirstFor("email"))
+
+	assert.True(t, v.ValidateMap(map[string]any{"email": "grace@example.com"}, rules).Passes())
+
+	type signup struct {
+		Email string `json:"email" validate:"required,unique_email"`
+	}
+	result = v.Validate(signup{Email: "ada@example.com"})
+	assert.Equal(t, "The Email ada@example.com has already been taken.", result.FirstFor("email"))
+
+	v.SetMessages(map[string]string{"email.unique_email": "Pick another email"})
+	result = v.InLocale("en").Validate(signup{Email: "ada@example.com"})
+	assert.Equal(t, "Pick another email", result.FirstFor("email"))
+}
+
+func TestLocalizedAttributeNames(t *testing.T) {
+	v := New()
+	v.SetTranslator(mapTranslator{
+		"en": {"validation.attributes.email": "email address"},
+		"de": {
+			"validation.attributes.email":  "E-Mail-Adresse",
+			"validation.attributes.phone":  "Telefon",
+			"validation.required_without": ":attribute ist erforderlich, wenn :other fehlt.",
+			"validation.failed":           ":attribute ist ungültig.",
+		},
+	})
+
+	result := v.ValidateMap(map[string]any{"email": "nope"}, map[string]string{"email": "email"})
+	assert.Equal(t, "email address must be a valid email address", result.FirstFor("email"))
+
+	german := v.InLocale("de")
+	result = german.ValidateMap(map[string]any{"code": "x"}, map[string]string{"phone": "required_without=email", "code": "hexadecimal"})
+	assert.Equal(t, "Telefon ist erforderlich, wenn E-Mail-Adresse fehlt.", result.FirstFor("phone"))
+	assert.Equal(t, "Code ist ungültig.", result.FirstFor("code"))
+
+	// Locales without a line use the default English messages.
+	result = v.InLocale("fr").ValidateMap(map[string]any{}, map[string]string{"name": "required", "code": "hexadecimal"})
+	assert.Equal(t, "Name is required", result.FirstFor("name"))
+	assert.Equal(t, "Code failed validation: hexadecimal", result.FirstFor("code"))
+}
+
+func TestPasswordRule(t *testing.T) {
+	rule := &Password{Min: 10, MixedCase: true, Numbers: true, Symbols: true}
+	assert.True(t, rule.Passes("password", "Correct-Horse9"))
+	assert.False(t, rule.Passes("password", "Short-9a"))
+	assert.False(t, rule.Passes("password", "correct-horse9"))
+	assert.False(t, rule.Passes("password", "Correct-Horse"))
+	assert.False(t, rule.Passes("password", "CorrectHorse9"))
+	assert.False(t, rule.Passes("password", 1234567890))
+
+	v := New()
+	require.NoError(t, v.RegisterRule("password", rule))
+	result := v.ValidateMap(map[string]any{"password": "weak"}, map[string]string{"password": "required,password"})
+	assert.Equal(t, "Password must be at least 10 characters and contain at least one uppercase and one lowercase letter, one number and one symbol", result.FirstFor("password"))
+}
+
+func TestPasswordUncompromised(t *testing.T) {
+	// SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8.
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		fmt.Fprint(w, "003D68EB55068C33ACE09247EE4C639306B:3\r\n1E4C9B93F3F0682250B6CF8331B7EE68FD8:3861493\r\n")
+	}))
+	defer server.Close()
+
+	rule := &Password{Uncompromised: true, URL: server.URL + "/range/"}
+	assert.False(t, rule.Passes("password", "password"))
+	assert.Equal(t, "/range/5BAA6", requested)
+	assert.True(t, rule.Passes("password", "not in the list"))
+	assert.Equal(t, ":attribute must be at least 8 characters, and must not have appeared in a data leak", rule.Message())
+
+	// An unreachable service doesn't block sign-ups.
+	server.Close()
+	assert.True(t, rule.Passes("password", "password"))
+}
@@ -0,0 +1,317 @@
+// Package view renders HTML pages from html/template views.
+//
+// Views are files under a directory such as resources/views, named with dots
+// for slashes and without their extension: resources/views/users/index.html
+// is "users.index". A view can extend a layout by starting with an extends
+// directive and filling the layout's blocks, which act as its sections:
+//
+//	{{/* layouts/app.html */}}
+//	<html><head><title>{{ block "title" . }}App{{ end }}</title></head>
+//	<body>{{ include "partials.nav" . }}{{ block "content" . }}{{ end }}</body></html>
+//
+//	{{/* users/index.html */}}
+//	{{ extends "layouts.app" }}
+//	{{ define "title" }}Users{{ end }}
+//	{{ define "content" }}{{ range .Users }}<p>{{ .Name }}</p>{{ end }}{{ end }}
+//
+// Layouts can extend other layouts, and include renders another view in
+// place, such as a partial.
+package view
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Data is the data views are rendered with.
+type Data map[string]any
+
+// View is a view about to be rendered, as passed to composers.
+type View struct {
+	Name string
+	Data Data
+}
+
+// Composer adds data to views before they are rendered.
+type Composer func(view *View)
+
+// composer is a composer and the view names it applies to.
+type composer struct {
+	pattern string
+	compose Composer
+}
+
+// extendsPattern matches the extends directive at the start of a view.
+var extendsPattern = regexp.MustCompile(`^\s*\{\{-?\s*extends\s+"([^"]+)"\s*-?\}\}`)
+
+// Engine loads and renders views.
+type Engine struct {
+	dir       string
+	extension string
+	reload    bool
+	sources   map[string]string
+	cache     map[string]*template.Template
+	funcs     template.FuncMap
+	shared    Data
+	composers []composer
+	mu        sync.RWMutex
+}
+
+// NewEngine creates an engine for the views in dir with the given file
+// extension, ".html" if empty. Call Load to read them.
+func NewEngine(dir, extension string) *Engine {
+	if extension == "" {
+		extension = ".html"
+	}
+	return &Engine{
+		dir:       dir,
+		extension: extension,
+		sources:   make(map[string]string),
+		cache:     make(map[string]*template.Template),
+		funcs:     make(template.FuncMap),
+		shared:    make(Data),
+	}
+}
+
+// Load reads every view in the engine's directory, replacing those loaded
+// before. Views added with AddView are kept. A missing directory has no
+// views.
+func (e *Engine) Load() error {
+	if e.dir == "" {
+		return nil
+	}
+	if _, err := os.Stat(e.dir); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	sources := make(map[string]string)
+	err := filepath.WalkDir(e.dir, func(file string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(file, e.extension) {
+			return err
+		}
+		source, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(e.dir, file)
+		if err != nil {
+			return err
+		}
+		sources[Name(strings.TrimSuffix(filepath.ToSlash(rel), e.extension))] = string(source)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("view: failed to load views: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for name, source := range sources {
+		e.sources[name] = source
+	}
+	e.cache = make(map[string]*template.Template)
+	return nil
+}
+
+// SetReload sets whether views are read from disk again before every
+// render, so edits show up without a restart. Use it in development only.
+func (e *Engine) SetReload(reload bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.reload = reload
+}
+
+// AddView registers a view from its source.
+func (e *Engine) AddView(name, source string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sources[Name(name)] = source
+	e.cache = make(map[string]*template.Template)
+}
+
+// Exists reports whether a view exists.
+func (e *Engine) Exists(name string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	_, ok := e.sources[Name(name)]
+	return ok
+}
+
+// Funcs adds template functions available to every view. Add them before
+// the first render.
+func (e *Engine) Funcs(funcs template.FuncMap) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for name, fn := range funcs {
+		e.funcs[name] = fn
+	}
+	e.cache = make(map[string]*template.Template)
+}
+
+// Share makes a value available to every view under key. Data passed to
+// Render takes precedence.
+func (e *Engine) Share(key string, value any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.shared[key] = value
+}
+
+// Composer registers a composer for the views matching pattern, a view name
+// in which * matches any part, such as "users.*" or "*".
+func (e *Engine) Composer(pattern string, compose Composer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.composers = append(e.composers, composer{pattern: Name(pattern), compose: compose})
+}
+
+// Render renders a view. Data given as Data or map[string]any is merged
+// over the shared data and passed through the view's composers; other
+// values, such as structs, are passed to the view as they are.
+func (e *Engine) Render(name string, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := e.RenderTo(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RenderTo renders a view to w.
+func (e *Engine) RenderTo(w io.Writer, name string, data any) error {
+	name = Name(name)
+	tmpl, root, err := e.template(name)
+	if err != nil {
+		return err
+	}
+	if err := tmpl.ExecuteTemplate(w, root, e.compose(name, data)); err != nil {
+		return fmt.Errorf("view: failed to render [%s]: %w", name, err)
+	}
+	return nil
+}
+
+// compose merges map data over the shared data and runs the composers.
+func (e *Engine) compose(name string, data any) any {
+	var values Data
+	switch data := data.(type) {
+	case nil:
+		values = make(Data)
+	case Data:
+		values = make(Data, len(data))
+		for key, value := range data {
+			values[key] = value
+		}
+	case map[string]any:
+		values = make(Data, len(data))
+		for key, value := range data {
+			values[key] = value
+		}
+	default:
+		return data
+	}
+
+	e.mu.RLock()
+	for key, value := range e.shared {
+		if _, ok := values[key]; !ok {
+			values[key] = value
+		}
+	}
+	composers := e.composers
+	e.mu.RUnlock()
+
+	view := &View{Name: name, Data: values}
+	for _, c := range composers {
+		if matched, _ := path.Match(c.pattern, name); matched {
+			c.compose(view)
+		}
+	}
+	return view.Data
+}
+
+// template returns the parsed template set for a view and the name of the
+// template to execute: the view itself, or the outermost layout it extends.
+func (e *Engine) template(name string) (*template.Template, string, error) {
+	e.mu.RLock()
+	reload := e.reload
+	tmpl, ok := e.cache[name]
+	e.mu.RUnlock()
+	if reload {
+		if err := e.Load(); err != nil {
+			return nil, "", err
+		}
+	} else if ok {
+		return tmpl, tmpl.Name(), nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Walk up the extends chain, then parse from the outermost layout down
+	// so each view's definitions replace its layout's blocks.
+	var chain []string
+	sources := make(map[string]string)
+	for current := name; current != ""; {
+		source, ok := e.sources[current]
+		if !ok {
+			if current == name {
+				return nil, "", fmt.Errorf("view: [%s] not found", name)
+			}
+			return nil, "", fmt.Errorf("view: layout [%s] extended by [%s] not found", current, chain[len(chain)-1])
+		}
+		if _, seen := sources[current]; seen {
+			return nil, "", fmt.Errorf("view: [%s] extends itself", current)
+		}
+		chain = append(chain, current)
+
+		parent := ""
+		if match := extendsPattern.FindStringSubmatchIndex(source); match != nil {
+			parent = Name(source[match[2]:match[3]])
+			source = source[match[1]:]
+		}
+		sources[current] = source
+		current = parent
+	}
+
+	root := chain[len(chain)-1]
+	tmpl = template.New(root).Funcs(e.funcs).Funcs(template.FuncMap{
+		"include": e.include,
+		"extends": func(string) string { return "" },
+	})
+	for i := len(chain) - 1; i >= 0; i-- {
+		var err error
+		if i == len(chain)-1 {
+			_, err = tmpl.Parse(sources[chain[i]])
+		} else {
+			_, err = tmpl.New(chain[i]).Parse(sources[chain[i]])
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("view: failed to parse [%s]: %w", chain[i], err)
+		}
+	}
+
+	e.cache[name] = tmpl
+	return tmpl, root, nil
+}
+
+// include renders another view in place, with the given data or none.
+func (e *Engine) include(name string, data ...any) (template.HTML, error) {
+	var value any
+	if len(data) > 0 {
+		value = data[0]
+	}
+	html, err := e.Render(name, value)
+	return template.HTML(html), err
+}
+
+// Name normalizes a view name to dot separators: "users/index" and
+// "users.index" both name resources/views/users/index.html.
+func Name(name string) string {
+	return strings.ReplaceAll(strings.Trim(name, "/"), "/", ".")
+}
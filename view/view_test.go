@@ -0,0 +1,132 @@
+package view_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/genesysflow/go-genesys/view"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeViews(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, source := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(source), 0644))
+	}
+	return dir
+}
+
+func newEngine(t *testing.T) *view.Engine {
+	dir := writeViews(t, map[string]string{
+		"layouts/base.html": `<html><head><title>{{ block "title" . }}App{{ end }}</title></head>` +
+			`<body>{{ block "body" . }}{{ end }}</body></html>`,
+		"layouts/app.html": `{{ extends "layouts.base" }}` +
+			`{{ define "body" }}{{ include "partials.nav" . }}<main>{{ block "content" . }}{{ end }}</main>{{ end }}`,
+		"partials/nav.html": `<nav>{{ .AppName }}</nav>`,
+		"users/index.html": `{{ extends "layouts.app" }}
+{{ define "title" }}Users{{ end }}
+{{ define "content" }}{{ range .Users }}<p>{{ . }}</p>{{ end }}{{ end }}`,
+		"home.html":   `{{ extends "layouts.app" }}{{ define "content" }}Home{{ end }}`,
+		"plain.html":  `<b>{{ .Name }}</b>`,
+		"notes.txt":   `not a view`,
+		"broken.html": `{{ extends "layouts.missing" }}`,
+		"loop.html":   `{{ extends "loop" }}`,
+	})
+	engine := view.NewEngine(dir, "")
+	require.NoError(t, engine.Load())
+	return engine
+}
+
+func TestEngineRendersLayoutsAndSections(t *testing.T) {
+	engine := newEngine(t)
+	engine.Share("AppName", "Acme")
+
+	html, err := engine.Render("users.index", view.Data{"Users": []string{"<jane>", "joe"}})
+	require.NoError(t, err)
+	assert.Equal(t, `<html><head><title>Users</title></head><body><nav>Acme</nav>`+
+		`<main><p>&lt;jane&gt;</p><p>joe</p></main></body></html>`, html)
+
+	// Blocks a view doesn't define keep the layout's default.
+	html, err = engine.Render("home", nil)
+	require.NoError(t, err)
+	assert.Contains(t, html, "<title>App</title>")
+	assert.Contains(t, html, "<main>Home</main>")
+}
+
+func TestEngineRendersStructData(t *testing.T) {
+	engine := newEngine(t)
+
+	html, err := engine.Render("plain", struct{ Name string }{Name: "Jane"})
+	require.NoError(t, err)
+	assert.Equal(t, "<b>Jane</b>", html)
+}
+
+func TestEngineComposers(t *testing.T) {
+	engine := newEngine(t)
+	engine.Share("AppName", "Acme")
+	engine.Composer("users.*", func(v *view.View) {
+		v.Data["Users"] = []string{"composed"}
+	})
+	engine.Composer("*", func(v *view.View) {
+		v.Data["AppName"] = strings.ToUpper(v.Data["AppName"].(string))
+	})
+
+	html, err := engine.Render("users/index", nil)
+	require.NoError(t, err)
+	assert.Contains(t, html, "<p>composed</p>")
+	assert.Contains(t, html, "<nav>ACME</nav>")
+
+	// Data passed to Render wins over shared data.
+	html, err = engine.Render("partials.nav", map[string]any{"AppName": "Local"})
+	require.NoError(t, err)
+	assert.Equal(t, "<nav>LOCAL</nav>", html)
+}
+
+func TestEngineErrors(t *testing.T) {
+	engine := newEngine(t)
+
+	assert.True(t, engine.Exists("users.index"))
+	assert.False(t, engine.Exists("notes"))
+
+	_, err := engine.Render("missing", nil)
+	assert.ErrorContains(t, err, "[missing] not found")
+	_, err = engine.Render("broken", nil)
+	assert.ErrorContains(t, err, "layout [layouts.missing]")
+	_, err = engine.Render("loop", nil)
+	assert.ErrorContains(t, err, "extends itself")
+}
+
+func TestEngineReload(t *testing.T) {
+	dir := writeViews(t, map[string]string{"page.html": "v1"})
+	engine := view.NewEngine(dir, ".html")
+	require.NoError(t, engine.Load())
+
+	html, err := engine.Render("page", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", html)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "page.html"), []byte("v2"), 0644))
+	html, _ = engine.Render("page", nil)
+	assert.Equal(t, "v1", html, "cached until reload is enabled")
+
+	engine.SetReload(true)
+	html, err = engine.Render("page", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", html)
+}
+
+func TestEngineMissingDirectory(t *testing.T) {
+	engine := view.NewEngine(filepath.Join(t.TempDir(), "missing"), "")
+	require.NoError(t, engine.Load())
+
+	engine.AddView("inline", "Hi {{ .Name }}")
+	html, err := engine.Render("inline", view.Data{"Name": "Jane"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hi Jane", html)
+}